@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/aidanlsb/raven/internal/history"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 // WriteFile writes data to path atomically (best-effort cross-platform).
@@ -13,6 +16,12 @@ import (
 //
 // perm is used for the temp file. If perm is 0, WriteFile will try to preserve the
 // existing file's mode (if it exists) and otherwise falls back to 0644.
+//
+// When a command is being recorded for undo, the file's previous content is
+// captured in the history journal before it is replaced.
+//
+// Files in a directory listed under encryption.directories in the vault's
+// raven.yaml are encrypted before they are written.
 func WriteFile(path string, data []byte, perm os.FileMode) error {
 	if perm == 0 {
 		if st, err := os.Stat(path); err == nil {
@@ -22,6 +31,13 @@ func WriteFile(path string, data []byte, perm os.FileMode) error {
 		}
 	}
 
+	data, err := vaultcrypt.Seal(path, data)
+	if err != nil {
+		return fmt.Errorf("encrypt file: %w", err)
+	}
+
+	history.Capture(path)
+
 	dir := filepath.Dir(path)
 	base := filepath.Base(path)
 
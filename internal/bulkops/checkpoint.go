@@ -0,0 +1,166 @@
+package bulkops
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/codes"
+)
+
+// CheckpointDirName is the vault-relative directory holding bulk checkpoints.
+const CheckpointDirName = ".raven/operations"
+
+const CodeOperationNotFound ErrorCode = codes.ErrNotFound
+
+// Checkpoint records the progress of an applied bulk operation so an
+// interrupted run can be resumed without redoing completed items.
+type Checkpoint struct {
+	ID        string                 `json:"id"`
+	Command   string                 `json:"command"`
+	Args      map[string]interface{} `json:"args"`
+	IDsKey    string                 `json:"ids_key"`
+	Pending   []string               `json:"pending"`
+	Completed []string               `json:"completed"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// NewCheckpoint creates an unsaved checkpoint with every ID pending.
+// The ID list is removed from args; it is tracked in Pending instead.
+func NewCheckpoint(command string, args map[string]interface{}, idsKey string, ids []string) (*Checkpoint, error) {
+	id, err := newOperationID()
+	if err != nil {
+		return nil, err
+	}
+
+	storedArgs := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		if key == idsKey || key == "confirm" {
+			continue
+		}
+		storedArgs[key] = value
+	}
+
+	now := time.Now().UTC()
+	return &Checkpoint{
+		ID:        id,
+		Command:   command,
+		Args:      storedArgs,
+		IDsKey:    idsKey,
+		Pending:   append([]string(nil), ids...),
+		Completed: []string{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// MarkCompleted moves ids from Pending to Completed.
+func (c *Checkpoint) MarkCompleted(ids []string) {
+	done := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		done[id] = struct{}{}
+	}
+	pending := c.Pending[:0]
+	for _, id := range c.Pending {
+		if _, ok := done[id]; ok {
+			c.Completed = append(c.Completed, id)
+			continue
+		}
+		pending = append(pending, id)
+	}
+	c.Pending = pending
+	c.UpdatedAt = time.Now().UTC()
+}
+
+// SaveCheckpoint writes the checkpoint under the vault's operations directory.
+func SaveCheckpoint(vaultPath string, cp *Checkpoint) error {
+	dir := filepath.Join(vaultPath, filepath.FromSlash(CheckpointDirName))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create operations directory: %w", err)
+	}
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(checkpointPath(vaultPath, cp.ID), append(data, '\n'), 0o644)
+}
+
+// LoadCheckpoint reads a saved checkpoint by operation ID.
+func LoadCheckpoint(vaultPath, operationID string) (*Checkpoint, error) {
+	operationID = strings.TrimSpace(operationID)
+	if operationID == "" || strings.ContainsAny(operationID, `/\`) {
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("invalid operation ID: %q", operationID), "Run 'rvn resume' to list resumable operations")
+	}
+
+	data, err := os.ReadFile(checkpointPath(vaultPath, operationID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, newError(CodeOperationNotFound, fmt.Sprintf("operation not found: %s", operationID), "Run 'rvn resume' to list resumable operations")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("parse checkpoint %s: %w", operationID, err)
+	}
+	return &cp, nil
+}
+
+// ListCheckpoints returns saved checkpoints, oldest first.
+func ListCheckpoints(vaultPath string) ([]*Checkpoint, error) {
+	entries, err := os.ReadDir(filepath.Join(vaultPath, filepath.FromSlash(CheckpointDirName)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make([]*Checkpoint, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		cp, err := LoadCheckpoint(vaultPath, strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			continue
+		}
+		checkpoints = append(checkpoints, cp)
+	}
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].CreatedAt.Before(checkpoints[j].CreatedAt)
+	})
+	return checkpoints, nil
+}
+
+// RemoveCheckpoint deletes a saved checkpoint. Missing checkpoints are ignored.
+func RemoveCheckpoint(vaultPath, operationID string) error {
+	err := os.Remove(checkpointPath(vaultPath, operationID))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func checkpointPath(vaultPath, operationID string) string {
+	return filepath.Join(vaultPath, filepath.FromSlash(CheckpointDirName), operationID+".json")
+}
+
+func newOperationID() (string, error) {
+	var suffix [3]byte
+	if _, err := rand.Read(suffix[:]); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("op-%s-%s", time.Now().UTC().Format("20060102-150405"), hex.EncodeToString(suffix[:])), nil
+}
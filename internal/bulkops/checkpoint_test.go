@@ -0,0 +1,69 @@
+package bulkops
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+
+	cp, err := NewCheckpoint("set", map[string]interface{}{
+		"stdin":      true,
+		"confirm":    true,
+		"fields":     map[string]interface{}{"status": "done"},
+		"object_ids": []interface{}{"a", "b", "c"},
+	}, "object_ids", []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("NewCheckpoint: %v", err)
+	}
+	if _, ok := cp.Args["object_ids"]; ok {
+		t.Fatalf("checkpoint args should not store the ID list: %#v", cp.Args)
+	}
+	if _, ok := cp.Args["confirm"]; ok {
+		t.Fatalf("checkpoint args should not store confirm: %#v", cp.Args)
+	}
+
+	cp.MarkCompleted([]string{"a", "c"})
+	if err := SaveCheckpoint(vaultPath, cp); err != nil {
+		t.Fatalf("SaveCheckpoint: %v", err)
+	}
+
+	loaded, err := LoadCheckpoint(vaultPath, cp.ID)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Pending, []string{"b"}) {
+		t.Fatalf("pending = %#v, want [b]", loaded.Pending)
+	}
+	if !reflect.DeepEqual(loaded.Completed, []string{"a", "c"}) {
+		t.Fatalf("completed = %#v, want [a c]", loaded.Completed)
+	}
+
+	listed, err := ListCheckpoints(vaultPath)
+	if err != nil {
+		t.Fatalf("ListCheckpoints: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != cp.ID {
+		t.Fatalf("listed = %#v, want single checkpoint %s", listed, cp.ID)
+	}
+
+	if err := RemoveCheckpoint(vaultPath, cp.ID); err != nil {
+		t.Fatalf("RemoveCheckpoint: %v", err)
+	}
+	if _, err := LoadCheckpoint(vaultPath, cp.ID); err == nil {
+		t.Fatal("expected removed checkpoint to be missing")
+	} else if bulkErr, ok := AsError(err); !ok || bulkErr.Code != CodeOperationNotFound {
+		t.Fatalf("LoadCheckpoint error = %v, want operation not found", err)
+	}
+}
+
+func TestLoadCheckpointRejectsPathLikeIDs(t *testing.T) {
+	t.Parallel()
+
+	_, err := LoadCheckpoint(t.TempDir(), "../raven")
+	if bulkErr, ok := AsError(err); !ok || bulkErr.Code != CodeInvalidInput {
+		t.Fatalf("LoadCheckpoint error = %v, want invalid input", err)
+	}
+}
@@ -2,6 +2,7 @@ package check
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/schema"
@@ -33,6 +34,8 @@ func (v *Validator) ValidateDocument(doc *parser.ParsedDocument) []Issue {
 		issues = append(issues, v.validateObject(doc.FilePath, obj)...)
 	}
 
+	issues = append(issues, v.validateRequiredTraits(doc)...)
+
 	// Validate traits
 	for _, trait := range doc.Traits {
 		issues = append(issues, v.validateTrait(doc.FilePath, trait)...)
@@ -46,11 +49,74 @@ func (v *Validator) ValidateDocument(doc *parser.ParsedDocument) []Issue {
 	return issues
 }
 
+// validateRequiredTraits reports objects missing a trait their type lists in
+// required_traits. Traits under any of the object's sections count.
+func (v *Validator) validateRequiredTraits(doc *parser.ParsedDocument) []Issue {
+	var issues []Issue
+	for _, obj := range doc.Objects {
+		typeDef := v.schema.Types[obj.ObjectType]
+		if typeDef == nil || len(typeDef.RequiredTraits) == 0 {
+			continue
+		}
+		present := make(map[string]struct{})
+		for _, trait := range doc.Traits {
+			if trait.ParentObjectID == obj.ID || strings.HasPrefix(trait.ParentObjectID, obj.ID+"#") {
+				present[trait.TraitType] = struct{}{}
+			}
+		}
+		for _, traitName := range typeDef.RequiredTraits {
+			if _, ok := present[traitName]; ok {
+				continue
+			}
+			issue := Issue{
+				Level:    LevelError,
+				Type:     IssueMissingRequiredTrait,
+				FilePath: doc.FilePath,
+				Line:     obj.LineStart,
+				Message:  fmt.Sprintf("Type '%s' requires trait '@%s'", obj.ObjectType, traitName),
+				Value:    traitName,
+				FixHint:  fmt.Sprintf("Add @%s to the file, or remove it from the type's required_traits", traitName),
+			}
+			if line, ok := defaultTraitLine(traitName, v.schema.Traits[traitName]); ok {
+				issue.FixCommand = fmt.Sprintf("rvn add %q --to %s", line, obj.ID)
+			}
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// defaultTraitLine returns the trait annotation a fix inserts: the bare trait
+// for markers, or the trait with its schema default. Valued traits without a
+// default have no safe line.
+func defaultTraitLine(traitName string, def *schema.TraitDefinition) (string, bool) {
+	if def == nil {
+		return "", false
+	}
+	if def.IsBoolean() {
+		return "@" + traitName, true
+	}
+	if def.Default == nil {
+		return "", false
+	}
+	value := fmt.Sprintf("%v", def.Default)
+	if strings.TrimSpace(value) == "" {
+		return "", false
+	}
+	return fmt.Sprintf("@%s(%s)", traitName, value), true
+}
+
 func (v *Validator) validateObject(filePath string, obj *parser.ParsedObject) []Issue {
 	var issues []Issue
 
-	// Track type usage
+	// Track type usage; an object also uses the types its type extends.
 	v.usedTypes[obj.ObjectType] = struct{}{}
+	for def := v.schema.Types[obj.ObjectType]; def != nil && def.Extends != ""; def = v.schema.Types[def.Extends] {
+		if _, seen := v.usedTypes[def.Extends]; seen {
+			break
+		}
+		v.usedTypes[def.Extends] = struct{}{}
+	}
 
 	// Check if type is defined
 	typeDef, typeExists := v.schema.Types[obj.ObjectType]
@@ -90,6 +156,8 @@ func (v *Validator) validateObject(filePath string, obj *parser.ParsedObject) []
 			})
 		}
 
+		issues = append(issues, v.validateUniqueFields(filePath, obj.ID, obj.ObjectType, obj.Fields, obj.LineStart)...)
+
 		// Validate ref fields with type context for missing ref tracking
 		for fieldName, fieldDef := range typeDef.Fields {
 			if fieldDef == nil {
@@ -134,14 +202,15 @@ func (v *Validator) validateObject(filePath string, obj *parser.ParsedObject) []
 		// Check for unknown frontmatter keys (not a defined field)
 		// Reserved keys that are always allowed
 		reservedKeys := map[string]bool{
-			"type":  true, // Object type declaration
-			"id":    true, // Optional file object ID override
-			"alias": true, // Alias for reference resolution
+			"type":   true, // Object type declaration
+			"id":     true, // Optional file object ID override
+			"alias":  true, // Alias for reference resolution
+			"locked": true, // Refuse content mutations without --unlock
 		}
 
 		for fieldName := range obj.Fields {
 			// Skip reserved keys
-			if reservedKeys[fieldName] {
+			if reservedKeys[fieldName] || schema.IsAttributionField(fieldName) {
 				continue
 			}
 			// Skip if it's a defined field
@@ -220,7 +289,8 @@ func (v *Validator) validateTrait(filePath string, trait *parser.ParsedTrait) []
 		return issues
 	}
 
-	if err := schema.ValidateTraitValue(traitDef, *trait.Value); err != nil {
+	value, _ := traitDef.NormalizeFieldValue(*trait.Value)
+	if err := schema.ValidateTraitValue(traitDef, value); err != nil {
 		valueStr := trait.ValueString()
 		if valueStr == "" {
 			valueStr = fmt.Sprintf("%v", trait.Value.Raw())
@@ -0,0 +1,66 @@
+package check
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/paths"
+)
+
+// SetExternalPaths sets the sparse directories that are not checked out
+// locally. Unresolved references into these directories are recorded as
+// external instead of being reported as missing.
+func (v *Validator) SetExternalPaths(roots []string) {
+	v.externalPaths = nil
+	for _, root := range roots {
+		if root = paths.NormalizeDirRoot(root); root != "" {
+			v.externalPaths = append(v.externalPaths, root)
+		}
+	}
+}
+
+// ExternalRefs returns the unresolved reference targets that point into
+// sparse directories absent from the local checkout, sorted.
+func (v *Validator) ExternalRefs() []string {
+	refs := make([]string, 0, len(v.externalRefs))
+	for target := range v.externalRefs {
+		refs = append(refs, target)
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+// isExternalRef reports whether targetRaw would live under an absent sparse
+// directory, either as written or once the object/page root is applied.
+func (v *Validator) isExternalRef(targetRaw string) bool {
+	if len(v.externalPaths) == 0 {
+		return false
+	}
+	baseID, _, _ := paths.ParseSectionID(paths.NormalizeVaultRelPath(targetRaw))
+	baseID = paths.TrimMDExtension(baseID)
+	if baseID == "" {
+		return false
+	}
+
+	candidates := []string{baseID}
+	for _, root := range []string{v.objectsRoot, v.pagesRoot} {
+		if root != "" && !strings.HasPrefix(baseID, root) {
+			candidates = append(candidates, root+baseID)
+		}
+	}
+	for _, candidate := range candidates {
+		for _, root := range v.externalPaths {
+			if strings.HasPrefix(candidate, root) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (v *Validator) trackExternalRef(targetRaw string) {
+	if v.externalRefs == nil {
+		v.externalRefs = make(map[string]struct{})
+	}
+	v.externalRefs[targetRaw] = struct{}{}
+}
@@ -25,6 +25,7 @@ const (
 	IssueMissingTargetType       IssueType = "missing_target_type"
 	IssueSelfReferentialRequired IssueType = "self_referential_required"
 	IssueUnknownFieldType        IssueType = "unknown_field_type"
+	IssueInvalidExtends          IssueType = "invalid_extends"
 	IssueIDCollision             IssueType = "id_collision"
 	IssueDuplicateAlias          IssueType = "duplicate_alias"
 	IssueAliasCollision          IssueType = "alias_collision"
@@ -35,6 +36,10 @@ const (
 	IssueDirectoryTypeMismatch   IssueType = "directory_type_mismatch"
 	IssueMissingAsset            IssueType = "missing_asset"
 	IssueOrphanedAsset           IssueType = "orphaned_asset"
+	IssueReviewOverdue           IssueType = "review_overdue"
+	IssueMissingSQLiteCapability IssueType = "missing_sqlite_capability"
+	IssueNamingConvention        IssueType = "naming_convention"
+	IssueDuplicateFieldValue     IssueType = "duplicate_field_value"
 )
 
 // AllIssueTypes returns the stable issue type strings emitted by check.
@@ -61,6 +66,7 @@ func AllIssueTypes() []IssueType {
 		IssueMissingTargetType,
 		IssueSelfReferentialRequired,
 		IssueUnknownFieldType,
+		IssueInvalidExtends,
 		IssueIDCollision,
 		IssueDuplicateAlias,
 		IssueAliasCollision,
@@ -71,6 +77,10 @@ func AllIssueTypes() []IssueType {
 		IssueDirectoryTypeMismatch,
 		IssueMissingAsset,
 		IssueOrphanedAsset,
+		IssueReviewOverdue,
+		IssueMissingSQLiteCapability,
+		IssueNamingConvention,
+		IssueDuplicateFieldValue,
 	}
 }
 
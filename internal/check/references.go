@@ -139,6 +139,13 @@ func (v *Validator) validateRefWithContext(filePath, sourceObjectID string, ref
 			}
 		}
 
+		// Targets inside sparse directories that are not checked out locally are
+		// external, not broken.
+		if v.isExternalRef(ref.TargetRaw) {
+			v.trackExternalRef(ref.TargetRaw)
+			return issues
+		}
+
 		// Determine the fix command based on type inference
 		fixCmd := ""
 		fixHint := ""
@@ -79,6 +79,23 @@ func (v *Validator) ValidateSchema() []SchemaIssue {
 		}
 	}
 
+	// Check that extends names a schema type without cycles; such types
+	// load without their inherited fields.
+	for typeName, typeDef := range v.schema.Types {
+		if typeDef == nil || typeDef.Extends == "" {
+			continue
+		}
+		if err := schema.ExtendsError(v.schema.Types, typeName); err != nil {
+			issues = append(issues, SchemaIssue{
+				Level:   LevelError,
+				Type:    IssueInvalidExtends,
+				Message: fmt.Sprintf("Type '%s' %s", typeName, err),
+				Value:   typeName,
+				FixHint: fmt.Sprintf("Point extends on type '%s' at an existing schema type or remove it", typeName),
+			})
+		}
+	}
+
 	// Check for missing target types in ref fields
 	for typeName, typeDef := range v.schema.Types {
 		if typeDef == nil || typeDef.Fields == nil {
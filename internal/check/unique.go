@@ -0,0 +1,69 @@
+package check
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// uniqueFieldKeys returns the comparable value of each unique field an
+// object sets.
+func uniqueFieldKeys(sch *schema.Schema, typeName string, fields map[string]schema.FieldValue) map[string]string {
+	if sch == nil || len(fields) == 0 {
+		return nil
+	}
+	typeDef := sch.Types[typeName]
+	if typeDef == nil {
+		return nil
+	}
+	keys := make(map[string]string)
+	for name, def := range typeDef.Fields {
+		if def == nil || !def.Unique {
+			continue
+		}
+		if key, ok := schema.UniqueValueKey(fields[name]); ok {
+			keys[name] = key
+		}
+	}
+	return keys
+}
+
+func uniqueOwnerKey(typeName, field, value string) string {
+	return typeName + "\x00" + field + "\x00" + value
+}
+
+// validateUniqueFields reports unique field values that other objects of the
+// same type also use.
+func (v *Validator) validateUniqueFields(filePath string, objectID, typeName string, fields map[string]schema.FieldValue, line int) []Issue {
+	keys := uniqueFieldKeys(v.schema, typeName, fields)
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var issues []Issue
+	for _, name := range names {
+		var others []string
+		for _, id := range v.uniqueOwners[uniqueOwnerKey(typeName, name, keys[name])] {
+			if id != objectID {
+				others = append(others, id)
+			}
+		}
+		if len(others) == 0 {
+			continue
+		}
+		issues = append(issues, Issue{
+			Level:    LevelError,
+			Type:     IssueDuplicateFieldValue,
+			FilePath: filePath,
+			Line:     line,
+			Message:  fmt.Sprintf("Field '%s' must be unique, but '%s' is also used by %s", name, keys[name], strings.Join(others, ", ")),
+			Value:    keys[name],
+			FixHint:  fmt.Sprintf("Give each %s a different '%s'", typeName, name),
+		})
+	}
+	return issues
+}
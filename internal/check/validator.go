@@ -27,12 +27,16 @@ type Validator struct {
 	objectsRoot      string                     // Directory prefix for typed objects (e.g., "objects/")
 	pagesRoot        string                     // Directory prefix for untyped pages (e.g., "pages/")
 	dailyDir         string                     // Directory prefix for daily notes (e.g., "daily")
+	externalPaths    []string                   // Sparse directories absent from the local checkout
+	externalRefs     map[string]struct{}        // Unresolved targets that fall under externalPaths
+	uniqueOwners     map[string][]string        // type, field, and value of unique fields -> object IDs
 }
 
 // ObjectInfo contains basic info about an object for validation.
 type ObjectInfo struct {
-	ID   string
-	Type string
+	ID     string
+	Type   string
+	Fields map[string]schema.FieldValue // Used to check unique fields across the vault
 }
 
 // NewValidator creates a new validator.
@@ -88,10 +92,15 @@ func NewValidatorWithTypesAliasesAndResolver(
 	objectTypes := make(map[string]string, len(objectInfos))
 	ids := make([]string, 0, len(objectInfos))
 
+	uniqueOwners := make(map[string][]string)
 	for _, info := range objectInfos {
 		allIDs[info.ID] = struct{}{}
 		objectTypes[info.ID] = info.Type
 		ids = append(ids, info.ID)
+		for field, value := range uniqueFieldKeys(s, info.Type, info.Fields) {
+			key := uniqueOwnerKey(info.Type, field, value)
+			uniqueOwners[key] = append(uniqueOwners[key], info.ID)
+		}
 	}
 	res := prebuiltResolver
 	if res == nil {
@@ -103,6 +112,7 @@ func NewValidatorWithTypesAliasesAndResolver(
 		resolver:        res,
 		allIDs:          allIDs,
 		objectTypes:     objectTypes,
+		uniqueOwners:    uniqueOwners,
 		aliases:         aliases,
 		missingRefs:     make(map[string]*MissingRef),
 		undefinedTraits: make(map[string]*UndefinedTrait),
@@ -669,6 +669,29 @@ func TestValidatorSchemaIntegrity(t *testing.T) {
 		}
 	})
 
+	t.Run("invalid extends", func(t *testing.T) {
+		s := &schema.Schema{
+			Types: map[string]*schema.TypeDefinition{
+				"meeting": {Extends: "event"},
+			},
+			Traits: map[string]*schema.TraitDefinition{},
+		}
+
+		v := NewValidatorWithTypes(s, []ObjectInfo{})
+		schemaIssues := v.ValidateSchema()
+
+		hasInvalidExtends := false
+		for _, issue := range schemaIssues {
+			if issue.Type == IssueInvalidExtends && issue.Value == "meeting" {
+				hasInvalidExtends = true
+				break
+			}
+		}
+		if !hasInvalidExtends {
+			t.Errorf("Expected invalid extends error, got: %v", schemaIssues)
+		}
+	})
+
 	t.Run("unknown field type", func(t *testing.T) {
 		s := &schema.Schema{
 			Types: map[string]*schema.TypeDefinition{
@@ -1107,3 +1130,78 @@ func TestValidatorStaleFragment(t *testing.T) {
 		t.Fatalf("expected local fragment issue, got %v", issues)
 	})
 }
+
+func TestValidatorUniqueFields(t *testing.T) {
+	t.Parallel()
+	s := &schema.Schema{
+		Types: map[string]*schema.TypeDefinition{
+			"book": {
+				Fields: map[string]*schema.FieldDefinition{
+					"isbn":  {Type: schema.FieldTypeString, Unique: true},
+					"title": {Type: schema.FieldTypeString},
+				},
+			},
+		},
+		Traits: map[string]*schema.TraitDefinition{},
+	}
+	v := NewValidatorWithTypes(s, []ObjectInfo{
+		{ID: "books/dune", Type: "book", Fields: map[string]schema.FieldValue{"isbn": schema.String("9780441013593"), "title": schema.String("Dune")}},
+		{ID: "books/dune-copy", Type: "book", Fields: map[string]schema.FieldValue{"isbn": schema.String(" 9780441013593 "), "title": schema.String("Dune")}},
+		{ID: "books/emma", Type: "book", Fields: map[string]schema.FieldValue{"isbn": schema.String("9780141439587")}},
+	})
+
+	validate := func(id string, fields map[string]schema.FieldValue) []Issue {
+		return v.ValidateDocument(&parser.ParsedDocument{
+			FilePath: id + ".md",
+			Objects:  []*parser.ParsedObject{{ID: id, ObjectType: "book", Fields: fields}},
+		})
+	}
+
+	issues := validate("books/dune", map[string]schema.FieldValue{"isbn": schema.String("9780441013593"), "title": schema.String("Dune")})
+	if len(issues) != 1 || issues[0].Type != IssueDuplicateFieldValue || !strings.Contains(issues[0].Message, "books/dune-copy") {
+		t.Fatalf("expected one duplicate isbn issue naming books/dune-copy, got %+v", issues)
+	}
+
+	if issues := validate("books/emma", map[string]schema.FieldValue{"isbn": schema.String("9780141439587")}); len(issues) != 0 {
+		t.Fatalf("expected no issues for a unique isbn, got %+v", issues)
+	}
+}
+
+func TestValidatorRequiredTraits(t *testing.T) {
+	t.Parallel()
+	s := &schema.Schema{
+		Types: map[string]*schema.TypeDefinition{
+			"task": {RequiredTraits: []string{"status", "reviewed", "due"}},
+		},
+		Traits: map[string]*schema.TraitDefinition{
+			"status":   {Type: schema.FieldTypeEnum, Values: []string{"todo", "done"}, Default: "todo"},
+			"reviewed": {},
+			"due":      {Type: schema.FieldTypeDate},
+		},
+	}
+	v := NewValidator(s, []string{"tasks/docs"})
+
+	doc := &parser.ParsedDocument{
+		FilePath: "tasks/docs.md",
+		Objects:  []*parser.ParsedObject{{ID: "tasks/docs", ObjectType: "task", LineStart: 1}},
+		Traits: []*parser.ParsedTrait{
+			{TraitType: "reviewed", ParentObjectID: "tasks/docs#notes", Line: 8},
+		},
+	}
+
+	fixes := make(map[string]string)
+	for _, issue := range v.ValidateDocument(doc) {
+		if issue.Type == IssueMissingRequiredTrait {
+			fixes[issue.Value] = issue.FixCommand
+		}
+	}
+	if len(fixes) != 2 {
+		t.Fatalf("expected missing status and due, got %v", fixes)
+	}
+	if fixes["status"] != `rvn add "@status(todo)" --to tasks/docs` {
+		t.Errorf("status fix command = %q", fixes["status"])
+	}
+	if fixes["due"] != "" {
+		t.Errorf("due has no default, expected no fix command, got %q", fixes["due"])
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/aidanlsb/raven/internal/check"
 	"github.com/aidanlsb/raven/internal/config"
@@ -37,14 +38,17 @@ type Scope struct {
 
 type RunResult struct {
 	Scope             Scope
+	Rules             *Rules
 	FileCount         int
 	ErrorCount        int
 	WarningCount      int
 	Issues            []check.Issue
 	SchemaIssues      []check.SchemaIssue
 	StaleWarningShown bool
+	StaleFileCount    int
 	MissingRefs       []*check.MissingRef
 	UndefinedTraits   []*check.UndefinedTrait
+	ExternalRefs      []string
 	ShortRefs         map[string]string
 }
 
@@ -73,14 +77,24 @@ type CheckScopeJSON struct {
 	Value string `json:"value,omitempty"`
 }
 
+type CheckProfileJSON struct {
+	Name   string `json:"name,omitempty"`
+	Source string `json:"source"`
+	Stale  bool   `json:"stale,omitempty"`
+}
+
 type CheckResultJSON struct {
 	VaultPath  string             `json:"vault_path"`
 	Scope      *CheckScopeJSON    `json:"scope,omitempty"`
+	Profile    *CheckProfileJSON  `json:"profile,omitempty"`
 	FileCount  int                `json:"file_count"`
 	ErrorCount int                `json:"error_count"`
 	WarnCount  int                `json:"warning_count"`
 	Issues     []CheckIssueJSON   `json:"issues"`
 	Summary    []CheckSummaryJSON `json:"summary"`
+	// ExternalRefs lists unresolved targets inside sparse directories that are
+	// not checked out locally. They are not reported as issues.
+	ExternalRefs []string `json:"external_refs,omitempty"`
 }
 
 type CreateMissingResult struct {
@@ -100,7 +114,11 @@ func Run(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, opt
 		return nil, err
 	}
 
-	includeIssues, excludeIssues := parseIssueFilter(opts)
+	rules, err := LoadRules(vaultPath, vaultCfg.Check, nil)
+	if err != nil {
+		return nil, &ProfileError{Err: err}
+	}
+	filter := newIssueFilter(opts, rules)
 	excludeMatcher, err := ravenignore.NewMatcher(vaultCfg.GetExcludePatterns())
 	if err != nil {
 		return nil, fmt.Errorf("invalid exclude config: %w", err)
@@ -111,6 +129,7 @@ func Run(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, opt
 			Type:  scope.Type,
 			Value: scope.Value,
 		},
+		Rules: rules,
 	}
 
 	var allDocs []*parser.ParsedDocument
@@ -140,12 +159,25 @@ func Run(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, opt
 					FixCommand: "rvn reindex",
 					FixHint:    "Run 'rvn reindex' to update the index",
 				}
-				if shouldIncludeIssue(staleIssue, includeIssues, excludeIssues, opts.ErrorsOnly) {
+				if filter.keep(&staleIssue) {
 					allIssues = append(allIssues, staleIssue)
-					result.WarningCount++
 				}
 			}
 			result.StaleWarningShown = staleCount > 0
+			result.StaleFileCount = staleCount
+		}
+
+		if missing := db.Capabilities().Missing(); len(missing) > 0 && scope.Type == "full" {
+			capabilityIssue := check.Issue{
+				Level:   check.LevelWarning,
+				Type:    check.IssueMissingSQLiteCapability,
+				Message: fmt.Sprintf("SQLite build lacks %s; search and query features fall back to LIKE matching", strings.Join(missing, ", ")),
+				Value:   strings.Join(missing, ","),
+				FixHint: "Use a Raven build with the bundled SQLite driver for full-text search and matches()",
+			}
+			if filter.keep(&capabilityIssue) {
+				allIssues = append(allIssues, capabilityIssue)
+			}
 		}
 
 		aliases, _ = db.AllAliases()
@@ -169,8 +201,9 @@ func Run(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, opt
 
 	walkOpts := &vault.WalkOptions{
 		ParseOptions: &parser.ParseOptions{
-			ObjectsRoot: vaultCfg.GetObjectsRoot(),
-			PagesRoot:   vaultCfg.GetPagesRoot(),
+			ObjectsRoot:  vaultCfg.GetObjectsRoot(),
+			PagesRoot:    vaultCfg.GetPagesRoot(),
+			HashtagTrait: vaultCfg.GetHashtagTrait(),
 		},
 		ExcludeMatcher: excludeMatcher,
 	}
@@ -191,7 +224,7 @@ func Run(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, opt
 		}
 
 		for _, obj := range walkResult.Document.Objects {
-			allObjectInfos = append(allObjectInfos, check.ObjectInfo{ID: obj.ID, Type: obj.ObjectType})
+			allObjectInfos = append(allObjectInfos, check.ObjectInfo{ID: obj.ID, Type: obj.ObjectType, Fields: obj.Fields})
 		}
 
 		if isFileInScope(walkResult.Path, scope, walkPath, targetFileSet) {
@@ -211,6 +244,7 @@ func Run(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, opt
 	if vaultCfg.HasDirectoriesConfig() {
 		validator.SetDirectoryRoots(vaultCfg.GetObjectsRoot(), vaultCfg.GetPagesRoot())
 	}
+	validator.SetExternalPaths(vaultCfg.AbsentSparsePaths(vaultPath))
 	if canonicalResolver == nil {
 		validator.SetDailyDirectory(vaultCfg.GetDailyDirectory())
 	}
@@ -221,16 +255,11 @@ func Run(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, opt
 			if !isIssueInScope(issue, doc, scope) {
 				continue
 			}
-			if !shouldIncludeIssue(issue, includeIssues, excludeIssues, opts.ErrorsOnly) {
+			if !filter.keep(&issue) {
 				continue
 			}
 
 			allIssues = append(allIssues, issue)
-			if issue.Level == check.LevelWarning {
-				result.WarningCount++
-			} else {
-				result.ErrorCount++
-			}
 		}
 	}
 
@@ -239,35 +268,40 @@ func Run(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, opt
 		if doc != nil && !isIssueInScope(issue, doc, scope) {
 			continue
 		}
-		if !shouldIncludeIssue(issue, includeIssues, excludeIssues, opts.ErrorsOnly) {
+		if !filter.keep(&issue) {
 			continue
 		}
 		allIssues = append(allIssues, issue)
-		if issue.Level == check.LevelWarning {
-			result.WarningCount++
-		} else {
-			result.ErrorCount++
+	}
+
+	for _, issue := range detectNamingIssues(allDocs, rules, vaultCfg) {
+		if !filter.keep(&issue) {
+			continue
 		}
+		allIssues = append(allIssues, issue)
 	}
 
 	if db != nil && (scope.Type == "full" || scope.Type == "directory") {
 		for _, issue := range detectAssetIssues(db, vaultPath, excludeMatcher, scope, walkPath, targetFileSet) {
-			if !shouldIncludeIssue(issue, includeIssues, excludeIssues, opts.ErrorsOnly) {
+			if !filter.keep(&issue) {
 				continue
 			}
 			allIssues = append(allIssues, issue)
-			if issue.Level == check.LevelWarning {
-				result.WarningCount++
-			} else {
-				result.ErrorCount++
+		}
+	}
+
+	if db != nil {
+		for _, issue := range detectReviewIssues(db, sch, allDocs, time.Now()) {
+			if !filter.keep(&issue) {
+				continue
 			}
+			allIssues = append(allIssues, issue)
 		}
 	}
 
 	for _, pe := range parseErrors {
-		if shouldIncludeIssue(pe, includeIssues, excludeIssues, opts.ErrorsOnly) {
+		if filter.keep(&pe) {
 			allIssues = append([]check.Issue{pe}, allIssues...)
-			result.ErrorCount++
 		}
 	}
 
@@ -282,16 +316,26 @@ func Run(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, opt
 			if scope.Type == "trait_filter" && issue.Value != scope.Value {
 				continue
 			}
-			if !shouldIncludeSchemaIssue(issue, includeIssues, excludeIssues, opts.ErrorsOnly) {
+			if !filter.keepSchema(&issue) {
 				continue
 			}
 
 			schemaIssues = append(schemaIssues, issue)
-			if issue.Level == check.LevelWarning {
-				result.WarningCount++
-			} else {
-				result.ErrorCount++
-			}
+		}
+	}
+
+	for _, issue := range allIssues {
+		if issue.Level == check.LevelWarning {
+			result.WarningCount++
+		} else {
+			result.ErrorCount++
+		}
+	}
+	for _, issue := range schemaIssues {
+		if issue.Level == check.LevelWarning {
+			result.WarningCount++
+		} else {
+			result.ErrorCount++
 		}
 	}
 
@@ -299,6 +343,7 @@ func Run(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, opt
 	result.SchemaIssues = schemaIssues
 	result.MissingRefs = validator.MissingRefs()
 	result.UndefinedTraits = validator.UndefinedTraits()
+	result.ExternalRefs = validator.ExternalRefs()
 	result.ShortRefs = validator.ShortRefs()
 	sort.Slice(result.Issues, func(i, j int) bool {
 		a := result.Issues[i]
@@ -339,11 +384,12 @@ func Run(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, opt
 
 func BuildJSON(vaultPath string, result *RunResult) CheckResultJSON {
 	jsonResult := CheckResultJSON{
-		VaultPath:  vaultPath,
-		FileCount:  result.FileCount,
-		ErrorCount: result.ErrorCount,
-		WarnCount:  result.WarningCount,
-		Issues:     make([]CheckIssueJSON, 0, len(result.Issues)+len(result.SchemaIssues)),
+		VaultPath:    vaultPath,
+		FileCount:    result.FileCount,
+		ErrorCount:   result.ErrorCount,
+		WarnCount:    result.WarningCount,
+		Issues:       make([]CheckIssueJSON, 0, len(result.Issues)+len(result.SchemaIssues)),
+		ExternalRefs: result.ExternalRefs,
 	}
 	if result.Scope.Type != "" && result.Scope.Type != "full" {
 		jsonResult.Scope = &CheckScopeJSON{
@@ -351,6 +397,13 @@ func BuildJSON(vaultPath string, result *RunResult) CheckResultJSON {
 			Value: result.Scope.Value,
 		}
 	}
+	if result.Rules != nil && result.Rules.ProfileSource != "" {
+		jsonResult.Profile = &CheckProfileJSON{
+			Name:   result.Rules.ProfileName,
+			Source: result.Rules.ProfileSource,
+			Stale:  result.Rules.Stale,
+		}
+	}
 
 	for _, issue := range result.Issues {
 		jsonResult.Issues = append(jsonResult.Issues, CheckIssueJSON{
@@ -446,6 +499,61 @@ func summaryFix(issueType string, issues []check.Issue) (string, string) {
 	return "", ""
 }
 
+// detectReviewIssues reports in-scope objects whose type review_after window
+// has elapsed, using the file timestamps recorded in the index.
+func detectReviewIssues(db *index.Database, sch *schema.Schema, docs []*parser.ParsedDocument, now time.Time) []check.Issue {
+	if sch == nil || len(docs) == 0 {
+		return nil
+	}
+	var types []string
+	for name, typeDef := range sch.Types {
+		if _, _, ok := typeDef.ReviewPolicy(); ok {
+			types = append(types, name)
+		}
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	inScope := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		inScope[doc.FilePath] = true
+	}
+
+	items, err := db.ObjectFileTimes(types)
+	if err != nil {
+		return nil
+	}
+	var issues []check.Issue
+	for _, item := range items {
+		if !inScope[item.FilePath] {
+			continue
+		}
+		typeDef := sch.Types[item.Type]
+		var created time.Time
+		if item.Created > 0 {
+			created = time.Unix(item.Created, 0)
+		}
+		expiry, ok := typeDef.ReviewExpiry(time.Unix(item.Mtime, 0), created)
+		if !ok || !expiry.Before(now) {
+			continue
+		}
+		fixHint := "Review the object and save any updates to restart the review window"
+		if _, from, _ := typeDef.ReviewPolicy(); from == schema.ReviewFromCreated {
+			fixHint = "Review the object; the window counts from creation, so edits do not reset it"
+		}
+		issues = append(issues, check.Issue{
+			Level:    check.LevelWarning,
+			Type:     check.IssueReviewOverdue,
+			FilePath: item.FilePath,
+			Line:     item.LineStart,
+			Message:  fmt.Sprintf("%s is overdue for review (due %s, review_after %s)", item.ID, expiry.Format("2006-01-02"), typeDef.ReviewAfter),
+			Value:    item.ID,
+			FixHint:  fixHint,
+		})
+	}
+	return issues
+}
+
 func detectAssetIssues(db *index.Database, vaultPath string, excludeMatcher *ravenignore.Matcher, scope *Scope, walkPath string, targetFileSet map[string]bool) []check.Issue {
 	assets, err := db.QueryAssets()
 	if err != nil {
@@ -593,15 +701,27 @@ func resolveScope(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Sc
 	return scope, nil
 }
 
-func parseIssueFilter(opts Options) (include map[check.IssueType]bool, exclude map[check.IssueType]bool) {
-	include = make(map[check.IssueType]bool)
-	exclude = make(map[check.IssueType]bool)
+// issueFilter applies rule severities from the lint profile and raven.yaml,
+// then the --issues, --exclude, and --errors-only options.
+type issueFilter struct {
+	include    map[check.IssueType]bool
+	exclude    map[check.IssueType]bool
+	errorsOnly bool
+	rules      *Rules
+}
 
+func newIssueFilter(opts Options, rules *Rules) *issueFilter {
+	filter := &issueFilter{
+		include:    make(map[check.IssueType]bool),
+		exclude:    make(map[check.IssueType]bool),
+		errorsOnly: opts.ErrorsOnly,
+		rules:      rules,
+	}
 	if opts.Issues != "" {
 		for _, issueType := range strings.Split(opts.Issues, ",") {
 			issueType = strings.TrimSpace(issueType)
 			if issueType != "" {
-				include[check.IssueType(issueType)] = true
+				filter.include[check.IssueType(issueType)] = true
 			}
 		}
 	}
@@ -609,35 +729,57 @@ func parseIssueFilter(opts Options) (include map[check.IssueType]bool, exclude m
 		for _, issueType := range strings.Split(opts.Exclude, ",") {
 			issueType = strings.TrimSpace(issueType)
 			if issueType != "" {
-				exclude[check.IssueType(issueType)] = true
+				filter.exclude[check.IssueType(issueType)] = true
 			}
 		}
 	}
-
-	return include, exclude
+	return filter
 }
 
-func shouldIncludeIssue(issue check.Issue, include, exclude map[check.IssueType]bool, errorsOnly bool) bool {
-	if errorsOnly && issue.Level == check.LevelWarning {
+// keep sets the issue's level from the configured rules and reports whether
+// the issue should be reported.
+func (f *issueFilter) keep(issue *check.Issue) bool {
+	level, ok := f.level(issue.Type, issue.Level)
+	if !ok {
 		return false
 	}
-	if len(include) > 0 && !include[issue.Type] {
+	issue.Level = level
+	return f.allows(issue.Type, level)
+}
+
+// keepSchema is keep for schema issues.
+func (f *issueFilter) keepSchema(issue *check.SchemaIssue) bool {
+	level, ok := f.level(issue.Type, issue.Level)
+	if !ok {
 		return false
 	}
-	if exclude[issue.Type] {
-		return false
+	issue.Level = level
+	return f.allows(issue.Type, level)
+}
+
+// level returns the issue level after rules are applied; ok is false when
+// the rule turns the issue type off.
+func (f *issueFilter) level(issueType check.IssueType, level check.IssueLevel) (check.IssueLevel, bool) {
+	switch f.rules.Severity(issueType) {
+	case severityOff:
+		return level, false
+	case severityError:
+		return check.LevelError, true
+	case severityWarning:
+		return check.LevelWarning, true
+	default:
+		return level, true
 	}
-	return true
 }
 
-func shouldIncludeSchemaIssue(issue check.SchemaIssue, include, exclude map[check.IssueType]bool, errorsOnly bool) bool {
-	if errorsOnly && issue.Level == check.LevelWarning {
+func (f *issueFilter) allows(issueType check.IssueType, level check.IssueLevel) bool {
+	if f.errorsOnly && level == check.LevelWarning {
 		return false
 	}
-	if len(include) > 0 && !include[issue.Type] {
+	if len(f.include) > 0 && !f.include[issueType] {
 		return false
 	}
-	if exclude[issue.Type] {
+	if f.exclude[issueType] {
 		return false
 	}
 	return true
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aidanlsb/raven/internal/check"
 	"github.com/aidanlsb/raven/internal/config"
@@ -253,3 +254,100 @@ func hasIssue(issues []check.Issue, issueType check.IssueType) bool {
 	}
 	return false
 }
+
+func TestRun_SparsePathsMarkRefsExternal(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).
+		WithSchema(testutil.PersonProjectSchema()).
+		WithFile("work/plan.md", "# Plan\n\nSee [[archive/2019/retro]] and [[work/missing]].\n").
+		Build()
+
+	sch, err := schema.Load(vault.Path)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	vaultCfg := &config.VaultConfig{SparsePaths: []string{"archive"}}
+
+	missingTargets := func(result *RunResult) []string {
+		var targets []string
+		for _, issue := range result.Issues {
+			if issue.Type == check.IssueMissingReference {
+				targets = append(targets, issue.Value)
+			}
+		}
+		return targets
+	}
+
+	result, err := Run(vault.Path, vaultCfg, sch, Options{})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if got := missingTargets(result); len(got) != 1 || got[0] != "work/missing" {
+		t.Fatalf("missing references = %v, want only work/missing", got)
+	}
+	if len(result.ExternalRefs) != 1 || result.ExternalRefs[0] != "archive/2019/retro" {
+		t.Fatalf("external refs = %v, want [archive/2019/retro]", result.ExternalRefs)
+	}
+
+	// Once the sparse directory is checked out, its refs are validated normally.
+	if err := os.MkdirAll(filepath.Join(vault.Path, "archive"), 0o755); err != nil {
+		t.Fatalf("mkdir archive: %v", err)
+	}
+	result, err = Run(vault.Path, vaultCfg, sch, Options{})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if got := missingTargets(result); len(got) != 2 {
+		t.Fatalf("missing references = %v, want both targets once archive/ is present", got)
+	}
+	if len(result.ExternalRefs) != 0 {
+		t.Fatalf("external refs = %v, want none", result.ExternalRefs)
+	}
+}
+
+func TestRun_ReportsOverdueReviews(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).
+		WithSchema(`version: 1
+types:
+  person:
+    default_path: people/
+    review_after: 30d
+    fields:
+      name:
+        type: string
+`).
+		WithFile("people/freya.md", "---\ntype: person\nname: Freya\n---\n").
+		WithFile("people/thor.md", "---\ntype: person\nname: Thor\n---\n").
+		Build()
+	old := time.Now().AddDate(0, 0, -45)
+	if err := os.Chtimes(filepath.Join(vault.Path, "people/freya.md"), old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	reindexForTest(t, vault.Path)
+
+	cfg, err := config.LoadVaultConfig(vault.Path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	sch, err := schema.Load(vault.Path)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+
+	result, err := Run(vault.Path, cfg, sch, Options{})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	var overdue []check.Issue
+	for _, issue := range result.Issues {
+		if issue.Type == check.IssueReviewOverdue {
+			overdue = append(overdue, issue)
+		}
+	}
+	if len(overdue) != 1 || overdue[0].Value != "people/freya" || overdue[0].Level != check.LevelWarning {
+		t.Fatalf("review_overdue issues = %#v, want one warning for people/freya", overdue)
+	}
+}
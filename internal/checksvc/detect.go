@@ -1,7 +1,6 @@
 package checksvc
 
 import (
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -10,6 +9,7 @@ import (
 	"github.com/aidanlsb/raven/internal/index"
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 // DetectMissingRefs returns the page-style missing references found in the given
@@ -56,6 +56,7 @@ func DetectMissingRefs(vaultPath string, vaultCfg *config.VaultConfig, sch *sche
 	if vaultCfg.HasDirectoriesConfig() {
 		validator.SetDirectoryRoots(vaultCfg.GetObjectsRoot(), vaultCfg.GetPagesRoot())
 	}
+	validator.SetExternalPaths(vaultCfg.AbsentSparsePaths(vaultPath))
 
 	parseOpts := &parser.ParseOptions{
 		ObjectsRoot: vaultCfg.GetObjectsRoot(),
@@ -74,7 +75,7 @@ func DetectMissingRefs(vaultPath string, vaultCfg *config.VaultConfig, sch *sche
 		}
 		seen[absPath] = struct{}{}
 
-		content, readErr := os.ReadFile(absPath)
+		content, readErr := vaultcrypt.ReadFile(absPath)
 		if readErr != nil {
 			continue
 		}
@@ -9,10 +9,12 @@ import (
 
 	"github.com/aidanlsb/raven/internal/check"
 	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/history"
 	"github.com/aidanlsb/raven/internal/objectsvc"
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/paths"
 	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 type FixType string
@@ -177,7 +179,7 @@ func applyTextFixes(vaultPath string, fixes []FixableIssue) (FixResult, error) {
 		fileFixes := grouped[filePath]
 		fullPath := filepath.Join(vaultPath, filePath)
 
-		content, err := os.ReadFile(fullPath)
+		content, err := vaultcrypt.ReadFile(fullPath)
 		if err != nil {
 			return result, fmt.Errorf("failed to read %s: %w", filePath, err)
 		}
@@ -211,7 +213,12 @@ func applyTextFixes(vaultPath string, fixes []FixableIssue) (FixResult, error) {
 		}
 
 		if fixedCount > 0 {
-			if err := os.WriteFile(fullPath, []byte(newContent), 0o644); err != nil {
+			history.Capture(fullPath)
+			data, err := vaultcrypt.Seal(fullPath, []byte(newContent))
+			if err != nil {
+				return result, fmt.Errorf("failed to write %s: %w", filePath, err)
+			}
+			if err := os.WriteFile(fullPath, data, 0o644); err != nil {
 				return result, fmt.Errorf("failed to write %s: %w", filePath, err)
 			}
 			result.FileCount++
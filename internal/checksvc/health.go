@@ -0,0 +1,84 @@
+package checksvc
+
+import "github.com/aidanlsb/raven/internal/check"
+
+// Health category names, in scoring order.
+const (
+	HealthErrors      = "errors"
+	HealthWarnings    = "warnings"
+	HealthBrokenLinks = "broken_links"
+	HealthStaleIndex  = "stale_index"
+	HealthSchema      = "schema"
+)
+
+// healthWeights is the scoring model: each counted item deducts Points from
+// 100, and a category never deducts more than Max. The caps sum to 100 so a
+// single noisy category cannot hide the others.
+var healthWeights = []struct {
+	Name   string
+	Points int
+	Max    int
+}{
+	{HealthErrors, 5, 35},
+	{HealthWarnings, 1, 15},
+	{HealthBrokenLinks, 2, 25},
+	{HealthStaleIndex, 1, 10},
+	{HealthSchema, 3, 15},
+}
+
+// HealthCategory is one line of the health score breakdown.
+type HealthCategory struct {
+	Name       string `json:"name"`
+	Count      int    `json:"count"`
+	Points     int    `json:"points_per_item"`
+	MaxPenalty int    `json:"max_penalty"`
+	Penalty    int    `json:"penalty"`
+}
+
+// Health is a 0-100 score summarizing a full-vault check run.
+type Health struct {
+	Score      int              `json:"score"`
+	FileCount  int              `json:"file_count"`
+	Categories []HealthCategory `json:"categories"`
+}
+
+// ScoreHealth computes the vault health score from a check run.
+//
+// Broken links (missing references and assets) and the stale index are scored
+// in their own categories, so they are not also counted as errors or warnings.
+func ScoreHealth(result *RunResult) Health {
+	counts := map[string]int{
+		HealthStaleIndex: result.StaleFileCount,
+		HealthSchema:     len(result.SchemaIssues),
+	}
+	for _, issue := range result.Issues {
+		switch {
+		case issue.Type == check.IssueMissingReference || issue.Type == check.IssueMissingAsset:
+			counts[HealthBrokenLinks]++
+		case issue.Type == check.IssueStaleIndex:
+			// Counted per stale file above.
+		case issue.Level == check.LevelError:
+			counts[HealthErrors]++
+		default:
+			counts[HealthWarnings]++
+		}
+	}
+
+	health := Health{
+		Score:      100,
+		FileCount:  result.FileCount,
+		Categories: make([]HealthCategory, 0, len(healthWeights)),
+	}
+	for _, weight := range healthWeights {
+		category := HealthCategory{
+			Name:       weight.Name,
+			Count:      counts[weight.Name],
+			Points:     weight.Points,
+			MaxPenalty: weight.Max,
+		}
+		category.Penalty = min(category.Count*weight.Points, weight.Max)
+		health.Score -= category.Penalty
+		health.Categories = append(health.Categories, category)
+	}
+	return health
+}
@@ -0,0 +1,77 @@
+package checksvc
+
+import (
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/check"
+)
+
+func repeatIssues(n int, issue check.Issue) []check.Issue {
+	issues := make([]check.Issue, n)
+	for i := range issues {
+		issues[i] = issue
+	}
+	return issues
+}
+
+func TestScoreHealth(t *testing.T) {
+	t.Parallel()
+	errorIssue := check.Issue{Level: check.LevelError, Type: check.IssueUnknownType}
+	warningIssue := check.Issue{Level: check.LevelWarning, Type: check.IssueUnknownFrontmatter}
+	brokenLink := check.Issue{Level: check.LevelError, Type: check.IssueMissingReference}
+	staleIssue := check.Issue{Level: check.LevelWarning, Type: check.IssueStaleIndex}
+
+	tests := []struct {
+		name      string
+		result    *RunResult
+		wantScore int
+		wantCount map[string]int
+	}{
+		{
+			name:      "clean vault",
+			result:    &RunResult{FileCount: 10},
+			wantScore: 100,
+		},
+		{
+			name: "mixed issues",
+			result: &RunResult{
+				Issues:         append(append([]check.Issue{errorIssue, warningIssue, warningIssue, brokenLink}, staleIssue), errorIssue),
+				SchemaIssues:   []check.SchemaIssue{{Level: check.LevelWarning, Type: check.IssueUnusedType}},
+				StaleFileCount: 3,
+			},
+			// 2 errors (10) + 2 warnings (2) + 1 broken link (2) + 3 stale files (3) + 1 schema issue (3)
+			wantScore: 80,
+			wantCount: map[string]int{
+				HealthErrors:      2,
+				HealthWarnings:    2,
+				HealthBrokenLinks: 1,
+				HealthStaleIndex:  3,
+				HealthSchema:      1,
+			},
+		},
+		{
+			name: "category penalties are capped",
+			result: &RunResult{
+				Issues: append(repeatIssues(50, errorIssue), repeatIssues(40, brokenLink)...),
+			},
+			wantScore: 40,
+			wantCount: map[string]int{HealthErrors: 50, HealthBrokenLinks: 40},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			health := ScoreHealth(tt.result)
+			if health.Score != tt.wantScore {
+				t.Errorf("score = %d, want %d (%+v)", health.Score, tt.wantScore, health.Categories)
+			}
+			for _, category := range health.Categories {
+				if category.Count != tt.wantCount[category.Name] {
+					t.Errorf("%s count = %d, want %d", category.Name, category.Count, tt.wantCount[category.Name])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package checksvc
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/check"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/shellquote"
+)
+
+// detectNamingIssues flags files whose names do not follow the naming
+// convention for their type. Daily notes, templates, and protected
+// directories are exempt.
+func detectNamingIssues(docs []*parser.ParsedDocument, rules *Rules, vaultCfg *config.VaultConfig) []check.Issue {
+	if !rules.hasNaming() {
+		return nil
+	}
+
+	exempt := exemptDirs(vaultCfg)
+	var issues []check.Issue
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		relPath := paths.NormalizeVaultRelPath(doc.FilePath)
+		if relPath == "" || hasAnyPrefix(relPath, exempt) {
+			continue
+		}
+		fileObj := primaryFileObject(doc)
+		if fileObj == nil {
+			continue
+		}
+		style := rules.namingFor(fileObj.ObjectType)
+		if style == nil {
+			continue
+		}
+
+		base := strings.TrimSuffix(path.Base(relPath), ".md")
+		if style.pattern.MatchString(base) {
+			continue
+		}
+
+		issue := check.Issue{
+			Level:    check.LevelWarning,
+			Type:     check.IssueNamingConvention,
+			FilePath: relPath,
+			Line:     1,
+			Message:  fmt.Sprintf("File name %q does not follow the %s naming convention for type %q", base, style.name, displayType(fileObj.ObjectType)),
+			Value:    base,
+			FixHint:  fmt.Sprintf("Rename the file to match %s", style.name),
+		}
+		if suggestion := style.suggest(base); suggestion != "" {
+			dest := path.Join(path.Dir(fileObj.ID), suggestion)
+			issue.FixCommand = fmt.Sprintf("rvn move %s %s", quoteArg(fileObj.ID), quoteArg(dest))
+			issue.FixHint = fmt.Sprintf("Rename to %q", suggestion)
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteArg(s string) string {
+	if strings.ContainsAny(s, " \t") {
+		return shellquote.Quote(s)
+	}
+	return shellquote.QuoteIfNeeded(s)
+}
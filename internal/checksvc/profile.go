@@ -0,0 +1,286 @@
+package checksvc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/check"
+	"github.com/aidanlsb/raven/internal/config"
+)
+
+const (
+	profileFetchTimeout = 10 * time.Second
+	profileMaxBytes     = 1 << 20
+
+	severityError   = "error"
+	severityWarning = "warning"
+	severityOff     = "off"
+)
+
+// Profile is a shareable lint profile: issue severities and naming
+// conventions that a team publishes as a YAML file and vaults reference with
+// check.profile in raven.yaml.
+type Profile struct {
+	Name   string                    `yaml:"name,omitempty"`
+	Rules  map[string]string         `yaml:"rules,omitempty"`
+	Naming *config.NamingConventions `yaml:"naming,omitempty"`
+}
+
+// Rules are the effective check rules after merging the profile with the
+// vault's own check settings.
+type Rules struct {
+	// ProfileName and ProfileSource identify the profile in use, if any.
+	ProfileName   string
+	ProfileSource string
+	// Stale is set when a URL profile could not be fetched and the cached
+	// copy was used; StaleReason holds the fetch error.
+	Stale       bool
+	StaleReason string
+
+	severities    map[check.IssueType]string
+	defaultNaming *namingStyle
+	typeNaming    map[string]*namingStyle
+}
+
+// ProfileError reports that the check profile or check settings in raven.yaml
+// could not be loaded.
+type ProfileError struct {
+	Err error
+}
+
+func (e *ProfileError) Error() string { return e.Err.Error() }
+
+func (e *ProfileError) Unwrap() error { return e.Err }
+
+type namingStyle struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// LoadRules loads check.profile, if set, and applies check.rules and
+// check.naming from raven.yaml on top of it. URL profiles are cached in
+// .raven/cache/check-profiles/ and the cached copy is used when a fetch fails.
+func LoadRules(vaultPath string, cfg *config.CheckConfig, client *http.Client) (*Rules, error) {
+	rules := &Rules{
+		severities: map[check.IssueType]string{},
+		typeNaming: map[string]*namingStyle{},
+	}
+	if cfg == nil {
+		return rules, nil
+	}
+
+	if source := strings.TrimSpace(cfg.Profile); source != "" {
+		profile, staleReason, err := loadProfile(vaultPath, source, client)
+		if err != nil {
+			return nil, err
+		}
+		rules.ProfileName = profile.Name
+		rules.ProfileSource = source
+		rules.Stale = staleReason != ""
+		rules.StaleReason = staleReason
+		if err := rules.apply(profile.Rules, profile.Naming); err != nil {
+			return nil, fmt.Errorf("profile %s: %w", source, err)
+		}
+	}
+
+	if err := rules.apply(cfg.Rules, cfg.Naming); err != nil {
+		return nil, fmt.Errorf("raven.yaml check: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *Rules) apply(severities map[string]string, naming *config.NamingConventions) error {
+	known := make(map[check.IssueType]bool)
+	for _, issueType := range check.AllIssueTypes() {
+		known[issueType] = true
+	}
+
+	keys := make([]string, 0, len(severities))
+	for key := range severities {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		issueType := check.IssueType(strings.TrimSpace(key))
+		if !known[issueType] {
+			return fmt.Errorf("unknown issue type %q in rules", key)
+		}
+		severity := strings.ToLower(strings.TrimSpace(severities[key]))
+		switch severity {
+		case severityError, severityWarning, severityOff:
+			r.severities[issueType] = severity
+		default:
+			return fmt.Errorf("rule %s: severity must be error, warning, or off (got %q)", key, severities[key])
+		}
+	}
+
+	if naming == nil {
+		return nil
+	}
+	if strings.TrimSpace(naming.Default) != "" {
+		style, err := parseNamingStyle(naming.Default)
+		if err != nil {
+			return fmt.Errorf("naming.default: %w", err)
+		}
+		r.defaultNaming = style
+	}
+	for typeName, raw := range naming.Types {
+		style, err := parseNamingStyle(raw)
+		if err != nil {
+			return fmt.Errorf("naming.types.%s: %w", typeName, err)
+		}
+		r.typeNaming[typeName] = style
+	}
+	return nil
+}
+
+// Severity returns the configured severity for an issue type, or "" when the
+// issue keeps its built-in level.
+func (r *Rules) Severity(issueType check.IssueType) string {
+	if r == nil {
+		return ""
+	}
+	return r.severities[issueType]
+}
+
+// hasNaming reports whether any naming convention is configured.
+func (r *Rules) hasNaming() bool {
+	return r != nil && (r.defaultNaming != nil || len(r.typeNaming) > 0)
+}
+
+// namingFor returns the naming style for objects of typeName.
+func (r *Rules) namingFor(typeName string) *namingStyle {
+	if style, ok := r.typeNaming[typeName]; ok {
+		return style
+	}
+	return r.defaultNaming
+}
+
+var namedStyles = map[string]*regexp.Regexp{
+	"kebab-case": regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`),
+	"snake_case": regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`),
+	"lowercase":  regexp.MustCompile(`^[^A-Z]*$`),
+}
+
+func parseNamingStyle(raw string) (*namingStyle, error) {
+	raw = strings.TrimSpace(raw)
+	if pattern, ok := namedStyles[raw]; ok {
+		return &namingStyle{name: raw, pattern: pattern}, nil
+	}
+	pattern, err := regexp.Compile("^(?:" + raw + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("%q is not kebab-case, snake_case, lowercase, or a valid regular expression: %w", raw, err)
+	}
+	return &namingStyle{name: raw, pattern: pattern}, nil
+}
+
+// suggest returns a conforming file name for the named styles, or "" when
+// the style is a custom pattern.
+func (s *namingStyle) suggest(base string) string {
+	var suggestion string
+	switch s.name {
+	case "kebab-case":
+		suggestion = kebabName(base)
+	case "snake_case":
+		suggestion = strings.ReplaceAll(kebabName(base), "-", "_")
+	case "lowercase":
+		suggestion = strings.ToLower(base)
+	default:
+		return ""
+	}
+	if suggestion == "" || !s.pattern.MatchString(suggestion) {
+		return ""
+	}
+	return suggestion
+}
+
+var nonKebabChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+func kebabName(base string) string {
+	return strings.Trim(nonKebabChars.ReplaceAllString(strings.ToLower(base), "-"), "-")
+}
+
+// loadProfile reads a profile from a vault-relative path or an http(s) URL.
+// The returned stale reason is set when a URL fetch failed and the cached copy
+// was used instead.
+func loadProfile(vaultPath, source string, client *http.Client) (*Profile, string, error) {
+	var data []byte
+	var staleReason string
+	if isProfileURL(source) {
+		fetched, err := fetchProfile(source, client)
+		cachePath := profileCachePath(vaultPath, source)
+		if err != nil {
+			cached, readErr := os.ReadFile(cachePath)
+			if readErr != nil {
+				return nil, "", fmt.Errorf("failed to fetch check profile %s: %w", source, err)
+			}
+			data, staleReason = cached, err.Error()
+		} else {
+			data = fetched
+			if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err == nil {
+				// The cache only covers later fetch failures; failing to
+				// write it is not worth surfacing.
+				_ = atomicfile.WriteFile(cachePath, data, 0o644)
+			}
+		}
+	} else {
+		path := source
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(vaultPath, filepath.FromSlash(path))
+		}
+		read, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read check profile %s: %w", source, err)
+		}
+		data = read
+	}
+
+	var profile Profile
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return nil, "", fmt.Errorf("invalid check profile %s: %w", source, err)
+	}
+	return &profile, staleReason, nil
+}
+
+func isProfileURL(source string) bool {
+	return strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://")
+}
+
+func fetchProfile(url string, client *http.Client) ([]byte, error) {
+	if client == nil {
+		client = &http.Client{Timeout: profileFetchTimeout}
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, profileMaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > profileMaxBytes {
+		return nil, fmt.Errorf("profile is larger than %d bytes", profileMaxBytes)
+	}
+	return data, nil
+}
+
+func profileCachePath(vaultPath, url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(vaultPath, ".raven", "cache", "check-profiles", hex.EncodeToString(sum[:8])+".yaml")
+}
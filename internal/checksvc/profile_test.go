@@ -0,0 +1,130 @@
+package checksvc
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/check"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+const teamProfile = `name: team-hygiene
+rules:
+  missing_required_field: warning
+  unused_trait: off
+naming:
+  default: kebab-case
+`
+
+func TestRun_AppliesProfileRulesAndLocalOverrides(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).
+		WithSchema(testutil.PersonProjectSchema()).
+		WithFile("lint/team.yaml", teamProfile).
+		WithFile("people/Freya Smith.md", "---\ntype: person\n---\n").
+		Build()
+	sch, err := schema.Load(vault.Path)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+
+	cfg := &config.VaultConfig{Check: &config.CheckConfig{
+		Profile: "lint/team.yaml",
+		Rules:   map[string]string{"naming_convention": "error"},
+	}}
+	result, err := Run(vault.Path, cfg, sch, Options{})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	levels := map[check.IssueType]check.IssueLevel{}
+	var naming check.Issue
+	for _, issue := range result.Issues {
+		levels[issue.Type] = issue.Level
+		if issue.Type == check.IssueNamingConvention {
+			naming = issue
+		}
+	}
+	if level, ok := levels[check.IssueMissingRequiredField]; !ok || level != check.LevelWarning {
+		t.Errorf("missing_required_field level = %v (reported %v), want warning from profile", level, ok)
+	}
+	if level, ok := levels[check.IssueNamingConvention]; !ok || level != check.LevelError {
+		t.Errorf("naming_convention level = %v (reported %v), want error from raven.yaml override", level, ok)
+	}
+	if naming.FixCommand != `rvn move 'people/Freya Smith' people/freya-smith` {
+		t.Errorf("naming fix command = %q", naming.FixCommand)
+	}
+	for _, issue := range result.SchemaIssues {
+		if issue.Type == check.IssueUnusedTrait {
+			t.Errorf("unused_trait reported, want it turned off by profile")
+		}
+	}
+	// The naming error, the downgraded required field, and unused_type.
+	if result.ErrorCount != 1 || result.WarningCount != 2 {
+		t.Errorf("counts = %d errors, %d warnings; want 1 and 2", result.ErrorCount, result.WarningCount)
+	}
+
+	jsonResult := BuildJSON(vault.Path, result)
+	if jsonResult.Profile == nil || jsonResult.Profile.Name != "team-hygiene" || jsonResult.Profile.Source != "lint/team.yaml" {
+		t.Errorf("json profile = %+v", jsonResult.Profile)
+	}
+}
+
+func TestLoadRules_URLProfileFallsBackToCache(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(teamProfile))
+	}))
+	vaultPath := t.TempDir()
+	cfg := &config.CheckConfig{Profile: server.URL + "/team.yaml"}
+
+	rules, err := LoadRules(vaultPath, cfg, server.Client())
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+	if rules.Stale || rules.Severity(check.IssueUnusedTrait) != severityOff {
+		t.Fatalf("rules = %+v, want fresh profile with unused_trait off", rules)
+	}
+
+	server.Close()
+	rules, err = LoadRules(vaultPath, cfg, nil)
+	if err != nil {
+		t.Fatalf("LoadRules() after server closed error = %v", err)
+	}
+	if !rules.Stale || rules.ProfileName != "team-hygiene" {
+		t.Fatalf("rules = %+v, want stale cached profile", rules)
+	}
+
+	if _, err := LoadRules(t.TempDir(), cfg, nil); err == nil {
+		t.Fatal("LoadRules() without cache succeeded, want fetch error")
+	}
+}
+
+func TestRun_RejectsInvalidCheckRules(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).WithSchema(testutil.PersonProjectSchema()).Build()
+	sch, err := schema.Load(vault.Path)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+
+	for _, checkCfg := range []*config.CheckConfig{
+		{Rules: map[string]string{"not_a_rule": "error"}},
+		{Rules: map[string]string{"unused_type": "loud"}},
+		{Naming: &config.NamingConventions{Default: "([a-z"}},
+		{Profile: "missing.yaml"},
+	} {
+		_, err := Run(vault.Path, &config.VaultConfig{Check: checkCfg}, sch, Options{})
+		var profileErr *ProfileError
+		if !errors.As(err, &profileErr) {
+			t.Errorf("Run(%+v) error = %v, want ProfileError", checkCfg, err)
+		}
+	}
+}
@@ -14,6 +14,8 @@ import (
 var (
 	addToFlag      string
 	addHeadingFlag string
+	addPosition    string
+	addLine        int
 	addStdin       bool
 	addConfirm     bool
 )
@@ -21,7 +23,7 @@ var (
 var addCmd = newCanonicalLeafCommand("add", canonicalLeafOptions{
 	VaultPath:       getVaultPath,
 	Args:            cobra.ArbitraryArgs,
-	BuildArgs:       buildAddArgs,
+	BuildArgs:       withUnlockArg(buildAddArgs),
 	Invoke:          invokeAdd,
 	RenderHuman:     renderAddResult,
 	SkipFlagBinding: true,
@@ -51,6 +53,7 @@ func buildAddArgs(cmd *cobra.Command, args []string) (map[string]interface{}, er
 		if headingSpec := effectiveAddHeadingSpec(); headingSpec != "" {
 			argsMap["heading"] = headingSpec
 		}
+		addPlacementArgs(cmd, argsMap)
 		return argsMap, nil
 	}
 
@@ -68,9 +71,19 @@ func buildAddArgs(cmd *cobra.Command, args []string) (map[string]interface{}, er
 	if headingSpec := effectiveAddHeadingSpec(); headingSpec != "" {
 		argsMap["heading"] = headingSpec
 	}
+	addPlacementArgs(cmd, argsMap)
 	return argsMap, nil
 }
 
+func addPlacementArgs(cmd *cobra.Command, argsMap map[string]interface{}) {
+	if position := strings.TrimSpace(addPosition); position != "" {
+		argsMap["position"] = position
+	}
+	if cmd.Flags().Changed("line") {
+		argsMap["line"] = addLine
+	}
+}
+
 func invokeAdd(_ *cobra.Command, commandID, vaultPath string, args map[string]interface{}) commandexec.Result {
 	return executeCanonicalRequest(commandexec.Request{
 		CommandID: commandID,
@@ -120,6 +133,8 @@ func buildCreateObjectCommand(typeName, targetRaw string) string {
 func init() {
 	addCmd.Flags().StringVar(&addToFlag, "to", "", "Target file (path or reference like 'cursor')")
 	addCmd.Flags().StringVar(&addHeadingFlag, "heading", "", "Target heading within destination (heading slug, object#heading ID, or markdown heading text)")
+	addCmd.Flags().StringVar(&addPosition, "position", "", "Where to insert within the target section: end (default) or after-heading")
+	addCmd.Flags().IntVar(&addLine, "line", 0, "Append the text to the end of this existing line (1-indexed)")
 	addCmd.Flags().BoolVar(&addStdin, "stdin", false, "Read object IDs from stdin (one per line)")
 	addCmd.Flags().BoolVar(&addConfirm, "confirm", false, "Apply changes (without this flag, shows preview only)")
 	if err := addCmd.RegisterFlagCompletionFunc("to", completeReferenceFlag(true)); err != nil {
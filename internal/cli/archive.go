@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var archiveCmd = newCanonicalLeafCommand("archive", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderArchiveResult,
+})
+
+func renderArchiveResult(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	state := stringValue(data["state"])
+	if previous := stringValue(data["previous_state"]); previous != "" && previous != state {
+		state = previous + " → " + state
+	}
+	fmt.Println(ui.Checkf("Archived %s (%s)", ui.FilePath(stringValue(data["file"])), state))
+	if boolValue(data["moved"]) {
+		fmt.Printf("  %s %s → %s\n", ui.Hint("Moved:"), ui.FilePath(stringValue(data["old_path"])), ui.FilePath(stringValue(data["new_path"])))
+	}
+	if updatedRefs := stringSliceFromAny(data["updated_refs"]); len(updatedRefs) > 0 {
+		fmt.Printf("  %s\n", ui.Hint(fmt.Sprintf("Updated %d references", len(updatedRefs))))
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("  %s\n", ui.Warning(warning.Message))
+	}
+	return nil
+}
+
+func init() {
+	archiveCmd.ValidArgsFunction = completeReferenceArgAt(0, referenceCompletionOptions{
+		IncludeDynamicDates: false,
+		NonTargetDirective:  cobra.ShellCompDirectiveNoFileComp,
+	})
+	rootCmd.AddCommand(archiveCmd)
+}
@@ -51,14 +51,21 @@ func buildBacklinksArgs(cmd *cobra.Command, args []string) (map[string]interface
 		if len(targets) == 0 {
 			return nil, fmt.Errorf("no targets provided on stdin")
 		}
-		return map[string]interface{}{
+		return withBacklinksGroupBy(cmd, map[string]interface{}{
 			"stdin":   true,
 			"targets": targets,
-		}, nil
+		}), nil
 	}
-	return map[string]interface{}{
+	return withBacklinksGroupBy(cmd, map[string]interface{}{
 		"target": args[0],
-	}, nil
+	}), nil
+}
+
+func withBacklinksGroupBy(cmd *cobra.Command, args map[string]interface{}) map[string]interface{} {
+	if groupBy, _ := cmd.Flags().GetString("group-by"); groupBy != "" {
+		args["group-by"] = groupBy
+	}
+	return args
 }
 
 func handleBacklinksFailure(cmd *cobra.Command, result commandexec.Result) error {
@@ -66,7 +73,10 @@ func handleBacklinksFailure(cmd *cobra.Command, result commandexec.Result) error
 		CommandID: "backlinks",
 		ArgKey:    "target",
 		Prompt:    "backlinks/ref> ",
-		Render:    renderBacklinks,
+		BuildArgs: func(cmd *cobra.Command, selected string) (map[string]interface{}, error) {
+			return withBacklinksGroupBy(cmd, map[string]interface{}{"target": selected}), nil
+		},
+		Render: renderBacklinks,
 	})
 }
 
@@ -95,6 +105,10 @@ func renderBacklinks(cmd *cobra.Command, result commandexec.Result) error {
 		}
 		return browseAndOpenReferences("Backlinks to "+target, browseItemsForBacklinkResults(links))
 	}
+	if groups, ok := data["groups"].([]model.ReferenceTypeGroup); ok {
+		printBacklinksByType(target, groups)
+		return nil
+	}
 	printBacklinksResults(target, links)
 	return nil
 }
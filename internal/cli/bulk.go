@@ -166,7 +166,7 @@ func PrintBulkPreview(preview *BulkPreview) {
 // PrintBulkSummary prints a human-readable summary of completed bulk operations.
 func PrintBulkSummary(summary *BulkSummary) {
 	switch summary.Action {
-	case "set":
+	case "set", "toggle":
 		fmt.Println(ui.Checkf("Updated %d objects", summary.Modified))
 	case "delete":
 		fmt.Println(ui.Checkf("Deleted %d objects", summary.Deleted))
@@ -181,13 +181,18 @@ func PrintBulkSummary(summary *BulkSummary) {
 	}
 	if summary.Errors > 0 {
 		fmt.Printf("  %s\n", ui.Errorf("%d errors", summary.Errors))
+		for _, result := range summary.Results {
+			if result.Status == "error" {
+				fmt.Println(ui.Indent(2, ui.Bullet(fmt.Sprintf("%s: %s", result.ID, result.Reason))))
+			}
+		}
 	}
 }
 
 // getActionVerb returns the past tense verb for an action.
 func getActionVerb(action string) string {
 	switch action {
-	case "set":
+	case "set", "toggle":
 		return "modified"
 	case "delete":
 		return "deleted"
@@ -52,7 +52,7 @@ func renderCanonicalBulkResult(result commandexec.Result) error {
 			return handleError(ErrInternal, err, "")
 		}
 		printTraitBulkSummary(&summary)
-		return nil
+		return resolveBulkFailures(data)
 	}
 
 	summary := &BulkSummary{
@@ -70,7 +70,7 @@ func renderCanonicalBulkResult(result commandexec.Result) error {
 	for _, warning := range result.Warnings {
 		fmt.Println(ui.Warning(warning.Message))
 	}
-	return nil
+	return resolveBulkFailures(data)
 }
 
 func decodeBulkPreviewItems(raw interface{}) []BulkPreviewItem {
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+// bulkFailure is one item an applied bulk operation could not change.
+type bulkFailure struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type bulkFailureAction string
+
+const (
+	bulkFailureRetry bulkFailureAction = "retry"
+	bulkFailureSkip  bulkFailureAction = "skip"
+	bulkFailureAbort bulkFailureAction = "abort"
+)
+
+// resolveBulkFailures walks the failed items of an applied bulk operation in an
+// interactive terminal. Each item can be retried, skipped, or the walk aborted;
+// retried items are re-applied together afterwards so fixes made in the
+// meantime are picked up.
+func resolveBulkFailures(data map[string]interface{}) error {
+	if !shouldPromptForConfirm() {
+		return nil
+	}
+	retryWith, _ := data["retry_with"].(map[string]interface{})
+	var failures []bulkFailure
+	_ = decodeResultData(data["failures"], &failures)
+	if retryWith == nil || len(failures) == 0 {
+		return nil
+	}
+
+	commandID, _ := retryWith["command"].(string)
+	retryArgs, _ := retryWith["args"].(map[string]interface{})
+	idsKey := bulkRetryIDsKey(retryArgs)
+	if commandID == "" || idsKey == "" {
+		return nil
+	}
+
+	interaction := newCheckInteraction(os.Stdin, os.Stdout)
+	retryIDs, remaining := promptBulkFailureActions(interaction, failures)
+	if len(remaining) > 0 {
+		interaction.Println(ui.Warningf("Stopped with %d unresolved item(s):", len(remaining)))
+		for _, id := range remaining {
+			interaction.Println(ui.Indent(2, id))
+		}
+		interaction.Println(ui.Hint("Pipe these IDs back with --stdin --confirm to resume."))
+	}
+	if len(retryIDs) == 0 {
+		return nil
+	}
+
+	args := copyArgsMap(retryArgs)
+	args[idsKey] = stringsToAny(retryIDs)
+	args["confirm"] = true
+	result := executeCanonicalCommand(commandID, getVaultPath(), args)
+	if !result.OK {
+		if result.Error != nil {
+			return handleErrorWithDetails(result.Error.Code, result.Error.Message, result.Error.Suggestion, result.Error.Details)
+		}
+		return handleErrorMsg(ErrInternal, "command execution failed", "")
+	}
+	return renderCanonicalBulkResult(result)
+}
+
+// promptBulkFailureActions asks what to do with each failed item. It returns
+// the IDs to retry and, when the user aborts, the IDs that were not visited.
+func promptBulkFailureActions(interaction checkInteraction, failures []bulkFailure) (retryIDs []string, remaining []string) {
+	interaction.Println()
+	interaction.Println(ui.SectionHeader(fmt.Sprintf("Resolve %d failed item(s)", len(failures))))
+	for i, failure := range failures {
+		interaction.Println(ui.Bullet(fmt.Sprintf("%s: %s", failure.ID, failure.Reason)))
+		interaction.Printf("  %s ", ui.Hint("[r]etry after fixing, [s]kip, [a]bort"))
+		switch parseBulkFailureAction(readTrimmedLowerLine(interaction)) {
+		case bulkFailureRetry:
+			retryIDs = append(retryIDs, failure.ID)
+		case bulkFailureAbort:
+			for _, rest := range failures[i:] {
+				remaining = append(remaining, rest.ID)
+			}
+			return retryIDs, remaining
+		}
+	}
+	return retryIDs, nil
+}
+
+func parseBulkFailureAction(answer string) bulkFailureAction {
+	switch answer {
+	case "r", "retry":
+		return bulkFailureRetry
+	case "a", "abort", "q", "quit":
+		return bulkFailureAbort
+	default:
+		return bulkFailureSkip
+	}
+}
+
+func bulkRetryIDsKey(args map[string]interface{}) string {
+	for _, key := range []string{"object_ids", "trait_ids"} {
+		if _, ok := args[key]; ok {
+			return key
+		}
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPromptBulkFailureActions(t *testing.T) {
+	failures := []bulkFailure{
+		{ID: "people/freya", Reason: "invalid value"},
+		{ID: "people/thor", Reason: "ambiguous reference"},
+		{ID: "people/loki", Reason: "invalid value"},
+		{ID: "people/odin", Reason: "invalid value"},
+	}
+
+	tests := []struct {
+		name          string
+		inputs        []string
+		wantRetry     []string
+		wantRemaining []string
+	}{
+		{
+			name:      "retry and skip every item",
+			inputs:    []string{"r\n", "s\n", "retry\n", "\n"},
+			wantRetry: []string{"people/freya", "people/loki"},
+		},
+		{
+			name:          "abort keeps unvisited items",
+			inputs:        []string{"r\n", "a\n"},
+			wantRetry:     []string{"people/freya"},
+			wantRemaining: []string{"people/thor", "people/loki", "people/odin"},
+		},
+		{
+			name:   "end of input skips remaining items",
+			inputs: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			interaction := &fakeCheckInteraction{inputs: tc.inputs}
+			retry, remaining := promptBulkFailureActions(interaction, failures)
+			if !reflect.DeepEqual(retry, tc.wantRetry) {
+				t.Fatalf("retry = %#v, want %#v", retry, tc.wantRetry)
+			}
+			if !reflect.DeepEqual(remaining, tc.wantRemaining) {
+				t.Fatalf("remaining = %#v, want %#v", remaining, tc.wantRemaining)
+			}
+		})
+	}
+}
@@ -71,6 +71,11 @@ func printTraitBulkSummary(summary *TraitBulkSummary) {
 	}
 	if summary.Errors > 0 {
 		fmt.Printf("  %s\n", ui.Errorf("%d errors", summary.Errors))
+		for _, result := range summary.Results {
+			if result.Status == "error" {
+				fmt.Println(ui.Indent(2, ui.Bullet(fmt.Sprintf("%s: %s", result.ID, result.Reason))))
+			}
+		}
 	}
 }
 
@@ -212,6 +212,24 @@ func bindMetaFlags(cmd *cobra.Command, flags []commands.FlagMeta) {
 	}
 }
 
+// withUnlockArg forwards the --unlock and --merge flags for commands whose
+// custom arg builders only map their own flags.
+func withUnlockArg(build func(cmd *cobra.Command, args []string) (map[string]interface{}, error)) func(cmd *cobra.Command, args []string) (map[string]interface{}, error) {
+	return func(cmd *cobra.Command, args []string) (map[string]interface{}, error) {
+		argsMap, err := build(cmd, args)
+		if err != nil || argsMap == nil {
+			return argsMap, err
+		}
+		if unlock, _ := cmd.Flags().GetBool("unlock"); unlock {
+			argsMap["unlock"] = true
+		}
+		if merge, _ := cmd.Flags().GetBool("merge"); merge {
+			argsMap["merge"] = true
+		}
+		return argsMap, nil
+	}
+}
+
 func buildCanonicalArgsForMeta(meta commands.Meta, cmd *cobra.Command, args []string) (map[string]interface{}, error) {
 	argsMap := make(map[string]interface{}, len(meta.Args)+len(meta.Flags))
 	for i, arg := range meta.Args {
@@ -311,32 +311,46 @@ func decodeCanonicalCheckJSON(result commandexec.Result) (CheckResultJSON, bool)
 	return decoded, true
 }
 
+// printCheckTotals prints the closing line for a check run, marked with the
+// status symbol of the most severe issue level found.
+func printCheckTotals(decoded CheckResultJSON) {
+	switch {
+	case decoded.ErrorCount > 0:
+		fmt.Println(ui.Errorf("Found %d error(s), %d warning(s) in %d files.", decoded.ErrorCount, decoded.WarnCount, decoded.FileCount))
+	case decoded.WarnCount > 0:
+		fmt.Println(ui.Warningf("Found %d error(s), %d warning(s) in %d files.", decoded.ErrorCount, decoded.WarnCount, decoded.FileCount))
+	default:
+		fmt.Println(ui.Starf("No issues found in %d files.", decoded.FileCount))
+	}
+}
+
 func renderCanonicalCheckValidate(result commandexec.Result) {
 	decoded, ok := decodeCanonicalCheckJSON(result)
 	if !ok {
 		fmt.Println(ui.Warning("failed to decode check results"))
 		return
 	}
+	defer printCheckWarnings(result.Warnings)
+	defer printExternalRefsNote(decoded.ExternalRefs)
+	if profile := decoded.Profile; profile != nil {
+		name := profile.Source
+		if profile.Name != "" {
+			name = fmt.Sprintf("%s (%s)", profile.Name, profile.Source)
+		}
+		fmt.Printf("Profile: %s\n", ui.Muted.Render(name))
+	}
 
 	if checkByFile {
 		printIssuesByFileFromJSON(decoded.Issues)
 		fmt.Println()
-		if decoded.ErrorCount == 0 && decoded.WarnCount == 0 {
-			fmt.Println(ui.Starf("No issues found in %d files.", decoded.FileCount))
-		} else {
-			fmt.Printf("Found %d error(s), %d warning(s) in %d files.\n", decoded.ErrorCount, decoded.WarnCount, decoded.FileCount)
-		}
+		printCheckTotals(decoded)
 		return
 	}
 
 	if checkVerbose {
 		printIssuesVerboseFromJSON(decoded.Issues)
 		fmt.Println()
-		if decoded.ErrorCount == 0 && decoded.WarnCount == 0 {
-			fmt.Println(ui.Starf("No issues found in %d files.", decoded.FileCount))
-		} else {
-			fmt.Printf("Found %d error(s), %d warning(s) in %d files.\n", decoded.ErrorCount, decoded.WarnCount, decoded.FileCount)
-		}
+		printCheckTotals(decoded)
 		return
 	}
 
@@ -347,10 +361,31 @@ func renderCanonicalCheckValidate(result commandexec.Result) {
 	}
 	printIssueSummaryFromJSON(decoded.Summary, decoded.Issues)
 	fmt.Println()
-	fmt.Printf("Found %d error(s), %d warning(s) in %d files.\n", decoded.ErrorCount, decoded.WarnCount, decoded.FileCount)
+	printCheckTotals(decoded)
 	fmt.Println(ui.Hint("Use --verbose to see all issues, or --by-file to group by file."))
 }
 
+// printCheckWarnings prints warnings about the run itself, such as schema
+// changes the index has not caught up with.
+func printCheckWarnings(warnings []commandexec.Warning) {
+	for _, warning := range warnings {
+		fmt.Println(ui.Warning(warning.Message))
+	}
+}
+
+// printExternalRefsNote mentions references into sparse directories that are
+// not checked out, which check skips instead of reporting as missing.
+func printExternalRefsNote(refs []string) {
+	if len(refs) == 0 {
+		return
+	}
+	noun := "references"
+	if len(refs) == 1 {
+		noun = "reference"
+	}
+	fmt.Println(ui.Hint(fmt.Sprintf("%d external %s into sparse paths not checked out locally were skipped.", len(refs), noun)))
+}
+
 func renderCanonicalCheckFix(result commandexec.Result) {
 	data := canonicalDataMap(result)
 	fixableIssues := intValue(data["fixable_issues"])
@@ -509,11 +544,7 @@ func printIssuesByFileFromJSON(issues []CheckIssueJSON) {
 	}
 
 	for _, issue := range globalIssues {
-		if issue.Level == "warning" {
-			fmt.Println(ui.Warning(issue.Message))
-		} else {
-			fmt.Println(ui.Error(issue.Message))
-		}
+		fmt.Printf("%s %s\n", ui.IssueSymbol(issue.Level), issue.Message)
 	}
 	if len(globalIssues) > 0 {
 		fmt.Println()
@@ -537,17 +568,13 @@ func printIssuesByFileFromJSON(issues []CheckIssueJSON) {
 		}
 
 		countBadge := ui.Muted.Render(ui.ErrorWarningCounts(errCount, warnCount))
-		fmt.Printf("%s %s:\n", ui.FilePath(filePath), countBadge)
+		fmt.Printf("%s %s:\n", formatFileLink(filePath), countBadge)
 		sort.Slice(fileIssues, func(i, j int) bool {
 			return fileIssues[i].Line < fileIssues[j].Line
 		})
 		for _, issue := range fileIssues {
-			symbol := ui.SymbolError
-			if issue.Level == "warning" {
-				symbol = ui.SymbolWarning
-			}
 			lineNum := ui.Muted.Render(fmt.Sprintf("L%d", issue.Line))
-			fmt.Printf("  %s %s %s\n", symbol, lineNum, issue.Message)
+			fmt.Printf("  %s %s %s\n", ui.IssueSymbol(issue.Level), lineNum, issue.Message)
 		}
 		fmt.Println()
 	}
@@ -555,19 +582,16 @@ func printIssuesByFileFromJSON(issues []CheckIssueJSON) {
 
 func printIssuesVerboseFromJSON(issues []CheckIssueJSON) {
 	for _, issue := range issues {
-		symbol := ui.SymbolError
-		if issue.Level == "warning" {
-			symbol = ui.SymbolWarning
-		}
-
-		prefix := issue.FilePath
-		if prefix == "" {
-			prefix = "global"
-		}
-		if issue.Line > 0 {
-			prefix = fmt.Sprintf("%s:%d", prefix, issue.Line)
-		}
-		fmt.Printf("%s %s %s\n", symbol, ui.FilePath(prefix), issue.Message)
+		var location string
+		switch {
+		case issue.FilePath == "":
+			location = ui.FilePath("global")
+		case issue.Line > 0:
+			location = formatLocationLinkSimpleStyled(issue.FilePath, issue.Line, ui.Bold.Render)
+		default:
+			location = formatFileLink(issue.FilePath)
+		}
+		fmt.Printf("%s %s %s\n", ui.IssueSymbol(issue.Level), location, issue.Message)
 		if issue.FixHint != "" {
 			fmt.Printf("  %s\n", ui.Muted.Render(issue.FixHint))
 		}
@@ -592,7 +616,7 @@ func printIssueSummaryFromJSON(summary []CheckSummaryJSON, issues []CheckIssueJS
 	}
 
 	if len(errorsSummary) > 0 {
-		fmt.Printf("%s %s\n", ui.SymbolAttention, ui.Bold.Render("Errors"))
+		fmt.Printf("%s %s\n", ui.Danger.Render(ui.SymbolAttention), ui.Bold.Render("Errors"))
 		for _, item := range errorsSummary {
 			printIssueSummaryItem(item)
 		}
@@ -601,7 +625,7 @@ func printIssueSummaryFromJSON(summary []CheckSummaryJSON, issues []CheckIssueJS
 		if len(errorsSummary) > 0 {
 			fmt.Println()
 		}
-		fmt.Printf("%s %s\n", ui.SymbolAttention, ui.Bold.Render("Warnings"))
+		fmt.Printf("%s %s\n", ui.Warn.Render(ui.SymbolAttention), ui.Bold.Render("Warnings"))
 		for _, item := range warningsSummary {
 			printIssueSummaryItem(item)
 		}
@@ -624,7 +648,7 @@ func printIssueSummaryItem(item CheckSummaryJSON) {
 
 func looksLikeWarningIssue(issueType string) bool {
 	switch issueType {
-	case string(check.IssueStaleIndex), string(check.IssueUnusedType), string(check.IssueUnusedTrait), string(check.IssueShortRefCouldBeFullPath):
+	case string(check.IssueStaleIndex), string(check.IssueUnusedType), string(check.IssueUnusedTrait), string(check.IssueShortRefCouldBeFullPath), string(check.IssueReviewOverdue), string(check.IssueMissingSQLiteCapability):
 		return true
 	default:
 		return false
@@ -0,0 +1,452 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// Completion candidate kinds reported by `rvn _complete`.
+const (
+	completionKindCommand = "command"
+	completionKindFlag    = "flag"
+	completionKindType    = "type"
+	completionKindTrait   = "trait"
+	completionKindQuery   = "query"
+	completionKindObject  = "object"
+	completionKindDate    = "date"
+	completionKindValue   = "value"
+)
+
+// CompletionCandidate is one structured completion for launchers and pickers.
+type CompletionCandidate struct {
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Kind        string `json:"kind"`
+}
+
+var completeCmd = &cobra.Command{
+	Use:    "_complete <partial command>",
+	Short:  "Return structured completion candidates for a partial command",
+	Hidden: true,
+	Long: `Return completion candidates for a partial rvn command line.
+
+Intended for launchers (Raycast, Alfred, fig-style pickers) that need
+structured candidates instead of shell completion scripts. Each candidate has
+a value, a description, and a kind: command, flag, type, trait, query,
+object, date, or value.
+
+Pass the partial command as a single quoted argument. A trailing space means
+the next word is being completed. Alternatively pass pre-split words after
+--, with the last word being the one to complete.`,
+	Example: `  rvn _complete --json 'read per'
+  rvn _complete --json 'new '
+  rvn _complete --json 'query trait:'
+  rvn _complete --json -- query --li`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		line := args[0]
+		words := splitCompletionLine(line)
+		if len(args) > 1 {
+			line = strings.Join(args, " ")
+			words = args
+		}
+
+		path, current, candidates := completionCandidates(cmd, words)
+		if isJSONOutput() {
+			outputSuccess(map[string]interface{}{
+				"line":       line,
+				"command":    path,
+				"current":    current,
+				"candidates": candidates,
+			}, &Meta{Count: len(candidates)})
+			return nil
+		}
+
+		for _, candidate := range candidates {
+			if candidate.Description != "" {
+				fmt.Printf("%s\t%s\n", candidate.Value, candidate.Description)
+				continue
+			}
+			fmt.Println(candidate.Value)
+		}
+		return nil
+	},
+}
+
+// splitCompletionLine splits a partial command line into words, honoring
+// single and double quotes. A trailing unquoted space yields a final empty
+// word, meaning a new word is being completed. A leading "rvn" is dropped.
+func splitCompletionLine(line string) []string {
+	var (
+		words   []string
+		current strings.Builder
+		quote   rune
+		inWord  bool
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	words = append(words, current.String())
+
+	if len(words) > 1 && words[0] == "rvn" {
+		words = words[1:]
+	}
+	return words
+}
+
+// completionCandidates resolves the command named by all but the last word and
+// returns candidates for the last word.
+func completionCandidates(cmd *cobra.Command, words []string) (string, string, []CompletionCandidate) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current := words[len(words)-1]
+
+	target := rootCmd
+	var (
+		positional  []string
+		pendingFlag *pflag.Flag
+		flagsDone   bool
+	)
+	for _, word := range words[:len(words)-1] {
+		if pendingFlag != nil {
+			pendingFlag = nil
+			continue
+		}
+		if word == "--" {
+			flagsDone = true
+			continue
+		}
+		if !flagsDone && len(word) > 1 && strings.HasPrefix(word, "-") {
+			if flag := lookupCompletionFlag(target, word); flag != nil && flag.NoOptDefVal == "" && !strings.Contains(word, "=") {
+				pendingFlag = flag
+			}
+			continue
+		}
+		if len(positional) == 0 {
+			if sub := findCompletionSubcommand(target, word); sub != nil {
+				target = sub
+				continue
+			}
+		}
+		positional = append(positional, word)
+	}
+
+	// Merge persistent flags so completion functions see --vault-path and
+	// friends on the target command.
+	_ = target.InheritedFlags()
+	path := commandPathForCommand(target)
+	source := &completionSource{vaultPath: completionVaultPath(cmd)}
+
+	if pendingFlag != nil {
+		return path, current, source.flagValueCandidates(target, pendingFlag, positional, current)
+	}
+	if !flagsDone && strings.HasPrefix(current, "-") {
+		return path, current, flagCandidates(target, current)
+	}
+
+	var candidates []CompletionCandidate
+	if len(positional) == 0 {
+		candidates = append(candidates, subcommandCandidates(target, current)...)
+	}
+	candidates = append(candidates, source.argCandidates(target, path, positional, current)...)
+	return path, current, candidates
+}
+
+func findCompletionSubcommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, sub := range cmd.Commands() {
+		if sub.Name() == name || sub.HasAlias(name) {
+			return sub
+		}
+	}
+	return nil
+}
+
+func lookupCompletionFlag(cmd *cobra.Command, word string) *pflag.Flag {
+	// InheritedFlags merges persistent parent flags into cmd.Flags().
+	_ = cmd.InheritedFlags()
+	if strings.HasPrefix(word, "--") {
+		name, _, _ := strings.Cut(strings.TrimPrefix(word, "--"), "=")
+		return cmd.Flags().Lookup(name)
+	}
+	short := strings.TrimPrefix(word, "-")
+	if len(short) != 1 {
+		return nil
+	}
+	return cmd.Flags().ShorthandLookup(short)
+}
+
+func subcommandCandidates(cmd *cobra.Command, current string) []CompletionCandidate {
+	var candidates []CompletionCandidate
+	for _, sub := range cmd.Commands() {
+		if !sub.IsAvailableCommand() || !matchesCompletion(sub.Name(), current) {
+			continue
+		}
+		candidates = append(candidates, CompletionCandidate{
+			Value:       sub.Name(),
+			Description: sub.Short,
+			Kind:        completionKindCommand,
+		})
+	}
+	return candidates
+}
+
+func flagCandidates(cmd *cobra.Command, current string) []CompletionCandidate {
+	var candidates []CompletionCandidate
+	add := func(flag *pflag.Flag) {
+		if flag.Hidden {
+			return
+		}
+		value := "--" + flag.Name
+		if !strings.HasPrefix(value, current) {
+			return
+		}
+		candidates = append(candidates, CompletionCandidate{
+			Value:       value,
+			Description: flag.Usage,
+			Kind:        completionKindFlag,
+		})
+	}
+	cmd.LocalFlags().VisitAll(add)
+	cmd.InheritedFlags().VisitAll(add)
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Value < candidates[j].Value })
+	return candidates
+}
+
+// completionSource lazily loads vault data for dynamic candidates.
+type completionSource struct {
+	vaultPath   string
+	schema      *schema.Schema
+	objectTypes map[string]string
+	loaded      bool
+}
+
+func (s *completionSource) loadSchema() *schema.Schema {
+	if s.schema == nil && s.vaultPath != "" {
+		s.schema, _ = schema.Load(s.vaultPath)
+	}
+	return s.schema
+}
+
+// objectType returns the type of an object ID or short name when indexed.
+func (s *completionSource) objectType(value string) (string, bool) {
+	if !s.loaded {
+		s.loaded = true
+		s.objectTypes = make(map[string]string)
+		if s.vaultPath == "" {
+			return "", false
+		}
+		db, err := index.Open(s.vaultPath)
+		if err != nil {
+			return "", false
+		}
+		defer db.Close()
+		objects, err := db.AllObjects()
+		if err != nil {
+			return "", false
+		}
+		for _, obj := range objects {
+			s.objectTypes[obj.ID] = obj.Type
+			short := paths.ShortNameFromID(obj.ID)
+			if _, exists := s.objectTypes[short]; !exists {
+				s.objectTypes[short] = obj.Type
+			}
+		}
+	}
+	typeName, ok := s.objectTypes[value]
+	return typeName, ok
+}
+
+func (s *completionSource) argCandidates(cmd *cobra.Command, path string, positional []string, current string) []CompletionCandidate {
+	if meta, ok := lookupRegistryMeta(path); ok && len(positional) < len(meta.Args) {
+		arg := meta.Args[len(positional)]
+		if arg.DynamicComp != "" || len(arg.Completions) > 0 {
+			return s.dynamicCandidates(arg.DynamicComp, arg.Completions, current)
+		}
+	}
+	if cmd.ValidArgsFunction == nil {
+		return nil
+	}
+	values, _ := cmd.ValidArgsFunction(cmd, positional, current)
+	return s.classifyValues(values)
+}
+
+func (s *completionSource) flagValueCandidates(cmd *cobra.Command, flag *pflag.Flag, positional []string, current string) []CompletionCandidate {
+	complete, ok := cmd.GetFlagCompletionFunc(flag.Name)
+	if !ok {
+		return nil
+	}
+	values, _ := complete(cmd, positional, current)
+	return s.classifyValues(values)
+}
+
+// classifyValues turns cobra completion values ("value" or "value\tdesc")
+// into candidates, recognizing indexed objects and relative date keywords.
+func (s *completionSource) classifyValues(values []string) []CompletionCandidate {
+	candidates := make([]CompletionCandidate, 0, len(values))
+	for _, raw := range values {
+		value, description, _ := strings.Cut(raw, "\t")
+		kind := completionKindValue
+		switch {
+		case value == "today" || value == "tomorrow" || value == "yesterday":
+			kind = completionKindDate
+		default:
+			if typeName, ok := s.objectType(value); ok {
+				kind = completionKindObject
+				if description == "" {
+					description = typeName
+				}
+			}
+		}
+		candidates = append(candidates, CompletionCandidate{Value: value, Description: description, Kind: kind})
+	}
+	return candidates
+}
+
+// dynamicCandidates resolves registry ArgMeta completion hints.
+func (s *completionSource) dynamicCandidates(dynamic string, static []string, current string) []CompletionCandidate {
+	var candidates []CompletionCandidate
+	for _, value := range static {
+		if matchesCompletion(value, current) {
+			candidates = append(candidates, CompletionCandidate{Value: value, Kind: completionKindValue})
+		}
+	}
+
+	switch dynamic {
+	case "types":
+		candidates = append(candidates, s.typeCandidates("", current)...)
+	case "traits":
+		candidates = append(candidates, s.traitCandidates("", current)...)
+	case "queries":
+		candidates = append(candidates, s.savedQueryCandidates(current)...)
+	case "query":
+		// Query strings complete saved query names and type:/trait: selectors.
+		switch {
+		case strings.HasPrefix(current, "type:"):
+			candidates = append(candidates, s.typeCandidates("type:", current)...)
+		case strings.HasPrefix(current, "trait:"):
+			candidates = append(candidates, s.traitCandidates("trait:", current)...)
+		default:
+			candidates = append(candidates, s.savedQueryCandidates(current)...)
+			for _, selector := range []string{"type:", "trait:"} {
+				if matchesCompletion(selector, current) {
+					candidates = append(candidates, CompletionCandidate{Value: selector, Kind: completionKindValue})
+				}
+			}
+		}
+	}
+	return candidates
+}
+
+func (s *completionSource) typeCandidates(prefix, current string) []CompletionCandidate {
+	descriptions := make(map[string]string)
+	for _, name := range schema.BuiltinTypeNames() {
+		descriptions[name] = "built-in"
+	}
+	if sch := s.loadSchema(); sch != nil {
+		for name, typeDef := range sch.Types {
+			if typeDef != nil && typeDef.Description != "" {
+				descriptions[name] = typeDef.Description
+			} else if _, builtin := descriptions[name]; !builtin {
+				descriptions[name] = ""
+			}
+		}
+	}
+	return sortedCandidates(descriptions, prefix, current, completionKindType)
+}
+
+func (s *completionSource) traitCandidates(prefix, current string) []CompletionCandidate {
+	descriptions := make(map[string]string)
+	if sch := s.loadSchema(); sch != nil {
+		for name, traitDef := range sch.Traits {
+			description := ""
+			if traitDef != nil && traitDef.Type != "" {
+				description = string(traitDef.Type)
+			}
+			descriptions[name] = description
+		}
+	}
+	return sortedCandidates(descriptions, prefix, current, completionKindTrait)
+}
+
+func (s *completionSource) savedQueryCandidates(current string) []CompletionCandidate {
+	if s.vaultPath == "" {
+		return nil
+	}
+	vaultCfg, err := config.LoadVaultConfig(s.vaultPath)
+	if err != nil || vaultCfg == nil {
+		return nil
+	}
+	descriptions := make(map[string]string, len(vaultCfg.Queries))
+	for name, saved := range vaultCfg.Queries {
+		description := ""
+		if saved != nil {
+			description = saved.Description
+			if description == "" {
+				description = saved.Query
+			}
+		}
+		descriptions[name] = description
+	}
+	return sortedCandidates(descriptions, "", current, completionKindQuery)
+}
+
+func sortedCandidates(descriptions map[string]string, prefix, current, kind string) []CompletionCandidate {
+	names := make([]string, 0, len(descriptions))
+	for name := range descriptions {
+		if matchesCompletion(prefix+name, current) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	candidates := make([]CompletionCandidate, 0, len(names))
+	for _, name := range names {
+		candidates = append(candidates, CompletionCandidate{
+			Value:       prefix + name,
+			Description: descriptions[name],
+			Kind:        kind,
+		})
+	}
+	return candidates
+}
+
+func init() {
+	markLocalLeaf(completeCmd)
+	rootCmd.AddCommand(completeCmd)
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestSplitCompletionLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{line: "", want: []string{""}},
+		{line: "rea", want: []string{"rea"}},
+		{line: "rvn read ", want: []string{"read", ""}},
+		{line: "query 'type:project .status==", want: []string{"query", "type:project .status=="}},
+		{line: `set "people/freya" sta`, want: []string{"set", "people/freya", "sta"}},
+	}
+
+	for _, tt := range tests {
+		if got := splitCompletionLine(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCompletionLine(%q) = %#v, want %#v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestCompletionCandidatesCommandsAndFlags(t *testing.T) {
+	path, current, got := completionCandidates(completeCmd, []string{"schema", "ren"})
+	if path != "schema" || current != "ren" {
+		t.Fatalf("completionCandidates() path=%q current=%q, want schema/ren", path, current)
+	}
+	if len(got) != 1 || got[0].Value != "rename" || got[0].Kind != completionKindCommand || got[0].Description == "" {
+		t.Fatalf("schema subcommand candidates = %#v, want rename command", got)
+	}
+
+	_, _, got = completionCandidates(completeCmd, []string{"query", "--li"})
+	if len(got) != 1 || got[0].Value != "--limit" || got[0].Kind != completionKindFlag {
+		t.Fatalf("query flag candidates = %#v, want --limit flag", got)
+	}
+
+	_, _, got = completionCandidates(completeCmd, []string{""})
+	for _, c := range got {
+		if c.Value == "_complete" {
+			t.Fatal("hidden _complete command should not be offered")
+		}
+	}
+}
+
+func TestCompletionSourceDynamicCandidates(t *testing.T) {
+	v := testutil.NewTestVault(t).
+		WithSchema(`version: 1
+types:
+  person:
+    description: People and contacts
+traits:
+  due:
+    type: date
+  priority:
+    type: enum
+    values: [low, high]
+`).
+		WithRavenYAML(`queries:
+  overdue:
+    query: "trait:due .value<today"
+    description: Overdue items
+`).
+		Build()
+	source := &completionSource{vaultPath: v.Path}
+
+	got := source.dynamicCandidates("types", nil, "pe")
+	want := []CompletionCandidate{{Value: "person", Description: "People and contacts", Kind: completionKindType}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("types candidates = %#v, want %#v", got, want)
+	}
+
+	got = source.dynamicCandidates("query", nil, "trait:p")
+	want = []CompletionCandidate{{Value: "trait:priority", Description: "enum", Kind: completionKindTrait}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("trait selector candidates = %#v, want %#v", got, want)
+	}
+
+	got = source.dynamicCandidates("query", nil, "ov")
+	want = []CompletionCandidate{{Value: "overdue", Description: "Overdue items", Kind: completionKindQuery}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("saved query candidates = %#v, want %#v", got, want)
+	}
+}
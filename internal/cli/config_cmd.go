@@ -16,7 +16,8 @@ var configCmd = &cobra.Command{
 	Short: "Manage global Raven config.toml settings",
 	Long: `Manage global Raven config.toml settings.
 
-Use this to initialize, inspect, and edit machine-level configuration.`,
+Use this to initialize, inspect, and edit machine-level configuration.
+'config edit' opens the active vault's raven.yaml with validation on save.`,
 	Args: cobra.NoArgs,
 	RunE: canonicalGroupDefaultRunE("config_show", nil, renderConfigShow),
 }
@@ -33,10 +34,17 @@ var configUnsetCmd = newCanonicalLeafCommand("config_unset", canonicalLeafOption
 	RenderHuman: renderConfigUnset,
 })
 
+var configEditCmd = newCanonicalLeafCommand("config_edit", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	Invoke:      invokeConfigEdit,
+	RenderHuman: renderConfigEdit,
+})
+
 func init() {
 	configCmd.AddCommand(configInitCmd)
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configEditCmd)
 	configCmd.AddCommand(newCanonicalLeafCommand("config_show", canonicalLeafOptions{
 		RenderHuman: renderConfigShow,
 	}))
@@ -79,6 +87,15 @@ func renderConfigShow(_ *cobra.Command, result commandexec.Result) error {
 	if v := strings.TrimSpace(stringValue(uiConfig["markdown_style"])); v != "" {
 		fmt.Printf("ui.markdown_style: %s\n", v)
 	}
+	if hyperlinks, ok := uiConfig["hyperlinks"].(bool); ok && !hyperlinks {
+		fmt.Println("ui.hyperlinks: false")
+	}
+	uiColors, _ := uiConfig["colors"].(map[string]interface{})
+	for _, key := range []string{"success", "warning", "error"} {
+		if v := strings.TrimSpace(stringValue(uiColors[key])); v != "" {
+			fmt.Printf("ui.colors.%s: %s\n", key, v)
+		}
+	}
 	vaults := stringMap(data["vaults"])
 	if len(vaults) == 0 {
 		fmt.Printf("%s %s\n", ui.Hint("vaults:"), ui.Hint("(none)"))
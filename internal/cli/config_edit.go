@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+	"github.com/aidanlsb/raven/internal/vaultconfigsvc"
+)
+
+// invokeConfigEdit opens raven.yaml in the configured editor and submits the
+// result for validation. Invalid content is never written; in a terminal the
+// user can reopen the editor on their draft until it validates or they give up.
+func invokeConfigEdit(cmd *cobra.Command, commandID, vaultPath string, args map[string]interface{}) commandexec.Result {
+	if cmd.Flags().Changed("content") || isJSONOutput() {
+		return executeCanonicalCommand(commandID, vaultPath, args)
+	}
+
+	editor := ""
+	if cfg := getConfig(); cfg != nil {
+		editor = strings.TrimSpace(cfg.GetEditor())
+	}
+	if editor == "" {
+		return commandexec.Failure(ErrMissingArgument, "no editor configured", nil, "Set 'editor' in config.toml or $EDITOR, or pass --content")
+	}
+
+	current, err := vaultconfigsvc.ReadRaw(vaultconfigsvc.ReadRawRequest{VaultPath: vaultPath})
+	if err != nil {
+		return commandexec.Failure(ErrConfigInvalid, err.Error(), nil, "")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "rvn-config-edit-*")
+	if err != nil {
+		return commandexec.Failure(ErrFileWriteError, err.Error(), nil, "Check temporary directory permissions and try again")
+	}
+	defer os.RemoveAll(tmpDir)
+	draftPath := filepath.Join(tmpDir, "raven.yaml")
+	if err := os.WriteFile(draftPath, []byte(current.Content), 0o600); err != nil {
+		return commandexec.Failure(ErrFileWriteError, err.Error(), nil, "Check temporary directory permissions and try again")
+	}
+
+	for {
+		if err := runBlockingEditor(editor, draftPath); err != nil {
+			return commandexec.Failure(ErrInternal, err.Error(), nil, "Make sure your editor command exits after saving, for example 'code --wait'")
+		}
+		draft, err := os.ReadFile(draftPath)
+		if err != nil {
+			return commandexec.Failure(ErrFileReadError, err.Error(), nil, "")
+		}
+
+		result := executeCanonicalCommand(commandID, vaultPath, map[string]interface{}{"content": string(draft)})
+		if result.OK || result.Error == nil || result.Error.Code != ErrConfigInvalid {
+			return result
+		}
+		printConfigIssues(result.Error.Details)
+		if !promptForConfirm("Reopen the editor to fix them?") {
+			return result
+		}
+	}
+}
+
+func printConfigIssues(details interface{}) {
+	detailMap, _ := details.(map[string]interface{})
+	var issues []vaultconfigsvc.Issue
+	if err := decodeResultData(detailMap["issues"], &issues); err != nil || len(issues) == 0 {
+		return
+	}
+	fmt.Println(ui.Warningf("raven.yaml was not saved; %d problem(s) found:", len(issues)))
+	for _, issue := range issues {
+		fmt.Println(ui.Bullet(issue.String()))
+	}
+	fmt.Println()
+}
+
+func renderConfigEdit(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	if !boolValue(data["changed"]) {
+		fmt.Println(ui.Hint("raven.yaml unchanged"))
+		return nil
+	}
+	fmt.Println(ui.Checkf("Saved %s", ui.FilePath(stringValue(data["config_path"]))))
+	return nil
+}
@@ -25,8 +25,24 @@ func handleDailyResult(cmd *cobra.Command, result commandexec.Result) error {
 		filePath = filepath.Join(getVaultPath(), filepath.FromSlash(relativePath))
 	}
 
-	if !isJSONOutput() && created {
-		fmt.Println(ui.Checkf("Created %s", ui.FilePath(relativePath)))
+	if !isJSONOutput() {
+		if created {
+			fmt.Println(ui.Checkf("Created %s", ui.FilePath(relativePath)))
+		}
+		if carried := itemMapsFromAny(data["carried_over"]); len(carried) > 0 {
+			fmt.Println(ui.Checkf("Carried over %d open %s", len(carried), taskNoun(len(carried))))
+		}
+		if scheduled := itemMapsFromAny(data["scheduled"]); len(scheduled) > 0 {
+			fmt.Println(ui.Checkf("Linked %d %s due today", len(scheduled), taskNoun(len(scheduled))))
+		}
+		for _, warning := range result.Warnings {
+			fmt.Println(ui.Warning(warning.Message))
+		}
+		if agenda := itemMapsFromAny(data["agenda"]); len(agenda) > 0 {
+			fmt.Println()
+			printTaskGroups(agenda)
+			fmt.Println()
+		}
 	}
 
 	edit, _ := cmd.Flags().GetBool("edit")
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var dashboardCmd = newCanonicalLeafCommand("dashboard", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderDashboard,
+})
+
+func renderDashboard(_ *cobra.Command, result commandexec.Result) error {
+	printStaleIndexWarning(result.Meta)
+	printDegradedSearchWarnings(result.Warnings)
+
+	data := canonicalDataMap(result)
+	sections := itemMapsFromAny(data["sections"])
+	if len(sections) == 0 {
+		fmt.Println(ui.Hint("No dashboard configured. List saved queries under dashboard in raven.yaml."))
+		return nil
+	}
+
+	sch, _ := schema.Load(getVaultPath())
+	fields := newFieldDisplay(false)
+	for i, section := range sections {
+		if i > 0 {
+			fmt.Println()
+		}
+		items := section["items"]
+		total := intFromAny(section["total"])
+		count := fmt.Sprintf("%d", total)
+		if shown := len(itemMapsFromAny(items)); shown < total {
+			count = fmt.Sprintf("%d of %d", shown, total)
+		}
+		fmt.Printf("%s %s\n", ui.SectionHeader(stringValue(section["title"])), ui.Badge(count))
+
+		if message := stringValue(section["error"]); message != "" {
+			fmt.Println(ui.Error(message))
+			continue
+		}
+		if total == 0 {
+			fmt.Println(ui.Hint("Nothing here"))
+			continue
+		}
+		fmt.Println()
+		switch stringValue(section["query_kind"]) {
+		case "type":
+			printObjectTable(sortObjectsByDisplayName(objectResultsFromAny(items), sch), sch, fields)
+		case "trait":
+			printTraitTable(traitResultsFromAny(items), false)
+		case "asset":
+			printAssetTable(assetResultsFromAny(items))
+		case "section":
+			printSectionTable(sectionResultsFromAny(items))
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(dashboardCmd)
+}
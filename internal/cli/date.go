@@ -21,15 +21,30 @@ func renderDate(_ *cobra.Command, result commandexec.Result) error {
 	data := canonicalDataMap(result)
 	display := ui.NewDisplayContext()
 	dateValue, _ := data["date"].(string)
-	dayOfWeek, _ := data["day_of_week"].(string)
-	fmt.Printf("%s %s\n\n", ui.SectionHeader(dateValue), ui.Hint(fmt.Sprintf("(%s)", dayOfWeek)))
+	var period *datesvc.DateHubPeriod
+	if raw, ok := data["period"]; ok && raw != nil {
+		period = &datesvc.DateHubPeriod{}
+		_ = decodeResultData(raw, period)
+	}
 
-	fmt.Println(ui.Divider("Daily Note", display.TermWidth))
-	if dailyNoteRaw, ok := data["daily_note"]; ok && dailyNoteRaw != nil {
-		dailyNote := objectResultFromAny(dailyNoteRaw)
-		fmt.Printf("%s\n\n", ui.Bullet(ui.FilePath(dailyNote.FilePath)))
+	if period != nil {
+		fmt.Printf("%s %s\n\n", ui.SectionHeader(period.Title), ui.Hint(fmt.Sprintf("(%s to %s)", period.Start, period.End)))
+		fmt.Println(ui.Divider(periodNoteLabel(period.Kind), display.TermWidth))
+		if period.NoteExists {
+			fmt.Printf("%s\n\n", ui.Bullet(ui.FilePath(period.NotePath)))
+		} else {
+			fmt.Printf("%s\n\n", ui.Bullet(ui.Hint(fmt.Sprintf("(not created yet - use 'rvn %sly %s' to create)", period.Kind, period.Key))))
+		}
 	} else {
-		fmt.Printf("%s\n\n", ui.Bullet(ui.Hint(fmt.Sprintf("(not created yet - use 'rvn daily %s' to create)", dateValue))))
+		dayOfWeek, _ := data["day_of_week"].(string)
+		fmt.Printf("%s %s\n\n", ui.SectionHeader(dateValue), ui.Hint(fmt.Sprintf("(%s)", dayOfWeek)))
+		fmt.Println(ui.Divider("Daily Note", display.TermWidth))
+		if dailyNoteRaw, ok := data["daily_note"]; ok && dailyNoteRaw != nil {
+			dailyNote := objectResultFromAny(dailyNoteRaw)
+			fmt.Printf("%s\n\n", ui.Bullet(ui.FilePath(dailyNote.FilePath)))
+		} else {
+			fmt.Printf("%s\n\n", ui.Bullet(ui.Hint(fmt.Sprintf("(not created yet - use 'rvn daily %s' to create)", dateValue))))
+		}
 	}
 
 	byField := make(map[string][]datesvc.DateAssociation)
@@ -53,7 +68,11 @@ func renderDate(_ *cobra.Command, result commandexec.Result) error {
 					}
 					line := fmt.Sprintf("%s %s", ui.Trait(item.Trait.TraitType, valueStr), item.Trait.Content)
 					fmt.Println(ui.Bullet(line))
-					fmt.Println(ui.Indent(2, ui.Hint(item.Trait.FilePath)))
+					location := item.Trait.FilePath
+					if period != nil {
+						location = fmt.Sprintf("%s, %s", item.Date, location)
+					}
+					fmt.Println(ui.Indent(2, ui.Hint(location)))
 				} else {
 					fmt.Println(ui.Bullet(item.SourceID))
 				}
@@ -63,6 +82,11 @@ func renderDate(_ *cobra.Command, result commandexec.Result) error {
 					if item.Object.Type != "" {
 						meta = ui.Hint(fmt.Sprintf("(%s)", item.Object.Type))
 					}
+					if item.Recurring {
+						meta = strings.TrimSpace(meta + " " + ui.Hint(fmt.Sprintf("(%s, %s)", item.Date, yearsLabel(item.Years))))
+					} else if period != nil {
+						meta = strings.TrimSpace(meta + " " + ui.Hint(fmt.Sprintf("(%s)", item.Date)))
+					}
 					fmt.Println(ui.Bullet(strings.TrimSpace(fmt.Sprintf("%s %s", item.SourceID, meta))))
 				} else {
 					fmt.Println(ui.Bullet(item.SourceID))
@@ -87,6 +111,24 @@ func renderDate(_ *cobra.Command, result commandexec.Result) error {
 	return nil
 }
 
+func periodNoteLabel(kind string) string {
+	switch kind {
+	case "week":
+		return "Weekly Note"
+	case "month":
+		return "Monthly Note"
+	default:
+		return "Quarterly Note"
+	}
+}
+
+func yearsLabel(years int) string {
+	if years == 1 {
+		return "1 year"
+	}
+	return fmt.Sprintf("%d years", years)
+}
+
 func dateAssociationsFromAny(raw interface{}) []datesvc.DateAssociation {
 	var items []datesvc.DateAssociation
 	_ = decodeResultData(raw, &items)
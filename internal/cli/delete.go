@@ -21,7 +21,7 @@ var (
 var deleteCmd = newCanonicalLeafCommand("delete", canonicalLeafOptions{
 	VaultPath:       getVaultPath,
 	Args:            cobra.MaximumNArgs(1),
-	BuildArgs:       buildDeleteArgs,
+	BuildArgs:       withUnlockArg(buildDeleteArgs),
 	Invoke:          invokeDelete,
 	RenderHuman:     renderDeleteResult,
 	SkipFlagBinding: true,
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/doctorsvc"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var doctorCmd = newCanonicalLeafCommand("doctor", canonicalLeafOptions{
+	VaultPath:    getVaultPath,
+	HandleResult: handleDoctorResult,
+})
+
+func handleDoctorResult(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	if isJSONOutput() {
+		outputCanonicalResultJSON(result)
+	} else {
+		renderDoctor(data)
+	}
+	if intValue(data["errors"]) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func renderDoctor(data map[string]interface{}) {
+	var findings []doctorsvc.Finding
+	_ = decodeResultData(data["checks"], &findings)
+	var repair *doctorsvc.RepairResult
+	_ = decodeResultData(data["repair"], &repair)
+
+	if repair != nil {
+		fmt.Println(ui.SectionHeader("Repairs"))
+		for _, line := range doctorRepairLines(repair) {
+			fmt.Println(ui.Check(line))
+		}
+		fmt.Println()
+	}
+
+	fmt.Println(ui.SectionHeader("Vault doctor"))
+	for _, finding := range findings {
+		if finding.Status == doctorsvc.StatusOK {
+			fmt.Println(ui.Check(finding.Message))
+			continue
+		}
+		message := finding.Message
+		if finding.Fixable {
+			message += " " + ui.Hint("(fixable)")
+		}
+		if finding.Status == doctorsvc.StatusWarning {
+			fmt.Println(ui.Warning(message))
+		} else {
+			fmt.Println(ui.Error(message))
+		}
+		for _, item := range finding.Items {
+			fmt.Println("  " + ui.Bullet(item))
+		}
+		if finding.Suggestion != "" {
+			fmt.Println("  " + ui.Hint(finding.Suggestion))
+		}
+	}
+
+	errors, warnings := intValue(data["errors"]), intValue(data["warnings"])
+	if errors == 0 && warnings == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println(ui.Hint(fmt.Sprintf("%d error(s), %d warning(s)", errors, warnings)))
+	switch {
+	case !boolValue(data["fixable"]):
+	case boolValue(data["preview"]):
+		fmt.Println(ui.Hint("Run 'rvn doctor --fix --confirm' to repair the fixable findings."))
+	case repair == nil:
+		fmt.Println(ui.Hint("Run 'rvn doctor --fix' to preview repairs."))
+	}
+}
+
+func doctorRepairLines(repair *doctorsvc.RepairResult) []string {
+	if repair.Rebuilt {
+		return []string{"Rebuilt the index from the vault files"}
+	}
+	var lines []string
+	if n := len(repair.RemovedFiles); n > 0 {
+		lines = append(lines, fmt.Sprintf("Removed %d deleted file(s) from the index", n))
+	}
+	if repair.RemovedOrphanRows > 0 {
+		lines = append(lines, fmt.Sprintf("Removed %d orphaned index row(s)", repair.RemovedOrphanRows))
+	}
+	if n := len(repair.ReindexedFiles); n > 0 {
+		lines = append(lines, fmt.Sprintf("Reindexed %d file(s)", n))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "Nothing needed repair")
+	}
+	return lines
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
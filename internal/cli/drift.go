@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/schemasvc"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Audit how schema.yaml and vault data have drifted apart",
+	Long: `Audit divergence between the schema and the data in the vault.
+
+  rvn drift report
+  rvn drift report --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var driftReportCmd = newCanonicalLeafCommand("drift_report", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderDriftReport,
+})
+
+func renderDriftReport(_ *cobra.Command, result commandexec.Result) error {
+	report, err := decodeSchemaValue[schemasvc.DriftReport](canonicalDataMap(result)["drift"])
+	if err != nil {
+		return err
+	}
+	if report.Summary.Total() == 0 {
+		fmt.Println(ui.Check("No drift: every declaration is used and all data matches the schema"))
+		return nil
+	}
+
+	if len(report.UndeclaredTraits) > 0 {
+		printDriftSection("Undeclared traits", len(report.UndeclaredTraits))
+		for _, trait := range report.UndeclaredTraits {
+			fmt.Println(ui.Bullet(fmt.Sprintf("@%s  %s", ui.Bold.Render(trait.Trait), ui.Hint(fmt.Sprintf("uses: %d, files: %d, e.g. %s", trait.Count, trait.FileCount, strings.Join(trait.Locations, ", "))))))
+		}
+	}
+	if len(report.RefTargetMismatches) > 0 {
+		printDriftSection("Ref targets off schema", len(report.RefTargetMismatches))
+		for _, mismatch := range report.RefTargetMismatches {
+			fmt.Println(ui.Bullet(fmt.Sprintf("%s.%s  %s", mismatch.Type, mismatch.Field, ui.Hint(fmt.Sprintf("%d → %s (declared %s), e.g. %s", mismatch.Count, mismatch.ActualType, mismatch.DeclaredTarget, mismatch.Example)))))
+		}
+	}
+	if len(report.UnusedFields) > 0 {
+		printDriftSection("Unused fields", len(report.UnusedFields))
+		for _, field := range report.UnusedFields {
+			fmt.Println(ui.Bullet(fmt.Sprintf("%s.%s  %s", field.Type, field.Field, ui.Hint(fmt.Sprintf("0 of %d objects", field.ObjectCount)))))
+		}
+	}
+	if len(report.UnusedTypes) > 0 {
+		printDriftSection("Unused types", len(report.UnusedTypes))
+		for _, typ := range report.UnusedTypes {
+			fmt.Println(ui.Bullet(typ.Type))
+		}
+	}
+	if len(report.UnusedEnumValues) > 0 {
+		printDriftSection("Unused enum values", len(report.UnusedEnumValues))
+		for _, enum := range report.UnusedEnumValues {
+			name := "@" + enum.Name
+			if enum.Kind == "field" {
+				name = enum.Type + "." + enum.Name
+			}
+			fmt.Println(ui.Bullet(fmt.Sprintf("%s  %s", name, ui.Hint(strings.Join(enum.Values, ", ")))))
+		}
+	}
+
+	printDriftSection("Cleanup plan", len(report.Plan))
+	for i, action := range report.Plan {
+		fmt.Printf("  %d. [%s] %s: %s\n", i+1, action.Priority, ui.Bold.Render(action.Target), action.Description)
+		if action.Command != "" {
+			fmt.Printf("     %s\n", ui.Hint(action.Command))
+		}
+	}
+	return nil
+}
+
+func printDriftSection(title string, count int) {
+	fmt.Printf("\n%s %s\n", ui.SectionHeader(title), ui.Badge(fmt.Sprintf("%d", count)))
+}
+
+func init() {
+	driftCmd.AddCommand(driftReportCmd)
+	rootCmd.AddCommand(driftCmd)
+}
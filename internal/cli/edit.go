@@ -47,12 +47,12 @@ func renderCanonicalEditResult(result commandexec.Result) error {
 			path, _ := data["path"].(string)
 			fmt.Printf("%s %s\n\n", ui.SectionHeader("Preview edits"), ui.FilePath(path))
 			for _, edit := range edits {
-				line, _ := edit["line"].(float64)
-				index, _ := edit["index"].(float64)
+				line := intFromAny(edit["line"])
+				index := intFromAny(edit["index"])
 				preview := stringMapValue(edit["preview"])
 				before := preview["before"]
 				after := preview["after"]
-				fmt.Println(ui.Muted.Render(fmt.Sprintf("EDIT %d (line %d):", int(index), int(line))))
+				fmt.Println(ui.Muted.Render(fmt.Sprintf("EDIT %d (line %d):", index, line)))
 				fmt.Println(ui.Muted.Render("BEFORE:"))
 				fmt.Println(indent(before, "  "))
 				fmt.Println()
@@ -65,11 +65,11 @@ func renderCanonicalEditResult(result commandexec.Result) error {
 		}
 
 		path, _ := data["path"].(string)
-		line, _ := data["line"].(float64)
+		line := intFromAny(data["line"])
 		preview := stringMapValue(data["preview"])
 		before := preview["before"]
 		after := preview["after"]
-		fmt.Printf("%s %s\n\n", ui.SectionHeader("Preview edit"), ui.FilePath(fmt.Sprintf("%s:%d", path, int(line))))
+		fmt.Printf("%s %s\n\n", ui.SectionHeader("Preview edit"), ui.FilePath(fmt.Sprintf("%s:%d", path, line)))
 		fmt.Println(ui.Muted.Render("BEFORE:"))
 		fmt.Println(indent(before, "  "))
 		fmt.Println()
@@ -85,10 +85,10 @@ func renderCanonicalEditResult(result commandexec.Result) error {
 		fmt.Println(ui.Checkf("Applied %d edits in %s", len(edits), ui.FilePath(path)))
 		fmt.Println()
 		for _, edit := range edits {
-			line, _ := edit["line"].(float64)
-			index, _ := edit["index"].(float64)
+			line := intFromAny(edit["line"])
+			index := intFromAny(edit["index"])
 			contextText, _ := edit["context"].(string)
-			fmt.Println(ui.Muted.Render(fmt.Sprintf("EDIT %d (line %d):", int(index), int(line))))
+			fmt.Println(ui.Muted.Render(fmt.Sprintf("EDIT %d (line %d):", index, line)))
 			fmt.Println(indent(contextText, "  "))
 			fmt.Println()
 		}
@@ -97,9 +97,9 @@ func renderCanonicalEditResult(result commandexec.Result) error {
 	}
 
 	path, _ := data["path"].(string)
-	line, _ := data["line"].(float64)
+	line := intFromAny(data["line"])
 	contextText, _ := data["context"].(string)
-	fmt.Println(ui.Checkf("Applied edit in %s", ui.FilePath(fmt.Sprintf("%s:%d", path, int(line)))))
+	fmt.Println(ui.Checkf("Applied edit in %s", ui.FilePath(fmt.Sprintf("%s:%d", path, line))))
 	fmt.Println()
 	fmt.Println(ui.Muted.Render("Context:"))
 	fmt.Println(indent(contextText, "  "))
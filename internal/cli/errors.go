@@ -34,10 +34,12 @@ const (
 	ErrFileReadError    = codes.ErrFileRead
 	ErrFileWriteError   = codes.ErrFileWrite
 	ErrFileOutsideVault = codes.ErrFileOutsideVault
+	ErrFileLocked       = codes.ErrFileLocked
 
 	// Database errors
 	ErrDatabaseError   = codes.ErrDatabase
 	ErrDatabaseVersion = codes.ErrDatabaseVersion
+	ErrIndexStale      = codes.ErrIndexStale
 
 	// Validation errors
 	ErrValidationFailed     = codes.ErrValidationFailed
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/fmtsvc"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var fmtCmd = newCanonicalLeafCommand("fmt", canonicalLeafOptions{
+	VaultPath:    getVaultPath,
+	HandleResult: handleFmtResult,
+})
+
+func handleFmtResult(cmd *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	if isJSONOutput() {
+		outputCanonicalResultJSON(result)
+	} else {
+		renderFmt(cmd, data)
+	}
+	if checkMode, _ := cmd.Flags().GetBool("check"); checkMode && intValue(data["changed"]) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func renderFmt(cmd *cobra.Command, data map[string]interface{}) {
+	var files []fmtsvc.FileResult
+	_ = decodeResultData(data["files"], &files)
+	var skipped []fmtsvc.SkippedFile
+	_ = decodeResultData(data["skipped"], &skipped)
+	preview := boolValue(data["preview"])
+
+	switch {
+	case len(files) == 0:
+		fmt.Println(ui.Checkf("%d files already formatted", intValue(data["checked"])))
+	case preview:
+		fmt.Println(ui.SectionHeader(fmt.Sprintf("%d of %d files need formatting", len(files), intValue(data["checked"]))))
+	default:
+		fmt.Println(ui.Checkf("Formatted %d files", len(files)))
+	}
+	for _, file := range files {
+		fmt.Println(ui.Bullet(file.Path + " " + ui.Hint("("+strings.Join(file.Rules, ", ")+")")))
+	}
+	for _, file := range skipped {
+		fmt.Println(ui.Warning(fmt.Sprintf("Skipped %s: %s", file.Path, file.Reason)))
+	}
+
+	if checkMode, _ := cmd.Flags().GetBool("check"); preview && len(files) > 0 && !checkMode {
+		fmt.Println()
+		fmt.Println(ui.Hint("Run with --confirm to apply"))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(fmtCmd)
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var focusCmd = &cobra.Command{
+	Use:   "focus",
+	Short: "Manage the focus working set (@focus)",
+	Long: `Keep a small working set of objects and refer to it as @focus.
+
+  rvn focus add projects/website projects/raven
+  rvn query 'type:project @focus .status==active'
+  rvn set @focus status=done --confirm
+  rvn focus clear`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var focusAddCmd = newCanonicalLeafCommand("focus_add", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	Args:        cobra.ArbitraryArgs,
+	BuildArgs:   buildFocusAddArgs,
+	RenderHuman: renderFocusAdd,
+})
+
+var focusListCmd = newCanonicalLeafCommand("focus_list", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderFocusList,
+})
+
+var focusClearCmd = newCanonicalLeafCommand("focus_clear", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderFocusClear,
+})
+
+func buildFocusAddArgs(cmd *cobra.Command, args []string) (map[string]interface{}, error) {
+	ids := append([]string{}, args...)
+	if stdin, _ := cmd.Flags().GetBool("stdin"); stdin {
+		fileIDs, sectionIDs, err := ReadIDsFromStdin()
+		if err != nil {
+			return nil, handleError(ErrInternal, err, "")
+		}
+		ids = append(ids, fileIDs...)
+		ids = append(ids, sectionIDs...)
+	}
+	if len(ids) == 0 {
+		return nil, handleErrorMsg(ErrMissingArgument, "requires at least one object", "Usage: rvn focus add <object-id>...")
+	}
+	return map[string]interface{}{
+		"object_ids": stringsToAny(ids),
+	}, nil
+}
+
+func renderFocusAdd(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	added := stringSliceFromAny(data["added"])
+	fmt.Println(ui.Checkf("Added %d to focus (%d total)", len(added), intFromAny(data["total"])))
+	for _, id := range added {
+		fmt.Println(ui.Bullet(id))
+	}
+	return nil
+}
+
+func renderFocusList(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	items, _ := data["items"].([]map[string]interface{})
+	if len(items) == 0 {
+		fmt.Println(ui.Hint("Focus is empty. Add objects with 'rvn focus add <object-id>'."))
+		return nil
+	}
+	for _, item := range items {
+		id, _ := item["id"].(string)
+		if boolValue(item["missing"]) {
+			fmt.Println(ui.Bullet(id + " " + ui.Warning("missing")))
+			continue
+		}
+		objectType, _ := item["type"].(string)
+		fmt.Println(ui.Bullet(id + " " + ui.Muted.Render(objectType)))
+	}
+	return nil
+}
+
+func renderFocusClear(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	fmt.Println(ui.Checkf("Cleared %d from focus", intFromAny(data["cleared"])))
+	return nil
+}
+
+func init() {
+	focusAddCmd.ValidArgsFunction = completeReferenceArgAt(0, referenceCompletionOptions{
+		IncludeDynamicDates: false,
+		DisableWhenStdin:    true,
+		NonTargetDirective:  cobra.ShellCompDirectiveNoFileComp,
+	})
+	focusCmd.AddCommand(focusAddCmd)
+	focusCmd.AddCommand(focusListCmd)
+	focusCmd.AddCommand(focusClearCmd)
+	rootCmd.AddCommand(focusCmd)
+}
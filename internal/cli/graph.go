@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the object and reference graph",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var graphExportCmd = newCanonicalLeafCommand("graph_export", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderGraphExport,
+})
+
+func renderGraphExport(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	if output, _ := data["output"].(string); output != "" {
+		count := 0
+		if result.Meta != nil {
+			count = result.Meta.Count
+		}
+		fmt.Println(ui.Checkf("Wrote %s graph (%d nodes) to %s", data["format"], count, ui.FilePath(output)))
+		return nil
+	}
+	if content, ok := data["content"].(string); ok {
+		fmt.Print(content)
+		return nil
+	}
+	out, err := json.MarshalIndent(struct {
+		Nodes interface{} `json:"nodes"`
+		Edges interface{} `json:"edges"`
+	}{data["nodes"], data["edges"]}, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+func init() {
+	graphCmd.AddCommand(graphExportCmd)
+	rootCmd.AddCommand(graphCmd)
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/checksvc"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var healthCmd = newCanonicalLeafCommand("health", canonicalLeafOptions{
+	VaultPath:    getVaultPath,
+	HandleResult: handleHealthResult,
+})
+
+func handleHealthResult(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	if isJSONOutput() {
+		outputCanonicalResultJSON(result)
+	} else {
+		renderHealth(data)
+	}
+	if !boolValue(data["passed"]) {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func renderHealth(data map[string]interface{}) {
+	var categories []checksvc.HealthCategory
+	_ = decodeResultData(data["categories"], &categories)
+	score := intValue(data["score"])
+	minScore := intValue(data["min_score"])
+
+	fmt.Println(ui.SectionHeader("Vault health"))
+	fmt.Println(ui.Stat("Score", fmt.Sprintf("%d/100", score)))
+	fileCount := intValue(data["file_count"])
+	fileNoun := "files"
+	if fileCount == 1 {
+		fileNoun = "file"
+	}
+	fmt.Println(ui.Hint(fmt.Sprintf("%d %s checked", fileCount, fileNoun)))
+	fmt.Println()
+
+	for _, category := range categories {
+		label := strings.ReplaceAll(category.Name, "_", " ")
+		line := fmt.Sprintf("%-13s %4d", label, category.Count)
+		if category.Penalty > 0 {
+			line += "  " + ui.Hint(fmt.Sprintf("-%d", category.Penalty))
+			if category.Penalty == category.MaxPenalty {
+				line += ui.Hint(" (max)")
+			}
+		}
+		fmt.Println(ui.Bullet(line))
+	}
+
+	if minScore == 0 {
+		if score < 100 {
+			fmt.Printf("\n%s\n", ui.Hint("Run 'rvn check' for details."))
+		}
+		return
+	}
+	fmt.Println()
+	if boolValue(data["passed"]) {
+		fmt.Println(ui.Checkf("Meets minimum score %d", minScore))
+		return
+	}
+	fmt.Println(ui.Errorf("Below minimum score %d", minScore))
+	fmt.Println(ui.Hint("Run 'rvn check' to see the issues behind the score."))
+}
+
+func init() {
+	rootCmd.AddCommand(healthCmd)
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+type historyOperationView struct {
+	ID        string `json:"id"`
+	Summary   string `json:"summary"`
+	Files     int    `json:"files"`
+	CreatedAt string `json:"created_at"`
+	Undone    bool   `json:"undone"`
+}
+
+var historyCmd = newCanonicalLeafCommand("history", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderHistory,
+})
+
+var undoCmd = newCanonicalLeafCommand("undo", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderUndo,
+})
+
+func renderHistory(_ *cobra.Command, result commandexec.Result) error {
+	var operations []historyOperationView
+	_ = decodeResultData(canonicalDataMap(result)["operations"], &operations)
+	if len(operations) == 0 {
+		fmt.Println(ui.Star("No recorded operations."))
+		return nil
+	}
+
+	fmt.Println(ui.SectionHeader("Recent operations"))
+	for _, op := range operations {
+		fmt.Println(ui.Bullet(formatHistoryOperation(op)))
+	}
+	return nil
+}
+
+func renderUndo(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+
+	var operations []historyOperationView
+	_ = decodeResultData(data["operations"], &operations)
+	var files []struct {
+		Path   string `json:"path"`
+		Action string `json:"action"`
+	}
+	_ = decodeResultData(data["files"], &files)
+	var conflicts []struct {
+		Path   string `json:"path"`
+		Reason string `json:"reason"`
+	}
+	_ = decodeResultData(data["conflicts"], &conflicts)
+
+	preview := boolValue(data["preview"])
+	if preview {
+		fmt.Println(ui.SectionHeader(fmt.Sprintf("Undo %d operation(s)", len(operations))))
+	} else {
+		fmt.Println(ui.Checkf("Undid %d operation(s)", len(operations)))
+	}
+	for _, op := range operations {
+		fmt.Println(ui.Bullet(formatHistoryOperation(op)))
+	}
+
+	if len(files) > 0 {
+		fmt.Println()
+		fmt.Println(ui.SectionHeader("Files"))
+		for _, file := range files {
+			fmt.Println(ui.Bullet(fmt.Sprintf("%s %s", file.Action, ui.FilePath(file.Path))))
+		}
+	}
+	if len(conflicts) > 0 {
+		fmt.Println()
+		fmt.Println(ui.SectionHeader("Conflicts"))
+		for _, conflict := range conflicts {
+			fmt.Println(ui.Warningf("%s: %s", conflict.Path, conflict.Reason))
+		}
+	}
+
+	if preview {
+		hint := "Run with --confirm to apply."
+		if len(conflicts) > 0 {
+			hint = "Run with --confirm --force to apply anyway."
+		}
+		fmt.Printf("\n%s\n", ui.Hint(hint))
+	}
+	return nil
+}
+
+func formatHistoryOperation(op historyOperationView) string {
+	line := fmt.Sprintf("%s %s %s", ui.Bold.Render(op.ID), op.Summary, ui.Hint(fmt.Sprintf("(%d file(s), %s)", op.Files, op.CreatedAt)))
+	if op.Undone {
+		line += " " + ui.Hint("[undone]")
+	}
+	return line
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(undoCmd)
+}
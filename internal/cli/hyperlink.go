@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/x/term"
 
 	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/ui"
 )
 
 // hyperlinkEnabled caches whether we should emit hyperlinks.
@@ -30,8 +31,11 @@ func shouldEmitHyperlinks() bool {
 		return *hyperlinkEnabled
 	}
 
-	// Don't emit hyperlinks for JSON output or non-TTY
+	// Don't emit hyperlinks for JSON output, non-TTY, or when [ui].hyperlinks = false
 	enabled := !jsonOutput && term.IsTerminal(os.Stdout.Fd()) && !hyperlinksDisabled
+	if c := getConfig(); c != nil && !c.UI.HyperlinksEnabled() {
+		enabled = false
+	}
 	hyperlinkEnabled = &enabled
 	return enabled
 }
@@ -88,6 +92,19 @@ func buildEditorURL(cfg *config.Config, absPath string, line int) string {
 	}
 }
 
+// editorLinkURL returns the editor URL for a vault-relative path, or false when
+// hyperlinks should not be emitted.
+func editorLinkURL(relPath string, line int) (string, bool) {
+	if !shouldEmitHyperlinks() {
+		return "", false
+	}
+	vaultPath := getVaultPath()
+	if vaultPath == "" {
+		return "", false
+	}
+	return buildEditorURL(getConfig(), filepath.Join(vaultPath, relPath), line), true
+}
+
 func formatLocationLinkSimpleStyled(relPath string, line int, render func(...string) string) string {
 	location := fmt.Sprintf("%s:%d", relPath, line)
 	if render == nil {
@@ -98,21 +115,27 @@ func formatLocationLinkSimpleStyled(relPath string, line int, render func(...str
 			return strs[0]
 		}
 	}
-
-	if !shouldEmitHyperlinks() {
-		return render(location)
+	if url, ok := editorLinkURL(relPath, line); ok {
+		location = ui.Hyperlink(url, location)
 	}
+	return render(location)
+}
 
-	// Get vault path and config
-	vaultPath := getVaultPath()
-	cfg := getConfig()
-
-	if vaultPath == "" {
-		return render(location)
+// formatFileLink renders a vault-relative file path, linked to the file when
+// hyperlinks are enabled.
+func formatFileLink(relPath string) string {
+	if url, ok := editorLinkURL(relPath, 1); ok {
+		return ui.FilePath(ui.Hyperlink(url, relPath))
 	}
+	return ui.FilePath(relPath)
+}
 
-	absPath := filepath.Join(vaultPath, relPath)
-	url := buildEditorURL(cfg, absPath, line)
-
-	return render(fmt.Sprintf("\x1b]8;;%s\x07%s\x1b]8;;\x07", url, location))
+// formatTruncatedFileLink truncates a file path to width for table cells,
+// keeping the link target pointed at the full path.
+func formatTruncatedFileLink(relPath string, width int) string {
+	label := ui.TruncateWithEllipsis(relPath, width)
+	if url, ok := editorLinkURL(relPath, 1); ok {
+		return ui.Hyperlink(url, label)
+	}
+	return label
 }
@@ -32,6 +32,37 @@ var importCmd = newCanonicalLeafCommand("import", canonicalLeafOptions{
 	SkipFlagBinding: true,
 })
 
+var importCSVCmd = newCanonicalLeafCommand("import_csv", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	Invoke:      invokeImportCSV,
+	RenderHuman: renderImportCSVResult,
+})
+
+var importMarkdownCmd = newCanonicalLeafCommand("import_markdown", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderImportMarkdownResult,
+})
+
+var importObsidianCmd = newCanonicalLeafCommand("import_obsidian", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderImportObsidianResult,
+})
+
+var importLogseqCmd = newCanonicalLeafCommand("import_logseq", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderImportOutlineResult,
+})
+
+var importOrgRoamCmd = newCanonicalLeafCommand("import_org_roam", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderImportOutlineResult,
+})
+
+var importNotionCmd = newCanonicalLeafCommand("import_notion", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderImportNotionResult,
+})
+
 type importResult = importsvc.ResultItem
 
 func buildImportArgs(_ *cobra.Command, args []string) (map[string]interface{}, error) {
@@ -70,11 +101,44 @@ func invokeImport(_ *cobra.Command, commandID, vaultPath string, args map[string
 }
 
 func renderImportResult(_ *cobra.Command, result commandexec.Result) error {
-	return renderCanonicalImportResult(result)
+	return renderCanonicalImportResult(result, importDryRun)
+}
+
+func invokeImportCSV(_ *cobra.Command, commandID, vaultPath string, args map[string]interface{}) commandexec.Result {
+	var stdinData []byte
+	if strings.TrimSpace(stringValue(args["file"])) == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return commandexec.Failure(ErrInvalidInput, err.Error(), nil, "Expected CSV with a header row")
+		}
+		stdinData = data
+	}
+
+	return executeCanonicalRequest(commandexec.Request{
+		CommandID: commandID,
+		VaultPath: vaultPath,
+		Args:      args,
+		Stdin:     stdinData,
+	})
+}
+
+func renderImportCSVResult(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	preview := boolValue(data["preview"])
+	if err := renderCanonicalImportResult(result, preview); err != nil {
+		return err
+	}
+	if ignored, _ := data["ignored_columns"].([]string); len(ignored) > 0 {
+		fmt.Println(ui.Hint("Ignored columns (no matching field): " + strings.Join(ignored, ", ")))
+	}
+	if preview {
+		fmt.Println(ui.Hint("Run with --confirm to apply changes."))
+	}
+	return nil
 }
 
 // outputImportResults outputs the import results in human-readable or JSON format.
-func outputImportResults(results []importResult, warnings []Warning) error {
+func outputImportResults(results []importResult, warnings []Warning, dryRun bool) error {
 	// Count outcomes
 	var created, updated, skipped, errored int
 	for _, r := range results {
@@ -108,7 +172,7 @@ func outputImportResults(results []importResult, warnings []Warning) error {
 	}
 
 	// Human-readable output
-	if importDryRun {
+	if dryRun {
 		fmt.Println(ui.Bold.Render("Dry run — no changes made:"))
 	}
 
@@ -158,7 +222,7 @@ func outputImportResults(results []importResult, warnings []Warning) error {
 	return nil
 }
 
-func renderCanonicalImportResult(result commandexec.Result) error {
+func renderCanonicalImportResult(result commandexec.Result, dryRun bool) error {
 	data := canonicalDataMap(result)
 	results := make([]importResult, 0)
 	switch rawResults := data["results"].(type) {
@@ -183,10 +247,278 @@ func renderCanonicalImportResult(result commandexec.Result) error {
 			results = append(results, item)
 		}
 	}
-	return outputImportResults(results, result.Warnings)
+	return outputImportResults(results, result.Warnings, dryRun)
+}
+
+func renderImportMarkdownResult(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	files, _ := data["files"].([]importsvc.MarkdownImportFile)
+	unresolved, _ := data["unresolved"].([]importsvc.UnresolvedLink)
+	destination := stringValue(data["destination"])
+
+	if boolValue(data["dry_run"]) {
+		fmt.Println(ui.Bold.Render("Dry run — no changes made:"))
+		for _, file := range files {
+			fmt.Printf("  %s %s\n", ui.Bold.Render("create"), formatImportedFile(file))
+		}
+	} else {
+		fmt.Println(ui.Checkf("Imported %d files into %s", len(files), ui.FilePath(destination)))
+		for _, file := range files {
+			fmt.Printf("  %s\n", formatImportedFile(file))
+		}
+	}
+
+	if len(unresolved) > 0 {
+		fmt.Printf("\n%s\n", ui.Warningf("Unconverted links (%d):", len(unresolved)))
+		for _, link := range unresolved {
+			fmt.Printf("  %s:%d %s %s\n", link.Source, link.Line, link.Link, ui.Hint("("+link.Reason+")"))
+		}
+	}
+	for _, w := range result.Warnings {
+		fmt.Printf("  %s\n", ui.Warning(w.Message))
+	}
+	return nil
+}
+
+func formatImportedFile(file importsvc.MarkdownImportFile) string {
+	line := fmt.Sprintf("%s → %s", file.Source, ui.FilePath(file.File))
+	var notes []string
+	if file.Renamed {
+		notes = append(notes, "renamed to avoid an existing ID")
+	}
+	if file.LinksConverted == 1 {
+		notes = append(notes, "1 link converted")
+	} else if file.LinksConverted > 1 {
+		notes = append(notes, fmt.Sprintf("%d links converted", file.LinksConverted))
+	}
+	if len(notes) > 0 {
+		line += " " + ui.Hint("("+strings.Join(notes, ", ")+")")
+	}
+	return line
+}
+
+func renderImportObsidianResult(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	files, _ := data["files"].([]importsvc.ObsidianImportFile)
+	types, _ := data["types"].([]importsvc.ProposedType)
+	unresolved, _ := data["unresolved"].([]importsvc.UnresolvedLink)
+	destination := stringValue(data["destination"])
+
+	if boolValue(data["dry_run"]) {
+		fmt.Println(ui.Bold.Render("Dry run — no changes made:"))
+		for _, file := range files {
+			fmt.Printf("  %s %s\n", ui.Bold.Render("create"), formatObsidianFile(file))
+		}
+	} else {
+		fmt.Println(ui.Checkf("Imported %d files into %s", len(files), ui.FilePath(destination)))
+		for _, file := range files {
+			fmt.Printf("  %s\n", formatObsidianFile(file))
+		}
+	}
+
+	if len(types) > 0 {
+		fmt.Printf("\n%s\n", ui.SectionHeader("Types from folders"))
+		for _, proposed := range types {
+			note := fmt.Sprintf("(%d notes)", proposed.Files)
+			if proposed.Exists {
+				note = fmt.Sprintf("(%d notes, already in schema.yaml)", proposed.Files)
+			}
+			fmt.Printf("  %s/ → %s %s\n", proposed.Folder, ui.Bold.Render(proposed.Name), ui.Hint(note))
+		}
+	}
+	if len(unresolved) > 0 {
+		fmt.Printf("\n%s\n", ui.Warningf("Unconverted links (%d):", len(unresolved)))
+		for _, link := range unresolved {
+			fmt.Printf("  %s:%d %s %s\n", link.Source, link.Line, link.Link, ui.Hint("("+link.Reason+")"))
+		}
+	}
+	if proposal := stringValue(data["schema_proposal"]); proposal != "" {
+		fmt.Printf("\n%s\n", ui.SectionHeader("Proposed schema.yaml additions"))
+		fmt.Print(proposal)
+		fmt.Println(ui.Hint("Merge these into schema.yaml, then run 'rvn reindex'."))
+	}
+	if !boolValue(data["hashtags_indexed"]) {
+		fmt.Println(ui.Hint(fmt.Sprintf("To index #tags in note bodies as @%s traits, set hashtags.enabled: true in raven.yaml.", stringValue(data["tag_trait"]))))
+	}
+	for _, w := range result.Warnings {
+		fmt.Printf("  %s\n", ui.Warning(w.Message))
+	}
+	return nil
+}
+
+func formatObsidianFile(file importsvc.ObsidianImportFile) string {
+	line := fmt.Sprintf("%s → %s", file.Source, ui.FilePath(file.File))
+	var notes []string
+	if file.Type != "" {
+		notes = append(notes, "type "+file.Type)
+	}
+	if file.Renamed {
+		notes = append(notes, "renamed to avoid an existing ID")
+	}
+	if file.LinksConverted == 1 {
+		notes = append(notes, "1 link converted")
+	} else if file.LinksConverted > 1 {
+		notes = append(notes, fmt.Sprintf("%d links converted", file.LinksConverted))
+	}
+	if len(file.Tags) > 0 {
+		notes = append(notes, fmt.Sprintf("%d tags", len(file.Tags)))
+	}
+	if len(file.Fields) > 0 {
+		notes = append(notes, "fields: "+strings.Join(file.Fields, ", "))
+	}
+	if len(notes) > 0 {
+		line += " " + ui.Hint("("+strings.Join(notes, ", ")+")")
+	}
+	return line
+}
+
+func renderImportOutlineResult(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	files, _ := data["files"].([]importsvc.OutlineImportFile)
+	unresolved, _ := data["unresolved"].([]importsvc.UnresolvedLink)
+	destination := stringValue(data["destination"])
+
+	if boolValue(data["dry_run"]) {
+		fmt.Println(ui.Bold.Render("Dry run — no changes made:"))
+		for _, file := range files {
+			fmt.Printf("  %s %s\n", ui.Bold.Render("create"), formatOutlineFile(file))
+		}
+	} else {
+		fmt.Println(ui.Checkf("Imported %d files into %s", len(files), ui.FilePath(destination)))
+		for _, file := range files {
+			fmt.Printf("  %s\n", formatOutlineFile(file))
+		}
+	}
+
+	if len(unresolved) > 0 {
+		fmt.Printf("\n%s\n", ui.Warningf("Unconverted links (%d):", len(unresolved)))
+		for _, link := range unresolved {
+			fmt.Printf("  %s:%d %s %s\n", link.Source, link.Line, link.Link, ui.Hint("("+link.Reason+")"))
+		}
+	}
+	if proposal := stringValue(data["schema_proposal"]); proposal != "" {
+		fmt.Printf("\n%s\n", ui.SectionHeader("Proposed schema.yaml additions"))
+		fmt.Print(proposal)
+		fmt.Println(ui.Hint("Merge these into schema.yaml, then run 'rvn reindex'."))
+	}
+	if !boolValue(data["hashtags_indexed"]) {
+		fmt.Println(ui.Hint(fmt.Sprintf("To index #tags in note bodies as @%s traits, set hashtags.enabled: true in raven.yaml.", stringValue(data["tag_trait"]))))
+	}
+	for _, w := range result.Warnings {
+		fmt.Printf("  %s\n", ui.Warning(w.Message))
+	}
+	return nil
+}
+
+func formatOutlineFile(file importsvc.OutlineImportFile) string {
+	line := fmt.Sprintf("%s → %s", file.Source, ui.FilePath(file.File))
+	var notes []string
+	if file.Type != "" {
+		notes = append(notes, "type "+file.Type)
+	}
+	if file.Renamed {
+		notes = append(notes, "renamed to avoid an existing ID")
+	}
+	if file.Sections > 0 {
+		notes = append(notes, fmt.Sprintf("%d sections", file.Sections))
+	}
+	if file.Tasks > 0 {
+		notes = append(notes, fmt.Sprintf("%d tasks", file.Tasks))
+	}
+	if file.LinksConverted == 1 {
+		notes = append(notes, "1 link converted")
+	} else if file.LinksConverted > 1 {
+		notes = append(notes, fmt.Sprintf("%d links converted", file.LinksConverted))
+	}
+	if len(file.Fields) > 0 {
+		notes = append(notes, "fields: "+strings.Join(file.Fields, ", "))
+	}
+	if len(notes) > 0 {
+		line += " " + ui.Hint("("+strings.Join(notes, ", ")+")")
+	}
+	return line
+}
+
+func renderImportNotionResult(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	files, _ := data["files"].([]importsvc.NotionImportFile)
+	types, _ := data["types"].([]importsvc.ProposedType)
+	unresolved, _ := data["unresolved"].([]importsvc.UnresolvedLink)
+	destination := stringValue(data["destination"])
+
+	if boolValue(data["dry_run"]) {
+		fmt.Println(ui.Bold.Render("Dry run — no changes made:"))
+		for _, file := range files {
+			fmt.Printf("  %s %s\n", ui.Bold.Render("create"), formatNotionFile(file))
+		}
+	} else {
+		fmt.Println(ui.Checkf("Imported %d files into %s", len(files), ui.FilePath(destination)))
+		for _, file := range files {
+			fmt.Printf("  %s\n", formatNotionFile(file))
+		}
+	}
+
+	if len(types) > 0 {
+		fmt.Printf("\n%s\n", ui.SectionHeader("Types from databases"))
+		for _, proposed := range types {
+			rows := fmt.Sprintf("%d rows", proposed.Files)
+			if proposed.Files == 1 {
+				rows = "1 row"
+			}
+			note := "(" + rows + ")"
+			if proposed.Exists {
+				note = "(" + rows + ", already in schema.yaml)"
+			}
+			fmt.Printf("  %s → %s %s\n", proposed.Folder, ui.Bold.Render(proposed.Name), ui.Hint(note))
+		}
+	}
+	if len(unresolved) > 0 {
+		fmt.Printf("\n%s\n", ui.Warningf("Unconverted links (%d):", len(unresolved)))
+		for _, link := range unresolved {
+			fmt.Printf("  %s:%d %s %s\n", link.Source, link.Line, link.Link, ui.Hint("("+link.Reason+")"))
+		}
+	}
+	if proposal := stringValue(data["schema_proposal"]); proposal != "" {
+		fmt.Printf("\n%s\n", ui.SectionHeader("Proposed schema.yaml additions"))
+		fmt.Print(proposal)
+		fmt.Println(ui.Hint("Merge these into schema.yaml, then run 'rvn reindex'."))
+	}
+	for _, w := range result.Warnings {
+		fmt.Printf("  %s\n", ui.Warning(w.Message))
+	}
+	return nil
+}
+
+func formatNotionFile(file importsvc.NotionImportFile) string {
+	line := fmt.Sprintf("%s → %s", file.Source, ui.FilePath(file.File))
+	var notes []string
+	if file.Type != "" {
+		notes = append(notes, "type "+file.Type)
+	}
+	if file.Renamed {
+		notes = append(notes, "renamed to avoid an existing ID")
+	}
+	if file.LinksConverted == 1 {
+		notes = append(notes, "1 link converted")
+	} else if file.LinksConverted > 1 {
+		notes = append(notes, fmt.Sprintf("%d links converted", file.LinksConverted))
+	}
+	if len(file.Fields) > 0 {
+		notes = append(notes, "fields: "+strings.Join(file.Fields, ", "))
+	}
+	if len(notes) > 0 {
+		line += " " + ui.Hint("("+strings.Join(notes, ", ")+")")
+	}
+	return line
 }
 
 func init() {
+	importCmd.AddCommand(importCSVCmd)
+	importCmd.AddCommand(importMarkdownCmd)
+	importCmd.AddCommand(importObsidianCmd)
+	importCmd.AddCommand(importLogseqCmd)
+	importCmd.AddCommand(importOrgRoamCmd)
+	importCmd.AddCommand(importNotionCmd)
 	importCmd.Flags().StringVar(&importFile, "file", "", "Read JSON from file instead of stdin")
 	importCmd.Flags().StringVar(&importMapping, "mapping", "", "Path to YAML mapping file")
 	importCmd.Flags().StringArrayVar(&importMapFlags, "map", nil, "Field mapping: external_key=schema_field (repeatable)")
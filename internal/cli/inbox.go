@@ -0,0 +1,340 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/ui"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+var inboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "List and triage objects waiting in the inbox",
+	Long: `List and triage objects that still need processing.
+
+The inbox is everything under the inbox directory (default: inbox/). With
+--untyped, untyped pages anywhere in the vault are included too.
+
+Use 'rvn inbox list' to see the items and 'rvn inbox triage' to work
+through them one at a time in an interactive terminal.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var inboxListCmd = newCanonicalLeafCommand("inbox_list", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderInboxList,
+})
+
+var inboxTriageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "Process inbox items one at a time",
+	Long: `Walks through inbox items one at a time and offers quick actions:
+
+  r <type>         reclassify to a type
+  m <destination>  move the file
+  d                delete (asks for confirmation)
+  f <key=value>    set a field
+  l <target>       append a [[target]] link
+  o                open in your editor
+  s                skip to the next item
+  q                quit
+
+Reclassify, move, delete, and skip advance to the next item; the other
+actions stay on the current item. Requires an interactive terminal.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if isJSONOutput() {
+			return handleErrorMsg(ErrInvalidInput, "--json cannot be used with inbox triage", "Use 'rvn inbox list --json' instead")
+		}
+		if !shouldPromptForConfirm() {
+			return handleErrorMsg(ErrInvalidInput, "inbox triage requires an interactive terminal", "Use 'rvn inbox list' in scripts")
+		}
+
+		vaultPath := getVaultPath()
+		dir, _ := cmd.Flags().GetString("dir")
+		untyped, _ := cmd.Flags().GetBool("untyped")
+		result := executeCanonicalRequest(commandexec.Request{
+			CommandID: "inbox_list",
+			VaultPath: vaultPath,
+			Args: map[string]interface{}{
+				"dir":     dir,
+				"untyped": untyped,
+			},
+		})
+		if !result.OK {
+			if result.Error != nil {
+				return handleErrorMsg(result.Error.Code, result.Error.Message, result.Error.Suggestion)
+			}
+			return handleErrorMsg(ErrInternal, "failed to list inbox", "")
+		}
+
+		items := objectResultsFromAny(canonicalDataMap(result)["items"])
+		if len(items) == 0 {
+			fmt.Println(ui.Starf("Inbox is empty"))
+			return nil
+		}
+
+		interaction := newCheckInteraction(os.Stdin, os.Stdout)
+		summary := triageInbox(interaction, vaultPath, items, executeCanonicalRequest)
+		fmt.Println()
+		fmt.Println(ui.Checkf("Processed %d of %d item(s)", summary.processed, len(items)))
+		return nil
+	},
+}
+
+func renderInboxList(cmd *cobra.Command, result commandexec.Result) error {
+	printStaleIndexWarning(result.Meta)
+
+	data := canonicalDataMap(result)
+	objects := objectResultsFromAny(data["items"])
+	dir := stringValue(data["dir"])
+	if len(objects) == 0 {
+		fmt.Println(ui.Starf("Inbox is empty (%s)", dir))
+		return nil
+	}
+
+	fmt.Println(ui.SectionHeader(fmt.Sprintf("Inbox (%s): %d item(s)", dir, intFromAny(data["total"]))))
+	sch, _ := schema.Load(getVaultPath())
+	printMixedObjectTable(objects, sch, newFieldDisplay(false))
+	fmt.Println(ui.Hint("Run 'rvn inbox triage' to process these items."))
+	return nil
+}
+
+type inboxTriageSummary struct {
+	processed int
+	skipped   int
+}
+
+// triageInbox prompts for an action on each item and dispatches it through
+// run. It returns when every item has been visited or the user quits.
+func triageInbox(interaction checkInteraction, vaultPath string, items []model.Object, run func(commandexec.Request) commandexec.Result) inboxTriageSummary {
+	var summary inboxTriageSummary
+	for i, item := range items {
+		interaction.Println()
+		interaction.Println(ui.SectionHeader(fmt.Sprintf("[%d/%d] %s", i+1, len(items), item.ID)))
+		printInboxItemPreview(interaction, vaultPath, item)
+
+		for done := false; !done; {
+			interaction.Printf("%s ", ui.Hint("[r]eclassify, [m]ove, [d]elete, [f]ield, [l]ink, [o]pen, [s]kip, [q]uit"))
+			line, err := interaction.ReadLine()
+			if err != nil && strings.TrimSpace(line) == "" {
+				return summary
+			}
+			action, value := parseInboxTriageInput(line)
+
+			var req *commandexec.Request
+			switch action {
+			case "r":
+				value = promptInboxValue(interaction, value, "Type")
+				if value == "" {
+					continue
+				}
+				result, applied := runInteractiveReclassify(interaction, run, vaultPath, item.ID, value)
+				if !result.OK {
+					interaction.Println(ui.Errorf("%s", canonicalFailureMessage(result)))
+					continue
+				}
+				if applied {
+					interaction.Println(ui.Checkf("%s", inboxTriageOutcome(action, value, result)))
+					summary.processed++
+					done = true
+				}
+				continue
+			case "m":
+				value = promptInboxValue(interaction, value, "Destination")
+				if value == "" {
+					continue
+				}
+				args := map[string]interface{}{"source": item.ID, "destination": value, "update-refs": true}
+				result := run(commandexec.Request{CommandID: "move", VaultPath: vaultPath, Args: args})
+				if result.OK && boolValue(canonicalDataMap(result)["needs_confirm"]) {
+					// The destination does not match the type's directory.
+					for _, warning := range result.Warnings {
+						interaction.Println(ui.Warningf("%s", warning.Message))
+					}
+					interaction.Printf("  Move anyway? %s ", ui.Hint("[y/N]"))
+					if answer := readTrimmedLowerLine(interaction); answer != "y" && answer != "yes" {
+						continue
+					}
+					retryArgs := cloneArgsMap(args)
+					retryArgs["skip-type-check"] = true
+					result = run(commandexec.Request{CommandID: "move", VaultPath: vaultPath, Args: retryArgs})
+				}
+				if !result.OK {
+					interaction.Println(ui.Errorf("%s", canonicalFailureMessage(result)))
+					continue
+				}
+				interaction.Println(ui.Checkf("%s", inboxTriageOutcome(action, value, result)))
+				summary.processed++
+				done = true
+				continue
+			case "d":
+				interaction.Printf("Delete %s? %s ", item.ID, ui.Hint("[y/N]"))
+				if answer := readTrimmedLowerLine(interaction); answer == "y" || answer == "yes" {
+					req = &commandexec.Request{CommandID: "delete", Args: map[string]interface{}{"object_id": item.ID}, Confirm: true}
+				}
+			case "f":
+				value = promptInboxValue(interaction, value, "Field (key=value)")
+				key, fieldValue, ok := strings.Cut(value, "=")
+				if !ok || strings.TrimSpace(key) == "" {
+					if value != "" {
+						interaction.Println(ui.Errorf("Use key=value"))
+					}
+					continue
+				}
+				req = &commandexec.Request{CommandID: "set", Args: map[string]interface{}{
+					"object_id": item.ID,
+					"fields":    map[string]interface{}{strings.TrimSpace(key): strings.TrimSpace(fieldValue)},
+				}}
+			case "l":
+				value = strings.Trim(promptInboxValue(interaction, value, "Link target"), "[]")
+				if value != "" {
+					req = &commandexec.Request{CommandID: "add", Args: map[string]interface{}{"text": "[[" + value + "]]", "to": item.ID}}
+				}
+			case "o":
+				req = &commandexec.Request{CommandID: "open", Args: map[string]interface{}{"reference": item.ID}}
+			case "s", "":
+				summary.skipped++
+				done = true
+				continue
+			case "q":
+				return summary
+			default:
+				interaction.Println(ui.Errorf("Unknown action %q", action))
+				continue
+			}
+			if req == nil {
+				continue
+			}
+
+			req.VaultPath = vaultPath
+			result := run(*req)
+			if !result.OK {
+				interaction.Println(ui.Errorf("%s", canonicalFailureMessage(result)))
+				continue
+			}
+			interaction.Println(ui.Checkf("%s", inboxTriageOutcome(action, value, result)))
+			if action == "d" {
+				summary.processed++
+				done = true
+			}
+		}
+	}
+	return summary
+}
+
+// runInteractiveReclassify reclassifies objectID as typeName, asking before
+// dropping fields the new type does not define. applied is false when the
+// command failed or the user declined.
+func runInteractiveReclassify(interaction checkInteraction, run func(commandexec.Request) commandexec.Result, vaultPath, objectID, typeName string) (result commandexec.Result, applied bool) {
+	args := map[string]interface{}{"object": objectID, "new-type": typeName}
+	result = run(commandexec.Request{CommandID: "reclassify", VaultPath: vaultPath, Args: args})
+	if !result.OK {
+		return result, false
+	}
+	data := canonicalDataMap(result)
+	if !boolValue(data["needs_confirm"]) {
+		return result, true
+	}
+
+	interaction.Printf("  Type '%s' does not define %s; drop them? %s ", typeName, strings.Join(stringSliceFromAny(data["dropped_fields"]), ", "), ui.Hint("[y/N]"))
+	if answer := readTrimmedLowerLine(interaction); answer != "y" && answer != "yes" {
+		return result, false
+	}
+	retryArgs := cloneArgsMap(args)
+	retryArgs["force"] = true
+	result = run(commandexec.Request{CommandID: "reclassify", VaultPath: vaultPath, Args: retryArgs})
+	return result, result.OK
+}
+
+func canonicalFailureMessage(result commandexec.Result) string {
+	if result.Error != nil && result.Error.Message != "" {
+		return result.Error.Message
+	}
+	return "command failed"
+}
+
+// parseInboxTriageInput splits "m projects/" into the action letter and its
+// argument. Full action words ("move") are accepted too.
+func parseInboxTriageInput(line string) (string, string) {
+	line = strings.TrimSpace(line)
+	word, rest, _ := strings.Cut(line, " ")
+	word = strings.ToLower(word)
+	if word != "" {
+		word = word[:1]
+	}
+	return word, strings.TrimSpace(rest)
+}
+
+func promptInboxValue(interaction checkInteraction, value, label string) string {
+	if value != "" {
+		return value
+	}
+	interaction.Printf("  %s: ", label)
+	return readTrimmedLine(interaction)
+}
+
+func inboxTriageOutcome(action, value string, result commandexec.Result) string {
+	data := canonicalDataMap(result)
+	switch action {
+	case "r":
+		return fmt.Sprintf("Reclassified as %s", value)
+	case "m":
+		if dest := stringValue(data["destination"]); dest != "" {
+			return fmt.Sprintf("Moved to %s", dest)
+		}
+		return fmt.Sprintf("Moved to %s", value)
+	case "d":
+		return "Deleted"
+	case "f":
+		return fmt.Sprintf("Set %s", value)
+	case "l":
+		return fmt.Sprintf("Linked [[%s]]", value)
+	case "o":
+		if !boolValue(data["opened"]) {
+			return fmt.Sprintf("File: %s", stringValue(data["file"]))
+		}
+		return "Opened in editor"
+	default:
+		return "Done"
+	}
+}
+
+func printInboxItemPreview(interaction checkInteraction, vaultPath string, item model.Object) {
+	interaction.Println(ui.Muted.Render(fmt.Sprintf("%s · %s", item.Type, item.FilePath)))
+	if len(item.Fields) > 0 {
+		keys := make([]string, 0, len(item.Fields))
+		for key := range item.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			interaction.Println(ui.Bullet(fmt.Sprintf("%s: %s", key, formatFieldValueSimple(item.Fields[key]))))
+		}
+	}
+	content, err := vaultcrypt.ReadFile(filepath.Join(vaultPath, item.FilePath))
+	if err == nil {
+		interaction.Println(previewExcerpt(string(content), 0))
+	}
+}
+
+func init() {
+	inboxTriageCmd.Flags().String("dir", "", "Vault-relative inbox directory (default: inbox/)")
+	inboxTriageCmd.Flags().Bool("untyped", false, "Also include untyped pages outside the inbox directory")
+	markLocalLeaf(inboxTriageCmd)
+	inboxCmd.AddCommand(inboxListCmd)
+	inboxCmd.AddCommand(inboxTriageCmd)
+	rootCmd.AddCommand(inboxCmd)
+}
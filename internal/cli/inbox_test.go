@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/model"
+)
+
+func TestTriageInboxDispatchesActions(t *testing.T) {
+	items := []model.Object{
+		{ID: "inbox/idea", Type: "page", FilePath: "inbox/idea.md"},
+		{ID: "inbox/call", Type: "page", FilePath: "inbox/call.md"},
+		{ID: "inbox/junk", Type: "page", FilePath: "inbox/junk.md"},
+		{ID: "inbox/later", Type: "page", FilePath: "inbox/later.md"},
+		{ID: "inbox/never", Type: "page", FilePath: "inbox/never.md"},
+	}
+	interaction := &fakeCheckInteraction{inputs: []string{
+		"f status=active", // stays on inbox/idea
+		"l projects/raven",
+		"r project",
+		"m",
+		"meetings/call",
+		"d",
+		"y",
+		"s",
+		"q",
+	}}
+
+	var got []commandexec.Request
+	run := func(req commandexec.Request) commandexec.Result {
+		got = append(got, req)
+		return commandexec.Success(map[string]interface{}{}, nil)
+	}
+
+	summary := triageInbox(interaction, "/vault", items, run)
+	if summary.processed != 3 || summary.skipped != 1 {
+		t.Fatalf("summary = %+v, want 3 processed and 1 skipped", summary)
+	}
+
+	want := []commandexec.Request{
+		{CommandID: "set", VaultPath: "/vault", Args: map[string]interface{}{"object_id": "inbox/idea", "fields": map[string]interface{}{"status": "active"}}},
+		{CommandID: "add", VaultPath: "/vault", Args: map[string]interface{}{"text": "[[projects/raven]]", "to": "inbox/idea"}},
+		{CommandID: "reclassify", VaultPath: "/vault", Args: map[string]interface{}{"object": "inbox/idea", "new-type": "project"}},
+		{CommandID: "move", VaultPath: "/vault", Args: map[string]interface{}{"source": "inbox/call", "destination": "meetings/call", "update-refs": true}},
+		{CommandID: "delete", VaultPath: "/vault", Args: map[string]interface{}{"object_id": "inbox/junk"}, Confirm: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("requests = %#v\nwant %#v", got, want)
+	}
+}
+
+func TestTriageInboxKeepsItemOnFailure(t *testing.T) {
+	items := []model.Object{{ID: "inbox/idea", Type: "page", FilePath: "inbox/idea.md"}}
+	interaction := &fakeCheckInteraction{inputs: []string{"r nosuchtype", "d", "n", "x", "s"}}
+
+	calls := 0
+	run := func(req commandexec.Request) commandexec.Result {
+		calls++
+		return commandexec.Failure("TYPE_NOT_FOUND", "type 'nosuchtype' not found", nil, "")
+	}
+
+	summary := triageInbox(interaction, "/vault", items, run)
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1 (declined delete must not run)", calls)
+	}
+	if summary.processed != 0 || summary.skipped != 1 {
+		t.Fatalf("summary = %+v, want only a skip", summary)
+	}
+}
+
+func TestTriageInboxConfirmsDroppedFields(t *testing.T) {
+	items := []model.Object{
+		{ID: "inbox/idea", Type: "page", FilePath: "inbox/idea.md"},
+		{ID: "inbox/call", Type: "page", FilePath: "inbox/call.md"},
+	}
+	interaction := &fakeCheckInteraction{inputs: []string{"r project", "n", "s", "r meeting", "y"}}
+
+	var got []commandexec.Request
+	run := func(req commandexec.Request) commandexec.Result {
+		got = append(got, req)
+		if req.Args["force"] == true {
+			return commandexec.Success(map[string]interface{}{}, nil)
+		}
+		return commandexec.Success(map[string]interface{}{"needs_confirm": true, "dropped_fields": []string{"source"}}, nil)
+	}
+
+	summary := triageInbox(interaction, "/vault", items, run)
+	if summary.processed != 1 || summary.skipped != 1 {
+		t.Fatalf("summary = %+v, want 1 processed and 1 skipped", summary)
+	}
+	if len(got) != 3 || got[2].Args["force"] != true || got[2].Args["object"] != "inbox/call" {
+		t.Fatalf("requests = %#v, want a forced retry for inbox/call only", got)
+	}
+}
+
+func TestParseInboxTriageInput(t *testing.T) {
+	tests := []struct {
+		line       string
+		wantAction string
+		wantValue  string
+	}{
+		{line: "r project", wantAction: "r", wantValue: "project"},
+		{line: "  Move  archive/old  ", wantAction: "m", wantValue: "archive/old"},
+		{line: "f due=2025-03-01", wantAction: "f", wantValue: "due=2025-03-01"},
+		{line: "", wantAction: "", wantValue: ""},
+	}
+	for _, tc := range tests {
+		action, value := parseInboxTriageInput(tc.line)
+		if action != tc.wantAction || value != tc.wantValue {
+			t.Fatalf("parseInboxTriageInput(%q) = %q, %q; want %q, %q", tc.line, action, value, tc.wantAction, tc.wantValue)
+		}
+	}
+}
@@ -675,6 +675,54 @@ status: done
 	}
 }
 
+func TestIntegration_QueryReportsIndexFreshness(t *testing.T) {
+	t.Parallel()
+	v := testutil.NewTestVault(t).
+		WithSchema(testutil.PersonProjectSchema()).
+		WithFile("people/alice.md", `---
+type: person
+name: Alice
+---
+`).
+		Build()
+
+	v.RunCLI("reindex").MustSucceed(t)
+
+	fresh := v.RunCLI("query", "type:person")
+	fresh.MustSucceed(t)
+	if fresh.Meta == nil || fresh.Meta.Freshness == nil || fresh.Meta.Freshness.Stale {
+		t.Fatalf("expected fresh index metadata, got %s", fresh.RawJSON)
+	}
+
+	filePath := filepath.Join(v.Path, "people/alice.md")
+	if err := os.WriteFile(filePath, []byte("---\ntype: person\nname: Alicia\n---\n"), 0o644); err != nil {
+		t.Fatalf("failed to update person file: %v", err)
+	}
+	future := time.Now().Add(2 * time.Second)
+	if err := os.Chtimes(filePath, future, future); err != nil {
+		t.Fatalf("failed to bump person mtime: %v", err)
+	}
+
+	stale := v.RunCLI("query", "type:person")
+	stale.MustSucceed(t)
+	if stale.Meta == nil || stale.Meta.Freshness == nil || !stale.Meta.Freshness.Stale {
+		t.Fatalf("expected stale index metadata, got %s", stale.RawJSON)
+	}
+	if got := stale.Meta.Freshness.StaleFiles; len(got) != 1 || got[0] != "people/alice.md" {
+		t.Fatalf("stale_files = %v, want [people/alice.md]", got)
+	}
+
+	refreshed := v.RunCLI("query", "type:person", "--require-fresh")
+	refreshed.MustSucceed(t)
+	if freshness := refreshed.Meta.Freshness; freshness == nil || freshness.Stale || freshness.Refreshed != 1 {
+		t.Fatalf("expected --require-fresh to reindex one file, got %s", refreshed.RawJSON)
+	}
+	item, _ := refreshed.DataList("items")[0].(map[string]interface{})
+	if fields, _ := item["fields"].(map[string]interface{}); fields["name"] != "Alicia" {
+		t.Fatalf("expected refreshed name Alicia, got %#v", item["fields"])
+	}
+}
+
 func TestIntegration_QueryRefreshRemovesDeletedFiles(t *testing.T) {
 	t.Parallel()
 	v := testutil.NewTestVault(t).
@@ -2519,7 +2567,7 @@ Second section
 			WithFile("broken.md", `---
 type: page
 meta:
-  nested: true
+  1: true
 ---
 # Broken
 `).
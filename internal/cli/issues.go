@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+// issueRefsFromAny converts an issues value from a command result, which is
+// []model.IssueRef in process or decoded JSON otherwise.
+func issueRefsFromAny(raw interface{}) []model.IssueRef {
+	if refs, ok := raw.([]model.IssueRef); ok {
+		return refs
+	}
+	if raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var refs []model.IssueRef
+	if err := json.Unmarshal(data, &refs); err != nil {
+		return nil
+	}
+	return refs
+}
+
+// formatIssueRefsInline renders issues for a table cell, e.g.
+// "PROJ-1 (In Progress), acme/web#4 (closed)".
+func formatIssueRefsInline(refs []model.IssueRef) string {
+	parts := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Status != "" {
+			parts = append(parts, fmt.Sprintf("%s (%s)", ref.Key, ref.Status))
+		} else {
+			parts = append(parts, ref.Key)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatIssueRefLine renders one issue for a list: key, status, and title.
+func formatIssueRefLine(ref model.IssueRef) string {
+	key := ref.Key
+	if ref.URL != "" && shouldEmitHyperlinks() {
+		key = ui.Hyperlink(ref.URL, ref.Key)
+	}
+	line := ui.Bold.Render(key)
+	if ref.Status != "" {
+		line += " " + ui.Badge(ref.Status)
+	}
+	if ref.Title != "" {
+		line += " " + ref.Title
+	}
+	return line
+}
+
+// printIssueFetchWarnings reports issue lookups that failed on stderr.
+func printIssueFetchWarnings(warnings []commandexec.Warning) {
+	for _, warning := range warnings {
+		if warning.Code == codes.WarnIssueFetchFailed {
+			fmt.Fprintf(os.Stderr, "%s\n", ui.Warning(warning.Message))
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var orphansCmd = newCanonicalLeafCommand("orphans", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderOrphans,
+})
+
+var deadlinksCmd = newCanonicalLeafCommand("deadlinks", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderDeadlinks,
+})
+
+func renderOrphans(cmd *cobra.Command, result commandexec.Result) error {
+	if cmd.Flags().Changed("apply") {
+		return renderCanonicalBulkResult(result)
+	}
+	data := canonicalDataMap(result)
+	items, _ := data["items"].([]map[string]interface{})
+	if ShouldUsePipeFormat() {
+		for _, item := range items {
+			fmt.Println(stringValue(item["id"]))
+		}
+		return nil
+	}
+	if len(items) == 0 {
+		fmt.Println(ui.Check("No orphaned objects"))
+		return nil
+	}
+	fmt.Println(ui.SectionHeader(fmt.Sprintf("%d orphaned objects", len(items))))
+	for _, item := range items {
+		fmt.Println(ui.Bullet(fmt.Sprintf("%s %s", stringValue(item["id"]), ui.Muted.Render("("+stringValue(item["type"])+")"))))
+	}
+	fmt.Println()
+	fmt.Println(ui.Hint("Nothing links to these and they link to nothing. Use --apply 'move archive/' to act on them."))
+	return nil
+}
+
+func renderDeadlinks(cmd *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	if cmd.Flags().Changed("apply") {
+		return renderDeadlinksApply(data, result.Warnings)
+	}
+	items, _ := data["items"].([]map[string]interface{})
+	if ShouldUsePipeFormat() {
+		for _, item := range items {
+			fmt.Printf("%s:%d\t%s\n", stringValue(item["file_path"]), intFromAny(item["line"]), stringValue(item["target"]))
+		}
+		return nil
+	}
+	if len(items) == 0 {
+		fmt.Println(ui.Check("No dead links"))
+		return nil
+	}
+	fmt.Println(ui.SectionHeader(fmt.Sprintf("%d dead links", len(items))))
+	for _, item := range items {
+		location := fmt.Sprintf("%s:%d", stringValue(item["file_path"]), intFromAny(item["line"]))
+		fmt.Println(ui.Bullet(fmt.Sprintf("[[%s]] %s", stringValue(item["target"]), ui.Muted.Render(location))))
+	}
+	fmt.Println()
+	fmt.Println(ui.Hint("Use --apply stub to create the missing objects, or --apply unlink to remove the links."))
+	return nil
+}
+
+func renderDeadlinksApply(data map[string]interface{}, warnings []commandexec.Warning) error {
+	preview := boolValue(data["preview"])
+	var lines []string
+	switch stringValue(data["action"]) {
+	case "stub":
+		var stubs []struct {
+			Type     string `json:"type"`
+			FilePath string `json:"file_path"`
+			Links    int    `json:"links"`
+		}
+		if err := decodeResultData(data["stubs"], &stubs); err != nil {
+			return handleError(ErrInternal, err, "")
+		}
+		for _, stub := range stubs {
+			lines = append(lines, fmt.Sprintf("%s %s", stub.FilePath, ui.Muted.Render(fmt.Sprintf("(%s, %d links)", stub.Type, stub.Links))))
+		}
+		if preview {
+			fmt.Println(ui.SectionHeader(fmt.Sprintf("Would create %d stubs", len(lines))))
+		} else {
+			fmt.Println(ui.Checkf("Created %d stubs", len(lines)))
+		}
+	default:
+		var unlinked []struct {
+			FilePath    string `json:"file_path"`
+			Line        int    `json:"line"`
+			Target      string `json:"target"`
+			Replacement string `json:"replacement"`
+		}
+		if err := decodeResultData(data["unlinked"], &unlinked); err != nil {
+			return handleError(ErrInternal, err, "")
+		}
+		for _, item := range unlinked {
+			lines = append(lines, fmt.Sprintf("[[%s]] → %s %s", item.Target, item.Replacement, ui.Muted.Render(fmt.Sprintf("%s:%d", item.FilePath, item.Line))))
+		}
+		if preview {
+			fmt.Println(ui.SectionHeader(fmt.Sprintf("Would unlink %d links", len(lines))))
+		} else {
+			fmt.Println(ui.Checkf("Unlinked %d links", len(lines)))
+		}
+	}
+	for _, line := range lines {
+		fmt.Println(ui.Bullet(line))
+	}
+
+	var skipped []struct {
+		FilePath string `json:"file_path"`
+		Line     int    `json:"line"`
+		Target   string `json:"target"`
+		Reason   string `json:"reason"`
+	}
+	if err := decodeResultData(data["skipped"], &skipped); err != nil {
+		return handleError(ErrInternal, err, "")
+	}
+	if len(skipped) > 0 {
+		fmt.Println()
+		fmt.Println(ui.SectionHeader(fmt.Sprintf("Skipped %d", len(skipped))))
+		for _, item := range skipped {
+			fmt.Println(ui.Bullet(fmt.Sprintf("[[%s]] %s: %s", item.Target, ui.Muted.Render(fmt.Sprintf("%s:%d", item.FilePath, item.Line)), item.Reason)))
+		}
+	}
+	for _, warning := range warnings {
+		if warning.Code == codes.WarnCheckIncomplete {
+			continue // The skipped list above already explains it
+		}
+		fmt.Println(ui.Warning(warning.Message))
+	}
+	if preview && len(lines) > 0 {
+		fmt.Println()
+		fmt.Println(ui.Hint("Run with --confirm to apply changes."))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(orphansCmd)
+	rootCmd.AddCommand(deadlinksCmd)
+}
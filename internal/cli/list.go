@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var listCmd = newCanonicalLeafCommand("list", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	HandleError: handleCanonicalQueryFailure,
+	RenderHuman: renderList,
+})
+
+func renderList(cmd *cobra.Command, result commandexec.Result) error {
+	SetPipeFormat(queryPipeOverride(cmd, nil))
+	printStaleIndexWarning(result.Meta)
+
+	data := canonicalDataMap(result)
+	if rawIDs, ok := data["ids"]; ok {
+		for _, id := range stringSliceFromAny(rawIDs) {
+			fmt.Println(id)
+		}
+		return nil
+	}
+
+	objects := objectResultsFromAny(data["items"])
+	if ShouldUsePipeFormat() {
+		WritePipeableList(os.Stdout, pipeItemsForObjectResults(objects))
+		return nil
+	}
+
+	typeName := stringValue(data["type"])
+	if len(objects) == 0 {
+		if typeName != "" {
+			fmt.Println(ui.Starf("No %s objects found", typeName))
+		} else {
+			fmt.Println(ui.Starf("No objects found"))
+		}
+		return nil
+	}
+
+	full, _ := cmd.Flags().GetBool("full")
+	fields := newFieldDisplay(full)
+	sch, _ := schema.Load(getVaultPath())
+
+	// Table columns come from a single type's fields, so mixed listings are
+	// printed as one table per type, keeping the sorted order within each.
+	for i, group := range groupObjectsByType(objects) {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s %s\n\n", ui.SectionHeader(group[0].Type), ui.Badge(fmt.Sprintf("%d", len(group))))
+		printObjectTable(group, sch, fields)
+	}
+
+	if total := intFromAny(data["total"]); total > len(objects) {
+		fmt.Printf("\n%s\n", ui.Hint(fmt.Sprintf("Showing %d of %d (use --limit and --offset to page)", len(objects), total)))
+	}
+	return nil
+}
+
+// groupObjectsByType splits objects by type in order of first appearance.
+func groupObjectsByType(objects []model.Object) [][]model.Object {
+	index := make(map[string]int)
+	var groups [][]model.Object
+	for _, obj := range objects {
+		i, ok := index[obj.Type]
+		if !ok {
+			i = len(groups)
+			index[obj.Type] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], obj)
+	}
+	return groups
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+}
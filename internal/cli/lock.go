@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var lockCmd = newCanonicalLeafCommand("lock", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderLock,
+})
+
+var unlockCmd = newCanonicalLeafCommand("unlock", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderUnlock,
+})
+
+func renderLock(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	if boolValue(data["changed"]) {
+		fmt.Println(ui.Checkf("Locked %s", ui.FilePath(stringValue(data["file"]))))
+	} else {
+		fmt.Println(ui.Starf("%s is already locked", ui.FilePath(stringValue(data["file"]))))
+	}
+	fmt.Println(ui.Hint("Mutations refuse this file unless run with --unlock."))
+	return nil
+}
+
+func renderUnlock(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	fmt.Println(ui.Checkf("Unlocked %s", ui.FilePath(stringValue(data["file"]))))
+	return nil
+}
+
+func init() {
+	lockCmd.ValidArgsFunction = completeReferenceArgAt(0, referenceCompletionOptions{
+		NonTargetDirective: cobra.ShellCompDirectiveNoFileComp,
+	})
+	unlockCmd.ValidArgsFunction = completeReferenceArgAt(0, referenceCompletionOptions{
+		NonTargetDirective: cobra.ShellCompDirectiveNoFileComp,
+	})
+	rootCmd.AddCommand(lockCmd)
+	rootCmd.AddCommand(unlockCmd)
+}
@@ -22,7 +22,7 @@ var (
 var moveCmd = newCanonicalLeafCommand("move", canonicalLeafOptions{
 	VaultPath:       getVaultPath,
 	Args:            cobra.MaximumNArgs(2),
-	BuildArgs:       buildMoveArgs,
+	BuildArgs:       withUnlockArg(buildMoveArgs),
 	Invoke:          invokeMove,
 	RenderHuman:     renderMoveResult,
 	SkipFlagBinding: true,
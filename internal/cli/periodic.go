@@ -0,0 +1,26 @@
+package cli
+
+import "github.com/spf13/cobra"
+
+var (
+	weeklyCmd    = newPeriodicCommand("weekly")
+	monthlyCmd   = newPeriodicCommand("monthly")
+	quarterlyCmd = newPeriodicCommand("quarterly")
+)
+
+// newPeriodicCommand builds a weekly, monthly, or quarterly note command.
+// Output and editor handling match `rvn daily`.
+func newPeriodicCommand(commandID string) *cobra.Command {
+	return newCanonicalLeafCommand(commandID, canonicalLeafOptions{
+		VaultPath:    getVaultPath,
+		HandleResult: handleDailyResult,
+	})
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{weeklyCmd, monthlyCmd, quarterlyCmd} {
+		// `--edit` is CLI-only, as for `rvn daily`.
+		cmd.Flags().BoolP("edit", "e", false, "Open the note in the configured editor (CLI only)")
+		rootCmd.AddCommand(cmd)
+	}
+}
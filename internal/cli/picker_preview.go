@@ -2,13 +2,13 @@ package cli
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"unicode/utf8"
 
 	"github.com/aidanlsb/raven/internal/picker"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 const previewContextLines = 8
@@ -29,7 +29,7 @@ func vaultFilePreview(vaultPath string) picker.PreviewFunc {
 			return picker.Preview{}, fmt.Errorf("preview path must be vault-relative")
 		}
 
-		content, err := os.ReadFile(filepath.Join(vaultPath, cleanPath))
+		content, err := vaultcrypt.ReadFile(filepath.Join(vaultPath, cleanPath))
 		if err != nil {
 			return picker.Preview{}, err
 		}
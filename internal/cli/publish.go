@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var publishCmd = newCanonicalLeafCommand("publish", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderPublish,
+})
+
+func renderPublish(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	pages := editItems(data["pages"])
+
+	if boolValue(data["dry_run"]) {
+		fmt.Println(ui.SectionHeader(fmt.Sprintf("Would publish %d page(s) to %s", len(pages), ui.FilePath(stringValue(data["output"])))))
+		for _, page := range pages {
+			fmt.Printf("  %s %s\n", stringValue(page["title"]), ui.Muted.Render(stringValue(page["url"])))
+		}
+		return nil
+	}
+
+	fmt.Println(ui.Checkf("Published %d page(s) to %s", len(pages), ui.FilePath(stringValue(data["output"]))))
+	if assets, ok := data["assets"].([]interface{}); ok && len(assets) > 0 {
+		fmt.Println(ui.Hint(fmt.Sprintf("Copied %d linked asset(s)", len(assets))))
+	}
+	if removed := intFromAny(data["removed"]); removed > 0 {
+		fmt.Println(ui.Hint(fmt.Sprintf("Removed %d file(s) no longer published", removed)))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(publishCmd)
+}
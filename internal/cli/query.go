@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,7 +9,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/aidanlsb/raven/internal/app"
 	"github.com/aidanlsb/raven/internal/codes"
 	"github.com/aidanlsb/raven/internal/commandexec"
 	"github.com/aidanlsb/raven/internal/config"
@@ -24,15 +22,21 @@ import (
 )
 
 // printObjectTable prints object results using the shared retrieval table.
-func printObjectTable(results []model.Object, sch *schema.Schema) {
+func printObjectTable(results []model.Object, sch *schema.Schema, fields fieldDisplay) {
 	if len(results) == 0 {
 		return
 	}
 
+	if hasMixedObjectTypes(results) {
+		printMixedObjectTable(results, sch, fields)
+		return
+	}
+
 	nameField, fieldColumns := objectTableColumns(results, sch)
 	display := ui.NewDisplayContext()
 	table := ui.NewResultsTable(display, ui.ObjectLayout(fieldColumns))
 	table.SetHeaders(objectTableHeaders(nameField, fieldColumns))
+	table.SetWrap(fields.full)
 
 	for i, r := range results {
 		cells := make([]string, 0, len(fieldColumns)+3)
@@ -42,7 +46,7 @@ func printObjectTable(results []model.Object, sch *schema.Schema) {
 		)
 
 		for _, col := range fieldColumns {
-			valStr := formatFieldValueSimple(r.Fields[col])
+			valStr := fields.format(r.Type, col, r.Fields[col])
 			if valStr == "" {
 				valStr = "-"
 			}
@@ -62,6 +66,39 @@ func printObjectTable(results []model.Object, sch *schema.Schema) {
 	fmt.Println(table.Render())
 }
 
+// printMixedObjectTable prints results of a cross-type query (type:* or a type
+// union). Field columns differ per type, so rows show the name and type only.
+func printMixedObjectTable(results []model.Object, sch *schema.Schema, fields fieldDisplay) {
+	display := ui.NewDisplayContext()
+	table := ui.NewResultsTable(display, ui.ObjectLayout([]string{"type"}))
+	table.SetHeaders([]string{"#", "name", "type", "location"})
+	table.SetWrap(fields.full)
+
+	for i, r := range results {
+		table.AddRow(ui.ResultRow{
+			Num: i + 1,
+			Cells: []string{
+				ui.FormatRowNum(i+1, len(results)),
+				objectDisplayName(r, sch),
+				r.Type,
+				formatLocationLinkSimpleStyled(r.FilePath, r.LineStart, ui.Muted.Render),
+			},
+			Location: fmt.Sprintf("%s:%d", r.FilePath, r.LineStart),
+		})
+	}
+
+	fmt.Println(table.Render())
+}
+
+func hasMixedObjectTypes(results []model.Object) bool {
+	for _, r := range results[1:] {
+		if r.Type != results[0].Type {
+			return true
+		}
+	}
+	return false
+}
+
 func objectTableColumns(results []model.Object, sch *schema.Schema) (string, []string) {
 	var typeDef *schema.TypeDefinition
 	var fieldColumns []string
@@ -106,6 +143,64 @@ func objectTableName(obj model.Object, nameField string) string {
 	return filepath.Base(obj.ID)
 }
 
+// objectDisplayName returns the name shown for an object in human output: its
+// type's name_field value, falling back to the last segment of its ID.
+func objectDisplayName(obj model.Object, sch *schema.Schema) string {
+	nameField := ""
+	if sch != nil {
+		if typeDef := sch.Types[obj.Type]; typeDef != nil {
+			nameField = typeDef.NameField
+		}
+	}
+	return objectTableName(obj, nameField)
+}
+
+// sortObjectsByDisplayName returns objects ordered by display name using the
+// user's locale collation. Objects with equal names keep their file order.
+func sortObjectsByDisplayName(results []model.Object, sch *schema.Schema) []model.Object {
+	names := make([]string, len(results))
+	order := make([]int, len(results))
+	for i, obj := range results {
+		names[i] = objectDisplayName(obj, sch)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return ui.CompareNames(names[order[i]], names[order[j]]) < 0
+	})
+
+	sorted := make([]model.Object, len(results))
+	for i, idx := range order {
+		sorted[i] = results[idx]
+	}
+	return sorted
+}
+
+// fieldDisplay controls how field values are shortened in human output.
+type fieldDisplay struct {
+	full     bool
+	vaultCfg *config.VaultConfig
+}
+
+// newFieldDisplay loads the vault's display limits. With full set, values
+// are shown untruncated.
+func newFieldDisplay(full bool) fieldDisplay {
+	vaultCfg, err := loadVaultConfigSafe(getVaultPath())
+	if err != nil {
+		vaultCfg = nil
+	}
+	return fieldDisplay{full: full, vaultCfg: vaultCfg}
+}
+
+// format renders a field value for display, truncated to the configured
+// limit for typeName.field unless full output was requested.
+func (d fieldDisplay) format(typeName, field string, val interface{}) string {
+	text := formatFieldValueSimple(val)
+	if d.full {
+		return text
+	}
+	return ui.TruncateField(text, d.vaultCfg.FieldTruncateLimit(typeName, field))
+}
+
 // formatFieldValueSimple formats a field value as a simple string for table display
 func formatFieldValueSimple(val interface{}) string {
 	if val == nil {
@@ -176,6 +271,8 @@ Predicates for type queries:
   refd([[source]])      Referenced by a specific source
   refd(type:...)      Referenced by an item matching nested type query
   refd(trait:...)       Referenced by a trait matching nested trait query
+  refs*([[target]], depth<=3)  Reaches target through a chain of references
+  refd*([[source]], depth<=3)  Reachable from source through a chain of references
   content("term")       Full-text search on item content
 
 Predicates for trait queries:
@@ -188,7 +285,7 @@ Predicates for trait queries:
   at(trait:...)        Co-located with trait matching nested trait query
   refs([[target]])     Line contains reference to target
   refs(type:...)     Line references an item matching nested type query
-  content("term")      Line content contains term
+  content("term")      Full-text search on line content
 
 Predicates for asset queries:
   .extension==pdf       Asset field equals value
@@ -199,10 +296,15 @@ Predicates for asset queries:
   refd(type:...)        Referenced by matching items
   refd(trait:...)       Referenced by matching trait lines
 
+Cross-type object queries:
+  type:*                Objects of every type
+  type:(project|task)   Objects of any listed type
+
 Boolean operators:
-  !pred            NOT
-  pred1 pred2      AND (space-separated)
-  pred1 | pred2    OR
+  !pred            NOT (or NOT pred)
+  pred1 pred2      AND (space-separated, or pred1 AND pred2)
+  pred1 | pred2    OR (or pred1 OR pred2)
+  ( ... )          Grouping
 
 Saved query inputs must be declared with args: in raven.yaml when using {{args.<name>}}.
 You can then pass inputs either by position (following args order) or as key=value pairs.
@@ -210,6 +312,19 @@ You can then pass inputs either by position (following args order) or as key=val
 Use --browse to open an interactive Raven picker with filtering, preview, and
 editor handoff for the selected result.
 
+Use --vaults work,personal to run the query in several vaults from
+config.toml and merge the rows, each tagged with the vault it came from.
+
+Use --watch to keep the query running: changed files are reindexed every
+--interval (default 2s) and added, removed, and changed results are printed.
+With --json, each change is printed as one JSON line.
+
+Use --format csv, tsv, md-table, or yaml to write the results as a table with
+a header row (id plus the --select columns, or every row key and field).
+Use --template to print each result through a Go template instead, e.g.
+--template '{{.id}}: {{.fields.status}}'. Rows have the same keys as --json
+items; join and json are available, and missing values print as empty.
+
 
 Examples:
   rvn query "type:project .status==active"
@@ -220,6 +335,8 @@ Examples:
   rvn query "asset startswith(.media_type, \"image/\")"
   rvn query "trait:todo content(\"my task\")"
   rvn query "trait:highlight in(type:book .status==reading)"
+  rvn query "trait:due .value<today" --watch
+  rvn query --vaults work,personal "trait:due .value==today"
   rvn query tasks                    # Run saved query
   rvn query project-todos raven      # Positional input (args: [project])
   rvn query project-todos project=projects/raven
@@ -231,6 +348,9 @@ Examples:
 		if len(args) == 0 {
 			return handleErrorMsg(ErrMissingArgument, "specify a query string", "Run 'rvn query saved list' to see saved queries")
 		}
+		if queryVaultsRequested(cmd) {
+			return runFederatedQuery(cmd, args)
+		}
 
 		// Load vault config for saved queries and unknown-query suggestions.
 		vaultCfg, err := config.LoadVaultConfig(vaultPath)
@@ -263,6 +383,7 @@ Examples:
 		}
 
 		refresh := queryBoolFlagValue(cmd, "refresh", savedBoolOption(savedOptions, "refresh"))
+		requireFresh, _ := cmd.Flags().GetBool("require-fresh")
 		idsOnly := queryBoolFlagValue(cmd, "ids", savedBoolOption(savedOptions, "ids"))
 		limit := queryIntFlagValue(cmd, "limit", savedIntOption(savedOptions, "limit"))
 		offset := queryIntFlagValue(cmd, "offset", savedIntOption(savedOptions, "offset"))
@@ -276,7 +397,35 @@ Examples:
 
 		applyArgs := queryStringArrayFlagValue(cmd, "apply", savedApplyOption(savedOptions))
 		confirmApply := queryBoolFlagValue(cmd, "confirm", savedBoolOption(savedOptions, "confirm"))
+		unlock, _ := cmd.Flags().GetBool("unlock")
+		merge, _ := cmd.Flags().GetBool("merge")
 		browse := queryBoolFlagValue(cmd, "browse", savedBoolOption(savedOptions, "browse"))
+		full := queryBoolFlagValue(cmd, "full", savedBoolOption(savedOptions, "full"))
+		selectColumns, _ := cmd.Flags().GetString("select")
+		explainMatches, _ := cmd.Flags().GetBool("explain-matches")
+		format, _ := cmd.Flags().GetString("format")
+		rowTemplate, _ := cmd.Flags().GetString("template")
+		if format != "" || rowTemplate != "" {
+			outputFlag := "--format"
+			if rowTemplate != "" {
+				outputFlag = "--template"
+			}
+			if format != "" && rowTemplate != "" {
+				return handleErrorMsg(ErrInvalidInput, "--format cannot be used with --template", "Use one of --format or --template")
+			}
+			if format != "" && !isQueryExportFormat(format) {
+				return handleErrorMsg(ErrInvalidInput, fmt.Sprintf("unknown --format %q", format), "Use one of: "+strings.Join(queryExportFormats, ", "))
+			}
+			if rowTemplate != "" {
+				if _, err := parseQueryRowTemplate(rowTemplate); err != nil {
+					return handleErrorMsg(ErrInvalidInput, fmt.Sprintf("invalid --template: %v", err), "Use Go template syntax, e.g. '{{.id}}: {{.fields.status}}'")
+				}
+			}
+			if isJSONOutput() || idsOnly || countOnly || len(applyArgs) > 0 || explainMatches || cmd.Flags().Changed("browse") || cmd.Flags().Changed("watch") {
+				return handleErrorMsg(ErrInvalidInput, outputFlag+" cannot be used with --json, --ids, --count-only, --apply, --explain-matches, --browse, or --watch", "Use "+outputFlag+" on its own, optionally with --select, --limit, or --offset")
+			}
+			browse = false
+		}
 		if isJSONOutput() && browse && !cmd.Flags().Changed("browse") {
 			// JSON is an explicit machine-readable mode; let it suppress saved
 			// interactive defaults so saved queries remain agent/script-friendly.
@@ -289,21 +438,35 @@ Examples:
 			if handled, err := validateInteractiveBrowse(true); handled || err != nil {
 				return err
 			}
-			if idsOnly || countOnly || len(applyArgs) > 0 {
-				return handleErrorMsg(ErrInvalidInput, "--browse cannot be used with --ids, --count-only, or --apply", "Run the query without browse for machine-readable or bulk modes")
+			if idsOnly || countOnly || len(applyArgs) > 0 || selectColumns != "" {
+				return handleErrorMsg(ErrInvalidInput, "--browse cannot be used with --ids, --count-only, --apply, or --select", "Run the query without browse for machine-readable or bulk modes")
 			}
 			if pipeOverride != nil && *pipeOverride {
 				return handleErrorMsg(ErrInvalidInput, "--browse cannot be used with --pipe", "Use --no-pipe or remove --browse")
 			}
 		}
 
+		watch, _ := cmd.Flags().GetBool("watch")
+		watchInterval, _ := cmd.Flags().GetDuration("interval")
+		if watch {
+			if browse || idsOnly || countOnly || len(applyArgs) > 0 {
+				return handleErrorMsg(ErrInvalidInput, "--watch cannot be used with --browse, --ids, --count-only, or --apply", "Run the query without watch for interactive or bulk modes")
+			}
+			if watchInterval <= 0 {
+				return handleErrorMsg(ErrInvalidInput, "--interval must be greater than zero", "Use e.g. --interval 5s")
+			}
+		}
+
 		// If --apply is set, route through the canonical query handler.
 		if len(applyArgs) > 0 {
 			return runCanonicalQuery(queryStr, map[string]interface{}{
-				"query_string": joinQueryArgs(args),
-				"refresh":      refresh,
-				"apply":        applyArgs,
-				"confirm":      confirmApply,
+				"query_string":  joinQueryArgs(args),
+				"refresh":       refresh,
+				"require-fresh": requireFresh,
+				"apply":         applyArgs,
+				"confirm":       confirmApply,
+				"unlock":        unlock,
+				"merge":         merge,
 			})
 		}
 
@@ -330,23 +493,52 @@ Examples:
 				suggestion)
 		}
 
-		return runCanonicalQuery(queryStr, map[string]interface{}{
-			"query_string": joinQueryArgs(args),
-			"refresh":      refresh,
-			"ids":          idsOnly,
-			"limit":        limit,
-			"offset":       offset,
-			"count-only":   countOnly,
-			"browse":       browse,
-		})
+		queryArgs := map[string]interface{}{
+			"query_string":    joinQueryArgs(args),
+			"refresh":         refresh,
+			"require-fresh":   requireFresh,
+			"ids":             idsOnly,
+			"limit":           limit,
+			"offset":          offset,
+			"count-only":      countOnly,
+			"browse":          browse,
+			"full":            full,
+			"select":          selectColumns,
+			"explain-matches": explainMatches,
+		}
+		if format != "" {
+			queryArgs["format"] = format
+		}
+		if rowTemplate != "" {
+			queryArgs["template"] = rowTemplate
+		}
+		if watch {
+			return runQueryWatch(queryStr, queryArgs, watchInterval)
+		}
+		return runCanonicalQuery(queryStr, queryArgs)
 	},
 }
 
 func runCanonicalQuery(queryStr string, args map[string]interface{}) error {
-	result := executeCanonicalQuery(args)
+	// --format and --template only change how the CLI renders rows; the
+	// query command itself does not take them.
+	execArgs := args
+	if _, ok := args["format"]; ok {
+		execArgs = copyArgsMap(args)
+		delete(execArgs, "format")
+	}
+	if _, ok := args["template"]; ok {
+		execArgs = copyArgsMap(execArgs)
+		delete(execArgs, "template")
+	}
+	result := executeCanonicalQuery(execArgs)
 	if hasQueryApply(args) {
 		return renderCanonicalQueryApplyResult(args, result)
 	}
+	return renderCanonicalQueryResult(queryStr, args, result)
+}
+
+func renderCanonicalQueryResult(queryStr string, args map[string]interface{}, result commandexec.Result) error {
 	if !result.OK {
 		if isJSONOutput() {
 			outputJSON(result)
@@ -362,6 +554,9 @@ func runCanonicalQuery(queryStr string, args map[string]interface{}) error {
 		outputJSON(result)
 		return nil
 	}
+	printStaleIndexWarning(result.Meta)
+	printDegradedSearchWarnings(result.Warnings)
+	printIssueFetchWarnings(result.Warnings)
 
 	data, _ := result.Data.(map[string]interface{})
 	if rawQueries, ok := data["queries"]; ok {
@@ -384,18 +579,45 @@ func runCanonicalQuery(queryStr string, args map[string]interface{}) error {
 		return nil
 	}
 
+	if format, _ := args["format"].(string); format != "" {
+		if err := writeQueryExport(os.Stdout, format, itemMapsFromAny(data["items"]), stringSliceFromAny(data["select"])); err != nil {
+			return handleError(ErrInternal, err, "")
+		}
+		return nil
+	}
+	if rowTemplate, _ := args["template"].(string); rowTemplate != "" {
+		if err := writeQueryTemplate(os.Stdout, rowTemplate, itemMapsFromAny(data["items"])); err != nil {
+			return handleErrorMsg(ErrInvalidInput, fmt.Sprintf("--template failed: %v", err), "")
+		}
+		return nil
+	}
+
 	queryKind, _ := data["query_kind"].(string)
 	browse := boolValue(args["browse"])
+	fields := newFieldDisplay(boolValue(args["full"]))
+	if boolValue(args["explain-matches"]) && !browse && !ShouldUsePipeFormat() {
+		printQueryMatchExplanations(queryStr, queryLabelFromData(data, queryStr), itemMapsFromAny(data["items"]))
+		return nil
+	}
+	if _, federated := data["vaults"]; federated && len(stringSliceFromAny(data["select"])) == 0 {
+		printFederatedQueryResults(queryStr, queryLabelFromData(data, queryStr), stringValue(data["query_kind"]), itemMapsFromAny(data["items"]), fields.full)
+		return nil
+	}
+	if columns := stringSliceFromAny(data["select"]); len(columns) > 0 && !ShouldUsePipeFormat() {
+		printSelectedQueryResults(queryStr, queryLabelFromData(data, queryStr), columns, itemMapsFromAny(data["items"]), fields.full)
+		return nil
+	}
 	switch queryKind {
 	case "type", "object":
 		objects := objectResultsFromAny(data["items"])
 		if browse {
 			if len(objects) == 0 {
 				sch, _ := schema.Load(getVaultPath())
-				printQueryObjectResults(queryStr, queryLabelFromData(data, queryStr), objects, sch)
+				printQueryObjectResults(queryStr, queryLabelFromData(data, queryStr), objects, sch, fields)
 				return nil
 			}
 			sch, _ := schema.Load(getVaultPath())
+			objects = sortObjectsByDisplayName(objects, sch)
 			return browseQueryResults(browseItemsForObjectResults(objects, sch), objectBrowseHeaders(objects, sch), objectBrowseLayout(objects, sch))
 		}
 		if ShouldUsePipeFormat() {
@@ -403,13 +625,13 @@ func runCanonicalQuery(queryStr string, args map[string]interface{}) error {
 			return nil
 		}
 		sch, _ := schema.Load(getVaultPath())
-		printQueryObjectResults(queryStr, queryLabelFromData(data, queryStr), objects, sch)
+		printQueryObjectResults(queryStr, queryLabelFromData(data, queryStr), objects, sch, fields)
 		return nil
 	case "trait":
 		traits := traitResultsFromAny(data["items"])
 		if browse {
 			if len(traits) == 0 {
-				printQueryTraitResults(queryStr, queryLabelFromData(data, queryStr), traits)
+				printQueryTraitResults(queryStr, queryLabelFromData(data, queryStr), traits, fields.full)
 				return nil
 			}
 			return browseQueryResults(browseItemsForTraitResults(traits), traitBrowseHeaders(), ui.TraitLayout())
@@ -418,7 +640,7 @@ func runCanonicalQuery(queryStr string, args map[string]interface{}) error {
 			WritePipeableList(os.Stdout, pipeItemsForTraitResults(traits))
 			return nil
 		}
-		printQueryTraitResults(queryStr, queryLabelFromData(data, queryStr), traits)
+		printQueryTraitResults(queryStr, queryLabelFromData(data, queryStr), traits, fields.full)
 		return nil
 	case "asset":
 		assets := assetResultsFromAny(data["items"])
@@ -709,6 +931,8 @@ func savedBoolOption(options *config.QueryOptions, name string) *bool {
 		return options.Confirm
 	case "browse":
 		return options.Browse
+	case "full":
+		return options.Full
 	default:
 		return nil
 	}
@@ -736,12 +960,7 @@ func savedApplyOption(options *config.QueryOptions) []string {
 }
 
 func executeCanonicalQuery(args map[string]interface{}) commandexec.Result {
-	return app.CommandInvoker().Execute(context.Background(), commandexec.Request{
-		CommandID: "query",
-		VaultPath: getVaultPath(),
-		Caller:    commandexec.CallerCLI,
-		Args:      args,
-	})
+	return executeCanonicalCommand("query", getVaultPath(), args)
 }
 
 func renderCanonicalQueryApplyResult(args map[string]interface{}, result commandexec.Result) error {
@@ -847,6 +1066,23 @@ func savedQueriesFromResult(raw interface{}) []SavedQueryInfo {
 	return queries
 }
 
+func itemMapsFromAny(raw interface{}) []map[string]interface{} {
+	if rows, ok := raw.([]map[string]interface{}); ok {
+		return rows
+	}
+	rows, ok := raw.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if entry, ok := row.(map[string]interface{}); ok {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
 func objectResultsFromAny(raw interface{}) []model.Object {
 	if rows, ok := raw.([]map[string]interface{}); ok {
 		results := make([]model.Object, 0, len(rows))
@@ -1132,17 +1368,33 @@ var querySavedRemoveCmd = newCanonicalLeafCommand("query_saved_remove", canonica
 	RenderHuman: renderQuerySavedRemove,
 })
 
+var querySnapshotCmd = newCanonicalLeafCommand("query_snapshot", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	HandleError: handleCanonicalQueryFailure,
+	RenderHuman: renderQuerySnapshot,
+})
+
+var queryDiffCmd = newCanonicalLeafCommand("query_diff", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	HandleError: handleCanonicalQueryFailure,
+	RenderHuman: renderQueryDiff,
+})
+
 func buildQuerySavedSetArgs(cmd *cobra.Command, args []string) (map[string]interface{}, error) {
 	declaredArgs, err := normalizeSavedQueryArgsForCommand(cmd)
 	if err != nil {
 		return nil, err
 	}
 	description, _ := cmd.Flags().GetString("description")
+	snapshotTarget, _ := cmd.Flags().GetString("snapshot-target")
+	snapshotSchedule, _ := cmd.Flags().GetString("snapshot-schedule")
 	argsMap := map[string]interface{}{
-		"name":         args[0],
-		"query_string": args[1],
-		"arg":          declaredArgs,
-		"description":  description,
+		"name":              args[0],
+		"query_string":      args[1],
+		"arg":               declaredArgs,
+		"description":       description,
+		"snapshot-target":   snapshotTarget,
+		"snapshot-schedule": snapshotSchedule,
 	}
 	addSavedQueryOptionArgs(cmd, argsMap)
 	return argsMap, nil
@@ -1190,6 +1442,10 @@ func addSavedQueryOptionArgs(cmd *cobra.Command, argsMap map[string]interface{})
 		value, _ := cmd.Flags().GetBool("browse")
 		argsMap["browse"] = value
 	}
+	if cmd.Flags().Changed("full") {
+		value, _ := cmd.Flags().GetBool("full")
+		argsMap["full"] = value
+	}
 }
 
 func savedQueryOptionsFromFlags(cmd *cobra.Command) *config.QueryOptions {
@@ -1236,6 +1492,10 @@ func savedQueryOptionsFromFlags(cmd *cobra.Command) *config.QueryOptions {
 		value, _ := cmd.Flags().GetBool("browse")
 		options.Browse = &value
 	}
+	if cmd.Flags().Changed("full") {
+		value, _ := cmd.Flags().GetBool("full")
+		options.Full = &value
+	}
 	if options.IsEmpty() {
 		return nil
 	}
@@ -1265,6 +1525,13 @@ func renderQuerySavedGet(_ *cobra.Command, result commandexec.Result) error {
 	if description := stringValue(data["description"]); description != "" {
 		fmt.Printf("%s %s\n", ui.Hint("Description:"), description)
 	}
+	if snapshot, ok := data["snapshot"].(*config.QuerySnapshot); ok && snapshot != nil {
+		schedule := snapshot.Schedule
+		if schedule == "" {
+			schedule = "manual"
+		}
+		fmt.Printf("%s %s (%s)\n", ui.Hint("Snapshot:"), snapshot.Target, schedule)
+	}
 	return nil
 }
 
@@ -1289,6 +1556,61 @@ func renderQuerySavedRemove(_ *cobra.Command, result commandexec.Result) error {
 	return nil
 }
 
+func renderQuerySnapshot(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	items, _ := data["snapshots"].([]map[string]interface{})
+	if len(items) == 0 {
+		fmt.Println(ui.Starf("No saved queries declare a snapshot"))
+		fmt.Println(ui.Hint("Add one with 'rvn query saved set <name> <query> --snapshot-target <note>'"))
+		return nil
+	}
+	for _, item := range items {
+		name := stringValue(item["name"])
+		switch stringValue(item["status"]) {
+		case "updated":
+			fmt.Println(ui.Checkf("Updated '%s' in %s (%d)", name, ui.FilePath(stringValue(item["file"])), intValue(item["count"])))
+		case "skipped":
+			fmt.Println(ui.Starf("Skipped '%s' (not due)", name))
+		default:
+			fmt.Println(ui.Warningf("Failed '%s': %s", name, stringValue(item["error"])))
+		}
+	}
+	return nil
+}
+
+func renderQueryDiff(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	onlyA := itemMapsFromAny(data["only_a"])
+	onlyB := itemMapsFromAny(data["only_b"])
+	common := itemMapsFromAny(data["common"])
+	printStaleIndexWarning(result.Meta)
+
+	if len(onlyA) == 0 && len(onlyB) == 0 {
+		fmt.Println(ui.Checkf("Both queries return the same results %s", ui.Hint(fmt.Sprintf("(%d)", len(common)))))
+		return nil
+	}
+	fmt.Printf("%s %s %s\n", ui.SectionHeader("Only in A"), ui.Hint(queryDiffLabel(data["a"])), ui.Badge(fmt.Sprintf("%d", len(onlyA))))
+	for _, item := range onlyA {
+		fmt.Printf("  %s %s\n", ui.Danger.Render("-"), queryWatchLabel(item))
+	}
+	fmt.Printf("%s %s %s\n", ui.SectionHeader("Only in B"), ui.Hint(queryDiffLabel(data["b"])), ui.Badge(fmt.Sprintf("%d", len(onlyB))))
+	for _, item := range onlyB {
+		fmt.Printf("  %s %s\n", ui.Success.Render("+"), queryWatchLabel(item))
+	}
+	fmt.Println(ui.Hint(fmt.Sprintf("%d in both", len(common))))
+	return nil
+}
+
+// queryDiffLabel names one side of a query diff: the saved query name when
+// there is one, otherwise the query string.
+func queryDiffLabel(raw interface{}) string {
+	side, _ := raw.(map[string]interface{})
+	if name := stringValue(side["saved_query"]); name != "" {
+		return name
+	}
+	return stringValue(side["query"])
+}
+
 // joinQueryArgs joins command-line arguments into a single query string.
 func joinQueryArgs(args []string) string {
 	if len(args) == 1 {
@@ -1350,6 +1672,35 @@ func normalizeSavedQueryArgsForCommand(cmd *cobra.Command) ([]string, error) {
 	return normalized, nil
 }
 
+// printStaleIndexWarning tells human readers on stderr that results came from
+// an index that no longer matches the vault files.
+func printStaleIndexWarning(meta *commandexec.Meta) {
+	if meta == nil || meta.Freshness == nil {
+		return
+	}
+	if meta.Freshness.Unknown {
+		fmt.Fprintf(os.Stderr, "%s\n", ui.Warning("Could not check whether the index is up to date, so results may be outdated (use --require-fresh or run 'rvn reindex')"))
+		return
+	}
+	if !meta.Freshness.Stale {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s\n", ui.Warning(fmt.Sprintf(
+		"Index is stale: %d file(s) changed since the last reindex, so results may be outdated (use --require-fresh or run 'rvn reindex')",
+		meta.Freshness.StaleCount,
+	)))
+}
+
+// printDegradedSearchWarnings reports search features running on LIKE-based
+// fallbacks because the SQLite build lacks FTS5 or REGEXP.
+func printDegradedSearchWarnings(warnings []commandexec.Warning) {
+	for _, warning := range warnings {
+		if warning.Code == codes.WarnDegradedSearch {
+			fmt.Fprintf(os.Stderr, "%s\n", ui.Warning(warning.Message))
+		}
+	}
+}
+
 func mapQueryCode(code codes.ErrorCode) codes.ErrorCode {
 	switch code {
 	case codes.ErrMissingArgument:
@@ -1362,6 +1713,8 @@ func mapQueryCode(code codes.ErrorCode) codes.ErrorCode {
 		return ErrQueryNotFound
 	case codes.ErrDatabaseVersion:
 		return ErrDatabaseVersion
+	case codes.ErrIndexStale:
+		return ErrIndexStale
 	case codes.ErrConfigInvalid:
 		return ErrConfigInvalid
 	case codes.ErrDatabase:
@@ -1395,20 +1748,33 @@ func mapQuerySvcError(err error) error {
 
 func init() {
 	queryCmd.Flags().Bool("refresh", false, "Refresh stale files before query")
+	queryCmd.Flags().Bool("require-fresh", false, "Reindex first if the index is stale; fail if it cannot be brought up to date")
 	queryCmd.Flags().Bool("ids", false, "Output only object/trait IDs, one per line (for piping)")
 	queryCmd.Flags().Int("limit", 0, "Maximum number of query results to return (0 means no limit)")
 	queryCmd.Flags().Int("offset", 0, "Zero-based offset for query results")
 	queryCmd.Flags().Bool("count-only", false, "Return only the total count of matches (no items or IDs)")
 	queryCmd.Flags().StringArray("apply", nil, "Apply a bulk operation to query results (format: command args...)")
 	queryCmd.Flags().Bool("confirm", false, "Apply changes (without this flag, shows preview only)")
+	queryCmd.Flags().Bool("unlock", false, "Allow --apply to modify files listed in locked_files")
+	queryCmd.Flags().Bool("merge", false, "Three-way merge the planned --apply edit into files changed since the preview")
+	queryCmd.Flags().String("vaults", "", "Comma-separated configured vault names to query together; each row gets a vault column")
 	queryCmd.Flags().Bool("pipe", false, "Force pipe-friendly output for shell pipelines (jq, head, sort)")
 	queryCmd.Flags().Bool("no-pipe", false, "Force human-readable output format")
 	queryCmd.Flags().Bool("browse", false, "Interactively browse query results in Raven's picker and open the selected result")
+	queryCmd.Flags().Bool("full", false, "Show full field values and content instead of truncating them")
+	queryCmd.Flags().String("select", "", "Comma-separated output columns (e.g. '.name, .status, backlinks, issues')")
+	queryCmd.Flags().Bool("explain-matches", false, "Annotate each row with the predicates that matched and the matched values")
+	queryCmd.Flags().String("format", "", "Write results as a table: csv, tsv, md-table, or yaml")
+	queryCmd.Flags().String("template", "", "Write each result through a Go template (e.g. '{{.id}}: {{.fields.status}}')")
+	queryCmd.Flags().Bool("watch", false, "Re-run the query as files change and print added, removed, and changed results")
+	queryCmd.Flags().Duration("interval", defaultQueryWatchInterval, "How often --watch checks for changes")
 
 	querySavedCmd.AddCommand(querySavedListCmd)
 	querySavedCmd.AddCommand(querySavedGetCmd)
 	querySavedCmd.AddCommand(querySavedSetCmd)
 	querySavedCmd.AddCommand(querySavedRemoveCmd)
 	queryCmd.AddCommand(querySavedCmd)
+	queryCmd.AddCommand(querySnapshotCmd)
+	queryCmd.AddCommand(queryDiffCmd)
 	rootCmd.AddCommand(queryCmd)
 }
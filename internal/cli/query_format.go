@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// queryExportFormats are the values accepted by rvn query --format.
+var queryExportFormats = []string{"csv", "tsv", "md-table", "yaml"}
+
+func isQueryExportFormat(format string) bool {
+	for _, candidate := range queryExportFormats {
+		if format == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// queryExportColumn is one output column: a row key such as id or line, or
+// an object field read from the row's fields map.
+type queryExportColumn struct {
+	Name  string
+	Field bool
+}
+
+// queryExportLeadingKeys and queryExportTrailingKeys fix where well-known row
+// keys appear; object fields and any other keys go in between.
+var (
+	queryExportLeadingKeys  = []string{"id", "type", "trait_type", "value", "content", "object_id", "title", "level"}
+	queryExportTrailingKeys = []string{"file_path", "line", "line_start"}
+)
+
+// queryExportColumns picks the columns for rows. With --select the columns
+// are id followed by the selected ones; otherwise every row key except num,
+// with object fields flattened into one column each.
+func queryExportColumns(rows []map[string]interface{}, selected []string) []queryExportColumn {
+	if len(selected) > 0 {
+		columns := []queryExportColumn{{Name: "id"}}
+		for _, name := range selected {
+			columns = append(columns, queryExportColumn{Name: name})
+		}
+		return columns
+	}
+	if len(rows) == 0 {
+		return []queryExportColumn{{Name: "id"}}
+	}
+
+	keys := make(map[string]bool)
+	fields := make(map[string]bool)
+	for _, row := range rows {
+		for key, value := range row {
+			switch key {
+			case "num", "matches":
+			case "fields":
+				for field := range mapValue(value) {
+					fields[field] = true
+				}
+			default:
+				keys[key] = true
+			}
+		}
+	}
+
+	var columns []queryExportColumn
+	for _, key := range queryExportLeadingKeys {
+		if keys[key] {
+			columns = append(columns, queryExportColumn{Name: key})
+			delete(keys, key)
+		}
+	}
+	for _, field := range sortedKeys(fields) {
+		columns = append(columns, queryExportColumn{Name: field, Field: true})
+	}
+	for _, key := range queryExportTrailingKeys {
+		delete(keys, key)
+	}
+	for _, key := range sortedKeys(keys) {
+		columns = append(columns, queryExportColumn{Name: key})
+	}
+	for _, key := range queryExportTrailingKeys {
+		if rowsHaveKey(rows, key) {
+			columns = append(columns, queryExportColumn{Name: key})
+		}
+	}
+	return columns
+}
+
+func rowsHaveKey(rows []map[string]interface{}, key string) bool {
+	for _, row := range rows {
+		if _, ok := row[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (c queryExportColumn) value(row map[string]interface{}) interface{} {
+	if c.Field {
+		return mapValue(row["fields"])[c.Name]
+	}
+	return row[c.Name]
+}
+
+// writeQueryExport writes rows in format, one line or record per row under a
+// header naming the columns. Values are written in full, never truncated.
+func writeQueryExport(w io.Writer, format string, rows []map[string]interface{}, selected []string) error {
+	rows = normalizeQueryExportRows(rows)
+	columns := queryExportColumns(rows, selected)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+
+	switch format {
+	case "csv":
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := writer.Write(queryExportCells(row, columns)); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case "tsv":
+		lines := make([]string, 0, len(rows)+1)
+		lines = append(lines, strings.Join(header, "\t"))
+		for _, row := range rows {
+			cells := queryExportCells(row, columns)
+			for i, cell := range cells {
+				cells[i] = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ").Replace(cell)
+			}
+			lines = append(lines, strings.Join(cells, "\t"))
+		}
+		_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+		return err
+	case "md-table":
+		escape := strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+		separator := make([]string, len(header))
+		for i := range separator {
+			separator[i] = "---"
+		}
+		lines := []string{"| " + strings.Join(header, " | ") + " |", "| " + strings.Join(separator, " | ") + " |"}
+		for _, row := range rows {
+			cells := queryExportCells(row, columns)
+			for i, cell := range cells {
+				cells[i] = escape.Replace(cell)
+			}
+			lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		}
+		_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+		return err
+	case "yaml":
+		doc := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, row := range rows {
+			item := &yaml.Node{Kind: yaml.MappingNode}
+			for _, column := range columns {
+				value := &yaml.Node{}
+				if err := value.Encode(column.value(row)); err != nil {
+					return err
+				}
+				item.Content = append(item.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: column.Name}, value)
+			}
+			doc.Content = append(doc.Content, item)
+		}
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// normalizeQueryExportRows round-trips rows through JSON so typed values
+// (issue refs, time values, ints) are written the same way --json shows them.
+func normalizeQueryExportRows(rows []map[string]interface{}) []map[string]interface{} {
+	normalized := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		data, err := json.Marshal(row)
+		if err != nil {
+			normalized = append(normalized, row)
+			continue
+		}
+		var out map[string]interface{}
+		if err := json.Unmarshal(data, &out); err != nil {
+			normalized = append(normalized, row)
+			continue
+		}
+		normalized = append(normalized, out)
+	}
+	return normalized
+}
+
+func queryExportCells(row map[string]interface{}, columns []queryExportColumn) []string {
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		if !column.Field && column.Name == "issues" {
+			cells[i] = formatIssueRefsInline(issueRefsFromAny(row[column.Name]))
+			continue
+		}
+		cells[i] = queryExportCell(column.value(row))
+	}
+	return cells
+}
+
+// queryExportCell renders one value as cell text: lists are comma-joined and
+// objects are written as JSON.
+func queryExportCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = queryExportCell(item)
+		}
+		return strings.Join(parts, ", ")
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+}
+
+// queryRowTemplateFuncs are the helpers available to --template.
+var queryRowTemplateFuncs = template.FuncMap{
+	"join": func(value interface{}, sep string) string {
+		items, ok := value.([]interface{})
+		if !ok {
+			return queryExportCell(value)
+		}
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = queryExportCell(item)
+		}
+		return strings.Join(parts, sep)
+	},
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+func parseQueryRowTemplate(text string) (*template.Template, error) {
+	return template.New("row").Funcs(queryRowTemplateFuncs).Parse(text)
+}
+
+// writeQueryTemplate executes text once per row, each followed by a newline.
+// Rows have the same shape as --json items. Missing keys print as empty
+// rather than text/template's "<no value>".
+func writeQueryTemplate(w io.Writer, text string, rows []map[string]interface{}) error {
+	tmpl, err := parseQueryRowTemplate(text)
+	if err != nil {
+		return err
+	}
+	for _, row := range normalizeQueryExportRows(rows) {
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, row); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w, strings.ReplaceAll(buf.String(), "<no value>", "")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteQueryExportFlattensFieldsAndEscapesCells(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"num": 1, "id": "project/raven", "type": "project", "file_path": "projects/raven.md", "line": 1,
+			"fields": map[string]interface{}{"status": "active", "tags": []interface{}{"core", "cli"}}},
+		{"num": 2, "id": "project/wren", "type": "project", "file_path": "projects/wren.md", "line": 1,
+			"fields": map[string]interface{}{"name": "Wren | bird, \"v2\"", "budget": 1200.5}},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "csv",
+			want: "id,type,budget,name,status,tags,file_path,line\n" +
+				"project/raven,project,,,active,\"core, cli\",projects/raven.md,1\n" +
+				"project/wren,project,1200.5,\"Wren | bird, \"\"v2\"\"\",,,projects/wren.md,1\n",
+		},
+		{
+			format: "md-table",
+			want: "| id | type | budget | name | status | tags | file_path | line |\n" +
+				"| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+				"| project/raven | project |  |  | active | core, cli | projects/raven.md | 1 |\n" +
+				"| project/wren | project | 1200.5 | Wren \\| bird, \"v2\" |  |  | projects/wren.md | 1 |\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeQueryExport(&out, tt.format, rows, nil); err != nil {
+				t.Fatalf("writeQueryExport: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Fatalf("output = %q\nwant %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteQueryExportKeepsSelectedColumnOrder(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"num": 1, "id": "project/raven", "status": "active", "backlinks": 3},
+		{"num": 2, "id": "project/wren", "backlinks": 0},
+	}
+
+	var out bytes.Buffer
+	if err := writeQueryExport(&out, "yaml", rows, []string{"status", "backlinks"}); err != nil {
+		t.Fatalf("writeQueryExport: %v", err)
+	}
+	want := "- id: project/raven\n" +
+		"  status: active\n" +
+		"  backlinks: 3\n" +
+		"- id: project/wren\n" +
+		"  status: null\n" +
+		"  backlinks: 0\n"
+	if out.String() != want {
+		t.Fatalf("output = %q\nwant %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := writeQueryExport(&out, "tsv", rows, []string{"status", "backlinks"}); err != nil {
+		t.Fatalf("writeQueryExport: %v", err)
+	}
+	if want := "id\tstatus\tbacklinks\nproject/raven\tactive\t3\nproject/wren\t\t0\n"; out.String() != want {
+		t.Fatalf("tsv = %q\nwant %q", out.String(), want)
+	}
+}
+
+func TestWriteQueryTemplateRendersEachRow(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": "project/raven", "fields": map[string]interface{}{"status": "active", "tags": []interface{}{"core", "cli"}}},
+		{"id": "project/wren", "fields": map[string]interface{}{}},
+	}
+
+	var out bytes.Buffer
+	if err := writeQueryTemplate(&out, `{{.id}}: {{.fields.status}} {{join .fields.tags "+"}}`, rows); err != nil {
+		t.Fatalf("writeQueryTemplate: %v", err)
+	}
+	if want := "project/raven: active core+cli\nproject/wren:  \n"; out.String() != want {
+		t.Fatalf("output = %q, want %q", out.String(), want)
+	}
+
+	if err := writeQueryTemplate(&out, "{{.id", rows); err == nil {
+		t.Fatal("expected a parse error for an unclosed action")
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/aidanlsb/raven/internal/config"
 	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/schema"
 )
 
 func TestJoinQueryArgs(t *testing.T) {
@@ -215,3 +217,28 @@ func newQueryOptionsTestCommand() *cobra.Command {
 	cmd.Flags().Bool("browse", false, "")
 	return cmd
 }
+
+func TestSortObjectsByDisplayName(t *testing.T) {
+	sch := &schema.Schema{Types: map[string]*schema.TypeDefinition{
+		"person": {NameField: "name"},
+	}}
+	objects := []model.Object{
+		{ID: "people/zed", Type: "person", Fields: map[string]interface{}{"name": "Zoë Park"}},
+		{ID: "people/bob", Type: "person", Fields: map[string]interface{}{"name": "bob"}},
+		{ID: "people/emile", Type: "person", Fields: map[string]interface{}{"name": "Émile"}},
+		{ID: "people/anon", Type: "person", Fields: map[string]interface{}{}},
+	}
+
+	sorted := sortObjectsByDisplayName(objects, sch)
+	got := make([]string, 0, len(sorted))
+	for _, obj := range sorted {
+		got = append(got, objectDisplayName(obj, sch))
+	}
+	want := []string{"anon", "bob", "Émile", "Zoë Park"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("sorted names = %v, want %v", got, want)
+	}
+	if objects[0].ID != "people/zed" {
+		t.Fatalf("input slice should not be reordered")
+	}
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// queryVaultsRequested reports whether a query runs across named vaults, in
+// which case no single vault is resolved for it.
+func queryVaultsRequested(cmd *cobra.Command) bool {
+	if cmd == nil || cmd.Flags().Lookup("vaults") == nil {
+		return false
+	}
+	vaults, _ := cmd.Flags().GetString("vaults")
+	return strings.TrimSpace(vaults) != ""
+}
+
+// runFederatedQuery runs `rvn query --vaults a,b` through the canonical query
+// command, which merges the per-vault rows.
+func runFederatedQuery(cmd *cobra.Command, args []string) error {
+	for _, flag := range []string{"browse", "watch", "apply", "ids"} {
+		if cmd.Flags().Changed(flag) {
+			return handleErrorMsg(ErrInvalidInput, fmt.Sprintf("--%s cannot be used with --vaults", flag), "Run the query against one vault with --vault <name>")
+		}
+	}
+	format, _ := cmd.Flags().GetString("format")
+	rowTemplate, _ := cmd.Flags().GetString("template")
+	if format != "" && rowTemplate != "" {
+		return handleErrorMsg(ErrInvalidInput, "--format cannot be used with --template", "Use one of --format or --template")
+	}
+	if format != "" && !isQueryExportFormat(format) {
+		return handleErrorMsg(ErrInvalidInput, fmt.Sprintf("unknown --format %q", format), "Use one of: "+strings.Join(queryExportFormats, ", "))
+	}
+	if rowTemplate != "" {
+		if _, err := parseQueryRowTemplate(rowTemplate); err != nil {
+			return handleErrorMsg(ErrInvalidInput, fmt.Sprintf("invalid --template: %v", err), "Use Go template syntax, e.g. '{{.id}}: {{.fields.status}}'")
+		}
+	}
+
+	vaults, _ := cmd.Flags().GetString("vaults")
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	requireFresh, _ := cmd.Flags().GetBool("require-fresh")
+	limit, _ := cmd.Flags().GetInt("limit")
+	offset, _ := cmd.Flags().GetInt("offset")
+	countOnly, _ := cmd.Flags().GetBool("count-only")
+	full, _ := cmd.Flags().GetBool("full")
+	selectColumns, _ := cmd.Flags().GetString("select")
+	explainMatches, _ := cmd.Flags().GetBool("explain-matches")
+	SetPipeFormat(queryPipeOverride(cmd, nil))
+
+	queryStr := joinQueryArgs(args)
+	queryArgs := map[string]interface{}{
+		"query_string":    queryStr,
+		"vaults":          vaults,
+		"refresh":         refresh,
+		"require-fresh":   requireFresh,
+		"limit":           limit,
+		"offset":          offset,
+		"count-only":      countOnly,
+		"full":            full,
+		"select":          selectColumns,
+		"explain-matches": explainMatches,
+	}
+	if format != "" {
+		queryArgs["format"] = format
+	}
+	if rowTemplate != "" {
+		queryArgs["template"] = rowTemplate
+	}
+	return runCanonicalQuery(queryStr, queryArgs)
+}
+
+// federatedQueryColumns are the human table columns for cross-vault rows,
+// led by the vault each row came from.
+func federatedQueryColumns(queryKind string) []string {
+	switch queryKind {
+	case "trait":
+		return []string{"vault", "value", "content"}
+	case "asset":
+		return []string{"vault", "file_path", "media_type"}
+	case "section":
+		return []string{"vault", "title", "file_path"}
+	default:
+		return []string{"vault", "type", "file_path"}
+	}
+}
+
+// printFederatedQueryResults prints cross-vault rows as a table, or as
+// vault/id pairs when piped.
+func printFederatedQueryResults(queryStr, label, queryKind string, rows []map[string]interface{}, full bool) {
+	if ShouldUsePipeFormat() {
+		for _, row := range rows {
+			fmt.Printf("%s\t%s\n", stringValue(row["vault"]), stringValue(row["id"]))
+		}
+		return
+	}
+	for _, row := range rows {
+		// Trait values come through as *string; nil is a bare trait.
+		if value, ok := row["value"].(*string); ok {
+			if value != nil {
+				row["value"] = *value
+			} else {
+				row["value"] = nil
+			}
+		}
+	}
+	printSelectedQueryResults(queryStr, label, federatedQueryColumns(queryKind), rows, full)
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+const defaultQueryWatchInterval = 2 * time.Second
+
+// queryWatchDiff is how a query's results changed between two runs. Items are
+// matched by id.
+type queryWatchDiff struct {
+	Added   []map[string]interface{} `json:"added"`
+	Removed []map[string]interface{} `json:"removed"`
+	Changed []map[string]interface{} `json:"changed"`
+}
+
+func (d queryWatchDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// diffQueryItems compares two result sets. Row numbers are ignored so that an
+// item moving position does not count as a change.
+func diffQueryItems(before, after []map[string]interface{}) queryWatchDiff {
+	diff := queryWatchDiff{
+		Added:   []map[string]interface{}{},
+		Removed: []map[string]interface{}{},
+		Changed: []map[string]interface{}{},
+	}
+	previous := make(map[string]map[string]interface{}, len(before))
+	for _, item := range before {
+		previous[stringValue(item["id"])] = item
+	}
+	current := make(map[string]bool, len(after))
+	for _, item := range after {
+		id := stringValue(item["id"])
+		current[id] = true
+		old, ok := previous[id]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, item)
+		case !reflect.DeepEqual(withoutRowNumber(old), withoutRowNumber(item)):
+			diff.Changed = append(diff.Changed, item)
+		}
+	}
+	for _, item := range before {
+		if !current[stringValue(item["id"])] {
+			diff.Removed = append(diff.Removed, item)
+		}
+	}
+	return diff
+}
+
+func withoutRowNumber(item map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(item))
+	for key, value := range item {
+		if key != "num" {
+			out[key] = value
+		}
+	}
+	return out
+}
+
+// runQueryWatch prints the query results, then re-runs the query every
+// interval and prints what was added, removed, or changed. Each run reindexes
+// changed files first, so edits show up without a separate reindex. It runs
+// until interrupted.
+//
+// With --json, every run that changes the results prints one compact JSON
+// line; the first line lists all results as added.
+func runQueryWatch(queryStr string, args map[string]interface{}, interval time.Duration) error {
+	args = copyArgsMap(args)
+	args["refresh"] = true
+
+	result := executeCanonicalQuery(args)
+	if !result.OK {
+		return renderCanonicalQueryResult(queryStr, args, result)
+	}
+	items := queryWatchItems(result)
+	if isJSONOutput() {
+		printQueryWatchJSON(diffQueryItems(nil, items))
+	} else {
+		if err := renderCanonicalQueryResult(queryStr, args, result); err != nil {
+			return err
+		}
+		fmt.Println()
+		fmt.Println(ui.Hint(fmt.Sprintf("Watching for changes every %s (Ctrl+C to stop)", interval)))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		result := executeCanonicalQuery(args)
+		if !result.OK {
+			// A file caught mid-edit can fail to parse; keep watching and
+			// pick up the next good state.
+			if result.Error != nil && !isJSONOutput() {
+				fmt.Fprintln(os.Stderr, ui.Warning(result.Error.Message))
+			}
+			continue
+		}
+		next := queryWatchItems(result)
+		diff := diffQueryItems(items, next)
+		items = next
+		if diff.empty() {
+			continue
+		}
+		if isJSONOutput() {
+			printQueryWatchJSON(diff)
+		} else {
+			printQueryWatchDiff(diff, len(next))
+		}
+	}
+}
+
+func queryWatchItems(result commandexec.Result) []map[string]interface{} {
+	data, _ := result.Data.(map[string]interface{})
+	return itemMapsFromAny(data["items"])
+}
+
+func printQueryWatchJSON(diff queryWatchDiff) {
+	line, err := json.Marshal(commandexec.Success(diff, nil))
+	if err != nil {
+		return
+	}
+	fmt.Println(string(line))
+}
+
+func printQueryWatchDiff(diff queryWatchDiff, total int) {
+	var parts []string
+	if n := len(diff.Added); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d added", n))
+	}
+	if n := len(diff.Removed); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d removed", n))
+	}
+	if n := len(diff.Changed); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d changed", n))
+	}
+	fmt.Println()
+	fmt.Printf("%s %s %s\n", ui.Muted.Render(time.Now().Format("15:04:05")), strings.Join(parts, ", "), ui.Badge(fmt.Sprintf("%d", total)))
+	for _, item := range diff.Added {
+		fmt.Printf("  %s %s\n", ui.Success.Render("+"), queryWatchLabel(item))
+	}
+	for _, item := range diff.Removed {
+		fmt.Printf("  %s %s\n", ui.Danger.Render("-"), queryWatchLabel(item))
+	}
+	for _, item := range diff.Changed {
+		fmt.Printf("  %s %s\n", ui.Warn.Render("~"), queryWatchLabel(item))
+	}
+}
+
+// queryWatchLabel names an item in a diff line: its id, followed by the
+// trait content or section title when there is one.
+func queryWatchLabel(item map[string]interface{}) string {
+	label := stringValue(item["id"])
+	for _, key := range []string{"content", "title"} {
+		if text := stringValue(item[key]); text != "" {
+			return label + "  " + ui.Muted.Render(ui.TruncateWithEllipsis(text, 60))
+		}
+	}
+	return label
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffQueryItems(t *testing.T) {
+	before := []map[string]interface{}{
+		{"num": 1, "id": "tasks/a", "fields": map[string]interface{}{"status": "open"}},
+		{"num": 2, "id": "tasks/b", "fields": map[string]interface{}{"status": "open"}},
+		{"num": 3, "id": "tasks/c", "fields": map[string]interface{}{"status": "open"}},
+	}
+	after := []map[string]interface{}{
+		{"num": 1, "id": "tasks/b", "fields": map[string]interface{}{"status": "open"}},
+		{"num": 2, "id": "tasks/c", "fields": map[string]interface{}{"status": "blocked"}},
+		{"num": 3, "id": "tasks/d", "fields": map[string]interface{}{"status": "open"}},
+	}
+
+	diff := diffQueryItems(before, after)
+	ids := func(items []map[string]interface{}) []string {
+		out := []string{}
+		for _, item := range items {
+			out = append(out, stringValue(item["id"]))
+		}
+		return out
+	}
+	if got := ids(diff.Added); !reflect.DeepEqual(got, []string{"tasks/d"}) {
+		t.Errorf("added = %v, want [tasks/d]", got)
+	}
+	if got := ids(diff.Removed); !reflect.DeepEqual(got, []string{"tasks/a"}) {
+		t.Errorf("removed = %v, want [tasks/a]", got)
+	}
+	if got := ids(diff.Changed); !reflect.DeepEqual(got, []string{"tasks/c"}) {
+		t.Errorf("changed = %v, want [tasks/c] (renumbered tasks/b is unchanged)", got)
+	}
+
+	if !diffQueryItems(after, after).empty() {
+		t.Error("diff of identical results should be empty")
+	}
+	if got := ids(diffQueryItems(nil, after).Added); len(got) != 3 {
+		t.Errorf("initial diff added = %v, want all results", got)
+	}
+}
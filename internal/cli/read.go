@@ -2,12 +2,14 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/aidanlsb/raven/internal/codes"
 	"github.com/aidanlsb/raven/internal/commandexec"
 	"github.com/aidanlsb/raven/internal/readsvc"
+	"github.com/aidanlsb/raven/internal/ui"
 )
 
 var readCmd = newCanonicalLeafCommand("read", canonicalLeafOptions{
@@ -57,6 +59,8 @@ func buildReadArgs(cmd *cobra.Command, args []string) (map[string]interface{}, e
 	lines, _ := cmd.Flags().GetBool("lines")
 	startLine, _ := cmd.Flags().GetInt("start-line")
 	endLine, _ := cmd.Flags().GetInt("end-line")
+	sections, _ := cmd.Flags().GetBool("sections")
+	render, _ := cmd.Flags().GetBool("render")
 	if lines || startLine > 0 || endLine > 0 {
 		raw = true
 	}
@@ -66,6 +70,8 @@ func buildReadArgs(cmd *cobra.Command, args []string) (map[string]interface{}, e
 		"lines":      lines,
 		"start-line": startLine,
 		"end-line":   endLine,
+		"sections":   sections,
+		"render":     render,
 	}, nil
 }
 
@@ -95,13 +101,20 @@ func renderRead(cmd *cobra.Command, result commandexec.Result) error {
 	endLine, _ := cmd.Flags().GetInt("end-line")
 	rawMode := raw || lines || startLine > 0 || endLine > 0
 
+	full, _ := cmd.Flags().GetBool("full")
+
 	data := canonicalDataMap(result)
+	if sections, ok := data["sections"].([]readsvc.ReadSection); ok {
+		renderReadOutline(data, sections)
+		return nil
+	}
 	if rawMode {
 		content, _ := data["content"].(string)
 		fmt.Print(content)
 		return nil
 	}
 
+	printIssueFetchWarnings(result.Warnings)
 	return readEnriched(readEnrichedOptions{
 		fileRelPath:    stringFromMap(data, "path"),
 		content:        stringFromMap(data, "content"),
@@ -110,9 +123,41 @@ func renderRead(cmd *cobra.Command, result commandexec.Result) error {
 		references:     readReferencesFromMap(data["references"]),
 		backlinks:      readBacklinksFromMap(data["backlinks"]),
 		backlinksCount: metaCount(result.Meta),
+		issues:         issueRefsFromAny(data["issues"]),
+		fields:         newFieldDisplay(full),
 	})
 }
 
+func renderReadOutline(data map[string]interface{}, sections []readsvc.ReadSection) {
+	header := ui.FilePath(stringFromMap(data, "path"))
+	if objectType := stringFromMap(data, "type"); objectType != "" {
+		header += " " + ui.Hint("("+objectType+")")
+	}
+	fmt.Println(header)
+
+	traits, _ := data["traits"].([]readsvc.ReadTrait)
+	printOutlineTraits(traits, 2)
+	for _, section := range sections {
+		indent := 2 * (section.Level - 1)
+		lineRange := fmt.Sprintf("L%d-%d", section.LineStart, section.SubtreeLineEnd)
+		fmt.Println(ui.Indent(indent, fmt.Sprintf("%s %s  %s  %s", strings.Repeat("#", section.Level), section.Title, ui.Hint(lineRange), ui.Hint(section.ID))))
+		printOutlineTraits(section.Traits, indent+2)
+	}
+	if len(sections) == 0 {
+		fmt.Println(ui.Hint("No headings"))
+	}
+}
+
+func printOutlineTraits(traits []readsvc.ReadTrait, indent int) {
+	for _, trait := range traits {
+		line := fmt.Sprintf("%s  %s", ui.Trait(trait.Trait, trait.Value), ui.Hint(fmt.Sprintf("L%d", trait.Line)))
+		if trait.Content != "" {
+			line += "  " + trait.Content
+		}
+		fmt.Println(ui.Indent(indent, line))
+	}
+}
+
 func init() {
 	readCmd.ValidArgsFunction = completeReferenceArgAt(0, referenceCompletionOptions{
 		IncludeDynamicDates: true,
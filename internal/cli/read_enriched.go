@@ -2,9 +2,12 @@ package cli
 
 import (
 	"fmt"
-	"path/filepath"
+	"regexp"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/model"
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/readsvc"
 	"github.com/aidanlsb/raven/internal/ui"
@@ -18,6 +21,10 @@ type readEnrichedOptions struct {
 	references     []readsvc.ReadReference
 	backlinks      []readsvc.ReadBacklinkGroup
 	backlinksCount int
+	// issues lists issue tracker references (nil when issue_refs is off).
+	issues []model.IssueRef
+	// fields controls truncation of long frontmatter values.
+	fields fieldDisplay
 }
 
 const readRenderMargin = ui.MarkdownRenderMargin
@@ -29,13 +36,17 @@ func readEnriched(opts readEnrichedOptions) error {
 	processedBody := body
 
 	if isJSONOutput() {
-		outputSuccess(map[string]interface{}{
+		data := map[string]interface{}{
 			"path":       opts.fileRelPath,
 			"content":    opts.content,
 			"line_count": opts.lineCount,
 			"references": opts.references,
 			"backlinks":  opts.backlinks,
-		}, &Meta{QueryTimeMs: opts.elapsedMs, Count: opts.backlinksCount})
+		}
+		if opts.issues != nil {
+			data["issues"] = opts.issues
+		}
+		outputSuccess(data, &Meta{QueryTimeMs: opts.elapsedMs, Count: opts.backlinksCount})
 		return nil
 	}
 
@@ -62,9 +73,12 @@ func readEnriched(opts readEnrichedOptions) error {
 	fmt.Println(marginPrefix + ui.DividerWithAccentLabel(opts.fileRelPath, width))
 	fmt.Println()
 
-	// Print frontmatter as-is (raw YAML)
+	// Print frontmatter as raw YAML, with long values shortened unless --full.
 	if frontmatter != "" {
 		renderedFrontmatter := frontmatter
+		if !opts.fields.full {
+			renderedFrontmatter = truncateFrontmatterForDisplay(frontmatter, opts.fields.vaultCfg)
+		}
 		if display.IsTTY {
 			renderedFrontmatter = ui.Muted.Render(frontmatter)
 		}
@@ -81,6 +95,15 @@ func readEnriched(opts readEnrichedOptions) error {
 		fmt.Println()
 	}
 
+	if len(opts.issues) > 0 {
+		fmt.Println()
+		fmt.Println(marginPrefix + ui.DividerWithAccentLabel(fmt.Sprintf("Issues (%d)", len(opts.issues)), width))
+		fmt.Println()
+		for _, issue := range opts.issues {
+			fmt.Println(marginPrefix + ui.Bullet(formatIssueRefLine(issue)))
+		}
+	}
+
 	fmt.Println()
 	fmt.Println(marginPrefix + ui.DividerWithAccentLabel(fmt.Sprintf("Backlinks (%d)", opts.backlinksCount), width))
 	fmt.Println()
@@ -120,6 +143,71 @@ func splitFrontmatterBody(content string) (frontmatter string, body string) {
 	return frontmatter, body
 }
 
+var frontmatterTopLevelFieldRe = regexp.MustCompile(`^([A-Za-z0-9_][A-Za-z0-9_.-]*):(?:\s+(.*))?$`)
+
+// truncateFrontmatterForDisplay shortens long top-level frontmatter values
+// using the vault's display limits. Block scalars (| and >) that exceed their
+// limit are folded onto a single truncated line.
+func truncateFrontmatterForDisplay(frontmatter string, vaultCfg *config.VaultConfig) string {
+	lines := strings.Split(strings.TrimSuffix(frontmatter, "\n"), "\n")
+
+	typeName := ""
+	for _, line := range lines {
+		if m := frontmatterTopLevelFieldRe.FindStringSubmatch(line); m != nil && m[1] == "type" {
+			typeName = strings.Trim(strings.TrimSpace(m[2]), `"'`)
+			break
+		}
+	}
+
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		m := frontmatterTopLevelFieldRe.FindStringSubmatch(line)
+		if m == nil {
+			out = append(out, line)
+			continue
+		}
+		key, value := m[1], strings.TrimSpace(m[2])
+		limit := vaultCfg.FieldTruncateLimit(typeName, key)
+		if limit <= 0 {
+			out = append(out, line)
+			continue
+		}
+
+		if isBlockScalarIndicator(value) {
+			end := i + 1
+			for end < len(lines) && lines[end] != "---" &&
+				(strings.TrimSpace(lines[end]) == "" || strings.HasPrefix(lines[end], " ") || strings.HasPrefix(lines[end], "\t")) {
+				end++
+			}
+			text := strings.Join(lines[i+1:end], " ")
+			if utf8.RuneCountInString(ui.TruncateField(text, 0)) > limit {
+				out = append(out, key+": "+ui.TruncateField(text, limit))
+				i = end - 1
+				continue
+			}
+			out = append(out, line)
+			continue
+		}
+
+		if utf8.RuneCountInString(value) > limit {
+			out = append(out, key+": "+ui.TruncateField(value, limit))
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n") + "\n"
+}
+
+func isBlockScalarIndicator(value string) bool {
+	switch value {
+	case "|", "|-", "|+", ">", ">-", ">+":
+		return true
+	default:
+		return false
+	}
+}
+
 func renderTraitsStyled(content string) string {
 	return ui.HighlightTraits(content)
 }
@@ -138,16 +226,3 @@ func indentBlock(content string, spaces int) string {
 	}
 	return strings.Join(parts, "")
 }
-
-func formatFileLink(relPath string) string {
-	if !shouldEmitHyperlinks() {
-		return ui.FilePath(relPath)
-	}
-	vaultPath := getVaultPath()
-	if vaultPath == "" {
-		return ui.FilePath(relPath)
-	}
-	abs := filepath.Join(vaultPath, relPath)
-	url := buildEditorURL(getConfig(), abs, 1)
-	return ui.FilePath(fmt.Sprintf("\x1b]8;;%s\x07%s\x1b]8;;\x07", url, relPath))
-}
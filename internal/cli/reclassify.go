@@ -24,7 +24,7 @@ var (
 
 var reclassifyCmd = newCanonicalLeafCommand("reclassify", canonicalLeafOptions{
 	VaultPath:       getVaultPath,
-	BuildArgs:       buildReclassifyArgs,
+	BuildArgs:       withUnlockArg(buildReclassifyArgs),
 	Invoke:          invokeReclassify,
 	RenderHuman:     renderReclassifyResult,
 	SkipFlagBinding: true,
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var redirectsCmd = &cobra.Command{
+	Use:   "redirects",
+	Short: "Manage redirects from moved or renamed object IDs",
+	Long: `rvn move and rvn rename record old object IDs in redirects.yaml so
+references held outside the vault keep resolving.
+
+  rvn redirects list
+  rvn redirects prune --confirm`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var redirectsListCmd = newCanonicalLeafCommand("redirects_list", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderRedirectsList,
+})
+
+var redirectsPruneCmd = newCanonicalLeafCommand("redirects_prune", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderRedirectsPrune,
+})
+
+func renderRedirectsList(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	items, _ := data["items"].([]map[string]interface{})
+	if len(items) == 0 {
+		fmt.Println(ui.Hint("No redirects. They are recorded when objects are moved or renamed."))
+		return nil
+	}
+	for _, item := range items {
+		fmt.Println(ui.Bullet(redirectLine(item)))
+	}
+	return nil
+}
+
+func renderRedirectsPrune(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	removed, _ := data["removed"].([]map[string]interface{})
+	if len(removed) == 0 {
+		fmt.Println(ui.Check("No stale redirects"))
+		return nil
+	}
+	if boolValue(data["preview"]) {
+		fmt.Println(ui.SectionHeader(fmt.Sprintf("Would remove %d redirects", len(removed))))
+	} else {
+		fmt.Println(ui.Checkf("Removed %d redirects", len(removed)))
+	}
+	for _, item := range removed {
+		fmt.Println(ui.Bullet(redirectLine(item)))
+	}
+	if boolValue(data["preview"]) {
+		fmt.Println()
+		fmt.Println(ui.Hint("Run with --confirm to remove them"))
+	}
+	return nil
+}
+
+func redirectLine(item map[string]interface{}) string {
+	from, _ := item["from"].(string)
+	to, _ := item["to"].(string)
+	line := fmt.Sprintf("%s → %s", from, to)
+	if status, _ := item["status"].(string); status != "ok" {
+		line += " " + ui.Warning(status)
+	}
+	return line
+}
+
+func init() {
+	redirectsCmd.AddCommand(redirectsListCmd)
+	redirectsCmd.AddCommand(redirectsPruneCmd)
+	rootCmd.AddCommand(redirectsCmd)
+}
@@ -93,10 +93,14 @@ func handleReindexResult(cmd *cobra.Command, result commandexec.Result) error {
 	}
 	fmt.Printf("  %s objects\n", ui.Bold.Render(fmt.Sprintf("%d", intFromMap(data, "objects"))))
 	fmt.Printf("  %s traits\n", ui.Bold.Render(fmt.Sprintf("%d", intFromMap(data, "traits"))))
-	if intFromMap(data, "refs_unresolved") > 0 {
+	if intFromMap(data, "refs_unresolved") > 0 || intFromMap(data, "refs_external") > 0 {
+		counts := fmt.Sprintf("%d resolved, %d unresolved", intFromMap(data, "refs_resolved"), intFromMap(data, "refs_unresolved"))
+		if external := intFromMap(data, "refs_external"); external > 0 {
+			counts += fmt.Sprintf(", %d external", external)
+		}
 		fmt.Printf("  %s references %s\n",
 			ui.Bold.Render(fmt.Sprintf("%d", intFromMap(data, "references"))),
-			ui.Hint(fmt.Sprintf("(%d resolved, %d unresolved)", intFromMap(data, "refs_resolved"), intFromMap(data, "refs_unresolved"))))
+			ui.Hint("("+counts+")"))
 	} else {
 		fmt.Printf("  %s references\n", ui.Bold.Render(fmt.Sprintf("%d", intFromMap(data, "references"))))
 	}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/objectsvc"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var renameCmd = newCanonicalLeafCommand("rename", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderRenameResult,
+})
+
+func init() {
+	renameCmd.ValidArgsFunction = completeReferenceArgAt(0, referenceCompletionOptions{
+		IncludeDynamicDates: false,
+		NonTargetDirective:  cobra.ShellCompDirectiveNoFileComp,
+	})
+	rootCmd.AddCommand(renameCmd)
+}
+
+func renderRenameResult(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	oldID := stringValue(data["old_id"])
+	newID := stringValue(data["new_id"])
+	changes, err := decodeSchemaValue[[]objectsvc.RenameChange](data["changes"])
+	if err != nil {
+		return err
+	}
+	for _, warning := range result.Warnings {
+		fmt.Println(ui.Warning(warning.Message))
+	}
+
+	if boolValue(data["preview"]) {
+		fmt.Printf("%s\n\n", ui.SectionHeader(fmt.Sprintf("Preview: Rename '%s' to '%s'", oldID, newID)))
+		fmt.Printf("%s\n", ui.Hint(fmt.Sprintf("Changes to be made (%d total):", len(changes))))
+		printRenameChanges(changes)
+		fmt.Printf("\n%s\n", ui.Hint("Run with --confirm to apply these changes."))
+		return nil
+	}
+
+	changesApplied, err := decodeSchemaCount(data["changes_applied"])
+	if err != nil {
+		return err
+	}
+	fmt.Println(ui.Checkf("Renamed %s → %s", ui.FilePath(oldID), ui.FilePath(newID)))
+	fmt.Printf("  %s\n", ui.Hint(fmt.Sprintf("Applied %d changes", changesApplied)))
+	return nil
+}
+
+func printRenameChanges(changes []objectsvc.RenameChange) {
+	byFile := make(map[string][]objectsvc.RenameChange)
+	for _, change := range changes {
+		byFile[change.FilePath] = append(byFile[change.FilePath], change)
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		fmt.Printf("\n  %s:\n", ui.FilePath(file))
+		for _, change := range byFile[file] {
+			fmt.Printf("    %s\n", change.Description)
+		}
+	}
+}
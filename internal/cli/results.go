@@ -143,11 +143,13 @@ type TypeSchema struct {
 	Name            string                 `json:"name"`
 	Builtin         bool                   `json:"builtin"`
 	DefaultPath     string                 `json:"default_path,omitempty"`
+	Extends         string                 `json:"extends,omitempty"`
 	Description     string                 `json:"description,omitempty"`
 	NameField       string                 `json:"name_field,omitempty"`
 	Template        string                 `json:"template,omitempty"`
 	Templates       []string               `json:"templates,omitempty"`
 	DefaultTemplate string                 `json:"default_template,omitempty"`
+	RequiredTraits  []string               `json:"required_traits,omitempty"`
 	Fields          map[string]FieldSchema `json:"fields,omitempty"`
 }
 
@@ -167,12 +169,17 @@ type TemplateSchema struct {
 
 // FieldSchema represents a field definition.
 type FieldSchema struct {
-	Type        string   `json:"type"`
-	Required    bool     `json:"required"`
-	Default     string   `json:"default,omitempty"`
-	Values      []string `json:"values,omitempty"`
-	Target      string   `json:"target,omitempty"`
-	Description string   `json:"description,omitempty"`
+	Type          string   `json:"type"`
+	Required      bool     `json:"required"`
+	Default       string   `json:"default,omitempty"`
+	Values        []string `json:"values,omitempty"`
+	Target        string   `json:"target,omitempty"`
+	Description   string   `json:"description,omitempty"`
+	Pattern       string   `json:"pattern,omitempty"`
+	Min           string   `json:"min,omitempty"`
+	Max           string   `json:"max,omitempty"`
+	Unique        bool     `json:"unique,omitempty"`
+	InheritedFrom string   `json:"inherited_from,omitempty"`
 }
 
 // TraitSchema represents a trait definition.
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var resumeCmd = newCanonicalLeafCommand("resume", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderResume,
+})
+
+func renderResume(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+
+	if operations, ok := data["operations"]; ok {
+		var items []struct {
+			ID        string `json:"id"`
+			Command   string `json:"command"`
+			Pending   int    `json:"pending"`
+			Completed int    `json:"completed"`
+			UpdatedAt string `json:"updated_at"`
+		}
+		_ = decodeResultData(operations, &items)
+		if len(items) == 0 {
+			fmt.Println(ui.Star("No resumable operations."))
+			return nil
+		}
+		fmt.Println(ui.SectionHeader("Resumable operations"))
+		for _, item := range items {
+			fmt.Println(ui.Bullet(fmt.Sprintf("%s %s %s",
+				ui.Bold.Render(item.ID),
+				item.Command,
+				ui.Hint(fmt.Sprintf("(%d pending, %d done, updated %s)", item.Pending, item.Completed, item.UpdatedAt)))))
+		}
+		return nil
+	}
+
+	if boolValue(data["preview"]) {
+		var operation struct {
+			ID        string `json:"id"`
+			Command   string `json:"command"`
+			Pending   int    `json:"pending"`
+			Completed int    `json:"completed"`
+		}
+		_ = decodeResultData(data["operation"], &operation)
+		fmt.Println(ui.SectionHeader(fmt.Sprintf("Operation %s (%s)", operation.ID, operation.Command)))
+		fmt.Println(ui.Bullet(fmt.Sprintf("%d item(s) pending, %d already applied", operation.Pending, operation.Completed)))
+		fmt.Printf("\n%s\n", ui.Hint("Run with --confirm to apply the remaining items."))
+		return nil
+	}
+
+	return renderCanonicalBulkResult(result)
+}
+
+func init() {
+	rootCmd.AddCommand(resumeCmd)
+}
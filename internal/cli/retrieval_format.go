@@ -1,35 +1,112 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
 
 	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/query"
 	"github.com/aidanlsb/raven/internal/schema"
 	"github.com/aidanlsb/raven/internal/ui"
 )
 
-func printQueryObjectResults(queryStr, typeName string, results []model.Object, sch *schema.Schema) {
+func printQueryObjectResults(queryStr, typeName string, results []model.Object, sch *schema.Schema, fields fieldDisplay) {
 	if len(results) == 0 {
 		fmt.Println(ui.Starf("No objects found for: %s", queryStr))
 		return
 	}
 
 	fmt.Printf("%s %s\n\n", ui.SectionHeader(typeName), ui.Badge(fmt.Sprintf("%d", len(results))))
-	printObjectTable(results, sch)
+	printObjectTable(sortObjectsByDisplayName(results, sch), sch, fields)
 }
 
-func printQueryTraitResults(queryStr, traitName string, results []model.Trait) {
+// printSelectedQueryResults prints rows projected with --select, showing only
+// the id and the requested columns.
+func printSelectedQueryResults(queryStr, label string, columns []string, rows []map[string]interface{}, full bool) {
+	if len(rows) == 0 {
+		fmt.Println(ui.Starf("No results found for: %s", queryStr))
+		return
+	}
+
+	fmt.Printf("%s %s\n\n", ui.SectionHeader(label), ui.Badge(fmt.Sprintf("%d", len(rows))))
+
+	display := ui.NewDisplayContext()
+	table := ui.NewResultsTable(display, ui.SelectLayout(columns))
+	table.SetHeaders(append([]string{"#", "id"}, columns...))
+	table.SetWrap(full)
+
+	for i, row := range rows {
+		cells := make([]string, 0, len(columns)+2)
+		cells = append(cells, ui.FormatRowNum(i+1, len(rows)), stringValue(row["id"]))
+		for _, column := range columns {
+			var valStr string
+			if column == "issues" {
+				valStr = formatIssueRefsInline(issueRefsFromAny(row[column]))
+			} else {
+				valStr = formatFieldValueSimple(row[column])
+			}
+			if valStr == "" {
+				valStr = "-"
+			}
+			cells = append(cells, valStr)
+		}
+		table.AddRow(ui.ResultRow{Num: i + 1, Cells: cells})
+	}
+
+	fmt.Println(table.Render())
+}
+
+// printQueryMatchExplanations lists each row with the predicates that matched
+// (--explain-matches), nesting OR alternatives under their predicate.
+func printQueryMatchExplanations(queryStr, label string, rows []map[string]interface{}) {
+	if len(rows) == 0 {
+		fmt.Println(ui.Starf("No results found for: %s", queryStr))
+		return
+	}
+
+	fmt.Printf("%s %s\n\n", ui.SectionHeader(label), ui.Badge(fmt.Sprintf("%d", len(rows))))
+	for i, row := range rows {
+		fmt.Printf("%s %s\n", ui.FormatRowNum(i+1, len(rows)), ui.Bold.Render(stringValue(row["id"])))
+		var matches []query.Match
+		if data, err := json.Marshal(row["matches"]); err == nil {
+			_ = json.Unmarshal(data, &matches)
+		}
+		printQueryMatches(matches, 1)
+		fmt.Println()
+	}
+}
+
+func printQueryMatches(matches []query.Match, depth int) {
+	for _, match := range matches {
+		line := ui.Error(match.Predicate)
+		if match.Matched {
+			line = ui.Check(match.Predicate)
+		}
+		if len(match.Values) > 0 {
+			line += "  " + ui.Muted.Render(strings.Join(match.Values, "; "))
+		}
+		fmt.Println(ui.Indent(depth*2, line))
+		printQueryMatches(match.Alternatives, depth+1)
+	}
+}
+
+func printQueryTraitResults(queryStr, traitName string, results []model.Trait, full bool) {
 	if len(results) == 0 {
 		fmt.Println(ui.Starf("No traits found for: %s", queryStr))
 		return
 	}
 
 	fmt.Printf("%s %s\n\n", ui.SectionHeader("@"+traitName), ui.Badge(fmt.Sprintf("%d", len(results))))
+	printTraitTable(results, full)
+}
 
+// printTraitTable prints trait results using the shared retrieval table.
+func printTraitTable(results []model.Trait, full bool) {
 	display := ui.NewDisplayContext()
 	table := ui.NewResultsTable(display, ui.TraitLayout())
+	table.SetWrap(full)
 
 	// Get the calculated content width for dynamic content sizing
 	// Use 2x width to allow for two-line content
@@ -50,7 +127,7 @@ func printQueryTraitResults(queryStr, traitName string, results []model.Trait) {
 
 		truncated := false
 		// Truncate content to fit two lines if needed
-		if len(content) > maxContentLen {
+		if !full && len(content) > maxContentLen {
 			if snippetHasCodeBlock(content) {
 				maxCodeLen := maxContentLen * 3
 				if len(content) > maxCodeLen {
@@ -87,7 +164,11 @@ func printQueryAssetResults(queryStr string, results []model.Asset) {
 	}
 
 	fmt.Printf("%s %s\n\n", ui.SectionHeader("asset"), ui.Badge(fmt.Sprintf("%d", len(results))))
+	printAssetTable(results)
+}
 
+// printAssetTable prints asset results using the shared retrieval table.
+func printAssetTable(results []model.Asset) {
 	display := ui.NewDisplayContext()
 	table := ui.NewResultsTable(display, ui.AssetLayout())
 
@@ -101,7 +182,7 @@ func printQueryAssetResults(queryStr string, results []model.Asset) {
 			Num: i + 1,
 			Cells: []string{
 				ui.FormatRowNum(i+1, len(results)),
-				ui.TruncateWithEllipsis(r.FilePath, table.GetColumnWidth(1)),
+				formatTruncatedFileLink(r.FilePath, table.GetColumnWidth(1)),
 				ui.TruncateWithEllipsis(mediaType, table.GetColumnWidth(2)),
 				size,
 			},
@@ -119,7 +200,11 @@ func printQuerySectionResults(queryStr string, results []model.Section) {
 	}
 
 	fmt.Printf("%s %s\n\n", ui.SectionHeader("section"), ui.Badge(fmt.Sprintf("%d", len(results))))
+	printSectionTable(results)
+}
 
+// printSectionTable prints section results using the shared retrieval table.
+func printSectionTable(results []model.Section) {
 	display := ui.NewDisplayContext()
 	table := ui.NewResultsTable(display, ui.SearchLayout())
 
@@ -488,18 +573,74 @@ func isEscapedBacktick(s string, idx int) bool {
 }
 
 func printBacklinksResults(target string, links []model.Reference) {
-	printReferenceResults(
-		"Backlinks to "+target,
-		fmt.Sprintf("No backlinks found for '%s'", target),
-		links,
-		func(link model.Reference) string {
-			displayText := link.SourceID
+	if len(links) == 0 {
+		fmt.Println(ui.Star(fmt.Sprintf("No backlinks found for '%s'", target)))
+		return
+	}
+
+	fmt.Printf("%s %s\n\n", ui.SectionHeader("Backlinks to "+target), ui.Badge(fmt.Sprintf("%d", len(links))))
+	printBacklinksTable(links)
+}
+
+// printBacklinksByType prints backlinks under one heading per source type.
+func printBacklinksByType(target string, groups []model.ReferenceTypeGroup) {
+	total := 0
+	for _, group := range groups {
+		total += group.Count
+	}
+	if total == 0 {
+		fmt.Println(ui.Star(fmt.Sprintf("No backlinks found for '%s'", target)))
+		return
+	}
+
+	fmt.Printf("%s %s\n", ui.SectionHeader("Backlinks to "+target), ui.Badge(fmt.Sprintf("%d", total)))
+	for _, group := range groups {
+		sourceType := group.SourceType
+		if sourceType == "" {
+			sourceType = "(untyped)"
+		}
+		fmt.Printf("\n%s %s\n", ui.Bold.Render(sourceType), ui.Muted.Render(fmt.Sprintf("(%d)", group.Count)))
+		printBacklinksTable(group.Items)
+	}
+}
+
+// printBacklinksTable renders backlinks as [num, line text, source, file] so
+// each row shows how the target is mentioned, not just where.
+func printBacklinksTable(links []model.Reference) {
+	display := ui.NewDisplayContext()
+	table := ui.NewResultsTable(display, ui.SearchLayout())
+	maxContentLen := table.ContentWidth("content") * 2
+
+	for i, link := range links {
+		content := link.Context
+		if content == "" {
+			content = link.SourceID
 			if link.DisplayText != nil {
-				displayText = *link.DisplayText
+				content = *link.DisplayText
 			}
-			return displayText
-		},
-	)
+		}
+		if len(content) > maxContentLen {
+			content = ui.TruncateWithEllipsis(content, maxContentLen)
+		}
+
+		source := link.ObjectID
+		if source == "" {
+			source = link.SourceID
+		}
+		if link.SectionTitle != "" {
+			source += " › " + link.SectionTitle
+		}
+
+		line := referenceLine(link)
+		location := formatLocationLinkSimpleStyled(link.FilePath, line, ui.Muted.Render)
+		table.AddRow(ui.ResultRow{
+			Num:      i + 1,
+			Cells:    []string{ui.FormatRowNum(i+1, len(links)), content, source, location},
+			Location: fmt.Sprintf("%s:%d", link.FilePath, line),
+		})
+	}
+
+	fmt.Println(table.Render())
 }
 
 func printBacklinksGroups(groups []model.BacklinksGroup, errors []model.ReferenceInputError) {
@@ -507,6 +648,10 @@ func printBacklinksGroups(groups []model.BacklinksGroup, errors []model.Referenc
 		if i > 0 {
 			fmt.Println()
 		}
+		if group.Groups != nil {
+			printBacklinksByType(group.Target, group.Groups)
+			continue
+		}
 		printBacklinksResults(group.Target, group.Items)
 	}
 	printReferenceInputErrors(errors)
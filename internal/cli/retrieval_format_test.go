@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/aidanlsb/raven/internal/config"
 	"github.com/aidanlsb/raven/internal/model"
 	"github.com/aidanlsb/raven/internal/schema"
 )
@@ -168,7 +169,7 @@ func TestPrintObjectTableIncludesHeadersNameFieldDynamicFieldsAndLocation(t *tes
 					"status": "active",
 				},
 			},
-		}, sch)
+		}, sch, fieldDisplay{})
 	})
 
 	for _, want := range []string{"name", "owner", "status", "location", "Raven Project", "aidan", "active", "projects/raven.md:3"} {
@@ -177,3 +178,54 @@ func TestPrintObjectTableIncludesHeadersNameFieldDynamicFieldsAndLocation(t *tes
 		}
 	}
 }
+
+func TestFieldDisplayTruncatesUnlessFull(t *testing.T) {
+	t.Parallel()
+
+	limit := 12
+	cfg := &config.VaultConfig{Display: &config.DisplayConfig{
+		Truncate: &limit,
+		Fields:   map[string]int{"project.status": 0},
+	}}
+	long := "a fairly long\nmulti-line summary"
+
+	if got := (fieldDisplay{vaultCfg: cfg}).format("project", "summary", long); got != "a fairly..." {
+		t.Fatalf("expected truncated value, got %q", got)
+	}
+	if got := (fieldDisplay{vaultCfg: cfg}).format("project", "status", long); got != "a fairly long multi-line summary" {
+		t.Fatalf("expected untruncated per-field value, got %q", got)
+	}
+	if got := (fieldDisplay{vaultCfg: cfg, full: true}).format("project", "summary", long); got != long {
+		t.Fatalf("expected --full to keep value as-is, got %q", got)
+	}
+}
+
+func TestTruncateFrontmatterForDisplay(t *testing.T) {
+	t.Parallel()
+
+	limit := 20
+	cfg := &config.VaultConfig{Display: &config.DisplayConfig{Truncate: &limit}}
+	frontmatter := "---\n" +
+		"type: project\n" +
+		"title: Short\n" +
+		"summary: This summary is much longer than twenty characters\n" +
+		"notes: |\n" +
+		"  First line of a long block scalar\n" +
+		"  second line\n" +
+		"tags:\n" +
+		"  - a\n" +
+		"---\n"
+
+	got := truncateFrontmatterForDisplay(frontmatter, cfg)
+	want := "---\n" +
+		"type: project\n" +
+		"title: Short\n" +
+		"summary: This summary is...\n" +
+		"notes: First line of a...\n" +
+		"tags:\n" +
+		"  - a\n" +
+		"---\n"
+	if got != want {
+		t.Fatalf("truncateFrontmatterForDisplay() =\n%s\nwant:\n%s", got, want)
+	}
+}
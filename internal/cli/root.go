@@ -12,6 +12,7 @@ import (
 	"github.com/aidanlsb/raven/internal/codes"
 	"github.com/aidanlsb/raven/internal/commands"
 	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/secrets"
 	"github.com/aidanlsb/raven/internal/ui"
 )
 
@@ -54,8 +55,14 @@ who gathered knowledge from across the world.`,
 		}
 		resolvedStatePath = config.ResolveStatePath(statePathFlag, resolvedConfigPath, cfg)
 		ui.ConfigureTheme(cfg.UI.Accent)
+		ui.ConfigurePalette(ui.Palette{
+			Success: cfg.UI.Colors.Success,
+			Warning: cfg.UI.Colors.Warning,
+			Error:   cfg.UI.Colors.Error,
+		})
 		ui.ConfigureMarkdownCodeTheme(cfg.UI.CodeTheme)
 		ui.ConfigureMarkdownStyle(cfg.UI.MarkdownStyle)
+		secrets.SetCommands(cfg.KeyCommands)
 
 		if !shouldResolveVaultForCommand(cmd) {
 			return nil
@@ -185,10 +192,18 @@ func shouldResolveVaultForCommand(cmd *cobra.Command) bool {
 	if isExplicitNoVaultRuntimeCommand(cmd) {
 		return false
 	}
+	if isRootChildCommand(cmd) && cmd.Name() == "serve" {
+		// The MCP server resolves a vault per call; the HTTP API serves one.
+		return strings.TrimSpace(serveHTTPAddr) != ""
+	}
 	commandID, ok := registryCommandIDForCommand(cmd)
 	if !ok {
 		return true
 	}
+	if commandID == "query" && queryVaultsRequested(cmd) {
+		// Cross-vault queries resolve each named vault themselves.
+		return false
+	}
 	return commands.RequiresVault(commandID)
 }
 
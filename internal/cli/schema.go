@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/aidanlsb/raven/internal/schemasvc"
+	"github.com/aidanlsb/raven/internal/ui"
 )
 
 var schemaCmd = &cobra.Command{
@@ -173,11 +175,17 @@ func listSchemaTypes(vaultPath string, start time.Time) error {
 	sort.Strings(names)
 	for _, name := range names {
 		t := types[name]
+		label := name
 		if t.Builtin {
-			fmt.Printf("  %s (built-in)\n", name)
-		} else {
-			fmt.Printf("  %s\n", name)
+			label += " (built-in)"
+		}
+		if t.Usage != nil {
+			label += " " + schemaUsageBadge(t.Usage.ObjectCount, "object", t.Usage.LastUsed)
 		}
+		fmt.Printf("  %s\n", label)
+	}
+	if boolValue(data["usage_unavailable"]) {
+		fmt.Println(ui.Hint("Usage counts unavailable; run 'rvn reindex'."))
 	}
 
 	return nil
@@ -207,16 +215,34 @@ func listSchemaTraits(vaultPath string, start time.Time) error {
 	sort.Strings(names)
 	for _, name := range names {
 		t := traits[name]
+		label := name
 		if t.Type != "" {
-			fmt.Printf("  %s (%s)\n", name, t.Type)
-		} else {
-			fmt.Printf("  %s\n", name)
+			label += fmt.Sprintf(" (%s)", t.Type)
+		}
+		if t.Usage != nil {
+			label += " " + schemaUsageBadge(t.Usage.InstanceCount, "use", t.Usage.LastUsed)
 		}
+		fmt.Printf("  %s\n", label)
+	}
+	if boolValue(data["usage_unavailable"]) {
+		fmt.Println(ui.Hint("Usage counts unavailable; run 'rvn reindex'."))
 	}
 
 	return nil
 }
 
+// schemaUsageBadge renders a usage count badge such as "[3 objects · 2026-05-01]".
+func schemaUsageBadge(count int, noun, lastUsed string) string {
+	text := fmt.Sprintf("%d %s", count, noun)
+	if count != 1 {
+		text += "s"
+	}
+	if lastUsed != "" {
+		text += " · " + lastUsed
+	}
+	return ui.Badge(text)
+}
+
 func listSchemaCore(vaultPath string, start time.Time) error {
 	result := executeCanonicalCommand("schema", vaultPath, map[string]interface{}{"subcommand": "core"})
 	if isJSONOutput() {
@@ -313,6 +339,9 @@ func getSchemaType(vaultPath, typeName string, start time.Time) error {
 	if typeJSON.DefaultPath != "" {
 		fmt.Printf("  Default path: %s\n", typeJSON.DefaultPath)
 	}
+	if typeJSON.Extends != "" {
+		fmt.Printf("  Extends: %s\n", typeJSON.Extends)
+	}
 	if typeJSON.NameField != "" {
 		fmt.Printf("  Name field: %s\n", typeJSON.NameField)
 	}
@@ -330,32 +359,69 @@ func getSchemaType(vaultPath, typeName string, start time.Time) error {
 	if typeJSON.DefaultTemplate != "" {
 		fmt.Printf("  Default template: %s\n", typeJSON.DefaultTemplate)
 	}
-	if len(typeJSON.Fields) > 0 {
+	if len(typeJSON.RequiredTraits) > 0 {
+		fmt.Printf("  Required traits: %s\n", strings.Join(typeJSON.RequiredTraits, ", "))
+	}
+	var ownFields, inheritedFields []string
+	for name, field := range typeJSON.Fields {
+		if field.InheritedFrom != "" {
+			inheritedFields = append(inheritedFields, name)
+		} else {
+			ownFields = append(ownFields, name)
+		}
+	}
+	sort.Strings(ownFields)
+	sort.Strings(inheritedFields)
+	printField := func(name string) {
+		field := typeJSON.Fields[name]
+		var rules []string
+		if field.Required {
+			rules = append(rules, "required")
+		}
+		if field.Unique {
+			rules = append(rules, "unique")
+		}
+		if field.Min != "" {
+			rules = append(rules, "min "+field.Min)
+		}
+		if field.Max != "" {
+			rules = append(rules, "max "+field.Max)
+		}
+		if field.Pattern != "" {
+			rules = append(rules, "pattern "+field.Pattern)
+		}
+		ruleText := ""
+		if len(rules) > 0 {
+			ruleText = " (" + strings.Join(rules, ", ") + ")"
+		}
+		fieldType := field.Type
+		if fieldType == "" {
+			fieldType = "string"
+		}
+		isNameField := ""
+		if name == typeJSON.NameField {
+			isNameField = " [name_field]"
+		}
+		fieldDescription := ""
+		if field.Description != "" {
+			fieldDescription = " - " + field.Description
+		}
+		inherited := ""
+		if field.InheritedFrom != "" {
+			inherited = " (from " + field.InheritedFrom + ")"
+		}
+		fmt.Printf("    %s: %s%s%s%s%s\n", name, fieldType, ruleText, isNameField, inherited, fieldDescription)
+	}
+	if len(ownFields) > 0 {
 		fmt.Println("  Fields:")
-		fieldNames := make([]string, 0, len(typeJSON.Fields))
-		for name := range typeJSON.Fields {
-			fieldNames = append(fieldNames, name)
+		for _, name := range ownFields {
+			printField(name)
 		}
-		sort.Strings(fieldNames)
-		for _, name := range fieldNames {
-			field := typeJSON.Fields[name]
-			required := ""
-			if field.Required {
-				required = " (required)"
-			}
-			fieldType := field.Type
-			if fieldType == "" {
-				fieldType = "string"
-			}
-			isNameField := ""
-			if name == typeJSON.NameField {
-				isNameField = " [name_field]"
-			}
-			fieldDescription := ""
-			if field.Description != "" {
-				fieldDescription = " - " + field.Description
-			}
-			fmt.Printf("    %s: %s%s%s%s\n", name, fieldType, required, isNameField, fieldDescription)
+	}
+	if len(inheritedFields) > 0 {
+		fmt.Println("  Inherited fields:")
+		for _, name := range inheritedFields {
+			printField(name)
 		}
 	}
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/aidanlsb/raven/internal/codes"
 	"github.com/aidanlsb/raven/internal/commandexec"
 	"github.com/aidanlsb/raven/internal/commands"
 	"github.com/aidanlsb/raven/internal/paths"
@@ -191,6 +192,7 @@ var schemaUpdateTraitCmd = newCanonicalLeafCommand("schema_update_trait", canoni
 
 var schemaUpdateFieldCmd = newCanonicalLeafCommand("schema_update_field", canonicalLeafOptions{
 	VaultPath:   getVaultPath,
+	Invoke:      invokeSchemaUpdateField,
 	RenderHuman: renderSchemaUpdateField,
 })
 
@@ -200,7 +202,7 @@ func renderSchemaUpdateType(_ *cobra.Command, result commandexec.Result) error {
 	if err != nil {
 		return err
 	}
-	printSchemaChangeList(fmt.Sprintf("✓ Updated type '%s'", stringValue(data["name"])), changes)
+	printSchemaChangeList(fmt.Sprintf("Updated type '%s'", stringValue(data["name"])), changes)
 	return nil
 }
 
@@ -210,17 +212,58 @@ func renderSchemaUpdateTrait(_ *cobra.Command, result commandexec.Result) error
 	if err != nil {
 		return err
 	}
-	printSchemaChangeList(fmt.Sprintf("✓ Updated trait '%s'", stringValue(data["name"])), changes)
+	printSchemaChangeList(fmt.Sprintf("Updated trait '%s'", stringValue(data["name"])), changes)
 	return nil
 }
 
 func renderSchemaUpdateField(_ *cobra.Command, result commandexec.Result) error {
 	data := canonicalDataMap(result)
+	fieldName, typeName := stringValue(data["field"]), stringValue(data["type"])
+	if _, ok := data["migration"]; ok {
+		migration, err := decodeSchemaValue[schemasvc.FieldMigration](data["migration"])
+		if err != nil {
+			return err
+		}
+		return renderSchemaFieldMigration(fieldName, typeName, boolValue(data["preview"]), migration, result.Warnings)
+	}
 	changes, err := decodeSchemaValue[[]string](data["changes"])
 	if err != nil {
 		return err
 	}
-	printSchemaChangeList(fmt.Sprintf("✓ Updated field '%s' on type '%s'", stringValue(data["field"]), stringValue(data["type"])), changes)
+	printSchemaChangeList(fmt.Sprintf("Updated field '%s' on type '%s'", fieldName, typeName), changes)
+	return nil
+}
+
+func renderSchemaFieldMigration(fieldName, typeName string, preview bool, migration schemasvc.FieldMigration, warnings []commandexec.Warning) error {
+	if preview {
+		fmt.Println(ui.SectionHeader(fmt.Sprintf("Changing '%s' on type '%s' from %s to %s would convert %d values", fieldName, typeName, migration.From, migration.To, len(migration.Conversions))))
+	} else {
+		fmt.Println(ui.Checkf("Changed '%s' on type '%s' from %s to %s and converted %d values", fieldName, typeName, migration.From, migration.To, len(migration.Conversions)))
+	}
+	for _, conversion := range migration.Conversions {
+		fmt.Println(ui.Bullet(fmt.Sprintf("%s: %s → %s", conversion.ID, conversion.From, conversion.To)))
+	}
+	if len(migration.Failures) > 0 {
+		fmt.Println()
+		fmt.Println(ui.SectionHeader(fmt.Sprintf("Cannot convert %d values", len(migration.Failures))))
+		for _, failure := range migration.Failures {
+			fmt.Println(ui.Bullet(fmt.Sprintf("%s: %s %s", failure.ID, failure.Value, ui.Muted.Render("("+failure.Reason+")"))))
+		}
+	}
+	for _, warning := range warnings {
+		if warning.Code == codes.WarnCheckIncomplete {
+			continue // The failure list above already explains it
+		}
+		fmt.Println(ui.Warning(warning.Message))
+	}
+	if preview {
+		fmt.Println()
+		if len(migration.Failures) > 0 {
+			fmt.Println(ui.Hint("Fix the values above, or run with --confirm --force to convert the rest and leave them unchanged."))
+		} else {
+			fmt.Println(ui.Hint("Run with --confirm to apply changes."))
+		}
+	}
 	return nil
 }
 
@@ -238,7 +281,9 @@ Subcommands:
   trait <name>             Remove a trait (existing instances remain in files)
   field <type> <field>     Remove a field from a type
 
-By default, warns about affected files. Use --force to skip warnings.
+By default, shows an impact report (affected files, saved queries, templates,
+and schema references) and asks for confirmation. Use --force to skip it.
+Run 'rvn schema impact' to see the report without removing anything.
 
 Examples:
   rvn schema remove type event
@@ -260,55 +305,22 @@ var schemaRemoveTraitCmd = newCanonicalLeafCommand("schema_remove_trait", canoni
 
 var schemaRemoveFieldCmd = newCanonicalLeafCommand("schema_remove_field", canonicalLeafOptions{
 	VaultPath:   getVaultPath,
+	Invoke:      invokeSchemaRemoveField,
 	RenderHuman: renderSchemaRemoveField,
 })
 
 func invokeSchemaRemoveType(_ *cobra.Command, commandID, vaultPath string, args map[string]interface{}) commandexec.Result {
-	result := executeCanonicalCommand(commandID, vaultPath, args)
-	if isJSONOutput() || boolValue(args["force"]) || result.OK || result.Error == nil || result.Error.Code != ErrConfirmationRequired {
-		return result
-	}
-
-	details, _ := result.Error.Details.(map[string]interface{})
-	count := detailInt(details, "affected_count")
-	typeName := stringValue(args["name"])
-	if count > 0 {
-		fmt.Println(ui.Warningf("%d files of type '%s' will become 'page' type:", count, typeName))
-		for _, filePath := range detailStringSlice(details, "affected_files") {
-			fmt.Println(ui.Bullet(ui.FilePath(filePath)))
-		}
-		if remaining := detailInt(details, "remaining_count"); remaining > 0 {
-			fmt.Printf("  %s\n", ui.Hint(fmt.Sprintf("... and %d more", remaining)))
-		}
+	if !confirmSchemaImpact(vaultPath, args, map[string]interface{}{"kind": "type", "name": args["name"]}) {
+		return cancelledSchemaChange()
 	}
-	if !promptForConfirm("Continue?") {
-		return commandexec.Failure(ErrConfirmationRequired, "operation cancelled", nil, "Use --force to skip confirmation")
-	}
-
-	retry := cloneArgsMap(args)
-	retry["force"] = true
-	return executeCanonicalCommand(commandID, vaultPath, retry)
+	return executeCanonicalCommand(commandID, vaultPath, args)
 }
 
 func invokeSchemaRemoveTrait(_ *cobra.Command, commandID, vaultPath string, args map[string]interface{}) commandexec.Result {
-	result := executeCanonicalCommand(commandID, vaultPath, args)
-	if isJSONOutput() || boolValue(args["force"]) || result.OK || result.Error == nil || result.Error.Code != ErrConfirmationRequired {
-		return result
-	}
-
-	details, _ := result.Error.Details.(map[string]interface{})
-	count := detailInt(details, "affected_count")
-	traitName := stringValue(args["name"])
-	if count > 0 {
-		fmt.Println(ui.Warningf("%d instances of @%s will remain in files (no longer indexed)", count, traitName))
-	}
-	if !promptForConfirm("Continue?") {
-		return commandexec.Failure(ErrConfirmationRequired, "operation cancelled", nil, "Use --force to skip confirmation")
+	if !confirmSchemaImpact(vaultPath, args, map[string]interface{}{"kind": "trait", "name": args["name"]}) {
+		return cancelledSchemaChange()
 	}
-
-	retry := cloneArgsMap(args)
-	retry["force"] = true
-	return executeCanonicalCommand(commandID, vaultPath, retry)
+	return executeCanonicalCommand(commandID, vaultPath, args)
 }
 
 func renderSchemaRemoveType(_ *cobra.Command, result commandexec.Result) error {
@@ -327,50 +339,6 @@ func renderSchemaRemoveField(_ *cobra.Command, result commandexec.Result) error
 	return nil
 }
 
-func detailInt(details map[string]interface{}, key string) int {
-	if details == nil {
-		return 0
-	}
-	value, ok := details[key]
-	if !ok {
-		return 0
-	}
-	switch typed := value.(type) {
-	case int:
-		return typed
-	case int64:
-		return int(typed)
-	case float64:
-		return int(typed)
-	default:
-		return 0
-	}
-}
-
-func detailStringSlice(details map[string]interface{}, key string) []string {
-	if details == nil {
-		return nil
-	}
-	raw, ok := details[key]
-	if !ok {
-		return nil
-	}
-	switch typed := raw.(type) {
-	case []string:
-		return typed
-	case []interface{}:
-		values := make([]string, 0, len(typed))
-		for _, item := range typed {
-			if str, ok := item.(string); ok && strings.TrimSpace(str) != "" {
-				values = append(values, str)
-			}
-		}
-		return values
-	default:
-		return nil
-	}
-}
-
 func decodeSchemaCount(raw interface{}) (int, error) {
 	switch typed := raw.(type) {
 	case int:
@@ -644,4 +612,5 @@ func init() {
 	schemaCmd.AddCommand(schemaRemoveCmd)
 	schemaCmd.AddCommand(schemaRenameCmd)
 	schemaCmd.AddCommand(schemaValidateCmd)
+	schemaCmd.AddCommand(schemaImpactCmd)
 }
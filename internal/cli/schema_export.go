@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/schemasvc"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var schemaExportCmd = newCanonicalLeafCommand("schema_export", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderSchemaExport,
+})
+
+var schemaImportCmd = newCanonicalLeafCommand("schema_import", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderSchemaImport,
+})
+
+var schemaExportSnippetsCmd = newCanonicalLeafCommand("schema_export_snippets", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderSchemaExportSnippets,
+})
+
+// renderSchemaExport prints the pack alone on stdout so it can be redirected
+// to a file; notes go to stderr.
+func renderSchemaExport(_ *cobra.Command, result commandexec.Result) error {
+	data, err := decodeSchemaValue[schemasvc.ExportResult](result.Data)
+	if err != nil {
+		return err
+	}
+	fmt.Print(data.Content)
+	for _, note := range data.Notes {
+		fmt.Fprintln(os.Stderr, ui.Warning(note))
+	}
+	return nil
+}
+
+func renderSchemaImport(_ *cobra.Command, result commandexec.Result) error {
+	data, err := decodeSchemaValue[schemasvc.ImportResult](result.Data)
+	if err != nil {
+		return err
+	}
+
+	conflicts := 0
+	for _, entry := range data.Entries {
+		label := fmt.Sprintf("%s %s", entry.Kind, entry.Name)
+		switch entry.Status {
+		case schemasvc.ImportStatusAdd:
+			fmt.Println(ui.Bullet(label + " " + ui.Muted.Render("(add)")))
+		case schemasvc.ImportStatusUnchanged:
+			fmt.Println(ui.Bullet(label + " " + ui.Muted.Render("(unchanged)")))
+		case schemasvc.ImportStatusConflict:
+			conflicts++
+			fmt.Println(ui.Warning(fmt.Sprintf("%s conflicts: %s differs", label, strings.Join(entry.Differences, ", "))))
+		case schemasvc.ImportStatusSkip:
+			fmt.Println(ui.Bullet(label + " " + ui.Muted.Render("(differs; keeping this vault's: "+strings.Join(entry.Differences, ", ")+")")))
+		case schemasvc.ImportStatusOverwrite:
+			fmt.Println(ui.Bullet(label + " " + ui.Muted.Render("(differs; overwriting: "+strings.Join(entry.Differences, ", ")+")")))
+		}
+	}
+	for _, issue := range data.Issues {
+		fmt.Println(ui.Warning(issue))
+	}
+
+	fmt.Println()
+	switch {
+	case !data.Preview && len(data.Applied) == 0:
+		fmt.Println(ui.Check("Nothing to import; schema.yaml already has these definitions"))
+	case !data.Preview:
+		fmt.Println(ui.Checkf("Imported %d definition(s) from %s", len(data.Applied), ui.FilePath(data.File)))
+	case conflicts > 0:
+		fmt.Println(ui.Hint("Resolve conflicts with --on-conflict skip or --on-conflict overwrite, then run with --confirm."))
+	case len(data.Issues) > 0:
+		fmt.Println(ui.Hint("Fix the issues above before importing."))
+	case len(data.Applied) == 0:
+		fmt.Println(ui.Check("Nothing to import; schema.yaml already has these definitions"))
+	default:
+		fmt.Println(ui.Hint(fmt.Sprintf("Run with --confirm to import %d definition(s).", len(data.Applied))))
+	}
+	return nil
+}
+
+func renderSchemaExportSnippets(_ *cobra.Command, result commandexec.Result) error {
+	data, err := decodeSchemaValue[schemasvc.ExportSnippetsResult](result.Data)
+	if err != nil {
+		return err
+	}
+
+	if data.OutputDir == "" {
+		for i, file := range data.Files {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(ui.Muted.Render("# " + file.Path))
+			fmt.Print(file.Content)
+		}
+		return nil
+	}
+
+	fmt.Println(ui.Checkf("Wrote %d %s snippet file(s) to %s", len(data.Files), data.Format, ui.FilePath(data.OutputDir)))
+	for _, file := range data.Files {
+		fmt.Println(ui.Bullet(ui.FilePath(filepath.FromSlash(file.Path))))
+	}
+	return nil
+}
+
+func init() {
+	schemaExportCmd.AddCommand(schemaExportSnippetsCmd)
+	schemaCmd.AddCommand(schemaExportCmd)
+	schemaCmd.AddCommand(schemaImportCmd)
+}
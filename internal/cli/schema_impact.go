@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/schemasvc"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+// schemaImpactFileLimit caps how many affected files are listed in human output.
+const schemaImpactFileLimit = 10
+
+var schemaImpactCmd = newCanonicalLeafCommand("schema_impact", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderSchemaImpact,
+})
+
+func renderSchemaImpact(_ *cobra.Command, result commandexec.Result) error {
+	impact, err := decodeSchemaValue[schemasvc.Impact](canonicalDataMap(result)["impact"])
+	if err != nil {
+		return err
+	}
+	printSchemaImpact(&impact)
+	return nil
+}
+
+func printSchemaImpact(impact *schemasvc.Impact) {
+	fmt.Println(ui.SectionHeader(schemaImpactTitle(impact)))
+	if impact.IndexUnavailable {
+		fmt.Println(ui.Warning("Index unavailable; file counts may be incomplete (run 'rvn reindex')"))
+	}
+	if !impact.HasUsage() {
+		fmt.Println(ui.Hint("Nothing in the vault uses it."))
+		return
+	}
+	fmt.Println(ui.Hint("Effect: " + impact.Effect))
+
+	switch {
+	case impact.TraitCount > 0:
+		fmt.Printf("\n%d instances in %d files\n", impact.TraitCount, len(impact.Files))
+	case impact.ObjectCount > 0:
+		fmt.Printf("\n%d objects in %d files\n", impact.ObjectCount, len(impact.Files))
+	}
+	for i, file := range impact.Files {
+		if i >= schemaImpactFileLimit {
+			fmt.Printf("  %s\n", ui.Hint(fmt.Sprintf("... and %d more", len(impact.Files)-i)))
+			break
+		}
+		fmt.Println(ui.Bullet(ui.FilePath(file)))
+	}
+
+	if len(impact.Queries) > 0 {
+		fmt.Printf("\nSaved queries (%d)\n", len(impact.Queries))
+		for _, q := range impact.Queries {
+			fmt.Println(ui.Bullet(fmt.Sprintf("%s  %s", ui.Bold.Render(q.Name), ui.Hint(q.Query))))
+		}
+	}
+	if len(impact.Templates) > 0 {
+		fmt.Printf("\nTemplates (%d)\n", len(impact.Templates))
+		for _, tmpl := range impact.Templates {
+			label := ui.FilePath(tmpl.File)
+			if tmpl.ID != "" {
+				label = fmt.Sprintf("%s (%s)", tmpl.ID, label)
+			}
+			fmt.Println(ui.Bullet(fmt.Sprintf("%s  %s", label, ui.Hint(tmpl.Reason))))
+		}
+	}
+	if len(impact.SchemaRefs) > 0 {
+		fmt.Printf("\nSchema references (%d)\n", len(impact.SchemaRefs))
+		for _, ref := range impact.SchemaRefs {
+			fmt.Println(ui.Bullet(fmt.Sprintf("%s  %s", ref.Location, ui.Hint(ref.Description))))
+		}
+	}
+}
+
+func schemaImpactTitle(impact *schemasvc.Impact) string {
+	switch impact.Kind {
+	case schemasvc.ImpactKindType:
+		return fmt.Sprintf("Impact of removing type '%s'", impact.Name)
+	case schemasvc.ImpactKindTrait:
+		return fmt.Sprintf("Impact of removing trait '%s'", impact.Name)
+	case schemasvc.ImpactKindEnumValues:
+		return fmt.Sprintf("Impact of dropping %s from '%s.%s'", strings.Join(impact.Values, ", "), impact.Type, impact.Field)
+	default:
+		return fmt.Sprintf("Impact of removing field '%s.%s'", impact.Type, impact.Field)
+	}
+}
+
+// confirmSchemaImpact shows the impact report for a destructive schema change
+// and asks the user to confirm. It returns true without prompting for JSON
+// output, --force, non-interactive sessions, or when nothing uses the item.
+func confirmSchemaImpact(vaultPath string, args map[string]interface{}, impactArgs map[string]interface{}) bool {
+	if isJSONOutput() || boolValue(args["force"]) || !shouldPromptForConfirm() {
+		return true
+	}
+	result := executeCanonicalCommand("schema_impact", vaultPath, impactArgs)
+	if !result.OK {
+		return true
+	}
+	impact, err := decodeSchemaValue[schemasvc.Impact](canonicalDataMap(result)["impact"])
+	if err != nil || !impact.HasUsage() {
+		return true
+	}
+	printSchemaImpact(&impact)
+	fmt.Println()
+	return promptForConfirm("Continue?")
+}
+
+func cancelledSchemaChange() commandexec.Result {
+	return commandexec.Failure(ErrConfirmationRequired, "operation cancelled", nil, "Use --force to skip confirmation")
+}
+
+func invokeSchemaRemoveField(_ *cobra.Command, commandID, vaultPath string, args map[string]interface{}) commandexec.Result {
+	if !confirmSchemaImpact(vaultPath, args, map[string]interface{}{
+		"kind":  "field",
+		"name":  args["type_name"],
+		"field": args["field_name"],
+	}) {
+		return cancelledSchemaChange()
+	}
+	return executeCanonicalCommand(commandID, vaultPath, args)
+}
+
+func invokeSchemaUpdateField(_ *cobra.Command, commandID, vaultPath string, args map[string]interface{}) commandexec.Result {
+	if dropped := droppedEnumValues(vaultPath, args); len(dropped) > 0 {
+		if !confirmSchemaImpact(vaultPath, args, map[string]interface{}{
+			"kind":   "field",
+			"name":   args["type_name"],
+			"field":  args["field_name"],
+			"values": strings.Join(dropped, ","),
+		}) {
+			return cancelledSchemaChange()
+		}
+	}
+	return executeCanonicalCommand(commandID, vaultPath, args)
+}
+
+// droppedEnumValues returns the enum values a `schema update field` call would
+// remove, or nil when values and type are unchanged or the schema can't be read.
+func droppedEnumValues(vaultPath string, args map[string]interface{}) []string {
+	values := stringValue(args["values"])
+	newType := strings.TrimSuffix(strings.TrimSpace(stringValue(args["type"])), "[]")
+	if strings.TrimSpace(values) == "" && newType == "" {
+		return nil
+	}
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return nil
+	}
+	typeDef := sch.Types[stringValue(args["type_name"])]
+	if typeDef == nil || typeDef.Fields == nil {
+		return nil
+	}
+	current := typeDef.Fields[stringValue(args["field_name"])]
+	if current == nil {
+		return nil
+	}
+	if strings.TrimSpace(values) == "" {
+		values = strings.Join(current.Values, ",")
+	}
+	return schemasvc.RemovedEnumValues(current, newType, values)
+}
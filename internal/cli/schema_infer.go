@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/schemasvc"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var schemaInferCmd = newCanonicalLeafCommand("schema_infer", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	Invoke:      invokeSchemaInfer,
+	RenderHuman: renderSchemaInfer,
+})
+
+// invokeSchemaInfer offers each proposal in turn when run interactively
+// without --confirm or --accept, then writes the accepted ones.
+func invokeSchemaInfer(_ *cobra.Command, commandID, vaultPath string, args map[string]interface{}) commandexec.Result {
+	if boolValue(args["confirm"]) || len(stringSliceFromAny(args["accept"])) > 0 || !shouldPromptForConfirm() {
+		return executeCanonicalCommand(commandID, vaultPath, args)
+	}
+
+	preview := executeCanonicalCommand(commandID, vaultPath, args)
+	if !preview.OK {
+		return preview
+	}
+	types, traits, err := decodeSchemaInferProposals(canonicalDataMap(preview))
+	if err != nil {
+		return commandexec.Failure(ErrInternal, err.Error(), nil, "")
+	}
+	if len(types)+len(traits) == 0 {
+		return preview
+	}
+
+	accepted := promptSchemaInferProposals(newCheckInteraction(os.Stdin, os.Stdout), types, traits)
+	fmt.Println()
+	if len(accepted) == 0 {
+		return commandexec.Success(map[string]interface{}{"preview": false, "applied": []string{}}, nil)
+	}
+	applyArgs := cloneArgsMap(args)
+	applyArgs["accept"] = accepted
+	applyArgs["confirm"] = true
+	return executeCanonicalCommand(commandID, vaultPath, applyArgs)
+}
+
+// promptSchemaInferProposals asks to accept or skip each proposal and returns
+// the accepted keys. Quitting skips the rest.
+func promptSchemaInferProposals(interaction checkInteraction, types []schemasvc.InferredType, traits []schemasvc.InferredTrait) []string {
+	total := len(types) + len(traits)
+	var accepted []string
+	ask := func(n int, key string, lines []string) bool {
+		interaction.Println()
+		for i, line := range lines {
+			if i == 0 {
+				interaction.Println(ui.SectionHeader(fmt.Sprintf("[%d/%d] %s", n, total, line)))
+				continue
+			}
+			interaction.Println(line)
+		}
+		interaction.Printf("  %s ", ui.Hint("[a]ccept, [s]kip, [q]uit"))
+		switch readTrimmedLowerLine(interaction) {
+		case "a", "accept", "y", "yes":
+			accepted = append(accepted, key)
+		case "q", "quit":
+			return false
+		}
+		return true
+	}
+
+	n := 0
+	for _, inferred := range types {
+		n++
+		if !ask(n, inferred.Key, schemaInferTypeLines(inferred)) {
+			return accepted
+		}
+	}
+	for _, inferred := range traits {
+		n++
+		if !ask(n, inferred.Key, []string{schemaInferTraitLine(inferred)}) {
+			return accepted
+		}
+	}
+	return accepted
+}
+
+func renderSchemaInfer(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	if !boolValue(data["preview"]) {
+		applied := stringSliceFromAny(data["applied"])
+		if len(applied) == 0 {
+			fmt.Println(ui.Hint("No proposals written; schema.yaml is unchanged."))
+			return nil
+		}
+		fmt.Println(ui.Checkf("Added %d definition(s) to schema.yaml", len(applied)))
+		for _, key := range applied {
+			fmt.Println(ui.Bullet(key))
+		}
+		if typed := stringSliceFromAny(data["typed_files"]); len(typed) > 0 {
+			fmt.Println(ui.Checkf("Set type on %d untyped file(s)", len(typed)))
+		}
+		for _, warning := range result.Warnings {
+			fmt.Println(ui.Warning(warning.Message))
+		}
+		return nil
+	}
+
+	types, traits, err := decodeSchemaInferProposals(data)
+	if err != nil {
+		return err
+	}
+	if len(types)+len(traits) == 0 {
+		fmt.Println(ui.Check("Nothing to infer: the schema covers every type and trait in use"))
+		return nil
+	}
+	if len(types) > 0 {
+		fmt.Println(ui.SectionHeader(fmt.Sprintf("%d proposed types", len(types))))
+		for _, inferred := range types {
+			lines := schemaInferTypeLines(inferred)
+			fmt.Println(ui.Bullet(lines[0]))
+			for _, line := range lines[1:] {
+				fmt.Println("  " + line)
+			}
+		}
+	}
+	if len(traits) > 0 {
+		if len(types) > 0 {
+			fmt.Println()
+		}
+		fmt.Println(ui.SectionHeader(fmt.Sprintf("%d proposed traits", len(traits))))
+		for _, inferred := range traits {
+			fmt.Println(ui.Bullet(schemaInferTraitLine(inferred)))
+		}
+	}
+	fmt.Println()
+	fmt.Println(ui.Hint("Run with --confirm to write these, or --accept type:<name> --confirm to pick some."))
+	return nil
+}
+
+func decodeSchemaInferProposals(data map[string]interface{}) ([]schemasvc.InferredType, []schemasvc.InferredTrait, error) {
+	types, err := decodeSchemaValue[[]schemasvc.InferredType](data["types"])
+	if err != nil {
+		return nil, nil, err
+	}
+	traits, err := decodeSchemaValue[[]schemasvc.InferredTrait](data["traits"])
+	if err != nil {
+		return nil, nil, err
+	}
+	return types, traits, nil
+}
+
+func schemaInferTypeLines(inferred schemasvc.InferredType) []string {
+	origin := fmt.Sprintf("%d files", inferred.Files)
+	if inferred.DefaultPath != "" {
+		origin += " in " + inferred.DefaultPath
+	}
+	if inferred.Source == schemasvc.InferSourceFrontmatter {
+		origin += ", declared with type:"
+	}
+	lines := []string{fmt.Sprintf("type %s %s", inferred.Name, ui.Muted.Render("("+origin+")"))}
+	for _, field := range inferred.Fields {
+		spec := field.Type
+		if len(field.Values) > 0 {
+			spec += " [" + strings.Join(field.Values, ", ") + "]"
+		}
+		if field.Target != "" {
+			spec += " → " + field.Target
+		}
+		detail := fmt.Sprintf("set in %d", field.Count)
+		if len(field.Examples) > 0 {
+			detail += "; e.g. " + strings.Join(field.Examples, ", ")
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s %s", field.Name, spec, ui.Muted.Render("("+detail+")")))
+	}
+	return lines
+}
+
+func schemaInferTraitLine(inferred schemasvc.InferredTrait) string {
+	spec := inferred.Type
+	if len(inferred.Values) > 0 {
+		spec += " [" + strings.Join(inferred.Values, ", ") + "]"
+	}
+	return fmt.Sprintf("trait @%s: %s %s", inferred.Name, spec, ui.Muted.Render(fmt.Sprintf("(used %d times)", inferred.Count)))
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaInferCmd)
+}
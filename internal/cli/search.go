@@ -75,6 +75,7 @@ func handleCanonicalSearchFailure(result commandexec.Result) error {
 func renderSearch(_ *cobra.Command, result commandexec.Result) error {
 	data := canonicalDataMap(result)
 	resultQuery, _ := data["query"].(string)
+	printDegradedSearchWarnings(result.Warnings)
 	printSearchResults(resultQuery, searchMatchesFromResult(data["results"]))
 	return nil
 }
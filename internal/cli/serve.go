@@ -1,26 +1,50 @@
 package cli
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"net"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
+	"github.com/aidanlsb/raven/internal/httpapi"
 	"github.com/aidanlsb/raven/internal/mcp"
 )
 
+// httpTokenEnv holds the bearer token for `rvn serve --http`.
+const httpTokenEnv = "RAVEN_HTTP_TOKEN"
+
+var (
+	serveDebugAddr       string
+	serveHTTPAddr        string
+	serveHTTPAllowOrigin string
+)
+
 var serveCmd = &cobra.Command{
 	Use:   "serve",
-	Short: "Run Raven as an MCP server",
+	Short: "Run Raven as an MCP server or HTTP API",
 	Long: `Run Raven as an MCP (Model Context Protocol) server.
 
 This enables LLM agents to interact with your vault through a standardized protocol.
 
 The server communicates over stdin/stdout using JSON-RPC 2.0.
 
+With --http, Raven instead serves a read-only JSON API for the vault on that
+address: /api/query, /api/read, /api/backlinks, and /api/stats. Requests must
+send 'Authorization: Bearer <token>', where the token is RAVEN_HTTP_TOKEN or,
+if that is unset, a random token printed at startup.
+
 Examples:
   rvn serve                    # Run MCP server using normal CLI vault resolution
   rvn serve --vault personal   # Force named vault for this server process
+  rvn serve --debug-addr 127.0.0.1:6060  # Also serve /debug/pprof/ and /metrics
+  rvn serve --http 127.0.0.1:8080        # Serve the JSON API instead
 
 For use with Claude Desktop, add to your config:
   {
@@ -32,6 +56,10 @@ For use with Claude Desktop, add to your config:
     }
   }`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if addr := strings.TrimSpace(serveHTTPAddr); addr != "" {
+			return runHTTPAPI(addr)
+		}
+
 		baseArgs := make([]string, 0, 8)
 		if strings.TrimSpace(configPath) != "" {
 			baseArgs = append(baseArgs, "--config", configPath)
@@ -49,6 +77,15 @@ For use with Claude Desktop, add to your config:
 		// (but we can log to stderr if needed)
 
 		server := mcp.NewServerWithBaseArgs(baseArgs)
+		if addr := strings.TrimSpace(serveDebugAddr); addr != "" {
+			debugServer, boundAddr, err := mcp.StartDebugServer(addr, server.Metrics())
+			if err != nil {
+				return err
+			}
+			defer debugServer.Close()
+			fmt.Fprintf(os.Stderr, "[raven-mcp] Debug endpoints on http://%s (/debug/pprof/, /metrics)\n", boundAddr)
+		}
+
 		if err := server.Run(); err != nil {
 			return fmt.Errorf("MCP server error: %w", err)
 		}
@@ -57,7 +94,41 @@ For use with Claude Desktop, add to your config:
 	},
 }
 
+// runHTTPAPI serves the vault's read-only JSON API until interrupted.
+func runHTTPAPI(addr string) error {
+	token := strings.TrimSpace(os.Getenv(httpTokenEnv))
+	generated := token == ""
+	if generated {
+		buf := make([]byte, 24)
+		if _, err := rand.Read(buf); err != nil {
+			return fmt.Errorf("generate API token: %w", err)
+		}
+		token = hex.EncodeToString(buf)
+	}
+
+	server, err := httpapi.NewServer(httpapi.Options{
+		VaultPath:   getVaultPath(),
+		Token:       token,
+		AllowOrigin: serveHTTPAllowOrigin,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return server.ListenAndServe(ctx, addr, func(bound net.Addr) {
+		fmt.Fprintf(os.Stderr, "Serving %s on http://%s/api/\n", getVaultPath(), bound)
+		if generated {
+			fmt.Fprintf(os.Stderr, "API token (set %s to choose one): %s\n", httpTokenEnv, token)
+		}
+	})
+}
+
 func init() {
+	serveCmd.Flags().StringVar(&serveDebugAddr, "debug-addr", "", "Serve pprof and Prometheus metrics on this address (e.g. 127.0.0.1:6060)")
+	serveCmd.Flags().StringVar(&serveHTTPAddr, "http", "", "Serve a read-only JSON API on this address instead of MCP (e.g. 127.0.0.1:8080)")
+	serveCmd.Flags().StringVar(&serveHTTPAllowOrigin, "http-allow-origin", "", "Allow browser requests from this origin (CORS) when serving --http")
 	markLocalLeaf(serveCmd)
 	rootCmd.AddCommand(serveCmd)
 }
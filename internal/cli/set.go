@@ -14,7 +14,7 @@ import (
 var setCmd = newCanonicalLeafCommand("set", canonicalLeafOptions{
 	VaultPath: getVaultPath,
 	Args:      cobra.ArbitraryArgs,
-	BuildArgs: buildSetArgs,
+	BuildArgs: withUnlockArg(buildSetArgs),
 	Invoke:    invokeSet,
 	RenderHuman: func(_ *cobra.Command, result commandexec.Result) error {
 		data := canonicalDataMap(result)
@@ -27,13 +27,76 @@ func handleCanonicalMaintSvcFailure(result commandexec.Result) error {
 func renderVaultStats(_ *cobra.Command, result commandexec.Result) error {
 	data := canonicalDataMap(result)
 	fmt.Println(ui.SectionHeader("Vault Statistics"))
-	fmt.Println(ui.Bullet(ui.Muted.Render("Files: ") + ui.Bold.Render(fmt.Sprintf("%v", data["file_count"]))))
-	fmt.Println(ui.Bullet(ui.Muted.Render("Objects: ") + ui.Bold.Render(fmt.Sprintf("%v", data["object_count"]))))
-	fmt.Println(ui.Bullet(ui.Muted.Render("Traits: ") + ui.Bold.Render(fmt.Sprintf("%v", data["trait_count"]))))
-	fmt.Println(ui.Bullet(ui.Muted.Render("References: ") + ui.Bold.Render(fmt.Sprintf("%v", data["ref_count"]))))
+	fmt.Println(ui.Bullet(ui.Stat("Files", data["file_count"])))
+	fmt.Println(ui.Bullet(ui.Stat("Objects", data["object_count"])))
+	fmt.Println(ui.Bullet(ui.Stat("Traits", data["trait_count"])))
+	fmt.Println(ui.Bullet(ui.Stat("References", data["ref_count"])))
+	fmt.Println(ui.Bullet(ui.Stat("Orphans", data["orphan_count"])))
+
+	if rawAuthors, ok := data["authors"]; ok {
+		var authors []maintsvc.AuthorCount
+		_ = decodeResultData(rawAuthors, &authors)
+		fmt.Println()
+		fmt.Println(ui.SectionHeader("By Author"))
+		if len(authors) == 0 {
+			fmt.Println(ui.Hint("No attributed objects. Enable attribution in raven.yaml to stamp created_by/modified_by."))
+		}
+		for _, author := range authors {
+			fmt.Println(ui.Bullet(fmt.Sprintf("%s  %s", author.Author, ui.Hint(fmt.Sprintf("created %d, modified %d", author.Created, author.Modified)))))
+		}
+	}
+
+	if rawActivity, ok := data["activity"]; ok {
+		var activity maintsvc.ActivityReport
+		_ = decodeResultData(rawActivity, &activity)
+		renderVaultActivity(&activity)
+	}
 	return nil
 }
 
+func renderVaultActivity(activity *maintsvc.ActivityReport) {
+	fmt.Println()
+	fmt.Println(ui.SectionHeader("Since " + activity.Since))
+	if len(activity.Series) > 0 {
+		fmt.Println(ui.Hint(fmt.Sprintf("Compared with the snapshot from %s", activity.Series[0].Day)))
+	}
+	growth := activity.Growth
+	for _, row := range []struct {
+		label  string
+		change maintsvc.CountChange
+	}{
+		{"Files", growth.Files},
+		{"Objects", growth.Objects},
+		{"Traits", growth.Traits},
+		{"References", growth.Refs},
+		{"Orphans", growth.Orphans},
+	} {
+		fmt.Println(ui.Bullet(fmt.Sprintf("%s  %s", ui.Stat(row.label, fmt.Sprintf("%+d", row.change.Change)), ui.Hint(fmt.Sprintf("%d → %d", row.change.From, row.change.To)))))
+	}
+
+	fmt.Println()
+	fmt.Println(ui.SectionHeader("Most Edited"))
+	if len(activity.MostEdited) == 0 {
+		fmt.Println(ui.Hint("No edits recorded in this window. Edits are counted when changed files are reindexed."))
+	}
+	for _, edited := range activity.MostEdited {
+		name := edited.ID
+		if name == "" {
+			name = edited.FilePath
+		}
+		fmt.Println(ui.Bullet(fmt.Sprintf("%s  %s", name, ui.Hint(fmt.Sprintf("%d edit(s)", edited.Edits)))))
+	}
+
+	fmt.Println()
+	fmt.Println(ui.SectionHeader("Most Referenced"))
+	if len(activity.MostReferenced) == 0 {
+		fmt.Println(ui.Hint("No references between objects."))
+	}
+	for _, referenced := range activity.MostReferenced {
+		fmt.Println(ui.Bullet(fmt.Sprintf("%s  %s", referenced.ID, ui.Hint(fmt.Sprintf("%d reference(s)", referenced.References)))))
+	}
+}
+
 func mapMaintSvcCode(code codes.ErrorCode) codes.ErrorCode {
 	switch code {
 	case maintsvc.CodeInvalidInput:
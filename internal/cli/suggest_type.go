@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var suggestTypeCmd = newCanonicalLeafCommand("suggest-type", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderSuggestType,
+})
+
+type typeSuggestionPage struct {
+	objectID    string
+	filePath    string
+	suggestions []typeSuggestion
+}
+
+type typeSuggestion struct {
+	typeName   string
+	confidence float64
+	reasons    []string
+}
+
+func renderSuggestType(_ *cobra.Command, result commandexec.Result) error {
+	printStaleIndexWarning(result.Meta)
+
+	pages := typeSuggestionPagesFromAny(canonicalDataMap(result)["pages"])
+	if len(pages) == 0 {
+		fmt.Println(ui.Starf("No untyped pages found"))
+		return nil
+	}
+
+	interaction := newCheckInteraction(os.Stdin, os.Stdout)
+	if !shouldPromptForConfirm() {
+		for _, page := range pages {
+			printTypeSuggestions(interaction, page)
+		}
+		return nil
+	}
+
+	accepted := acceptTypeSuggestions(interaction, getVaultPath(), pages, executeCanonicalRequest)
+	if accepted > 0 {
+		fmt.Println()
+		fmt.Println(ui.Checkf("Reclassified %d page(s)", accepted))
+	}
+	return nil
+}
+
+func printTypeSuggestions(interaction checkInteraction, page typeSuggestionPage) {
+	interaction.Println()
+	interaction.Println(ui.SectionHeader(page.objectID))
+	if len(page.suggestions) == 0 {
+		interaction.Println(ui.Muted.Render("  No type suggestions"))
+		return
+	}
+	for i, suggestion := range page.suggestions {
+		interaction.Printf("  %d. %s %s\n", i+1, ui.Bold.Render(suggestion.typeName), ui.Muted.Render(fmt.Sprintf("(%.0f%%)", suggestion.confidence*100)))
+		for _, reason := range suggestion.reasons {
+			interaction.Println(ui.Muted.Render("     " + reason))
+		}
+	}
+}
+
+// acceptTypeSuggestions shows each page's suggestions and reclassifies it on
+// a single keystroke: Enter takes the top suggestion, a number picks another.
+// It returns the number of pages reclassified.
+func acceptTypeSuggestions(interaction checkInteraction, vaultPath string, pages []typeSuggestionPage, run func(commandexec.Request) commandexec.Result) int {
+	accepted := 0
+	for _, page := range pages {
+		printTypeSuggestions(interaction, page)
+		if len(page.suggestions) == 0 {
+			continue
+		}
+
+		for {
+			interaction.Printf("  %s ", ui.Hint(fmt.Sprintf("[Enter] accept %s, [1-%d] choose, [s]kip, [q]uit", page.suggestions[0].typeName, len(page.suggestions))))
+			line, err := interaction.ReadLine()
+			if err != nil && strings.TrimSpace(line) == "" {
+				return accepted
+			}
+			answer := strings.ToLower(strings.TrimSpace(line))
+
+			choice := 0
+			switch answer {
+			case "", "y", "yes":
+				choice = 1
+			case "s", "skip", "n", "no":
+			case "q", "quit":
+				return accepted
+			default:
+				n, convErr := strconv.Atoi(answer)
+				if convErr != nil || n < 1 || n > len(page.suggestions) {
+					interaction.Println(ui.Errorf("Enter a number between 1 and %d", len(page.suggestions)))
+					continue
+				}
+				choice = n
+			}
+			if choice == 0 {
+				break
+			}
+
+			typeName := page.suggestions[choice-1].typeName
+			result, applied := runInteractiveReclassify(interaction, run, vaultPath, page.objectID, typeName)
+			if !result.OK {
+				interaction.Println(ui.Errorf("%s", canonicalFailureMessage(result)))
+				if result.Error != nil && result.Error.Code == ErrRequiredFieldMissing {
+					interaction.Println(ui.Hint(fmt.Sprintf("Run 'rvn reclassify %s %s' to fill in required fields", page.objectID, typeName)))
+				}
+				break
+			}
+			if !applied {
+				break
+			}
+			interaction.Println(ui.Checkf("Reclassified %s as %s", page.objectID, typeName))
+			accepted++
+			break
+		}
+	}
+	return accepted
+}
+
+func typeSuggestionPagesFromAny(raw interface{}) []typeSuggestionPage {
+	var pages []typeSuggestionPage
+	for _, entry := range editItems(raw) {
+		page := typeSuggestionPage{
+			objectID: stringValue(entry["object_id"]),
+			filePath: stringValue(entry["file_path"]),
+		}
+		for _, rawSuggestion := range editItems(entry["suggestions"]) {
+			confidence, _ := rawSuggestion["confidence"].(float64)
+			page.suggestions = append(page.suggestions, typeSuggestion{
+				typeName:   stringValue(rawSuggestion["type"]),
+				confidence: confidence,
+				reasons:    stringSliceFromAny(rawSuggestion["reasons"]),
+			})
+		}
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+func init() {
+	suggestTypeCmd.ValidArgsFunction = completeReferenceArgAt(0, referenceCompletionOptions{
+		NonTargetDirective: cobra.ShellCompDirectiveNoFileComp,
+	})
+	rootCmd.AddCommand(suggestTypeCmd)
+}
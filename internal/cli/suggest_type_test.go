@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+)
+
+func TestAcceptTypeSuggestions(t *testing.T) {
+	pages := []typeSuggestionPage{
+		{objectID: "inbox/website", suggestions: []typeSuggestion{{typeName: "project", confidence: 0.8}, {typeName: "meeting", confidence: 0.3}}},
+		{objectID: "inbox/empty"},
+		{objectID: "inbox/freya", suggestions: []typeSuggestion{{typeName: "person", confidence: 0.5}, {typeName: "company", confidence: 0.4}}},
+		{objectID: "inbox/misc", suggestions: []typeSuggestion{{typeName: "note", confidence: 0.2}}},
+		{objectID: "inbox/never", suggestions: []typeSuggestion{{typeName: "note", confidence: 0.2}}},
+	}
+	// Enter accepts the top suggestion; "9" is out of range and re-prompts.
+	interaction := &fakeCheckInteraction{inputs: []string{"", "9", "2", "s", "q"}}
+
+	var got []string
+	run := func(req commandexec.Request) commandexec.Result {
+		if req.CommandID != "reclassify" || req.VaultPath != "/vault" {
+			t.Fatalf("unexpected request: %#v", req)
+		}
+		got = append(got, req.Args["object"].(string)+"="+req.Args["new-type"].(string))
+		return commandexec.Success(map[string]interface{}{}, nil)
+	}
+
+	accepted := acceptTypeSuggestions(interaction, "/vault", pages, run)
+	if accepted != 2 {
+		t.Fatalf("accepted = %d, want 2", accepted)
+	}
+	want := []string{"inbox/website=project", "inbox/freya=company"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("reclassified = %v, want %v", got, want)
+	}
+}
+
+func TestTypeSuggestionPagesFromAny(t *testing.T) {
+	raw := []map[string]interface{}{
+		{
+			"object_id": "inbox/loki",
+			"file_path": "inbox/loki.md",
+			"suggestions": []map[string]interface{}{
+				{"type": "person", "confidence": 0.25, "reasons": []string{"fields: email"}},
+			},
+		},
+	}
+	pages := typeSuggestionPagesFromAny(raw)
+	want := []typeSuggestionPage{{
+		objectID:    "inbox/loki",
+		filePath:    "inbox/loki.md",
+		suggestions: []typeSuggestion{{typeName: "person", confidence: 0.25, reasons: []string{"fields: email"}}},
+	}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Fatalf("pages = %#v, want %#v", pages, want)
+	}
+}
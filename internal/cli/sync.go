@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync the vault with git and external systems",
+	Long: `Sync the vault with a git remote or with external systems.
+
+Use 'rvn sync git' to commit vault changes and pull/push the git remote, and
+'rvn sync external <name>' to run one sync configured under sync: in raven.yaml.`,
+}
+
+var syncExternalCmd = newCanonicalLeafCommand("sync_external", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderSyncExternal,
+})
+
+var syncGitCmd = newCanonicalLeafCommand("sync_git", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderSyncGit,
+})
+
+func renderSyncGit(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	changes := editItems(data["changes"])
+	dryRun := boolValue(data["dry_run"])
+	remote := stringValue(data["remote"])
+
+	if len(changes) == 0 {
+		fmt.Println(ui.Starf("No uncommitted changes in the vault"))
+	} else {
+		if dryRun {
+			fmt.Println(ui.SectionHeader(fmt.Sprintf("Would commit %d file(s) — dry run", len(changes))))
+		} else {
+			fmt.Println(ui.Checkf("Committed %d file(s) as %s", len(changes), stringValue(data["commit"])))
+		}
+		for _, change := range changes {
+			label := stringValue(change["path"])
+			if objectID := stringValue(change["object_id"]); objectID != "" {
+				label = objectID + " " + ui.Muted.Render(label)
+			}
+			fmt.Printf("  %-9s %s\n", stringValue(change["status"]), label)
+		}
+		if dryRun {
+			fmt.Println()
+			fmt.Println(ui.Hint("Message: " + strings.SplitN(stringValue(data["message"]), "\n", 2)[0]))
+		}
+	}
+	if dryRun {
+		return nil
+	}
+
+	if remote == "" {
+		fmt.Println(ui.Hint("No git remote configured; nothing was pulled or pushed."))
+		return nil
+	}
+	if pulled := len(stringSliceFromAny(data["pulled_files"])); pulled > 0 {
+		fmt.Println(ui.Checkf("Pulled %d changed file(s) from %s/%s", pulled, remote, stringValue(data["branch"])))
+	}
+	if boolValue(data["pushed"]) {
+		fmt.Println(ui.Checkf("Pushed to %s/%s", remote, stringValue(data["branch"])))
+	}
+	return nil
+}
+
+func renderSyncExternal(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	records := editItems(data["records"])
+	dryRun := boolValue(data["dry_run"])
+
+	title := fmt.Sprintf("%s (%s → %s)", stringValue(data["name"]), stringValue(data["adapter"]), stringValue(data["type"]))
+	if dryRun {
+		title += " — dry run"
+	}
+	fmt.Println(ui.SectionHeader(title))
+
+	shown := 0
+	for _, record := range records {
+		action := stringValue(record["action"])
+		if action == "unchanged" {
+			continue
+		}
+		shown++
+		label := stringValue(record["external_id"])
+		if objectID := stringValue(record["object_id"]); objectID != "" {
+			label += " " + ui.Muted.Render(objectID)
+		}
+		fmt.Printf("  %-9s %s\n", action, label)
+		for _, change := range editItems(record["pulled"]) {
+			fmt.Printf("            %s %s\n", ui.Muted.Render("← "+stringValue(change["field"])), stringValue(change["remote"]))
+		}
+		for _, change := range editItems(record["pushed"]) {
+			fmt.Printf("            %s %s\n", ui.Muted.Render("→ "+stringValue(change["field"])), stringValue(change["local"]))
+		}
+		for _, change := range editItems(record["conflicts"]) {
+			fmt.Printf("            %s local %q, remote %q\n", ui.Errorf("≠ %s", stringValue(change["field"])), stringValue(change["local"]), stringValue(change["remote"]))
+		}
+		if message := stringValue(record["error"]); message != "" {
+			fmt.Printf("            %s\n", ui.Errorf("%s", message))
+		}
+	}
+	if shown == 0 {
+		fmt.Println(ui.Starf("Everything is in sync (%d record(s))", len(records)))
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println(ui.Hint(fmt.Sprintf("%d created, %d pulled, %d pushed, %d conflict(s), %d failed",
+		intFromAny(data["created"]), intFromAny(data["pulled"]), intFromAny(data["pushed"]),
+		intFromAny(data["conflicts"]), intFromAny(data["failed"]))))
+	if intFromAny(data["conflicts"]) > 0 {
+		fmt.Println(ui.Hint("Resolve conflicts by editing either side, or rerun with --prefer local|remote."))
+	}
+	return nil
+}
+
+func init() {
+	syncCmd.AddCommand(syncExternalCmd)
+	syncCmd.AddCommand(syncGitCmd)
+	rootCmd.AddCommand(syncCmd)
+}
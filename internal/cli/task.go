@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Review and update tasks",
+	Long: `Work with tasks: lines carrying the task trait (default @todo) and an
+optional due date trait (default @due).
+
+  rvn task list
+  rvn task done daily/2026-01-25.md:7
+  rvn task snooze daily/2026-01-25.md:7 --by 3d
+  rvn task schedule daily/2026-01-25.md:7 every:friday`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var taskListCmd = newCanonicalLeafCommand("task_list", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderTaskList,
+})
+
+var taskDoneCmd = newCanonicalLeafCommand("task_done", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderTaskEditResult,
+})
+
+var taskSnoozeCmd = newCanonicalLeafCommand("task_snooze", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderTaskEditResult,
+})
+
+var taskScheduleCmd = newCanonicalLeafCommand("task_schedule", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderTaskEditResult,
+})
+
+func renderTaskList(_ *cobra.Command, result commandexec.Result) error {
+	printStaleIndexWarning(result.Meta)
+
+	data := canonicalDataMap(result)
+	groups := itemMapsFromAny(data["groups"])
+	if len(groups) == 0 {
+		fmt.Println(ui.Hint(fmt.Sprintf("No open tasks. Mark a line with @%s to add one.", stringValue(data["trait"]))))
+		return nil
+	}
+
+	printTaskGroups(groups)
+	return nil
+}
+
+func printTaskGroups(groups []map[string]interface{}) {
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Println()
+		}
+		tasks := itemMapsFromAny(group["tasks"])
+		fmt.Printf("%s %s\n", ui.SectionHeader(taskGroupTitle(stringValue(group["key"]))), ui.Badge(fmt.Sprintf("%d", len(tasks))))
+		for _, task := range tasks {
+			line := strings.TrimSpace(stringValue(task["content"]))
+			if status := stringValue(task["status"]); status != "" {
+				line = fmt.Sprintf("%s %s", ui.Muted.Render("["+status+"]"), line)
+			}
+			if rule := stringValue(task["recurrence"]); rule != "" {
+				line += " " + ui.Muted.Render("↻ "+rule)
+			}
+			location := fmt.Sprintf("%s:%d", stringValue(task["file_path"]), intFromAny(task["line"]))
+			fmt.Printf("  %s\n    %s\n", line, ui.FilePath(location))
+		}
+	}
+}
+
+func taskNoun(n int) string {
+	if n == 1 {
+		return "task"
+	}
+	return "tasks"
+}
+
+func taskGroupTitle(key string) string {
+	switch key {
+	case "overdue":
+		return "Overdue"
+	case "no_date":
+		return "No date"
+	default:
+		return key
+	}
+}
+
+func renderTaskEditResult(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	location := fmt.Sprintf("%s:%d", stringValue(data["file"]), intFromAny(data["line"]))
+	action := stringValue(data["action"])
+	if !boolValue(data["changed"]) {
+		fmt.Println(ui.Hint(fmt.Sprintf("Task on %s is already %s", location, stringValue(data["new_value"]))))
+		return nil
+	}
+	if boolValue(data["preview"]) {
+		fmt.Printf("%s %s\n\n", ui.SectionHeader("Preview task update"), ui.FilePath(location))
+		fmt.Println(ui.Muted.Render("BEFORE:"))
+		fmt.Println(indent(stringValue(data["before"]), "  "))
+		fmt.Println()
+		fmt.Println(ui.Bold.Render("AFTER:"))
+		fmt.Println(indent(stringValue(data["after"]), "  "))
+		fmt.Println()
+		fmt.Println(ui.Hint("Dry run: re-run without --dry-run to apply this update"))
+		return nil
+	}
+
+	switch action {
+	case "done", "cancelled":
+		fmt.Println(ui.Checkf("Marked task %s on %s", action, ui.FilePath(location)))
+	default:
+		fmt.Println(ui.Checkf("Task %s on %s", action, ui.FilePath(location)))
+		if oldValue := stringValue(data["old_value"]); oldValue != "" {
+			fmt.Printf("  @%s %s → %s\n", stringValue(data["trait_type"]), ui.Muted.Render(oldValue), stringValue(data["new_value"]))
+		} else {
+			fmt.Printf("  @%s %s\n", stringValue(data["trait_type"]), stringValue(data["new_value"]))
+		}
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("  %s\n", ui.Warning(warning.Message))
+	}
+	return nil
+}
+
+func init() {
+	taskCmd.AddCommand(taskListCmd)
+	taskCmd.AddCommand(taskDoneCmd)
+	taskCmd.AddCommand(taskSnoozeCmd)
+	taskCmd.AddCommand(taskScheduleCmd)
+	rootCmd.AddCommand(taskCmd)
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+)
+
+var toggleCmd = newCanonicalLeafCommand("toggle", canonicalLeafOptions{
+	VaultPath: getVaultPath,
+	Args:      cobra.ArbitraryArgs,
+	BuildArgs: withUnlockArg(buildToggleArgs),
+	Invoke:    invokeSet,
+	RenderHuman: func(cmd *cobra.Command, result commandexec.Result) error {
+		if stdin, _ := cmd.Flags().GetBool("stdin"); stdin {
+			return renderCanonicalBulkResult(result)
+		}
+		return renderCanonicalSetSingleResult(result)
+	},
+})
+
+func buildToggleArgs(cmd *cobra.Command, args []string) (map[string]interface{}, error) {
+	stdin, _ := cmd.Flags().GetBool("stdin")
+
+	if stdin {
+		if len(args) != 1 {
+			return nil, handleErrorMsg(ErrMissingArgument, "requires exactly one field", "Usage: rvn toggle --stdin <field>")
+		}
+		fileIDs, sectionIDs, err := ReadIDsFromStdin()
+		if err != nil {
+			return nil, handleError(ErrInternal, err, "")
+		}
+		ids := append(fileIDs, sectionIDs...)
+		if len(ids) == 0 {
+			return nil, handleErrorMsg(ErrMissingArgument, "no object IDs provided via stdin", "Pipe object IDs to stdin, one per line")
+		}
+		return map[string]interface{}{
+			"stdin":      true,
+			"object_ids": stringsToAny(ids),
+			"field":      args[0],
+		}, nil
+	}
+
+	if len(args) != 2 {
+		return nil, handleErrorMsg(ErrMissingArgument, "requires object-id and field", "Usage: rvn toggle <object-id> <field>")
+	}
+	return map[string]interface{}{
+		"object_id": args[0],
+		"field":     args[1],
+	}, nil
+}
+
+func init() {
+	toggleCmd.ValidArgsFunction = completeReferenceArgAt(0, referenceCompletionOptions{
+		IncludeDynamicDates: false,
+		DisableWhenStdin:    true,
+		NonTargetDirective:  cobra.ShellCompDirectiveNoFileComp,
+	})
+	rootCmd.AddCommand(toggleCmd)
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var traitCmd = &cobra.Command{
+	Use:   "trait",
+	Short: "Edit individual trait annotations",
+	Long: `Edit one trait annotation addressed by file and line or by trait ID.
+
+  rvn trait set daily/2026-01-25.md:7 done
+  rvn trait set projects/website.md:12 2026-03-01 --trait due`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var traitSetCmd = newCanonicalLeafCommand("trait_set", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	BuildArgs:   withUnlockArg(buildTraitSetArgs),
+	RenderHuman: renderTraitSetResult,
+})
+
+func buildTraitSetArgs(cmd *cobra.Command, args []string) (map[string]interface{}, error) {
+	argsMap := map[string]interface{}{
+		"anchor": args[0],
+		"value":  args[1],
+	}
+	if trait, _ := cmd.Flags().GetString("trait"); trait != "" {
+		argsMap["trait"] = trait
+	}
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		argsMap["dry-run"] = true
+	}
+	return argsMap, nil
+}
+
+func renderTraitSetResult(_ *cobra.Command, result commandexec.Result) error {
+	data := canonicalDataMap(result)
+	location := fmt.Sprintf("%s:%d", stringValue(data["file"]), intFromAny(data["line"]))
+	if !boolValue(data["changed"]) {
+		fmt.Println(ui.Hint(fmt.Sprintf("@%s on %s already has value %s", stringValue(data["trait_type"]), location, stringValue(data["new_value"]))))
+		return nil
+	}
+	if boolValue(data["preview"]) {
+		fmt.Printf("%s %s\n\n", ui.SectionHeader("Preview trait update"), ui.FilePath(location))
+		fmt.Println(ui.Muted.Render("BEFORE:"))
+		fmt.Println(indent(stringValue(data["before"]), "  "))
+		fmt.Println()
+		fmt.Println(ui.Bold.Render("AFTER:"))
+		fmt.Println(indent(stringValue(data["after"]), "  "))
+		fmt.Println()
+		fmt.Println(ui.Hint("Dry run: re-run without --dry-run to apply this update"))
+		return nil
+	}
+
+	fmt.Println(ui.Checkf("Updated @%s on %s", stringValue(data["trait_type"]), ui.FilePath(location)))
+	if oldValue := stringValue(data["old_value"]); oldValue != "" {
+		fmt.Printf("  %s → %s\n", ui.Muted.Render(oldValue), stringValue(data["new_value"]))
+	} else {
+		fmt.Printf("  %s\n", stringValue(data["new_value"]))
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("  %s\n", ui.Warning(warning.Message))
+	}
+	return nil
+}
+
+func init() {
+	traitCmd.AddCommand(traitSetCmd)
+	rootCmd.AddCommand(traitCmd)
+}
@@ -14,7 +14,7 @@ import (
 var unsetCmd = newCanonicalLeafCommand("unset", canonicalLeafOptions{
 	VaultPath: getVaultPath,
 	Args:      cobra.ArbitraryArgs,
-	BuildArgs: buildUnsetArgs,
+	BuildArgs: withUnlockArg(buildUnsetArgs),
 	RenderHuman: func(_ *cobra.Command, result commandexec.Result) error {
 		return renderCanonicalUnsetResult(result)
 	},
@@ -12,7 +12,7 @@ import (
 var updateCmd = newCanonicalLeafCommand("update", canonicalLeafOptions{
 	VaultPath:   getVaultPath,
 	Args:        cobra.ArbitraryArgs,
-	BuildArgs:   buildUpdateArgs,
+	BuildArgs:   withUnlockArg(buildUpdateArgs),
 	Invoke:      invokeUpdate,
 	RenderHuman: renderUpdateResult,
 })
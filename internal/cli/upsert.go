@@ -22,7 +22,7 @@ var (
 
 var upsertCmd = newCanonicalLeafCommand("upsert", canonicalLeafOptions{
 	VaultPath:       getVaultPath,
-	BuildArgs:       buildUpsertArgs,
+	BuildArgs:       withUnlockArg(buildUpsertArgs),
 	RenderHuman:     renderUpsertResult,
 	SkipFlagBinding: true,
 })
@@ -247,6 +247,28 @@ func renderVaultConfigShow(_ *cobra.Command, result commandexec.Result) error {
 			fmt.Println(ui.Bullet(pattern))
 		}
 	}
+
+	if sparse := stringSliceFromAny(data["sparse_paths"]); len(sparse) > 0 {
+		absent := make(map[string]bool)
+		for _, root := range stringSliceFromAny(data["absent_sparse_paths"]) {
+			absent[root] = true
+		}
+		fmt.Println(ui.SectionHeader("sparse_paths"))
+		for _, root := range sparse {
+			if absent[root] {
+				fmt.Println(ui.Bullet(root + " " + ui.Hint("(not checked out)")))
+			} else {
+				fmt.Println(ui.Bullet(root))
+			}
+		}
+	}
+
+	if locked := stringSliceFromAny(data["locked_files"]); len(locked) > 0 {
+		fmt.Println(ui.SectionHeader("locked_files"))
+		for _, path := range locked {
+			fmt.Println(ui.Bullet(path))
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+)
+
+var vaultEncryptCmd = newCanonicalLeafCommand("vault_encrypt", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderVaultEncryption("Encrypted", "encrypt"),
+})
+
+var vaultDecryptCmd = newCanonicalLeafCommand("vault_decrypt", canonicalLeafOptions{
+	VaultPath:   getVaultPath,
+	RenderHuman: renderVaultEncryption("Decrypted", "decrypt"),
+})
+
+// renderVaultEncryption renders vault encrypt and decrypt results: the files
+// rewritten, or in a preview the files that would be.
+func renderVaultEncryption(done, verb string) func(*cobra.Command, commandexec.Result) error {
+	return func(_ *cobra.Command, result commandexec.Result) error {
+		data := canonicalDataMap(result)
+		dir := stringValue(data["directory"])
+		files := stringSliceFromAny(data["files"])
+		noun := "files"
+		if len(files) == 1 {
+			noun = "file"
+		}
+
+		if boolValue(data["preview"]) {
+			if len(files) == 0 {
+				fmt.Println(ui.Starf("No files to %s in %s", verb, ui.FilePath(dir)))
+			} else {
+				fmt.Printf("%s %s\n\n", ui.SectionHeader(fmt.Sprintf("Would %s %s", verb, dir)), ui.Badge(fmt.Sprintf("%d", len(files))))
+				for _, file := range files {
+					fmt.Println(ui.Bullet(ui.FilePath(file)))
+				}
+				fmt.Println()
+			}
+			fmt.Println(ui.Hint("Run with --confirm to apply changes."))
+			return nil
+		}
+
+		fmt.Println(ui.Checkf("%s %d %s in %s", done, len(files), noun, ui.FilePath(dir)))
+		for _, warning := range result.Warnings {
+			fmt.Println(ui.Warning(warning.Message))
+		}
+		return nil
+	}
+}
+
+func init() {
+	vaultCmd.AddCommand(vaultEncryptCmd)
+	vaultCmd.AddCommand(vaultDecryptCmd)
+}
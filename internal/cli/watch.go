@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/ui"
+	"github.com/aidanlsb/raven/internal/watchsvc"
+)
+
+var watchDebounce time.Duration
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Reindex changed files as they are saved",
+	Long: `Run until interrupted, reindexing files as they change.
+
+Rapid saves are debounced into one incremental reindex. While running, the
+watcher serves index freshness at GET /status on the unix socket
+.raven/watch.sock, which the MCP server exposes as raven://index/status.
+
+Examples:
+  rvn watch
+  rvn watch --debounce 1s
+  rvn watch --json          # One JSON line per reindex`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vaultPath := getVaultPath()
+		if watchDebounce <= 0 {
+			return handleErrorMsg(ErrInvalidInput, "--debounce must be positive", "Pass a duration such as 300ms or 1s")
+		}
+
+		watcher := watchsvc.New(watchsvc.Options{
+			VaultPath: vaultPath,
+			Debounce:  watchDebounce,
+			OnReindex: printWatchEvent,
+		})
+		closeSocket, err := watcher.Serve()
+		if err != nil {
+			return handleErrorMsg(ErrInvalidInput, err.Error(), "Stop the other 'rvn watch' for this vault first")
+		}
+		defer closeSocket()
+
+		if !isJSONOutput() {
+			fmt.Println(ui.Hint(fmt.Sprintf("Watching %s (Ctrl+C to stop)", vaultPath)))
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := watcher.Run(ctx); err != nil {
+			return handleError(ErrDatabaseError, err, "Run 'rvn reindex' to see the error")
+		}
+		return nil
+	},
+}
+
+// printWatchEvent reports one reindex: a line per run in human output, or one
+// compact JSON envelope per run with --json.
+func printWatchEvent(event watchsvc.Event) {
+	if isJSONOutput() {
+		var result commandexec.Result
+		if event.Err != nil {
+			result = commandexec.Failure(ErrDatabaseError, event.Err.Error(), nil, "")
+		} else {
+			data := event.Result.Data()
+			changed := event.Changed
+			if changed == nil {
+				changed = []string{}
+			}
+			data["changed"] = changed
+			result = commandexec.Success(data, nil)
+		}
+		line, err := json.Marshal(result)
+		if err != nil {
+			return
+		}
+		fmt.Println(string(line))
+		return
+	}
+
+	stamp := ui.Muted.Render(event.At.Format("15:04:05"))
+	if event.Err != nil {
+		fmt.Fprintf(os.Stderr, "%s %s\n", stamp, ui.Errorf("reindex failed: %v", event.Err))
+		return
+	}
+	result := event.Result
+	if event.Changed == nil {
+		fmt.Printf("%s %s\n", stamp, ui.Checkf("Index up to date %s", ui.Hint(fmt.Sprintf("(%d files indexed)", result.FilesIndexed))))
+		return
+	}
+	summary := fmt.Sprintf("Indexed %d, removed %d", result.FilesIndexed, result.FilesDeleted)
+	fmt.Printf("%s %s %s\n", stamp, ui.Check(summary), ui.Hint(watchChangedLabel(event.Changed)))
+	for _, message := range result.WarningMessages {
+		fmt.Fprintln(os.Stderr, ui.Warning(message))
+	}
+}
+
+func watchChangedLabel(changed []string) string {
+	const shown = 3
+	if len(changed) <= shown {
+		return strings.Join(changed, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(changed[:shown], ", "), len(changed)-shown)
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", watchsvc.DefaultDebounce, "Wait this long after the last change before reindexing")
+	markLocalLeaf(watchCmd)
+	rootCmd.AddCommand(watchCmd)
+}
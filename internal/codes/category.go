@@ -0,0 +1,67 @@
+package codes
+
+// Category groups error codes by who can act on them.
+type Category string
+
+const (
+	// CategoryUser covers bad input: unknown objects, invalid arguments, ambiguous refs.
+	CategoryUser Category = "user"
+	// CategoryConfig covers vault resolution and configuration problems.
+	CategoryConfig Category = "config"
+	// CategorySchema covers schema definitions and schema-driven validation.
+	CategorySchema Category = "schema"
+	// CategoryInternal covers storage, index, and execution failures.
+	CategoryInternal Category = "internal"
+)
+
+var errorCategories = map[ErrorCode]Category{
+	ErrVaultNotFound:          CategoryConfig,
+	ErrVaultNotSpecified:      CategoryConfig,
+	ErrVaultResolution:        CategoryConfig,
+	ErrConfigInvalid:          CategoryConfig,
+	ErrMCPClientInvalid:       CategoryConfig,
+	ErrExecutableRequired:     CategoryConfig,
+	ErrSkillTargetUnsupported: CategoryConfig,
+	ErrSkillPathUnresolved:    CategoryConfig,
+	ErrSkillReceiptInvalid:    CategoryConfig,
+	ErrPolicyDenied:           CategoryConfig,
+
+	ErrSchemaNotFound:       CategorySchema,
+	ErrSchemaInvalid:        CategorySchema,
+	ErrSchemaMismatch:       CategorySchema,
+	ErrTypeNotFound:         CategorySchema,
+	ErrTraitNotFound:        CategorySchema,
+	ErrFieldNotFound:        CategorySchema,
+	ErrDataIntegrityBlock:   CategorySchema,
+	ErrValidationFailed:     CategorySchema,
+	ErrRequiredFieldMissing: CategorySchema,
+	ErrInvalidValue:         CategorySchema,
+	ErrUnknownField:         CategorySchema,
+
+	ErrFileRead:          CategoryInternal,
+	ErrFileWrite:         CategoryInternal,
+	ErrDatabase:          CategoryInternal,
+	ErrDatabaseVersion:   CategoryInternal,
+	ErrSkillRenderFailed: CategoryInternal,
+	ErrMCPConfigWrite:    CategoryInternal,
+	ErrExecutionFailed:   CategoryInternal,
+	ErrExecutionError:    CategoryInternal,
+	ErrToolReturnedError: CategoryInternal,
+	ErrFetchFailed:       CategoryInternal,
+	ErrGitFailed:         CategoryInternal,
+	ErrInternal:          CategoryInternal,
+	ErrNotImplemented:    CategoryInternal,
+}
+
+// CategoryOf returns the stable category for an error code.
+// Documented codes without an explicit mapping are user errors; unknown
+// codes are treated as internal.
+func CategoryOf(code ErrorCode) Category {
+	if category, ok := errorCategories[code]; ok {
+		return category
+	}
+	if _, ok := knownErrorCodes[code]; ok {
+		return CategoryUser
+	}
+	return CategoryInternal
+}
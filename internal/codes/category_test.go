@@ -0,0 +1,46 @@
+package codes
+
+import "testing"
+
+func TestEveryKnownErrorCodeHasCategory(t *testing.T) {
+	t.Parallel()
+
+	valid := map[Category]struct{}{
+		CategoryUser:     {},
+		CategoryConfig:   {},
+		CategorySchema:   {},
+		CategoryInternal: {},
+	}
+	for code := range knownErrorCodes {
+		if _, ok := valid[CategoryOf(code)]; !ok {
+			t.Errorf("CategoryOf(%q) = %q, want a known category", code, CategoryOf(code))
+		}
+	}
+	for code := range errorCategories {
+		if !IsErrorCode(string(code)) {
+			t.Errorf("category mapping for undocumented code %q", code)
+		}
+	}
+}
+
+func TestCategoryOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code ErrorCode
+		want Category
+	}{
+		{code: ErrRefAmbiguous, want: CategoryUser},
+		{code: ErrInvalidArgs, want: CategoryUser},
+		{code: ErrVaultNotFound, want: CategoryConfig},
+		{code: ErrRequiredFieldMissing, want: CategorySchema},
+		{code: ErrTypeNotFound, want: CategorySchema},
+		{code: ErrDatabase, want: CategoryInternal},
+		{code: ErrorCode("NOT_A_CODE"), want: CategoryInternal},
+	}
+	for _, tc := range tests {
+		if got := CategoryOf(tc.code); got != tc.want {
+			t.Errorf("CategoryOf(%q) = %q, want %q", tc.code, got, tc.want)
+		}
+	}
+}
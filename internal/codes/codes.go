@@ -38,8 +38,14 @@ const (
 	ErrFileRead         ErrorCode = "FILE_READ_ERROR"
 	ErrFileWrite        ErrorCode = "FILE_WRITE_ERROR"
 	ErrFileOutsideVault ErrorCode = "FILE_OUTSIDE_VAULT"
+	ErrFileLocked       ErrorCode = "FILE_LOCKED"
 	ErrDatabase         ErrorCode = "DATABASE_ERROR"
 	ErrDatabaseVersion  ErrorCode = "DATABASE_VERSION_MISMATCH"
+	ErrIndexStale       ErrorCode = "INDEX_STALE"
+	ErrUndoConflict     ErrorCode = "UNDO_CONFLICT"
+	ErrGitFailed        ErrorCode = "GIT_FAILED"
+	ErrMergeConflict    ErrorCode = "MERGE_CONFLICT"
+	ErrPreviewStale     ErrorCode = "PREVIEW_STALE"
 
 	// Validation/input errors.
 	ErrValidationFailed     ErrorCode = "VALIDATION_FAILED"
@@ -81,6 +87,9 @@ const (
 	ErrToolReturnedError  ErrorCode = "TOOL_RETURNED_ERROR"
 	ErrFetchFailed        ErrorCode = "FETCH_FAILED"
 	ErrCancelled          ErrorCode = "CANCELLED"
+	ErrPolicyDenied       ErrorCode = "POLICY_DENIED"
+	ErrPreviewRequired    ErrorCode = "PREVIEW_REQUIRED"
+	ErrUnauthorized       ErrorCode = "UNAUTHORIZED"
 
 	// General errors.
 	ErrInternal       ErrorCode = "INTERNAL_ERROR"
@@ -103,24 +112,32 @@ const (
 	WarnOrphanedFiles     WarningCode = "ORPHANED_FILES"
 	WarnOrphanedTraits    WarningCode = "ORPHANED_TRAITS"
 	WarnCheckIncomplete   WarningCode = "CHECK_APPLY_INCOMPLETE"
+	WarnDegradedSearch    WarningCode = "DEGRADED_SEARCH"
+	WarnIssueFetchFailed  WarningCode = "ISSUE_FETCH_FAILED"
+	WarnHistoryNotSaved   WarningCode = "HISTORY_NOT_RECORDED"
+	WarnProfileStale      WarningCode = "PROFILE_STALE"
+	WarnIndexNotEncrypted WarningCode = "INDEX_NOT_ENCRYPTED"
+	WarnGitCommitFailed   WarningCode = "GIT_COMMIT_FAILED"
+	WarnMergeConflict     WarningCode = "MERGE_CONFLICT"
 )
 
 var knownErrorCodes = map[ErrorCode]struct{}{
 	ErrVaultNotFound: {}, ErrVaultNotSpecified: {}, ErrVaultResolution: {}, ErrConfigInvalid: {},
 	ErrSchemaNotFound: {}, ErrSchemaInvalid: {}, ErrSchemaMismatch: {}, ErrTypeNotFound: {}, ErrTraitNotFound: {}, ErrFieldNotFound: {}, ErrDataIntegrityBlock: {}, ErrConfirmationRequired: {},
 	ErrObjectNotFound: {}, ErrObjectExists: {}, ErrObjectInvalid: {}, ErrRefNotFound: {}, ErrRefInvalid: {}, ErrRefAmbiguous: {},
-	ErrFileNotFound: {}, ErrFileExists: {}, ErrFileRead: {}, ErrFileWrite: {}, ErrFileOutsideVault: {}, ErrDatabase: {}, ErrDatabaseVersion: {},
+	ErrFileNotFound: {}, ErrFileExists: {}, ErrFileRead: {}, ErrFileWrite: {}, ErrFileOutsideVault: {}, ErrFileLocked: {}, ErrDatabase: {}, ErrDatabaseVersion: {}, ErrIndexStale: {}, ErrUndoConflict: {}, ErrGitFailed: {}, ErrMergeConflict: {}, ErrPreviewStale: {},
 	ErrValidationFailed: {}, ErrRequiredFieldMissing: {}, ErrInvalidValue: {}, ErrUnknownField: {}, ErrInvalidInput: {}, ErrInvalidArgs: {}, ErrMissingArgument: {}, ErrCommandNotFound: {}, ErrCommandNotInvokable: {}, ErrDuplicateName: {}, ErrPrefixNotFound: {}, ErrStringNotFound: {}, ErrMultipleMatches: {}, ErrNotFound: {},
 	ErrQueryNotFound: {}, ErrQueryInvalid: {}, ErrQueryFailed: {},
 	ErrSkillNotFound: {}, ErrSkillNotInstalled: {}, ErrSkillTargetUnsupported: {}, ErrSkillRenderFailed: {}, ErrSkillPathUnresolved: {}, ErrSkillReceiptInvalid: {},
-	ErrMCPClientInvalid: {}, ErrMCPConfigWrite: {}, ErrExecutableRequired: {}, ErrUnknownTool: {}, ErrExecutionFailed: {}, ErrExecutionError: {}, ErrInvalidJSON: {}, ErrToolReturnedError: {}, ErrFetchFailed: {}, ErrCancelled: {},
+	ErrMCPClientInvalid: {}, ErrMCPConfigWrite: {}, ErrExecutableRequired: {}, ErrUnknownTool: {}, ErrExecutionFailed: {}, ErrExecutionError: {}, ErrInvalidJSON: {}, ErrToolReturnedError: {}, ErrFetchFailed: {}, ErrCancelled: {}, ErrPolicyDenied: {}, ErrPreviewRequired: {}, ErrUnauthorized: {},
 	ErrInternal: {}, ErrNotImplemented: {},
 }
 
 var knownWarningCodes = map[WarningCode]struct{}{
 	WarnRefNotFound: {}, WarnDeprecated: {}, WarnSchemaOutdated: {}, WarnDatabaseOutdated: {}, WarnIndexUpdateFailed: {}, WarnDocsFetchFailed: {},
 	WarnWrongCommand: {}, WarnMissingField: {}, WarnBacklinks: {}, WarnSectionSkipped: {}, WarnUnknownField: {}, WarnTypeMismatch: {},
-	WarnOrphanedFiles: {}, WarnOrphanedTraits: {}, WarnCheckIncomplete: {},
+	WarnOrphanedFiles: {}, WarnOrphanedTraits: {}, WarnCheckIncomplete: {}, WarnDegradedSearch: {}, WarnIssueFetchFailed: {}, WarnHistoryNotSaved: {},
+	WarnProfileStale: {}, WarnIndexNotEncrypted: {}, WarnGitCommitFailed: {}, WarnMergeConflict: {},
 }
 
 // IsErrorCode reports whether code is part of Raven's stable error contract.
@@ -6,6 +6,8 @@ type Caller string
 const (
 	CallerCLI Caller = "cli"
 	CallerMCP Caller = "mcp"
+	// CallerHTTP is the read-only JSON API served by `rvn serve --http`.
+	CallerHTTP Caller = "http"
 )
 
 // Request is the normalized execution request shared across adapters.
@@ -1,6 +1,11 @@
 package commandexec
 
-import "github.com/aidanlsb/raven/internal/codes"
+import (
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/codes"
+)
 
 // Result is the transport-neutral Raven execution envelope.
 type Result struct {
@@ -14,9 +19,12 @@ type Result struct {
 // ErrorInfo contains structured error information.
 type ErrorInfo struct {
 	Code       codes.ErrorCode `json:"code"`
+	Category   codes.Category  `json:"category"`
 	Message    string          `json:"message"`
 	Details    interface{}     `json:"details,omitempty"`
 	Suggestion string          `json:"suggestion,omitempty"`
+	Paths      []string        `json:"paths,omitempty"`
+	RetryWith  interface{}     `json:"retry_with,omitempty"`
 }
 
 // Warning represents a non-fatal warning.
@@ -40,6 +48,17 @@ type Meta struct {
 	Count        int           `json:"count,omitempty"`
 	QueryTimeMs  int64         `json:"query_time_ms,omitempty"`
 	VaultContext *VaultContext `json:"vault_context,omitempty"`
+	Freshness    *Freshness    `json:"freshness,omitempty"`
+}
+
+// Freshness reports whether a read was served from an index that matched the
+// vault files on disk.
+type Freshness struct {
+	Stale      bool     `json:"stale"`
+	StaleCount int      `json:"stale_count,omitempty"`
+	StaleFiles []string `json:"stale_files,omitempty"`
+	Refreshed  int      `json:"refreshed,omitempty"`
+	Unknown    bool     `json:"unknown,omitempty"` // Staleness could not be checked
 }
 
 // Success builds a successful result envelope.
@@ -62,14 +81,73 @@ func SuccessWithWarnings(data interface{}, warnings []Warning, meta *Meta) Resul
 }
 
 // Failure builds an error result envelope.
+// The category is derived from the code, and affected paths and any
+// retry_with template are lifted out of details so every error exposes
+// remediation metadata in the same place.
 func Failure(code codes.ErrorCode, message string, details interface{}, suggestion string) Result {
 	return Result{
 		OK: false,
 		Error: &ErrorInfo{
 			Code:       code,
+			Category:   codes.CategoryOf(code),
 			Message:    message,
 			Details:    details,
 			Suggestion: suggestion,
+			Paths:      pathsFromDetails(details),
+			RetryWith:  retryWithFromDetails(details),
 		},
 	}
 }
+
+var detailPathKeys = []string{"path", "file", "file_path", "files", "paths"}
+
+func pathsFromDetails(details interface{}) []string {
+	detailMap, ok := details.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	for _, key := range detailPathKeys {
+		switch value := detailMap[key].(type) {
+		case string:
+			addDetailPath(seen, value)
+		case []string:
+			for _, item := range value {
+				addDetailPath(seen, item)
+			}
+		case []interface{}:
+			for _, item := range value {
+				if str, ok := item.(string); ok {
+					addDetailPath(seen, str)
+				}
+			}
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+
+	paths := make([]string, 0, len(seen))
+	for path := range seen {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func addDetailPath(seen map[string]struct{}, path string) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return
+	}
+	seen[path] = struct{}{}
+}
+
+func retryWithFromDetails(details interface{}) interface{} {
+	detailMap, ok := details.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return detailMap["retry_with"]
+}
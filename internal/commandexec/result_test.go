@@ -0,0 +1,44 @@
+package commandexec
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/codes"
+)
+
+func TestFailureAddsRemediationMetadata(t *testing.T) {
+	t.Parallel()
+
+	retry := map[string]interface{}{"type": "person", "title": "Freya"}
+	result := Failure(codes.ErrRequiredFieldMissing, "Missing required fields: name", map[string]interface{}{
+		"file":       "people/freya.md",
+		"paths":      []string{"people/freya.md", "people/thor.md", " "},
+		"retry_with": retry,
+	}, "")
+
+	if result.OK || result.Error == nil {
+		t.Fatalf("expected failure envelope, got %#v", result)
+	}
+	if result.Error.Category != codes.CategorySchema {
+		t.Fatalf("category = %q, want %q", result.Error.Category, codes.CategorySchema)
+	}
+	if want := []string{"people/freya.md", "people/thor.md"}; !reflect.DeepEqual(result.Error.Paths, want) {
+		t.Fatalf("paths = %#v, want %#v", result.Error.Paths, want)
+	}
+	if !reflect.DeepEqual(result.Error.RetryWith, retry) {
+		t.Fatalf("retry_with = %#v, want %#v", result.Error.RetryWith, retry)
+	}
+}
+
+func TestFailureWithoutDetailsHasCategoryOnly(t *testing.T) {
+	t.Parallel()
+
+	result := Failure(codes.ErrVaultNotFound, "vault not found", nil, "")
+	if result.Error.Category != codes.CategoryConfig {
+		t.Fatalf("category = %q, want %q", result.Error.Category, codes.CategoryConfig)
+	}
+	if result.Error.Paths != nil || result.Error.RetryWith != nil {
+		t.Fatalf("expected no paths or retry_with, got %#v", result.Error)
+	}
+}
@@ -37,23 +37,56 @@ func HandleAdd(_ context.Context, req commandexec.Request) commandexec.Result {
 	if err != nil {
 		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
 	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
 
 	sch, err := schema.Load(vaultPath)
 	if err != nil {
 		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
 	}
 
+	position, err := objectsvc.ParseAddPosition(stringArg(req.Args, "position"))
+	if err != nil {
+		return mapContentMutationError(err)
+	}
+	lineNum, onLine := intArg(req.Args, "line")
+	if onLine {
+		switch {
+		case stdinMode:
+			return commandexec.Failure("INVALID_INPUT", "--line cannot be used with --stdin", nil, "Target one file with --to and --line")
+		case strings.TrimSpace(stringArg(req.Args, "heading")) != "":
+			return commandexec.Failure("INVALID_INPUT", "cannot combine --line with --heading", nil, "Use either --line or --heading")
+		case position != objectsvc.AddPositionEnd:
+			return commandexec.Failure("INVALID_INPUT", "cannot combine --line with --position", nil, "Use either --line or --position")
+		}
+	}
+
 	if !stdinMode {
-		return runAddSingle(vaultPath, vaultCfg, sch, text, strings.TrimSpace(stringArg(req.Args, "to")), strings.TrimSpace(stringArg(req.Args, "heading")))
+		target := addTarget{
+			toRef:       strings.TrimSpace(stringArg(req.Args, "to")),
+			headingSpec: strings.TrimSpace(stringArg(req.Args, "heading")),
+			position:    position,
+			line:        lineNum,
+			onLine:      onLine,
+		}
+		return runAddSingle(vaultPath, vaultCfg, sch, text, target)
 	}
 	if len(objectIDs) == 0 {
 		return commandexec.Failure("MISSING_ARGUMENT", "no object IDs provided via stdin", nil, "Pipe object IDs to stdin, one per line")
 	}
 
-	return runAddBulk(vaultPath, vaultCfg, sch, objectIDs, text, strings.TrimSpace(stringArg(req.Args, "heading")), req.Confirm)
+	return withBulkFailureReport(runAddBulk(vaultPath, vaultCfg, sch, objectIDs, text, strings.TrimSpace(stringArg(req.Args, "heading")), position, req.Confirm), req, "object_ids")
+}
+
+// addTarget is where a single add writes within its destination file.
+type addTarget struct {
+	toRef       string
+	headingSpec string
+	position    string
+	line        int
+	onLine      bool
 }
 
-func runAddBulk(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, ids []string, text string, headingSpec string, confirm bool) commandexec.Result {
+func runAddBulk(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, ids []string, text string, headingSpec string, position string, confirm bool) commandexec.Result {
 	fileIDs, sectionIDs := splitSectionIDs(ids)
 	warnings := sectionSkipWarnings(sectionIDs)
 	request := objectsvc.AddBulkRequest{
@@ -62,6 +95,7 @@ func runAddBulk(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Sche
 		ObjectIDs:    fileIDs,
 		Line:         text,
 		HeadingSpec:  headingSpec,
+		Position:     position,
 		ParseOptions: buildParseOptions(vaultCfg),
 	}
 
@@ -83,7 +117,9 @@ func runAddBulk(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Sche
 
 	var reindexWarnings []commandexec.Warning
 	var affectedFiles []string
+	stamper := newAttributionStamper(vaultPath, vaultCfg)
 	summary, err := objectsvc.ApplyAddBulk(request, func(filePath string) {
+		stamper.stamp(false, filePath)
 		reindexWarnings = appendCommandWarnings(reindexWarnings, autoReindexWarnings(vaultPath, vaultCfg, filePath))
 		if rel, relErr := filepath.Rel(vaultPath, filePath); relErr == nil {
 			affectedFiles = append(affectedFiles, rel)
@@ -109,7 +145,8 @@ func runAddBulk(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Sche
 	return commandexec.SuccessWithWarnings(data, warnings, &commandexec.Meta{Count: summary.Total - summary.Skipped - summary.Errors})
 }
 
-func runAddSingle(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, text, toRef, headingSpec string) commandexec.Result {
+func runAddSingle(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, text string, target addTarget) commandexec.Result {
+	toRef, headingSpec := target.toRef, target.headingSpec
 	captureCfg := vaultCfg.GetCaptureConfig()
 	parseOpts := buildParseOptions(vaultCfg)
 
@@ -166,15 +203,32 @@ func runAddSingle(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Sc
 		}
 		targetObjectID = resolvedTarget
 	}
+	inSection := strings.Contains(targetObjectID, "#")
+	if target.onLine && inSection {
+		return commandexec.Failure("INVALID_INPUT", "cannot combine --line with a section reference in --to", nil, "Pass the file in --to and the line number in --line")
+	}
+	if target.position == objectsvc.AddPositionAfterHeading && !inSection {
+		return commandexec.Failure("INVALID_INPUT", "--position after-heading needs a section target", nil, "Pass --heading or a file#section reference in --to")
+	}
 
 	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
 		return commandexec.Failure("FILE_WRITE_ERROR", err.Error(), nil, "")
 	}
-	line, err := objectsvc.AppendToFile(vaultPath, destPath, text, captureCfg, vaultCfg, isDailyNote, targetObjectID, parseOpts)
+	var line int
+	var err error
+	switch {
+	case target.onLine:
+		line, err = objectsvc.AppendToLine(destPath, target.line, text)
+	case target.position == objectsvc.AddPositionAfterHeading:
+		line, err = objectsvc.InsertAfterHeading(vaultPath, destPath, text, targetObjectID, parseOpts)
+	default:
+		line, err = objectsvc.AppendToFile(vaultPath, destPath, text, captureCfg, vaultCfg, isDailyNote, targetObjectID, parseOpts)
+	}
 	if err != nil {
 		return mapContentMutationError(err)
 	}
 
+	stampAttribution(vaultPath, vaultCfg, false, destPath)
 	warnings := autoReindexWarnings(vaultPath, vaultCfg, destPath)
 	relPath, _ := filepath.Rel(vaultPath, destPath)
 	data := map[string]interface{}{
@@ -0,0 +1,307 @@
+package commandimpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/commands"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/history"
+	ravenignore "github.com/aidanlsb/raven/internal/ignore"
+	"github.com/aidanlsb/raven/internal/reindexsvc"
+)
+
+// agentAuditLogPath is the vault-relative log of MCP mutations written when
+// mcp.audit is enabled.
+const agentAuditLogPath = ".raven/audit/mcp.jsonl"
+
+// Outcomes recorded in the agent audit log.
+const (
+	agentOutcomeApplied    = "applied"
+	agentOutcomeFailed     = "failed"
+	agentOutcomeDenied     = "denied"
+	agentOutcomeRolledBack = "rolled_back"
+)
+
+type agentAuditRecord struct {
+	Time    string                 `json:"time"`
+	Command string                 `json:"command"`
+	Args    map[string]interface{} `json:"args,omitempty"`
+	Outcome string                 `json:"outcome"`
+	Error   string                 `json:"error,omitempty"`
+	Reason  string                 `json:"reason,omitempty"`
+	Files   []string               `json:"files,omitempty"`
+}
+
+// previewedCalls remembers previews agents have run, keyed by vault and call
+// fingerprint, so mcp.require_preview can check an apply was previewed.
+var (
+	previewedCallsMu sync.Mutex
+	previewedCalls   = map[string]struct{}{}
+)
+
+// enforceAgentPolicy wraps the handlers of mutating commands so calls from MCP
+// clients follow the vault's mcp policy in raven.yaml.
+func enforceAgentPolicy(registry *commandexec.HandlerRegistry) {
+	for commandID, handler := range registry.Handlers() {
+		if isAgentGuardedCommand(commandID) {
+			registry.Register(commandID, withAgentPolicy(handler))
+		}
+	}
+}
+
+func isAgentGuardedCommand(commandID string) bool {
+	if isApplyCommand(commandID) {
+		return true
+	}
+	meta, ok := commands.EffectiveMeta(commandID)
+	return ok && meta.Access == commands.AccessWrite
+}
+
+func withAgentPolicy(handler commandexec.Handler) commandexec.Handler {
+	return func(ctx context.Context, req commandexec.Request) commandexec.Result {
+		if req.Caller != commandexec.CallerMCP || strings.TrimSpace(req.VaultPath) == "" {
+			return handler(ctx, req)
+		}
+		if isApplyCommand(req.CommandID) && !hasApplyArg(req.Args) {
+			return handler(ctx, req)
+		}
+		vaultCfg, err := config.LoadVaultConfig(req.VaultPath)
+		if err != nil {
+			// The policy cannot be read, so nothing it might deny is allowed.
+			return commandexec.Failure(codes.ErrConfigInvalid, fmt.Sprintf("cannot check the mcp policy: %v", err), nil, "Fix raven.yaml and try again")
+		}
+		policy := vaultCfg.GetMCPConfig()
+		if policy == nil {
+			return handler(ctx, req)
+		}
+
+		key := previewKey(req)
+		if req.Preview {
+			result := handler(ctx, req)
+			if result.OK && policy.RequirePreview {
+				previewedCallsMu.Lock()
+				previewedCalls[key] = struct{}{}
+				previewedCallsMu.Unlock()
+			}
+			return result
+		}
+
+		if reason := agentCommandDenial(policy, req.CommandID); reason != "" {
+			auditAgentMutation(policy, req, agentOutcomeDenied, string(codes.ErrPolicyDenied), reason, nil)
+			return commandexec.Failure(codes.ErrPolicyDenied, reason, map[string]interface{}{"command": req.CommandID}, "Ask the user to run this command, or to change mcp in raven.yaml")
+		}
+		if policy.RequirePreview && commandCanPreview(req.CommandID) && !takePreview(key) {
+			reason := "this command must be previewed before it is applied (mcp.require_preview)"
+			auditAgentMutation(policy, req, agentOutcomeDenied, string(codes.ErrPreviewRequired), reason, nil)
+			return commandexec.Failure(codes.ErrPreviewRequired, reason, map[string]interface{}{"command": req.CommandID}, "Run the same call without confirm (or with dry_run: true), review the preview, then apply it")
+		}
+
+		if len(policy.AllowPaths) == 0 && len(policy.DenyPaths) == 0 && !policy.Audit {
+			return handler(ctx, req)
+		}
+		if _, nested := history.FromContext(ctx); nested {
+			return handler(ctx, req)
+		}
+		return runGuardedMutation(ctx, handler, req, policy)
+	}
+}
+
+// runGuardedMutation applies req under a history journal, rolls it back when
+// it changed files outside the allowed paths, and audits the outcome.
+func runGuardedMutation(ctx context.Context, handler commandexec.Handler, req commandexec.Request, policy *config.MCPConfig) commandexec.Result {
+	rules, err := newAgentPathRules(policy)
+	if err != nil {
+		return commandexec.Failure(codes.ErrConfigInvalid, fmt.Sprintf("invalid mcp path pattern: %v", err), nil, "Fix mcp.allow_paths and mcp.deny_paths in raven.yaml")
+	}
+	journal, err := history.Begin(req.VaultPath, req.CommandID, historySummary(req), req.Args)
+	if err != nil {
+		return commandexec.Failure(codes.ErrFileRead, fmt.Sprintf("failed to snapshot the vault before an agent mutation: %v", err), nil, "")
+	}
+	result := handler(history.WithJournal(ctx, journal), req)
+
+	changes, err := journal.Changes()
+	if err != nil {
+		_, _ = journal.Finish()
+		return result
+	}
+
+	if denied := rules.denied(changes); len(denied) > 0 {
+		reverted, rollbackErr := journal.Rollback()
+		reason := fmt.Sprintf("changes outside the paths agents may modify were rolled back: %s", strings.Join(denied, ", "))
+		auditAgentMutation(policy, req, agentOutcomeRolledBack, string(codes.ErrPolicyDenied), reason, changedPaths(reverted))
+		if rollbackErr != nil {
+			return commandexec.Failure(codes.ErrFileWrite, fmt.Sprintf("failed to roll back a denied agent mutation: %v", rollbackErr), map[string]interface{}{"paths": denied}, "Run 'rvn check' and 'rvn reindex' to verify the vault")
+		}
+		if vaultCfg, err := config.LoadVaultConfig(req.VaultPath); err == nil && vaultCfg.IsAutoReindexEnabled() {
+			_, _ = reindexsvc.Run(reindexsvc.RunRequest{VaultPath: req.VaultPath, Context: ctx})
+		}
+		return commandexec.Failure(codes.ErrPolicyDenied, reason, map[string]interface{}{"command": req.CommandID, "paths": denied}, "Limit the change to paths allowed by mcp.allow_paths and mcp.deny_paths")
+	}
+
+	entry, err := journal.Finish()
+	if err != nil {
+		result.Warnings = append(result.Warnings, commandexec.Warning{
+			Code:    codes.WarnHistoryNotSaved,
+			Message: fmt.Sprintf("changes were applied but could not be recorded for undo: %v", err),
+		})
+	}
+	result.Warnings = append(result.Warnings, gitAutoCommitWarnings(req, entry)...)
+	outcome, code := agentOutcomeApplied, ""
+	if !result.OK {
+		outcome = agentOutcomeFailed
+		if result.Error != nil {
+			code = string(result.Error.Code)
+		}
+	}
+	auditAgentMutation(policy, req, outcome, code, "", changedPaths(changes))
+	return result
+}
+
+// agentCommandDenial explains why policy forbids applying commandID, or
+// returns "" when it is allowed.
+func agentCommandDenial(policy *config.MCPConfig, commandID string) string {
+	if policy.ReadOnly {
+		return "the vault is read-only for agents (mcp.read_only)"
+	}
+	if commandListed(policy.DenyCommands, commandID) {
+		return fmt.Sprintf("agents may not apply '%s' (mcp.deny_commands)", commandID)
+	}
+	if len(policy.AllowCommands) > 0 && !commandListed(policy.AllowCommands, commandID) {
+		return fmt.Sprintf("agents may not apply '%s' (not in mcp.allow_commands)", commandID)
+	}
+	return ""
+}
+
+func commandListed(list []string, commandID string) bool {
+	for _, entry := range list {
+		resolved, ok := commands.ResolveCommandID(strings.TrimSpace(entry))
+		if !ok {
+			resolved = strings.TrimSpace(entry)
+		}
+		if resolved == commandID {
+			return true
+		}
+	}
+	return false
+}
+
+// commandCanPreview reports whether a command previews by default or accepts
+// dry-run, so an apply can be required to follow a preview.
+func commandCanPreview(commandID string) bool {
+	if commands.PreviewModeForCommandID(commandID) != commands.PreviewModeNone {
+		return true
+	}
+	meta, ok := commands.EffectiveMeta(commandID)
+	if !ok {
+		return false
+	}
+	for _, flag := range meta.Flags {
+		if flag.Name == "dry-run" {
+			return true
+		}
+	}
+	return false
+}
+
+// previewKey identifies a call independent of whether it previews or
+// applies.
+func previewKey(req commandexec.Request) string {
+	args := make(map[string]interface{}, len(req.Args))
+	for key, value := range req.Args {
+		if key == "confirm" || key == "dry-run" || key == "merge" {
+			continue
+		}
+		args[key] = value
+	}
+	encoded, _ := json.Marshal(args)
+	return filepath.Clean(req.VaultPath) + "\x00" + req.CommandID + "\x00" + string(encoded)
+}
+
+func takePreview(key string) bool {
+	previewedCallsMu.Lock()
+	defer previewedCallsMu.Unlock()
+	if _, ok := previewedCalls[key]; !ok {
+		return false
+	}
+	delete(previewedCalls, key)
+	return true
+}
+
+// agentPathRules holds the compiled mcp.allow_paths and mcp.deny_paths.
+type agentPathRules struct {
+	allow *ravenignore.Matcher
+	deny  *ravenignore.Matcher
+	// restricted is set when allow_paths is configured, so files must match it.
+	restricted bool
+}
+
+func newAgentPathRules(policy *config.MCPConfig) (*agentPathRules, error) {
+	allow, err := ravenignore.NewMatcher(policy.AllowPaths)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := ravenignore.NewMatcher(policy.DenyPaths)
+	if err != nil {
+		return nil, err
+	}
+	return &agentPathRules{allow: allow, deny: deny, restricted: len(policy.AllowPaths) > 0}, nil
+}
+
+// denied returns the changed files agents may not modify.
+func (r *agentPathRules) denied(changes []history.FileChange) []string {
+	var denied []string
+	for _, change := range changes {
+		if (r.restricted && !r.allow.Match(change.Path, false)) || r.deny.Match(change.Path, false) {
+			denied = append(denied, change.Path)
+		}
+	}
+	return denied
+}
+
+func changedPaths(changes []history.FileChange) []string {
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	return paths
+}
+
+// auditAgentMutation appends one record to the agent audit log when
+// mcp.audit is enabled. Logging is best-effort and never fails the command.
+func auditAgentMutation(policy *config.MCPConfig, req commandexec.Request, outcome, code, reason string, files []string) {
+	if !policy.Audit {
+		return
+	}
+	line, err := json.Marshal(agentAuditRecord{
+		Time:    time.Now().UTC().Format(time.RFC3339),
+		Command: req.CommandID,
+		Args:    req.Args,
+		Outcome: outcome,
+		Error:   code,
+		Reason:  reason,
+		Files:   files,
+	})
+	if err != nil {
+		return
+	}
+
+	logPath := filepath.Join(req.VaultPath, filepath.FromSlash(agentAuditLogPath))
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(append(line, '\n'))
+}
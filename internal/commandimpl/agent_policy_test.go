@@ -0,0 +1,154 @@
+package commandimpl
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func newAgentPolicyVault(t *testing.T, ravenYAML string) *testutil.TestVault {
+	t.Helper()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(`version: 1
+types:
+  note:
+    default_path: note/
+    name_field: title
+    fields:
+      title:
+        type: string
+        required: true
+`).
+		WithRavenYAML(ravenYAML).
+		WithFile("note/example.md", "---\ntype: note\ntitle: Example\n---\n\nold body\n").
+		WithFile("private/secret.md", "---\ntype: note\ntitle: Secret\n---\n\nold body\n").
+		Build()
+	reindexForEditTest(t, v.Path)
+	return v
+}
+
+func agentEdit(v *testutil.TestVault, path string, preview bool) commandexec.Result {
+	return withAgentPolicy(HandleEdit)(context.Background(), commandexec.Request{
+		CommandID: "edit",
+		VaultPath: v.Path,
+		Caller:    commandexec.CallerMCP,
+		Preview:   preview,
+		Args: map[string]any{
+			"path":    path,
+			"old_str": "old body",
+			"new_str": "new body",
+		},
+	})
+}
+
+func TestAgentPolicyReadOnlyAllowsPreviewOnly(t *testing.T) {
+	t.Parallel()
+
+	v := newAgentPolicyVault(t, "mcp:\n  read_only: true\n")
+
+	if result := agentEdit(v, "note/example", true); !result.OK {
+		t.Fatalf("preview failed under read_only: %#v", result.Error)
+	}
+	result := agentEdit(v, "note/example", false)
+	if result.OK || result.Error.Code != codes.ErrPolicyDenied {
+		t.Fatalf("apply under read_only = %#v, want POLICY_DENIED", result.Error)
+	}
+	if strings.Contains(v.ReadFile("note/example.md"), "new body") {
+		t.Fatal("edit applied despite read_only")
+	}
+
+	// The CLI is not subject to the MCP policy.
+	cli := withAgentPolicy(HandleEdit)(context.Background(), commandexec.Request{
+		CommandID: "edit",
+		VaultPath: v.Path,
+		Caller:    commandexec.CallerCLI,
+		Args:      map[string]any{"path": "note/example", "old_str": "old body", "new_str": "new body"},
+	})
+	if !cli.OK {
+		t.Fatalf("CLI edit failed: %#v", cli.Error)
+	}
+}
+
+func TestAgentPolicyFailsClosedOnUnreadableConfig(t *testing.T) {
+	t.Parallel()
+
+	v := newAgentPolicyVault(t, "mcp:\n  read_only: true\n")
+	v.WriteFile("raven.yaml", "mcp: [\n")
+
+	result := agentEdit(v, "note/example", false)
+	if result.OK || result.Error.Code != codes.ErrConfigInvalid {
+		t.Fatalf("apply with unreadable raven.yaml = %#v, want CONFIG_INVALID", result.Error)
+	}
+	if strings.Contains(v.ReadFile("note/example.md"), "new body") {
+		t.Fatal("edit applied although the policy could not be read")
+	}
+}
+
+func TestAgentPolicyRequirePreview(t *testing.T) {
+	t.Parallel()
+
+	v := newAgentPolicyVault(t, "mcp:\n  require_preview: true\n")
+
+	result := agentEdit(v, "note/example", false)
+	if result.OK || result.Error.Code != codes.ErrPreviewRequired {
+		t.Fatalf("unpreviewed apply = %#v, want PREVIEW_REQUIRED", result.Error)
+	}
+	if result := agentEdit(v, "note/example", true); !result.OK {
+		t.Fatalf("preview failed: %#v", result.Error)
+	}
+	if result := agentEdit(v, "note/example", false); !result.OK {
+		t.Fatalf("apply after preview failed: %#v", result.Error)
+	}
+	if !strings.Contains(v.ReadFile("note/example.md"), "new body") {
+		t.Fatal("previewed edit was not applied")
+	}
+}
+
+func TestAgentPolicyRollsBackDeniedPathsAndAudits(t *testing.T) {
+	t.Parallel()
+
+	v := newAgentPolicyVault(t, "mcp:\n  deny_paths:\n    - private/\n  audit: true\n")
+
+	result := agentEdit(v, "private/secret", false)
+	if result.OK || result.Error.Code != codes.ErrPolicyDenied {
+		t.Fatalf("edit in denied path = %#v, want POLICY_DENIED", result.Error)
+	}
+	if strings.Contains(v.ReadFile("private/secret.md"), "new body") {
+		t.Fatal("edit in denied path was not rolled back")
+	}
+
+	if result := agentEdit(v, "note/example", false); !result.OK {
+		t.Fatalf("edit in allowed path failed: %#v", result.Error)
+	}
+
+	content, err := os.ReadFile(filepath.Join(v.Path, filepath.FromSlash(agentAuditLogPath)))
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("audit log has %d records, want 2:\n%s", len(lines), content)
+	}
+	var records []agentAuditRecord
+	for _, line := range lines {
+		var record agentAuditRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("decode audit record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	if records[0].Outcome != agentOutcomeRolledBack || records[1].Outcome != agentOutcomeApplied {
+		t.Fatalf("audit outcomes = %q, %q; want rolled_back, applied", records[0].Outcome, records[1].Outcome)
+	}
+	if got := strings.Join(records[1].Files, ","); got != "note/example.md" {
+		t.Fatalf("applied files = %q, want note/example.md", got)
+	}
+}
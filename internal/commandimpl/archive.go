@@ -0,0 +1,73 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/objectsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// HandleArchive executes the canonical `archive` command.
+func HandleArchive(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
+
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+	}
+
+	reference := strings.TrimSpace(stringArg(req.Args, "object_id"))
+	if reference == "" {
+		return commandexec.Failure("MISSING_ARGUMENT", "requires object-id", nil, "Usage: rvn archive <object-id>")
+	}
+
+	result, err := objectsvc.ArchiveByReference(objectsvc.ArchiveByReferenceRequest{
+		VaultPath:    vaultPath,
+		VaultConfig:  vaultCfg,
+		Schema:       sch,
+		Reference:    reference,
+		NoMove:       boolArg(req.Args, "no-move"),
+		UpdateRefs:   boolArgDefault(req.Args, "update-refs", true),
+		ParseOptions: buildParseOptions(vaultCfg),
+	})
+	if err != nil {
+		return mapContentMutationError(err)
+	}
+
+	data := map[string]interface{}{
+		"object_id": result.ObjectID,
+		"type":      result.Type,
+		"file":      result.File,
+		"state":     result.State,
+		"moved":     result.Moved,
+	}
+	if result.PreviousState != "" {
+		data["previous_state"] = result.PreviousState
+	}
+	if result.Moved {
+		data["old_path"] = result.OldPath
+		data["new_path"] = result.NewPath
+	}
+	if len(result.UpdatedRefs) > 0 {
+		data["updated_refs"] = result.UpdatedRefs
+	}
+
+	stampAttribution(vaultPath, vaultCfg, false, result.ChangedFilePath)
+	warnings := appendCommandWarnings(
+		warningMessagesToCommandWarnings(result.WarningMessages, indexUpdateFailedWarningCode),
+		autoReindexWarnings(vaultPath, vaultCfg, result.ChangedFilePath),
+	)
+	return commandexec.SuccessWithWarnings(data, warnings, nil)
+}
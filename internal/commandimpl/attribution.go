@@ -0,0 +1,63 @@
+package commandimpl
+
+import (
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/fieldmutation"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vault"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+// attributionStamper writes created_by/modified_by into the frontmatter of
+// mutated files when attribution is enabled for the vault. Stamping is
+// best-effort: files without frontmatter or unreadable files are left as-is.
+type attributionStamper struct {
+	identity string
+}
+
+func newAttributionStamper(vaultPath string, vaultCfg *config.VaultConfig) *attributionStamper {
+	if !vaultCfg.IsAttributionEnabled() {
+		return &attributionStamper{}
+	}
+	globalCfg, err := config.Load()
+	if err != nil {
+		globalCfg = nil
+	}
+	return &attributionStamper{identity: vault.ResolveIdentity(globalCfg, vaultPath)}
+}
+
+// stamp sets modified_by on each file, and created_by when created is true.
+func (s *attributionStamper) stamp(created bool, filePaths ...string) {
+	if s == nil || s.identity == "" {
+		return
+	}
+	updates := map[string]schema.FieldValue{
+		schema.FieldModifiedBy: schema.String(s.identity),
+	}
+	if created {
+		updates[schema.FieldCreatedBy] = schema.String(s.identity)
+	}
+
+	for _, filePath := range filePaths {
+		if strings.TrimSpace(filePath) == "" {
+			continue
+		}
+		content, err := vaultcrypt.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		updated, err := fieldmutation.UpdateFrontmatterFields(string(content), updates)
+		if err != nil || updated == string(content) {
+			continue
+		}
+		_ = atomicfile.WriteFile(filePath, []byte(updated), 0o644)
+	}
+}
+
+// stampAttribution is a one-shot helper for single-file mutations.
+func stampAttribution(vaultPath string, vaultCfg *config.VaultConfig, created bool, filePaths ...string) {
+	newAttributionStamper(vaultPath, vaultCfg).stamp(created, filePaths...)
+}
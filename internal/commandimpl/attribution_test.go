@@ -0,0 +1,55 @@
+package commandimpl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestAttributionStamperStampsFrontmatter(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithFile("note/created.md", "---\ntype: note\ntitle: Created\n---\nBody\n").
+		WithFile("note/edited.md", "---\ntype: note\ntitle: Edited\ncreated_by: Grace\n---\nBody\n").
+		WithFile("note/plain.md", "No frontmatter here\n").
+		Build()
+
+	stamper := &attributionStamper{identity: "Ada"}
+	stamper.stamp(true, v.Path+"/note/created.md")
+	stamper.stamp(false, v.Path+"/note/edited.md", v.Path+"/note/plain.md")
+
+	created := v.ReadFile("note/created.md")
+	if !strings.Contains(created, "created_by: Ada\n") || !strings.Contains(created, "modified_by: Ada\n") {
+		t.Fatalf("created file missing attribution:\n%s", created)
+	}
+	if !strings.HasSuffix(created, "---\nBody\n") {
+		t.Fatalf("body should be preserved:\n%s", created)
+	}
+
+	edited := v.ReadFile("note/edited.md")
+	if !strings.Contains(edited, "created_by: Grace\n") || !strings.Contains(edited, "modified_by: Ada\n") {
+		t.Fatalf("edited file should keep created_by and stamp modified_by:\n%s", edited)
+	}
+
+	if got := v.ReadFile("note/plain.md"); got != "No frontmatter here\n" {
+		t.Fatalf("file without frontmatter should be untouched, got:\n%s", got)
+	}
+}
+
+func TestAttributionStamperDisabled(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithFile("note/example.md", "---\ntype: note\n---\nBody\n").
+		Build()
+
+	stamper := newAttributionStamper(v.Path, &config.VaultConfig{})
+	stamper.stamp(true, v.Path+"/note/example.md")
+
+	if got := v.ReadFile("note/example.md"); strings.Contains(got, "_by:") {
+		t.Fatalf("attribution should not be stamped when disabled:\n%s", got)
+	}
+}
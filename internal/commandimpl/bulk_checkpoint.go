@@ -0,0 +1,165 @@
+package commandimpl
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/aidanlsb/raven/internal/bulkops"
+	"github.com/aidanlsb/raven/internal/commandexec"
+)
+
+// bulkCheckpointBatchSize is the number of items applied between checkpoint
+// saves. Runs no larger than one batch are not checkpointed.
+const bulkCheckpointBatchSize = 25
+
+type resumeCheckpointKey struct{}
+
+func withResumeCheckpoint(ctx context.Context, cp *bulkops.Checkpoint) context.Context {
+	return context.WithValue(ctx, resumeCheckpointKey{}, cp)
+}
+
+func resumeCheckpointFromContext(ctx context.Context) (*bulkops.Checkpoint, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	cp, ok := ctx.Value(resumeCheckpointKey{}).(*bulkops.Checkpoint)
+	return cp, ok && cp != nil
+}
+
+// withBulkCheckpoints wraps a bulk-capable handler so large applied runs are
+// processed in batches with progress saved under .raven/operations after each
+// batch. An interrupted run can then be finished with `rvn resume`.
+func withBulkCheckpoints(idsKey string, handler commandexec.Handler) commandexec.Handler {
+	return func(ctx context.Context, req commandexec.Request) commandexec.Result {
+		cp, resuming := resumeCheckpointFromContext(ctx)
+		if !resuming {
+			ids := commandIDsArg(req.Args, idsKey)
+			if !req.Confirm || len(ids) <= bulkCheckpointBatchSize {
+				return handler(ctx, req)
+			}
+
+			var err error
+			cp, err = bulkops.NewCheckpoint(req.CommandID, req.Args, idsKey, ids)
+			if err != nil {
+				return commandexec.Failure("INTERNAL_ERROR", err.Error(), nil, "")
+			}
+			if err := bulkops.SaveCheckpoint(req.VaultPath, cp); err != nil {
+				return commandexec.Failure("FILE_WRITE_ERROR", fmt.Sprintf("failed to save operation checkpoint: %v", err), nil, "")
+			}
+		}
+		return runCheckpointedBulk(ctx, req, cp, handler, resuming)
+	}
+}
+
+func runCheckpointedBulk(ctx context.Context, req commandexec.Request, cp *bulkops.Checkpoint, handler commandexec.Handler, resuming bool) commandexec.Result {
+	processed := append([]string(nil), cp.Pending...)
+	batchSize := bulkCheckpointBatchSizeFor(cp.Command)
+
+	var merged commandexec.Result
+	for len(cp.Pending) > 0 {
+		batch := append([]string(nil), cp.Pending[:min(batchSize, len(cp.Pending))]...)
+
+		batchReq := req
+		batchReq.Args = checkpointArgs(cp, batch)
+		result := handler(ctx, batchReq)
+		if !result.OK {
+			if result.Error != nil {
+				result.Error.Suggestion = fmt.Sprintf("Fix the problem, then run 'rvn resume %s --confirm' to finish the remaining %d item(s)", cp.ID, len(cp.Pending))
+			}
+			return result
+		}
+		merged = mergeBulkBatchResults(merged, result)
+
+		cp.MarkCompleted(batch)
+		if err := bulkops.SaveCheckpoint(req.VaultPath, cp); err != nil {
+			return commandexec.Failure("FILE_WRITE_ERROR", fmt.Sprintf("failed to save operation checkpoint: %v", err), nil, "")
+		}
+	}
+	_ = bulkops.RemoveCheckpoint(req.VaultPath, cp.ID)
+
+	data, ok := merged.Data.(map[string]interface{})
+	if !ok {
+		return merged
+	}
+	for _, key := range []string{"partial", "failures", "retry_with"} {
+		delete(data, key)
+	}
+	data["operation_id"] = cp.ID
+	if resuming {
+		data["resumed"] = true
+	}
+
+	fullReq := req
+	fullReq.Args = checkpointArgs(cp, processed)
+	return withBulkFailureReport(merged, fullReq, cp.IDsKey)
+}
+
+// bulkCheckpointBatchSizeFor returns the checkpoint granularity for a command.
+// Commands that are not idempotent are checkpointed per item, so each item's
+// completion is recorded before the next one starts: re-running an appended
+// add would duplicate text, and re-running a move or delete would fail with
+// "not found" for an item that already completed.
+func bulkCheckpointBatchSizeFor(commandID string) int {
+	switch commandID {
+	case "add", "move", "delete":
+		return 1
+	default:
+		return bulkCheckpointBatchSize
+	}
+}
+
+func checkpointArgs(cp *bulkops.Checkpoint, ids []string) map[string]interface{} {
+	args := make(map[string]interface{}, len(cp.Args)+2)
+	for key, value := range cp.Args {
+		args[key] = value
+	}
+	args["stdin"] = true
+	args[cp.IDsKey] = stringsToInterfaces(ids)
+	return args
+}
+
+// mergeBulkBatchResults folds one batch result into the running total:
+// counts are summed, result lists are appended, and other fields keep the
+// first batch's value.
+func mergeBulkBatchResults(total, batch commandexec.Result) commandexec.Result {
+	if total.Data == nil {
+		return batch
+	}
+
+	totalData, ok := total.Data.(map[string]interface{})
+	batchData, batchOK := batch.Data.(map[string]interface{})
+	if ok && batchOK {
+		for key, value := range batchData {
+			totalData[key] = mergeBulkBatchValue(totalData[key], value)
+		}
+	}
+	total.Warnings = append(total.Warnings, batch.Warnings...)
+	if total.Meta != nil && batch.Meta != nil {
+		total.Meta.Count += batch.Meta.Count
+	}
+	return total
+}
+
+func mergeBulkBatchValue(current, next interface{}) interface{} {
+	if current == nil {
+		return next
+	}
+	switch value := current.(type) {
+	case int:
+		if n, ok := next.(int); ok {
+			return value + n
+		}
+	case bool:
+		if b, ok := next.(bool); ok {
+			return value && b
+		}
+	}
+
+	currentValue := reflect.ValueOf(current)
+	nextValue := reflect.ValueOf(next)
+	if currentValue.Kind() == reflect.Slice && nextValue.IsValid() && nextValue.Type() == currentValue.Type() {
+		return reflect.AppendSlice(currentValue, nextValue).Interface()
+	}
+	return current
+}
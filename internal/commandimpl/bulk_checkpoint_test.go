@@ -0,0 +1,229 @@
+package commandimpl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/bulkops"
+	"github.com/aidanlsb/raven/internal/commandexec"
+)
+
+func fakeBulkHandler(calls *[][]string, failOn string) commandexec.Handler {
+	return func(_ context.Context, req commandexec.Request) commandexec.Result {
+		ids := commandIDsArg(req.Args, "object_ids")
+		*calls = append(*calls, ids)
+		results := make([]canonicalBulkResult, 0, len(ids))
+		for _, id := range ids {
+			if id == failOn {
+				return commandexec.Failure("FILE_WRITE_ERROR", "disk full", nil, "")
+			}
+			results = append(results, canonicalBulkResult{ID: id, Status: "modified"})
+		}
+		return commandexec.Success(map[string]interface{}{
+			"action":   "set",
+			"results":  results,
+			"total":    len(ids),
+			"modified": len(ids),
+		}, &commandexec.Meta{Count: len(ids)})
+	}
+}
+
+func bulkTestIDs(n int) []interface{} {
+	ids := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		ids = append(ids, fmt.Sprintf("notes/item-%02d", i))
+	}
+	return ids
+}
+
+func TestWithBulkCheckpointsBatchesLargeRuns(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+
+	var calls [][]string
+	handler := withBulkCheckpoints("object_ids", fakeBulkHandler(&calls, ""))
+	result := handler(context.Background(), commandexec.Request{
+		CommandID: "set",
+		VaultPath: vaultPath,
+		Confirm:   true,
+		Args:      map[string]interface{}{"stdin": true, "object_ids": bulkTestIDs(60)},
+	})
+
+	if !result.OK {
+		t.Fatalf("result failed: %#v", result.Error)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("handler calls = %d, want 3 batches", len(calls))
+	}
+	data := result.Data.(map[string]interface{})
+	if data["modified"] != 60 || len(data["results"].([]canonicalBulkResult)) != 60 {
+		t.Fatalf("merged data = %#v, want 60 modified results", data)
+	}
+	if id, _ := data["operation_id"].(string); id == "" {
+		t.Fatalf("expected operation_id in data: %#v", data)
+	}
+	if remaining, _ := bulkops.ListCheckpoints(vaultPath); len(remaining) != 0 {
+		t.Fatalf("completed run left checkpoints: %#v", remaining)
+	}
+}
+
+func TestWithBulkCheckpointsSkipsSmallOrPreviewRuns(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+
+	var calls [][]string
+	handler := withBulkCheckpoints("object_ids", fakeBulkHandler(&calls, ""))
+	handler(context.Background(), commandexec.Request{
+		CommandID: "set",
+		VaultPath: vaultPath,
+		Args:      map[string]interface{}{"stdin": true, "object_ids": bulkTestIDs(60)},
+	})
+	handler(context.Background(), commandexec.Request{
+		CommandID: "set",
+		VaultPath: vaultPath,
+		Confirm:   true,
+		Args:      map[string]interface{}{"stdin": true, "object_ids": bulkTestIDs(3)},
+	})
+
+	if len(calls) != 2 || len(calls[0]) != 60 || len(calls[1]) != 3 {
+		t.Fatalf("expected unbatched passthrough calls, got %d calls", len(calls))
+	}
+}
+
+func TestResumeFinishesInterruptedRun(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+
+	var calls [][]string
+	handler := withBulkCheckpoints("object_ids", fakeBulkHandler(&calls, "notes/item-30"))
+	failed := handler(context.Background(), commandexec.Request{
+		CommandID: "set",
+		VaultPath: vaultPath,
+		Confirm:   true,
+		Args:      map[string]interface{}{"stdin": true, "object_ids": bulkTestIDs(60)},
+	})
+	if failed.OK {
+		t.Fatal("expected interrupted run to fail")
+	}
+
+	checkpoints, err := bulkops.ListCheckpoints(vaultPath)
+	if err != nil || len(checkpoints) != 1 {
+		t.Fatalf("checkpoints = %#v, err = %v; want one", checkpoints, err)
+	}
+	cp := checkpoints[0]
+	if len(cp.Completed) != 25 || len(cp.Pending) != 35 {
+		t.Fatalf("checkpoint completed=%d pending=%d, want 25/35", len(cp.Completed), len(cp.Pending))
+	}
+
+	var resumedCalls [][]string
+	registry := commandexec.NewHandlerRegistry()
+	registry.Register("set", withBulkCheckpoints("object_ids", fakeBulkHandler(&resumedCalls, "")))
+	registry.Register("resume", HandleResume)
+	invoker := commandexec.NewInvoker(registry, nil)
+
+	result := invoker.Execute(context.Background(), commandexec.Request{
+		CommandID: "resume",
+		VaultPath: vaultPath,
+		Confirm:   true,
+		Args:      map[string]interface{}{"operation_id": cp.ID},
+	})
+	if !result.OK {
+		t.Fatalf("resume failed: %#v", result.Error)
+	}
+	data := result.Data.(map[string]interface{})
+	if data["modified"] != 35 || data["resumed"] != true || data["operation_id"] != cp.ID {
+		t.Fatalf("resume data = %#v", data)
+	}
+	for _, batch := range resumedCalls {
+		for _, id := range batch {
+			if id < "notes/item-25" {
+				t.Fatalf("resume re-applied completed item %s", id)
+			}
+		}
+	}
+	if remaining, _ := bulkops.ListCheckpoints(vaultPath); len(remaining) != 0 {
+		t.Fatalf("resume left checkpoints: %#v", remaining)
+	}
+}
+
+// fakeDeleteHandler removes items from a shared set, failing with "not found"
+// for items that were already removed, like a real delete. It stops before
+// handling interruptAt, as if the process were killed mid-batch.
+func fakeDeleteHandler(existing map[string]bool, interruptAt string) commandexec.Handler {
+	return func(_ context.Context, req commandexec.Request) commandexec.Result {
+		ids := commandIDsArg(req.Args, "object_ids")
+		results := make([]canonicalBulkResult, 0, len(ids))
+		for _, id := range ids {
+			if id == interruptAt {
+				return commandexec.Failure("INTERNAL_ERROR", "interrupted", nil, "")
+			}
+			if !existing[id] {
+				return commandexec.Failure("NOT_FOUND", "object not found: "+id, nil, "")
+			}
+			delete(existing, id)
+			results = append(results, canonicalBulkResult{ID: id, Status: "deleted"})
+		}
+		return commandexec.Success(map[string]interface{}{
+			"action":  "delete",
+			"results": results,
+			"total":   len(ids),
+			"deleted": len(ids),
+		}, &commandexec.Meta{Count: len(ids)})
+	}
+}
+
+func TestResumeAfterMidBatchInterruptDoesNotRedoCompletedItems(t *testing.T) {
+	t.Parallel()
+
+	for _, command := range []string{"delete", "move", "add"} {
+		vaultPath := t.TempDir()
+		ids := bulkTestIDs(60)
+		existing := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			existing[id.(string)] = true
+		}
+
+		// Interrupt partway through what would be the second 25-item batch.
+		handler := withBulkCheckpoints("object_ids", fakeDeleteHandler(existing, "notes/item-30"))
+		failed := handler(context.Background(), commandexec.Request{
+			CommandID: command,
+			VaultPath: vaultPath,
+			Confirm:   true,
+			Args:      map[string]interface{}{"stdin": true, "object_ids": ids},
+		})
+		if failed.OK {
+			t.Fatalf("%s: expected interrupted run to fail", command)
+		}
+
+		checkpoints, err := bulkops.ListCheckpoints(vaultPath)
+		if err != nil || len(checkpoints) != 1 {
+			t.Fatalf("%s: checkpoints = %#v, err = %v; want one", command, checkpoints, err)
+		}
+		cp := checkpoints[0]
+		if len(cp.Completed) != 30 || len(cp.Pending) != 30 {
+			t.Fatalf("%s: checkpoint completed=%d pending=%d, want 30/30", command, len(cp.Completed), len(cp.Pending))
+		}
+
+		registry := commandexec.NewHandlerRegistry()
+		registry.Register(command, withBulkCheckpoints("object_ids", fakeDeleteHandler(existing, "")))
+		registry.Register("resume", HandleResume)
+		invoker := commandexec.NewInvoker(registry, nil)
+
+		result := invoker.Execute(context.Background(), commandexec.Request{
+			CommandID: "resume",
+			VaultPath: vaultPath,
+			Confirm:   true,
+			Args:      map[string]interface{}{"operation_id": cp.ID},
+		})
+		if !result.OK {
+			t.Fatalf("%s: resume failed: %#v", command, result.Error)
+		}
+		if data := result.Data.(map[string]interface{}); data["deleted"] != 30 {
+			t.Fatalf("%s: resume data = %#v, want 30 items applied", command, data)
+		}
+		if len(existing) != 0 {
+			t.Fatalf("%s: items left unapplied after resume: %v", command, existing)
+		}
+	}
+}
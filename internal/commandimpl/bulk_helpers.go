@@ -8,6 +8,7 @@ import (
 	"github.com/aidanlsb/raven/internal/commandexec"
 	"github.com/aidanlsb/raven/internal/objectsvc"
 	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/traitsvc"
 )
 
 const warnSectionSkipped = codes.WarnSectionSkipped
@@ -19,6 +20,11 @@ type canonicalBulkResult struct {
 	Details string `json:"details,omitempty"`
 }
 
+type canonicalBulkFailure struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason,omitempty"`
+}
+
 type canonicalBulkPreviewItem struct {
 	ID      string            `json:"id"`
 	Changes map[string]string `json:"changes,omitempty"`
@@ -219,3 +225,57 @@ func canonicalMoveResults(items []objectsvc.MoveBulkResult) []canonicalBulkResul
 	}
 	return out
 }
+
+// withBulkFailureReport adds a partial-failure report to an applied bulk
+// result: the items that errored, and a retry_with request that re-runs the
+// same command for just those items once they have been resolved.
+func withBulkFailureReport(result commandexec.Result, req commandexec.Request, idsKey string) commandexec.Result {
+	data, ok := result.Data.(map[string]interface{})
+	if !result.OK || !ok || boolArg(data, "preview") {
+		return result
+	}
+
+	failures := bulkFailures(data["results"])
+	if len(failures) == 0 {
+		return result
+	}
+
+	retryIDs := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		retryIDs = append(retryIDs, failure.ID)
+	}
+	retryArgs := make(map[string]interface{}, len(req.Args)+2)
+	for key, value := range req.Args {
+		retryArgs[key] = value
+	}
+	delete(retryArgs, "confirm")
+	retryArgs["stdin"] = true
+	retryArgs[idsKey] = stringsToInterfaces(retryIDs)
+
+	data["partial"] = true
+	data["failures"] = failures
+	data["retry_with"] = map[string]interface{}{
+		"command": req.CommandID,
+		"args":    retryArgs,
+	}
+	return result
+}
+
+func bulkFailures(raw interface{}) []canonicalBulkFailure {
+	var failures []canonicalBulkFailure
+	switch results := raw.(type) {
+	case []canonicalBulkResult:
+		for _, item := range results {
+			if item.Status == "error" {
+				failures = append(failures, canonicalBulkFailure{ID: item.ID, Reason: item.Reason})
+			}
+		}
+	case []traitsvc.BulkResult:
+		for _, item := range results {
+			if item.Status == "error" {
+				failures = append(failures, canonicalBulkFailure{ID: item.ID, Reason: item.Reason})
+			}
+		}
+	}
+	return failures
+}
@@ -0,0 +1,65 @@
+package commandimpl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+)
+
+func TestWithBulkFailureReportAddsRetryRequest(t *testing.T) {
+	t.Parallel()
+
+	req := commandexec.Request{
+		CommandID: "set",
+		Args: map[string]interface{}{
+			"stdin":      true,
+			"confirm":    true,
+			"fields":     map[string]interface{}{"status": "done"},
+			"object_ids": []interface{}{"projects/a", "projects/b", "projects/c"},
+		},
+	}
+	result := commandexec.Success(map[string]interface{}{
+		"action": "set",
+		"results": []canonicalBulkResult{
+			{ID: "projects/a", Status: "modified"},
+			{ID: "projects/b", Status: "error", Reason: "invalid value"},
+			{ID: "projects/c", Status: "skipped", Reason: "object not found"},
+		},
+	}, nil)
+
+	data := withBulkFailureReport(result, req, "object_ids").Data.(map[string]interface{})
+	if data["partial"] != true {
+		t.Fatalf("partial = %v, want true", data["partial"])
+	}
+	wantFailures := []canonicalBulkFailure{{ID: "projects/b", Reason: "invalid value"}}
+	if !reflect.DeepEqual(data["failures"], wantFailures) {
+		t.Fatalf("failures = %#v, want %#v", data["failures"], wantFailures)
+	}
+	retryWith := data["retry_with"].(map[string]interface{})
+	if retryWith["command"] != "set" {
+		t.Fatalf("retry command = %v, want set", retryWith["command"])
+	}
+	retryArgs := retryWith["args"].(map[string]interface{})
+	if _, ok := retryArgs["confirm"]; ok {
+		t.Fatalf("retry args should not carry confirm: %#v", retryArgs)
+	}
+	if !reflect.DeepEqual(retryArgs["object_ids"], []interface{}{"projects/b"}) {
+		t.Fatalf("retry object_ids = %#v", retryArgs["object_ids"])
+	}
+	if _, ok := req.Args["object_ids"].([]interface{}); !ok || len(req.Args["object_ids"].([]interface{})) != 3 {
+		t.Fatalf("request args were mutated: %#v", req.Args)
+	}
+}
+
+func TestWithBulkFailureReportLeavesCleanRunsAlone(t *testing.T) {
+	t.Parallel()
+
+	result := commandexec.Success(map[string]interface{}{
+		"results": []canonicalBulkResult{{ID: "projects/a", Status: "modified"}},
+	}, nil)
+	data := withBulkFailureReport(result, commandexec.Request{CommandID: "set"}, "object_ids").Data.(map[string]interface{})
+	if _, ok := data["retry_with"]; ok {
+		t.Fatalf("unexpected retry_with for clean run: %#v", data)
+	}
+}
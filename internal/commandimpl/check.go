@@ -3,6 +3,7 @@ package commandimpl
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/aidanlsb/raven/internal/codes"
 	"github.com/aidanlsb/raven/internal/commandexec"
 	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
 	"github.com/aidanlsb/raven/internal/schema"
 )
 
@@ -43,6 +45,10 @@ func HandleCheck(_ context.Context, req commandexec.Request) commandexec.Result
 		Exclude:     strings.TrimSpace(stringArg(req.Args, "exclude")),
 		ErrorsOnly:  boolArg(req.Args, "errors-only"),
 	})
+	var profileErr *checksvc.ProfileError
+	if errors.As(err, &profileErr) {
+		return commandexec.Failure("CONFIG_INVALID", err.Error(), nil, "Fix the check settings in raven.yaml or the profile they reference")
+	}
 	if err != nil {
 		return commandexec.Failure("VALIDATION_FAILED", err.Error(), nil, "")
 	}
@@ -57,8 +63,61 @@ func HandleCheck(_ context.Context, req commandexec.Request) commandexec.Result
 		if convErr != nil {
 			return commandexec.Failure("INTERNAL_ERROR", "failed to build check response", nil, "")
 		}
-		return commandexec.Success(data, nil)
+		warnings := schemaSkewWarnings(vaultPath, vaultCfg, sch)
+		if rules := result.Rules; rules != nil && rules.Stale {
+			warnings = append(warnings, commandexec.Warning{
+				Code:    codes.WarnProfileStale,
+				Message: fmt.Sprintf("could not fetch check profile %s (%s); using the cached copy", rules.ProfileSource, rules.StaleReason),
+			})
+		}
+		return commandexec.SuccessWithWarnings(data, warnings, nil)
+	}
+}
+
+// schemaSkewWarnings reports when schema.yaml has changed since the index was
+// built or since raven.yaml was last stamped by a reindex.
+func schemaSkewWarnings(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema) []commandexec.Warning {
+	fingerprint := sch.Fingerprint()
+
+	var warnings []commandexec.Warning
+	if db, err := index.Open(vaultPath); err == nil {
+		defer db.Close()
+		if stored, ok, err := db.SchemaFingerprint(); err == nil && ok && stored.Hash != fingerprint.Hash {
+			drift := fingerprint.DriftSince(stored)
+			affected, _ := db.FilesAffectedBySchemaDrift(drift)
+			warnings = append(warnings, commandexec.Warning{
+				Code: codes.WarnSchemaOutdated,
+				Message: fmt.Sprintf("%d indexed file(s) were indexed under an older schema (changed: %s); run 'rvn reindex' to refresh them",
+					len(affected), describeSchemaDrift(drift, stored.Version != fingerprint.Version)),
+			})
+			return warnings
+		}
+	}
+
+	if stamp := vaultCfg.SchemaStamp; stamp != nil && (stamp.Hash != fingerprint.Hash || stamp.Version != fingerprint.Version) {
+		warnings = append(warnings, commandexec.Warning{
+			Code:    codes.WarnSchemaOutdated,
+			Message: fmt.Sprintf("schema.yaml has changed since the vault was stamped (schema %s, now %s); run 'rvn reindex' to restamp it", stamp.Hash, fingerprint.Hash),
+		})
 	}
+	return warnings
+}
+
+func describeSchemaDrift(drift schema.Drift, versionChanged bool) string {
+	var parts []string
+	if versionChanged {
+		parts = append(parts, "schema version")
+	}
+	for _, name := range drift.Types {
+		parts = append(parts, "type "+name)
+	}
+	for _, name := range drift.Traits {
+		parts = append(parts, "trait "+name)
+	}
+	if len(parts) == 0 {
+		return "schema"
+	}
+	return strings.Join(parts, ", ")
 }
 
 // HandleCheckFix executes the canonical `check_fix` command.
@@ -73,6 +132,41 @@ func HandleCheckCreateMissing(ctx context.Context, req commandexec.Request) comm
 	return HandleCheck(ctx, req)
 }
 
+// HandleHealth executes the canonical `health` command.
+func HandleHealth(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+	minScore, _ := intArg(req.Args, "min-score")
+	if minScore < 0 || minScore > 100 {
+		return commandexec.Failure("INVALID_INPUT", "--min-score must be between 0 and 100", nil, "")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+	}
+
+	result, err := checksvc.Run(vaultPath, vaultCfg, sch, checksvc.Options{})
+	if err != nil {
+		return commandexec.Failure("VALIDATION_FAILED", err.Error(), nil, "")
+	}
+
+	health := checksvc.ScoreHealth(result)
+	return commandexec.Success(map[string]interface{}{
+		"score":      health.Score,
+		"min_score":  minScore,
+		"passed":     health.Score >= minScore,
+		"file_count": health.FileCount,
+		"categories": health.Categories,
+	}, nil)
+}
+
 func handleCheckFix(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, result *checksvc.RunResult, confirm bool) commandexec.Result {
 	fixes := checksvc.CollectFixableIssues(result.Issues, result.ShortRefs, sch, vaultCfg)
 	grouped := checksvc.GroupFixesByFile(fixes)
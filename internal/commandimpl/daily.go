@@ -0,0 +1,90 @@
+package commandimpl
+
+import (
+	"time"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/dates"
+	"github.com/aidanlsb/raven/internal/datesvc"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/tasksvc"
+)
+
+// runDailyAutomation adds carried-over and due tasks to a daily note that
+// `rvn daily` just created for today or later, then adds the note date's
+// agenda to data. Vaults without the configured task traits get neither.
+func runDailyAutomation(vaultPath string, daily *datesvc.EnsureDailyResult, data map[string]interface{}) ([]commandexec.Warning, *commandexec.Result) {
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		failure := commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+		return nil, &failure
+	}
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		failure := commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+		return nil, &failure
+	}
+	tasksCfg := vaultCfg.GetTasksConfig()
+	if requireTaskTrait(sch, tasksCfg) != nil {
+		return nil, nil
+	}
+
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		failure := commandexec.Failure("DATABASE_ERROR", "failed to open database", nil, "Run 'rvn reindex' to rebuild the database")
+		return nil, &failure
+	}
+	defer db.Close()
+	db.SetDailyDirectory(vaultCfg.GetDailyDirectory())
+	if compatible, err := db.SchemaCompatible(); err != nil || !compatible {
+		return []commandexec.Warning{{
+			Code:    codes.WarnDatabaseOutdated,
+			Message: "index schema is stale or incompatible; skipped carry-over and the agenda (run 'rvn reindex --full')",
+		}}, nil
+	}
+
+	var warnings []commandexec.Warning
+	if daily.Created && daily.Date >= time.Now().Format(dates.DateLayout) {
+		automated, err := datesvc.AutomateDaily(datesvc.AutomateDailyRequest{
+			VaultPath: vaultPath,
+			VaultCfg:  vaultCfg,
+			DB:        db,
+			Daily:     daily,
+		})
+		if err != nil {
+			if _, ok := tasksvc.AsError(err); ok {
+				failure := mapTaskServiceError(err)
+				return nil, &failure
+			}
+			failure := mapDateServiceError(err)
+			return nil, &failure
+		}
+		data["carried_over"] = taskItems(automated.CarriedOver)
+		data["scheduled"] = taskItems(automated.Scheduled)
+		if len(automated.ChangedFiles) > 0 {
+			stampAttribution(vaultPath, vaultCfg, false, automated.ChangedFiles...)
+			warnings = autoReindexWarnings(vaultPath, vaultCfg, automated.ChangedFiles...)
+		}
+	}
+
+	noteDate, err := time.Parse(dates.DateLayout, daily.Date)
+	if err != nil {
+		return warnings, nil
+	}
+	groups, err := tasksvc.List(tasksvc.ListRequest{DB: db, Config: tasksCfg, Today: noteDate})
+	if err != nil {
+		failure := mapTaskServiceError(err)
+		return nil, &failure
+	}
+	agenda := make([]map[string]interface{}, 0, 2)
+	for _, group := range groups {
+		if group.Key == tasksvc.GroupOverdue || group.Key == daily.Date {
+			agenda = append(agenda, taskGroupItem(group))
+		}
+	}
+	data["agenda"] = agenda
+	return warnings, nil
+}
@@ -0,0 +1,138 @@
+package commandimpl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/querysvc"
+	"github.com/aidanlsb/raven/internal/readsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// dashboardDefaultLimit caps the rows each dashboard section shows when the
+// section sets no limit.
+const dashboardDefaultLimit = 10
+
+// HandleDashboard executes the canonical `dashboard` command.
+func HandleDashboard(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	if len(vaultCfg.Dashboard) == 0 {
+		return commandexec.Success(map[string]interface{}{
+			"sections": []map[string]interface{}{},
+		}, &commandexec.Meta{Count: 0})
+	}
+
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+	}
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return commandexec.Failure("DATABASE_ERROR", "failed to open database", nil, "Run 'rvn reindex' to rebuild the database")
+	}
+	defer db.Close()
+	db.SetDailyDirectory(vaultCfg.GetDailyDirectory())
+	compatible, err := db.SchemaCompatible()
+	if err != nil {
+		return commandexec.Failure(codes.ErrDatabase, "failed to read index schema version", nil, "Run 'rvn reindex --full' to rebuild the index")
+	}
+	if !compatible {
+		return commandexec.Failure(codes.ErrDatabaseVersion, "index schema is stale or incompatible", nil, "Run 'rvn reindex --full' to rebuild the index")
+	}
+
+	rt := &readsvc.Runtime{
+		VaultPath: vaultPath,
+		VaultCfg:  vaultCfg,
+		Schema:    sch,
+		DB:        db,
+	}
+	freshness, failure := checkIndexFreshness(rt, req.Args)
+	if failure != nil {
+		return *failure
+	}
+
+	sections := make([]map[string]interface{}, 0, len(vaultCfg.Dashboard))
+	var degraded []string
+	seenDegraded := map[string]bool{}
+	for _, section := range vaultCfg.Dashboard {
+		item, features := runDashboardSection(rt, section)
+		sections = append(sections, item)
+		for _, feature := range features {
+			if !seenDegraded[feature] {
+				seenDegraded[feature] = true
+				degraded = append(degraded, feature)
+			}
+		}
+	}
+
+	return commandexec.SuccessWithWarnings(map[string]interface{}{
+		"sections": sections,
+	}, degradedQueryWarnings(degraded), &commandexec.Meta{Count: len(sections), Freshness: freshness})
+}
+
+// runDashboardSection runs one section's saved query. A section that fails
+// reports its error instead of failing the whole dashboard.
+func runDashboardSection(rt *readsvc.Runtime, section config.DashboardSection) (map[string]interface{}, []string) {
+	name := strings.TrimSpace(section.Query)
+	title := strings.TrimSpace(section.Title)
+	if title == "" {
+		title = name
+	}
+	limit := section.Limit
+	if limit <= 0 {
+		limit = dashboardDefaultLimit
+	}
+	item := map[string]interface{}{
+		"query": name,
+		"title": title,
+		"total": 0,
+		"items": []map[string]interface{}{},
+	}
+
+	saved, ok := rt.VaultCfg.Queries[name]
+	if !ok {
+		item["error"] = fmt.Sprintf("saved query '%s' not found", name)
+		return item, nil
+	}
+	queryString, err := querysvc.ResolveSavedQuery(name, saved, section.Args, nil)
+	if err != nil {
+		item["error"] = err.Error()
+		return item, nil
+	}
+	if !isFullQueryString(queryString) {
+		item["error"] = fmt.Sprintf("saved query '%s' must start with 'type:', 'trait:', 'section', or 'asset'", name)
+		return item, nil
+	}
+
+	result, err := readsvc.ExecuteQuery(rt, readsvc.ExecuteQueryRequest{QueryString: queryString, Limit: limit})
+	if err != nil {
+		item["error"] = err.Error()
+		return item, nil
+	}
+	item["query_kind"] = result.QueryKind
+	item["total"] = result.Total
+	switch result.QueryKind {
+	case "type":
+		item["items"] = objectQueryItems(result)
+	case "trait":
+		item["items"] = traitQueryItems(result)
+	case "asset":
+		item["items"] = assetQueryItems(result)
+	case "section":
+		item["items"] = sectionQueryItems(result)
+	}
+	return item, result.Degraded
+}
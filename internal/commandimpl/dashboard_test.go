@@ -0,0 +1,55 @@
+package commandimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestHandleDashboardRunsEachSection(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(`version: 1
+types:
+  project:
+    default_path: projects/
+    fields:
+      status: { type: string }
+`).
+		WithRavenYAML(`queries:
+  by-status:
+    query: "type:project .status=={{args.status}}"
+    args: [status]
+dashboard:
+  - query: by-status
+    title: Active projects
+    args: [active]
+    limit: 1
+  - query: missing
+`).
+		WithFile("projects/alpha.md", "---\ntype: project\nstatus: active\n---\n").
+		WithFile("projects/beta.md", "---\ntype: project\nstatus: active\n---\n").
+		WithFile("projects/gamma.md", "---\ntype: project\nstatus: done\n---\n").
+		Build()
+	reindexForEditTest(t, v.Path)
+
+	result := HandleDashboard(context.Background(), commandexec.Request{VaultPath: v.Path, Args: map[string]any{}})
+	if !result.OK {
+		t.Fatalf("HandleDashboard() failed: %#v", result.Error)
+	}
+	sections := result.Data.(map[string]interface{})["sections"].([]map[string]interface{})
+	if len(sections) != 2 {
+		t.Fatalf("sections = %#v, want 2", sections)
+	}
+
+	active := sections[0]
+	if active["title"] != "Active projects" || active["total"] != 2 || len(active["items"].([]map[string]interface{})) != 1 {
+		t.Errorf("active section = %#v, want title, total 2, and 1 item", active)
+	}
+	if missing := sections[1]; missing["error"] == nil || missing["title"] != "missing" {
+		t.Errorf("missing section = %#v, want an error", missing)
+	}
+}
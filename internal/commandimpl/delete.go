@@ -25,6 +25,7 @@ func HandleDelete(_ context.Context, req commandexec.Request) commandexec.Result
 	if err != nil {
 		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
 	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
 
 	objectIDs := commandIDsArg(req.Args, "object_ids")
 	stdinMode := boolArg(req.Args, "stdin") || len(objectIDs) > 0
@@ -32,7 +33,7 @@ func HandleDelete(_ context.Context, req commandexec.Request) commandexec.Result
 		if len(objectIDs) == 0 {
 			return commandexec.Failure("MISSING_ARGUMENT", "no object IDs provided via stdin", nil, "Pipe object IDs to stdin, one per line")
 		}
-		return runDeleteBulk(vaultPath, vaultCfg, objectIDs, req.Confirm)
+		return withBulkFailureReport(runDeleteBulk(vaultPath, vaultCfg, objectIDs, req.Confirm), req, "object_ids")
 	}
 
 	reference := strings.TrimSpace(stringArg(req.Args, "object_id"))
@@ -0,0 +1,65 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/doctorsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// HandleDoctor executes the canonical `doctor` command.
+func HandleDoctor(ctx context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+	}
+
+	report, err := doctorsvc.Diagnose(vaultPath, vaultCfg, sch)
+	if err != nil {
+		return commandexec.Failure("INTERNAL_ERROR", err.Error(), nil, "")
+	}
+	if !boolArg(req.Args, "fix") {
+		return commandexec.Success(doctorData(report), &commandexec.Meta{Count: report.Errors + report.Warnings})
+	}
+	if !req.Confirm {
+		data := doctorData(report)
+		data["preview"] = true
+		return commandexec.Success(data, &commandexec.Meta{Count: report.Errors + report.Warnings})
+	}
+
+	repaired, err := doctorsvc.Repair(ctx, vaultPath, report)
+	if err != nil {
+		return commandexec.Failure(codes.ErrDatabase, err.Error(), nil, "Run 'rvn reindex --full' to rebuild the index")
+	}
+	after, err := doctorsvc.Diagnose(vaultPath, vaultCfg, sch)
+	if err != nil {
+		return commandexec.Failure("INTERNAL_ERROR", err.Error(), nil, "")
+	}
+	data := doctorData(after)
+	data["preview"] = false
+	data["repair"] = repaired
+	return commandexec.Success(data, &commandexec.Meta{Count: after.Errors + after.Warnings})
+}
+
+func doctorData(report *doctorsvc.Report) map[string]interface{} {
+	return map[string]interface{}{
+		"checks":   report.Findings,
+		"errors":   report.Errors,
+		"warnings": report.Warnings,
+		"healthy":  report.Errors == 0 && report.Warnings == 0,
+		"fixable":  report.Fixable(),
+	}
+}
@@ -0,0 +1,29 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/schemasvc"
+)
+
+// HandleDriftReport executes the canonical `drift report` command.
+func HandleDriftReport(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+
+	report, err := schemasvc.AnalyzeDrift(vaultPath, vaultCfg)
+	if err != nil {
+		return mapSchemaFailure(err)
+	}
+	return commandexec.Success(map[string]interface{}{"drift": report}, &commandexec.Meta{Count: report.Summary.Total()})
+}
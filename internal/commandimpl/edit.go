@@ -3,7 +3,7 @@ package commandimpl
 import (
 	"context"
 	"encoding/json"
-	"os"
+	"fmt"
 	"path/filepath"
 	"strings"
 
@@ -12,9 +12,11 @@ import (
 	"github.com/aidanlsb/raven/internal/config"
 	"github.com/aidanlsb/raven/internal/editsvc"
 	ravenignore "github.com/aidanlsb/raven/internal/ignore"
+	"github.com/aidanlsb/raven/internal/objectsvc"
 	"github.com/aidanlsb/raven/internal/paths"
 	"github.com/aidanlsb/raven/internal/readsvc"
 	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 // HandleEdit executes the canonical `edit` command.
@@ -28,6 +30,7 @@ func HandleEdit(_ context.Context, req commandexec.Request) commandexec.Result {
 	if err != nil {
 		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
 	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
 
 	reference := strings.TrimSpace(stringArg(req.Args, "path"))
 	if reference == "" {
@@ -55,7 +58,7 @@ func HandleEdit(_ context.Context, req commandexec.Request) commandexec.Result {
 		return *validation
 	}
 
-	content, err := os.ReadFile(resolved.FilePath)
+	content, err := vaultcrypt.ReadFile(resolved.FilePath)
 	if err != nil {
 		return commandexec.Failure("FILE_READ_ERROR", err.Error(), nil, "")
 	}
@@ -81,6 +84,7 @@ func HandleEdit(_ context.Context, req commandexec.Request) commandexec.Result {
 			editsPreview := make([]map[string]interface{}, 0, len(results))
 			for _, result := range results {
 				editsPreview = append(editsPreview, map[string]interface{}{
+					"op":      result.Op,
 					"index":   result.Index,
 					"line":    result.Line,
 					"old_str": result.OldStr,
@@ -102,6 +106,7 @@ func HandleEdit(_ context.Context, req commandexec.Request) commandexec.Result {
 		result := results[0]
 		return commandexec.Success(map[string]interface{}{
 			"status": "preview",
+			"op":     result.Op,
 			"path":   relPath,
 			"line":   result.Line,
 			"preview": map[string]string{
@@ -114,6 +119,7 @@ func HandleEdit(_ context.Context, req commandexec.Request) commandexec.Result {
 	if err := atomicfile.WriteFile(resolved.FilePath, []byte(newContent), 0o644); err != nil {
 		return commandexec.Failure("FILE_WRITE_ERROR", err.Error(), nil, "")
 	}
+	stampAttribution(vaultPath, vaultCfg, false, resolved.FilePath)
 	warnings := autoReindexWarnings(vaultPath, vaultCfg, resolved.FilePath)
 
 	var missingData map[string]interface{}
@@ -127,6 +133,7 @@ func HandleEdit(_ context.Context, req commandexec.Request) commandexec.Result {
 		applied := make([]map[string]interface{}, 0, len(results))
 		for _, result := range results {
 			applied = append(applied, map[string]interface{}{
+				"op":      result.Op,
 				"index":   result.Index,
 				"line":    result.Line,
 				"old_str": result.OldStr,
@@ -147,6 +154,7 @@ func HandleEdit(_ context.Context, req commandexec.Request) commandexec.Result {
 	result := results[0]
 	data := map[string]interface{}{
 		"status":  "applied",
+		"op":      result.Op,
 		"path":    relPath,
 		"line":    result.Line,
 		"old_str": result.OldStr,
@@ -184,6 +192,10 @@ func parseCanonicalEditInput(args map[string]any) ([]editsvc.EditSpec, bool, err
 		return edits, true, nil
 	}
 
+	if edit, ok, err := structuredEditFromArgs(args); ok || err != nil {
+		return []editsvc.EditSpec{edit}, false, err
+	}
+
 	oldStr := stringArg(args, "old_str")
 	newStr, hasNew := args["new_str"]
 	if oldStr == "" || !hasNew {
@@ -200,6 +212,40 @@ func parseCanonicalEditInput(args map[string]any) ([]editsvc.EditSpec, bool, err
 	}}, false, nil
 }
 
+// structuredEditFromArgs builds an edit from --append-to-section,
+// --insert-after-heading, or --replace-line. The text is new_str when given,
+// otherwise the single positional argument after the reference.
+func structuredEditFromArgs(args map[string]any) (editsvc.EditSpec, bool, error) {
+	edit := editsvc.EditSpec{
+		AppendToSection:    strings.TrimSpace(stringArg(args, "append-to-section")),
+		InsertAfterHeading: strings.TrimSpace(stringArg(args, "insert-after-heading")),
+	}
+	if line, ok := intArg(args, "replace-line"); ok {
+		if line <= 0 {
+			return edit, true, &editsvc.Error{Code: editsvc.CodeInvalidInput, Message: "--replace-line must be a positive line number", Suggestion: "Use a line number from 'rvn read --raw --lines'"}
+		}
+		edit.ReplaceLine = line
+	}
+	if edit.Op() == editsvc.OpReplace {
+		return edit, false, nil
+	}
+
+	oldStr := stringArg(args, "old_str")
+	newStr, hasNew := args["new_str"]
+	switch {
+	case hasNew && oldStr != "":
+		return edit, true, &editsvc.Error{Code: editsvc.CodeInvalidInput, Message: "structured edits take a single text argument", Suggestion: `Usage: rvn edit <reference> --append-to-section "## Log" "text"`}
+	case hasNew:
+		edit.NewStr = toAnyString(newStr)
+	default:
+		edit.NewStr = oldStr
+	}
+	if err := editsvc.ValidateEdit(edit); err != nil {
+		return edit, true, err
+	}
+	return edit, true, nil
+}
+
 func mapEditFailure(err error) commandexec.Result {
 	if svcErr, ok := editsvc.AsError(err); ok {
 		var details map[string]interface{}
@@ -250,6 +296,11 @@ func validateEditableContentPath(vaultPath string, vaultCfg *config.VaultConfig,
 		return &result
 	}
 
+	if vaultCfg.IsLockedPath(relPath) || (!vaultCfg.LocksIgnored() && objectsvc.IsFrontmatterLocked(filePath)) {
+		result := commandexec.Failure("FILE_LOCKED", fmt.Sprintf("file is locked: %s", relPath), map[string]interface{}{"path": relPath}, fmt.Sprintf("Run 'rvn unlock %s' or pass --unlock to edit it", relPath))
+		return &result
+	}
+
 	if vaultCfg != nil {
 		excludeMatcher, err := ravenignore.NewMatcher(vaultCfg.GetExcludePatterns())
 		if err != nil {
@@ -97,6 +97,49 @@ repeat me
 	}
 }
 
+func TestHandleEditAppendToSectionWithinSectionTarget(t *testing.T) {
+	t.Parallel()
+
+	v := newSectionEditVault(t, `---
+type: note
+title: Example
+---
+
+# Target
+## Log
+- first
+
+# Other
+## Log
+- elsewhere
+`)
+	reindexForEditTest(t, v.Path)
+
+	result := HandleEdit(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args: map[string]any{
+			"path":              "note/example#target",
+			"append-to-section": "## Log",
+			"old_str":           "- second",
+		},
+	})
+	if !result.OK {
+		t.Fatalf("HandleEdit() failed: %#v", result.Error)
+	}
+	data, _ := result.Data.(map[string]interface{})
+	if got, want := data["line"], 9; got != want {
+		t.Fatalf("line = %#v, want %d", got, want)
+	}
+
+	content := v.ReadFile("note/example.md")
+	if !strings.Contains(content, "## Log\n- first\n- second\n\n# Other") {
+		t.Fatalf("text was not appended to the target section:\n%s", content)
+	}
+	if !strings.Contains(content, "## Log\n- elsewhere\n") {
+		t.Fatalf("outside section should remain unchanged:\n%s", content)
+	}
+}
+
 func newSectionEditVault(t *testing.T, noteContent string) *testutil.TestVault {
 	t.Helper()
 
@@ -0,0 +1,63 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/fmtsvc"
+	"github.com/aidanlsb/raven/internal/objectsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// HandleFmt executes the canonical `fmt` command.
+func HandleFmt(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	rules, err := fmtsvc.RulesFromConfig(vaultCfg.Fmt)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", err.Error(), nil, "Fix the fmt settings in raven.yaml and try again")
+	}
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+	}
+
+	// --check never writes, even when confirmed.
+	apply := req.Confirm && !boolArg(req.Args, "check")
+	result, err := fmtsvc.Run(fmtsvc.Request{
+		VaultPath: vaultPath,
+		VaultCfg:  vaultCfg,
+		Schema:    sch,
+		Rules:     rules,
+		Path:      stringArg(req.Args, "path"),
+		Apply:     apply,
+		CanWrite: func(absPath string) error {
+			return objectsvc.ValidateContentMutationFilePath(vaultPath, vaultCfg, absPath)
+		},
+	})
+	if err != nil {
+		return commandexec.Failure("INVALID_INPUT", err.Error(), nil, "Pass a file, directory, or reference inside the vault")
+	}
+
+	data := map[string]interface{}{
+		"preview": !apply,
+		"checked": result.Checked,
+		"changed": len(result.Files),
+		"files":   result.Files,
+		"skipped": result.Skipped,
+	}
+	meta := &commandexec.Meta{Count: len(result.Files)}
+	if len(result.Written) == 0 {
+		return commandexec.Success(data, meta)
+	}
+	return commandexec.SuccessWithWarnings(data, autoReindexWarnings(vaultPath, vaultCfg, result.Written...), meta)
+}
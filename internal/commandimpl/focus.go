@@ -0,0 +1,172 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/readsvc"
+)
+
+// focusTarget is the object_id value that stands for the focus working set.
+const focusTarget = "@focus"
+
+// HandleFocusAdd executes the canonical `focus_add` command.
+func HandleFocusAdd(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	var references []string
+	if reference := strings.TrimSpace(stringArg(req.Args, "object_id")); reference != "" {
+		references = append(references, reference)
+	}
+	references = append(references, commandIDsArg(req.Args, "object_ids")...)
+	if len(references) == 0 {
+		return commandexec.Failure("MISSING_ARGUMENT", "requires at least one object", nil, "Usage: rvn focus add <object-id>...")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	rt := &readsvc.Runtime{VaultPath: vaultPath, VaultCfg: vaultCfg}
+
+	objectIDs := make([]string, 0, len(references))
+	for _, reference := range references {
+		resolved, err := readsvc.ResolveReference(reference, rt, false)
+		if err != nil {
+			return mapResolveFailure(err, reference)
+		}
+		if resolved.IsSection {
+			return commandexec.Failure("INVALID_INPUT", "focus only holds file-level objects: "+reference, nil, "Use the object ID without a section fragment")
+		}
+		objectIDs = append(objectIDs, resolved.ObjectID)
+	}
+
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return commandexec.Failure("DATABASE_ERROR", "failed to open database", nil, "Run 'rvn reindex' to rebuild the database")
+	}
+	defer db.Close()
+
+	added, err := db.AddFocus(objectIDs)
+	if err != nil {
+		return commandexec.Failure("DATABASE_ERROR", "failed to update focus: "+err.Error(), nil, "")
+	}
+	entries, err := db.FocusEntries()
+	if err != nil {
+		return commandexec.Failure("DATABASE_ERROR", "failed to read focus: "+err.Error(), nil, "")
+	}
+	if added == nil {
+		added = []string{}
+	}
+	return commandexec.Success(map[string]interface{}{
+		"added": added,
+		"total": len(entries),
+	}, nil)
+}
+
+// HandleFocusList executes the canonical `focus_list` command.
+func HandleFocusList(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return commandexec.Failure("DATABASE_ERROR", "failed to open database", nil, "Run 'rvn reindex' to rebuild the database")
+	}
+	defer db.Close()
+
+	entries, err := db.FocusEntries()
+	if err != nil {
+		return commandexec.Failure("DATABASE_ERROR", "failed to read focus: "+err.Error(), nil, "")
+	}
+
+	items := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		item := map[string]interface{}{
+			"id":       entry.ObjectID,
+			"added_at": entry.AddedAt,
+		}
+		if entry.FilePath == "" {
+			item["missing"] = true
+		} else {
+			item["type"] = entry.Type
+			item["file"] = entry.FilePath
+		}
+		items = append(items, item)
+	}
+	return commandexec.Success(map[string]interface{}{
+		"items": items,
+	}, &commandexec.Meta{Count: len(items)})
+}
+
+// HandleFocusClear executes the canonical `focus_clear` command.
+func HandleFocusClear(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return commandexec.Failure("DATABASE_ERROR", "failed to open database", nil, "Run 'rvn reindex' to rebuild the database")
+	}
+	defer db.Close()
+
+	cleared, err := db.ClearFocus()
+	if err != nil {
+		return commandexec.Failure("DATABASE_ERROR", "failed to clear focus: "+err.Error(), nil, "")
+	}
+	return commandexec.Success(map[string]interface{}{
+		"cleared": cleared,
+	}, nil)
+}
+
+// withFocusTarget lets a bulk-capable handler take @focus as its object_id:
+// the focused object IDs become the bulk object_ids, so the request previews
+// unless confirmed, exactly like piping the IDs to --stdin.
+func withFocusTarget(handler commandexec.Handler) commandexec.Handler {
+	return func(ctx context.Context, req commandexec.Request) commandexec.Result {
+		if strings.TrimSpace(stringArg(req.Args, "object_id")) != focusTarget {
+			return handler(ctx, req)
+		}
+
+		db, err := index.Open(req.VaultPath)
+		if err != nil {
+			return commandexec.Failure("DATABASE_ERROR", "failed to open database", nil, "Run 'rvn reindex' to rebuild the database")
+		}
+		entries, err := db.FocusEntries()
+		db.Close()
+		if err != nil {
+			return commandexec.Failure("DATABASE_ERROR", "failed to read focus: "+err.Error(), nil, "")
+		}
+		if len(entries) == 0 {
+			return commandexec.Failure("MISSING_ARGUMENT", "focus is empty", nil, "Add objects with 'rvn focus add <object-id>'")
+		}
+
+		args := make(map[string]interface{}, len(req.Args)+1)
+		for key, value := range req.Args {
+			args[key] = value
+		}
+		delete(args, "object_id")
+		ids := make([]interface{}, 0, len(entries))
+		for _, entry := range entries {
+			ids = append(ids, entry.ObjectID)
+		}
+		args["object_ids"] = ids
+		req.Args = args
+
+		result := handler(ctx, req)
+		if data, ok := result.Data.(map[string]interface{}); ok {
+			data["bulk"] = true
+		}
+		return result
+	}
+}
@@ -0,0 +1,40 @@
+package commandimpl
+
+import (
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/readsvc"
+)
+
+func TestCheckIndexFreshnessOnlyFailsWhenRefreshRequested(t *testing.T) {
+	t.Parallel()
+
+	db, err := index.OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open in-memory db: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	// Break the staleness check without affecting object reads.
+	if _, err := db.DB().Exec("DROP TABLE assets"); err != nil {
+		t.Fatalf("drop assets: %v", err)
+	}
+	rt := &readsvc.Runtime{VaultPath: t.TempDir(), VaultCfg: &config.VaultConfig{}, DB: db}
+
+	freshness, failure := checkIndexFreshness(rt, map[string]interface{}{})
+	if failure != nil {
+		t.Fatalf("plain read failed: %#v", failure.Error)
+	}
+	if freshness == nil || !freshness.Unknown || freshness.Stale {
+		t.Fatalf("freshness = %#v, want unknown and not stale", freshness)
+	}
+
+	for _, flag := range []string{"refresh", "require-fresh"} {
+		_, failure := checkIndexFreshness(rt, map[string]interface{}{flag: true})
+		if failure == nil || failure.Error == nil || failure.Error.Code != codes.ErrDatabase {
+			t.Fatalf("--%s: failure = %#v, want DATABASE_ERROR", flag, failure)
+		}
+	}
+}
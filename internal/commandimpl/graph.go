@@ -0,0 +1,64 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/graphsvc"
+	"github.com/aidanlsb/raven/internal/readsvc"
+)
+
+// HandleGraphExport executes the canonical `graph_export` command.
+func HandleGraphExport(_ context.Context, req commandexec.Request) commandexec.Result {
+	start := time.Now()
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	var rootID string
+	if root := strings.TrimSpace(stringArg(req.Args, "root")); root != "" {
+		vaultCfg, err := config.LoadVaultConfig(vaultPath)
+		if err != nil {
+			return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+		}
+		resolved, err := readsvc.ResolveReference(root, &readsvc.Runtime{VaultPath: vaultPath, VaultCfg: vaultCfg}, false)
+		if err != nil {
+			return mapResolveFailure(err, root)
+		}
+		rootID = resolved.ObjectID
+	}
+
+	depth, _ := intArg(req.Args, "depth")
+	result, err := graphsvc.Export(graphsvc.ExportRequest{
+		VaultPath: vaultPath,
+		Format:    stringArg(req.Args, "format"),
+		Types:     stringSliceArg(req.Args["type"]),
+		Root:      rootID,
+		Depth:     depth,
+		Sections:  boolArg(req.Args, "sections"),
+		Output:    strings.TrimSpace(stringArg(req.Args, "output")),
+	})
+	if err != nil {
+		svcErr, ok := graphsvc.AsError(err)
+		if !ok {
+			return commandexec.Failure("INTERNAL_ERROR", err.Error(), nil, "")
+		}
+		return commandexec.Failure(svcErr.Code, svcErr.Message, nil, svcErr.Suggestion)
+	}
+
+	data := map[string]interface{}{
+		"format": result.Format,
+		"nodes":  result.Graph.Nodes,
+		"edges":  result.Graph.Edges,
+	}
+	if result.Output != "" {
+		data["output"] = result.Output
+	} else if result.Format != graphsvc.FormatJSON {
+		data["content"] = result.Content
+	}
+	return commandexec.Success(data, &commandexec.Meta{Count: len(result.Graph.Nodes), QueryTimeMs: time.Since(start).Milliseconds()})
+}
@@ -0,0 +1,256 @@
+package commandimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/commands"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/history"
+	"github.com/aidanlsb/raven/internal/reindexsvc"
+)
+
+const defaultHistoryLimit = 20
+
+// recordedCommandIDs are mutating commands outside the content category whose
+// applied runs are recorded for undo.
+var recordedCommandIDs = map[string]struct{}{
+	"check_fix":            {},
+	"check create-missing": {},
+	"daily":                {},
+	"fmt":                  {},
+	"schema_rename_field":  {},
+	"schema_rename_type":   {},
+}
+
+type historyEntrySummary struct {
+	ID        string `json:"id"`
+	Command   string `json:"command"`
+	Summary   string `json:"summary"`
+	Files     int    `json:"files"`
+	CreatedAt string `json:"created_at"`
+	Undone    bool   `json:"undone"`
+}
+
+// recordHistory wraps the handlers of recorded commands so each applied run
+// saves the files it changed under .raven/history for `rvn undo`.
+func recordHistory(registry *commandexec.HandlerRegistry) {
+	for commandID, handler := range registry.Handlers() {
+		if isRecordedCommand(commandID) {
+			registry.Register(commandID, withHistory(handler))
+		}
+	}
+}
+
+func isRecordedCommand(commandID string) bool {
+	if isApplyCommand(commandID) {
+		return true
+	}
+	if _, ok := recordedCommandIDs[commandID]; ok {
+		return true
+	}
+	meta, ok := commands.EffectiveMeta(commandID)
+	return ok && meta.Category == commands.CategoryContent && meta.Access == commands.AccessWrite
+}
+
+func withHistory(handler commandexec.Handler) commandexec.Handler {
+	return func(ctx context.Context, req commandexec.Request) commandexec.Result {
+		if req.Preview || strings.TrimSpace(req.VaultPath) == "" {
+			return handler(ctx, req)
+		}
+		if isApplyCommand(req.CommandID) && !hasApplyArg(req.Args) {
+			return handler(ctx, req)
+		}
+		if _, nested := history.FromContext(ctx); nested {
+			return handler(ctx, req)
+		}
+
+		journal, err := history.Begin(req.VaultPath, req.CommandID, historySummary(req), req.Args)
+		if err != nil {
+			return handler(ctx, req)
+		}
+		result := handler(history.WithJournal(ctx, journal), req)
+		entry, err := journal.Finish()
+		if err != nil {
+			result.Warnings = append(result.Warnings, commandexec.Warning{
+				Code:    codes.WarnHistoryNotSaved,
+				Message: fmt.Sprintf("changes were applied but could not be recorded for undo: %v", err),
+			})
+		}
+		result.Warnings = append(result.Warnings, gitAutoCommitWarnings(req, entry)...)
+		return result
+	}
+}
+
+// historySummary renders the command line a recorded run corresponds to,
+// using the registry's positional argument order.
+func historySummary(req commandexec.Request) string {
+	meta, ok := commands.EffectiveMeta(req.CommandID)
+	if !ok {
+		return req.CommandID
+	}
+	parts := []string{meta.Name}
+	for _, arg := range meta.Args {
+		switch value := req.Args[arg.Name].(type) {
+		case string:
+			if value != "" {
+				parts = append(parts, value)
+			}
+		case []interface{}:
+			if len(value) > 0 {
+				parts = append(parts, fmt.Sprintf("(%d items)", len(value)))
+			}
+		case []string:
+			if len(value) > 0 {
+				parts = append(parts, fmt.Sprintf("(%d items)", len(value)))
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// applyCommandIDs are read commands that change files only when run with
+// --apply.
+var applyCommandIDs = map[string]struct{}{
+	"query":     {},
+	"orphans":   {},
+	"deadlinks": {},
+}
+
+func isApplyCommand(commandID string) bool {
+	_, ok := applyCommandIDs[commandID]
+	return ok
+}
+
+// hasApplyArg reports whether a request carries --apply, the only form of an
+// apply command that changes files.
+func hasApplyArg(args map[string]interface{}) bool {
+	return strings.TrimSpace(stringArg(args, "apply")) != "" || lenArgList(args["apply"]) > 0
+}
+
+func lenArgList(raw interface{}) int {
+	switch values := raw.(type) {
+	case []interface{}:
+		return len(values)
+	case []string:
+		return len(values)
+	default:
+		return 0
+	}
+}
+
+// HandleHistory executes the canonical `history` command.
+func HandleHistory(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	limit, ok := intArg(req.Args, "limit")
+	if !ok || limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	entries, err := history.List(vaultPath)
+	if err != nil {
+		return commandexec.Failure("FILE_READ_ERROR", fmt.Sprintf("failed to read operation history: %v", err), nil, "")
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	operations := make([]historyEntrySummary, 0, len(entries))
+	for _, entry := range entries {
+		operations = append(operations, summarizeHistoryEntry(entry))
+	}
+	return commandexec.Success(map[string]interface{}{
+		"operations": operations,
+	}, &commandexec.Meta{Count: len(operations)})
+}
+
+// HandleUndo executes the canonical `undo` command.
+func HandleUndo(ctx context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	count := 1
+	if raw := strings.TrimSpace(stringArg(req.Args, "count")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return commandexec.Failure("INVALID_INPUT", fmt.Sprintf("invalid count: %s", raw), nil, "Pass a positive number of operations to undo")
+		}
+		count = n
+	}
+
+	plan, err := history.PlanUndo(vaultPath, count)
+	if errors.Is(err, history.ErrNothingToUndo) {
+		return commandexec.Failure("NOT_FOUND", "no recorded operations to undo", nil, "Run 'rvn history' to see recorded operations")
+	}
+	if err != nil {
+		return commandexec.Failure("FILE_READ_ERROR", fmt.Sprintf("failed to read operation history: %v", err), nil, "")
+	}
+
+	operations := make([]historyEntrySummary, 0, len(plan.Entries))
+	for _, entry := range plan.Entries {
+		operations = append(operations, summarizeHistoryEntry(entry))
+	}
+	conflicts := plan.Conflicts
+	if conflicts == nil {
+		conflicts = []history.UndoConflict{}
+	}
+	data := map[string]interface{}{
+		"operations": operations,
+		"files":      plan.Files,
+		"conflicts":  conflicts,
+	}
+
+	force := boolArg(req.Args, "force")
+	if !req.Confirm {
+		data["preview"] = true
+		return commandexec.Success(data, &commandexec.Meta{Count: len(plan.Files)})
+	}
+	if len(plan.Conflicts) > 0 && !force {
+		return commandexec.Failure(
+			"UNDO_CONFLICT",
+			fmt.Sprintf("%d file(s) changed since the operation ran", len(plan.Conflicts)),
+			map[string]interface{}{"conflicts": plan.Conflicts},
+			"Review the conflicts, then pass --force to overwrite later edits",
+		)
+	}
+
+	changed, err := history.ApplyUndo(vaultPath, plan)
+	if err != nil {
+		return commandexec.Failure("FILE_WRITE_ERROR", fmt.Sprintf("undo failed: %v", err), nil, "Run 'rvn check' and 'rvn reindex' to verify the vault")
+	}
+
+	var warnings []commandexec.Warning
+	if vaultCfg, err := config.LoadVaultConfig(vaultPath); err == nil && vaultCfg.IsAutoReindexEnabled() && len(changed) > 0 {
+		if _, err := reindexsvc.Run(reindexsvc.RunRequest{VaultPath: vaultPath, Context: ctx}); err != nil {
+			warnings = append(warnings, commandexec.Warning{
+				Code:    codes.WarnIndexUpdateFailed,
+				Message: fmt.Sprintf("files were restored but the index was not updated: %v (run 'rvn reindex')", err),
+			})
+		}
+	}
+
+	data["undone"] = len(plan.Entries)
+	return commandexec.SuccessWithWarnings(data, warnings, &commandexec.Meta{Count: len(changed)})
+}
+
+func summarizeHistoryEntry(entry *history.Entry) historyEntrySummary {
+	return historyEntrySummary{
+		ID:        entry.ID,
+		Command:   entry.Command,
+		Summary:   entry.Summary,
+		Files:     len(entry.Files),
+		CreatedAt: entry.CreatedAt.Format(time.RFC3339),
+		Undone:    entry.UndoneAt != nil,
+	}
+}
@@ -3,6 +3,7 @@ package commandimpl
 import (
 	"context"
 	"io"
+	"path/filepath"
 	"strings"
 
 	"github.com/aidanlsb/raven/internal/codes"
@@ -48,6 +49,12 @@ func HandleImport(_ context.Context, req commandexec.Request) commandexec.Result
 		return mapImportFailure(err, "")
 	}
 
+	return importRunResult(vaultPath, serviceResult, boolArg(req.Args, "dry-run"), nil)
+}
+
+// importRunResult summarizes an import run, stamping and reindexing the
+// files it changed. extra is merged into the result data.
+func importRunResult(vaultPath string, serviceResult *importsvc.RunResult, dryRun bool, extra map[string]interface{}) commandexec.Result {
 	var created, updated, skipped, errored int
 	for _, item := range serviceResult.Results {
 		switch item.Action {
@@ -62,41 +69,47 @@ func HandleImport(_ context.Context, req commandexec.Request) commandexec.Result
 		}
 	}
 
-	if !boolArg(req.Args, "dry-run") {
-		reindexed := make(map[string]struct{}, len(serviceResult.ChangedFilePaths))
-		var reindexWarnings []commandexec.Warning
-		for _, changedFile := range serviceResult.ChangedFilePaths {
-			if changedFile == "" {
-				continue
-			}
-			if _, seen := reindexed[changedFile]; seen {
-				continue
-			}
-			reindexed[changedFile] = struct{}{}
-			reindexWarnings = appendCommandWarnings(
-				reindexWarnings,
-				autoReindexWarnings(vaultPath, serviceResult.VaultConfig, changedFile),
-			)
-		}
-		serviceWarnings := warningMessagesToCommandWarnings(serviceResult.WarningMessages, codes.WarnUnknownField)
-		return commandexec.SuccessWithWarnings(map[string]interface{}{
-			"total":   len(serviceResult.Results),
-			"created": created,
-			"updated": updated,
-			"skipped": skipped,
-			"errors":  errored,
-			"results": serviceResult.Results,
-		}, appendCommandWarnings(serviceWarnings, reindexWarnings), nil)
-	}
-
-	return commandexec.SuccessWithWarnings(map[string]interface{}{
+	data := map[string]interface{}{
 		"total":   len(serviceResult.Results),
 		"created": created,
 		"updated": updated,
 		"skipped": skipped,
 		"errors":  errored,
 		"results": serviceResult.Results,
-	}, warningMessagesToCommandWarnings(serviceResult.WarningMessages, codes.WarnUnknownField), nil)
+	}
+	for key, value := range extra {
+		data[key] = value
+	}
+	warnings := warningMessagesToCommandWarnings(serviceResult.WarningMessages, codes.WarnUnknownField)
+	if dryRun {
+		return commandexec.SuccessWithWarnings(data, warnings, nil)
+	}
+
+	reindexed := make(map[string]struct{}, len(serviceResult.ChangedFilePaths))
+	var reindexWarnings []commandexec.Warning
+	createdFiles := make(map[string]struct{})
+	for _, item := range serviceResult.Results {
+		if item.Action == "created" && item.File != "" {
+			createdFiles[filepath.Clean(filepath.Join(vaultPath, item.File))] = struct{}{}
+		}
+	}
+	stamper := newAttributionStamper(vaultPath, serviceResult.VaultConfig)
+	for _, changedFile := range serviceResult.ChangedFilePaths {
+		if changedFile == "" {
+			continue
+		}
+		if _, seen := reindexed[changedFile]; seen {
+			continue
+		}
+		reindexed[changedFile] = struct{}{}
+		_, created := createdFiles[filepath.Clean(changedFile)]
+		stamper.stamp(created, changedFile)
+		reindexWarnings = appendCommandWarnings(
+			reindexWarnings,
+			autoReindexWarnings(vaultPath, serviceResult.VaultConfig, changedFile),
+		)
+	}
+	return commandexec.SuccessWithWarnings(data, appendCommandWarnings(warnings, reindexWarnings), nil)
 }
 
 func mapImportFailure(err error, fallbackSuggestion string) commandexec.Result {
@@ -0,0 +1,75 @@
+package commandimpl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/importsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// HandleImportCSV executes the canonical `import csv` command.
+func HandleImportCSV(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+	typeName := strings.TrimSpace(stringArg(req.Args, "type"))
+	if typeName == "" {
+		return commandexec.Failure("MISSING_ARGUMENT", "--type is required", nil, "Pass the type each row becomes, e.g. --type person")
+	}
+
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema.yaml", nil, "Fix schema.yaml and try again")
+	}
+	items, header, err := importsvc.ReadCSVInput(strings.TrimSpace(stringArg(req.Args, "file")), stdinReader(req.Stdin))
+	if err != nil {
+		return mapImportFailure(err, "Expected a CSV file with a header row")
+	}
+	if len(items) == 0 {
+		return commandexec.Failure("INVALID_INPUT", "no rows to import", nil, "Provide a CSV file with a header row and at least one data row")
+	}
+
+	contentColumn := strings.TrimSpace(stringArg(req.Args, "content-field"))
+	columnMap, ignored, err := importsvc.MapCSVColumns(header, stringSliceArg(req.Args["map"]), typeName, sch, contentColumn)
+	if err != nil {
+		return mapImportFailure(err, "Map columns with --map field=Column")
+	}
+	for _, item := range items {
+		for _, column := range ignored {
+			delete(item, column)
+		}
+	}
+
+	preview := !req.Confirm
+	serviceResult, err := importsvc.Run(importsvc.RunRequest{
+		VaultPath: vaultPath,
+		MappingConfig: &importsvc.MappingConfig{
+			Type:         typeName,
+			Key:          strings.TrimSpace(stringArg(req.Args, "key")),
+			Map:          columnMap,
+			ContentField: contentColumn,
+		},
+		Items:             items,
+		DryRun:            preview,
+		CreateOnly:        boolArg(req.Args, "create-only"),
+		UpdateOnly:        boolArg(req.Args, "update-only"),
+		CoerceStrings:     true,
+		SkipDuplicateKeys: true,
+		ItemLabel:         func(i int) string { return fmt.Sprintf("row %d", i+1) },
+	})
+	if err != nil {
+		return mapImportFailure(err, "")
+	}
+
+	if ignored == nil {
+		ignored = []string{}
+	}
+	return importRunResult(vaultPath, serviceResult, preview, map[string]interface{}{
+		"preview":         preview,
+		"ignored_columns": ignored,
+	})
+}
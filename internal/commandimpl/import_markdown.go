@@ -0,0 +1,63 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/importsvc"
+)
+
+// HandleImportMarkdown executes the canonical `import markdown` command.
+func HandleImportMarkdown(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+
+	dryRun := boolArg(req.Args, "dry-run")
+	result, err := importsvc.ImportMarkdown(importsvc.MarkdownImportRequest{
+		VaultPath:   vaultPath,
+		VaultConfig: vaultCfg,
+		SourceDir:   strings.TrimSpace(stringArg(req.Args, "dir")),
+		Destination: strings.TrimSpace(stringArg(req.Args, "to")),
+		DryRun:      dryRun,
+	})
+	if err != nil {
+		return mapImportFailure(err, "Pass a folder of markdown files outside the vault")
+	}
+
+	var warnings []commandexec.Warning
+	if !dryRun {
+		stamper := newAttributionStamper(vaultPath, vaultCfg)
+		for _, changedFile := range result.ChangedFilePaths {
+			stamper.stamp(true, changedFile)
+			warnings = appendCommandWarnings(warnings, autoReindexWarnings(vaultPath, vaultCfg, changedFile))
+		}
+	}
+
+	renamed := 0
+	for _, file := range result.Files {
+		if file.Renamed {
+			renamed++
+		}
+	}
+	unresolved := result.Unresolved
+	if unresolved == nil {
+		unresolved = []importsvc.UnresolvedLink{}
+	}
+	return commandexec.SuccessWithWarnings(map[string]interface{}{
+		"dry_run":     dryRun,
+		"destination": result.Destination,
+		"total":       len(result.Files),
+		"renamed":     renamed,
+		"files":       result.Files,
+		"unresolved":  unresolved,
+	}, warnings, &commandexec.Meta{Count: len(result.Files)})
+}
@@ -0,0 +1,71 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/importsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// HandleImportObsidian executes the canonical `import obsidian` command.
+func HandleImportObsidian(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema.yaml", nil, "Fix schema.yaml and try again")
+	}
+
+	dryRun := boolArg(req.Args, "dry-run")
+	result, err := importsvc.ImportObsidian(importsvc.ObsidianImportRequest{
+		VaultPath:   vaultPath,
+		VaultConfig: vaultCfg,
+		Schema:      sch,
+		SourceDir:   strings.TrimSpace(stringArg(req.Args, "dir")),
+		Destination: strings.TrimSpace(stringArg(req.Args, "to")),
+		TagTrait:    strings.TrimSpace(stringArg(req.Args, "tag-trait")),
+		DryRun:      dryRun,
+	})
+	if err != nil {
+		return mapImportFailure(err, "Pass an Obsidian vault folder outside this vault")
+	}
+
+	var warnings []commandexec.Warning
+	if !dryRun {
+		stamper := newAttributionStamper(vaultPath, vaultCfg)
+		for _, changedFile := range result.ChangedFilePaths {
+			stamper.stamp(true, changedFile)
+			warnings = appendCommandWarnings(warnings, autoReindexWarnings(vaultPath, vaultCfg, changedFile))
+		}
+	}
+
+	unresolved := result.Unresolved
+	if unresolved == nil {
+		unresolved = []importsvc.UnresolvedLink{}
+	}
+	types := result.Types
+	if types == nil {
+		types = []importsvc.ProposedType{}
+	}
+	return commandexec.SuccessWithWarnings(map[string]interface{}{
+		"dry_run":          dryRun,
+		"destination":      result.Destination,
+		"tag_trait":        result.TagTrait,
+		"total":            len(result.Files),
+		"files":            result.Files,
+		"types":            types,
+		"unresolved":       unresolved,
+		"schema_proposal":  result.SchemaProposal,
+		"hashtags_indexed": vaultCfg.GetHashtagTrait() != "",
+	}, warnings, &commandexec.Meta{Count: len(result.Files)})
+}
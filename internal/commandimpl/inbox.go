@@ -0,0 +1,67 @@
+package commandimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/readsvc"
+)
+
+// HandleInboxList executes the canonical `inbox list` command.
+func HandleInboxList(_ context.Context, req commandexec.Request) commandexec.Result {
+	start := time.Now()
+
+	limit, _ := intArg(req.Args, "limit")
+	offset, _ := intArg(req.Args, "offset")
+	if limit < 0 {
+		return commandexec.Failure("INVALID_INPUT", "--limit must be >= 0", nil, "Use --limit 0 for no limit")
+	}
+	if offset < 0 {
+		return commandexec.Failure("INVALID_INPUT", "--offset must be >= 0", nil, "Use --offset 0 for no offset")
+	}
+
+	rt, failure := newReadRuntime(req.VaultPath, readsvc.RuntimeOptions{OpenDB: true})
+	if failure.Error != nil {
+		return failure
+	}
+	defer rt.Close()
+
+	freshness, freshnessFailure := checkIndexFreshness(rt, req.Args)
+	if freshnessFailure != nil {
+		return *freshnessFailure
+	}
+
+	result, err := readsvc.ListInbox(rt, readsvc.ListInboxRequest{
+		Dir:            stringArg(req.Args, "dir"),
+		IncludeUntyped: boolArg(req.Args, "untyped"),
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		return commandexec.Failure("DATABASE_ERROR", err.Error(), nil, "Run 'rvn reindex' to rebuild the database")
+	}
+
+	items := make([]map[string]interface{}, len(result.Items))
+	for i, item := range result.Items {
+		items[i] = map[string]interface{}{
+			"num":       offset + i + 1,
+			"id":        item.Object.ID,
+			"type":      item.Object.Type,
+			"fields":    item.Object.Fields,
+			"file_path": item.Object.FilePath,
+			"line":      item.Object.LineStart,
+			"reason":    item.Reason,
+		}
+	}
+
+	return commandexec.Success(map[string]interface{}{
+		"dir":      result.Dir,
+		"untyped":  boolArg(req.Args, "untyped"),
+		"items":    items,
+		"total":    result.Total,
+		"returned": len(items),
+		"offset":   offset,
+		"limit":    limit,
+	}, &commandexec.Meta{Count: len(items), QueryTimeMs: time.Since(start).Milliseconds(), Freshness: freshness})
+}
@@ -0,0 +1,59 @@
+package commandimpl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/issuesvc"
+	"github.com/aidanlsb/raven/internal/model"
+)
+
+// enrichIssueRefs fills in live issue titles and statuses when
+// issue_refs.fetch is enabled. Failed lookups leave refs as indexed and are
+// reported as a single warning.
+func enrichIssueRefs(ctx context.Context, vaultPath string, vaultCfg *config.VaultConfig, refs []model.IssueRef) ([]model.IssueRef, []commandexec.Warning) {
+	result := issuesvc.Enrich(issuesvc.EnrichRequest{
+		VaultPath: vaultPath,
+		Config:    vaultCfg.GetIssueRefs(),
+		Refs:      refs,
+		Context:   ctx,
+	})
+	if len(result.Failed) == 0 {
+		return result.Refs, nil
+	}
+	message := fmt.Sprintf("could not fetch %d issue(s): %s", len(result.Failed), strings.Join(result.Failed, ", "))
+	if result.Err != nil {
+		message += " (" + result.Err.Error() + ")"
+	}
+	return result.Refs, []commandexec.Warning{{Code: codes.WarnIssueFetchFailed, Message: message}}
+}
+
+// enrichSelectedIssues enriches the issues column of projected type query
+// rows with one lookup pass over all rows.
+func enrichSelectedIssues(ctx context.Context, vaultPath string, vaultCfg *config.VaultConfig, items []map[string]interface{}) []commandexec.Warning {
+	var all []model.IssueRef
+	for _, item := range items {
+		refs, _ := item[querySelectIssues].([]model.IssueRef)
+		all = append(all, refs...)
+	}
+	if len(all) == 0 {
+		return nil
+	}
+
+	enriched, warnings := enrichIssueRefs(ctx, vaultPath, vaultCfg, all)
+	byKey := make(map[string]model.IssueRef, len(enriched))
+	for _, ref := range enriched {
+		byKey[ref.Provider+":"+ref.Key] = ref
+	}
+	for _, item := range items {
+		refs, _ := item[querySelectIssues].([]model.IssueRef)
+		for i, ref := range refs {
+			refs[i] = byKey[ref.Provider+":"+ref.Key]
+		}
+	}
+	return warnings
+}
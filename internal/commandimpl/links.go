@@ -0,0 +1,178 @@
+package commandimpl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/bulkops"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/graphsvc"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// HandleOrphans executes the canonical `orphans` command.
+func HandleOrphans(ctx context.Context, req commandexec.Request) commandexec.Result {
+	start := time.Now()
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	filter := linkFilterArgs(req.Args)
+	orphans, err := graphsvc.Orphans(vaultPath, filter)
+	if err != nil {
+		return mapGraphSvcFailure(err)
+	}
+
+	if applyArgs := keyValuePairs(req.Args["apply"]); len(applyArgs) > 0 {
+		rawApply, err := bulkops.ParseRawApply(applyArgs)
+		if err != nil {
+			return mapBulkopsFailure(err)
+		}
+		ids := make([]string, 0, len(orphans))
+		for _, orphan := range orphans {
+			ids = append(ids, orphan.ID)
+		}
+		return applyToObjects(ctx, req, rawApply, ids, time.Since(start).Milliseconds())
+	}
+
+	items := make([]map[string]interface{}, len(orphans))
+	for i, orphan := range orphans {
+		items[i] = map[string]interface{}{
+			"num":       i + 1,
+			"id":        orphan.ID,
+			"type":      orphan.Type,
+			"file_path": orphan.FilePath,
+			"line":      orphan.Line,
+		}
+	}
+	return commandexec.Success(map[string]interface{}{
+		"items": items,
+		"total": len(items),
+		"type":  filter.Types,
+		"dir":   filter.Dir,
+	}, &commandexec.Meta{Count: len(items), QueryTimeMs: time.Since(start).Milliseconds()})
+}
+
+// HandleDeadlinks executes the canonical `deadlinks` command.
+func HandleDeadlinks(_ context.Context, req commandexec.Request) commandexec.Result {
+	start := time.Now()
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	filter := linkFilterArgs(req.Args)
+	links, err := graphsvc.DeadLinks(vaultPath, filter)
+	if err != nil {
+		return mapGraphSvcFailure(err)
+	}
+
+	action := strings.TrimSpace(stringArg(req.Args, "apply"))
+	if action == "" {
+		items := make([]map[string]interface{}, len(links))
+		for i, link := range links {
+			items[i] = map[string]interface{}{
+				"num":          i + 1,
+				"source_id":    link.SourceID,
+				"source_type":  link.SourceType,
+				"target":       link.Target,
+				"display_text": link.DisplayText,
+				"file_path":    link.FilePath,
+				"line":         link.Line,
+			}
+		}
+		return commandexec.Success(map[string]interface{}{
+			"items": items,
+			"total": len(items),
+			"type":  filter.Types,
+			"dir":   filter.Dir,
+		}, &commandexec.Meta{Count: len(items), QueryTimeMs: time.Since(start).Milliseconds()})
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+	}
+
+	result, err := graphsvc.ApplyDeadLinks(graphsvc.DeadLinkApplyRequest{
+		VaultPath: vaultPath,
+		VaultCfg:  vaultCfg,
+		Schema:    sch,
+		Action:    action,
+		Links:     links,
+		Confirm:   req.Confirm,
+	})
+	if err != nil {
+		return mapGraphSvcFailure(err)
+	}
+
+	data := map[string]interface{}{
+		"preview":       !req.Confirm,
+		"action":        result.Action,
+		"total":         len(links),
+		"stubs":         result.Stubs,
+		"unlinked":      result.Unlinked,
+		"skipped":       result.Skipped,
+		"changed_files": result.ChangedFiles,
+	}
+	meta := &commandexec.Meta{Count: len(result.Stubs) + len(result.Unlinked), QueryTimeMs: time.Since(start).Milliseconds()}
+	if !req.Confirm {
+		return commandexec.Success(data, meta)
+	}
+
+	changed := make([]string, 0, len(result.ChangedFiles))
+	for _, filePath := range result.ChangedFiles {
+		changed = append(changed, filepath.Join(vaultPath, filepath.FromSlash(filePath)))
+	}
+	var warnings []commandexec.Warning
+	if len(result.Skipped) > 0 {
+		warnings = append(warnings, commandexec.Warning{
+			Code:    checkApplyIncompleteWarningCode,
+			Message: fmt.Sprintf("%d dead link(s) were skipped; see skipped for the reasons.", len(result.Skipped)),
+		})
+	}
+	warnings = append(warnings, autoReindexWarnings(vaultPath, vaultCfg, changed...)...)
+	if result.Action == graphsvc.DeadLinkStub && len(changed) > 0 && vaultCfg.IsAutoReindexEnabled() {
+		// Links elsewhere in the vault were indexed before their stubs existed.
+		if err := resolveIndexedReferences(vaultPath, vaultCfg, sch); err != nil {
+			warnings = append(warnings, indexUpdateWarning(vaultPath, vaultPath, "failed to resolve references", err))
+		}
+	}
+	return commandexec.SuccessWithWarnings(data, warnings, meta)
+}
+
+func resolveIndexedReferences(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema) error {
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	_, err = db.ResolveReferencesWithSchema(vaultCfg.GetDailyDirectory(), sch)
+	return err
+}
+
+func linkFilterArgs(args map[string]interface{}) graphsvc.LinkFilter {
+	return graphsvc.LinkFilter{
+		Types: stringSliceArg(args["type"]),
+		Dir:   strings.TrimSpace(stringArg(args, "dir")),
+	}
+}
+
+func mapGraphSvcFailure(err error) commandexec.Result {
+	svcErr, ok := graphsvc.AsError(err)
+	if !ok {
+		return commandexec.Failure("INTERNAL_ERROR", err.Error(), nil, "")
+	}
+	return commandexec.Failure(svcErr.Code, svcErr.Message, nil, svcErr.Suggestion)
+}
@@ -0,0 +1,114 @@
+package commandimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/readsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// HandleList executes the canonical `list` command.
+func HandleList(_ context.Context, req commandexec.Request) commandexec.Result {
+	start := time.Now()
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	limit, _ := intArg(req.Args, "limit")
+	offset, _ := intArg(req.Args, "offset")
+	if limit < 0 {
+		return commandexec.Failure("INVALID_INPUT", "--limit must be >= 0", nil, "Use --limit 0 for no limit")
+	}
+	if offset < 0 {
+		return commandexec.Failure("INVALID_INPUT", "--offset must be >= 0", nil, "Use --offset 0 for no offset")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+	}
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return commandexec.Failure("DATABASE_ERROR", "failed to open database", nil, "Run 'rvn reindex' to rebuild the database")
+	}
+	defer db.Close()
+	db.SetDailyDirectory(vaultCfg.GetDailyDirectory())
+	compatible, err := db.SchemaCompatible()
+	if err != nil {
+		return commandexec.Failure(codes.ErrDatabase, "failed to read index schema version", nil, "Run 'rvn reindex --full' to rebuild the index")
+	}
+	if !compatible {
+		return commandexec.Failure(codes.ErrDatabaseVersion, "index schema is stale or incompatible", nil, "Run 'rvn reindex --full' to rebuild the index")
+	}
+
+	rt := &readsvc.Runtime{
+		VaultPath: vaultPath,
+		VaultCfg:  vaultCfg,
+		Schema:    sch,
+		DB:        db,
+	}
+	freshness, failure := checkIndexFreshness(rt, req.Args)
+	if failure != nil {
+		return *failure
+	}
+
+	typeName := strings.TrimSpace(stringArg(req.Args, "type"))
+	result, err := readsvc.ListObjects(rt, readsvc.ListObjectsRequest{
+		TypeName:   typeName,
+		Sort:       stringArg(req.Args, "sort"),
+		Descending: boolArg(req.Args, "desc"),
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		var sortErr *readsvc.ListSortError
+		if errors.As(err, &sortErr) {
+			suggestion := "Sort by .id or .display_name"
+			if len(sortErr.Fields) > 0 {
+				suggestion = fmt.Sprintf("Sort by .id, .display_name, or one of: .%s", strings.Join(sortErr.Fields, ", ."))
+			}
+			return commandexec.Failure("INVALID_INPUT", sortErr.Error(), nil, suggestion)
+		}
+		return mapExecuteQueryFailure("type:"+typeName, err)
+	}
+
+	meta := &commandexec.Meta{Count: len(result.Objects), QueryTimeMs: time.Since(start).Milliseconds(), Freshness: freshness}
+	if boolArg(req.Args, "ids") {
+		ids := make([]string, 0, len(result.Objects))
+		for _, obj := range result.Objects {
+			ids = append(ids, obj.ID)
+		}
+		return commandexec.Success(map[string]interface{}{
+			"ids":      ids,
+			"total":    result.Total,
+			"returned": len(ids),
+			"offset":   offset,
+			"limit":    limit,
+		}, meta)
+	}
+
+	return commandexec.Success(map[string]interface{}{
+		"query_kind": "type",
+		"type":       result.TypeName,
+		"sort":       result.Sort,
+		"desc":       boolArg(req.Args, "desc"),
+		"items":      objectQueryItems(&readsvc.ExecuteQueryResult{Objects: result.Objects, Offset: offset}),
+		"total":      result.Total,
+		"returned":   len(result.Objects),
+		"offset":     offset,
+		"limit":      limit,
+	}, meta)
+}
@@ -0,0 +1,102 @@
+package commandimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func newListTestVault(t *testing.T) *testutil.TestVault {
+	t.Helper()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(`version: 1
+types:
+  person:
+    default_path: person/
+    name_field: name
+    fields:
+      name:
+        type: string
+      age:
+        type: number
+`).
+		WithFile("person/carol.md", "---\ntype: person\nname: carol\nage: 9\n---\n").
+		WithFile("person/alice.md", "---\ntype: person\nname: Alice\nage: 30\n---\n").
+		WithFile("person/bob.md", "---\ntype: person\nname: Bob\n---\n").
+		Build()
+	reindexForEditTest(t, v.Path)
+	return v
+}
+
+func TestHandleListSortsBeforePaginating(t *testing.T) {
+	t.Parallel()
+
+	v := newListTestVault(t)
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want []string
+	}{
+		{
+			name: "default sort by display name",
+			args: map[string]any{"type": "person", "ids": true},
+			want: []string{"person/alice", "person/bob", "person/carol"},
+		},
+		{
+			name: "numeric field with missing values last",
+			args: map[string]any{"type": "person", "sort": ".age", "ids": true},
+			want: []string{"person/carol", "person/alice", "person/bob"},
+		},
+		{
+			name: "descending keeps missing values last",
+			args: map[string]any{"type": "person", "sort": "age", "desc": true, "ids": true},
+			want: []string{"person/alice", "person/carol", "person/bob"},
+		},
+		{
+			name: "limit and offset page the sorted order",
+			args: map[string]any{"type": "person", "sort": ".name", "limit": 1, "offset": 1, "ids": true},
+			want: []string{"person/bob"},
+		},
+	}
+
+	for _, tt := range tests {
+		result := HandleList(context.Background(), commandexec.Request{VaultPath: v.Path, Args: tt.args})
+		if !result.OK {
+			t.Fatalf("%s: HandleList() failed: %#v", tt.name, result.Error)
+		}
+		data := result.Data.(map[string]interface{})
+		got, _ := data["ids"].([]string)
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: ids = %v, want %v", tt.name, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("%s: ids = %v, want %v", tt.name, got, tt.want)
+			}
+		}
+		if data["total"] != 3 {
+			t.Fatalf("%s: total = %#v, want 3", tt.name, data["total"])
+		}
+	}
+}
+
+func TestHandleListUnknownSortFieldFails(t *testing.T) {
+	t.Parallel()
+
+	v := newListTestVault(t)
+
+	result := HandleList(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args:      map[string]any{"type": "person", "sort": ".height"},
+	})
+	if result.OK {
+		t.Fatalf("HandleList() succeeded, want unknown sort field failure: %#v", result.Data)
+	}
+	if result.Error == nil || result.Error.Code != "INVALID_INPUT" {
+		t.Fatalf("error = %#v, want INVALID_INPUT", result.Error)
+	}
+}
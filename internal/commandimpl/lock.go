@@ -0,0 +1,100 @@
+package commandimpl
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/readsvc"
+	"github.com/aidanlsb/raven/internal/vaultconfigsvc"
+)
+
+// HandleLock executes the canonical `lock` command.
+func HandleLock(_ context.Context, req commandexec.Request) commandexec.Result {
+	relPath, failure := resolveLockTarget(req, false)
+	if failure != nil {
+		return *failure
+	}
+
+	result, err := vaultconfigsvc.LockFile(vaultconfigsvc.LockFileRequest{
+		VaultPath: req.VaultPath,
+		Path:      relPath,
+	})
+	if err != nil {
+		return mapVaultConfigFailure(err)
+	}
+	return commandexec.Success(map[string]interface{}{
+		"config_path":  result.ConfigPath,
+		"created":      result.Created,
+		"changed":      result.Changed,
+		"file":         result.Path,
+		"locked_files": result.LockedFiles,
+	}, nil)
+}
+
+// HandleUnlock executes the canonical `unlock` command.
+func HandleUnlock(_ context.Context, req commandexec.Request) commandexec.Result {
+	relPath, failure := resolveLockTarget(req, true)
+	if failure != nil {
+		return *failure
+	}
+
+	result, err := vaultconfigsvc.UnlockFile(vaultconfigsvc.UnlockFileRequest{
+		VaultPath: req.VaultPath,
+		Path:      relPath,
+	})
+	if err != nil {
+		return mapVaultConfigFailure(err)
+	}
+	return commandexec.Success(map[string]interface{}{
+		"config_path":  result.ConfigPath,
+		"changed":      result.Changed,
+		"file":         result.Removed,
+		"locked_files": result.LockedFiles,
+	}, nil)
+}
+
+// resolveLockTarget resolves the reference argument to a vault-relative file
+// path. When unlocking, an entry already listed in locked_files is accepted
+// as-is so entries for moved or deleted files can still be removed.
+func resolveLockTarget(req commandexec.Request, unlocking bool) (string, *commandexec.Result) {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		result := commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+		return "", &result
+	}
+
+	reference := strings.TrimSpace(stringArg(req.Args, "reference"))
+	if reference == "" {
+		result := commandexec.Failure("MISSING_ARGUMENT", "requires reference argument", nil, "Usage: rvn lock <reference>")
+		return "", &result
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		result := commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+		return "", &result
+	}
+	if unlocking && vaultCfg.IsLockedPath(reference) {
+		return paths.NormalizeVaultRelPath(reference), nil
+	}
+
+	resolved, err := readsvc.ResolveReference(reference, &readsvc.Runtime{
+		VaultPath: vaultPath,
+		VaultCfg:  vaultCfg,
+	}, false)
+	if err != nil {
+		result := mapResolveFailure(err, reference)
+		return "", &result
+	}
+
+	relPath, err := filepath.Rel(vaultPath, resolved.FilePath)
+	if err != nil {
+		result := commandexec.Failure("FILE_OUTSIDE_VAULT", "resolved file is outside the vault", nil, "")
+		return "", &result
+	}
+	return paths.NormalizeVaultRelPath(relPath), nil
+}
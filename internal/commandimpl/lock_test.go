@@ -0,0 +1,135 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestLockedFileRefusesMutationWithoutUnlock(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(`version: 1
+types:
+  note:
+    default_path: note/
+    fields:
+      status:
+        type: string
+`).
+		WithFile("note/canon.md", "---\ntype: note\nstatus: draft\n---\nCanonical text\n").
+		Build()
+
+	lock := HandleLock(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args:      map[string]any{"reference": "note/canon"},
+	})
+	if !lock.OK {
+		t.Fatalf("HandleLock() failed: %#v", lock.Error)
+	}
+	if file := lock.Data.(map[string]interface{})["file"]; file != "note/canon.md" {
+		t.Fatalf("locked file = %#v, want note/canon.md", file)
+	}
+
+	setArgs := map[string]any{
+		"object_id": "note/canon",
+		"fields":    map[string]any{"status": "final"},
+	}
+	refused := HandleSet(context.Background(), commandexec.Request{VaultPath: v.Path, Args: setArgs})
+	if refused.OK || refused.Error == nil || refused.Error.Code != codes.ErrFileLocked {
+		t.Fatalf("HandleSet() on locked file = %#v, want FILE_LOCKED", refused.Error)
+	}
+
+	edit := HandleEdit(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args: map[string]any{
+			"path":    "note/canon",
+			"old_str": "Canonical text",
+			"new_str": "Changed text",
+		},
+	})
+	if edit.OK || edit.Error == nil || edit.Error.Code != codes.ErrFileLocked {
+		t.Fatalf("HandleEdit() on locked file = %#v, want FILE_LOCKED", edit.Error)
+	}
+
+	setArgs["unlock"] = true
+	allowed := HandleSet(context.Background(), commandexec.Request{VaultPath: v.Path, Args: setArgs})
+	if !allowed.OK {
+		t.Fatalf("HandleSet() with unlock failed: %#v", allowed.Error)
+	}
+	if content := v.ReadFile("note/canon.md"); !strings.Contains(content, "status: final") {
+		t.Fatalf("expected --unlock set to apply, got:\n%s", content)
+	}
+
+	unlock := HandleUnlock(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args:      map[string]any{"reference": "note/canon.md"},
+	})
+	if !unlock.OK {
+		t.Fatalf("HandleUnlock() failed: %#v", unlock.Error)
+	}
+	delete(setArgs, "unlock")
+	setArgs["fields"] = map[string]any{"status": "archived"}
+	if result := HandleSet(context.Background(), commandexec.Request{VaultPath: v.Path, Args: setArgs}); !result.OK {
+		t.Fatalf("HandleSet() after unlock failed: %#v", result.Error)
+	}
+}
+
+func TestFrontmatterLockedFileRefusesMutationWithoutUnlock(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(`version: 1
+types:
+  note:
+    default_path: note/
+    fields:
+      status:
+        type: string
+`).
+		WithFile("note/canon.md", "---\ntype: note\nlocked: true\nstatus: draft\n---\nCanonical text\n").
+		Build()
+
+	setArgs := map[string]any{
+		"object_id": "note/canon",
+		"fields":    map[string]any{"status": "final"},
+	}
+	refused := HandleSet(context.Background(), commandexec.Request{VaultPath: v.Path, Args: setArgs})
+	if refused.OK || refused.Error == nil || refused.Error.Code != codes.ErrFileLocked {
+		t.Fatalf("HandleSet() on frontmatter-locked file = %#v, want FILE_LOCKED", refused.Error)
+	}
+
+	edit := HandleEdit(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args: map[string]any{
+			"path":    "note/canon",
+			"old_str": "Canonical text",
+			"new_str": "Changed text",
+		},
+	})
+	if edit.OK || edit.Error == nil || edit.Error.Code != codes.ErrFileLocked {
+		t.Fatalf("HandleEdit() on frontmatter-locked file = %#v, want FILE_LOCKED", edit.Error)
+	}
+
+	deleted := HandleDelete(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args:      map[string]any{"object_id": "note/canon"},
+	})
+	if deleted.OK || deleted.Error == nil || deleted.Error.Code != codes.ErrFileLocked {
+		t.Fatalf("HandleDelete() on frontmatter-locked file = %#v, want FILE_LOCKED", deleted.Error)
+	}
+
+	setArgs["unlock"] = true
+	allowed := HandleSet(context.Background(), commandexec.Request{VaultPath: v.Path, Args: setArgs})
+	if !allowed.OK {
+		t.Fatalf("HandleSet() with unlock failed: %#v", allowed.Error)
+	}
+	if content := v.ReadFile("note/canon.md"); !strings.Contains(content, "status: final") {
+		t.Fatalf("expected --unlock set to apply, got:\n%s", content)
+	}
+}
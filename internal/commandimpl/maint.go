@@ -12,7 +12,10 @@ import (
 func HandleVaultStats(_ context.Context, req commandexec.Request) commandexec.Result {
 	start := time.Now()
 
-	stats, err := maintsvc.Stats(req.VaultPath)
+	stats, err := maintsvc.Stats(req.VaultPath, maintsvc.StatsOptions{
+		ByAuthor: boolArg(req.Args, "by-author"),
+		Since:    stringArg(req.Args, "since"),
+	})
 	if err != nil {
 		svcErr, ok := maintsvc.AsError(err)
 		if !ok {
@@ -21,10 +24,19 @@ func HandleVaultStats(_ context.Context, req commandexec.Request) commandexec.Re
 		return commandexec.Failure(svcErr.Code, svcErr.Message, nil, svcErr.Suggestion)
 	}
 
-	return commandexec.Success(map[string]interface{}{
+	data := map[string]interface{}{
 		"file_count":   stats.FileCount,
 		"object_count": stats.ObjectCount,
 		"trait_count":  stats.TraitCount,
 		"ref_count":    stats.RefCount,
-	}, &commandexec.Meta{QueryTimeMs: time.Since(start).Milliseconds()})
+		"orphan_count": stats.OrphanCount,
+	}
+	if stats.Authors != nil {
+		data["authors"] = stats.Authors
+	}
+	if stats.Activity != nil {
+		data["activity"] = stats.Activity
+	}
+
+	return commandexec.Success(data, &commandexec.Meta{QueryTimeMs: time.Since(start).Milliseconds()})
 }
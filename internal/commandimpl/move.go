@@ -23,6 +23,7 @@ func HandleMove(_ context.Context, req commandexec.Request) commandexec.Result {
 	if err != nil {
 		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
 	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
 
 	sch, err := schema.Load(vaultPath)
 	if err != nil {
@@ -39,7 +40,7 @@ func HandleMove(_ context.Context, req commandexec.Request) commandexec.Result {
 		if len(objectIDs) == 0 {
 			return commandexec.Failure("MISSING_ARGUMENT", "no object IDs provided via stdin", nil, "Provide object IDs when using bulk move")
 		}
-		return runMoveBulk(vaultPath, vaultCfg, sch, objectIDs, destination, boolArgDefault(req.Args, "update-refs", true), req.Confirm)
+		return withBulkFailureReport(runMoveBulk(vaultPath, vaultCfg, sch, objectIDs, destination, boolArgDefault(req.Args, "update-refs", true), req.Confirm), req, "object_ids")
 	}
 
 	source := strings.TrimSpace(stringArg(req.Args, "source"))
@@ -69,6 +69,7 @@ func HandleNew(_ context.Context, req commandexec.Request) commandexec.Result {
 		return mapContentMutationError(err)
 	}
 
+	stampAttribution(vaultPath, vaultCfg, true, result.FilePath)
 	warnings := autoReindexWarnings(vaultPath, vaultCfg, result.FilePath)
 
 	data := map[string]interface{}{
@@ -97,7 +98,7 @@ func mapContentMutationError(err error) commandexec.Result {
 
 	var validationErr *fieldmutation.ValidationError
 	if errors.As(err, &validationErr) {
-		return commandexec.Failure("VALIDATION_FAILED", validationErr.Error(), nil, validationErr.Suggestion())
+		return commandexec.Failure("VALIDATION_FAILED", validationErr.Error(), validationErr.Details(), validationErr.Suggestion())
 	}
 
 	return commandexec.Failure(codes.ErrInternal, err.Error(), nil, "")
@@ -121,6 +122,7 @@ func HandleUpsert(_ context.Context, req commandexec.Request) commandexec.Result
 	if err != nil {
 		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
 	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
 
 	sch, err := schema.Load(vaultPath)
 	if err != nil {
@@ -177,6 +179,7 @@ func HandleUpsert(_ context.Context, req commandexec.Request) commandexec.Result
 		"title":  title,
 	}
 	if result.Status == "created" || result.Status == "updated" {
+		stampAttribution(vaultPath, vaultCfg, result.Status == "created", result.FilePath)
 		warnings = appendCommandWarnings(warnings, autoReindexWarnings(vaultPath, vaultCfg, result.FilePath))
 		missingData, missingWarnings := missingRefEnvelope(vaultPath, vaultCfg, sch, result.RelativePath)
 		data = mergeDataFields(data, missingData)
@@ -0,0 +1,238 @@
+package commandimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/history"
+	"github.com/aidanlsb/raven/internal/textmerge"
+	"github.com/aidanlsb/raven/internal/vault"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+// withPreviewGuard wraps a bulk-capable handler so an applied run notices
+// files that changed on disk after the same call was previewed. The preview
+// records the content of each target file; the apply is blocked when any of
+// them changed, unless merge is passed, in which case the planned edit is
+// made against the previewed content and three-way merged into the new one.
+// Without a recorded preview the run applies as usual.
+func withPreviewGuard(idsKey string, canMerge bool, handler commandexec.Handler) commandexec.Handler {
+	return func(ctx context.Context, req commandexec.Request) commandexec.Result {
+		vaultPath := strings.TrimSpace(req.VaultPath)
+		ids := commandIDsArg(req.Args, idsKey)
+		if vaultPath == "" || len(ids) == 0 {
+			return handler(ctx, req)
+		}
+		if _, resuming := resumeCheckpointFromContext(ctx); resuming {
+			return handler(ctx, req)
+		}
+
+		key := previewKey(req)
+		if !req.Confirm {
+			result := handler(ctx, req)
+			if result.OK {
+				_ = history.SavePreview(vaultPath, key, previewTargetFiles(vaultPath, idsKey, ids))
+			}
+			return result
+		}
+
+		preview, err := history.LoadPreview(vaultPath, key)
+		if err != nil || preview == nil {
+			return handler(ctx, req)
+		}
+		changed := preview.Changed(vaultPath)
+		if len(changed) == 0 {
+			result := handler(ctx, req)
+			history.DeletePreview(vaultPath, key)
+			return result
+		}
+
+		paths := make([]string, len(changed))
+		for i, file := range changed {
+			paths[i] = file.Path
+		}
+		if !canMerge || !boolArg(req.Args, "merge") {
+			suggestion := "Run the command again without --confirm to preview it against the new content"
+			if canMerge {
+				suggestion += ", or pass --merge to merge the planned edit into it"
+			}
+			return commandexec.Failure(
+				codes.ErrPreviewStale,
+				fmt.Sprintf("%d file(s) changed since the preview: %s", len(changed), strings.Join(paths, ", ")),
+				map[string]interface{}{"paths": paths},
+				suggestion,
+			)
+		}
+
+		result, merged, conflicts := applyWithMerge(ctx, req, handler, changed)
+		history.DeletePreview(vaultPath, key)
+		if !result.OK {
+			return result
+		}
+		if data, ok := result.Data.(map[string]interface{}); ok {
+			data["merged"] = nonNilStrings(merged)
+			data["merge_conflicts"] = nonNilStrings(conflicts)
+		}
+		if len(conflicts) > 0 {
+			result.Warnings = append(result.Warnings, commandexec.Warning{
+				Code:    codes.WarnMergeConflict,
+				Message: fmt.Sprintf("the planned edit conflicts with changes made since the preview and was not applied to: %s", strings.Join(conflicts, ", ")),
+			})
+		}
+		if vaultCfg, err := config.LoadVaultConfig(vaultPath); err == nil {
+			files := make([]string, 0, len(merged)+len(conflicts))
+			for _, rel := range append(append([]string(nil), merged...), conflicts...) {
+				files = append(files, filepath.Join(vaultPath, filepath.FromSlash(rel)))
+			}
+			result.Warnings = append(result.Warnings, autoReindexWarnings(vaultPath, vaultCfg, files...)...)
+		}
+		return result
+	}
+}
+
+// applyWithMerge puts the previewed content of the changed files back, runs
+// the planned edit on it, and three-way merges the result with the content
+// found on disk. Files that do not merge cleanly keep the content found on
+// disk. The restores and merges go through atomicfile, so history records
+// the content found on disk as each file's before-image.
+//
+// Every file's content is read before any is touched, and any failure puts
+// all of them back, so a partial restore or merge never loses the changes
+// made since the preview.
+func applyWithMerge(ctx context.Context, req commandexec.Request, handler commandexec.Handler, changed []history.PreviewFile) (commandexec.Result, []string, []string) {
+	type mergeFile struct {
+		history.PreviewFile
+		abs     string
+		current []byte
+	}
+
+	var files []mergeFile
+	for _, file := range changed {
+		abs := filepath.Join(req.VaultPath, filepath.FromSlash(file.Path))
+		current, err := os.ReadFile(abs)
+		if !file.Exists || err != nil {
+			// Created or deleted since the preview: there is no edit to
+			// merge, so the command sees the file as it is now.
+			continue
+		}
+		files = append(files, mergeFile{PreviewFile: file, abs: abs, current: current})
+	}
+
+	// rollback puts every file back to the content found on disk and
+	// returns the ones it could not write.
+	rollback := func() []string {
+		var lost []string
+		for _, file := range files {
+			if err := atomicfile.WriteFile(file.abs, file.current, 0); err != nil {
+				lost = append(lost, file.Path)
+			}
+		}
+		return lost
+	}
+
+	for _, file := range files {
+		if err := atomicfile.WriteFile(file.abs, file.Content, 0); err != nil {
+			return mergeRollbackFailure(fmt.Sprintf("failed to restore %s for merging: %v", file.Path, err), rollback()), nil, nil
+		}
+	}
+
+	result := handler(ctx, req)
+	if !result.OK {
+		if lost := rollback(); len(lost) > 0 {
+			return mergeRollbackFailure(result.Error.Message, lost), nil, nil
+		}
+		return result, nil, nil
+	}
+
+	var merged, conflicts []string
+	contents := make([][]byte, len(files))
+	for i, file := range files {
+		content, ok := mergeChangedFile(file.abs, file.Content, file.current)
+		if !ok {
+			content = file.current
+			conflicts = append(conflicts, file.Path)
+		} else {
+			merged = append(merged, file.Path)
+		}
+		contents[i] = content
+	}
+	for i, file := range files {
+		if err := atomicfile.WriteFile(file.abs, contents[i], 0); err != nil {
+			return mergeRollbackFailure(fmt.Sprintf("failed to write merged %s: %v", file.Path, err), rollback()), nil, nil
+		}
+	}
+	return result, merged, conflicts
+}
+
+// mergeRollbackFailure reports a failed merged apply, naming any files that
+// could not be put back to the content found on disk.
+func mergeRollbackFailure(message string, lost []string) commandexec.Result {
+	if len(lost) == 0 {
+		return commandexec.Failure(codes.ErrFileWrite, message, nil, "")
+	}
+	return commandexec.Failure(
+		codes.ErrFileWrite,
+		fmt.Sprintf("%s; could not put back: %s", message, strings.Join(lost, ", ")),
+		map[string]interface{}{"paths": lost},
+		"Run 'rvn undo' to revert the operation",
+	)
+}
+
+// mergeChangedFile merges the edit the command made to base into current,
+// all raw file bytes (encrypted for files in encrypted directories).
+func mergeChangedFile(abs string, baseRaw, currentRaw []byte) ([]byte, bool) {
+	oursRaw, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, false
+	}
+	base, errBase := vaultcrypt.Open(abs, baseRaw)
+	ours, errOurs := vaultcrypt.Open(abs, oursRaw)
+	current, errCurrent := vaultcrypt.Open(abs, currentRaw)
+	if err := errors.Join(errBase, errOurs, errCurrent); err != nil {
+		return nil, false
+	}
+	merged, ok := textmerge.Merge(string(base), string(ours), string(current))
+	if !ok {
+		return nil, false
+	}
+	return []byte(merged), true
+}
+
+// previewTargetFiles resolves bulk IDs to the vault-relative files they edit.
+// Trait IDs carry their file path; object IDs are resolved through the vault.
+func previewTargetFiles(vaultPath, idsKey string, ids []string) []string {
+	vaultCfg, _ := config.LoadVaultConfig(vaultPath)
+	files := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if idsKey == "trait_ids" {
+			if filePath, _, ok := strings.Cut(id, ":trait:"); ok {
+				files = append(files, filepath.ToSlash(filePath))
+			}
+			continue
+		}
+		objectID, _, _ := strings.Cut(id, "#")
+		abs, err := vault.ResolveObjectToFileWithConfig(vaultPath, objectID, vaultCfg)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(vaultPath, abs); err == nil {
+			files = append(files, filepath.ToSlash(rel))
+		}
+	}
+	return files
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
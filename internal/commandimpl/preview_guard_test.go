@@ -0,0 +1,110 @@
+package commandimpl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestPreviewGuardBlocksOrMergesFilesChangedSincePreview(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(`version: 1
+types:
+  note:
+    default_path: note/
+    fields:
+      status:
+        type: string
+`).
+		WithFile("note/a.md", "---\ntype: note\nstatus: draft\n---\nBody\n").
+		WithFile("note/b.md", "---\ntype: note\nstatus: draft\n---\nBody\n").
+		Build()
+
+	handler := withPreviewGuard("object_ids", true, HandleSet)
+	run := func(confirm bool, extra map[string]any) commandexec.Result {
+		args := map[string]any{
+			"object_ids": []any{"note/a", "note/b"},
+			"fields":     map[string]any{"status": "final"},
+			"stdin":      true,
+		}
+		for key, value := range extra {
+			args[key] = value
+		}
+		return handler(context.Background(), commandexec.Request{VaultPath: v.Path, Args: args, Confirm: confirm})
+	}
+
+	if preview := run(false, nil); !preview.OK {
+		t.Fatalf("preview failed: %#v", preview.Error)
+	}
+	v.WriteFile("note/a.md", "---\ntype: note\nstatus: draft\n---\nBody\n\nAdded in an editor.\n")
+
+	stale := run(true, nil)
+	if stale.OK || stale.Error == nil || stale.Error.Code != codes.ErrPreviewStale {
+		t.Fatalf("confirm after edit = %#v, want PREVIEW_STALE", stale.Error)
+	}
+	v.AssertFileContains("note/b.md", "status: draft")
+
+	merged := run(true, map[string]any{"merge": true})
+	if !merged.OK {
+		t.Fatalf("confirm with merge failed: %#v", merged.Error)
+	}
+	if got := v.ReadFile("note/a.md"); got != "---\ntype: note\nstatus: final\n---\nBody\n\nAdded in an editor.\n" {
+		t.Fatalf("merged note/a.md = %q", got)
+	}
+	v.AssertFileContains("note/b.md", "status: final")
+
+	// The preview is consumed by the apply, so a later confirm runs as usual.
+	v.WriteFile("note/a.md", "---\ntype: note\nstatus: review\n---\nBody\n")
+	if result := run(true, nil); !result.OK {
+		t.Fatalf("confirm without a preview failed: %#v", result.Error)
+	}
+	v.AssertFileContains("note/a.md", "status: final")
+}
+
+func TestPreviewGuardMergeRestoresFilesWhenApplyFails(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(`version: 1
+types:
+  note:
+    default_path: note/
+`).
+		WithFile("note/a.md", "---\ntype: note\n---\nBody\n").
+		Build()
+
+	handler := withPreviewGuard("object_ids", true, func(ctx context.Context, req commandexec.Request) commandexec.Result {
+		if req.Confirm {
+			// A partial edit before the failure must not survive it.
+			if err := os.WriteFile(filepath.Join(v.Path, "note", "a.md"), []byte("---\ntype: note\nstatus: half\n---\nBody\n"), 0o644); err != nil {
+				t.Error(err)
+			}
+			return commandexec.Failure(codes.ErrInternal, "apply failed", nil, "")
+		}
+		return commandexec.Success(map[string]interface{}{}, nil)
+	})
+	run := func(confirm bool) commandexec.Result {
+		args := map[string]any{"object_ids": []any{"note/a"}, "merge": true}
+		return handler(context.Background(), commandexec.Request{VaultPath: v.Path, Args: args, Confirm: confirm})
+	}
+
+	if preview := run(false); !preview.OK {
+		t.Fatalf("preview failed: %#v", preview.Error)
+	}
+	edited := "---\ntype: note\n---\nBody\n\nAdded in an editor.\n"
+	v.WriteFile("note/a.md", edited)
+
+	if result := run(true); result.OK || result.Error.Message != "apply failed" {
+		t.Fatalf("confirm = %#v, want the handler's failure", result.Error)
+	}
+	if got := v.ReadFile("note/a.md"); got != edited {
+		t.Fatalf("note/a.md = %q, want the content edited since the preview", got)
+	}
+}
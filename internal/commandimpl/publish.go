@@ -0,0 +1,58 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/publishsvc"
+)
+
+// HandlePublish executes the canonical `publish` command.
+func HandlePublish(_ context.Context, req commandexec.Request) commandexec.Result {
+	start := time.Now()
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	result, err := publishsvc.Publish(publishsvc.PublishRequest{
+		VaultPath: vaultPath,
+		Output:    strings.TrimSpace(stringArg(req.Args, "output")),
+		Query:     strings.TrimSpace(stringArg(req.Args, "query")),
+		All:       boolArg(req.Args, "all"),
+		Template:  strings.TrimSpace(stringArg(req.Args, "template")),
+		DryRun:    boolArg(req.Args, "dry-run"),
+	})
+	if err != nil {
+		svcErr, ok := publishsvc.AsError(err)
+		if !ok {
+			return commandexec.Failure("INTERNAL_ERROR", err.Error(), nil, "")
+		}
+		return commandexec.Failure(svcErr.Code, svcErr.Message, nil, svcErr.Suggestion)
+	}
+
+	pages := make([]map[string]interface{}, 0, len(result.Pages))
+	for _, page := range result.Pages {
+		pages = append(pages, map[string]interface{}{
+			"id":    page.ID,
+			"type":  page.Type,
+			"title": page.Title,
+			"file":  page.File,
+			"url":   page.URL,
+		})
+	}
+
+	data := map[string]interface{}{
+		"output":    result.Output,
+		"selection": result.Selection,
+		"pages":     pages,
+		"dry_run":   result.DryRun,
+	}
+	if !result.DryRun {
+		data["assets"] = result.Assets
+		data["removed"] = result.Removed
+	}
+	return commandexec.Success(data, &commandexec.Meta{Count: len(result.Pages), QueryTimeMs: time.Since(start).Milliseconds()})
+}
@@ -21,6 +21,9 @@ import (
 
 // HandleQuery executes the canonical `query` command path.
 func HandleQuery(ctx context.Context, req commandexec.Request) commandexec.Result {
+	if names := queryVaultNames(stringArg(req.Args, "vaults")); len(names) > 0 {
+		return handleFederatedQuery(ctx, req, names)
+	}
 	start := time.Now()
 	vaultPath := strings.TrimSpace(req.VaultPath)
 	if vaultPath == "" {
@@ -73,18 +76,16 @@ func HandleQuery(ctx context.Context, req commandexec.Request) commandexec.Resul
 		DB:        db,
 	}
 
-	if boolArg(req.Args, "refresh") {
-		if _, err := readsvc.SmartReindex(rt); err != nil {
-			return commandexec.Failure("DATABASE_ERROR", fmt.Sprintf("failed to refresh index: %v", err), nil, "Run 'rvn reindex' to rebuild the database")
-		}
-	} else {
-		_, _, _ = readsvc.CheckStaleness(rt)
+	freshness, failure := checkIndexFreshness(rt, req.Args)
+	if failure != nil {
+		return *failure
 	}
 
 	limit, _ := intArg(req.Args, "limit")
 	offset, _ := intArg(req.Args, "offset")
 	idsOnly := boolArg(req.Args, "ids")
 	countOnly := boolArg(req.Args, "count-only")
+	explainMatches := boolArg(req.Args, "explain-matches")
 
 	if limit < 0 {
 		return commandexec.Failure("INVALID_INPUT", "--limit must be >= 0", nil, "Use --limit 0 for no limit")
@@ -92,21 +93,46 @@ func HandleQuery(ctx context.Context, req commandexec.Request) commandexec.Resul
 	if offset < 0 {
 		return commandexec.Failure("INVALID_INPUT", "--offset must be >= 0", nil, "Use --offset 0 for no offset")
 	}
-	if len(applyArgs) > 0 && (limit > 0 || offset > 0 || countOnly) {
+	selectColumns, err := parseQuerySelect(stringArg(req.Args, "select"))
+	if err != nil {
+		return commandexec.Failure("INVALID_INPUT", err.Error(), nil, "Use a comma-separated list such as '.name, .status, backlinks'")
+	}
+	if len(selectColumns) > 0 && (idsOnly || countOnly || len(applyArgs) > 0) {
+		return commandexec.Failure("INVALID_INPUT", "--select cannot be used with --ids, --count-only, or --apply", nil, "Remove --select, or drop the conflicting flag")
+	}
+	if explainMatches && (idsOnly || countOnly || len(applyArgs) > 0) {
+		return commandexec.Failure("INVALID_INPUT", "--explain-matches cannot be used with --ids, --count-only, or --apply", nil, "Remove --explain-matches, or drop the conflicting flag")
+	}
+	cursor := strings.TrimSpace(stringArg(req.Args, "cursor"))
+	if len(applyArgs) > 0 && (limit > 0 || offset > 0 || countOnly || cursor != "") {
 		return commandexec.Failure(
 			"INVALID_INPUT",
-			"--limit, --offset, and --count-only cannot be used with --apply",
+			"--limit, --offset, --cursor, and --count-only cannot be used with --apply",
 			nil,
 			"Remove pagination/count-only flags when using --apply",
 		)
 	}
+	if cursor != "" {
+		if offset > 0 {
+			return commandexec.Failure("INVALID_INPUT", "--cursor cannot be combined with --offset", nil, "Pass either --cursor or --offset")
+		}
+		page, err := decodeQueryCursor(cursor, resolvedQuery)
+		if err != nil {
+			return commandexec.Failure("INVALID_INPUT", err.Error(), nil, "Pass the next_cursor returned by the previous page of the same query")
+		}
+		offset = page.Offset
+		if limit == 0 {
+			limit = page.Limit
+		}
+	}
 
 	result, err := readsvc.ExecuteQuery(rt, readsvc.ExecuteQueryRequest{
-		QueryString: resolvedQuery,
-		IDsOnly:     idsOnly,
-		Limit:       limit,
-		Offset:      offset,
-		CountOnly:   countOnly,
+		QueryString:    resolvedQuery,
+		IDsOnly:        idsOnly,
+		Limit:          limit,
+		Offset:         offset,
+		CountOnly:      countOnly,
+		ExplainMatches: explainMatches,
 	})
 	if err != nil {
 		return mapExecuteQueryFailure(resolvedQuery, err)
@@ -116,101 +142,143 @@ func HandleQuery(ctx context.Context, req commandexec.Request) commandexec.Resul
 		return handleQueryApply(ctx, req, result, applyArgs, time.Since(start).Milliseconds())
 	}
 
-	meta := &commandexec.Meta{QueryTimeMs: time.Since(start).Milliseconds()}
+	meta := &commandexec.Meta{QueryTimeMs: time.Since(start).Milliseconds(), Freshness: freshness}
+	warnings := degradedQueryWarnings(result.Degraded)
 	if countOnly {
 		meta.Count = result.Total
 		key := "type"
 		if result.QueryKind == "trait" {
 			key = "trait"
 		} else if result.QueryKind == "asset" || result.QueryKind == "section" {
-			return commandexec.Success(map[string]interface{}{
+			return commandexec.SuccessWithWarnings(map[string]interface{}{
 				"query_kind": result.QueryKind,
 				"total":      result.Total,
-			}, meta)
+			}, warnings, meta)
 		}
-		return commandexec.Success(map[string]interface{}{
+		return commandexec.SuccessWithWarnings(map[string]interface{}{
 			"query_kind": result.QueryKind,
 			key:          result.TypeName,
 			"total":      result.Total,
-		}, meta)
+		}, warnings, meta)
 	}
 
 	if idsOnly {
 		meta.Count = result.Returned
-		return commandexec.Success(map[string]interface{}{
+		data := map[string]interface{}{
 			"ids":      result.IDs,
 			"total":    result.Total,
 			"returned": result.Returned,
 			"offset":   result.Offset,
 			"limit":    result.Limit,
-		}, meta)
+		}
+		addQueryPage(data, resolvedQuery, result)
+		return commandexec.SuccessWithWarnings(data, warnings, meta)
 	}
 
 	if result.QueryKind == "type" {
 		meta.Count = result.Returned
+		items, failure := selectQueryItems(db, result.QueryKind, objectQueryItems(result), selectColumns)
+		if failure != nil {
+			return *failure
+		}
+		attachQueryMatches(items, result.Matches)
+		if querySelectHasColumn(selectColumns, querySelectIssues) {
+			warnings = append(warnings, enrichSelectedIssues(ctx, vaultPath, vaultCfg, items)...)
+		}
 		data := map[string]interface{}{
 			"query_kind": "type",
-			"items":      objectQueryItems(result),
+			"items":      items,
 			"total":      result.Total,
 			"returned":   result.Returned,
 			"offset":     result.Offset,
 			"limit":      result.Limit,
 		}
+		addQueryPage(data, resolvedQuery, result)
+		if len(selectColumns) > 0 {
+			data["select"] = querySelectNames(selectColumns)
+		}
 		if isSavedQuery && queryName != "" {
 			data["saved_query"] = queryName
 		} else {
 			data["type"] = result.TypeName
 		}
-		return commandexec.Success(data, meta)
+		return commandexec.SuccessWithWarnings(data, warnings, meta)
 	}
 
 	if result.QueryKind == "asset" {
 		meta.Count = result.Returned
+		items, failure := selectQueryItems(db, result.QueryKind, assetQueryItems(result), selectColumns)
+		if failure != nil {
+			return *failure
+		}
+		attachQueryMatches(items, result.Matches)
 		data := map[string]interface{}{
 			"query_kind": "asset",
-			"items":      assetQueryItems(result),
+			"items":      items,
 			"total":      result.Total,
 			"returned":   result.Returned,
 			"offset":     result.Offset,
 			"limit":      result.Limit,
 		}
+		addQueryPage(data, resolvedQuery, result)
+		if len(selectColumns) > 0 {
+			data["select"] = querySelectNames(selectColumns)
+		}
 		if isSavedQuery && queryName != "" {
 			data["saved_query"] = queryName
 		}
-		return commandexec.Success(data, meta)
+		return commandexec.SuccessWithWarnings(data, warnings, meta)
 	}
 
 	if result.QueryKind == "section" {
 		meta.Count = result.Returned
+		items, failure := selectQueryItems(db, result.QueryKind, sectionQueryItems(result), selectColumns)
+		if failure != nil {
+			return *failure
+		}
+		attachQueryMatches(items, result.Matches)
 		data := map[string]interface{}{
 			"query_kind": "section",
-			"items":      sectionQueryItems(result),
+			"items":      items,
 			"total":      result.Total,
 			"returned":   result.Returned,
 			"offset":     result.Offset,
 			"limit":      result.Limit,
 		}
+		addQueryPage(data, resolvedQuery, result)
+		if len(selectColumns) > 0 {
+			data["select"] = querySelectNames(selectColumns)
+		}
 		if isSavedQuery && queryName != "" {
 			data["saved_query"] = queryName
 		}
-		return commandexec.Success(data, meta)
+		return commandexec.SuccessWithWarnings(data, warnings, meta)
 	}
 
 	meta.Count = result.Returned
+	items, failure := selectQueryItems(db, result.QueryKind, traitQueryItems(result), selectColumns)
+	if failure != nil {
+		return *failure
+	}
+	attachQueryMatches(items, result.Matches)
 	data := map[string]interface{}{
 		"query_kind": "trait",
-		"items":      traitQueryItems(result),
+		"items":      items,
 		"total":      result.Total,
 		"returned":   result.Returned,
 		"offset":     result.Offset,
 		"limit":      result.Limit,
 	}
+	addQueryPage(data, resolvedQuery, result)
+	if len(selectColumns) > 0 {
+		data["select"] = querySelectNames(selectColumns)
+	}
 	if isSavedQuery && queryName != "" {
 		data["saved_query"] = queryName
 	} else {
 		data["trait"] = result.TypeName
 	}
-	return commandexec.Success(data, meta)
+	return commandexec.SuccessWithWarnings(data, warnings, meta)
 }
 
 func handleQueryApply(ctx context.Context, req commandexec.Request, result *readsvc.ExecuteQueryResult, applyArgs []string, queryTimeMs int64) commandexec.Result {
@@ -244,6 +312,12 @@ func handleQueryApply(ctx context.Context, req commandexec.Request, result *read
 	for _, row := range result.Objects {
 		ids = append(ids, row.ID)
 	}
+	return applyToObjects(ctx, req, rawApply, ids, queryTimeMs)
+}
+
+// applyToObjects runs a parsed --apply command (set, delete, add, move) over
+// object IDs through the matching bulk command.
+func applyToObjects(ctx context.Context, req commandexec.Request, rawApply *bulkops.RawApplyCommand, ids []string, queryTimeMs int64) commandexec.Result {
 	ids = dedupeQueryApplyIDs(ids)
 	if len(ids) == 0 {
 		return commandexec.Success(map[string]interface{}{
@@ -299,6 +373,12 @@ func invokeNestedCommand(ctx context.Context, req commandexec.Request, commandID
 	if !ok {
 		return commandexec.Failure("INTERNAL_ERROR", "query apply runtime is unavailable", nil, "Retry the command")
 	}
+	if boolArg(req.Args, "unlock") {
+		args["unlock"] = true
+	}
+	if boolArg(req.Args, "merge") {
+		args["merge"] = true
+	}
 
 	result := invoker.Execute(ctx, commandexec.Request{
 		CommandID:      commandID,
@@ -395,6 +475,39 @@ func resolveQueryString(queryString string, rawInputs interface{}, vaultCfg *con
 	return resolvedQuery, name, true, nil
 }
 
+// selectQueryItems applies a --select projection to query rows. Without
+// selected columns the rows are returned unchanged.
+func selectQueryItems(db *index.Database, queryKind string, items []map[string]interface{}, columns []querySelectColumn) ([]map[string]interface{}, *commandexec.Result) {
+	if len(columns) == 0 {
+		return items, nil
+	}
+	var projected []map[string]interface{}
+	var err error
+	if queryKind == "type" {
+		projected, err = projectObjectQueryItems(db, items, columns)
+	} else {
+		projected, err = projectQueryItems(items, columns)
+	}
+	if err != nil {
+		failure := commandexec.Failure("INVALID_INPUT", err.Error(), nil, "Check the --select column names")
+		return nil, &failure
+	}
+	return projected, nil
+}
+
+// attachQueryMatches adds each row's --explain-matches annotations under "matches".
+func attachQueryMatches(items []map[string]interface{}, matches map[string][]query.Match) {
+	if matches == nil {
+		return
+	}
+	for _, item := range items {
+		id, _ := item["id"].(string)
+		if rowMatches, ok := matches[id]; ok {
+			item["matches"] = rowMatches
+		}
+	}
+}
+
 func objectQueryItems(result *readsvc.ExecuteQueryResult) []map[string]interface{} {
 	items := make([]map[string]interface{}, len(result.Objects))
 	for i, row := range result.Objects {
@@ -423,6 +536,9 @@ func traitQueryItems(result *readsvc.ExecuteQueryResult) []map[string]interface{
 			"line":       row.Line,
 			"object_id":  row.ParentObjectID,
 		}
+		if row.RawValue != nil {
+			items[i]["raw_value"] = *row.RawValue
+		}
 	}
 	return items
 }
@@ -571,6 +687,7 @@ func HandleQuerySavedSet(_ context.Context, req commandexec.Request) commandexec
 		Args:        stringSliceArg(req.Args["arg"]),
 		Description: strings.TrimSpace(stringArg(req.Args, "description")),
 		Options:     savedQueryOptionsFromArgs(req.Args),
+		Snapshot:    savedQuerySnapshotFromArgs(req.Args),
 	})
 	if err != nil {
 		return mapQuerySvcFailure(err)
@@ -612,9 +729,21 @@ func savedQueryData(q querysvc.SavedQueryInfo) map[string]interface{} {
 	if !q.Options.IsEmpty() {
 		data["options"] = q.Options
 	}
+	if q.Snapshot != nil {
+		data["snapshot"] = q.Snapshot
+	}
 	return data
 }
 
+func savedQuerySnapshotFromArgs(args map[string]interface{}) *config.QuerySnapshot {
+	target := strings.TrimSpace(stringArg(args, "snapshot-target"))
+	schedule := strings.TrimSpace(stringArg(args, "snapshot-schedule"))
+	if target == "" && schedule == "" {
+		return nil
+	}
+	return &config.QuerySnapshot{Target: target, Schedule: schedule}
+}
+
 func savedQueryOptionsFromArgs(args map[string]interface{}) *config.QueryOptions {
 	if args == nil {
 		return nil
@@ -654,6 +783,9 @@ func savedQueryOptionsFromArgs(args map[string]interface{}) *config.QueryOptions
 	if v, ok := boolPointerArg(args, "browse"); ok {
 		opts.Browse = v
 	}
+	if v, ok := boolPointerArg(args, "full"); ok {
+		opts.Full = v
+	}
 	if opts.IsEmpty() {
 		return nil
 	}
@@ -707,6 +839,9 @@ func savedQueryOptionsFromRaw(raw interface{}) *config.QueryOptions {
 		if v, ok := boolPointerRaw(v["browse"]); ok {
 			opts.Browse = v
 		}
+		if v, ok := boolPointerRaw(v["full"]); ok {
+			opts.Full = v
+		}
 		if opts.IsEmpty() {
 			return nil
 		}
@@ -761,3 +896,57 @@ func intPointerRaw(raw interface{}) (*int, bool) {
 		return nil, false
 	}
 }
+
+// maxReportedStaleFiles caps the stale file list carried in result metadata.
+const maxReportedStaleFiles = 20
+
+// checkIndexFreshness applies the refresh and require-fresh args before a
+// read and returns the index freshness to report in result metadata.
+func checkIndexFreshness(rt *readsvc.Runtime, args map[string]interface{}) (*commandexec.Freshness, *commandexec.Result) {
+	requireFresh := boolArg(args, "require-fresh")
+	checked, err := readsvc.CheckFreshness(rt, boolArg(args, "refresh"), requireFresh)
+	if err != nil {
+		failure := commandexec.Failure(codes.ErrDatabase, err.Error(), nil, "Run 'rvn reindex' to rebuild the database")
+		return nil, &failure
+	}
+
+	freshness := &commandexec.Freshness{
+		Stale:      len(checked.StaleFiles) > 0,
+		StaleCount: len(checked.StaleFiles),
+		StaleFiles: checked.StaleFiles,
+		Refreshed:  checked.Refreshed,
+		Unknown:    checked.Unknown,
+	}
+	if len(freshness.StaleFiles) > maxReportedStaleFiles {
+		freshness.StaleFiles = freshness.StaleFiles[:maxReportedStaleFiles]
+	}
+	if requireFresh && freshness.Stale {
+		failure := commandexec.Failure(
+			codes.ErrIndexStale,
+			fmt.Sprintf("index is still stale after refresh (%d file(s) could not be reindexed)", freshness.StaleCount),
+			map[string]interface{}{"stale_files": freshness.StaleFiles},
+			"Run 'rvn check' to find files that fail to parse, then 'rvn reindex'",
+		)
+		return nil, &failure
+	}
+	return freshness, nil
+}
+
+// degradedQueryWarnings explains query features that ran on a fallback
+// because the SQLite build lacks FTS5 or REGEXP.
+func degradedQueryWarnings(features []string) []commandexec.Warning {
+	var warnings []commandexec.Warning
+	for _, feature := range features {
+		var message string
+		switch feature {
+		case "content":
+			message = "content() is using LIKE matching because this SQLite build lacks FTS5; phrase, prefix, and boolean search syntax is approximated and results are unranked"
+		case "matches":
+			message = "matches() is using LIKE matching because this SQLite build lacks REGEXP; only literal patterns with ^, $, and .* are supported"
+		default:
+			continue
+		}
+		warnings = append(warnings, commandexec.Warning{Code: codes.WarnDegradedSearch, Message: message})
+	}
+	return warnings
+}
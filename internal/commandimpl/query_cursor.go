@@ -0,0 +1,62 @@
+package commandimpl
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/aidanlsb/raven/internal/readsvc"
+)
+
+// queryCursor is the position a next_cursor token resumes from. The query
+// fingerprint keeps a cursor from being replayed against a different query.
+type queryCursor struct {
+	Offset int    `json:"o"`
+	Limit  int    `json:"l"`
+	Query  string `json:"q"`
+}
+
+var errInvalidQueryCursor = errors.New("invalid or expired cursor")
+
+func queryFingerprint(queryString string) string {
+	sum := sha256.Sum256([]byte(queryString))
+	return hex.EncodeToString(sum[:6])
+}
+
+func encodeQueryCursor(queryString string, offset, limit int) string {
+	payload, _ := json.Marshal(queryCursor{Offset: offset, Limit: limit, Query: queryFingerprint(queryString)})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func decodeQueryCursor(token, queryString string) (queryCursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return queryCursor{}, errInvalidQueryCursor
+	}
+	var cursor queryCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return queryCursor{}, errInvalidQueryCursor
+	}
+	if cursor.Offset < 0 || cursor.Limit <= 0 {
+		return queryCursor{}, errInvalidQueryCursor
+	}
+	if cursor.Query != queryFingerprint(queryString) {
+		return queryCursor{}, errors.New("cursor belongs to a different query")
+	}
+	return cursor, nil
+}
+
+// addQueryPage records whether a limited query has more rows and, if so, the
+// cursor for the next page.
+func addQueryPage(data map[string]interface{}, queryString string, result *readsvc.ExecuteQueryResult) {
+	if result.Limit <= 0 {
+		return
+	}
+	hasMore := result.Offset+result.Returned < result.Total
+	data["has_more"] = hasMore
+	if hasMore {
+		data["next_cursor"] = encodeQueryCursor(queryString, result.Offset+result.Returned, result.Limit)
+	}
+}
@@ -0,0 +1,61 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestHandleQueryCursorPagesThroughResults(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(testutil.MinimalSchema()).
+		WithFile("pages/a.md", "# A\n").
+		WithFile("pages/b.md", "# B\n").
+		WithFile("pages/c.md", "# C\n").
+		Build()
+	reindexForEditTest(t, v.Path)
+
+	query := func(args map[string]any) map[string]interface{} {
+		t.Helper()
+		args["query_string"] = "type:page"
+		args["ids"] = true
+		result := HandleQuery(context.Background(), commandexec.Request{VaultPath: v.Path, Args: args})
+		if !result.OK {
+			t.Fatalf("HandleQuery(%v) failed: %#v", args, result.Error)
+		}
+		return result.Data.(map[string]interface{})
+	}
+
+	var seen []string
+	data := query(map[string]any{"limit": 2})
+	for page := 0; ; page++ {
+		if page > 2 {
+			t.Fatal("cursor did not terminate")
+		}
+		seen = append(seen, data["ids"].([]string)...)
+		if data["has_more"] != true {
+			if _, ok := data["next_cursor"]; ok {
+				t.Fatalf("last page has next_cursor: %v", data)
+			}
+			break
+		}
+		data = query(map[string]any{"cursor": data["next_cursor"]})
+	}
+	if got := strings.Join(seen, ","); got != "pages/a,pages/b,pages/c" {
+		t.Fatalf("paged ids = %s, want pages/a,pages/b,pages/c", got)
+	}
+
+	first := query(map[string]any{"limit": 1})
+	result := HandleQuery(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args:      map[string]any{"query_string": "section", "cursor": first["next_cursor"]},
+	})
+	if result.OK || result.Error == nil || !strings.Contains(result.Error.Message, "different query") {
+		t.Fatalf("cursor for another query = %#v, want a different-query error", result.Error)
+	}
+}
@@ -0,0 +1,204 @@
+package commandimpl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/readsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// HandleQueryDiff executes the canonical `query_diff` command.
+func HandleQueryDiff(_ context.Context, req commandexec.Request) commandexec.Result {
+	start := time.Now()
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+
+	queryA := strings.TrimSpace(stringArg(req.Args, "query_a"))
+	queryB := strings.TrimSpace(stringArg(req.Args, "query_b"))
+	if queryA == "" || queryB == "" {
+		return commandexec.Failure("MISSING_ARGUMENT", "specify two queries to compare", nil, "Usage: rvn query diff '<query-a>' '<query-b>'")
+	}
+	sideA, failure := resolveQueryDiffSide(queryA, vaultCfg)
+	if failure != nil {
+		return *failure
+	}
+	sideB, failure := resolveQueryDiffSide(queryB, vaultCfg)
+	if failure != nil {
+		return *failure
+	}
+
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+	}
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return commandexec.Failure("DATABASE_ERROR", "failed to open database", nil, "Run 'rvn reindex' to rebuild the database")
+	}
+	defer db.Close()
+	db.SetDailyDirectory(vaultCfg.GetDailyDirectory())
+	compatible, err := db.SchemaCompatible()
+	if err != nil {
+		return commandexec.Failure(codes.ErrDatabase, "failed to read index schema version", nil, "Run 'rvn reindex --full' to rebuild the index")
+	}
+	if !compatible {
+		return commandexec.Failure(codes.ErrDatabaseVersion, "index schema is stale or incompatible", nil, "Run 'rvn reindex --full' to rebuild the index")
+	}
+
+	rt := &readsvc.Runtime{
+		VaultPath: vaultPath,
+		VaultCfg:  vaultCfg,
+		Schema:    sch,
+		DB:        db,
+	}
+	freshness, failure := checkIndexFreshness(rt, req.Args)
+	if failure != nil {
+		return *failure
+	}
+
+	kindA, itemsA, failure := runQueryDiffSide(rt, sideA)
+	if failure != nil {
+		return *failure
+	}
+	kindB, itemsB, failure := runQueryDiffSide(rt, sideB)
+	if failure != nil {
+		return *failure
+	}
+	if kindA != kindB {
+		return commandexec.Failure(
+			"INVALID_INPUT",
+			fmt.Sprintf("cannot compare a %s query with a %s query", queryKindLabel(kindA), queryKindLabel(kindB)),
+			nil,
+			"Compare two object queries, two trait queries, two section queries, or two asset queries",
+		)
+	}
+
+	diff := diffQueryResults(itemsA, itemsB)
+	data := map[string]interface{}{
+		"query_kind": kindA,
+		"a":          sideA.data(len(itemsA)),
+		"b":          sideB.data(len(itemsB)),
+		"only_a":     diff.onlyA,
+		"only_b":     diff.onlyB,
+		"common":     diff.common,
+		"identical":  len(diff.onlyA) == 0 && len(diff.onlyB) == 0,
+	}
+	return commandexec.Success(data, &commandexec.Meta{
+		Count:       len(diff.onlyA) + len(diff.onlyB),
+		QueryTimeMs: time.Since(start).Milliseconds(),
+		Freshness:   freshness,
+	})
+}
+
+// queryDiffSide is one of the two queries being compared.
+type queryDiffSide struct {
+	resolved  string
+	savedName string
+}
+
+func (s queryDiffSide) data(total int) map[string]interface{} {
+	data := map[string]interface{}{
+		"query": s.resolved,
+		"total": total,
+	}
+	if s.savedName != "" {
+		data["saved_query"] = s.savedName
+	}
+	return data
+}
+
+// resolveQueryDiffSide expands a saved query name (with inline inputs) into
+// its query string.
+func resolveQueryDiffSide(input string, vaultCfg *config.VaultConfig) (queryDiffSide, *commandexec.Result) {
+	resolved, name, isSaved, err := resolveQueryString(input, nil, vaultCfg)
+	if err != nil {
+		failure := mapQuerySvcFailure(err)
+		return queryDiffSide{}, &failure
+	}
+	if isSaved && !isFullQueryString(resolved) {
+		failure := commandexec.Failure("QUERY_INVALID", fmt.Sprintf("saved query '%s' must start with 'type:', 'trait:', 'section', or 'asset'", name), nil, "")
+		return queryDiffSide{}, &failure
+	}
+	return queryDiffSide{resolved: resolved, savedName: name}, nil
+}
+
+// runQueryDiffSide runs one side of the comparison over all matching rows.
+func runQueryDiffSide(rt *readsvc.Runtime, side queryDiffSide) (string, []map[string]interface{}, *commandexec.Result) {
+	result, err := readsvc.ExecuteQuery(rt, readsvc.ExecuteQueryRequest{QueryString: side.resolved})
+	if err != nil {
+		failure := mapExecuteQueryFailure(side.resolved, err)
+		return "", nil, &failure
+	}
+	var items []map[string]interface{}
+	switch result.QueryKind {
+	case "type":
+		items = objectQueryItems(result)
+	case "trait":
+		items = traitQueryItems(result)
+	case "section":
+		items = sectionQueryItems(result)
+	case "asset":
+		items = assetQueryItems(result)
+	}
+	for _, item := range items {
+		delete(item, "num")
+	}
+	return result.QueryKind, items, nil
+}
+
+// queryResultDiff splits two result sets by ID, keeping each query's row
+// order.
+type queryResultDiff struct {
+	onlyA  []map[string]interface{}
+	onlyB  []map[string]interface{}
+	common []map[string]interface{}
+}
+
+func diffQueryResults(a, b []map[string]interface{}) queryResultDiff {
+	diff := queryResultDiff{
+		onlyA:  []map[string]interface{}{},
+		onlyB:  []map[string]interface{}{},
+		common: []map[string]interface{}{},
+	}
+	inA := make(map[string]bool, len(a))
+	for _, item := range a {
+		inA[fmt.Sprint(item["id"])] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, item := range b {
+		id := fmt.Sprint(item["id"])
+		inB[id] = true
+		if inA[id] {
+			diff.common = append(diff.common, item)
+		} else {
+			diff.onlyB = append(diff.onlyB, item)
+		}
+	}
+	for _, item := range a {
+		if !inB[fmt.Sprint(item["id"])] {
+			diff.onlyA = append(diff.onlyA, item)
+		}
+	}
+	return diff
+}
+
+func queryKindLabel(kind string) string {
+	if kind == "type" {
+		return "object"
+	}
+	return kind
+}
@@ -0,0 +1,64 @@
+package commandimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestHandleQueryDiffSplitsResultsByID(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(`version: 1
+types:
+  project:
+    default_path: projects/
+    fields:
+      status: { type: string }
+      state: { type: string }
+traits:
+  due:
+    type: date
+`).
+		WithRavenYAML(`queries:
+  migrated:
+    query: "type:project .state==active"
+`).
+		WithFile("projects/alpha.md", "---\ntype: project\nstatus: active\nstate: active\n---\n- Ship @due(2026-01-01)\n").
+		WithFile("projects/beta.md", "---\ntype: project\nstatus: active\n---\n").
+		WithFile("projects/gamma.md", "---\ntype: project\nstate: active\n---\n").
+		Build()
+	reindexForEditTest(t, v.Path)
+
+	result := HandleQueryDiff(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args:      map[string]any{"query_a": "type:project .status==active", "query_b": "migrated"},
+	})
+	if !result.OK {
+		t.Fatalf("HandleQueryDiff() failed: %#v", result.Error)
+	}
+	data := result.Data.(map[string]interface{})
+	for key, want := range map[string]string{"only_a": "projects/beta", "only_b": "projects/gamma", "common": "projects/alpha"} {
+		items := data[key].([]map[string]interface{})
+		if len(items) != 1 || items[0]["id"] != want {
+			t.Errorf("%s = %#v, want [%s]", key, items, want)
+		}
+	}
+	if data["identical"] != false {
+		t.Errorf("identical = %#v, want false", data["identical"])
+	}
+	if b := data["b"].(map[string]interface{}); b["saved_query"] != "migrated" || b["query"] != "type:project .state==active" {
+		t.Errorf("b = %#v, want the resolved saved query", b)
+	}
+
+	mixed := HandleQueryDiff(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args:      map[string]any{"query_a": "type:project", "query_b": "trait:due"},
+	})
+	if mixed.OK || mixed.Error.Code != "INVALID_INPUT" {
+		t.Fatalf("comparing object and trait queries = %#v, want INVALID_INPUT", mixed)
+	}
+}
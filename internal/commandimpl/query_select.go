@@ -0,0 +1,196 @@
+package commandimpl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// querySelectBacklinks is the computed column holding an object's backlink count.
+const querySelectBacklinks = "backlinks"
+
+// querySelectIssues is the computed column listing issue tracker references
+// in an object's file (see issue_refs in raven.yaml).
+const querySelectIssues = "issues"
+
+// objectSelectBuiltins lists the non-field columns available to type queries.
+var objectSelectBuiltins = map[string]bool{
+	"type":               true,
+	"file_path":          true,
+	"line":               true,
+	querySelectBacklinks: true,
+	querySelectIssues:    true,
+}
+
+// querySelectColumn is one requested output column.
+type querySelectColumn struct {
+	Name  string // Output key (leading '.' removed)
+	Field bool   // Column reads an object field (.name) rather than a row key
+}
+
+// parseQuerySelect parses a comma-separated --select list such as
+// ".name, .status, backlinks". Blank entries and duplicates are dropped.
+func parseQuerySelect(raw string) ([]querySelectColumn, error) {
+	var columns []querySelectColumn
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		column := querySelectColumn{Name: part}
+		if strings.HasPrefix(part, ".") {
+			column.Name = strings.TrimPrefix(part, ".")
+			column.Field = true
+		}
+		if column.Name == "" {
+			return nil, fmt.Errorf("invalid select column %q", part)
+		}
+		if seen[column.Name] {
+			continue
+		}
+		seen[column.Name] = true
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// projectObjectQueryItems reduces type query rows to num, id, and the selected
+// columns. Field columns read from the row's fields; backlinks and issues are
+// computed for all rows with a single grouped query each.
+func projectObjectQueryItems(db *index.Database, items []map[string]interface{}, columns []querySelectColumn) ([]map[string]interface{}, error) {
+	wantBacklinks := false
+	wantIssues := false
+	for _, column := range columns {
+		if !column.Field && column.Name != "id" && !objectSelectBuiltins[column.Name] {
+			return nil, fmt.Errorf("unknown select column %q for type queries (use .%s for a field, or one of: %s)", column.Name, column.Name, strings.Join(sortedSelectBuiltins(), ", "))
+		}
+		if !column.Field && column.Name == querySelectBacklinks {
+			wantBacklinks = true
+		}
+		if !column.Field && column.Name == querySelectIssues {
+			wantIssues = true
+		}
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		if id, ok := item["id"].(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	var backlinks map[string]int
+	if wantBacklinks && len(ids) > 0 {
+		counts, err := db.BacklinkCounts(ids)
+		if err != nil {
+			return nil, err
+		}
+		backlinks = counts
+	}
+	var issues map[string][]model.IssueRef
+	if wantIssues && len(ids) > 0 {
+		refs, err := db.IssueRefsForObjects(ids)
+		if err != nil {
+			return nil, err
+		}
+		issues = refs
+	}
+
+	projected := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		row := map[string]interface{}{"num": item["num"], "id": item["id"]}
+		fields, _ := item["fields"].(map[string]interface{})
+		for _, column := range columns {
+			switch {
+			case column.Field:
+				row[column.Name], _ = schema.ValueAtPath(fields, column.Name)
+			case column.Name == querySelectBacklinks:
+				id, _ := item["id"].(string)
+				row[column.Name] = backlinks[id]
+			case column.Name == querySelectIssues:
+				id, _ := item["id"].(string)
+				refs := issues[id]
+				if refs == nil {
+					refs = []model.IssueRef{}
+				}
+				row[column.Name] = refs
+			default:
+				row[column.Name] = item[column.Name]
+			}
+		}
+		projected[i] = row
+	}
+	return projected, nil
+}
+
+// projectQueryItems reduces trait, asset, or section rows to num, id, and the
+// selected row keys. A leading '.' is accepted, so .value selects a trait value.
+func projectQueryItems(items []map[string]interface{}, columns []querySelectColumn) ([]map[string]interface{}, error) {
+	if len(items) > 0 {
+		available := make(map[string]bool)
+		for _, item := range items {
+			for key := range item {
+				available[key] = true
+			}
+		}
+		for _, column := range columns {
+			if !available[column.Name] {
+				return nil, fmt.Errorf("unknown select column %q (available: %s)", column.Name, strings.Join(sortedItemKeys(available), ", "))
+			}
+		}
+	}
+
+	projected := make([]map[string]interface{}, len(items))
+	for i, item := range items {
+		row := map[string]interface{}{"num": item["num"], "id": item["id"]}
+		for _, column := range columns {
+			row[column.Name] = item[column.Name]
+		}
+		projected[i] = row
+	}
+	return projected, nil
+}
+
+// querySelectHasColumn reports whether a computed (non-field) column was selected.
+func querySelectHasColumn(columns []querySelectColumn, name string) bool {
+	for _, column := range columns {
+		if !column.Field && column.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// querySelectNames returns the output keys in requested order.
+func querySelectNames(columns []querySelectColumn) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+func sortedSelectBuiltins() []string {
+	names := make([]string, 0, len(objectSelectBuiltins))
+	for name := range objectSelectBuiltins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedItemKeys(available map[string]bool) []string {
+	keys := make([]string, 0, len(available))
+	for key := range available {
+		if key == "num" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
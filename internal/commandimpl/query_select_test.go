@@ -0,0 +1,219 @@
+package commandimpl
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestParseQuerySelect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		raw     string
+		want    []querySelectColumn
+		wantErr bool
+	}{
+		{name: "empty", raw: "", want: nil},
+		{
+			name: "fields and computed column",
+			raw:  ".name, .status, backlinks",
+			want: []querySelectColumn{
+				{Name: "name", Field: true},
+				{Name: "status", Field: true},
+				{Name: "backlinks"},
+			},
+		},
+		{
+			name: "blank entries and duplicates dropped",
+			raw:  " .name,, .name ,file_path",
+			want: []querySelectColumn{
+				{Name: "name", Field: true},
+				{Name: "file_path"},
+			},
+		},
+		{name: "bare dot rejected", raw: ".name, .", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseQuerySelect(tt.raw)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: parseQuerySelect(%q) succeeded, want error", tt.name, tt.raw)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: parseQuerySelect(%q) error: %v", tt.name, tt.raw, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseQuerySelect(%q) = %#v, want %#v", tt.name, tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestProjectQueryItems(t *testing.T) {
+	t.Parallel()
+
+	traitItems := []map[string]interface{}{
+		{"num": 1, "id": "a.md:trait:0", "trait_type": "due", "value": "2026-01-01", "content": "ship", "file_path": "a.md", "line": 3},
+		{"num": 2, "id": "b.md:trait:0", "trait_type": "due", "value": "2026-02-01", "content": "plan", "file_path": "b.md", "line": 7, "raw_value": "2026-02-01"},
+	}
+
+	tests := []struct {
+		name    string
+		columns string
+		want    []map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:    "dotted and bare keys select row keys",
+			columns: ".value, line",
+			want: []map[string]interface{}{
+				{"num": 1, "id": "a.md:trait:0", "value": "2026-01-01", "line": 3},
+				{"num": 2, "id": "b.md:trait:0", "value": "2026-02-01", "line": 7},
+			},
+		},
+		{
+			name:    "key present on only some rows",
+			columns: "raw_value",
+			want: []map[string]interface{}{
+				{"num": 1, "id": "a.md:trait:0", "raw_value": nil},
+				{"num": 2, "id": "b.md:trait:0", "raw_value": "2026-02-01"},
+			},
+		},
+		{name: "unknown key", columns: "status", wantErr: `unknown select column "status"`},
+	}
+
+	for _, tt := range tests {
+		columns, err := parseQuerySelect(tt.columns)
+		if err != nil {
+			t.Fatalf("%s: parseQuerySelect: %v", tt.name, err)
+		}
+		got, err := projectQueryItems(traitItems, columns)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: projectQueryItems: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: projectQueryItems = %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func newQuerySelectTestVault(t *testing.T) *testutil.TestVault {
+	t.Helper()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(`version: 1
+types:
+  project:
+    default_path: project/
+    name_field: name
+    fields:
+      name:
+        type: string
+      status:
+        type: string
+      owner:
+        type: string
+`).
+		WithFile("project/alpha.md", "---\ntype: project\nname: Alpha\nstatus: active\nowner: freya\n---\n").
+		WithFile("project/beta.md", "---\ntype: project\nname: Beta\nstatus: active\n---\n").
+		WithFile("notes/one.md", "See [[project/alpha]] and [[project/alpha#plan]].\n").
+		WithFile("notes/two.md", "Also [[project/alpha]].\n").
+		Build()
+	reindexForEditTest(t, v.Path)
+	return v
+}
+
+func TestHandleQuerySelectProjectsObjectRows(t *testing.T) {
+	t.Parallel()
+
+	v := newQuerySelectTestVault(t)
+
+	result := HandleQuery(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args: map[string]any{
+			"query_string": "type:project .status==active",
+			"select":       ".name, backlinks",
+		},
+	})
+	if !result.OK {
+		t.Fatalf("HandleQuery() failed: %#v", result.Error)
+	}
+	data := result.Data.(map[string]interface{})
+	if got := data["select"]; !reflect.DeepEqual(got, []string{"name", "backlinks"}) {
+		t.Fatalf("select = %#v, want [name backlinks]", got)
+	}
+
+	items := data["items"].([]map[string]interface{})
+	byID := make(map[string]map[string]interface{}, len(items))
+	for _, item := range items {
+		byID[item["id"].(string)] = item
+	}
+	alpha := byID["project/alpha"]
+	if alpha == nil {
+		t.Fatalf("items = %#v, want project/alpha", items)
+	}
+	if _, ok := alpha["fields"]; ok {
+		t.Fatalf("projected row still has fields: %#v", alpha)
+	}
+	if _, ok := alpha["owner"]; ok {
+		t.Fatalf("projected row has unselected owner: %#v", alpha)
+	}
+	if alpha["name"] != "Alpha" || alpha["backlinks"] != 3 {
+		t.Fatalf("alpha = %#v, want name Alpha and 3 backlinks", alpha)
+	}
+	if beta := byID["project/beta"]; beta == nil || beta["backlinks"] != 0 {
+		t.Fatalf("beta = %#v, want 0 backlinks", beta)
+	}
+}
+
+func TestHandleQuerySelectRejectsInvalidUse(t *testing.T) {
+	t.Parallel()
+
+	v := newQuerySelectTestVault(t)
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		wantMsg string
+	}{
+		{
+			name:    "unknown column",
+			args:    map[string]any{"query_string": "type:project", "select": "status"},
+			wantMsg: `unknown select column "status"`,
+		},
+		{
+			name:    "conflicts with ids",
+			args:    map[string]any{"query_string": "type:project", "select": ".name", "ids": true},
+			wantMsg: "--select cannot be used",
+		},
+		{
+			name:    "conflicts with apply",
+			args:    map[string]any{"query_string": "type:project", "select": ".name", "apply": []string{"set status=done"}},
+			wantMsg: "--select cannot be used",
+		},
+	}
+
+	for _, tt := range tests {
+		result := HandleQuery(context.Background(), commandexec.Request{VaultPath: v.Path, Args: tt.args})
+		if result.OK || result.Error == nil {
+			t.Fatalf("%s: HandleQuery() succeeded, want failure", tt.name)
+		}
+		if result.Error.Code != "INVALID_INPUT" || !strings.Contains(result.Error.Message, tt.wantMsg) {
+			t.Fatalf("%s: error = %#v, want INVALID_INPUT containing %q", tt.name, result.Error, tt.wantMsg)
+		}
+	}
+}
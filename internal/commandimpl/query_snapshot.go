@@ -0,0 +1,219 @@
+package commandimpl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/objectsvc"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/querysvc"
+	"github.com/aidanlsb/raven/internal/readsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+// snapshotNow is the clock used for snapshot timestamps (overridable in tests).
+var snapshotNow = time.Now
+
+// HandleQuerySnapshot executes the canonical `query_snapshot` command.
+func HandleQuerySnapshot(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
+
+	name := strings.TrimSpace(stringArg(req.Args, "name"))
+	var names []string
+	if name != "" {
+		saved, ok := vaultCfg.Queries[name]
+		if !ok {
+			return commandexec.Failure(codes.ErrQueryNotFound, fmt.Sprintf("query '%s' not found", name), nil, "Run 'rvn query saved list' to see available queries")
+		}
+		if saved.Snapshot == nil {
+			return commandexec.Failure("INVALID_INPUT", fmt.Sprintf("saved query '%s' has no snapshot target", name), nil, fmt.Sprintf("Run 'rvn query saved set %s ... --snapshot-target <note>' to add one", name))
+		}
+		names = []string{name}
+	} else {
+		for queryName, saved := range vaultCfg.Queries {
+			if saved != nil && saved.Snapshot != nil {
+				names = append(names, queryName)
+			}
+		}
+		sort.Strings(names)
+	}
+
+	due := boolArg(req.Args, "due")
+	if len(names) == 0 {
+		return commandexec.Success(map[string]interface{}{
+			"due":       due,
+			"snapshots": []map[string]interface{}{},
+			"updated":   0,
+			"skipped":   0,
+			"failed":    0,
+		}, &commandexec.Meta{Count: 0})
+	}
+
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+	}
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return commandexec.Failure("DATABASE_ERROR", "failed to open database", nil, "Run 'rvn reindex' to rebuild the database")
+	}
+	defer db.Close()
+	db.SetDailyDirectory(vaultCfg.GetDailyDirectory())
+	compatible, err := db.SchemaCompatible()
+	if err != nil {
+		return commandexec.Failure(codes.ErrDatabase, "failed to read index schema version", nil, "Run 'rvn reindex --full' to rebuild the index")
+	}
+	if !compatible {
+		return commandexec.Failure(codes.ErrDatabaseVersion, "index schema is stale or incompatible", nil, "Run 'rvn reindex --full' to rebuild the index")
+	}
+
+	rt := &readsvc.Runtime{
+		VaultPath: vaultPath,
+		VaultCfg:  vaultCfg,
+		Schema:    sch,
+		DB:        db,
+	}
+	_, _, _ = readsvc.CheckStaleness(rt)
+
+	now := snapshotNow()
+	items := make([]map[string]interface{}, 0, len(names))
+	var writtenFiles []string
+	updated, skipped, failed := 0, 0, 0
+	for _, queryName := range names {
+		item, filePath, failure := runQuerySnapshot(rt, queryName, vaultCfg.Queries[queryName], due, now)
+		if failure != nil {
+			if name != "" {
+				return *failure
+			}
+			item["status"] = "failed"
+			item["error"] = failure.Error.Message
+			failed++
+			items = append(items, item)
+			continue
+		}
+		switch item["status"] {
+		case "updated":
+			updated++
+			writtenFiles = append(writtenFiles, filePath)
+		default:
+			skipped++
+		}
+		items = append(items, item)
+	}
+
+	var warnings []commandexec.Warning
+	if len(writtenFiles) > 0 {
+		stampAttribution(vaultPath, vaultCfg, false, writtenFiles...)
+		warnings = autoReindexWarnings(vaultPath, vaultCfg, writtenFiles...)
+	}
+
+	result := commandexec.Success(map[string]interface{}{
+		"due":       due,
+		"snapshots": items,
+		"updated":   updated,
+		"skipped":   skipped,
+		"failed":    failed,
+	}, &commandexec.Meta{Count: len(items)})
+	result.Warnings = warnings
+	return result
+}
+
+// runQuerySnapshot renders one saved query into its snapshot note. It returns
+// the item summary, the absolute path written (if any), and a failure result
+// when the snapshot could not be rendered.
+func runQuerySnapshot(rt *readsvc.Runtime, name string, saved *config.SavedQuery, due bool, now time.Time) (map[string]interface{}, string, *commandexec.Result) {
+	item := map[string]interface{}{
+		"name":   name,
+		"target": saved.Snapshot.Target,
+	}
+	fail := func(code codes.ErrorCode, message, suggestion string) (map[string]interface{}, string, *commandexec.Result) {
+		result := commandexec.Failure(code, message, map[string]interface{}{"name": name}, suggestion)
+		return item, "", &result
+	}
+
+	if err := querysvc.ValidateSnapshot(name, saved.Snapshot, saved.Args); err != nil {
+		return fail("INVALID_INPUT", err.Error(), "Fix the snapshot in raven.yaml under queries")
+	}
+
+	resolved, err := readsvc.ResolveReference(saved.Snapshot.Target, rt, false)
+	if err != nil || resolved == nil || resolved.FilePath == "" {
+		return fail(codes.ErrRefNotFound, fmt.Sprintf("snapshot target '%s' not found", saved.Snapshot.Target), "Create the target note first, e.g. with 'rvn new page <title>'")
+	}
+	if resolved.IsSection {
+		return fail("INVALID_INPUT", fmt.Sprintf("snapshot target '%s' must be a note, not a section", saved.Snapshot.Target), "")
+	}
+	relPath, err := filepath.Rel(rt.VaultPath, resolved.FilePath)
+	if err != nil {
+		return fail("VALIDATION_FAILED", "failed to resolve snapshot target path", "")
+	}
+	relPath = paths.NormalizeVaultRelPath(relPath)
+	item["file"] = relPath
+
+	content, err := vaultcrypt.ReadFile(resolved.FilePath)
+	if err != nil {
+		return fail("FILE_READ_ERROR", err.Error(), "")
+	}
+	if last, ok := querysvc.SnapshotLastUpdated(string(content), name); ok {
+		item["last_updated"] = last.Format(time.RFC3339)
+	}
+	if due && !querysvc.SnapshotDue(saved.Snapshot, string(content), name, now) {
+		item["status"] = "skipped"
+		return item, "", nil
+	}
+
+	if err := objectsvc.ValidateContentMutationFilePath(rt.VaultPath, rt.VaultCfg, resolved.FilePath); err != nil {
+		result := mapContentMutationError(err)
+		return item, "", &result
+	}
+
+	queryString, err := querysvc.ResolveQueryString(name, saved, nil)
+	if err != nil {
+		result := mapQuerySvcFailure(err)
+		return item, "", &result
+	}
+	if !isFullQueryString(queryString) {
+		return fail("QUERY_INVALID", fmt.Sprintf("saved query '%s' must start with 'type:', 'trait:', 'section', or 'asset'", name), "")
+	}
+	limit := 0
+	if saved.Options != nil && saved.Options.Limit != nil {
+		limit = *saved.Options.Limit
+	}
+	queryResult, err := readsvc.ExecuteQuery(rt, readsvc.ExecuteQueryRequest{
+		QueryString: queryString,
+		Limit:       limit,
+	})
+	if err != nil {
+		return fail("QUERY_INVALID", fmt.Sprintf("saved query '%s' failed: %v", name, err), "")
+	}
+
+	lines := readsvc.QueryResultLines(queryResult)
+	block := querysvc.RenderSnapshotBlock(name, lines, now)
+	next := querysvc.ApplySnapshotBlock(string(content), name, block)
+	if err := atomicfile.WriteFile(resolved.FilePath, []byte(next), 0o644); err != nil {
+		return fail("FILE_WRITE_ERROR", err.Error(), "")
+	}
+
+	item["status"] = "updated"
+	item["count"] = len(lines)
+	item["updated_at"] = now.Format(time.RFC3339)
+	return item, resolved.FilePath, nil
+}
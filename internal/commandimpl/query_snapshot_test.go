@@ -0,0 +1,100 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestHandleQuerySnapshotRendersResultsIntoTargetNote(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(`version: 1
+types:
+  note:
+    default_path: note/
+traits:
+  question:
+    type: bool
+`).
+		WithRavenYAML(`queries:
+  open-questions:
+    query: "trait:question"
+    snapshot:
+      target: note/questions
+      schedule: daily
+`).
+		WithFile("note/alpha.md", "---\ntype: note\n---\n- Should we ship? @question\n").
+		WithFile("note/questions.md", "---\ntype: note\n---\n# Open Questions\n\nIntro stays.\n").
+		Build()
+	reindexForEditTest(t, v.Path)
+
+	result := HandleQuerySnapshot(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args:      map[string]any{"name": "open-questions"},
+	})
+	if !result.OK {
+		t.Fatalf("HandleQuerySnapshot() failed: %#v", result.Error)
+	}
+	data := result.Data.(map[string]interface{})
+	if data["updated"] != 1 {
+		t.Fatalf("updated = %#v, want 1", data["updated"])
+	}
+
+	content := v.ReadFile("note/questions.md")
+	for _, want := range []string{
+		"Intro stays.",
+		"<!-- rvn:snapshot open-questions updated=",
+		"- Should we ship? ([[note/alpha]])",
+		"<!-- /rvn:snapshot open-questions -->",
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("snapshot note missing %q:\n%s", want, content)
+		}
+	}
+
+	due := HandleQuerySnapshot(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args:      map[string]any{"due": true},
+	})
+	if !due.OK {
+		t.Fatalf("HandleQuerySnapshot(--due) failed: %#v", due.Error)
+	}
+	dueData := due.Data.(map[string]interface{})
+	if dueData["skipped"] != 1 || dueData["updated"] != 0 {
+		t.Fatalf("--due result = %#v, want one skipped snapshot", dueData)
+	}
+	if after := v.ReadFile("note/questions.md"); after != content {
+		t.Fatalf("--due rewrote a snapshot that was not due:\n%s", after)
+	}
+}
+
+func TestHandleQuerySnapshotMissingTargetFails(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(testutil.MinimalSchema()).
+		WithRavenYAML(`queries:
+  pages:
+    query: "type:page"
+    snapshot:
+      target: missing/note
+`).
+		Build()
+	reindexForEditTest(t, v.Path)
+
+	result := HandleQuerySnapshot(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args:      map[string]any{"name": "pages"},
+	})
+	if result.OK {
+		t.Fatalf("HandleQuerySnapshot() succeeded, want missing target failure: %#v", result.Data)
+	}
+	if result.Error == nil || result.Error.Code != "REF_NOT_FOUND" {
+		t.Fatalf("error = %#v, want REF_NOT_FOUND", result.Error)
+	}
+}
@@ -0,0 +1,164 @@
+package commandimpl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/configsvc"
+)
+
+// queryVaultNames splits the comma-separated --vaults value, dropping
+// duplicates and blanks.
+func queryVaultNames(raw string) []string {
+	seen := make(map[string]struct{})
+	var names []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := seen[name]; ok || name == "" {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names
+}
+
+// handleFederatedQuery runs the query in each named vault from config.toml
+// and merges the rows in vault order, tagging each with a vault column.
+// Saved queries resolve against each vault's own raven.yaml. Limit and
+// offset page over the merged rows.
+func handleFederatedQuery(ctx context.Context, req commandexec.Request, names []string) commandexec.Result {
+	start := time.Now()
+	if len(keyValuePairs(req.Args["apply"])) > 0 {
+		return commandexec.Failure("INVALID_INPUT", "--apply cannot be used with --vaults", nil, "Run --apply against one vault at a time with --vault <name>")
+	}
+	if boolArg(req.Args, "ids") {
+		return commandexec.Failure("INVALID_INPUT", "--ids cannot be used with --vaults", nil, "IDs are relative to one vault; query each vault with --vault <name> --ids")
+	}
+	if strings.TrimSpace(stringArg(req.Args, "cursor")) != "" {
+		return commandexec.Failure("INVALID_INPUT", "--cursor cannot be used with --vaults", nil, "Page cross-vault results with --limit and --offset")
+	}
+	limit, _ := intArg(req.Args, "limit")
+	offset, _ := intArg(req.Args, "offset")
+	if limit < 0 {
+		return commandexec.Failure("INVALID_INPUT", "--limit must be >= 0", nil, "Use --limit 0 for no limit")
+	}
+	if offset < 0 {
+		return commandexec.Failure("INVALID_INPUT", "--offset must be >= 0", nil, "Use --offset 0 for no offset")
+	}
+
+	vaultCtx, err := configsvc.LoadVaultContext(configContextOptions(req))
+	if err != nil {
+		return mapConfigSvcFailure(err, "Fix config.toml and try again")
+	}
+	paths := make([]string, len(names))
+	for i, name := range names {
+		path, err := vaultCtx.Cfg.GetVaultPath(name)
+		if err != nil {
+			return commandexec.Failure(codes.ErrVaultNotFound, fmt.Sprintf("vault '%s' not found", name), nil, "Run 'rvn vault list' to see configured vaults")
+		}
+		paths[i] = path
+	}
+
+	subArgs := make(map[string]interface{}, len(req.Args))
+	for key, value := range req.Args {
+		switch key {
+		case "vaults", "limit", "offset", "cursor":
+			continue
+		}
+		subArgs[key] = value
+	}
+
+	var queryKind string
+	var items []map[string]interface{}
+	var warnings []commandexec.Warning
+	var first map[string]interface{}
+	totals := make([]map[string]interface{}, 0, len(names))
+	total := 0
+	for i, name := range names {
+		sub := req
+		sub.VaultPath = paths[i]
+		sub.Args = subArgs
+		result := HandleQuery(ctx, sub)
+		if !result.OK {
+			if result.Error != nil {
+				result.Error.Message = fmt.Sprintf("vault '%s': %s", name, result.Error.Message)
+			}
+			return result
+		}
+		data, _ := result.Data.(map[string]interface{})
+		kind, _ := data["query_kind"].(string)
+		if first == nil {
+			first, queryKind = data, kind
+		} else if kind != queryKind {
+			return commandexec.Failure(
+				"QUERY_INVALID",
+				fmt.Sprintf("query returns %s results in vault '%s' but %s results in vault '%s'", kind, name, queryKind, names[0]),
+				nil,
+				"Use a saved query that means the same thing in every vault, or an inline query",
+			)
+		}
+		vaultTotal, _ := data["total"].(int)
+		total += vaultTotal
+		totals = append(totals, map[string]interface{}{"vault": name, "total": vaultTotal})
+		for _, item := range queryItemMaps(data["items"]) {
+			item["vault"] = name
+			items = append(items, item)
+		}
+		for _, warning := range result.Warnings {
+			warning.Message = fmt.Sprintf("vault '%s': %s", name, warning.Message)
+			warnings = append(warnings, warning)
+		}
+	}
+
+	meta := &commandexec.Meta{QueryTimeMs: time.Since(start).Milliseconds()}
+	data := map[string]interface{}{
+		"query_kind": queryKind,
+		"vaults":     totals,
+		"total":      total,
+	}
+	for _, key := range []string{"type", "trait", "saved_query"} {
+		if value, ok := first[key]; ok {
+			data[key] = value
+		}
+	}
+	if boolArg(req.Args, "count-only") {
+		meta.Count = total
+		return commandexec.SuccessWithWarnings(data, warnings, meta)
+	}
+
+	if items == nil {
+		items = []map[string]interface{}{}
+	}
+	if offset > len(items) {
+		offset = len(items)
+	}
+	items = items[offset:]
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	for i, item := range items {
+		item["num"] = offset + i + 1
+	}
+	meta.Count = len(items)
+	data["items"] = items
+	data["returned"] = len(items)
+	data["offset"] = offset
+	data["limit"] = limit
+	if limit > 0 {
+		data["has_more"] = offset+len(items) < total
+	}
+	if columns, ok := first["select"].([]string); ok {
+		data["select"] = append([]string{"vault"}, columns...)
+	}
+	return commandexec.SuccessWithWarnings(data, warnings, meta)
+}
+
+func queryItemMaps(raw interface{}) []map[string]interface{} {
+	items, _ := raw.([]map[string]interface{})
+	return items
+}
@@ -0,0 +1,70 @@
+package commandimpl
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestHandleQueryMergesResultsAcrossVaults(t *testing.T) {
+	t.Parallel()
+
+	schema := `version: 1
+traits:
+  due:
+    type: date
+`
+	work := testutil.NewTestVault(t).WithSchema(schema).
+		WithFile("plan.md", "# Plan\n- Ship @due(2026-03-01)\n").
+		Build()
+	personal := testutil.NewTestVault(t).WithSchema(schema).
+		WithFile("home.md", "# Home\n- Call @due(2026-03-01)\n- Paint @due(2026-04-01)\n").
+		Build()
+	reindexForEditTest(t, work.Path)
+	reindexForEditTest(t, personal.Path)
+
+	configPath := filepath.Join(t.TempDir(), "config.toml")
+	config := fmt.Sprintf("[vaults]\nwork = %q\npersonal = %q\n", work.Path, personal.Path)
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run := func(args map[string]any) commandexec.Result {
+		return HandleQuery(context.Background(), commandexec.Request{ConfigPath: configPath, Args: args})
+	}
+
+	result := run(map[string]any{"query_string": "trait:due .value==2026-03-01", "vaults": "work, personal"})
+	if !result.OK {
+		t.Fatalf("HandleQuery() failed: %#v", result.Error)
+	}
+	data := result.Data.(map[string]interface{})
+	items := data["items"].([]map[string]interface{})
+	if len(items) != 2 || items[0]["vault"] != "work" || items[0]["id"] != "plan.md:trait:0" || items[1]["vault"] != "personal" || items[1]["num"] != 2 {
+		t.Fatalf("items = %#v, want the work row then the personal row", items)
+	}
+	if data["total"] != 2 {
+		t.Fatalf("total = %#v, want 2", data["total"])
+	}
+
+	paged := run(map[string]any{"query_string": "trait:due", "vaults": "work,personal", "limit": 1, "offset": 1})
+	if !paged.OK {
+		t.Fatalf("paged HandleQuery() failed: %#v", paged.Error)
+	}
+	data = paged.Data.(map[string]interface{})
+	items = data["items"].([]map[string]interface{})
+	if len(items) != 1 || items[0]["id"] != "home.md:trait:0" || data["total"] != 3 || data["has_more"] != true {
+		t.Fatalf("paged data = %#v, want the second of 3 merged rows", data)
+	}
+
+	if missing := run(map[string]any{"query_string": "trait:due", "vaults": "work,nope"}); missing.OK || missing.Error.Code != codes.ErrVaultNotFound {
+		t.Fatalf("unknown vault = %#v, want VAULT_NOT_FOUND", missing.Error)
+	}
+	if apply := run(map[string]any{"query_string": "trait:due", "vaults": "work", "apply": []any{"update 2026-05-01"}}); apply.OK || apply.Error.Code != "INVALID_INPUT" {
+		t.Fatalf("--apply with --vaults = %#v, want INVALID_INPUT", apply.Error)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aidanlsb/raven/internal/codes"
 	"github.com/aidanlsb/raven/internal/commandexec"
 	"github.com/aidanlsb/raven/internal/configsvc"
 	"github.com/aidanlsb/raven/internal/model"
@@ -40,10 +41,18 @@ func HandleSearch(_ context.Context, req commandexec.Request) commandexec.Result
 		return mapSearchFailure(err)
 	}
 
-	return commandexec.Success(map[string]interface{}{
+	var warnings []commandexec.Warning
+	if !rt.DB.Capabilities().FTS5 {
+		warnings = append(warnings, commandexec.Warning{
+			Code:    codes.WarnDegradedSearch,
+			Message: "search is using LIKE matching because this SQLite build lacks FTS5; results are unranked and search syntax is approximated",
+		})
+	}
+
+	return commandexec.SuccessWithWarnings(map[string]interface{}{
 		"query":   query,
 		"results": formatSearchResults(results),
-	}, &commandexec.Meta{Count: len(results), QueryTimeMs: time.Since(start).Milliseconds()})
+	}, warnings, &commandexec.Meta{Count: len(results), QueryTimeMs: time.Since(start).Milliseconds()})
 }
 
 func mapSearchFailure(err error) commandexec.Result {
@@ -80,8 +89,12 @@ func HandleBacklinks(_ context.Context, req commandexec.Request) commandexec.Res
 	}
 	defer rt.Close()
 
+	groupByType, failure := backlinksGroupBy(req.Args)
+	if failure.Error != nil {
+		return failure
+	}
 	if backlinksStdinMode(req.Args) {
-		return handleBacklinksStdin(rt, req, start)
+		return handleBacklinksStdin(rt, req, groupByType, start)
 	}
 
 	reference := stringArg(req.Args, "target")
@@ -93,15 +106,43 @@ func HandleBacklinks(_ context.Context, req commandexec.Request) commandexec.Res
 		return mapResolveFailure(err, reference)
 	}
 
-	links, err := readsvc.Backlinks(rt, resolved.ObjectID)
+	links, err := backlinksWithContext(rt, resolved.ObjectID)
 	if err != nil {
 		return commandexec.Failure("DATABASE_ERROR", fmt.Sprintf("failed to read backlinks: %v", err), nil, "")
 	}
 
-	return commandexec.Success(map[string]interface{}{
+	data := map[string]interface{}{
 		"target": resolved.ObjectID,
 		"items":  links,
-	}, &commandexec.Meta{Count: len(links), QueryTimeMs: time.Since(start).Milliseconds()})
+	}
+	if groupByType {
+		data["groups"] = readsvc.GroupReferencesBySourceType(links)
+	}
+	return commandexec.Success(data, &commandexec.Meta{Count: len(links), QueryTimeMs: time.Since(start).Milliseconds()})
+}
+
+// backlinksGroupBy validates the backlinks --group-by value and reports whether
+// results are grouped by source type.
+func backlinksGroupBy(args map[string]interface{}) (bool, commandexec.Result) {
+	switch groupBy := strings.TrimSpace(stringArg(args, "group-by")); groupBy {
+	case "":
+		return false, commandexec.Result{}
+	case "type":
+		return true, commandexec.Result{}
+	default:
+		return false, commandexec.Failure("INVALID_INPUT", fmt.Sprintf("unknown --group-by value: %s", groupBy), nil, "Use --group-by type")
+	}
+}
+
+func backlinksWithContext(rt *readsvc.Runtime, target string) ([]model.Reference, error) {
+	links, err := readsvc.Backlinks(rt, target)
+	if err != nil {
+		return nil, err
+	}
+	if err := readsvc.AddBacklinkContext(rt, links); err != nil {
+		return nil, err
+	}
+	return links, nil
 }
 
 // HandleOutlinks executes the canonical `outlinks` command.
@@ -138,7 +179,7 @@ func HandleOutlinks(_ context.Context, req commandexec.Request) commandexec.Resu
 	}, &commandexec.Meta{Count: len(links), QueryTimeMs: time.Since(start).Milliseconds()})
 }
 
-func handleBacklinksStdin(rt *readsvc.Runtime, req commandexec.Request, start time.Time) commandexec.Result {
+func handleBacklinksStdin(rt *readsvc.Runtime, req commandexec.Request, groupByType bool, start time.Time) commandexec.Result {
 	targets := stringSliceArg(req.Args["targets"])
 	if len(targets) == 0 {
 		return commandexec.Failure("MISSING_ARGUMENT", "no targets provided via stdin", nil, "Pipe targets to stdin, one per line")
@@ -153,17 +194,21 @@ func handleBacklinksStdin(rt *readsvc.Runtime, req commandexec.Request, start ti
 			errors = append(errors, referenceInputError(target, mapResolveFailure(err, target)))
 			continue
 		}
-		links, err := readsvc.Backlinks(rt, resolved.ObjectID)
+		links, err := backlinksWithContext(rt, resolved.ObjectID)
 		if err != nil {
 			errors = append(errors, referenceInputError(target, commandexec.Failure("DATABASE_ERROR", fmt.Sprintf("failed to read backlinks: %v", err), nil, "")))
 			continue
 		}
-		groups = append(groups, model.BacklinksGroup{
+		group := model.BacklinksGroup{
 			Input:  target,
 			Target: resolved.ObjectID,
 			Items:  links,
 			Count:  len(links),
-		})
+		}
+		if groupByType {
+			group.Groups = readsvc.GroupReferencesBySourceType(links)
+		}
+		groups = append(groups, group)
 		total += len(links)
 	}
 
@@ -321,13 +366,21 @@ func HandleResolve(_ context.Context, req commandexec.Request) commandexec.Resul
 }
 
 // HandleRead executes the canonical `read` command.
-func HandleRead(_ context.Context, req commandexec.Request) commandexec.Result {
+func HandleRead(ctx context.Context, req commandexec.Request) commandexec.Result {
 	start := time.Now()
 	reference := stringArg(req.Args, "path")
 	raw := boolArg(req.Args, "raw")
 	lines := boolArg(req.Args, "lines")
 	startLine, _ := intArg(req.Args, "start-line")
 	endLine, _ := intArg(req.Args, "end-line")
+	sections := boolArg(req.Args, "sections")
+	if sections && (raw || lines || startLine > 0 || endLine > 0) {
+		return commandexec.Failure("INVALID_INPUT", "--sections cannot be combined with --raw, --lines, or a line range", nil, "Read the outline first, then use --start-line/--end-line from a section's line range")
+	}
+	render := boolArg(req.Args, "render")
+	if render && (sections || raw || lines || startLine > 0 || endLine > 0) {
+		return commandexec.Failure("INVALID_INPUT", "--render cannot be combined with --sections, --raw, --lines, or a line range", nil, "Use --render on its own to read a note with its query blocks filled in")
+	}
 
 	rt, failure := newReadRuntime(req.VaultPath, readsvc.RuntimeOptions{OpenDB: false})
 	if failure.Error != nil {
@@ -341,11 +394,24 @@ func HandleRead(_ context.Context, req commandexec.Request) commandexec.Result {
 		Lines:     lines,
 		StartLine: startLine,
 		EndLine:   endLine,
+		Sections:  sections,
+		Render:    render,
 	})
 	if err != nil {
 		return mapReadFailure(err)
 	}
 
+	if result.Outline != nil {
+		return commandexec.Success(map[string]interface{}{
+			"object_id":  result.ObjectID,
+			"path":       result.Path,
+			"line_count": result.LineCount,
+			"type":       result.Outline.Type,
+			"traits":     result.Outline.Traits,
+			"sections":   result.Outline.Sections,
+		}, &commandexec.Meta{Count: len(result.Outline.Sections), QueryTimeMs: time.Since(start).Milliseconds()})
+	}
+
 	data := map[string]interface{}{
 		"object_id":  result.ObjectID,
 		"path":       result.Path,
@@ -355,6 +421,10 @@ func HandleRead(_ context.Context, req commandexec.Request) commandexec.Result {
 	if result.StartLine > 0 {
 		data["start_line"] = result.StartLine
 		data["end_line"] = result.EndLine
+		data["has_more"] = result.EndLine < result.LineCount
+		if result.EndLine < result.LineCount {
+			data["next_start_line"] = result.EndLine + 1
+		}
 	}
 
 	rawMode := raw || lines || startLine > 0 || endLine > 0
@@ -368,8 +438,16 @@ func HandleRead(_ context.Context, req commandexec.Request) commandexec.Result {
 
 	data["references"] = result.References
 	data["backlinks"] = result.Backlinks
+	if render {
+		data["query_blocks"] = result.QueryBlocks
+	}
 	meta.Count = result.BacklinksCount
-	return commandexec.Success(data, meta)
+	if result.Issues == nil {
+		return commandexec.Success(data, meta)
+	}
+	issues, warnings := enrichIssueRefs(ctx, rt.VaultPath, rt.VaultCfg, result.Issues)
+	data["issues"] = issues
+	return commandexec.SuccessWithWarnings(data, warnings, meta)
 }
 
 // HandleOpen executes the canonical `open` command.
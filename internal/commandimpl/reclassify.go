@@ -23,6 +23,7 @@ func HandleReclassify(_ context.Context, req commandexec.Request) commandexec.Re
 	if err != nil {
 		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
 	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
 
 	sch, err := schema.Load(vaultPath)
 	if err != nil {
@@ -78,6 +79,7 @@ func HandleReclassify(_ context.Context, req commandexec.Request) commandexec.Re
 		})
 	}
 	if result.ChangedFilePath != "" {
+		stampAttribution(vaultPath, vaultCfg, false, result.ChangedFilePath)
 		warnings = appendCommandWarnings(
 			warnings,
 			autoReindexWarnings(vaultPath, vaultCfg, result.ChangedFilePath),
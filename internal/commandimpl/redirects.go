@@ -0,0 +1,135 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/redirects"
+)
+
+// Redirect statuses reported by redirects list. Only ok redirects take part
+// in resolution; prune removes the rest.
+const (
+	redirectStatusOK            = "ok"
+	redirectStatusMissingTarget = "missing_target"
+	redirectStatusShadowed      = "shadowed"
+	redirectStatusCycle         = "cycle"
+)
+
+// HandleRedirectsList executes the canonical `redirects_list` command.
+func HandleRedirectsList(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	items, failure := loadRedirectItems(vaultPath)
+	if failure != nil {
+		return *failure
+	}
+	return commandexec.Success(map[string]interface{}{
+		"file":  redirects.FileName,
+		"items": items,
+	}, &commandexec.Meta{Count: len(items)})
+}
+
+// HandleRedirectsPrune executes the canonical `redirects_prune` command.
+func HandleRedirectsPrune(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	items, failure := loadRedirectItems(vaultPath)
+	if failure != nil {
+		return *failure
+	}
+	stale := make([]map[string]interface{}, 0)
+	for _, item := range items {
+		if item["status"] != redirectStatusOK {
+			stale = append(stale, item)
+		}
+	}
+
+	data := map[string]interface{}{
+		"file":      redirects.FileName,
+		"preview":   !req.Confirm,
+		"removed":   stale,
+		"remaining": len(items) - len(stale),
+	}
+	if !req.Confirm || len(stale) == 0 {
+		return commandexec.Success(data, &commandexec.Meta{Count: len(stale)})
+	}
+
+	redirectMap, err := redirects.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("FILE_READ_ERROR", err.Error(), nil, "Fix "+redirects.FileName+" and try again")
+	}
+	for _, item := range stale {
+		delete(redirectMap, item["from"].(string))
+	}
+	if err := redirects.Save(vaultPath, redirectMap); err != nil {
+		return commandexec.Failure("FILE_WRITE_ERROR", err.Error(), nil, "")
+	}
+	return commandexec.Success(data, &commandexec.Meta{Count: len(stale)})
+}
+
+// loadRedirectItems returns every redirect with the ID it resolves to and
+// its status, checked against the index.
+func loadRedirectItems(vaultPath string) ([]map[string]interface{}, *commandexec.Result) {
+	redirectMap, err := redirects.Load(vaultPath)
+	if err != nil {
+		failure := commandexec.Failure("FILE_READ_ERROR", err.Error(), nil, "Fix "+redirects.FileName+" and try again")
+		return nil, &failure
+	}
+
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		failure := commandexec.Failure("DATABASE_ERROR", "failed to open database", nil, "Run 'rvn reindex' to rebuild the database")
+		return nil, &failure
+	}
+	defer db.Close()
+
+	objectExists := func(id string) (bool, error) {
+		obj, err := db.GetObject(id)
+		return obj != nil, err
+	}
+
+	items := make([]map[string]interface{}, 0, len(redirectMap))
+	for _, from := range redirects.Sources(redirectMap) {
+		item := map[string]interface{}{
+			"from": from,
+			"to":   redirectMap[from],
+		}
+		target, ok := redirects.Lookup(redirectMap, from)
+		if ok {
+			item["resolves_to"] = target
+		}
+
+		shadowed, err := objectExists(from)
+		if err != nil {
+			failure := commandexec.Failure("DATABASE_ERROR", err.Error(), nil, "Run 'rvn reindex' to rebuild the database")
+			return nil, &failure
+		}
+		switch {
+		case !ok:
+			item["status"] = redirectStatusCycle
+		case shadowed:
+			item["status"] = redirectStatusShadowed
+		default:
+			found, err := objectExists(target)
+			if err != nil {
+				failure := commandexec.Failure("DATABASE_ERROR", err.Error(), nil, "Run 'rvn reindex' to rebuild the database")
+				return nil, &failure
+			}
+			item["status"] = redirectStatusOK
+			if !found {
+				item["status"] = redirectStatusMissingTarget
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
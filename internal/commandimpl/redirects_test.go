@@ -0,0 +1,72 @@
+package commandimpl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/readsvc"
+	"github.com/aidanlsb/raven/internal/redirects"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestMoveRecordsRedirectThatResolvesAndPrunes(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(testutil.PersonProjectSchema()).
+		WithFile("people/freya.md", "---\ntype: person\nname: Freya\n---\n## Notes\n").
+		Build()
+	reindexForEditTest(t, v.Path)
+
+	moved := HandleMove(context.Background(), commandexec.Request{
+		VaultPath: v.Path,
+		Args:      map[string]any{"source": "people/freya", "destination": "people/freyja"},
+	})
+	if !moved.OK {
+		t.Fatalf("HandleMove() failed: %#v", moved.Error)
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(v.Path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	resolved, err := readsvc.ResolveReference("people/freya#notes", &readsvc.Runtime{VaultPath: v.Path, VaultCfg: vaultCfg}, false)
+	if err != nil {
+		t.Fatalf("ResolveReference(old ID) error: %v", err)
+	}
+	if resolved.ObjectID != "people/freyja#notes" || resolved.MatchSource != "redirect" {
+		t.Fatalf("resolved = %+v, want people/freyja#notes via redirect", resolved)
+	}
+
+	listed := HandleRedirectsList(context.Background(), commandexec.Request{VaultPath: v.Path})
+	items := listed.Data.(map[string]interface{})["items"].([]map[string]interface{})
+	if len(items) != 1 || items[0]["from"] != "people/freya" || items[0]["status"] != redirectStatusOK {
+		t.Fatalf("items = %#v, want one ok redirect from people/freya", items)
+	}
+
+	if err := os.Remove(filepath.Join(v.Path, "people", "freyja.md")); err != nil {
+		t.Fatalf("remove target: %v", err)
+	}
+	reindexForEditTest(t, v.Path)
+
+	preview := HandleRedirectsPrune(context.Background(), commandexec.Request{VaultPath: v.Path})
+	removed := preview.Data.(map[string]interface{})["removed"].([]map[string]interface{})
+	if len(removed) != 1 || removed[0]["status"] != redirectStatusMissingTarget {
+		t.Fatalf("prune preview removed = %#v, want the missing_target redirect", removed)
+	}
+	if _, err := os.Stat(redirects.Path(v.Path)); err != nil {
+		t.Fatalf("preview should leave %s in place: %v", redirects.FileName, err)
+	}
+
+	pruned := HandleRedirectsPrune(context.Background(), commandexec.Request{VaultPath: v.Path, Confirm: true})
+	if !pruned.OK {
+		t.Fatalf("HandleRedirectsPrune() failed: %#v", pruned.Error)
+	}
+	if _, err := os.Stat(redirects.Path(v.Path)); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed after pruning its only redirect, stat err = %v", redirects.FileName, err)
+	}
+}
@@ -1,6 +1,9 @@
 package commandimpl
 
-import "github.com/aidanlsb/raven/internal/commandexec"
+import (
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/dates"
+)
 
 // RegisterAll registers canonical command handlers.
 func RegisterAll(registry *commandexec.HandlerRegistry) {
@@ -10,27 +13,66 @@ func RegisterAll(registry *commandexec.HandlerRegistry) {
 
 	registry.Register("new", HandleNew)
 	registry.Register("upsert", HandleUpsert)
-	registry.Register("add", HandleAdd)
-	registry.Register("set", HandleSet)
+	registry.Register("add", withPreviewGuard("object_ids", true, withBulkCheckpoints("object_ids", HandleAdd)))
+	registry.Register("set", withFocusTarget(withPreviewGuard("object_ids", true, withBulkCheckpoints("object_ids", HandleSet))))
 	registry.Register("unset", HandleUnset)
-	registry.Register("delete", HandleDelete)
-	registry.Register("move", HandleMove)
+	registry.Register("toggle", withFocusTarget(withPreviewGuard("object_ids", true, HandleToggle)))
+	registry.Register("focus_add", HandleFocusAdd)
+	registry.Register("focus_list", HandleFocusList)
+	registry.Register("focus_clear", HandleFocusClear)
+	registry.Register("graph_export", HandleGraphExport)
+	registry.Register("orphans", HandleOrphans)
+	registry.Register("deadlinks", HandleDeadlinks)
+	registry.Register("publish", HandlePublish)
+	registry.Register("delete", withPreviewGuard("object_ids", false, withBulkCheckpoints("object_ids", HandleDelete)))
+	registry.Register("move", withPreviewGuard("object_ids", false, withBulkCheckpoints("object_ids", HandleMove)))
+	registry.Register("rename", HandleRename)
 	registry.Register("reclassify", HandleReclassify)
-	registry.Register("update", HandleUpdate)
+	registry.Register("archive", HandleArchive)
+	registry.Register("update", withPreviewGuard("trait_ids", true, withBulkCheckpoints("trait_ids", HandleUpdate)))
+	registry.Register("trait_set", HandleTraitSet)
+	registry.Register("task_list", HandleTaskList)
+	registry.Register("task_done", HandleTaskDone)
+	registry.Register("task_snooze", HandleTaskSnooze)
+	registry.Register("task_schedule", HandleTaskSchedule)
 	registry.Register("edit", HandleEdit)
+	registry.Register("lock", HandleLock)
+	registry.Register("unlock", HandleUnlock)
+	registry.Register("vault_encrypt", HandleVaultEncrypt)
+	registry.Register("vault_decrypt", HandleVaultDecrypt)
+	registry.Register("sync_external", HandleSyncExternal)
+	registry.Register("sync_git", HandleSyncGit)
 	registry.Register("import", HandleImport)
+	registry.Register("import_csv", HandleImportCSV)
+	registry.Register("import_markdown", HandleImportMarkdown)
+	registry.Register("import_obsidian", HandleImportObsidian)
+	registry.Register("import_logseq", HandleImportLogseq)
+	registry.Register("import_org_roam", HandleImportOrgRoam)
+	registry.Register("import_notion", HandleImportNotion)
+	registry.Register("resume", HandleResume)
+	registry.Register("history", HandleHistory)
+	registry.Register("redirects_list", HandleRedirectsList)
+	registry.Register("redirects_prune", HandleRedirectsPrune)
+	registry.Register("undo", HandleUndo)
 	registry.Register("init", HandleInit)
 	registry.Register("reindex", HandleReindex)
 	registry.Register("check", HandleCheck)
 	registry.Register("check_fix", HandleCheckFix)
 	registry.Register("check create-missing", HandleCheckCreateMissing)
+	registry.Register("fmt", HandleFmt)
+	registry.Register("health", HandleHealth)
+	registry.Register("doctor", HandleDoctor)
 	registry.Register("daily", HandleDaily)
+	registry.Register("weekly", HandlePeriodic(dates.PeriodWeek))
+	registry.Register("monthly", HandlePeriodic(dates.PeriodMonth))
+	registry.Register("quarterly", HandlePeriodic(dates.PeriodQuarter))
 	registry.Register("date", HandleDate)
 	registry.Register("version", HandleVersion)
 	registry.Register("config_show", HandleConfigShow)
 	registry.Register("config_init", HandleConfigInit)
 	registry.Register("config_set", HandleConfigSet)
 	registry.Register("config_unset", HandleConfigUnset)
+	registry.Register("config_edit", HandleConfigEdit)
 	registry.Register("vault_list", HandleVaultList)
 	registry.Register("vault_current", HandleVaultCurrent)
 	registry.Register("vault_path", HandleVaultPath)
@@ -62,10 +104,17 @@ func RegisterAll(registry *commandexec.HandlerRegistry) {
 	registry.Register("read", HandleRead)
 	registry.Register("open", HandleOpen)
 	registry.Register("query", HandleQuery)
+	registry.Register("list", HandleList)
+	registry.Register("inbox_list", HandleInboxList)
+	registry.Register("suggest-type", HandleSuggestType)
 	registry.Register("query_saved_list", HandleQuerySavedList)
 	registry.Register("query_saved_get", HandleQuerySavedGet)
 	registry.Register("query_saved_set", HandleQuerySavedSet)
 	registry.Register("query_saved_remove", HandleQuerySavedRemove)
+	registry.Register("query_snapshot", HandleQuerySnapshot)
+	registry.Register("query_diff", HandleQueryDiff)
+	registry.Register("dashboard", HandleDashboard)
+	registry.Register("drift_report", HandleDriftReport)
 	registry.Register("docs", HandleDocs)
 	registry.Register("docs_fetch", HandleDocsFetch)
 	registry.Register("docs_list", HandleDocsList)
@@ -88,6 +137,11 @@ func RegisterAll(registry *commandexec.HandlerRegistry) {
 	registry.Register("schema_remove_type", HandleSchemaRemoveType)
 	registry.Register("schema_remove_trait", HandleSchemaRemoveTrait)
 	registry.Register("schema_remove_field", HandleSchemaRemoveField)
+	registry.Register("schema_impact", HandleSchemaImpact)
+	registry.Register("schema_infer", HandleSchemaInfer)
+	registry.Register("schema_export", HandleSchemaExport)
+	registry.Register("schema_import", HandleSchemaImport)
+	registry.Register("schema_export_snippets", HandleSchemaExportSnippets)
 	registry.Register("schema_rename_type", HandleSchemaRenameType)
 	registry.Register("schema_rename_field", HandleSchemaRenameField)
 	registry.Register("schema_template_list", HandleSchemaTemplateList)
@@ -100,4 +154,7 @@ func RegisterAll(registry *commandexec.HandlerRegistry) {
 	registry.Register("template_list", HandleTemplateList)
 	registry.Register("template_write", HandleTemplateWrite)
 	registry.Register("template_delete", HandleTemplateDelete)
+
+	recordHistory(registry)
+	enforceAgentPolicy(registry)
 }
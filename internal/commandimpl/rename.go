@@ -0,0 +1,67 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/objectsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// HandleRename executes the canonical `rename` command.
+func HandleRename(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
+
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		sch = schema.New()
+	}
+
+	id := strings.TrimSpace(stringArg(req.Args, "id"))
+	newID := strings.TrimSpace(stringArg(req.Args, "new_id"))
+	if id == "" || newID == "" {
+		return commandexec.Failure("MISSING_ARGUMENT", "requires id and new_id arguments", nil, "Usage: rvn rename <id> <new-id>")
+	}
+
+	result, err := objectsvc.RenameObject(objectsvc.RenameRequest{
+		VaultPath:    vaultPath,
+		VaultConfig:  vaultCfg,
+		Schema:       sch,
+		Reference:    id,
+		NewID:        newID,
+		Confirm:      req.Confirm,
+		ParseOptions: buildParseOptions(vaultCfg),
+	})
+	if err != nil {
+		return mapContentMutationError(err)
+	}
+
+	warnings := warningMessagesToCommandWarnings(result.WarningMessages, indexUpdateFailedWarningCode)
+	data := map[string]interface{}{
+		"old_id":   result.OldID,
+		"new_id":   result.NewID,
+		"old_path": result.OldPath,
+		"new_path": result.NewPath,
+		"changes":  result.Changes,
+	}
+	if result.Preview {
+		data["preview"] = true
+		data["total_changes"] = result.TotalChanges
+		data["hint"] = result.Hint
+	} else {
+		data["renamed"] = true
+		data["changes_applied"] = result.ChangesApplied
+	}
+	return commandexec.SuccessWithWarnings(data, warnings, &commandexec.Meta{Count: result.TotalChanges})
+}
@@ -0,0 +1,101 @@
+package commandimpl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/bulkops"
+	"github.com/aidanlsb/raven/internal/commandexec"
+)
+
+type resumeOperationSummary struct {
+	ID        string `json:"id"`
+	Command   string `json:"command"`
+	Pending   int    `json:"pending"`
+	Completed int    `json:"completed"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// HandleResume executes the canonical `resume` command.
+func HandleResume(ctx context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	operationID := strings.TrimSpace(stringArg(req.Args, "operation_id"))
+	if operationID == "" {
+		checkpoints, err := bulkops.ListCheckpoints(vaultPath)
+		if err != nil {
+			return commandexec.Failure("FILE_READ_ERROR", fmt.Sprintf("failed to list operations: %v", err), nil, "")
+		}
+		operations := make([]resumeOperationSummary, 0, len(checkpoints))
+		for _, cp := range checkpoints {
+			operations = append(operations, summarizeCheckpoint(cp))
+		}
+		return commandexec.Success(map[string]interface{}{
+			"operations": operations,
+		}, &commandexec.Meta{Count: len(operations)})
+	}
+
+	cp, err := bulkops.LoadCheckpoint(vaultPath, operationID)
+	if err != nil {
+		if bulkErr, ok := bulkops.AsError(err); ok {
+			return commandexec.Failure(bulkErr.Code, bulkErr.Message, nil, bulkErr.Suggestion)
+		}
+		return commandexec.Failure("FILE_READ_ERROR", err.Error(), nil, "")
+	}
+
+	if !req.Confirm {
+		summary := summarizeCheckpoint(cp)
+		return commandexec.Success(map[string]interface{}{
+			"preview":     true,
+			"operation":   summary,
+			"pending_ids": cp.Pending,
+		}, &commandexec.Meta{Count: len(cp.Pending)})
+	}
+
+	invoker, ok := commandexec.InvokerFromContext(ctx)
+	if !ok {
+		return commandexec.Failure("INTERNAL_ERROR", "resume runtime is unavailable", nil, "Retry the command")
+	}
+	handler, ok := invoker.Handlers().Lookup(cp.Command)
+	if !ok {
+		return commandexec.Failure("COMMAND_NOT_FOUND", fmt.Sprintf("operation %s uses unknown command: %s", cp.ID, cp.Command), nil, "")
+	}
+	if len(cp.Pending) == 0 {
+		_ = bulkops.RemoveCheckpoint(vaultPath, cp.ID)
+		return commandexec.Success(map[string]interface{}{
+			"operation_id": cp.ID,
+			"action":       cp.Command,
+			"results":      []interface{}{},
+			"total":        0,
+			"resumed":      true,
+		}, &commandexec.Meta{Count: 0})
+	}
+
+	return handler(withResumeCheckpoint(ctx, cp), commandexec.Request{
+		CommandID:      cp.Command,
+		VaultPath:      req.VaultPath,
+		ConfigPath:     req.ConfigPath,
+		StatePath:      req.StatePath,
+		ExecutablePath: req.ExecutablePath,
+		Caller:         req.Caller,
+		Args:           checkpointArgs(cp, cp.Pending),
+		Confirm:        true,
+	})
+}
+
+func summarizeCheckpoint(cp *bulkops.Checkpoint) resumeOperationSummary {
+	return resumeOperationSummary{
+		ID:        cp.ID,
+		Command:   cp.Command,
+		Pending:   len(cp.Pending),
+		Completed: len(cp.Completed),
+		CreatedAt: cp.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: cp.UpdatedAt.Format(time.RFC3339),
+	}
+}
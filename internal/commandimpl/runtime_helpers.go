@@ -14,7 +14,9 @@ import (
 	"github.com/aidanlsb/raven/internal/config"
 	"github.com/aidanlsb/raven/internal/index"
 	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/query"
 	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 const indexUpdateFailedWarningCode = codes.WarnIndexUpdateFailed
@@ -25,10 +27,25 @@ func buildParseOptions(vaultCfg *config.VaultConfig) *parser.ParseOptions {
 	if vaultCfg == nil {
 		return nil
 	}
-	return &parser.ParseOptions{
-		ObjectsRoot: vaultCfg.GetObjectsRoot(),
-		PagesRoot:   vaultCfg.GetPagesRoot(),
+	opts := &parser.ParseOptions{
+		ObjectsRoot:        vaultCfg.GetObjectsRoot(),
+		PagesRoot:          vaultCfg.GetPagesRoot(),
+		DateMentionFormats: vaultCfg.GetDateMentionFormats(),
+		HashtagTrait:       vaultCfg.GetHashtagTrait(),
 	}
+	if issueRefs := vaultCfg.GetIssueRefs(); issueRefs != nil {
+		opts.IssueRefs = &parser.IssueRefOptions{JiraProjects: issueRefs.JiraProjects(), JiraURL: issueRefs.JiraURL()}
+	}
+	return opts
+}
+
+// applyUnlockArg drops locked_files from vaultCfg when the request passes
+// --unlock, so the invocation may modify locked files.
+func applyUnlockArg(req commandexec.Request, vaultCfg *config.VaultConfig) *config.VaultConfig {
+	if !boolArg(req.Args, "unlock") {
+		return vaultCfg
+	}
+	return vaultCfg.WithoutLocks()
 }
 
 func autoReindexWarnings(vaultPath string, vaultCfg *config.VaultConfig, filePaths ...string) []commandexec.Warning {
@@ -61,7 +78,7 @@ func autoReindexWarning(vaultPath, filePath string, vaultCfg *config.VaultConfig
 		return indexUpdateWarning(vaultPath, filePath, "failed to load schema", err), true
 	}
 
-	content, err := os.ReadFile(filePath)
+	content, err := vaultcrypt.ReadFile(filePath)
 	if err != nil {
 		return indexUpdateWarning(vaultPath, filePath, "failed to read file", err), true
 	}
@@ -85,6 +102,9 @@ func autoReindexWarning(vaultPath, filePath string, vaultCfg *config.VaultConfig
 	if err := db.IndexDocumentWithMtime(doc, sch, mtime); err != nil {
 		return indexUpdateWarning(vaultPath, filePath, "failed to update index", err), true
 	}
+	if err := query.MaterializeDerivedFields(db, sch, vaultCfg.GetDailyDirectory()); err != nil {
+		return indexUpdateWarning(vaultPath, filePath, "failed to compute derived fields", err), true
+	}
 	return commandexec.Warning{}, false
 }
 
@@ -55,13 +55,20 @@ func HandleSchema(_ context.Context, req commandexec.Request) commandexec.Result
 		if result.Hint != nil {
 			data["hint"] = result.Hint
 		}
+		if result.UsageUnavailable {
+			data["usage_unavailable"] = true
+		}
 		return commandexec.Success(data, &commandexec.Meta{Count: len(result.Types), QueryTimeMs: time.Since(start).Milliseconds()})
 	case "traits":
 		result, err := schemasvc.Traits(req.VaultPath)
 		if err != nil {
 			return mapSchemaFailure(err)
 		}
-		return commandexec.Success(map[string]interface{}{"traits": result.Traits}, &commandexec.Meta{Count: len(result.Traits), QueryTimeMs: time.Since(start).Milliseconds()})
+		data := map[string]interface{}{"traits": result.Traits}
+		if result.UsageUnavailable {
+			data["usage_unavailable"] = true
+		}
+		return commandexec.Success(data, &commandexec.Meta{Count: len(result.Traits), QueryTimeMs: time.Since(start).Milliseconds()})
 	case "core":
 		if name == "" {
 			result, err := schemasvc.CoreList(req.VaultPath)
@@ -211,11 +218,41 @@ func HandleSchemaUpdateField(_ context.Context, req commandexec.Request) command
 		Values:      commaStringArg(req.Args, "values"),
 		Target:      stringArg(req.Args, "target"),
 		Description: stringArg(req.Args, "description"),
+		Confirm:     req.Confirm,
+		Force:       boolArg(req.Args, "force"),
 	})
 	if err != nil {
 		return mapSchemaFailure(err)
 	}
-	return commandexec.Success(schemapayload.Update("field", "", typeName, fieldName, result.Changes), &commandexec.Meta{QueryTimeMs: time.Since(start).Milliseconds()})
+	data := schemapayload.Update("field", "", typeName, fieldName, result.Changes)
+	if result.Impact != nil {
+		data["impact"] = result.Impact
+	}
+	meta := &commandexec.Meta{QueryTimeMs: time.Since(start).Milliseconds()}
+	if result.Migration == nil {
+		return commandexec.Success(data, meta)
+	}
+	data["preview"] = result.Preview
+	data["migration"] = result.Migration
+	if result.Preview {
+		return commandexec.Success(data, meta)
+	}
+
+	var warnings []commandexec.Warning
+	if len(result.Migration.Failures) > 0 {
+		warnings = append(warnings, commandexec.Warning{
+			Code:    checkApplyIncompleteWarningCode,
+			Message: fmt.Sprintf("%d value(s) could not be converted and were left unchanged.", len(result.Migration.Failures)),
+		})
+	}
+	if vaultCfg, err := config.LoadVaultConfig(req.VaultPath); err == nil {
+		changed := make([]string, 0, len(result.Migration.Conversions))
+		for _, conversion := range result.Migration.Conversions {
+			changed = append(changed, filepath.Join(req.VaultPath, filepath.FromSlash(conversion.FilePath)))
+		}
+		warnings = append(warnings, autoReindexWarnings(req.VaultPath, vaultCfg, changed...)...)
+	}
+	return commandexec.SuccessWithWarnings(data, warnings, meta)
 }
 
 // HandleSchemaRemoveType executes the canonical `schema_remove_type` command.
@@ -231,6 +268,7 @@ func HandleSchemaRemoveType(_ context.Context, req commandexec.Request) commande
 		return mapSchemaFailure(err)
 	}
 	data := schemapayload.Remove("type", stringArg(req.Args, "name"), "", "")
+	data["impact"] = result.Impact
 	warnings := canonicalSchemaWarnings(result.Warnings)
 	if len(warnings) > 0 {
 		return commandexec.SuccessWithWarnings(data, warnings, &commandexec.Meta{QueryTimeMs: time.Since(start).Milliseconds()})
@@ -251,6 +289,7 @@ func HandleSchemaRemoveTrait(_ context.Context, req commandexec.Request) command
 		return mapSchemaFailure(err)
 	}
 	data := schemapayload.Remove("trait", stringArg(req.Args, "name"), "", "")
+	data["impact"] = result.Impact
 	warnings := canonicalSchemaWarnings(result.Warnings)
 	if len(warnings) > 0 {
 		return commandexec.SuccessWithWarnings(data, warnings, &commandexec.Meta{QueryTimeMs: time.Since(start).Milliseconds()})
@@ -263,14 +302,138 @@ func HandleSchemaRemoveField(_ context.Context, req commandexec.Request) command
 	start := time.Now()
 	typeName := stringArg(req.Args, "type_name")
 	fieldName := stringArg(req.Args, "field_name")
-	if _, err := schemasvc.RemoveField(schemasvc.RemoveFieldRequest{
+	result, err := schemasvc.RemoveField(schemasvc.RemoveFieldRequest{
 		VaultPath: req.VaultPath,
 		TypeName:  typeName,
 		FieldName: fieldName,
-	}); err != nil {
+	})
+	if err != nil {
+		return mapSchemaFailure(err)
+	}
+	data := schemapayload.Remove("field", "", typeName, fieldName)
+	data["impact"] = result.Impact
+	return commandexec.Success(data, &commandexec.Meta{QueryTimeMs: time.Since(start).Milliseconds()})
+}
+
+// HandleSchemaImpact executes the canonical `schema_impact` command.
+func HandleSchemaImpact(_ context.Context, req commandexec.Request) commandexec.Result {
+	start := time.Now()
+	kind := strings.TrimSpace(stringArg(req.Args, "kind"))
+	name := strings.TrimSpace(stringArg(req.Args, "name"))
+	field := strings.TrimSpace(stringArg(req.Args, "field"))
+	values := commaStringArg(req.Args, "values")
+
+	var (
+		impact *schemasvc.Impact
+		err    error
+	)
+	switch kind {
+	case "type":
+		impact, err = schemasvc.AnalyzeTypeImpact(req.VaultPath, name)
+	case "trait":
+		impact, err = schemasvc.AnalyzeTraitImpact(req.VaultPath, name)
+	case "field":
+		if field == "" {
+			return commandexec.Failure("MISSING_ARGUMENT", "specify a field name", nil, "Usage: rvn schema impact field <type> <field>")
+		}
+		if strings.TrimSpace(values) != "" {
+			dropped := make([]string, 0)
+			for _, value := range strings.Split(values, ",") {
+				if value = strings.TrimSpace(value); value != "" {
+					dropped = append(dropped, value)
+				}
+			}
+			impact, err = schemasvc.AnalyzeEnumValuesImpact(req.VaultPath, name, field, dropped)
+		} else {
+			impact, err = schemasvc.AnalyzeFieldImpact(req.VaultPath, name, field)
+		}
+	default:
+		return commandexec.Failure("INVALID_INPUT", fmt.Sprintf("unknown impact kind: %s", kind), nil, "Use: type <name>, trait <name>, or field <type> <field>")
+	}
+	if err != nil {
+		return mapSchemaFailure(err)
+	}
+	return commandexec.Success(map[string]interface{}{"impact": impact}, &commandexec.Meta{Count: len(impact.Files), QueryTimeMs: time.Since(start).Milliseconds()})
+}
+
+// HandleSchemaInfer executes the canonical `schema_infer` command.
+func HandleSchemaInfer(_ context.Context, req commandexec.Request) commandexec.Result {
+	start := time.Now()
+	minFiles, _ := intArg(req.Args, "min-files")
+	result, err := schemasvc.Infer(schemasvc.InferRequest{
+		VaultPath: req.VaultPath,
+		MinFiles:  minFiles,
+		Accept:    stringSliceArg(req.Args["accept"]),
+		Confirm:   req.Confirm,
+	})
+	if err != nil {
+		return mapSchemaFailure(err)
+	}
+	data := map[string]interface{}{
+		"preview": result.Preview,
+		"types":   result.Types,
+		"traits":  result.Traits,
+	}
+	meta := &commandexec.Meta{Count: len(result.Types) + len(result.Traits), QueryTimeMs: time.Since(start).Milliseconds()}
+	if result.Preview {
+		return commandexec.Success(data, meta)
+	}
+
+	data["applied"] = result.Applied
+	data["typed_files"] = result.TypedFiles
+	vaultCfg, err := config.LoadVaultConfig(req.VaultPath)
+	if err != nil {
+		return commandexec.Success(data, meta)
+	}
+	changed := make([]string, 0, len(result.TypedFiles))
+	for _, relPath := range result.TypedFiles {
+		changed = append(changed, filepath.Join(req.VaultPath, filepath.FromSlash(relPath)))
+	}
+	return commandexec.SuccessWithWarnings(data, autoReindexWarnings(req.VaultPath, vaultCfg, changed...), meta)
+}
+
+// HandleSchemaExport executes the canonical `schema_export` command.
+func HandleSchemaExport(_ context.Context, req commandexec.Request) commandexec.Result {
+	start := time.Now()
+	result, err := schemasvc.Export(schemasvc.ExportRequest{
+		VaultPath: req.VaultPath,
+		Types:     stringSliceArg(req.Args["types"]),
+		Traits:    stringSliceArg(req.Args["traits"]),
+	})
+	if err != nil {
+		return mapSchemaFailure(err)
+	}
+	return commandexec.Success(result, &commandexec.Meta{Count: len(result.Types) + len(result.Traits), QueryTimeMs: time.Since(start).Milliseconds()})
+}
+
+// HandleSchemaImport executes the canonical `schema_import` command.
+func HandleSchemaImport(_ context.Context, req commandexec.Request) commandexec.Result {
+	start := time.Now()
+	result, err := schemasvc.Import(schemasvc.ImportRequest{
+		VaultPath:  req.VaultPath,
+		File:       stringArg(req.Args, "file"),
+		OnConflict: stringArg(req.Args, "on-conflict"),
+		Confirm:    req.Confirm,
+	})
+	if err != nil {
+		return mapSchemaFailure(err)
+	}
+	return commandexec.Success(result, &commandexec.Meta{Count: len(result.Entries), QueryTimeMs: time.Since(start).Milliseconds()})
+}
+
+// HandleSchemaExportSnippets executes the canonical `schema_export_snippets` command.
+func HandleSchemaExportSnippets(_ context.Context, req commandexec.Request) commandexec.Result {
+	start := time.Now()
+	result, err := schemasvc.ExportSnippets(schemasvc.ExportSnippetsRequest{
+		VaultPath: req.VaultPath,
+		Format:    stringArg(req.Args, "format"),
+		Types:     stringSliceArg(req.Args["type"]),
+		OutputDir: strings.TrimSpace(stringArg(req.Args, "output")),
+	})
+	if err != nil {
 		return mapSchemaFailure(err)
 	}
-	return commandexec.Success(schemapayload.Remove("field", "", typeName, fieldName), &commandexec.Meta{QueryTimeMs: time.Since(start).Milliseconds()})
+	return commandexec.Success(result, &commandexec.Meta{Count: len(result.Files), QueryTimeMs: time.Since(start).Milliseconds()})
 }
 
 // HandleSchemaRenameType executes the canonical `schema_rename_type` command.
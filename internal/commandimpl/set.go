@@ -58,6 +58,7 @@ func HandleSet(_ context.Context, req commandexec.Request) commandexec.Result {
 	if err != nil {
 		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
 	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
 
 	sch, err := schema.Load(vaultPath)
 	if err != nil {
@@ -85,7 +86,7 @@ func HandleSet(_ context.Context, req commandexec.Request) commandexec.Result {
 		if len(allUpdates) == 0 {
 			return commandexec.Failure("MISSING_ARGUMENT", "no fields to set", nil, setMissingFields(req.Caller, true))
 		}
-		return runSetBulk(vaultPath, vaultCfg, sch, objectIDs, allUpdates, req.Confirm)
+		return withBulkFailureReport(runSetBulk(vaultPath, vaultCfg, sch, objectIDs, allUpdates, req.Confirm), req, "object_ids")
 	}
 
 	reference := strings.TrimSpace(stringArg(req.Args, "object_id"))
@@ -129,6 +130,7 @@ func HandleSet(_ context.Context, req commandexec.Request) commandexec.Result {
 		)
 	}
 
+	stampAttribution(vaultPath, vaultCfg, false, serviceResult.FilePath)
 	warnings := appendCommandWarnings(
 		warningMessagesToCommandWarnings(serviceResult.WarningMessages, codes.WarnUnknownField),
 		autoReindexWarnings(vaultPath, vaultCfg, serviceResult.FilePath),
@@ -156,6 +158,7 @@ func HandleUnset(_ context.Context, req commandexec.Request) commandexec.Result
 	if err != nil {
 		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
 	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
 
 	sch, err := schema.Load(vaultPath)
 	if err != nil {
@@ -186,6 +189,7 @@ func HandleUnset(_ context.Context, req commandexec.Request) commandexec.Result
 
 	var warnings []commandexec.Warning
 	if serviceResult.Modified {
+		stampAttribution(vaultPath, vaultCfg, false, serviceResult.FilePath)
 		warnings = autoReindexWarnings(vaultPath, vaultCfg, serviceResult.FilePath)
 	}
 
@@ -229,7 +233,9 @@ func runSetBulk(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Sche
 
 	var reindexWarnings []commandexec.Warning
 	var affectedFiles []string
+	stamper := newAttributionStamper(vaultPath, vaultCfg)
 	summary, err := objectsvc.ApplySetBulk(request, func(filePath string) {
+		stamper.stamp(false, filePath)
 		reindexWarnings = appendCommandWarnings(reindexWarnings, autoReindexWarnings(vaultPath, vaultCfg, filePath))
 		if rel, relErr := filepath.Rel(vaultPath, filePath); relErr == nil {
 			affectedFiles = append(affectedFiles, rel)
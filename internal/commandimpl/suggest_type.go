@@ -0,0 +1,85 @@
+package commandimpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/readsvc"
+)
+
+// HandleSuggestType executes the canonical `suggest-type` command.
+func HandleSuggestType(_ context.Context, req commandexec.Request) commandexec.Result {
+	start := time.Now()
+
+	reference := strings.TrimSpace(stringArg(req.Args, "object"))
+	all := boolArg(req.Args, "all")
+	if reference == "" && !all {
+		return commandexec.Failure("MISSING_ARGUMENT", "requires an object or --all", nil, "Usage: rvn suggest-type <object> or rvn suggest-type --all")
+	}
+	if reference != "" && all {
+		return commandexec.Failure("INVALID_INPUT", "cannot combine an object with --all", nil, "Pass either an object reference or --all")
+	}
+	limit, _ := intArg(req.Args, "limit")
+	if limit < 0 {
+		return commandexec.Failure("INVALID_INPUT", "--limit must be >= 0", nil, fmt.Sprintf("Use --limit 0 for the default of %d", readsvc.DefaultTypeSuggestionLimit))
+	}
+
+	rt, failure := newReadRuntime(req.VaultPath, readsvc.RuntimeOptions{OpenDB: true})
+	if failure.Error != nil {
+		return failure
+	}
+	defer rt.Close()
+
+	freshness, freshnessFailure := checkIndexFreshness(rt, req.Args)
+	if freshnessFailure != nil {
+		return *freshnessFailure
+	}
+
+	objectID := ""
+	if reference != "" {
+		resolved, err := readsvc.ResolveReference(reference, rt, false)
+		if err != nil {
+			return mapResolveFailure(err, reference)
+		}
+		objectID = resolved.ObjectID
+	}
+
+	result, err := readsvc.SuggestTypes(rt, readsvc.SuggestTypesRequest{ObjectID: objectID, Limit: limit})
+	if err != nil {
+		var notUntyped *readsvc.NotUntypedError
+		if errors.As(err, &notUntyped) {
+			return commandexec.Failure("INVALID_INPUT", notUntyped.Error(), nil, fmt.Sprintf("Use 'rvn reclassify %s <type>' to change an existing type", notUntyped.ObjectID))
+		}
+		var notFound *readsvc.RefNotFoundError
+		if errors.As(err, &notFound) {
+			return mapResolveFailure(err, reference)
+		}
+		return commandexec.Failure("DATABASE_ERROR", err.Error(), nil, "Run 'rvn reindex' to rebuild the database")
+	}
+
+	pages := make([]map[string]interface{}, len(result.Pages))
+	for i, page := range result.Pages {
+		suggestions := make([]map[string]interface{}, len(page.Suggestions))
+		for j, suggestion := range page.Suggestions {
+			suggestions[j] = map[string]interface{}{
+				"type":       suggestion.Type,
+				"confidence": suggestion.Confidence,
+				"signals":    suggestion.Signals,
+				"reasons":    suggestion.Reasons,
+			}
+		}
+		pages[i] = map[string]interface{}{
+			"object_id":   page.ObjectID,
+			"file_path":   page.FilePath,
+			"suggestions": suggestions,
+		}
+	}
+
+	return commandexec.Success(map[string]interface{}{
+		"pages": pages,
+	}, &commandexec.Meta{Count: len(pages), QueryTimeMs: time.Since(start).Milliseconds(), Freshness: freshness})
+}
@@ -0,0 +1,221 @@
+package commandimpl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/gitsvc"
+	"github.com/aidanlsb/raven/internal/history"
+	"github.com/aidanlsb/raven/internal/readsvc"
+	"github.com/aidanlsb/raven/internal/reindexsvc"
+	"github.com/aidanlsb/raven/internal/syncsvc"
+)
+
+// HandleSyncExternal executes the canonical `sync external` command.
+func HandleSyncExternal(ctx context.Context, req commandexec.Request) commandexec.Result {
+	name := strings.TrimSpace(stringArg(req.Args, "name"))
+	if name == "" {
+		return commandexec.Failure("MISSING_ARGUMENT", "requires a sync name", nil, "Usage: rvn sync external <name>")
+	}
+
+	rt, failure := newReadRuntime(req.VaultPath, readsvc.RuntimeOptions{OpenDB: true})
+	if failure.Error != nil {
+		return failure
+	}
+	if _, err := readsvc.SmartReindex(rt); err != nil {
+		rt.Close()
+		return commandexec.Failure("DATABASE_ERROR", "failed to refresh index: "+err.Error(), nil, "Run 'rvn reindex' to rebuild the database")
+	}
+	vaultCfg := applyUnlockArg(req, rt.VaultCfg)
+
+	result, err := syncsvc.Run(syncsvc.RunRequest{
+		VaultPath:   rt.VaultPath,
+		VaultConfig: vaultCfg,
+		Schema:      rt.Schema,
+		DB:          rt.DB,
+		Name:        name,
+		DryRun:      boolArg(req.Args, "dry-run"),
+		Prefer:      strings.TrimSpace(stringArg(req.Args, "prefer")),
+		Context:     ctx,
+	})
+	// Close before reindexing changed files: an encrypted index holds the
+	// index lock while open.
+	rt.Close()
+	if err != nil {
+		return mapSyncFailure(err)
+	}
+
+	data := map[string]interface{}{
+		"name":      result.Name,
+		"adapter":   result.Adapter,
+		"type":      result.Type,
+		"dry_run":   result.DryRun,
+		"records":   syncRecordMaps(result.Records),
+		"created":   result.Count(syncsvc.ActionCreated),
+		"pulled":    result.Count(syncsvc.ActionPulled) + result.Count(syncsvc.ActionUpdated),
+		"pushed":    result.Count(syncsvc.ActionPushed) + result.Count(syncsvc.ActionUpdated),
+		"conflicts": result.Count(syncsvc.ActionConflict),
+		"failed":    result.Count(syncsvc.ActionFailed),
+	}
+	warnings := autoReindexWarnings(req.VaultPath, vaultCfg, result.ChangedFiles...)
+	return commandexec.SuccessWithWarnings(data, warnings, &commandexec.Meta{Count: len(result.Records)})
+}
+
+func syncRecordMaps(records []syncsvc.RecordResult) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(records))
+	for i, record := range records {
+		item := map[string]interface{}{
+			"external_id": record.ExternalID,
+			"action":      record.Action,
+		}
+		if record.ObjectID != "" {
+			item["object_id"] = record.ObjectID
+		}
+		if len(record.Pulled) > 0 {
+			item["pulled"] = syncFieldChangeMaps(record.Pulled)
+		}
+		if len(record.Pushed) > 0 {
+			item["pushed"] = syncFieldChangeMaps(record.Pushed)
+		}
+		if len(record.Conflicts) > 0 {
+			item["conflicts"] = syncFieldChangeMaps(record.Conflicts)
+		}
+		if record.Error != "" {
+			item["error"] = record.Error
+		}
+		out[i] = item
+	}
+	return out
+}
+
+func syncFieldChangeMaps(changes []syncsvc.FieldChange) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(changes))
+	for i, change := range changes {
+		out[i] = map[string]interface{}{
+			"field":  change.Field,
+			"local":  change.Local,
+			"remote": change.Remote,
+		}
+	}
+	return out
+}
+
+func mapSyncFailure(err error) commandexec.Result {
+	svcErr, ok := syncsvc.AsError(err)
+	if !ok {
+		return commandexec.Failure("INTERNAL_ERROR", err.Error(), nil, "")
+	}
+	message := svcErr.Message
+	if svcErr.Err != nil {
+		message += ": " + svcErr.Err.Error()
+	}
+	return commandexec.Failure(svcErr.Code, message, nil, svcErr.Suggestion)
+}
+
+// HandleSyncGit executes the canonical `sync git` command.
+func HandleSyncGit(ctx context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", fmt.Sprintf("failed to load raven.yaml: %v", err), nil, "Fix raven.yaml and try again")
+	}
+
+	result, err := gitsvc.Sync(gitsvc.SyncRequest{
+		VaultPath:   vaultPath,
+		VaultConfig: vaultCfg,
+		Message:     stringArg(req.Args, "message"),
+		NoPull:      boolArg(req.Args, "no-pull"),
+		NoPush:      boolArg(req.Args, "no-push"),
+		DryRun:      boolArg(req.Args, "dry-run"),
+		Context:     ctx,
+	})
+	if err != nil {
+		return mapGitFailure(err)
+	}
+
+	changes := make([]map[string]interface{}, len(result.Changes))
+	for i, change := range result.Changes {
+		changes[i] = map[string]interface{}{"path": change.Path, "status": change.Status}
+		if change.ObjectID != "" {
+			changes[i]["object_id"] = change.ObjectID
+		}
+	}
+	pulled := result.PulledFiles
+	if pulled == nil {
+		pulled = []string{}
+	}
+	data := map[string]interface{}{
+		"branch":       result.Branch,
+		"remote":       result.Remote,
+		"changes":      changes,
+		"message":      result.Message,
+		"commit":       result.Commit,
+		"pulled_files": pulled,
+		"pushed":       result.Pushed,
+		"dry_run":      result.DryRun,
+	}
+
+	// A merge rewrites files behind the index's back; bring it up to date
+	// the same way undo does.
+	var warnings []commandexec.Warning
+	if len(result.PulledFiles) > 0 && vaultCfg.IsAutoReindexEnabled() {
+		changed := make([]string, len(result.PulledFiles))
+		for i, file := range result.PulledFiles {
+			changed[i] = filepath.FromSlash(file)
+		}
+		if _, err := reindexsvc.Run(reindexsvc.RunRequest{VaultPath: vaultPath, Context: ctx, Changed: changed}); err != nil {
+			warnings = append(warnings, commandexec.Warning{
+				Code:    codes.WarnIndexUpdateFailed,
+				Message: fmt.Sprintf("pulled changes were merged but the index was not updated: %v (run 'rvn reindex')", err),
+			})
+		} else {
+			data["reindexed"] = true
+		}
+	}
+	return commandexec.SuccessWithWarnings(data, warnings, &commandexec.Meta{Count: len(changes)})
+}
+
+// gitAutoCommitWarnings commits the files an applied --confirm run changed
+// when git.auto_commit is enabled, so each run is its own commit. Failures
+// never undo the run; they are reported as warnings.
+func gitAutoCommitWarnings(req commandexec.Request, entry *history.Entry) []commandexec.Warning {
+	if !req.Confirm || entry == nil || len(entry.Files) == 0 {
+		return nil
+	}
+	vaultCfg, err := config.LoadVaultConfig(req.VaultPath)
+	if err != nil || !vaultCfg.IsGitAutoCommitEnabled() {
+		return nil
+	}
+
+	files := make([]string, len(entry.Files))
+	for i, file := range entry.Files {
+		files[i] = file.Path
+	}
+	if _, err := gitsvc.CommitFiles(req.VaultPath, vaultCfg, files, "rvn "+entry.Summary); err != nil {
+		return []commandexec.Warning{{
+			Code:    codes.WarnGitCommitFailed,
+			Message: fmt.Sprintf("changes were applied but not committed (git.auto_commit): %v", err),
+		}}
+	}
+	return nil
+}
+
+func mapGitFailure(err error) commandexec.Result {
+	svcErr, ok := gitsvc.AsError(err)
+	if !ok {
+		return commandexec.Failure("INTERNAL_ERROR", err.Error(), nil, "")
+	}
+	message := svcErr.Message
+	if svcErr.Err != nil && svcErr.Code == gitsvc.CodeGitFailed {
+		message += ": " + svcErr.Err.Error()
+	}
+	return commandexec.Failure(svcErr.Code, message, nil, svcErr.Suggestion)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/aidanlsb/raven/internal/commandexec"
 	"github.com/aidanlsb/raven/internal/config"
 	"github.com/aidanlsb/raven/internal/configsvc"
+	"github.com/aidanlsb/raven/internal/dates"
 	"github.com/aidanlsb/raven/internal/datesvc"
 	"github.com/aidanlsb/raven/internal/initsvc"
 	"github.com/aidanlsb/raven/internal/maintsvc"
@@ -107,12 +108,49 @@ func HandleDaily(_ context.Context, req commandexec.Request) commandexec.Result
 		return mapDateServiceError(err)
 	}
 
-	return commandexec.Success(map[string]interface{}{
+	data := map[string]interface{}{
 		"file":    result.RelativePath,
 		"date":    result.Date,
 		"created": result.Created,
 		"opened":  false,
-	}, nil)
+	}
+	warnings, failure := runDailyAutomation(vaultPath, result, data)
+	if failure != nil {
+		return *failure
+	}
+	return commandexec.SuccessWithWarnings(data, warnings, nil)
+}
+
+// HandlePeriodic executes the canonical `weekly`, `monthly`, and `quarterly`
+// commands.
+func HandlePeriodic(kind dates.PeriodKind) commandexec.Handler {
+	return func(_ context.Context, req commandexec.Request) commandexec.Result {
+		vaultPath := strings.TrimSpace(req.VaultPath)
+		if vaultPath == "" {
+			return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+		}
+
+		result, err := datesvc.EnsurePeriodic(datesvc.EnsurePeriodicRequest{
+			VaultPath: vaultPath,
+			Kind:      kind,
+			PeriodArg: stringArg(req.Args, "period"),
+			Template:  stringArg(req.Args, "template"),
+		})
+		if err != nil {
+			return mapDateServiceError(err)
+		}
+
+		return commandexec.Success(map[string]interface{}{
+			"file":    result.RelativePath,
+			"period":  string(result.Period.Kind),
+			"key":     result.Period.Key(),
+			"title":   result.Period.Title(),
+			"start":   result.Period.Start.Format(dates.DateLayout),
+			"end":     result.Period.End.Format(dates.DateLayout),
+			"created": result.Created,
+			"opened":  false,
+		}, nil)
+	}
 }
 
 // HandleDate executes the canonical `date` command.
@@ -142,6 +180,14 @@ func HandleDate(_ context.Context, req commandexec.Request) commandexec.Result {
 	if result.DailyNote != nil {
 		data["daily_note"] = result.DailyNote
 	}
+	if result.Period != nil {
+		data = map[string]interface{}{
+			"date":      result.Date,
+			"period":    result.Period,
+			"items":     result.Items,
+			"backlinks": result.Backlinks,
+		}
+	}
 
 	return commandexec.Success(data, &commandexec.Meta{Count: len(result.Items)})
 }
@@ -0,0 +1,245 @@
+package commandimpl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/objectsvc"
+	"github.com/aidanlsb/raven/internal/readsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/tasksvc"
+	"github.com/aidanlsb/raven/internal/traitsvc"
+)
+
+// HandleTaskList executes the canonical `task list` command.
+func HandleTaskList(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+	}
+	tasksCfg := vaultCfg.GetTasksConfig()
+	if failure := requireTaskTrait(sch, tasksCfg); failure != nil {
+		return *failure
+	}
+
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return commandexec.Failure("DATABASE_ERROR", "failed to open database", nil, "Run 'rvn reindex' to rebuild the database")
+	}
+	defer db.Close()
+	db.SetDailyDirectory(vaultCfg.GetDailyDirectory())
+	compatible, err := db.SchemaCompatible()
+	if err != nil {
+		return commandexec.Failure(codes.ErrDatabase, "failed to read index schema version", nil, "Run 'rvn reindex --full' to rebuild the index")
+	}
+	if !compatible {
+		return commandexec.Failure(codes.ErrDatabaseVersion, "index schema is stale or incompatible", nil, "Run 'rvn reindex --full' to rebuild the index")
+	}
+
+	rt := &readsvc.Runtime{VaultPath: vaultPath, VaultCfg: vaultCfg, Schema: sch, DB: db}
+	freshness, failure := checkIndexFreshness(rt, req.Args)
+	if failure != nil {
+		return *failure
+	}
+
+	groups, err := tasksvc.List(tasksvc.ListRequest{
+		DB:            db,
+		Config:        tasksCfg,
+		IncludeClosed: boolArg(req.Args, "all"),
+		Today:         time.Now(),
+	})
+	if err != nil {
+		return mapTaskServiceError(err)
+	}
+
+	total := 0
+	items := make([]map[string]interface{}, 0, len(groups))
+	for _, group := range groups {
+		total += len(group.Tasks)
+		items = append(items, taskGroupItem(group))
+	}
+	return commandexec.Success(map[string]interface{}{
+		"trait":  tasksCfg.Trait,
+		"groups": items,
+	}, &commandexec.Meta{Count: total, Freshness: freshness})
+}
+
+func taskGroupItem(group tasksvc.Group) map[string]interface{} {
+	return map[string]interface{}{
+		"key":   group.Key,
+		"tasks": taskItems(group.Tasks),
+	}
+}
+
+func taskItems(taskList []tasksvc.Task) []map[string]interface{} {
+	tasks := make([]map[string]interface{}, 0, len(taskList))
+	for _, task := range taskList {
+		item := map[string]interface{}{
+			"id":        task.ID,
+			"file_path": task.FilePath,
+			"line":      task.Line,
+			"object_id": task.ObjectID,
+			"content":   task.Content,
+		}
+		if task.Status != "" {
+			item["status"] = task.Status
+		}
+		if task.Due != "" {
+			item["due"] = task.Due
+		}
+		if task.Recurrence != "" {
+			item["recurrence"] = task.Recurrence
+		}
+		if task.Closed {
+			item["closed"] = true
+		}
+		tasks = append(tasks, item)
+	}
+	return tasks
+}
+
+// HandleTaskDone executes the canonical `task done` command.
+func HandleTaskDone(_ context.Context, req commandexec.Request) commandexec.Result {
+	return runTaskEdit(req, "Usage: rvn task done <file:line|trait_id>", func(edit tasksvc.EditRequest) (*tasksvc.EditResult, error) {
+		return tasksvc.Done(edit, boolArg(req.Args, "cancel"))
+	})
+}
+
+// HandleTaskSnooze executes the canonical `task snooze` command.
+func HandleTaskSnooze(_ context.Context, req commandexec.Request) commandexec.Result {
+	by := strings.TrimSpace(stringArg(req.Args, "by"))
+	if by == "" {
+		by = "1d"
+	}
+	return runTaskEdit(req, "Usage: rvn task snooze <file:line|trait_id> [--by 3d]", func(edit tasksvc.EditRequest) (*tasksvc.EditResult, error) {
+		return tasksvc.Snooze(edit, by)
+	})
+}
+
+// HandleTaskSchedule executes the canonical `task schedule` command.
+func HandleTaskSchedule(_ context.Context, req commandexec.Request) commandexec.Result {
+	when := strings.TrimSpace(stringArg(req.Args, "when"))
+	if when == "" {
+		return commandexec.Failure("MISSING_ARGUMENT", "requires anchor and when arguments", nil, "Usage: rvn task schedule <file:line|trait_id> <when>")
+	}
+	return runTaskEdit(req, "Usage: rvn task schedule <file:line|trait_id> <when>", func(edit tasksvc.EditRequest) (*tasksvc.EditResult, error) {
+		return tasksvc.Schedule(edit, when)
+	})
+}
+
+// runTaskEdit loads the vault for a task edit, applies it, and reports the
+// rewritten line.
+func runTaskEdit(req commandexec.Request, usage string, apply func(tasksvc.EditRequest) (*tasksvc.EditResult, error)) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	rawAnchor := strings.TrimSpace(stringArg(req.Args, "anchor"))
+	if rawAnchor == "" {
+		return commandexec.Failure("MISSING_ARGUMENT", "requires an anchor argument", nil, usage)
+	}
+	anchor, err := traitsvc.ParseAnchor(rawAnchor)
+	if err != nil {
+		return mapTraitMutationError(err)
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
+
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+	}
+	tasksCfg := vaultCfg.GetTasksConfig()
+	if failure := requireTaskTrait(sch, tasksCfg); failure != nil {
+		return *failure
+	}
+
+	if err := objectsvc.ValidateContentMutationFilePath(vaultPath, vaultCfg, filepath.Join(vaultPath, anchor.FilePath)); err != nil {
+		return mapContentMutationError(err)
+	}
+
+	result, err := apply(tasksvc.EditRequest{
+		VaultPath:    vaultPath,
+		Schema:       sch,
+		Config:       tasksCfg,
+		Anchor:       anchor,
+		ParseOptions: buildParseOptions(vaultCfg),
+		Preview:      req.Preview,
+		Today:        time.Now(),
+	})
+	if err != nil {
+		if _, ok := tasksvc.AsError(err); ok {
+			return mapTaskServiceError(err)
+		}
+		return mapTraitMutationError(err)
+	}
+
+	data := map[string]interface{}{
+		"action":     result.Action,
+		"file":       result.FilePath,
+		"line":       result.Line,
+		"trait_type": result.TraitType,
+		"old_value":  result.OldValue,
+		"new_value":  result.NewValue,
+		"before":     result.Before,
+		"after":      result.After,
+		"changed":    result.Changed,
+	}
+	if req.Preview {
+		data["preview"] = true
+		return commandexec.Success(data, nil)
+	}
+	if result.ChangedFilePath == "" {
+		return commandexec.Success(data, nil)
+	}
+
+	stampAttribution(vaultPath, vaultCfg, false, result.ChangedFilePath)
+	return commandexec.SuccessWithWarnings(data, autoReindexWarnings(vaultPath, vaultCfg, result.ChangedFilePath), nil)
+}
+
+// requireTaskTrait fails when the configured task and due traits are not
+// defined in the schema.
+func requireTaskTrait(sch *schema.Schema, cfg *config.TasksConfig) *commandexec.Result {
+	for _, name := range []string{cfg.Trait, cfg.DueTrait} {
+		if _, ok := sch.Traits[name]; ok {
+			continue
+		}
+		failure := commandexec.Failure(
+			"INVALID_INPUT",
+			fmt.Sprintf("trait '%s' is not defined in schema.yaml", name),
+			nil,
+			"Define the trait in schema.yaml, or point tasks.trait / tasks.due_trait in raven.yaml at existing traits",
+		)
+		return &failure
+	}
+	return nil
+}
+
+func mapTaskServiceError(err error) commandexec.Result {
+	svcErr, ok := tasksvc.AsError(err)
+	if !ok {
+		return commandexec.Failure("INTERNAL_ERROR", err.Error(), nil, "")
+	}
+	return commandexec.Failure(svcErr.Code, svcErr.Message, nil, svcErr.Suggestion)
+}
@@ -0,0 +1,133 @@
+package commandimpl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/objectsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// HandleToggle executes the canonical `toggle` command.
+func HandleToggle(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
+
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+	}
+
+	field := strings.TrimSpace(stringArg(req.Args, "field"))
+	if field == "" {
+		return commandexec.Failure("MISSING_ARGUMENT", "requires field", nil, "Usage: rvn toggle <object-id> <field>")
+	}
+
+	objectIDs := commandIDsArg(req.Args, "object_ids")
+	if boolArg(req.Args, "stdin") || len(objectIDs) > 0 {
+		if len(objectIDs) == 0 {
+			message, suggestion := setMissingBulkObjectIDs(req.Caller)
+			return commandexec.Failure("MISSING_ARGUMENT", message, nil, suggestion)
+		}
+		return withBulkFailureReport(runToggleBulk(vaultPath, vaultCfg, sch, objectIDs, field, req.Confirm), req, "object_ids")
+	}
+
+	reference := strings.TrimSpace(stringArg(req.Args, "object_id"))
+	if reference == "" {
+		return commandexec.Failure("MISSING_ARGUMENT", "requires object-id", nil, "Usage: rvn toggle <object-id> <field>")
+	}
+
+	serviceResult, err := objectsvc.ToggleByReference(objectsvc.ToggleByReferenceRequest{
+		VaultPath:    vaultPath,
+		VaultConfig:  vaultCfg,
+		Schema:       sch,
+		Reference:    reference,
+		Field:        field,
+		ParseOptions: buildParseOptions(vaultCfg),
+		Preview:      req.Preview,
+	})
+	if err != nil {
+		return mapContentMutationError(err)
+	}
+
+	data := map[string]interface{}{
+		"file":           serviceResult.RelativePath,
+		"object_id":      serviceResult.ObjectID,
+		"type":           serviceResult.ObjectType,
+		"field":          field,
+		"value":          serviceResult.ResolvedUpdates[field],
+		"updated_fields": serviceResult.ResolvedUpdates,
+	}
+	if len(serviceResult.PreviousFields) > 0 {
+		data["previous_fields"] = serviceResult.PreviousFields
+	}
+
+	warnings := warningMessagesToCommandWarnings(serviceResult.WarningMessages, codes.WarnUnknownField)
+	if req.Preview {
+		data["preview"] = true
+		return commandexec.SuccessWithWarnings(data, warnings, nil)
+	}
+
+	stampAttribution(vaultPath, vaultCfg, false, serviceResult.FilePath)
+	warnings = appendCommandWarnings(warnings, autoReindexWarnings(vaultPath, vaultCfg, serviceResult.FilePath))
+	return commandexec.SuccessWithWarnings(data, warnings, nil)
+}
+
+func runToggleBulk(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema, ids []string, field string, confirm bool) commandexec.Result {
+	request := objectsvc.ToggleBulkRequest{
+		VaultPath:    vaultPath,
+		VaultConfig:  vaultCfg,
+		Schema:       sch,
+		ObjectIDs:    ids,
+		Field:        field,
+		ParseOptions: buildParseOptions(vaultCfg),
+	}
+
+	if !confirm {
+		preview, err := objectsvc.PreviewToggleBulk(request)
+		if err != nil {
+			return mapContentMutationError(err)
+		}
+		return commandexec.Success(map[string]interface{}{
+			"preview":  true,
+			"action":   preview.Action,
+			"items":    canonicalSetPreviewItems(preview.Items),
+			"skipped":  canonicalSetResults(preview.Skipped),
+			"total":    preview.Total,
+			"warnings": nil,
+			"field":    field,
+		}, &commandexec.Meta{Count: len(preview.Items)})
+	}
+
+	var warnings []commandexec.Warning
+	stamper := newAttributionStamper(vaultPath, vaultCfg)
+	summary, err := objectsvc.ApplyToggleBulk(request, func(filePath string) {
+		stamper.stamp(false, filePath)
+		warnings = appendCommandWarnings(warnings, autoReindexWarnings(vaultPath, vaultCfg, filePath))
+	})
+	if err != nil {
+		return mapContentMutationError(err)
+	}
+
+	return commandexec.SuccessWithWarnings(map[string]interface{}{
+		"ok":       summary.Errors == 0,
+		"action":   summary.Action,
+		"results":  canonicalSetResults(summary.Results),
+		"total":    summary.Total,
+		"skipped":  summary.Skipped,
+		"errors":   summary.Errors,
+		"modified": summary.Modified,
+		"field":    field,
+	}, warnings, &commandexec.Meta{Count: summary.Total - summary.Skipped - summary.Errors})
+}
@@ -0,0 +1,83 @@
+package commandimpl
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/objectsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/traitsvc"
+)
+
+// HandleTraitSet executes the canonical `trait set` command.
+func HandleTraitSet(_ context.Context, req commandexec.Request) commandexec.Result {
+	vaultPath := strings.TrimSpace(req.VaultPath)
+	if vaultPath == "" {
+		return commandexec.Failure("INVALID_INPUT", "vault path is required", nil, "Resolve a vault before invoking the command")
+	}
+
+	rawAnchor := strings.TrimSpace(stringArg(req.Args, "anchor"))
+	value := strings.TrimSpace(stringArg(req.Args, "value"))
+	if rawAnchor == "" || value == "" {
+		return commandexec.Failure("MISSING_ARGUMENT", "requires anchor and value arguments", nil, "Usage: rvn trait set <file:line|trait_id> <value>")
+	}
+	anchor, err := traitsvc.ParseAnchor(rawAnchor)
+	if err != nil {
+		return mapTraitMutationError(err)
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
+	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
+
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		return commandexec.Failure("SCHEMA_INVALID", "failed to load schema", nil, "Fix schema.yaml and try again")
+	}
+
+	if err := objectsvc.ValidateContentMutationFilePath(vaultPath, vaultCfg, filepath.Join(vaultPath, anchor.FilePath)); err != nil {
+		return mapContentMutationError(err)
+	}
+
+	result, err := traitsvc.SetAtAnchor(traitsvc.SetRequest{
+		VaultPath:    vaultPath,
+		Schema:       sch,
+		Anchor:       anchor,
+		TraitType:    stringArg(req.Args, "trait"),
+		Value:        value,
+		ParseOptions: buildParseOptions(vaultCfg),
+		Preview:      req.Preview,
+	})
+	if err != nil {
+		return mapTraitMutationError(err)
+	}
+
+	data := map[string]interface{}{
+		"file":       result.FilePath,
+		"line":       result.Line,
+		"trait_type": result.TraitType,
+		"old_value":  result.OldValue,
+		"new_value":  result.NewValue,
+		"before":     result.Before,
+		"after":      result.After,
+		"changed":    result.Changed,
+	}
+	if anchor.TraitID != "" {
+		data["trait_id"] = anchor.TraitID
+	}
+	if req.Preview {
+		data["preview"] = true
+		return commandexec.Success(data, nil)
+	}
+	if result.ChangedFilePath == "" {
+		return commandexec.Success(data, nil)
+	}
+
+	stampAttribution(vaultPath, vaultCfg, false, result.ChangedFilePath)
+	return commandexec.SuccessWithWarnings(data, autoReindexWarnings(vaultPath, vaultCfg, result.ChangedFilePath), nil)
+}
@@ -63,6 +63,7 @@ func HandleUpdate(_ context.Context, req commandexec.Request) commandexec.Result
 	if err != nil {
 		return commandexec.Failure("CONFIG_INVALID", "failed to load raven.yaml", nil, "Fix raven.yaml and try again")
 	}
+	vaultCfg = applyUnlockArg(req, vaultCfg)
 
 	db, err := index.Open(vaultPath)
 	if err != nil {
@@ -113,9 +114,10 @@ func HandleUpdate(_ context.Context, req commandexec.Request) commandexec.Result
 		return mapTraitMutationError(err)
 	}
 
+	stampAttribution(vaultPath, vaultCfg, false, summary.ChangedFilePaths...)
 	warnings := autoReindexWarnings(vaultPath, vaultCfg, summary.ChangedFilePaths...)
 
-	return commandexec.SuccessWithWarnings(map[string]interface{}{
+	result := commandexec.SuccessWithWarnings(map[string]interface{}{
 		"action":   summary.Action,
 		"results":  summary.Results,
 		"total":    summary.Total,
@@ -123,6 +125,10 @@ func HandleUpdate(_ context.Context, req commandexec.Request) commandexec.Result
 		"skipped":  summary.Skipped,
 		"errors":   summary.Errors,
 	}, warnings, &commandexec.Meta{Count: summary.Modified})
+	if !stdinMode {
+		return result
+	}
+	return withBulkFailureReport(result, req, "trait_ids")
 }
 
 func mapTraitMutationError(err error) commandexec.Result {
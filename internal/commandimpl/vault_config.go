@@ -2,6 +2,7 @@ package commandimpl
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/aidanlsb/raven/internal/commandexec"
@@ -40,6 +41,9 @@ func HandleVaultConfigShow(_ context.Context, req commandexec.Request) commandex
 		"protected_prefixes_count": len(result.ProtectedPrefixes),
 		"exclude":                  result.Exclude,
 		"exclude_count":            len(result.Exclude),
+		"sparse_paths":             result.SparsePaths,
+		"absent_sparse_paths":      result.AbsentSparsePaths,
+		"locked_files":             result.LockedFiles,
 	}, &commandexec.Meta{Count: len(result.ProtectedPrefixes) + len(result.Exclude)})
 }
 
@@ -292,6 +296,35 @@ func HandleVaultConfigDeletionUnset(_ context.Context, req commandexec.Request)
 	return commandexec.Success(data, nil)
 }
 
+// HandleConfigEdit executes the canonical `config_edit` command.
+func HandleConfigEdit(_ context.Context, req commandexec.Request) commandexec.Result {
+	if _, ok := req.Args["content"]; !ok {
+		return commandexec.Failure("MISSING_ARGUMENT", "content is required", nil, "Run 'rvn config edit' in a terminal to open raven.yaml in your editor, or pass --content")
+	}
+	result, err := vaultconfigsvc.WriteRaw(vaultconfigsvc.WriteRawRequest{
+		VaultPath: req.VaultPath,
+		Content:   stringArg(req.Args, "content"),
+	})
+	if err != nil {
+		return mapVaultConfigFailure(err)
+	}
+	if !result.Valid {
+		return commandexec.Failure(
+			"CONFIG_INVALID",
+			fmt.Sprintf("raven.yaml has %d problem(s); nothing was written", len(result.Issues)),
+			map[string]interface{}{
+				"file":   "raven.yaml",
+				"issues": result.Issues,
+			},
+			"Fix the reported lines and try again",
+		)
+	}
+	return commandexec.Success(map[string]interface{}{
+		"config_path": result.ConfigPath,
+		"changed":     result.Changed,
+	}, nil)
+}
+
 func mapVaultConfigFailure(err error) commandexec.Result {
 	svcErr, ok := vaultconfigsvc.AsError(err)
 	if !ok {
@@ -0,0 +1,58 @@
+package commandimpl
+
+import (
+	"context"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/vaultconfigsvc"
+)
+
+// HandleVaultEncrypt executes the canonical `vault encrypt` command.
+func HandleVaultEncrypt(_ context.Context, req commandexec.Request) commandexec.Result {
+	result, err := vaultconfigsvc.EncryptDirectory(vaultconfigsvc.EncryptDirectoryRequest{
+		VaultPath: req.VaultPath,
+		Directory: stringArg(req.Args, "directory"),
+		Confirm:   req.Confirm,
+	})
+	if err != nil {
+		return mapVaultConfigFailure(err)
+	}
+	return vaultEncryptionResult(result)
+}
+
+// HandleVaultDecrypt executes the canonical `vault decrypt` command.
+func HandleVaultDecrypt(_ context.Context, req commandexec.Request) commandexec.Result {
+	result, err := vaultconfigsvc.DecryptDirectory(vaultconfigsvc.DecryptDirectoryRequest{
+		VaultPath: req.VaultPath,
+		Directory: stringArg(req.Args, "directory"),
+		Confirm:   req.Confirm,
+	})
+	if err != nil {
+		return mapVaultConfigFailure(err)
+	}
+	return vaultEncryptionResult(result)
+}
+
+func vaultEncryptionResult(result *vaultconfigsvc.EncryptionChangeResult) commandexec.Result {
+	files := result.Files
+	if files == nil {
+		files = []string{}
+	}
+	data := map[string]interface{}{
+		"config_path":           result.ConfigPath,
+		"directory":             result.Directory,
+		"files":                 files,
+		"preview":               !result.Applied,
+		"encrypted_directories": result.EncryptedDirectories,
+		"index_encrypted":       result.IndexEncrypted,
+	}
+	var warnings []commandexec.Warning
+	if result.Applied && !result.IndexEncrypted && len(result.EncryptedDirectories) > 0 {
+		warnings = append(warnings, commandexec.Warning{
+			Code:    codes.WarnIndexNotEncrypted,
+			Message: "the index stores decrypted text from encrypted directories; set index.encrypt: true in raven.yaml to encrypt it too",
+		})
+	}
+	return commandexec.SuccessWithWarnings(data, warnings, nil)
+}
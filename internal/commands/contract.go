@@ -380,6 +380,12 @@ func exampleValueForParam(spec ParameterSpec) interface{} {
 	}
 }
 
+// CanonicalArgumentName resolves key, which may use dashes, underscores, or an
+// alias, to the parameter name it refers to in spec.
+func CanonicalArgumentName(spec map[string]ParameterSpec, key string) (string, bool) {
+	return canonicalSpecKey(spec, key)
+}
+
 func canonicalSpecKey(spec map[string]ParameterSpec, key string) (string, bool) {
 	if _, ok := spec[key]; ok {
 		return key, true
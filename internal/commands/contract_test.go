@@ -80,7 +80,7 @@ func TestValidateArgumentsStrictNormalizesUpdateBulkAliases(t *testing.T) {
 func TestBuildCommandContractBulkPreviewModes(t *testing.T) {
 	t.Parallel()
 
-	for _, commandID := range []string{"add", "delete", "move", "set", "update"} {
+	for _, commandID := range []string{"add", "delete", "move", "set", "toggle", "update"} {
 		t.Run(commandID, func(t *testing.T) {
 			t.Parallel()
 
@@ -169,11 +169,13 @@ func TestBuildCommandContractPreviewDefaultForApplyCommands(t *testing.T) {
 		"check",
 		"check create-missing",
 		"check_fix",
+		"import_csv",
 		"query",
 		"schema_rename_field",
 		"schema_rename_type",
 		"skill_remove",
 		"skill_sync",
+		"vault_encrypt",
 	} {
 		t.Run(commandID, func(t *testing.T) {
 			t.Parallel()
@@ -75,6 +75,7 @@ func ShouldPreviewByDefault(commandID string, args map[string]interface{}) bool
 var nonInvokableCommandIDs = map[string]struct{}{
 	"path":        {},
 	"serve":       {},
+	"watch":       {},
 	"mcp_install": {},
 	"mcp_remove":  {},
 	"mcp_status":  {},
@@ -91,24 +92,41 @@ var nonInvokableCommandIDs = map[string]struct{}{
 // apply immediately and only preview when the caller passes `dry-run`; these
 // are either absent (PreviewModeNone) or use PreviewModeBulkPreviewDefault,
 // which previews only when a bulk input (stdin/object_ids/trait_ids) is
-// present. High-blast-radius operations (bulk writes, query --apply, schema
-// rename, check fixes, skill sync/remove) preview by default and require
+// present. High-blast-radius operations (bulk writes, --apply on query,
+// orphans, and deadlinks, object and schema renames, schema inference and
+// pack imports, field type changes that convert existing values, check fixes,
+// doctor repairs, skill sync/remove, undo) preview by default and require
 // `confirm` to apply.
 var previewModeByCommandID = map[string]PreviewMode{
 	"add":    PreviewModeBulkPreviewDefault,
 	"delete": PreviewModeBulkPreviewDefault,
 	"move":   PreviewModeBulkPreviewDefault,
 	"set":    PreviewModeBulkPreviewDefault,
+	"toggle": PreviewModeBulkPreviewDefault,
 	"update": PreviewModeBulkPreviewDefault,
 
 	"check":                PreviewModePreviewDefault,
 	"check create-missing": PreviewModePreviewDefault,
 	"check_fix":            PreviewModePreviewDefault,
+	"deadlinks":            PreviewModePreviewDefault,
+	"doctor":               PreviewModePreviewDefault,
+	"fmt":                  PreviewModePreviewDefault,
+	"import_csv":           PreviewModePreviewDefault,
+	"orphans":              PreviewModePreviewDefault,
 	"query":                PreviewModePreviewDefault,
+	"redirects_prune":      PreviewModePreviewDefault,
+	"rename":               PreviewModePreviewDefault,
+	"resume":               PreviewModePreviewDefault,
+	"schema_import":        PreviewModePreviewDefault,
+	"schema_infer":         PreviewModePreviewDefault,
 	"schema_rename_field":  PreviewModePreviewDefault,
 	"schema_rename_type":   PreviewModePreviewDefault,
+	"schema_update_field":  PreviewModePreviewDefault,
 	"skill_remove":         PreviewModePreviewDefault,
 	"skill_sync":           PreviewModePreviewDefault,
+	"undo":                 PreviewModePreviewDefault,
+	"vault_decrypt":        PreviewModePreviewDefault,
+	"vault_encrypt":        PreviewModePreviewDefault,
 }
 
 func hasBulkPreviewInput(args map[string]interface{}) bool {
@@ -29,7 +29,7 @@ type ArgMeta struct {
 	Required    bool     // Is this argument required for canonical/MCP invocation?
 	CLIOptional bool     `json:"-"` // Can interactive CLI omit this and prompt/pick instead?
 	Completions []string // Static completions (if any)
-	DynamicComp string   // Dynamic completion type: "types", "traits", "files"
+	DynamicComp string   // Dynamic completion type: "types", "traits", "queries", "query"
 }
 
 // FlagMeta defines a command flag.
@@ -176,6 +176,12 @@ Accepted values:
 - Full object ID (e.g., project/raven#bugs-fixes)
 - Markdown heading text (e.g., "### Bugs / Fixes")
 
+Placement within a section or file:
+- --position end (default) appends at the end of the target section
+- --position after-heading inserts directly below the section heading
+- --line N appends the text to the end of line N, e.g. to put a trait on an
+  existing task line (not with --heading, --position, or --stdin)
+
 Permissive writes: if appended text contains a [[ref]] whose target does not exist
 yet, the write still succeeds. The response adds data.missing_refs,
 data.missing_ref_items, and a REF_NOT_FOUND warning per missing target.`,
@@ -185,8 +191,12 @@ data.missing_ref_items, and a REF_NOT_FOUND warning per missing target.`,
 		Flags: []FlagMeta{
 			{Name: "to", Description: "Target file path or daily note date (today/tomorrow/yesterday/YYYY-MM-DD)", Type: FlagTypeString, Examples: []string{"projects/website.md", "inbox.md", "tomorrow"}},
 			{Name: "heading", Description: "Target existing heading within destination (slug, object#heading ID, or markdown heading text)", Type: FlagTypeString, Examples: []string{"bugs-fixes", "project/raven#bugs-fixes", "### Bugs / Fixes"}},
+			{Name: "position", Description: "Where to insert within the target section: end (default) or after-heading", Type: FlagTypeString, Examples: []string{"end", "after-heading"}},
+			{Name: "line", Description: "Append the text to the end of this existing line (1-indexed)", Type: FlagTypeInt},
 			{Name: "stdin", Description: "Read object IDs from stdin for bulk operations", Type: FlagTypeBool},
 			{Name: "confirm", Description: "Apply bulk changes (without this flag, shows preview only)", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
+			{Name: "merge", Description: "Three-way merge the planned edit into files changed since the preview", Type: FlagTypeBool},
 		},
 		Examples: []string{
 			"rvn add \"Quick thought\" --json",
@@ -195,11 +205,14 @@ data.missing_ref_items, and a REF_NOT_FOUND warning per missing target.`,
 			"rvn add \"Plan\" --to tomorrow --json",
 			"rvn add \"Bug report\" --to project/raven --heading bugs-fixes --json",
 			"rvn add \"Bug report\" --to project/raven --heading \"### Bugs / Fixes\" --json",
+			"rvn add \"@priority(high) Triage first\" --to project/raven --heading bugs-fixes --position after-heading --json",
+			"rvn add \"@due(2026-01-15)\" --to project/raven --line 12 --json",
 		},
 		UseCases: []string{
 			"Quick capture to daily note",
 			"Add tasks to existing project files",
 			"Append notes to existing documents",
+			"Attach a trait to an existing task line",
 		},
 	},
 	"upsert": {
@@ -242,6 +255,7 @@ data.missing_ref_items, and a REF_NOT_FOUND warning per missing target.`,
 			{Name: "content", Description: "Replace body content (full-body idempotent mode)", Type: FlagTypeString},
 			{Name: "content-file", Description: "Read replacement body content from a file, or '-' for stdin (mutually exclusive with --content)", Type: FlagTypeString, Examples: []string{"/tmp/brief.md", "-"}},
 			{Name: "path", Description: "Explicit target path (overrides title-derived path)", Type: FlagTypeString, Examples: []string{"brief/daily-2026-02-14", "note/raven-friction"}},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
 		},
 		Examples: []string{
 			"rvn upsert brief \"Daily Brief 2026-02-14\" --content \"# Daily Brief\" --json",
@@ -290,15 +304,23 @@ post-init setup suggestions instead of mutating global config implicitly.`,
 	},
 	"serve": {
 		Name:        "serve",
-		Description: "Run Raven as an MCP server",
+		Description: "Run Raven as an MCP server or HTTP API",
 		VaultScope:  VaultScopeNone,
-		LongDesc:    "Run Raven as an MCP server over stdio.",
+		LongDesc:    "Run Raven as an MCP server over stdio.\n\nWith --debug-addr, the server also listens on that address for pprof profiles (/debug/pprof/) and Prometheus metrics (/metrics): per-command latency and errors, in-flight tool calls, and Go runtime gauges.\n\nWith --http, Raven serves a read-only JSON API for the resolved vault instead: GET /api/query (q, limit, cursor, ...), POST /api/query with a JSON body of arguments, GET /api/read/<reference>, GET /api/backlinks/<target>, and GET /api/stats. Responses use the same envelope as --json. Every request must send 'Authorization: Bearer <token>'; the token comes from RAVEN_HTTP_TOKEN or is generated and printed at startup.",
+		Flags: []FlagMeta{
+			{Name: "debug-addr", Description: "Serve pprof and Prometheus metrics on this address (e.g. 127.0.0.1:6060)", Type: FlagTypeString},
+			{Name: "http", Description: "Serve a read-only JSON API on this address instead of MCP (e.g. 127.0.0.1:8080)", Type: FlagTypeString},
+			{Name: "http-allow-origin", Description: "Allow browser requests from this origin (CORS) when serving --http", Type: FlagTypeString},
+		},
 		Examples: []string{
 			"rvn serve",
 			"rvn serve --vault personal",
+			"rvn serve --debug-addr 127.0.0.1:6060",
+			"RAVEN_HTTP_TOKEN=secret rvn serve --http 127.0.0.1:8080",
 		},
 		UseCases: []string{
 			"Launch Raven MCP server for local clients",
+			"Back a web UI or mobile shortcut with the vault's query and read API",
 		},
 	},
 	"mcp_install": {
@@ -477,6 +499,10 @@ Template files contain Markdown body content only. Raven writes object
 frontmatter separately when applying templates, so template files must not
 include YAML frontmatter blocks.
 
+Template bodies are rendered at creation time with variables such as {{title}},
+{{date}}, and {{field.name}}, plus text/template conditionals and loops, date
+offsets ({{today +7d}}), and {{range query "..."}} over query results.
+
 Use this command group for template file lifecycle operations:
 - create/update template files
 - interactively author template files in your editor
@@ -583,6 +609,7 @@ IMPORTANT:
 			{Name: "stdin", Description: "Read object IDs from stdin for bulk operations", Type: FlagTypeBool},
 			{Name: "confirm", Description: "Apply bulk delete (without this flag, bulk shows preview only)", Type: FlagTypeBool},
 			{Name: "dry-run", Description: "Preview a single-object delete without applying it", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
 		},
 		Examples: []string{
 			"rvn delete people/freya --json",
@@ -638,6 +665,7 @@ IMPORTANT: Bulk operations return preview by default. Changes are NOT applied un
 			{Name: "stdin", Description: "Read object IDs from stdin for bulk operations", Type: FlagTypeBool},
 			{Name: "confirm", Description: "Apply bulk move (without this flag, bulk shows preview only)", Type: FlagTypeBool},
 			{Name: "dry-run", Description: "Preview a single-object move without applying it", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
 		},
 		Examples: []string{
 			"rvn move people/loki people/loki-archived --json",
@@ -653,6 +681,41 @@ IMPORTANT: Bulk operations return preview by default. Changes are NOT applied un
 			"Archive old content without breaking references",
 		},
 	},
+	"rename": {
+		Name:        "rename",
+		Description: "Rename an object and rewrite every reference to it",
+		LongDesc: `Give an object a new ID and rewrite references to it across the vault.
+
+This command:
+1. Moves the object's file to match the new ID
+2. Updates [[wikilinks]], Markdown links, and frontmatter ref/ref[] values that point to it
+3. Updates saved queries and query snapshot targets in raven.yaml
+4. Updates [[wikilinks]] in template files
+
+References written through one of the object's aliases still resolve after the
+rename and are left unchanged. Short references like [[freya]] are rewritten to
+the new short name when it is unambiguous, otherwise to the full new ID.
+
+IMPORTANT: Returns preview by default. Changes are NOT applied unless confirm=true.
+Use 'rvn move' for assets or to move files into another directory without
+caring about saved queries and templates.`,
+		Args: []ArgMeta{
+			{Name: "id", Description: "Object to rename (ID, short name, or alias)", Required: true},
+			{Name: "new_id", Description: "New object ID (e.g., people/freya-smith)", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "confirm", Description: "Apply the rename (default: preview only)", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn rename people/freya people/freya-smith --json",
+			"rvn rename people/freya people/freya-smith --confirm --json",
+		},
+		UseCases: []string{
+			"Rename an object without leaving broken links, queries, or templates",
+			"Preview every file a rename would touch before applying it",
+		},
+	},
 	"reclassify": {
 		Name:        "reclassify",
 		Description: "Change an object's type",
@@ -685,6 +748,7 @@ References are updated when the file moves (controlled by --update-refs).`,
 			{Name: "no-move", Description: "Skip moving file to new type's default_path", Type: FlagTypeBool},
 			{Name: "update-refs", Description: "Update references when file moves (default: true)", Type: FlagTypeBool, Default: "true"},
 			{Name: "force", Description: "Skip confirmation prompts (dropped fields, etc.)", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
 		},
 		Examples: []string{
 			"rvn reclassify inbox/note book --json",
@@ -700,6 +764,169 @@ References are updated when the file moves (controlled by --update-refs).`,
 			"Convert between custom types with field mapping",
 		},
 	},
+	"archive": {
+		Name:        "archive",
+		Description: "Move an object to its type's archive state",
+		LongDesc: `Set an object's lifecycle state to the archive state declared for its
+type in schema.yaml (default: archived). The transition is checked against the
+lifecycle like any other 'rvn set'.
+
+If the lifecycle names an archive_directory, the file is also moved there and
+references are updated (controlled by --update-refs). Use --no-move to archive
+in place.`,
+		Args: []ArgMeta{
+			{Name: "object_id", Description: "Object reference (short name, path, or ID)", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "no-move", Description: "Skip moving the file to the archive directory", Type: FlagTypeBool},
+			{Name: "update-refs", Description: "Update references when the file moves (default: true)", Type: FlagTypeBool, Default: "true"},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn archive projects/website --json",
+			"rvn archive projects/website --no-move --json",
+		},
+		UseCases: []string{
+			"Retire finished work without deleting it",
+			"Move archived objects out of active directories",
+		},
+	},
+	"list": {
+		Name:        "list",
+		Description: "List objects of a type, sorted by a field",
+		LongDesc: `Lists indexed objects without writing a query. 'rvn list person' is
+equivalent to 'rvn query type:person', with sorting and pagination applied
+in sorted order.
+
+--sort takes a field (e.g. .name or .due); objects without a value sort last.
+The default is .display_name, the type's name_field falling back to the file
+name. .id is also accepted. Without a type, objects of every type are listed.
+
+Output formats match 'rvn query': a human table with the type's fields as
+columns, --json, --ids, and --pipe.`,
+		Args: []ArgMeta{
+			{Name: "type", Description: "Type to list (default: all types)", DynamicComp: "types"},
+		},
+		Flags: []FlagMeta{
+			{Name: "sort", Description: "Field to sort by, e.g. .name (default: .display_name)", Type: FlagTypeString},
+			{Name: "desc", Description: "Sort in descending order", Type: FlagTypeBool},
+			{Name: "limit", Description: "Maximum number of objects to return (0 means no limit)", Type: FlagTypeInt},
+			{Name: "offset", Description: "Zero-based offset into the sorted objects", Type: FlagTypeInt},
+			{Name: "ids", Description: "Output only object IDs, one per line (for piping)", Type: FlagTypeBool},
+			{Name: "refresh", Description: "Refresh stale files before listing", Type: FlagTypeBool},
+			{Name: "require-fresh", Description: "Reindex first if the index is stale; fail if it cannot be brought up to date", Type: FlagTypeBool},
+			{Name: "pipe", Description: "Force pipe-friendly output for shell pipelines (jq, head, sort)", Type: FlagTypeBool},
+			{Name: "no-pipe", Description: "Force human-readable output format", Type: FlagTypeBool},
+			{Name: "full", Description: "Show full field values in human output instead of truncating them", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn list person --json",
+			"rvn list project --sort .due --limit 10 --json",
+			"rvn list book --sort .rating --desc",
+			"rvn list person --ids",
+		},
+	},
+	"suggest-type": {
+		Name:        "suggest-type",
+		Description: "Suggest a type for untyped pages",
+		LongDesc: `Proposes schema types for untyped pages, ranked by a confidence score
+between 0 and 1. The score combines four signals:
+
+- directory: the page sits under a type's default_path, or among typed
+  files of that type
+- fields: the page's frontmatter keys are fields of the type
+- traits: the page uses traits common in objects of the type
+- content: the page's text resembles existing objects of the type
+
+Each suggestion lists the reasons behind it. In an interactive terminal,
+press Enter to accept the top suggestion (or 1-N to pick another), which runs
+'rvn reclassify'. Suggestions are read-only otherwise.`,
+		Args: []ArgMeta{
+			{Name: "object", Description: "Untyped page to suggest a type for", Required: false},
+		},
+		Flags: []FlagMeta{
+			{Name: "all", Description: "Suggest types for every untyped page", Type: FlagTypeBool},
+			{Name: "limit", Description: "Maximum suggestions per page (default: 3)", Type: FlagTypeInt},
+			{Name: "refresh", Description: "Refresh stale files before suggesting", Type: FlagTypeBool},
+			{Name: "require-fresh", Description: "Reindex first if the index is stale; fail if it cannot be brought up to date", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn suggest-type inbox/q1-planning --json",
+			"rvn suggest-type --all --json",
+			"rvn suggest-type --all --limit 1",
+		},
+	},
+	"focus_add": {
+		Name:        "focus add",
+		Description: "Add objects to the focus working set",
+		LongDesc: `Add one or more objects to the focus working set.
+
+Focus is a small, persistent list of the objects you are working on right now,
+stored in the index so it survives between commands. Refer to it as @focus:
+
+  rvn query 'type:project @focus .status==active'
+  rvn set @focus status=done          # bulk set, previews unless --confirm
+  rvn toggle @focus done --confirm
+
+Use --stdin to add object IDs piped from a query. Clear the set with
+'rvn focus clear' when you move on.`,
+		Args: []ArgMeta{
+			{Name: "object_id", Description: "Object to focus (e.g., projects/website)", Required: false},
+		},
+		Flags: []FlagMeta{
+			{Name: "stdin", Description: "Read object IDs from stdin", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn focus add projects/website projects/raven --json",
+			"rvn query 'type:project .status==active' --ids | rvn focus add --stdin",
+		},
+		UseCases: []string{
+			"Collect the objects for a multi-step task once and refer to them as @focus",
+			"Run queries and bulk updates against a hand-picked set of objects",
+		},
+	},
+	"focus_list": {
+		Name:        "focus list",
+		Description: "List the focus working set",
+		LongDesc: `List the objects in the focus working set in the order they were added.
+
+Objects that were deleted or moved since they were focused are marked missing;
+re-add them under their new ID or clear the set.`,
+		Examples: []string{
+			"rvn focus list --json",
+		},
+	},
+	"focus_clear": {
+		Name:        "focus clear",
+		Description: "Empty the focus working set",
+		Examples: []string{
+			"rvn focus clear --json",
+		},
+	},
+	"inbox_list": {
+		Name:        "inbox list",
+		Description: "List objects waiting in the inbox",
+		LongDesc: `Lists objects that still need processing: everything under the inbox
+directory (default: inbox/), ordered by file path. With --untyped, untyped
+pages anywhere in the vault are included too. Daily notes are never listed.
+
+Each item carries a reason: "inbox_dir" for files in the inbox directory and
+"untyped" for untyped pages elsewhere. Use 'rvn inbox triage' to work through
+the items one at a time in an interactive terminal.`,
+		Flags: []FlagMeta{
+			{Name: "dir", Description: "Vault-relative inbox directory (default: inbox/)", Type: FlagTypeString},
+			{Name: "untyped", Description: "Also include untyped pages outside the inbox directory", Type: FlagTypeBool},
+			{Name: "limit", Description: "Maximum number of items to return (0 means no limit)", Type: FlagTypeInt},
+			{Name: "offset", Description: "Zero-based offset into the inbox items", Type: FlagTypeInt},
+			{Name: "refresh", Description: "Refresh stale files before listing", Type: FlagTypeBool},
+			{Name: "require-fresh", Description: "Reindex first if the index is stale; fail if it cannot be brought up to date", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn inbox list --json",
+			"rvn inbox list --untyped --json",
+			"rvn inbox list --dir capture/ --limit 20 --json",
+		},
+	},
 	"query": {
 		Name:        "query",
 		Use:         "query <query_string|saved-query> [inputs...]",
@@ -731,8 +958,15 @@ Common predicates:
 - refs([[target]]) — References target (refs([[people/freya]]))
 - refs(type:...) — References items matching subquery (refs(type:project .status==active))
 - refd(type:...) — Asset is referenced by matching source items (asset refd(type:note))
+- refs*(...), refd*(...) — Transitive refs/refd with an optional hop limit (refs*([[projects/raven]], depth<=3))
 - .value==X — Trait value equals X (.value==today, .value==high)
 - content("text") — Full-text search within content (content("meeting notes"))
+- content(title:"text"), content(heading:"text"), content(code:"text"), content(field:"text") — Search only titles, headings, fenced code, or frontmatter values
+- modified(within:7d), created(before:2026-01-01) — File timestamp windows (within:/before:/after:)
+- expired() — Past the type's review_after window (schema.yaml)
+- lifecycle(active, draft) — Objects in any of these lifecycle states (schema.yaml)
+- has_attachment(), attachment(type:pdf, min_size:5MB) — Links to vault assets (type: extension or image/audio/video/text)
+- @focus — In the focus working set (rvn focus add); traits and sections match when their file's object is focused
 
 Common agent patterns:
 - Real open todos: trait:todo .value==todo
@@ -744,15 +978,32 @@ Common agent patterns:
 
 Special date values for trait and type:date .date comparisons:
 - today, tomorrow, yesterday
+- date(+7d), date(-2w), date(+1m): today shifted by d/w/m/y offsets
+- startOfWeek(), endOfWeek(), startOfMonth(), endOfMonth(), startOfYear(), endOfYear(), each with an optional offset such as startOfMonth(-1m)
+- within(3d): the range from today through today+3d (== tests membership)
 
 Saved query inputs must be declared in the saved query definition when using {{args.<name>}}.
 You can then pass inputs by position (in args order) or as key=value pairs.
 
 Use --ids to output just IDs (one per line) for piping to other commands.
-Use --limit/--offset for paginated result windows.
+Use --limit/--offset for paginated result windows. A limited page reports
+has_more and, when more rows remain, a next_cursor to pass as --cursor for the
+next page. Over MCP, queries without a limit return the first 50 rows with the
+total and fetch instructions; pass limit 0 for every row.
 Use --count-only to return only the total match count without items.
+Use --select to return only the listed columns, e.g. --select '.name, .status, backlinks'.
+Each row keeps num and id; .field reads an object field, bare names read row
+keys (type, file_path, line, value, ...), backlinks counts incoming references,
+and issues lists Jira and GitHub issues mentioned in the file (see issue_refs).
+Use --explain-matches to annotate each row with which top-level predicates
+matched and the values behind them (field values, refs with line numbers,
+matching traits). OR predicates list each alternative.
 Use --browse to open an interactive Raven picker with filtering and editor
 handoff for the selected result.
+Use --vaults work,personal to run the query in several vaults from
+config.toml and merge the rows, each tagged with a vault column. Saved queries
+resolve in each vault; --limit and --offset page over the merged rows, and
+--apply, --ids, and --cursor are not available across vaults.
 Use --apply to run a bulk operation directly on query results.
 Section and asset queries return stable IDs but do not support --apply.
 
@@ -765,20 +1016,28 @@ For trait queries (trait:...):
 - Supported command: update <new_value> (updates trait values in-place)
 - Example: trait:todo .value==todo --apply "update done" marks todos as done`,
 		Args: []ArgMeta{
-			{Name: "query_string", Description: "Query string (e.g., 'type:project .status==active', 'asset .extension==pdf', or saved query name) optionally followed by saved-query inputs.", Required: true},
+			{Name: "query_string", Description: "Query string (e.g., 'type:project .status==active', 'asset .extension==pdf', or saved query name) optionally followed by saved-query inputs.", Required: true, DynamicComp: "query"},
 		},
 		Flags: []FlagMeta{
 			{Name: "refresh", Description: "Refresh stale files before query (auto-reindex changed files)", Type: FlagTypeBool},
+			{Name: "require-fresh", Description: "Reindex first if the index is stale; fail if it cannot be brought up to date", Type: FlagTypeBool},
 			{Name: "ids", Description: "Output only object/trait IDs, one per line (for piping)", Type: FlagTypeBool},
 			{Name: "limit", Description: "Maximum number of query results to return (0 means no limit)", Type: FlagTypeInt},
 			{Name: "offset", Description: "Zero-based offset for query results", Type: FlagTypeInt},
+			{Name: "cursor", Description: "Continue from the next_cursor returned by a previous page of the same query", Type: FlagTypeString},
 			{Name: "count-only", Description: "Return only the total count of matches (no items or IDs)", Type: FlagTypeBool},
 			{Name: "apply", Description: "Apply bulk operation to results (e.g., 'set status=done', 'delete', 'add @reviewed', 'update done')", Type: FlagTypeStringSlice},
 			{Name: "confirm", Description: "Apply bulk changes (without this flag, shows preview only)", Type: FlagTypeBool},
 			{Name: "pipe", Description: "Force pipe-friendly output for shell pipelines (jq, head, sort)", Type: FlagTypeBool},
 			{Name: "no-pipe", Description: "Force human-readable output format", Type: FlagTypeBool},
 			{Name: "browse", Description: "Interactively browse results in Raven's picker and open the selected result in the configured editor", Type: FlagTypeBool},
+			{Name: "full", Description: "Show full field values and content in human output instead of truncating them", Type: FlagTypeBool},
+			{Name: "select", Description: "Comma-separated output columns: .field for object fields, row keys (type, file_path, line, value, ...), backlinks, or issues", Type: FlagTypeString, Examples: []string{".name, .status, backlinks"}},
+			{Name: "explain-matches", Description: "Annotate each row with the predicates that matched and the matched values", Type: FlagTypeBool},
 			{Name: "inputs", Description: "Saved query inputs as key=value pairs", Type: FlagTypePosKeyValue, Examples: []string{`{"project": "projects/raven"}`}},
+			{Name: "unlock", Description: "Allow --apply to modify files listed in locked_files", Type: FlagTypeBool},
+			{Name: "merge", Description: "Three-way merge the planned --apply edit into files changed since the preview", Type: FlagTypeBool},
+			{Name: "vaults", Description: "Comma-separated configured vault names to query together; each row gets a vault column", Type: FlagTypeString, Examples: []string{"work,personal"}},
 		},
 		Examples: []string{
 			"rvn query 'type:project .status==active' --json",
@@ -789,8 +1048,12 @@ For trait queries (trait:...):
 			"rvn query 'asset refd(type:project .status==active)' --json",
 			"rvn query 'trait:due .value<today' --ids",
 			"rvn query 'trait:todo .value==todo' --limit 50 --offset 100 --json",
+			"rvn query 'trait:todo .value==todo' --limit 50 --cursor <next_cursor> --json",
 			"rvn query 'trait:todo .value==todo' --count-only --json",
+			"rvn query 'type:project .status==active' --select '.name, .status, backlinks' --json",
+			"rvn query 'type:project refs([[people/freya]]) | has(trait:due)' --explain-matches --json",
 			"rvn query 'type:issue .status==open' --browse",
+			"rvn query --vaults work,personal 'trait:due .value==today' --json",
 			"rvn query 'type:project .status==active' --apply 'set status=done' --confirm --json",
 			"rvn query 'trait:todo .value==todo' --apply 'update done' --confirm --json",
 			"rvn query tasks --json",
@@ -842,6 +1105,9 @@ For trait queries (trait:...):
 			{Name: "pipe", Description: "Save pipe-friendly output as a default option for this query", Type: FlagTypeBool},
 			{Name: "no-pipe", Description: "Save human-readable output as a default option for this query", Type: FlagTypeBool},
 			{Name: "browse", Description: "Save interactive browse/open as a default option for this query", Type: FlagTypeBool},
+			{Name: "full", Description: "Save --full (untruncated human output) as a default option for this query", Type: FlagTypeBool},
+			{Name: "snapshot-target", Description: "Note that 'rvn query snapshot' renders this query's results into", Type: FlagTypeString},
+			{Name: "snapshot-schedule", Description: "Snapshot refresh interval for 'rvn query snapshot --due' (hourly, daily, weekly, or e.g. 6h)", Type: FlagTypeString},
 		},
 		Examples: []string{
 			"rvn query saved set tasks 'trait:due' --json",
@@ -849,6 +1115,7 @@ For trait queries (trait:...):
 			"rvn query saved set active-projects 'type:project .status==active' --json",
 			"rvn query saved set project-todos 'trait:todo refs([[{{args.project}}]])' --arg project --json",
 			"rvn query saved set open-issues 'type:issue .status==open' --browse --limit 100 --json",
+			"rvn query saved set open-questions 'trait:question' --snapshot-target pages/open-questions --snapshot-schedule daily --json",
 		},
 	},
 	"query_saved_remove": {
@@ -861,6 +1128,82 @@ For trait queries (trait:...):
 			"rvn query saved remove overdue --json",
 		},
 	},
+	"query_snapshot": {
+		Name:        "query snapshot",
+		Description: "Render saved query results into their snapshot notes",
+		LongDesc: `Runs saved queries that declare a snapshot target and writes their
+results into the target note between snapshot markers:
+
+  <!-- rvn:snapshot NAME updated=TIMESTAMP -->
+  ...
+  <!-- /rvn:snapshot NAME -->
+
+Content outside the markers is left untouched. When the note has no markers
+yet, the block is appended at the end of the note.
+
+With no name, every saved query with a snapshot is rendered. Use --due (for
+cron jobs or watchers) to render only snapshots whose schedule has elapsed
+since the timestamp recorded in their markers.`,
+		Args: []ArgMeta{
+			{Name: "name", Description: "Saved query to snapshot (default: all saved queries with a snapshot)", DynamicComp: "queries"},
+		},
+		Flags: []FlagMeta{
+			{Name: "due", Description: "Only render snapshots whose schedule has elapsed", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow writing snapshot notes listed in locked_files", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn query snapshot open-questions --json",
+			"rvn query snapshot --due --json",
+		},
+	},
+	"query_diff": {
+		Name:        "query diff",
+		Description: "Compare the results of two queries",
+		LongDesc: `Runs two queries and reports the results only the first query returns,
+only the second returns, and both return, matched by object, trait, section,
+or asset ID. Either side can be a saved query name, followed by its inputs.
+
+Use it to check that a schema or data migration changed exactly the results
+you intended, for example by comparing a query on the old field with the
+same query on the new one. Both queries must be the same kind.`,
+		Args: []ArgMeta{
+			{Name: "query_a", Description: "First query string or saved query name", Required: true, DynamicComp: "query"},
+			{Name: "query_b", Description: "Second query string or saved query name", Required: true, DynamicComp: "query"},
+		},
+		Flags: []FlagMeta{
+			{Name: "refresh", Description: "Refresh stale files before running the queries", Type: FlagTypeBool},
+			{Name: "require-fresh", Description: "Reindex first if the index is stale; fail if it cannot be brought up to date", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn query diff 'type:project .status==active' 'type:project .state==active' --json",
+			"rvn query diff overdue 'trait:due .value<today' --json",
+		},
+		UseCases: []string{
+			"Verify that a field rename or bulk update moved exactly the intended objects",
+			"See how two saved queries overlap",
+		},
+	},
+	"dashboard": {
+		Name:        "dashboard",
+		Description: "Show the saved queries configured as the vault dashboard",
+		LongDesc: `Runs the saved queries listed under dashboard in raven.yaml and shows them
+together, one section per query with its match count.
+
+Each section names a saved query and may set a title, positional args for
+queries that declare them, and a row limit (default 10). A section whose query
+fails shows its error without hiding the others.`,
+		Flags: []FlagMeta{
+			{Name: "refresh", Description: "Refresh stale files before running the queries", Type: FlagTypeBool},
+			{Name: "require-fresh", Description: "Reindex first if the index is stale; fail if it cannot be brought up to date", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn dashboard",
+			"rvn dashboard --json",
+		},
+		UseCases: []string{
+			"Review open tasks, active projects, and other standing queries at a glance",
+		},
+	},
 	"backlinks": {
 		Name:        "backlinks",
 		Use:         "backlinks [target]",
@@ -872,19 +1215,26 @@ over indexed object, section, and asset references.
 When an interactive backlinks target is ambiguous, Raven prompts you to choose the target.
 Use --browse to browse incoming references interactively and open the selected reference location.
 Use --stdin to read targets from stdin and return grouped results for each target.
-Non-interactive use requires either a target or --stdin input.`,
+Non-interactive use requires either a target or --stdin input.
+
+Each backlink includes the text of the referencing line (context), the
+file-level object containing it (object_id), and the innermost heading section
+around it (section_id, section_title). Use --group-by type to also group
+backlinks by the type of the referencing object.`,
 		Args: []ArgMeta{
 			{Name: "target", Description: "Target object ID or asset path (e.g., people/freya, assets/pdfs/file.pdf)", Required: false, CLIOptional: true},
 		},
 		Flags: []FlagMeta{
 			{Name: "browse", Description: "Interactively browse backlinks in Raven's picker and open the selected reference", Type: FlagTypeBool},
 			{Name: "stdin", Description: "Read targets from stdin and return grouped backlinks", Type: FlagTypeBool},
+			{Name: "group-by", Description: "Group backlinks by source: type", Type: FlagTypeString, Examples: []string{"type"}},
 		},
 		BulkStdinArgName: "targets",
 		Examples: []string{
 			"rvn backlinks people/freya --json",
 			"rvn backlinks people/freya --browse",
 			"rvn backlinks assets/pdfs/paper.pdf --json",
+			"rvn backlinks people/freya --group-by type",
 			"rvn query 'type:project .status==active' --ids | rvn backlinks --stdin --json",
 		},
 		UseCases: []string{
@@ -894,6 +1244,7 @@ Non-interactive use requires either a target or --stdin input.`,
 			"Browse incoming references and open one at the reference line",
 			"Traverse backlinks for multiple targets with grouped output",
 			"Audit incoming links before moving or deleting content",
+			"See how an object is mentioned, grouped by the kind of object mentioning it",
 		},
 	},
 	"outlinks": {
@@ -930,15 +1281,131 @@ Non-interactive use requires either a source or --stdin input.`,
 			"Follow references from a file to related objects and assets",
 		},
 	},
+	"graph_export": {
+		Name:        "graph export",
+		Description: "Export the object and reference graph as DOT, GraphML, or JSON",
+		LongDesc: `Export the indexed graph for tools like Graphviz, Gephi, or yEd.
+
+Nodes are objects with their type and fields. Edges are references between
+objects: body wikilinks and ref-typed fields (named on the edge). References
+from a section count as references from its file's object; with --sections,
+sections become nodes instead, linked to their parent heading or file.
+Unresolved references are left out.
+
+Formats:
+  json     {nodes, edges} (default)
+  dot      Graphviz digraph; render with 'dot -Tsvg'
+  graphml  GraphML with type, file, fields, and edge count attributes
+
+Use --root to export the neighborhood of one object, following references in
+either direction up to --depth hops (0 means the whole connected component).
+--type keeps only objects of the given types; the root is always kept.
+With --output the export is written to a file (relative paths are resolved
+against the vault root) instead of printed.`,
+		Flags: []FlagMeta{
+			{Name: "format", Description: "Output format: json (default), dot, or graphml", Type: FlagTypeString, Examples: []string{"json", "dot", "graphml"}},
+			{Name: "type", Description: "Only include objects of these types (repeatable)", Type: FlagTypeStringSlice, Examples: []string{"project", "person"}},
+			{Name: "root", Description: "Only export objects connected to this object", Type: FlagTypeString, Examples: []string{"projects/website"}},
+			{Name: "depth", Description: "Maximum hops from --root (0 = unlimited)", Type: FlagTypeInt, Default: "0"},
+			{Name: "sections", Description: "Include sections as nodes with parent edges", Type: FlagTypeBool},
+			{Name: "output", Description: "File to write the export to", Type: FlagTypeString, Examples: []string{"exports/vault.graphml"}},
+		},
+		Examples: []string{
+			"rvn graph export --format dot | dot -Tsvg > vault.svg",
+			"rvn graph export --format graphml --output exports/vault.graphml",
+			"rvn graph export --root projects/website --depth 2 --json",
+			"rvn graph export --type project --type person --format dot",
+		},
+		UseCases: []string{
+			"Visualize how projects, people, and notes connect",
+			"Load the vault graph into Gephi or yEd for analysis",
+			"Inspect the neighborhood of one object",
+		},
+	},
+	"orphans": {
+		Name:        "orphans",
+		Description: "List objects with no backlinks and no outgoing references",
+		LongDesc: `List objects that nothing links to and that link to nothing: no
+references from other files and no wikilinks or ref fields of their own.
+References from or to a section or embedded object count for the object
+whose ID it starts with, and links within one file are ignored.
+
+Filter with --type (repeatable) and --dir. --apply runs a bulk operation on
+the listed objects, the same operations as 'rvn query --apply' (set, delete,
+add, move); it previews until --confirm.`,
+		Flags: []FlagMeta{
+			{Name: "type", Description: "Only include objects of these types (repeatable)", Type: FlagTypeStringSlice, Examples: []string{"note", "person"}},
+			{Name: "dir", Description: "Only include files under this vault-relative directory", Type: FlagTypeString, Examples: []string{"notes/", "inbox/"}},
+			{Name: "apply", Description: "Apply a bulk operation to the orphans (e.g., 'move archive/', 'set status=stale', 'delete')", Type: FlagTypeStringSlice},
+			{Name: "confirm", Description: "Apply changes (without this flag, --apply shows a preview)", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn orphans --json",
+			"rvn orphans --type note --dir notes/ --json",
+			"rvn orphans --type note --apply 'move archive/' --confirm",
+		},
+		UseCases: []string{
+			"Find notes that never got connected to anything",
+			"Archive or tag disconnected objects in bulk",
+		},
+	},
+	"deadlinks": {
+		Name:        "deadlinks",
+		Description: "List references whose target does not exist",
+		LongDesc: `List wikilinks and ref fields whose target does not resolve to an
+object, with the object and line they appear on.
+
+Filter with --type (the type of the linking object, repeatable) and --dir
+(the linking file's directory). --apply fixes the listed links in bulk and
+previews until --confirm:
+  stub    Create an object for each missing target, typed by the schema
+          type whose default_path contains it (otherwise a page)
+  unlink  Replace each body link with its display text, or its target when
+          it has none
+
+Links in frontmatter are skipped by unlink, since the field would still
+hold the value; change them with 'rvn set' or 'rvn unset'.`,
+		Flags: []FlagMeta{
+			{Name: "type", Description: "Only include links from objects of these types (repeatable)", Type: FlagTypeStringSlice, Examples: []string{"project", "meeting"}},
+			{Name: "dir", Description: "Only include links in files under this vault-relative directory", Type: FlagTypeString, Examples: []string{"projects/"}},
+			{Name: "apply", Description: "Fix the listed links: stub or unlink", Type: FlagTypeString, Examples: []string{"stub", "unlink"}},
+			{Name: "confirm", Description: "Apply changes (without this flag, --apply shows a preview)", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn deadlinks --json",
+			"rvn deadlinks --dir projects/ --json",
+			"rvn deadlinks --type meeting --apply stub --confirm",
+			"rvn deadlinks --apply unlink",
+		},
+		UseCases: []string{
+			"Find links to pages that were never written or were deleted",
+			"Create stubs for missing people or projects in bulk",
+			"Remove links that are no longer wanted",
+		},
+	},
 	"date": {
 		Name:        "date",
 		Description: "Date hub - all activity for a date",
+		LongDesc: `Show the daily note, dated traits, objects with a date field on this day,
+and references to the daily note.
+
+date_hub.fields in raven.yaml limits which object date fields appear (type.field);
+date_hub.recurring fields such as person.birthday appear every year on their
+month and day.
+
+A period key (2026-W07, 2026-02, 2026-Q1) or phrase (this-week, last-month,
+next-quarter) shows the whole week, month, or quarter instead: everything dated
+within it, its weekly, monthly, or quarterly note, and references to that note.`,
 		Args: []ArgMeta{
-			{Name: "date", Description: "Date (today, yesterday, YYYY-MM-DD)", Required: false},
+			{Name: "date", Description: "Date or period (today, yesterday, YYYY-MM-DD, 2026-W07, 2026-02, 2026-Q1, this-week)", Required: false},
 		},
 		Examples: []string{
 			"rvn date today --json",
 			"rvn date 2025-02-01 --json",
+			"rvn date 2026-W07 --json",
+			"rvn date this-month --json",
 		},
 	},
 	"read": {
@@ -957,7 +1424,21 @@ In an interactive terminal, bare 'rvn read' launches Raven's picker.
 When an interactive read reference is ambiguous, Raven prompts you to choose the target.
 
 For long files, you can request a specific range with --start-line/--end-line, and/or
-ask for structured line output with --lines for copy-paste-safe anchors.`,
+ask for structured line output with --lines for copy-paste-safe anchors. A range
+reports has_more and, when lines remain, next_start_line for the next page.
+
+Use --sections to get a structured outline instead of content: each heading's
+section ID, level, parent, line range (line_end for the section's own text,
+subtree_line_end including subsections), and the traits inside it. Section IDs
+can be passed to 'rvn edit' and 'rvn read', and the line ranges to
+--start-line/--end-line. Reading a section reference outlines just that section.
+
+Use --render to replace fenced raven-query blocks with their live results. The
+block body is a query string or saved query name, optionally preceded by
+format: (list or table), fields: (columns for a table), and limit: lines.
+Object results render as wikilinks, traits as their content; query_blocks
+reports each block's line, query, result count, and error. The file itself is
+not changed.`,
 		Args: []ArgMeta{
 			{Name: "path", Description: "Reference to read (short ref, partial path, or full path)", Required: true, CLIOptional: true},
 		},
@@ -967,6 +1448,9 @@ ask for structured line output with --lines for copy-paste-safe anchors.`,
 			{Name: "lines", Description: "Include structured lines with line numbers (recommended for agents)", Type: FlagTypeBool},
 			{Name: "start-line", Description: "Start line (1-indexed, inclusive) for raw output", Type: FlagTypeInt},
 			{Name: "end-line", Description: "End line (1-indexed, inclusive) for raw output", Type: FlagTypeInt},
+			{Name: "full", Description: "Show long frontmatter values in full instead of truncating them", Type: FlagTypeBool},
+			{Name: "sections", Description: "Output a structured outline of sections and traits with line ranges instead of content", Type: FlagTypeBool},
+			{Name: "render", Description: "Replace raven-query blocks with live query results", Type: FlagTypeBool},
 		},
 		Examples: []string{
 			"rvn read daily/2025-02-01.md --json",
@@ -974,6 +1458,8 @@ ask for structured line output with --lines for copy-paste-safe anchors.`,
 			"rvn read people/freya --raw --json",
 			"rvn read people/freya --raw --start-line 10 --end-line 40 --json",
 			"rvn read people/freya --raw --lines --json",
+			"rvn read projects/website --sections --json",
+			"rvn read dashboards/projects --render --json",
 		},
 		UseCases: []string{
 			"Read vault file content (use instead of 'cat', 'head', 'tail')",
@@ -981,6 +1467,8 @@ ask for structured line output with --lines for copy-paste-safe anchors.`,
 			"Interactively disambiguate read references in Raven's picker",
 			"Inspect file before editing (prefer --raw for exact string matching)",
 			"Extract copy-paste-safe anchors with --lines or line ranges for long files",
+			"Find the section to target for an edit with --sections instead of parsing markdown",
+			"View a dashboard note with its embedded queries filled in",
 			"Get full content after finding object via query",
 		},
 	},
@@ -1033,6 +1521,34 @@ Use --full to force a complete rebuild of the entire index.`,
 			{Name: "dry-run", Description: "Show what would be reindexed without doing it", Type: FlagTypeBool},
 		},
 	},
+	"watch": {
+		Name:        "watch",
+		Description: "Reindex changed files as they are saved",
+		LongDesc: `Runs until interrupted, reindexing files as they change.
+
+The watcher scans the vault for added, modified, and deleted files, waits for
+rapid saves to settle (--debounce), then runs an incremental reindex of just
+those files. Changes to raven.yaml and schema.yaml are picked up too.
+
+While running, the watcher answers GET /status on the unix socket
+.raven/watch.sock with JSON describing index freshness (pending, fresh,
+last_reindex_at). The MCP server exposes the same status as the
+raven://index/status resource. Only one watcher runs per vault.
+
+With --json, each reindex prints one compact JSON line.`,
+		Examples: []string{
+			"rvn watch",
+			"rvn watch --debounce 1s",
+			"rvn watch --json",
+		},
+		Flags: []FlagMeta{
+			{Name: "debounce", Description: "Wait this long after the last change before reindexing", Type: FlagTypeString, Default: "300ms"},
+		},
+		UseCases: []string{
+			"Keep the index current while editing in another editor",
+			"Let agents and editor integrations skip manual reindexing",
+		},
+	},
 	"check": {
 		Name:        "check",
 		Description: "Validate managed vault files against schema",
@@ -1055,6 +1571,10 @@ Scoping:
 Paths matched by raven.yaml exclude patterns are outside Raven management and
 are not checked.
 
+check in raven.yaml can change or turn off issue levels and enforce file
+naming conventions (naming_convention), optionally from a shared lint profile
+file or URL. The profile in use is returned as profile.
+
 For agents: Use this tool to discover issues, then use the fix_command suggestions to resolve them.
 For missing_reference summaries, preview generated pages with 'rvn check create-missing --json'
 before applying with 'rvn check create-missing --confirm --json'.
@@ -1096,6 +1616,70 @@ Ask the user for clarification when needed (e.g., which type to use for missing
 			"Preview and create deterministic missing referenced pages",
 		},
 	},
+	"health": {
+		Name:        "health",
+		Description: "Score vault health and gate CI on a minimum score",
+		LongDesc: `Runs a full-vault check and reduces it to a 0-100 health score.
+
+Each category deducts points per item, up to a cap:
+- errors: 5 per check error (max 35)
+- warnings: 1 per check warning (max 15)
+- broken_links: 2 per missing reference or missing asset (max 25)
+- stale_index: 1 per file changed since the last reindex (max 10)
+- schema: 3 per schema issue, such as unused types or traits (max 15)
+
+Broken links and the stale index are scored only in their own categories.
+
+With --min-score, the command exits non-zero when the score is below the
+threshold, so it can gate CI on shared vaults. JSON output always includes
+score, min_score, passed, and the per-category breakdown.`,
+		Flags: []FlagMeta{
+			{Name: "min-score", Description: "Fail when the score is below this value (0-100)", Type: FlagTypeInt},
+		},
+		Examples: []string{
+			"rvn health",
+			"rvn health --min-score 90",
+			"rvn health --json",
+		},
+		UseCases: []string{
+			"Track overall vault quality over time",
+			"Fail a CI job when a team vault drops below a quality bar",
+		},
+	},
+	"doctor": {
+		Name:        "doctor",
+		Description: "Diagnose and repair index, schema, permission, and trash problems",
+		LongDesc: `Checks the parts of a vault that 'rvn check' does not cover:
+
+- index_readable, index_integrity, index_version: the index opens, passes
+  SQLite's integrity check, and was built by this version of Raven
+- missing_files: indexed files that no longer exist on disk
+- orphan_rows: index rows left behind for files with no indexed object
+- fts_sync: full-text search rows that do not match objects, sections, and traits
+- schema_types: indexed types and traits that schema.yaml does not define
+- schema_drift: files indexed under an older schema.yaml
+- permissions: unreadable or read-only vault files, and an unwritable .raven/
+- trash: size of the trash directory (warns above 100 MB)
+
+With --fix, previews the repairs; add --confirm to apply them. A corrupt,
+unreadable, or outdated index is deleted and rebuilt from the vault files.
+Otherwise rows for deleted files and orphaned rows are removed, and files with
+out-of-sync search rows or an outdated schema are reindexed. Schema, permission,
+and trash findings are reported with a suggestion but never changed.`,
+		Flags: []FlagMeta{
+			{Name: "fix", Description: "Preview repairs for fixable findings", Type: FlagTypeBool},
+			{Name: "confirm", Description: "Apply the repairs (with --fix)", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn doctor",
+			"rvn doctor --json",
+			"rvn doctor --fix --confirm --json",
+		},
+		UseCases: []string{
+			"Find out why queries or search return stale or missing results",
+			"Repair a corrupted index without deleting it blindly",
+		},
+	},
 	"check_fix": {
 		Name:        "check fix",
 		Description: "Preview or apply safe auto-fixes for check findings",
@@ -1342,7 +1926,15 @@ Use --name-field="-" to remove the name_field setting.`,
 Note: Making a field required will be blocked if any objects lack that field.
 Add the field to all objects first, then make it required.
 Use --description to set optional context for this field.
-Use --description="-" to remove an existing description.`,
+Use --description="-" to remove an existing description.
+
+Changing --type converts existing frontmatter values: strings are parsed as
+dates, numbers, or booleans, single values are wrapped when the field becomes
+a list, and one-item lists are unwrapped when it stops being one. When any
+value needs converting, the change is previewed with the conversions and the
+values that cannot be converted; pass --confirm to apply it. Unconvertible
+values block the change unless --force is also given, which leaves them as
+they are.`,
 		Args: []ArgMeta{
 			{Name: "type_name", Description: "Type containing the field", Required: true},
 			{Name: "field_name", Description: "Field to update", Required: true},
@@ -1354,20 +1946,48 @@ Use --description="-" to remove an existing description.`,
 			{Name: "values", Description: "Update enum values (comma-separated)", Type: FlagTypeString},
 			{Name: "target", Description: "Update target type for ref fields", Type: FlagTypeString},
 			{Name: "description", Description: "Set/update description (use '-' to remove)", Type: FlagTypeString},
+			{Name: "confirm", Description: "Apply a type change that converts existing values (default: preview only)", Type: FlagTypeBool},
+			{Name: "force", Description: "With --confirm, convert what can be converted and leave the rest", Type: FlagTypeBool},
 		},
 		Examples: []string{
 			"rvn schema update field person email --required=true --json",
+			"rvn schema update field project due --type date --confirm --json",
 			"rvn schema update field project status --default=active --json",
 			"rvn schema update field project status --values active,paused,done,archived --json",
 			"rvn schema update field person email --description \"Primary contact email\" --json",
 		},
 	},
+	"drift_report": {
+		Name:        "drift report",
+		Use:         "report",
+		Description: "Report where schema.yaml and vault data have drifted apart",
+		LongDesc: `Compare schema.yaml with the data in the vault and report:
+  - types with no objects, and fields no object of their type sets
+  - enum values (fields and traits) that never occur
+  - traits used in files but not declared in the schema
+  - ref field values that point at a type other than the declared target
+
+Findings are summarized as a cleanup plan ordered by priority: data the
+schema cannot validate or query first, then declarations nothing uses. Each
+step suggests a command; none are run. Field, enum, and ref findings come
+from the index, so run 'rvn reindex' first if it is stale.`,
+		Examples: []string{
+			"rvn drift report",
+			"rvn drift report --json",
+		},
+		UseCases: []string{
+			"Audit a long-lived vault before tidying its schema",
+			"Feed schema health into a dashboard",
+		},
+	},
 	"schema_remove_type": {
 		Name:        "schema remove type",
 		Description: "Remove a type from the schema",
 		LongDesc: `Remove a type definition from schema.yaml.
 
 Existing files of this type will become 'page' type (fallback).
+The result includes an impact report (files, saved queries, templates, and ref
+fields targeting the type); run 'rvn schema impact type <name>' to see it first.
 Use --force to skip confirmation prompt.`,
 		Args: []ArgMeta{
 			{Name: "name", Description: "Name of the type to remove", Required: true},
@@ -1386,6 +2006,7 @@ Use --force to skip confirmation prompt.`,
 		LongDesc: `Remove a trait definition from schema.yaml.
 
 Existing @trait instances will remain in files but no longer be indexed.
+The result includes an impact report; run 'rvn schema impact trait <name>' to see it first.
 Use --force to skip confirmation prompt.`,
 		Args: []ArgMeta{
 			{Name: "name", Description: "Name of the trait to remove", Required: true},
@@ -1403,7 +2024,8 @@ Use --force to skip confirmation prompt.`,
 		LongDesc: `Remove a field from a type definition.
 
 If the field is required, removal will be blocked until you make it optional first.
-Existing field values will remain in files but no longer be validated.`,
+Existing field values will remain in files but no longer be validated.
+The result includes an impact report; run 'rvn schema impact field <type> <field>' to see it first.`,
 		Args: []ArgMeta{
 			{Name: "type_name", Description: "Type containing the field", Required: true},
 			{Name: "field_name", Description: "Field to remove", Required: true},
@@ -1412,6 +2034,167 @@ Existing field values will remain in files but no longer be validated.`,
 			"rvn schema remove field person nickname --json",
 		},
 	},
+	"schema_impact": {
+		Name:        "schema impact",
+		Use:         "impact <type|trait|field> <name> [field]",
+		Description: "Report what a schema removal would affect",
+		LongDesc: `Report the impact of removing a type, trait, or field, or of dropping enum values.
+
+The report is built from the index, schema.yaml, raven.yaml, and template files:
+affected files and object counts, saved queries that mention the item, templates
+bound to or using it, and other schema definitions that depend on it.
+
+Use --values with a field to analyze dropping specific enum values instead of
+removing the whole field. Run this before 'rvn schema remove' or before narrowing
+an enum with 'rvn schema update field --values'.`,
+		Args: []ArgMeta{
+			{Name: "kind", Description: "What would be removed: type, trait, or field", Required: true},
+			{Name: "name", Description: "Type or trait name (the owning type for kind=field)", Required: true},
+			{Name: "field", Description: "Field name (required for kind=field)", Required: false},
+		},
+		Flags: []FlagMeta{
+			{Name: "values", Description: "Enum values to analyze dropping (comma-separated, kind=field only)", Type: FlagTypeString, Examples: []string{"done,blocked"}},
+		},
+		Examples: []string{
+			"rvn schema impact type event --json",
+			"rvn schema impact trait priority --json",
+			"rvn schema impact field person nickname --json",
+			"rvn schema impact field project status --values paused --json",
+		},
+		UseCases: []string{
+			"Check what depends on a type before removing it",
+			"Find objects still using an enum value before dropping it",
+		},
+	},
+	"schema_infer": {
+		Name:        "schema infer",
+		Description: "Propose schema types and traits from an untyped vault",
+		LongDesc: `Scan frontmatter and trait annotations and propose the schema.yaml
+definitions the vault is missing:
+  - a type for each directory holding --min-files or more untyped files,
+    named after the directory (projects/ -> project)
+  - a type for each 'type:' value that schema.yaml does not define
+  - a trait for each @trait used but not declared
+
+Field types are inferred from the values in use (bool, number, date,
+datetime, url, ref, string, and lists of these). Strings that repeat a few
+distinct values become enums, and refs whose targets share one type get it
+as their target.
+
+Returns the proposals as a preview. With --confirm, writes them to
+schema.yaml (limit with --accept type:<name> or trait:<name>) and sets
+'type:' on the untyped files behind each accepted directory type. In an
+interactive terminal, each proposal is offered in turn to accept or skip.`,
+		Flags: []FlagMeta{
+			{Name: "min-files", Description: "Untyped files a directory needs to become a type (default 2)", Type: FlagTypeInt},
+			{Name: "accept", Description: "Only write these proposals (type:<name> or trait:<name>, repeatable)", Type: FlagTypeStringSlice, Examples: []string{"type:project", "trait:due"}},
+			{Name: "confirm", Description: "Write the proposals (default: preview only)", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn schema infer",
+			"rvn schema infer --json",
+			"rvn schema infer --accept type:project --accept trait:due --confirm --json",
+		},
+		UseCases: []string{
+			"Bootstrap a schema for an existing folder of markdown notes",
+			"Find the types and traits a vault uses without declaring them",
+		},
+	},
+	"schema_export": {
+		Name:        "schema export",
+		Description: "Export type and trait definitions as a shareable schema pack",
+		LongDesc: `Print a schema pack: a schema.yaml holding only the selected type and trait
+definitions, ready to share between vaults and add with 'rvn schema import'.
+
+Without --types or --traits, every type and trait is exported. With either,
+only the named definitions are. Template bindings are left out, since
+templates are files in this vault. Fields that refer to a type outside the
+pack are listed under notes so you can add that type too.
+
+Redirect the output to a file to create a pack. With --json, the pack is
+returned as content.`,
+		Flags: []FlagMeta{
+			{Name: "types", Description: "Types to export (comma-separated or repeatable)", Type: FlagTypeStringSlice, Examples: []string{"person,meeting"}},
+			{Name: "traits", Description: "Traits to export (comma-separated or repeatable)", Type: FlagTypeStringSlice, Examples: []string{"due,priority"}},
+		},
+		Examples: []string{
+			"rvn schema export --types person,meeting > crm-pack.yaml",
+			"rvn schema export --types project --traits due,priority > gtd-pack.yaml",
+			"rvn schema export --json",
+		},
+		UseCases: []string{
+			"Share a curated set of types and traits with a team",
+			"Copy definitions from one vault to another",
+		},
+	},
+	"schema_import": {
+		Name:        "schema import",
+		Description: "Add the types and traits from a schema pack to schema.yaml",
+		LongDesc: `Merge the types and traits of a schema pack (from 'rvn schema export', or
+another vault's schema.yaml) into this vault's schema.yaml.
+
+Each definition is reported as:
+  add        not defined here yet
+  unchanged  identical to this vault's definition
+  conflict   defined here differently (the differing keys are listed)
+
+Conflicts block the import unless --on-conflict is given: skip keeps this
+vault's definitions, overwrite replaces them with the pack's. The merged
+schema is validated before anything is written, so a pack whose fields refer
+to a type defined in neither place is refused.
+
+Returns a preview by default. Use --confirm to write schema.yaml.`,
+		Args: []ArgMeta{
+			{Name: "file", Description: "Schema pack file to import", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "on-conflict", Description: "How to handle definitions that differ: fail (default), skip, or overwrite", Type: FlagTypeString, Examples: []string{"skip", "overwrite"}},
+			{Name: "confirm", Description: "Write schema.yaml (default: preview only)", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn schema import crm-pack.yaml",
+			"rvn schema import crm-pack.yaml --confirm",
+			"rvn schema import gtd-pack.yaml --on-conflict skip --confirm --json",
+		},
+		UseCases: []string{
+			"Adopt a shared type pack in a new vault",
+			"Pull updated definitions from a team pack",
+		},
+	},
+	"schema_export_snippets": {
+		Name:        "schema export snippets",
+		Description: "Generate editor snippets that scaffold frontmatter for each type",
+		LongDesc: `Generate editor snippets or templates from schema.yaml so files created
+outside rvn start with the right type and fields.
+
+Formats:
+  vscode     One raven.code-snippets file; put it in .vscode/ (prefix: rvn-<type>)
+  obsidian   One <type>.md template per type for the core Templates plugin
+  yasnippet  markdown-mode/rvn-<type> snippets for Emacs (key: rvn-<type>)
+
+Each snippet lists the name field first, then required fields, then optional
+fields. Enum and bool fields offer their values as choices, schema defaults are
+filled in, and date fields default to today. Derived fields are left out.
+
+Without --output the generated files are printed. With --output they are written
+under that directory (relative paths are resolved against the vault root).
+Re-run after schema changes to keep snippets in sync.`,
+		Flags: []FlagMeta{
+			{Name: "format", Description: "Snippet format: vscode (default), obsidian, or yasnippet", Type: FlagTypeString, Examples: []string{"vscode", "obsidian", "yasnippet"}},
+			{Name: "type", Description: "Only export these types (repeatable)", Type: FlagTypeStringSlice, Examples: []string{"project"}},
+			{Name: "output", Description: "Directory to write the files into", Type: FlagTypeString, Examples: []string{".vscode", "templates"}},
+		},
+		Examples: []string{
+			"rvn schema export snippets --output .vscode",
+			"rvn schema export snippets --format obsidian --output templates",
+			"rvn schema export snippets --format yasnippet --output ~/.emacs.d/snippets",
+			"rvn schema export snippets --type project --json",
+		},
+		UseCases: []string{
+			"Create correctly typed files from VS Code, Obsidian, or Emacs",
+			"Keep editor templates in sync with schema.yaml",
+		},
+	},
 	"schema_rename_type": {
 		Name:        "schema rename type",
 		Description: "Rename a type and update all references",
@@ -1610,6 +2393,7 @@ field changes (including previous values) without writing.
 Bulk operations:
 Use --stdin to read object IDs from stdin (one per line). Bulk updates accept
 both field=value literals and --fields-json typed values.
+Pass @focus as the object ID to update every object in the focus working set.
 IMPORTANT: Bulk operations return preview by default. Changes are NOT applied unless confirm=true.
 
 Permissive writes: if a ref field is set to a target that does not exist yet, the
@@ -1624,6 +2408,8 @@ and a REF_NOT_FOUND warning per missing target.`,
 			{Name: "stdin", Description: "Read object IDs from stdin for bulk operations", Type: FlagTypeBool},
 			{Name: "confirm", Description: "Apply bulk changes (without this flag, bulk shows preview only)", Type: FlagTypeBool},
 			{Name: "dry-run", Description: "Preview a single-object set without applying it", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
+			{Name: "merge", Description: "Three-way merge the planned edit into files changed since the preview", Type: FlagTypeBool},
 		},
 		Examples: []string{
 			"rvn set people/freya email=freya@asgard.realm --json",
@@ -1659,6 +2445,7 @@ The reserved type field cannot be unset; use reclassify to change object type.`,
 		},
 		Flags: []FlagMeta{
 			{Name: "fields", Description: "Frontmatter field names to remove (repeatable; MCP should pass an array)", Type: FlagTypeStringSlice, Examples: []string{`["date", "link"]`}},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
 		},
 		Examples: []string{
 			"rvn unset docs/cleanup date link --json",
@@ -1670,6 +2457,47 @@ The reserved type field cannot be unset; use reclassify to change object type.`,
 			"Delete optional metadata from an object without editing the file manually",
 		},
 	},
+	"toggle": {
+		Name:        "toggle",
+		Use:         "toggle <object-id> <field>",
+		Description: "Flip a bool field or cycle an enum field",
+		LongDesc: `Toggle a frontmatter field on an existing file-level object.
+
+Bool fields flip between true and false; an unset bool becomes true. Enum
+fields advance to the next value in the order the schema declares them,
+wrapping back to the first; an unset or unrecognized value becomes the first
+declared value. Other field types are rejected; use set for those.
+
+Single-object toggle applies immediately. Pass --dry-run to preview the new
+value without writing.
+
+Bulk operations:
+Use --stdin to read object IDs from stdin (one per line); the field is the
+only positional argument. Each object toggles from its own current value.
+Pass @focus as the object ID to toggle every object in the focus working set.
+IMPORTANT: Bulk operations return preview by default. Changes are NOT applied unless confirm=true.`,
+		Args: []ArgMeta{
+			{Name: "object_id", Description: "Object to update (e.g., tasks/launch)", Required: false},
+			{Name: "field", Description: "Bool or enum field to toggle", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "stdin", Description: "Read object IDs from stdin for bulk operations", Type: FlagTypeBool},
+			{Name: "confirm", Description: "Apply bulk changes (without this flag, bulk shows preview only)", Type: FlagTypeBool},
+			{Name: "dry-run", Description: "Preview a single-object toggle without applying it", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
+			{Name: "merge", Description: "Three-way merge the planned edit into files changed since the preview", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn toggle tasks/launch done --json",
+			"rvn toggle projects/website status --dry-run --json",
+			"rvn query 'type:task .status==todo' --ids | rvn toggle --stdin status --confirm",
+		},
+		UseCases: []string{
+			"Mark a task done or not done",
+			"Advance a project to its next status",
+			"Bulk advance statuses for query results via --stdin",
+		},
+	},
 	"update": {
 		Name:        "update",
 		Use:         "update <trait_id> <new_value>",
@@ -1695,6 +2523,8 @@ IMPORTANT: Bulk operations return preview by default. Changes are NOT applied un
 			{Name: "trait-id", Description: "Trait ID for explicit-list bulk update (repeatable)", Type: FlagTypeStringSlice, Examples: []string{"daily/2026-01-25.md:trait:0"}},
 			{Name: "confirm", Description: "Apply bulk changes (without this flag, bulk shows preview only)", Type: FlagTypeBool},
 			{Name: "dry-run", Description: "Preview a single-object update without applying it", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
+			{Name: "merge", Description: "Three-way merge the planned edit into files changed since the preview", Type: FlagTypeBool},
 		},
 		BulkStdinArgName:    "trait_ids",
 		BulkStdinArgAliases: []string{"object_ids", "ids"},
@@ -1709,6 +2539,151 @@ IMPORTANT: Bulk operations return preview by default. Changes are NOT applied un
 			"Bulk update an explicit list of trait IDs without stdin piping",
 		},
 	},
+	"trait_set": {
+		Name:        "trait set",
+		Use:         "set <file:line|trait_id> <value>",
+		Description: "Set one trait annotation's value by file and line",
+		LongDesc: `Rewrite the value of a single trait annotation in place. Only the annotation
+changes; the rest of the line, including other traits, is left as written.
+
+Address the trait by:
+  - file and line: projects/website.md:12 (the file_path and line from
+    'rvn query trait:... --json')
+  - trait ID: projects/website.md:trait:3
+
+When a line has more than one trait, pass --trait to choose one. Values are
+validated against the trait's schema definition, and relative dates such as
+"tomorrow" resolve for date traits.
+
+Applies immediately. Pass --dry-run to see the rewritten line without writing.`,
+		Args: []ArgMeta{
+			{Name: "anchor", Description: "file:line (e.g., daily/2026-01-25.md:7) or trait ID", Required: true},
+			{Name: "value", Description: "New trait value", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "trait", Description: "Trait name to edit when the line has several traits", Type: FlagTypeString, Examples: []string{"due"}},
+			{Name: "dry-run", Description: "Preview the rewritten line without applying it", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn trait set daily/2026-01-25.md:7 done --json",
+			"rvn trait set projects/website.md:12 2026-03-01 --trait due --json",
+			"rvn trait set daily/2026-01-25.md:trait:0 tomorrow --dry-run --json",
+		},
+		UseCases: []string{
+			"Edit a trait from query results without matching on text",
+			"Change one of several traits on a line",
+		},
+	},
+	"task_list": {
+		Name:        "task list",
+		Use:         "list",
+		Description: "Show tasks as an agenda grouped by due date",
+		LongDesc: `List tasks: lines carrying the task trait (default @todo), grouped into an
+agenda of overdue tasks, one group per due date, and tasks with no due date.
+
+The due date comes from the due trait (default @due) on the same line. A
+recurring due date such as @due(every:friday) shows its next occurrence.
+
+Done and cancelled tasks are hidden unless --all is passed. The trait names
+and closed values are configured under tasks in raven.yaml.`,
+		Flags: []FlagMeta{
+			{Name: "all", Description: "Include done and cancelled tasks", Type: FlagTypeBool},
+			{Name: "refresh", Description: "Refresh stale files before listing", Type: FlagTypeBool},
+			{Name: "require-fresh", Description: "Reindex first if the index is stale; fail if it cannot be brought up to date", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn task list",
+			"rvn task list --all --json",
+		},
+		UseCases: []string{
+			"See what is overdue and what is due this week",
+			"Find a task's file:line to complete or reschedule it",
+		},
+	},
+	"task_done": {
+		Name:        "task done",
+		Use:         "done <file:line|trait_id>",
+		Description: "Mark a task done or cancelled",
+		LongDesc: `Set a task's trait value to the configured done value (default: done), or
+the cancelled value with --cancel.
+
+A task with a recurring due date, such as @due(every:friday), stays open and
+moves its due date to the next occurrence instead.
+
+Applies immediately. Pass --dry-run to see the rewritten line without writing.`,
+		Args: []ArgMeta{
+			{Name: "anchor", Description: "file:line (e.g., daily/2026-01-25.md:7) or trait ID", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "cancel", Description: "Mark the task cancelled instead of done", Type: FlagTypeBool},
+			{Name: "dry-run", Description: "Preview the rewritten line without applying it", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn task done daily/2026-01-25.md:7 --json",
+			"rvn task done projects/website.md:12 --cancel --json",
+		},
+		UseCases: []string{
+			"Complete a task from the agenda",
+			"Advance a recurring task to its next occurrence",
+		},
+	},
+	"task_snooze": {
+		Name:        "task snooze",
+		Use:         "snooze <file:line|trait_id>",
+		Description: "Push a task's due date back",
+		LongDesc: `Move a task's due date later by --by (default 1d), counting from its current
+due date or today, whichever is later. A task without a due date gets one.
+Recurring due dates keep their rule.
+
+Applies immediately. Pass --dry-run to see the rewritten line without writing.`,
+		Args: []ArgMeta{
+			{Name: "anchor", Description: "file:line (e.g., daily/2026-01-25.md:7) or trait ID", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "by", Description: "How long to snooze (e.g., 1d, 3d, 1w)", Type: FlagTypeString, Default: "1d", Examples: []string{"3d", "1w"}},
+			{Name: "dry-run", Description: "Preview the rewritten line without applying it", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn task snooze daily/2026-01-25.md:7 --json",
+			"rvn task snooze daily/2026-01-25.md:7 --by 1w --json",
+		},
+		UseCases: []string{
+			"Defer a task you cannot get to today",
+		},
+	},
+	"task_schedule": {
+		Name:        "task schedule",
+		Use:         "schedule <file:line|trait_id> <when>",
+		Description: "Set a task's due date or recurrence",
+		LongDesc: `Set a task's due date, adding the due trait when the line has none.
+
+<when> is a date (YYYY-MM-DD, today, tomorrow), a weekday name for its next
+occurrence, an offset from today (+3d, +2w), or a recurrence:
+every:day, every:weekday, every:week, every:month, every:year, or
+every:<weekday>.
+
+Applies immediately. Pass --dry-run to see the rewritten line without writing.`,
+		Args: []ArgMeta{
+			{Name: "anchor", Description: "file:line (e.g., daily/2026-01-25.md:7) or trait ID", Required: true},
+			{Name: "when", Description: "Due date, weekday, offset, or every:<rule>", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "dry-run", Description: "Preview the rewritten line without applying it", Type: FlagTypeBool},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn task schedule daily/2026-01-25.md:7 friday --json",
+			"rvn task schedule projects/website.md:12 2026-03-01 --json",
+			"rvn task schedule daily/2026-01-25.md:9 every:monday --json",
+		},
+		UseCases: []string{
+			"Give a task a due date",
+			"Make a task repeat",
+		},
+	},
 	"edit": {
 		Name:        "edit",
 		Description: "Surgical text replacement in vault content files",
@@ -1734,10 +2709,24 @@ without writing.
 Whitespace matters—old_str must match exactly including indentation.
 For multi-line replacements, include newlines in both old_str and new_str.
 
-Supports two input modes:
+Supports three input modes:
   - Single edit (backward compatible): <path> <old_str> <new_str>
+  - Structured edit: <path> --append-to-section "## Log" <text>,
+    --insert-after-heading "## Tasks" <text>, or --replace-line N <text>
   - Batch edits via JSON: <path> --edits-json '{"edits":[{"old_str":"from","new_str":"to"}]}'
 
+Structured edits place text by heading or line number instead of matching a
+string. Headings are given as "## Log" (level and text must match) or bare
+text (any level), and must be unique in the target scope; headings inside
+frontmatter or fenced code are ignored. --append-to-section inserts at the
+end of the section's subtree, before trailing blank lines.
+--insert-after-heading inserts directly below the heading. --replace-line
+replaces one body line (an empty text deletes it) and refuses frontmatter
+lines; use 'rvn read --raw --lines' for line numbers. In --edits-json, use
+{"append_to_section": "## Log", "new_str": "text"},
+{"insert_after_heading": ...}, or {"replace_line": 12, "new_str": ...}; each
+edit sees the result of the edits before it.
+
 Permissive writes: if an applied edit introduces a [[ref]] whose target does not
 exist yet, the edit still succeeds. The response adds data.missing_refs,
 data.missing_ref_items, and a REF_NOT_FOUND warning per missing target.`,
@@ -1749,6 +2738,10 @@ data.missing_ref_items, and a REF_NOT_FOUND warning per missing target.`,
 		Flags: []FlagMeta{
 			{Name: "dry-run", Description: "Preview the edit without applying it", Type: FlagTypeBool},
 			{Name: "edits-json", Description: "JSON object with ordered edits, e.g. '{\"edits\":[{\"old_str\":\"from\",\"new_str\":\"to\"}]}'", Type: FlagTypeJSON},
+			{Name: "append-to-section", Description: "Append the text at the end of this section (heading like \"## Log\" or heading text)", Type: FlagTypeString},
+			{Name: "insert-after-heading", Description: "Insert the text directly below this heading", Type: FlagTypeString},
+			{Name: "replace-line", Description: "Replace this body line (1-indexed) with the text; empty text deletes it", Type: FlagTypeInt},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
 		},
 		Examples: []string{
 			`rvn edit "daily/2025-12-27.md" "- Churn analysis" "- [[churn-analysis|Churn analysis]]" --json`,
@@ -1756,16 +2749,150 @@ data.missing_ref_items, and a REF_NOT_FOUND warning per missing target.`,
 			`rvn edit "project/raven#working-docs" "old link" "new link" --json`,
 			`rvn edit "daily/2026-01-02.md" "- old task" "" --json`,
 			`rvn edit "pages/notes.md" --edits-json '{"edits":[{"old_str":"reccommendation","new_str":"recommendation"},{"old_str":"Status: draft","new_str":"Status: active"}]}' --json`,
+			`rvn edit project/raven --append-to-section "## Log" --json -- "- 2026-03-01 shipped beta"`,
+			`rvn edit daily/2026-03-01 --insert-after-heading "## Tasks" --dry-run --json -- "- [ ] Review PR"`,
+			`rvn edit pages/notes --replace-line 12 "Status: active" --json`,
 		},
 		UseCases: []string{
 			"Edit markdown vault content files (use instead of 'sed', 'awk', or direct file writes)",
 			"Add wiki links to existing text",
 			"Fix typos in notes",
 			"Apply multiple ordered replacements in one command",
+			"Append to or insert under a heading without copying surrounding text",
 			"Add traits to existing lines",
 			"Delete specific content (use --dry-run to preview first)",
 		},
 	},
+	"lock": {
+		Name:        "lock",
+		Description: "Lock a file against content mutations",
+		LongDesc: `Add a file to locked_files in raven.yaml.
+
+Locked files are refused by set, unset, edit, add, update, move, delete,
+reclassify, upsert, and bulk --apply operations with a FILE_LOCKED error.
+Pass --unlock to one of those commands to modify a locked file once, or run
+'rvn unlock' to remove the lock.
+
+Use locks to protect canonical reference documents from accidental agent or
+bulk edits.`,
+		Args: []ArgMeta{
+			{Name: "reference", Description: "Object reference or file path to lock", Required: true},
+		},
+		Examples: []string{
+			"rvn lock reference/style-guide --json",
+			"rvn lock projects/roadmap.md --json",
+		},
+	},
+	"unlock": {
+		Name:        "unlock",
+		Description: "Remove a file from locked_files",
+		Args: []ArgMeta{
+			{Name: "reference", Description: "Object reference or locked file path", Required: true},
+		},
+		Examples: []string{
+			"rvn unlock reference/style-guide --json",
+		},
+	},
+	"publish": {
+		Name:        "publish",
+		Description: "Render vault pages to a static HTML site",
+		LongDesc: `Render pages to a static HTML site in an output directory.
+
+By default, pages with 'publish: true' in their frontmatter are published.
+--query publishes the files containing the query's results instead, and --all
+publishes every page. Each page becomes <object-id>.html with its frontmatter
+as a metadata table, its content rendered from markdown, and a list of the
+published pages that link to it. Wikilinks to published pages become links;
+links to unpublished pages become plain text. Assets linked from published
+pages are copied, and raven-query blocks are replaced with live results.
+An index.html lists the published pages by type.
+
+Defaults come from publish in raven.yaml (output, query, template, title).
+--template names an html/template file, relative to the vault, that lays out
+every page. The output directory must be new, empty, or a previous publish:
+files the previous publish wrote and this one did not are removed.`,
+		Flags: []FlagMeta{
+			{Name: "output", Description: "Directory to write the site to (relative paths are vault-relative)", Type: FlagTypeString, Examples: []string{"../site"}},
+			{Name: "query", Description: "Publish the files matching this query instead of publish: true pages", Type: FlagTypeString, Examples: []string{"type:project .status==active"}},
+			{Name: "all", Description: "Publish every page", Type: FlagTypeBool},
+			{Name: "template", Description: "html/template file for pages, relative to the vault", Type: FlagTypeString, Examples: []string{"templates/site.html"}},
+			{Name: "dry-run", Description: "List the pages that would be published without writing", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn publish --output ../site",
+			"rvn publish --output ../site --query 'type:project .status==active'",
+			"rvn publish --dry-run --json",
+		},
+		UseCases: []string{
+			"Share part of a vault as a static website",
+			"Preview which pages are marked for publishing",
+		},
+	},
+	"sync_external": {
+		Name:        "sync external",
+		Description: "Two-way sync a type with an external system",
+		LongDesc: `Syncs objects of one type with records in an external system, as configured
+under sync.<name> in raven.yaml. Supported adapters: github (repository issues).
+
+Objects link to records through an ID field (default: external_id). For each
+record, mapped fields are compared with the object and with the values saved
+at the previous sync (.raven/sync/<name>.json):
+
+- changed only in the external system: the object is updated (pulled)
+- changed only in Raven: the record is updated (pushed)
+- changed on both sides: reported as a conflict and left alone, unless
+  --prefer local or --prefer remote picks a side
+
+Records with no linked object are created as new objects. Objects without an
+ID are not pushed. Use --dry-run to preview changes without writing anything.`,
+		Args: []ArgMeta{
+			{Name: "name", Description: "Sync entry name from raven.yaml", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "dry-run", Description: "Show what would change without writing", Type: FlagTypeBool},
+			{Name: "prefer", Description: "Resolve conflicts in favor of one side: local or remote", Type: FlagTypeString},
+			{Name: "unlock", Description: "Allow modifying files listed in locked_files", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn sync external github --dry-run --json",
+			"rvn sync external github --json",
+			"rvn sync external github --prefer remote --json",
+		},
+	},
+	"sync_git": {
+		Name:        "sync git",
+		Description: "Commit vault changes and sync them with a git remote",
+		LongDesc: `Commits uncommitted changes in the vault, pulls the remote branch, and pushes.
+
+The commit message is generated from the changed files: the subject names
+the changed objects and the body lists them as added, modified, renamed, or
+deleted. Pass --message to write your own. Only files inside the vault are
+committed, and .raven/ is never committed.
+
+The remote branch is merged (never rebased). When the merge changes files,
+they are reindexed; when it conflicts, the command stops with the conflicted
+files so you can resolve them and run it again. The remote and branch default
+to origin and the current branch (git.remote and git.branch in raven.yaml).
+Without the default remote, changes are committed locally only.
+
+Set git.auto_commit: true to also commit the files changed by every applied
+--confirm run as its own commit.`,
+		Flags: []FlagMeta{
+			{Name: "message", Description: "Commit message instead of the generated one", Type: FlagTypeString},
+			{Name: "no-pull", Description: "Do not pull from the remote", Type: FlagTypeBool},
+			{Name: "no-push", Description: "Do not push to the remote", Type: FlagTypeBool},
+			{Name: "dry-run", Description: "Show what would be committed without running git", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn sync git --json",
+			"rvn sync git --dry-run --json",
+			"rvn sync git --message 'Weekly review' --no-push --json",
+		},
+		UseCases: []string{
+			"Back up the vault to a git remote",
+			"Pick up changes made to the vault on another machine",
+		},
+	},
 	"search": {
 		Name:        "search",
 		Use:         "search [query]",
@@ -1809,7 +2936,12 @@ indexed files. Non-interactive use still requires a query string.`,
 		Description: "Resolve or create a daily note",
 		LongDesc: `Resolve or create a daily note for a given date.
 
-If no date is provided, resolves today's note. Creates the file if it doesn't exist.`,
+If no date is provided, resolves today's note. Creates the file if it doesn't exist.
+
+When it creates a note for today or a later date, open tasks from the previous
+week's daily notes are moved into it under "## Carried over", and open tasks due
+that day are linked under "## Due today" (see daily: in raven.yaml). The output
+ends with the day's agenda: overdue tasks and tasks due on the note's date.`,
 		Args: []ArgMeta{
 			{Name: "date", Description: "Date (today, yesterday, tomorrow, YYYY-MM-DD)", Required: false},
 		},
@@ -1827,6 +2959,81 @@ If no date is provided, resolves today's note. Creates the file if it doesn't ex
 			"Navigate to past daily notes",
 		},
 	},
+	"weekly": {
+		Name:        "weekly",
+		Description: "Resolve or create a weekly note",
+		LongDesc: `Resolve or create the weekly note for a week.
+
+If no week is provided, resolves the current week. Creates the file from
+periodic.weekly.template in raven.yaml if it doesn't exist. Notes are stored
+under periodic.weekly.directory (default: weekly/) and named by week key.`,
+		Args: []ArgMeta{
+			{Name: "period", Description: "Week (this, last, next, 2026-W07, or a date inside it)", Required: false},
+		},
+		Flags: []FlagMeta{
+			{Name: "template", Description: "Template file to use when creating a new weekly note", Type: FlagTypeString},
+		},
+		Examples: []string{
+			"rvn weekly --json",
+			"rvn weekly last --json",
+			"rvn weekly 2026-W07 --json",
+			"rvn weekly 2026-02-12 --json",
+		},
+		UseCases: []string{
+			"Access or create this week's note",
+			"Plan or review a week",
+		},
+	},
+	"monthly": {
+		Name:        "monthly",
+		Description: "Resolve or create a monthly note",
+		LongDesc: `Resolve or create the monthly note for a month.
+
+If no month is provided, resolves the current month. Creates the file from
+periodic.monthly.template in raven.yaml if it doesn't exist. Notes are stored
+under periodic.monthly.directory (default: monthly/) and named by month key.`,
+		Args: []ArgMeta{
+			{Name: "period", Description: "Month (this, last, next, 2026-02, or a date inside it)", Required: false},
+		},
+		Flags: []FlagMeta{
+			{Name: "template", Description: "Template file to use when creating a new monthly note", Type: FlagTypeString},
+		},
+		Examples: []string{
+			"rvn monthly --json",
+			"rvn monthly last --json",
+			"rvn monthly 2026-02 --json",
+			"rvn monthly 2026-02-12 --json",
+		},
+		UseCases: []string{
+			"Access or create this month's note",
+			"Plan or review a month",
+		},
+	},
+	"quarterly": {
+		Name:        "quarterly",
+		Description: "Resolve or create a quarterly note",
+		LongDesc: `Resolve or create the quarterly note for a quarter.
+
+If no quarter is provided, resolves the current quarter. Creates the file from
+periodic.quarterly.template in raven.yaml if it doesn't exist. Notes are stored
+under periodic.quarterly.directory (default: quarterly/) and named by quarter key.`,
+		Args: []ArgMeta{
+			{Name: "period", Description: "Quarter (this, last, next, 2026-Q1, or a date inside it)", Required: false},
+		},
+		Flags: []FlagMeta{
+			{Name: "template", Description: "Template file to use when creating a new quarterly note", Type: FlagTypeString},
+		},
+		Examples: []string{
+			"rvn quarterly --json",
+			"rvn quarterly last --json",
+			"rvn quarterly 2026-Q1 --json",
+			"rvn quarterly 2026-02-12 --json",
+		},
+		UseCases: []string{
+			"Access or create this quarter's note",
+			"Plan or review a quarter",
+		},
+	},
 	"config": {
 		Name:        "config",
 		Description: "Manage global config.toml settings",
@@ -1917,6 +3124,30 @@ Use 'config unset' to clear fields.`,
 			"rvn config unset --ui-accent --ui-code-theme --ui-markdown-style --json",
 		},
 	},
+	"config_edit": {
+		Name:        "config edit",
+		Description: "Edit the active vault's raven.yaml with validation",
+		LongDesc: `Open the active vault's raven.yaml in your editor and validate it on save.
+
+The edited content is checked for YAML syntax, unknown keys, values of the wrong
+type, and saved queries that do not parse. Invalid content is never written;
+each problem is reported with its line number, and in a terminal you can reopen
+the editor to fix it.
+
+Pass --content to replace raven.yaml non-interactively (the same validation applies).
+The editor is determined by the 'editor' setting in config.toml or $EDITOR.`,
+		Flags: []FlagMeta{
+			{Name: "content", Description: "Full raven.yaml content to validate and write instead of opening an editor", Type: FlagTypeString},
+		},
+		Examples: []string{
+			"rvn config edit",
+			"rvn config edit --content \"auto_reindex: true\" --json",
+		},
+		UseCases: []string{
+			"Hand-edit raven.yaml without risking a config that breaks every command",
+			"Replace raven.yaml from a script with line-level validation errors",
+		},
+	},
 	"vault": {
 		Name:        "vault",
 		Use:         "vault [subcommand]",
@@ -1973,8 +3204,25 @@ The default vault is stored in config.toml and used as fallback.`,
 	"vault_stats": {
 		Name:        "vault stats",
 		Description: "Show vault statistics",
+		LongDesc: `Show file, object, trait, reference, and orphan counts for the vault index.
+Orphans are objects no other file references.
+
+Each run of vault stats or reindex records the day's counts in the index.
+With --since 30d (or 4w, 1y, or a YYYY-MM-DD date), also report activity over
+that window: growth in each count since the start of the window, the daily
+series, the most-edited objects (edits seen when files were reindexed), and
+the most-referenced objects.
+
+With --by-author, also count objects per created_by/modified_by author.
+Attribution fields are stamped when attribution.enabled is set in raven.yaml.`,
+		Flags: []FlagMeta{
+			{Name: "by-author", Description: "Break down objects by created_by/modified_by author", Type: FlagTypeBool},
+			{Name: "since", Description: "Report activity over a window: 30d, 4w, 1y, or a YYYY-MM-DD start date", Type: FlagTypeString, Examples: []string{"30d"}},
+		},
 		Examples: []string{
 			"rvn vault stats --json",
+			"rvn vault stats --by-author",
+			"rvn vault stats --since 30d",
 		},
 	},
 	"vault_use": {
@@ -2060,6 +3308,52 @@ Safety checks:
 			"rvn vault clear --json",
 		},
 	},
+	"vault_encrypt": {
+		Name:        "vault encrypt",
+		Description: "Store a directory's files encrypted at rest",
+		LongDesc: `Add a directory to encryption.directories in raven.yaml and encrypt the
+markdown files in it.
+
+Raven decrypts these files in memory to index, read, and query them, and
+encrypts them again whenever it writes them (AES-256-GCM, key derived with
+PBKDF2). The passphrase comes from RAVEN_VAULT_KEY (or encryption.key_env), or
+from a command set for it under [key_commands] in config.toml, such as a
+keychain lookup.
+
+Other programs see ciphertext, including editors opened outside Raven. The
+index holds decrypted text; enable index.encrypt to keep it encrypted too.
+Running the command again for an encrypted directory encrypts files added to
+it as plaintext. Without --confirm, lists the files that would be encrypted.`,
+		Args: []ArgMeta{
+			{Name: "directory", Description: "Vault-relative directory to encrypt", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "confirm", Description: "Encrypt the files (default: preview only)", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn vault encrypt people/private/ --json",
+			"rvn vault encrypt people/private/ --confirm --json",
+		},
+		UseCases: []string{
+			"Keep sensitive notes unreadable in backups, sync services, and git remotes",
+		},
+	},
+	"vault_decrypt": {
+		Name:        "vault decrypt",
+		Description: "Store an encrypted directory's files as plaintext again",
+		LongDesc: `Remove a directory from encryption.directories in raven.yaml and rewrite
+its encrypted files as plaintext. Requires the passphrase. Without --confirm,
+lists the files that would be decrypted.`,
+		Args: []ArgMeta{
+			{Name: "directory", Description: "Encrypted directory to decrypt", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "confirm", Description: "Decrypt the files (default: preview only)", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn vault decrypt people/private/ --confirm --json",
+		},
+	},
 	"vault_config_show": {
 		Name:        "vault config show",
 		Description: "Show current raven.yaml values",
@@ -2443,6 +3737,163 @@ with their match sources.`,
 			"Validate references without side effects",
 		},
 	},
+	"resume": {
+		Name:        "resume",
+		Description: "Resume an interrupted bulk operation",
+		LongDesc: `Resume an interrupted bulk operation from its last checkpoint.
+
+Applied bulk runs larger than one batch (25 items) save progress under
+.raven/operations/ after each batch. If a run is interrupted or stops on an
+error, resume finishes only the items that were not completed, so nothing is
+applied twice. Bulk add is checkpointed per item because appending is not
+idempotent.
+
+Without an operation ID, lists resumable operations. With an operation ID,
+returns a preview of the pending items by default; pass --confirm to apply.
+Checkpoints are removed once every item has been applied.`,
+		Args: []ArgMeta{
+			{Name: "operation_id", Description: "Operation ID reported by the interrupted run (e.g., op-20260115-093000-a1b2c3)", Required: false},
+		},
+		Flags: []FlagMeta{
+			{Name: "confirm", Description: "Apply the remaining items (without this flag, shows preview only)", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn resume --json",
+			"rvn resume op-20260115-093000-a1b2c3 --json",
+			"rvn resume op-20260115-093000-a1b2c3 --confirm --json",
+		},
+		UseCases: []string{
+			"Finish a large bulk apply that was interrupted",
+			"List bulk operations that still have pending items",
+		},
+	},
+	"redirects_list": {
+		Name:        "redirects list",
+		Description: "List redirects from moved or renamed object IDs",
+		LongDesc: `List the redirects recorded in redirects.yaml at the vault root.
+
+rvn move and rvn rename record the old object ID and its new ID, so references
+to the old ID from outside the vault (bookmarks, exported sites, scripts,
+printed links) still resolve in read, open, edit, and other commands.
+Redirects pointing at a moved object are updated to its latest ID.
+
+Each redirect reports a status:
+  ok              the old ID resolves to an existing object
+  missing_target  the object it points to no longer exists
+  shadowed        a new object now uses the old ID, so the redirect is unused
+  cycle           the redirects loop without reaching an object
+
+Status is checked against the index. Remove non-ok redirects with
+'rvn redirects prune'.`,
+		Examples: []string{
+			"rvn redirects list --json",
+		},
+		UseCases: []string{
+			"See where an old object ID now points",
+			"Find redirects left behind by deleted objects",
+		},
+	},
+	"redirects_prune": {
+		Name:        "redirects prune",
+		Description: "Remove redirects that no longer resolve",
+		LongDesc: `Remove redirects whose status is not ok (see 'rvn redirects list').
+
+Preview is default; use --confirm to apply.`,
+		Flags: []FlagMeta{
+			{Name: "confirm", Description: "Remove stale redirects (without this flag, shows preview only)", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn redirects prune --json",
+			"rvn redirects prune --confirm --json",
+		},
+	},
+	"fmt": {
+		Name:        "fmt",
+		Description: "Normalize markdown files to vault conventions",
+		LongDesc: `Normalizes markdown files to the vault's conventions:
+
+- frontmatter_order: puts frontmatter keys in canonical order (fmt.key_order
+  first, default [type], then the rest alphabetically)
+- trait_spacing: writes trait annotations as @name(value), without spaces
+- wikilinks: trims spaces inside [[links]] and drops .md from targets
+- heading_case: capitalizes headings in sentence or title case (off by default)
+
+Fenced code blocks, inline code, and template files are never changed.
+Configure rules under fmt in raven.yaml.
+
+Preview is default; use --confirm to apply. Use --check in CI: it never
+writes and exits non-zero when any file needs formatting.`,
+		Args: []ArgMeta{
+			{Name: "path", Description: "File, directory, or reference to format (optional, defaults to entire vault)", Required: false},
+		},
+		Flags: []FlagMeta{
+			{Name: "check", Description: "Exit non-zero if any file needs formatting; never writes", Type: FlagTypeBool},
+			{Name: "confirm", Description: "Write formatted files (without this flag, shows preview only)", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn fmt --json",
+			"rvn fmt projects/ --confirm --json",
+			"rvn fmt --check",
+		},
+		UseCases: []string{
+			"Normalize notes written by hand or by other tools",
+			"Fail CI when notes drift from vault conventions",
+		},
+	},
+	"history": {
+		Name:        "history",
+		Description: "List recent operations that can be undone",
+		LongDesc: `Lists recent mutating operations recorded under .raven/history/, newest first.
+
+Every applied content command (new, add, set, edit, move, rename, delete,
+reclassify, import, bulk applies) and every applied check fix, fmt, or schema
+rename is recorded with a copy of each changed file as it was before the command ran.
+Previews and dry runs are not recorded. The most recent 50 operations are kept.
+
+Use 'rvn undo' to revert the most recent operations.`,
+		Flags: []FlagMeta{
+			{Name: "limit", Short: "n", Description: "Maximum number of operations to list", Type: FlagTypeInt, Default: "20"},
+		},
+		Examples: []string{
+			"rvn history",
+			"rvn history --limit 5 --json",
+		},
+		UseCases: []string{
+			"See what recent commands changed before undoing them",
+		},
+	},
+	"undo": {
+		Name:        "undo",
+		Description: "Revert the most recent recorded operations",
+		LongDesc: `Reverts the most recent operations listed by 'rvn history'.
+
+Files changed by each operation are restored from the copies recorded before it
+ran; files it created are removed. A deleted object comes back from its
+recorded copy and its .trash/ copy is removed. With a count, the last n
+operations that have not already been undone are reverted together.
+
+Returns a preview by default; pass --confirm to apply. If a file was edited
+after the operation ran, or was changed in a way that could not be recorded,
+undo reports a conflict and refuses to apply unless --force is passed. With
+--force, later edits to conflicting files are overwritten and files without a
+recorded copy are left as they are.`,
+		Args: []ArgMeta{
+			{Name: "count", Description: "Number of operations to undo (default: 1)", Required: false},
+		},
+		Flags: []FlagMeta{
+			{Name: "confirm", Description: "Apply the undo (without this flag, shows preview only)", Type: FlagTypeBool},
+			{Name: "force", Description: "Undo even if files changed since the operation ran", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn undo",
+			"rvn undo --confirm",
+			"rvn undo 3 --confirm --json",
+		},
+		UseCases: []string{
+			"Revert an accidental bulk set or delete",
+			"Roll back the last few agent edits",
+		},
+	},
 	"import": {
 		Name:        "import",
 		Description: "Import objects from JSON data",
@@ -2508,4 +3959,254 @@ Mapping file format (heterogeneous):
 			"Sync external data sources into the vault",
 		},
 	},
+	"import_csv": {
+		Name:        "import csv",
+		Description: "Create or update objects of one type from CSV rows",
+		LongDesc: `Create or update one object of --type per CSV row.
+
+The first row names the columns. --map field=Column maps a column to a
+field (repeatable, or comma-separated: --map email=Email,name=Full Name).
+Columns without a mapping map to the field of the same name, compared
+case-insensitively with spaces as underscores ("Due Date" is due_date).
+Columns matching no field are ignored and listed under ignored_columns.
+
+Values are converted to their field's type: numbers, booleans (yes/no,
+true/false), loosely written dates (Mar 1, 2026), and comma-separated
+lists for array fields. Empty cells leave a field unset.
+
+Rows are matched to existing objects by --key (default: the type's
+name_field): a match is updated, otherwise an object is created. Later
+rows repeating a key are skipped as duplicates. Use --create-only or
+--update-only to restrict this.
+
+Without --confirm, shows a preview of what each row would do. The result
+reports created, updated, and skipped rows with reasons.`,
+		Args: []ArgMeta{
+			{Name: "file", Description: "CSV file to import (- for stdin)", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "type", Description: "Type each row becomes", Type: FlagTypeString, Examples: []string{"person", "book"}},
+			{Name: "map", Description: "Column mapping: field=Column (repeatable, comma-separated)", Type: FlagTypeStringSlice, Examples: []string{"email=Email,name=Full Name"}},
+			{Name: "key", Description: "Field used to match existing objects and dedupe rows (default: type's name_field)", Type: FlagTypeString},
+			{Name: "content-field", Description: "Column to use as page body content", Type: FlagTypeString},
+			{Name: "create-only", Description: "Only create new objects, skip rows matching existing ones", Type: FlagTypeBool},
+			{Name: "update-only", Description: "Only update existing objects, skip new rows", Type: FlagTypeBool},
+			{Name: "confirm", Description: "Apply the import (default: preview only)", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			`rvn import csv contacts.csv --type person --map "email=Email,name=Full Name" --json`,
+			`rvn import csv contacts.csv --type person --map "email=Email,name=Full Name" --confirm --json`,
+			"rvn import csv books.csv --type book --key isbn --update-only --confirm",
+		},
+		UseCases: []string{
+			"Import contacts or a reading list from a spreadsheet",
+			"Re-sync objects from a regularly exported CSV",
+		},
+	},
+	"import_markdown": {
+		Name:        "import markdown",
+		Description: "Import a folder of plain markdown files",
+		LongDesc: `Copy a folder of markdown files into the vault as pages.
+
+Each .md file gets a slugified ID under the destination directory (default:
+the slug of the source folder name). When an ID is already taken, a numeric
+suffix is added (-2, -3, ...) and the file is reported as renamed.
+
+Relative links between imported files, such as [Plan](../plans/Q1 Plan.md#goals),
+become [[refs]] to the new IDs. Links that cannot be converted are left as
+written and listed under unresolved: targets missing from the folder, links
+that leave the folder, and links to non-markdown files. URLs, same-page
+anchors, and fenced code blocks are not touched.
+
+Without --dry-run, import applies changes immediately.`,
+		Args: []ArgMeta{
+			{Name: "dir", Description: "Folder of markdown files to import (outside the vault)", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "to", Description: "Vault directory to import into (default: slug of the folder name)", Type: FlagTypeString, Examples: []string{"garden", "imports/notes"}},
+			{Name: "dry-run", Description: "Preview changes without writing", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn import markdown ~/Documents/notes --dry-run --json",
+			"rvn import markdown ~/Documents/notes --to garden --json",
+		},
+		UseCases: []string{
+			"Adopt an existing plain-markdown collection",
+			"Find links that will break before importing a folder",
+		},
+	},
+	"import_obsidian": {
+		Name:        "import obsidian",
+		Description: "Import an Obsidian vault, converting its conventions",
+		LongDesc: `Copy an Obsidian vault into this vault and convert Obsidian conventions.
+
+Each note gets a slugified ID under the destination directory, as with
+'rvn import markdown'. Then:
+
+- [[Note Name]] links and embeds, which Obsidian resolves by file name,
+  become [[refs]] to the new IDs (headings become section slugs).
+- YAML tags become trait annotations (@tag(name)) at the top of the note.
+- Dataview inline fields (key:: value, [key:: value]) move to frontmatter.
+- aliases sets Raven's alias field to the first alias.
+- Notes in a top-level folder with several notes get a type named after
+  the folder (People/ -> person).
+
+The result includes a schema.yaml proposal declaring the inferred types,
+their frontmatter fields, and the tag trait. It is not applied; merge it
+into schema.yaml. To index #tags in note bodies as the same trait, enable
+hashtags in raven.yaml.
+
+Links that cannot be converted are left as written and listed under
+unresolved. Hidden folders such as .obsidian are skipped.
+
+Without --dry-run, import applies changes immediately.`,
+		Args: []ArgMeta{
+			{Name: "dir", Description: "Obsidian vault folder to import (outside this vault)", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "to", Description: "Vault directory to import into (default: slug of the folder name)", Type: FlagTypeString, Examples: []string{"obsidian", "imports/notes"}},
+			{Name: "tag-trait", Description: "Trait that YAML tags become (default: hashtags.trait, or tag)", Type: FlagTypeString, Examples: []string{"tag", "topic"}},
+			{Name: "dry-run", Description: "Preview changes and the schema proposal without writing", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn import obsidian ~/Obsidian/Notes --dry-run --json",
+			"rvn import obsidian ~/Obsidian/Notes --to notes --json",
+		},
+		UseCases: []string{
+			"Move an Obsidian vault to Raven",
+			"Draft a schema from an Obsidian vault's folders and properties",
+		},
+	},
+	"import_logseq": {
+		Name:        "import logseq",
+		Description: "Import a Logseq graph, keeping its block structure",
+		LongDesc: `Copy a Logseq graph into this vault and convert its outlines.
+
+Pages from pages/ land at the top of the destination directory, namespaced
+pages (a___b.md) under a/b, and journals under journals/ named by date.
+Then:
+
+- Page properties (key:: value) become frontmatter fields; type:: types the
+  page, alias:: sets alias, and tags:: become trait annotations (@tag(name)).
+- Blocks with child blocks, an id::, or a heading marker become sections,
+  so the outline is kept as child objects. Other blocks stay list items.
+- TODO, DOING, NOW, LATER, and WAITING become the task trait (@todo);
+  DONE and CANCELED set its done and cancelled values. [#A] priorities
+  become @priority, and DEADLINE or SCHEDULED dates become @due.
+- Block properties become trait annotations on the block.
+- [[Page]] links, #[[Page]] tags, and ((block refs)) become [[refs]].
+
+The result includes a schema.yaml proposal declaring the types, fields,
+and traits the pages use. It is not applied; merge it into schema.yaml.
+
+Links that cannot be converted are left as written and listed under
+unresolved. The logseq/, assets/, and hidden folders are skipped.
+
+Without --dry-run, import applies changes immediately.`,
+		Args: []ArgMeta{
+			{Name: "dir", Description: "Logseq graph folder to import (outside this vault)", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "to", Description: "Vault directory to import into (default: slug of the folder name)", Type: FlagTypeString, Examples: []string{"logseq", "imports/graph"}},
+			{Name: "tag-trait", Description: "Trait that tags become (default: hashtags.trait, or tag)", Type: FlagTypeString, Examples: []string{"tag", "topic"}},
+			{Name: "dry-run", Description: "Preview changes and the schema proposal without writing", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn import logseq ~/Logseq/graph --dry-run --json",
+			"rvn import logseq ~/Logseq/graph --to notes --json",
+		},
+		UseCases: []string{
+			"Move a Logseq graph to Raven",
+			"Keep Logseq tasks and block references when migrating",
+		},
+	},
+	"import_org_roam": {
+		Name:        "import org-roam",
+		Description: "Import an org-roam directory, converting org files to markdown",
+		LongDesc: `Copy an org-roam directory into this vault, converting each .org file to
+markdown.
+
+Each file gets a slugified ID under the destination directory, without
+org-roam's timestamp prefix. Then:
+
+- #+title names the note, the file's property drawer becomes frontmatter
+  fields, ROAM_ALIASES sets alias, and #+filetags become trait annotations
+  (@tag(name)).
+- Headlines become sections, so the outline is kept as child objects.
+  Headline tags and properties become trait annotations on the heading.
+- TODO and other open keywords become the task trait (@todo); DONE and
+  CANCELLED set its done and cancelled values. [#A] priorities become
+  @priority, and DEADLINE or SCHEDULED dates become @due.
+- [[id:...]] links become [[refs]] to the note or section with that ID, and
+  [[file:...]] links to imported files become refs too.
+- Source, example, and quote blocks, lists, tables, and emphasis are
+  converted to markdown; comments and other #+ keywords are dropped.
+
+The result includes a schema.yaml proposal declaring the traits the notes
+use. It is not applied; merge it into schema.yaml.
+
+Links that cannot be converted are left as written and listed under
+unresolved. Hidden folders are skipped.
+
+Without --dry-run, import applies changes immediately.`,
+		Args: []ArgMeta{
+			{Name: "dir", Description: "org-roam directory to import (outside this vault)", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "to", Description: "Vault directory to import into (default: slug of the folder name)", Type: FlagTypeString, Examples: []string{"roam", "imports/org"}},
+			{Name: "tag-trait", Description: "Trait that tags become (default: hashtags.trait, or tag)", Type: FlagTypeString, Examples: []string{"tag", "topic"}},
+			{Name: "dry-run", Description: "Preview changes and the schema proposal without writing", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn import org-roam ~/org/roam --dry-run --json",
+			"rvn import org-roam ~/org/roam --to notes --json",
+		},
+		UseCases: []string{
+			"Move an org-roam knowledge base to Raven",
+			"Convert org files with TODO headlines into Raven tasks",
+		},
+	},
+	"import_notion": {
+		Name:        "import notion",
+		Description: "Import a Notion export, turning databases into types",
+		LongDesc: `Copy a Notion export into this vault. Export from Notion with the
+"Markdown & CSV" format; HTML exports are not supported. Pass the
+downloaded .zip, or the folder it was unzipped to.
+
+Pages keep their hierarchy under the destination directory, with the IDs
+Notion appends to file names removed. Then:
+
+- Each database becomes a type named after it (Projects -> project). Its
+  rows become objects of that type, and each column becomes a frontmatter
+  field, typed from its values: Yes/No columns become bool, numbers
+  number, dates date or datetime, and URLs url. Date ranges keep the end
+  date in <field>_end.
+- Relation columns become ref fields (ref[] when a row relates to several
+  pages) pointing at the related rows' type.
+- Rows missing a page in the export are created from the CSV alone.
+- Relative links between exported pages become [[refs]] to the new IDs.
+
+The result includes a schema.yaml proposal declaring a type per database
+with a field per column. It is not applied; merge it into schema.yaml.
+
+Links and relations that cannot be converted are left as written and
+listed under unresolved.
+
+Without --dry-run, import applies changes immediately.`,
+		Args: []ArgMeta{
+			{Name: "export", Description: "Notion export .zip or unzipped folder (outside this vault)", Required: true},
+		},
+		Flags: []FlagMeta{
+			{Name: "to", Description: "Vault directory to import into (default: notion)", Type: FlagTypeString, Examples: []string{"notion", "imports/wiki"}},
+			{Name: "dry-run", Description: "Preview changes and the schema proposal without writing", Type: FlagTypeBool},
+		},
+		Examples: []string{
+			"rvn import notion ~/Downloads/Export-2f1c.zip --dry-run --json",
+			"rvn import notion ~/Downloads/Export-2f1c.zip --to wiki --json",
+		},
+		UseCases: []string{
+			"Move a Notion workspace to Raven",
+			"Turn Notion databases into typed objects and a schema",
+		},
+	},
 }
@@ -55,19 +55,22 @@ func RequiresVault(commandID string) bool {
 func defaultCategoryForCommandID(commandID string) Category {
 	commandID = strings.ReplaceAll(commandID, " ", "_")
 	switch {
-	case commandID == "query" || commandID == "query_saved_list" || commandID == "query_saved_get" ||
-		commandID == "query_saved_set" || commandID == "query_saved_remove" ||
-		commandID == "search" || commandID == "backlinks" || commandID == "outlinks" || commandID == "resolve":
+	case commandID == "query" || commandID == "list" || commandID == "inbox_list" || strings.HasPrefix(commandID, "focus_") || commandID == "suggest-type" || commandID == "query_saved_list" || commandID == "query_saved_get" ||
+		commandID == "query_saved_set" || commandID == "query_saved_remove" || commandID == "query_snapshot" || commandID == "query_diff" || commandID == "dashboard" || commandID == "task_list" ||
+		commandID == "search" || commandID == "backlinks" || commandID == "outlinks" || commandID == "resolve" || commandID == "graph_export" ||
+		commandID == "orphans" || commandID == "deadlinks":
 		return CategoryQuery
-	case commandID == "new" || commandID == "add" || commandID == "upsert" || commandID == "set" || commandID == "unset" ||
-		commandID == "delete" || commandID == "move" || commandID == "reclassify" || commandID == "import" ||
-		commandID == "edit" || commandID == "update":
+	case commandID == "new" || commandID == "add" || commandID == "upsert" || commandID == "set" || commandID == "unset" || commandID == "toggle" ||
+		commandID == "delete" || commandID == "move" || commandID == "rename" || commandID == "reclassify" || commandID == "archive" || commandID == "import" || commandID == "import_csv" || commandID == "import_markdown" || commandID == "import_obsidian" || commandID == "import_logseq" || commandID == "import_org_roam" || commandID == "import_notion" ||
+		commandID == "edit" || commandID == "update" || commandID == "trait_set" || commandID == "task_done" || commandID == "task_snooze" || commandID == "task_schedule" || commandID == "resume" ||
+		commandID == "lock" || commandID == "unlock" || commandID == "sync_external":
 		return CategoryContent
-	case commandID == "schema" || strings.HasPrefix(commandID, "schema_") || commandID == "template" || strings.HasPrefix(commandID, "template_"):
+	case commandID == "schema" || strings.HasPrefix(commandID, "schema_") || commandID == "drift_report" || commandID == "template" || strings.HasPrefix(commandID, "template_"):
 		return CategorySchema
-	case commandID == "read" || commandID == "open" || commandID == "daily" || commandID == "date":
+	case commandID == "read" || commandID == "open" || commandID == "daily" || commandID == "weekly" || commandID == "monthly" || commandID == "quarterly" || commandID == "date":
 		return CategoryNavigation
-	case commandID == "check" || commandID == "reindex" || commandID == "version":
+	case commandID == "check" || commandID == "fmt" || commandID == "health" || commandID == "doctor" || commandID == "reindex" || commandID == "watch" || commandID == "version" || commandID == "history" || commandID == "undo" ||
+		strings.HasPrefix(commandID, "redirects_"):
 		return CategoryMaintenance
 	default:
 		return CategoryVault
@@ -77,10 +80,10 @@ func defaultCategoryForCommandID(commandID string) Category {
 func defaultAccessForCommandID(commandID string) AccessMode {
 	commandID = strings.ReplaceAll(commandID, " ", "_")
 	switch commandID {
-	case "read", "search", "backlinks", "outlinks", "resolve", "query", "query_saved_list", "query_saved_get",
-		"schema", "schema_validate", "schema_template_list", "schema_template_get",
+	case "read", "search", "backlinks", "outlinks", "resolve", "query", "list", "inbox_list", "focus_list", "suggest-type", "query_saved_list", "query_saved_get", "query_diff", "dashboard", "task_list", "date", "orphans", "deadlinks",
+		"schema", "schema_validate", "schema_impact", "schema_export", "drift_report", "schema_template_list", "schema_template_get",
 		"docs", "docs_list", "docs_search",
-		"version",
+		"health", "version", "history", "redirects_list",
 		"vault", "vault_list", "vault_current", "vault_path", "vault_stats",
 		"config", "config_show":
 		return AccessRead
@@ -94,7 +97,7 @@ func defaultRiskForCommandID(commandID string, access AccessMode) RiskLevel {
 	if access == AccessRead {
 		return RiskSafe
 	}
-	if commandID == "delete" || commandID == "move" || commandID == "reclassify" {
+	if commandID == "delete" || commandID == "move" || commandID == "rename" || commandID == "reclassify" {
 		return RiskDestructive
 	}
 	if strings.Contains(commandID, "remove") || strings.Contains(commandID, "delete") {
@@ -34,6 +34,22 @@ type Config struct {
 
 	// UI controls optional CLI theming preferences.
 	UI UIConfig `toml:"ui"`
+
+	// Identity names the current user for vault attribution metadata.
+	Identity IdentityConfig `toml:"identity"`
+
+	// KeyCommands maps environment variable names to commands that print
+	// their value (for example a keychain lookup). Raven runs a command when
+	// it needs a passphrase or token and the variable is unset. Vault files
+	// cannot configure commands.
+	KeyCommands map[string]string `toml:"key_commands"`
+}
+
+// IdentityConfig identifies the user stamped into created_by/modified_by.
+type IdentityConfig struct {
+	// Name is the display name recorded on mutations.
+	// When empty, Raven falls back to `git config user.name`.
+	Name string `toml:"name"`
 }
 
 // UIConfig represents optional CLI theming preferences.
@@ -49,6 +65,27 @@ type UIConfig struct {
 	// MarkdownStyle sets the full Glamour markdown style.
 	// Empty or "auto" uses Glamour's automatic light/dark style.
 	MarkdownStyle string `toml:"markdown_style"`
+
+	// Colors overrides the semantic status colors used in human output.
+	Colors UIColors `toml:"colors"`
+
+	// Hyperlinks controls OSC 8 file links in terminal output.
+	// Unset means enabled whenever stdout is a terminal.
+	Hyperlinks *bool `toml:"hyperlinks"`
+}
+
+// UIColors represents the configurable semantic color palette.
+// Each value accepts the same formats as UIConfig.Accent, plus "none" to
+// render that status without color.
+type UIColors struct {
+	Success string `toml:"success"`
+	Warning string `toml:"warning"`
+	Error   string `toml:"error"`
+}
+
+// HyperlinksEnabled reports whether file hyperlinks are allowed by config.
+func (u UIConfig) HyperlinksEnabled() bool {
+	return u.Hyperlinks == nil || *u.Hyperlinks
 }
 
 // GetVaultPath returns the path for a named vault.
@@ -206,6 +243,13 @@ func CreateDefaultAt(path string) (string, error) {
 # accent = "39"
 # code_theme = "monokai"
 # markdown_style = "auto"
+# hyperlinks = true
+#
+# Status colors used for success/warning/error markers.
+# [ui.colors]
+# success = "2"
+# warning = "3"
+# error = "1"
 `
 
 	if err := os.WriteFile(configPath, []byte(defaultConfig), 0o644); err != nil {
@@ -20,12 +20,25 @@ type persistedConfig struct {
 	Editor       *string              `toml:"editor,omitempty"`
 	EditorMode   *string              `toml:"editor_mode,omitempty"`
 	UI           *persistedUISettings `toml:"ui,omitempty"`
+	Identity     *persistedIdentity   `toml:"identity,omitempty"`
+}
+
+type persistedIdentity struct {
+	Name *string `toml:"name,omitempty"`
 }
 
 type persistedUISettings struct {
-	Accent        *string `toml:"accent,omitempty"`
-	CodeTheme     *string `toml:"code_theme,omitempty"`
-	MarkdownStyle *string `toml:"markdown_style,omitempty"`
+	Accent        *string            `toml:"accent,omitempty"`
+	CodeTheme     *string            `toml:"code_theme,omitempty"`
+	MarkdownStyle *string            `toml:"markdown_style,omitempty"`
+	Hyperlinks    *bool              `toml:"hyperlinks,omitempty"`
+	Colors        *persistedUIColors `toml:"colors,omitempty"`
+}
+
+type persistedUIColors struct {
+	Success *string `toml:"success,omitempty"`
+	Warning *string `toml:"warning,omitempty"`
+	Error   *string `toml:"error,omitempty"`
 }
 
 func nonEmptyPtr(value string) *string {
@@ -59,14 +72,27 @@ func SaveTo(path string, cfg *Config) error {
 	accent := nonEmptyPtr(cfg.UI.Accent)
 	codeTheme := nonEmptyPtr(cfg.UI.CodeTheme)
 	markdownStyle := nonEmptyPtr(cfg.UI.MarkdownStyle)
-	if accent != nil || codeTheme != nil || markdownStyle != nil {
+	var colors *persistedUIColors
+	success := nonEmptyPtr(cfg.UI.Colors.Success)
+	warning := nonEmptyPtr(cfg.UI.Colors.Warning)
+	errorColor := nonEmptyPtr(cfg.UI.Colors.Error)
+	if success != nil || warning != nil || errorColor != nil {
+		colors = &persistedUIColors{Success: success, Warning: warning, Error: errorColor}
+	}
+	if accent != nil || codeTheme != nil || markdownStyle != nil || cfg.UI.Hyperlinks != nil || colors != nil {
 		out.UI = &persistedUISettings{
 			Accent:        accent,
 			CodeTheme:     codeTheme,
 			MarkdownStyle: markdownStyle,
+			Hyperlinks:    cfg.UI.Hyperlinks,
+			Colors:        colors,
 		}
 	}
 
+	if name := nonEmptyPtr(cfg.Identity.Name); name != nil {
+		out.Identity = &persistedIdentity{Name: name}
+	}
+
 	var buf bytes.Buffer
 	if err := toml.NewEncoder(&buf).Encode(out); err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
@@ -9,6 +9,7 @@ func TestSaveToPersistsConfigFields(t *testing.T) {
 	tmp := t.TempDir()
 	path := filepath.Join(tmp, "config.toml")
 
+	hyperlinks := false
 	cfg := &Config{
 		DefaultVault: "work",
 		StateFile:    "state.toml",
@@ -21,6 +22,8 @@ func TestSaveToPersistsConfigFields(t *testing.T) {
 			Accent:        "39",
 			CodeTheme:     "dracula",
 			MarkdownStyle: "dark",
+			Hyperlinks:    &hyperlinks,
+			Colors:        UIColors{Success: "#00ff00", Error: "none"},
 		},
 	}
 
@@ -51,4 +54,10 @@ func TestSaveToPersistsConfigFields(t *testing.T) {
 	if loaded.UI.MarkdownStyle != "dark" {
 		t.Fatalf("expected ui.markdown_style=dark, got %q", loaded.UI.MarkdownStyle)
 	}
+	if loaded.UI.HyperlinksEnabled() {
+		t.Fatalf("expected ui.hyperlinks=false to persist")
+	}
+	if loaded.UI.Colors.Success != "#00ff00" || loaded.UI.Colors.Error != "none" || loaded.UI.Colors.Warning != "" {
+		t.Fatalf("unexpected ui.colors: %#v", loaded.UI.Colors)
+	}
 }
@@ -11,6 +11,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/dates"
 	ravenignore "github.com/aidanlsb/raven/internal/ignore"
 	"github.com/aidanlsb/raven/internal/paths"
 )
@@ -36,6 +37,9 @@ type VaultConfig struct {
 	// Queries defines saved queries that can be run with `rvn query <name>`
 	Queries map[string]*SavedQuery `yaml:"queries,omitempty"`
 
+	// Dashboard lists the saved queries shown together by `rvn dashboard`.
+	Dashboard []DashboardSection `yaml:"dashboard,omitempty"`
+
 	// ProtectedPrefixes are additional vault-relative path prefixes that Raven should
 	// treat as protected/system-managed. Raven automation features
 	// should refuse to read/write/move/edit/delete within these prefixes.
@@ -47,11 +51,81 @@ type VaultConfig struct {
 	// Exclude contains gitignore-style patterns for paths that are not managed by Raven.
 	Exclude []string `yaml:"exclude,omitempty"`
 
+	// SparsePaths are vault-relative directories that belong to a shared vault
+	// but may not be checked out locally. While a sparse directory is absent,
+	// references into it are treated as external rather than broken.
+	SparsePaths []string `yaml:"sparse_paths,omitempty"`
+
+	// LockedFiles are vault-relative file paths that content mutation commands
+	// refuse to modify unless invoked with --unlock. Managed by `rvn lock`/`rvn unlock`.
+	LockedFiles []string `yaml:"locked_files,omitempty"`
+
+	// ignoreLocks is set by WithoutLocks so frontmatter locks are bypassed too.
+	ignoreLocks bool
+
 	// Capture configures quick capture behavior
 	Capture *CaptureConfig `yaml:"capture,omitempty"`
 
 	// Deletion configures file deletion behavior
 	Deletion *DeletionConfig `yaml:"deletion,omitempty"`
+
+	// Attribution stamps created_by/modified_by into frontmatter on mutations.
+	Attribution *AttributionConfig `yaml:"attribution,omitempty"`
+
+	// DateLinks indexes plain-text date mentions as refs to daily notes.
+	DateLinks *DateLinksConfig `yaml:"date_links,omitempty"`
+
+	// DateHub selects the date fields shown by `rvn date`.
+	DateHub *DateHubConfig `yaml:"date_hub,omitempty"`
+
+	// IssueRefs indexes Jira and GitHub issue mentions as external refs.
+	IssueRefs *IssueRefsConfig `yaml:"issue_refs,omitempty"`
+
+	// Hashtags indexes #tags in body text as traits.
+	Hashtags *HashtagsConfig `yaml:"hashtags,omitempty"`
+
+	// Display controls how field values are shortened in human output.
+	Display *DisplayConfig `yaml:"display,omitempty"`
+
+	// Index configures the derived SQLite index in .raven/.
+	Index *IndexConfig `yaml:"index,omitempty"`
+
+	// Encryption lists directories whose files are stored encrypted at rest.
+	Encryption *EncryptionConfig `yaml:"encryption,omitempty"`
+
+	// Git configures `rvn sync git` and auto-commits of confirmed runs.
+	Git *GitConfig `yaml:"git,omitempty"`
+
+	// Check applies a shared lint profile and local rule overrides to
+	// `rvn check`.
+	Check *CheckConfig `yaml:"check,omitempty"`
+
+	// Fmt configures the formatting rules applied by `rvn fmt`.
+	Fmt *FmtConfig `yaml:"fmt,omitempty"`
+
+	// Tasks configures the traits `rvn task` treats as tasks.
+	Tasks *TasksConfig `yaml:"tasks,omitempty"`
+
+	// Daily configures what `rvn daily` adds to a newly created daily note.
+	Daily *DailyConfig `yaml:"daily,omitempty"`
+
+	// Periodic configures weekly, monthly, and quarterly notes.
+	Periodic *PeriodicConfig `yaml:"periodic,omitempty"`
+
+	// MCP restricts what agents connected through `rvn serve` may change.
+	MCP *MCPConfig `yaml:"mcp,omitempty"`
+
+	// Publish configures the static site `rvn publish` renders.
+	Publish *PublishConfig `yaml:"publish,omitempty"`
+
+	// SchemaStamp records the schema the vault was last reindexed against.
+	// It is written by `rvn reindex`; `rvn check` warns when schema.yaml has
+	// changed since.
+	SchemaStamp *SchemaStamp `yaml:"schema_stamp,omitempty"`
+
+	// Sync configures two-way sync with external systems, keyed by the name
+	// passed to `rvn sync external <name>`.
+	Sync map[string]*SyncAdapterConfig `yaml:"sync,omitempty"`
 }
 
 func (vc *VaultConfig) UnmarshalYAML(value *yaml.Node) error {
@@ -198,6 +272,622 @@ type DeletionConfig struct {
 	TrashDir string `yaml:"trash_dir,omitempty"`
 }
 
+// AttributionConfig configures team attribution metadata on objects.
+type AttributionConfig struct {
+	// Enabled stamps created_by/modified_by frontmatter fields when Raven
+	// creates or modifies a file (default: false).
+	Enabled bool `yaml:"enabled,omitempty"`
+}
+
+// IsAttributionEnabled returns whether mutations should stamp attribution fields.
+func (vc *VaultConfig) IsAttributionEnabled() bool {
+	return vc != nil && vc.Attribution != nil && vc.Attribution.Enabled
+}
+
+// DefaultIndexKeyEnv is the environment variable holding the index
+// encryption passphrase when index.key_env is not set.
+const DefaultIndexKeyEnv = "RAVEN_INDEX_KEY"
+
+// SchemaStamp identifies a schema by its format version and content hash.
+type SchemaStamp struct {
+	Version int    `yaml:"version"`
+	Hash    string `yaml:"hash"`
+}
+
+// IndexConfig configures the derived SQLite index.
+type IndexConfig struct {
+	// Encrypt stores the index encrypted at rest as .raven/index.db.enc and
+	// keeps the working copy in memory (default: false).
+	Encrypt bool `yaml:"encrypt,omitempty"`
+
+	// KeyEnv names the environment variable holding the passphrase
+	// (default: RAVEN_INDEX_KEY). A command for it may be set under
+	// [key_commands] in config.toml.
+	KeyEnv string `yaml:"key_env,omitempty"`
+}
+
+// IsIndexEncrypted returns whether the index is stored encrypted at rest.
+func (vc *VaultConfig) IsIndexEncrypted() bool {
+	return vc != nil && vc.Index != nil && vc.Index.Encrypt
+}
+
+// GetIndexKeyEnv returns the environment variable holding the index passphrase.
+func (vc *VaultConfig) GetIndexKeyEnv() string {
+	if vc == nil || vc.Index == nil || strings.TrimSpace(vc.Index.KeyEnv) == "" {
+		return DefaultIndexKeyEnv
+	}
+	return strings.TrimSpace(vc.Index.KeyEnv)
+}
+
+// DefaultEncryptionKeyEnv is the environment variable holding the passphrase
+// for encrypted directories when encryption.key_env is not set.
+const DefaultEncryptionKeyEnv = "RAVEN_VAULT_KEY"
+
+// EncryptionConfig configures directories stored encrypted at rest. Raven
+// decrypts their files in memory to index and read them and encrypts them
+// again when it writes.
+type EncryptionConfig struct {
+	// Directories are vault-relative directories (with trailing slash)
+	// whose markdown files are encrypted. Managed by `rvn vault encrypt`.
+	Directories []string `yaml:"directories,omitempty"`
+
+	// KeyEnv names the environment variable holding the passphrase
+	// (default: RAVEN_VAULT_KEY). A command that prints the passphrase can
+	// be set for it under [key_commands] in config.toml.
+	KeyEnv string `yaml:"key_env,omitempty"`
+
+	// Salt is the hex-encoded key derivation salt shared by the vault's
+	// encrypted files. It is generated by `rvn vault encrypt`.
+	Salt string `yaml:"salt,omitempty"`
+}
+
+// GetEncryptedDirectories returns the directories stored encrypted at rest.
+func (vc *VaultConfig) GetEncryptedDirectories() []string {
+	if vc == nil || vc.Encryption == nil {
+		return nil
+	}
+	return vc.Encryption.Directories
+}
+
+// DefaultGitRemote is the remote `rvn sync git` pulls from and pushes to
+// when git.remote is not set.
+const DefaultGitRemote = "origin"
+
+// GitConfig configures committing vault changes to the git repository that
+// holds the vault.
+type GitConfig struct {
+	// Remote is the remote to pull from and push to (default: origin).
+	Remote string `yaml:"remote,omitempty"`
+
+	// Branch is the remote branch to sync (default: the current branch).
+	Branch string `yaml:"branch,omitempty"`
+
+	// AutoCommit commits the files changed by each applied --confirm run
+	// as its own commit (default: false).
+	AutoCommit bool `yaml:"auto_commit,omitempty"`
+}
+
+// GetGitRemote returns the remote `rvn sync git` uses.
+func (vc *VaultConfig) GetGitRemote() string {
+	if vc == nil || vc.Git == nil || strings.TrimSpace(vc.Git.Remote) == "" {
+		return DefaultGitRemote
+	}
+	return strings.TrimSpace(vc.Git.Remote)
+}
+
+// GetGitBranch returns the configured remote branch, or "" for the current
+// branch.
+func (vc *VaultConfig) GetGitBranch() string {
+	if vc == nil || vc.Git == nil {
+		return ""
+	}
+	return strings.TrimSpace(vc.Git.Branch)
+}
+
+// IsGitAutoCommitEnabled returns whether confirmed runs are committed.
+func (vc *VaultConfig) IsGitAutoCommitEnabled() bool {
+	return vc != nil && vc.Git != nil && vc.Git.AutoCommit
+}
+
+// DefaultSyncIDField is the frontmatter field that links an object to its
+// external record when sync.<name>.id_field is not set.
+const DefaultSyncIDField = "external_id"
+
+// SyncAdapterConfig configures two-way sync between one Raven type and an
+// external system.
+type SyncAdapterConfig struct {
+	// Adapter selects the implementation (default: the sync entry's name).
+	// Supported adapters: github.
+	Adapter string `yaml:"adapter,omitempty"`
+
+	// Type is the Raven type that external records map to.
+	Type string `yaml:"type"`
+
+	// IDField is the frontmatter field holding the external record ID
+	// (default: external_id).
+	IDField string `yaml:"id_field,omitempty"`
+
+	// Fields maps Raven field names to adapter field names
+	// (e.g., name: title, status: state).
+	Fields map[string]string `yaml:"fields,omitempty"`
+
+	// Repo is the GitHub repository as owner/name.
+	Repo string `yaml:"repo,omitempty"`
+
+	// URL overrides the adapter's API base URL (e.g., GitHub Enterprise).
+	URL string `yaml:"url,omitempty"`
+
+	// TokenEnv names the environment variable holding the API token. A
+	// command that prints the token can be set for it under [key_commands]
+	// in config.toml.
+	TokenEnv string `yaml:"token_env,omitempty"`
+}
+
+// GetSyncAdapter returns the named sync configuration, if present.
+func (vc *VaultConfig) GetSyncAdapter(name string) (*SyncAdapterConfig, bool) {
+	if vc == nil || vc.Sync == nil {
+		return nil, false
+	}
+	cfg, ok := vc.Sync[name]
+	return cfg, ok && cfg != nil
+}
+
+// GetAdapter returns the adapter kind, defaulting to the sync entry's name.
+func (sc *SyncAdapterConfig) GetAdapter(name string) string {
+	if adapter := strings.TrimSpace(sc.Adapter); adapter != "" {
+		return adapter
+	}
+	return name
+}
+
+// GetIDField returns the field linking objects to external records.
+func (sc *SyncAdapterConfig) GetIDField() string {
+	if field := strings.TrimSpace(sc.IDField); field != "" {
+		return field
+	}
+	return DefaultSyncIDField
+}
+
+// DateLinksConfig configures automatic linking of dates mentioned in text.
+type DateLinksConfig struct {
+	// Enabled records dates mentioned in body text as refs to the matching
+	// daily note during indexing (default: false).
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// Formats lists extra date formats to detect besides ISO YYYY-MM-DD,
+	// using the tokens YYYY, MM, M, DD and D (e.g. "MM/DD/YYYY").
+	Formats []string `yaml:"formats,omitempty"`
+}
+
+// GetDateMentionFormats returns the extra date mention formats to detect, or
+// nil when date linking is disabled. A non-nil empty slice means ISO only.
+func (vc *VaultConfig) GetDateMentionFormats() []string {
+	if vc == nil || vc.DateLinks == nil || !vc.DateLinks.Enabled {
+		return nil
+	}
+	formats := make([]string, 0, len(vc.DateLinks.Formats))
+	return append(formats, vc.DateLinks.Formats...)
+}
+
+// DateHubConfig selects the object date fields that appear in the date hub.
+// Fields are named type.field (e.g. "meeting.scheduled_at").
+type DateHubConfig struct {
+	// Fields lists the object date fields shown for their date. When empty,
+	// every date-valued object field is shown.
+	Fields []string `yaml:"fields,omitempty"`
+
+	// Recurring lists date fields that recur yearly (e.g. "person.birthday").
+	// They are shown on the same month and day of every later year.
+	Recurring []string `yaml:"recurring,omitempty"`
+}
+
+// DateHubField names one object date field in the date hub.
+type DateHubField struct {
+	Type  string
+	Field string
+}
+
+// parseDateHubFields parses type.field entries, skipping malformed ones.
+func parseDateHubFields(entries []string) []DateHubField {
+	fields := make([]DateHubField, 0, len(entries))
+	for _, entry := range entries {
+		typeName, field, ok := strings.Cut(strings.TrimSpace(entry), ".")
+		if !ok || typeName == "" || field == "" {
+			continue
+		}
+		fields = append(fields, DateHubField{Type: typeName, Field: field})
+	}
+	return fields
+}
+
+// DateHubRecurringFields returns the yearly recurring date fields.
+func (vc *VaultConfig) DateHubRecurringFields() []DateHubField {
+	if vc == nil || vc.DateHub == nil {
+		return nil
+	}
+	return parseDateHubFields(vc.DateHub.Recurring)
+}
+
+// DateHubIncludesField reports whether a date stored in an object field
+// appears in the date hub for that exact date.
+func (vc *VaultConfig) DateHubIncludesField(typeName, field string) bool {
+	if vc == nil || vc.DateHub == nil || len(vc.DateHub.Fields) == 0 {
+		return true
+	}
+	want := DateHubField{Type: typeName, Field: field}
+	for _, candidate := range parseDateHubFields(vc.DateHub.Fields) {
+		if candidate == want {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckConfig configures `rvn check` rules. Rules and naming set here
+// override the same keys from the profile.
+type CheckConfig struct {
+	// Profile is a lint profile file, either a path relative to the vault
+	// root or an http(s) URL.
+	Profile string `yaml:"profile,omitempty"`
+
+	// Rules sets the severity of issue types: error, warning, or off.
+	Rules map[string]string `yaml:"rules,omitempty"`
+
+	// Naming sets file naming conventions checked by naming_convention.
+	Naming *NamingConventions `yaml:"naming,omitempty"`
+}
+
+// NamingConventions names the file naming style objects should follow. A
+// style is kebab-case, snake_case, lowercase, or a regular expression matched
+// against the file name without .md.
+type NamingConventions struct {
+	// Default applies to every type without its own entry.
+	Default string `yaml:"default,omitempty"`
+
+	// Types sets the style for objects of a specific type.
+	Types map[string]string `yaml:"types,omitempty"`
+}
+
+// FmtConfig configures `rvn fmt`.
+type FmtConfig struct {
+	// Rules turns formatting rules on or off: frontmatter_order,
+	// trait_spacing, and wikilinks take on or off; heading_case takes off,
+	// sentence, or title.
+	Rules map[string]string `yaml:"rules,omitempty"`
+
+	// KeyOrder lists the frontmatter keys placed first, in order; other keys
+	// follow alphabetically (default: [type]).
+	KeyOrder []string `yaml:"key_order,omitempty"`
+}
+
+// TasksConfig configures `rvn task`.
+type TasksConfig struct {
+	// Trait marks a line as a task (default: todo).
+	Trait string `yaml:"trait,omitempty"`
+
+	// DueTrait holds a task's due date (default: due).
+	DueTrait string `yaml:"due_trait,omitempty"`
+
+	// DoneValue is the task trait value `rvn task done` sets (default: done).
+	DoneValue string `yaml:"done_value,omitempty"`
+
+	// CancelledValue is the value `rvn task done --cancel` sets
+	// (default: cancelled).
+	CancelledValue string `yaml:"cancelled_value,omitempty"`
+}
+
+// GetTasksConfig returns the tasks config with defaults applied.
+func (vc *VaultConfig) GetTasksConfig() *TasksConfig {
+	cfg := TasksConfig{}
+	if vc != nil && vc.Tasks != nil {
+		cfg = *vc.Tasks
+	}
+	if strings.TrimSpace(cfg.Trait) == "" {
+		cfg.Trait = "todo"
+	}
+	if strings.TrimSpace(cfg.DueTrait) == "" {
+		cfg.DueTrait = "due"
+	}
+	if strings.TrimSpace(cfg.DoneValue) == "" {
+		cfg.DoneValue = "done"
+	}
+	if strings.TrimSpace(cfg.CancelledValue) == "" {
+		cfg.CancelledValue = "cancelled"
+	}
+	return &cfg
+}
+
+// DailyConfig configures the automation `rvn daily` runs when it creates a
+// daily note for today or a later date.
+type DailyConfig struct {
+	// CarryOver moves open tasks from earlier daily notes into the new note
+	// (default: true).
+	CarryOver *bool `yaml:"carry_over,omitempty"`
+
+	// CarryOverDays is how many days back carry-over looks (default: 7).
+	CarryOverDays int `yaml:"carry_over_days,omitempty"`
+
+	// Scheduled lists open tasks due on the note's date (default: true).
+	Scheduled *bool `yaml:"scheduled,omitempty"`
+}
+
+const defaultCarryOverDays = 7
+
+// GetDailyConfig returns the daily note config with defaults applied.
+func (vc *VaultConfig) GetDailyConfig() *DailyConfig {
+	cfg := DailyConfig{}
+	if vc != nil && vc.Daily != nil {
+		cfg = *vc.Daily
+	}
+	if cfg.CarryOver == nil {
+		enabled := true
+		cfg.CarryOver = &enabled
+	}
+	if cfg.CarryOverDays <= 0 {
+		cfg.CarryOverDays = defaultCarryOverDays
+	}
+	if cfg.Scheduled == nil {
+		enabled := true
+		cfg.Scheduled = &enabled
+	}
+	return &cfg
+}
+
+// PeriodicConfig configures the notes `rvn weekly`, `rvn monthly`, and
+// `rvn quarterly` create.
+type PeriodicConfig struct {
+	Weekly    *PeriodicNoteConfig `yaml:"weekly,omitempty"`
+	Monthly   *PeriodicNoteConfig `yaml:"monthly,omitempty"`
+	Quarterly *PeriodicNoteConfig `yaml:"quarterly,omitempty"`
+}
+
+// PeriodicNoteConfig configures one kind of periodic note.
+type PeriodicNoteConfig struct {
+	// Directory holds the notes, one per period named by its key such as
+	// 2026-w07 (default: weekly, monthly, or quarterly).
+	Directory string `yaml:"directory,omitempty"`
+
+	// Template is a template file for new notes, under the template
+	// directory.
+	Template string `yaml:"template,omitempty"`
+}
+
+var defaultPeriodicDirectories = map[dates.PeriodKind]string{
+	dates.PeriodWeek:    "weekly",
+	dates.PeriodMonth:   "monthly",
+	dates.PeriodQuarter: "quarterly",
+}
+
+// GetPeriodicNoteConfig returns the config for one kind of periodic note with
+// defaults applied. Directory is normalized like the daily directory.
+func (vc *VaultConfig) GetPeriodicNoteConfig(kind dates.PeriodKind) PeriodicNoteConfig {
+	var cfg PeriodicNoteConfig
+	if vc != nil && vc.Periodic != nil {
+		var configured *PeriodicNoteConfig
+		switch kind {
+		case dates.PeriodWeek:
+			configured = vc.Periodic.Weekly
+		case dates.PeriodMonth:
+			configured = vc.Periodic.Monthly
+		case dates.PeriodQuarter:
+			configured = vc.Periodic.Quarterly
+		}
+		if configured != nil {
+			cfg = *configured
+		}
+	}
+
+	fallback := defaultPeriodicDirectories[kind]
+	cleaned := paths.NormalizeVaultRelPath(paths.NormalizeDirRoot(cfg.Directory))
+	if cleaned == "" || !paths.IsValidVaultRelPath(cleaned) {
+		cleaned = fallback
+	}
+	cfg.Directory = strings.TrimSuffix(cleaned, "/")
+	return cfg
+}
+
+// PeriodicNotePath returns the vault-relative path of a period's note, such
+// as weekly/2026-w07.md.
+func (vc *VaultConfig) PeriodicNotePath(period dates.Period) string {
+	return path.Join(vc.GetPeriodicNoteConfig(period.Kind).Directory, strings.ToLower(period.Key())+".md")
+}
+
+// MCPConfig is the write policy for commands invoked by MCP clients. It does
+// not affect the CLI.
+type MCPConfig struct {
+	// ReadOnly rejects every command that would change the vault. Previews
+	// and dry runs are still allowed.
+	ReadOnly bool `yaml:"read_only,omitempty"`
+
+	// AllowCommands, when set, lists the only mutating commands agents may
+	// apply (command IDs such as "add" or "schema_add_type").
+	AllowCommands []string `yaml:"allow_commands,omitempty"`
+
+	// DenyCommands lists mutating commands agents may never apply.
+	DenyCommands []string `yaml:"deny_commands,omitempty"`
+
+	// AllowPaths and DenyPaths are gitignore-style patterns for the files
+	// agent mutations may change. A mutation touching a file outside
+	// AllowPaths (when set) or inside DenyPaths is rolled back.
+	AllowPaths []string `yaml:"allow_paths,omitempty"`
+	DenyPaths  []string `yaml:"deny_paths,omitempty"`
+
+	// RequirePreview rejects applying a command that can preview unless the
+	// same call was previewed first.
+	RequirePreview bool `yaml:"require_preview,omitempty"`
+
+	// Audit appends every agent mutation attempt to .raven/audit/mcp.jsonl.
+	Audit bool `yaml:"audit,omitempty"`
+}
+
+// GetMCPConfig returns the MCP write policy, or nil when none is configured.
+func (vc *VaultConfig) GetMCPConfig() *MCPConfig {
+	if vc == nil || vc.MCP == nil {
+		return nil
+	}
+	cfg := *vc.MCP
+	return &cfg
+}
+
+// PublishConfig configures `rvn publish`.
+type PublishConfig struct {
+	// Output is the directory the site is written to. Relative paths are
+	// resolved against the vault root.
+	Output string `yaml:"output,omitempty"`
+
+	// Query selects the pages to publish instead of the publish: true
+	// frontmatter filter.
+	Query string `yaml:"query,omitempty"`
+
+	// Template is an html/template file, relative to the vault root, used
+	// to lay out every page.
+	Template string `yaml:"template,omitempty"`
+
+	// Title is the site title shown on the index page (default: the vault
+	// directory name).
+	Title string `yaml:"title,omitempty"`
+}
+
+// GetPublishConfig returns the publish settings, or an empty config when
+// none are set.
+func (vc *VaultConfig) GetPublishConfig() PublishConfig {
+	if vc == nil || vc.Publish == nil {
+		return PublishConfig{}
+	}
+	return *vc.Publish
+}
+
+// IssueRefsConfig configures detection of issue tracker references in content.
+type IssueRefsConfig struct {
+	// Enabled records Jira keys and GitHub issue URLs found in body text
+	// during indexing (default: false).
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// Fetch looks up live titles and statuses for read and query output.
+	Fetch bool `yaml:"fetch,omitempty"`
+
+	// Jira configures Jira key detection and lookups.
+	Jira *IssueTrackerConfig `yaml:"jira,omitempty"`
+
+	// GitHub configures GitHub issue lookups. Issue URLs are detected
+	// whenever issue_refs is enabled.
+	GitHub *IssueTrackerConfig `yaml:"github,omitempty"`
+}
+
+// IssueTrackerConfig configures one issue tracker.
+type IssueTrackerConfig struct {
+	// URL is the Jira site (e.g., https://acme.atlassian.net) or the GitHub
+	// API base URL (default: https://api.github.com).
+	URL string `yaml:"url,omitempty"`
+
+	// Projects lists Jira project keys recognized as bare mentions such as
+	// PROJ-123. Links to {url}/browse/KEY-123 are recognized for any project.
+	Projects []string `yaml:"projects,omitempty"`
+
+	// EmailEnv names the environment variable holding the Jira account
+	// email. When set, Jira requests use basic auth with the token.
+	EmailEnv string `yaml:"email_env,omitempty"`
+
+	// TokenEnv names the environment variable holding the API token. A
+	// command that prints the token can be set for it under [key_commands]
+	// in config.toml.
+	TokenEnv string `yaml:"token_env,omitempty"`
+}
+
+// GetIssueRefs returns the issue reference configuration, or nil when issue
+// reference detection is disabled.
+func (vc *VaultConfig) GetIssueRefs() *IssueRefsConfig {
+	if vc == nil || vc.IssueRefs == nil || !vc.IssueRefs.Enabled {
+		return nil
+	}
+	return vc.IssueRefs
+}
+
+// JiraProjects returns the Jira project keys recognized as bare mentions.
+func (ic *IssueRefsConfig) JiraProjects() []string {
+	if ic == nil || ic.Jira == nil {
+		return nil
+	}
+	projects := make([]string, 0, len(ic.Jira.Projects))
+	for _, project := range ic.Jira.Projects {
+		if project = strings.ToUpper(strings.TrimSpace(project)); project != "" {
+			projects = append(projects, project)
+		}
+	}
+	return projects
+}
+
+// JiraURL returns the Jira site URL without a trailing slash.
+func (ic *IssueRefsConfig) JiraURL() string {
+	if ic == nil || ic.Jira == nil {
+		return ""
+	}
+	return strings.TrimRight(strings.TrimSpace(ic.Jira.URL), "/")
+}
+
+// DefaultHashtagTrait is the trait #tags are indexed as when hashtags.trait
+// is not set.
+const DefaultHashtagTrait = "tag"
+
+// HashtagsConfig configures #tag syntax, as used by Obsidian, as an
+// alternative way to write a trait.
+type HashtagsConfig struct {
+	// Enabled indexes each #tag in body text as a trait whose value is the
+	// tag (default: false).
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// Trait names the trait tags are recorded as (default: "tag").
+	Trait string `yaml:"trait,omitempty"`
+}
+
+// GetHashtagTrait returns the trait #tags are indexed as, or "" when hashtag
+// indexing is disabled.
+func (vc *VaultConfig) GetHashtagTrait() string {
+	if vc == nil || vc.Hashtags == nil || !vc.Hashtags.Enabled {
+		return ""
+	}
+	if trait := strings.TrimSpace(vc.Hashtags.Trait); trait != "" {
+		return trait
+	}
+	return DefaultHashtagTrait
+}
+
+// DefaultFieldTruncate is the default maximum length of a field value in
+// human-readable output.
+const DefaultFieldTruncate = 80
+
+// DisplayConfig controls human-readable output formatting. JSON output is
+// never truncated.
+type DisplayConfig struct {
+	// Truncate is the maximum length of field values in human output.
+	// Unset uses DefaultFieldTruncate; 0 disables truncation.
+	Truncate *int `yaml:"truncate,omitempty"`
+
+	// Fields overrides Truncate per field, keyed by field name or by
+	// type.field (which takes precedence). 0 disables truncation.
+	Fields map[string]int `yaml:"fields,omitempty"`
+}
+
+// FieldTruncateLimit returns the maximum display length for a field value of
+// the given type, or 0 when the value should not be truncated.
+func (vc *VaultConfig) FieldTruncateLimit(typeName, field string) int {
+	if vc == nil || vc.Display == nil {
+		return DefaultFieldTruncate
+	}
+	if typeName != "" {
+		if limit, ok := vc.Display.Fields[typeName+"."+field]; ok {
+			return max(limit, 0)
+		}
+	}
+	if limit, ok := vc.Display.Fields[field]; ok {
+		return max(limit, 0)
+	}
+	if vc.Display.Truncate != nil {
+		return max(*vc.Display.Truncate, 0)
+	}
+	return DefaultFieldTruncate
+}
+
 // GetDeletionConfig returns the deletion config with defaults applied.
 func (vc *VaultConfig) GetDeletionConfig() *DeletionConfig {
 	if vc.Deletion == nil {
@@ -233,6 +923,82 @@ func (vc *VaultConfig) GetExcludePatterns() []string {
 	return ravenignore.NormalizePatterns(vc.Exclude)
 }
 
+// GetSparsePaths returns the configured sparse directories as normalized
+// directory roots (e.g., "archive/").
+func (vc *VaultConfig) GetSparsePaths() []string {
+	if vc == nil {
+		return nil
+	}
+	roots := make([]string, 0, len(vc.SparsePaths))
+	for _, p := range vc.SparsePaths {
+		p = paths.NormalizeVaultRelPath(p)
+		if p == "" || p == "." {
+			continue
+		}
+		roots = append(roots, paths.NormalizeDirRoot(p))
+	}
+	return roots
+}
+
+// AbsentSparsePaths returns the sparse directories that are not present in the
+// local checkout of the vault at vaultPath.
+func (vc *VaultConfig) AbsentSparsePaths(vaultPath string) []string {
+	var absent []string
+	for _, root := range vc.GetSparsePaths() {
+		info, err := os.Stat(filepath.Join(vaultPath, filepath.FromSlash(root)))
+		if err != nil || !info.IsDir() {
+			absent = append(absent, root)
+		}
+	}
+	return absent
+}
+
+// GetLockedFiles returns the configured locked files as normalized
+// vault-relative paths.
+func (vc *VaultConfig) GetLockedFiles() []string {
+	if vc == nil {
+		return nil
+	}
+	locked := make([]string, 0, len(vc.LockedFiles))
+	for _, p := range vc.LockedFiles {
+		p = paths.NormalizeVaultRelPath(p)
+		if p == "" || p == "." {
+			continue
+		}
+		locked = append(locked, p)
+	}
+	return locked
+}
+
+// IsLockedPath reports whether the vault-relative relPath is a locked file.
+func (vc *VaultConfig) IsLockedPath(relPath string) bool {
+	relPath = paths.NormalizeVaultRelPath(relPath)
+	for _, locked := range vc.GetLockedFiles() {
+		if locked == relPath {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutLocks returns a shallow copy of the config that ignores locked_files
+// and `locked: true` frontmatter. Commands use it to honor --unlock for a
+// single invocation.
+func (vc *VaultConfig) WithoutLocks() *VaultConfig {
+	if vc == nil {
+		return nil
+	}
+	unlocked := *vc
+	unlocked.LockedFiles = nil
+	unlocked.ignoreLocks = true
+	return &unlocked
+}
+
+// LocksIgnored reports whether the config came from WithoutLocks.
+func (vc *VaultConfig) LocksIgnored() bool {
+	return vc != nil && vc.ignoreLocks
+}
+
 // CaptureConfig defines settings for quick capture via `rvn add`.
 type CaptureConfig struct {
 	// Destination where captures are appended.
@@ -350,6 +1116,38 @@ type SavedQuery struct {
 	// Options stores default rvn query flags for this saved query. Pointer
 	// fields distinguish an omitted default from an explicit false/zero value.
 	Options *QueryOptions `yaml:"options,omitempty"`
+
+	// Snapshot renders this query's results into a note when
+	// `rvn query snapshot` runs.
+	Snapshot *QuerySnapshot `yaml:"snapshot,omitempty"`
+}
+
+// QuerySnapshot configures rendering a saved query's results into a note.
+type QuerySnapshot struct {
+	// Target is the note (reference or vault-relative path) that receives
+	// the rendered results.
+	Target string `yaml:"target" json:"target"`
+
+	// Schedule is the minimum interval between refreshes when running
+	// `rvn query snapshot --due`: hourly, daily, weekly, or a count with an
+	// m, h, d or w suffix (e.g. "6h"). Empty means explicit runs only.
+	Schedule string `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+}
+
+// DashboardSection is one saved query shown by `rvn dashboard`.
+type DashboardSection struct {
+	// Query is the saved query name.
+	Query string `yaml:"query"`
+
+	// Args are positional inputs for saved queries that declare args.
+	Args []string `yaml:"args,omitempty"`
+
+	// Title labels the section; it defaults to the query name.
+	Title string `yaml:"title,omitempty"`
+
+	// Limit caps the rows shown (default: 10). The section count is always
+	// the full number of matches.
+	Limit int `yaml:"limit,omitempty"`
 }
 
 // QueryOptions stores default `rvn query` flags for saved queries.
@@ -363,6 +1161,7 @@ type QueryOptions struct {
 	Confirm   *bool    `yaml:"confirm,omitempty" json:"confirm,omitempty"`
 	Pipe      *bool    `yaml:"pipe,omitempty" json:"pipe,omitempty"`
 	Browse    *bool    `yaml:"browse,omitempty" json:"browse,omitempty"`
+	Full      *bool    `yaml:"full,omitempty" json:"full,omitempty"`
 }
 
 // IsEmpty reports whether no saved query option defaults are set.
@@ -378,7 +1177,8 @@ func (o *QueryOptions) IsEmpty() bool {
 		len(o.Apply) == 0 &&
 		o.Confirm == nil &&
 		o.Pipe == nil &&
-		o.Browse == nil
+		o.Browse == nil &&
+		o.Full == nil
 }
 
 // DefaultVaultConfig returns the default vault configuration.
@@ -455,6 +1255,62 @@ func SaveVaultConfig(vaultPath string, cfg *VaultConfig) error {
 	return nil
 }
 
+// StampSchema records stamp under schema_stamp in raven.yaml, leaving the
+// rest of the file, including comments, as written. Vaults without a
+// raven.yaml are left alone.
+func StampSchema(vaultPath string, stamp SchemaStamp) error {
+	configPath := filepath.Join(vaultPath, "raven.yaml")
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read raven.yaml: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse raven.yaml: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("raven.yaml must be a mapping")
+	}
+
+	var value yaml.Node
+	if err := value.Encode(stamp); err != nil {
+		return fmt.Errorf("failed to encode schema stamp: %w", err)
+	}
+	replaced := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "schema_stamp" {
+			root.Content[i+1] = &value
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "schema_stamp"}, &value)
+	}
+
+	var buf strings.Builder
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to marshal raven.yaml: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to marshal raven.yaml: %w", err)
+	}
+	if err := atomicfile.WriteFile(configPath, []byte(buf.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write raven.yaml: %w", err)
+	}
+	return nil
+}
+
 // DailyNotePath returns the full path for a daily note given a date string (YYYY-MM-DD).
 func (vc *VaultConfig) DailyNotePath(vaultPath, date string) string {
 	return filepath.Join(vaultPath, vc.GetDailyDirectory(), date+".md")
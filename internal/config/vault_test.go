@@ -4,6 +4,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/aidanlsb/raven/internal/query"
@@ -201,6 +202,95 @@ func TestDirectoriesConfigAssets(t *testing.T) {
 		t.Fatalf("directories.assets = %q, want resources/assets/", dirs.Assets)
 	}
 }
+func TestSparsePaths(t *testing.T) {
+	t.Parallel()
+
+	vaultPath := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(vaultPath, "work"), 0o755); err != nil {
+		t.Fatalf("mkdir work: %v", err)
+	}
+
+	cfg := &VaultConfig{SparsePaths: []string{"./work", "archive/", " ", "/team/shared/"}}
+	if got, want := cfg.GetSparsePaths(), []string{"work/", "archive/", "team/shared/"}; !slices.Equal(got, want) {
+		t.Fatalf("sparse paths = %v, want %v", got, want)
+	}
+	if got, want := cfg.AbsentSparsePaths(vaultPath), []string{"archive/", "team/shared/"}; !slices.Equal(got, want) {
+		t.Fatalf("absent sparse paths = %v, want %v", got, want)
+	}
+}
+
+func TestLockedFiles(t *testing.T) {
+	t.Parallel()
+
+	cfg := &VaultConfig{LockedFiles: []string{"./reference/style.md", " ", "/notes/canon.md"}}
+	if got, want := cfg.GetLockedFiles(), []string{"reference/style.md", "notes/canon.md"}; !slices.Equal(got, want) {
+		t.Fatalf("locked files = %v, want %v", got, want)
+	}
+	if !cfg.IsLockedPath("notes/canon.md") || !cfg.IsLockedPath("./reference/style.md") {
+		t.Fatal("expected configured files to be locked")
+	}
+	if cfg.IsLockedPath("notes/other.md") {
+		t.Fatal("unexpected lock on unlisted file")
+	}
+	if cfg.WithoutLocks().IsLockedPath("notes/canon.md") {
+		t.Fatal("WithoutLocks should ignore locked files")
+	}
+	if !cfg.IsLockedPath("notes/canon.md") {
+		t.Fatal("WithoutLocks must not modify the original config")
+	}
+}
+
+func TestDateMentionFormats(t *testing.T) {
+	t.Parallel()
+
+	if got := (&VaultConfig{}).GetDateMentionFormats(); got != nil {
+		t.Fatalf("expected nil formats when date_links is unset, got %v", got)
+	}
+	if got := (&VaultConfig{DateLinks: &DateLinksConfig{Formats: []string{"MM/DD/YYYY"}}}).GetDateMentionFormats(); got != nil {
+		t.Fatalf("expected nil formats when date_links is disabled, got %v", got)
+	}
+	got := (&VaultConfig{DateLinks: &DateLinksConfig{Enabled: true}}).GetDateMentionFormats()
+	if got == nil || len(got) != 0 {
+		t.Fatalf("expected empty non-nil formats for ISO-only linking, got %#v", got)
+	}
+	cfg := &VaultConfig{DateLinks: &DateLinksConfig{Enabled: true, Formats: []string{"MM/DD/YYYY"}}}
+	if got, want := cfg.GetDateMentionFormats(), []string{"MM/DD/YYYY"}; !slices.Equal(got, want) {
+		t.Fatalf("formats = %v, want %v", got, want)
+	}
+}
+
+func TestFieldTruncateLimit(t *testing.T) {
+	t.Parallel()
+
+	if got := (&VaultConfig{}).FieldTruncateLimit("project", "summary"); got != DefaultFieldTruncate {
+		t.Fatalf("default limit = %d, want %d", got, DefaultFieldTruncate)
+	}
+
+	defaultLimit := 40
+	cfg := &VaultConfig{Display: &DisplayConfig{
+		Truncate: &defaultLimit,
+		Fields: map[string]int{
+			"summary":       120,
+			"project.notes": 0,
+			"notes":         10,
+		},
+	}}
+	tests := []struct {
+		typeName, field string
+		want            int
+	}{
+		{typeName: "project", field: "status", want: 40},
+		{typeName: "project", field: "summary", want: 120},
+		{typeName: "project", field: "notes", want: 0},
+		{typeName: "meeting", field: "notes", want: 10},
+	}
+	for _, tt := range tests {
+		if got := cfg.FieldTruncateLimit(tt.typeName, tt.field); got != tt.want {
+			t.Errorf("FieldTruncateLimit(%q, %q) = %d, want %d", tt.typeName, tt.field, got, tt.want)
+		}
+	}
+}
+
 func TestVaultConfigPaths(t *testing.T) {
 	cfg := &VaultConfig{
 		DailyDirectory: "daily",
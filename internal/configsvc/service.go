@@ -108,6 +108,12 @@ func (ctx *GlobalConfigContext) Data() map[string]interface{} {
 			"accent":         strings.TrimSpace(ctx.Cfg.UI.Accent),
 			"code_theme":     strings.TrimSpace(ctx.Cfg.UI.CodeTheme),
 			"markdown_style": strings.TrimSpace(ctx.Cfg.UI.MarkdownStyle),
+			"hyperlinks":     ctx.Cfg.UI.HyperlinksEnabled(),
+			"colors": map[string]interface{}{
+				"success": strings.TrimSpace(ctx.Cfg.UI.Colors.Success),
+				"warning": strings.TrimSpace(ctx.Cfg.UI.Colors.Warning),
+				"error":   strings.TrimSpace(ctx.Cfg.UI.Colors.Error),
+			},
 		},
 	}
 }
@@ -49,6 +49,36 @@ func ParseDate(s string) (time.Time, error) {
 	return time.Parse(DateLayout, s)
 }
 
+// looseDateLayouts are the unambiguous date spellings CanonicalizeDate accepts.
+// Day-first and month-first slash forms (01/02/2025) are deliberately excluded.
+var looseDateLayouts = []string{
+	DateLayout,
+	"2006-1-2",
+	"2006/1/2",
+	"2006.1.2",
+	"Jan 2, 2006",
+	"Jan 2 2006",
+	"January 2, 2006",
+	"January 2 2006",
+	"2 Jan 2006",
+	"2 January 2006",
+}
+
+// CanonicalizeDate rewrites a loosely formatted date as YYYY-MM-DD. It reports
+// false when s is not a recognizable date.
+func CanonicalizeDate(s string) (string, bool) {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return "", false
+	}
+	for _, layout := range looseDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format(DateLayout), true
+		}
+	}
+	return "", false
+}
+
 // IsValidDatetime checks if a string is a valid datetime.
 //
 // Accepted formats (preserving current behavior):
@@ -44,6 +44,32 @@ func TestIsValidDatetime(t *testing.T) {
 	}
 }
 
+func TestCanonicalizeDate(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"2025-02-01":           "2025-02-01",
+		"2025-2-1":             "2025-02-01",
+		"2025/02/01":           "2025-02-01",
+		"2025.2.1":             "2025-02-01",
+		"Feb 1, 2025":          "2025-02-01",
+		"february 1 2025":      "2025-02-01",
+		"1 Feb 2025":           "2025-02-01",
+		"  1   February 2025 ": "2025-02-01",
+	}
+	for input, want := range tests {
+		got, ok := CanonicalizeDate(input)
+		if !ok || got != want {
+			t.Fatalf("CanonicalizeDate(%q) = %q, %v; want %q", input, got, ok, want)
+		}
+	}
+
+	for _, input := range []string{"", "01/02/2025", "2025-02-30", "soon"} {
+		if got, ok := CanonicalizeDate(input); ok {
+			t.Fatalf("CanonicalizeDate(%q) = %q, want no match", input, got)
+		}
+	}
+}
+
 func TestParseDateArg(t *testing.T) {
 	t.Parallel()
 	now := time.Date(2025, 2, 15, 10, 0, 0, 0, time.UTC)
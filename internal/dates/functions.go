@@ -0,0 +1,165 @@
+package dates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateFunctions are the date functions accepted by ResolveDateFunction, keyed
+// by their lowercase name.
+var dateFunctions = map[string]struct{}{
+	"date":         {},
+	"within":       {},
+	"startofweek":  {},
+	"endofweek":    {},
+	"startofmonth": {},
+	"endofmonth":   {},
+	"startofyear":  {},
+	"endofyear":    {},
+}
+
+// IsDateFunction reports whether name (case-insensitive) is a date function
+// such as date, within, or startOfMonth.
+func IsDateFunction(name string) bool {
+	_, ok := dateFunctions[strings.ToLower(strings.TrimSpace(name))]
+	return ok
+}
+
+// FormatDateFunction returns the canonical spelling of a date function call.
+func FormatDateFunction(name, arg string) string {
+	return strings.ToLower(strings.TrimSpace(name)) + "(" + strings.ToLower(strings.TrimSpace(arg)) + ")"
+}
+
+// ResolveDateFunction resolves a date function call against now:
+//
+//   - date(+7d), date(-2w), date(1m): today shifted by an offset
+//   - startofweek(), endofmonth(-1m), ...: the boundary day of the period
+//     containing today, optionally shifted by an offset first
+//   - within(3d), within(-1w): the range from today to today plus the offset
+//
+// Offsets are a signed count with a d, w, m, or y unit. Function names are
+// case-insensitive. It returns ok=false when value is not a date function call
+// and an error when a date function has a malformed argument.
+func ResolveDateFunction(value string, now time.Time, weekStart time.Weekday) (RelativeDateResolution, bool, error) {
+	name, arg, ok := splitFunctionCall(value)
+	if !ok || !IsDateFunction(name) {
+		return RelativeDateResolution{}, false, nil
+	}
+	keyword := FormatDateFunction(name, arg)
+
+	offset := dateOffset{}
+	if arg != "" {
+		parsed, err := parseDateOffset(arg)
+		if err != nil {
+			return RelativeDateResolution{}, false, fmt.Errorf("invalid offset %q in %s: use a signed count with d, w, m, or y (e.g. +7d)", arg, keyword)
+		}
+		offset = parsed
+	}
+
+	today := startOfDay(now)
+	switch name {
+	case "date":
+		return instantResolution(keyword, offset.apply(today)), true, nil
+	case "within":
+		if arg == "" || offset.n == 0 {
+			return RelativeDateResolution{}, false, fmt.Errorf("%s needs a non-zero offset such as within(3d)", keyword)
+		}
+		start, end := today, offset.apply(today)
+		if end.Before(start) {
+			start, end = end, start
+		}
+		return RelativeDateResolution{Keyword: keyword, Kind: RelativeDateRange, Date: start, End: end}, true, nil
+	}
+
+	anchor := offset.apply(today)
+	var day time.Time
+	switch name {
+	case "startofweek":
+		day = startOfWeek(anchor, weekStart)
+	case "endofweek":
+		day = startOfWeek(anchor, weekStart).AddDate(0, 0, 6)
+	case "startofmonth":
+		day = time.Date(anchor.Year(), anchor.Month(), 1, 0, 0, 0, 0, anchor.Location())
+	case "endofmonth":
+		day = time.Date(anchor.Year(), anchor.Month()+1, 0, 0, 0, 0, 0, anchor.Location())
+	case "startofyear":
+		day = time.Date(anchor.Year(), time.January, 1, 0, 0, 0, 0, anchor.Location())
+	case "endofyear":
+		day = time.Date(anchor.Year(), time.December, 31, 0, 0, 0, 0, anchor.Location())
+	}
+	return instantResolution(keyword, day), true, nil
+}
+
+// splitFunctionCall splits "name(arg)" into its lowercase name and trimmed arg.
+func splitFunctionCall(value string) (string, string, bool) {
+	value = strings.TrimSpace(value)
+	open := strings.IndexByte(value, '(')
+	if open <= 0 || !strings.HasSuffix(value, ")") {
+		return "", "", false
+	}
+	name := strings.ToLower(strings.TrimSpace(value[:open]))
+	arg := strings.ToLower(strings.TrimSpace(value[open+1 : len(value)-1]))
+	return name, arg, true
+}
+
+// ShiftDate shifts t by an offset such as +7d, -2w, 1m, or +1y. Month and
+// year offsets clamp to the last day of the target month.
+func ShiftDate(t time.Time, offset string) (time.Time, error) {
+	parsed, err := parseDateOffset(strings.ToLower(strings.TrimSpace(offset)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid offset %q: use a signed count with d, w, m, or y (e.g. +7d)", offset)
+	}
+	return parsed.apply(t), nil
+}
+
+type dateOffset struct {
+	n    int
+	unit byte
+}
+
+func parseDateOffset(value string) (dateOffset, error) {
+	if len(value) < 2 {
+		return dateOffset{}, fmt.Errorf("invalid offset")
+	}
+	unit := value[len(value)-1]
+	switch unit {
+	case 'd', 'w', 'm', 'y':
+	default:
+		return dateOffset{}, fmt.Errorf("invalid offset unit")
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil {
+		return dateOffset{}, err
+	}
+	return dateOffset{n: n, unit: unit}, nil
+}
+
+// apply shifts t by the offset. Month and year offsets clamp to the last day
+// of the target month, so date(+1m) on January 31 is the end of February.
+func (o dateOffset) apply(t time.Time) time.Time {
+	switch o.unit {
+	case 'd':
+		return t.AddDate(0, 0, o.n)
+	case 'w':
+		return t.AddDate(0, 0, 7*o.n)
+	case 'm':
+		return addMonthsClamped(t, o.n)
+	case 'y':
+		return addMonthsClamped(t, 12*o.n)
+	default:
+		return t
+	}
+}
+
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), lastDay)-1)
+}
+
+func startOfWeek(t time.Time, weekStart time.Weekday) time.Time {
+	diff := (int(t.Weekday()) - int(weekStart) + 7) % 7
+	return startOfDay(t).AddDate(0, 0, -diff)
+}
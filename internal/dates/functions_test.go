@@ -0,0 +1,55 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveDateFunction(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, time.January, 31, 14, 30, 0, 0, time.UTC) // Saturday
+
+	tests := []struct {
+		value string
+		want  string
+		end   string
+	}{
+		{value: "date(+7d)", want: "2026-02-07"},
+		{value: "date(-2w)", want: "2026-01-17"},
+		{value: "date()", want: "2026-01-31"},
+		{value: "date(+1m)", want: "2026-02-28"},
+		{value: "date(-1y)", want: "2025-01-31"},
+		{value: "startofweek()", want: "2026-01-26"},
+		{value: "endOfWeek()", want: "2026-02-01"},
+		{value: "startOfMonth()", want: "2026-01-01"},
+		{value: "endofmonth(+1m)", want: "2026-02-28"},
+		{value: "startofmonth(-1m)", want: "2025-12-01"},
+		{value: "startofyear()", want: "2026-01-01"},
+		{value: "endofyear()", want: "2026-12-31"},
+		{value: "within(3d)", want: "2026-01-31", end: "2026-02-03"},
+		{value: "within(-1w)", want: "2026-01-24", end: "2026-01-31"},
+	}
+	for _, tt := range tests {
+		got, ok, err := ResolveDateFunction(tt.value, now, time.Monday)
+		if err != nil || !ok {
+			t.Fatalf("ResolveDateFunction(%q) = ok %v, err %v", tt.value, ok, err)
+		}
+		if got.Date.Format(DateLayout) != tt.want {
+			t.Errorf("ResolveDateFunction(%q) = %s, want %s", tt.value, got.Date.Format(DateLayout), tt.want)
+		}
+		if tt.end != "" && (got.Kind != RelativeDateRange || got.End.Format(DateLayout) != tt.end) {
+			t.Errorf("ResolveDateFunction(%q) range end = %s (kind %v), want %s", tt.value, got.End.Format(DateLayout), got.Kind, tt.end)
+		}
+	}
+
+	for _, value := range []string{"today", "Notes (draft)", "2026-01-01"} {
+		if _, ok, err := ResolveDateFunction(value, now, time.Monday); ok || err != nil {
+			t.Errorf("ResolveDateFunction(%q) = ok %v, err %v; want not a date function", value, ok, err)
+		}
+	}
+	for _, value := range []string{"date(7)", "date(+7x)", "within()", "within(0d)"} {
+		if _, _, err := ResolveDateFunction(value, now, time.Monday); err == nil {
+			t.Errorf("ResolveDateFunction(%q) succeeded, want error", value)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package dates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PeriodKind names a span of days longer than one: an ISO week, a calendar
+// month, or a calendar quarter.
+type PeriodKind string
+
+const (
+	PeriodWeek    PeriodKind = "week"
+	PeriodMonth   PeriodKind = "month"
+	PeriodQuarter PeriodKind = "quarter"
+)
+
+// PeriodKinds lists the supported period kinds, shortest first.
+var PeriodKinds = []PeriodKind{PeriodWeek, PeriodMonth, PeriodQuarter}
+
+// ParsePeriodKind parses "week", "month", or "quarter" (also "weekly",
+// "monthly", and "quarterly").
+func ParsePeriodKind(value string) (PeriodKind, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "week", "weekly":
+		return PeriodWeek, true
+	case "month", "monthly":
+		return PeriodMonth, true
+	case "quarter", "quarterly":
+		return PeriodQuarter, true
+	default:
+		return "", false
+	}
+}
+
+// Period is one week, month, or quarter. Start and End are its first and
+// last days.
+type Period struct {
+	Kind  PeriodKind
+	Start time.Time
+	End   time.Time
+}
+
+// PeriodContaining returns the period of the given kind that contains date.
+// Weeks run Monday through Sunday, as in ISO 8601.
+func PeriodContaining(kind PeriodKind, date time.Time) Period {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	var start time.Time
+	switch kind {
+	case PeriodWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		start = day.AddDate(0, 0, -offset)
+	case PeriodMonth:
+		start = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		kind = PeriodQuarter
+		firstMonth := time.Month((int(day.Month())-1)/3*3 + 1)
+		start = time.Date(day.Year(), firstMonth, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return Period{Kind: kind, Start: start, End: periodEnd(kind, start)}
+}
+
+func periodEnd(kind PeriodKind, start time.Time) time.Time {
+	switch kind {
+	case PeriodWeek:
+		return start.AddDate(0, 0, 6)
+	case PeriodMonth:
+		return start.AddDate(0, 1, -1)
+	default:
+		return start.AddDate(0, 3, -1)
+	}
+}
+
+// Shift returns the period n periods later (earlier when n is negative).
+func (p Period) Shift(n int) Period {
+	var start time.Time
+	switch p.Kind {
+	case PeriodWeek:
+		start = p.Start.AddDate(0, 0, 7*n)
+	case PeriodMonth:
+		start = p.Start.AddDate(0, n, 0)
+	default:
+		start = p.Start.AddDate(0, 3*n, 0)
+	}
+	return Period{Kind: p.Kind, Start: start, End: periodEnd(p.Kind, start)}
+}
+
+// Key formats the period as 2026-W07, 2026-02, or 2026-Q1.
+func (p Period) Key() string {
+	switch p.Kind {
+	case PeriodWeek:
+		year, week := p.Start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case PeriodMonth:
+		return p.Start.Format("2006-01")
+	default:
+		return fmt.Sprintf("%d-Q%d", p.Start.Year(), (int(p.Start.Month())-1)/3+1)
+	}
+}
+
+// Title formats the period for headings, e.g. "Week 7, 2026",
+// "February 2026", or "Q1 2026".
+func (p Period) Title() string {
+	switch p.Kind {
+	case PeriodWeek:
+		year, week := p.Start.ISOWeek()
+		return fmt.Sprintf("Week %d, %d", week, year)
+	case PeriodMonth:
+		return p.Start.Format("January 2006")
+	default:
+		return fmt.Sprintf("Q%d %d", (int(p.Start.Month())-1)/3+1, p.Start.Year())
+	}
+}
+
+// Contains reports whether date (YYYY-MM-DD) falls within the period.
+func (p Period) Contains(date string) bool {
+	return date >= p.Start.Format(DateLayout) && date <= p.End.Format(DateLayout)
+}
+
+// ParsePeriodKey parses a period key: 2026-W07 (ISO week), 2026-02 (month),
+// or 2026-Q1 (quarter). Matching is case-insensitive.
+func ParsePeriodKey(value string) (Period, bool) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	yearPart, rest, ok := strings.Cut(value, "-")
+	if !ok || len(yearPart) != 4 {
+		return Period{}, false
+	}
+	year, err := strconv.Atoi(yearPart)
+	if err != nil {
+		return Period{}, false
+	}
+
+	switch {
+	case strings.HasPrefix(rest, "W") && len(rest) == 3:
+		week, err := strconv.Atoi(rest[1:])
+		if err != nil || week < 1 {
+			return Period{}, false
+		}
+		// January 4 is always in ISO week 1.
+		first := PeriodContaining(PeriodWeek, time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC))
+		p := first.Shift(week - 1)
+		if y, _ := p.Start.ISOWeek(); y != year {
+			return Period{}, false
+		}
+		return p, true
+	case strings.HasPrefix(rest, "Q") && len(rest) == 2:
+		quarter, err := strconv.Atoi(rest[1:])
+		if err != nil || quarter < 1 || quarter > 4 {
+			return Period{}, false
+		}
+		return PeriodContaining(PeriodQuarter, time.Date(year, time.Month(quarter*3-2), 1, 0, 0, 0, 0, time.UTC)), true
+	case len(rest) == 2:
+		month, err := strconv.Atoi(rest)
+		if err != nil || month < 1 || month > 12 {
+			return Period{}, false
+		}
+		return PeriodContaining(PeriodMonth, time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)), true
+	default:
+		return Period{}, false
+	}
+}
+
+// ParsePeriodPhrase parses "this week", "last month", "next quarter", and
+// the same with a hyphen ("this-week").
+func ParsePeriodPhrase(value string, now time.Time) (Period, bool) {
+	fields := strings.Fields(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(value)), "-", " "))
+	if len(fields) != 2 {
+		return Period{}, false
+	}
+	kind, ok := ParsePeriodKind(fields[1])
+	if !ok {
+		return Period{}, false
+	}
+	offset, ok := periodOffsets[fields[0]]
+	if !ok {
+		return Period{}, false
+	}
+	return PeriodContaining(kind, now).Shift(offset), true
+}
+
+var periodOffsets = map[string]int{
+	"this":     0,
+	"current":  0,
+	"last":     -1,
+	"previous": -1,
+	"next":     1,
+}
+
+// ParsePeriodArg resolves the period of a given kind named by value: empty or
+// "this" for the current period, "last"/"next", a phrase such as "last week",
+// a period key of the same kind, or any date ParseDateArg accepts, which
+// selects the period containing it.
+func ParsePeriodArg(kind PeriodKind, value string, now time.Time) (Period, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	current := PeriodContaining(kind, now)
+	if normalized == "" {
+		return current, nil
+	}
+	if offset, ok := periodOffsets[normalized]; ok {
+		return current.Shift(offset), nil
+	}
+	if p, ok := ParsePeriodPhrase(normalized, now); ok {
+		if p.Kind != kind {
+			return Period{}, fmt.Errorf("%q is not a %s", value, kind)
+		}
+		return p, nil
+	}
+	if p, ok := ParsePeriodKey(normalized); ok {
+		if p.Kind != kind {
+			return Period{}, fmt.Errorf("%q is not a %s", value, kind)
+		}
+		return p, nil
+	}
+	date, err := ParseDateArg(normalized, now)
+	if err != nil {
+		return Period{}, fmt.Errorf("invalid %s %q: use this, last, next, %s, or a date", kind, value, current.Key())
+	}
+	return PeriodContaining(kind, date), nil
+}
@@ -0,0 +1,67 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePeriodArg(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, time.February, 12, 10, 0, 0, 0, time.UTC) // Thursday, 2026-W07
+
+	tests := []struct {
+		kind    PeriodKind
+		value   string
+		key     string
+		start   string
+		end     string
+		wantErr bool
+	}{
+		{kind: PeriodWeek, value: "", key: "2026-W07", start: "2026-02-09", end: "2026-02-15"},
+		{kind: PeriodWeek, value: "last", key: "2026-W06", start: "2026-02-02", end: "2026-02-08"},
+		{kind: PeriodWeek, value: "next week", key: "2026-W08", start: "2026-02-16", end: "2026-02-22"},
+		{kind: PeriodWeek, value: "2026-w01", key: "2026-W01", start: "2025-12-29", end: "2026-01-04"},
+		{kind: PeriodWeek, value: "2026-01-01", key: "2026-W01", start: "2025-12-29", end: "2026-01-04"},
+		{kind: PeriodWeek, value: "2027-W53", wantErr: true},
+		{kind: PeriodMonth, value: "this-month", key: "2026-02", start: "2026-02-01", end: "2026-02-28"},
+		{kind: PeriodMonth, value: "2026-12", key: "2026-12", start: "2026-12-01", end: "2026-12-31"},
+		{kind: PeriodMonth, value: "2026-Q1", wantErr: true},
+		{kind: PeriodQuarter, value: "next", key: "2026-Q2", start: "2026-04-01", end: "2026-06-30"},
+		{kind: PeriodQuarter, value: "2025-11-30", key: "2025-Q4", start: "2025-10-01", end: "2025-12-31"},
+		{kind: PeriodQuarter, value: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		p, err := ParsePeriodArg(tt.kind, tt.value, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParsePeriodArg(%s, %q) = %s, want error", tt.kind, tt.value, p.Key())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePeriodArg(%s, %q): %v", tt.kind, tt.value, err)
+			continue
+		}
+		if p.Key() != tt.key || p.Start.Format(DateLayout) != tt.start || p.End.Format(DateLayout) != tt.end {
+			t.Errorf("ParsePeriodArg(%s, %q) = %s %s..%s, want %s %s..%s", tt.kind, tt.value,
+				p.Key(), p.Start.Format(DateLayout), p.End.Format(DateLayout), tt.key, tt.start, tt.end)
+		}
+	}
+}
+
+func TestPeriodTitle(t *testing.T) {
+	t.Parallel()
+	for key, want := range map[string]string{
+		"2026-W07": "Week 7, 2026",
+		"2026-02":  "February 2026",
+		"2026-Q3":  "Q3 2026",
+	} {
+		p, ok := ParsePeriodKey(key)
+		if !ok {
+			t.Fatalf("ParsePeriodKey(%q) failed", key)
+		}
+		if got := p.Title(); got != want {
+			t.Errorf("%s title = %q, want %q", key, got, want)
+		}
+	}
+}
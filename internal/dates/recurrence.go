@@ -0,0 +1,227 @@
+package dates
+
+import (
+	"strings"
+	"time"
+)
+
+// RecurrencePrefix introduces a repeat rule in a date value, as in
+// every:friday.
+const RecurrencePrefix = "every:"
+
+// RepeatPrefix introduces a repeat rule after a start date, as in
+// "2026-03-01, repeat:monthly".
+const RepeatPrefix = "repeat:"
+
+// repeatRules maps repeat: rule names to every: rules.
+var repeatRules = map[string]string{
+	"daily":    "day",
+	"weekdays": "weekday",
+	"weekly":   "week",
+	"monthly":  "month",
+	"yearly":   "year",
+	"annually": "year",
+}
+
+// recurrenceWeekdays maps weekday rules to their weekday.
+var recurrenceWeekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// recurrenceIntervals are the rules that repeat a fixed step after the last
+// occurrence.
+var recurrenceIntervals = map[string]bool{
+	"day":   true,
+	"week":  true,
+	"month": true,
+	"year":  true,
+}
+
+// RecurringDate is a date value that repeats, written as "every:<rule>",
+// "YYYY-MM-DD every:<rule>", or "YYYY-MM-DD, repeat:<rule>". The date is the
+// next occurrence; without one the first occurrence is the first match on or
+// after today.
+//
+// Rules: day, weekday (Monday-Friday), week, month, year, or a weekday name.
+// repeat: also accepts daily, weekdays, weekly, monthly, yearly, and annually.
+type RecurringDate struct {
+	Date time.Time // Zero until the first occurrence is scheduled
+	Rule string
+
+	// written keeps the repeat: form so String round-trips it.
+	written string
+}
+
+// ParseRecurringDate parses a recurring date value. It returns false for
+// values without an every: or repeat: rule.
+func ParseRecurringDate(value string) (RecurringDate, bool) {
+	fields := strings.Fields(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(value)), ",", " "))
+	var r RecurringDate
+	switch len(fields) {
+	case 1:
+	case 2:
+		date, err := ParseDate(fields[0])
+		if err != nil {
+			return RecurringDate{}, false
+		}
+		r.Date = date
+		fields = fields[1:]
+	default:
+		return RecurringDate{}, false
+	}
+
+	if rule, ok := strings.CutPrefix(fields[0], RepeatPrefix); ok {
+		if mapped, ok := repeatRules[rule]; ok {
+			r.Rule, r.written = mapped, rule
+			return r, true
+		}
+		if isRecurrenceRule(rule) {
+			r.Rule, r.written = rule, rule
+			return r, true
+		}
+		return RecurringDate{}, false
+	}
+	rule, ok := strings.CutPrefix(fields[0], RecurrencePrefix)
+	if !ok || !isRecurrenceRule(rule) {
+		return RecurringDate{}, false
+	}
+	r.Rule = rule
+	return r, true
+}
+
+// IsRecurringDate reports whether value is a valid recurring date.
+func IsRecurringDate(value string) bool {
+	_, ok := ParseRecurringDate(value)
+	return ok
+}
+
+// ParseWeekday parses a lowercase or capitalized weekday name.
+func ParseWeekday(name string) (time.Weekday, bool) {
+	day, ok := recurrenceWeekdays[strings.ToLower(strings.TrimSpace(name))]
+	return day, ok
+}
+
+func isRecurrenceRule(rule string) bool {
+	if _, ok := recurrenceWeekdays[rule]; ok {
+		return true
+	}
+	return rule == "weekday" || recurrenceIntervals[rule]
+}
+
+// Due returns the date of the pending occurrence: the scheduled date, or the
+// first match on or after today.
+func (r RecurringDate) Due(today time.Time) time.Time {
+	if !r.Date.IsZero() {
+		return r.Date
+	}
+	today = startOfDay(today)
+	if recurrenceIntervals[r.Rule] {
+		return today
+	}
+	return r.Next(today.AddDate(0, 0, -1))
+}
+
+// Next returns the first occurrence strictly after from.
+func (r RecurringDate) Next(from time.Time) time.Time {
+	from = startOfDay(from)
+	switch r.Rule {
+	case "day":
+		return from.AddDate(0, 0, 1)
+	case "week":
+		return from.AddDate(0, 0, 7)
+	case "month":
+		return addMonthsClamped(from, 1)
+	case "year":
+		return addMonthsClamped(from, 12)
+	}
+	for next := from.AddDate(0, 0, 1); ; next = next.AddDate(0, 0, 1) {
+		if r.matches(next.Weekday()) {
+			return next
+		}
+	}
+}
+
+func (r RecurringDate) matches(day time.Weekday) bool {
+	if r.Rule == "weekday" {
+		return day != time.Saturday && day != time.Sunday
+	}
+	return recurrenceWeekdays[r.Rule] == day
+}
+
+// WithDate returns the rule scheduled for date.
+func (r RecurringDate) WithDate(date time.Time) RecurringDate {
+	r.Date = startOfDay(date)
+	return r
+}
+
+// Occurrences returns the occurrences that fall within [from, through].
+// Anchored interval rules step from their date, so a monthly series started
+// on the 31st stays on the last day of shorter months.
+func (r RecurringDate) Occurrences(from, through time.Time) []time.Time {
+	from, through = startOfDay(from), startOfDay(through)
+	if !r.Date.IsZero() {
+		// Compare calendar days in the series' location.
+		loc := r.Date.Location()
+		from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+		through = time.Date(through.Year(), through.Month(), through.Day(), 0, 0, 0, 0, loc)
+	}
+	start := r.Due(from)
+	if start.After(through) {
+		return nil
+	}
+
+	var out []time.Time
+	if recurrenceIntervals[r.Rule] {
+		for n := 0; ; n++ {
+			next := r.step(start, n)
+			if next.After(through) {
+				return out
+			}
+			if !next.Before(from) {
+				out = append(out, next)
+			}
+		}
+	}
+	if start.Before(from) {
+		start = r.Next(from.AddDate(0, 0, -1))
+	}
+	for next := start; !next.After(through); next = r.Next(next) {
+		out = append(out, next)
+	}
+	return out
+}
+
+// step returns the nth occurrence of an interval rule after start.
+func (r RecurringDate) step(start time.Time, n int) time.Time {
+	switch r.Rule {
+	case "day":
+		return start.AddDate(0, 0, n)
+	case "week":
+		return start.AddDate(0, 0, 7*n)
+	case "month":
+		return addMonthsClamped(start, n)
+	default:
+		return addMonthsClamped(start, 12*n)
+	}
+}
+
+// String formats the value as written in a trait, e.g. "2026-01-30 every:friday"
+// or "2026-03-01, repeat:monthly".
+func (r RecurringDate) String() string {
+	if r.written != "" {
+		if r.Date.IsZero() {
+			return RepeatPrefix + r.written
+		}
+		return r.Date.Format(DateLayout) + ", " + RepeatPrefix + r.written
+	}
+	if r.Date.IsZero() {
+		return RecurrencePrefix + r.Rule
+	}
+	return r.Date.Format(DateLayout) + " " + RecurrencePrefix + r.Rule
+}
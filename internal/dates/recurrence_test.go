@@ -0,0 +1,106 @@
+package dates
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRecurringDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{value: "every:friday", want: "every:friday", ok: true},
+		{value: "Every:Weekday", want: "every:weekday", ok: true},
+		{value: "2026-01-30 every:week", want: "2026-01-30 every:week", ok: true},
+		{value: "2026-03-01, repeat:monthly", want: "2026-03-01, repeat:monthly", ok: true},
+		{value: "2026-03-01 repeat:friday", want: "2026-03-01, repeat:friday", ok: true},
+		{value: "repeat:weekdays", want: "repeat:weekdays", ok: true},
+		{value: "2026-03-01, repeat:hourly"},
+		{value: "every:fortnight"},
+		{value: "2026-01-30"},
+		{value: "friday"},
+		{value: "2026-13-01 every:day"},
+	}
+	for _, tt := range tests {
+		got, ok := ParseRecurringDate(tt.value)
+		if ok != tt.ok {
+			t.Errorf("ParseRecurringDate(%q) ok = %v, want %v", tt.value, ok, tt.ok)
+			continue
+		}
+		if ok && got.String() != tt.want {
+			t.Errorf("ParseRecurringDate(%q) = %q, want %q", tt.value, got.String(), tt.want)
+		}
+	}
+}
+
+func TestRecurringDateDueAndNext(t *testing.T) {
+	t.Parallel()
+	today := time.Date(2026, time.January, 31, 14, 30, 0, 0, time.UTC) // Saturday
+
+	tests := []struct {
+		value string
+		due   string
+		next  string
+	}{
+		{value: "every:friday", due: "2026-02-06", next: "2026-02-13"},
+		{value: "every:saturday", due: "2026-01-31", next: "2026-02-07"},
+		{value: "every:weekday", due: "2026-02-02", next: "2026-02-03"},
+		{value: "every:day", due: "2026-01-31", next: "2026-02-01"},
+		{value: "every:month", due: "2026-01-31", next: "2026-02-28"},
+		{value: "2026-01-23 every:friday", due: "2026-01-23", next: "2026-01-30"},
+		{value: "2026-01-31 every:year", due: "2026-01-31", next: "2027-01-31"},
+	}
+	for _, tt := range tests {
+		r, ok := ParseRecurringDate(tt.value)
+		if !ok {
+			t.Fatalf("ParseRecurringDate(%q) failed", tt.value)
+		}
+		due := r.Due(today)
+		if got := due.Format(DateLayout); got != tt.due {
+			t.Errorf("%q due = %s, want %s", tt.value, got, tt.due)
+		}
+		if got := r.Next(due).Format(DateLayout); got != tt.next {
+			t.Errorf("%q next = %s, want %s", tt.value, got, tt.next)
+		}
+	}
+}
+
+func TestRecurringDateOccurrences(t *testing.T) {
+	t.Parallel()
+	from := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	through := time.Date(2026, time.May, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: "2026-01-31, repeat:monthly", want: []string{"2026-02-28", "2026-03-31", "2026-04-30", "2026-05-31"}},
+		{value: "2026-03-01, repeat:monthly", want: []string{"2026-03-01", "2026-04-01", "2026-05-01"}},
+		{value: "2026-01-15, repeat:yearly", want: nil},
+		{value: "2025-04-02 every:year", want: []string{"2026-04-02"}},
+		{value: "2026-05-20 every:week", want: []string{"2026-05-20", "2026-05-27"}},
+		{value: "2026-01-02 every:friday", want: []string{"2026-02-06", "2026-02-13"}},
+	}
+	for _, tt := range tests {
+		r, ok := ParseRecurringDate(tt.value)
+		if !ok {
+			t.Fatalf("ParseRecurringDate(%q) failed", tt.value)
+		}
+		end := through
+		if r.Rule == "friday" {
+			end = time.Date(2026, time.February, 13, 0, 0, 0, 0, time.UTC)
+		}
+		var got []string
+		for _, day := range r.Occurrences(from, end) {
+			got = append(got, day.Format(DateLayout))
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%q occurrences = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
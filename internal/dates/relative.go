@@ -11,6 +11,8 @@ type RelativeDateKind int
 const (
 	RelativeDateUnknown RelativeDateKind = iota
 	RelativeDateInstant
+	// RelativeDateRange spans Date through End, inclusive.
+	RelativeDateRange
 )
 
 // RelativeDateResolution is the resolved representation of a relative date keyword.
@@ -18,6 +20,7 @@ type RelativeDateResolution struct {
 	Keyword string
 	Kind    RelativeDateKind
 	Date    time.Time
+	End     time.Time // Last day of a RelativeDateRange; zero for instants
 }
 
 var relativeDateKeywords = map[string]struct{}{
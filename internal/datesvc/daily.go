@@ -0,0 +1,221 @@
+package datesvc
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/dates"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/objectsvc"
+	"github.com/aidanlsb/raven/internal/tasksvc"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+// Headings `rvn daily` appends to a new daily note.
+const (
+	CarriedOverHeading = "## Carried over"
+	DueTodayHeading    = "## Due today"
+)
+
+type AutomateDailyRequest struct {
+	VaultPath string
+	VaultCfg  *config.VaultConfig
+	DB        *index.Database
+	Daily     *EnsureDailyResult
+}
+
+type AutomateDailyResult struct {
+	CarriedOver []tasksvc.Task
+	Scheduled   []tasksvc.Task
+	// ChangedFiles are the absolute paths written: the new note and the
+	// notes tasks were carried out of.
+	ChangedFiles []string
+}
+
+// AutomateDaily fills a newly created daily note. Open tasks from daily notes
+// in the carry-over window are moved under CarriedOverHeading, and open tasks
+// due on the note's date elsewhere in the vault are linked under
+// DueTodayHeading.
+func AutomateDaily(req AutomateDailyRequest) (*AutomateDailyResult, error) {
+	dailyCfg := req.VaultCfg.GetDailyConfig()
+	tasksCfg := req.VaultCfg.GetTasksConfig()
+	noteDate, err := time.Parse(dates.DateLayout, req.Daily.Date)
+	if err != nil {
+		return nil, newError(CodeInvalidInput, err.Error(), "", err)
+	}
+
+	groups, err := tasksvc.List(tasksvc.ListRequest{DB: req.DB, Config: tasksCfg, Today: noteDate})
+	if err != nil {
+		return nil, err
+	}
+
+	earliest := noteDate.AddDate(0, 0, -dailyCfg.CarryOverDays).Format(dates.DateLayout)
+	dailyDir := req.VaultCfg.GetDailyDirectory()
+	notePath := filepath.ToSlash(req.Daily.RelativePath)
+
+	result := &AutomateDailyResult{}
+	var carried []tasksvc.Task
+	for _, group := range groups {
+		for _, task := range group.Tasks {
+			if task.FilePath == notePath {
+				continue
+			}
+			taskNoteDate := dailyNoteDate(task.FilePath, dailyDir)
+			if *dailyCfg.CarryOver && taskNoteDate != "" && taskNoteDate >= earliest && taskNoteDate < req.Daily.Date {
+				carried = append(carried, task)
+				continue
+			}
+			if *dailyCfg.Scheduled && task.Due == req.Daily.Date {
+				result.Scheduled = append(result.Scheduled, task)
+			}
+		}
+	}
+
+	carriedLines, err := takeTaskLines(req.VaultPath, req.VaultCfg, "@"+tasksCfg.Trait, carried)
+	if err != nil {
+		return nil, err
+	}
+	var sections []string
+	if len(carriedLines) > 0 {
+		lines := make([]string, 0, len(carriedLines))
+		for _, taken := range carriedLines {
+			lines = append(lines, taken.line)
+			result.CarriedOver = append(result.CarriedOver, taken.task)
+		}
+		sections = append(sections, CarriedOverHeading+"\n\n"+strings.Join(lines, "\n"))
+	}
+	if len(result.Scheduled) > 0 {
+		lines := make([]string, 0, len(result.Scheduled))
+		for _, task := range result.Scheduled {
+			lines = append(lines, scheduledLine(task))
+		}
+		sections = append(sections, DueTodayHeading+"\n\n"+strings.Join(lines, "\n"))
+	}
+	if len(sections) == 0 {
+		return result, nil
+	}
+
+	// Write the new note before removing carried lines so a failure never
+	// drops a task.
+	content, err := vaultcrypt.ReadFile(req.Daily.FilePath)
+	if err != nil {
+		return nil, newError(CodeFileWriteErr, "failed to read daily note", "", err)
+	}
+	body := strings.TrimRight(string(content), "\n")
+	if body != "" {
+		body += "\n\n"
+	}
+	body += strings.Join(sections, "\n\n") + "\n"
+	if err := atomicfile.WriteFile(req.Daily.FilePath, []byte(body), 0o644); err != nil {
+		return nil, newError(CodeFileWriteErr, "failed to write daily note", "", err)
+	}
+	result.ChangedFiles = append(result.ChangedFiles, req.Daily.FilePath)
+
+	sources, err := removeTakenLines(req.VaultPath, carriedLines)
+	if err != nil {
+		return nil, err
+	}
+	result.ChangedFiles = append(result.ChangedFiles, sources...)
+	return result, nil
+}
+
+// dailyNoteDate returns the date of a daily note path such as
+// daily/2026-01-25.md, or "" for other files.
+func dailyNoteDate(filePath, dailyDir string) string {
+	if path.Dir(filePath) != dailyDir {
+		return ""
+	}
+	name := strings.TrimSuffix(path.Base(filePath), ".md")
+	if !dates.IsValidDate(name) {
+		return ""
+	}
+	return name
+}
+
+func scheduledLine(task tasksvc.Task) string {
+	content := strings.TrimSpace(task.Content)
+	if content == "" {
+		return "- [[" + task.ObjectID + "]]"
+	}
+	return "- " + content + " ([[" + task.ObjectID + "]])"
+}
+
+type takenLine struct {
+	task tasksvc.Task
+	line string
+}
+
+// takeTaskLines reads the lines of tasks being carried over. Tasks in files
+// that cannot be modified, or whose line no longer holds the task trait, are
+// left in place.
+func takeTaskLines(vaultPath string, vaultCfg *config.VaultConfig, marker string, tasks []tasksvc.Task) ([]takenLine, error) {
+	fileLines := map[string][]string{}
+	seen := map[string]bool{}
+	taken := make([]takenLine, 0, len(tasks))
+	for _, task := range tasks {
+		key := task.FilePath + ":" + strconv.Itoa(task.Line)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		fullPath := filepath.Join(vaultPath, filepath.FromSlash(task.FilePath))
+		lines, ok := fileLines[task.FilePath]
+		if !ok {
+			if err := objectsvc.ValidateContentMutationFilePath(vaultPath, vaultCfg, fullPath); err != nil {
+				fileLines[task.FilePath] = nil
+				continue
+			}
+			content, err := vaultcrypt.ReadFile(fullPath)
+			if err != nil {
+				return nil, newError(CodeFileWriteErr, "failed to read "+task.FilePath, "", err)
+			}
+			lines = strings.Split(string(content), "\n")
+			fileLines[task.FilePath] = lines
+		}
+		if task.Line < 1 || task.Line > len(lines) || !strings.Contains(lines[task.Line-1], marker) {
+			continue
+		}
+		taken = append(taken, takenLine{task: task, line: strings.TrimLeft(lines[task.Line-1], " \t")})
+	}
+	return taken, nil
+}
+
+// removeTakenLines deletes carried task lines from their source notes and
+// returns the absolute paths it rewrote.
+func removeTakenLines(vaultPath string, taken []takenLine) ([]string, error) {
+	byFile := map[string][]int{}
+	for _, t := range taken {
+		byFile[t.task.FilePath] = append(byFile[t.task.FilePath], t.task.Line)
+	}
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	changed := make([]string, 0, len(files))
+	for _, file := range files {
+		fullPath := filepath.Join(vaultPath, filepath.FromSlash(file))
+		content, err := vaultcrypt.ReadFile(fullPath)
+		if err != nil {
+			return changed, newError(CodeFileWriteErr, "failed to read "+file, "", err)
+		}
+		lines := strings.Split(string(content), "\n")
+		remove := byFile[file]
+		sort.Sort(sort.Reverse(sort.IntSlice(remove)))
+		for _, line := range remove {
+			lines = append(lines[:line-1], lines[line:]...)
+		}
+		if err := atomicfile.WriteFile(fullPath, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+			return changed, newError(CodeFileWriteErr, "failed to write "+file, "", err)
+		}
+		changed = append(changed, fullPath)
+	}
+	return changed, nil
+}
@@ -0,0 +1,87 @@
+package datesvc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestAutomateDaily_CarriesOverAndLinksDueTasks(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).
+		WithSchema(`version: 2
+traits:
+  todo:
+    type: enum
+    values: [todo, done, cancelled]
+    default: todo
+  due:
+    type: date
+`).
+		WithFile("daily/2026-03-08.md", "# March 8\n\n- buy milk @todo\n- shipped @todo(done)\n  - call bob @todo\n").
+		WithFile("daily/2026-02-20.md", "- too old @todo\n").
+		WithFile("notes/taxes.md", "- file taxes @todo @due(2026-03-10)\n- renew lease @todo @due(2026-04-01)\n").
+		WithFile("daily/2026-03-10.md", "# March 10\n").
+		Build()
+
+	vault.RunCLI("reindex").MustSucceed(t)
+
+	vaultCfg, err := config.LoadVaultConfig(vault.Path)
+	if err != nil {
+		t.Fatalf("LoadVaultConfig: %v", err)
+	}
+	db, err := index.Open(vault.Path)
+	if err != nil {
+		t.Fatalf("index.Open: %v", err)
+	}
+	defer db.Close()
+
+	notePath := filepath.Join(vault.Path, "daily", "2026-03-10.md")
+	result, err := AutomateDaily(AutomateDailyRequest{
+		VaultPath: vault.Path,
+		VaultCfg:  vaultCfg,
+		DB:        db,
+		Daily: &EnsureDailyResult{
+			Date:         "2026-03-10",
+			RelativePath: "daily/2026-03-10.md",
+			FilePath:     notePath,
+			Created:      true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("AutomateDaily: %v", err)
+	}
+	if len(result.CarriedOver) != 2 || len(result.Scheduled) != 1 {
+		t.Fatalf("carried = %#v, scheduled = %#v", result.CarriedOver, result.Scheduled)
+	}
+
+	note, err := os.ReadFile(notePath)
+	if err != nil {
+		t.Fatalf("read note: %v", err)
+	}
+	wantNote := "# March 10\n\n## Carried over\n\n- buy milk @todo\n- call bob @todo\n\n## Due today\n\n- file taxes ([[notes/taxes]])\n"
+	if string(note) != wantNote {
+		t.Fatalf("note =\n%s\nwant\n%s", note, wantNote)
+	}
+
+	source, err := os.ReadFile(filepath.Join(vault.Path, "daily", "2026-03-08.md"))
+	if err != nil {
+		t.Fatalf("read source: %v", err)
+	}
+	if got := string(source); got != "# March 8\n\n- shipped @todo(done)\n" {
+		t.Fatalf("source note = %q", got)
+	}
+	old, err := os.ReadFile(filepath.Join(vault.Path, "daily", "2026-02-20.md"))
+	if err != nil {
+		t.Fatalf("read old note: %v", err)
+	}
+	if !strings.Contains(string(old), "too old @todo") {
+		t.Fatalf("task outside the carry-over window was moved: %q", old)
+	}
+}
@@ -0,0 +1,79 @@
+package datesvc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/dates"
+	"github.com/aidanlsb/raven/internal/pages"
+	"github.com/aidanlsb/raven/internal/readsvc"
+)
+
+type EnsurePeriodicRequest struct {
+	VaultPath string
+	Kind      dates.PeriodKind
+	PeriodArg string
+	// Template overrides periodic.<kind>.template for a new note.
+	Template string
+}
+
+type EnsurePeriodicResult struct {
+	Period       dates.Period
+	RelativePath string
+	FilePath     string
+	Created      bool
+}
+
+// EnsurePeriodic resolves the weekly, monthly, or quarterly note for a period
+// and creates it from the configured template if it does not exist.
+func EnsurePeriodic(req EnsurePeriodicRequest) (*EnsurePeriodicResult, error) {
+	if strings.TrimSpace(req.VaultPath) == "" {
+		return nil, newError(CodeInvalidInput, "vault path is required", "", nil)
+	}
+
+	vaultCfg, err := config.LoadVaultConfig(req.VaultPath)
+	if err != nil {
+		return nil, newError(CodeConfigInvalid, "failed to load vault config", "Fix raven.yaml and try again", err)
+	}
+
+	period, err := dates.ParsePeriodArg(req.Kind, req.PeriodArg, time.Now())
+	if err != nil {
+		return nil, newError(CodeInvalidInput, err.Error(), "Use this, last, next, a period key like 2026-W07, 2026-02, or 2026-Q1, or a date", err)
+	}
+
+	relPath := vaultCfg.PeriodicNotePath(period)
+	result := &EnsurePeriodicResult{
+		Period:       period,
+		RelativePath: relPath,
+		FilePath:     filepath.Join(req.VaultPath, filepath.FromSlash(relPath)),
+	}
+	if _, err := os.Stat(result.FilePath); err == nil {
+		return result, nil
+	}
+
+	templateFile := strings.TrimSpace(req.Template)
+	if templateFile == "" {
+		templateFile = vaultCfg.GetPeriodicNoteConfig(req.Kind).Template
+	}
+	created, err := pages.Create(pages.CreateOptions{
+		VaultPath:         req.VaultPath,
+		TypeName:          "page",
+		Title:             period.Title(),
+		TargetPath:        strings.TrimSuffix(relPath, ".md"),
+		TemplateOverride:  templateFile,
+		TemplateDir:       vaultCfg.GetTemplateDirectory(),
+		ProtectedPrefixes: vaultCfg.ProtectedPrefixes,
+		TemplateDate:      period.Start.Format(dates.DateLayout),
+		TemplateQuery:     readsvc.TemplateQuery(req.VaultPath),
+	})
+	if err != nil {
+		return nil, newError(CodeFileWriteErr, "failed to create "+string(req.Kind)+"ly note", "", err)
+	}
+	result.RelativePath = filepath.ToSlash(created.RelativePath)
+	result.FilePath = created.FilePath
+	result.Created = true
+	return result, nil
+}
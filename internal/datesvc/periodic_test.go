@@ -0,0 +1,111 @@
+package datesvc
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/dates"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestEnsurePeriodic_CreatesNoteFromTemplate(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).
+		WithSchema(testutil.MinimalSchema()).
+		WithRavenYAML(`periodic:
+  weekly:
+    directory: journal/weeks/
+    template: templates/weekly.md
+`).
+		WithFile("templates/weekly.md", "# {{title}}\n\nStarts {{date}}.\n").
+		Build()
+
+	result, err := EnsurePeriodic(EnsurePeriodicRequest{
+		VaultPath: vault.Path,
+		Kind:      dates.PeriodWeek,
+		PeriodArg: "2026-W07",
+	})
+	if err != nil {
+		t.Fatalf("EnsurePeriodic returned error: %v", err)
+	}
+	if !result.Created {
+		t.Fatal("expected the weekly note to be created")
+	}
+	if got, want := result.RelativePath, "journal/weeks/2026-w07.md"; got != want {
+		t.Fatalf("relative path = %q, want %q", got, want)
+	}
+	content, err := os.ReadFile(result.FilePath)
+	if err != nil {
+		t.Fatalf("read weekly note: %v", err)
+	}
+	for _, want := range []string{"# Week 7, 2026", "Starts 2026-02-09."} {
+		if !strings.Contains(string(content), want) {
+			t.Fatalf("weekly note missing %q:\n%s", want, content)
+		}
+	}
+
+	again, err := EnsurePeriodic(EnsurePeriodicRequest{
+		VaultPath: vault.Path,
+		Kind:      dates.PeriodWeek,
+		PeriodArg: "2026-02-15",
+	})
+	if err != nil {
+		t.Fatalf("EnsurePeriodic returned error: %v", err)
+	}
+	if again.Created || again.RelativePath != result.RelativePath {
+		t.Fatalf("second call = %+v, want existing %s", again, result.RelativePath)
+	}
+
+	if _, err := EnsurePeriodic(EnsurePeriodicRequest{
+		VaultPath: vault.Path,
+		Kind:      dates.PeriodWeek,
+		PeriodArg: "2026-Q1",
+	}); err == nil {
+		t.Fatal("expected a quarter key to be rejected for a weekly note")
+	}
+}
+
+func TestDateHub_Period(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).
+		WithSchema(`version: 2
+types: {}
+traits:
+  due:
+    type: date
+`).
+		WithFile("monthly/2026-02.md", "# February 2026\n").
+		WithFile("tasks.md", "- @due(2026-02-03) File taxes\n- @due(2026-02-27) Renew passport\n- @due(2026-03-01) Out of range\n").
+		WithFile("review.md", "See [[monthly/2026-02]].\n").
+		Build()
+
+	vault.RunCLI("reindex").MustSucceed(t)
+
+	result, err := DateHub(DateHubRequest{VaultPath: vault.Path, DateArg: "2026-02"})
+	if err != nil {
+		t.Fatalf("DateHub returned error: %v", err)
+	}
+	if result.Period == nil {
+		t.Fatal("expected a period hub")
+	}
+	if got, want := result.Period.NotePath, "monthly/2026-02.md"; got != want || !result.Period.NoteExists {
+		t.Fatalf("period note = %q (exists %v), want existing %q", got, result.Period.NoteExists, want)
+	}
+	if result.Period.Start != "2026-02-01" || result.Period.End != "2026-02-28" {
+		t.Fatalf("period range = %s..%s, want 2026-02-01..2026-02-28", result.Period.Start, result.Period.End)
+	}
+
+	var gotDates []string
+	for _, item := range result.Items {
+		gotDates = append(gotDates, item.Date)
+	}
+	if strings.Join(gotDates, ",") != "2026-02-03,2026-02-27" {
+		t.Fatalf("item dates = %v, want [2026-02-03 2026-02-27]", gotDates)
+	}
+	if len(result.Backlinks) != 1 || result.Backlinks[0].SourceID != "review" {
+		t.Fatalf("backlinks = %#v, want one from review", result.Backlinks)
+	}
+}
@@ -3,15 +3,19 @@ package datesvc
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/aidanlsb/raven/internal/codes"
 	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/dates"
 	"github.com/aidanlsb/raven/internal/index"
 	"github.com/aidanlsb/raven/internal/model"
 	"github.com/aidanlsb/raven/internal/pages"
+	"github.com/aidanlsb/raven/internal/readsvc"
 	"github.com/aidanlsb/raven/internal/schema"
 	"github.com/aidanlsb/raven/internal/vault"
 )
@@ -131,6 +135,7 @@ func EnsureDaily(req EnsureDailyRequest) (*EnsureDailyResult, error) {
 			templateFile,
 			vaultCfg.GetTemplateDirectory(),
 			vaultCfg.ProtectedPrefixes,
+			readsvc.TemplateQuery(req.VaultPath),
 		)
 		if err != nil {
 			return nil, newError(CodeFileWriteErr, "failed to create daily note", "", err)
@@ -144,6 +149,7 @@ func EnsureDaily(req EnsureDailyRequest) (*EnsureDailyResult, error) {
 			sch,
 			vaultCfg.GetTemplateDirectory(),
 			vaultCfg.ProtectedPrefixes,
+			readsvc.TemplateQuery(req.VaultPath),
 		)
 		if err != nil {
 			return nil, newError(CodeFileWriteErr, "failed to create daily note", "", err)
@@ -177,9 +183,27 @@ type DateAssociation struct {
 	FilePath   string        `json:"file_path"`
 	Trait      *model.Trait  `json:"trait,omitempty"`
 	Object     *model.Object `json:"object,omitempty"`
+	// Recurring marks a yearly date (date_hub.recurring) shown on its
+	// anniversary; Years counts the years since Date.
+	Recurring bool `json:"recurring,omitempty"`
+	Years     int  `json:"years,omitempty"`
+}
+
+// DateHubPeriod describes the week, month, or quarter a period hub covers.
+type DateHubPeriod struct {
+	Kind       string `json:"kind"`
+	Key        string `json:"key"`
+	Title      string `json:"title"`
+	Start      string `json:"start"`
+	End        string `json:"end"`
+	NotePath   string `json:"note_path"`
+	NoteExists bool   `json:"note_exists"`
 }
 
 type DateHubResult struct {
+	// Period is set when the hub covers a week, month, or quarter rather than
+	// a day. Date is then the period key and Items span the whole period.
+	Period      *DateHubPeriod    `json:"period,omitempty"`
 	Date        string            `json:"date"`
 	DayOfWeek   string            `json:"day_of_week"`
 	DailyNoteID string            `json:"daily_note_id"`
@@ -200,9 +224,13 @@ func DateHub(req DateHubRequest) (*DateHubResult, error) {
 		return nil, newError(CodeConfigInvalid, "failed to load vault config", "Fix raven.yaml and try again", err)
 	}
 
+	if period, ok := parseHubPeriod(req.DateArg); ok {
+		return periodHub(req.VaultPath, vaultCfg, period)
+	}
+
 	targetDate, err := vault.ParseDateArg(strings.TrimSpace(req.DateArg))
 	if err != nil {
-		return nil, newError(CodeInvalidInput, err.Error(), "Use today/yesterday/tomorrow or YYYY-MM-DD", err)
+		return nil, newError(CodeInvalidInput, err.Error(), "Use today/yesterday/tomorrow, YYYY-MM-DD, or a period like 2026-W07, 2026-02, 2026-Q1, or this-week", err)
 	}
 
 	dateStr := vault.FormatDateISO(targetDate)
@@ -228,42 +256,161 @@ func DateHub(req DateHubRequest) (*DateHubResult, error) {
 	result.DailyNote = dailyNote
 	result.DailyExists = dailyNote != nil
 
+	associations, err := dayAssociations(db, vaultCfg, targetDate)
+	if err != nil {
+		return nil, err
+	}
+	result.Items = associations
+
+	backlinks, err := db.Backlinks(result.DailyNoteID)
+	if err != nil {
+		return nil, newError(CodeQueryFailed, "failed to query backlinks", "", err)
+	}
+	result.Backlinks = backlinks
+	return result, nil
+}
+
+// parseHubPeriod recognizes period keys (2026-W07, 2026-02, 2026-Q1) and
+// phrases (this-week, last month) in a date hub argument.
+func parseHubPeriod(arg string) (dates.Period, bool) {
+	if period, ok := dates.ParsePeriodKey(arg); ok {
+		return period, true
+	}
+	return dates.ParsePeriodPhrase(arg, time.Now())
+}
+
+// periodHub collects every day's associations in a period along with the
+// period's note and the references to it.
+func periodHub(vaultPath string, vaultCfg *config.VaultConfig, period dates.Period) (*DateHubResult, error) {
+	notePath := vaultCfg.PeriodicNotePath(period)
+	_, statErr := os.Stat(filepath.Join(vaultPath, filepath.FromSlash(notePath)))
+	result := &DateHubResult{
+		Period: &DateHubPeriod{
+			Kind:       string(period.Kind),
+			Key:        period.Key(),
+			Title:      period.Title(),
+			Start:      period.Start.Format(dates.DateLayout),
+			End:        period.End.Format(dates.DateLayout),
+			NotePath:   notePath,
+			NoteExists: statErr == nil,
+		},
+		Date:      period.Key(),
+		Items:     []DateAssociation{},
+		Backlinks: []model.Reference{},
+	}
+
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return nil, newError(CodeDatabaseError, "failed to open database", "Run 'rvn reindex' to rebuild the database", err)
+	}
+	defer db.Close()
+
+	for day := period.Start; !day.After(period.End); day = day.AddDate(0, 0, 1) {
+		associations, err := dayAssociations(db, vaultCfg, day)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, associations...)
+	}
+
+	backlinks, err := db.Backlinks(vaultCfg.FilePathToObjectID(notePath))
+	if err != nil {
+		return nil, newError(CodeQueryFailed, "failed to query backlinks", "", err)
+	}
+	result.Backlinks = backlinks
+	return result, nil
+}
+
+// dayAssociations returns the objects and traits dated on day, followed by
+// the configured yearly fields whose anniversary it is.
+func dayAssociations(db *index.Database, vaultCfg *config.VaultConfig, day time.Time) ([]DateAssociation, error) {
+	dateStr := day.Format(dates.DateLayout)
 	items, err := db.QueryDateIndex(dateStr)
 	if err != nil {
 		return nil, newError(CodeQueryFailed, "failed to query date index", "", err)
 	}
 
+	recurring := vaultCfg.DateHubRecurringFields()
 	associations := make([]DateAssociation, 0, len(items))
 	for _, item := range items {
-		assoc := DateAssociation{
-			Date:       item.Date,
-			SourceType: item.SourceType,
-			SourceID:   item.SourceID,
-			FieldName:  item.FieldName,
-			FilePath:   item.FilePath,
+		assoc, err := dateAssociation(db, item)
+		if err != nil {
+			return nil, err
 		}
-		switch item.SourceType {
-		case "trait":
-			trait, err := db.GetTrait(item.SourceID)
-			if err != nil {
-				return nil, newError(CodeQueryFailed, fmt.Sprintf("failed to query trait %s", item.SourceID), "", err)
+		if obj := assoc.Object; obj != nil && obj.Type != "date" {
+			if isDateHubField(recurring, obj.Type, item.FieldName) || !vaultCfg.DateHubIncludesField(obj.Type, item.FieldName) {
+				continue
 			}
-			assoc.Trait = trait
-		case "object":
-			obj, err := db.GetObject(item.SourceID)
+		}
+		associations = append(associations, assoc)
+	}
+
+	for _, field := range recurring {
+		for _, monthDay := range anniversaryMonthDays(day) {
+			matches, err := db.QueryDateIndexAnniversaries(monthDay, field.Type, field.Field, dateStr)
 			if err != nil {
-				return nil, newError(CodeQueryFailed, fmt.Sprintf("failed to query object %s", item.SourceID), "", err)
+				return nil, newError(CodeQueryFailed, "failed to query recurring dates", "", err)
+			}
+			for _, item := range matches {
+				assoc, err := dateAssociation(db, item)
+				if err != nil {
+					return nil, err
+				}
+				assoc.Recurring = true
+				if original, err := time.Parse("2006-01-02", item.Date); err == nil {
+					assoc.Years = day.Year() - original.Year()
+				}
+				associations = append(associations, assoc)
 			}
-			assoc.Object = obj
 		}
-		associations = append(associations, assoc)
 	}
-	result.Items = associations
+	return associations, nil
+}
 
-	backlinks, err := db.Backlinks(result.DailyNoteID)
-	if err != nil {
-		return nil, newError(CodeQueryFailed, "failed to query backlinks", "", err)
+func dateAssociation(db *index.Database, item index.DateIndexResult) (DateAssociation, error) {
+	assoc := DateAssociation{
+		Date:       item.Date,
+		SourceType: item.SourceType,
+		SourceID:   item.SourceID,
+		FieldName:  item.FieldName,
+		FilePath:   item.FilePath,
 	}
-	result.Backlinks = backlinks
-	return result, nil
+	switch item.SourceType {
+	case "trait":
+		trait, err := db.GetTrait(item.SourceID)
+		if err != nil {
+			return assoc, newError(CodeQueryFailed, fmt.Sprintf("failed to query trait %s", item.SourceID), "", err)
+		}
+		assoc.Trait = trait
+	case "object":
+		obj, err := db.GetObject(item.SourceID)
+		if err != nil {
+			return assoc, newError(CodeQueryFailed, fmt.Sprintf("failed to query object %s", item.SourceID), "", err)
+		}
+		assoc.Object = obj
+	}
+	return assoc, nil
+}
+
+func isDateHubField(fields []config.DateHubField, typeName, field string) bool {
+	for _, candidate := range fields {
+		if candidate.Type == typeName && candidate.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// anniversaryMonthDays returns the month-day keys whose yearly dates fall on
+// target. February 29 dates fall on February 28 in other years.
+func anniversaryMonthDays(target time.Time) []string {
+	monthDays := []string{target.Format("01-02")}
+	if target.Month() == time.February && target.Day() == 28 && !isLeapYear(target.Year()) {
+		monthDays = append(monthDays, "02-29")
+	}
+	return monthDays
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
 }
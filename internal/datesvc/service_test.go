@@ -37,3 +37,67 @@ func TestDateHub_BacklinksUseDailyNoteObjectID(t *testing.T) {
 		t.Fatalf("backlink target raw = %q, want %q", got, want)
 	}
 }
+
+func TestDateHub_ConfiguredAndRecurringFields(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).
+		WithSchema(`version: 2
+types:
+  person:
+    default_path: people/
+    fields:
+      birthday:
+        type: date
+  meeting:
+    default_path: meetings/
+    fields:
+      scheduled_at:
+        type: datetime
+      follow_up:
+        type: date
+`).
+		WithRavenYAML(`date_hub:
+  fields:
+    - meeting.scheduled_at
+  recurring:
+    - person.birthday
+`).
+		WithFile("people/freya.md", "---\ntype: person\nbirthday: 1990-03-01\n---\n").
+		WithFile("people/loki.md", "---\ntype: person\nbirthday: 2030-03-01\n---\n").
+		WithFile("people/thor.md", "---\ntype: person\nbirthday: 1992-02-29\n---\n").
+		WithFile("meetings/standup.md", "---\ntype: meeting\nscheduled_at: 2026-03-01T09:30\nfollow_up: 2026-03-01\n---\n").
+		Build()
+
+	vault.RunCLI("reindex").MustSucceed(t)
+
+	result, err := DateHub(DateHubRequest{VaultPath: vault.Path, DateArg: "2026-03-01"})
+	if err != nil {
+		t.Fatalf("DateHub returned error: %v", err)
+	}
+	got := make(map[string]DateAssociation)
+	for _, item := range result.Items {
+		got[item.SourceID+"."+item.FieldName] = item
+	}
+	if _, ok := got["meetings/standup.scheduled_at"]; !ok {
+		t.Errorf("missing configured field meeting.scheduled_at in %#v", result.Items)
+	}
+	if _, ok := got["meetings/standup.follow_up"]; ok {
+		t.Errorf("unconfigured field meeting.follow_up should be excluded")
+	}
+	birthday, ok := got["people/freya.birthday"]
+	if !ok || !birthday.Recurring || birthday.Years != 36 || birthday.Date != "1990-03-01" {
+		t.Errorf("freya birthday = %#v, want recurring 36 years", birthday)
+	}
+	if _, ok := got["people/loki.birthday"]; ok {
+		t.Errorf("future birthday should not recur before it happens")
+	}
+
+	leap, err := DateHub(DateHubRequest{VaultPath: vault.Path, DateArg: "2027-02-28"})
+	if err != nil {
+		t.Fatalf("DateHub returned error: %v", err)
+	}
+	if len(leap.Items) != 1 || leap.Items[0].SourceID != "people/thor" || leap.Items[0].Years != 35 {
+		t.Errorf("2027-02-28 items = %#v, want thor's leap-day birthday", leap.Items)
+	}
+}
@@ -0,0 +1,529 @@
+// Package doctorsvc diagnoses and repairs problems with a vault's index and
+// files that `rvn check` does not cover: a corrupt or outdated index, rows
+// left behind for deleted files, full-text search drift, schema mismatches,
+// file permissions, and trash growth.
+package doctorsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/reindexsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// Check names, in report order.
+const (
+	CheckIndexReadable  = "index_readable"
+	CheckIndexIntegrity = "index_integrity"
+	CheckIndexVersion   = "index_version"
+	CheckMissingFiles   = "missing_files"
+	CheckOrphanRows     = "orphan_rows"
+	CheckFTSSync        = "fts_sync"
+	CheckSchemaTypes    = "schema_types"
+	CheckSchemaDrift    = "schema_drift"
+	CheckPermissions    = "permissions"
+	CheckTrash          = "trash"
+)
+
+// Status is the outcome of one check.
+type Status string
+
+const (
+	StatusOK      Status = "ok"
+	StatusWarning Status = "warning"
+	StatusError   Status = "error"
+)
+
+// TrashWarnBytes is the trash size above which the trash check warns.
+const TrashWarnBytes = 100 << 20
+
+// maxFindingItems caps the examples listed for one finding.
+const maxFindingItems = 20
+
+// Finding is the result of one check.
+type Finding struct {
+	Check   string   `json:"check"`
+	Status  Status   `json:"status"`
+	Message string   `json:"message"`
+	Items   []string `json:"items,omitempty"`
+	// Fixable reports whether Repair can fix the problem.
+	Fixable    bool   `json:"fixable"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Report is the result of Diagnose.
+type Report struct {
+	Findings []Finding
+	Errors   int
+	Warnings int
+
+	rebuild      bool
+	missingFiles []string
+	orphanRows   int
+	reindexFiles []string
+}
+
+// Fixable reports whether Repair has anything to do.
+func (r *Report) Fixable() bool {
+	for _, finding := range r.Findings {
+		if finding.Fixable {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Report) add(finding Finding) {
+	if len(finding.Items) > maxFindingItems {
+		finding.Items = append(finding.Items[:maxFindingItems:maxFindingItems], fmt.Sprintf("... and %d more", len(finding.Items)-maxFindingItems))
+	}
+	switch finding.Status {
+	case StatusError:
+		r.Errors++
+	case StatusWarning:
+		r.Warnings++
+	}
+	r.Findings = append(r.Findings, finding)
+}
+
+func (r *Report) ok(check, message string) {
+	r.add(Finding{Check: check, Status: StatusOK, Message: message})
+}
+
+// Diagnose runs every check against the vault. It only reads: the index is
+// opened, never rebuilt.
+func Diagnose(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema) (*Report, error) {
+	if vaultCfg == nil {
+		vaultCfg = &config.VaultConfig{}
+	}
+	report := &Report{}
+	diagnoseIndex(report, vaultPath, vaultCfg, sch)
+	if err := diagnosePermissions(report, vaultPath); err != nil {
+		return nil, err
+	}
+	if err := diagnoseTrash(report, vaultPath, vaultCfg); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func diagnoseIndex(report *Report, vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema) {
+	dbDir := filepath.Join(vaultPath, ".raven")
+	if !fileExists(filepath.Join(dbDir, "index.db")) && !fileExists(filepath.Join(dbDir, index.EncryptedIndexFile)) {
+		report.rebuild = true
+		report.add(Finding{
+			Check:   CheckIndexReadable,
+			Status:  StatusWarning,
+			Message: "The vault has no index yet",
+			Fixable: true,
+		})
+		return
+	}
+
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		finding := Finding{
+			Check:   CheckIndexReadable,
+			Status:  StatusError,
+			Message: fmt.Sprintf("The index cannot be opened: %v", err),
+			Fixable: true,
+		}
+		if vaultCfg.IsIndexEncrypted() {
+			// The passphrase may be missing rather than the index broken;
+			// deleting it would not help and the rebuild would fail too.
+			finding.Fixable = false
+			finding.Suggestion = fmt.Sprintf("Check the index passphrase (%s or its entry under [key_commands] in config.toml)", vaultCfg.GetIndexKeyEnv())
+		} else {
+			report.rebuild = true
+		}
+		report.add(finding)
+		return
+	}
+	defer db.Close()
+	report.ok(CheckIndexReadable, "The index opens")
+
+	problems, err := db.IntegrityCheck()
+	if err != nil {
+		problems = []string{err.Error()}
+	}
+	if len(problems) > 0 {
+		report.rebuild = true
+		report.add(Finding{
+			Check:   CheckIndexIntegrity,
+			Status:  StatusError,
+			Message: "SQLite reports the index database is corrupt",
+			Items:   problems,
+			Fixable: true,
+		})
+		return
+	}
+	report.ok(CheckIndexIntegrity, "SQLite integrity check passed")
+
+	compatible, err := db.SchemaCompatible()
+	if err != nil || !compatible {
+		report.rebuild = true
+		report.add(Finding{
+			Check:   CheckIndexVersion,
+			Status:  StatusError,
+			Message: "The index was built by a different version of Raven",
+			Fixable: true,
+		})
+		return
+	}
+	report.ok(CheckIndexVersion, "The index format is current")
+
+	diagnoseIndexRows(report, vaultPath, db)
+	diagnoseSchema(report, db, sch)
+}
+
+func diagnoseIndexRows(report *Report, vaultPath string, db *index.Database) {
+	missing, err := db.MissingFiles(vaultPath)
+	switch {
+	case err != nil:
+		report.add(queryFailure(CheckMissingFiles, err))
+	case len(missing) > 0:
+		report.missingFiles = missing
+		report.add(Finding{
+			Check:   CheckMissingFiles,
+			Status:  StatusError,
+			Message: fmt.Sprintf("%d indexed file(s) no longer exist", len(missing)),
+			Items:   missing,
+			Fixable: true,
+		})
+	default:
+		report.ok(CheckMissingFiles, "Every indexed file exists")
+	}
+
+	orphans, err := db.OrphanRowCounts()
+	switch {
+	case err != nil:
+		report.add(queryFailure(CheckOrphanRows, err))
+	case len(orphans) > 0:
+		tables := make([]string, 0, len(orphans))
+		for table := range orphans {
+			tables = append(tables, table)
+		}
+		sort.Strings(tables)
+		items := make([]string, 0, len(tables))
+		for _, table := range tables {
+			items = append(items, fmt.Sprintf("%s: %d", table, orphans[table]))
+			report.orphanRows += orphans[table]
+		}
+		report.add(Finding{
+			Check:   CheckOrphanRows,
+			Status:  StatusError,
+			Message: fmt.Sprintf("%d index row(s) belong to files with no indexed object", report.orphanRows),
+			Items:   items,
+			Fixable: true,
+		})
+	default:
+		report.ok(CheckOrphanRows, "No orphaned index rows")
+	}
+
+	desynced, err := db.FTSDesyncedFiles()
+	switch {
+	case err != nil:
+		report.add(queryFailure(CheckFTSSync, err))
+	case len(desynced) > 0:
+		report.reindexFiles = append(report.reindexFiles, desynced...)
+		report.add(Finding{
+			Check:   CheckFTSSync,
+			Status:  StatusError,
+			Message: fmt.Sprintf("Full-text search is out of sync for %d file(s)", len(desynced)),
+			Items:   desynced,
+			Fixable: true,
+		})
+	default:
+		report.ok(CheckFTSSync, "Full-text search matches the index")
+	}
+}
+
+func diagnoseSchema(report *Report, db *index.Database, sch *schema.Schema) {
+	if sch == nil {
+		return
+	}
+
+	var unknown []string
+	typeUsage, err := db.TypeUsage()
+	if err == nil {
+		for name, usage := range typeUsage {
+			if _, ok := sch.Types[name]; !ok && !schema.IsBuiltinType(name) {
+				unknown = append(unknown, fmt.Sprintf("type %s (%d objects)", name, usage.Count))
+			}
+		}
+	}
+	traitUsage, traitErr := db.TraitUsage()
+	if traitErr == nil {
+		for name, usage := range traitUsage {
+			if _, ok := sch.Traits[name]; !ok {
+				unknown = append(unknown, fmt.Sprintf("trait %s (%d uses)", name, usage.Count))
+			}
+		}
+	}
+	switch {
+	case err != nil || traitErr != nil:
+		report.add(queryFailure(CheckSchemaTypes, errors.Join(err, traitErr)))
+	case len(unknown) > 0:
+		sort.Strings(unknown)
+		report.add(Finding{
+			Check:      CheckSchemaTypes,
+			Status:     StatusWarning,
+			Message:    fmt.Sprintf("%d indexed type(s) or trait(s) are not defined in schema.yaml", len(unknown)),
+			Items:      unknown,
+			Suggestion: "Add them with 'rvn schema add type|trait <name>', or run 'rvn check --issues unknown_type,undefined_trait' to find the files",
+		})
+	default:
+		report.ok(CheckSchemaTypes, "Every indexed type and trait is defined in schema.yaml")
+	}
+
+	stored, ok, err := db.SchemaFingerprint()
+	if err != nil || !ok {
+		return
+	}
+	current := sch.Fingerprint()
+	if stored.Hash == current.Hash {
+		report.ok(CheckSchemaDrift, "The index was built with the current schema.yaml")
+		return
+	}
+	affected, err := db.FilesAffectedBySchemaDrift(current.DriftSince(stored))
+	if err != nil {
+		report.add(queryFailure(CheckSchemaDrift, err))
+		return
+	}
+	report.reindexFiles = append(report.reindexFiles, affected...)
+	report.add(Finding{
+		Check:   CheckSchemaDrift,
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("schema.yaml changed since the index was built; %d file(s) were indexed under the old schema", len(affected)),
+		Items:   affected,
+		Fixable: true,
+	})
+}
+
+func diagnosePermissions(report *Report, vaultPath string) error {
+	var problems []string
+	dbDir := filepath.Join(vaultPath, ".raven")
+	if info, err := os.Stat(dbDir); err == nil && info.IsDir() {
+		if probe, err := os.CreateTemp(dbDir, ".doctor-*"); err != nil {
+			problems = append(problems, ".raven/ is not writable")
+		} else {
+			probe.Close()
+			os.Remove(probe.Name())
+		}
+		for _, name := range []string{"index.db", index.EncryptedIndexFile} {
+			path := filepath.Join(dbDir, name)
+			if !fileExists(path) {
+				continue
+			}
+			if f, err := os.OpenFile(path, os.O_RDWR, 0); err != nil {
+				problems = append(problems, ".raven/"+name+" is not readable and writable")
+			} else {
+				f.Close()
+			}
+		}
+	}
+
+	var readOnly []string
+	err := filepath.WalkDir(vaultPath, func(path string, d fs.DirEntry, err error) error {
+		rel, _ := filepath.Rel(vaultPath, path)
+		rel = filepath.ToSlash(rel)
+		if err != nil {
+			problems = append(problems, rel+" cannot be read")
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if name := d.Name(); rel != "." && (name == ".raven" || name == ".trash" || name == ".git") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(rel, ".md") {
+			return nil
+		}
+		f, openErr := os.Open(path)
+		if openErr != nil {
+			problems = append(problems, rel+" cannot be read")
+			return nil
+		}
+		f.Close()
+		if info, infoErr := d.Info(); infoErr == nil && info.Mode().Perm()&0o200 == 0 {
+			readOnly = append(readOnly, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(problems) > 0:
+		report.add(Finding{
+			Check:      CheckPermissions,
+			Status:     StatusError,
+			Message:    fmt.Sprintf("%d path(s) Raven cannot read or write", len(problems)),
+			Items:      append(problems, readOnly...),
+			Suggestion: "Fix the file permissions so your user can read the vault and write .raven/",
+		})
+	case len(readOnly) > 0:
+		report.add(Finding{
+			Check:      CheckPermissions,
+			Status:     StatusWarning,
+			Message:    fmt.Sprintf("%d markdown file(s) are read-only, so Raven cannot edit them", len(readOnly)),
+			Items:      readOnly,
+			Suggestion: "Make them writable (chmod u+w) if Raven should edit them",
+		})
+	default:
+		report.ok(CheckPermissions, "Vault files and .raven/ have the expected permissions")
+	}
+	return nil
+}
+
+func diagnoseTrash(report *Report, vaultPath string, vaultCfg *config.VaultConfig) error {
+	trashDir := vaultCfg.GetDeletionConfig().TrashDir
+	var files int
+	var size int64
+	err := filepath.WalkDir(filepath.Join(vaultPath, trashDir), func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files++
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("%s/ holds %d file(s) (%s)", trashDir, files, formatBytes(size))
+	if size > TrashWarnBytes {
+		report.add(Finding{
+			Check:      CheckTrash,
+			Status:     StatusWarning,
+			Message:    message,
+			Suggestion: fmt.Sprintf("Review %s/ and delete what you no longer need", trashDir),
+		})
+		return nil
+	}
+	report.ok(CheckTrash, message)
+	return nil
+}
+
+// RepairResult summarizes what Repair changed.
+type RepairResult struct {
+	Rebuilt           bool     `json:"rebuilt"`
+	RemovedFiles      []string `json:"removed_files"`
+	RemovedOrphanRows int      `json:"removed_orphan_rows"`
+	ReindexedFiles    []string `json:"reindexed_files"`
+}
+
+// Repair fixes the fixable findings of a report. A corrupt, unreadable, or
+// outdated index is deleted and rebuilt from the vault files; otherwise rows
+// for deleted files and orphaned rows are removed, and files with stale
+// full-text rows or an outdated schema are reindexed.
+func Repair(ctx context.Context, vaultPath string, report *Report) (*RepairResult, error) {
+	result := &RepairResult{RemovedFiles: []string{}, ReindexedFiles: []string{}}
+	if report.rebuild {
+		if err := index.DeleteIndexFiles(vaultPath); err != nil {
+			return nil, fmt.Errorf("delete index: %w", err)
+		}
+		if _, err := reindexsvc.Run(reindexsvc.RunRequest{VaultPath: vaultPath, Full: true, Context: ctx}); err != nil {
+			return nil, fmt.Errorf("rebuild index: %w", err)
+		}
+		result.Rebuilt = true
+		return result, nil
+	}
+
+	if len(report.missingFiles) > 0 || report.orphanRows > 0 {
+		db, err := index.Open(vaultPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := db.RemoveFiles(report.missingFiles); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("remove deleted files: %w", err)
+		}
+		result.RemovedFiles = append(result.RemovedFiles, report.missingFiles...)
+		removed, err := db.RemoveOrphanRows()
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		result.RemovedOrphanRows = removed
+		if err := db.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(report.reindexFiles) > 0 {
+		changed := uniqueSorted(report.reindexFiles)
+		if _, err := reindexsvc.Run(reindexsvc.RunRequest{VaultPath: vaultPath, Changed: changed, Context: ctx}); err != nil {
+			return nil, fmt.Errorf("reindex: %w", err)
+		}
+		result.ReindexedFiles = changed
+	}
+	return result, nil
+}
+
+func queryFailure(check string, err error) Finding {
+	return Finding{
+		Check:      check,
+		Status:     StatusError,
+		Message:    fmt.Sprintf("The check could not run: %v", err),
+		Suggestion: "Run 'rvn reindex --full' to rebuild the index",
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			out = append(out, value)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func formatBytes(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+	units := []string{"KB", "MB", "GB", "TB"}
+	value := float64(size)
+	for _, unit := range units {
+		value = value / 1024
+		if value < 1024 {
+			return fmt.Sprintf("%.1f %s", value, unit)
+		}
+	}
+	return fmt.Sprintf("%.1f PB", value/1024)
+}
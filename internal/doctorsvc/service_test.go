@@ -0,0 +1,143 @@
+package doctorsvc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/reindexsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestDiagnoseAndRepairIndexDamage(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(`version: 2
+types:
+  note:
+    default_path: notes/
+traits:
+  todo:
+    type: string
+`).
+		WithFile("notes/alpha.md", "---\ntype: note\n---\n# Alpha\n\n- Ship it @todo\n").
+		WithFile("notes/beta.md", "---\ntype: note\n---\nBeta\n").
+		WithFile("notes/gamma.md", "---\ntype: note\n---\nGamma\n").
+		Build()
+	if _, err := reindexsvc.Run(reindexsvc.RunRequest{VaultPath: v.Path, Full: true}); err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+	sch, err := schema.Load(v.Path)
+	if err != nil {
+		t.Fatalf("schema.Load: %v", err)
+	}
+	vaultCfg := &config.VaultConfig{}
+
+	report, err := Diagnose(v.Path, vaultCfg, sch)
+	if err != nil {
+		t.Fatalf("Diagnose: %v", err)
+	}
+	if report.Errors != 0 || report.Warnings != 0 || report.Fixable() {
+		t.Fatalf("healthy vault reported problems: %+v", report.Findings)
+	}
+
+	// Delete a file behind the index's back, leave rows for a file that has
+	// no object, and drop a full-text row.
+	if err := os.Remove(filepath.Join(v.Path, "notes/gamma.md")); err != nil {
+		t.Fatal(err)
+	}
+	db, err := index.Open(v.Path)
+	if err != nil {
+		t.Fatalf("index.Open: %v", err)
+	}
+	if _, err := db.DB().Exec(`
+		INSERT INTO traits (id, file_path, parent_object_id, trait_type, content, line_number)
+		VALUES ('ghost.md:todo:1', 'ghost.md', 'ghost', 'todo', 'Boo', 1);
+		DELETE FROM fts_content WHERE object_id = 'notes/beta';
+	`); err != nil {
+		t.Fatalf("damage index: %v", err)
+	}
+	db.Close()
+
+	report, err = Diagnose(v.Path, vaultCfg, sch)
+	if err != nil {
+		t.Fatalf("Diagnose: %v", err)
+	}
+	want := map[string]string{
+		CheckMissingFiles: "notes/gamma.md",
+		CheckOrphanRows:   "traits: 1",
+		CheckFTSSync:      "notes/beta.md",
+	}
+	for _, finding := range report.Findings {
+		item, ok := want[finding.Check]
+		if !ok {
+			if finding.Status != StatusOK {
+				t.Errorf("unexpected finding %+v", finding)
+			}
+			continue
+		}
+		if finding.Status != StatusError || !finding.Fixable || len(finding.Items) != 1 || finding.Items[0] != item {
+			t.Errorf("%s finding = %+v, want a fixable error for %s", finding.Check, finding, item)
+		}
+		delete(want, finding.Check)
+	}
+	if len(want) > 0 {
+		t.Fatalf("missing findings for %v", want)
+	}
+
+	repaired, err := Repair(context.Background(), v.Path, report)
+	if err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+	if repaired.Rebuilt || len(repaired.RemovedFiles) != 1 || repaired.RemovedOrphanRows != 1 || len(repaired.ReindexedFiles) != 1 {
+		t.Fatalf("repair = %+v", repaired)
+	}
+
+	report, err = Diagnose(v.Path, vaultCfg, sch)
+	if err != nil {
+		t.Fatalf("Diagnose: %v", err)
+	}
+	if report.Errors != 0 || report.Warnings != 0 {
+		t.Fatalf("problems remain after repair: %+v", report.Findings)
+	}
+}
+
+func TestDiagnoseAndRepairCorruptIndex(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(testutil.MinimalSchema()).
+		WithFile("notes/alpha.md", "# Alpha\n").
+		Build()
+	if _, err := reindexsvc.Run(reindexsvc.RunRequest{VaultPath: v.Path, Full: true}); err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(v.Path, ".raven", "index.db"), []byte("not a database"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, suffix := range []string{"-wal", "-shm"} {
+		_ = os.Remove(filepath.Join(v.Path, ".raven", "index.db"+suffix))
+	}
+
+	report, err := Diagnose(v.Path, &config.VaultConfig{}, schema.New())
+	if err != nil {
+		t.Fatalf("Diagnose: %v", err)
+	}
+	if report.Errors == 0 || !report.rebuild {
+		t.Fatalf("corrupt index not reported: %+v", report.Findings)
+	}
+
+	repaired, err := Repair(context.Background(), v.Path, report)
+	if err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+	if !repaired.Rebuilt {
+		t.Fatalf("repair = %+v, want a rebuild", repaired)
+	}
+	v.AssertQueryCount("type:page", 1)
+}
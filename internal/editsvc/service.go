@@ -58,9 +58,15 @@ func AsError(err error) (*Error, bool) {
 	return nil, false
 }
 
+// EditSpec is one edit. By default it replaces the unique OldStr with NewStr.
+// Setting one of AppendToSection, InsertAfterHeading, or ReplaceLine instead
+// makes it a structured edit that places NewStr relative to a heading or line.
 type EditSpec struct {
-	OldStr string `json:"old_str"`
-	NewStr string `json:"new_str"`
+	OldStr             string `json:"old_str,omitempty"`
+	NewStr             string `json:"new_str"`
+	AppendToSection    string `json:"append_to_section,omitempty"`
+	InsertAfterHeading string `json:"insert_after_heading,omitempty"`
+	ReplaceLine        int    `json:"replace_line,omitempty"`
 }
 
 type editBatchInput struct {
@@ -68,6 +74,7 @@ type editBatchInput struct {
 }
 
 type EditResult struct {
+	Op      string
 	Index   int
 	Line    int
 	OldStr  string
@@ -101,8 +108,8 @@ func ParseEditsJSON(raw string) ([]EditSpec, error) {
 		return nil, newError(CodeInvalidInput, "invalid --edits-json payload", `Provide an object like: --edits-json '{"edits":[{"old_str":"from","new_str":"to"}]}'`, map[string]string{"error": "edits must contain at least one item"}, nil)
 	}
 	for i, edit := range input.Edits {
-		if edit.OldStr == "" {
-			return nil, newError(CodeInvalidInput, "invalid --edits-json payload", `Provide an object like: --edits-json '{"edits":[{"old_str":"from","new_str":"to"}]}'`, map[string]string{"error": fmt.Sprintf("edits[%d].old_str must be non-empty", i)}, nil)
+		if err := edit.validate(); err != nil {
+			return nil, newError(CodeInvalidInput, "invalid --edits-json payload", `Provide an object like: --edits-json '{"edits":[{"old_str":"from","new_str":"to"}]}'`, map[string]string{"error": fmt.Sprintf("edits[%d]: %s", i, err)}, err)
 		}
 	}
 	return input.Edits, nil
@@ -118,6 +125,17 @@ func ApplyEditsInMemoryWithScope(content, relPath string, edits []EditSpec, scop
 	activeScope := normalizeScope(scope)
 
 	for i, edit := range edits {
+		if op := edit.Op(); op != OpReplace {
+			var result EditResult
+			var err error
+			updated, result, err = applyStructuredEdit(updated, relPath, i+1, edit, activeScope)
+			if err != nil {
+				return "", nil, err
+			}
+			results = append(results, result)
+			continue
+		}
+
 		searchStart, searchEnd := 0, len(updated)
 		if activeScope != nil {
 			searchStart, searchEnd = lineRangeOffsets(updated, *activeScope)
@@ -165,6 +183,7 @@ func ApplyEditsInMemoryWithScope(content, relPath string, edits []EditSpec, scop
 			activeScope.EndLine = adjustEndLine(activeScope.EndLine, edit.OldStr, edit.NewStr)
 		}
 		results = append(results, EditResult{
+			Op:      OpReplace,
 			Index:   editIndex,
 			Line:    lineNumber,
 			OldStr:  edit.OldStr,
@@ -101,3 +101,53 @@ func TestAsErrorWithWrappedError(t *testing.T) {
 		t.Fatalf("expected AsError to recover wrapped editsvc error, got %#v ok=%v", got, ok)
 	}
 }
+
+func TestApplyStructuredEdits(t *testing.T) {
+	t.Parallel()
+	content := "---\ntitle: Notes\n---\n# Notes\n## Log\n- one\n### Detail\ndetail\n\n## Tasks\n```\n## Log\n```\n"
+
+	t.Run("appends to section subtree and inserts after heading", func(t *testing.T) {
+		updated, results, err := ApplyEditsInMemory(content, "notes/test.md", []EditSpec{
+			{AppendToSection: "## Log", NewStr: "- two"},
+			{InsertAfterHeading: "tasks", NewStr: "- [ ] review"},
+			{ReplaceLine: 6, NewStr: "- first"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := "---\ntitle: Notes\n---\n# Notes\n## Log\n- first\n### Detail\ndetail\n- two\n\n## Tasks\n- [ ] review\n```\n## Log\n```\n"
+		if updated != want {
+			t.Fatalf("updated =\n%s\nwant\n%s", updated, want)
+		}
+		if results[0].Op != OpAppendToSection || results[0].Line != 9 {
+			t.Fatalf("append result = %+v, want line 9", results[0])
+		}
+		if results[2].OldStr != "- one" {
+			t.Fatalf("replace_line old_str = %q, want %q", results[2].OldStr, "- one")
+		}
+	})
+
+	t.Run("replace_line refuses frontmatter", func(t *testing.T) {
+		_, _, err := ApplyEditsInMemory(content, "notes/test.md", []EditSpec{{ReplaceLine: 2, NewStr: "title: x"}})
+		assertServiceCode(t, err, CodeInvalidInput)
+	})
+
+	t.Run("missing and ambiguous headings", func(t *testing.T) {
+		_, _, err := ApplyEditsInMemory(content, "notes/test.md", []EditSpec{{AppendToSection: "## Missing", NewStr: "x"}})
+		assertServiceCode(t, err, CodeStringNotFound)
+		_, _, err = ApplyEditsInMemory("# A\n## Log\n# B\n## Log\n", "notes/test.md", []EditSpec{{AppendToSection: "Log", NewStr: "x"}})
+		assertServiceCode(t, err, CodeMultipleMatches)
+	})
+
+	t.Run("edits-json accepts structured edits", func(t *testing.T) {
+		edits, err := ParseEditsJSON(`{"edits":[{"append_to_section":"## Log","new_str":"x"},{"replace_line":3,"new_str":""}]}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if edits[0].Op() != OpAppendToSection || edits[1].Op() != OpReplaceLine {
+			t.Fatalf("ops = %s, %s", edits[0].Op(), edits[1].Op())
+		}
+		_, err = ParseEditsJSON(`{"edits":[{"old_str":"a","append_to_section":"## Log","new_str":"x"}]}`)
+		assertServiceCode(t, err, CodeInvalidInput)
+	})
+}
@@ -0,0 +1,244 @@
+package editsvc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/parser"
+)
+
+// Edit operations. OpReplace is the default old_str/new_str replacement; the
+// others are structured edits addressed by heading or line number.
+const (
+	OpReplace            = "replace"
+	OpAppendToSection    = "append_to_section"
+	OpInsertAfterHeading = "insert_after_heading"
+	OpReplaceLine        = "replace_line"
+)
+
+// Op returns the operation the edit performs.
+func (e EditSpec) Op() string {
+	switch {
+	case e.AppendToSection != "":
+		return OpAppendToSection
+	case e.InsertAfterHeading != "":
+		return OpInsertAfterHeading
+	case e.ReplaceLine != 0:
+		return OpReplaceLine
+	default:
+		return OpReplace
+	}
+}
+
+// ValidateEdit checks that an edit names exactly one operation and carries the
+// text it needs.
+func ValidateEdit(edit EditSpec) error {
+	if err := edit.validate(); err != nil {
+		return newError(CodeInvalidInput, err.Error(), `Pass old_str/new_str, or one of append_to_section, insert_after_heading, replace_line with new_str`, nil, err)
+	}
+	return nil
+}
+
+func (e EditSpec) validate() error {
+	set := 0
+	for _, present := range []bool{e.OldStr != "", e.AppendToSection != "", e.InsertAfterHeading != "", e.ReplaceLine != 0} {
+		if present {
+			set++
+		}
+	}
+	switch {
+	case set == 0:
+		return fmt.Errorf("old_str must be non-empty")
+	case set > 1:
+		return fmt.Errorf("set only one of old_str, append_to_section, insert_after_heading, replace_line")
+	case e.ReplaceLine < 0:
+		return fmt.Errorf("replace_line must be a positive line number")
+	case e.Op() != OpReplace && e.Op() != OpReplaceLine && e.NewStr == "":
+		return fmt.Errorf("new_str must be non-empty for %s", e.Op())
+	}
+	return nil
+}
+
+// applyStructuredEdit applies an append_to_section, insert_after_heading, or
+// replace_line edit. Headings are matched outside frontmatter and fenced code,
+// and replace_line refuses frontmatter lines.
+func applyStructuredEdit(content, relPath string, editIndex int, edit EditSpec, scope *EditScope) (string, EditResult, error) {
+	lines := strings.Split(content, "\n")
+	lineCount := len(lines)
+	if lineCount > 0 && lines[lineCount-1] == "" {
+		lineCount--
+	}
+	bodyStart := 0
+	if _, end, ok := parser.FrontmatterBounds(lines); ok {
+		bodyStart = lineCount
+		if end >= 0 {
+			bodyStart = end + 1
+		}
+	}
+	rangeStart, rangeEnd := bodyStart, lineCount
+	if scope != nil {
+		rangeStart = max(rangeStart, scope.StartLine-1)
+		if scope.EndLine > 0 {
+			rangeEnd = min(rangeEnd, scope.EndLine)
+		}
+	}
+
+	op := edit.Op()
+	details := func(extra map[string]string) map[string]string {
+		return errorDetails(relPath, editIndex, "", scope, map[string]string{"op": op}, extra)
+	}
+
+	var (
+		insertIdx   int
+		removeCount int
+		oldStr      string
+	)
+	switch op {
+	case OpReplaceLine:
+		lineNum := edit.ReplaceLine
+		if lineNum > lineCount {
+			return "", EditResult{}, newError(CodeInvalidInput, fmt.Sprintf("line %d is out of range (file has %d lines)", lineNum, lineCount), "Use a line number from 'rvn read --raw --lines'", details(nil), nil)
+		}
+		if lineNum <= bodyStart {
+			return "", EditResult{}, newError(CodeInvalidInput, fmt.Sprintf("line %d is inside frontmatter", lineNum), "Use 'rvn set' to change frontmatter fields", details(nil), nil)
+		}
+		if lineNum <= rangeStart || lineNum > rangeEnd {
+			return "", EditResult{}, newError(CodeInvalidInput, fmt.Sprintf("line %d is outside the selected section", lineNum), "Target the whole file or pick a line inside the section", details(nil), nil)
+		}
+		insertIdx, removeCount, oldStr = lineNum-1, 1, lines[lineNum-1]
+	default:
+		spec := edit.AppendToSection
+		if op == OpInsertAfterHeading {
+			spec = edit.InsertAfterHeading
+		}
+		headingIdx, level, err := findHeading(lines, rangeStart, rangeEnd, spec)
+		if err != nil {
+			code := CodeStringNotFound
+			if err == errHeadingAmbiguous {
+				code = CodeMultipleMatches
+			}
+			return "", EditResult{}, newError(code, fmt.Sprintf("heading %q %s", spec, err), "Use the exact heading line, e.g. \"## Log\"", details(map[string]string{"heading": spec}), nil)
+		}
+		insertIdx = headingIdx + 1
+		if op == OpAppendToSection {
+			insertIdx = sectionEnd(lines, headingIdx, level, rangeEnd)
+			for insertIdx > headingIdx+1 && strings.TrimSpace(lines[insertIdx-1]) == "" {
+				insertIdx--
+			}
+		}
+	}
+
+	var inserted []string
+	if newStr := strings.TrimSuffix(edit.NewStr, "\n"); newStr != "" || op != OpReplaceLine {
+		inserted = strings.Split(newStr, "\n")
+	}
+	updatedLines := make([]string, 0, len(lines)+len(inserted))
+	updatedLines = append(updatedLines, lines[:insertIdx]...)
+	updatedLines = append(updatedLines, inserted...)
+	updatedLines = append(updatedLines, lines[insertIdx+removeCount:]...)
+	updated := strings.Join(updatedLines, "\n")
+
+	if scope != nil && scope.EndLine > 0 {
+		scope.EndLine += len(inserted) - removeCount
+	}
+	after := extractContext(updated, lineStartOffset(updated, insertIdx+1))
+	return updated, EditResult{
+		Op:      op,
+		Index:   editIndex,
+		Line:    insertIdx + 1,
+		OldStr:  oldStr,
+		NewStr:  edit.NewStr,
+		Before:  extractContext(content, lineStartOffset(content, insertIdx+1)),
+		After:   after,
+		Context: after,
+	}, nil
+}
+
+type headingError string
+
+func (e headingError) Error() string { return string(e) }
+
+const (
+	errHeadingNotFound  = headingError("not found")
+	errHeadingAmbiguous = headingError("matches more than one heading")
+)
+
+// findHeading returns the index and level of the heading named by spec within
+// lines[start:end]. A spec like "## Log" must match level and text; bare text
+// matches a heading of any level. Text comparison ignores case.
+func findHeading(lines []string, start, end int, spec string) (int, int, error) {
+	wantLevel, wantText := headingLine(spec)
+	if wantLevel == 0 {
+		wantText = strings.TrimSpace(spec)
+	}
+
+	found, foundLevel, matches := -1, 0, 0
+	fence := ""
+	for i := start; i < end; i++ {
+		if fence, _ = codeFence(lines[i], fence); fence != "" {
+			continue
+		}
+		level, text := headingLine(lines[i])
+		if level == 0 || !strings.EqualFold(text, wantText) || (wantLevel != 0 && level != wantLevel) {
+			continue
+		}
+		found, foundLevel = i, level
+		matches++
+	}
+	switch matches {
+	case 0:
+		return -1, 0, errHeadingNotFound
+	case 1:
+		return found, foundLevel, nil
+	default:
+		return -1, 0, errHeadingAmbiguous
+	}
+}
+
+// sectionEnd returns the index of the first line after the section whose
+// heading is at headingIdx: the next heading of the same or a higher level.
+func sectionEnd(lines []string, headingIdx, level, end int) int {
+	fence := ""
+	for i := headingIdx + 1; i < end; i++ {
+		var isFence bool
+		if fence, isFence = codeFence(lines[i], fence); fence != "" || isFence {
+			continue
+		}
+		if l, _ := headingLine(lines[i]); l > 0 && l <= level {
+			return i
+		}
+	}
+	return end
+}
+
+// headingLine parses an ATX heading, returning level 0 for other lines.
+func headingLine(line string) (int, string) {
+	trimmed := strings.TrimSpace(line)
+	level := 0
+	for level < len(trimmed) && trimmed[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || (level < len(trimmed) && trimmed[level] != ' ') {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(trimmed[level:])
+}
+
+// codeFence tracks fenced code blocks. Given the currently open fence marker
+// ("" outside code), it returns the marker after line and whether line is a
+// fence delimiter.
+func codeFence(line, open string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	for _, marker := range []string{"```", "~~~"} {
+		if !strings.HasPrefix(trimmed, marker) {
+			continue
+		}
+		if open == "" {
+			return marker, true
+		}
+		if open == marker {
+			return "", true
+		}
+	}
+	return open, false
+}
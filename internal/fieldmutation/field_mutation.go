@@ -3,7 +3,6 @@ package fieldmutation
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -18,6 +17,7 @@ import (
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/readsvc"
 	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 type ValidationError struct {
@@ -62,6 +62,26 @@ func (e *ValidationError) Suggestion() string {
 	return fmt.Sprintf("Ensure values match the schema field types for type '%s'", e.ObjectType)
 }
 
+// Details lists each failing field with the rule it breaks, when it breaks
+// one beyond the field type.
+func (e *ValidationError) Details() map[string]interface{} {
+	issues := make([]map[string]interface{}, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		entry := map[string]interface{}{
+			"field":   issue.Field,
+			"message": issue.Message,
+		}
+		if issue.Rule != "" {
+			entry["rule"] = issue.Rule
+		}
+		issues = append(issues, entry)
+	}
+	return map[string]interface{}{
+		"object_type": e.ObjectType,
+		"issues":      issues,
+	}
+}
+
 func (e *UnknownFieldMutationError) Error() string {
 	if len(e.Unknown) == 1 {
 		return fmt.Sprintf("unknown field '%s' for type '%s'", e.Unknown[0], e.ObjectType)
@@ -100,6 +120,10 @@ func PrepareValidatedFieldMutationValues(
 		return nil, nil, unknownErr
 	}
 
+	if err := checkLifecycleTransition(normalizedType, existingFields, coercedUpdates, sch); err != nil {
+		return nil, nil, err
+	}
+
 	merged := make(map[string]schema.FieldValue, len(existingFields)+len(coercedUpdates))
 	for key, value := range existingFields {
 		merged[key] = value
@@ -111,6 +135,9 @@ func PrepareValidatedFieldMutationValues(
 	if err := validateMergedFields(normalizedType, merged, sch, refCtx); err != nil {
 		return nil, nil, err
 	}
+	if err := checkUniqueFields(normalizedType, existingFields, coercedUpdates, fieldDefs, refCtx); err != nil {
+		return nil, nil, err
+	}
 
 	return coercedUpdates, nil, nil
 }
@@ -382,6 +409,12 @@ func parseFieldValueToSchema(value string) schema.FieldValue {
 	return parser.ParseFieldValue(value)
 }
 
+// UpdateFrontmatterFields sets the given fields in content's frontmatter,
+// leaving all other keys untouched. Content without frontmatter is an error.
+func UpdateFrontmatterFields(content string, updates map[string]schema.FieldValue) (string, error) {
+	return updateFrontmatterWithFieldValues(content, updates)
+}
+
 func updateFrontmatterWithFieldValues(content string, updates map[string]schema.FieldValue) (string, error) {
 	lines := strings.Split(content, "\n")
 
@@ -502,6 +535,32 @@ func validateMergedFields(objectType string, fields map[string]schema.FieldValue
 	}
 }
 
+// checkLifecycleTransition rejects an update that moves an object between
+// lifecycle states its type does not allow.
+func checkLifecycleTransition(objectType string, existing, updates map[string]schema.FieldValue, sch *schema.Schema) error {
+	if sch == nil {
+		return nil
+	}
+	typeDef := sch.Types[objectType]
+	if typeDef == nil || typeDef.Lifecycle == nil {
+		return nil
+	}
+	field := typeDef.Lifecycle.StateField()
+	next, ok := updates[field]
+	if !ok {
+		return nil
+	}
+	to, _ := next.AsString()
+	from, _ := existing[field].AsString()
+	if err := typeDef.Lifecycle.CheckTransition(from, to); err != nil {
+		return &ValidationError{
+			ObjectType: objectType,
+			Issues:     []schema.ValidationError{{Field: field, Message: err.Error()}},
+		}
+	}
+	return nil
+}
+
 func normalizedUnsetFields(fields []string) []string {
 	seen := make(map[string]struct{}, len(fields))
 	out := make([]string, 0, len(fields))
@@ -519,6 +578,61 @@ func normalizedUnsetFields(fields []string) []string {
 	return out
 }
 
+// checkUniqueFields rejects updates that give a unique field a value another
+// object of the type already has in the index. Unchanged values are not
+// rechecked, so an existing duplicate does not block unrelated edits.
+func checkUniqueFields(
+	objectType string,
+	existing map[string]schema.FieldValue,
+	updates map[string]schema.FieldValue,
+	fieldDefs map[string]*schema.FieldDefinition,
+	refCtx *RefValidationContext,
+) error {
+	if refCtx == nil || strings.TrimSpace(refCtx.VaultPath) == "" {
+		return nil
+	}
+	var names []string
+	for name, def := range fieldDefs {
+		if _, updated := updates[name]; updated && def != nil && def.Unique {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	db, err := index.Open(refCtx.VaultPath)
+	if err != nil {
+		return nil //nolint:nilerr // without an index there is nothing to compare against
+	}
+	defer db.Close()
+
+	var issues []schema.ValidationError
+	for _, name := range names {
+		key, ok := schema.UniqueValueKey(updates[name])
+		if !ok {
+			continue
+		}
+		if previous, ok := schema.UniqueValueKey(existing[name]); ok && previous == key {
+			continue
+		}
+		owners, err := db.ObjectIDsWithFieldValue(objectType, name, key)
+		if err != nil || len(owners) == 0 {
+			continue
+		}
+		issues = append(issues, schema.ValidationError{
+			Field:   name,
+			Message: fmt.Sprintf("value '%s' is already used by %s", key, strings.Join(owners, ", ")),
+			Rule:    schema.RuleUnique,
+		})
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	return &ValidationError{ObjectType: objectType, Issues: issues}
+}
+
 func validateRefTargets(
 	fields map[string]schema.FieldValue,
 	fieldDefs map[string]*schema.FieldDefinition,
@@ -591,7 +705,7 @@ func resolveReferenceType(rt *readsvc.Runtime, parseOpts *parser.ParseOptions, r
 		return "", err
 	}
 
-	content, err := os.ReadFile(resolved.FilePath)
+	content, err := vaultcrypt.ReadFile(resolved.FilePath)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,350 @@
+// Package fmtsvc normalizes markdown files to vault conventions for
+// `rvn fmt`: frontmatter key order, trait annotation spacing, wikilink style,
+// and heading capitalization.
+package fmtsvc
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/parser"
+)
+
+// Rule names, as used in fmt.rules and in results.
+const (
+	RuleFrontmatterOrder = "frontmatter_order"
+	RuleTraitSpacing     = "trait_spacing"
+	RuleWikilinks        = "wikilinks"
+	RuleHeadingCase      = "heading_case"
+)
+
+// Heading case styles.
+const (
+	HeadingCaseOff      = "off"
+	HeadingCaseSentence = "sentence"
+	HeadingCaseTitle    = "title"
+)
+
+// Rules selects the formatting applied by Format.
+type Rules struct {
+	FrontmatterOrder bool
+	KeyOrder         []string
+	TraitSpacing     bool
+	Wikilinks        bool
+	HeadingCase      string
+
+	// Traits limits trait_spacing to these trait names. Annotations for
+	// other names are left alone, since they may be ordinary text.
+	Traits map[string]bool
+}
+
+// RulesFromConfig returns the rules configured under fmt in raven.yaml.
+// frontmatter_order, trait_spacing, and wikilinks are on by default;
+// heading_case is off.
+func RulesFromConfig(cfg *config.FmtConfig) (Rules, error) {
+	rules := Rules{
+		FrontmatterOrder: true,
+		KeyOrder:         []string{"type"},
+		TraitSpacing:     true,
+		Wikilinks:        true,
+		HeadingCase:      HeadingCaseOff,
+	}
+	if cfg == nil {
+		return rules, nil
+	}
+	if len(cfg.KeyOrder) > 0 {
+		rules.KeyOrder = append([]string(nil), cfg.KeyOrder...)
+	}
+
+	names := make([]string, 0, len(cfg.Rules))
+	for name := range cfg.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.ToLower(strings.TrimSpace(cfg.Rules[name]))
+		if name == RuleHeadingCase {
+			switch value {
+			case HeadingCaseOff, HeadingCaseSentence, HeadingCaseTitle:
+				rules.HeadingCase = value
+			default:
+				return rules, fmt.Errorf("fmt rule %s must be off, sentence, or title (got %q)", name, cfg.Rules[name])
+			}
+			continue
+		}
+
+		var target *bool
+		switch name {
+		case RuleFrontmatterOrder:
+			target = &rules.FrontmatterOrder
+		case RuleTraitSpacing:
+			target = &rules.TraitSpacing
+		case RuleWikilinks:
+			target = &rules.Wikilinks
+		default:
+			return rules, fmt.Errorf("unknown fmt rule %q (use %s, %s, %s, or %s)", name, RuleFrontmatterOrder, RuleTraitSpacing, RuleWikilinks, RuleHeadingCase)
+		}
+		switch value {
+		case "on":
+			*target = true
+		case "off":
+			*target = false
+		default:
+			return rules, fmt.Errorf("fmt rule %s must be on or off (got %q)", name, cfg.Rules[name])
+		}
+	}
+	return rules, nil
+}
+
+// Format returns content normalized by rules and the names of the rules that
+// changed it. Fenced code blocks and inline code are never changed.
+func Format(content string, rules Rules) (string, []string) {
+	lines := strings.Split(content, "\n")
+	changed := map[string]bool{}
+
+	bodyStart := 0
+	if start, end, ok := parser.FrontmatterBounds(lines); ok && end > start {
+		if rules.FrontmatterOrder {
+			if ordered, ok := orderFrontmatter(lines[start+1:end], rules.KeyOrder); ok {
+				copy(lines[start+1:end], ordered)
+				changed[RuleFrontmatterOrder] = true
+			}
+		}
+		bodyStart = end + 1
+	}
+
+	var fence parser.FenceState
+	for i := bodyStart; i < len(lines); i++ {
+		if fence.UpdateFenceState(lines[i]) || fence.InFence {
+			continue
+		}
+		line := lines[i]
+		if rules.HeadingCase != "" && rules.HeadingCase != HeadingCaseOff {
+			if next := formatHeading(line, rules.HeadingCase); next != line {
+				line = next
+				changed[RuleHeadingCase] = true
+			}
+		}
+		if rules.Wikilinks {
+			if next := replaceOutsideCode(line, wikilinkPattern, false, formatWikilink); next != line {
+				line = next
+				changed[RuleWikilinks] = true
+			}
+		}
+		if rules.TraitSpacing {
+			next := replaceOutsideCode(line, traitPattern, true, func(match string) string {
+				return formatTrait(match, rules.Traits)
+			})
+			if next != line {
+				line = next
+				changed[RuleTraitSpacing] = true
+			}
+		}
+		lines[i] = line
+	}
+
+	names := make([]string, 0, len(changed))
+	for name := range changed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(lines, "\n"), names
+}
+
+var topLevelKeyPattern = regexp.MustCompile(`^([A-Za-z0-9_][^:#]*?|"[^"]+"|'[^']+'):(\s|$)`)
+
+// orderFrontmatter reorders top-level frontmatter keys: keyOrder first, then
+// the rest alphabetically. Each key keeps its value lines and the comments
+// above it. It reports false when the keys are already in order or the block
+// cannot be reordered without changing its meaning.
+func orderFrontmatter(lines []string, keyOrder []string) ([]string, bool) {
+	type entry struct {
+		key   string
+		lines []string
+	}
+	var entries []entry
+	var pending []string
+	for _, line := range lines {
+		if match := topLevelKeyPattern.FindStringSubmatch(line); match != nil {
+			key := strings.Trim(strings.TrimSpace(match[1]), `"'`)
+			entries = append(entries, entry{key: key, lines: append(pending, line)})
+			pending = nil
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			pending = append(pending, line)
+			continue
+		}
+		if len(entries) == 0 || len(pending) > 0 {
+			// Value lines before any key, or after a comment, are not
+			// something we can safely move.
+			return nil, false
+		}
+		entries[len(entries)-1].lines = append(entries[len(entries)-1].lines, line)
+	}
+	if len(entries) < 2 {
+		return nil, false
+	}
+
+	rank := make(map[string]int, len(keyOrder))
+	for i, key := range keyOrder {
+		rank[key] = i
+	}
+	sorted := append([]entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, iRanked := rank[sorted[i].key]
+		rj, jRanked := rank[sorted[j].key]
+		switch {
+		case iRanked && jRanked:
+			return ri < rj
+		case iRanked != jRanked:
+			return iRanked
+		default:
+			return sorted[i].key < sorted[j].key
+		}
+	})
+
+	inOrder := true
+	for i := range entries {
+		if entries[i].key != sorted[i].key {
+			inOrder = false
+			break
+		}
+	}
+	if inOrder {
+		return nil, false
+	}
+
+	var out []string
+	for _, e := range sorted {
+		out = append(out, e.lines...)
+	}
+	out = append(out, pending...)
+
+	// Reordering must not change what the frontmatter says.
+	var before, after map[string]interface{}
+	if yaml.Unmarshal([]byte(strings.Join(lines, "\n")), &before) != nil ||
+		yaml.Unmarshal([]byte(strings.Join(out, "\n")), &after) != nil ||
+		!reflect.DeepEqual(before, after) {
+		return nil, false
+	}
+	return out, true
+}
+
+var (
+	wikilinkPattern = regexp.MustCompile(`\[\[([^\]\[|]+)(?:\|([^\]]+))?\]\]`)
+	traitPattern    = regexp.MustCompile(`@([\w-]+)\s*\(([^)]*)\)`)
+	headingPattern  = regexp.MustCompile(`^(#{1,6})(\s+)(.*?)$`)
+)
+
+// formatWikilink trims spaces around the target and alias and drops a .md
+// extension from the target: [[ people/freya.md | Freya ]] -> [[people/freya|Freya]].
+func formatWikilink(match string) string {
+	parts := wikilinkPattern.FindStringSubmatch(match)
+	target := strings.TrimSpace(parts[1])
+	fragment := ""
+	if idx := strings.Index(target, "#"); idx >= 0 {
+		target, fragment = target[:idx], target[idx:]
+	}
+	target = strings.TrimSuffix(target, ".md") + fragment
+	if target == "" {
+		return match
+	}
+	if alias := strings.TrimSpace(parts[2]); alias != "" {
+		return "[[" + target + "|" + alias + "]]"
+	}
+	return "[[" + target + "]]"
+}
+
+// formatTrait removes spaces between a trait name and its value and inside
+// the parentheses: @due ( 2026-01-31 ) -> @due(2026-01-31).
+func formatTrait(match string, traits map[string]bool) string {
+	parts := traitPattern.FindStringSubmatch(match)
+	if traits != nil && !traits[parts[1]] {
+		return match
+	}
+	return "@" + parts[1] + "(" + strings.TrimSpace(parts[2]) + ")"
+}
+
+// replaceOutsideCode replaces pattern matches in line that are not inside
+// inline code spans. With wordBoundary, matches directly after a letter or
+// digit (such as the @ in an email address) are left alone.
+func replaceOutsideCode(line string, pattern *regexp.Regexp, wordBoundary bool, replace func(string) string) string {
+	masked := parser.RemoveInlineCode(line)
+	matches := pattern.FindAllStringIndex(masked, -1)
+	if len(matches) == 0 {
+		return line
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if wordBoundary && m[0] > 0 {
+			if prev, _ := utf8.DecodeLastRuneInString(masked[:m[0]]); unicode.IsLetter(prev) || unicode.IsDigit(prev) {
+				continue
+			}
+		}
+		b.WriteString(line[last:m[0]])
+		b.WriteString(replace(line[m[0]:m[1]]))
+		last = m[1]
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+// smallWords stay lowercase inside title-case headings.
+var smallWords = map[string]bool{
+	"a": true, "an": true, "and": true, "as": true, "at": true, "but": true, "by": true,
+	"for": true, "in": true, "of": true, "on": true, "or": true, "the": true, "to": true, "vs": true, "with": true,
+}
+
+// formatHeading applies a capitalization style to an ATX heading. Only the
+// case of lowercase-initial words changes, so section IDs stay the same and
+// acronyms, names, links, and traits are untouched.
+func formatHeading(line, style string) string {
+	parts := headingPattern.FindStringSubmatch(line)
+	if parts == nil || parts[3] == "" {
+		return line
+	}
+	words := strings.Split(parts[3], " ")
+	firstWord := -1
+	lastWord := -1
+	for i, word := range words {
+		if word != "" {
+			if firstWord < 0 {
+				firstWord = i
+			}
+			lastWord = i
+		}
+	}
+	for i, word := range words {
+		if word == "" {
+			continue
+		}
+		switch {
+		case i == firstWord:
+			words[i] = capitalize(word)
+		case style == HeadingCaseTitle && (i == lastWord || !smallWords[strings.ToLower(word)]):
+			words[i] = capitalize(word)
+		}
+	}
+	return parts[1] + parts[2] + strings.Join(words, " ")
+}
+
+// capitalize uppercases the first letter of an all-lowercase word. Words
+// with any capital letter, such as iPhone, are left as written.
+func capitalize(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	if !unicode.IsLower(r) || strings.ToLower(word) != word {
+		return word
+	}
+	return string(unicode.ToUpper(r)) + word[size:]
+}
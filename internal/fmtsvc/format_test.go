@@ -0,0 +1,129 @@
+package fmtsvc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	defaults, err := RulesFromConfig(nil)
+	if err != nil {
+		t.Fatalf("RulesFromConfig(nil) error = %v", err)
+	}
+	defaults.Traits = map[string]bool{"due": true}
+	titleCase := defaults
+	titleCase.HeadingCase = HeadingCaseTitle
+	sentenceCase := defaults
+	sentenceCase.HeadingCase = HeadingCaseSentence
+
+	tests := []struct {
+		name      string
+		rules     Rules
+		content   string
+		want      string
+		wantRules []string
+	}{
+		{
+			name:      "orders frontmatter keys with comments",
+			rules:     defaults,
+			content:   "---\nstatus: active\n# kind\ntype: project\ntags:\n  - a\n---\nBody\n",
+			want:      "---\n# kind\ntype: project\nstatus: active\ntags:\n  - a\n---\nBody\n",
+			wantRules: []string{RuleFrontmatterOrder},
+		},
+		{
+			name:      "normalizes traits and wikilinks outside code",
+			rules:     defaults,
+			content:   "- @due ( 2026-01-31 ) see [[ people/freya.md#notes | Freya ]]\n- me@due ( x ) `@due ( y )` @other ( z )\n```\n@due ( z )\n```\n",
+			want:      "- @due(2026-01-31) see [[people/freya#notes|Freya]]\n- me@due ( x ) `@due ( y )` @other ( z )\n```\n@due ( z )\n```\n",
+			wantRules: []string{RuleTraitSpacing, RuleWikilinks},
+		},
+		{
+			name:      "title case keeps small words and capitals",
+			rules:     titleCase,
+			content:   "## notes on the iPhone and API of raven\n",
+			want:      "## Notes on the iPhone and API of Raven\n",
+			wantRules: []string{RuleHeadingCase},
+		},
+		{
+			name:      "sentence case capitalizes the first word only",
+			rules:     sentenceCase,
+			content:   "# getting started\n",
+			want:      "# Getting started\n",
+			wantRules: []string{RuleHeadingCase},
+		},
+		{
+			name:    "formatted content is unchanged",
+			rules:   titleCase,
+			content: "---\ntype: project\nalpha: 1\n---\n# Done\n\n@due(2026-01-31) [[people/freya]]\n",
+			want:    "---\ntype: project\nalpha: 1\n---\n# Done\n\n@due(2026-01-31) [[people/freya]]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, gotRules := Format(tt.content, tt.rules)
+			if got != tt.want {
+				t.Errorf("Format() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if len(gotRules) == 0 && len(tt.wantRules) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(gotRules, tt.wantRules) {
+				t.Errorf("Format() rules = %v, want %v", gotRules, tt.wantRules)
+			}
+		})
+	}
+}
+
+func TestRulesFromConfig_RejectsInvalidRules(t *testing.T) {
+	t.Parallel()
+
+	for _, cfg := range []*config.FmtConfig{
+		{Rules: map[string]string{"spacing": "on"}},
+		{Rules: map[string]string{RuleWikilinks: "maybe"}},
+		{Rules: map[string]string{RuleHeadingCase: "upper"}},
+	} {
+		if _, err := RulesFromConfig(cfg); err == nil {
+			t.Errorf("RulesFromConfig(%+v) succeeded, want error", cfg.Rules)
+		}
+	}
+}
+
+func TestRun_PreviewsThenWritesAndSkipsTemplates(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).
+		WithFile("notes/a.md", "See [[ b.md ]]\n").
+		WithFile("notes/b.md", "Already [[a]]\n").
+		WithFile("templates/t.md", "See [[ {{title}}.md ]]\n").
+		Build()
+	cfg := &config.VaultConfig{}
+	rules, _ := RulesFromConfig(nil)
+
+	result, err := Run(Request{VaultPath: vault.Path, VaultCfg: cfg, Rules: rules})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Checked != 2 || len(result.Files) != 1 || result.Files[0].Path != "notes/a.md" || len(result.Written) != 0 {
+		t.Fatalf("preview result = %+v", result)
+	}
+
+	if _, err := Run(Request{VaultPath: vault.Path, VaultCfg: cfg, Rules: rules, Path: "notes/a", Apply: true}); err != nil {
+		t.Fatalf("Run(apply) error = %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(vault.Path, "notes", "a.md"))
+	if err != nil {
+		t.Fatalf("read formatted file: %v", err)
+	}
+	if string(content) != "See [[b]]\n" {
+		t.Errorf("formatted file = %q", content)
+	}
+}
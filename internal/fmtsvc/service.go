@@ -0,0 +1,160 @@
+package fmtsvc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/config"
+	ravenignore "github.com/aidanlsb/raven/internal/ignore"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/readsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vault"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+// Request describes a formatting run.
+type Request struct {
+	VaultPath string
+	VaultCfg  *config.VaultConfig
+	Schema    *schema.Schema
+	Rules     Rules
+
+	// Path is a file, directory, or reference; empty formats the whole vault.
+	Path string
+
+	// Apply writes formatted files. Without it, Run only reports them.
+	Apply bool
+
+	// CanWrite reports whether a file may be rewritten (for example, that it
+	// is not locked). Files it rejects are reported as skipped.
+	CanWrite func(absPath string) error
+}
+
+// FileResult names a file that formatting changes and the rules that
+// changed it.
+type FileResult struct {
+	Path  string   `json:"path"`
+	Rules []string `json:"rules"`
+}
+
+// SkippedFile is a file that was not formatted and why.
+type SkippedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// Result reports the files a run checked, changed, and skipped.
+type Result struct {
+	Checked int
+	Files   []FileResult
+	Skipped []SkippedFile
+	// Written holds the absolute paths of files rewritten when applying.
+	Written []string
+}
+
+// Run formats the markdown files in scope. Template files are not formatted,
+// since their bodies are template source rather than notes.
+func Run(req Request) (*Result, error) {
+	rules := req.Rules
+	if rules.Traits == nil && req.Schema != nil {
+		rules.Traits = make(map[string]bool, len(req.Schema.Traits))
+		for name := range req.Schema.Traits {
+			rules.Traits[name] = true
+		}
+	}
+
+	files, err := filesInScope(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Files: []FileResult{}, Skipped: []SkippedFile{}}
+	templateDir := paths.NormalizeDirRoot(req.VaultCfg.GetTemplateDirectory())
+	for _, absPath := range files {
+		relPath, err := filepath.Rel(req.VaultPath, absPath)
+		if err != nil {
+			return nil, err
+		}
+		relPath = paths.NormalizeVaultRelPath(relPath)
+		if templateDir != "" && strings.HasPrefix(relPath, templateDir) {
+			continue
+		}
+		result.Checked++
+
+		content, err := vaultcrypt.ReadFile(absPath)
+		if err != nil {
+			result.Skipped = append(result.Skipped, SkippedFile{Path: relPath, Reason: err.Error()})
+			continue
+		}
+		formatted, changed := Format(string(content), rules)
+		if len(changed) == 0 {
+			continue
+		}
+		if req.Apply && req.CanWrite != nil {
+			if err := req.CanWrite(absPath); err != nil {
+				result.Skipped = append(result.Skipped, SkippedFile{Path: relPath, Reason: err.Error()})
+				continue
+			}
+		}
+		result.Files = append(result.Files, FileResult{Path: relPath, Rules: changed})
+		if !req.Apply {
+			continue
+		}
+		if err := atomicfile.WriteFile(absPath, []byte(formatted), 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", relPath, err)
+		}
+		result.Written = append(result.Written, absPath)
+	}
+	return result, nil
+}
+
+// filesInScope returns the absolute paths of the markdown files to format.
+func filesInScope(req Request) ([]string, error) {
+	pathArg := strings.TrimSpace(req.Path)
+	walkRoot := req.VaultPath
+	if pathArg != "" {
+		fullPath := filepath.Join(req.VaultPath, pathArg)
+		if err := paths.ValidateWithinVault(req.VaultPath, fullPath); err != nil {
+			return nil, fmt.Errorf("path %q is outside the vault", pathArg)
+		}
+		if info, err := os.Stat(fullPath); err == nil && info.IsDir() {
+			walkRoot = fullPath
+		} else {
+			filePath := fullPath
+			if !strings.HasSuffix(filePath, ".md") {
+				filePath += ".md"
+			}
+			if info, err := os.Stat(filePath); err == nil && !info.IsDir() {
+				return []string{filePath}, nil
+			}
+			rt := &readsvc.Runtime{VaultPath: req.VaultPath, VaultCfg: req.VaultCfg, Schema: req.Schema}
+			resolved, err := readsvc.ResolveReference(pathArg, rt, false)
+			if err != nil {
+				return nil, fmt.Errorf("could not resolve '%s': %w", pathArg, err)
+			}
+			return []string{resolved.FilePath}, nil
+		}
+	}
+
+	excludeMatcher, err := ravenignore.NewMatcher(req.VaultCfg.GetExcludePatterns())
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude config: %w", err)
+	}
+	var files []string
+	walkErr := vault.WalkMarkdownFilesWithOptions(req.VaultPath, &vault.WalkOptions{ExcludeMatcher: excludeMatcher}, func(walkResult vault.WalkResult) error {
+		if walkRoot == req.VaultPath || strings.HasPrefix(walkResult.Path, walkRoot+string(filepath.Separator)) {
+			files = append(files, walkResult.Path)
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("error walking vault: %w", walkErr)
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,455 @@
+// Package gitsvc commits vault changes to the git repository that holds the
+// vault and syncs it with a remote.
+//
+// Commit messages are generated from the changed files: the subject names
+// the changed objects and the body lists them by kind of change. Only paths
+// inside the vault are staged, so a vault kept in a subdirectory of a larger
+// repository never commits the rest of the work tree.
+package gitsvc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/paths"
+)
+
+type Code = codes.ErrorCode
+
+const (
+	CodeInvalidInput  Code = codes.ErrInvalidInput
+	CodeConfigInvalid Code = codes.ErrConfigInvalid
+	CodeGitFailed     Code = codes.ErrGitFailed
+	CodeMergeConflict Code = codes.ErrMergeConflict
+)
+
+type Error struct {
+	Code       Code
+	Message    string
+	Suggestion string
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e == nil {
+		return ""
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return string(e.Code)
+}
+
+func (e *Error) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+func newError(code Code, message, suggestion string, err error) *Error {
+	return &Error{Code: code, Message: message, Suggestion: suggestion, Err: err}
+}
+
+func AsError(err error) (*Error, bool) {
+	var svcErr *Error
+	if !errors.As(err, &svcErr) {
+		return nil, false
+	}
+	return svcErr, true
+}
+
+// ErrNotRepository is returned when the vault is not inside a git work tree.
+var ErrNotRepository = errors.New("vault is not in a git repository")
+
+// Change kinds reported for changed files.
+const (
+	StatusAdded    = "added"
+	StatusModified = "modified"
+	StatusDeleted  = "deleted"
+	StatusRenamed  = "renamed"
+)
+
+// FileChange is one changed file in the vault.
+type FileChange struct {
+	Path     string `json:"path"`
+	ObjectID string `json:"object_id,omitempty"`
+	Status   string `json:"status"`
+	// From is the original path of a renamed file.
+	From string `json:"from,omitempty"`
+}
+
+type SyncRequest struct {
+	VaultPath   string
+	VaultConfig *config.VaultConfig
+	// Message replaces the generated commit message.
+	Message string
+	NoPull  bool
+	NoPush  bool
+	DryRun  bool
+	Context context.Context
+}
+
+type SyncResult struct {
+	Branch  string
+	Remote  string
+	Changes []FileChange
+	Message string
+	// Commit is the short hash of the commit made for local changes.
+	Commit string
+	// PulledFiles lists vault files changed by commits pulled from the
+	// remote, so the caller can bring the index up to date.
+	PulledFiles []string
+	Pushed      bool
+	DryRun      bool
+}
+
+// Sync commits the vault's uncommitted changes, pulls the remote branch
+// (merging, never rebasing), and pushes the result. Pull and push are
+// skipped when the default remote does not exist.
+func Sync(req SyncRequest) (*SyncResult, error) {
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	repo := &repo{ctx: ctx, dir: req.VaultPath}
+	if err := repo.check(); err != nil {
+		return nil, err
+	}
+
+	changes, err := repo.status(req.VaultConfig)
+	if err != nil {
+		return nil, err
+	}
+	branch, err := repo.branch(req.VaultConfig.GetGitBranch())
+	if err != nil {
+		return nil, err
+	}
+	remote, err := repo.remote(req.VaultConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{Branch: branch, Remote: remote, Changes: changes, DryRun: req.DryRun}
+	if len(changes) > 0 {
+		result.Message = strings.TrimSpace(req.Message)
+		if result.Message == "" {
+			result.Message = CommitMessage("", changes)
+		}
+	}
+	if req.DryRun {
+		return result, nil
+	}
+
+	if len(changes) > 0 {
+		if result.Commit, err = repo.commit(changedPaths(changes), result.Message); err != nil {
+			return nil, err
+		}
+	}
+	if remote == "" {
+		return result, nil
+	}
+
+	if !req.NoPull {
+		result.PulledFiles, err = repo.pull(remote, branch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !req.NoPush {
+		if _, err := repo.run("push", "-q", remote, "HEAD:"+branch); err != nil {
+			return nil, err
+		}
+		result.Pushed = true
+	}
+	return result, nil
+}
+
+// CommitFiles commits the given vault-relative files, and only those, with
+// a message generated from them under subject. It returns the short commit
+// hash, or "" when none of the files differ from HEAD.
+func CommitFiles(vaultPath string, vaultCfg *config.VaultConfig, files []string, subject string) (string, error) {
+	repo := &repo{ctx: context.Background(), dir: vaultPath}
+	if err := repo.check(); err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", nil
+	}
+	// Match against the vault's status rather than passing files to git
+	// directly: status leaves out ignored files (such as .trash/) and files
+	// that were created and removed again without ever being tracked.
+	status, err := repo.status(vaultCfg)
+	if err != nil {
+		return "", err
+	}
+	wanted := make(map[string]bool, len(files))
+	for _, file := range files {
+		wanted[filepath.ToSlash(file)] = true
+	}
+	var changes []FileChange
+	for _, change := range status {
+		if wanted[change.Path] || (change.From != "" && wanted[change.From]) {
+			changes = append(changes, change)
+		}
+	}
+	if len(changes) == 0 {
+		return "", nil
+	}
+	return repo.commit(changedPaths(changes), CommitMessage(subject, changes))
+}
+
+// changedPaths lists the paths to stage for changes, including the original
+// path of each rename.
+func changedPaths(changes []FileChange) []string {
+	var out []string
+	for _, change := range changes {
+		out = append(out, change.Path)
+		if change.From != "" {
+			out = append(out, change.From)
+		}
+	}
+	return out
+}
+
+// CommitMessage builds a commit message for changes. The subject is subject
+// when given, otherwise a summary of the changed objects; the body lists
+// every changed object grouped by kind of change.
+func CommitMessage(subject string, changes []FileChange) string {
+	names := make([]string, 0, len(changes))
+	groups := make(map[string][]string)
+	for _, change := range changes {
+		name := change.ObjectID
+		if name == "" {
+			name = change.Path
+		}
+		names = append(names, name)
+		groups[change.Status] = append(groups[change.Status], name)
+	}
+
+	if subject == "" {
+		const shown = 3
+		subject = "Update " + strings.Join(names[:min(len(names), shown)], ", ")
+		if len(names) > shown {
+			subject += fmt.Sprintf(" and %d more", len(names)-shown)
+		}
+	}
+
+	var body strings.Builder
+	body.WriteString(subject)
+	body.WriteString("\n")
+	for _, group := range []struct{ status, label string }{
+		{StatusAdded, "Added"},
+		{StatusModified, "Modified"},
+		{StatusRenamed, "Renamed"},
+		{StatusDeleted, "Deleted"},
+	} {
+		if len(groups[group.status]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&body, "\n%s:\n", group.label)
+		for _, name := range groups[group.status] {
+			fmt.Fprintf(&body, "- %s\n", name)
+		}
+	}
+	return strings.TrimRight(body.String(), "\n")
+}
+
+// repo runs git in the vault directory.
+type repo struct {
+	ctx context.Context
+	dir string
+}
+
+func (r *repo) run(args ...string) (string, error) {
+	out, err := r.output(args...)
+	if err != nil {
+		return "", newError(CodeGitFailed, fmt.Sprintf("git %s failed", args[0]), "", err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// commit stages files (vault-relative) and commits exactly those, leaving
+// anything else already staged in the repository alone.
+func (r *repo) commit(files []string, message string) (string, error) {
+	if _, err := r.run(append([]string{"add", "-A", "--"}, files...)...); err != nil {
+		return "", err
+	}
+	if _, err := r.run(append([]string{"commit", "-q", "-m", message, "--only", "--"}, files...)...); err != nil {
+		return "", err
+	}
+	return r.run("rev-parse", "--short", "HEAD")
+}
+
+// output runs git and returns stdout; a failure carries git's stderr.
+func (r *repo) output(args ...string) (string, error) {
+	cmd := exec.CommandContext(r.ctx, "git", args...)
+	cmd.Dir = r.dir
+	// Never wait on a credential prompt: sync runs non-interactively too.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", errors.New(message)
+		}
+		return "", err
+	}
+	return string(out), nil
+}
+
+func (r *repo) check() error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return newError(CodeGitFailed, "git is not installed", "Install git and make sure it is on PATH", err)
+	}
+	if out, err := r.output("rev-parse", "--is-inside-work-tree"); err != nil || strings.TrimSpace(out) != "true" {
+		return newError(CodeInvalidInput, ErrNotRepository.Error(), "Run 'git init' in the vault (or a parent directory) first", ErrNotRepository)
+	}
+	return nil
+}
+
+// status lists uncommitted changes under the vault. Paths are reported
+// relative to the vault.
+func (r *repo) status(vaultCfg *config.VaultConfig) ([]FileChange, error) {
+	prefix, err := r.run("rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	out, err := r.output("status", "--porcelain=v1", "-z", "--untracked-files=all", "--", ".")
+	if err != nil {
+		return nil, newError(CodeGitFailed, "git status failed", "", err)
+	}
+
+	var changes []FileChange
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		xy, path := entry[:2], strings.TrimPrefix(entry[3:], prefix)
+		status, from := StatusModified, ""
+		switch {
+		case xy[0] == 'R' || xy[1] == 'R':
+			status = StatusRenamed
+			// The original path follows a rename.
+			if i+1 < len(entries) {
+				i++
+				from = strings.TrimPrefix(entries[i], prefix)
+			}
+		case xy == "??" || xy[0] == 'A':
+			status = StatusAdded
+		case xy[0] == 'D' || xy[1] == 'D':
+			status = StatusDeleted
+		}
+		if isRavenStatePath(path) {
+			continue
+		}
+		change := FileChange{Path: path, Status: status, From: from}
+		if paths.HasMDExtension(path) {
+			change.ObjectID = vaultCfg.FilePathToObjectID(path)
+		}
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+func (r *repo) branch(configured string) (string, error) {
+	if configured != "" {
+		return configured, nil
+	}
+	// symbolic-ref also names the branch of a repository with no commits yet.
+	branch, err := r.output("symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return "", newError(CodeGitFailed, "HEAD is detached", "Check out a branch, or set git.branch in raven.yaml", err)
+	}
+	return strings.TrimSpace(branch), nil
+}
+
+// remote returns the remote to sync with, or "" when the default remote is
+// missing and the repository is local only.
+func (r *repo) remote(vaultCfg *config.VaultConfig) (string, error) {
+	name := vaultCfg.GetGitRemote()
+	out, err := r.run("remote")
+	if err != nil {
+		return "", err
+	}
+	for _, remote := range strings.Fields(out) {
+		if remote == name {
+			return name, nil
+		}
+	}
+	if vaultCfg.GetGitBranch() != "" || (vaultCfg != nil && vaultCfg.Git != nil && vaultCfg.Git.Remote != "") {
+		return "", newError(CodeConfigInvalid, fmt.Sprintf("git remote '%s' does not exist", name), "Add it with 'git remote add' or fix git.remote in raven.yaml", nil)
+	}
+	return "", nil
+}
+
+// pull merges the remote branch into HEAD and returns the vault files the
+// merge changed. A branch missing on the remote (a first push) is skipped.
+func (r *repo) pull(remote, branch string) ([]string, error) {
+	if _, err := r.output("ls-remote", "--exit-code", "--heads", remote, branch); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+			return nil, nil
+		}
+		return nil, newError(CodeGitFailed, fmt.Sprintf("could not reach remote '%s'", remote), "Check the remote URL and your credentials", err)
+	}
+
+	before, err := r.run("rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.output("pull", "-q", "--no-rebase", "--no-edit", remote, branch); err != nil {
+		if conflicts, _ := r.run("diff", "--name-only", "--relative", "--diff-filter=U", "-z"); conflicts != "" {
+			files := splitNUL(conflicts)
+			return nil, newError(CodeMergeConflict,
+				fmt.Sprintf("merge conflicts in %d file(s): %s", len(files), strings.Join(files, ", ")),
+				"Resolve the conflicts, commit them, and run 'rvn sync git' again", err)
+		}
+		return nil, newError(CodeGitFailed, "git pull failed", "", err)
+	}
+	after, err := r.run("rev-parse", "HEAD")
+	if err != nil || after == before {
+		return nil, err
+	}
+	out, err := r.run("diff", "--name-only", "--relative", "-z", before, after)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, file := range splitNUL(out) {
+		if !isRavenStatePath(file) {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+func isRavenStatePath(path string) bool {
+	return path == ".raven" || strings.HasPrefix(path, ".raven/")
+}
+
+func splitNUL(out string) []string {
+	var items []string
+	for _, item := range strings.Split(out, "\x00") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
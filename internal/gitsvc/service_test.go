@@ -0,0 +1,106 @@
+package gitsvc
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+)
+
+func TestCommitMessageSummarizesChangedObjects(t *testing.T) {
+	t.Parallel()
+
+	changes := []FileChange{
+		{Path: "people/freya.md", ObjectID: "people/freya", Status: StatusAdded},
+		{Path: "projects/raven.md", ObjectID: "projects/raven", Status: StatusModified},
+		{Path: "projects/wren.md", ObjectID: "projects/wren", Status: StatusModified},
+		{Path: "assets/logo.png", Status: StatusDeleted},
+	}
+	want := "Update people/freya, projects/raven, projects/wren and 1 more\n\n" +
+		"Added:\n- people/freya\n\n" +
+		"Modified:\n- projects/raven\n- projects/wren\n\n" +
+		"Deleted:\n- assets/logo.png"
+	if got := CommitMessage("", changes); got != want {
+		t.Fatalf("CommitMessage() = %q, want %q", got, want)
+	}
+	if got := CommitMessage("rvn set people/freya", changes[:1]); got != "rvn set people/freya\n\nAdded:\n- people/freya" {
+		t.Fatalf("CommitMessage() with subject = %q", got)
+	}
+}
+
+func TestSyncCommitsVaultChangesAndPushes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "Raven")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "raven@example.com")
+	}
+	tmp := t.TempDir()
+	remote := filepath.Join(tmp, "remote.git")
+	repoDir := filepath.Join(tmp, "repo")
+	vault := filepath.Join(repoDir, "notes")
+	gitCmd(t, tmp, "init", "-q", "--bare", "-b", "main", remote)
+	gitCmd(t, tmp, "init", "-q", "-b", "main", repoDir)
+	gitCmd(t, repoDir, "remote", "add", "origin", remote)
+	writeFile(t, filepath.Join(repoDir, "outside.txt"), "not part of the vault\n")
+	writeFile(t, filepath.Join(vault, "people", "freya.md"), "# Freya\n")
+	writeFile(t, filepath.Join(vault, ".raven", "index.db"), "derived\n")
+
+	cfg := &config.VaultConfig{}
+	result, err := Sync(SyncRequest{VaultPath: vault, VaultConfig: cfg})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result.Commit == "" || !result.Pushed || result.Remote != "origin" || result.Branch != "main" {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	if len(result.Changes) != 1 || result.Changes[0].Path != "people/freya.md" || result.Changes[0].ObjectID != "people/freya" {
+		t.Fatalf("changes = %#v, want only people/freya.md", result.Changes)
+	}
+	if got := gitCmd(t, repoDir, "log", "-1", "--format=%s", "origin/main"); got != "Update people/freya" {
+		t.Fatalf("pushed commit subject = %q", got)
+	}
+	if status := gitCmd(t, repoDir, "status", "--porcelain"); status != "?? notes/.raven/\n?? outside.txt" {
+		t.Fatalf("expected .raven/ and files outside the vault to stay uncommitted, got %q", status)
+	}
+
+	writeFile(t, filepath.Join(vault, "people", "freya.md"), "# Freya Stark\n")
+	writeFile(t, filepath.Join(vault, "people", "wren.md"), "# Wren\n")
+	commit, err := CommitFiles(vault, cfg, []string{"people/wren.md"}, "rvn new person Wren")
+	if err != nil {
+		t.Fatalf("CommitFiles() error = %v", err)
+	}
+	if commit == "" {
+		t.Fatal("expected CommitFiles to commit people/wren.md")
+	}
+	if files := gitCmd(t, repoDir, "show", "--name-only", "--format=%s", "HEAD"); files != "rvn new person Wren\n\nnotes/people/wren.md" {
+		t.Fatalf("auto-commit = %q, want only people/wren.md", files)
+	}
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,427 @@
+package graphsvc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/checksvc"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/history"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/pages"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+	"github.com/aidanlsb/raven/internal/wikilink"
+)
+
+// Dead-link apply actions.
+const (
+	DeadLinkStub   = "stub"   // Create a stub object for each missing target
+	DeadLinkUnlink = "unlink" // Replace each dead link with its display text
+)
+
+// DeadLinkActions lists the supported --apply actions in display order.
+var DeadLinkActions = []string{DeadLinkStub, DeadLinkUnlink}
+
+// LinkFilter narrows orphan and dead-link reports by object type and
+// directory.
+type LinkFilter struct {
+	Types []string // Keep objects (or link sources) of these types; empty keeps all
+	Dir   string   // Keep files under this vault-relative directory; empty keeps all
+}
+
+type Orphan struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+}
+
+type DeadLink struct {
+	SourceID    string  `json:"source_id"`
+	SourceType  string  `json:"source_type"`
+	Target      string  `json:"target"` // Target as written
+	DisplayText *string `json:"display_text,omitempty"`
+	FilePath    string  `json:"file_path"`
+	Line        int     `json:"line"`
+}
+
+// Orphans returns the objects with no references from other files and no
+// references of their own, ordered by ID. References from or to a section or
+// embedded object count for the object its ID starts with.
+func Orphans(vaultPath string, filter LinkFilter) ([]Orphan, error) {
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return nil, newError(CodeDatabaseError, "failed to open database", "Run 'rvn reindex' to rebuild the database", err)
+	}
+	defer db.Close()
+
+	orphans, err := loadOrphans(db, filter)
+	if err != nil {
+		return nil, newError(CodeDatabaseError, "failed to read references from index", "Run 'rvn reindex' to rebuild the database", err)
+	}
+	return orphans, nil
+}
+
+func loadOrphans(db *index.Database, filter LinkFilter) ([]Orphan, error) {
+	rows, err := db.DB().Query(`SELECT id, type, file_path, line_start FROM objects ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	var objects []Orphan
+	files := make(map[string]string)
+	for rows.Next() {
+		var o Orphan
+		if err := rows.Scan(&o.ID, &o.Type, &o.FilePath, &o.Line); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		objects = append(objects, o)
+		files[o.ID] = o.FilePath
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// owner maps a ref endpoint to the object it belongs to, falling back to
+	// the file object for section and embedded IDs.
+	owner := func(id string) (string, bool) {
+		if _, ok := files[id]; ok {
+			return id, true
+		}
+		if i := strings.Index(id, "#"); i > 0 {
+			if _, ok := files[id[:i]]; ok {
+				return id[:i], true
+			}
+		}
+		return "", false
+	}
+
+	linked := make(map[string]bool)
+	rows, err = db.DB().Query(`
+		SELECT source_id, COALESCE(target_id, ''), file_path FROM refs
+		UNION ALL
+		SELECT source_id, COALESCE(target_id, ''), file_path FROM field_refs
+	`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var sourceID, targetID, filePath string
+		if err := rows.Scan(&sourceID, &targetID, &filePath); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		target, resolved := owner(targetID)
+		if resolved && files[target] == filePath {
+			continue // Links within a file connect it to nothing else
+		}
+		if resolved {
+			linked[target] = true
+		}
+		if source, ok := owner(sourceID); ok {
+			linked[source] = true
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	dir := normalizeFilterDir(filter.Dir)
+	orphans := []Orphan{}
+	for _, o := range objects {
+		if linked[o.ID] || !filter.keep(o.Type, o.FilePath, dir) {
+			continue
+		}
+		orphans = append(orphans, o)
+	}
+	return orphans, nil
+}
+
+// DeadLinks returns the references whose target does not resolve, ordered by
+// file and line.
+func DeadLinks(vaultPath string, filter LinkFilter) ([]DeadLink, error) {
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return nil, newError(CodeDatabaseError, "failed to open database", "Run 'rvn reindex' to rebuild the database", err)
+	}
+	defer db.Close()
+
+	rows, err := db.DB().Query(`
+		SELECT r.source_id, COALESCE(o.type, f.type, ''), r.target_raw, r.display_text, r.file_path, COALESCE(r.line_number, 0)
+		FROM refs r
+		LEFT JOIN objects o ON o.id = r.source_id
+		LEFT JOIN objects f ON instr(r.source_id, '#') > 0 AND f.id = substr(r.source_id, 1, instr(r.source_id, '#') - 1)
+		WHERE r.target_id IS NULL
+		ORDER BY r.file_path, r.line_number, r.position_start
+	`)
+	if err != nil {
+		return nil, newError(CodeDatabaseError, "failed to read references from index", "Run 'rvn reindex' to rebuild the database", err)
+	}
+	defer rows.Close()
+
+	dir := normalizeFilterDir(filter.Dir)
+	links := []DeadLink{}
+	for rows.Next() {
+		var link DeadLink
+		var display *string
+		if err := rows.Scan(&link.SourceID, &link.SourceType, &link.Target, &display, &link.FilePath, &link.Line); err != nil {
+			return nil, newError(CodeDatabaseError, "failed to read references from index", "Run 'rvn reindex' to rebuild the database", err)
+		}
+		if display != nil && *display != "" {
+			link.DisplayText = display
+		}
+		if filter.keep(link.SourceType, link.FilePath, dir) {
+			links = append(links, link)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newError(CodeDatabaseError, "failed to read references from index", "Run 'rvn reindex' to rebuild the database", err)
+	}
+	return links, nil
+}
+
+func (f LinkFilter) keep(typeName, filePath, dir string) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, typeName) {
+		return false
+	}
+	return dir == "" || strings.HasPrefix(filePath, dir)
+}
+
+func normalizeFilterDir(dir string) string {
+	dir = paths.NormalizeVaultRelPath(dir)
+	if dir == "" || dir == "." {
+		return ""
+	}
+	return strings.TrimSuffix(dir, "/") + "/"
+}
+
+// DeadLinkApplyRequest applies one action to a set of dead links.
+type DeadLinkApplyRequest struct {
+	VaultPath string
+	VaultCfg  *config.VaultConfig
+	Schema    *schema.Schema
+	Action    string
+	Links     []DeadLink
+	Confirm   bool // Write changes; otherwise only plan them
+}
+
+// Stub is an object created for a missing link target.
+type Stub struct {
+	Target   string `json:"target"`
+	Type     string `json:"type"`
+	FilePath string `json:"file_path"`
+	Links    int    `json:"links"` // Dead links the stub resolves
+}
+
+// Unlinked is a dead link replaced by plain text.
+type Unlinked struct {
+	FilePath    string `json:"file_path"`
+	Line        int    `json:"line"`
+	Target      string `json:"target"`
+	Replacement string `json:"replacement"`
+}
+
+type SkippedLink struct {
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line,omitempty"`
+	Target   string `json:"target"`
+	Reason   string `json:"reason"`
+}
+
+type DeadLinkApplyResult struct {
+	Action       string        `json:"action"`
+	Stubs        []Stub        `json:"stubs"`
+	Unlinked     []Unlinked    `json:"unlinked"`
+	Skipped      []SkippedLink `json:"skipped"`
+	ChangedFiles []string      `json:"changed_files"` // Vault-relative files written
+}
+
+// ApplyDeadLinks plans, and with Confirm applies, a dead-link action: stub
+// creates one object per missing target, typed by the first type whose
+// default_path the target falls under (or page); unlink replaces each body
+// link with its display text. Links in frontmatter are left alone, since
+// the field would still hold the value.
+func ApplyDeadLinks(req DeadLinkApplyRequest) (*DeadLinkApplyResult, error) {
+	switch req.Action {
+	case DeadLinkStub:
+		return applyStubs(req)
+	case DeadLinkUnlink:
+		return applyUnlink(req)
+	default:
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("unknown --apply action %q", req.Action), "Use one of: "+strings.Join(DeadLinkActions, ", "), nil)
+	}
+}
+
+func newDeadLinkApplyResult(action string) *DeadLinkApplyResult {
+	return &DeadLinkApplyResult{Action: action, Stubs: []Stub{}, Unlinked: []Unlinked{}, Skipped: []SkippedLink{}, ChangedFiles: []string{}}
+}
+
+func applyStubs(req DeadLinkApplyRequest) (*DeadLinkApplyResult, error) {
+	result := newDeadLinkApplyResult(DeadLinkStub)
+	cfg := req.VaultCfg
+	byPath := make(map[string]int)
+	for _, link := range req.Links {
+		target, _, _ := strings.Cut(link.Target, "#")
+		target = paths.TrimMDExtension(paths.NormalizeVaultRelPath(target))
+		if target == "" {
+			result.Skipped = append(result.Skipped, SkippedLink{FilePath: link.FilePath, Line: link.Line, Target: link.Target, Reason: "empty target"})
+			continue
+		}
+		typeName := stubType(req.Schema, target)
+		filePath := pages.SlugifyPath(checksvc.ResolveTargetPath(target, typeName, req.Schema, cfg.GetObjectsRoot(), cfg.GetPagesRoot(), cfg.GetDailyDirectory()))
+		if !strings.HasSuffix(filePath, ".md") {
+			filePath += ".md"
+		}
+		if i, ok := byPath[filePath]; ok {
+			result.Stubs[i].Links++
+			continue
+		}
+		if pages.Exists(req.VaultPath, filePath) {
+			result.Skipped = append(result.Skipped, SkippedLink{FilePath: link.FilePath, Line: link.Line, Target: link.Target, Reason: fmt.Sprintf("%s exists but the link does not resolve to it", filePath)})
+			continue
+		}
+		byPath[filePath] = len(result.Stubs)
+		result.Stubs = append(result.Stubs, Stub{Target: target, Type: typeName, FilePath: filePath, Links: 1})
+	}
+	if !req.Confirm {
+		return result, nil
+	}
+
+	for _, stub := range result.Stubs {
+		err := checksvc.CreateMissingPage(req.VaultPath, req.Schema, stub.Target, stub.Type, cfg.GetObjectsRoot(), cfg.GetPagesRoot(), cfg.GetDailyDirectory(), cfg.GetTemplateDirectory(), cfg.ProtectedPrefixes)
+		if err != nil {
+			return result, newError(CodeFileWriteErr, fmt.Sprintf("failed to create %s", stub.FilePath), "", err)
+		}
+		result.ChangedFiles = append(result.ChangedFiles, stub.FilePath)
+	}
+	return result, nil
+}
+
+// stubType picks the type for a stub at target: the first type, by name,
+// whose default_path contains it, otherwise page.
+func stubType(sch *schema.Schema, target string) string {
+	if sch == nil {
+		return "page"
+	}
+	names := make([]string, 0, len(sch.Types))
+	for name := range sch.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		typeDef := sch.Types[name]
+		if typeDef == nil || typeDef.DefaultPath == "" {
+			continue
+		}
+		prefix := strings.TrimSuffix(paths.NormalizeVaultRelPath(typeDef.DefaultPath), "/") + "/"
+		if strings.HasPrefix(target, prefix) && len(target) > len(prefix) {
+			return name
+		}
+	}
+	return "page"
+}
+
+func applyUnlink(req DeadLinkApplyRequest) (*DeadLinkApplyResult, error) {
+	result := newDeadLinkApplyResult(DeadLinkUnlink)
+	byFile := make(map[string][]DeadLink)
+	var files []string
+	for _, link := range req.Links {
+		if _, ok := byFile[link.FilePath]; !ok {
+			files = append(files, link.FilePath)
+		}
+		byFile[link.FilePath] = append(byFile[link.FilePath], link)
+	}
+	sort.Strings(files)
+
+	for _, filePath := range files {
+		links := byFile[filePath]
+		skipAll := func(reason string) {
+			for _, link := range links {
+				result.Skipped = append(result.Skipped, SkippedLink{FilePath: filePath, Line: link.Line, Target: link.Target, Reason: reason})
+			}
+		}
+		if req.VaultCfg.IsLockedPath(filePath) {
+			skipAll("file is locked (pass --unlock to modify it)")
+			continue
+		}
+		fullPath := filepath.Join(req.VaultPath, filepath.FromSlash(filePath))
+		content, err := vaultcrypt.ReadFile(fullPath)
+		if err != nil {
+			return result, newError(CodeFileReadErr, fmt.Sprintf("failed to read %s", filePath), "", err)
+		}
+
+		lines := strings.Split(string(content), "\n")
+		bodyStart := frontmatterEndLine(lines) + 1
+		changed := false
+		for _, link := range links {
+			if link.Line < bodyStart || link.Line > len(lines) {
+				reason := "link is in frontmatter; change the field with 'rvn set' or 'rvn unset'"
+				if link.Line > len(lines) {
+					reason = "line no longer exists; run 'rvn reindex'"
+				}
+				result.Skipped = append(result.Skipped, SkippedLink{FilePath: filePath, Line: link.Line, Target: link.Target, Reason: reason})
+				continue
+			}
+			line, replacement, ok := unlinkLine(lines[link.Line-1], link.Target)
+			if !ok {
+				result.Skipped = append(result.Skipped, SkippedLink{FilePath: filePath, Line: link.Line, Target: link.Target, Reason: "link no longer on this line; run 'rvn reindex'"})
+				continue
+			}
+			lines[link.Line-1] = line
+			changed = true
+			result.Unlinked = append(result.Unlinked, Unlinked{FilePath: filePath, Line: link.Line, Target: link.Target, Replacement: replacement})
+		}
+		if !changed || !req.Confirm {
+			continue
+		}
+
+		history.Capture(fullPath)
+		data, err := vaultcrypt.Seal(fullPath, []byte(strings.Join(lines, "\n")))
+		if err != nil {
+			return result, newError(CodeFileWriteErr, fmt.Sprintf("failed to write %s", filePath), "", err)
+		}
+		if err := os.WriteFile(fullPath, data, 0o644); err != nil {
+			return result, newError(CodeFileWriteErr, fmt.Sprintf("failed to write %s", filePath), "", err)
+		}
+		result.ChangedFiles = append(result.ChangedFiles, filePath)
+	}
+	return result, nil
+}
+
+// unlinkLine replaces the first [[target]] or [[target|text]] on line with
+// its display text, or the target when there is none.
+func unlinkLine(line, target string) (string, string, bool) {
+	for _, match := range wikilink.FindAllInLine(line, false) {
+		if match.Target != target {
+			continue
+		}
+		replacement := match.Target
+		if match.DisplayText != nil && *match.DisplayText != "" {
+			replacement = *match.DisplayText
+		}
+		return line[:match.Start] + replacement + line[match.End:], replacement, true
+	}
+	return line, "", false
+}
+
+// frontmatterEndLine returns the 1-based line of the closing frontmatter
+// delimiter, or 0 when the file has no frontmatter.
+func frontmatterEndLine(lines []string) int {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return i + 1
+		}
+	}
+	return 0
+}
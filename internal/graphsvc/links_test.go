@@ -0,0 +1,90 @@
+package graphsvc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func TestOrphansAndDeadLinks(t *testing.T) {
+	t.Parallel()
+	vaultPath := setupGraphVault(t)
+
+	orphans, err := Orphans(vaultPath, LinkFilter{})
+	if err != nil {
+		t.Fatalf("Orphans returned error: %v", err)
+	}
+	want := []Orphan{{ID: "notes/lonely", Type: "page", FilePath: "notes/lonely.md", Line: 1}}
+	if !reflect.DeepEqual(orphans, want) {
+		t.Fatalf("orphans = %#v, want %#v", orphans, want)
+	}
+	if orphans, _ := Orphans(vaultPath, LinkFilter{Types: []string{"project"}}); len(orphans) != 0 {
+		t.Fatalf("orphans of type project = %#v, want none", orphans)
+	}
+
+	links, err := DeadLinks(vaultPath, LinkFilter{Dir: "notes"})
+	if err != nil {
+		t.Fatalf("DeadLinks returned error: %v", err)
+	}
+	if len(links) != 1 || links[0].SourceID != "notes/b" || links[0].SourceType != "page" || links[0].Target != "nowhere" || links[0].Line != 2 {
+		t.Fatalf("dead links = %#v, want notes/b -> nowhere on line 2", links)
+	}
+	if links, _ := DeadLinks(vaultPath, LinkFilter{Dir: "projects/"}); len(links) != 0 {
+		t.Fatalf("dead links under projects/ = %#v, want none", links)
+	}
+}
+
+func TestApplyDeadLinks(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+	content := "---\nowner: \"[[people/ghost]]\"\n---\nSee [[people/ghost]] and [[nowhere|the plan]].\n"
+	if err := os.MkdirAll(filepath.Join(vaultPath, "notes"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	notePath := filepath.Join(vaultPath, "notes", "b.md")
+	if err := os.WriteFile(notePath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	links := []DeadLink{
+		{SourceID: "notes/b", Target: "people/ghost", FilePath: "notes/b.md", Line: 2},
+		{SourceID: "notes/b", Target: "people/ghost", FilePath: "notes/b.md", Line: 4},
+		{SourceID: "notes/b", Target: "nowhere", FilePath: "notes/b.md", Line: 4},
+	}
+	sch := schema.New()
+	sch.Types["person"] = &schema.TypeDefinition{DefaultPath: "people/"}
+	req := DeadLinkApplyRequest{VaultPath: vaultPath, VaultCfg: &config.VaultConfig{}, Schema: sch, Links: links}
+
+	req.Action = DeadLinkStub
+	stubs, err := ApplyDeadLinks(req)
+	if err != nil {
+		t.Fatalf("stub preview returned error: %v", err)
+	}
+	wantStubs := []Stub{
+		{Target: "people/ghost", Type: "person", FilePath: "people/ghost.md", Links: 2},
+		{Target: "nowhere", Type: "page", FilePath: "nowhere.md", Links: 1},
+	}
+	if !reflect.DeepEqual(stubs.Stubs, wantStubs) || len(stubs.ChangedFiles) != 0 {
+		t.Fatalf("stub preview = %#v, want %#v and no writes", stubs, wantStubs)
+	}
+
+	req.Action = DeadLinkUnlink
+	req.Confirm = true
+	unlinked, err := ApplyDeadLinks(req)
+	if err != nil {
+		t.Fatalf("unlink returned error: %v", err)
+	}
+	if len(unlinked.Unlinked) != 2 || len(unlinked.Skipped) != 1 || unlinked.Skipped[0].Line != 2 {
+		t.Fatalf("unlink = %#v, want two body links unlinked and the frontmatter link skipped", unlinked)
+	}
+	got, err := os.ReadFile(notePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "---\nowner: \"[[people/ghost]]\"\n---\nSee people/ghost and the plan.\n"; string(got) != want {
+		t.Fatalf("notes/b.md = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package graphsvc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func renderDOT(g Graph) string {
+	var b strings.Builder
+	b.WriteString("digraph raven {\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, node := range g.Nodes {
+		attrs := []string{"label=" + dotQuote(node.Label)}
+		if node.Type != "" {
+			attrs = append(attrs, "type="+dotQuote(node.Type))
+		}
+		if node.Kind == "section" {
+			attrs = append(attrs, "shape=note")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(node.ID), strings.Join(attrs, ", "))
+	}
+	for _, edge := range g.Edges {
+		var attrs []string
+		switch {
+		case edge.Kind == EdgeParent:
+			attrs = append(attrs, "style=dashed")
+		case len(edge.Fields) > 0:
+			attrs = append(attrs, "label="+dotQuote(strings.Join(edge.Fields, ", ")))
+		}
+		if edge.Count > 1 {
+			attrs = append(attrs, "weight="+strconv.Itoa(edge.Count))
+		}
+		line := fmt.Sprintf("  %s -> %s", dotQuote(edge.Source), dotQuote(edge.Target))
+		if len(attrs) > 0 {
+			line += " [" + strings.Join(attrs, ", ") + "]"
+		}
+		b.WriteString(line + ";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func renderGraphML(g Graph) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	b.WriteString(`  <key id="kind" for="node" attr.name="kind" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="type" for="node" attr.name="type" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="label" for="node" attr.name="label" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="file" for="node" attr.name="file" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="fields" for="node" attr.name="fields" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="edge_kind" for="edge" attr.name="kind" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="edge_fields" for="edge" attr.name="fields" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="count" for="edge" attr.name="count" attr.type="int"/>` + "\n")
+	b.WriteString(`  <graph id="raven" edgedefault="directed">` + "\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&b, "    <node id=\"%s\">\n", xmlEscape(node.ID))
+		writeGraphMLData(&b, "kind", node.Kind)
+		writeGraphMLData(&b, "type", node.Type)
+		writeGraphMLData(&b, "label", node.Label)
+		writeGraphMLData(&b, "file", node.File)
+		if len(node.Fields) > 0 {
+			// Field values keep their structure as a JSON string; GraphML
+			// attributes are scalar and per-type schemas differ.
+			data, _ := json.Marshal(node.Fields)
+			writeGraphMLData(&b, "fields", string(data))
+		}
+		b.WriteString("    </node>\n")
+	}
+	for i, edge := range g.Edges {
+		fmt.Fprintf(&b, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, xmlEscape(edge.Source), xmlEscape(edge.Target))
+		writeGraphMLData(&b, "edge_kind", edge.Kind)
+		writeGraphMLData(&b, "edge_fields", strings.Join(edge.Fields, ","))
+		writeGraphMLData(&b, "count", strconv.Itoa(edge.Count))
+		b.WriteString("    </edge>\n")
+	}
+	b.WriteString("  </graph>\n")
+	b.WriteString("</graphml>\n")
+	return b.String()
+}
+
+func writeGraphMLData(b *strings.Builder, key, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, "      <data key=\"%s\">%s</data>\n", key, xmlEscape(value))
+}
+
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;").Replace(s)
+}
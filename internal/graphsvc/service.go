@@ -0,0 +1,399 @@
+// Package graphsvc exports the indexed object/reference graph in formats that
+// graph tools such as Gephi, yEd, and Graphviz can load.
+package graphsvc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/index"
+)
+
+type Code = codes.ErrorCode
+
+const (
+	CodeInvalidInput  Code = codes.ErrInvalidInput
+	CodeDatabaseError Code = codes.ErrDatabase
+	CodeFileReadErr   Code = codes.ErrFileRead
+	CodeFileWriteErr  Code = codes.ErrFileWrite
+)
+
+type Error struct {
+	Code       Code
+	Message    string
+	Suggestion string
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e == nil {
+		return ""
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return string(e.Code)
+}
+
+func (e *Error) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+func newError(code Code, message, suggestion string, err error) *Error {
+	return &Error{Code: code, Message: message, Suggestion: suggestion, Err: err}
+}
+
+func AsError(err error) (*Error, bool) {
+	var svcErr *Error
+	if errors.As(err, &svcErr) {
+		return svcErr, true
+	}
+	return nil, false
+}
+
+// Export formats supported by Export.
+const (
+	FormatJSON    = "json"
+	FormatDOT     = "dot"
+	FormatGraphML = "graphml"
+)
+
+// Formats lists the supported formats in display order.
+var Formats = []string{FormatJSON, FormatDOT, FormatGraphML}
+
+// Edge kinds.
+const (
+	EdgeRef    = "ref"    // A wikilink or ref-typed field
+	EdgeParent = "parent" // A section to its parent section or file object
+)
+
+type ExportRequest struct {
+	VaultPath string
+	Format    string
+	Types     []string // Keep only objects of these types; empty keeps all
+	Root      string   // Resolved object ID to start from; empty exports everything
+	Depth     int      // Hops from Root, following edges in either direction; 0 means unlimited
+	Sections  bool     // Include sections as nodes, with parent edges
+	Output    string   // Write here (relative paths are vault-relative); empty returns content only
+}
+
+type Node struct {
+	ID     string                 `json:"id"`
+	Kind   string                 `json:"kind"` // object or section
+	Type   string                 `json:"type,omitempty"`
+	Label  string                 `json:"label"`
+	File   string                 `json:"file"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+type Edge struct {
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Kind   string   `json:"kind"`
+	Fields []string `json:"fields,omitempty"` // Ref-typed fields behind a ref edge
+	Count  int      `json:"count"`            // Number of references collapsed into the edge
+}
+
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+type ExportResult struct {
+	Format  string
+	Graph   Graph
+	Content string // Serialized graph in Format
+	Output  string // Absolute path written, when Output was set
+}
+
+// Export builds the object graph from the index: objects are nodes, and
+// references between them are edges. References from sections and embedded
+// objects count as references from their file's object unless Sections is
+// set, in which case sections become nodes linked to their parents.
+func Export(req ExportRequest) (*ExportResult, error) {
+	format := strings.ToLower(strings.TrimSpace(req.Format))
+	if format == "" {
+		format = FormatJSON
+	}
+	if !slices.Contains(Formats, format) {
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("unknown graph format %q", req.Format), "Use one of: "+strings.Join(Formats, ", "), nil)
+	}
+	if req.Depth < 0 {
+		return nil, newError(CodeInvalidInput, "depth must be zero or greater", "Use --depth 0 for no limit", nil)
+	}
+
+	db, err := index.Open(req.VaultPath)
+	if err != nil {
+		return nil, newError(CodeDatabaseError, "failed to open database", "Run 'rvn reindex' to rebuild the database", err)
+	}
+	defer db.Close()
+
+	graph, err := loadGraph(db, req.Sections)
+	if err != nil {
+		return nil, newError(CodeDatabaseError, "failed to read graph from index", "Run 'rvn reindex' to rebuild the database", err)
+	}
+	if req.Root != "" {
+		if !graph.hasNode(req.Root) {
+			return nil, newError(CodeInvalidInput, fmt.Sprintf("'%s' is not in the index", req.Root), "Run 'rvn reindex' if the object was created recently", nil)
+		}
+		graph = graph.neighborhood(req.Root, req.Depth)
+	}
+	if len(req.Types) > 0 {
+		graph = graph.filterTypes(req.Types, req.Root)
+	}
+
+	var content string
+	switch format {
+	case FormatDOT:
+		content = renderDOT(graph)
+	case FormatGraphML:
+		content = renderGraphML(graph)
+	default:
+		data, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			return nil, newError(codes.ErrInternal, "failed to encode graph", "", err)
+		}
+		content = string(data) + "\n"
+	}
+
+	result := &ExportResult{Format: format, Graph: graph, Content: content}
+	if strings.TrimSpace(req.Output) == "" {
+		return result, nil
+	}
+
+	output := req.Output
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(req.VaultPath, output)
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0o755); err != nil {
+		return nil, newError(CodeFileWriteErr, fmt.Sprintf("failed to create %s", filepath.Dir(output)), "", err)
+	}
+	if err := atomicfile.WriteFile(output, []byte(content), 0o644); err != nil {
+		return nil, newError(CodeFileWriteErr, fmt.Sprintf("failed to write %s", output), "", err)
+	}
+	result.Output = output
+	return result, nil
+}
+
+func loadGraph(db *index.Database, withSections bool) (Graph, error) {
+	var graph Graph
+	nodeIDs := make(map[string]bool)
+
+	rows, err := db.DB().Query(`SELECT id, type, file_path, fields FROM objects ORDER BY id`)
+	if err != nil {
+		return graph, err
+	}
+	for rows.Next() {
+		var node Node
+		var fieldsJSON string
+		if err := rows.Scan(&node.ID, &node.Type, &node.File, &fieldsJSON); err != nil {
+			rows.Close()
+			return graph, err
+		}
+		node.Kind = "object"
+		node.Label = node.ID
+		if fieldsJSON != "" && fieldsJSON != "{}" {
+			_ = json.Unmarshal([]byte(fieldsJSON), &node.Fields)
+		}
+		graph.Nodes = append(graph.Nodes, node)
+		nodeIDs[node.ID] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return graph, err
+	}
+
+	if withSections {
+		rows, err := db.DB().Query(`SELECT id, file_object_id, file_path, title, COALESCE(parent_section_id, '') FROM sections ORDER BY id`)
+		if err != nil {
+			return graph, err
+		}
+		for rows.Next() {
+			var node Node
+			var fileObjectID, parentID string
+			if err := rows.Scan(&node.ID, &fileObjectID, &node.File, &node.Label, &parentID); err != nil {
+				rows.Close()
+				return graph, err
+			}
+			node.Kind = "section"
+			if parentID == "" {
+				parentID = fileObjectID
+			}
+			graph.Nodes = append(graph.Nodes, node)
+			graph.Edges = append(graph.Edges, Edge{Source: node.ID, Target: parentID, Kind: EdgeParent, Count: 1})
+			nodeIDs[node.ID] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return graph, err
+		}
+	}
+
+	// endpoint maps a ref endpoint to a node, falling back to the file object
+	// for section and embedded IDs that are not nodes themselves.
+	endpoint := func(id string) (string, bool) {
+		if nodeIDs[id] {
+			return id, true
+		}
+		if i := strings.Index(id, "#"); i > 0 && nodeIDs[id[:i]] {
+			return id[:i], true
+		}
+		return "", false
+	}
+
+	type pair struct{ source, target string }
+	refEdges := make(map[pair]*Edge)
+	var order []pair
+	addRef := func(source, target, field string) {
+		s, ok := endpoint(source)
+		if !ok {
+			return
+		}
+		t, ok := endpoint(target)
+		if !ok || s == t {
+			return
+		}
+		key := pair{s, t}
+		edge, ok := refEdges[key]
+		if !ok {
+			edge = &Edge{Source: s, Target: t, Kind: EdgeRef}
+			refEdges[key] = edge
+			order = append(order, key)
+		}
+		if field == "" {
+			edge.Count++
+		} else if !slices.Contains(edge.Fields, field) {
+			edge.Fields = append(edge.Fields, field)
+		}
+	}
+
+	// Ref-typed fields are also indexed as refs, so refs supply the counts
+	// and field_refs only name the fields.
+	rows, err = db.DB().Query(`SELECT source_id, target_id FROM refs WHERE target_id IS NOT NULL ORDER BY id`)
+	if err != nil {
+		return graph, err
+	}
+	for rows.Next() {
+		var source, target string
+		if err := rows.Scan(&source, &target); err != nil {
+			rows.Close()
+			return graph, err
+		}
+		addRef(source, target, "")
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return graph, err
+	}
+
+	rows, err = db.DB().Query(`SELECT source_id, target_id, field_name FROM field_refs WHERE target_id IS NOT NULL ORDER BY id`)
+	if err != nil {
+		return graph, err
+	}
+	for rows.Next() {
+		var source, target, field string
+		if err := rows.Scan(&source, &target, &field); err != nil {
+			rows.Close()
+			return graph, err
+		}
+		addRef(source, target, field)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return graph, err
+	}
+
+	for _, key := range order {
+		edge := refEdges[key]
+		if edge.Count == 0 {
+			edge.Count = 1
+		}
+		sort.Strings(edge.Fields)
+		graph.Edges = append(graph.Edges, *edge)
+	}
+	return graph, nil
+}
+
+func (g Graph) hasNode(id string) bool {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// neighborhood keeps the nodes within depth hops of root, following edges in
+// either direction, and the edges between them. A depth of 0 keeps the whole
+// connected component.
+func (g Graph) neighborhood(root string, depth int) Graph {
+	adjacent := make(map[string][]string)
+	for _, edge := range g.Edges {
+		adjacent[edge.Source] = append(adjacent[edge.Source], edge.Target)
+		adjacent[edge.Target] = append(adjacent[edge.Target], edge.Source)
+	}
+
+	keep := map[string]bool{root: true}
+	frontier := []string{root}
+	for hop := 0; len(frontier) > 0 && (depth == 0 || hop < depth); hop++ {
+		var next []string
+		for _, id := range frontier {
+			for _, neighbor := range adjacent[id] {
+				if !keep[neighbor] {
+					keep[neighbor] = true
+					next = append(next, neighbor)
+				}
+			}
+		}
+		frontier = next
+	}
+	return g.subgraph(func(node Node) bool { return keep[node.ID] })
+}
+
+// filterTypes keeps objects of the given types, sections in files whose
+// object is kept, and root.
+func (g Graph) filterTypes(types []string, root string) Graph {
+	keptFiles := make(map[string]bool)
+	for _, node := range g.Nodes {
+		if node.Kind == "object" && (slices.Contains(types, node.Type) || node.ID == root) {
+			keptFiles[node.File] = true
+		}
+	}
+	return g.subgraph(func(node Node) bool {
+		if node.Kind == "section" {
+			return keptFiles[node.File]
+		}
+		return slices.Contains(types, node.Type) || node.ID == root
+	})
+}
+
+func (g Graph) subgraph(keep func(Node) bool) Graph {
+	out := Graph{Nodes: []Node{}, Edges: []Edge{}}
+	kept := make(map[string]bool)
+	for _, node := range g.Nodes {
+		if keep(node) {
+			out.Nodes = append(out.Nodes, node)
+			kept[node.ID] = true
+		}
+	}
+	for _, edge := range g.Edges {
+		if kept[edge.Source] && kept[edge.Target] {
+			out.Edges = append(out.Edges, edge)
+		}
+	}
+	return out
+}
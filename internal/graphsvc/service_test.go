@@ -0,0 +1,169 @@
+package graphsvc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/index"
+)
+
+func setupGraphVault(t *testing.T) string {
+	t.Helper()
+	vaultPath := t.TempDir()
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		t.Fatalf("failed to open index db: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.DB().Exec(`
+		INSERT INTO objects (id, file_path, type, line_start, fields) VALUES
+			('projects/raven', 'projects/raven.md', 'project', 1, '{"owner":"people/freya","status":"active"}'),
+			('people/freya', 'people/freya.md', 'person', 1, '{}'),
+			('notes/a', 'notes/a.md', 'page', 1, '{}'),
+			('notes/b', 'notes/b.md', 'page', 1, '{}'),
+			('notes/lonely', 'notes/lonely.md', 'page', 1, '{}')
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert objects: %v", err)
+	}
+	_, err = db.DB().Exec(`
+		INSERT INTO sections (id, file_object_id, file_path, slug, title, level, line_start) VALUES
+			('notes/a#plan', 'notes/a', 'notes/a.md', 'plan', 'Plan', 2, 3)
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert sections: %v", err)
+	}
+	_, err = db.DB().Exec(`
+		INSERT INTO refs (source_id, target_id, target_raw, file_path, line_number) VALUES
+			('projects/raven', 'people/freya', 'people/freya', 'projects/raven.md', 3),
+			('notes/a#plan', 'projects/raven', 'projects/raven', 'notes/a.md', 4),
+			('notes/a', 'projects/raven', 'raven', 'notes/a.md', 6),
+			('notes/b', 'notes/a', 'notes/a', 'notes/b.md', 1),
+			('notes/b', NULL, 'nowhere', 'notes/b.md', 2),
+			('notes/a', 'notes/a', 'notes/a', 'notes/a.md', 7)
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert refs: %v", err)
+	}
+	_, err = db.DB().Exec(`
+		INSERT INTO field_refs (source_id, field_name, target_id, target_raw, resolution_status, file_path, line_number) VALUES
+			('projects/raven', 'owner', 'people/freya', 'people/freya', 'resolved', 'projects/raven.md', 3)
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert field refs: %v", err)
+	}
+	return vaultPath
+}
+
+func edgeKeys(g Graph) []string {
+	keys := make([]string, 0, len(g.Edges))
+	for _, edge := range g.Edges {
+		keys = append(keys, edge.Kind+":"+edge.Source+"->"+edge.Target)
+	}
+	return keys
+}
+
+func nodeIDs(g Graph) []string {
+	ids := make([]string, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		ids = append(ids, node.ID)
+	}
+	return ids
+}
+
+func TestExport_CollapsesRefsOntoObjects(t *testing.T) {
+	t.Parallel()
+	vaultPath := setupGraphVault(t)
+
+	result, err := Export(ExportRequest{VaultPath: vaultPath})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if got := strings.Join(nodeIDs(result.Graph), ","); got != "notes/a,notes/b,notes/lonely,people/freya,projects/raven" {
+		t.Fatalf("nodes = %s", got)
+	}
+	if got := strings.Join(edgeKeys(result.Graph), ","); got != "ref:projects/raven->people/freya,ref:notes/a->projects/raven,ref:notes/b->notes/a" {
+		t.Fatalf("edges = %s", got)
+	}
+	for _, edge := range result.Graph.Edges {
+		switch edge.Source {
+		case "projects/raven":
+			if len(edge.Fields) != 1 || edge.Fields[0] != "owner" || edge.Count != 1 {
+				t.Fatalf("field edge = %+v", edge)
+			}
+		case "notes/a":
+			if edge.Count != 2 {
+				t.Fatalf("expected section and body refs to collapse into one edge, got %+v", edge)
+			}
+		}
+	}
+	if !strings.HasPrefix(result.Content, "{") {
+		t.Fatalf("expected JSON content by default, got %q", result.Content)
+	}
+}
+
+func TestExport_RootDepthAndTypes(t *testing.T) {
+	t.Parallel()
+	vaultPath := setupGraphVault(t)
+
+	result, err := Export(ExportRequest{VaultPath: vaultPath, Root: "projects/raven", Depth: 1})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if got := strings.Join(nodeIDs(result.Graph), ","); got != "notes/a,people/freya,projects/raven" {
+		t.Fatalf("depth 1 nodes = %s", got)
+	}
+
+	result, err = Export(ExportRequest{VaultPath: vaultPath, Root: "projects/raven", Types: []string{"page"}})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if got := strings.Join(nodeIDs(result.Graph), ","); got != "notes/a,notes/b,projects/raven" {
+		t.Fatalf("typed nodes = %s", got)
+	}
+
+	_, err = Export(ExportRequest{VaultPath: vaultPath, Root: "projects/missing"})
+	if svcErr, ok := AsError(err); !ok || svcErr.Code != CodeInvalidInput {
+		t.Fatalf("expected invalid input for unknown root, got %v", err)
+	}
+}
+
+func TestExport_SectionsAndFormats(t *testing.T) {
+	t.Parallel()
+	vaultPath := setupGraphVault(t)
+
+	result, err := Export(ExportRequest{VaultPath: vaultPath, Format: "dot", Sections: true, Output: "exports/graph.dot"})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	keys := strings.Join(edgeKeys(result.Graph), ",")
+	if !strings.Contains(keys, "parent:notes/a#plan->notes/a") || !strings.Contains(keys, "ref:notes/a#plan->projects/raven") {
+		t.Fatalf("expected section node edges, got %s", keys)
+	}
+	if !strings.Contains(result.Content, `"projects/raven" -> "people/freya" [label="owner"];`) {
+		t.Fatalf("unexpected DOT output:\n%s", result.Content)
+	}
+	written, err := os.ReadFile(filepath.Join(vaultPath, "exports", "graph.dot"))
+	if err != nil {
+		t.Fatalf("expected output file: %v", err)
+	}
+	if string(written) != result.Content {
+		t.Fatalf("written file does not match content")
+	}
+
+	result, err = Export(ExportRequest{VaultPath: vaultPath, Format: "graphml"})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if !strings.Contains(result.Content, `<data key="fields">{&quot;owner&quot;:&quot;people/freya&quot;,&quot;status&quot;:&quot;active&quot;}</data>`) {
+		t.Fatalf("unexpected GraphML output:\n%s", result.Content)
+	}
+
+	_, err = Export(ExportRequest{VaultPath: vaultPath, Format: "svg"})
+	if svcErr, ok := AsError(err); !ok || svcErr.Code != CodeInvalidInput {
+		t.Fatalf("expected invalid input for unknown format, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestUndoRestoresChangedAndRemovesCreatedFiles(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithFile("notes/a.md", "a before\n").
+		WithFile("notes/b.md", "b before\n").
+		Build()
+
+	j, err := Begin(v.Path, "set", "set notes/a", nil)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	writeVaultFile(t, v.Path, "notes/a.md", "a after\n")
+	writeVaultFile(t, v.Path, "notes/new/c.md", "created\n")
+	Capture(filepath.Join(v.Path, "notes/b.md"))
+	if err := os.Remove(filepath.Join(v.Path, "notes/b.md")); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := j.Finish()
+	if err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	if entry == nil || len(entry.Files) != 3 {
+		t.Fatalf("entry = %+v, want 3 changed files", entry)
+	}
+
+	plan, err := PlanUndo(v.Path, 1)
+	if err != nil {
+		t.Fatalf("PlanUndo: %v", err)
+	}
+	// notes/a.md was written without a capture, so it cannot be restored.
+	if len(plan.Conflicts) != 1 || plan.Conflicts[0].Path != "notes/a.md" {
+		t.Fatalf("conflicts = %+v", plan.Conflicts)
+	}
+	if _, err := ApplyUndo(v.Path, plan); err != nil {
+		t.Fatalf("ApplyUndo: %v", err)
+	}
+
+	if got := v.ReadFile("notes/b.md"); got != "b before\n" {
+		t.Fatalf("b.md = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(v.Path, "notes/new")); !os.IsNotExist(err) {
+		t.Fatalf("created file or its directory was not removed: %v", err)
+	}
+	if got := v.ReadFile("notes/a.md"); got != "a after\n" {
+		t.Fatalf("untracked file should be left as is, got %q", got)
+	}
+	if _, err := PlanUndo(v.Path, 1); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("expected nothing left to undo, got %v", err)
+	}
+}
+
+func TestPlanUndoReportsLaterEditsAndChainsOperations(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).WithFile("a.md", "v1\n").Build()
+	path := filepath.Join(v.Path, "a.md")
+
+	for _, content := range []string{"v2\n", "v3\n"} {
+		j, err := Begin(v.Path, "edit", "edit a", nil)
+		if err != nil {
+			t.Fatalf("Begin: %v", err)
+		}
+		Capture(path)
+		writeVaultFile(t, v.Path, "a.md", content)
+		if _, err := j.Finish(); err != nil {
+			t.Fatalf("Finish: %v", err)
+		}
+	}
+
+	writeVaultFile(t, v.Path, "a.md", "edited by hand\n")
+	plan, err := PlanUndo(v.Path, 1)
+	if err != nil {
+		t.Fatalf("PlanUndo: %v", err)
+	}
+	if len(plan.Conflicts) != 1 {
+		t.Fatalf("expected a conflict for the later edit, got %+v", plan.Conflicts)
+	}
+
+	writeVaultFile(t, v.Path, "a.md", "v3\n")
+	plan, err = PlanUndo(v.Path, 2)
+	if err != nil {
+		t.Fatalf("PlanUndo: %v", err)
+	}
+	if len(plan.Entries) != 2 || len(plan.Conflicts) != 0 {
+		t.Fatalf("plan = %+v", plan)
+	}
+	if _, err := ApplyUndo(v.Path, plan); err != nil {
+		t.Fatalf("ApplyUndo: %v", err)
+	}
+	if got := v.ReadFile("a.md"); got != "v1\n" {
+		t.Fatalf("a.md = %q, want v1", got)
+	}
+}
+
+func writeVaultFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
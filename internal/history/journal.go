@@ -0,0 +1,338 @@
+// Package history records the files each mutating command changes so recent
+// operations can be listed and undone.
+//
+// A Journal is active for one vault while a recorded command runs. Write
+// paths call Capture before replacing, moving, or removing a file so the
+// journal keeps its before-image; files created by the command are found by
+// comparing the vault's file listing before and after.
+package history
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type fileStat struct {
+	size    int64
+	modTime time.Time
+}
+
+type capturedFile struct {
+	exists  bool
+	content []byte
+}
+
+// Journal collects before-images for one command run.
+type Journal struct {
+	root      string
+	command   string
+	summary   string
+	args      map[string]interface{}
+	startedAt time.Time
+	before    map[string]fileStat
+
+	mu       sync.Mutex
+	captured map[string]*capturedFile
+}
+
+var (
+	activeMu sync.Mutex
+	active   = map[string]*Journal{}
+
+	vaultLocksMu sync.Mutex
+	vaultLocks   = map[string]*sync.Mutex{}
+)
+
+type journalContextKey struct{}
+
+// WithJournal returns ctx carrying j, so nested commands join it.
+func WithJournal(ctx context.Context, j *Journal) context.Context {
+	return context.WithValue(ctx, journalContextKey{}, j)
+}
+
+// FromContext returns the journal recording the current command, if any.
+func FromContext(ctx context.Context) (*Journal, bool) {
+	j, ok := ctx.Value(journalContextKey{}).(*Journal)
+	return j, ok && j != nil
+}
+
+// Begin starts recording a command for vaultPath. Recorded commands on the
+// same vault run one at a time until Finish is called.
+func Begin(vaultPath, command, summary string, args map[string]interface{}) (*Journal, error) {
+	root, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+	root = filepath.Clean(root)
+
+	lock := vaultLock(root)
+	lock.Lock()
+	before, err := scanVault(root)
+	if err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+
+	j := &Journal{
+		root:      root,
+		command:   command,
+		summary:   summary,
+		args:      args,
+		startedAt: time.Now().UTC(),
+		before:    before,
+		captured:  map[string]*capturedFile{},
+	}
+	activeMu.Lock()
+	active[root] = j
+	activeMu.Unlock()
+	return j, nil
+}
+
+// Finish stops recording. When the command changed any files, the operation
+// is saved to the vault's history and returned.
+func (j *Journal) Finish() (*Entry, error) {
+	j.stop()
+	defer vaultLock(j.root).Unlock()
+
+	after, err := scanVault(j.root)
+	if err != nil {
+		return nil, err
+	}
+	changes, blobs := j.diff(after)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	entry, err := newEntry(j.command, j.summary, j.args, j.startedAt, changes)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveEntry(j.root, entry, blobs); err != nil {
+		return nil, err
+	}
+	if err := prune(j.root, MaxEntries); err != nil {
+		return entry, err
+	}
+	return entry, nil
+}
+
+// Changes lists the files the command has changed so far without ending the
+// recording.
+func (j *Journal) Changes() ([]FileChange, error) {
+	after, err := scanVault(j.root)
+	if err != nil {
+		return nil, err
+	}
+	changes, _ := j.diff(after)
+	return changes, nil
+}
+
+// Rollback stops recording and restores every file the command changed, so
+// nothing is saved to history. It returns the changes it reverted; files
+// changed without a before-image are left as they are and marked Untracked.
+func (j *Journal) Rollback() ([]FileChange, error) {
+	j.stop()
+	defer vaultLock(j.root).Unlock()
+
+	after, err := scanVault(j.root)
+	if err != nil {
+		return nil, err
+	}
+	changes, blobs := j.diff(after)
+	for _, change := range changes {
+		target := filepath.Join(j.root, filepath.FromSlash(change.Path))
+		switch {
+		case change.Untracked:
+			continue
+		case change.BeforeExists:
+			if err := restoreFile(target, blobs[change.Path]); err != nil {
+				return changes, fmt.Errorf("restore %s: %w", change.Path, err)
+			}
+		default:
+			if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return changes, fmt.Errorf("remove %s: %w", change.Path, err)
+			}
+			removeEmptyParents(j.root, filepath.Dir(target))
+		}
+	}
+	return changes, nil
+}
+
+func (j *Journal) stop() {
+	activeMu.Lock()
+	if active[j.root] == j {
+		delete(active, j.root)
+	}
+	activeMu.Unlock()
+}
+
+// Capture records the current content of path as its before-image in the
+// journal active for the containing vault. Only the first capture of a path
+// per command is kept. It is a no-op when no command is being recorded.
+func Capture(path string) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+	abs = filepath.Clean(abs)
+
+	activeMu.Lock()
+	var j *Journal
+	for root, candidate := range active {
+		if strings.HasPrefix(abs, root+string(filepath.Separator)) {
+			j = candidate
+			break
+		}
+	}
+	activeMu.Unlock()
+	if j == nil {
+		return
+	}
+
+	rel, err := filepath.Rel(j.root, abs)
+	if err != nil {
+		return
+	}
+	rel = filepath.ToSlash(rel)
+	if ignoredPath(rel) {
+		return
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, seen := j.captured[rel]; seen {
+		return
+	}
+	content, err := os.ReadFile(abs)
+	switch {
+	case err == nil:
+		j.captured[rel] = &capturedFile{exists: true, content: content}
+	case errors.Is(err, os.ErrNotExist):
+		j.captured[rel] = &capturedFile{}
+	}
+}
+
+// diff compares the vault before and after the command and returns the
+// changed files with the before-images to store, keyed by path.
+func (j *Journal) diff(after map[string]fileStat) ([]FileChange, map[string][]byte) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	paths := map[string]struct{}{}
+	for rel := range j.captured {
+		paths[rel] = struct{}{}
+	}
+	for rel, st := range after {
+		if prev, ok := j.before[rel]; !ok || prev != st {
+			paths[rel] = struct{}{}
+		}
+	}
+	for rel := range j.before {
+		if _, ok := after[rel]; !ok {
+			paths[rel] = struct{}{}
+		}
+	}
+
+	blobs := map[string][]byte{}
+	var changes []FileChange
+	for _, rel := range sortedKeys(paths) {
+		change := FileChange{Path: rel}
+		if content, err := os.ReadFile(filepath.Join(j.root, filepath.FromSlash(rel))); err == nil {
+			change.AfterExists = true
+			change.AfterHash = hashContent(content)
+		}
+
+		if captured, ok := j.captured[rel]; ok {
+			change.BeforeExists = captured.exists
+			if captured.exists {
+				change.BeforeHash = hashContent(captured.content)
+				blobs[rel] = captured.content
+			}
+		} else if _, existed := j.before[rel]; existed {
+			// Changed by a write path that does not capture before-images.
+			change.BeforeExists = true
+			change.Untracked = true
+		}
+
+		if change.BeforeExists == change.AfterExists && change.BeforeHash == change.AfterHash && !change.Untracked {
+			continue
+		}
+		if !change.BeforeExists && !change.AfterExists {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes, blobs
+}
+
+func vaultLock(root string) *sync.Mutex {
+	vaultLocksMu.Lock()
+	defer vaultLocksMu.Unlock()
+	lock, ok := vaultLocks[root]
+	if !ok {
+		lock = &sync.Mutex{}
+		vaultLocks[root] = lock
+	}
+	return lock
+}
+
+// scanVault lists the vault's files with their size and modification time.
+func scanVault(root string) (map[string]fileStat, error) {
+	files := map[string]fileStat{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if errors.Is(walkErr, os.ErrNotExist) {
+				return nil
+			}
+			return walkErr
+		}
+		if path == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if ignoredPath(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files[rel] = fileStat{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	return files, err
+}
+
+// ignoredPath reports whether rel is Raven or VCS state rather than vault
+// content, including in-flight atomic write temp files.
+func ignoredPath(rel string) bool {
+	if rel == ".raven" || strings.HasPrefix(rel, ".raven/") || rel == ".git" || strings.HasPrefix(rel, ".git/") {
+		return true
+	}
+	base := filepath.Base(rel)
+	return strings.HasPrefix(base, ".") && strings.Contains(base, ".tmp-")
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
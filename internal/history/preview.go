@@ -0,0 +1,130 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// PreviewDirName is the vault-relative directory holding the file contents
+// previews were planned against.
+const PreviewDirName = ".raven/previews"
+
+// PreviewTTL is how long a preview is matched against a later apply. Older
+// previews are ignored and pruned.
+const PreviewTTL = time.Hour
+
+// PreviewFile is one file as it was on disk when a preview was generated.
+// Content holds the raw bytes, so files in encrypted directories stay
+// encrypted in the record.
+type PreviewFile struct {
+	Path    string `json:"path"`
+	Exists  bool   `json:"exists"`
+	Hash    string `json:"hash,omitempty"`
+	Content []byte `json:"content,omitempty"`
+}
+
+// Preview records the files a previewed command would change, so applying
+// the same command can tell whether they changed in between.
+type Preview struct {
+	Key       string        `json:"key"`
+	CreatedAt time.Time     `json:"created_at"`
+	Files     []PreviewFile `json:"files"`
+}
+
+// SavePreview records the current content of files (vault-relative) for the
+// preview identified by key, replacing an earlier preview of the same call.
+func SavePreview(vaultPath, key string, files []string) error {
+	preview := &Preview{Key: key, CreatedAt: time.Now().UTC()}
+	seen := make(map[string]struct{}, len(files))
+	for _, rel := range files {
+		rel = filepath.ToSlash(rel)
+		if _, ok := seen[rel]; ok || rel == "" {
+			continue
+		}
+		seen[rel] = struct{}{}
+		file := PreviewFile{Path: rel}
+		content, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(rel)))
+		if err == nil {
+			file.Exists = true
+			file.Hash = hashContent(content)
+			file.Content = content
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		preview.Files = append(preview.Files, file)
+	}
+
+	dir := filepath.Join(vaultPath, filepath.FromSlash(PreviewDirName))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create preview directory: %w", err)
+	}
+	prunePreviews(dir)
+	data, err := json.Marshal(preview)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(previewPath(vaultPath, key), data, 0o600)
+}
+
+// LoadPreview returns the unexpired preview recorded for key, or nil.
+func LoadPreview(vaultPath, key string) (*Preview, error) {
+	data, err := os.ReadFile(previewPath(vaultPath, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var preview Preview
+	if err := json.Unmarshal(data, &preview); err != nil || preview.Key != key {
+		return nil, nil
+	}
+	if time.Since(preview.CreatedAt) > PreviewTTL {
+		return nil, nil
+	}
+	return &preview, nil
+}
+
+// DeletePreview removes the preview recorded for key once it is applied.
+func DeletePreview(vaultPath, key string) {
+	_ = os.Remove(previewPath(vaultPath, key))
+}
+
+// Changed returns the files whose content on disk no longer matches the
+// preview.
+func (p *Preview) Changed(vaultPath string) []PreviewFile {
+	var changed []PreviewFile
+	for _, file := range p.Files {
+		content, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(file.Path)))
+		exists := err == nil
+		if exists != file.Exists || (exists && hashContent(content) != file.Hash) {
+			changed = append(changed, file)
+		}
+	}
+	return changed
+}
+
+func previewPath(vaultPath, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(vaultPath, filepath.FromSlash(PreviewDirName), hex.EncodeToString(sum[:12])+".json")
+}
+
+// prunePreviews removes expired preview records.
+func prunePreviews(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err == nil && time.Since(info.ModTime()) > PreviewTTL {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package history
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DirName is the vault-relative directory holding recorded operations.
+const DirName = ".raven/history"
+
+// MaxEntries is how many operations are kept; older ones are pruned.
+const MaxEntries = 50
+
+const (
+	entryFileName = "entry.json"
+	blobDirName   = "before"
+)
+
+// FileChange describes one file changed by an operation.
+type FileChange struct {
+	Path         string `json:"path"`
+	BeforeExists bool   `json:"before_exists"`
+	BeforeHash   string `json:"before_hash,omitempty"`
+	AfterExists  bool   `json:"after_exists"`
+	AfterHash    string `json:"after_hash,omitempty"`
+	// Untracked marks files changed without a recorded before-image; they
+	// cannot be restored by undo.
+	Untracked bool `json:"untracked,omitempty"`
+}
+
+// Entry is one recorded operation.
+type Entry struct {
+	ID        string                 `json:"id"`
+	Command   string                 `json:"command"`
+	Summary   string                 `json:"summary"`
+	Args      map[string]interface{} `json:"args,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	Files     []FileChange           `json:"files"`
+	UndoneAt  *time.Time             `json:"undone_at,omitempty"`
+}
+
+func newEntry(command, summary string, args map[string]interface{}, createdAt time.Time, files []FileChange) (*Entry, error) {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+	storedArgs := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		if key == "confirm" {
+			continue
+		}
+		storedArgs[key] = value
+	}
+	return &Entry{
+		ID:        fmt.Sprintf("h-%s-%s", createdAt.Format("20060102-150405"), hex.EncodeToString(suffix)),
+		Command:   command,
+		Summary:   summary,
+		Args:      storedArgs,
+		CreatedAt: createdAt,
+		Files:     files,
+	}, nil
+}
+
+// saveEntry writes the entry and its before-images under the history directory.
+// It writes directly rather than through atomicfile so recording never
+// captures its own files.
+func saveEntry(root string, entry *Entry, blobs map[string][]byte) error {
+	dir := entryDir(root, entry.ID)
+	for rel, content := range blobs {
+		blobPath := filepath.Join(dir, blobDirName, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(blobPath), 0o755); err != nil {
+			return fmt.Errorf("create history directory: %w", err)
+		}
+		if err := os.WriteFile(blobPath, content, 0o644); err != nil {
+			return fmt.Errorf("write history snapshot: %w", err)
+		}
+	}
+	return writeEntry(root, entry)
+}
+
+func writeEntry(root string, entry *Entry) error {
+	dir := entryDir(root, entry.ID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create history directory: %w", err)
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, entryFileName), append(data, '\n'), 0o644)
+}
+
+// List returns recorded operations, newest first.
+func List(vaultPath string) ([]*Entry, error) {
+	dirEntries, err := os.ReadDir(filepath.Join(vaultPath, filepath.FromSlash(DirName)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*Entry, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() {
+			continue
+		}
+		entry, err := load(vaultPath, dirEntry.Name())
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, k int) bool {
+		if entries[i].CreatedAt.Equal(entries[k].CreatedAt) {
+			return entries[i].ID > entries[k].ID
+		}
+		return entries[i].CreatedAt.After(entries[k].CreatedAt)
+	})
+	return entries, nil
+}
+
+func load(vaultPath, id string) (*Entry, error) {
+	data, err := os.ReadFile(filepath.Join(entryDir(vaultPath, id), entryFileName))
+	if err != nil {
+		return nil, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("parse history entry %s: %w", id, err)
+	}
+	return &entry, nil
+}
+
+// prune removes all but the newest keep entries.
+func prune(root string, keep int) error {
+	entries, err := List(root)
+	if err != nil || len(entries) <= keep {
+		return err
+	}
+	for _, entry := range entries[keep:] {
+		if err := os.RemoveAll(entryDir(root, entry.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func entryDir(root, id string) string {
+	return filepath.Join(root, filepath.FromSlash(DirName), id)
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,206 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNothingToUndo is returned when no recorded operation is left to undo.
+var ErrNothingToUndo = errors.New("no recorded operations to undo")
+
+// Actions reported for files in an undo plan.
+const (
+	UndoActionRestore = "restore"
+	UndoActionRemove  = "remove"
+	UndoActionSkip    = "skip"
+)
+
+// UndoFile is one file an undo will restore or remove.
+type UndoFile struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+
+	// entryID names the operation whose before-image is restored.
+	entryID string
+}
+
+// UndoConflict is a file whose current content no longer matches what the
+// operation left behind, or that cannot be restored.
+type UndoConflict struct {
+	Path    string `json:"path"`
+	EntryID string `json:"entry_id"`
+	Reason  string `json:"reason"`
+}
+
+// UndoPlan describes the effect of undoing the most recent operations.
+type UndoPlan struct {
+	Entries   []*Entry
+	Files     []UndoFile
+	Conflicts []UndoConflict
+}
+
+type fileState struct {
+	exists bool
+	hash   string
+}
+
+// PlanUndo works out how to revert the count most recent operations that have
+// not already been undone. Files changed since an operation ran, and files the
+// operation changed without a before-image, are reported as conflicts.
+func PlanUndo(vaultPath string, count int) (*UndoPlan, error) {
+	if count < 1 {
+		count = 1
+	}
+	entries, err := List(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &UndoPlan{}
+	for _, entry := range entries {
+		if entry.UndoneAt != nil {
+			continue
+		}
+		plan.Entries = append(plan.Entries, entry)
+		if len(plan.Entries) == count {
+			break
+		}
+	}
+	if len(plan.Entries) == 0 {
+		return nil, ErrNothingToUndo
+	}
+
+	// Walk operations newest first, tracking what each file will look like
+	// once the newer operations have been reverted.
+	state := map[string]fileState{}
+	targets := map[string]UndoFile{}
+	for _, entry := range plan.Entries {
+		for _, change := range entry.Files {
+			current, ok := state[change.Path]
+			if !ok {
+				current = currentState(vaultPath, change.Path)
+			}
+			if current.exists != change.AfterExists || current.hash != change.AfterHash {
+				plan.Conflicts = append(plan.Conflicts, UndoConflict{Path: change.Path, EntryID: entry.ID, Reason: "changed since the operation ran"})
+			}
+			if change.Untracked {
+				plan.Conflicts = append(plan.Conflicts, UndoConflict{Path: change.Path, EntryID: entry.ID, Reason: "no before-image was recorded"})
+				targets[change.Path] = UndoFile{Path: change.Path, Action: UndoActionSkip}
+				state[change.Path] = current
+				continue
+			}
+
+			target := UndoFile{Path: change.Path, Action: UndoActionRemove}
+			if change.BeforeExists {
+				target = UndoFile{Path: change.Path, Action: UndoActionRestore, entryID: entry.ID}
+			}
+			targets[change.Path] = target
+			state[change.Path] = fileState{exists: change.BeforeExists, hash: change.BeforeHash}
+		}
+	}
+
+	keys := make(map[string]struct{}, len(targets))
+	for path := range targets {
+		keys[path] = struct{}{}
+	}
+	for _, path := range sortedKeys(keys) {
+		plan.Files = append(plan.Files, targets[path])
+	}
+	return plan, nil
+}
+
+// ApplyUndo restores the files in plan and marks its operations as undone.
+// It returns the absolute paths of files it restored or removed.
+func ApplyUndo(vaultPath string, plan *UndoPlan) ([]string, error) {
+	root, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+	root = filepath.Clean(root)
+	lock := vaultLock(root)
+	lock.Lock()
+	defer lock.Unlock()
+
+	var changed []string
+	for _, file := range plan.Files {
+		target := filepath.Join(root, filepath.FromSlash(file.Path))
+		switch file.Action {
+		case UndoActionRestore:
+			content, err := os.ReadFile(filepath.Join(entryDir(root, file.entryID), blobDirName, filepath.FromSlash(file.Path)))
+			if err != nil {
+				return changed, fmt.Errorf("read snapshot of %s: %w", file.Path, err)
+			}
+			if err := restoreFile(target, content); err != nil {
+				return changed, fmt.Errorf("restore %s: %w", file.Path, err)
+			}
+		case UndoActionRemove:
+			if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return changed, fmt.Errorf("remove %s: %w", file.Path, err)
+			}
+			removeEmptyParents(root, filepath.Dir(target))
+		default:
+			continue
+		}
+		changed = append(changed, target)
+	}
+
+	now := time.Now().UTC()
+	for _, entry := range plan.Entries {
+		entry.UndoneAt = &now
+		if err := writeEntry(root, entry); err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+func currentState(vaultPath, rel string) fileState {
+	content, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(rel)))
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, hash: hashContent(content)}
+}
+
+// removeEmptyParents removes dir and its parents while they are empty,
+// stopping at the vault root.
+func removeEmptyParents(root, dir string) {
+	for dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// restoreFile writes content through a temp file in the target directory.
+// atomicfile imports this package, so it cannot be used here.
+func restoreFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	_ = tmp.Chmod(0o644)
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
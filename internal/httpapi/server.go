@@ -0,0 +1,300 @@
+// Package httpapi serves a read-only JSON API over a vault so web UIs and
+// scripts can query it without shelling out to the CLI.
+package httpapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/app"
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/commands"
+)
+
+// maxRequestBody caps POST bodies; query arguments are small.
+const maxRequestBody = 1 << 20
+
+// Options configures a Server.
+type Options struct {
+	VaultPath string
+	// Token is the bearer token every request must present.
+	Token string
+	// AllowOrigin, when set, is returned as Access-Control-Allow-Origin so a
+	// browser app on that origin can call the API.
+	AllowOrigin string
+	// Invoker overrides the shared command invoker (tests).
+	Invoker *commandexec.Invoker
+}
+
+// Server routes HTTP requests to Raven's read commands.
+type Server struct {
+	opts    Options
+	invoker *commandexec.Invoker
+	mux     *http.ServeMux
+}
+
+// endpoint maps a route to a command and the arguments it may receive.
+type endpoint struct {
+	commandID string
+	// params lists the canonical arguments callers may set. Anything that
+	// writes to the vault or needs a terminal is left out.
+	params []string
+	// aliases maps short query parameter names to canonical arguments.
+	aliases map[string]string
+	// pathParam receives the trailing path segment, as in /api/read/people/freya.
+	pathParam string
+}
+
+var (
+	queryEndpoint = endpoint{
+		commandID: "query",
+		params:    []string{"query_string", "refresh", "require-fresh", "limit", "offset", "cursor", "count-only", "select", "explain-matches", "inputs"},
+		aliases:   map[string]string{"q": "query_string"},
+	}
+	readEndpoint = endpoint{
+		commandID: "read",
+		params:    []string{"path", "raw", "lines", "start-line", "end-line", "full", "sections", "render"},
+		pathParam: "path",
+	}
+	backlinksEndpoint = endpoint{
+		commandID: "backlinks",
+		params:    []string{"target", "group-by"},
+		pathParam: "target",
+	}
+	statsEndpoint = endpoint{
+		commandID: "vault_stats",
+		params:    []string{"by-author"},
+	}
+)
+
+// NewServer builds a server for one vault.
+func NewServer(opts Options) (*Server, error) {
+	if strings.TrimSpace(opts.VaultPath) == "" {
+		return nil, errors.New("vault path is required")
+	}
+	if strings.TrimSpace(opts.Token) == "" {
+		return nil, errors.New("an API token is required")
+	}
+	s := &Server{opts: opts, invoker: opts.Invoker}
+	if s.invoker == nil {
+		s.invoker = app.CommandInvoker()
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET /api/query", s.handle(queryEndpoint))
+	s.mux.HandleFunc("POST /api/query", s.handle(queryEndpoint))
+	s.mux.HandleFunc("GET /api/read", s.handle(readEndpoint))
+	s.mux.HandleFunc("GET /api/read/{path...}", s.handle(readEndpoint))
+	s.mux.HandleFunc("GET /api/backlinks", s.handle(backlinksEndpoint))
+	s.mux.HandleFunc("GET /api/backlinks/{target...}", s.handle(backlinksEndpoint))
+	s.mux.HandleFunc("GET /api/stats", s.handle(statsEndpoint))
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeResult(w, commandexec.Failure(codes.ErrNotFound, "no such endpoint: "+r.URL.Path, nil, "Use /api/query, /api/read, /api/backlinks, or /api/stats"))
+	})
+	return s, nil
+}
+
+// ServeHTTP authenticates the request and dispatches it.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if origin := strings.TrimSpace(s.opts.AllowOrigin); origin != "" {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Add("Vary", "Origin")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="raven"`)
+		writeResult(w, commandexec.Failure(codes.ErrUnauthorized, "missing or invalid API token", nil, "Send the token as 'Authorization: Bearer <token>'"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(s.opts.Token)) == 1
+}
+
+// ListenAndServe serves on addr until ctx is cancelled. ready, if non-nil, is
+// called with the bound address once the listener is open.
+func (s *Server) ListenAndServe(ctx context.Context, addr string, ready func(net.Addr)) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("http listener on %s: %w", addr, err)
+	}
+	server := &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	if ready != nil {
+		ready(listener.Addr())
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(listener) }()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+func (s *Server) handle(ep endpoint) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		args, err := ep.args(r)
+		if err != nil {
+			writeResult(w, commandexec.Failure(codes.ErrInvalidArgs, err.Error(), map[string]interface{}{"allowed": ep.params}, ""))
+			return
+		}
+		result := s.invoker.Execute(r.Context(), commandexec.Request{
+			CommandID: ep.commandID,
+			VaultPath: s.opts.VaultPath,
+			Caller:    commandexec.CallerHTTP,
+			Args:      args,
+		})
+		writeResult(w, result)
+	}
+}
+
+// args collects the command arguments from the URL path, query string, and
+// (for POST) a JSON object body.
+func (ep endpoint) args(r *http.Request) (map[string]interface{}, error) {
+	contract, ok := commands.BuildCommandContract(ep.commandID)
+	if !ok {
+		return nil, fmt.Errorf("unknown command %q", ep.commandID)
+	}
+	spec := commands.BuildInvokeParamSpec(contract)
+	args := map[string]interface{}{}
+
+	resolve := func(key string) (string, error) {
+		if alias, ok := ep.aliases[key]; ok {
+			key = alias
+		}
+		name, ok := commands.CanonicalArgumentName(spec, key)
+		if !ok || !ep.allows(name) {
+			return "", fmt.Errorf("unsupported parameter %q", key)
+		}
+		if _, dup := args[name]; dup {
+			return "", fmt.Errorf("parameter %q given more than once", key)
+		}
+		return name, nil
+	}
+
+	if ep.pathParam != "" {
+		if value := r.PathValue(ep.pathParam); value != "" {
+			args[ep.pathParam] = value
+		}
+	}
+	for key, values := range r.URL.Query() {
+		name, err := resolve(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := queryValue(key, spec[name].Type, values)
+		if err != nil {
+			return nil, err
+		}
+		args[name] = value
+	}
+	if r.Method == http.MethodPost {
+		var body map[string]interface{}
+		decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxRequestBody))
+		if err := decoder.Decode(&body); err != nil {
+			return nil, fmt.Errorf("request body must be a JSON object of arguments: %v", err)
+		}
+		for key, value := range body {
+			name, err := resolve(key)
+			if err != nil {
+				return nil, err
+			}
+			args[name] = value
+		}
+	}
+	return args, nil
+}
+
+func (ep endpoint) allows(name string) bool {
+	for _, param := range ep.params {
+		if param == name {
+			return true
+		}
+	}
+	return false
+}
+
+// queryValue converts a query string parameter to the type its argument
+// expects. Bare boolean flags (?raw) are true.
+func queryValue(key string, paramType commands.ParameterType, values []string) (interface{}, error) {
+	if paramType == commands.ParameterTypeStringArray {
+		return values, nil
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("parameter %q given more than once", key)
+	}
+	raw := values[0]
+	switch paramType {
+	case commands.ParameterTypeBool:
+		if raw == "" {
+			return true, nil
+		}
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q must be true or false", key)
+		}
+		return parsed, nil
+	case commands.ParameterTypeInteger:
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q must be an integer", key)
+		}
+		return parsed, nil
+	case commands.ParameterTypeObject:
+		return nil, fmt.Errorf("parameter %q is an object; send it in a POST body", key)
+	default:
+		return raw, nil
+	}
+}
+
+func writeResult(w http.ResponseWriter, result commandexec.Result) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusFor(result))
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(result)
+}
+
+// statusFor maps a result envelope to an HTTP status. The envelope itself
+// carries the precise error code.
+func statusFor(result commandexec.Result) int {
+	if result.OK || result.Error == nil {
+		return http.StatusOK
+	}
+	code := result.Error.Code
+	switch {
+	case code == codes.ErrUnauthorized:
+		return http.StatusUnauthorized
+	case code == codes.ErrNotFound || strings.HasSuffix(string(code), "_NOT_FOUND"):
+		return http.StatusNotFound
+	case result.Error.Category == codes.CategoryUser || result.Error.Category == codes.CategorySchema:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
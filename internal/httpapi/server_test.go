@@ -0,0 +1,145 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/app"
+	"github.com/aidanlsb/raven/internal/commandexec"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+const testToken = "secret"
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(`version: 1
+types:
+  person:
+    default_path: people/
+    name_field: name
+    fields:
+      name:
+        type: string
+        required: true
+`).
+		WithFile("people/freya.md", "---\ntype: person\nname: Freya\n---\n").
+		WithFile("people/odin.md", "---\ntype: person\nname: Odin\n---\n\nFather of [[people/freya]].\n").
+		Build()
+	if result := app.CommandInvoker().Execute(context.Background(), commandexec.Request{
+		CommandID: "reindex",
+		VaultPath: v.Path,
+		Args:      map[string]any{"full": true},
+	}); !result.OK {
+		t.Fatalf("reindex failed: %#v", result.Error)
+	}
+
+	server, err := NewServer(Options{VaultPath: v.Path, Token: testToken})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	return server
+}
+
+func serve(t *testing.T, server *Server, method, target, body string) (int, commandexec.Result) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	var result commandexec.Result
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode %s %s response %q: %v", method, target, rec.Body.String(), err)
+	}
+	return rec.Code, result
+}
+
+func TestServerRequiresToken(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+	for _, header := range []string{"", "Bearer wrong", testToken} {
+		req := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), "UNAUTHORIZED") {
+			t.Fatalf("Authorization %q: status %d body %s, want 401 UNAUTHORIZED", header, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestServerEndpoints(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	status, result := serve(t, server, http.MethodGet, "/api/query?q=type:person&limit=1", "")
+	data, _ := result.Data.(map[string]interface{})
+	if status != http.StatusOK || data["total"] != float64(2) || data["has_more"] != true {
+		t.Fatalf("GET /api/query = %d %#v", status, result)
+	}
+
+	status, result = serve(t, server, http.MethodPost, "/api/query", `{"query_string":"type:person","count_only":true}`)
+	data, _ = result.Data.(map[string]interface{})
+	if status != http.StatusOK || data["total"] != float64(2) {
+		t.Fatalf("POST /api/query = %d %#v", status, result)
+	}
+
+	status, result = serve(t, server, http.MethodGet, "/api/read/people/freya?raw", "")
+	data, _ = result.Data.(map[string]interface{})
+	if status != http.StatusOK || !strings.Contains(data["content"].(string), "name: Freya") {
+		t.Fatalf("GET /api/read = %d %#v", status, result)
+	}
+
+	status, result = serve(t, server, http.MethodGet, "/api/backlinks/people/freya", "")
+	data, _ = result.Data.(map[string]interface{})
+	items, _ := data["items"].([]interface{})
+	if status != http.StatusOK || len(items) != 1 {
+		t.Fatalf("GET /api/backlinks = %d %#v", status, result)
+	}
+
+	status, result = serve(t, server, http.MethodGet, "/api/stats", "")
+	if status != http.StatusOK || !result.OK {
+		t.Fatalf("GET /api/stats = %d %#v", status, result)
+	}
+}
+
+func TestServerRejectsWritesAndBadInput(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+	cases := []struct {
+		method, target, body string
+		status               int
+		code                 string
+	}{
+		{http.MethodGet, "/api/query?q=type:person&apply=delete&confirm=true", "", http.StatusBadRequest, "INVALID_ARGS"},
+		{http.MethodPost, "/api/query", `{"query_string":"type:person","apply":["delete"]}`, http.StatusBadRequest, "INVALID_ARGS"},
+		{http.MethodGet, "/api/query?q=type:person&limit=ten", "", http.StatusBadRequest, "INVALID_ARGS"},
+		{http.MethodGet, "/api/read/people/nobody", "", http.StatusNotFound, "REF_NOT_FOUND"},
+		{http.MethodGet, "/api/delete", "", http.StatusNotFound, "NOT_FOUND"},
+	}
+	for _, tc := range cases {
+		status, result := serve(t, server, tc.method, tc.target, tc.body)
+		if status != tc.status || result.Error == nil || string(result.Error.Code) != tc.code {
+			t.Errorf("%s %s = %d %#v, want %d %s", tc.method, tc.target, status, result.Error, tc.status, tc.code)
+		}
+	}
+
+	status, result := serve(t, server, http.MethodGet, "/api/query?q=type:person", "")
+	data, _ := result.Data.(map[string]interface{})
+	if status != http.StatusOK || data["total"] != float64(2) {
+		t.Fatalf("objects changed after rejected writes: %d %#v", status, result)
+	}
+}
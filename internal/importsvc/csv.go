@@ -0,0 +1,199 @@
+package importsvc
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/dates"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// ReadCSVInput reads CSV rows from a file, or from stdin when filePath is
+// empty or "-". The first row names the columns and is returned as the
+// header. Each later row becomes an item keyed by column, without its empty
+// cells, so data row i is items[i-1].
+func ReadCSVInput(filePath string, stdin io.Reader) ([]map[string]interface{}, []string, error) {
+	var data []byte
+	var err error
+	if path := strings.TrimSpace(filePath); path != "" && path != "-" {
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, nil, newError(CodeInvalidInput, fmt.Sprintf("failed to read file %s: %v", path, err), err)
+		}
+	} else {
+		data, err = io.ReadAll(stdin)
+		if err != nil {
+			return nil, nil, newError(CodeInvalidInput, fmt.Sprintf("failed to read stdin: %v", err), err)
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, newError(CodeInvalidInput, fmt.Sprintf("invalid CSV: %v", err), err)
+	}
+	if len(records) == 0 {
+		return nil, nil, newError(CodeInvalidInput, "empty input", nil)
+	}
+
+	header := make([]string, len(records[0]))
+	for i, column := range records[0] {
+		header[i] = strings.TrimSpace(column)
+	}
+	items := make([]map[string]interface{}, 0, len(records)-1)
+	for _, record := range records[1:] {
+		item := make(map[string]interface{}, len(header))
+		for i, cell := range record {
+			if i >= len(header) || header[i] == "" {
+				break
+			}
+			if cell = strings.TrimSpace(cell); cell != "" {
+				item[header[i]] = cell
+			}
+		}
+		items = append(items, item)
+	}
+	return items, header, nil
+}
+
+// MapCSVColumns maps CSV columns to typeName's fields. mapFlags are
+// field=Column pairs, several to a flag when comma-separated; other columns map to the field with the same name,
+// compared case-insensitively with spaces as underscores ("Due Date" is
+// due_date). It returns the column -> field map and the columns matching no
+// field, which are not imported. contentColumn, if set, must be a column and
+// is left unmapped.
+func MapCSVColumns(header []string, mapFlags []string, typeName string, sch *schema.Schema, contentColumn string) (map[string]string, []string, error) {
+	columns := make(map[string]bool, len(header))
+	for _, column := range header {
+		columns[column] = true
+	}
+	if contentColumn != "" && !columns[contentColumn] {
+		return nil, nil, newError(CodeInvalidInput, fmt.Sprintf("content column %q not found in CSV header", contentColumn), nil)
+	}
+
+	columnMap := make(map[string]string, len(header))
+	for _, flag := range splitMapFlags(mapFlags) {
+		field, column, ok := strings.Cut(flag, "=")
+		field, column = strings.TrimSpace(field), strings.TrimSpace(column)
+		if !ok || field == "" || column == "" {
+			return nil, nil, newError(CodeInvalidInput, fmt.Sprintf("invalid --map format: %q (expected field=Column)", flag), nil)
+		}
+		if !columns[column] {
+			return nil, nil, newError(CodeInvalidInput, fmt.Sprintf("column %q not found in CSV header", column), nil)
+		}
+		columnMap[column] = field
+	}
+
+	var typeDef *schema.TypeDefinition
+	if sch != nil {
+		typeDef = sch.Types[typeName]
+	}
+	var ignored []string
+	for _, column := range header {
+		if column == "" || column == contentColumn {
+			continue
+		}
+		if _, mapped := columnMap[column]; mapped {
+			continue
+		}
+		if field := csvColumnField(column, typeDef); field != "" {
+			columnMap[column] = field
+			continue
+		}
+		ignored = append(ignored, column)
+	}
+	return columnMap, ignored, nil
+}
+
+// splitMapFlags splits "a=A,b=B" flags into pairs. A comma not followed by
+// another pair belongs to the column name.
+func splitMapFlags(flags []string) []string {
+	var pairs []string
+	for _, flag := range flags {
+		start := len(pairs)
+		for _, part := range strings.Split(flag, ",") {
+			if len(pairs) > start && !strings.Contains(part, "=") {
+				pairs[len(pairs)-1] += "," + part
+				continue
+			}
+			pairs = append(pairs, part)
+		}
+	}
+	return pairs
+}
+
+// csvColumnField returns the field of typeDef a column names, or "".
+func csvColumnField(column string, typeDef *schema.TypeDefinition) string {
+	if column == "alias" {
+		return column
+	}
+	if typeDef == nil {
+		return ""
+	}
+	if _, ok := typeDef.Fields[column]; ok {
+		return column
+	}
+	key := dataviewFieldKey(column)
+	if _, ok := typeDef.Fields[key]; ok {
+		return key
+	}
+	return ""
+}
+
+// coerceStringFields converts string values to their field's type: numbers,
+// booleans, loosely written dates, and comma-separated lists for array
+// fields. Values that do not convert are left for validation to report.
+func coerceStringFields(fields map[string]interface{}, typeDef *schema.TypeDefinition) {
+	if typeDef == nil {
+		return
+	}
+	for key, value := range fields {
+		raw, ok := value.(string)
+		def := typeDef.Fields[key]
+		if !ok || def == nil || def.Type == schema.FieldTypeObject || def.Type == schema.FieldTypeObjectArray {
+			continue
+		}
+		if item, isArray := strings.CutSuffix(string(def.Type), "[]"); isArray {
+			if strings.HasPrefix(raw, "[") && !strings.HasPrefix(raw, "[[") {
+				raw = strings.TrimSuffix(strings.TrimPrefix(raw, "["), "]")
+			}
+			parts := strings.Split(raw, ",")
+			items := make([]interface{}, 0, len(parts))
+			for _, part := range parts {
+				if part = strings.TrimSpace(part); part != "" {
+					items = append(items, coerceString(part, schema.FieldType(item)))
+				}
+			}
+			fields[key] = items
+			continue
+		}
+		fields[key] = coerceString(raw, def.Type)
+	}
+}
+
+func coerceString(raw string, fieldType schema.FieldType) interface{} {
+	switch fieldType {
+	case schema.FieldTypeNumber:
+		if n, err := strconv.ParseFloat(strings.ReplaceAll(raw, ",", ""), 64); err == nil {
+			return n
+		}
+	case schema.FieldTypeBool:
+		switch strings.ToLower(raw) {
+		case "true", "yes", "y", "1":
+			return true
+		case "false", "no", "n", "0":
+			return false
+		}
+	case schema.FieldTypeDate:
+		if date, ok := dates.CanonicalizeDate(raw); ok {
+			return date
+		}
+	}
+	return raw
+}
@@ -0,0 +1,109 @@
+package importsvc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+const csvTestSchema = `version: 1
+types:
+  person:
+    default_path: people/
+    name_field: name
+    fields:
+      name:
+        type: string
+        required: true
+      email:
+        type: string
+      age:
+        type: number
+      active:
+        type: bool
+      met_on:
+        type: date
+      tags:
+        type: string[]
+traits: {}
+`
+
+func TestMapCSVColumnsMatchesFieldsAndReportsIgnoredColumns(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).WithSchema(csvTestSchema).Build()
+	sch, err := schema.Load(v.Path)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	header := []string{"Full Name", "E-mail, work", "Met On", "Age", "Notes", "Shoe Size"}
+
+	columnMap, ignored, err := MapCSVColumns(header, []string{"name=Full Name,email=E-mail, work"}, "person", sch, "Notes")
+	if err != nil {
+		t.Fatalf("MapCSVColumns: %v", err)
+	}
+	want := map[string]string{"Full Name": "name", "E-mail, work": "email", "Met On": "met_on", "Age": "age"}
+	if !reflect.DeepEqual(columnMap, want) {
+		t.Fatalf("column map = %v, want %v", columnMap, want)
+	}
+	if !reflect.DeepEqual(ignored, []string{"Shoe Size"}) {
+		t.Fatalf("ignored = %v, want [Shoe Size]", ignored)
+	}
+
+	if _, _, err := MapCSVColumns(header, []string{"email=Mail"}, "person", sch, ""); err == nil || !strings.Contains(err.Error(), `"Mail"`) {
+		t.Fatalf("err = %v, want unknown column Mail", err)
+	}
+}
+
+func TestRunCoercesCSVValuesAndSkipsDuplicateKeys(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).WithSchema(csvTestSchema).Build()
+	items, header, err := ReadCSVInput("", strings.NewReader("\xef\xbb\xbfName,Age,Active,Met On,Tags\n"+
+		"Freya Stark,34,yes,\"Mar 1, 2026\",\"core, lead\"\n"+
+		"Freya Stark,40,no,,\n"+
+		",1,,,\n"))
+	if err != nil {
+		t.Fatalf("ReadCSVInput: %v", err)
+	}
+	sch, err := schema.Load(v.Path)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	columnMap, _, err := MapCSVColumns(header, nil, "person", sch, "")
+	if err != nil {
+		t.Fatalf("MapCSVColumns: %v", err)
+	}
+
+	result, err := Run(RunRequest{
+		VaultPath:         v.Path,
+		MappingConfig:     &MappingConfig{Type: "person", Map: columnMap},
+		Items:             items,
+		CoerceStrings:     true,
+		SkipDuplicateKeys: true,
+		ItemLabel:         func(i int) string { return fmt.Sprintf("row %d", i+1) },
+	})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	actions := make([]string, len(result.Results))
+	for i, item := range result.Results {
+		actions[i] = item.Action + " " + item.ID + " " + item.Reason
+	}
+	want := []string{
+		"created people/freya-stark ",
+		"skipped people/freya-stark duplicate name 'Freya Stark' (first in row 1)",
+		"skipped row 3 missing match key 'name'",
+	}
+	if !reflect.DeepEqual(actions, want) {
+		t.Fatalf("results = %q, want %q", actions, want)
+	}
+	for _, line := range []string{"age: 34\n", "active: true\n", "met_on: \"2026-03-01\"\n", "    - core\n    - lead\n"} {
+		v.AssertFileContains("people/freya-stark.md", line)
+	}
+}
@@ -0,0 +1,294 @@
+package importsvc
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/pages"
+)
+
+// logseqFormat reads Logseq graphs: markdown pages made of "- " blocks, kept
+// in pages/ and journals/.
+type logseqFormat struct{}
+
+var (
+	logseqBlockRE    = regexp.MustCompile(`^([ \t]*)-(?:[ \t]+(.*))?$`)
+	logseqPropertyRE = regexp.MustCompile(`^([A-Za-z][\w.-]*)::(?:[ \t]+(.*?))?[ \t]*$`)
+	logseqTaskRE     = regexp.MustCompile(`^(TODO|DOING|NOW|LATER|WAITING|WAIT|IN-PROGRESS|DONE|CANCELED|CANCELLED)(?:\s+|$)`)
+	logseqHeadingRE  = regexp.MustCompile(`^#{1,6}\s+`)
+	logseqJournalRE  = regexp.MustCompile(`^(\d{4})_(\d{2})_(\d{2})$`)
+	// logseqLinkRE matches [label]([[Page]]), #[[Page]], and [[Page]].
+	logseqLinkRE     = regexp.MustCompile(`\[([^\]\[]+)\]\(\[\[([^\]\[]+)\]\]\)|(#?)\[\[([^\]\[]+)\]\]`)
+	logseqBlockRefRE = regexp.MustCompile(`\(\(([0-9A-Fa-f-]{36})\)\)`)
+)
+
+// logseqSkippedDirs hold app data, not pages.
+var logseqSkippedDirs = map[string]bool{"logseq": true, "assets": true, "draws": true, "whiteboards": true, "version-files": true}
+
+// logseqTaskStates maps Logseq task keywords to task states.
+var logseqTaskStates = map[string]string{
+	"DONE":      taskDone,
+	"CANCELED":  taskCancelled,
+	"CANCELLED": taskCancelled,
+}
+
+func (logseqFormat) layout() folderLayout {
+	return folderLayout{
+		skipDir: func(rel string) bool { return logseqSkippedDirs[rel] },
+		relID:   logseqRelID,
+	}
+}
+
+// logseqRelID places pages/ at the top of the destination, expands
+// namespaced file names (a___b.md is page a/b), and names journals by date.
+func logseqRelID(source string) string {
+	dir, base := path.Split(strings.TrimSuffix(source, path.Ext(source)))
+	if m := logseqJournalRE.FindStringSubmatch(base); m != nil && dir == "journals/" {
+		return "journals/" + m[1] + "-" + m[2] + "-" + m[3]
+	}
+	if dir == "pages/" {
+		dir = ""
+	}
+	return pages.SlugifyPath(path.Join(dir, logseqPageName(base)))
+}
+
+// logseqPageName recovers a page name from its file name.
+func logseqPageName(base string) string {
+	name := strings.ReplaceAll(base, "___", "/")
+	if decoded, err := url.PathUnescape(name); err == nil {
+		name = decoded
+	}
+	return name
+}
+
+// logseqJournalTitle formats a journal date the way Logseq links to it by
+// default ("Mar 1st, 2026").
+func logseqJournalTitle(date time.Time) string {
+	day := date.Day()
+	suffix := "th"
+	if day < 11 || day > 13 {
+		switch day % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%s %d%s, %d", date.Format("Jan"), day, suffix, date.Year())
+}
+
+// logseqBlockFrame is an open block while parsing, with the indentation of
+// its dash and of its continuation lines.
+type logseqBlockFrame struct {
+	block        *outlineBlock
+	indent       int
+	contentWidth int
+}
+
+func (logseqFormat) parse(source, content string) (*outlineNote, error) {
+	base := strings.TrimSuffix(path.Base(source), path.Ext(source))
+	note := &outlineNote{title: logseqPageName(base)}
+	if m := logseqJournalRE.FindStringSubmatch(base); m != nil {
+		if date, err := time.Parse("2006_01_02", base); err == nil {
+			note.title = logseqJournalTitle(date)
+			note.names = append(note.names, m[1]+"-"+m[2]+"-"+m[3], base)
+		}
+	}
+
+	var pageProps []outlineProperty
+	var stack []logseqBlockFrame
+	var current *logseqBlockFrame
+	fence, drawer := "", false
+	for i, line := range strings.Split(content, "\n") {
+		lineNum := i + 1
+		if fence == "" && !drawer {
+			if m := logseqBlockRE.FindStringSubmatch(line); m != nil {
+				indent := indentWidth(m[1])
+				b := &outlineBlock{line: lineNum}
+				for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+					stack = stack[:len(stack)-1]
+				}
+				if len(stack) == 0 {
+					note.blocks = append(note.blocks, b)
+				} else {
+					parent := stack[len(stack)-1].block
+					parent.children = append(parent.children, b)
+				}
+				frame := logseqBlockFrame{block: b, indent: indent, contentWidth: indent + 2}
+				stack = append(stack, frame)
+				current = &frame
+				fence = parseLogseqBlockLine(b, m[2], lineNum)
+				continue
+			}
+		}
+
+		if current == nil {
+			// Page properties and text before the first block.
+			trimmed := strings.TrimSpace(line)
+			if m := logseqPropertyRE.FindStringSubmatch(trimmed); m != nil && len(note.preamble) == 0 {
+				pageProps = append(pageProps, outlineProperty{key: m[1], value: m[2], line: lineNum})
+			} else if trimmed != "" || len(note.preamble) > 0 {
+				note.preamble = append(note.preamble, outlineLine{text: line, line: lineNum})
+			}
+			continue
+		}
+
+		b := current.block
+		text := stripIndentWidth(line, current.contentWidth)
+		trimmed := strings.TrimSpace(text)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			b.body = append(b.body, outlineLine{text: text, line: lineNum})
+		case drawer:
+			drawer = !strings.EqualFold(trimmed, ":END:")
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+			b.body = append(b.body, outlineLine{text: text, line: lineNum})
+		case trimmed == ":LOGBOOK:":
+			drawer = true
+		case len(b.body) == 0 && logseqPropertyRE.MatchString(trimmed):
+			addLogseqBlockProperty(b, logseqPropertyRE.FindStringSubmatch(trimmed), lineNum)
+		case outlinePlanningRE.MatchString(trimmed):
+			rest, due := parsePlanning(trimmed)
+			if due != "" {
+				b.due = due
+			}
+			if rest != "" {
+				b.body = append(b.body, outlineLine{text: rest, line: lineNum})
+			}
+		default:
+			b.body = append(b.body, outlineLine{text: text, line: lineNum})
+		}
+	}
+
+	// A first block holding only properties carries the page's properties.
+	if len(pageProps) == 0 && len(note.blocks) > 0 {
+		first := note.blocks[0]
+		if first.text == "" && first.task == "" && len(first.body) == 0 && len(first.children) == 0 {
+			pageProps = first.properties
+			if len(first.tags) > 0 {
+				pageProps = append(pageProps, outlineProperty{key: "tags", value: strings.Join(first.tags, ", "), line: first.line})
+			}
+			note.blocks = note.blocks[1:]
+		}
+	}
+	for _, prop := range pageProps {
+		switch strings.ToLower(prop.key) {
+		case "title":
+			note.title = strings.TrimSpace(prop.value)
+		case "alias":
+			note.aliases = append(note.aliases, splitListValue(prop.value)...)
+		case "tags":
+			note.tags = append(note.tags, splitListValue(prop.value)...)
+		default:
+			note.properties = append(note.properties, prop)
+		}
+	}
+	return note, nil
+}
+
+// parseLogseqBlockLine reads a block's first line: task keyword, priority,
+// heading marker, or a property when the block starts with one. It returns
+// the code fence the line opens, if any.
+func parseLogseqBlockLine(b *outlineBlock, text string, lineNum int) string {
+	text = strings.TrimSpace(text)
+	if m := logseqPropertyRE.FindStringSubmatch(text); m != nil {
+		addLogseqBlockProperty(b, m, lineNum)
+		return ""
+	}
+	if strings.HasPrefix(text, "```") || strings.HasPrefix(text, "~~~") {
+		b.body = append(b.body, outlineLine{text: text, line: lineNum})
+		return text[:3]
+	}
+	if m := logseqTaskRE.FindStringSubmatch(text); m != nil {
+		b.task = taskOpen
+		if state, ok := logseqTaskStates[m[1]]; ok {
+			b.task = state
+		}
+		text = text[len(m[0]):]
+	}
+	text, b.priority = takePriority(text)
+	if loc := logseqHeadingRE.FindStringIndex(text); loc != nil {
+		b.heading = true
+		text = text[loc[1]:]
+	}
+	b.text = strings.TrimSpace(text)
+	return ""
+}
+
+func addLogseqBlockProperty(b *outlineBlock, m []string, lineNum int) {
+	switch strings.ToLower(m[1]) {
+	case "id":
+		b.nodeID = strings.TrimSpace(m[2])
+	case "heading":
+		b.heading = b.heading || strings.TrimSpace(m[2]) != "false"
+	case "tags":
+		b.tags = append(b.tags, splitListValue(m[2])...)
+	default:
+		b.properties = append(b.properties, outlineProperty{key: m[1], value: m[2], line: lineNum})
+	}
+}
+
+func (logseqFormat) convertText(c *outlineConverter, text string, line int) string {
+	if !strings.Contains(text, "[[") {
+		return text
+	}
+	return mapOutsideCode(text, func(segment string) string {
+		return logseqLinkRE.ReplaceAllStringFunc(segment, func(match string) string {
+			parts := logseqLinkRE.FindStringSubmatch(match)
+			if parts[2] != "" {
+				return c.pageLink(parts[2], parts[1], match, line)
+			}
+			// Logseq treats #[[Page]] as a link to the page.
+			return c.pageLink(parts[4], parts[4], match, line)
+		})
+	})
+}
+
+func (logseqFormat) nodeRefPattern() *regexp.Regexp {
+	return logseqBlockRefRE
+}
+
+// isSection makes a section of every block other blocks hang off or refer
+// to, and of blocks written as headings.
+func (logseqFormat) isSection(b *outlineBlock) bool {
+	return b.heading || b.nodeID != "" || len(b.children) > 0
+}
+
+// indentWidth measures leading whitespace, counting a tab as two spaces.
+func indentWidth(ws string) int {
+	width := 0
+	for _, r := range ws {
+		if r == '\t' {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// stripIndentWidth removes up to width columns of leading whitespace.
+func stripIndentWidth(line string, width int) string {
+	removed := 0
+	for i, r := range line {
+		if removed >= width || (r != ' ' && r != '\t') {
+			return line[i:]
+		}
+		if r == '\t' {
+			removed += 2
+		} else {
+			removed++
+		}
+	}
+	return ""
+}
@@ -0,0 +1,351 @@
+package importsvc
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/pages"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/slugs"
+)
+
+// Reasons reported for links that could not be converted to [[refs]].
+const (
+	UnresolvedTargetNotFound  = "target not found in import folder"
+	UnresolvedOutsideFolder   = "target is outside the import folder"
+	UnresolvedNotMarkdownFile = "target is not a markdown file"
+)
+
+type MarkdownImportRequest struct {
+	VaultPath   string
+	VaultConfig *config.VaultConfig
+	SourceDir   string
+	// Destination is the vault directory (under the pages root) to import into.
+	// Defaults to the slug of the source folder name.
+	Destination string
+	DryRun      bool
+}
+
+type MarkdownImportFile struct {
+	Source         string `json:"source"`
+	ID             string `json:"id"`
+	File           string `json:"file"`
+	Renamed        bool   `json:"renamed,omitempty"`
+	LinksConverted int    `json:"links_converted"`
+}
+
+type UnresolvedLink struct {
+	Source string `json:"source"`
+	Line   int    `json:"line"`
+	Link   string `json:"link"`
+	Reason string `json:"reason"`
+}
+
+type MarkdownImportResult struct {
+	Destination      string
+	Files            []MarkdownImportFile
+	Unresolved       []UnresolvedLink
+	ChangedFilePaths []string
+}
+
+// markdownLinkRE matches inline Markdown links and images: [text](target "title").
+var markdownLinkRE = regexp.MustCompile(`(!?)\[([^\]]*)\]\((<[^>]*>|[^)\s]+)(\s+"[^"]*")?\)`)
+
+// uriSchemeRE matches a leading URI scheme such as https: or mailto:.
+var uriSchemeRE = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+
+// ImportMarkdown copies the .md files under SourceDir into the vault, giving
+// each a slugified ID that does not collide with existing objects, and turns
+// relative links between imported files into [[refs]]. Links that cannot be
+// converted are left as written and reported in Unresolved.
+func ImportMarkdown(req MarkdownImportRequest) (*MarkdownImportResult, error) {
+	plan, err := planFolderImport(req.VaultPath, req.VaultConfig, req.SourceDir, req.Destination, folderLayout{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MarkdownImportResult{Destination: plan.Destination, Files: plan.Files}
+	for i := range result.Files {
+		file := &result.Files[i]
+		content, err := os.ReadFile(filepath.Join(plan.SourceDir, filepath.FromSlash(file.Source)))
+		if err != nil {
+			return nil, newError(codes.ErrFileRead, fmt.Sprintf("failed to read %s: %v", file.Source, err), err)
+		}
+		converted, count, unresolved := convertMarkdownLinks(string(content), file.Source, plan.IDsBySource)
+		file.LinksConverted = count
+		result.Unresolved = append(result.Unresolved, unresolved...)
+		if req.DryRun {
+			continue
+		}
+
+		target, err := writeImportedFile(plan.VaultPath, file.File, converted)
+		if err != nil {
+			return nil, err
+		}
+		result.ChangedFilePaths = append(result.ChangedFilePaths, target)
+	}
+
+	return result, nil
+}
+
+// folderLayout adapts planFolderImport to a source format. The zero value
+// imports every .md file at its slugified path.
+type folderLayout struct {
+	ext     string                     // Source file extension (default .md)
+	skipDir func(rel string) bool      // Directories left out of the import
+	relID   func(source string) string // ID below the destination (default: slugified source path)
+}
+
+// folderImportPlan is a folder of markdown files with a vault ID assigned to
+// each one.
+type folderImportPlan struct {
+	VaultPath   string
+	SourceDir   string
+	Destination string
+	Files       []MarkdownImportFile
+	IDsBySource map[string]string
+	claimed     map[string]bool
+}
+
+// claimID returns baseID, or baseID with the first numeric suffix (-2, -3,
+// ...) that is neither claimed by this import nor an existing object.
+func (plan *folderImportPlan) claimID(baseID, objectsRoot, pagesRoot string) string {
+	id := baseID
+	for n := 2; plan.claimed[id] || objectIDExists(plan.VaultPath, id, objectsRoot, pagesRoot); n++ {
+		id = fmt.Sprintf("%s-%d", baseID, n)
+	}
+	plan.claimed[id] = true
+	return id
+}
+
+// planFolderImport validates a folder import and gives each source file under
+// sourceDir a slugified ID that does not collide with existing objects.
+func planFolderImport(vaultPath string, vaultCfg *config.VaultConfig, sourceDir, destination string, layout folderLayout) (*folderImportPlan, error) {
+	vaultPath = strings.TrimSpace(vaultPath)
+	if vaultPath == "" {
+		return nil, newError(CodeInvalidInput, "vault path is required", nil)
+	}
+	if vaultCfg == nil {
+		return nil, newError(CodeConfigInvalid, "vault config is required", nil)
+	}
+	sourceArg := strings.TrimSpace(sourceDir)
+	sourceDir, err := filepath.Abs(sourceArg)
+	if err != nil || sourceArg == "" {
+		return nil, newError(CodeInvalidInput, "source folder is required", err)
+	}
+	info, err := os.Stat(sourceDir)
+	if err != nil || !info.IsDir() {
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("source folder not found: %s", sourceArg), err)
+	}
+	if err := paths.ValidateWithinVault(vaultPath, sourceDir); err == nil {
+		return nil, newError(CodeInvalidInput, "source folder is already inside the vault", nil)
+	}
+
+	plan := &folderImportPlan{VaultPath: vaultPath, SourceDir: sourceDir, Destination: pages.Slugify(filepath.Base(sourceDir))}
+	if strings.TrimSpace(destination) != "" {
+		plan.Destination = pages.SlugifyPath(strings.Trim(paths.NormalizeVaultRelPath(destination), "/"))
+	}
+	if !paths.IsValidVaultRelPath(plan.Destination) {
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("invalid destination: %s", destination), nil)
+	}
+
+	if layout.ext == "" {
+		layout.ext = ".md"
+	}
+	if layout.relID == nil {
+		layout.relID = pages.SlugifyPath
+	}
+	sources, err := collectSources(sourceDir, layout)
+	if err != nil {
+		return nil, newError(codes.ErrFileRead, fmt.Sprintf("failed to read source folder: %v", err), err)
+	}
+	if len(sources) == 0 {
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("no %s files found in source folder", layout.ext), nil)
+	}
+
+	objectsRoot := vaultCfg.GetObjectsRoot()
+	pagesRoot := vaultCfg.GetPagesRoot()
+	plan.claimed = make(map[string]bool, len(sources))
+	plan.IDsBySource = make(map[string]string, len(sources))
+	for _, source := range sources {
+		baseID := path.Join(plan.Destination, layout.relID(source))
+		id := plan.claimID(baseID, objectsRoot, pagesRoot)
+		plan.IDsBySource[source] = id
+		plan.Files = append(plan.Files, MarkdownImportFile{
+			Source:  source,
+			ID:      id,
+			File:    paths.ObjectIDToFilePath(id, "", objectsRoot, pagesRoot),
+			Renamed: id != baseID,
+		})
+	}
+	return plan, nil
+}
+
+// writeImportedFile writes an imported file to its vault-relative path and
+// returns the absolute path written.
+func writeImportedFile(vaultPath, relPath, content string) (string, error) {
+	target := filepath.Join(vaultPath, filepath.FromSlash(relPath))
+	if err := paths.ValidateWithinVault(vaultPath, target); err != nil {
+		return "", newError(CodeInvalidInput, fmt.Sprintf("cannot import outside vault: %s", relPath), err)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", newError(codes.ErrFileWrite, fmt.Sprintf("failed to create directory for %s: %v", relPath, err), err)
+	}
+	if err := atomicfile.WriteFile(target, []byte(content), 0o644); err != nil {
+		return "", newError(codes.ErrFileWrite, fmt.Sprintf("failed to write %s: %v", relPath, err), err)
+	}
+	return target, nil
+}
+
+// collectSources returns the source-relative paths of the layout's files
+// under dir, skipping hidden files and directories.
+func collectSources(dir string, layout folderLayout) ([]string, error) {
+	var sources []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if p != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if p != dir && layout.skipDir != nil && layout.skipDir(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(p), layout.ext) {
+			return nil
+		}
+		sources = append(sources, rel)
+		return nil
+	})
+	sort.Strings(sources)
+	return sources, err
+}
+
+func objectIDExists(vaultPath, id, objectsRoot, pagesRoot string) bool {
+	for _, candidate := range paths.CandidateFilePaths(id, objectsRoot, pagesRoot) {
+		if _, err := os.Stat(filepath.Join(vaultPath, filepath.FromSlash(candidate))); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// convertMarkdownLinks rewrites relative links to other imported files as
+// [[refs]], leaving fenced code blocks untouched.
+func convertMarkdownLinks(content, source string, idsBySource map[string]string) (string, int, []UnresolvedLink) {
+	lines := strings.Split(content, "\n")
+	converted := 0
+	var unresolved []UnresolvedLink
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		lines[i] = markdownLinkRE.ReplaceAllStringFunc(line, func(match string) string {
+			parts := markdownLinkRE.FindStringSubmatch(match)
+			isImage, text, rawTarget := parts[1] == "!", parts[2], parts[3]
+			targetPath, fragment, local := splitLocalLinkTarget(rawTarget)
+			if !local {
+				return match
+			}
+
+			resolved, reason := resolveImportLink(source, targetPath, idsBySource)
+			if reason == "" && isImage {
+				reason = UnresolvedNotMarkdownFile
+			}
+			if reason != "" {
+				unresolved = append(unresolved, UnresolvedLink{Source: source, Line: i + 1, Link: rawTarget, Reason: reason})
+				return match
+			}
+
+			converted++
+			ref := resolved
+			if fragment != "" {
+				ref += "#" + slugs.HeadingSlug(fragment)
+			}
+			if text = strings.TrimSpace(text); text != "" && text != resolved {
+				ref += "|" + text
+			}
+			return "[[" + ref + "]]"
+		})
+	}
+	return strings.Join(lines, "\n"), converted, unresolved
+}
+
+// splitLocalLinkTarget separates a link target into its decoded path and
+// fragment. URLs and same-page anchors are not local.
+func splitLocalLinkTarget(raw string) (string, string, bool) {
+	target := strings.TrimSuffix(strings.TrimPrefix(raw, "<"), ">")
+	if target == "" || strings.HasPrefix(target, "#") || uriSchemeRE.MatchString(target) {
+		return "", "", false
+	}
+	fragment := ""
+	if idx := strings.Index(target, "#"); idx >= 0 {
+		target, fragment = target[:idx], target[idx+1:]
+	}
+	if decoded, err := url.PathUnescape(target); err == nil {
+		target = decoded
+	}
+	return target, fragment, true
+}
+
+// resolveImportLink maps a link target, relative to the linking file, to the
+// ID of an imported file. It returns a reason when the link cannot be mapped.
+func resolveImportLink(source, target string, idsBySource map[string]string) (string, string) {
+	var rel string
+	if strings.HasPrefix(target, "/") {
+		rel = path.Clean(strings.TrimPrefix(target, "/"))
+	} else {
+		rel = path.Clean(path.Join(path.Dir(source), target))
+	}
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", UnresolvedOutsideFolder
+	}
+
+	ext := path.Ext(rel)
+	if ext != "" && !strings.EqualFold(ext, ".md") {
+		return "", UnresolvedNotMarkdownFile
+	}
+	if ext == "" {
+		rel += ".md"
+	}
+	if id, ok := idsBySource[rel]; ok {
+		return id, ""
+	}
+	for candidate, id := range idsBySource {
+		if strings.EqualFold(candidate, rel) {
+			return id, ""
+		}
+	}
+	return "", UnresolvedTargetNotFound
+}
@@ -0,0 +1,101 @@
+package importsvc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestImportMarkdownConvertsRelativeLinks(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithFile("garden/notes.md", "existing\n").
+		Build()
+
+	source := filepath.Join(t.TempDir(), "Garden")
+	writeSourceFile(t, source, "Index.md", "See [the plan](sub/Q1%20Plan.md#Goals) and [notes](Notes.md).\n"+
+		"![chart](chart.png) [site](https://example.com) [gone](missing.md)\n"+
+		"```\n[code](Notes.md)\n```\n")
+	writeSourceFile(t, source, "Notes.md", "notes\n")
+	writeSourceFile(t, source, "sub/Q1 Plan.md", "Back to [Index](../Index.md)\n")
+
+	result, err := ImportMarkdown(MarkdownImportRequest{
+		VaultPath:   v.Path,
+		VaultConfig: config.DefaultVaultConfig(),
+		SourceDir:   source,
+	})
+	if err != nil {
+		t.Fatalf("ImportMarkdown: %v", err)
+	}
+	if result.Destination != "garden" || len(result.Files) != 3 {
+		t.Fatalf("result = %+v", result)
+	}
+
+	notes := result.Files[1]
+	if notes.ID != "garden/notes-2" || !notes.Renamed {
+		t.Fatalf("expected Notes.md to be renamed around the existing ID, got %+v", notes)
+	}
+	if got := v.ReadFile("garden/notes.md"); got != "existing\n" {
+		t.Fatalf("existing file was overwritten: %q", got)
+	}
+
+	want := "See [[garden/sub/q1-plan#goals|the plan]] and [[garden/notes-2|notes]].\n" +
+		"![chart](chart.png) [site](https://example.com) [gone](missing.md)\n" +
+		"```\n[code](Notes.md)\n```\n"
+	if got := v.ReadFile("garden/index.md"); got != want {
+		t.Fatalf("index.md = %q, want %q", got, want)
+	}
+	if got := v.ReadFile("garden/sub/q1-plan.md"); got != "Back to [[garden/index|Index]]\n" {
+		t.Fatalf("q1-plan.md = %q", got)
+	}
+
+	if len(result.Unresolved) != 2 {
+		t.Fatalf("unresolved = %+v, want 2 entries", result.Unresolved)
+	}
+	if got := result.Unresolved[0]; got.Link != "chart.png" || got.Line != 2 || got.Reason != UnresolvedNotMarkdownFile {
+		t.Fatalf("unresolved[0] = %+v", got)
+	}
+	if got := result.Unresolved[1]; got.Link != "missing.md" || got.Reason != UnresolvedTargetNotFound {
+		t.Fatalf("unresolved[1] = %+v", got)
+	}
+}
+
+func TestImportMarkdownDryRunWritesNothing(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).Build()
+	source := t.TempDir()
+	writeSourceFile(t, source, "a.md", "a\n")
+
+	result, err := ImportMarkdown(MarkdownImportRequest{
+		VaultPath:   v.Path,
+		VaultConfig: config.DefaultVaultConfig(),
+		SourceDir:   source,
+		Destination: "imports",
+		DryRun:      true,
+	})
+	if err != nil {
+		t.Fatalf("ImportMarkdown: %v", err)
+	}
+	if len(result.Files) != 1 || result.Files[0].File != "imports/a.md" {
+		t.Fatalf("files = %+v", result.Files)
+	}
+	if _, err := os.Stat(filepath.Join(v.Path, "imports")); !os.IsNotExist(err) {
+		t.Fatalf("dry run created files: %v", err)
+	}
+}
+
+func writeSourceFile(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", rel, err)
+	}
+}
@@ -0,0 +1,688 @@
+package importsvc
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/dates"
+	"github.com/aidanlsb/raven/internal/pages"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// defaultNotionDestination is where a Notion export is imported without --to.
+const defaultNotionDestination = "notion"
+
+// maxNotionZipDepth is how deep zips inside the export are opened. Notion
+// splits large exports into part zips inside the downloaded one.
+const maxNotionZipDepth = 2
+
+type NotionImportRequest struct {
+	VaultPath   string
+	VaultConfig *config.VaultConfig
+	// Schema is the vault's current schema; types and fields it already
+	// defines are left out of the proposal. May be nil.
+	Schema *schema.Schema
+	// Source is the export .zip, or a folder it was unzipped to.
+	Source string
+	// Destination is the vault directory (under the pages root) to import into.
+	// Defaults to notion.
+	Destination string
+	DryRun      bool
+}
+
+type NotionImportFile struct {
+	Source         string   `json:"source"`
+	ID             string   `json:"id"`
+	File           string   `json:"file"`
+	Type           string   `json:"type,omitempty"`
+	Database       string   `json:"database,omitempty"`
+	Renamed        bool     `json:"renamed,omitempty"`
+	LinksConverted int      `json:"links_converted"`
+	Fields         []string `json:"fields,omitempty"` // Columns written to frontmatter
+}
+
+type NotionImportResult struct {
+	Destination string
+	Files       []NotionImportFile
+	// Types has one entry per database, named after it. Folder is the
+	// database's path in the export.
+	Types      []ProposedType
+	Unresolved []UnresolvedLink
+	// SchemaProposal is schema.yaml content declaring a type per database with
+	// a field per column. It is not applied.
+	SchemaProposal   string
+	ChangedFilePaths []string
+}
+
+var (
+	// notionIDRE matches the page ID Notion appends to exported file names.
+	notionIDRE = regexp.MustCompile(`\s+[0-9a-f]{32}$`)
+	// notionRelationRE matches one relation value, "Title (target)", where
+	// the target is a path or URL ending in the related page's ID.
+	notionRelationRE = regexp.MustCompile(`\s*([^()]*?)\s*\(([^()\s]*?([0-9a-f]{32})(?:\.md)?)\)\s*(?:,|$)`)
+	notionHexRE      = regexp.MustCompile(`[0-9a-f]{32}`)
+)
+
+// notionDatetimeLayouts are the spellings of dates with a time in exports.
+var notionDatetimeLayouts = []string{
+	"January 2, 2006 3:04 PM",
+	"January 2, 2006 15:04",
+	"2006/01/02 3:04 PM",
+	"2006/01/02 15:04",
+	dates.DatetimeLayout,
+}
+
+// ImportNotion copies a Notion "Markdown & CSV" export into the vault. Pages
+// keep their hierarchy without Notion's IDs in the names. Each database
+// becomes a type: its rows are typed objects whose frontmatter holds the
+// columns, with values converted by the type inferred for each column, and
+// relation columns become ref fields. Links between exported pages become
+// [[refs]], and a schema.yaml proposal for the databases is returned.
+func ImportNotion(req NotionImportRequest) (*NotionImportResult, error) {
+	sourceDir, cleanup, err := openNotionExport(req.Source)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	htmlFiles, err := collectSources(sourceDir, folderLayout{ext: ".html"})
+	if err != nil {
+		return nil, newError(codes.ErrFileRead, fmt.Sprintf("failed to read export: %v", err), err)
+	}
+	mdFiles, err := collectSources(sourceDir, folderLayout{ext: ".md"})
+	if err != nil {
+		return nil, newError(codes.ErrFileRead, fmt.Sprintf("failed to read export: %v", err), err)
+	}
+	if len(htmlFiles) > 0 && len(mdFiles) == 0 {
+		return nil, newError(CodeInvalidInput, "HTML exports are not supported; export from Notion as Markdown & CSV", nil)
+	}
+
+	destination := req.Destination
+	if strings.TrimSpace(destination) == "" {
+		destination = defaultNotionDestination
+	}
+	plan, err := planFolderImport(req.VaultPath, req.VaultConfig, sourceDir, destination, folderLayout{relID: notionRelID})
+	if err != nil {
+		return nil, err
+	}
+	objectsRoot := req.VaultConfig.GetObjectsRoot()
+	pagesRoot := req.VaultConfig.GetPagesRoot()
+
+	databases, err := loadNotionDatabases(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pair database rows with their pages; rows without one get an ID of
+	// their own.
+	idsByNotionID := make(map[string]string, len(plan.Files))
+	pagesByFolder := make(map[string][]string)
+	for _, file := range plan.Files {
+		base := strings.TrimSuffix(path.Base(file.Source), path.Ext(file.Source))
+		if hex := notionHexRE.FindString(base); hex != "" {
+			idsByNotionID[hex] = file.ID
+		}
+		dir := path.Dir(file.Source)
+		pagesByFolder[dir] = append(pagesByFolder[dir], file.Source)
+	}
+	rowsBySource := make(map[string]*notionRow)
+	typeByID := make(map[string]string)
+	var csvRows []*notionRow
+	for _, db := range databases {
+		unmatched := pagesByFolder[db.folder]
+		for _, record := range db.records {
+			row := &notionRow{db: db, record: record, title: strings.TrimSpace(record[0])}
+			for i, source := range unmatched {
+				if pages.Slugify(notionName(path.Base(strings.TrimSuffix(source, ".md")))) == pages.Slugify(row.title) {
+					row.source = source
+					row.id = plan.IDsBySource[source]
+					unmatched = append(unmatched[:i:i], unmatched[i+1:]...)
+					break
+				}
+			}
+			if row.source == "" {
+				title := row.title
+				if title == "" {
+					title = "Untitled"
+				}
+				baseID := path.Join(plan.Destination, notionRelID(db.folder), pages.Slugify(title))
+				row.source = db.source
+				row.id = plan.claimID(baseID, objectsRoot, pagesRoot)
+				row.renamed = row.id != baseID
+				csvRows = append(csvRows, row)
+			} else {
+				rowsBySource[row.source] = row
+			}
+			db.rows = append(db.rows, row)
+			typeByID[row.id] = db.typeName
+		}
+		// Pages in the database folder missing from the CSV are still rows.
+		for _, source := range unmatched {
+			row := &notionRow{db: db, source: source, id: plan.IDsBySource[source], title: notionName(path.Base(strings.TrimSuffix(source, ".md")))}
+			rowsBySource[source] = row
+			db.rows = append(db.rows, row)
+			typeByID[row.id] = db.typeName
+		}
+	}
+
+	result := &NotionImportResult{Destination: plan.Destination}
+	proposal := newSchemaProposal(req.Schema)
+	for _, db := range databases {
+		db.inferColumns(idsByNotionID, typeByID)
+		if db.typeName == "" {
+			continue
+		}
+		defaultPath := path.Join(plan.Destination, notionRelID(db.folder)) + "/"
+		proposal.addType(db.typeName, defaultPath)
+		for _, col := range db.columns {
+			proposal.addField(db.typeName, col.key, string(col.fieldType), col.target)
+			if col.ranged {
+				proposal.addField(db.typeName, col.key+"_end", string(col.fieldType), "")
+			}
+		}
+		proposed := ProposedType{Name: db.typeName, Folder: db.folder, DefaultPath: defaultPath, Files: len(db.rows)}
+		if req.Schema != nil && req.Schema.Types[db.typeName] != nil {
+			proposed.Exists = true
+		}
+		result.Types = append(result.Types, proposed)
+	}
+
+	rows := &notionRowWriter{objectsRoot: objectsRoot, pagesRoot: pagesRoot, idsByNotionID: idsByNotionID, proposal: proposal}
+	write := func(file NotionImportFile, content string) error {
+		if !req.DryRun {
+			target, err := writeImportedFile(plan.VaultPath, file.File, content)
+			if err != nil {
+				return err
+			}
+			result.ChangedFilePaths = append(result.ChangedFilePaths, target)
+		}
+		result.Files = append(result.Files, file)
+		return nil
+	}
+
+	for _, planned := range plan.Files {
+		file := NotionImportFile{Source: planned.Source, ID: planned.ID, File: planned.File, Renamed: planned.Renamed}
+		content, err := os.ReadFile(filepath.Join(plan.SourceDir, filepath.FromSlash(file.Source)))
+		if err != nil {
+			return nil, newError(codes.ErrFileRead, fmt.Sprintf("failed to read %s: %v", file.Source, err), err)
+		}
+		body, bodyLine := strings.ReplaceAll(string(content), "\r\n", "\n"), 1
+		row := rowsBySource[file.Source]
+		if row != nil {
+			body, bodyLine = stripNotionRowHeader(body, row.db.header)
+		}
+		body, count, unresolved := convertMarkdownLinks(body, file.Source, plan.IDsBySource)
+		file.LinksConverted = count
+		for _, link := range unresolved {
+			link.Line += bodyLine - 1
+			result.Unresolved = append(result.Unresolved, link)
+		}
+		if row != nil {
+			body, err = rows.render(row, &file, body)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := write(file, body); err != nil {
+			return nil, err
+		}
+	}
+	for _, row := range csvRows {
+		file := NotionImportFile{Source: row.source, ID: row.id, File: paths.ObjectIDToFilePath(row.id, "", objectsRoot, pagesRoot), Renamed: row.renamed}
+		content, err := rows.render(row, &file, "")
+		if err != nil {
+			return nil, err
+		}
+		if err := write(file, content); err != nil {
+			return nil, err
+		}
+	}
+
+	result.Unresolved = append(result.Unresolved, rows.unresolved...)
+	result.SchemaProposal = proposal.render()
+	return result, nil
+}
+
+// openNotionExport returns the folder holding the export: source itself, or
+// a temporary folder the zip is extracted to. cleanup removes that folder.
+func openNotionExport(source string) (string, func(), error) {
+	noop := func() {}
+	sourceArg := strings.TrimSpace(source)
+	abs, err := filepath.Abs(sourceArg)
+	if err != nil || sourceArg == "" {
+		return "", noop, newError(CodeInvalidInput, "Notion export is required", err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", noop, newError(CodeInvalidInput, fmt.Sprintf("Notion export not found: %s", sourceArg), err)
+	}
+	if info.IsDir() {
+		return abs, noop, nil
+	}
+	if !strings.EqualFold(filepath.Ext(abs), ".zip") {
+		return "", noop, newError(CodeInvalidInput, fmt.Sprintf("Notion export must be a .zip file or a folder: %s", sourceArg), nil)
+	}
+
+	dir, err := os.MkdirTemp("", "rvn-notion-")
+	if err != nil {
+		return "", noop, newError(codes.ErrFileWrite, fmt.Sprintf("failed to create a folder for the export: %v", err), err)
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		cleanup()
+		return "", noop, newError(codes.ErrFileRead, fmt.Sprintf("failed to read %s: %v", sourceArg, err), err)
+	}
+	if err := extractNotionZip(data, dir, 0); err != nil {
+		cleanup()
+		return "", noop, newError(CodeInvalidInput, fmt.Sprintf("failed to read %s: %v", sourceArg, err), err)
+	}
+	return dir, cleanup, nil
+}
+
+// extractNotionZip writes the pages, databases, and nested zips of an export
+// zip under dir.
+func extractNotionZip(data []byte, dir string, depth int) error {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	for _, entry := range reader.File {
+		name := path.Clean(entry.Name)
+		if entry.FileInfo().IsDir() || !filepath.IsLocal(filepath.FromSlash(name)) {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(name))
+		if ext != ".md" && ext != ".csv" && ext != ".html" && ext != ".zip" {
+			continue
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			return err
+		}
+		content, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+		if ext == ".zip" {
+			if depth+1 < maxNotionZipDepth {
+				if err := extractNotionZip(content, dir, depth+1); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notionName strips the ID Notion appends to a page or database name.
+func notionName(name string) string {
+	return strings.TrimSpace(notionIDRE.ReplaceAllString(name, ""))
+}
+
+// notionRelID slugifies an export path without Notion's IDs.
+func notionRelID(source string) string {
+	parts := strings.Split(strings.TrimSuffix(source, path.Ext(source)), "/")
+	for i, part := range parts {
+		parts[i] = notionName(part)
+	}
+	return pages.SlugifyPath(strings.Join(parts, "/"))
+}
+
+// notionDatabase is one exported database: a CSV of its rows, whose pages
+// are in the folder of the same name.
+type notionDatabase struct {
+	name     string
+	source   string // CSV path in the export
+	folder   string // Folder of row pages
+	typeName string
+	header   []string
+	records  [][]string
+	columns  []*notionColumn // All columns but the title
+	rows     []*notionRow
+}
+
+// notionColumn is a database column and the field it becomes.
+type notionColumn struct {
+	index     int
+	name      string
+	key       string
+	fieldType schema.FieldType
+	target    string // Type of the rows a relation points at
+	ranged    bool   // Date ranges; the end goes to <key>_end
+}
+
+type notionRow struct {
+	db      *notionDatabase
+	record  []string // nil for pages missing from the CSV
+	source  string
+	id      string
+	title   string
+	renamed bool
+}
+
+// loadNotionDatabases reads every database CSV in the export. Notion writes
+// both Name.csv (the current view) and Name_all.csv (every row); the latter
+// is used when present.
+func loadNotionDatabases(dir string) ([]*notionDatabase, error) {
+	sources, err := collectSources(dir, folderLayout{ext: ".csv"})
+	if err != nil {
+		return nil, newError(codes.ErrFileRead, fmt.Sprintf("failed to read export: %v", err), err)
+	}
+	byFolder := make(map[string]string, len(sources))
+	for _, source := range sources {
+		folder := strings.TrimSuffix(source, path.Ext(source))
+		if trimmed := strings.TrimSuffix(folder, "_all"); trimmed != folder {
+			byFolder[trimmed] = source
+		} else if _, ok := byFolder[folder]; !ok {
+			byFolder[folder] = source
+		}
+	}
+
+	var databases []*notionDatabase
+	for _, folder := range sortedKeysOf(byFolder) {
+		source := byFolder[folder]
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(source)))
+		if err != nil {
+			return nil, newError(codes.ErrFileRead, fmt.Sprintf("failed to read %s: %v", source, err), err)
+		}
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, newError(CodeInvalidInput, fmt.Sprintf("invalid CSV in %s: %v", source, err), err)
+		}
+		if len(records) == 0 || len(records[0]) == 0 {
+			continue
+		}
+		name := notionName(path.Base(folder))
+		db := &notionDatabase{name: name, source: source, folder: folder, header: records[0]}
+		if typeName := typeNameForFolder(name); typeName != "" && !schema.IsBuiltinType(typeName) {
+			db.typeName = typeName
+		}
+		for _, record := range records[1:] {
+			if len(record) > 0 {
+				db.records = append(db.records, record)
+			}
+		}
+		databases = append(databases, db)
+	}
+	return databases, nil
+}
+
+// inferColumns gives each column a field key and a type that all of its
+// values fit.
+func (db *notionDatabase) inferColumns(idsByNotionID, typeByID map[string]string) {
+	used := map[string]bool{"type": true, "id": true, proposedNameField: true}
+	for i, name := range db.header {
+		if i == 0 {
+			continue
+		}
+		key := dataviewFieldKey(name)
+		if key == "" {
+			continue
+		}
+		if used[key] {
+			key = "notion_" + key
+		}
+		for n, base := 2, key; used[key]; n++ {
+			key = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[key] = true
+
+		var values []string
+		for _, record := range db.records {
+			if i < len(record) && strings.TrimSpace(record[i]) != "" {
+				values = append(values, strings.TrimSpace(record[i]))
+			}
+		}
+		col := &notionColumn{index: i, name: name, key: key}
+		col.infer(values, idsByNotionID, typeByID)
+		db.columns = append(db.columns, col)
+	}
+}
+
+func (col *notionColumn) infer(values []string, idsByNotionID, typeByID map[string]string) {
+	col.fieldType = schema.FieldTypeString
+	if len(values) == 0 {
+		return
+	}
+	all := func(fn func(string) bool) bool {
+		for _, value := range values {
+			if !fn(value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case all(func(v string) bool { return v == "Yes" || v == "No" }):
+		col.fieldType = schema.FieldTypeBool
+	case all(func(v string) bool { _, err := strconv.ParseFloat(v, 64); return err == nil }):
+		col.fieldType = schema.FieldTypeNumber
+	case all(func(v string) bool { _, _, ok := notionDateRange(v); return ok }):
+		col.fieldType = schema.FieldTypeDate
+		for _, value := range values {
+			start, end, _ := notionDateRange(value)
+			col.ranged = col.ranged || end != ""
+			if strings.Contains(start, "T") || strings.Contains(end, "T") {
+				col.fieldType = schema.FieldTypeDatetime
+			}
+		}
+	case all(func(v string) bool { _, ok := notionRelations(v); return ok }):
+		col.fieldType = schema.FieldTypeRef
+		targets := make(map[string]bool)
+		for _, value := range values {
+			relations, _ := notionRelations(value)
+			if len(relations) > 1 {
+				col.fieldType = schema.FieldTypeRefArray
+			}
+			for _, rel := range relations {
+				if id, ok := idsByNotionID[rel.notionID]; ok {
+					targets[typeByID[id]] = true
+				}
+			}
+		}
+		if len(targets) == 1 {
+			for target := range targets {
+				col.target = target
+			}
+		}
+	case all(func(v string) bool {
+		return (strings.HasPrefix(v, "https://") || strings.HasPrefix(v, "http://")) && !strings.ContainsAny(v, " \t")
+	}):
+		col.fieldType = schema.FieldTypeURL
+	}
+}
+
+// notionRelation is one related page in a relation value.
+type notionRelation struct {
+	title    string
+	notionID string
+}
+
+// notionRelations splits a relation value such as
+// "Freya Stark (People%20abc/Freya%20Stark%20def.md), Raven (...)" into its
+// pages. It reports false when the value is not entirely relations.
+func notionRelations(value string) ([]notionRelation, bool) {
+	matches := notionRelationRE.FindAllStringSubmatchIndex(value, -1)
+	if len(matches) == 0 {
+		return nil, false
+	}
+	relations := make([]notionRelation, 0, len(matches))
+	last := 0
+	for _, m := range matches {
+		if m[0] != last {
+			return nil, false
+		}
+		last = m[1]
+		relations = append(relations, notionRelation{title: value[m[2]:m[3]], notionID: value[m[6]:m[7]]})
+	}
+	return relations, last == len(value)
+}
+
+// notionDateRange parses a date or a "start → end" range, returning
+// YYYY-MM-DD dates, or YYYY-MM-DDTHH:MM when a time is given.
+func notionDateRange(value string) (string, string, bool) {
+	startText, endText, ranged := strings.Cut(value, "→")
+	start, ok := notionDate(startText)
+	if !ok {
+		return "", "", false
+	}
+	if !ranged {
+		return start, "", true
+	}
+	end, ok := notionDate(endText)
+	if !ok {
+		return "", "", false
+	}
+	return start, end, true
+}
+
+func notionDate(text string) (string, bool) {
+	text = strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(text), "@")), " ")
+	if date, ok := dates.CanonicalizeDate(text); ok {
+		return date, true
+	}
+	for _, layout := range notionDatetimeLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t.Format(dates.DatetimeLayout), true
+		}
+	}
+	return "", false
+}
+
+// stripNotionRowHeader removes the "# Title" heading and "Column: value"
+// lines Notion writes at the top of a row's page; the values come from the
+// CSV instead. It returns the rest and the 1-based line it starts on.
+func stripNotionRowHeader(content string, header []string) (string, int) {
+	columns := make(map[string]bool, len(header))
+	for _, name := range header {
+		columns[name] = true
+	}
+	lines := strings.Split(content, "\n")
+	i := 0
+	for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+		i++
+	}
+	if i < len(lines) && strings.HasPrefix(lines[i], "# ") {
+		i++
+	}
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		name, _, ok := strings.Cut(line, ":")
+		if !ok || !columns[name] {
+			break
+		}
+	}
+	return strings.Join(lines[i:], "\n"), i + 1
+}
+
+// notionRowWriter renders database rows.
+type notionRowWriter struct {
+	objectsRoot   string
+	pagesRoot     string
+	idsByNotionID map[string]string
+	proposal      *schemaProposal
+	unresolved    []UnresolvedLink
+}
+
+// render returns the row's page with its frontmatter: type, name, and one
+// field per non-empty column.
+func (w *notionRowWriter) render(row *notionRow, file *NotionImportFile, body string) (string, error) {
+	db := row.db
+	file.Database = db.name
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	if db.typeName != "" {
+		file.Type = db.typeName
+		file.File = paths.ObjectIDToFilePath(file.ID, db.typeName, w.objectsRoot, w.pagesRoot)
+		mapping.Content = append(mapping.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "type"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: db.typeName})
+		insertNameField(mapping, w.proposal.nameField(db.typeName), row.title)
+	}
+
+	add := func(key string, value *yaml.Node) {
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+		file.Fields = append(file.Fields, key)
+	}
+	for _, col := range db.columns {
+		if row.record == nil || col.index >= len(row.record) {
+			break
+		}
+		value := strings.TrimSpace(row.record[col.index])
+		if value == "" {
+			continue
+		}
+		switch col.fieldType {
+		case schema.FieldTypeBool:
+			add(col.key, &yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatBool(value == "Yes")})
+		case schema.FieldTypeNumber:
+			add(col.key, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+		case schema.FieldTypeDate, schema.FieldTypeDatetime:
+			start, end, _ := notionDateRange(value)
+			add(col.key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: notionDateValue(start, col.fieldType)})
+			if end != "" {
+				add(col.key+"_end", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: notionDateValue(end, col.fieldType)})
+			}
+		case schema.FieldTypeRef, schema.FieldTypeRefArray:
+			relations, _ := notionRelations(value)
+			refs := make([]*yaml.Node, 0, len(relations))
+			for _, rel := range relations {
+				ref := rel.title
+				if id, ok := w.idsByNotionID[rel.notionID]; ok {
+					ref = "[[" + id + "]]"
+					file.LinksConverted++
+				} else {
+					w.unresolved = append(w.unresolved, UnresolvedLink{Source: file.Source, Line: 1, Link: rel.title + " (" + col.name + ")", Reason: UnresolvedTargetNotFound})
+				}
+				refs = append(refs, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Style: yaml.DoubleQuotedStyle, Value: ref})
+			}
+			if col.fieldType == schema.FieldTypeRef {
+				add(col.key, refs[0])
+			} else {
+				add(col.key, &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle, Content: refs})
+			}
+		default:
+			add(col.key, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+		}
+	}
+	return withFrontmatter(mapping, strings.TrimLeft(body, "\n"), file.Source)
+}
+
+// notionDateValue widens a date to a datetime for datetime columns.
+func notionDateValue(value string, fieldType schema.FieldType) string {
+	if fieldType == schema.FieldTypeDatetime && !strings.Contains(value, "T") {
+		return value + "T00:00"
+	}
+	return value
+}
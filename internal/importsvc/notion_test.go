@@ -0,0 +1,122 @@
+package importsvc
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestImportNotionTypesDatabaseRows(t *testing.T) {
+	t.Parallel()
+
+	home, projects, raven := strings.Repeat("1", 32), strings.Repeat("2", 32), strings.Repeat("3", 32)
+	people, freya := strings.Repeat("4", 32), strings.Repeat("5", 32)
+	projectsCSV := "Name,Status,Owner,Due,Done,Budget\n" +
+		"Raven,Active,Freya Stark (../People%20" + people + "/Freya%20Stark%20" + freya + ".md),\"March 1, 2026 → March 3, 2026\",No,1200\n" +
+		"Wren,Planned,,\"April 2, 2026\",Yes,\n"
+
+	v := testutil.NewTestVault(t).Build()
+	export := filepath.Join(t.TempDir(), "Export.zip")
+	writeZip(t, export, map[string]string{
+		"Home " + home + ".md":                                "# Home\n\nSee [Raven](Home%20" + home + "/Projects%20" + projects + "/Raven%20" + raven + ".md).\n",
+		"Home " + home + "/Projects " + projects + ".csv":     "Name,Status\nRaven,Active\n",
+		"Home " + home + "/Projects " + projects + "_all.csv": "\xef\xbb\xbf" + projectsCSV,
+		"Home " + home + "/Projects " + projects + "/Raven " + raven + ".md": "# Raven\n\n" +
+			"Status: Active\n" +
+			"Owner: Freya Stark (../People%20" + people + "/Freya%20Stark%20" + freya + ".md)\n\n" +
+			"Kickoff with [Freya](../People%20" + people + "/Freya%20Stark%20" + freya + ".md) and [the board](Board.md).\n",
+		"Home " + home + "/People " + people + ".csv":                          "Name,Role\nFreya Stark,Lead\n",
+		"Home " + home + "/People " + people + "/Freya Stark " + freya + ".md": "# Freya Stark\n\nRole: Lead\n",
+		"Home " + home + "/image.png":                                          "png",
+	})
+
+	result, err := ImportNotion(NotionImportRequest{
+		VaultPath:   v.Path,
+		VaultConfig: config.DefaultVaultConfig(),
+		Source:      export,
+	})
+	if err != nil {
+		t.Fatalf("ImportNotion: %v", err)
+	}
+	if len(result.Files) != 4 {
+		t.Fatalf("files = %+v, want 4 (3 pages and the Wren row)", result.Files)
+	}
+
+	if got := v.ReadFile("notion/home.md"); got != "# Home\n\nSee [[notion/home/projects/raven|Raven]].\n" {
+		t.Fatalf("home.md = %q", got)
+	}
+	wantRaven := "---\n" +
+		"type: project\n" +
+		"name: Raven\n" +
+		"status: Active\n" +
+		"owner: \"[[notion/home/people/freya-stark]]\"\n" +
+		"due: \"2026-03-01\"\n" +
+		"due_end: \"2026-03-03\"\n" +
+		"done: false\n" +
+		"budget: 1200\n" +
+		"---\n" +
+		"Kickoff with [[notion/home/people/freya-stark|Freya]] and [the board](Board.md).\n"
+	if got := v.ReadFile("notion/home/projects/raven.md"); got != wantRaven {
+		t.Fatalf("raven.md = %q\nwant %q", got, wantRaven)
+	}
+	if got := v.ReadFile("notion/home/projects/wren.md"); !strings.Contains(got, "done: true\n") || strings.Contains(got, "owner") {
+		t.Fatalf("wren.md = %q, want a row from the CSV alone", got)
+	}
+	if len(result.Unresolved) != 1 || result.Unresolved[0].Line != 6 || result.Unresolved[0].Link != "Board.md" {
+		t.Fatalf("unresolved = %+v, want Board.md on line 6", result.Unresolved)
+	}
+
+	for _, want := range []string{
+		"  person:\n    default_path: notion/home/people/\n",
+		"      owner:\n        type: ref\n        target: person\n",
+		"      due_end:\n        type: date\n",
+		"      budget:\n        type: number\n",
+		"      done:\n        type: bool\n",
+	} {
+		if !strings.Contains(result.SchemaProposal, want) {
+			t.Errorf("schema proposal missing %q:\n%s", want, result.SchemaProposal)
+		}
+	}
+}
+
+func TestImportNotionRejectsHTMLExports(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).Build()
+	export := filepath.Join(t.TempDir(), "Export.zip")
+	writeZip(t, export, map[string]string{"Home " + strings.Repeat("1", 32) + ".html": "<html></html>"})
+
+	_, err := ImportNotion(NotionImportRequest{VaultPath: v.Path, VaultConfig: config.DefaultVaultConfig(), Source: export})
+	if err == nil || !strings.Contains(err.Error(), "Markdown & CSV") {
+		t.Fatalf("err = %v, want a hint to export as Markdown & CSV", err)
+	}
+}
+
+func writeZip(t *testing.T, target string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(target)
+	if err != nil {
+		t.Fatalf("create zip: %v", err)
+	}
+	w := zip.NewWriter(f)
+	for _, name := range sortedKeysOf(files) {
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("zip entry %s: %v", name, err)
+		}
+		if _, err := entry.Write([]byte(files[name])); err != nil {
+			t.Fatalf("zip write %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close file: %v", err)
+	}
+}
@@ -0,0 +1,887 @@
+package importsvc
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/pages"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/slugs"
+	"github.com/aidanlsb/raven/internal/wikilink"
+)
+
+// UnresolvedAmbiguousName is reported for wikilinks whose note name matches
+// several imported files.
+const UnresolvedAmbiguousName = "note name matches more than one imported file"
+
+// proposedNameField is the name field of proposed types. Imported notes get
+// their file name as its value.
+const proposedNameField = "name"
+
+// minTypeFolderFiles is how many notes a top-level folder needs before its
+// name is proposed as a type.
+const minTypeFolderFiles = 2
+
+type ObsidianImportRequest struct {
+	VaultPath   string
+	VaultConfig *config.VaultConfig
+	// Schema is the vault's current schema; types and traits it already
+	// defines are left out of the proposal. May be nil.
+	Schema    *schema.Schema
+	SourceDir string
+	// Destination is the vault directory (under the pages root) to import into.
+	// Defaults to the slug of the source folder name.
+	Destination string
+	// TagTrait is the trait YAML tags are converted to. Defaults to the
+	// vault's hashtags trait.
+	TagTrait string
+	DryRun   bool
+}
+
+type ObsidianImportFile struct {
+	Source         string   `json:"source"`
+	ID             string   `json:"id"`
+	File           string   `json:"file"`
+	Type           string   `json:"type,omitempty"`
+	Renamed        bool     `json:"renamed,omitempty"`
+	LinksConverted int      `json:"links_converted"`
+	Tags           []string `json:"tags,omitempty"`
+	Fields         []string `json:"fields,omitempty"` // Dataview inline fields moved to frontmatter
+}
+
+// ProposedType is a type inferred from a top-level folder of the import.
+type ProposedType struct {
+	Name        string `json:"name"`
+	Folder      string `json:"folder"`
+	DefaultPath string `json:"default_path"`
+	Files       int    `json:"files"`
+	Exists      bool   `json:"exists,omitempty"` // Already defined in schema.yaml
+}
+
+type ObsidianImportResult struct {
+	Destination string
+	TagTrait    string
+	Files       []ObsidianImportFile
+	Types       []ProposedType
+	Unresolved  []UnresolvedLink
+	// SchemaProposal is schema.yaml content declaring the inferred types, their
+	// frontmatter fields, and the tag trait. It is not applied.
+	SchemaProposal   string
+	ChangedFilePaths []string
+}
+
+var (
+	// dataviewLineRE matches a line that is one dataview field, such as
+	// "Status:: active" or "- due:: 2026-03-01".
+	dataviewLineRE = regexp.MustCompile(`^\s*(?:[-*+]\s+)?([\p{L}][\p{L}\p{N} _-]*?)::\s*(.*?)\s*$`)
+	// dataviewInlineRE matches bracketed inline fields: [key:: value] or (key:: value).
+	// Values may contain wikilinks.
+	dataviewInlineRE = regexp.MustCompile(`[\[(]([\p{L}][\p{L}\p{N} _-]*?)::\s*((?:\[\[[^\]]*\]\]|[^\])\[])*?)\s*[\])]`)
+	fieldKeyCleanRE  = regexp.MustCompile(`[^a-z0-9_]+`)
+	isoDateRE        = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// ImportObsidian copies an Obsidian vault into the vault, converting its
+// conventions: wikilinks by note name become refs to the new IDs, YAML tags
+// become trait annotations, dataview inline fields move into frontmatter, and
+// aliases become Raven's alias field. Notes in top-level folders are typed by
+// folder, and a schema.yaml proposal for those types is returned.
+func ImportObsidian(req ObsidianImportRequest) (*ObsidianImportResult, error) {
+	plan, err := planFolderImport(req.VaultPath, req.VaultConfig, req.SourceDir, req.Destination, folderLayout{})
+	if err != nil {
+		return nil, err
+	}
+
+	tagTrait := importTagTrait(req.TagTrait, req.VaultConfig)
+	result := &ObsidianImportResult{Destination: plan.Destination, TagTrait: tagTrait}
+	result.Types = proposeFolderTypes(plan, req.Schema)
+	typeByFolder := make(map[string]string, len(result.Types))
+	for _, proposed := range result.Types {
+		typeByFolder[proposed.Folder] = proposed.Name
+	}
+
+	links := newObsidianLinkIndex(plan.IDsBySource)
+	proposal := newSchemaProposal(req.Schema)
+	proposal.addTrait(tagTrait, schema.FieldTypeString)
+	for _, proposed := range result.Types {
+		proposal.addType(proposed.Name, proposed.DefaultPath)
+	}
+
+	for _, planned := range plan.Files {
+		file := ObsidianImportFile{Source: planned.Source, ID: planned.ID, File: planned.File, Renamed: planned.Renamed}
+		content, err := os.ReadFile(filepath.Join(plan.SourceDir, filepath.FromSlash(file.Source)))
+		if err != nil {
+			return nil, newError(codes.ErrFileRead, fmt.Sprintf("failed to read %s: %v", file.Source, err), err)
+		}
+
+		note, err := convertObsidianNote(string(content), &file, links, plan.IDsBySource, tagTrait, typeByFolder[topFolder(file.Source)], proposal.nameField)
+		if err != nil {
+			return nil, err
+		}
+		result.Unresolved = append(result.Unresolved, note.unresolved...)
+		if file.Type != "" {
+			// Typed notes live under the type root, untyped ones under the pages root.
+			file.File = paths.ObjectIDToFilePath(file.ID, file.Type, req.VaultConfig.GetObjectsRoot(), req.VaultConfig.GetPagesRoot())
+			proposal.addType(file.Type, "")
+			proposal.addFields(file.Type, note.frontmatter)
+		}
+		if !req.DryRun {
+			target, err := writeImportedFile(plan.VaultPath, file.File, note.content)
+			if err != nil {
+				return nil, err
+			}
+			result.ChangedFilePaths = append(result.ChangedFilePaths, target)
+		}
+		result.Files = append(result.Files, file)
+	}
+
+	result.SchemaProposal = proposal.render()
+	return result, nil
+}
+
+// importTagTrait returns the trait tags are imported as: the requested one,
+// else the vault's hashtags trait, else tag.
+func importTagTrait(requested string, vaultCfg *config.VaultConfig) string {
+	if trait := strings.TrimSpace(requested); trait != "" {
+		return trait
+	}
+	if configured := vaultCfg.GetHashtagTrait(); configured != "" {
+		return configured
+	}
+	return config.DefaultHashtagTrait
+}
+
+// topFolder returns the first directory of a source path, or "".
+func topFolder(source string) string {
+	if folder, _, ok := strings.Cut(source, "/"); ok {
+		return folder
+	}
+	return ""
+}
+
+// proposeFolderTypes names a type after each top-level folder holding enough
+// notes. Folders of dated notes and the usual Obsidian support folders are
+// left untyped.
+func proposeFolderTypes(plan *folderImportPlan, sch *schema.Schema) []ProposedType {
+	counts := make(map[string]int)
+	dated := make(map[string]int)
+	for _, file := range plan.Files {
+		folder := topFolder(file.Source)
+		if folder == "" {
+			continue
+		}
+		counts[folder]++
+		if isoDateRE.MatchString(strings.TrimSuffix(path.Base(file.Source), path.Ext(file.Source))) {
+			dated[folder]++
+		}
+	}
+
+	var types []ProposedType
+	for _, folder := range sortedKeysOf(counts) {
+		count := counts[folder]
+		if count < minTypeFolderFiles || dated[folder]*2 > count {
+			continue
+		}
+		switch strings.ToLower(folder) {
+		case "templates", "attachments", "assets", "files", "images", "archive", "inbox":
+			continue
+		}
+		name := typeNameForFolder(folder)
+		if name == "" || schema.IsBuiltinType(name) {
+			continue
+		}
+		proposed := ProposedType{
+			Name:        name,
+			Folder:      folder,
+			DefaultPath: path.Join(plan.Destination, pages.Slugify(folder)) + "/",
+			Files:       count,
+		}
+		if sch != nil && sch.Types[name] != nil {
+			proposed.Exists = true
+		}
+		types = append(types, proposed)
+	}
+	return types
+}
+
+// typeNameForFolder turns a folder name such as "People" or "Meeting Notes"
+// into a singular snake_case type name ("person", "meeting_note").
+func typeNameForFolder(folder string) string {
+	name := strings.Trim(fieldKeyCleanRE.ReplaceAllString(strings.ToLower(folder), "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return ""
+	}
+	head, last := "", name
+	if idx := strings.LastIndex(name, "_"); idx >= 0 {
+		head, last = name[:idx+1], name[idx+1:]
+	}
+	switch {
+	case last == "people":
+		last = "person"
+	case strings.HasSuffix(last, "ies") && len(last) > 3:
+		last = strings.TrimSuffix(last, "ies") + "y"
+	case strings.HasSuffix(last, "sses"), strings.HasSuffix(last, "xes"), strings.HasSuffix(last, "ches"), strings.HasSuffix(last, "shes"):
+		last = strings.TrimSuffix(last, "es")
+	case strings.HasSuffix(last, "s") && !strings.HasSuffix(last, "ss") && len(last) > 1:
+		last = strings.TrimSuffix(last, "s")
+	}
+	return head + last
+}
+
+// obsidianLinkIndex resolves Obsidian link targets, which name a note by its
+// file name or by a path relative to the vault root.
+type obsidianLinkIndex struct {
+	byPath map[string]string
+	byName map[string][]string
+}
+
+func newObsidianLinkIndex(idsBySource map[string]string) *obsidianLinkIndex {
+	idx := &obsidianLinkIndex{byPath: make(map[string]string), byName: make(map[string][]string)}
+	for _, source := range sortedKeysOf(idsBySource) {
+		key := strings.ToLower(strings.TrimSuffix(source, path.Ext(source)))
+		idx.byPath[key] = idsBySource[source]
+		name := path.Base(key)
+		idx.byName[name] = append(idx.byName[name], idsBySource[source])
+	}
+	return idx
+}
+
+// resolve maps a link target (without fragment) to an imported ID, or
+// returns a reason it cannot.
+func (idx *obsidianLinkIndex) resolve(target string) (string, string) {
+	key := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(target), "/"))
+	if ext := path.Ext(key); ext != "" {
+		if ext != ".md" {
+			if _, ok := idx.byPath[key]; !ok && len(idx.byName[path.Base(key)]) == 0 {
+				return "", UnresolvedNotMarkdownFile
+			}
+		} else {
+			key = strings.TrimSuffix(key, ext)
+		}
+	}
+	if id, ok := idx.byPath[key]; ok {
+		return id, ""
+	}
+	if strings.Contains(key, "/") {
+		var match string
+		for candidate, id := range idx.byPath {
+			if strings.HasSuffix(candidate, "/"+key) {
+				if match != "" {
+					return "", UnresolvedAmbiguousName
+				}
+				match = id
+			}
+		}
+		if match != "" {
+			return match, ""
+		}
+		return "", UnresolvedTargetNotFound
+	}
+	switch ids := idx.byName[key]; len(ids) {
+	case 0:
+		return "", UnresolvedTargetNotFound
+	case 1:
+		return ids[0], ""
+	default:
+		return "", UnresolvedAmbiguousName
+	}
+}
+
+// convertedNote is one Obsidian note rewritten for Raven.
+type convertedNote struct {
+	content     string
+	frontmatter *yaml.Node // Final frontmatter mapping, nil when there is none
+	unresolved  []UnresolvedLink
+}
+
+// convertObsidianNote rewrites one note. folderType, when set, types notes
+// without a type in frontmatter, and nameField names the field that holds a
+// typed note's title.
+func convertObsidianNote(content string, file *ObsidianImportFile, links *obsidianLinkIndex, idsBySource map[string]string, tagTrait, folderType string, nameField func(typeName string) string) (*convertedNote, error) {
+	note := &convertedNote{}
+	fmText, body, bodyLine := splitFrontmatter(content)
+
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	if strings.TrimSpace(fmText) != "" {
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(fmText), &doc); err != nil {
+			return nil, newError(CodeInvalidInput, fmt.Sprintf("invalid frontmatter in %s: %v", file.Source, err), err)
+		}
+		if len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+			mapping = doc.Content[0]
+		}
+	}
+
+	convertLinks := func(text string, line int) string {
+		converted, count, unresolved := convertObsidianLinks(text, file.Source, line, links)
+		file.LinksConverted += count
+		note.unresolved = append(note.unresolved, unresolved...)
+		return converted
+	}
+
+	// Frontmatter: tags become traits, aliases become alias, and links in
+	// properties are rewritten like links in the body.
+	file.Tags = takeTags(mapping)
+	convertAliases(mapping)
+	walkScalars(mapping, func(n *yaml.Node) {
+		if strings.Contains(n.Value, "[[") {
+			n.Value = convertLinks(n.Value, 1)
+			n.Style = yaml.DoubleQuotedStyle
+		}
+	})
+
+	// Body: dataview fields move to frontmatter; links are rewritten.
+	lines := strings.Split(body, "\n")
+	var kept []string
+	fence := ""
+	for i, line := range lines {
+		lineNum := bodyLine + i
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			kept = append(kept, line)
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			kept = append(kept, line)
+			continue
+		}
+
+		if m := dataviewLineRE.FindStringSubmatch(line); m != nil && !strings.Contains(line, "`") {
+			if key := dataviewFieldKey(m[1]); key != "" {
+				addDataviewField(mapping, &file.Fields, key, convertLinks(m[2], lineNum))
+				continue
+			}
+		}
+		line = dataviewInlineRE.ReplaceAllStringFunc(line, func(match string) string {
+			parts := dataviewInlineRE.FindStringSubmatch(match)
+			key := dataviewFieldKey(parts[1])
+			if key == "" {
+				return match
+			}
+			// The value stays in the line, where its links are converted
+			// and counted below.
+			value, _, _ := convertObsidianLinks(parts[2], file.Source, lineNum, links)
+			addDataviewField(mapping, &file.Fields, key, value)
+			return parts[2]
+		})
+		kept = append(kept, convertLinks(line, lineNum))
+	}
+	body = strings.Join(kept, "\n")
+	body, mdCount, mdUnresolved := convertMarkdownLinks(body, file.Source, idsBySource)
+	file.LinksConverted += mdCount
+	for _, link := range mdUnresolved {
+		link.Line += bodyLine - 1
+		note.unresolved = append(note.unresolved, link)
+	}
+
+	if typeNode := mappingValue(mapping, "type"); typeNode != nil {
+		file.Type = typeNode.Value
+	} else if folderType != "" {
+		file.Type = folderType
+		mapping.Content = append([]*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "type"},
+			{Kind: yaml.ScalarNode, Value: folderType},
+		}, mapping.Content...)
+	}
+	insertNameField(mapping, nameField(file.Type), strings.TrimSuffix(path.Base(file.Source), path.Ext(file.Source)))
+
+	if len(file.Tags) > 0 {
+		annotations := make([]string, len(file.Tags))
+		for i, tag := range file.Tags {
+			annotations[i] = "@" + tagTrait + "(" + tag + ")"
+		}
+		body = strings.Join(annotations, " ") + "\n\n" + strings.TrimLeft(body, "\n")
+	}
+
+	if len(mapping.Content) > 0 {
+		note.frontmatter = mapping
+	}
+	content, err := withFrontmatter(mapping, body, file.Source)
+	if err != nil {
+		return nil, err
+	}
+	note.content = content
+	return note, nil
+}
+
+// insertNameField sets a typed note's name field to its title, right after
+// type, unless the note already has it. field may be empty.
+func insertNameField(mapping *yaml.Node, field, title string) {
+	if field == "" || mappingValue(mapping, field) != nil {
+		return
+	}
+	at := 0
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == "type" {
+			at = i + 2
+		}
+	}
+	mapping.Content = append(mapping.Content[:at:at], append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: field},
+		{Kind: yaml.ScalarNode, Value: title},
+	}, mapping.Content[at:]...)...)
+}
+
+// withFrontmatter prepends mapping to body as YAML frontmatter. An empty
+// mapping leaves body as is.
+func withFrontmatter(mapping *yaml.Node, body, source string) (string, error) {
+	if len(mapping.Content) == 0 {
+		return body, nil
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(mapping); err != nil {
+		return "", newError(CodeInvalidInput, fmt.Sprintf("failed to write frontmatter for %s: %v", source, err), err)
+	}
+	return "---\n" + buf.String() + "---\n" + body, nil
+}
+
+// splitFrontmatter separates YAML frontmatter from the body and returns the
+// 1-based line the body starts on.
+func splitFrontmatter(content string) (string, string, int) {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return "", normalized, 1
+	}
+	lines := strings.Split(normalized, "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return strings.Join(lines[1:i], "\n"), strings.Join(lines[i+1:], "\n"), i + 2
+		}
+	}
+	return "", normalized, 1
+}
+
+// convertObsidianLinks rewrites [[Note Name#Heading|text]] links and embeds
+// to refs to imported IDs. Links inside inline code are left alone.
+func convertObsidianLinks(line, source string, lineNum int, links *obsidianLinkIndex) (string, int, []UnresolvedLink) {
+	matches := wikilink.FindAllInLine(line, false)
+	if len(matches) == 0 {
+		return line, 0, nil
+	}
+	code := inlineCodeRanges(line)
+
+	converted := 0
+	var unresolved []UnresolvedLink
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if inRanges(m.Start, code) {
+			continue
+		}
+		target, fragment, _ := strings.Cut(m.Target, "#")
+		if strings.TrimSpace(target) == "" {
+			continue // Same-note heading link
+		}
+		id, reason := links.resolve(target)
+		if reason != "" {
+			unresolved = append(unresolved, UnresolvedLink{Source: source, Line: lineNum, Link: m.Literal, Reason: reason})
+			continue
+		}
+
+		// Obsidian shows a heading link as "Note > Heading". Block links
+		// (#^id) have no Raven equivalent and point at the note.
+		ref, display := id, strings.TrimSpace(target)
+		if fragment = strings.TrimSpace(fragment); fragment != "" && !strings.HasPrefix(fragment, "^") {
+			if idx := strings.LastIndex(fragment, "#"); idx >= 0 {
+				fragment = fragment[idx+1:]
+			}
+			ref += "#" + slugs.HeadingSlug(fragment)
+			display += " > " + fragment
+		}
+		embed := m.Start > 0 && line[m.Start-1] == '!'
+		switch {
+		case m.DisplayText != nil && *m.DisplayText != "":
+			ref += "|" + *m.DisplayText
+		case !embed && display != ref:
+			ref += "|" + display
+		}
+		b.WriteString(line[last:m.Start])
+		b.WriteString("[[" + ref + "]]")
+		last = m.End
+		converted++
+	}
+	b.WriteString(line[last:])
+	return b.String(), converted, unresolved
+}
+
+// inlineCodeRanges returns the byte ranges of `code` spans in a line.
+func inlineCodeRanges(line string) [][2]int {
+	var ranges [][2]int
+	for start := strings.Index(line, "`"); start >= 0; {
+		end := strings.Index(line[start+1:], "`")
+		if end < 0 {
+			break
+		}
+		end += start + 1
+		ranges = append(ranges, [2]int{start, end})
+		next := strings.Index(line[end+1:], "`")
+		if next < 0 {
+			break
+		}
+		start = end + 1 + next
+	}
+	return ranges
+}
+
+func inRanges(pos int, ranges [][2]int) bool {
+	for _, r := range ranges {
+		if pos > r[0] && pos < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func dataviewFieldKey(raw string) string {
+	key := strings.Trim(fieldKeyCleanRE.ReplaceAllString(strings.ToLower(strings.TrimSpace(raw)), "_"), "_")
+	if key == "" || key == "id" {
+		return ""
+	}
+	return key
+}
+
+// addDataviewField records an inline field in frontmatter. Existing
+// frontmatter keys win; a field repeated in the body becomes a list. added
+// tracks the keys set this way.
+func addDataviewField(mapping *yaml.Node, added *[]string, key, value string) {
+	valueNode := &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	if strings.Contains(value, "[[") {
+		valueNode.Style = yaml.DoubleQuotedStyle
+	}
+	for _, existing := range *added {
+		if existing != key {
+			continue
+		}
+		current := mappingValue(mapping, key)
+		if current.Kind != yaml.SequenceNode {
+			*current = yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{{Kind: current.Kind, Style: current.Style, Tag: current.Tag, Value: current.Value}}}
+		}
+		current.Content = append(current.Content, valueNode)
+		return
+	}
+	if mappingValue(mapping, key) != nil {
+		return
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, valueNode)
+	*added = append(*added, key)
+}
+
+// takeTags removes tags (or tag) from frontmatter and returns them without
+// their leading '#'. Obsidian accepts a list or a comma- or space-separated
+// string.
+func takeTags(mapping *yaml.Node) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, key := range []string{"tags", "tag"} {
+		node := removeMappingKey(mapping, key)
+		if node == nil {
+			continue
+		}
+		var raw []string
+		switch node.Kind {
+		case yaml.SequenceNode:
+			for _, item := range node.Content {
+				raw = append(raw, item.Value)
+			}
+		case yaml.ScalarNode:
+			raw = strings.FieldsFunc(node.Value, func(r rune) bool { return r == ',' || r == ' ' })
+		}
+		for _, tag := range raw {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+			if tag != "" && !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// convertAliases sets Raven's single alias field from Obsidian's aliases.
+// The full aliases list is kept so no alias is lost.
+func convertAliases(mapping *yaml.Node) {
+	if alias := mappingValue(mapping, "alias"); alias != nil && alias.Kind == yaml.SequenceNode {
+		list := removeMappingKey(mapping, "alias")
+		if mappingValue(mapping, "aliases") == nil {
+			mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "aliases"}, list)
+		}
+	}
+	if mappingValue(mapping, "alias") != nil {
+		return
+	}
+	aliases := mappingValue(mapping, "aliases")
+	if aliases == nil {
+		return
+	}
+	first := aliases
+	if aliases.Kind == yaml.SequenceNode {
+		if len(aliases.Content) == 0 {
+			return
+		}
+		first = aliases.Content[0]
+	}
+	if first.Kind != yaml.ScalarNode || strings.TrimSpace(first.Value) == "" {
+		return
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "alias"}, &yaml.Node{Kind: yaml.ScalarNode, Value: first.Value})
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func removeMappingKey(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value := mapping.Content[i+1]
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return value
+		}
+	}
+	return nil
+}
+
+func walkScalars(node *yaml.Node, fn func(*yaml.Node)) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		fn(node)
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			walkScalars(node.Content[i], fn)
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			walkScalars(child, fn)
+		}
+	}
+}
+
+// schemaProposal accumulates the types, fields, and traits an import needs
+// beyond what the vault's schema already defines.
+type schemaProposal struct {
+	schema *schema.Schema
+	types  map[string]*proposedTypeDef
+	traits map[string]schema.FieldType
+}
+
+type proposedTypeDef struct {
+	exists      bool // Defined in schema.yaml; only missing fields are proposed
+	defaultPath string
+	nameField   string
+	fields      map[string]string // field -> inferred type
+	targets     map[string]string // ref field -> target type
+}
+
+func newSchemaProposal(sch *schema.Schema) *schemaProposal {
+	return &schemaProposal{schema: sch, types: make(map[string]*proposedTypeDef), traits: make(map[string]schema.FieldType)}
+}
+
+// addType records a type notes are imported as. New types get defaultPath
+// and a name field.
+func (p *schemaProposal) addType(name, defaultPath string) {
+	if name == "" || schema.IsBuiltinType(name) || p.types[name] != nil {
+		return
+	}
+	def := &proposedTypeDef{fields: make(map[string]string), targets: make(map[string]string)}
+	if existing := p.existingType(name); existing != nil {
+		def.exists = true
+		def.nameField = existing.NameField
+	} else {
+		def.defaultPath = defaultPath
+		def.nameField = proposedNameField
+	}
+	p.types[name] = def
+}
+
+// addTrait records a trait the import uses, unless the schema defines it.
+func (p *schemaProposal) addTrait(name string, fieldType schema.FieldType) {
+	if name == "" || p.traits[name] != "" || (p.schema != nil && p.schema.Traits[name] != nil) {
+		return
+	}
+	p.traits[name] = fieldType
+}
+
+func (p *schemaProposal) existingType(name string) *schema.TypeDefinition {
+	if p.schema == nil {
+		return nil
+	}
+	return p.schema.Types[name]
+}
+
+// nameField returns the field that holds a note's title for typeName, or "".
+func (p *schemaProposal) nameField(typeName string) string {
+	if def := p.types[typeName]; def != nil {
+		return def.nameField
+	}
+	return ""
+}
+
+// addFields merges the frontmatter fields of one note of typeName into the
+// proposal.
+func (p *schemaProposal) addFields(typeName string, mapping *yaml.Node) {
+	if mapping == nil {
+		return
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		p.addField(typeName, mapping.Content[i].Value, inferFieldType(mapping.Content[i+1]), "")
+	}
+}
+
+// addField merges one field of typeName into the proposal. Fields whose
+// values disagree on a type fall back to string; a ref keeps its target only
+// while every value points at that type.
+func (p *schemaProposal) addField(typeName, key, fieldType, target string) {
+	def := p.types[typeName]
+	if def == nil {
+		return
+	}
+	switch key {
+	case "type", "id", "alias", def.nameField:
+		return
+	}
+	if existing := p.existingType(typeName); existing != nil && existing.Fields[key] != nil {
+		return
+	}
+	current, seen := def.fields[key]
+	switch {
+	case !seen:
+		def.fields[key] = fieldType
+		if target != "" {
+			def.targets[key] = target
+		}
+	case current != fieldType:
+		delete(def.targets, key)
+		if strings.HasSuffix(current, "[]") || strings.HasSuffix(fieldType, "[]") {
+			def.fields[key] = string(schema.FieldTypeStringArray)
+		} else {
+			def.fields[key] = string(schema.FieldTypeString)
+		}
+	case def.targets[key] != target:
+		delete(def.targets, key)
+	}
+}
+
+func inferFieldType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		item := ""
+		for i, child := range node.Content {
+			childType := inferFieldType(child)
+			if i == 0 {
+				item = childType
+			} else if childType != item {
+				item = string(schema.FieldTypeString)
+			}
+		}
+		if item == "" || strings.HasSuffix(item, "[]") || item == string(schema.FieldTypeObject) {
+			item = string(schema.FieldTypeString)
+		}
+		return item + "[]"
+	case yaml.MappingNode:
+		return string(schema.FieldTypeObject)
+	}
+	value := strings.TrimSpace(node.Value)
+	switch {
+	case node.Tag == "!!bool":
+		return string(schema.FieldTypeBool)
+	case node.Tag == "!!int" || node.Tag == "!!float":
+		return string(schema.FieldTypeNumber)
+	case isoDateRE.MatchString(value):
+		return string(schema.FieldTypeDate)
+	case strings.HasPrefix(value, "[[") && strings.HasSuffix(value, "]]") && strings.Count(value, "[[") == 1:
+		return string(schema.FieldTypeRef)
+	default:
+		return string(schema.FieldTypeString)
+	}
+}
+
+// render returns the proposal as schema.yaml content, or "" when there is
+// nothing to add.
+func (p *schemaProposal) render() string {
+
+	type fieldDef struct {
+		Type     string `yaml:"type"`
+		Target   string `yaml:"target,omitempty"`
+		Required bool   `yaml:"required,omitempty"`
+	}
+	type typeDef struct {
+		DefaultPath string              `yaml:"default_path,omitempty"`
+		NameField   string              `yaml:"name_field,omitempty"`
+		Fields      map[string]fieldDef `yaml:"fields,omitempty"`
+	}
+	type traitDef struct {
+		Type string `yaml:"type"`
+	}
+	out := struct {
+		Version int                 `yaml:"version"`
+		Types   map[string]typeDef  `yaml:"types,omitempty"`
+		Traits  map[string]traitDef `yaml:"traits,omitempty"`
+	}{Version: schema.CurrentSchemaVersion}
+
+	for name, def := range p.types {
+		if def.exists && len(def.fields) == 0 {
+			continue
+		}
+		td := typeDef{Fields: make(map[string]fieldDef, len(def.fields)+1)}
+		if !def.exists {
+			td.DefaultPath = def.defaultPath
+			td.NameField = def.nameField
+			td.Fields[def.nameField] = fieldDef{Type: string(schema.FieldTypeString), Required: true}
+		}
+		for field, fieldType := range def.fields {
+			td.Fields[field] = fieldDef{Type: fieldType, Target: def.targets[field]}
+		}
+		if out.Types == nil {
+			out.Types = make(map[string]typeDef)
+		}
+		out.Types[name] = td
+	}
+	if out.Types == nil && len(p.traits) == 0 {
+		return ""
+	}
+	if len(p.traits) > 0 {
+		out.Traits = make(map[string]traitDef, len(p.traits))
+		for name, fieldType := range p.traits {
+			out.Traits[name] = traitDef{Type: string(fieldType)}
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(out); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+func sortedKeysOf[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,123 @@
+package importsvc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestImportObsidianConvertsConventions(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).Build()
+	source := t.TempDir()
+	writeSourceFile(t, source, ".obsidian/app.json", "{}\n")
+	writeSourceFile(t, source, "People/Freya Stark.md", "---\n"+
+		"aliases: [Freya, FS]\n"+
+		"tags: [person, team/core]\n"+
+		"---\n"+
+		"role:: Lead engineer\n"+
+		"Works on [[Raven Project|Raven]] and [[Raven Project#Next Steps]].\n")
+	writeSourceFile(t, source, "People/Bob Li.md", "tags: not frontmatter\n")
+	writeSourceFile(t, source, "Projects/Raven Project.md", "---\nstarted: 2026-01-05\n---\n"+
+		"Owner [owner:: [[Freya Stark]]] and ![[diagram.png]].\n"+
+		"```\n[[Bob Li]]\nkey:: not a field\n```\n")
+	writeSourceFile(t, source, "Home.md", "See [[Freya Stark#^block]] and [[Nowhere]].\n")
+
+	result, err := ImportObsidian(ObsidianImportRequest{
+		VaultPath:   v.Path,
+		VaultConfig: config.DefaultVaultConfig(),
+		SourceDir:   source,
+		Destination: "obsidian",
+	})
+	if err != nil {
+		t.Fatalf("ImportObsidian: %v", err)
+	}
+	if len(result.Files) != 4 {
+		t.Fatalf("files = %+v, want 4 (.obsidian skipped)", result.Files)
+	}
+	if len(result.Types) != 1 || result.Types[0].Name != "person" || result.Types[0].DefaultPath != "obsidian/people/" {
+		t.Fatalf("types = %+v, want only person from People/", result.Types)
+	}
+
+	wantFreya := "---\n" +
+		"type: person\n" +
+		"name: Freya Stark\n" +
+		"aliases: [Freya, FS]\n" +
+		"alias: Freya\n" +
+		"role: Lead engineer\n" +
+		"---\n" +
+		"@tag(person) @tag(team/core)\n\n" +
+		"Works on [[obsidian/projects/raven-project|Raven]] and [[obsidian/projects/raven-project#next-steps|Raven Project > Next Steps]].\n"
+	if got := v.ReadFile("obsidian/people/freya-stark.md"); got != wantFreya {
+		t.Fatalf("freya-stark.md = %q\nwant %q", got, wantFreya)
+	}
+
+	wantProject := "---\n" +
+		"started: 2026-01-05\n" +
+		"owner: \"[[obsidian/people/freya-stark|Freya Stark]]\"\n" +
+		"---\n" +
+		"Owner [[obsidian/people/freya-stark|Freya Stark]] and ![[diagram.png]].\n" +
+		"```\n[[Bob Li]]\nkey:: not a field\n```\n"
+	if got := v.ReadFile("obsidian/projects/raven-project.md"); got != wantProject {
+		t.Fatalf("raven-project.md = %q\nwant %q", got, wantProject)
+	}
+	if got := v.ReadFile("obsidian/home.md"); got != "See [[obsidian/people/freya-stark|Freya Stark]] and [[Nowhere]].\n" {
+		t.Fatalf("home.md = %q", got)
+	}
+
+	reasons := map[string]string{}
+	for _, link := range result.Unresolved {
+		reasons[link.Link] = link.Reason
+	}
+	if reasons["[[diagram.png]]"] != UnresolvedNotMarkdownFile || reasons["[[Nowhere]]"] != UnresolvedTargetNotFound || len(reasons) != 2 {
+		t.Fatalf("unresolved = %+v", result.Unresolved)
+	}
+
+	for _, want := range []string{
+		"  person:\n    default_path: obsidian/people/\n    name_field: name\n",
+		"      aliases:\n        type: string[]\n",
+		"      name:\n        type: string\n        required: true\n",
+		"traits:\n  tag:\n    type: string\n",
+	} {
+		if !strings.Contains(result.SchemaProposal, want) {
+			t.Errorf("schema proposal missing %q:\n%s", want, result.SchemaProposal)
+		}
+	}
+}
+
+func TestImportObsidianAmbiguousNamesAreReported(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).Build()
+	source := t.TempDir()
+	writeSourceFile(t, source, "a/Plan.md", "a\n")
+	writeSourceFile(t, source, "b/Plan.md", "b\n")
+	writeSourceFile(t, source, "Index.md", "[[Plan]] and [[b/Plan]]\n")
+
+	result, err := ImportObsidian(ObsidianImportRequest{
+		VaultPath:   v.Path,
+		VaultConfig: config.DefaultVaultConfig(),
+		SourceDir:   source,
+		Destination: "notes",
+		TagTrait:    "topic",
+		DryRun:      true,
+	})
+	if err != nil {
+		t.Fatalf("ImportObsidian: %v", err)
+	}
+	if len(result.Unresolved) != 1 || result.Unresolved[0].Reason != UnresolvedAmbiguousName {
+		t.Fatalf("unresolved = %+v, want one ambiguous link", result.Unresolved)
+	}
+	if result.Files[0].LinksConverted != 1 {
+		t.Fatalf("index links converted = %d, want 1", result.Files[0].LinksConverted)
+	}
+	if !strings.Contains(result.SchemaProposal, "  topic:\n") {
+		t.Fatalf("schema proposal should declare the tag trait:\n%s", result.SchemaProposal)
+	}
+	if v.FileExists("notes") {
+		t.Fatal("dry run wrote files")
+	}
+}
@@ -0,0 +1,378 @@
+package importsvc
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/pages"
+)
+
+// orgRoamFormat reads org-roam directories: .org files whose headlines carry
+// the structure and whose :ID: properties link notes together.
+type orgRoamFormat struct{}
+
+var (
+	orgHeadlineRE  = regexp.MustCompile(`^(\*+)[ \t]+(.*?)[ \t]*$`)
+	orgTaskRE      = regexp.MustCompile(`^(TODO|NEXT|DOING|STARTED|WAITING|HOLD|DONE|CANCELLED|CANCELED)(?:\s+|$)`)
+	orgTagsRE      = regexp.MustCompile(`[ \t]+:((?:[\w@#%]+:)+)$`)
+	orgKeywordRE   = regexp.MustCompile(`^#\+(\w+):[ \t]*(.*?)[ \t]*$`)
+	orgPropertyRE  = regexp.MustCompile(`^:([\w-]+):(?:[ \t]+(.*?))?[ \t]*$`)
+	orgListRE      = regexp.MustCompile(`^([ \t]*)(?:\+|(\d+)\))[ \t]`)
+	orgTableRuleRE = regexp.MustCompile(`^[ \t]*\|[-+|]+\|?[ \t]*$`)
+	orgLinkRE      = regexp.MustCompile(`\[\[([^\]\[]+)\](?:\[([^\]\[]+)\])?\]`)
+	orgIDLinkRE    = regexp.MustCompile(`\[\[id:([^\]\[]+)\](?:\[([^\]\[]+)\])?\]`)
+	orgVerbatimRE  = regexp.MustCompile(`(^|[\s(])[=~]([^\s=~](?:[^=~]*[^\s=~])?)[=~]($|[\s.,;:!?)])`)
+	orgBoldRE      = regexp.MustCompile(`(^|[\s(])\*([^\s*](?:[^*]*[^\s*])?)\*($|[\s.,;:!?)])`)
+	orgItalicRE    = regexp.MustCompile(`(^|[\s(])/([^\s/](?:[^/]*[^\s/])?)/($|[\s.,;:!?)])`)
+	// orgRoamFileRE matches the timestamp org-roam puts before file names.
+	orgRoamFileRE = regexp.MustCompile(`^\d{14}-`)
+	orgURLRE      = regexp.MustCompile(`^(?:https?|ftp|mailto):`)
+	orgQuotedRE   = regexp.MustCompile(`"([^"]+)"|(\S+)`)
+)
+
+// orgTaskStates maps org TODO keywords to task states.
+var orgTaskStates = map[string]string{
+	"DONE":      taskDone,
+	"CANCELLED": taskCancelled,
+	"CANCELED":  taskCancelled,
+}
+
+func (orgRoamFormat) layout() folderLayout {
+	return folderLayout{ext: ".org", relID: orgRoamRelID}
+}
+
+// orgRoamRelID drops org-roam's timestamp prefix from file names.
+func orgRoamRelID(source string) string {
+	dir, base := path.Split(strings.TrimSuffix(source, path.Ext(source)))
+	return pages.SlugifyPath(path.Join(dir, strings.ReplaceAll(orgRoamFileRE.ReplaceAllString(base, ""), "_", "-")))
+}
+
+// orgHeadingFrame is an open headline while parsing.
+type orgHeadingFrame struct {
+	level int
+	block *outlineBlock
+}
+
+func (orgRoamFormat) parse(source, content string) (*outlineNote, error) {
+	base := strings.TrimSuffix(path.Base(source), path.Ext(source))
+	note := &outlineNote{title: strings.ReplaceAll(orgRoamFileRE.ReplaceAllString(base, ""), "_", " ")}
+	lines := strings.Split(content, "\n")
+
+	// The file's property drawer and keywords come before its text.
+	i := 0
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			continue
+		}
+		if strings.EqualFold(trimmed, ":PROPERTIES:") {
+			var props []outlineProperty
+			props, i = readOrgDrawer(lines, i)
+			for _, prop := range props {
+				switch strings.ToUpper(prop.key) {
+				case "ID":
+					note.nodeID = prop.value
+				case "ROAM_ALIASES":
+					note.aliases = append(note.aliases, orgQuotedList(prop.value)...)
+				default:
+					note.properties = append(note.properties, prop)
+				}
+			}
+			continue
+		}
+		m := orgKeywordRE.FindStringSubmatch(trimmed)
+		if m == nil {
+			break
+		}
+		switch strings.ToLower(m[1]) {
+		case "title":
+			note.title = m[2]
+		case "filetags":
+			note.tags = append(note.tags, orgTagList(m[2])...)
+		case "roam_tags":
+			note.tags = append(note.tags, strings.Fields(m[2])...)
+		case "roam_alias":
+			note.aliases = append(note.aliases, orgQuotedList(m[2])...)
+		}
+	}
+
+	var stack []orgHeadingFrame
+	target := &note.preamble
+	body := &orgBodyConverter{}
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if body.inBlock() {
+			if text, ok := body.convert(line); ok {
+				*target = append(*target, outlineLine{text: text, line: i + 1})
+			}
+			continue
+		}
+		m := orgHeadlineRE.FindStringSubmatch(line)
+		if m == nil {
+			if text, ok := body.convert(line); ok {
+				*target = append(*target, outlineLine{text: text, line: i + 1})
+			}
+			continue
+		}
+
+		level := len(m[1])
+		b := parseOrgHeadline(m[2], i+1)
+		// Planning lines and the property drawer follow the headline.
+		for i+1 < len(lines) {
+			next := strings.TrimSpace(lines[i+1])
+			if strings.EqualFold(next, ":PROPERTIES:") {
+				var props []outlineProperty
+				props, i = readOrgDrawer(lines, i+1)
+				for _, prop := range props {
+					if strings.EqualFold(prop.key, "ID") {
+						b.nodeID = prop.value
+					} else {
+						b.properties = append(b.properties, prop)
+					}
+				}
+				continue
+			}
+			if rest, due := parsePlanning(next); due != "" || (rest == "" && strings.HasPrefix(next, "CLOSED:")) {
+				if due != "" {
+					b.due = due
+				}
+				i++
+				continue
+			}
+			break
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].level >= level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			note.blocks = append(note.blocks, b)
+		} else {
+			parent := stack[len(stack)-1].block
+			parent.children = append(parent.children, b)
+		}
+		stack = append(stack, orgHeadingFrame{level: level, block: b})
+		target = &b.body
+	}
+
+	// Leading and trailing blank lines of each body are layout only.
+	note.preamble = trimBlankLines(note.preamble)
+	var trim func([]*outlineBlock)
+	trim = func(blocks []*outlineBlock) {
+		for _, b := range blocks {
+			b.body = trimBlankLines(b.body)
+			trim(b.children)
+		}
+	}
+	trim(note.blocks)
+	return note, nil
+}
+
+// parseOrgHeadline reads a headline's TODO keyword, priority, and tags.
+func parseOrgHeadline(text string, lineNum int) *outlineBlock {
+	b := &outlineBlock{heading: true, line: lineNum}
+	if m := orgTaskRE.FindStringSubmatch(text); m != nil {
+		b.task = taskOpen
+		if state, ok := orgTaskStates[m[1]]; ok {
+			b.task = state
+		}
+		text = text[len(m[0]):]
+	}
+	text, b.priority = takePriority(text)
+	if m := orgTagsRE.FindStringSubmatch(text); m != nil {
+		b.tags = orgTagList(":" + m[1])
+		text = text[:len(text)-len(m[0])]
+	}
+	b.text = orgInline(strings.TrimSpace(text))
+	return b
+}
+
+// readOrgDrawer reads a :PROPERTIES: drawer starting at lines[start] and
+// returns its properties and the index of its :END: line.
+func readOrgDrawer(lines []string, start int) ([]outlineProperty, int) {
+	var props []outlineProperty
+	for i := start + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.EqualFold(trimmed, ":END:") {
+			return props, i
+		}
+		if m := orgPropertyRE.FindStringSubmatch(trimmed); m != nil {
+			props = append(props, outlineProperty{key: m[1], value: strings.TrimSpace(m[2]), line: i + 1})
+		}
+	}
+	return props, len(lines) - 1
+}
+
+// orgTagList splits ":a:b:" into its tags.
+func orgTagList(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(strings.TrimSpace(value), ":") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// orgQuotedList splits a list such as `"Freya Stark" FS`.
+func orgQuotedList(value string) []string {
+	var out []string
+	for _, m := range orgQuotedRE.FindAllStringSubmatch(value, -1) {
+		out = append(out, m[1]+m[2])
+	}
+	return out
+}
+
+func trimBlankLines(lines []outlineLine) []outlineLine {
+	for len(lines) > 0 && strings.TrimSpace(lines[0].text) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1].text) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// orgBodyConverter turns org body lines into markdown, tracking the
+// #+begin_ block or drawer a line is in.
+type orgBodyConverter struct {
+	block  string // "src", "example", or "quote"
+	drawer bool
+}
+
+func (o *orgBodyConverter) inBlock() bool {
+	return o.block != "" || o.drawer
+}
+
+// convert returns the markdown for one body line, or false to drop it.
+func (o *orgBodyConverter) convert(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	lower := strings.ToLower(trimmed)
+	if o.drawer {
+		o.drawer = lower != ":end:"
+		return "", false
+	}
+	switch {
+	case strings.HasPrefix(lower, "#+begin_src"), strings.HasPrefix(lower, "#+begin_example"):
+		o.block = "src"
+		lang := ""
+		if fields := strings.Fields(trimmed); strings.HasPrefix(lower, "#+begin_src") && len(fields) > 1 {
+			lang = fields[1]
+		}
+		return "```" + lang, true
+	case strings.HasPrefix(lower, "#+end_src"), strings.HasPrefix(lower, "#+end_example"):
+		o.block = ""
+		return "```", true
+	case strings.HasPrefix(lower, "#+begin_quote"):
+		o.block = "quote"
+		return "", false
+	case strings.HasPrefix(lower, "#+end_quote"):
+		// A blank line ends the quote; text after it is not quoted.
+		o.block = ""
+		return "", true
+	case o.block == "src":
+		return line, true
+	case o.block == "quote":
+		return strings.TrimSpace("> " + orgInline(trimmed)), true
+	case lower == ":logbook:" || lower == ":properties:":
+		o.drawer = true
+		return "", false
+	case strings.HasPrefix(trimmed, "#+") || trimmed == "#" || strings.HasPrefix(trimmed, "# "):
+		// Keywords and comments have no markdown equivalent.
+		return "", false
+	case orgTableRuleRE.MatchString(line):
+		return strings.ReplaceAll(line, "+", "|"), true
+	}
+	if m := orgListRE.FindStringSubmatch(line); m != nil {
+		marker := "- "
+		if m[2] != "" {
+			marker = m[2] + ". "
+		}
+		line = m[1] + marker + line[len(m[0]):]
+	}
+	return orgInline(line), true
+}
+
+// orgInline converts org emphasis to markdown: =code= and ~code~ to `code`,
+// *bold* to **bold**, and /italic/ to *italic*. Links are converted when the
+// page is rendered.
+func orgInline(text string) string {
+	text = replaceRepeated(orgVerbatimRE, text, "$1`$2`$3")
+	return mapOutsideCode(text, func(segment string) string {
+		var b strings.Builder
+		last := 0
+		for _, loc := range orgLinkRE.FindAllStringIndex(segment, -1) {
+			b.WriteString(orgEmphasis(segment[last:loc[0]]))
+			b.WriteString(segment[loc[0]:loc[1]])
+			last = loc[1]
+		}
+		b.WriteString(orgEmphasis(segment[last:]))
+		return b.String()
+	})
+}
+
+func orgEmphasis(text string) string {
+	text = replaceRepeated(orgBoldRE, text, "$1**$2**$3")
+	return replaceRepeated(orgItalicRE, text, "$1*$2*$3")
+}
+
+// replaceRepeated applies a boundary-matching regexp until the text stops
+// changing, since adjacent matches share their boundary character.
+func replaceRepeated(re *regexp.Regexp, text, repl string) string {
+	for {
+		next := re.ReplaceAllString(text, repl)
+		if next == text {
+			return text
+		}
+		text = next
+	}
+}
+
+func (orgRoamFormat) convertText(c *outlineConverter, text string, line int) string {
+	if !strings.Contains(text, "[[") {
+		return text
+	}
+	return mapOutsideCode(text, func(segment string) string {
+		return orgLinkRE.ReplaceAllStringFunc(segment, func(match string) string {
+			parts := orgLinkRE.FindStringSubmatch(match)
+			target, display := parts[1], parts[2]
+			switch {
+			case strings.HasPrefix(target, "id:"):
+				return match // Resolved once sections are known
+			case orgURLRE.MatchString(target):
+				if display == "" {
+					return "<" + target + ">"
+				}
+				return "[" + display + "](" + target + ")"
+			case strings.HasPrefix(target, "file:"):
+				file, _, _ := strings.Cut(strings.TrimPrefix(target, "file:"), "::")
+				rel := path.Clean(path.Join(path.Dir(c.file.Source), file))
+				id, ok := c.idsBySource[rel]
+				if !ok {
+					reason := UnresolvedTargetNotFound
+					if rel == ".." || strings.HasPrefix(rel, "../") {
+						reason = UnresolvedOutsideFolder
+					}
+					c.report(match, reason, line)
+					return match
+				}
+				c.file.LinksConverted++
+				if display != "" && display != id {
+					return "[[" + id + "|" + display + "]]"
+				}
+				return "[[" + id + "]]"
+			}
+			return match
+		})
+	})
+}
+
+func (orgRoamFormat) nodeRefPattern() *regexp.Regexp {
+	return orgIDLinkRE
+}
+
+// isSection makes a section of every headline.
+func (orgRoamFormat) isSection(*outlineBlock) bool {
+	return true
+}
@@ -0,0 +1,741 @@
+package importsvc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/wikilink"
+)
+
+// UnresolvedBlockNotFound is reported for block references and id: links
+// whose block is not part of the import.
+const UnresolvedBlockNotFound = "referenced block not found in import folder"
+
+// Task states of outliner TODO keywords.
+const (
+	taskOpen      = "open"
+	taskDone      = "done"
+	taskCancelled = "cancelled"
+)
+
+// maxHeadingLevel is the deepest markdown heading. Blocks nested deeper are
+// written as list items.
+const maxHeadingLevel = 6
+
+// priorityTrait is the trait [#A]-style priorities become.
+const priorityTrait = "priority"
+
+// outlinePriorities maps outliner priorities to the default priority values.
+var outlinePriorities = map[string]string{"A": "high", "B": "medium", "C": "low"}
+
+var (
+	outlinePriorityRE = regexp.MustCompile(`^\[#([A-Ca-c])\]\s*`)
+	// outlinePlanningRE matches SCHEDULED and DEADLINE timestamps, which both
+	// Logseq and org write as "DEADLINE: <2026-03-01 Sun>".
+	outlinePlanningRE = regexp.MustCompile(`\b(SCHEDULED|DEADLINE|CLOSED):\s*[<\[](\d{4}-\d{2}-\d{2})[^>\]]*[>\]]`)
+)
+
+// ignoredOutlineProperties are outliner display settings, not data.
+var ignoredOutlineProperties = map[string]bool{
+	"collapsed":        true,
+	"heading":          true,
+	"background_color": true,
+	"icon":             true,
+	"public":           true,
+	"filters":          true,
+}
+
+type OutlineImportRequest struct {
+	VaultPath   string
+	VaultConfig *config.VaultConfig
+	// Schema is the vault's current schema; types and traits it already
+	// defines are left out of the proposal. May be nil.
+	Schema    *schema.Schema
+	SourceDir string
+	// Destination is the vault directory (under the pages root) to import into.
+	// Defaults to the slug of the source folder name.
+	Destination string
+	// TagTrait is the trait tags are converted to. Defaults to the vault's
+	// hashtags trait.
+	TagTrait string
+	DryRun   bool
+}
+
+type OutlineImportFile struct {
+	Source         string   `json:"source"`
+	ID             string   `json:"id"`
+	File           string   `json:"file"`
+	Type           string   `json:"type,omitempty"`
+	Renamed        bool     `json:"renamed,omitempty"`
+	Sections       int      `json:"sections"` // Blocks and headlines that became sections
+	Tasks          int      `json:"tasks"`
+	LinksConverted int      `json:"links_converted"`
+	Tags           []string `json:"tags,omitempty"`
+	Fields         []string `json:"fields,omitempty"` // Page properties moved to frontmatter
+}
+
+type OutlineImportResult struct {
+	Destination string
+	TagTrait    string
+	Files       []OutlineImportFile
+	Unresolved  []UnresolvedLink
+	// SchemaProposal is schema.yaml content declaring the types, fields, and
+	// traits the imported pages use. It is not applied.
+	SchemaProposal   string
+	ChangedFilePaths []string
+}
+
+// outlineNote is one outliner page, parsed but not yet rendered. Text keeps
+// the outliner's link syntax until it is rendered.
+type outlineNote struct {
+	title      string
+	names      []string // Other names links use for the page
+	nodeID     string   // ID id: links use for the whole page
+	properties []outlineProperty
+	tags       []string
+	aliases    []string
+	preamble   []outlineLine // Text before the first block
+	blocks     []*outlineBlock
+}
+
+type outlineProperty struct {
+	key   string
+	value string
+	line  int
+}
+
+type outlineLine struct {
+	text string
+	line int
+}
+
+type outlineBlock struct {
+	nodeID     string // Block ID that block references and id: links use
+	heading    bool   // Written as a heading in the source
+	text       string // First line, without task keyword or priority
+	body       []outlineLine
+	task       string
+	priority   string
+	due        string
+	tags       []string
+	properties []outlineProperty
+	children   []*outlineBlock
+	line       int
+}
+
+func (b *outlineBlock) empty() bool {
+	return strings.TrimSpace(b.text) == "" && len(b.body) == 0 && len(b.children) == 0 &&
+		b.task == "" && len(b.tags) == 0 && len(b.properties) == 0
+}
+
+// outliner is what differs between outliner formats: which files make up an
+// import, how a page is parsed, and link syntax.
+type outliner interface {
+	layout() folderLayout
+	parse(source, content string) (*outlineNote, error)
+	// convertText rewrites page links and markup in one line of text.
+	// References to blocks by ID are left for the final pass.
+	convertText(c *outlineConverter, text string, line int) string
+	// nodeRefPattern matches a reference to a block by ID. Submatch 1 is the
+	// ID; submatch 2, when present, is display text.
+	nodeRefPattern() *regexp.Regexp
+	// isSection reports whether a block becomes a section by itself.
+	isSection(b *outlineBlock) bool
+}
+
+// ImportLogseq copies a Logseq graph into the vault. Pages keep their
+// properties as frontmatter; blocks with children or an id:: become sections,
+// so their nesting is kept as child objects and ((block refs)) become
+// section refs; TODO keywords become task traits.
+func ImportLogseq(req OutlineImportRequest) (*OutlineImportResult, error) {
+	return importOutline(req, logseqFormat{})
+}
+
+// ImportOrgRoam copies an org-roam directory into the vault, converting org
+// syntax to markdown. File properties become frontmatter, headlines become
+// sections, id: links become refs, and TODO keywords become task traits.
+func ImportOrgRoam(req OutlineImportRequest) (*OutlineImportResult, error) {
+	return importOutline(req, orgRoamFormat{})
+}
+
+func importOutline(req OutlineImportRequest, format outliner) (*OutlineImportResult, error) {
+	plan, err := planFolderImport(req.VaultPath, req.VaultConfig, req.SourceDir, req.Destination, format.layout())
+	if err != nil {
+		return nil, err
+	}
+
+	c := &outlineConverter{
+		format:      format,
+		tasks:       req.VaultConfig.GetTasksConfig(),
+		tagTrait:    importTagTrait(req.TagTrait, req.VaultConfig),
+		proposal:    newSchemaProposal(req.Schema),
+		idsBySource: plan.IDsBySource,
+		pages:       make(map[string]string),
+		nodes:       make(map[string]*outlineNodeTarget),
+	}
+	result := &OutlineImportResult{Destination: plan.Destination, TagTrait: c.tagTrait}
+
+	// Parse every page before rendering any: links name pages by titles
+	// and aliases, which properties may set.
+	notes := make([]*outlineNote, len(plan.Files))
+	for i, planned := range plan.Files {
+		content, err := os.ReadFile(filepath.Join(plan.SourceDir, filepath.FromSlash(planned.Source)))
+		if err != nil {
+			return nil, newError(codes.ErrFileRead, fmt.Sprintf("failed to read %s: %v", planned.Source, err), err)
+		}
+		note, err := format.parse(planned.Source, strings.ReplaceAll(string(content), "\r\n", "\n"))
+		if err != nil {
+			return nil, err
+		}
+		notes[i] = note
+		for _, name := range append(append([]string{note.title}, note.names...), note.aliases...) {
+			c.addPage(name, planned.ID)
+		}
+		c.addNodes(note, planned.ID)
+	}
+
+	rendered := make([]*renderedOutline, len(notes))
+	for i, planned := range plan.Files {
+		file := OutlineImportFile{Source: planned.Source, ID: planned.ID, File: planned.File, Renamed: planned.Renamed}
+		c.file = &file
+		out, err := c.render(notes[i])
+		if err != nil {
+			return nil, err
+		}
+		if file.Type != "" {
+			// Typed pages live under the type root, untyped ones under the pages root.
+			file.File = paths.ObjectIDToFilePath(file.ID, file.Type, req.VaultConfig.GetObjectsRoot(), req.VaultConfig.GetPagesRoot())
+			c.proposal.addType(file.Type, "")
+			c.proposal.addFields(file.Type, out.frontmatter)
+		}
+		if err := c.locateNodes(notes[i], planned.ID, out); err != nil {
+			return nil, err
+		}
+		rendered[i] = out
+		result.Files = append(result.Files, file)
+	}
+
+	// Block references resolve last, once every page's section slugs are known.
+	for i := range result.Files {
+		file := &result.Files[i]
+		content, count := c.resolveNodeRefs(rendered[i].content)
+		file.LinksConverted += count
+		if req.DryRun {
+			continue
+		}
+		target, err := writeImportedFile(plan.VaultPath, file.File, content)
+		if err != nil {
+			return nil, err
+		}
+		result.ChangedFilePaths = append(result.ChangedFilePaths, target)
+	}
+
+	result.Unresolved = c.unresolved
+	result.SchemaProposal = c.proposal.render()
+	return result, nil
+}
+
+// outlineNodeTarget is where a block ID points once its page is rendered.
+type outlineNodeTarget struct {
+	block *outlineBlock // nil for a page's own ID
+	ref   string
+	title string
+}
+
+// outlineConverter holds the state shared while rendering an import.
+type outlineConverter struct {
+	format      outliner
+	tasks       *config.TasksConfig
+	tagTrait    string
+	proposal    *schemaProposal
+	idsBySource map[string]string
+	pages       map[string]string // Lowercased page name -> ID; "" when ambiguous
+	nodes       map[string]*outlineNodeTarget
+	file        *OutlineImportFile // Page being rendered
+	unresolved  []UnresolvedLink
+}
+
+func (c *outlineConverter) addPage(name, id string) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" {
+		return
+	}
+	if existing, ok := c.pages[key]; ok && existing != id {
+		c.pages[key] = ""
+		return
+	}
+	c.pages[key] = id
+}
+
+func (c *outlineConverter) addNodes(note *outlineNote, id string) {
+	if note.nodeID != "" {
+		c.nodes[note.nodeID] = &outlineNodeTarget{ref: id, title: note.title}
+	}
+	var walk func([]*outlineBlock)
+	walk = func(blocks []*outlineBlock) {
+		for _, b := range blocks {
+			if b.nodeID != "" {
+				c.nodes[b.nodeID] = &outlineNodeTarget{block: b, ref: id}
+			}
+			walk(b.children)
+		}
+	}
+	walk(note.blocks)
+}
+
+// pageLink returns a ref to the page called name, or literal when no
+// imported page has that name.
+func (c *outlineConverter) pageLink(name, display, literal string, line int) string {
+	id, ok := c.pages[strings.ToLower(strings.TrimSpace(name))]
+	if !ok || id == "" {
+		reason := UnresolvedTargetNotFound
+		if ok {
+			reason = UnresolvedAmbiguousName
+		}
+		c.report(literal, reason, line)
+		return literal
+	}
+	c.file.LinksConverted++
+	if display = strings.TrimSpace(display); display != "" && display != id {
+		return "[[" + id + "|" + display + "]]"
+	}
+	return "[[" + id + "]]"
+}
+
+func (c *outlineConverter) report(link, reason string, line int) {
+	c.unresolved = append(c.unresolved, UnresolvedLink{Source: c.file.Source, Line: line, Link: link, Reason: reason})
+}
+
+// text converts one line and reports references to unknown blocks.
+func (c *outlineConverter) text(text string, line int) string {
+	text = c.format.convertText(c, text, line)
+	mapOutsideCode(text, func(segment string) string {
+		for _, m := range c.format.nodeRefPattern().FindAllStringSubmatch(segment, -1) {
+			if c.nodes[m[1]] == nil {
+				c.report(m[0], UnresolvedBlockNotFound, line)
+			}
+		}
+		return segment
+	})
+	return text
+}
+
+// bodyText converts block body lines, leaving fenced code as written.
+func (c *outlineConverter) bodyText(lines []outlineLine) []string {
+	out := make([]string, 0, len(lines))
+	fence := ""
+	for _, l := range lines {
+		trimmed := strings.TrimSpace(l.text)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			out = append(out, l.text)
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+			out = append(out, l.text)
+		default:
+			out = append(out, c.text(l.text, l.line))
+		}
+	}
+	return out
+}
+
+// renderedOutline is a page rendered to markdown, before block references
+// are resolved.
+type renderedOutline struct {
+	content      string
+	frontmatter  *yaml.Node
+	headingLines map[*outlineBlock]int // 1-based line of each section heading
+}
+
+func (c *outlineConverter) render(note *outlineNote) (*renderedOutline, error) {
+	mapping := c.frontmatter(note)
+
+	w := &outlineWriter{headings: make(map[*outlineBlock]int)}
+	if len(note.tags) > 0 {
+		c.file.Tags = note.tags
+		c.proposal.addTrait(c.tagTrait, schema.FieldTypeString)
+		w.line(c.tagAnnotations(note.tags))
+		w.blank()
+	}
+	for _, line := range c.bodyText(note.preamble) {
+		w.line(line)
+	}
+	c.renderBlocks(w, note.blocks, 2, -1)
+	body := w.String()
+
+	content, err := withFrontmatter(mapping, body, c.file.Source)
+	if err != nil {
+		return nil, err
+	}
+	out := &renderedOutline{content: content, headingLines: make(map[*outlineBlock]int, len(w.headings))}
+	if len(mapping.Content) > 0 {
+		out.frontmatter = mapping
+	}
+	offset := strings.Count(content[:len(content)-len(body)], "\n")
+	for b, idx := range w.headings {
+		out.headingLines[b] = offset + idx + 1
+	}
+	return out, nil
+}
+
+// frontmatter builds a page's frontmatter from its properties. A type
+// property types the page.
+func (c *outlineConverter) frontmatter(note *outlineNote) *yaml.Node {
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	for _, prop := range note.properties {
+		key := outlinePropertyKey(prop.key)
+		if key == "" {
+			continue
+		}
+		value := strings.TrimSpace(prop.value)
+		if key == "type" {
+			if c.file.Type == "" {
+				c.file.Type = strings.TrimSuffix(strings.TrimPrefix(value, "[["), "]]")
+			}
+			continue
+		}
+		addDataviewField(mapping, &c.file.Fields, key, c.text(value, prop.line))
+	}
+	if c.file.Type != "" {
+		mapping.Content = append([]*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "type"},
+			{Kind: yaml.ScalarNode, Value: c.file.Type},
+		}, mapping.Content...)
+		c.proposal.addType(c.file.Type, "")
+		insertNameField(mapping, c.proposal.nameField(c.file.Type), note.title)
+	}
+	if len(note.aliases) > 0 {
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "alias"}, &yaml.Node{Kind: yaml.ScalarNode, Value: note.aliases[0]})
+	}
+	if len(note.aliases) > 1 {
+		list := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, alias := range note.aliases {
+			list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: alias})
+		}
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "aliases"}, list)
+	}
+	return mapping
+}
+
+// outlinePropertyKey normalizes a property name to a field or trait name.
+// It returns "" for properties that are not carried over.
+func outlinePropertyKey(raw string) string {
+	if strings.HasPrefix(strings.ToLower(raw), "logseq.") {
+		return ""
+	}
+	key := dataviewFieldKey(raw)
+	if ignoredOutlineProperties[key] {
+		return ""
+	}
+	return key
+}
+
+// renderBlocks writes sibling blocks. listDepth is -1 where blocks may become
+// sections. Once one sibling is a section, the siblings after it must be too,
+// or they would read as part of that section.
+//
+// Headings hold no traits or refs, so a section's heading is the block's
+// plain text, and a block with traits or links repeats its line beneath.
+func (c *outlineConverter) renderBlocks(w *outlineWriter, blocks []*outlineBlock, level, listDepth int) {
+	asSections := false
+	for _, b := range blocks {
+		if b.empty() {
+			continue
+		}
+		line := c.blockLine(b)
+		if listDepth < 0 && level <= maxHeadingLevel && (asSections || c.format.isSection(b)) {
+			asSections = true
+			c.file.Sections++
+			title := plainText(c.nodeRefDisplay(line))
+			w.heading(level, title, b)
+			if line != title {
+				w.line(line)
+				w.blank()
+			}
+			for _, text := range c.bodyText(b.body) {
+				w.line(text)
+			}
+			c.renderBlocks(w, b.children, level+1, -1)
+			continue
+		}
+
+		depth := max(listDepth, 0)
+		indent := strings.Repeat("  ", depth)
+		w.line(indent + "- " + line)
+		for _, text := range c.bodyText(b.body) {
+			if text != "" {
+				text = indent + "  " + text
+			}
+			w.line(text)
+		}
+		c.renderBlocks(w, b.children, level, depth+1)
+	}
+}
+
+// blockLine is a block's first line with its task state, priority,
+// scheduling, tags, and properties written as traits.
+func (c *outlineConverter) blockLine(b *outlineBlock) string {
+	parts := []string{}
+	if text := strings.TrimSpace(c.text(b.text, b.line)); text != "" {
+		parts = append(parts, text)
+	}
+	switch b.task {
+	case taskOpen:
+		parts = append(parts, "@"+c.tasks.Trait)
+	case taskDone:
+		parts = append(parts, "@"+c.tasks.Trait+"("+c.tasks.DoneValue+")")
+	case taskCancelled:
+		parts = append(parts, "@"+c.tasks.Trait+"("+c.tasks.CancelledValue+")")
+	}
+	if b.task != "" {
+		c.file.Tasks++
+		c.proposal.addTrait(c.tasks.Trait, schema.FieldTypeString)
+	}
+	if b.priority != "" {
+		parts = append(parts, "@"+priorityTrait+"("+b.priority+")")
+		c.proposal.addTrait(priorityTrait, schema.FieldTypeString)
+	}
+	if b.due != "" {
+		parts = append(parts, "@"+c.tasks.DueTrait+"("+b.due+")")
+		c.proposal.addTrait(c.tasks.DueTrait, schema.FieldTypeDate)
+	}
+	if len(b.tags) > 0 {
+		parts = append(parts, c.tagAnnotations(b.tags))
+		c.proposal.addTrait(c.tagTrait, schema.FieldTypeString)
+	}
+	for _, prop := range b.properties {
+		key := outlinePropertyKey(prop.key)
+		if key == "" {
+			continue
+		}
+		c.proposal.addTrait(key, schema.FieldTypeString)
+		if value := strings.TrimSpace(c.text(prop.value, prop.line)); value != "" {
+			parts = append(parts, "@"+key+"("+value+")")
+		} else {
+			parts = append(parts, "@"+key)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (c *outlineConverter) tagAnnotations(tags []string) string {
+	annotations := make([]string, len(tags))
+	for i, tag := range tags {
+		annotations[i] = "@" + c.tagTrait + "(" + tag + ")"
+	}
+	return strings.Join(annotations, " ")
+}
+
+// locateNodes points the page's block IDs at the sections they became, using
+// the slugs the parser gives the rendered headings.
+func (c *outlineConverter) locateNodes(note *outlineNote, id string, out *renderedOutline) error {
+	doc, err := parser.ParseDocument(out.content, c.file.File, "")
+	if err != nil {
+		return newError(CodeInvalidInput, fmt.Sprintf("failed to parse converted %s: %v", c.file.Source, err), err)
+	}
+	sectionsByLine := make(map[int]*parser.ParsedSection, len(doc.Sections))
+	for _, section := range doc.Sections {
+		sectionsByLine[section.LineStart] = section
+	}
+	var walk func([]*outlineBlock)
+	walk = func(blocks []*outlineBlock) {
+		for _, b := range blocks {
+			if target := c.nodes[b.nodeID]; target != nil && target.block == b {
+				// Blocks written as list items point at the page.
+				target.title = plainText(b.text)
+				if section := sectionsByLine[out.headingLines[b]]; section != nil {
+					target.ref = id + "#" + section.Slug
+					target.title = plainText(section.Title)
+				}
+			}
+			walk(b.children)
+		}
+	}
+	walk(note.blocks)
+	return nil
+}
+
+// resolveNodeRefs rewrites references to blocks by ID as refs and returns
+// how many it converted.
+func (c *outlineConverter) resolveNodeRefs(content string) (string, int) {
+	converted := 0
+	pattern := c.format.nodeRefPattern()
+	lines := strings.Split(content, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		lines[i] = mapOutsideCode(line, func(segment string) string {
+			return pattern.ReplaceAllStringFunc(segment, func(match string) string {
+				parts := pattern.FindStringSubmatch(match)
+				target := c.nodes[parts[1]]
+				if target == nil {
+					return match
+				}
+				display := target.title
+				if len(parts) > 2 && strings.TrimSpace(parts[2]) != "" {
+					display = strings.TrimSpace(parts[2])
+				}
+				converted++
+				if display != "" && display != target.ref {
+					return "[[" + target.ref + "|" + display + "]]"
+				}
+				return "[[" + target.ref + "]]"
+			})
+		})
+	}
+	return strings.Join(lines, "\n"), converted
+}
+
+// nodeRefDisplay replaces block references that carry display text with
+// that text.
+func (c *outlineConverter) nodeRefDisplay(text string) string {
+	pattern := c.format.nodeRefPattern()
+	return pattern.ReplaceAllStringFunc(text, func(match string) string {
+		if parts := pattern.FindStringSubmatch(match); len(parts) > 2 && parts[2] != "" {
+			return parts[2]
+		}
+		return match
+	})
+}
+
+// plainText strips refs and trait annotations from a heading, for use as
+// link display text.
+func plainText(text string) string {
+	matches := wikilink.FindAllInLine(text, false)
+	for i := len(matches) - 1; i >= 0; i-- {
+		m := matches[i]
+		display := m.Target
+		if m.DisplayText != nil {
+			display = *m.DisplayText
+		}
+		text = text[:m.Start] + display + text[m.End:]
+	}
+	return parser.StripTraitAnnotations(text)
+}
+
+// mapOutsideCode applies fn to the parts of line outside `code` spans.
+func mapOutsideCode(line string, fn func(string) string) string {
+	ranges := inlineCodeRanges(line)
+	if len(ranges) == 0 {
+		return fn(line)
+	}
+	var b strings.Builder
+	last := 0
+	for _, r := range ranges {
+		b.WriteString(fn(line[last:r[0]]))
+		b.WriteString(line[r[0] : r[1]+1])
+		last = r[1] + 1
+	}
+	b.WriteString(fn(line[last:]))
+	return b.String()
+}
+
+// outlineWriter assembles a rendered page, keeping blank lines around
+// headings.
+type outlineWriter struct {
+	lines      []string
+	headings   map[*outlineBlock]int // 0-based line of each heading
+	pendingGap bool
+}
+
+func (w *outlineWriter) blank() {
+	if len(w.lines) > 0 && w.lines[len(w.lines)-1] != "" {
+		w.lines = append(w.lines, "")
+	}
+}
+
+func (w *outlineWriter) heading(level int, text string, b *outlineBlock) {
+	w.blank()
+	w.headings[b] = len(w.lines)
+	w.lines = append(w.lines, strings.Repeat("#", level)+" "+text)
+	w.pendingGap = true
+}
+
+func (w *outlineWriter) line(text string) {
+	if w.pendingGap {
+		if text == "" {
+			return
+		}
+		w.blank()
+		w.pendingGap = false
+	}
+	w.lines = append(w.lines, text)
+}
+
+func (w *outlineWriter) String() string {
+	end := len(w.lines)
+	for end > 0 && w.lines[end-1] == "" {
+		end--
+	}
+	if end == 0 {
+		return ""
+	}
+	return strings.Join(w.lines[:end], "\n") + "\n"
+}
+
+// parsePlanning removes SCHEDULED and DEADLINE timestamps from a line and
+// returns the rest and the due date, preferring the deadline.
+func parsePlanning(line string) (string, string) {
+	due, scheduled := "", ""
+	rest := outlinePlanningRE.ReplaceAllStringFunc(line, func(match string) string {
+		parts := outlinePlanningRE.FindStringSubmatch(match)
+		switch parts[1] {
+		case "DEADLINE":
+			due = parts[2]
+		case "SCHEDULED":
+			scheduled = parts[2]
+		}
+		return ""
+	})
+	if due == "" {
+		due = scheduled
+	}
+	return strings.TrimSpace(rest), due
+}
+
+// takePriority strips a leading [#A] priority from text.
+func takePriority(text string) (string, string) {
+	m := outlinePriorityRE.FindStringSubmatch(text)
+	if m == nil {
+		return text, ""
+	}
+	return text[len(m[0]):], outlinePriorities[strings.ToUpper(m[1])]
+}
+
+// splitListValue splits a comma-separated property value such as
+// "[[Freya]], design, #ops" into names.
+func splitListValue(value string) []string {
+	var out []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimPrefix(strings.TrimSpace(item), "#")
+		item = strings.TrimSuffix(strings.TrimPrefix(item, "[["), "]]")
+		item = strings.Trim(strings.TrimSpace(item), `"`)
+		if item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
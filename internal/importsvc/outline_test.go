@@ -0,0 +1,152 @@
+package importsvc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestImportLogseqKeepsBlocksTasksAndProperties(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).Build()
+	source := t.TempDir()
+	writeSourceFile(t, source, "logseq/config.edn", "{}\n")
+	writeSourceFile(t, source, "pages/Raven Project.md", "type:: project\n"+
+		"status:: active\n"+
+		"tags:: work, [[tools]]\n"+
+		"alias:: Raven\n"+
+		"\n"+
+		"- Goals\n"+
+		"\t- Ship the [[Freya Stark]] importer\n"+
+		"\t  id:: 6650a1b2-0000-4000-8000-000000000001\n"+
+		"\t- TODO [#A] Write docs\n"+
+		"\t  DEADLINE: <2026-03-01 Sun>\n"+
+		"- Loose note\n")
+	writeSourceFile(t, source, "pages/Freya Stark.md", "- role:: lead\n- DONE Review [label]([[Raven]])\n")
+	writeSourceFile(t, source, "journals/2026_03_01.md", "- Met about ((6650a1b2-0000-4000-8000-000000000001))\n"+
+		"- LATER Call [[Nobody]]\n"+
+		"  :LOGBOOK:\n"+
+		"  CLOCK: [2026-03-01 Sun 10:00]\n"+
+		"  :END:\n")
+
+	result, err := ImportLogseq(OutlineImportRequest{
+		VaultPath:   v.Path,
+		VaultConfig: config.DefaultVaultConfig(),
+		SourceDir:   source,
+		Destination: "graph",
+	})
+	if err != nil {
+		t.Fatalf("ImportLogseq: %v", err)
+	}
+	if len(result.Files) != 3 {
+		t.Fatalf("files = %+v, want 3 (logseq/ skipped)", result.Files)
+	}
+
+	wantProject := "---\n" +
+		"type: project\n" +
+		"name: Raven Project\n" +
+		"status: active\n" +
+		"alias: Raven\n" +
+		"---\n" +
+		"@tag(work) @tag(tools)\n\n" +
+		"## Goals\n\n" +
+		"### Ship the Freya Stark importer\n\n" +
+		"Ship the [[graph/freya-stark|Freya Stark]] importer\n\n" +
+		"### Write docs\n\n" +
+		"Write docs @todo @priority(high) @due(2026-03-01)\n\n" +
+		"## Loose note\n"
+	if got := v.ReadFile("graph/raven-project.md"); got != wantProject {
+		t.Fatalf("raven-project.md = %q\nwant %q", got, wantProject)
+	}
+	if got := v.ReadFile("graph/freya-stark.md"); got != "---\nrole: lead\n---\n- Review [[graph/raven-project|label]] @todo(done)\n" {
+		t.Fatalf("freya-stark.md = %q", got)
+	}
+	wantJournal := "- Met about [[graph/raven-project#ship-the-freya-stark-importer|Ship the Freya Stark importer]]\n" +
+		"- Call [[Nobody]] @todo\n"
+	if got := v.ReadFile("graph/journals/2026-03-01.md"); got != wantJournal {
+		t.Fatalf("journal = %q\nwant %q", got, wantJournal)
+	}
+
+	if len(result.Unresolved) != 1 || result.Unresolved[0].Link != "[[Nobody]]" || result.Unresolved[0].Line != 2 {
+		t.Fatalf("unresolved = %+v, want [[Nobody]] on line 2", result.Unresolved)
+	}
+	if project := result.Files[2]; project.Type != "project" || project.Sections != 4 || project.Tasks != 1 {
+		t.Fatalf("project file = %+v, want type project with 4 sections and 1 task", project)
+	}
+	for _, want := range []string{"  project:\n", "      status:\n        type: string\n", "  todo:\n", "  due:\n    type: date\n"} {
+		if !strings.Contains(result.SchemaProposal, want) {
+			t.Errorf("schema proposal missing %q:\n%s", want, result.SchemaProposal)
+		}
+	}
+}
+
+func TestImportOrgRoamConvertsOrgSyntax(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).Build()
+	source := t.TempDir()
+	writeSourceFile(t, source, "20260301101500-raven_project.org", ":PROPERTIES:\n"+
+		":ID:       node-raven\n"+
+		":STATUS:   active\n"+
+		":ROAM_ALIASES: \"Raven\" RP\n"+
+		":END:\n"+
+		"#+title: Raven Project\n"+
+		"#+filetags: :work:\n"+
+		"\n"+
+		"Intro with *bold*, =code= and [[https://example.com][a site]].\n"+
+		"# a comment\n"+
+		"* Goals\n"+
+		"** TODO [#B] Write docs :docs:\n"+
+		"DEADLINE: <2026-03-01 Sun>\n"+
+		":PROPERTIES:\n"+
+		":ID: node-docs\n"+
+		":END:\n"+
+		"Ask [[id:node-freya][Freya]].\n"+
+		"+ item\n"+
+		"#+begin_src go\n"+
+		"* not a headline\n"+
+		"#+end_src\n")
+	writeSourceFile(t, source, "freya.org", "#+title: Freya Stark\n"+
+		":PROPERTIES:\n:ID: node-freya\n:END:\n"+
+		"Works on [[id:node-docs]] for [[id:node-raven][Raven]], see [[file:missing.org][this]].\n")
+
+	result, err := ImportOrgRoam(OutlineImportRequest{
+		VaultPath:   v.Path,
+		VaultConfig: config.DefaultVaultConfig(),
+		SourceDir:   source,
+		Destination: "roam",
+		TagTrait:    "topic",
+	})
+	if err != nil {
+		t.Fatalf("ImportOrgRoam: %v", err)
+	}
+
+	wantProject := "---\n" +
+		"status: active\n" +
+		"alias: Raven\n" +
+		"aliases: [Raven, RP]\n" +
+		"---\n" +
+		"@topic(work)\n\n" +
+		"Intro with **bold**, `code` and [a site](https://example.com).\n\n" +
+		"## Goals\n\n" +
+		"### Write docs\n\n" +
+		"Write docs @todo @priority(medium) @due(2026-03-01) @topic(docs)\n\n" +
+		"Ask [[roam/freya|Freya]].\n" +
+		"- item\n" +
+		"```go\n* not a headline\n```\n"
+	if got := v.ReadFile("roam/raven-project.md"); got != wantProject {
+		t.Fatalf("raven-project.md = %q\nwant %q", got, wantProject)
+	}
+	if got := v.ReadFile("roam/freya.md"); got != "Works on [[roam/raven-project#write-docs|Write docs]] for [[roam/raven-project|Raven]], see [[file:missing.org][this]].\n" {
+		t.Fatalf("freya.md = %q", got)
+	}
+	if len(result.Unresolved) != 1 || result.Unresolved[0].Reason != UnresolvedTargetNotFound {
+		t.Fatalf("unresolved = %+v, want the missing file link", result.Unresolved)
+	}
+	if !strings.Contains(result.SchemaProposal, "  topic:\n") {
+		t.Fatalf("schema proposal should declare the tag trait:\n%s", result.SchemaProposal)
+	}
+}
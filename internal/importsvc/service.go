@@ -18,6 +18,7 @@ import (
 	"github.com/aidanlsb/raven/internal/pages"
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 type Code = codes.ErrorCode
@@ -345,6 +346,13 @@ type RunRequest struct {
 	DryRun        bool
 	CreateOnly    bool
 	UpdateOnly    bool
+	// CoerceStrings converts string values to their field's schema type, for
+	// input such as CSV where every value is text.
+	CoerceStrings bool
+	// SkipDuplicateKeys skips items whose match key repeats an earlier item's.
+	SkipDuplicateKeys bool
+	// ItemLabel names an item in skipped results (default: item[i]).
+	ItemLabel func(index int) string
 }
 
 type RunResult struct {
@@ -388,12 +396,17 @@ func Run(req RunRequest) (*RunResult, error) {
 	objectsRoot := vaultCfg.GetObjectsRoot()
 	pagesRoot := vaultCfg.GetPagesRoot()
 	templateDir := vaultCfg.GetTemplateDirectory()
+	itemLabel := req.ItemLabel
+	if itemLabel == nil {
+		itemLabel = func(i int) string { return fmt.Sprintf("item[%d]", i) }
+	}
+	firstByTarget := make(map[string]int)
 
 	for i, item := range req.Items {
 		itemCfg, err := ResolveItemMapping(item, req.MappingConfig, sch)
 		if err != nil {
 			result.Results = append(result.Results, ResultItem{
-				ID:     fmt.Sprintf("item[%d]", i),
+				ID:     itemLabel(i),
 				Action: "skipped",
 				Reason: err.Error(),
 			})
@@ -401,6 +414,9 @@ func Run(req RunRequest) (*RunResult, error) {
 		}
 
 		mapped := ApplyFieldMappings(item, itemCfg.FieldMap)
+		if req.CoerceStrings {
+			coerceStringFields(mapped, sch.Types[itemCfg.TypeName])
+		}
 		contentValue := ""
 		if itemCfg.ContentField != "" {
 			contentValue = ExtractContentField(mapped, itemCfg.ContentField)
@@ -409,7 +425,7 @@ func Run(req RunRequest) (*RunResult, error) {
 		matchValue, ok := MatchKeyValue(mapped, itemCfg.MatchKey)
 		if !ok {
 			result.Results = append(result.Results, ResultItem{
-				ID:     fmt.Sprintf("item[%d]", i),
+				ID:     itemLabel(i),
 				Action: "skipped",
 				Reason: fmt.Sprintf("missing match key '%s'", itemCfg.MatchKey),
 			})
@@ -418,6 +434,17 @@ func Run(req RunRequest) (*RunResult, error) {
 
 		targetName := importTargetName(matchValue)
 		targetPath := pages.ResolveTargetPathWithRoots(targetName, itemCfg.TypeName, sch, objectsRoot, pagesRoot)
+		if req.SkipDuplicateKeys {
+			if first, seen := firstByTarget[targetPath]; seen {
+				result.Results = append(result.Results, ResultItem{
+					ID:     targetPath,
+					Action: "skipped",
+					Reason: fmt.Sprintf("duplicate %s '%s' (first in %s)", itemCfg.MatchKey, matchValue, itemLabel(first)),
+				})
+				continue
+			}
+			firstByTarget[targetPath] = i
+		}
 		exists := pages.Exists(vaultPath, targetPath)
 
 		if exists && req.CreateOnly {
@@ -547,7 +574,7 @@ func updateObject(
 		filePath += ".md"
 	}
 
-	fileData, err := os.ReadFile(filePath)
+	fileData, err := vaultcrypt.ReadFile(filePath)
 	if err != nil {
 		return ResultItem{ID: targetPath, Action: "error", Reason: fmt.Sprintf("read error: %v", err)}, warnings, ""
 	}
@@ -616,10 +643,11 @@ func mutationErrorResult(id string, err error, fallbackPrefix string) ResultItem
 	var validationErr *fieldmutation.ValidationError
 	if errors.As(err, &validationErr) {
 		return ResultItem{
-			ID:     id,
-			Action: "error",
-			Reason: validationErr.Error(),
-			Code:   "VALIDATION_FAILED",
+			ID:      id,
+			Action:  "error",
+			Reason:  validationErr.Error(),
+			Code:    "VALIDATION_FAILED",
+			Details: validationErr.Details(),
 		}
 	}
 
@@ -635,7 +663,7 @@ func mutationErrorResult(id string, err error, fallbackPrefix string) ResultItem
 }
 
 func appendContentToFile(filePath, content string) error {
-	existing, err := os.ReadFile(filePath)
+	existing, err := vaultcrypt.ReadFile(filePath)
 	if err != nil {
 		return err
 	}
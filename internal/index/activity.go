@@ -0,0 +1,181 @@
+package index
+
+import (
+	"database/sql"
+	"time"
+)
+
+// StatsSnapshot is one day's recorded vault statistics.
+type StatsSnapshot struct {
+	Day     string // Local date, YYYY-MM-DD
+	Files   int
+	Objects int
+	Traits  int
+	Refs    int
+	Orphans int
+}
+
+// FileEditCount is how many times a file was re-indexed with a newer
+// modification time within a window.
+type FileEditCount struct {
+	FilePath string
+	ObjectID string // Empty when the file is no longer indexed
+	Edits    int
+}
+
+// ReferenceCount is the number of references an object receives from other
+// files.
+type ReferenceCount struct {
+	ObjectID   string
+	FilePath   string
+	References int
+}
+
+// recordFileEdit counts one edit of filePath on the local date of mtime.
+func recordFileEdit(tx *sql.Tx, filePath string, mtime int64) error {
+	_, err := tx.Exec(`
+		INSERT INTO file_edits (file_path, day, edits) VALUES (?, ?, 1)
+		ON CONFLICT(file_path, day) DO UPDATE SET edits = edits + 1
+	`, filePath, time.Unix(mtime, 0).Format("2006-01-02"))
+	return err
+}
+
+// incomingRefsSQL selects (object id, source file) for every resolved
+// reference and ref-typed field, with section fragments stripped so a link to
+// a section counts for its object.
+const incomingRefsSQL = `
+	SELECT CASE WHEN instr(target_id, '#') > 0 THEN substr(target_id, 1, instr(target_id, '#') - 1) ELSE target_id END AS id, file_path
+	FROM refs WHERE target_id IS NOT NULL
+	UNION ALL
+	SELECT target_id, file_path FROM field_refs WHERE target_id IS NOT NULL
+`
+
+// OrphanCount returns the number of objects no other file references.
+func (d *Database) OrphanCount() (int, error) {
+	var count int
+	err := d.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM objects
+		WHERE id NOT IN (
+			SELECT i.id
+			FROM (` + incomingRefsSQL + `) i
+			JOIN objects t ON t.id = i.id
+			WHERE i.file_path != t.file_path
+		)
+	`).Scan(&count)
+	return count, err
+}
+
+// RecordStatsSnapshot stores the current statistics as the snapshot for the
+// local date of now, replacing an earlier snapshot from the same day.
+func (d *Database) RecordStatsSnapshot(now time.Time) (*StatsSnapshot, error) {
+	stats, err := d.Stats()
+	if err != nil {
+		return nil, err
+	}
+	orphans, err := d.OrphanCount()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &StatsSnapshot{
+		Day:     now.Format("2006-01-02"),
+		Files:   stats.FileCount,
+		Objects: stats.ObjectCount,
+		Traits:  stats.TraitCount,
+		Refs:    stats.RefCount,
+		Orphans: orphans,
+	}
+	_, err = d.db.Exec(`
+		INSERT OR REPLACE INTO stats_snapshots (day, files, objects, traits, refs, orphans, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, snapshot.Day, snapshot.Files, snapshot.Objects, snapshot.Traits, snapshot.Refs, snapshot.Orphans, now.Unix())
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// StatsSnapshotsSince returns the snapshots from sinceDay on, oldest first,
+// preceded by the latest snapshot before sinceDay when there is one, so the
+// series starts from the counts as they stood at the start of the window.
+func (d *Database) StatsSnapshotsSince(sinceDay string) ([]StatsSnapshot, error) {
+	rows, err := d.db.Query(`
+		SELECT day, files, objects, traits, refs, orphans FROM (
+			SELECT * FROM (
+				SELECT * FROM stats_snapshots WHERE day < ? ORDER BY day DESC LIMIT 1
+			)
+			UNION ALL
+			SELECT * FROM stats_snapshots WHERE day >= ?
+		)
+		ORDER BY day
+	`, sinceDay, sinceDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []StatsSnapshot
+	for rows.Next() {
+		var s StatsSnapshot
+		if err := rows.Scan(&s.Day, &s.Files, &s.Objects, &s.Traits, &s.Refs, &s.Orphans); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, rows.Err()
+}
+
+// MostEditedFiles returns the files with the most recorded edits from
+// sinceDay on, most edited first.
+func (d *Database) MostEditedFiles(sinceDay string, limit int) ([]FileEditCount, error) {
+	rows, err := d.db.Query(`
+		SELECT e.file_path, COALESCE((SELECT MIN(o.id) FROM objects o WHERE o.file_path = e.file_path), ''), SUM(e.edits) AS edits
+		FROM file_edits e
+		WHERE e.day >= ?
+		GROUP BY e.file_path
+		ORDER BY edits DESC, e.file_path
+		LIMIT ?
+	`, sinceDay, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []FileEditCount
+	for rows.Next() {
+		var f FileEditCount
+		if err := rows.Scan(&f.FilePath, &f.ObjectID, &f.Edits); err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, rows.Err()
+}
+
+// MostReferencedObjects returns the objects with the most references from
+// other files, most referenced first.
+func (d *Database) MostReferencedObjects(limit int) ([]ReferenceCount, error) {
+	rows, err := d.db.Query(`
+		SELECT o.id, o.file_path, COUNT(*) AS refs
+		FROM (`+incomingRefsSQL+`) i
+		JOIN objects o ON o.id = i.id
+		WHERE i.file_path != o.file_path
+		GROUP BY o.id
+		ORDER BY refs DESC, o.id
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects []ReferenceCount
+	for rows.Next() {
+		var r ReferenceCount
+		if err := rows.Scan(&r.ObjectID, &r.FilePath, &r.References); err != nil {
+			return nil, err
+		}
+		objects = append(objects, r)
+	}
+	return objects, rows.Err()
+}
@@ -0,0 +1,80 @@
+package index
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func TestActivity_EditsSnapshotsAndReferences(t *testing.T) {
+	t.Parallel()
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	sch := schema.New()
+	doc := func(path, id string, refs ...string) *parser.ParsedDocument {
+		d := &parser.ParsedDocument{
+			FilePath: path,
+			Objects:  []*parser.ParsedObject{{ID: id, ObjectType: "page", LineStart: 1}},
+		}
+		for _, target := range refs {
+			d.Refs = append(d.Refs, &parser.ParsedRef{SourceID: id, TargetRaw: target, Line: 2})
+		}
+		return d
+	}
+	day1 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local).Unix()
+	day2 := time.Date(2026, 3, 2, 12, 0, 0, 0, time.Local).Unix()
+	for _, step := range []struct {
+		doc   *parser.ParsedDocument
+		mtime int64
+	}{
+		{doc("hub.md", "hub"), day1},
+		{doc("a.md", "a", "hub"), day1},
+		{doc("b.md", "b", "hub", "b"), day1},
+		// Re-indexing with a newer mtime is an edit; the same mtime is not.
+		{doc("a.md", "a", "hub"), day2},
+		{doc("a.md", "a", "hub"), day2},
+	} {
+		if err := db.IndexDocumentWithMtime(step.doc, sch, step.mtime); err != nil {
+			t.Fatalf("failed to index %s: %v", step.doc.FilePath, err)
+		}
+	}
+
+	edited, err := db.MostEditedFiles("2026-03-01", 10)
+	if err != nil {
+		t.Fatalf("MostEditedFiles: %v", err)
+	}
+	if len(edited) != 1 || edited[0].FilePath != "a.md" || edited[0].ObjectID != "a" || edited[0].Edits != 1 {
+		t.Fatalf("MostEditedFiles = %#v, want one edit of a.md", edited)
+	}
+
+	referenced, err := db.MostReferencedObjects(10)
+	if err != nil {
+		t.Fatalf("MostReferencedObjects: %v", err)
+	}
+	if len(referenced) != 1 || referenced[0].ObjectID != "hub" || referenced[0].References != 2 {
+		t.Fatalf("MostReferencedObjects = %#v, want hub with 2 references (self-references excluded)", referenced)
+	}
+
+	for _, day := range []time.Time{time.Date(2026, 2, 20, 9, 0, 0, 0, time.Local), time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)} {
+		snapshot, err := db.RecordStatsSnapshot(day)
+		if err != nil {
+			t.Fatalf("RecordStatsSnapshot: %v", err)
+		}
+		if snapshot.Objects != 3 || snapshot.Orphans != 2 {
+			t.Fatalf("snapshot = %#v, want 3 objects and 2 orphans (a and b)", snapshot)
+		}
+	}
+	series, err := db.StatsSnapshotsSince("2026-03-01")
+	if err != nil {
+		t.Fatalf("StatsSnapshotsSince: %v", err)
+	}
+	if len(series) != 2 || series[0].Day != "2026-02-20" || series[1].Day != "2026-03-02" {
+		t.Fatalf("series = %#v, want the last snapshot before the window then the one inside it", series)
+	}
+}
@@ -0,0 +1,136 @@
+package index
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Capability names reported by Capabilities.Missing.
+const (
+	CapabilityFTS5   = "fts5"
+	CapabilityRegexp = "regexp"
+)
+
+// Capabilities describes optional SQLite features available to the index.
+//
+// Raven's bundled driver ships FTS5 and registers a REGEXP function, but
+// stock SQLite builds may lack either. When a capability is missing, search
+// and content() fall back to LIKE matching and matches() accepts only simple
+// patterns, so results are less precise but queries still run.
+type Capabilities struct {
+	FTS5   bool
+	Regexp bool
+}
+
+// FullCapabilities reports every optional feature as available.
+func FullCapabilities() Capabilities {
+	return Capabilities{FTS5: true, Regexp: true}
+}
+
+// DetectCapabilities probes the connection for optional SQLite features.
+func DetectCapabilities(db *sql.DB) Capabilities {
+	var caps Capabilities
+
+	var fts5 int
+	if err := db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5); err == nil && fts5 == 1 {
+		caps.FTS5 = true
+	} else {
+		// Some builds load FTS5 as an extension without the compile option.
+		caps.FTS5 = probeFTS5Module(db)
+	}
+
+	var matched int
+	if err := db.QueryRow("SELECT 'raven' REGEXP 'rav'").Scan(&matched); err == nil {
+		caps.Regexp = true
+	}
+
+	return caps
+}
+
+func probeFTS5Module(db *sql.DB) bool {
+	var name string
+	err := db.QueryRow("SELECT name FROM pragma_module_list WHERE name = 'fts5'").Scan(&name)
+	return err == nil && name == "fts5"
+}
+
+// Missing returns the names of unavailable capabilities, in a stable order.
+func (c Capabilities) Missing() []string {
+	var missing []string
+	if !c.FTS5 {
+		missing = append(missing, CapabilityFTS5)
+	}
+	if !c.Regexp {
+		missing = append(missing, CapabilityRegexp)
+	}
+	return missing
+}
+
+// Capabilities returns the optional SQLite features detected when the
+// database was opened.
+func (d *Database) Capabilities() Capabilities {
+	return d.caps
+}
+
+// BuildLikeSearchCondition builds a LIKE-based stand-in for an FTS5 MATCH
+// query. Every search term must appear (case-insensitively) in at least one
+// of columns. Quoted phrases are kept together; FTS operators, parentheses,
+// and trailing prefix wildcards are dropped.
+//
+// It is used when FTS5 is unavailable. An empty query matches nothing.
+func BuildLikeSearchCondition(columns []string, userQuery string) (string, []interface{}) {
+	terms := likeSearchTerms(userQuery)
+	if len(terms) == 0 || len(columns) == 0 {
+		return "0", nil
+	}
+
+	var clauses []string
+	var args []interface{}
+	for _, term := range terms {
+		pattern := "%" + escapeLike(term) + "%"
+		alternatives := make([]string, 0, len(columns))
+		for _, col := range columns {
+			alternatives = append(alternatives, fmt.Sprintf("%s LIKE ? ESCAPE '\\'", col))
+			args = append(args, pattern)
+		}
+		clauses = append(clauses, "("+strings.Join(alternatives, " OR ")+")")
+	}
+	return "(" + strings.Join(clauses, " AND ") + ")", args
+}
+
+// likeSearchTerms splits an FTS-style query into plain search terms.
+func likeSearchTerms(q string) []string {
+	var terms []string
+	add := func(term string) {
+		term = strings.TrimSpace(strings.TrimSuffix(strings.Trim(term, "()"), "*"))
+		switch term {
+		case "", "AND", "OR", "NOT", "NEAR":
+			return
+		}
+		terms = append(terms, term)
+	}
+
+	var cur strings.Builder
+	inQuotes := false
+	for _, r := range q {
+		switch {
+		case r == '"':
+			// Either end of a phrase closes the current term.
+			add(cur.String())
+			cur.Reset()
+			inQuotes = !inQuotes
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\n'):
+			add(cur.String())
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	add(cur.String())
+	return terms
+}
+
+func escapeLike(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s)
+}
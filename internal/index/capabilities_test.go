@@ -0,0 +1,104 @@
+package index
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func TestDetectCapabilities(t *testing.T) {
+	t.Parallel()
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	// The bundled driver compiles in FTS5. REGEXP is registered by the query
+	// package, which this test binary does not import.
+	caps := db.Capabilities()
+	if !caps.FTS5 {
+		t.Error("expected FTS5 to be detected")
+	}
+	if caps.Regexp {
+		t.Error("expected REGEXP to be unavailable without the query package")
+	}
+	if got := caps.Missing(); !reflect.DeepEqual(got, []string{CapabilityRegexp}) {
+		t.Errorf("Missing() = %v, want [regexp]", got)
+	}
+}
+
+func TestLikeSearchTerms(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "meeting notes", want: []string{"meeting", "notes"}},
+		{query: `"team meeting" retro`, want: []string{"team meeting", "retro"}},
+		{query: "meet* AND (notes OR docs)", want: []string{"meet", "notes", "docs"}},
+		{query: "   ", want: nil},
+	}
+	for _, tt := range tests {
+		if got := likeSearchTerms(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("likeSearchTerms(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchFallsBackToLikeWithoutFTS5(t *testing.T) {
+	t.Parallel()
+	sqlDB, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	db, err := openInMemory(sqlDB, Capabilities{})
+	if err != nil {
+		t.Fatalf("failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	var sqlText string
+	if err := db.db.QueryRow(`SELECT sql FROM sqlite_master WHERE name = 'fts_content'`).Scan(&sqlText); err != nil {
+		t.Fatalf("read fts_content DDL: %v", err)
+	}
+	if sqlText == "" || sqlText[:12] != "CREATE TABLE" {
+		t.Fatalf("fts_content DDL = %q, want a plain table", sqlText)
+	}
+
+	for path, content := range map[string]string{
+		"notes/retro.md":  "# Sprint Retro\n\nThe team meeting ran long.\n",
+		"notes/budget.md": "# Budget\n\nNumbers for 100% of the quarter.\n",
+	} {
+		doc, err := parser.ParseDocument(content, path, "")
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", path, err)
+		}
+		if err := db.IndexDocument(doc, schema.New()); err != nil {
+			t.Fatalf("failed to index %s: %v", path, err)
+		}
+	}
+
+	results, err := db.Search(`"team meeting"`, 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) == 0 || results[0].ObjectID != "notes/retro" {
+		t.Fatalf("Search(team meeting) = %+v, want notes/retro first", results)
+	}
+
+	// Title-only matches count, and LIKE wildcards in the query are literal.
+	if results, err := db.Search("budget", 10); err != nil || len(results) == 0 {
+		t.Fatalf("Search(budget) = %+v, %v; want a title match", results, err)
+	}
+	if results, err := db.Search("1_0%", 10); err != nil || len(results) != 0 {
+		t.Fatalf("Search(1_0%%) = %+v, %v; want no matches", results, err)
+	}
+
+	if _, err := db.SearchWithType("meeting", "page", 10); err != nil {
+		t.Fatalf("SearchWithType: %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -27,6 +28,9 @@ type Database struct {
 	db              *sql.DB
 	dailyDirectory  string
 	autoResolveRefs bool
+	caps            Capabilities
+	store           *encryptedStore // Set when the index is encrypted at rest
+	externalPaths   []string        // ID prefixes of absent sparse directories
 }
 
 var (
@@ -42,7 +46,19 @@ func (d *Database) DB() *sql.DB {
 }
 
 // Open opens or creates the database.
+// When raven.yaml sets index.encrypt, the index is decrypted into memory and
+// written back encrypted on Close. An encrypted index holds the index lock
+// while open, so a second Open waits until the first is closed.
 func Open(vaultPath string) (*Database, error) {
+	settings, err := loadEncryptionSettings(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+	if settings.enabled {
+		d, _, err := openEncrypted(vaultPath, settings, false)
+		return d, err
+	}
+
 	dbDir := filepath.Join(vaultPath, ".raven")
 	if err := os.MkdirAll(dbDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create .raven directory: %w", err)
@@ -58,7 +74,7 @@ func Open(vaultPath string) (*Database, error) {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	d := &Database{db: db, dailyDirectory: "daily", autoResolveRefs: true}
+	d := &Database{db: db, dailyDirectory: "daily", autoResolveRefs: true, caps: DetectCapabilities(db), externalPaths: loadExternalPaths(vaultPath)}
 	if err := d.initialize(isNewDB); err != nil {
 		db.Close()
 		return nil, err
@@ -73,6 +89,15 @@ func OpenWithRebuild(vaultPath string) (*Database, bool, error) {
 	dbDir := filepath.Join(vaultPath, ".raven")
 	dbPath := filepath.Join(dbDir, "index.db")
 
+	settings, err := loadEncryptionSettings(vaultPath)
+	if err != nil {
+		return nil, false, err
+	}
+	if settings.enabled {
+		// The encrypted index takes the lock itself and holds it until Close.
+		return openEncrypted(vaultPath, settings, true)
+	}
+
 	lock, err := acquireIndexLock(dbDir)
 	if err != nil {
 		return nil, false, err
@@ -174,8 +199,13 @@ func OpenInMemory() (*Database, error) {
 	if err != nil {
 		return nil, err
 	}
+	return openInMemory(db, DetectCapabilities(db))
+}
 
-	d := &Database{db: db, dailyDirectory: "daily", autoResolveRefs: true}
+// openInMemory initializes an in-memory database with the given capabilities,
+// letting tests exercise the fallbacks used on SQLite builds without FTS5.
+func openInMemory(db *sql.DB, caps Capabilities) (*Database, error) {
+	d := &Database{db: db, dailyDirectory: "daily", autoResolveRefs: true, caps: caps}
 	if err := d.initialize(true); err != nil {
 		db.Close()
 		return nil, err
@@ -184,8 +214,15 @@ func OpenInMemory() (*Database, error) {
 	return d, nil
 }
 
-// Close closes the database.
+// Close closes the database. An encrypted index is written back to disk first.
 func (d *Database) Close() error {
+	if d.store != nil {
+		saveErr := d.store.close()
+		if err := d.db.Close(); err != nil {
+			return err
+		}
+		return saveErr
+	}
 	return d.db.Close()
 }
 
@@ -219,7 +256,63 @@ func (d *Database) Analyze() error {
 // v12: Added first-class sections table
 // v13: Removed object hierarchy/heading columns; objects are file-backed only
 // v14: Added subtree line ranges for heading-derived sections
-const CurrentDBVersion = 14
+// v15: Added raw_value column to traits for schema-normalized values
+// v16: Added file_created column to objects for created() query predicates
+// v17: Added headings and code columns to fts_content for scoped content() search
+// v18: Added issue_refs table for Jira and GitHub issue mentions
+// v19: Added fields column to fts_content and fts_traits table for trait content() search
+// v20: Added trait_occurrences table for recurring date traits
+const CurrentDBVersion = 20
+
+// ftsContentDDL returns the DDL for the full-text search tables. Without FTS5
+// they are plain tables with the same columns, searched with LIKE instead.
+func (d *Database) ftsContentDDL() string {
+	if !d.caps.FTS5 {
+		return `
+		-- Plain-table fallback for content search (SQLite built without FTS5)
+		CREATE TABLE IF NOT EXISTS fts_content (
+			object_id TEXT,
+			title TEXT,
+			content TEXT,
+			headings TEXT,
+			code TEXT,
+			fields TEXT,
+			file_path TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_fts_content_object ON fts_content(object_id);
+		CREATE INDEX IF NOT EXISTS idx_fts_content_file ON fts_content(file_path);
+
+		CREATE TABLE IF NOT EXISTS fts_traits (
+			trait_id TEXT,
+			content TEXT,
+			file_path TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_fts_traits_trait ON fts_traits(trait_id);
+		CREATE INDEX IF NOT EXISTS idx_fts_traits_file ON fts_traits(file_path);
+	`
+	}
+	return `
+		-- Full-text search index for content search
+		CREATE VIRTUAL TABLE IF NOT EXISTS fts_content USING fts5(
+			object_id,
+			title,
+			content,
+			headings,
+			code,
+			fields,
+			file_path UNINDEXED,
+			tokenize='porter unicode61'
+		);
+
+		-- Full-text search index for trait content() predicates
+		CREATE VIRTUAL TABLE IF NOT EXISTS fts_traits USING fts5(
+			trait_id UNINDEXED,
+			content,
+			file_path UNINDEXED,
+			tokenize='porter unicode61'
+		);
+	`
+}
 
 // initialize creates the database schema.
 func (d *Database) initialize(isNewDB bool) error {
@@ -248,6 +341,7 @@ func (d *Database) initialize(isNewDB bool) error {
 			line_start INTEGER NOT NULL,
 			alias TEXT,                 -- Optional alias for reference resolution
 			file_mtime INTEGER,         -- File modification time from filesystem (Unix timestamp)
+			file_created INTEGER,       -- Earliest known creation time (git history or first indexed mtime)
 			indexed_at INTEGER          -- When this row was written to the index
 		);
 
@@ -273,6 +367,7 @@ func (d *Database) initialize(isNewDB bool) error {
 			parent_object_id TEXT NOT NULL,
 			trait_type TEXT NOT NULL,
 			value TEXT,                          -- Single trait value (NULL for boolean traits)
+			raw_value TEXT,                      -- Value as written, when schema normalization changed it
 			content TEXT NOT NULL,
 			line_number INTEGER NOT NULL,
 			indexed_at INTEGER          -- When this row was written to the index
@@ -298,7 +393,7 @@ func (d *Database) initialize(isNewDB bool) error {
 			field_name TEXT NOT NULL,
 			target_id TEXT,
 			target_raw TEXT NOT NULL,
-			resolution_status TEXT NOT NULL, -- resolved | ambiguous | missing
+			resolution_status TEXT NOT NULL, -- resolved | ambiguous | missing | external
 			file_path TEXT NOT NULL,
 			line_number INTEGER
 		);
@@ -361,15 +456,59 @@ func (d *Database) initialize(isNewDB bool) error {
 		CREATE INDEX IF NOT EXISTS idx_date_index_date ON date_index(date);
 		CREATE INDEX IF NOT EXISTS idx_date_index_file ON date_index(file_path);
 
-		-- Full-text search index for content search
-		CREATE VIRTUAL TABLE IF NOT EXISTS fts_content USING fts5(
-			object_id,
-			title,
-			content,
-			file_path UNINDEXED,
-			tokenize='porter unicode61'
+		-- Expanded occurrences of recurring date traits (@due(2026-03-01, repeat:monthly))
+		-- within a window around the time the file was indexed
+		CREATE TABLE IF NOT EXISTS trait_occurrences (
+			trait_id TEXT NOT NULL,
+			date TEXT NOT NULL,              -- YYYY-MM-DD
+			file_path TEXT NOT NULL,
+			PRIMARY KEY (trait_id, date)
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_trait_occurrences_date ON trait_occurrences(date);
+		CREATE INDEX IF NOT EXISTS idx_trait_occurrences_file ON trait_occurrences(file_path);
+
+		-- Issue tracker mentions (Jira keys, GitHub issue URLs) in content
+		CREATE TABLE IF NOT EXISTS issue_refs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			source_id TEXT NOT NULL,
+			provider TEXT NOT NULL,          -- jira | github
+			issue_key TEXT NOT NULL,         -- PROJ-123 or owner/repo#45
+			url TEXT,
+			file_path TEXT NOT NULL,
+			line_number INTEGER
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_issue_refs_file ON issue_refs(file_path);
+		CREATE INDEX IF NOT EXISTS idx_issue_refs_key ON issue_refs(provider, issue_key);
+
+		-- Focus working set (rvn focus, @focus). User state rather than
+		-- derived data, so reindexing leaves it alone.
+		CREATE TABLE IF NOT EXISTS focus (
+			object_id TEXT PRIMARY KEY,
+			added_at INTEGER NOT NULL
+		);
+
+		-- Daily vault statistics and per-file edit counts (rvn vault stats
+		-- --since). History rather than derived data, so reindexing leaves
+		-- them alone.
+		CREATE TABLE IF NOT EXISTS stats_snapshots (
+			day TEXT PRIMARY KEY,       -- Local date, YYYY-MM-DD
+			files INTEGER NOT NULL,
+			objects INTEGER NOT NULL,
+			traits INTEGER NOT NULL,
+			refs INTEGER NOT NULL,
+			orphans INTEGER NOT NULL,
+			recorded_at INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS file_edits (
+			file_path TEXT NOT NULL,
+			day TEXT NOT NULL,          -- Local date of the edit, YYYY-MM-DD
+			edits INTEGER NOT NULL,
+			PRIMARY KEY (file_path, day)
 		);
 	`
+	schema += d.ftsContentDDL()
 
 	_, err := d.db.Exec(schema)
 	if err != nil {
@@ -444,6 +583,12 @@ func (d *Database) IndexDocumentWithMtime(doc *parser.ParsedDocument, sch *schem
 	}
 	defer tx.Rollback()
 
+	// Carry the known creation time across re-indexing of the same file.
+	var existingCreated, existingMtime sql.NullInt64
+	if err := tx.QueryRow(`SELECT MIN(file_created), MAX(file_mtime) FROM objects WHERE file_path = ?`, doc.FilePath).Scan(&existingCreated, &existingMtime); err != nil {
+		return err
+	}
+
 	// Delete existing data for this file
 	if err := deleteByFilePath(tx, doc.FilePath); err != nil {
 		return err
@@ -453,8 +598,18 @@ func (d *Database) IndexDocumentWithMtime(doc *parser.ParsedDocument, sch *schem
 
 	// Use provided mtime or fall back to current time
 	mtime := indexedMtime(now, fileMtime)
+	created := mtime
+	if existingCreated.Valid && existingCreated.Int64 > 0 && existingCreated.Int64 < created {
+		created = existingCreated.Int64
+	}
 
-	if err := indexObjects(tx, doc, mtime, now); err != nil {
+	if existingMtime.Valid && existingMtime.Int64 > 0 && mtime > existingMtime.Int64 {
+		if err := recordFileEdit(tx, doc.FilePath, mtime); err != nil {
+			return err
+		}
+	}
+
+	if err := indexObjects(tx, doc, mtime, created, now); err != nil {
 		return err
 	}
 	if err := indexSections(tx, doc, now); err != nil {
@@ -472,6 +627,12 @@ func (d *Database) IndexDocumentWithMtime(doc *parser.ParsedDocument, sch *schem
 	if err := indexDates(tx, doc, sch); err != nil {
 		return err
 	}
+	if err := indexTraitOccurrences(tx, doc, sch, time.Now()); err != nil {
+		return err
+	}
+	if err := indexIssueRefs(tx, doc); err != nil {
+		return err
+	}
 	if err := indexFTS(tx, doc, sch); err != nil {
 		return err
 	}
@@ -540,10 +701,10 @@ func nullableString(value string) interface{} {
 	return value
 }
 
-func indexObjects(tx *sql.Tx, doc *parser.ParsedDocument, mtime, indexedAt int64) error {
+func indexObjects(tx *sql.Tx, doc *parser.ParsedDocument, mtime, created, indexedAt int64) error {
 	objStmt, err := tx.Prepare(`
-		INSERT INTO objects (id, file_path, type, fields, line_start, alias, file_mtime, indexed_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO objects (id, file_path, type, fields, line_start, alias, file_mtime, file_created, indexed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
@@ -572,6 +733,7 @@ func indexObjects(tx *sql.Tx, doc *parser.ParsedDocument, mtime, indexedAt int64
 			obj.LineStart,
 			alias,
 			mtime,
+			created,
 			indexedAt,
 		)
 		if err != nil {
@@ -615,23 +777,35 @@ func indexSections(tx *sql.Tx, doc *parser.ParsedDocument, indexedAt int64) erro
 
 func indexInlineTraits(tx *sql.Tx, doc *parser.ParsedDocument, sch *schema.Schema, indexedAt int64) error {
 	traitStmt, err := tx.Prepare(`
-		INSERT INTO traits (id, file_path, parent_object_id, trait_type, value, content, line_number, indexed_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO traits (id, file_path, parent_object_id, trait_type, value, raw_value, content, line_number, indexed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
 	}
 	defer traitStmt.Close()
 
+	ftsStmt, err := tx.Prepare(`
+		INSERT INTO fts_traits (trait_id, content, file_path)
+		VALUES (?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer ftsStmt.Close()
+
 	for _, indexedTrait := range indexedTraits(doc, sch) {
 		trait := indexedTrait.Trait
 
 		// Get value as string, applying schema defaults for bare traits
-		var valueStr interface{}
-		if trait.Value != nil {
-			if s := traitValueForIndex(*trait.Value); s != "" {
+		var valueStr, rawValue interface{}
+		if indexedTrait.Value != nil {
+			if s := traitValueForIndex(*indexedTrait.Value); s != "" {
 				valueStr = s
 			}
+			if indexedTrait.Normalized {
+				rawValue = traitValueForIndex(*trait.Value)
+			}
 		} else {
 			// Bare trait with no value - check schema for default
 			valueStr = getTraitDefault(sch, trait.TraitType)
@@ -643,6 +817,7 @@ func indexInlineTraits(tx *sql.Tx, doc *parser.ParsedDocument, sch *schema.Schem
 			trait.ParentObjectID,
 			trait.TraitType,
 			valueStr,
+			rawValue,
 			trait.Content,
 			trait.Line,
 			indexedAt,
@@ -650,6 +825,9 @@ func indexInlineTraits(tx *sql.Tx, doc *parser.ParsedDocument, sch *schema.Schem
 		if execErr != nil {
 			return execErr
 		}
+		if _, execErr := ftsStmt.Exec(indexedTrait.ID, trait.Content, doc.FilePath); execErr != nil {
+			return execErr
+		}
 	}
 
 	return nil
@@ -671,20 +849,35 @@ func traitValueForIndex(value schema.FieldValue) string {
 type indexedTrait struct {
 	ID    string
 	Trait *parser.ParsedTrait
+	// Value is the trait value after schema normalization (nil for bare traits).
+	Value *schema.FieldValue
+	// Normalized is true when Value differs from the value as written.
+	Normalized bool
 }
 
 func indexedTraits(doc *parser.ParsedDocument, sch *schema.Schema) []indexedTrait {
 	indexed := make([]indexedTrait, 0, len(doc.Traits))
 	for _, trait := range doc.Traits {
+		var traitDef *schema.TraitDefinition
 		if sch != nil {
-			if _, defined := sch.Traits[trait.TraitType]; !defined {
+			def, defined := sch.Traits[trait.TraitType]
+			if !defined {
 				continue
 			}
+			traitDef = def
 		}
-		indexed = append(indexed, indexedTrait{
+		entry := indexedTrait{
 			ID:    fmt.Sprintf("%s:trait:%d", doc.FilePath, len(indexed)),
 			Trait: trait,
-		})
+			Value: trait.Value,
+		}
+		if trait.Value != nil {
+			if normalized, changed := traitDef.NormalizeFieldValue(*trait.Value); changed {
+				entry.Value = &normalized
+				entry.Normalized = true
+			}
+		}
+		indexed = append(indexed, entry)
 	}
 	return indexed
 }
@@ -709,6 +902,9 @@ func indexRefs(tx *sql.Tx, doc *parser.ParsedDocument, sch *schema.Schema) error
 		allRefs = mergeRefs(allRefs, schemaRefs)
 	}
 
+	// Plain-text date mentions link the source to the matching daily note.
+	allRefs = append(allRefs, doc.DateMentions...)
+
 	for _, ref := range allRefs {
 		_, err = refStmt.Exec(
 			ref.SourceID,
@@ -810,8 +1006,8 @@ func indexDates(tx *sql.Tx, doc *parser.ParsedDocument, sch *schema.Schema) erro
 	for _, indexedTrait := range indexedTraits(doc, sch) {
 		trait := indexedTrait.Trait
 		// For single-value traits, check if the value is a date
-		if trait.Value != nil {
-			if dateStr := extractDateString(*trait.Value); dateStr != "" {
+		if indexedTrait.Value != nil {
+			if dateStr := extractDateString(*indexedTrait.Value); dateStr != "" {
 				_, err = dateStmt.Exec(dateStr, "trait", indexedTrait.ID, trait.TraitType, doc.FilePath)
 				if err != nil {
 					return err
@@ -823,10 +1019,56 @@ func indexDates(tx *sql.Tx, doc *parser.ParsedDocument, sch *schema.Schema) erro
 	return nil
 }
 
+// Recurring date traits are expanded from a year before indexing time up to
+// two years after it. Occurrences outside the window are not queryable until
+// the file is reindexed.
+const (
+	occurrenceLookbackYears = 1
+	occurrenceHorizonYears  = 2
+)
+
+func indexTraitOccurrences(tx *sql.Tx, doc *parser.ParsedDocument, sch *schema.Schema, now time.Time) error {
+	occurrenceStmt, err := tx.Prepare(`
+		INSERT OR IGNORE INTO trait_occurrences (trait_id, date, file_path)
+		VALUES (?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer occurrenceStmt.Close()
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	through := today.AddDate(occurrenceHorizonYears, 0, 0)
+	for _, indexedTrait := range indexedTraits(doc, sch) {
+		if indexedTrait.Value == nil {
+			continue
+		}
+		value, ok := indexedTrait.Value.AsString()
+		if !ok {
+			continue
+		}
+		recurring, ok := dates.ParseRecurringDate(value)
+		if !ok {
+			continue
+		}
+		from := today
+		if !recurring.Date.IsZero() {
+			from = today.AddDate(-occurrenceLookbackYears, 0, 0)
+		}
+		for _, day := range recurring.Occurrences(from, through) {
+			if _, err := occurrenceStmt.Exec(indexedTrait.ID, day.Format(dates.DateLayout), doc.FilePath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func indexFTS(tx *sql.Tx, doc *parser.ParsedDocument, sch *schema.Schema) error {
 	ftsStmt, err := tx.Prepare(`
-		INSERT INTO fts_content (object_id, title, content, file_path)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO fts_content (object_id, title, content, headings, code, fields, file_path)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return err
@@ -836,6 +1078,14 @@ func indexFTS(tx *sql.Tx, doc *parser.ParsedDocument, sch *schema.Schema) error
 	// Pre-split content into lines for section extraction
 	lines := strings.Split(doc.RawContent, "\n")
 
+	// Object rows carry every heading in the file; section rows carry their own.
+	headingTitles := make([]string, 0, len(doc.Sections))
+	for _, section := range doc.Sections {
+		headingTitles = append(headingTitles, section.Title)
+	}
+	headings := strings.Join(headingTitles, "\n")
+	code := extractFencedCode(strings.Split(doc.Body, "\n"))
+
 	for _, obj := range doc.Objects {
 		// Get title: check schema name_field first, then "title" field, then object ID
 		title := ""
@@ -859,7 +1109,7 @@ func indexFTS(tx *sql.Tx, doc *parser.ParsedDocument, sch *schema.Schema) error
 			title = obj.ID
 		}
 
-		_, err = ftsStmt.Exec(obj.ID, title, doc.Body, doc.FilePath)
+		_, err = ftsStmt.Exec(obj.ID, title, doc.Body, headings, code, fieldsSearchText(obj.Fields), doc.FilePath)
 		if err != nil {
 			return err
 		}
@@ -867,7 +1117,8 @@ func indexFTS(tx *sql.Tx, doc *parser.ParsedDocument, sch *schema.Schema) error
 
 	for _, section := range doc.Sections {
 		content := extractSectionContent(lines, section.LineStart, section.LineEnd)
-		_, err = ftsStmt.Exec(section.ID, section.Title, content, doc.FilePath)
+		sectionCode := extractFencedCode(strings.Split(content, "\n"))
+		_, err = ftsStmt.Exec(section.ID, section.Title, content, section.Title, sectionCode, "", doc.FilePath)
 		if err != nil {
 			return err
 		}
@@ -876,6 +1127,24 @@ func indexFTS(tx *sql.Tx, doc *parser.ParsedDocument, sch *schema.Schema) error
 	return nil
 }
 
+// fieldsSearchText joins an object's frontmatter values, one per line in
+// field-name order, for content(field:"...") search.
+func fieldsSearchText(fields map[string]schema.FieldValue) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		if value := traitValueForIndex(fields[name]); value != "" {
+			values = append(values, value)
+		}
+	}
+	return strings.Join(values, "\n")
+}
+
 // extractSectionContent extracts direct content for a section from the given line range.
 // lineStart and lineEnd are 1-indexed. If lineEnd is nil, extracts to end of file.
 func extractSectionContent(lines []string, lineStart int, lineEnd *int) string {
@@ -897,6 +1166,22 @@ func extractSectionContent(lines []string, lineStart int, lineEnd *int) string {
 	return strings.Join(lines[start:end], "\n")
 }
 
+// extractFencedCode returns the lines inside fenced code blocks, excluding the
+// fence markers themselves.
+func extractFencedCode(lines []string) string {
+	var fence parser.FenceState
+	var code []string
+	for _, line := range lines {
+		if fence.UpdateFenceState(line) {
+			continue
+		}
+		if fence.InFence {
+			code = append(code, line)
+		}
+	}
+	return strings.Join(code, "\n")
+}
+
 func generatedDateObjectDate(obj *parser.ParsedObject) string {
 	if obj == nil || obj.ObjectType != "date" {
 		return ""
@@ -972,7 +1257,10 @@ func (d *Database) ClearAllData() error {
 		"DELETE FROM refs",
 		"DELETE FROM field_refs",
 		"DELETE FROM date_index",
+		"DELETE FROM trait_occurrences",
+		"DELETE FROM issue_refs",
 		"DELETE FROM fts_content",
+		"DELETE FROM fts_traits",
 		"DELETE FROM assets",
 	} {
 		if _, err := tx.Exec(stmt); err != nil {
@@ -1048,16 +1336,9 @@ func (d *Database) AllIndexedFilePaths() ([]string, error) {
 // RemoveDeletedFiles removes index entries for files that no longer exist on the filesystem.
 // Returns the list of removed file paths.
 func (d *Database) RemoveDeletedFiles(vaultPath string) ([]string, error) {
-	indexedPaths, err := d.AllIndexedFilePaths()
+	removed, err := d.MissingFiles(vaultPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get indexed paths: %w", err)
-	}
-
-	var removed []string
-	for _, relPath := range indexedPaths {
-		if fileMissing(filepath.Join(vaultPath, relPath)) {
-			removed = append(removed, relPath)
-		}
+		return nil, err
 	}
 	if err := d.RemoveFiles(removed); err != nil {
 		return nil, fmt.Errorf("failed to remove deleted files: %w", err)
@@ -1156,6 +1437,135 @@ type IndexStats struct {
 	AssetCount  int
 }
 
+// AuthorStats counts objects attributed to one author via the created_by and
+// modified_by frontmatter fields.
+type AuthorStats struct {
+	Author   string
+	Created  int
+	Modified int
+}
+
+// AuthorStats returns per-author attribution counts, sorted by author name.
+func (d *Database) AuthorStats() ([]AuthorStats, error) {
+	rows, err := d.db.Query(`
+		SELECT author, SUM(created), SUM(modified)
+		FROM (
+			SELECT json_extract(fields, '$.created_by') AS author, 1 AS created, 0 AS modified
+			FROM objects
+			WHERE json_type(fields, '$.created_by') = 'text'
+			UNION ALL
+			SELECT json_extract(fields, '$.modified_by'), 0, 1
+			FROM objects
+			WHERE json_type(fields, '$.modified_by') = 'text'
+		)
+		WHERE author != ''
+		GROUP BY author
+		ORDER BY author
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var authors []AuthorStats
+	for rows.Next() {
+		var stats AuthorStats
+		if err := rows.Scan(&stats.Author, &stats.Created, &stats.Modified); err != nil {
+			return nil, err
+		}
+		authors = append(authors, stats)
+	}
+	return authors, rows.Err()
+}
+
+// UsageStats summarizes how often a type or trait appears in the index.
+type UsageStats struct {
+	Count    int
+	LastUsed int64 // Latest modification time (Unix seconds) of a file using it; 0 if unknown
+}
+
+// TypeUsage returns object counts and latest file modification times keyed by type.
+func (d *Database) TypeUsage() (map[string]UsageStats, error) {
+	return d.usageStats(`
+		SELECT type, COUNT(*), COALESCE(MAX(file_mtime), 0)
+		FROM objects
+		GROUP BY type
+	`)
+}
+
+// TraitUsage returns trait instance counts and latest file modification times
+// keyed by trait type. Trait rows carry no mtime, so it comes from the objects
+// in the same file.
+func (d *Database) TraitUsage() (map[string]UsageStats, error) {
+	return d.usageStats(`
+		SELECT t.trait_type, COUNT(*), COALESCE(MAX(m.file_mtime), 0)
+		FROM traits t
+		LEFT JOIN (
+			SELECT file_path, MAX(file_mtime) AS file_mtime
+			FROM objects
+			GROUP BY file_path
+		) m ON m.file_path = t.file_path
+		GROUP BY t.trait_type
+	`)
+}
+
+// FieldRefTarget counts resolved ref field values by the type of object they
+// point at.
+type FieldRefTarget struct {
+	SourceType string
+	FieldName  string
+	TargetType string
+	Count      int
+	Example    string // One source object ID with such a value
+}
+
+// FieldRefTargets groups resolved ref field values by source type, field,
+// and target object type.
+func (d *Database) FieldRefTargets() ([]FieldRefTarget, error) {
+	rows, err := d.db.Query(`
+		SELECT s.type, fr.field_name, t.type, COUNT(*), MIN(fr.source_id)
+		FROM field_refs fr
+		JOIN objects s ON s.id = fr.source_id
+		JOIN objects t ON t.id = fr.target_id
+		WHERE fr.resolution_status = 'resolved'
+		GROUP BY s.type, fr.field_name, t.type
+		ORDER BY s.type, fr.field_name, t.type
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var targets []FieldRefTarget
+	for rows.Next() {
+		var target FieldRefTarget
+		if err := rows.Scan(&target.SourceType, &target.FieldName, &target.TargetType, &target.Count, &target.Example); err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, rows.Err()
+}
+
+func (d *Database) usageStats(query string) (map[string]UsageStats, error) {
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := make(map[string]UsageStats)
+	for rows.Next() {
+		var name string
+		var stats UsageStats
+		if err := rows.Scan(&name, &stats.Count, &stats.LastUsed); err != nil {
+			return nil, err
+		}
+		usage[name] = stats
+	}
+	return usage, rows.Err()
+}
+
 // AllObjectIDs returns all object IDs (for reference resolution).
 func (d *Database) AllObjectIDs() ([]string, error) {
 	return allObjectIDsFromDB(d.db)
@@ -1187,6 +1597,10 @@ type ResolverOptions struct {
 	// ExtraAssetIDs are additional asset IDs to include in the resolver.
 	// Useful for hypothetical asset moves.
 	ExtraAssetIDs []string
+
+	// ExternalPaths are ID prefixes of sparse directories absent from the
+	// local checkout. Database.Resolver fills them from raven.yaml when unset.
+	ExternalPaths []string
 }
 
 // Resolver builds the canonical resolver for this vault index.
@@ -1200,6 +1614,9 @@ type ResolverOptions struct {
 //
 // Use this method for all resolver creation to ensure consistent behavior.
 func (d *Database) Resolver(opts ResolverOptions) (*resolver.Resolver, error) {
+	if opts.ExternalPaths == nil {
+		opts.ExternalPaths = d.externalPaths
+	}
 	return BuildResolver(d.db, opts)
 }
 
@@ -1238,6 +1655,7 @@ func BuildResolver(db *sql.DB, opts ResolverOptions) (*resolver.Resolver, error)
 		Aliases:        aliases,
 		AliasMatches:   aliasMatches,
 		AssetIDs:       assetIDs,
+		ExternalPaths:  opts.ExternalPaths,
 	}
 	if opts.Schema != nil {
 		nameFieldMap, err := allNameFieldValuesFromDB(db, opts.Schema)
@@ -1537,6 +1955,30 @@ func (d *Database) FindDuplicateAliases() ([]DuplicateAlias, error) {
 }
 
 // Helper to convert FieldValue map to interface map for JSON serialization.
+// ObjectIDsWithFieldValue returns the objects of a type whose field, read as
+// text, equals value. Used to enforce unique fields.
+func (d *Database) ObjectIDsWithFieldValue(typeName, field, value string) ([]string, error) {
+	rows, err := d.db.Query(`
+		SELECT id FROM objects
+		WHERE type = ? AND TRIM(CAST(json_extract(fields, ?) AS TEXT)) = ?
+		ORDER BY id
+	`, typeName, `$."`+field+`"`, value)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 func fieldsToMap(fields map[string]schema.FieldValue) map[string]interface{} {
 	result := make(map[string]interface{}, len(fields))
 	for k, v := range fields {
@@ -1661,6 +2103,90 @@ func isFileStaleAgainstIndexedMtime(fullPath string, indexedMtime sql.NullInt64)
 	return false, checked, nil
 }
 
+// ApplyFileCreatedTimes lowers the recorded creation time of indexed files to
+// the given Unix timestamps (e.g. first git commit), keyed by vault-relative
+// file path. Existing earlier values are kept. Returns the rows updated.
+func (d *Database) ApplyFileCreatedTimes(created map[string]int64) (int64, error) {
+	if len(created) == 0 {
+		return 0, nil
+	}
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		UPDATE objects SET file_created = ?
+		WHERE file_path = ? AND (file_created IS NULL OR file_created > ?)
+	`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var updated int64
+	for filePath, ts := range created {
+		if ts <= 0 {
+			continue
+		}
+		res, err := stmt.Exec(ts, filePath, ts)
+		if err != nil {
+			return 0, err
+		}
+		if n, err := res.RowsAffected(); err == nil {
+			updated += n
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+// ObjectFileTime holds the indexed file timestamps of one object.
+type ObjectFileTime struct {
+	ID        string
+	Type      string
+	FilePath  string
+	LineStart int
+	Mtime     int64
+	Created   int64 // 0 when unknown
+}
+
+// ObjectFileTimes returns the indexed file timestamps of all objects of the
+// given types, ordered by ID.
+func (d *Database) ObjectFileTimes(types []string) ([]ObjectFileTime, error) {
+	if len(types) == 0 {
+		return nil, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(types)), ",")
+	args := make([]interface{}, len(types))
+	for i, typeName := range types {
+		args[i] = typeName
+	}
+	rows, err := d.db.Query(`
+		SELECT id, type, file_path, line_start, COALESCE(file_mtime, 0), COALESCE(file_created, 0)
+		FROM objects
+		WHERE type IN (`+placeholders+`)
+		ORDER BY id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ObjectFileTime
+	for rows.Next() {
+		var item ObjectFileTime
+		if err := rows.Scan(&item.ID, &item.Type, &item.FilePath, &item.LineStart, &item.Mtime, &item.Created); err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	return out, rows.Err()
+}
+
 // GetFileMtime returns the indexed mtime for a file, or 0 if not found.
 func (d *Database) GetFileMtime(filePath string) (int64, error) {
 	var mtime sql.NullInt64
@@ -1691,11 +2217,13 @@ type ReferenceResolutionResult struct {
 	Resolved   int // Number of references successfully resolved
 	Unresolved int // Number of references that couldn't be resolved
 	Ambiguous  int // Number of ambiguous references (multiple matches)
+	External   int // Number of references into absent sparse directories
 	Total      int // Total number of references processed
 
 	FieldResolved   int // Number of field refs successfully resolved
 	FieldUnresolved int // Number of field refs that couldn't be resolved
 	FieldAmbiguous  int // Number of ambiguous field refs (multiple matches)
+	FieldExternal   int // Number of field refs into absent sparse directories
 	FieldTotal      int // Total number of field refs processed
 }
 
@@ -1889,6 +2417,8 @@ func (d *Database) resolveRefBatch(res *resolver.Resolver, refs []refToResolve,
 				return err
 			}
 			result.Resolved++
+		} else if resolved.External {
+			result.External++
 		} else {
 			result.Unresolved++
 		}
@@ -1927,6 +2457,11 @@ func (d *Database) resolveFieldRefBatch(res *resolver.Resolver, refs []fieldRefT
 				return err
 			}
 			result.FieldResolved++
+		} else if resolved.External {
+			if _, err := stmt.Exec(nil, "external", ref.id); err != nil {
+				return err
+			}
+			result.FieldExternal++
 		} else {
 			if _, err := stmt.Exec(nil, "missing", ref.id); err != nil {
 				return err
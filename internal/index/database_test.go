@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/aidanlsb/raven/internal/filelock"
 	"github.com/aidanlsb/raven/internal/parser"
@@ -72,6 +73,49 @@ func TestDatabase(t *testing.T) {
 		}
 	})
 
+	t.Run("type and trait usage", func(t *testing.T) {
+		db, err := OpenInMemory()
+		if err != nil {
+			t.Fatalf("failed to open database: %v", err)
+		}
+		defer db.Close()
+
+		if _, err := db.db.Exec(`
+			INSERT INTO objects (id, file_path, type, fields, line_start, file_mtime) VALUES
+				('people/freya', 'people/freya.md', 'person', '{}', 1, 100),
+				('people/thor', 'people/thor.md', 'person', '{}', 1, 300),
+				('notes/a', 'notes/a.md', 'page', '{}', 1, 200);
+			INSERT INTO traits (id, file_path, parent_object_id, trait_type, value, content, line_number) VALUES
+				('t1', 'notes/a.md', 'notes/a', 'due', '2026-01-01', 'Ship', 3),
+				('t2', 'people/freya.md', 'people/freya', 'due', '2026-02-01', 'Call', 5),
+				('t3', 'orphan.md', 'orphan', 'todo', NULL, 'Task', 1);
+		`); err != nil {
+			t.Fatalf("seed database: %v", err)
+		}
+
+		types, err := db.TypeUsage()
+		if err != nil {
+			t.Fatalf("TypeUsage: %v", err)
+		}
+		if got := types["person"]; got.Count != 2 || got.LastUsed != 300 {
+			t.Errorf("person usage = %+v, want count 2 last used 300", got)
+		}
+		if _, ok := types["project"]; ok {
+			t.Errorf("unused type should not have usage stats")
+		}
+
+		traits, err := db.TraitUsage()
+		if err != nil {
+			t.Fatalf("TraitUsage: %v", err)
+		}
+		if got := traits["due"]; got.Count != 2 || got.LastUsed != 200 {
+			t.Errorf("due usage = %+v, want count 2 last used 200", got)
+		}
+		if got := traits["todo"]; got.Count != 1 || got.LastUsed != 0 {
+			t.Errorf("todo usage = %+v, want count 1 with unknown last used", got)
+		}
+	})
+
 	t.Run("index document", func(t *testing.T) {
 		db, err := OpenInMemory()
 		if err != nil {
@@ -966,6 +1010,63 @@ func TestTraitIDConsistency(t *testing.T) {
 	}
 }
 
+func TestIndexTraitOccurrencesExpandsRecurringDates(t *testing.T) {
+	t.Parallel()
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	testSchema := schema.New()
+	testSchema.Traits["due"] = &schema.TraitDefinition{Type: schema.FieldTypeDate}
+
+	rent := schema.Date("2026-01-31, repeat:monthly")
+	once := schema.Date("2026-03-15")
+	doc := &parser.ParsedDocument{
+		FilePath: "notes/rent.md",
+		Traits: []*parser.ParsedTrait{
+			{TraitType: "due", Value: &rent, Content: "pay rent", Line: 3, ParentObjectID: "notes/rent"},
+			{TraitType: "due", Value: &once, Content: "renew lease", Line: 4, ParentObjectID: "notes/rent"},
+		},
+	}
+
+	tx, err := db.db.Begin()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer tx.Rollback()
+	now := time.Date(2026, time.March, 10, 15, 0, 0, 0, time.UTC)
+	if err := indexTraitOccurrences(tx, doc, testSchema, now); err != nil {
+		t.Fatalf("indexTraitOccurrences: %v", err)
+	}
+
+	rows, err := tx.Query(`SELECT trait_id, date FROM trait_occurrences ORDER BY date`)
+	if err != nil {
+		t.Fatalf("query trait_occurrences: %v", err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var traitID, date string
+		if err := rows.Scan(&traitID, &date); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		if traitID != "notes/rent.md:trait:0" {
+			t.Fatalf("occurrence for %q, want only the recurring trait", traitID)
+		}
+		got = append(got, date)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("iterate: %v", err)
+	}
+
+	// 2026-01-31 through 2028-02-29 (two years past indexing), clamped to month ends.
+	if len(got) != 26 || got[0] != "2026-01-31" || got[1] != "2026-02-28" || got[len(got)-1] != "2028-02-29" {
+		t.Fatalf("occurrences = %v", got)
+	}
+}
+
 func TestDateIndexTraitIDsTrackIndexedTraitOrder(t *testing.T) {
 	t.Parallel()
 	db, err := OpenInMemory()
@@ -1088,6 +1189,241 @@ func TestDateIndexTraitIDsTrackIndexedTraitOrder(t *testing.T) {
 	}
 }
 
+func TestIndexNormalizesTraitValues(t *testing.T) {
+	t.Parallel()
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	sch := schema.New()
+	sch.Traits["priority"] = &schema.TraitDefinition{
+		Type:   schema.FieldTypeEnum,
+		Values: []string{"low", "high"},
+		Normalize: &schema.TraitNormalization{
+			Lowercase: true,
+			Synonyms:  map[string]string{"hi": "high"},
+		},
+	}
+	sch.Traits["due"] = &schema.TraitDefinition{
+		Type:      schema.FieldTypeDate,
+		Normalize: &schema.TraitNormalization{Dates: true},
+	}
+
+	hi := schema.String("Hi")
+	low := schema.String("low")
+	due := schema.String("2025/3/15")
+	doc := &parser.ParsedDocument{
+		FilePath: "notes/plan.md",
+		Objects: []*parser.ParsedObject{
+			{ID: "notes/plan", ObjectType: "note", Fields: map[string]schema.FieldValue{}, LineStart: 1},
+		},
+		Traits: []*parser.ParsedTrait{
+			{TraitType: "priority", Value: &hi, Content: "ship it", Line: 2, ParentObjectID: "notes/plan"},
+			{TraitType: "priority", Value: &low, Content: "tidy up", Line: 3, ParentObjectID: "notes/plan"},
+			{TraitType: "due", Value: &due, Content: "deadline", Line: 4, ParentObjectID: "notes/plan"},
+		},
+	}
+	if err := db.IndexDocument(doc, sch); err != nil {
+		t.Fatalf("failed to index document: %v", err)
+	}
+
+	high := "high"
+	traits, err := db.QueryTraits("priority", &high)
+	if err != nil {
+		t.Fatalf("QueryTraits: %v", err)
+	}
+	if len(traits) != 1 || traits[0].RawValue == nil || *traits[0].RawValue != "Hi" {
+		t.Fatalf("expected one normalized trait with raw value 'Hi', got %#v", traits)
+	}
+
+	lowTrait, err := db.GetTrait("notes/plan.md:trait:1")
+	if err != nil {
+		t.Fatalf("GetTrait: %v", err)
+	}
+	if lowTrait == nil || lowTrait.RawValue != nil {
+		t.Fatalf("unchanged value should not record a raw value, got %#v", lowTrait)
+	}
+
+	dated, err := db.QueryDateIndex("2025-03-15")
+	if err != nil {
+		t.Fatalf("QueryDateIndex: %v", err)
+	}
+	if len(dated) != 1 || dated[0].SourceID != "notes/plan.md:trait:2" {
+		t.Fatalf("expected canonicalized due date in date index, got %#v", dated)
+	}
+}
+
+func TestIndexDateMentionsBacklinkDailyNote(t *testing.T) {
+	t.Parallel()
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	doc, err := parser.ParseDocumentWithOptions(
+		"# Planning\n\nLaunch review on 02/14/2026 and retro 2026-02-20.\n",
+		"notes/plan.md", "", &parser.ParseOptions{DateMentionFormats: []string{"MM/DD/YYYY"}},
+	)
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+	if err := db.IndexDocument(doc, schema.New()); err != nil {
+		t.Fatalf("failed to index document: %v", err)
+	}
+
+	backlinks, err := db.Backlinks("daily/2026-02-14")
+	if err != nil {
+		t.Fatalf("Backlinks: %v", err)
+	}
+	if len(backlinks) != 1 || backlinks[0].SourceID != "notes/plan#planning" {
+		t.Fatalf("expected one backlink from notes/plan#planning, got %#v", backlinks)
+	}
+	if backlinks[0].DisplayText == nil || *backlinks[0].DisplayText != "02/14/2026" {
+		t.Fatalf("expected mention text as display text, got %#v", backlinks[0].DisplayText)
+	}
+
+	other, err := db.Backlinks("daily/2026-02-20")
+	if err != nil {
+		t.Fatalf("Backlinks: %v", err)
+	}
+	if len(other) != 1 {
+		t.Fatalf("expected ISO mention backlink, got %#v", other)
+	}
+}
+
+func TestIndexFTSHeadingsAndCode(t *testing.T) {
+	t.Parallel()
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	content := "# Sprint Retro\n\nNotes here.\n\n## Queries\n\n```sql\nSELECT id FROM objects\n```\n"
+	doc, err := parser.ParseDocument(content, "notes/retro.md", "")
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+	if err := db.IndexDocument(doc, schema.New()); err != nil {
+		t.Fatalf("failed to index document: %v", err)
+	}
+
+	var headings, code string
+	if err := db.db.QueryRow(`SELECT headings, code FROM fts_content WHERE object_id = ?`, "notes/retro").Scan(&headings, &code); err != nil {
+		t.Fatalf("query object fts row: %v", err)
+	}
+	if headings != "Sprint Retro\nQueries" {
+		t.Errorf("object headings = %q, want both heading titles", headings)
+	}
+	if code != "SELECT id FROM objects" {
+		t.Errorf("object code = %q, want fenced block contents", code)
+	}
+
+	if err := db.db.QueryRow(`SELECT headings, code FROM fts_content WHERE object_id = ?`, "notes/retro#queries").Scan(&headings, &code); err != nil {
+		t.Fatalf("query section fts row: %v", err)
+	}
+	if headings != "Queries" || code != "SELECT id FROM objects" {
+		t.Errorf("section headings/code = %q/%q, want Queries/SELECT id FROM objects", headings, code)
+	}
+}
+
+func TestIndexFTSFieldsAndTraitContent(t *testing.T) {
+	t.Parallel()
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	content := "---\nstatus: reading\ntopics: [distributed systems, storage]\n---\n\n- @highlight Consensus is hard\n"
+	doc, err := parser.ParseDocument(content, "books/ddia.md", "")
+	if err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+	sch := schema.New()
+	sch.Traits["highlight"] = &schema.TraitDefinition{Type: schema.FieldTypeBool}
+	if err := db.IndexDocument(doc, sch); err != nil {
+		t.Fatalf("failed to index document: %v", err)
+	}
+
+	var fields string
+	if err := db.db.QueryRow(`SELECT fields FROM fts_content WHERE object_id = ?`, "books/ddia").Scan(&fields); err != nil {
+		t.Fatalf("query object fts row: %v", err)
+	}
+	if want := "reading\n[\"distributed systems\",\"storage\"]"; fields != want {
+		t.Errorf("object fields = %q, want %q", fields, want)
+	}
+
+	var traitID, traitContent string
+	if err := db.db.QueryRow(`SELECT trait_id, content FROM fts_traits WHERE file_path = ?`, "books/ddia.md").Scan(&traitID, &traitContent); err != nil {
+		t.Fatalf("query trait fts row: %v", err)
+	}
+	if traitID != "books/ddia.md:trait:0" || traitContent != "Consensus is hard" {
+		t.Errorf("trait fts row = %q/%q, want books/ddia.md:trait:0/Consensus is hard", traitID, traitContent)
+	}
+
+	if err := db.RemoveFile("books/ddia.md"); err != nil {
+		t.Fatalf("RemoveFile: %v", err)
+	}
+	var remaining int
+	if err := db.db.QueryRow(`SELECT COUNT(*) FROM fts_traits`).Scan(&remaining); err != nil {
+		t.Fatalf("count trait fts rows: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("fts_traits rows after remove = %d, want 0", remaining)
+	}
+}
+
+func TestIndexKeepsEarliestFileCreatedTime(t *testing.T) {
+	t.Parallel()
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	doc := &parser.ParsedDocument{
+		FilePath: "notes/plan.md",
+		Objects: []*parser.ParsedObject{
+			{ID: "notes/plan", ObjectType: "page", Fields: map[string]schema.FieldValue{}, LineStart: 1},
+		},
+	}
+	createdAt := func() int64 {
+		t.Helper()
+		var created int64
+		if err := db.DB().QueryRow(`SELECT file_created FROM objects WHERE id = 'notes/plan'`).Scan(&created); err != nil {
+			t.Fatalf("failed to read file_created: %v", err)
+		}
+		return created
+	}
+
+	if err := db.IndexDocumentWithMtime(doc, schema.New(), 1000); err != nil {
+		t.Fatalf("failed to index document: %v", err)
+	}
+	if err := db.IndexDocumentWithMtime(doc, schema.New(), 2000); err != nil {
+		t.Fatalf("failed to reindex document: %v", err)
+	}
+	if got := createdAt(); got != 1000 {
+		t.Fatalf("file_created after reindex = %d, want 1000", got)
+	}
+
+	if _, err := db.ApplyFileCreatedTimes(map[string]int64{"notes/plan.md": 500, "missing.md": 1}); err != nil {
+		t.Fatalf("ApplyFileCreatedTimes: %v", err)
+	}
+	if got := createdAt(); got != 500 {
+		t.Fatalf("file_created after git times = %d, want 500", got)
+	}
+	if _, err := db.ApplyFileCreatedTimes(map[string]int64{"notes/plan.md": 900}); err != nil {
+		t.Fatalf("ApplyFileCreatedTimes: %v", err)
+	}
+	if got := createdAt(); got != 500 {
+		t.Fatalf("later git time should not override earlier creation, got %d", got)
+	}
+}
+
 func TestTraitIDsStableAcrossReindexForMultilineParagraph(t *testing.T) {
 	t.Parallel()
 	db, err := OpenInMemory()
@@ -32,7 +32,7 @@ func ParseDateFilterWithOptions(filter string, fieldExpr string, opts DateFilter
 // - ok=false when value is not a date keyword/date literal
 // - err when value looks like a date input but is invalid
 func TryParseDateComparisonWithOptions(filter string, op string, fieldExpr string, opts DateFilterOptions) (condition string, args []interface{}, ok bool, err error) {
-	dateValue, isDate, err := resolveDateFilterValue(filter, opts)
+	dateValue, rangeEnd, isDate, err := resolveDateFilterValue(filter, opts)
 	if err != nil {
 		return "", nil, false, err
 	}
@@ -40,6 +40,10 @@ func TryParseDateComparisonWithOptions(filter string, op string, fieldExpr strin
 		return "", nil, false, nil
 	}
 
+	if rangeEnd != "" {
+		return dateRangeComparison(op, fieldExpr, dateValue, rangeEnd)
+	}
+
 	switch op {
 	case "=", "!=", "<", "<=", ">", ">=":
 		return fieldExpr + " " + op + " ?", []interface{}{dateValue}, true, nil
@@ -48,6 +52,24 @@ func TryParseDateComparisonWithOptions(filter string, op string, fieldExpr strin
 	}
 }
 
+// dateRangeComparison compares a field against an inclusive date range such
+// as within(3d): equality tests membership, and ordering compares against the
+// nearer end of the range.
+func dateRangeComparison(op, fieldExpr, start, end string) (string, []interface{}, bool, error) {
+	switch op {
+	case "=":
+		return "(" + fieldExpr + " >= ? AND " + fieldExpr + " <= ?)", []interface{}{start, end}, true, nil
+	case "!=":
+		return "(" + fieldExpr + " < ? OR " + fieldExpr + " > ?)", []interface{}{start, end}, true, nil
+	case "<", ">=":
+		return fieldExpr + " " + op + " ?", []interface{}{start}, true, nil
+	case ">", "<=":
+		return fieldExpr + " " + op + " ?", []interface{}{end}, true, nil
+	default:
+		return "", nil, false, fmt.Errorf("unsupported date comparison operator: %s", op)
+	}
+}
+
 func normalizeDateFilterOptions(opts DateFilterOptions) DateFilterOptions {
 	if opts.Now.IsZero() {
 		opts.Now = time.Now()
@@ -55,29 +77,42 @@ func normalizeDateFilterOptions(opts DateFilterOptions) DateFilterOptions {
 	return opts
 }
 
-func resolveDateFilterValue(filter string, opts DateFilterOptions) (string, bool, error) {
+// resolveDateFilterValue resolves a date literal, relative keyword, or date
+// function. rangeEnd is set only for range functions such as within(3d).
+func resolveDateFilterValue(filter string, opts DateFilterOptions) (value string, rangeEnd string, isDate bool, err error) {
 	normalized := strings.TrimSpace(filter)
 	if normalized == "" {
-		return "", false, fmt.Errorf("invalid date filter: %q", normalized)
+		return "", "", false, fmt.Errorf("invalid date filter: %q", normalized)
 	}
 
 	opts = normalizeDateFilterOptions(opts)
 
 	if dates.IsValidDate(normalized) {
-		return normalized, true, nil
+		return normalized, "", true, nil
 	}
 
 	relative, ok := dates.ResolveRelativeDateKeyword(normalized, opts.Now, time.Monday)
 	if ok && relative.Kind == dates.RelativeDateInstant {
-		return relative.Date.Format(dates.DateLayout), true, nil
+		return relative.Date.Format(dates.DateLayout), "", true, nil
+	}
+
+	relative, ok, err = dates.ResolveDateFunction(normalized, opts.Now, time.Monday)
+	if err != nil {
+		return "", "", false, err
+	}
+	if ok {
+		if relative.Kind == dates.RelativeDateRange {
+			return relative.Date.Format(dates.DateLayout), relative.End.Format(dates.DateLayout), true, nil
+		}
+		return relative.Date.Format(dates.DateLayout), "", true, nil
 	}
 
 	// If value looks like a date literal but isn't valid, surface an explicit error.
 	if looksLikeDateLiteral(normalized) {
-		return "", false, fmt.Errorf("invalid date filter: %q", normalized)
+		return "", "", false, fmt.Errorf("invalid date filter: %q", normalized)
 	}
 
-	return "", false, nil
+	return "", "", false, nil
 }
 
 func looksLikeDateLiteral(value string) bool {
@@ -0,0 +1,398 @@
+package index
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"modernc.org/sqlite"
+	"modernc.org/sqlite/vfs"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/secrets"
+)
+
+// EncryptedIndexFile is the name of the encrypted index inside .raven/.
+const EncryptedIndexFile = "index.db.enc"
+
+var (
+	// ErrIndexKeyMissing indicates index encryption is enabled but no passphrase is available.
+	ErrIndexKeyMissing = errors.New("index encryption key is not available")
+	// ErrIndexKeyInvalid indicates the encrypted index could not be decrypted.
+	ErrIndexKeyInvalid = errors.New("index could not be decrypted (wrong key or corrupted file)")
+)
+
+// Encrypted index file layout: magic | salt | nonce | AES-256-GCM ciphertext
+// (see secrets.Seal).
+var encryptedIndexMagic = []byte("RVNIDX01")
+
+// encryptionSettings is the index encryption configuration for a vault.
+type encryptionSettings struct {
+	enabled bool
+	keyEnv  string
+}
+
+// vaultIndexConfig mirrors the index section of raven.yaml (config.IndexConfig).
+// The index package reads it directly because config's tests import the
+// query package, which imports index.
+type vaultIndexConfig struct {
+	Index *struct {
+		Encrypt bool   `yaml:"encrypt"`
+		KeyEnv  string `yaml:"key_env"`
+	} `yaml:"index"`
+}
+
+// defaultIndexKeyEnv matches config.DefaultIndexKeyEnv.
+const defaultIndexKeyEnv = "RAVEN_INDEX_KEY"
+
+func loadEncryptionSettings(vaultPath string) (encryptionSettings, error) {
+	data, err := os.ReadFile(filepath.Join(vaultPath, "raven.yaml"))
+	if os.IsNotExist(err) {
+		return encryptionSettings{}, nil
+	}
+	var cfg vaultIndexConfig
+	if err == nil {
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		// Without readable config we cannot tell whether encryption is on.
+		// Refuse to fall back to a plaintext index if an encrypted one exists.
+		if _, statErr := os.Stat(filepath.Join(vaultPath, ".raven", EncryptedIndexFile)); statErr == nil {
+			return encryptionSettings{}, fmt.Errorf("cannot open encrypted index: failed to read raven.yaml: %w", err)
+		}
+		return encryptionSettings{}, nil
+	}
+	if cfg.Index == nil || !cfg.Index.Encrypt {
+		return encryptionSettings{}, nil
+	}
+
+	settings := encryptionSettings{
+		enabled: true,
+		keyEnv:  strings.TrimSpace(cfg.Index.KeyEnv),
+	}
+	if settings.keyEnv == "" {
+		settings.keyEnv = defaultIndexKeyEnv
+	}
+	return settings, nil
+}
+
+// passphrase reads the passphrase from the configured environment variable
+// or its command in the user's config.toml, never from the vault.
+func (s encryptionSettings) passphrase() ([]byte, error) {
+	value, err := secrets.Lookup(s.keyEnv)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIndexKeyMissing, err)
+	}
+	return value, nil
+}
+
+// encryptedStore persists an in-memory index to an encrypted file.
+//
+// The working database is a shared-cache in-memory SQLite database, so the
+// plaintext index never touches disk.
+// keeper pins one connection for the life of the Database; the in-memory
+// database is discarded when the last connection closes.
+//
+// The index lock is held from load until close, so concurrent processes
+// take turns instead of each writing back its own snapshot.
+type encryptedStore struct {
+	path       string
+	passphrase []byte
+	salt       []byte
+	keeper     *sql.Conn
+	lock       *indexLock
+	loadedHash [sha256.Size]byte
+	// fileHash is the hash of the encrypted file as last read or written,
+	// or zero when no file existed.
+	fileHash [sha256.Size]byte
+}
+
+type sqliteSerializer interface {
+	Serialize() ([]byte, error)
+}
+
+type sqliteRestorer interface {
+	NewRestore(srcURI string) (*sqlite.Backup, error)
+}
+
+// ErrIndexChanged indicates the encrypted index file was replaced on disk
+// while it was open, so writing it back would discard another writer's changes.
+var ErrIndexChanged = errors.New("encrypted index changed on disk since it was loaded")
+
+// encryptedIndexLockWait bounds how long an Open waits for another process
+// holding the encrypted index.
+var encryptedIndexLockWait = 30 * time.Second
+
+// openEncrypted opens the encrypted index for a vault. When rebuildIncompatible
+// is true, an index with an outdated schema version is discarded and wasRebuilt
+// reports it.
+func openEncrypted(vaultPath string, settings encryptionSettings, rebuildIncompatible bool) (*Database, bool, error) {
+	dbDir := filepath.Join(vaultPath, ".raven")
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		return nil, false, fmt.Errorf("failed to create .raven directory: %w", err)
+	}
+
+	passphrase, err := settings.passphrase()
+	if err != nil {
+		return nil, false, err
+	}
+
+	lock, err := waitIndexLock(dbDir, encryptedIndexLockWait)
+	if err != nil {
+		return nil, false, err
+	}
+
+	store := &encryptedStore{path: filepath.Join(dbDir, EncryptedIndexFile), passphrase: passphrase, lock: lock}
+	plaintext, err := store.load()
+	if err != nil {
+		lock.Release()
+		return nil, false, err
+	}
+
+	db, err := openSharedMemoryDB()
+	if err != nil {
+		lock.Release()
+		return nil, false, err
+	}
+
+	if store.keeper, err = db.Conn(context.Background()); err != nil {
+		db.Close()
+		lock.Release()
+		return nil, false, fmt.Errorf("failed to open in-memory index: %w", err)
+	}
+	closeAll := func() {
+		store.keeper.Close()
+		db.Close()
+		lock.Release()
+	}
+
+	isNewDB := plaintext == nil
+	wasRebuilt := false
+	if plaintext != nil {
+		if err := store.restore(plaintext); err != nil {
+			closeAll()
+			return nil, false, fmt.Errorf("failed to load encrypted index: %w", err)
+		}
+		if rebuildIncompatible && !isSchemaCompatible(db) {
+			// Start over with an empty in-memory database.
+			store.keeper.Close()
+			db.Close()
+			db, err = openSharedMemoryDB()
+			if err != nil {
+				lock.Release()
+				return nil, false, err
+			}
+			if store.keeper, err = db.Conn(context.Background()); err != nil {
+				db.Close()
+				lock.Release()
+				return nil, false, fmt.Errorf("failed to open in-memory index: %w", err)
+			}
+			isNewDB = true
+			wasRebuilt = true
+		}
+	}
+
+	// An encrypted index supersedes any plaintext copy from before
+	// encryption was enabled.
+	if err := removeDatabaseFiles(filepath.Join(dbDir, "index.db")); err != nil {
+		closeAll()
+		return nil, false, err
+	}
+
+	d := &Database{db: db, dailyDirectory: "daily", autoResolveRefs: true, caps: DetectCapabilities(db), store: store, externalPaths: loadExternalPaths(vaultPath)}
+	if err := d.initialize(isNewDB); err != nil {
+		closeAll()
+		return nil, false, err
+	}
+	return d, wasRebuilt, nil
+}
+
+// waitIndexLock acquires the index lock, retrying until timeout while another
+// process holds it.
+func waitIndexLock(dbDir string, timeout time.Duration) (*indexLock, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		lock, err := acquireIndexLock(dbDir)
+		if !errors.Is(err, ErrIndexLocked) || time.Now().After(deadline) {
+			return lock, err
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// openSharedMemoryDB opens a uniquely named shared-cache in-memory database.
+// read_uncommitted keeps readers from blocking writers across connections,
+// matching the concurrency the on-disk WAL index allows.
+func openSharedMemoryDB() (*sql.DB, error) {
+	name := make([]byte, 8)
+	if _, err := rand.Read(name); err != nil {
+		return nil, err
+	}
+	uri := "file:raven-index-" + hex.EncodeToString(name) + "?mode=memory&cache=shared"
+	db, err := sql.Open("sqlite", uri+"&_pragma=read_uncommitted(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open in-memory index: %w", err)
+	}
+	return db, nil
+}
+
+// restore copies a decrypted database image into the in-memory index. The
+// image is served to SQLite through a read-only in-memory VFS and copied with
+// the backup API, so the plaintext never touches disk.
+func (s *encryptedStore) restore(plaintext []byte) error {
+	vfsName, fsys, err := vfs.New(imageFS(plaintext))
+	if err != nil {
+		return err
+	}
+	defer fsys.Close()
+
+	return s.keeper.Raw(func(driverConn any) error {
+		restorer, ok := driverConn.(sqliteRestorer)
+		if !ok {
+			return errors.New("sqlite driver does not support restore")
+		}
+		backup, err := restorer.NewRestore("file:" + imageFileName + "?vfs=" + vfsName + "&immutable=1")
+		if err != nil {
+			return err
+		}
+		if _, err := backup.Step(-1); err != nil {
+			backup.Finish()
+			return err
+		}
+		return backup.Finish()
+	})
+}
+
+// imageFileName is the name the decrypted image has inside imageFS.
+const imageFileName = "index.db"
+
+// imageFS is a read-only file system holding one database image in memory.
+type imageFS []byte
+
+func (f imageFS) Open(name string) (fs.File, error) {
+	if name != imageFileName {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &imageFile{Reader: bytes.NewReader(f), size: int64(len(f))}, nil
+}
+
+type imageFile struct {
+	*bytes.Reader
+	size int64
+}
+
+func (f *imageFile) Stat() (fs.FileInfo, error) { return imageInfo(f.size), nil }
+func (f *imageFile) Close() error               { return nil }
+
+type imageInfo int64
+
+func (i imageInfo) Name() string       { return imageFileName }
+func (i imageInfo) Size() int64        { return int64(i) }
+func (i imageInfo) Mode() fs.FileMode  { return 0o400 }
+func (i imageInfo) ModTime() time.Time { return time.Time{} }
+func (i imageInfo) IsDir() bool        { return false }
+func (i imageInfo) Sys() any           { return nil }
+
+// load reads and decrypts the index file. It returns nil when no file exists.
+func (s *encryptedStore) load() ([]byte, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		s.salt, err = secrets.NewSalt()
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encrypted index: %w", err)
+	}
+	s.fileHash = sha256.Sum256(data)
+
+	plaintext, salt, err := secrets.Open(encryptedIndexMagic, s.passphrase, data)
+	if errors.Is(err, secrets.ErrInvalid) {
+		return nil, ErrIndexKeyInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.salt = salt
+	s.loadedHash = sha256.Sum256(plaintext)
+	return plaintext, nil
+}
+
+// save serializes the in-memory index and writes it encrypted, skipping the
+// write when nothing changed since it was loaded. It refuses to write if the
+// file on disk no longer matches what was loaded.
+func (s *encryptedStore) save() error {
+	var plaintext []byte
+	err := s.keeper.Raw(func(driverConn any) error {
+		var err error
+		plaintext, err = driverConn.(sqliteSerializer).Serialize()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to serialize index: %w", err)
+	}
+	hash := sha256.Sum256(plaintext)
+	if hash == s.loadedHash {
+		return nil
+	}
+
+	out, err := secrets.Seal(encryptedIndexMagic, s.passphrase, s.salt, plaintext)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkUnchanged(); err != nil {
+		return err
+	}
+	if err := atomicfile.WriteFile(s.path, out, 0o600); err != nil {
+		return fmt.Errorf("failed to write encrypted index: %w", err)
+	}
+	s.loadedHash = hash
+	s.fileHash = sha256.Sum256(out)
+	return nil
+}
+
+// checkUnchanged re-reads the encrypted file and fails if another writer
+// replaced it since it was loaded or last saved.
+func (s *encryptedStore) checkUnchanged() error {
+	data, err := os.ReadFile(s.path)
+	var current [sha256.Size]byte
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return fmt.Errorf("failed to read encrypted index: %w", err)
+	default:
+		current = sha256.Sum256(data)
+	}
+	if current != s.fileHash {
+		return ErrIndexChanged
+	}
+	return nil
+}
+
+// close saves and releases the in-memory index, then the index lock.
+func (s *encryptedStore) close() error {
+	err := s.save()
+	if closeErr := s.keeper.Close(); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	if releaseErr := s.lock.Release(); err == nil && releaseErr != nil {
+		err = releaseErr
+	}
+	return err
+}
+
+// IsEncrypted reports whether the index is stored encrypted at rest.
+func (d *Database) IsEncrypted() bool {
+	return d.store != nil
+}
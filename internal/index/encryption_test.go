@@ -0,0 +1,219 @@
+package index
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/secrets"
+)
+
+func writeEncryptedVaultConfig(t *testing.T, vaultPath, indexConfig string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(vaultPath, "raven.yaml"), []byte("index:\n"+indexConfig), 0o644); err != nil {
+		t.Fatalf("write raven.yaml: %v", err)
+	}
+}
+
+func indexSecretNote(t *testing.T, db *Database) {
+	t.Helper()
+	doc, err := parser.ParseDocument("# Project Nightjar\n\nAcquisition target is Bluefin Labs.\n", "notes/nightjar.md", "")
+	if err != nil {
+		t.Fatalf("parse document: %v", err)
+	}
+	if err := db.IndexDocument(doc, schema.New()); err != nil {
+		t.Fatalf("index document: %v", err)
+	}
+}
+
+func TestEncryptedIndexRoundTrip(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeEncryptedVaultConfig(t, vaultPath, "  encrypt: true\n  key_env: RAVEN_TEST_INDEX_KEY\n")
+	t.Setenv("RAVEN_TEST_INDEX_KEY", "correct horse battery staple")
+
+	// A plaintext index from before encryption was enabled is removed.
+	plainPath := filepath.Join(vaultPath, ".raven", "index.db")
+	if err := os.MkdirAll(filepath.Dir(plainPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(plainPath, []byte("stale plaintext"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := Open(vaultPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if !db.IsEncrypted() {
+		t.Fatal("expected encrypted index")
+	}
+	indexSecretNote(t, db)
+	if results, err := db.Search("Bluefin", 10); err != nil || len(results) == 0 {
+		t.Fatalf("Search on open encrypted index = %v, %v", results, err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := os.Stat(plainPath); !os.IsNotExist(err) {
+		t.Fatalf("plaintext index.db should be removed, stat err = %v", err)
+	}
+	encPath := filepath.Join(vaultPath, ".raven", EncryptedIndexFile)
+	data, err := os.ReadFile(encPath)
+	if err != nil {
+		t.Fatalf("read encrypted index: %v", err)
+	}
+	if bytes.Contains(data, []byte("Bluefin")) || bytes.Contains(data, []byte("nightjar")) {
+		t.Fatal("encrypted index file contains plaintext vault content")
+	}
+
+	reopened, err := Open(vaultPath)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	obj, err := reopened.GetObject("notes/nightjar")
+	if err != nil || obj == nil {
+		t.Fatalf("GetObject after reopen = %v, %v", obj, err)
+	}
+	if err := reopened.Close(); err != nil {
+		t.Fatalf("Close after reopen: %v", err)
+	}
+
+	t.Setenv("RAVEN_TEST_INDEX_KEY", "wrong key")
+	if _, err := Open(vaultPath); !errors.Is(err, ErrIndexKeyInvalid) {
+		t.Fatalf("Open with wrong key error = %v, want ErrIndexKeyInvalid", err)
+	}
+
+	t.Setenv("RAVEN_TEST_INDEX_KEY", "")
+	if _, err := Open(vaultPath); !errors.Is(err, ErrIndexKeyMissing) {
+		t.Fatalf("Open without key error = %v, want ErrIndexKeyMissing", err)
+	}
+}
+
+func TestEncryptedIndexKeyCommandAndRebuild(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeEncryptedVaultConfig(t, vaultPath, "  encrypt: true\n  key_env: RAVEN_TEST_UNSET_INDEX_KEY\n")
+	t.Setenv("RAVEN_TEST_UNSET_INDEX_KEY", "")
+	secrets.SetCommands(map[string]string{"RAVEN_TEST_UNSET_INDEX_KEY": "echo from-keyring"})
+	t.Cleanup(func() { secrets.SetCommands(nil) })
+
+	db, wasRebuilt, err := OpenWithRebuild(vaultPath)
+	if err != nil {
+		t.Fatalf("OpenWithRebuild: %v", err)
+	}
+	if wasRebuilt {
+		t.Fatal("new encrypted index should not report a rebuild")
+	}
+	indexSecretNote(t, db)
+	if _, err := db.db.Exec("UPDATE meta SET value = '1' WHERE key = 'version'"); err != nil {
+		t.Fatalf("downgrade version: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	db, wasRebuilt, err = OpenWithRebuild(vaultPath)
+	if err != nil {
+		t.Fatalf("OpenWithRebuild (outdated): %v", err)
+	}
+	defer db.Close()
+	if !wasRebuilt {
+		t.Fatal("expected an outdated encrypted index to be rebuilt")
+	}
+	if ids, err := db.AllObjectIDs(); err != nil || len(ids) != 0 {
+		t.Fatalf("rebuilt index objects = %v, %v; want empty", ids, err)
+	}
+}
+
+func TestEncryptedIndexConcurrentOpensKeepBothWrites(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeEncryptedVaultConfig(t, vaultPath, "  encrypt: true\n  key_env: RAVEN_TEST_INDEX_KEY\n")
+	t.Setenv("RAVEN_TEST_INDEX_KEY", "correct horse battery staple")
+
+	indexNote := func(db *Database, path, body string) {
+		t.Helper()
+		doc, err := parser.ParseDocument(body, path, "")
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		if err := db.IndexDocument(doc, schema.New()); err != nil {
+			t.Fatalf("index %s: %v", path, err)
+		}
+	}
+
+	first, err := Open(vaultPath)
+	if err != nil {
+		t.Fatalf("first Open: %v", err)
+	}
+
+	// The second Open must wait for the first to close, then see its writes.
+	type opened struct {
+		db  *Database
+		err error
+	}
+	secondCh := make(chan opened, 1)
+	go func() {
+		db, err := Open(vaultPath)
+		secondCh <- opened{db, err}
+	}()
+
+	indexNote(first, "notes/first.md", "# First\n\nWritten by the first process.\n")
+	select {
+	case got := <-secondCh:
+		t.Fatalf("second Open returned while the first was still open: %v", got.err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("first Close: %v", err)
+	}
+
+	got := <-secondCh
+	if got.err != nil {
+		t.Fatalf("second Open: %v", got.err)
+	}
+	second := got.db
+	indexNote(second, "notes/second.md", "# Second\n\nWritten by the second process.\n")
+	if err := second.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+
+	reopened, err := Open(vaultPath)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+	for _, id := range []string{"notes/first", "notes/second"} {
+		if obj, err := reopened.GetObject(id); err != nil || obj == nil {
+			t.Fatalf("GetObject(%q) after concurrent writes = %v, %v", id, obj, err)
+		}
+	}
+}
+
+func TestEncryptedIndexRefusesToOverwriteChangedFile(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeEncryptedVaultConfig(t, vaultPath, "  encrypt: true\n  key_env: RAVEN_TEST_INDEX_KEY\n")
+	t.Setenv("RAVEN_TEST_INDEX_KEY", "correct horse battery staple")
+
+	db, err := Open(vaultPath)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	indexSecretNote(t, db)
+
+	// Simulate a writer that ignored the index lock.
+	encPath := filepath.Join(vaultPath, ".raven", EncryptedIndexFile)
+	if err := os.WriteFile(encPath, []byte("replaced"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); !errors.Is(err, ErrIndexChanged) {
+		t.Fatalf("Close error = %v, want ErrIndexChanged", err)
+	}
+	if data, _ := os.ReadFile(encPath); string(data) != "replaced" {
+		t.Fatal("Close overwrote an index file changed by another writer")
+	}
+}
@@ -0,0 +1,85 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aidanlsb/raven/internal/paths"
+)
+
+// vaultSparseConfig mirrors the raven.yaml keys that decide which references
+// point outside the local checkout (config.VaultConfig.SparsePaths and the
+// type/page roots of config.DirectoriesConfig). See vaultIndexConfig for why
+// the index package reads raven.yaml itself.
+type vaultSparseConfig struct {
+	SparsePaths []string `yaml:"sparse_paths"`
+	Directories *struct {
+		Object string `yaml:"type"`
+		Page   string `yaml:"page"`
+		Pages  string `yaml:"pages"`
+	} `yaml:"directories"`
+}
+
+// loadExternalPaths returns the ID prefixes of sparse directories that are
+// absent from the local checkout. Each absent directory is listed as written
+// and, when it sits under the type or page root, with that root stripped so it
+// matches object IDs. Unreadable config yields no external paths; config
+// errors are reported by the commands that load raven.yaml.
+func loadExternalPaths(vaultPath string) []string {
+	data, err := os.ReadFile(filepath.Join(vaultPath, "raven.yaml"))
+	if err != nil {
+		return nil
+	}
+	var cfg vaultSparseConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil || len(cfg.SparsePaths) == 0 {
+		return nil
+	}
+
+	var roots []string
+	if cfg.Directories != nil {
+		objectRoot := paths.NormalizeDirRoot(cfg.Directories.Object)
+		pageRoot := paths.NormalizeDirRoot(cfg.Directories.Page)
+		if pageRoot == "" {
+			pageRoot = paths.NormalizeDirRoot(cfg.Directories.Pages)
+		}
+		if pageRoot == "" {
+			pageRoot = objectRoot
+		}
+		roots = []string{objectRoot, pageRoot}
+	}
+
+	var external []string
+	seen := make(map[string]bool)
+	add := func(prefix string) {
+		if prefix != "" && !seen[prefix] {
+			seen[prefix] = true
+			external = append(external, prefix)
+		}
+	}
+	for _, p := range cfg.SparsePaths {
+		p = paths.NormalizeVaultRelPath(p)
+		if p == "" || p == "." {
+			continue
+		}
+		root := paths.NormalizeDirRoot(p)
+		if info, err := os.Stat(filepath.Join(vaultPath, filepath.FromSlash(root))); err == nil && info.IsDir() {
+			continue
+		}
+		add(root)
+		for _, dirRoot := range roots {
+			if dirRoot != "" && strings.HasPrefix(root, dirRoot) {
+				add(strings.TrimPrefix(root, dirRoot))
+			}
+		}
+	}
+	return external
+}
+
+// SetExternalPaths overrides the ID prefixes treated as external during
+// reference resolution. Open loads them from raven.yaml's sparse_paths.
+func (d *Database) SetExternalPaths(prefixes []string) {
+	d.externalPaths = append([]string(nil), prefixes...)
+}
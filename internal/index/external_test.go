@@ -0,0 +1,96 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func TestLoadExternalPaths(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+	config := "directories:\n  type: objects/\nsparse_paths:\n  - objects/archive\n  - shared\n  - team/\n"
+	if err := os.WriteFile(filepath.Join(vaultPath, "raven.yaml"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(vaultPath, "shared"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got := loadExternalPaths(vaultPath)
+	want := []string{"objects/archive/", "archive/", "team/"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("loadExternalPaths() = %#v, want %#v", got, want)
+	}
+}
+
+func TestResolveReferences_ExternalRef(t *testing.T) {
+	t.Parallel()
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+	db.SetAutoResolveRefs(false)
+	db.SetExternalPaths([]string{"archive/"})
+
+	sch := schema.New()
+	sch.Types["note"] = &schema.TypeDefinition{
+		Fields: map[string]*schema.FieldDefinition{
+			"source": {Type: schema.FieldTypeRef},
+		},
+	}
+
+	doc := &parser.ParsedDocument{
+		FilePath: "notes/plan.md",
+		Objects: []*parser.ParsedObject{
+			{
+				ID:         "notes/plan",
+				ObjectType: "note",
+				Fields: map[string]schema.FieldValue{
+					"source": schema.String("archive/2019/retro"),
+				},
+				LineStart: 1,
+			},
+		},
+		Refs: []*parser.ParsedRef{
+			{SourceID: "notes/plan", TargetRaw: "archive/2019/retro", Line: 3},
+			{SourceID: "notes/plan", TargetRaw: "projects/missing", Line: 4},
+		},
+	}
+	if err := db.IndexDocument(doc, sch); err != nil {
+		t.Fatalf("failed to index doc: %v", err)
+	}
+
+	result, err := db.ResolveReferencesWithSchema("daily", sch)
+	if err != nil {
+		t.Fatalf("failed to resolve references: %v", err)
+	}
+	if result.External != 1 || result.Unresolved != 1 {
+		t.Errorf("refs external=%d unresolved=%d, want 1 and 1", result.External, result.Unresolved)
+	}
+	if result.FieldExternal != 1 || result.FieldUnresolved != 0 {
+		t.Errorf("field refs external=%d unresolved=%d, want 1 and 0", result.FieldExternal, result.FieldUnresolved)
+	}
+
+	var status string
+	err = db.db.QueryRow(`SELECT resolution_status FROM field_refs WHERE source_id = ? AND field_name = ?`, "notes/plan", "source").Scan(&status)
+	if err != nil {
+		t.Fatalf("failed to query field_refs: %v", err)
+	}
+	if status != "external" {
+		t.Errorf("resolution_status = %q, want external", status)
+	}
+
+	backlinks, err := db.Backlinks("archive/2019/retro")
+	if err != nil {
+		t.Fatalf("Backlinks() error: %v", err)
+	}
+	if len(backlinks) == 0 {
+		t.Errorf("Backlinks(archive/2019/retro) is empty, want the external ref")
+	}
+}
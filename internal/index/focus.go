@@ -0,0 +1,76 @@
+package index
+
+import (
+	"time"
+)
+
+// FocusEntry is one object in the focus working set.
+type FocusEntry struct {
+	ObjectID string
+	AddedAt  int64
+	Type     string // Empty when the object is no longer indexed
+	FilePath string
+}
+
+// AddFocus adds object IDs to the focus working set and returns the IDs that
+// were not already focused.
+func (d *Database) AddFocus(objectIDs []string) ([]string, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT OR IGNORE INTO focus (object_id, added_at) VALUES (?, ?)`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	now := time.Now().Unix()
+	var added []string
+	for _, id := range objectIDs {
+		res, err := stmt.Exec(id, now)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			added = append(added, id)
+		}
+	}
+	return added, tx.Commit()
+}
+
+// FocusEntries returns the focus working set in the order objects were added.
+func (d *Database) FocusEntries() ([]FocusEntry, error) {
+	rows, err := d.db.Query(`
+		SELECT f.object_id, f.added_at, COALESCE(o.type, ''), COALESCE(o.file_path, '')
+		FROM focus f
+		LEFT JOIN objects o ON o.id = f.object_id
+		ORDER BY f.added_at, f.rowid
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []FocusEntry
+	for rows.Next() {
+		var entry FocusEntry
+		if err := rows.Scan(&entry.ObjectID, &entry.AddedAt, &entry.Type, &entry.FilePath); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// ClearFocus empties the focus working set and returns how many objects it held.
+func (d *Database) ClearFocus() (int, error) {
+	res, err := d.db.Exec(`DELETE FROM focus`)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
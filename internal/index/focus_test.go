@@ -0,0 +1,69 @@
+package index
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func TestFocus_AddListClear(t *testing.T) {
+	t.Parallel()
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	doc := &parser.ParsedDocument{
+		FilePath: "projects/launch.md",
+		Objects: []*parser.ParsedObject{
+			{ID: "projects/launch", ObjectType: "project", LineStart: 1},
+		},
+	}
+	if err := db.IndexDocument(doc, schema.New()); err != nil {
+		t.Fatalf("failed to index doc: %v", err)
+	}
+
+	added, err := db.AddFocus([]string{"projects/launch", "notes/gone"})
+	if err != nil {
+		t.Fatalf("AddFocus: %v", err)
+	}
+	if !reflect.DeepEqual(added, []string{"projects/launch", "notes/gone"}) {
+		t.Fatalf("unexpected added IDs: %v", added)
+	}
+	added, err = db.AddFocus([]string{"projects/launch"})
+	if err != nil {
+		t.Fatalf("AddFocus again: %v", err)
+	}
+	if len(added) != 0 {
+		t.Fatalf("expected re-adding to be a no-op, got %v", added)
+	}
+
+	entries, err := db.FocusEntries()
+	if err != nil {
+		t.Fatalf("FocusEntries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if entries[0].ObjectID != "projects/launch" || entries[0].Type != "project" || entries[0].FilePath != "projects/launch.md" {
+		t.Fatalf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].ObjectID != "notes/gone" || entries[1].FilePath != "" {
+		t.Fatalf("expected unindexed entry to have no file, got %+v", entries[1])
+	}
+
+	// Reindexing leaves the focus set alone.
+	if err := db.ClearAllData(); err != nil {
+		t.Fatalf("ClearAllData: %v", err)
+	}
+	cleared, err := db.ClearFocus()
+	if err != nil {
+		t.Fatalf("ClearFocus: %v", err)
+	}
+	if cleared != 2 {
+		t.Fatalf("expected to clear 2 entries, got %d", cleared)
+	}
+}
@@ -24,15 +24,30 @@ func BuildFTSSearchQuery(userQuery string) string {
 //
 // The returned string is meant to be passed as the RHS of `fts_content MATCH ?`.
 func BuildFTSContentQuery(userQuery string) string {
+	return BuildFTSColumnQuery(FTSColumnContent, userQuery)
+}
+
+// Searchable fts_content columns for scoped content() predicates.
+const (
+	FTSColumnTitle    = "title"
+	FTSColumnContent  = "content"
+	FTSColumnHeadings = "headings"
+	FTSColumnCode     = "code"
+	FTSColumnFields   = "fields"
+)
+
+// BuildFTSColumnQuery builds a safe FTS5 MATCH query scoped to a single
+// fts_content column (one of the FTSColumn* constants).
+func BuildFTSColumnQuery(column, userQuery string) string {
 	q := strings.TrimSpace(userQuery)
 	if q == "" {
 		// Match nothing (FTS phrase query for empty string).
-		return `content:""`
+		return column + `:""`
 	}
 
 	// Wrap the entire expression so the column scope applies to boolean ops.
 	// (Without parentheses, `content: a OR b` scopes only `a` to the column.)
-	return "content: (" + sanitizeFTSQuery(q) + ")"
+	return column + ": (" + sanitizeFTSQuery(q) + ")"
 }
 
 // sanitizeFTSQuery quotes unquoted tokens containing FTS-special punctuation
@@ -0,0 +1,181 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// fileScopedTables are the tables whose rows belong to an indexed markdown
+// file. A row is orphaned when no object in objects shares its file_path.
+var fileScopedTables = []string{"sections", "traits", "refs", "field_refs", "date_index", "trait_occurrences", "issue_refs", "fts_content", "fts_traits"}
+
+// IntegrityCheck runs SQLite's integrity check and returns the problems it
+// reports. An empty result means the database file is sound.
+func (d *Database) IntegrityCheck() ([]string, error) {
+	rows, err := d.db.Query(`PRAGMA integrity_check`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var message string
+		if err := rows.Scan(&message); err != nil {
+			return nil, err
+		}
+		if message != "ok" {
+			problems = append(problems, message)
+		}
+	}
+	return problems, rows.Err()
+}
+
+// MissingFiles returns indexed file paths that no longer exist under
+// vaultPath.
+func (d *Database) MissingFiles(vaultPath string) ([]string, error) {
+	indexedPaths, err := d.AllIndexedFilePaths()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indexed paths: %w", err)
+	}
+
+	var missing []string
+	for _, relPath := range indexedPaths {
+		if fileMissing(filepath.Join(vaultPath, relPath)) {
+			missing = append(missing, relPath)
+		}
+	}
+	return missing, nil
+}
+
+// OrphanRowCounts returns, per table, the number of rows left behind for files
+// that have no indexed object. Tables without orphans are omitted.
+func (d *Database) OrphanRowCounts() (map[string]int, error) {
+	counts := make(map[string]int)
+	for _, table := range fileScopedTables {
+		var count int
+		err := d.db.QueryRow(`SELECT COUNT(*) FROM ` + table + ` WHERE file_path NOT IN (SELECT file_path FROM objects)`).Scan(&count)
+		if err != nil {
+			return nil, fmt.Errorf("count orphans in %s: %w", table, err)
+		}
+		if count > 0 {
+			counts[table] = count
+		}
+	}
+	return counts, nil
+}
+
+// RemoveOrphanRows deletes the rows OrphanRowCounts reports and returns how
+// many were removed.
+func (d *Database) RemoveOrphanRows() (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	removed := 0
+	for _, table := range fileScopedTables {
+		result, err := tx.Exec(`DELETE FROM ` + table + ` WHERE file_path NOT IN (SELECT file_path FROM objects)`)
+		if err != nil {
+			return 0, fmt.Errorf("delete orphans from %s: %w", table, err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		removed += int(n)
+	}
+	return removed, tx.Commit()
+}
+
+// FTSDesyncedFiles returns the indexed files whose full-text rows do not match
+// their objects, sections, and traits: a row is missing from fts_content or
+// fts_traits, or a full-text row has no matching object, section, or trait.
+// Files with no indexed object are reported by OrphanRowCounts instead.
+func (d *Database) FTSDesyncedFiles() ([]string, error) {
+	// FTS5 tables cannot be indexed by id, so compare the id sets in memory
+	// rather than probing the full-text tables once per row.
+	content, err := d.idsByFile(`SELECT object_id, file_path FROM fts_content`)
+	if err != nil {
+		return nil, err
+	}
+	indexed, err := d.idsByFile(`SELECT id, file_path FROM objects UNION ALL SELECT id, file_path FROM sections`)
+	if err != nil {
+		return nil, err
+	}
+	traitContent, err := d.idsByFile(`SELECT trait_id, file_path FROM fts_traits`)
+	if err != nil {
+		return nil, err
+	}
+	traits, err := d.idsByFile(`SELECT id, file_path FROM traits`)
+	if err != nil {
+		return nil, err
+	}
+	files, err := d.idsByFile(`SELECT DISTINCT file_path, file_path FROM objects`)
+	if err != nil {
+		return nil, err
+	}
+
+	desynced := make(map[string]bool)
+	for _, pair := range [][2]map[string]string{{indexed, content}, {content, indexed}, {traits, traitContent}, {traitContent, traits}} {
+		for id, filePath := range pair[0] {
+			if _, ok := pair[1][id]; !ok {
+				desynced[filePath] = true
+			}
+		}
+	}
+
+	var out []string
+	for filePath := range desynced {
+		if _, ok := files[filePath]; ok {
+			out = append(out, filePath)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// idsByFile runs a two-column (id, file_path) query and maps each id to its
+// file.
+func (d *Database) idsByFile(query string) (map[string]string, error) {
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]string)
+	for rows.Next() {
+		var id, filePath string
+		if err := rows.Scan(&id, &filePath); err != nil {
+			return nil, err
+		}
+		ids[id] = filePath
+	}
+	return ids, rows.Err()
+}
+
+// DeleteIndexFiles removes the vault's index database (plain or encrypted) so
+// the next reindex rebuilds it from scratch. It is the last resort for an
+// index SQLite cannot read.
+func DeleteIndexFiles(vaultPath string) error {
+	dbDir := filepath.Join(vaultPath, ".raven")
+	lock, err := acquireIndexLock(dbDir)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	if err := removeDatabaseFiles(filepath.Join(dbDir, "index.db")); err != nil {
+		return err
+	}
+	encrypted := filepath.Join(dbDir, EncryptedIndexFile)
+	if err := os.Remove(encrypted); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove %s: %w", encrypted, err)
+	}
+	return nil
+}
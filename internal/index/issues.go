@@ -0,0 +1,107 @@
+package index
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/parser"
+)
+
+func indexIssueRefs(tx *sql.Tx, doc *parser.ParsedDocument) error {
+	if len(doc.IssueRefs) == 0 {
+		return nil
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO issue_refs (source_id, provider, issue_key, url, file_path, line_number)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, ref := range doc.IssueRefs {
+		if _, err := stmt.Exec(ref.SourceID, ref.Provider, ref.Key, nullableString(ref.URL), doc.FilePath, ref.Line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IssueRefsForFile returns the distinct issues mentioned in a file, in order
+// of first mention.
+func (d *Database) IssueRefsForFile(filePath string) ([]model.IssueRef, error) {
+	rows, err := d.db.Query(`
+		SELECT provider, issue_key, COALESCE(url, '')
+		FROM issue_refs
+		WHERE file_path = ?
+		ORDER BY line_number, id
+	`, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refs []model.IssueRef
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var ref model.IssueRef
+		if err := rows.Scan(&ref.Provider, &ref.Key, &ref.URL); err != nil {
+			return nil, err
+		}
+		if id := ref.Provider + ":" + ref.Key; !seen[id] {
+			seen[id] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs, rows.Err()
+}
+
+// IssueRefsForObjects returns the distinct issues mentioned in each object's
+// file, keyed by object ID. IDs are looked up in batches, one query each.
+func (d *Database) IssueRefsForObjects(objectIDs []string) (map[string][]model.IssueRef, error) {
+	result := make(map[string][]model.IssueRef)
+	seen := make(map[string]bool)
+	for start := 0; start < len(objectIDs); start += backlinkCountBatchSize {
+		end := start + backlinkCountBatchSize
+		if end > len(objectIDs) {
+			end = len(objectIDs)
+		}
+		batch := objectIDs[start:end]
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(batch)), ",")
+		args := make([]interface{}, len(batch))
+		for i, id := range batch {
+			args[i] = id
+		}
+		rows, err := d.db.Query(`
+			SELECT o.id, i.provider, i.issue_key, COALESCE(i.url, '')
+			FROM issue_refs i
+			JOIN objects o ON o.file_path = i.file_path
+			WHERE o.id IN (`+placeholders+`)
+			ORDER BY o.id, i.line_number, i.id
+		`, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var objectID string
+			var ref model.IssueRef
+			if err := rows.Scan(&objectID, &ref.Provider, &ref.Key, &ref.URL); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if id := objectID + "\x00" + ref.Provider + ":" + ref.Key; !seen[id] {
+				seen[id] = true
+				result[objectID] = append(result[objectID], ref)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+	return result, nil
+}
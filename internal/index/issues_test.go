@@ -0,0 +1,66 @@
+package index
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func TestIssueRefs_FileAndObjectLookups(t *testing.T) {
+	t.Parallel()
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	doc := &parser.ParsedDocument{
+		FilePath: "projects/launch.md",
+		Objects: []*parser.ParsedObject{
+			{ID: "projects/launch", ObjectType: "page", LineStart: 1},
+		},
+		IssueRefs: []*parser.ParsedIssueRef{
+			{SourceID: "projects/launch", Provider: parser.IssueProviderJira, Key: "PROJ-2", URL: "https://acme.atlassian.net/browse/PROJ-2", Line: 3},
+			{SourceID: "projects/launch", Provider: parser.IssueProviderGitHub, Key: "acme/widgets#7", URL: "https://github.com/acme/widgets/issues/7", Line: 5},
+			{SourceID: "projects/launch", Provider: parser.IssueProviderJira, Key: "PROJ-2", URL: "https://acme.atlassian.net/browse/PROJ-2", Line: 9},
+		},
+	}
+	if err := db.IndexDocument(doc, schema.New()); err != nil {
+		t.Fatalf("failed to index doc: %v", err)
+	}
+
+	want := []model.IssueRef{
+		{Provider: parser.IssueProviderJira, Key: "PROJ-2", URL: "https://acme.atlassian.net/browse/PROJ-2"},
+		{Provider: parser.IssueProviderGitHub, Key: "acme/widgets#7", URL: "https://github.com/acme/widgets/issues/7"},
+	}
+
+	got, err := db.IssueRefsForFile("projects/launch.md")
+	if err != nil {
+		t.Fatalf("IssueRefsForFile: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("IssueRefsForFile() = %#v, want %#v", got, want)
+	}
+
+	byObject, err := db.IssueRefsForObjects([]string{"projects/launch", "projects/other"})
+	if err != nil {
+		t.Fatalf("IssueRefsForObjects: %v", err)
+	}
+	if !reflect.DeepEqual(byObject, map[string][]model.IssueRef{"projects/launch": want}) {
+		t.Errorf("IssueRefsForObjects() = %#v", byObject)
+	}
+
+	if err := db.RemoveFile("projects/launch.md"); err != nil {
+		t.Fatalf("RemoveFile: %v", err)
+	}
+	got, err = db.IssueRefsForFile("projects/launch.md")
+	if err != nil {
+		t.Fatalf("IssueRefsForFile after remove: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("IssueRefsForFile() after remove = %#v, want none", got)
+	}
+}
@@ -20,7 +20,7 @@ import (
 //   - Date filters: "today", "tomorrow", "yesterday", YYYY-MM-DD (also work with | and !)
 func (d *Database) QueryTraits(traitType string, valueFilter *string) ([]model.Trait, error) {
 	query := `
-		SELECT id, trait_type, value, content, file_path, line_number, parent_object_id
+		SELECT id, trait_type, value, raw_value, content, file_path, line_number, parent_object_id
 		FROM traits
 		WHERE trait_type = ?
 	`
@@ -48,7 +48,7 @@ func (d *Database) QueryTraits(traitType string, valueFilter *string) ([]model.T
 	var results []model.Trait
 	for rows.Next() {
 		var result model.Trait
-		if err := rows.Scan(&result.ID, &result.TraitType, &result.Value, &result.Content, &result.FilePath, &result.Line, &result.ParentObjectID); err != nil {
+		if err := rows.Scan(&result.ID, &result.TraitType, &result.Value, &result.RawValue, &result.Content, &result.FilePath, &result.Line, &result.ParentObjectID); err != nil {
 			return nil, err
 		}
 		results = append(results, result)
@@ -85,6 +85,37 @@ func (d *Database) GetSection(id string) (*model.Section, error) {
 	return nil, err
 }
 
+// SectionAtLine returns the innermost section of filePath whose heading
+// subtree contains line, or nil when the line precedes the first heading.
+func (d *Database) SectionAtLine(filePath string, line int) (*model.Section, error) {
+	var section model.Section
+	err := d.db.QueryRow(`
+		SELECT id, file_object_id, file_path, slug, title, level, line_start, line_end, subtree_line_end, parent_section_id
+		FROM sections
+		WHERE file_path = ? AND line_start <= ? AND (subtree_line_end IS NULL OR subtree_line_end >= ?)
+		ORDER BY line_start DESC, level DESC
+		LIMIT 1
+	`, filePath, line, line).Scan(
+		&section.ID,
+		&section.FileObjectID,
+		&section.FilePath,
+		&section.Slug,
+		&section.Title,
+		&section.Level,
+		&section.LineStart,
+		&section.LineEnd,
+		&section.SubtreeLineEnd,
+		&section.ParentSectionID,
+	)
+	if err == nil {
+		return &section, nil
+	}
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return nil, err
+}
+
 func parseFilterExpressionWithOptions(filter string, fieldExpr string, opts DateFilterOptions) (condition string, args []interface{}, err error) {
 	opts = normalizeDateFilterOptions(opts)
 
@@ -317,6 +348,63 @@ func (d *Database) Backlinks(targetID string) ([]model.Reference, error) {
 	return d.BacklinksWithRoots(targetID, "", "")
 }
 
+// backlinkCountBatchSize bounds the number of target IDs bound per
+// BacklinkCounts statement so large result sets stay under SQLite's
+// host-parameter limit.
+const backlinkCountBatchSize = 500
+
+// BacklinkCounts returns the number of incoming references for each target ID,
+// matching the same ref forms as Backlinks (exact and section-qualified).
+// Counts are computed with one grouped query per batch of IDs; IDs without
+// backlinks map to zero.
+func (d *Database) BacklinkCounts(targetIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(targetIDs))
+	for start := 0; start < len(targetIDs); start += backlinkCountBatchSize {
+		end := start + backlinkCountBatchSize
+		if end > len(targetIDs) {
+			end = len(targetIDs)
+		}
+		batch := targetIDs[start:end]
+
+		placeholders := make([]string, len(batch))
+		args := make([]interface{}, len(batch))
+		for i, id := range batch {
+			placeholders[i] = "(?)"
+			args[i] = id
+		}
+
+		query := `
+			WITH targets(id) AS (VALUES ` + strings.Join(placeholders, ", ") + `)
+			SELECT t.id, COUNT(r.id)
+			FROM targets t
+			LEFT JOIN refs r ON r.target_raw = t.id
+				OR r.target_raw LIKE t.id || '#%'
+				OR r.target_id = t.id
+				OR r.target_id LIKE t.id || '#%'
+			GROUP BY t.id`
+
+		rows, err := d.db.Query(query, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id string
+			var count int
+			if err := rows.Scan(&id, &count); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			counts[id] = count
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+	return counts, nil
+}
+
 // Outlinks returns all references made by the given source object.
 //
 // Includes refs whose source_id is a section of the source (source_id LIKE '<source>#%').
@@ -435,9 +523,9 @@ func (d *Database) GetObject(id string) (*model.Object, error) {
 func (d *Database) GetTrait(id string) (*model.Trait, error) {
 	var result model.Trait
 	err := d.db.QueryRow(
-		"SELECT id, trait_type, value, content, file_path, line_number, parent_object_id FROM traits WHERE id = ?",
+		"SELECT id, trait_type, value, raw_value, content, file_path, line_number, parent_object_id FROM traits WHERE id = ?",
 		id,
-	).Scan(&result.ID, &result.TraitType, &result.Value, &result.Content, &result.FilePath, &result.Line, &result.ParentObjectID)
+	).Scan(&result.ID, &result.TraitType, &result.Value, &result.RawValue, &result.Content, &result.FilePath, &result.Line, &result.ParentObjectID)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -481,6 +569,34 @@ func (d *Database) QueryDateIndex(date string) ([]DateIndexResult, error) {
 	return results, rows.Err()
 }
 
+// QueryDateIndexAnniversaries returns the object field dates of one type and
+// field that fall on the given month and day ("MM-DD") in any year up to and
+// including through ("YYYY-MM-DD").
+func (d *Database) QueryDateIndexAnniversaries(monthDay, objectType, fieldName, through string) ([]DateIndexResult, error) {
+	rows, err := d.db.Query(`
+		SELECT d.date, d.source_type, d.source_id, d.field_name, d.file_path
+		FROM date_index d
+		JOIN objects o ON o.id = d.source_id
+		WHERE d.source_type = 'object' AND substr(d.date, 6) = ? AND o.type = ? AND d.field_name = ? AND d.date <= ?
+		ORDER BY d.date, d.source_id
+	`, monthDay, objectType, fieldName, through)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []DateIndexResult
+	for rows.Next() {
+		var result DateIndexResult
+		if err := rows.Scan(&result.Date, &result.SourceType, &result.SourceID, &result.FieldName, &result.FilePath); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
 // UntypedPages returns file paths of all objects using the fallback 'page' type.
 func (d *Database) UntypedPages() ([]string, error) {
 	rows, err := d.db.Query(
@@ -503,6 +619,53 @@ func (d *Database) UntypedPages() ([]string, error) {
 	return results, rows.Err()
 }
 
+// ObjectContents returns the indexed body text of each file-level object,
+// keyed by object ID.
+func (d *Database) ObjectContents() (map[string]string, error) {
+	rows, err := d.db.Query(`
+		SELECT f.object_id, f.content
+		FROM fts_content f
+		JOIN objects o ON o.id = f.object_id
+		WHERE instr(o.id, '#') = 0
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contents := make(map[string]string)
+	for rows.Next() {
+		var id, content string
+		if err := rows.Scan(&id, &content); err != nil {
+			return nil, err
+		}
+		contents[id] = content
+	}
+	return contents, rows.Err()
+}
+
+// TraitTypesByObject returns the distinct trait types used in each object,
+// keyed by parent object ID.
+func (d *Database) TraitTypesByObject() (map[string][]string, error) {
+	rows, err := d.db.Query(
+		"SELECT DISTINCT parent_object_id, trait_type FROM traits ORDER BY parent_object_id, trait_type",
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	traits := make(map[string][]string)
+	for rows.Next() {
+		var objectID, traitType string
+		if err := rows.Scan(&objectID, &traitType); err != nil {
+			return nil, err
+		}
+		traits[objectID] = append(traits[objectID], traitType)
+	}
+	return traits, rows.Err()
+}
+
 // Search performs a full-text search across all content in the vault.
 // The query supports FTS5 query syntax:
 //   - Simple words: "meeting notes"
@@ -516,11 +679,10 @@ func (d *Database) Search(query string, limit int) ([]model.SearchMatch, error)
 		limit = 20
 	}
 
-	ftsQuery := BuildFTSSearchQuery(query)
-
 	// Use FTS5 match query with BM25 ranking
 	// Search both title and content columns
 	// The snippet function extracts matching content with context
+	match, args := d.searchMatch(query)
 	rows, err := d.db.Query(`
 		SELECT 
 			f.object_id,
@@ -531,14 +693,14 @@ func (d *Database) Search(query string, limit int) ([]model.SearchMatch, error)
 			s.line_start,
 			s.line_end,
 			s.subtree_line_end,
-			snippet(fts_content, 2, '»', '«', '...', 32) as snippet,
-			bm25(fts_content) as rank
+			`+match.snippet+` as snippet,
+			`+match.rank+` as rank
 		FROM fts_content f
 		LEFT JOIN sections s ON f.object_id = s.id
-		WHERE fts_content MATCH ?
-		ORDER BY rank
+		WHERE `+match.where+`
+		ORDER BY rank, f.object_id
 		LIMIT ?
-	`, ftsQuery, limit)
+	`, append(args, limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
@@ -587,27 +749,55 @@ func (d *Database) Search(query string, limit int) ([]model.SearchMatch, error)
 	return results, rows.Err()
 }
 
+// searchMatchSQL holds the SQL fragments that differ between FTS5 search and
+// the LIKE fallback used when FTS5 is unavailable.
+type searchMatchSQL struct {
+	where   string
+	snippet string
+	rank    string
+}
+
+// searchMatch returns the match condition, snippet, and rank expressions for a
+// search over the fts_content table aliased as f.
+func (d *Database) searchMatch(query string) (searchMatchSQL, []interface{}) {
+	if d.caps.FTS5 {
+		return searchMatchSQL{
+			where:   "fts_content MATCH ?",
+			snippet: "snippet(fts_content, 2, '»', '«', '...', 32)",
+			rank:    "bm25(fts_content)",
+		}, []interface{}{BuildFTSSearchQuery(query)}
+	}
+
+	// Without FTS5 there is no relevance ranking; every match ties at rank 0
+	// and falls back to ID order. The start of the body serves as the snippet.
+	where, args := BuildLikeSearchCondition([]string{"f.title", "f.content"}, query)
+	return searchMatchSQL{
+		where:   where,
+		snippet: "substr(f.content, 1, 160)",
+		rank:    "0.0",
+	}, args
+}
+
 // SearchWithType performs a full-text search filtered by object type.
 func (d *Database) SearchWithType(query string, objectType string, limit int) ([]model.SearchMatch, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 
-	ftsQuery := BuildFTSSearchQuery(query)
-
+	match, args := d.searchMatch(query)
 	rows, err := d.db.Query(`
 		SELECT 
 			f.object_id,
 			f.title,
 			f.file_path,
-			snippet(fts_content, 2, '»', '«', '...', 32) as snippet,
-			bm25(fts_content) as rank
+			`+match.snippet+` as snippet,
+			`+match.rank+` as rank
 		FROM fts_content f
 		JOIN objects o ON f.object_id = o.id
-		WHERE fts_content MATCH ? AND o.type = ?
-		ORDER BY rank
+		WHERE `+match.where+` AND o.type = ?
+		ORDER BY rank, f.object_id
 		LIMIT ?
-	`, ftsQuery, objectType, limit)
+	`, append(args, objectType, limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
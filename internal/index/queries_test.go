@@ -723,3 +723,85 @@ func TestAllSections(t *testing.T) {
 		t.Fatalf("parent section ID = %#v, want notes/alpha#overview", results[1].ParentSectionID)
 	}
 }
+
+func TestObjectContentsAndTraitTypes(t *testing.T) {
+	t.Parallel()
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.db.Exec(`
+		INSERT INTO objects (id, file_path, type, line_start, fields) VALUES
+			('notes/random', 'notes/random.md', 'page', 1, '{}'),
+			('notes/random#meeting', 'notes/random.md', 'meeting', 5, '{}');
+		INSERT INTO fts_content (object_id, title, content, headings, code, file_path) VALUES
+			('notes/random', 'Random', 'quarterly roadmap', '', '', 'notes/random.md'),
+			('notes/random#meeting', 'Meeting', 'standup', '', '', 'notes/random.md');
+		INSERT INTO traits (id, file_path, parent_object_id, trait_type, value, content, line_number) VALUES
+			('notes/random.md:trait:0', 'notes/random.md', 'notes/random', 'todo', NULL, 'a', 2),
+			('notes/random.md:trait:1', 'notes/random.md', 'notes/random', 'todo', NULL, 'b', 3),
+			('notes/random.md:trait:2', 'notes/random.md', 'notes/random', 'due', '2025-02-01', 'c', 4)
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	contents, err := db.ObjectContents()
+	if err != nil {
+		t.Fatalf("ObjectContents: %v", err)
+	}
+	if len(contents) != 1 || contents["notes/random"] != "quarterly roadmap" {
+		t.Errorf("contents = %#v, want only the file-level object", contents)
+	}
+
+	traits, err := db.TraitTypesByObject()
+	if err != nil {
+		t.Fatalf("TraitTypesByObject: %v", err)
+	}
+	if got := traits["notes/random"]; len(got) != 2 || got[0] != "due" || got[1] != "todo" {
+		t.Errorf("traits = %#v, want [due todo]", got)
+	}
+}
+
+func TestBacklinkCounts(t *testing.T) {
+	t.Parallel()
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.db.Exec(`
+		INSERT INTO refs (source_id, target_id, target_raw, file_path, line_number) VALUES
+			('daily/2025-02-01', 'people/freya', 'people/freya', 'daily/2025-02-01.md', 5),
+			('projects/bifrost', 'people/freya', 'freya', 'projects/bifrost.md', 10),
+			('projects/bifrost', 'people/freya#notes', 'freya#notes', 'projects/bifrost.md', 11),
+			('people/freya', 'projects/bifrost', 'projects/bifrost', 'people/freya.md', 2)
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	counts, err := db.BacklinkCounts([]string{"people/freya", "projects/bifrost", "people/thor"})
+	if err != nil {
+		t.Fatalf("BacklinkCounts: %v", err)
+	}
+	want := map[string]int{"people/freya": 3, "projects/bifrost": 1, "people/thor": 0}
+	for id, n := range want {
+		if counts[id] != n {
+			t.Errorf("counts[%q] = %d, want %d", id, counts[id], n)
+		}
+	}
+
+	for _, id := range []string{"people/freya", "projects/bifrost"} {
+		refs, err := db.Backlinks(id)
+		if err != nil {
+			t.Fatalf("Backlinks(%q): %v", id, err)
+		}
+		if len(refs) != counts[id] {
+			t.Errorf("Backlinks(%q) = %d refs, BacklinkCounts = %d", id, len(refs), counts[id])
+		}
+	}
+}
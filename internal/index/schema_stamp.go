@@ -0,0 +1,80 @@
+package index
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+const schemaFingerprintMetaKey = "schema_fingerprint"
+
+// SchemaFingerprint returns the fingerprint of the schema the index was last
+// built against. ok is false for indexes built before fingerprints were
+// recorded.
+func (d *Database) SchemaFingerprint() (fp schema.Fingerprint, ok bool, err error) {
+	var raw string
+	err = d.db.QueryRow(`SELECT value FROM meta WHERE key = ?`, schemaFingerprintMetaKey).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return schema.Fingerprint{}, false, nil
+	}
+	if err != nil {
+		return schema.Fingerprint{}, false, err
+	}
+	if err := json.Unmarshal([]byte(raw), &fp); err != nil {
+		// An unreadable stamp is treated as missing; the next reindex rewrites it.
+		return schema.Fingerprint{}, false, nil
+	}
+	return fp, true, nil
+}
+
+// SetSchemaFingerprint records the schema the index is now consistent with.
+func (d *Database) SetSchemaFingerprint(fp schema.Fingerprint) error {
+	data, err := json.Marshal(fp)
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`INSERT OR REPLACE INTO meta (key, value) VALUES (?, ?)`, schemaFingerprintMetaKey, string(data))
+	return err
+}
+
+// FilesAffectedBySchemaDrift returns the indexed files holding objects of the
+// drifted types or traits of the drifted trait names, sorted by path.
+func (d *Database) FilesAffectedBySchemaDrift(drift schema.Drift) ([]string, error) {
+	if drift.IsEmpty() {
+		return nil, nil
+	}
+
+	var clauses []string
+	var args []interface{}
+	if len(drift.Types) > 0 {
+		clauses = append(clauses, `SELECT file_path FROM objects WHERE type IN (`+strings.TrimSuffix(strings.Repeat("?,", len(drift.Types)), ",")+`)`)
+		for _, name := range drift.Types {
+			args = append(args, name)
+		}
+	}
+	if len(drift.Traits) > 0 {
+		clauses = append(clauses, `SELECT file_path FROM traits WHERE trait_type IN (`+strings.TrimSuffix(strings.Repeat("?,", len(drift.Traits)), ",")+`)`)
+		for _, name := range drift.Traits {
+			args = append(args, name)
+		}
+	}
+
+	rows, err := d.db.Query(strings.Join(clauses, " UNION ")+` ORDER BY file_path`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []string
+	for rows.Next() {
+		var filePath string
+		if err := rows.Scan(&filePath); err != nil {
+			return nil, err
+		}
+		files = append(files, filePath)
+	}
+	return files, rows.Err()
+}
@@ -0,0 +1,313 @@
+// Package issuesvc looks up live titles and statuses for issue tracker
+// references (Jira keys, GitHub issue URLs) found in vault content.
+//
+// Lookups are cached in .raven/cache/issues.json for a few minutes so read
+// and query output stay fast when the same issues are shown repeatedly.
+package issuesvc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/secrets"
+)
+
+const (
+	defaultGitHubURL      = "https://api.github.com"
+	defaultGitHubTokenEnv = "GITHUB_TOKEN"
+	defaultJiraTokenEnv   = "JIRA_API_TOKEN"
+	requestTimeout        = 10 * time.Second
+	cacheTTL              = 10 * time.Minute
+	fetchWorkers          = 4
+)
+
+type EnrichRequest struct {
+	VaultPath string
+	Config    *config.IssueRefsConfig
+	Refs      []model.IssueRef
+	Client    *http.Client // Defaults to a client with a short timeout
+	Context   context.Context
+}
+
+// EnrichResult holds the refs with titles and statuses filled in where the
+// lookup succeeded. Failed lists the keys that could not be fetched, and Err
+// is the first lookup error.
+type EnrichResult struct {
+	Refs   []model.IssueRef
+	Failed []string
+	Err    error
+}
+
+// Enrich fills in Title and Status for each ref when issue_refs.fetch is
+// enabled. Refs are returned unchanged when fetching is off; lookup failures
+// leave the affected refs unchanged and are reported in the result.
+func Enrich(req EnrichRequest) EnrichResult {
+	refs := append([]model.IssueRef(nil), req.Refs...)
+	result := EnrichResult{Refs: refs}
+	if req.Config == nil || !req.Config.Fetch || len(refs) == 0 {
+		return result
+	}
+
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := req.Client
+	if client == nil {
+		client = &http.Client{Timeout: requestTimeout}
+	}
+	fetcher := &fetcher{cfg: req.Config, client: client}
+
+	cache := loadCache(req.VaultPath)
+	now := time.Now()
+
+	// Look up each distinct issue once, reusing fresh cache entries.
+	type lookup struct {
+		ref   model.IssueRef
+		entry cacheEntry
+		err   error
+	}
+	pending := make(map[string]*lookup)
+	var order []string
+	for _, ref := range refs {
+		key := cacheKey(ref)
+		if _, ok := pending[key]; ok {
+			continue
+		}
+		if entry, ok := cache.Issues[key]; ok && now.Sub(time.Unix(entry.FetchedAt, 0)) < cacheTTL {
+			continue
+		}
+		pending[key] = &lookup{ref: ref}
+		order = append(order, key)
+	}
+
+	if len(order) > 0 {
+		jobs := make(chan *lookup)
+		var wg sync.WaitGroup
+		for i := 0; i < fetchWorkers && i < len(order); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for job := range jobs {
+					job.entry, job.err = fetcher.fetch(ctx, job.ref)
+				}
+			}()
+		}
+		for _, key := range order {
+			jobs <- pending[key]
+		}
+		close(jobs)
+		wg.Wait()
+
+		changed := false
+		for _, key := range order {
+			job := pending[key]
+			if job.err != nil {
+				result.Failed = append(result.Failed, job.ref.Key)
+				if result.Err == nil {
+					result.Err = job.err
+				}
+				continue
+			}
+			job.entry.FetchedAt = now.Unix()
+			cache.Issues[key] = job.entry
+			changed = true
+		}
+		if changed {
+			// The cache only saves repeat lookups; failing to write it is
+			// not worth surfacing.
+			_ = saveCache(req.VaultPath, cache)
+		}
+	}
+
+	for i := range refs {
+		if entry, ok := cache.Issues[cacheKey(refs[i])]; ok {
+			refs[i].Title = entry.Title
+			refs[i].Status = entry.Status
+		}
+	}
+	return result
+}
+
+type fetcher struct {
+	cfg    *config.IssueRefsConfig
+	client *http.Client
+}
+
+func (f *fetcher) fetch(ctx context.Context, ref model.IssueRef) (cacheEntry, error) {
+	switch ref.Provider {
+	case parser.IssueProviderGitHub:
+		return f.fetchGitHub(ctx, ref.Key)
+	case parser.IssueProviderJira:
+		return f.fetchJira(ctx, ref.Key)
+	default:
+		return cacheEntry{}, fmt.Errorf("unknown issue provider %q", ref.Provider)
+	}
+}
+
+func (f *fetcher) fetchGitHub(ctx context.Context, key string) (cacheEntry, error) {
+	repo, number, ok := strings.Cut(key, "#")
+	if !ok {
+		return cacheEntry{}, fmt.Errorf("invalid GitHub issue key %q", key)
+	}
+	tracker := f.cfg.GitHub
+	if tracker == nil {
+		tracker = &config.IssueTrackerConfig{}
+	}
+	baseURL := strings.TrimRight(strings.TrimSpace(tracker.URL), "/")
+	if baseURL == "" {
+		baseURL = defaultGitHubURL
+	}
+	token, err := resolveToken(tokenEnv(tracker, defaultGitHubTokenEnv))
+	if err != nil {
+		return cacheEntry{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/repos/"+repo+"/issues/"+number, nil)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	var issue struct {
+		Title string `json:"title"`
+		State string `json:"state"`
+	}
+	if err := f.do(req, key, &issue); err != nil {
+		return cacheEntry{}, err
+	}
+	return cacheEntry{Title: issue.Title, Status: issue.State}, nil
+}
+
+func (f *fetcher) fetchJira(ctx context.Context, key string) (cacheEntry, error) {
+	tracker := f.cfg.Jira
+	jiraURL := f.cfg.JiraURL()
+	if tracker == nil || jiraURL == "" {
+		return cacheEntry{}, fmt.Errorf("%s: issue_refs.jira.url is not set", key)
+	}
+	token, err := resolveToken(tokenEnv(tracker, defaultJiraTokenEnv))
+	if err != nil {
+		return cacheEntry{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jiraURL+"/rest/api/2/issue/"+key+"?fields=summary,status", nil)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		// Jira Cloud API tokens use basic auth with the account email; Data
+		// Center personal access tokens are bearer tokens.
+		if email := strings.TrimSpace(os.Getenv(strings.TrimSpace(tracker.EmailEnv))); email != "" {
+			req.SetBasicAuth(email, token)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	var issue struct {
+		Fields struct {
+			Summary string `json:"summary"`
+			Status  struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	if err := f.do(req, key, &issue); err != nil {
+		return cacheEntry{}, err
+	}
+	return cacheEntry{Title: issue.Fields.Summary, Status: issue.Fields.Status.Name}, nil
+}
+
+func (f *fetcher) do(req *http.Request, key string, out interface{}) error {
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: status %d", key, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+	return nil
+}
+
+func tokenEnv(tracker *config.IssueTrackerConfig, fallback string) string {
+	if env := strings.TrimSpace(tracker.TokenEnv); env != "" {
+		return env
+	}
+	return fallback
+}
+
+// resolveToken reads an API token from the environment variable envName, or
+// from the command config.toml configures for it under [key_commands]. A
+// missing token is not an error; public issues are fetched without one.
+func resolveToken(envName string) (string, error) {
+	token, err := secrets.Lookup(envName)
+	if errors.Is(err, secrets.ErrNotSet) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+type cacheEntry struct {
+	Title     string `json:"title,omitempty"`
+	Status    string `json:"status,omitempty"`
+	FetchedAt int64  `json:"fetched_at"`
+}
+
+type issueCache struct {
+	Issues map[string]cacheEntry `json:"issues"`
+}
+
+func cacheKey(ref model.IssueRef) string {
+	return ref.Provider + ":" + ref.Key
+}
+
+func cachePath(vaultPath string) string {
+	return filepath.Join(vaultPath, ".raven", "cache", "issues.json")
+}
+
+// loadCache reads the lookup cache. A missing or unreadable cache is empty.
+func loadCache(vaultPath string) *issueCache {
+	cache := &issueCache{}
+	if data, err := os.ReadFile(cachePath(vaultPath)); err == nil {
+		_ = json.Unmarshal(data, cache)
+	}
+	if cache.Issues == nil {
+		cache.Issues = make(map[string]cacheEntry)
+	}
+	return cache
+}
+
+func saveCache(vaultPath string, cache *issueCache) error {
+	path := cachePath(vaultPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,101 @@
+package issuesvc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/parser"
+)
+
+func TestEnrich_FetchesGitHubAndJiraAndCaches(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/repos/acme/widgets/issues/7":
+			if got := r.Header.Get("Authorization"); got != "Bearer gh-secret" {
+				t.Errorf("github authorization = %q", got)
+			}
+			_, _ = w.Write([]byte(`{"title":"Fix login","state":"open"}`))
+		case "/rest/api/2/issue/PROJ-2":
+			if user, pass, ok := r.BasicAuth(); !ok || user != "ada@example.com" || pass != "jira-secret" {
+				t.Errorf("jira basic auth = %q %q %v", user, pass, ok)
+			}
+			_, _ = w.Write([]byte(`{"fields":{"summary":"Ship it","status":{"name":"In Progress"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("RAVEN_ISSUE_TEST_GH", "gh-secret")
+	t.Setenv("RAVEN_ISSUE_TEST_JIRA", "jira-secret")
+	t.Setenv("RAVEN_ISSUE_TEST_EMAIL", "ada@example.com")
+
+	cfg := &config.IssueRefsConfig{
+		Enabled: true,
+		Fetch:   true,
+		GitHub:  &config.IssueTrackerConfig{URL: server.URL, TokenEnv: "RAVEN_ISSUE_TEST_GH"},
+		Jira:    &config.IssueTrackerConfig{URL: server.URL, TokenEnv: "RAVEN_ISSUE_TEST_JIRA", EmailEnv: "RAVEN_ISSUE_TEST_EMAIL"},
+	}
+	refs := []model.IssueRef{
+		{Provider: parser.IssueProviderGitHub, Key: "acme/widgets#7"},
+		{Provider: parser.IssueProviderJira, Key: "PROJ-2"},
+		{Provider: parser.IssueProviderJira, Key: "PROJ-404"},
+	}
+	vaultPath := t.TempDir()
+
+	result := Enrich(EnrichRequest{VaultPath: vaultPath, Config: cfg, Refs: refs, Client: server.Client()})
+	want := []model.IssueRef{
+		{Provider: parser.IssueProviderGitHub, Key: "acme/widgets#7", Title: "Fix login", Status: "open"},
+		{Provider: parser.IssueProviderJira, Key: "PROJ-2", Title: "Ship it", Status: "In Progress"},
+		{Provider: parser.IssueProviderJira, Key: "PROJ-404"},
+	}
+	if !reflect.DeepEqual(result.Refs, want) {
+		t.Fatalf("refs = %#v, want %#v", result.Refs, want)
+	}
+	if !reflect.DeepEqual(result.Failed, []string{"PROJ-404"}) || result.Err == nil {
+		t.Fatalf("failed = %v, err = %v; want PROJ-404 reported", result.Failed, result.Err)
+	}
+
+	// Successful lookups are served from the cache; the failed one is retried.
+	atomic.StoreInt32(&requests, 0)
+	again := Enrich(EnrichRequest{VaultPath: vaultPath, Config: cfg, Refs: refs, Client: server.Client(), Context: context.Background()})
+	if !reflect.DeepEqual(again.Refs, want) {
+		t.Fatalf("cached refs = %#v, want %#v", again.Refs, want)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("requests on second run = %d, want 1", got)
+	}
+}
+
+func TestEnrich_NoFetchLeavesRefsUnchanged(t *testing.T) {
+	t.Parallel()
+
+	refs := []model.IssueRef{{Provider: parser.IssueProviderJira, Key: "PROJ-2"}}
+	for _, cfg := range []*config.IssueRefsConfig{nil, {Enabled: true}} {
+		result := Enrich(EnrichRequest{VaultPath: t.TempDir(), Config: cfg, Refs: refs})
+		if !reflect.DeepEqual(result.Refs, refs) || result.Failed != nil || result.Err != nil {
+			t.Fatalf("Enrich(%#v) = %#v, want refs unchanged", cfg, result)
+		}
+	}
+}
+
+func TestEnrich_JiraWithoutURLFails(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.IssueRefsConfig{Enabled: true, Fetch: true}
+	result := Enrich(EnrichRequest{
+		VaultPath: t.TempDir(),
+		Config:    cfg,
+		Refs:      []model.IssueRef{{Provider: parser.IssueProviderJira, Key: "PROJ-2"}},
+	})
+	if !reflect.DeepEqual(result.Failed, []string{"PROJ-2"}) || result.Err == nil {
+		t.Fatalf("failed = %v, err = %v; want PROJ-2 reported", result.Failed, result.Err)
+	}
+}
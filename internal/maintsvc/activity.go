@@ -0,0 +1,138 @@
+package maintsvc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/index"
+)
+
+// activityTopN is how many objects the most-edited and most-referenced
+// lists hold.
+const activityTopN = 10
+
+// ActivityReport describes how the vault changed over a window, from the
+// daily snapshots and edit counts recorded in the index.
+type ActivityReport struct {
+	Since          string             `json:"since"`
+	Growth         ActivityGrowth     `json:"growth"`
+	Series         []StatsSnapshot    `json:"series"`
+	MostEdited     []EditedObject     `json:"most_edited"`
+	MostReferenced []ReferencedObject `json:"most_referenced"`
+}
+
+// ActivityGrowth is the change in each count across the window.
+type ActivityGrowth struct {
+	Files   CountChange `json:"files"`
+	Objects CountChange `json:"objects"`
+	Traits  CountChange `json:"traits"`
+	Refs    CountChange `json:"refs"`
+	Orphans CountChange `json:"orphans"`
+}
+
+// CountChange is a count at the start and end of a window.
+type CountChange struct {
+	From   int `json:"from"`
+	To     int `json:"to"`
+	Change int `json:"change"`
+}
+
+// StatsSnapshot is one day's recorded counts.
+type StatsSnapshot struct {
+	Day     string `json:"day"`
+	Files   int    `json:"files"`
+	Objects int    `json:"objects"`
+	Traits  int    `json:"traits"`
+	Refs    int    `json:"refs"`
+	Orphans int    `json:"orphans"`
+}
+
+// EditedObject is a file and the number of edits indexed within the window.
+type EditedObject struct {
+	ID       string `json:"id,omitempty"`
+	FilePath string `json:"file_path"`
+	Edits    int    `json:"edits"`
+}
+
+// ReferencedObject is an object and the references it receives from other
+// files.
+type ReferencedObject struct {
+	ID         string `json:"id"`
+	FilePath   string `json:"file_path"`
+	References int    `json:"references"`
+}
+
+func activityReport(db *index.Database, sinceDay string) (*ActivityReport, error) {
+	snapshots, err := db.StatsSnapshotsSince(sinceDay)
+	if err != nil {
+		return nil, err
+	}
+	edited, err := db.MostEditedFiles(sinceDay, activityTopN)
+	if err != nil {
+		return nil, err
+	}
+	referenced, err := db.MostReferencedObjects(activityTopN)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ActivityReport{
+		Since:          sinceDay,
+		Series:         make([]StatsSnapshot, 0, len(snapshots)),
+		MostEdited:     make([]EditedObject, 0, len(edited)),
+		MostReferenced: make([]ReferencedObject, 0, len(referenced)),
+	}
+	for _, s := range snapshots {
+		report.Series = append(report.Series, StatsSnapshot(s))
+	}
+	if len(report.Series) > 0 {
+		first, last := report.Series[0], report.Series[len(report.Series)-1]
+		report.Growth = ActivityGrowth{
+			Files:   countChange(first.Files, last.Files),
+			Objects: countChange(first.Objects, last.Objects),
+			Traits:  countChange(first.Traits, last.Traits),
+			Refs:    countChange(first.Refs, last.Refs),
+			Orphans: countChange(first.Orphans, last.Orphans),
+		}
+	}
+	for _, f := range edited {
+		report.MostEdited = append(report.MostEdited, EditedObject{ID: f.ObjectID, FilePath: f.FilePath, Edits: f.Edits})
+	}
+	for _, r := range referenced {
+		report.MostReferenced = append(report.MostReferenced, ReferencedObject{ID: r.ObjectID, FilePath: r.FilePath, References: r.References})
+	}
+	return report, nil
+}
+
+func countChange(from, to int) CountChange {
+	return CountChange{From: from, To: to, Change: to - from}
+}
+
+// parseStatsSince returns the first local date of a --since window: a count
+// of days, weeks, or years before now (30d, 4w, 1y), or a YYYY-MM-DD date.
+func parseStatsSince(value string, now time.Time) (string, error) {
+	value = strings.TrimSpace(value)
+	if day, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return day.Format("2006-01-02"), nil
+	}
+	invalid := fmt.Errorf("invalid --since %q", value)
+	if len(value) < 2 {
+		return "", invalid
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n <= 0 {
+		return "", invalid
+	}
+	switch strings.ToLower(value[len(value)-1:]) {
+	case "d":
+		return now.AddDate(0, 0, -n).Format("2006-01-02"), nil
+	case "w":
+		return now.AddDate(0, 0, -7*n).Format("2006-01-02"), nil
+	case "y":
+		return now.AddDate(-n, 0, 0).Format("2006-01-02"), nil
+	default:
+		return "", invalid
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/aidanlsb/raven/internal/buildinfo"
 	"github.com/aidanlsb/raven/internal/codes"
@@ -59,13 +60,32 @@ func AsError(err error) (*Error, bool) {
 }
 
 type StatsResult struct {
-	FileCount   int `json:"file_count"`
-	ObjectCount int `json:"object_count"`
-	TraitCount  int `json:"trait_count"`
-	RefCount    int `json:"ref_count"`
+	FileCount   int             `json:"file_count"`
+	ObjectCount int             `json:"object_count"`
+	TraitCount  int             `json:"trait_count"`
+	RefCount    int             `json:"ref_count"`
+	OrphanCount int             `json:"orphan_count"`
+	Authors     []AuthorCount   `json:"authors,omitempty"`
+	Activity    *ActivityReport `json:"activity,omitempty"`
 }
 
-func Stats(vaultPath string) (*StatsResult, error) {
+// AuthorCount is the number of objects an author created and last modified.
+type AuthorCount struct {
+	Author   string `json:"author"`
+	Created  int    `json:"created"`
+	Modified int    `json:"modified"`
+}
+
+type StatsOptions struct {
+	ByAuthor bool
+	// Since adds an activity report for the window, as a count of days,
+	// weeks, or years (30d, 4w, 1y) or a YYYY-MM-DD start date.
+	Since string
+	// Now overrides the current time (for tests).
+	Now time.Time
+}
+
+func Stats(vaultPath string, opts StatsOptions) (*StatsResult, error) {
 	if strings.TrimSpace(vaultPath) == "" {
 		return nil, newError(CodeInvalidInput, "vault path is required", "", nil)
 	}
@@ -76,17 +96,56 @@ func Stats(vaultPath string) (*StatsResult, error) {
 	}
 	defer db.Close()
 
-	stats, err := db.Stats()
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	var sinceDay string
+	if strings.TrimSpace(opts.Since) != "" {
+		sinceDay, err = parseStatsSince(opts.Since, now)
+		if err != nil {
+			return nil, newError(CodeInvalidInput, err.Error(), "Use a window such as 30d, 4w, or 1y, or a date such as 2026-01-31", err)
+		}
+	}
+
+	// Every stats run records today's snapshot, building the time series
+	// that --since reports on.
+	snapshot, err := db.RecordStatsSnapshot(now)
 	if err != nil {
 		return nil, newError(CodeDatabaseError, "failed to query stats", "", err)
 	}
 
-	return &StatsResult{
-		FileCount:   stats.FileCount,
-		ObjectCount: stats.ObjectCount,
-		TraitCount:  stats.TraitCount,
-		RefCount:    stats.RefCount,
-	}, nil
+	result := &StatsResult{
+		FileCount:   snapshot.Files,
+		ObjectCount: snapshot.Objects,
+		TraitCount:  snapshot.Traits,
+		RefCount:    snapshot.Refs,
+		OrphanCount: snapshot.Orphans,
+	}
+
+	if opts.ByAuthor {
+		authors, err := db.AuthorStats()
+		if err != nil {
+			return nil, newError(CodeDatabaseError, "failed to query author stats", "", err)
+		}
+		result.Authors = make([]AuthorCount, 0, len(authors))
+		for _, author := range authors {
+			result.Authors = append(result.Authors, AuthorCount{
+				Author:   author.Author,
+				Created:  author.Created,
+				Modified: author.Modified,
+			})
+		}
+	}
+
+	if sinceDay != "" {
+		result.Activity, err = activityReport(db, sinceDay)
+		if err != nil {
+			return nil, newError(CodeDatabaseError, "failed to query activity", "", err)
+		}
+	}
+
+	return result, nil
 }
 
 const defaultModulePath = "github.com/aidanlsb/raven"
@@ -1,8 +1,10 @@
 package maintsvc
 
 import (
+	"reflect"
 	"runtime/debug"
 	"testing"
+	"time"
 
 	"github.com/aidanlsb/raven/internal/buildinfo"
 	"github.com/aidanlsb/raven/internal/index"
@@ -24,7 +26,7 @@ func assertCode(t *testing.T, err error, want Code) {
 
 func TestStats_InvalidInput(t *testing.T) {
 	t.Parallel()
-	_, err := Stats(" ")
+	_, err := Stats(" ", StatsOptions{})
 	assertCode(t, err, CodeInvalidInput)
 }
 
@@ -65,13 +67,110 @@ func TestStats_HappyPath(t *testing.T) {
 		t.Fatalf("failed to close db: %v", err)
 	}
 
-	stats, err := Stats(vaultPath)
+	stats, err := Stats(vaultPath, StatsOptions{})
 	if err != nil {
 		t.Fatalf("Stats returned error: %v", err)
 	}
 	if stats.ObjectCount != 2 || stats.TraitCount != 1 || stats.RefCount != 1 || stats.FileCount != 2 {
 		t.Fatalf("unexpected stats: %#v", stats)
 	}
+	if stats.Authors != nil {
+		t.Fatalf("expected no author breakdown without ByAuthor, got %#v", stats.Authors)
+	}
+}
+
+func TestStats_ByAuthor(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		t.Fatalf("failed to open index db: %v", err)
+	}
+
+	_, err = db.DB().Exec(`
+		INSERT INTO objects (id, file_path, type, line_start, fields) VALUES
+			('page/one', 'pages/one.md', 'page', 1, '{"created_by":"Ada","modified_by":"Grace"}'),
+			('page/two', 'pages/two.md', 'page', 1, '{"created_by":"Grace","modified_by":"Grace"}'),
+			('page/three', 'pages/three.md', 'page', 1, '{}')
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert objects: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("failed to close db: %v", err)
+	}
+
+	stats, err := Stats(vaultPath, StatsOptions{ByAuthor: true})
+	if err != nil {
+		t.Fatalf("Stats returned error: %v", err)
+	}
+	want := []AuthorCount{
+		{Author: "Ada", Created: 1, Modified: 0},
+		{Author: "Grace", Created: 1, Modified: 2},
+	}
+	if !reflect.DeepEqual(stats.Authors, want) {
+		t.Fatalf("Authors = %#v, want %#v", stats.Authors, want)
+	}
+}
+
+func TestStats_Since(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		t.Fatalf("failed to open index db: %v", err)
+	}
+
+	_, err = db.DB().Exec(`
+		INSERT INTO objects (id, file_path, type, line_start, fields) VALUES
+			('page/one', 'pages/one.md', 'page', 1, '{}'),
+			('page/two', 'pages/two.md', 'page', 1, '{}');
+		INSERT INTO refs (source_id, target_id, target_raw, file_path, line_number) VALUES
+			('page/one', 'page/two', 'page/two', 'pages/one.md', 3);
+		INSERT INTO stats_snapshots (day, files, objects, traits, refs, orphans, recorded_at) VALUES
+			('2026-01-01', 1, 1, 0, 0, 1, 0),
+			('2026-02-20', 1, 1, 0, 0, 1, 0);
+		INSERT INTO file_edits (file_path, day, edits) VALUES
+			('pages/one.md', '2026-02-25', 2),
+			('pages/one.md', '2026-03-01', 1),
+			('pages/two.md', '2026-01-15', 5)
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert rows: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("failed to close db: %v", err)
+	}
+
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	stats, err := Stats(vaultPath, StatsOptions{Since: "30d", Now: now})
+	if err != nil {
+		t.Fatalf("Stats returned error: %v", err)
+	}
+	if stats.OrphanCount != 1 {
+		t.Fatalf("OrphanCount = %d, want 1", stats.OrphanCount)
+	}
+	activity := stats.Activity
+	if activity == nil || activity.Since != "2026-01-31" {
+		t.Fatalf("Activity = %#v, want a report since 2026-01-31", activity)
+	}
+	if len(activity.Series) != 3 || activity.Series[0].Day != "2026-01-01" || activity.Series[2].Day != "2026-03-02" {
+		t.Fatalf("Series = %#v, want the last snapshot before the window through today's", activity.Series)
+	}
+	if activity.Growth.Objects != (CountChange{From: 1, To: 2, Change: 1}) {
+		t.Fatalf("Growth.Objects = %#v", activity.Growth.Objects)
+	}
+	wantEdited := []EditedObject{{ID: "page/one", FilePath: "pages/one.md", Edits: 3}}
+	if !reflect.DeepEqual(activity.MostEdited, wantEdited) {
+		t.Fatalf("MostEdited = %#v, want %#v", activity.MostEdited, wantEdited)
+	}
+	wantReferenced := []ReferencedObject{{ID: "page/two", FilePath: "pages/two.md", References: 1}}
+	if !reflect.DeepEqual(activity.MostReferenced, wantReferenced) {
+		t.Fatalf("MostReferenced = %#v, want %#v", activity.MostReferenced, wantReferenced)
+	}
+
+	_, err = Stats(vaultPath, StatsOptions{Since: "last month", Now: now})
+	assertCode(t, err, CodeInvalidInput)
 }
 
 func TestCurrentVersionInfoWithReader(t *testing.T) {
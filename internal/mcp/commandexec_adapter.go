@@ -73,6 +73,8 @@ func marshalCanonicalResult(result commandexec.Result) (string, bool, bool) {
 }
 
 func normalizeCanonicalArgs(commandID string, args map[string]interface{}) map[string]interface{} {
-	_ = commandID
+	if commandID == "query" {
+		return withDefaultQueryPage(args)
+	}
 	return args
 }
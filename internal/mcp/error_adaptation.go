@@ -8,7 +8,10 @@ import (
 )
 
 func adaptCanonicalResultForMCP(commandID string, rawArgs map[string]interface{}, result commandexec.Result) commandexec.Result {
-	if result.OK || result.Error == nil {
+	if result.OK {
+		return withPageInstructions(commandID, result)
+	}
+	if result.Error == nil {
 		return result
 	}
 
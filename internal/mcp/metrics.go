@@ -0,0 +1,147 @@
+package mcp
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// commandDurationBuckets are the histogram upper bounds, in seconds, for
+// command latency. They span quick reads through full reindexes.
+var commandDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics records MCP server activity for the Prometheus /metrics endpoint.
+// All methods are safe for concurrent use.
+type Metrics struct {
+	started  time.Time
+	inFlight atomic.Int64
+
+	mu       sync.Mutex
+	commands map[string]*commandMetrics
+}
+
+type commandMetrics struct {
+	count   uint64
+	errors  uint64
+	sum     float64
+	buckets []uint64 // cumulative counts, parallel to commandDurationBuckets
+}
+
+// NewMetrics creates an empty metrics recorder.
+func NewMetrics() *Metrics {
+	return &Metrics{started: time.Now(), commands: map[string]*commandMetrics{}}
+}
+
+// Metrics returns the server's metrics recorder, creating it on first use.
+func (s *Server) Metrics() *Metrics {
+	s.metricsOnce.Do(func() {
+		s.metrics = NewMetrics()
+	})
+	return s.metrics
+}
+
+// ObserveCommand records one invocation of a canonical command.
+func (m *Metrics) ObserveCommand(commandID string, elapsed time.Duration, isError bool) {
+	seconds := elapsed.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cm := m.commands[commandID]
+	if cm == nil {
+		cm = &commandMetrics{buckets: make([]uint64, len(commandDurationBuckets))}
+		m.commands[commandID] = cm
+	}
+	cm.count++
+	cm.sum += seconds
+	if isError {
+		cm.errors++
+	}
+	for i, bound := range commandDurationBuckets {
+		if seconds <= bound {
+			cm.buckets[i]++
+		}
+	}
+}
+
+func (m *Metrics) toolCallStarted()  { m.inFlight.Add(1) }
+func (m *Metrics) toolCallFinished() { m.inFlight.Add(-1) }
+
+// WritePrometheus writes all metrics in the Prometheus text exposition format.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	ids := make([]string, 0, len(m.commands))
+	for id := range m.commands {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	snapshot := make(map[string]commandMetrics, len(ids))
+	for _, id := range ids {
+		cm := *m.commands[id]
+		cm.buckets = append([]uint64(nil), cm.buckets...)
+		snapshot[id] = cm
+	}
+	m.mu.Unlock()
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	p := &promWriter{w: w}
+	p.printf("# HELP raven_mcp_command_duration_seconds Latency of commands invoked through the MCP server.\n")
+	p.printf("# TYPE raven_mcp_command_duration_seconds histogram\n")
+	for _, id := range ids {
+		cm := snapshot[id]
+		for i, bound := range commandDurationBuckets {
+			p.printf("raven_mcp_command_duration_seconds_bucket{command=%q,le=%q} %d\n", id, formatPromFloat(bound), cm.buckets[i])
+		}
+		p.printf("raven_mcp_command_duration_seconds_bucket{command=%q,le=\"+Inf\"} %d\n", id, cm.count)
+		p.printf("raven_mcp_command_duration_seconds_sum{command=%q} %s\n", id, formatPromFloat(cm.sum))
+		p.printf("raven_mcp_command_duration_seconds_count{command=%q} %d\n", id, cm.count)
+	}
+
+	p.printf("# HELP raven_mcp_command_errors_total Commands invoked through the MCP server that returned an error.\n")
+	p.printf("# TYPE raven_mcp_command_errors_total counter\n")
+	for _, id := range ids {
+		p.printf("raven_mcp_command_errors_total{command=%q} %d\n", id, snapshot[id].errors)
+	}
+
+	p.printf("# HELP raven_mcp_tool_calls_in_flight Tool calls currently being handled.\n")
+	p.printf("# TYPE raven_mcp_tool_calls_in_flight gauge\n")
+	p.printf("raven_mcp_tool_calls_in_flight %d\n", m.inFlight.Load())
+
+	p.printf("# HELP raven_mcp_uptime_seconds Seconds since the MCP server started.\n")
+	p.printf("# TYPE raven_mcp_uptime_seconds gauge\n")
+	p.printf("raven_mcp_uptime_seconds %s\n", formatPromFloat(time.Since(m.started).Seconds()))
+
+	p.printf("# HELP raven_go_goroutines Number of goroutines.\n")
+	p.printf("# TYPE raven_go_goroutines gauge\n")
+	p.printf("raven_go_goroutines %d\n", runtime.NumGoroutine())
+
+	p.printf("# HELP raven_go_heap_alloc_bytes Bytes of allocated heap objects.\n")
+	p.printf("# TYPE raven_go_heap_alloc_bytes gauge\n")
+	p.printf("raven_go_heap_alloc_bytes %d\n", mem.HeapAlloc)
+
+	return p.err
+}
+
+// promWriter keeps the first write error so WritePrometheus can print
+// unconditionally.
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *promWriter) printf(format string, args ...interface{}) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+func formatPromFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
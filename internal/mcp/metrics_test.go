@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsWritePrometheus(t *testing.T) {
+	t.Parallel()
+
+	m := NewMetrics()
+	m.ObserveCommand("query", 30*time.Millisecond, false)
+	m.ObserveCommand("query", 2*time.Second, true)
+	m.ObserveCommand("reindex", 7*time.Second, false)
+	m.toolCallStarted()
+
+	var buf bytes.Buffer
+	if err := m.WritePrometheus(&buf); err != nil {
+		t.Fatalf("WritePrometheus: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE raven_mcp_command_duration_seconds histogram",
+		`raven_mcp_command_duration_seconds_bucket{command="query",le="0.025"} 0`,
+		`raven_mcp_command_duration_seconds_bucket{command="query",le="0.05"} 1`,
+		`raven_mcp_command_duration_seconds_bucket{command="query",le="+Inf"} 2`,
+		`raven_mcp_command_duration_seconds_count{command="query"} 2`,
+		`raven_mcp_command_duration_seconds_bucket{command="reindex",le="5"} 0`,
+		`raven_mcp_command_duration_seconds_bucket{command="reindex",le="10"} 1`,
+		`raven_mcp_command_errors_total{command="query"} 1`,
+		`raven_mcp_command_errors_total{command="reindex"} 0`,
+		"raven_mcp_tool_calls_in_flight 1",
+		"raven_go_goroutines ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output missing %q\n%s", want, out)
+		}
+	}
+	if strings.Index(out, `command="query"`) > strings.Index(out, `command="reindex"`) {
+		t.Error("expected command series sorted by command id")
+	}
+}
+
+func TestStartDebugServerServesMetricsAndPprof(t *testing.T) {
+	t.Parallel()
+
+	m := NewMetrics()
+	m.ObserveCommand("search", 10*time.Millisecond, false)
+
+	server, addr, err := StartDebugServer("127.0.0.1:0", m)
+	if err != nil {
+		t.Fatalf("StartDebugServer: %v", err)
+	}
+	defer server.Close()
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get("http://" + addr.String() + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	status, body := get("/metrics")
+	if status != http.StatusOK || !strings.Contains(body, `raven_mcp_command_duration_seconds_count{command="search"} 1`) {
+		t.Fatalf("/metrics = %d\n%s", status, body)
+	}
+	if status, _ := get("/debug/pprof/"); status != http.StatusOK {
+		t.Fatalf("/debug/pprof/ status = %d, want 200", status)
+	}
+}
+
+func TestServerMetricsRecordInvokedCommands(t *testing.T) {
+	t.Parallel()
+
+	s := newTestServerWithVault(t)
+	s.callToolWithContext(t.Context(), compactToolInvoke, map[string]interface{}{
+		"command": "query",
+		"args":    map[string]interface{}{"query_string": "type:person"},
+	})
+
+	var buf bytes.Buffer
+	if err := s.Metrics().WritePrometheus(&buf); err != nil {
+		t.Fatalf("WritePrometheus: %v", err)
+	}
+	if !strings.Contains(buf.String(), `raven_mcp_command_duration_seconds_count{command="query"} 1`) {
+		t.Fatalf("expected query invocation to be recorded\n%s", buf.String())
+	}
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"fmt"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+)
+
+// defaultQueryPageSize caps query rows returned over MCP when the caller does
+// not pass a limit, so large result sets stay within client token limits.
+const defaultQueryPageSize = 50
+
+// withDefaultQueryPage applies defaultQueryPageSize to a query invocation that
+// sets no limit. Explicit limits (including 0 for every row), cursors,
+// count-only queries, and bulk applies are left alone.
+func withDefaultQueryPage(args map[string]interface{}) map[string]interface{} {
+	for _, key := range []string{"limit", "cursor", "count-only", "apply"} {
+		if _, ok := args[key]; ok {
+			return args
+		}
+	}
+
+	out := make(map[string]interface{}, len(args)+1)
+	for key, value := range args {
+		out[key] = value
+	}
+	out["limit"] = defaultQueryPageSize
+	return out
+}
+
+// withPageInstructions tells the agent how to fetch the rest of a partial
+// query or read result.
+func withPageInstructions(commandID string, result commandexec.Result) commandexec.Result {
+	data, ok := result.Data.(map[string]interface{})
+	if !ok {
+		return result
+	}
+
+	var next string
+	switch commandID {
+	case "query":
+		cursor, ok := data["next_cursor"].(string)
+		if !ok || cursor == "" {
+			return result
+		}
+		next = fmt.Sprintf(
+			"Showing %v of %v results. Call raven_invoke with command 'query', the same args, and args.cursor=%q for the next page, args.limit=0 for every row, or args.count_only=true for just the count.",
+			data["returned"], data["total"], cursor,
+		)
+	case "read":
+		nextLine, ok := data["next_start_line"].(int)
+		if !ok {
+			return result
+		}
+		next = fmt.Sprintf(
+			"Showing lines %v-%v of %v. Call raven_invoke with command 'read' and args.start_line=%d for the next lines.",
+			data["start_line"], data["end_line"], data["line_count"], nextLine,
+		)
+	default:
+		return result
+	}
+
+	out := make(map[string]interface{}, len(data)+1)
+	for key, value := range data {
+		out[key] = value
+	}
+	out["next"] = next
+	result.Data = out
+	return result
+}
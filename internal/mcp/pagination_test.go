@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/commandexec"
+)
+
+func TestWithDefaultQueryPage(t *testing.T) {
+	t.Parallel()
+
+	args := map[string]interface{}{"query_string": "type:project"}
+	got := withDefaultQueryPage(args)
+	if got["limit"] != defaultQueryPageSize {
+		t.Fatalf("limit = %v, want %d", got["limit"], defaultQueryPageSize)
+	}
+	if _, ok := args["limit"]; ok {
+		t.Fatal("withDefaultQueryPage modified the caller's args")
+	}
+
+	for _, key := range []string{"limit", "cursor", "count-only", "apply"} {
+		explicit := map[string]interface{}{"query_string": "type:project", key: 0}
+		if got := withDefaultQueryPage(explicit); got["limit"] == defaultQueryPageSize {
+			t.Errorf("default page applied despite %s", key)
+		}
+	}
+}
+
+func TestWithPageInstructions(t *testing.T) {
+	t.Parallel()
+
+	result := withPageInstructions("query", commandexec.Success(map[string]interface{}{
+		"total":       120,
+		"returned":    50,
+		"has_more":    true,
+		"next_cursor": "abc",
+	}, nil))
+	next, _ := result.Data.(map[string]interface{})["next"].(string)
+	if !strings.Contains(next, "Showing 50 of 120") || !strings.Contains(next, `args.cursor="abc"`) {
+		t.Fatalf("query next = %q", next)
+	}
+
+	result = withPageInstructions("read", commandexec.Success(map[string]interface{}{
+		"start_line":      1,
+		"end_line":        40,
+		"line_count":      90,
+		"has_more":        true,
+		"next_start_line": 41,
+	}, nil))
+	next, _ = result.Data.(map[string]interface{})["next"].(string)
+	if !strings.Contains(next, "args.start_line=41") {
+		t.Fatalf("read next = %q", next)
+	}
+
+	complete := withPageInstructions("query", commandexec.Success(map[string]interface{}{"total": 3, "has_more": false}, nil))
+	if _, ok := complete.Data.(map[string]interface{})["next"]; ok {
+		t.Fatal("complete query result should not include next")
+	}
+}
@@ -2,9 +2,11 @@ package mcp
 
 import (
 	"encoding/json"
+	"errors"
 	"sort"
 
 	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/watchsvc"
 )
 
 type savedQueryResource struct {
@@ -48,3 +50,29 @@ func (s *Server) readSavedQueriesResource(vaultName, vaultPath string) (string,
 	}
 	return string(out), nil
 }
+
+// readIndexStatusResource reports whether a watcher is keeping the index
+// current. Without one, agents should pass refresh or run reindex themselves.
+func (s *Server) readIndexStatusResource(vaultName, vaultPath string) (string, error) {
+	vaultPath, err := s.resolveVaultPathForInvocation(vaultName, vaultPath)
+	if err != nil {
+		return "", err
+	}
+
+	payload := map[string]interface{}{"watching": false}
+	status, err := watchsvc.ReadStatus(vaultPath)
+	switch {
+	case errors.Is(err, watchsvc.ErrNotRunning):
+	case err != nil:
+		return "", err
+	default:
+		payload["watching"] = true
+		payload["watcher"] = status
+	}
+
+	out, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
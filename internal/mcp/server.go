@@ -33,6 +33,9 @@ type Server struct {
 	inFlightMu sync.Mutex
 	inFlight   map[string]context.CancelFunc
 	runWG      sync.WaitGroup
+
+	metricsOnce sync.Once
+	metrics     *Metrics
 }
 
 // Request represents a JSON-RPC 2.0 request.
@@ -355,9 +358,12 @@ func (s *Server) dispatchToolCall(req *Request) {
 		s.trackInFlight(requestKey, cancel)
 	}
 
+	metrics := s.Metrics()
+	metrics.toolCallStarted()
 	s.runWG.Add(1)
 	go func() {
 		defer s.runWG.Done()
+		defer metrics.toolCallFinished()
 		defer cancel()
 		if tracked {
 			defer s.untrackInFlight(requestKey)
@@ -470,6 +476,12 @@ func (s *Server) handleResourcesList(req *Request) {
 		Description: "Saved queries defined in raven.yaml.",
 		MimeType:    "application/json",
 	})
+	resources = append(resources, Resource{
+		URI:         "raven://index/status",
+		Name:        "Index Status",
+		Description: "Index freshness reported by a running 'rvn watch' for this vault.",
+		MimeType:    "application/json",
+	})
 	if agentInstructions, ok := s.agentInstructionsResource(); ok {
 		resources = append(resources, agentInstructions)
 	}
@@ -525,6 +537,17 @@ func (s *Server) handleResourcesRead(req *Request) {
 			MimeType: "application/json",
 			Text:     queriesContent,
 		}
+	case "raven://index/status":
+		statusContent, err := s.readIndexStatusResource(params.Vault, params.VaultPath)
+		if err != nil {
+			s.sendError(req.ID, -32603, "Failed to read index status", err.Error())
+			return
+		}
+		content = ResourceContent{
+			URI:      params.URI,
+			MimeType: "application/json",
+			Text:     statusContent,
+		}
 	case vaultAgentInstructionsResourceURI:
 		agentInstructions, err := s.readAgentInstructionsResource(params.Vault, params.VaultPath)
 		if err != nil {
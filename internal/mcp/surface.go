@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/aidanlsb/raven/internal/commands"
 )
@@ -231,7 +232,9 @@ func (s *Server) callCompactInvokeWithContext(ctx context.Context, args map[stri
 		return commandValidationErrorEnvelope(contract, rawInvokeArgs, commandIssues), true
 	}
 
+	started := time.Now()
 	if out, isErr, handled := s.callCanonicalCommandWithContext(ctx, commandID, normalizedInvokeArgs, vaultName, vaultPath); handled {
+		s.Metrics().ObserveCommand(commandID, time.Since(started), isErr)
 		return out, isErr
 	}
 
@@ -0,0 +1,19 @@
+package model
+
+// IssueRef is an issue in an external tracker (Jira or GitHub) mentioned in
+// vault content.
+type IssueRef struct {
+	// Provider is the tracker: "jira" or "github".
+	Provider string `json:"provider"`
+
+	// Key identifies the issue: PROJ-123 for Jira, owner/repo#45 for GitHub.
+	Key string `json:"key"`
+
+	// URL links to the issue, when known.
+	URL string `json:"url,omitempty"`
+
+	// Title and Status are filled from the tracker when issue_refs.fetch is
+	// enabled and the lookup succeeded.
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status,omitempty"`
+}
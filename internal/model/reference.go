@@ -21,6 +21,26 @@ type Reference struct {
 
 	// DisplayText is the display text of the wikilink, if different from target.
 	DisplayText *string `json:"display_text,omitempty"`
+
+	// Context is the text of the line containing the reference. Only set for
+	// backlinks.
+	Context string `json:"context,omitempty"`
+
+	// ObjectID is the file-level object containing the reference. Only set for
+	// backlinks.
+	ObjectID string `json:"object_id,omitempty"`
+
+	// SectionID and SectionTitle identify the innermost heading section
+	// containing the reference, when there is one. Only set for backlinks.
+	SectionID    string `json:"section_id,omitempty"`
+	SectionTitle string `json:"section_title,omitempty"`
+}
+
+// ReferenceTypeGroup collects references whose sources share a type.
+type ReferenceTypeGroup struct {
+	SourceType string      `json:"source_type"`
+	Items      []Reference `json:"items"`
+	Count      int         `json:"count"`
 }
 
 // ReferenceInputError describes a non-fatal error for one input in a bulk
@@ -35,10 +55,11 @@ type ReferenceInputError struct {
 
 // BacklinksGroup contains backlinks for one requested target.
 type BacklinksGroup struct {
-	Input  string      `json:"input"`
-	Target string      `json:"target"`
-	Items  []Reference `json:"items"`
-	Count  int         `json:"count"`
+	Input  string               `json:"input"`
+	Target string               `json:"target"`
+	Items  []Reference          `json:"items"`
+	Count  int                  `json:"count"`
+	Groups []ReferenceTypeGroup `json:"groups,omitempty"`
 }
 
 // OutlinksGroup contains outlinks for one requested source.
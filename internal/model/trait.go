@@ -16,6 +16,10 @@ type Trait struct {
 	// Value is the trait's value, if any. Nil for boolean traits like @highlight.
 	Value *string `json:"value,omitempty"`
 
+	// RawValue is the value as written in the file. It is only set when schema
+	// normalization rewrote it, so Value holds the canonical form.
+	RawValue *string `json:"raw_value,omitempty"`
+
 	// Content is the text content of the line containing this trait,
 	// with trait annotations removed.
 	Content string `json:"content"`
@@ -12,10 +12,13 @@ import (
 	"github.com/aidanlsb/raven/internal/config"
 	"github.com/aidanlsb/raven/internal/dates"
 	"github.com/aidanlsb/raven/internal/filelock"
+	"github.com/aidanlsb/raven/internal/history"
 	"github.com/aidanlsb/raven/internal/pages"
 	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/readsvc"
 	"github.com/aidanlsb/raven/internal/schema"
 	"github.com/aidanlsb/raven/internal/vault"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 // ResolveAddHeadingTarget resolves an add --heading spec to a section ID.
@@ -31,7 +34,7 @@ func ResolveAddHeadingTarget(
 		return "", nil
 	}
 
-	contentBytes, err := os.ReadFile(destPath)
+	contentBytes, err := vaultcrypt.ReadFile(destPath)
 	if err != nil {
 		return "", addFileReadError(destPath, "failed to read target file", "Check that the target file exists and is readable", err)
 	}
@@ -121,7 +124,7 @@ func AppendToFile(
 		if err != nil {
 			return 0, newError(ErrorValidationFailed, "failed to load schema", "Fix schema.yaml and try again", nil, err)
 		}
-		if _, err := pages.CreateDailyNoteWithSchema(vaultPath, dailyDir, dateStr, friendlyTitle, s, vaultCfg.GetTemplateDirectory(), vaultCfg.ProtectedPrefixes); err != nil {
+		if _, err := pages.CreateDailyNoteWithSchema(vaultPath, dailyDir, dateStr, friendlyTitle, s, vaultCfg.GetTemplateDirectory(), vaultCfg.ProtectedPrefixes, readsvc.TemplateQuery(vaultPath)); err != nil {
 			return 0, addFileWriteError(destPath, "failed to create daily note", "Check the daily note path and try again", err)
 		}
 	}
@@ -134,6 +137,7 @@ func AppendToFile(
 		return appendUnderHeading(destPath, line, cfg.Heading)
 	}
 
+	history.Capture(destPath)
 	f, err := os.OpenFile(destPath, os.O_APPEND|os.O_RDWR, 0o644)
 	if err != nil {
 		return 0, addFileWriteError(destPath, "failed to open target file", "Check that the target file is writable", err)
@@ -154,6 +158,23 @@ func AppendToFile(
 	if err != nil {
 		return 0, addFileReadError(destPath, "failed to read target file", "Check that the target file is readable", err)
 	}
+	if vaultcrypt.IsEncrypted(content) {
+		// Encrypted files cannot be appended to in place; rewrite the
+		// decrypted content, which atomicfile encrypts again.
+		content, err = vaultcrypt.Open(destPath, content)
+		if err != nil {
+			return 0, addFileReadError(destPath, "failed to decrypt target file", "Set the vault encryption key and try again", err)
+		}
+		insertedLine := appendedLineNumber(content)
+		updated := string(content)
+		if len(updated) > 0 && !strings.HasSuffix(updated, "\n") {
+			updated += "\n"
+		}
+		if err := atomicfile.WriteFile(destPath, []byte(updated+line+"\n"), 0o644); err != nil {
+			return 0, addFileWriteError(destPath, "failed to write capture", "Check that the target file is writable", err)
+		}
+		return insertedLine, nil
+	}
 	insertedLine := appendedLineNumber(content)
 	if len(content) > 0 && content[len(content)-1] != '\n' {
 		if _, err := f.WriteString("\n"); err != nil {
@@ -169,27 +190,9 @@ func AppendToFile(
 }
 
 func appendWithinObject(vaultPath, destPath, line, objectID string, parseOpts *parser.ParseOptions) (int, error) {
-	contentBytes, err := os.ReadFile(destPath)
-	if err != nil {
-		return 0, addFileReadError(destPath, "failed to read target file", "Check that the target file exists and is readable", err)
-	}
-	content := string(contentBytes)
-	lines := strings.Split(content, "\n")
-
-	doc, err := parser.ParseDocumentWithOptions(content, destPath, vaultPath, parseOpts)
+	lines, target, err := readTargetSection(vaultPath, destPath, objectID, parseOpts)
 	if err != nil {
-		return 0, newError(ErrorInvalidInput, "failed to parse target file", "Fix the target file content and try again", nil, err)
-	}
-
-	var target *parser.ParsedSection
-	for _, section := range doc.Sections {
-		if section != nil && section.ID == objectID {
-			target = section
-			break
-		}
-	}
-	if target == nil {
-		return 0, newError(ErrorRefNotFound, fmt.Sprintf("target section not found: %s", objectID), "Use an existing section slug/id or heading text", nil, nil)
+		return 0, err
 	}
 
 	insertIdx := len(lines)
@@ -215,19 +218,47 @@ func appendWithinObject(vaultPath, destPath, line, objectID string, parseOpts *p
 	}
 	insertedLine := insertIdx + 1
 
+	if err := insertLineAt(destPath, lines, insertIdx, line); err != nil {
+		return 0, err
+	}
+	return insertedLine, nil
+}
+
+// readTargetSection returns the lines of destPath and the parsed section with
+// the given ID.
+func readTargetSection(vaultPath, destPath, sectionID string, parseOpts *parser.ParseOptions) ([]string, *parser.ParsedSection, error) {
+	contentBytes, err := vaultcrypt.ReadFile(destPath)
+	if err != nil {
+		return nil, nil, addFileReadError(destPath, "failed to read target file", "Check that the target file exists and is readable", err)
+	}
+	content := string(contentBytes)
+
+	doc, err := parser.ParseDocumentWithOptions(content, destPath, vaultPath, parseOpts)
+	if err != nil {
+		return nil, nil, newError(ErrorInvalidInput, "failed to parse target file", "Fix the target file content and try again", nil, err)
+	}
+	for _, section := range doc.Sections {
+		if section != nil && section.ID == sectionID {
+			return strings.Split(content, "\n"), section, nil
+		}
+	}
+	return nil, nil, newError(ErrorRefNotFound, fmt.Sprintf("target section not found: %s", sectionID), "Use an existing section slug/id or heading text", nil, nil)
+}
+
+func insertLineAt(destPath string, lines []string, insertIdx int, line string) error {
 	newLines := make([]string, 0, len(lines)+1)
 	newLines = append(newLines, lines[:insertIdx]...)
 	newLines = append(newLines, line)
 	newLines = append(newLines, lines[insertIdx:]...)
 
 	if err := atomicfile.WriteFile(destPath, []byte(strings.Join(newLines, "\n")), 0o644); err != nil {
-		return 0, addFileWriteError(destPath, "failed to write updated file", "Check that the target file is writable", err)
+		return addFileWriteError(destPath, "failed to write updated file", "Check that the target file is writable", err)
 	}
-	return insertedLine, nil
+	return nil
 }
 
 func appendUnderHeading(destPath, line, heading string) (int, error) {
-	content, err := os.ReadFile(destPath)
+	content, err := vaultcrypt.ReadFile(destPath)
 	if err != nil {
 		return 0, addFileReadError(destPath, "failed to read target file", "Check that the target file exists and is readable", err)
 	}
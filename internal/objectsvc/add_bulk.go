@@ -9,6 +9,7 @@ import (
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/paths"
 	"github.com/aidanlsb/raven/internal/vault"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 type AddBulkRequest struct {
@@ -17,6 +18,7 @@ type AddBulkRequest struct {
 	ObjectIDs    []string
 	Line         string
 	HeadingSpec  string
+	Position     string
 	ParseOptions *parser.ParseOptions
 }
 
@@ -79,7 +81,7 @@ func PreviewAddBulk(req AddBulkRequest) (*AddBulkPreview, error) {
 		}
 
 		if strings.Contains(id, "#") {
-			content, err := os.ReadFile(filePath)
+			content, err := vaultcrypt.ReadFile(filePath)
 			if err != nil {
 				skipped = append(skipped, AddBulkResult{ID: id, Status: "skipped", Reason: fmt.Sprintf("read error: %v", err)})
 				continue
@@ -119,8 +121,15 @@ func PreviewAddBulk(req AddBulkRequest) (*AddBulkPreview, error) {
 			targetObjectID = resolvedTarget
 		}
 
+		if req.Position == AddPositionAfterHeading && targetObjectID == "" {
+			skipped = append(skipped, AddBulkResult{ID: id, Status: "skipped", Reason: "--position after-heading needs a section target"})
+			continue
+		}
+
 		details := fmt.Sprintf("append: %s", req.Line)
-		if targetObjectID != "" {
+		if req.Position == AddPositionAfterHeading {
+			details = fmt.Sprintf("insert after heading of %s: %s", targetObjectID, req.Line)
+		} else if targetObjectID != "" {
 			details = fmt.Sprintf("append within %s: %s", targetObjectID, req.Line)
 		}
 		items = append(items, AddBulkPreviewItem{
@@ -193,7 +202,16 @@ func ApplyAddBulk(req AddBulkRequest, onAdded func(filePath string)) (*AddBulkSu
 			targetObjectID = resolvedTarget
 		}
 
-		if _, err := AppendToFile(req.VaultPath, filePath, req.Line, captureCfg, req.VaultConfig, false, targetObjectID, req.ParseOptions); err != nil {
+		if req.Position == AddPositionAfterHeading {
+			if targetObjectID == "" {
+				err = newError(ErrorInvalidInput, "--position after-heading needs a section target", "", nil, nil)
+			} else {
+				_, err = InsertAfterHeading(req.VaultPath, filePath, req.Line, targetObjectID, req.ParseOptions)
+			}
+		} else {
+			_, err = AppendToFile(req.VaultPath, filePath, req.Line, captureCfg, req.VaultConfig, false, targetObjectID, req.ParseOptions)
+		}
+		if err != nil {
 			result.Status = "error"
 			result.Reason = fmt.Sprintf("append failed: %v", err)
 			errorCount++
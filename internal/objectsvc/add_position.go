@@ -0,0 +1,83 @@
+package objectsvc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+// Positions accepted by add --position for section targets.
+const (
+	AddPositionEnd          = "end"
+	AddPositionAfterHeading = "after-heading"
+)
+
+// ParseAddPosition validates an add --position value. Empty means the end of
+// the target section.
+func ParseAddPosition(raw string) (string, error) {
+	switch position := strings.ToLower(strings.TrimSpace(raw)); position {
+	case "", AddPositionEnd:
+		return AddPositionEnd, nil
+	case AddPositionAfterHeading:
+		return position, nil
+	default:
+		return "", newError(ErrorInvalidInput, fmt.Sprintf("unknown position: %s", raw), "Use --position end or --position after-heading", nil, nil)
+	}
+}
+
+// InsertAfterHeading inserts line directly below the heading of sectionID and
+// returns the 1-indexed line it was written to.
+func InsertAfterHeading(vaultPath, destPath, line, sectionID string, parseOpts *parser.ParseOptions) (int, error) {
+	lines, target, err := readTargetSection(vaultPath, destPath, sectionID, parseOpts)
+	if err != nil {
+		return 0, err
+	}
+
+	insertIdx := target.LineStart
+	if insertIdx < 0 {
+		insertIdx = 0
+	}
+	if insertIdx > len(lines) {
+		insertIdx = len(lines)
+	}
+	if err := insertLineAt(destPath, lines, insertIdx, line); err != nil {
+		return 0, err
+	}
+	return insertIdx + 1, nil
+}
+
+// AppendToLine appends text to the end of an existing body line, so a trait
+// can be attached to the task or bullet it describes. lineNum is 1-indexed.
+func AppendToLine(destPath string, lineNum int, text string) (int, error) {
+	contentBytes, err := vaultcrypt.ReadFile(destPath)
+	if err != nil {
+		return 0, addFileReadError(destPath, "failed to read target file", "Check that the target file exists and is readable", err)
+	}
+	lines := strings.Split(string(contentBytes), "\n")
+	lineCount := len(lines)
+	if lineCount > 0 && lines[lineCount-1] == "" {
+		lineCount--
+	}
+	if lineNum < 1 || lineNum > lineCount {
+		return 0, newError(ErrorInvalidInput, fmt.Sprintf("line %d is out of range (file has %d lines)", lineNum, lineCount), "Pass a line number from the target file", nil, nil)
+	}
+	if _, end, ok := parser.FrontmatterBounds(lines); ok && (end < 0 || lineNum <= end+1) {
+		return 0, newError(ErrorInvalidInput, fmt.Sprintf("line %d is inside frontmatter", lineNum), "Use 'rvn set' to change frontmatter fields", nil, nil)
+	}
+
+	idx := lineNum - 1
+	existing := strings.TrimRight(lines[idx], " \t")
+	if strings.TrimSpace(existing) == "" {
+		lines[idx] = text
+	} else {
+		lines[idx] = existing + " " + text
+	}
+
+	if err := atomicfile.WriteFile(destPath, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		return 0, addFileWriteError(destPath, "failed to write updated file", "Check that the target file is writable", err)
+	}
+	return lineNum, nil
+}
@@ -224,7 +224,7 @@ func TestResolveAddHeadingTargetReportsParseFailure(t *testing.T) {
 	content := `---
 type: page
 meta:
-  nested: true
+  1: true
 ---
 # Broken
 `
@@ -245,3 +245,61 @@ meta:
 		t.Fatalf("error code = %q, want %q", svcErr.Code, ErrorInvalidInput)
 	}
 }
+
+func TestInsertAfterHeadingPlacesLineBelowHeading(t *testing.T) {
+	t.Parallel()
+
+	vaultPath := t.TempDir()
+	destPath := filepath.Join(vaultPath, "project.md")
+	content := "# Project\n\n### Bugs / Fixes\n- Existing item\n\n### Other\n"
+	if err := os.WriteFile(destPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	line, err := InsertAfterHeading(vaultPath, destPath, "@priority(high) First", "project#bugs-fixes", nil)
+	if err != nil {
+		t.Fatalf("InsertAfterHeading failed: %v", err)
+	}
+	if line != 4 {
+		t.Fatalf("line = %d, want 4", line)
+	}
+
+	updated, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if got, want := string(updated), "# Project\n\n### Bugs / Fixes\n@priority(high) First\n- Existing item\n\n### Other\n"; got != want {
+		t.Fatalf("content = %q, want %q", got, want)
+	}
+}
+
+func TestAppendToLine(t *testing.T) {
+	t.Parallel()
+
+	destPath := filepath.Join(t.TempDir(), "tasks.md")
+	content := "---\ntype: page\n---\n- Ship release  \n- Write notes\n"
+	if err := os.WriteFile(destPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	line, err := AppendToLine(destPath, 4, "@due(2026-01-15)")
+	if err != nil {
+		t.Fatalf("AppendToLine failed: %v", err)
+	}
+	if line != 4 {
+		t.Fatalf("line = %d, want 4", line)
+	}
+	updated, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if got, want := string(updated), "---\ntype: page\n---\n- Ship release @due(2026-01-15)\n- Write notes\n"; got != want {
+		t.Fatalf("content = %q, want %q", got, want)
+	}
+
+	for _, lineNum := range []int{0, 2, 3, 6} {
+		if _, err := AppendToLine(destPath, lineNum, "@due(2026-01-15)"); err == nil {
+			t.Fatalf("expected error for line %d", lineNum)
+		}
+	}
+}
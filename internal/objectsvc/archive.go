@@ -0,0 +1,189 @@
+package objectsvc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/fieldmutation"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+type ArchiveByReferenceRequest struct {
+	VaultPath   string
+	VaultConfig *config.VaultConfig
+	Schema      *schema.Schema
+
+	Reference string
+
+	NoMove     bool
+	UpdateRefs bool
+
+	ParseOptions *parser.ParseOptions
+}
+
+type ArchiveResult struct {
+	ObjectID      string   `json:"object_id"`
+	Type          string   `json:"type"`
+	File          string   `json:"file"`
+	State         string   `json:"state"`
+	PreviousState string   `json:"previous_state,omitempty"`
+	Moved         bool     `json:"moved,omitempty"`
+	OldPath       string   `json:"old_path,omitempty"`
+	NewPath       string   `json:"new_path,omitempty"`
+	UpdatedRefs   []string `json:"updated_refs,omitempty"`
+
+	ChangedFilePath string   `json:"-"`
+	WarningMessages []string `json:"-"`
+}
+
+// ArchiveByReference moves an object to its type's archive state and, when
+// the lifecycle names an archive directory, moves the file there.
+func ArchiveByReference(req ArchiveByReferenceRequest) (*ArchiveResult, error) {
+	if strings.TrimSpace(req.VaultPath) == "" {
+		return nil, newError(ErrorInvalidInput, "vault path is required", "", nil, nil)
+	}
+	if req.VaultConfig == nil {
+		return nil, newError(ErrorValidationFailed, "vault config is required", "Fix raven.yaml and try again", nil, nil)
+	}
+	if req.Schema == nil {
+		return nil, newError(ErrorValidationFailed, "schema is required", "Fix schema.yaml and try again", nil, nil)
+	}
+	if strings.TrimSpace(req.Reference) == "" {
+		return nil, newError(ErrorInvalidInput, "reference is required", "Usage: rvn archive <object>", nil, nil)
+	}
+
+	resolved, err := resolveReferenceForMutation(req.VaultPath, req.VaultConfig, req.Schema, req.Reference)
+	if err != nil {
+		return nil, err
+	}
+	if resolved.IsSection {
+		return nil, newError(ErrorInvalidInput, "archive only supports file-level objects", "Use a file-level object ID without a section fragment", nil, nil)
+	}
+
+	contentBytes, err := vaultcrypt.ReadFile(resolved.FilePath)
+	if err != nil {
+		return nil, newError(ErrorFileRead, "failed to read file", "", nil, err)
+	}
+	content := string(contentBytes)
+
+	fm, err := parser.ParseFrontmatter(content)
+	if err != nil {
+		return nil, newError(ErrorInvalidInput, "failed to parse frontmatter", "The file must have YAML frontmatter (---) to archive", nil, err)
+	}
+	if fm == nil {
+		return nil, newError(ErrorInvalidInput, "file has no frontmatter", "The file must have YAML frontmatter (---) to archive", nil, nil)
+	}
+
+	objectType := fm.ObjectType
+	if objectType == "" {
+		objectType = "page"
+	}
+	typeDef := req.Schema.Types[objectType]
+	if typeDef == nil || typeDef.Lifecycle == nil {
+		return nil, newError(
+			ErrorInvalidInput,
+			fmt.Sprintf("type '%s' has no lifecycle", objectType),
+			"Declare lifecycle states for the type in schema.yaml",
+			nil,
+			nil,
+		)
+	}
+	lifecycle := typeDef.Lifecycle
+	field := lifecycle.StateField()
+	state := lifecycle.ArchiveTarget()
+	previousState, _ := fm.Fields[field].AsString()
+
+	newContent, warningMessages, err := fieldmutation.PrepareValidatedFrontmatterMutationValues(
+		content,
+		fm,
+		objectType,
+		map[string]schema.FieldValue{field: schema.String(state)},
+		req.Schema,
+		nil,
+		&fieldmutation.RefValidationContext{
+			VaultPath:    req.VaultPath,
+			VaultConfig:  req.VaultConfig,
+			ParseOptions: req.ParseOptions,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	relPath, err := filepath.Rel(req.VaultPath, resolved.FilePath)
+	if err != nil {
+		relPath = resolved.FilePath
+	}
+	relPath = filepath.ToSlash(relPath)
+
+	result := &ArchiveResult{
+		ObjectID:        resolved.ObjectID,
+		Type:            objectType,
+		File:            relPath,
+		State:           state,
+		PreviousState:   previousState,
+		WarningMessages: warningMessages,
+	}
+
+	moveDestRelPath := ""
+	archiveDir := strings.Trim(strings.TrimSpace(lifecycle.ArchiveDirectory), "/")
+	if !req.NoMove && archiveDir != "" && filepath.ToSlash(filepath.Dir(relPath)) != archiveDir {
+		filename := strings.TrimSuffix(filepath.Base(relPath), ".md")
+		moveDestRelPath = paths.EnsureMDExtension(req.VaultConfig.ResolveReferenceToFilePath(archiveDir + "/" + filename))
+		if err := ValidateContentMutationRelPath(req.VaultConfig, moveDestRelPath); err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(filepath.Join(req.VaultPath, moveDestRelPath)); err == nil {
+			return nil, newError(
+				ErrorValidationFailed,
+				fmt.Sprintf("Destination '%s' already exists", moveDestRelPath),
+				"Rename the object or use --no-move to archive it in place",
+				nil,
+				nil,
+			)
+		}
+	}
+
+	if moveDestRelPath == "" {
+		if err := atomicfile.WriteFile(resolved.FilePath, []byte(newContent), 0o644); err != nil {
+			return nil, newError(ErrorFileWrite, "failed to write file", "", nil, err)
+		}
+		result.ChangedFilePath = resolved.FilePath
+		return result, nil
+	}
+
+	moveDestAbsPath := filepath.Join(req.VaultPath, moveDestRelPath)
+	destinationObjectID := req.VaultConfig.FilePathToObjectID(moveDestRelPath)
+	moveResult, err := MoveFile(MoveFileRequest{
+		VaultPath:          req.VaultPath,
+		SourceFile:         resolved.FilePath,
+		DestinationFile:    moveDestAbsPath,
+		SourceObjectID:     resolved.ObjectID,
+		DestinationObject:  destinationObjectID,
+		ReplacementContent: []byte(newContent),
+		UpdateRefs:         req.UpdateRefs,
+		VaultConfig:        req.VaultConfig,
+		Schema:             req.Schema,
+		ParseOptions:       req.ParseOptions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Moved = true
+	result.OldPath = relPath
+	result.NewPath = moveDestRelPath
+	result.File = moveDestRelPath
+	result.ObjectID = destinationObjectID
+	result.UpdatedRefs = moveResult.UpdatedRefs
+	result.WarningMessages = append(result.WarningMessages, moveResult.WarningMessages...)
+	result.ChangedFilePath = moveDestAbsPath
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package objectsvc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/fieldmutation"
+)
+
+const archiveTestSchema = `
+types:
+  project:
+    default_path: projects/
+    fields: {}
+    lifecycle:
+      states: [active, done, archived]
+      transitions:
+        active: [done]
+        done: [archived]
+      archive_directory: archive/projects
+traits: {}
+`
+
+func TestArchiveByReferenceSetsStateAndMoves(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+	writeTestSchema(t, vaultPath, archiveTestSchema)
+	sch := loadTestSchema(t, vaultPath)
+
+	writeArchiveTestFile(t, vaultPath, "projects/site.md", "---\ntype: project\nstatus: done\n---\n# Site\n")
+
+	result, err := ArchiveByReference(ArchiveByReferenceRequest{
+		VaultPath:   vaultPath,
+		VaultConfig: &config.VaultConfig{},
+		Schema:      sch,
+		Reference:   "projects/site",
+	})
+	if err != nil {
+		t.Fatalf("ArchiveByReference: %v", err)
+	}
+	if !result.Moved || result.ObjectID != "archive/projects/site" || result.PreviousState != "done" || result.State != "archived" {
+		t.Fatalf("result = %+v", result)
+	}
+	content, err := os.ReadFile(filepath.Join(vaultPath, "archive/projects/site.md"))
+	if err != nil {
+		t.Fatalf("read archived file: %v", err)
+	}
+	if !strings.Contains(string(content), "status: archived") {
+		t.Fatalf("archived file = %q, want status: archived", content)
+	}
+	if _, err := os.Stat(filepath.Join(vaultPath, "projects/site.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected source file to be moved, stat err = %v", err)
+	}
+}
+
+func TestArchiveByReferenceRejectsDisallowedTransition(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+	writeTestSchema(t, vaultPath, archiveTestSchema)
+	sch := loadTestSchema(t, vaultPath)
+
+	writeArchiveTestFile(t, vaultPath, "projects/site.md", "---\ntype: project\nstatus: active\n---\n")
+
+	_, err := ArchiveByReference(ArchiveByReferenceRequest{
+		VaultPath:   vaultPath,
+		VaultConfig: &config.VaultConfig{},
+		Schema:      sch,
+		Reference:   "projects/site",
+		NoMove:      true,
+	})
+	var validationErr *fieldmutation.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected lifecycle validation error, got %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(vaultPath, "projects/site.md"))
+	if !strings.Contains(string(content), "status: active") {
+		t.Fatalf("file changed despite rejected transition: %q", content)
+	}
+}
+
+func writeArchiveTestFile(t *testing.T, vaultPath, relPath, content string) {
+	t.Helper()
+	filePath := filepath.Join(vaultPath, relPath)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+		t.Fatalf("seed file: %v", err)
+	}
+}
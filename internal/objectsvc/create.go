@@ -1,10 +1,12 @@
 package objectsvc
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/fieldmutation"
 	"github.com/aidanlsb/raven/internal/pages"
 	"github.com/aidanlsb/raven/internal/schema"
 )
@@ -106,7 +108,12 @@ func Create(req CreateRequest) (*CreateResult, error) {
 
 	validatedFields, _, err := validateCreateFieldValues(req.TypeName, fieldValues, req.Schema, nil, createRefValidationContext(req.VaultPath, req.VaultConfig))
 	if err != nil {
-		return nil, newError(ErrorValidationFailed, err.Error(), "Ensure values match the schema field types for this object", nil, err)
+		var details map[string]interface{}
+		var validationErr *fieldmutation.ValidationError
+		if errors.As(err, &validationErr) {
+			details = validationErr.Details()
+		}
+		return nil, newError(ErrorValidationFailed, err.Error(), "Ensure values match the schema field types for this object", details, err)
 	}
 
 	result, err := createObjectPage(createPageRequest{
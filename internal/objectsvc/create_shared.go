@@ -1,6 +1,7 @@
 package objectsvc
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -9,6 +10,7 @@ import (
 	"github.com/aidanlsb/raven/internal/fieldmutation"
 	"github.com/aidanlsb/raven/internal/pages"
 	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/readsvc"
 	"github.com/aidanlsb/raven/internal/schema"
 )
 
@@ -151,7 +153,12 @@ func createObjectPage(req createPageRequest) (*pages.CreateResult, error) {
 		ProtectedPrefixes: protectedPrefixes(req.VaultConfig),
 		ObjectsRoot:       req.ObjectsRoot,
 		PagesRoot:         req.PagesRoot,
+		TemplateQuery:     readsvc.TemplateQuery(req.VaultPath),
 	})
+	var renderErr *pages.TemplateRenderError
+	if errors.As(err, &renderErr) {
+		return nil, newError(ErrorInvalidInput, renderErr.Error(), "Fix the template with 'rvn template write' and try again", nil, err)
+	}
 	if err != nil {
 		return nil, newError(ErrorFileWrite, "failed to create object", "", nil, err)
 	}
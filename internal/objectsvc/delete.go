@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/aidanlsb/raven/internal/history"
 )
 
 type DeleteFileRequest struct {
@@ -67,6 +69,7 @@ func DeleteFile(req DeleteFileRequest) (*DeleteFileResult, error) {
 			destPath = filepath.Join(filepath.Dir(destPath), fmt.Sprintf("%s-%s%s", base, timestamp, ext))
 		}
 
+		history.Capture(req.FilePath)
 		if err := os.Rename(req.FilePath, destPath); err != nil {
 			return nil, newError(ErrorFileWrite, "failed to move file to trash", "", nil, err)
 		}
@@ -77,6 +80,7 @@ func DeleteFile(req DeleteFileRequest) (*DeleteFileResult, error) {
 		}, nil
 
 	case "permanent":
+		history.Capture(req.FilePath)
 		if err := os.Remove(req.FilePath); err != nil {
 			return nil, newError(ErrorFileWrite, "failed to delete file", "", nil, err)
 		}
@@ -10,12 +10,15 @@ import (
 
 	"github.com/aidanlsb/raven/internal/atomicfile"
 	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/history"
 	"github.com/aidanlsb/raven/internal/index"
 	"github.com/aidanlsb/raven/internal/pages"
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/redirects"
 	"github.com/aidanlsb/raven/internal/schema"
 	"github.com/aidanlsb/raven/internal/vault"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 type MoveFileRequest struct {
@@ -26,6 +29,7 @@ type MoveFileRequest struct {
 	DestinationObject  string
 	ReplacementContent []byte
 	UpdateRefs         bool
+	SkipTemplateRefs   bool // Leave references in template files for the caller to rewrite
 	Preview            bool
 	FailOnIndexError   bool
 	VaultConfig        *config.VaultConfig
@@ -70,7 +74,21 @@ var (
 	moveFileWriter   = atomicfile.WriteFile
 )
 
+// MoveFile moves an object or asset file, rewriting references to it when
+// UpdateRefs is set. An applied object move is recorded in redirects.yaml so
+// the old ID keeps resolving.
 func MoveFile(req MoveFileRequest) (*MoveFileResult, error) {
+	result, err := moveFile(req)
+	if err != nil || req.Preview || req.IsAsset {
+		return result, err
+	}
+	if err := redirects.Record(req.VaultPath, req.SourceObjectID, req.DestinationObject); err != nil {
+		result.WarningMessages = append(result.WarningMessages, fmt.Sprintf("Failed to record redirect in %s: %v", redirects.FileName, err))
+	}
+	return result, nil
+}
+
+func moveFile(req MoveFileRequest) (*MoveFileResult, error) {
 	if strings.TrimSpace(req.VaultPath) == "" {
 		return nil, newError(ErrorInvalidInput, "vault path is required", "", nil, nil)
 	}
@@ -128,6 +146,7 @@ func MoveFile(req MoveFileRequest) (*MoveFileResult, error) {
 	if err := os.MkdirAll(filepath.Dir(req.DestinationFile), 0o755); err != nil {
 		return nil, newError(ErrorFileWrite, "failed to create destination directory", "", nil, err)
 	}
+	history.Capture(req.SourceFile)
 	if len(writePlan.destinationContent) > 0 {
 		if err := writeMoveFile(req.DestinationFile, writePlan.destinationContent, sourceSnapshot.perm); err != nil {
 			return nil, newError(ErrorFileWrite, "failed to write moved file", "", nil, err)
@@ -274,6 +293,10 @@ func prepareMoveWritePlan(req MoveFileRequest, refPlans []refUpdatePlan, sourceS
 			continue
 		}
 
+		if req.SkipTemplateRefs && inTemplateDirectory(req.VaultConfig, refPlan.applySourceID) {
+			continue
+		}
+
 		rewrite, err := planRewriteForSource(req.VaultPath, req.VaultConfig, refPlan)
 		if err != nil {
 			var svcErr *Error
@@ -335,8 +358,21 @@ func planRewriteForSource(vaultPath string, vaultCfg *config.VaultConfig, refPla
 	}, nil
 }
 
+// inTemplateDirectory reports whether the file behind sourceID lives in the
+// vault's template directory.
+func inTemplateDirectory(vaultCfg *config.VaultConfig, sourceID string) bool {
+	if vaultCfg == nil {
+		return false
+	}
+	if idx := strings.Index(sourceID, "#"); idx >= 0 {
+		sourceID = sourceID[:idx]
+	}
+	relPath := paths.NormalizeVaultRelPath(vaultCfg.ResolveReferenceToFilePath(sourceID))
+	return strings.HasPrefix(relPath, vaultCfg.GetTemplateDirectory())
+}
+
 func readFileSnapshot(path string) (*fileSnapshot, error) {
-	content, err := os.ReadFile(path)
+	content, err := vaultcrypt.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
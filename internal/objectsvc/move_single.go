@@ -10,6 +10,7 @@ import (
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/paths"
 	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 type MoveByReferenceRequest struct {
@@ -19,6 +20,7 @@ type MoveByReferenceRequest struct {
 	Reference      string
 	Destination    string
 	UpdateRefs     bool
+	SkipTemplates  bool // Leave references in template files for the caller to rewrite
 	SkipTypeCheck  bool
 	Preview        bool
 	ParseOptions   *parser.ParseOptions
@@ -73,7 +75,7 @@ func MoveByReference(req MoveByReferenceRequest) (*MoveByReferenceResult, error)
 		return nil, newError(ErrorUnexpected, "failed to resolve source path", "", nil, err)
 	}
 	sourceRelPath = paths.NormalizeVaultRelPath(sourceRelPath)
-	if err := ValidateContentMutationRelPath(req.VaultConfig, sourceRelPath); err != nil {
+	if err := ValidateContentMutationFilePath(req.VaultPath, req.VaultConfig, sourceFile); err != nil {
 		return nil, err
 	}
 	sourceIsAsset := !paths.HasMDExtension(sourceRelPath)
@@ -160,7 +162,7 @@ func MoveByReference(req MoveByReferenceRequest) (*MoveByReferenceResult, error)
 		sch = schema.New()
 	}
 
-	content, err := os.ReadFile(sourceFile)
+	content, err := vaultcrypt.ReadFile(sourceFile)
 	if err != nil {
 		return nil, newError(ErrorFileRead, "failed to read source file", "", nil, err)
 	}
@@ -204,6 +206,7 @@ func MoveByReference(req MoveByReferenceRequest) (*MoveByReferenceResult, error)
 		SourceObjectID:    sourceID,
 		DestinationObject: req.VaultConfig.FilePathToObjectID(destPath),
 		UpdateRefs:        req.UpdateRefs,
+		SkipTemplateRefs:  req.SkipTemplates,
 		Preview:           req.Preview,
 		FailOnIndexError:  req.FailOnIndexErr,
 		VaultConfig:       req.VaultConfig,
@@ -1,12 +1,15 @@
 package objectsvc
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
 
 	"github.com/aidanlsb/raven/internal/config"
 	ravenignore "github.com/aidanlsb/raven/internal/ignore"
+	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 func ValidateContentMutationFilePath(vaultPath string, vaultCfg *config.VaultConfig, filePath string) error {
@@ -15,6 +18,7 @@ func ValidateContentMutationFilePath(vaultPath string, vaultCfg *config.VaultCon
 	}
 
 	relPath := filePath
+	absPath := filePath
 	if filepath.IsAbs(filePath) {
 		if strings.TrimSpace(vaultPath) == "" {
 			return nil
@@ -24,9 +28,44 @@ func ValidateContentMutationFilePath(vaultPath string, vaultCfg *config.VaultCon
 		if err != nil {
 			return newError(ErrorValidationFailed, "failed to resolve target path", "", nil, err)
 		}
+	} else {
+		absPath = filepath.Join(vaultPath, filePath)
 	}
 
-	return ValidateContentMutationRelPath(vaultCfg, relPath)
+	if err := ValidateContentMutationRelPath(vaultCfg, relPath); err != nil {
+		return err
+	}
+
+	if !vaultCfg.LocksIgnored() && IsFrontmatterLocked(absPath) {
+		normalized := paths.NormalizeVaultRelPath(relPath)
+		return newError(
+			ErrorFileLocked,
+			fmt.Sprintf("file is locked: %s", normalized),
+			"Pass --unlock to modify it, or remove 'locked: true' from its frontmatter",
+			map[string]interface{}{"path": normalized},
+			nil,
+		)
+	}
+	return nil
+}
+
+// IsFrontmatterLocked reports whether the Markdown file at filePath sets
+// `locked: true` in its frontmatter. Unreadable files and non-Markdown files
+// are not locked.
+func IsFrontmatterLocked(filePath string) bool {
+	if !paths.HasMDExtension(filePath) {
+		return false
+	}
+	content, err := vaultcrypt.ReadFile(filePath)
+	if err != nil {
+		return false
+	}
+	fm, err := parser.ParseFrontmatter(string(content))
+	if err != nil || fm == nil {
+		return false
+	}
+	locked, ok := fm.Fields["locked"].AsBool()
+	return ok && locked
 }
 
 func ValidateContentMutationRelPath(vaultCfg *config.VaultConfig, relPath string) error {
@@ -52,6 +91,16 @@ func ValidateContentMutationRelPath(vaultCfg *config.VaultConfig, relPath string
 		)
 	}
 
+	if vaultCfg.IsLockedPath(normalized) {
+		return newError(
+			ErrorFileLocked,
+			fmt.Sprintf("file is locked: %s", normalized),
+			fmt.Sprintf("Run 'rvn unlock %s' or pass --unlock to modify it", normalized),
+			map[string]interface{}{"path": normalized},
+			nil,
+		)
+	}
+
 	if vaultCfg != nil {
 		excludeMatcher, err := ravenignore.NewMatcher(vaultCfg.GetExcludePatterns())
 		if err != nil {
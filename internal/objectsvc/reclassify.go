@@ -14,6 +14,7 @@ import (
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/paths"
 	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 type ReclassifyRequest struct {
@@ -90,7 +91,7 @@ func Reclassify(req ReclassifyRequest) (*ReclassifyResult, error) {
 		return nil, newError(ErrorInvalidInput, "new type is required", "Usage: rvn reclassify <object> <new-type>", nil, nil)
 	}
 
-	contentBytes, err := os.ReadFile(req.FilePath)
+	contentBytes, err := vaultcrypt.ReadFile(req.FilePath)
 	if err != nil {
 		return nil, newError(ErrorFileRead, "failed to read file", "", nil, err)
 	}
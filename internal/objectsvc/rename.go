@@ -0,0 +1,258 @@
+package objectsvc
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+// Rename change types reported in RenameResult.Changes.
+const (
+	RenameChangeMove       = "move"
+	RenameChangeReference  = "reference"
+	RenameChangeSavedQuery = "saved_query"
+	RenameChangeSnapshot   = "snapshot_target"
+	RenameChangeTemplate   = "template_file"
+)
+
+type RenameChange struct {
+	FilePath    string `json:"file_path"`
+	ChangeType  string `json:"change_type"`
+	Description string `json:"description"`
+}
+
+type RenameRequest struct {
+	VaultPath    string
+	VaultConfig  *config.VaultConfig
+	Schema       *schema.Schema
+	Reference    string
+	NewID        string
+	Confirm      bool
+	ParseOptions *parser.ParseOptions
+}
+
+type RenameResult struct {
+	Preview         bool
+	OldID           string
+	NewID           string
+	OldPath         string
+	NewPath         string
+	TotalChanges    int
+	Changes         []RenameChange
+	ChangesApplied  int
+	WarningMessages []string
+	Hint            string
+}
+
+// RenameObject gives a file-level object a new ID. On top of what MoveByReference
+// rewrites (wikilinks, Markdown links, and frontmatter ref values found through
+// the index), it updates saved queries and snapshot targets in raven.yaml and
+// wikilinks in template files, which the index does not cover. References
+// written through one of the object's aliases still resolve after the rename
+// and are left as they are.
+//
+// Without Confirm, nothing is written and the planned changes are returned.
+func RenameObject(req RenameRequest) (*RenameResult, error) {
+	if strings.TrimSpace(req.VaultPath) == "" {
+		return nil, newError(ErrorInvalidInput, "vault path is required", "", nil, nil)
+	}
+	if req.VaultConfig == nil {
+		return nil, newError(ErrorValidationFailed, "vault config is required", "Fix raven.yaml and try again", nil, nil)
+	}
+	newID := strings.TrimSuffix(strings.Trim(strings.TrimSpace(req.NewID), "/"), ".md")
+	if strings.TrimSpace(req.Reference) == "" || newID == "" {
+		return nil, newError(ErrorInvalidInput, "object and new ID are required", "Usage: rvn rename <id> <new-id>", nil, nil)
+	}
+
+	moveReq := MoveByReferenceRequest{
+		VaultPath:      req.VaultPath,
+		VaultConfig:    req.VaultConfig,
+		Schema:         req.Schema,
+		Reference:      req.Reference,
+		Destination:    newID,
+		UpdateRefs:     true,
+		SkipTemplates:  true,
+		SkipTypeCheck:  true,
+		Preview:        true,
+		ParseOptions:   req.ParseOptions,
+		FailOnIndexErr: true,
+	}
+	planned, err := MoveByReference(moveReq)
+	if err != nil {
+		return nil, err
+	}
+	if !paths.HasMDExtension(planned.SourceRelative) {
+		return nil, newError(ErrorInvalidInput, "rename only supports objects", "Use 'rvn move' to move assets", nil, nil)
+	}
+
+	result := &RenameResult{
+		Preview:         !req.Confirm,
+		OldID:           planned.SourceID,
+		NewID:           planned.DestinationID,
+		OldPath:         planned.SourceRelative,
+		NewPath:         planned.DestinationRel,
+		WarningMessages: planned.WarningMessages,
+	}
+	result.Changes = append(result.Changes, RenameChange{
+		FilePath:    planned.SourceRelative,
+		ChangeType:  RenameChangeMove,
+		Description: fmt.Sprintf("move %s → %s", planned.SourceRelative, planned.DestinationRel),
+	})
+	for _, sourceID := range planned.UpdatedRefs {
+		fileID := sourceID
+		if i := strings.Index(fileID, "#"); i >= 0 {
+			fileID = fileID[:i]
+		}
+		result.Changes = append(result.Changes, RenameChange{
+			FilePath:    paths.EnsureMDExtension(req.VaultConfig.ResolveReferenceToFilePath(fileID)),
+			ChangeType:  RenameChangeReference,
+			Description: fmt.Sprintf("update references in %s", sourceID),
+		})
+	}
+
+	queriesChanged, queryChanges := planSavedQueryRenames(req.VaultConfig, result.OldID, result.NewID, result.OldPath)
+	result.Changes = append(result.Changes, queryChanges...)
+
+	templates, templateChanges, err := planTemplateRenames(req.VaultPath, req.VaultConfig, result.OldID, result.NewID)
+	if err != nil {
+		return nil, err
+	}
+	result.Changes = append(result.Changes, templateChanges...)
+	result.TotalChanges = len(result.Changes)
+
+	if !req.Confirm {
+		result.Hint = "Run with --confirm to apply changes"
+		return result, nil
+	}
+
+	moveReq.Preview = false
+	moved, err := MoveByReference(moveReq)
+	if err != nil {
+		return nil, err
+	}
+	result.WarningMessages = moved.WarningMessages
+	result.ChangesApplied = 1 + len(moved.UpdatedRefs)
+
+	templatePaths := make([]string, 0, len(templates))
+	for path := range templates {
+		templatePaths = append(templatePaths, path)
+	}
+	sort.Strings(templatePaths)
+	for _, path := range templatePaths {
+		if err := atomicfile.WriteFile(path, templates[path], 0o644); err != nil {
+			return nil, newError(ErrorFileWrite, fmt.Sprintf("failed to update template %s", path), "The object was renamed; update the template by hand", nil, err)
+		}
+		result.ChangesApplied++
+	}
+
+	if queriesChanged {
+		if err := config.SaveVaultConfig(req.VaultPath, req.VaultConfig); err != nil {
+			return nil, newError(ErrorFileWrite, "failed to update saved queries in raven.yaml", "The object was renamed; update the saved queries by hand", nil, err)
+		}
+		result.ChangesApplied += len(queryChanges)
+	}
+
+	return result, nil
+}
+
+// idTokenChars are the characters that can continue an object ID, so renaming
+// people/freya leaves people/freya-old and people/freya.md alone.
+const idTokenChars = `A-Za-z0-9_./-`
+
+func replaceIDToken(s, oldID, newID string) string {
+	pattern := regexp.MustCompile(`(^|[^` + idTokenChars + `])` + regexp.QuoteMeta(oldID) + `([^` + idTokenChars + `]|$)`)
+	return pattern.ReplaceAllString(s, "${1}"+strings.ReplaceAll(newID, "$", "$$")+"${2}")
+}
+
+// planSavedQueryRenames rewrites saved queries in vaultCfg in place and
+// reports whether anything changed.
+func planSavedQueryRenames(vaultCfg *config.VaultConfig, oldID, newID, oldPath string) (bool, []RenameChange) {
+	names := make([]string, 0, len(vaultCfg.Queries))
+	for name := range vaultCfg.Queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var changes []RenameChange
+	for _, name := range names {
+		q := vaultCfg.Queries[name]
+		if q == nil {
+			continue
+		}
+		if updated := replaceIDToken(q.Query, oldID, newID); updated != q.Query {
+			q.Query = updated
+			changes = append(changes, RenameChange{
+				FilePath:    "raven.yaml",
+				ChangeType:  RenameChangeSavedQuery,
+				Description: fmt.Sprintf("update saved query '%s': %s → %s", name, oldID, newID),
+			})
+		}
+		if q.Snapshot == nil {
+			continue
+		}
+		switch strings.TrimSpace(q.Snapshot.Target) {
+		case oldID, "[[" + oldID + "]]", oldPath:
+			q.Snapshot.Target = newID
+			changes = append(changes, RenameChange{
+				FilePath:    "raven.yaml",
+				ChangeType:  RenameChangeSnapshot,
+				Description: fmt.Sprintf("update snapshot target of '%s': %s → %s", name, oldID, newID),
+			})
+		}
+	}
+	return len(changes) > 0, changes
+}
+
+// planTemplateRenames returns updated content for template files that link to
+// oldID, keyed by absolute path.
+func planTemplateRenames(vaultPath string, vaultCfg *config.VaultConfig, oldID, newID string) (map[string][]byte, []RenameChange, error) {
+	updated := make(map[string][]byte)
+	var changes []RenameChange
+
+	root := filepath.Join(vaultPath, filepath.FromSlash(vaultCfg.GetTemplateDirectory()))
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return updated, nil, nil
+	}
+
+	objectRoot := vaultCfg.GetObjectsRoot()
+	pageRoot := vaultCfg.GetPagesRoot()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() || !paths.HasMDExtension(path) {
+			return nil
+		}
+		content, err := vaultcrypt.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		next := ReplaceAllRefVariants(string(content), oldID, oldID, newID, objectRoot, pageRoot)
+		if next == string(content) {
+			return nil
+		}
+		updated[path] = []byte(next)
+		rel, _ := filepath.Rel(vaultPath, path)
+		changes = append(changes, RenameChange{
+			FilePath:    filepath.ToSlash(rel),
+			ChangeType:  RenameChangeTemplate,
+			Description: fmt.Sprintf("update template links [[%s]] → [[%s]]", oldID, newID),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, nil, newError(ErrorFileRead, "failed to scan template files", "", nil, err)
+	}
+	return updated, changes, nil
+}
@@ -0,0 +1,116 @@
+package objectsvc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestRenameObjectRewritesQueriesAndTemplates(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(testutil.PersonProjectSchema()).
+		WithFile("people/freya.md", "---\ntype: person\nname: Freya\n---\n").
+		WithFile("notes/ref.md", "See [[people/freya]].\n").
+		WithFile("templates/meeting.md", "Attendees: [[people/freya]], [[people/freya-old]]\n").
+		Build()
+
+	sch := loadTestSchema(t, v.Path)
+	indexVaultFiles(t, v.Path, sch, "people/freya.md", "notes/ref.md", "templates/meeting.md")
+
+	cfg := config.DefaultVaultConfig()
+	cfg.Queries = map[string]*config.SavedQuery{
+		"freya-work": {Query: "type:project .owner==people/freya"},
+		"old-freya":  {Query: "type:project .owner==people/freya-old"},
+		"mentions": {
+			Query:    "type:page refs([[people/freya]])",
+			Snapshot: &config.QuerySnapshot{Target: "people/freya"},
+		},
+	}
+
+	req := RenameRequest{
+		VaultPath:   v.Path,
+		VaultConfig: cfg,
+		Schema:      sch,
+		Reference:   "people/freya",
+		NewID:       "people/freya-smith",
+	}
+	preview, err := RenameObject(req)
+	if err != nil {
+		t.Fatalf("RenameObject preview: %v", err)
+	}
+	if !preview.Preview || preview.TotalChanges != 6 {
+		t.Fatalf("preview = %+v, want 6 planned changes", preview)
+	}
+	if _, err := os.Stat(filepath.Join(v.Path, "people/freya.md")); err != nil {
+		t.Fatalf("preview should not move the file: %v", err)
+	}
+
+	req.VaultConfig = config.DefaultVaultConfig()
+	req.VaultConfig.Queries = map[string]*config.SavedQuery{
+		"freya-work": {Query: "type:project .owner==people/freya"},
+		"old-freya":  {Query: "type:project .owner==people/freya-old"},
+		"mentions": {
+			Query:    "type:page refs([[people/freya]])",
+			Snapshot: &config.QuerySnapshot{Target: "people/freya"},
+		},
+	}
+	req.Confirm = true
+	result, err := RenameObject(req)
+	if err != nil {
+		t.Fatalf("RenameObject: %v", err)
+	}
+	if result.ChangesApplied != 6 {
+		t.Fatalf("ChangesApplied = %d, want 6 (changes: %+v)", result.ChangesApplied, result.Changes)
+	}
+
+	if _, err := os.Stat(filepath.Join(v.Path, "people/freya-smith.md")); err != nil {
+		t.Fatalf("expected renamed file to exist: %v", err)
+	}
+	if got := v.ReadFile("notes/ref.md"); !strings.Contains(got, "[[people/freya-smith]]") {
+		t.Fatalf("backlink not updated:\n%s", got)
+	}
+	if got := v.ReadFile("templates/meeting.md"); got != "Attendees: [[people/freya-smith]], [[people/freya-old]]\n" {
+		t.Fatalf("template = %q", got)
+	}
+
+	saved, err := config.LoadVaultConfig(v.Path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if got := saved.Queries["freya-work"].Query; got != "type:project .owner==people/freya-smith" {
+		t.Fatalf("freya-work query = %q", got)
+	}
+	if got := saved.Queries["old-freya"].Query; got != "type:project .owner==people/freya-old" {
+		t.Fatalf("old-freya query should be untouched, got %q", got)
+	}
+	if got := saved.Queries["mentions"].Snapshot.Target; got != "people/freya-smith" {
+		t.Fatalf("snapshot target = %q", got)
+	}
+}
+
+func TestRenameObjectRejectsExistingDestination(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(testutil.PersonProjectSchema()).
+		WithFile("people/freya.md", "---\ntype: person\nname: Freya\n---\n").
+		Build()
+
+	sch := loadTestSchema(t, v.Path)
+	_, err := RenameObject(RenameRequest{
+		VaultPath:   v.Path,
+		VaultConfig: config.DefaultVaultConfig(),
+		Schema:      sch,
+		Reference:   "people/freya",
+		NewID:       "people/freya",
+	})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected existing-destination error, got %v", err)
+	}
+}
@@ -1,13 +1,12 @@
 package objectsvc
 
 import (
-	"os"
-
 	"github.com/aidanlsb/raven/internal/atomicfile"
 	"github.com/aidanlsb/raven/internal/config"
 	"github.com/aidanlsb/raven/internal/fieldmutation"
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 type SetObjectFileRequest struct {
@@ -40,7 +39,7 @@ func SetObjectFile(req SetObjectFileRequest) (*SetObjectFileResult, error) {
 		return nil, err
 	}
 
-	content, err := os.ReadFile(req.FilePath)
+	content, err := vaultcrypt.ReadFile(req.FilePath)
 	if err != nil {
 		return nil, newError(ErrorFileRead, "failed to read file", "", nil, err)
 	}
@@ -3,7 +3,6 @@ package objectsvc
 import (
 	"errors"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/aidanlsb/raven/internal/config"
@@ -11,6 +10,7 @@ import (
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/schema"
 	"github.com/aidanlsb/raven/internal/vault"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 type SetBulkRequest struct {
@@ -77,7 +77,7 @@ func PreviewSetBulk(req SetBulkRequest) (*SetBulkPreview, error) {
 			continue
 		}
 
-		content, err := os.ReadFile(filePath)
+		content, err := vaultcrypt.ReadFile(filePath)
 		if err != nil {
 			skipped = append(skipped, SetBulkResult{ID: id, Status: "skipped", Reason: fmt.Sprintf("read error: %v", err)})
 			continue
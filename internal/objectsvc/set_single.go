@@ -61,7 +61,7 @@ func SetByReference(req SetByReferenceRequest) (*SetByReferenceResult, error) {
 		ObjectID:      resolved.ObjectID,
 		TypedUpdates:  req.TypedUpdates,
 		Schema:        req.Schema,
-		AllowedFields: map[string]bool{"alias": true},
+		AllowedFields: map[string]bool{"alias": true, "locked": true},
 		ParseOptions:  req.ParseOptions,
 		Preview:       req.Preview,
 	})
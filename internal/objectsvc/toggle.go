@@ -0,0 +1,285 @@
+package objectsvc
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vault"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+type ToggleByReferenceRequest struct {
+	VaultPath    string
+	VaultConfig  *config.VaultConfig
+	Schema       *schema.Schema
+	Reference    string
+	Field        string
+	ParseOptions *parser.ParseOptions
+	// Preview computes the next value without writing the file, for dry-run
+	// callers.
+	Preview bool
+}
+
+type ToggleBulkRequest struct {
+	VaultPath    string
+	VaultConfig  *config.VaultConfig
+	Schema       *schema.Schema
+	ObjectIDs    []string
+	Field        string
+	ParseOptions *parser.ParseOptions
+}
+
+// NextToggleValue returns the value a toggle moves field to from current.
+// Bool fields flip, with an unset value counting as false. Enum fields advance
+// to the next declared value, wrapping at the end; an unset or undeclared value
+// moves to the first declared value.
+func NextToggleValue(fieldName string, field *schema.FieldDefinition, current schema.FieldValue, present bool) (schema.FieldValue, error) {
+	if field == nil {
+		return schema.FieldValue{}, newError(ErrorInvalidInput, fmt.Sprintf("field '%s' is not defined for this type", fieldName), "Only schema-declared bool and enum fields can be toggled", nil, nil)
+	}
+
+	switch field.Type {
+	case schema.FieldTypeBool:
+		if !present || current.IsNull() {
+			return schema.Bool(true), nil
+		}
+		if b, ok := current.AsBool(); ok {
+			return schema.Bool(!b), nil
+		}
+		if s, ok := current.AsString(); ok {
+			return schema.Bool(!strings.EqualFold(strings.TrimSpace(s), "true")), nil
+		}
+		return schema.Bool(true), nil
+	case schema.FieldTypeEnum:
+		if len(field.Values) == 0 {
+			return schema.FieldValue{}, newError(ErrorInvalidInput, fmt.Sprintf("enum field '%s' declares no values", fieldName), "Add values to the field in schema.yaml", nil, nil)
+		}
+		if present && !current.IsNull() {
+			if s, ok := current.AsString(); ok {
+				for i, value := range field.Values {
+					if value == s {
+						return schema.String(field.Values[(i+1)%len(field.Values)]), nil
+					}
+				}
+			}
+		}
+		return schema.String(field.Values[0]), nil
+	default:
+		return schema.FieldValue{}, newError(ErrorInvalidInput, fmt.Sprintf("field '%s' is %s, not bool or enum", fieldName, field.Type), "Use 'rvn set' to change other field types", nil, nil)
+	}
+}
+
+// toggleUpdate reads the object's frontmatter and returns the update that
+// toggles field, along with the current fields for previews.
+func toggleUpdate(sch *schema.Schema, filePath, fieldName string) (map[string]schema.FieldValue, map[string]schema.FieldValue, error) {
+	content, err := vaultcrypt.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, newError(ErrorFileRead, "failed to read file", "", nil, err)
+	}
+	fm, err := parser.ParseFrontmatter(string(content))
+	if err != nil {
+		return nil, nil, newError(ErrorInvalidInput, "failed to parse frontmatter", "Failed to parse frontmatter", nil, err)
+	}
+	if fm == nil {
+		return nil, nil, newError(ErrorInvalidInput, "file has no frontmatter", "The file must have YAML frontmatter (---) to toggle fields", nil, nil)
+	}
+
+	objectType := fm.ObjectType
+	if objectType == "" {
+		objectType = "page"
+	}
+	var field *schema.FieldDefinition
+	if typeDef, ok := sch.Types[objectType]; ok && typeDef != nil {
+		field = typeDef.Fields[fieldName]
+	}
+	current, present := fm.Fields[fieldName]
+	next, err := NextToggleValue(fieldName, field, current, present)
+	if err != nil {
+		return nil, nil, err
+	}
+	return map[string]schema.FieldValue{fieldName: next}, fm.Fields, nil
+}
+
+// ToggleByReference flips a bool field or cycles an enum field on a
+// file-level object.
+func ToggleByReference(req ToggleByReferenceRequest) (*SetByReferenceResult, error) {
+	if strings.TrimSpace(req.VaultPath) == "" {
+		return nil, newError(ErrorInvalidInput, "vault path is required", "", nil, nil)
+	}
+	if req.VaultConfig == nil {
+		return nil, newError(ErrorValidationFailed, "vault config is required", "Fix raven.yaml and try again", nil, nil)
+	}
+	if req.Schema == nil {
+		return nil, newError(ErrorValidationFailed, "schema is required", "Fix schema.yaml and try again", nil, nil)
+	}
+	if strings.TrimSpace(req.Reference) == "" {
+		return nil, newError(ErrorInvalidInput, "reference is required", "Usage: rvn toggle <object-id> <field>", nil, nil)
+	}
+	if strings.TrimSpace(req.Field) == "" {
+		return nil, newError(ErrorInvalidInput, "field is required", "Usage: rvn toggle <object-id> <field>", nil, nil)
+	}
+
+	resolved, err := resolveReferenceForMutation(req.VaultPath, req.VaultConfig, req.Schema, req.Reference)
+	if err != nil {
+		return nil, err
+	}
+	if resolved.IsSection {
+		return nil, newError(ErrorInvalidInput, "toggle only supports file-level object frontmatter", "Use a file-level object ID without a section fragment", nil, nil)
+	}
+	if err := ValidateContentMutationFilePath(req.VaultPath, req.VaultConfig, resolved.FilePath); err != nil {
+		return nil, err
+	}
+
+	updates, _, err := toggleUpdate(req.Schema, resolved.FilePath, req.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := SetObjectFile(SetObjectFileRequest{
+		VaultPath:    req.VaultPath,
+		VaultConfig:  req.VaultConfig,
+		FilePath:     resolved.FilePath,
+		ObjectID:     resolved.ObjectID,
+		TypedUpdates: updates,
+		Schema:       req.Schema,
+		ParseOptions: req.ParseOptions,
+		Preview:      req.Preview,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	relPath, _ := filepath.Rel(req.VaultPath, resolved.FilePath)
+	relPath = filepath.ToSlash(relPath)
+	return &SetByReferenceResult{
+		FilePath:        resolved.FilePath,
+		RelativePath:    relPath,
+		ObjectID:        resolved.ObjectID,
+		ObjectType:      result.ObjectType,
+		ResolvedUpdates: result.ResolvedUpdates,
+		WarningMessages: result.WarningMessages,
+		PreviousFields:  result.PreviousFields,
+	}, nil
+}
+
+func PreviewToggleBulk(req ToggleBulkRequest) (*SetBulkPreview, error) {
+	if req.VaultConfig == nil {
+		return nil, newError(ErrorValidationFailed, "vault config is required", "Fix raven.yaml and try again", nil, nil)
+	}
+	if req.Schema == nil {
+		return nil, newError(ErrorValidationFailed, "schema is required", "Fix schema.yaml and try again", nil, nil)
+	}
+
+	items := make([]SetBulkPreviewItem, 0, len(req.ObjectIDs))
+	skipped := make([]SetBulkResult, 0)
+
+	for _, id := range req.ObjectIDs {
+		filePath, reason := resolveToggleBulkTarget(req.VaultPath, req.VaultConfig, id)
+		if reason != "" {
+			skipped = append(skipped, SetBulkResult{ID: id, Status: "skipped", Reason: reason})
+			continue
+		}
+
+		updates, existing, err := toggleUpdate(req.Schema, filePath, req.Field)
+		if err != nil {
+			skipped = append(skipped, SetBulkResult{ID: id, Status: "skipped", Reason: setBulkReasonFromError(err)})
+			continue
+		}
+
+		items = append(items, SetBulkPreviewItem{
+			ID:      id,
+			Action:  "toggle",
+			Changes: formatSetPreviewChanges(existing, updates),
+		})
+	}
+
+	return &SetBulkPreview{
+		Action:  "toggle",
+		Items:   items,
+		Skipped: skipped,
+		Total:   len(req.ObjectIDs),
+	}, nil
+}
+
+func ApplyToggleBulk(req ToggleBulkRequest, onModified func(filePath string)) (*SetBulkSummary, error) {
+	if req.VaultConfig == nil {
+		return nil, newError(ErrorValidationFailed, "vault config is required", "Fix raven.yaml and try again", nil, nil)
+	}
+	if req.Schema == nil {
+		return nil, newError(ErrorValidationFailed, "schema is required", "Fix schema.yaml and try again", nil, nil)
+	}
+
+	results := make([]SetBulkResult, 0, len(req.ObjectIDs))
+	modifiedCount := 0
+	skippedCount := 0
+	errorCount := 0
+
+	for _, id := range req.ObjectIDs {
+		result := SetBulkResult{ID: id}
+
+		filePath, reason := resolveToggleBulkTarget(req.VaultPath, req.VaultConfig, id)
+		if reason != "" {
+			result.Status = "skipped"
+			result.Reason = reason
+			skippedCount++
+			results = append(results, result)
+			continue
+		}
+
+		updates, _, err := toggleUpdate(req.Schema, filePath, req.Field)
+		if err == nil {
+			_, err = SetObjectFile(SetObjectFileRequest{
+				VaultPath:    req.VaultPath,
+				VaultConfig:  req.VaultConfig,
+				FilePath:     filePath,
+				ObjectID:     id,
+				TypedUpdates: updates,
+				Schema:       req.Schema,
+				ParseOptions: req.ParseOptions,
+			})
+		}
+		if err != nil {
+			result.Status = "error"
+			result.Reason = setBulkReasonFromError(err)
+			errorCount++
+			results = append(results, result)
+			continue
+		}
+
+		result.Status = "modified"
+		modifiedCount++
+		if onModified != nil {
+			onModified(filePath)
+		}
+		results = append(results, result)
+	}
+
+	return &SetBulkSummary{
+		Action:   "toggle",
+		Results:  results,
+		Total:    len(results),
+		Skipped:  skippedCount,
+		Errors:   errorCount,
+		Modified: modifiedCount,
+	}, nil
+}
+
+// resolveToggleBulkTarget resolves a bulk ID to its file, or returns the
+// reason the ID is skipped.
+func resolveToggleBulkTarget(vaultPath string, vaultCfg *config.VaultConfig, id string) (string, string) {
+	if strings.Contains(id, "#") {
+		return "", "toggle only supports file-level object frontmatter"
+	}
+	filePath, err := vault.ResolveObjectToFileWithConfig(vaultPath, id, vaultCfg)
+	if err != nil {
+		return "", "object not found"
+	}
+	if err := ValidateContentMutationFilePath(vaultPath, vaultCfg, filePath); err != nil {
+		return "", err.Error()
+	}
+	return filePath, ""
+}
@@ -0,0 +1,133 @@
+package objectsvc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+)
+
+const toggleTestSchema = `
+types:
+  task:
+    default_path: tasks/
+    fields:
+      done:
+        type: bool
+      status:
+        type: enum
+        values: [todo, doing, done]
+      title:
+        type: string
+traits: {}
+`
+
+func seedToggleTask(t *testing.T, vaultPath, name, frontmatter string) string {
+	t.Helper()
+	filePath := filepath.Join(vaultPath, "tasks", name+".md")
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filePath, []byte("---\ntype: task\n"+frontmatter+"---\n"), 0o644); err != nil {
+		t.Fatalf("seed file: %v", err)
+	}
+	return filePath
+}
+
+func TestToggleByReferenceFlipsBoolAndCyclesEnum(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+	writeTestSchema(t, vaultPath, toggleTestSchema)
+	sch := loadTestSchema(t, vaultPath)
+	filePath := seedToggleTask(t, vaultPath, "launch", "status: doing\n")
+
+	steps := []struct {
+		field string
+		want  string
+	}{
+		{"done", "true"}, // unset bool becomes true
+		{"done", "false"},
+		{"status", "done"},
+		{"status", "todo"}, // wraps to the first declared value
+	}
+	for _, step := range steps {
+		result, err := ToggleByReference(ToggleByReferenceRequest{
+			VaultPath:   vaultPath,
+			VaultConfig: &config.VaultConfig{},
+			Schema:      sch,
+			Reference:   "tasks/launch",
+			Field:       step.field,
+		})
+		if err != nil {
+			t.Fatalf("ToggleByReference(%s): %v", step.field, err)
+		}
+		if got := result.ResolvedUpdates[step.field]; got != step.want {
+			t.Fatalf("toggle %s: expected %q, got %q", step.field, step.want, got)
+		}
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if !strings.Contains(string(content), "done: false") || !strings.Contains(string(content), "status: todo") {
+		t.Fatalf("unexpected file content:\n%s", content)
+	}
+
+	if _, err := ToggleByReference(ToggleByReferenceRequest{
+		VaultPath:   vaultPath,
+		VaultConfig: &config.VaultConfig{},
+		Schema:      sch,
+		Reference:   "tasks/launch",
+		Field:       "title",
+	}); err == nil {
+		t.Fatal("expected error toggling a string field")
+	}
+}
+
+func TestToggleBulkUsesEachObjectsCurrentValue(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+	writeTestSchema(t, vaultPath, toggleTestSchema)
+	sch := loadTestSchema(t, vaultPath)
+	seedToggleTask(t, vaultPath, "a", "status: todo\n")
+	seedToggleTask(t, vaultPath, "b", "status: done\n")
+
+	req := ToggleBulkRequest{
+		VaultPath:   vaultPath,
+		VaultConfig: &config.VaultConfig{},
+		Schema:      sch,
+		ObjectIDs:   []string{"tasks/a", "tasks/b", "tasks/missing"},
+		Field:       "status",
+	}
+
+	preview, err := PreviewToggleBulk(req)
+	if err != nil {
+		t.Fatalf("PreviewToggleBulk: %v", err)
+	}
+	if len(preview.Items) != 2 || len(preview.Skipped) != 1 {
+		t.Fatalf("expected 2 items and 1 skipped, got %+v", preview)
+	}
+	if got := preview.Items[0].Changes["status"]; got != "doing (was: todo)" {
+		t.Fatalf("unexpected preview change for tasks/a: %q", got)
+	}
+
+	summary, err := ApplyToggleBulk(req, nil)
+	if err != nil {
+		t.Fatalf("ApplyToggleBulk: %v", err)
+	}
+	if summary.Modified != 2 || summary.Skipped != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	for name, want := range map[string]string{"a": "status: doing", "b": "status: todo"} {
+		content, err := os.ReadFile(filepath.Join(vaultPath, "tasks", name+".md"))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if !strings.Contains(string(content), want) {
+			t.Fatalf("expected %q in tasks/%s, got:\n%s", want, name, content)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/aidanlsb/raven/internal/pages"
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 type ErrorCode = codes.ErrorCode
@@ -23,6 +24,7 @@ const (
 	ErrorInvalidInput     ErrorCode = codes.ErrInvalidInput
 	ErrorFileNotFound     ErrorCode = codes.ErrFileNotFound
 	ErrorFileExists       ErrorCode = codes.ErrFileExists
+	ErrorFileLocked       ErrorCode = codes.ErrFileLocked
 	ErrorRefNotFound      ErrorCode = codes.ErrRefNotFound
 	ErrorRefAmbiguous     ErrorCode = codes.ErrRefAmbiguous
 	ErrorDatabase         ErrorCode = codes.ErrDatabase
@@ -104,7 +106,7 @@ func Upsert(req UpsertRequest) (*UpsertResult, error) {
 	if !strings.HasSuffix(slugified, ".md") {
 		slugified += ".md"
 	}
-	if err := ValidateContentMutationRelPath(req.VaultConfig, slugified); err != nil {
+	if err := ValidateContentMutationFilePath(req.VaultPath, req.VaultConfig, slugified); err != nil {
 		return nil, err
 	}
 
@@ -166,7 +168,7 @@ func Upsert(req UpsertRequest) (*UpsertResult, error) {
 		relPath = createResult.RelativePath
 
 		if req.ReplaceBody {
-			createdBytes, err := os.ReadFile(filePath)
+			createdBytes, err := vaultcrypt.ReadFile(filePath)
 			if err != nil {
 				return nil, newError(ErrorFileRead, "failed to read created object", "", nil, err)
 			}
@@ -182,7 +184,7 @@ func Upsert(req UpsertRequest) (*UpsertResult, error) {
 	} else if err != nil {
 		return nil, newError(ErrorFileRead, "failed to inspect existing object", "", nil, err)
 	} else {
-		originalBytes, err := os.ReadFile(filePath)
+		originalBytes, err := vaultcrypt.ReadFile(filePath)
 		if err != nil {
 			return nil, newError(ErrorFileRead, "failed to read existing object", "", nil, err)
 		}
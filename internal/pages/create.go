@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/dates"
 	"github.com/aidanlsb/raven/internal/frontmatter"
 	"github.com/aidanlsb/raven/internal/paths"
 	"github.com/aidanlsb/raven/internal/schema"
@@ -70,8 +71,29 @@ type CreateOptions struct {
 	// PagesRoot is the root directory for untyped pages (e.g., "pages/").
 	// If set, pages without a type-specific directory go here.
 	PagesRoot string
+
+	// TemplateDate is the date (YYYY-MM-DD) that {{date}} and the other date
+	// variables refer to in the template. Defaults to today.
+	TemplateDate string
+
+	// TemplateQuery runs {{query}} calls in the template. If nil, templates
+	// that call query fail.
+	TemplateQuery template.QueryFunc
 }
 
+// TemplateRenderError reports a template that failed to render, such as one
+// with a syntax error or a failing {{query}}.
+type TemplateRenderError struct {
+	Template string
+	Err      error
+}
+
+func (e *TemplateRenderError) Error() string {
+	return fmt.Sprintf("failed to render template %s: %v", e.Template, e.Err)
+}
+
+func (e *TemplateRenderError) Unwrap() error { return e.Err }
+
 // CreateResult contains information about the created page.
 type CreateResult struct {
 	// FilePath is the absolute path to the created file.
@@ -169,6 +191,10 @@ func Create(opts CreateOptions) (*CreateResult, error) {
 			return nil, fmt.Errorf("failed to load template: %w", err)
 		}
 
+		templateContent, err = template.Render(templateContent, templateVariables(opts, slugifiedPath, allFields))
+		if err != nil {
+			return nil, &TemplateRenderError{Template: templateSpec, Err: err}
+		}
 		if templateContent != "" {
 			content.WriteString(templateContent)
 			// Ensure template ends with newline
@@ -195,6 +221,37 @@ func Create(opts CreateOptions) (*CreateResult, error) {
 	}, nil
 }
 
+// templateVariables builds the variables a template body is rendered with.
+func templateVariables(opts CreateOptions, slugifiedPath string, fields map[string]schema.FieldValue) *template.Variables {
+	title := opts.Title
+	if title == "" {
+		title = path.Base(slugifiedPath)
+	}
+	stringFields := make(map[string]string, len(fields))
+	values := make(map[string]interface{}, len(fields))
+	for name, value := range fields {
+		if value.IsNull() {
+			values[name] = ""
+			continue
+		}
+		values[name] = value.Raw()
+		if str, ok := value.AsString(); ok {
+			stringFields[name] = str
+		} else {
+			stringFields[name] = fmt.Sprint(value.Raw())
+		}
+	}
+
+	vars := template.NewVariables(title, opts.TypeName, path.Base(slugifiedPath), stringFields)
+	if date, err := dates.ParseDate(opts.TemplateDate); err == nil {
+		daily := template.NewDailyVariables(date)
+		vars.Date, vars.Datetime, vars.Year, vars.Month, vars.Day, vars.Weekday = daily.Date, daily.Datetime, daily.Year, daily.Month, daily.Day, daily.Weekday
+	}
+	vars.Values = values
+	vars.Query = opts.TemplateQuery
+	return vars
+}
+
 func validateCreateRelPath(relPath, templateDir string, protectedPrefixes []string) error {
 	normalized := paths.NormalizeVaultRelPath(relPath)
 	if paths.IsProtectedRelPath(normalized, protectedPrefixes) {
@@ -302,7 +359,8 @@ func Slugify(s string) string {
 }
 
 // CreateDailyNoteWithSchema creates a daily note using schema-driven template resolution.
-func CreateDailyNoteWithSchema(vaultPath, dailyDir, dateStr, friendlyTitle string, sch *schema.Schema, templateDir string, protectedPrefixes []string) (*CreateResult, error) {
+// templateQuery runs {{query}} calls in the template and may be nil.
+func CreateDailyNoteWithSchema(vaultPath, dailyDir, dateStr, friendlyTitle string, sch *schema.Schema, templateDir string, protectedPrefixes []string, templateQuery template.QueryFunc) (*CreateResult, error) {
 	targetPath := path.Join(dailyDir, dateStr)
 
 	return Create(CreateOptions{
@@ -313,11 +371,14 @@ func CreateDailyNoteWithSchema(vaultPath, dailyDir, dateStr, friendlyTitle strin
 		Schema:            sch,
 		TemplateDir:       templateDir,
 		ProtectedPrefixes: protectedPrefixes,
+		TemplateDate:      dateStr,
+		TemplateQuery:     templateQuery,
 	})
 }
 
 // CreateDailyNoteWithTemplate creates a daily note with an optional template.
-func CreateDailyNoteWithTemplate(vaultPath, dailyDir, dateStr, friendlyTitle, dailyTemplate, templateDir string, protectedPrefixes []string) (*CreateResult, error) {
+// templateQuery runs {{query}} calls in the template and may be nil.
+func CreateDailyNoteWithTemplate(vaultPath, dailyDir, dateStr, friendlyTitle, dailyTemplate, templateDir string, protectedPrefixes []string, templateQuery template.QueryFunc) (*CreateResult, error) {
 	targetPath := path.Join(dailyDir, dateStr)
 
 	return Create(CreateOptions{
@@ -328,5 +389,7 @@ func CreateDailyNoteWithTemplate(vaultPath, dailyDir, dateStr, friendlyTitle, da
 		TemplateOverride:  dailyTemplate,
 		TemplateDir:       templateDir,
 		ProtectedPrefixes: protectedPrefixes,
+		TemplateDate:      dateStr,
+		TemplateQuery:     templateQuery,
 	})
 }
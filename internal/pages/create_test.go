@@ -8,6 +8,7 @@ import (
 
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/template"
 )
 
 func TestSlugify(t *testing.T) {
@@ -419,8 +420,8 @@ func TestCreateWithTemplate(t *testing.T) {
 		}
 		contentStr := string(content)
 
-		if !strings.Contains(contentStr, "# {{title}}") {
-			t.Error("Expected template content to be copied without interpolation")
+		if !strings.Contains(contentStr, "# Weekly Standup") {
+			t.Error("Expected {{title}} to be rendered with the page title")
 		}
 		if !strings.Contains(contentStr, "## Attendees") {
 			t.Error("Template attendees section not present")
@@ -472,11 +473,48 @@ func TestCreateWithTemplate(t *testing.T) {
 		}
 		contentStr := string(content)
 
-		if !strings.Contains(contentStr, "**Time:** {{field.time}}") {
-			t.Error("Expected field placeholders to remain unexpanded")
+		if !strings.Contains(contentStr, "**Time:** 14:00") {
+			t.Error("Expected {{field.time}} to be rendered")
 		}
-		if !strings.Contains(contentStr, "**Location:** {{field.location}}") {
-			t.Error("Expected field placeholders to remain unexpanded")
+		if !strings.Contains(contentStr, "**Location:** Room A") {
+			t.Error("Expected {{field.location}} to be rendered")
+		}
+	})
+
+	t.Run("with template logic", func(t *testing.T) {
+		templateDir := filepath.Join(tmpDir, "templates")
+		if err := os.MkdirAll(templateDir, 0755); err != nil {
+			t.Fatalf("Failed to create template dir: %v", err)
+		}
+		body := "Follow up {{date +1d}}\n{{range field.attendees}}- {{link .}}\n{{end}}{{range query \"trait:todo\"}}- {{.Content}}\n{{end}}"
+		if err := os.WriteFile(filepath.Join(templateDir, "meeting-logic.md"), []byte(body), 0644); err != nil {
+			t.Fatalf("Failed to write template: %v", err)
+		}
+
+		result, err := Create(CreateOptions{
+			VaultPath:        tmpDir,
+			TypeName:         "meeting",
+			Title:            "Planning",
+			TargetPath:       "meetings/planning",
+			Fields:           map[string]schema.FieldValue{"attendees": schema.Array([]schema.FieldValue{schema.Ref("people/freya"), schema.Ref("people/thor")})},
+			TemplateOverride: "templates/meeting-logic.md",
+			TemplateDir:      "templates/",
+			TemplateDate:     "2026-02-28",
+			TemplateQuery: func(string) ([]template.QueryItem, error) {
+				return []template.QueryItem{{Content: "book room"}}, nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+
+		content, err := os.ReadFile(result.FilePath)
+		if err != nil {
+			t.Fatalf("Failed to read file: %v", err)
+		}
+		want := "Follow up 2026-03-01\n- [[people/freya]]\n- [[people/thor]]\n- book room\n"
+		if !strings.HasSuffix(string(content), want) {
+			t.Errorf("content = %q, want suffix %q", content, want)
 		}
 	})
 }
@@ -20,6 +20,14 @@ type ASTContent struct {
 	Headings []Heading
 	Traits   []TraitAnnotation
 	Refs     []Reference
+
+	// DateMentions holds plain-text date mentions; only populated by
+	// extractFromAST when date mention patterns are supplied.
+	DateMentions []Reference
+
+	// IssueMentions holds issue tracker references; only populated by
+	// extractFromAST when issue reference patterns are supplied.
+	IssueMentions []IssueMention
 }
 
 // ExtractFromAST parses markdown content with goldmark and extracts all
@@ -28,6 +36,13 @@ type ASTContent struct {
 // Code blocks (fenced, indented, inline) are automatically skipped - any
 // @traits or [[references]] inside code will not be extracted.
 func ExtractFromAST(content []byte, startLine int) (*ASTContent, error) {
+	return extractFromAST(content, startLine, nil, nil, "")
+}
+
+// extractFromAST is ExtractFromAST with optional plain-text date mention and
+// issue reference detection. When hashtagTrait is set, #tags are extracted as
+// traits of that name.
+func extractFromAST(content []byte, startLine int, datePatterns []*dateMentionPattern, issuePatterns *issueRefPatterns, hashtagTrait string) (*ASTContent, error) {
 	md := goldmark.New()
 	reader := text.NewReader(content)
 	doc := md.Parser().Parse(reader)
@@ -89,10 +104,22 @@ func ExtractFromAST(content []byte, startLine int) (*ASTContent, error) {
 				// Parse traits
 				traits := ParseTraitAnnotations(seg.text, line)
 				result.Traits = append(result.Traits, traits...)
+				if hashtagTrait != "" {
+					result.Traits = append(result.Traits, extractHashtagTraitsFromLine(seg.text, line, hashtagTrait)...)
+				}
 
 				// Parse refs
 				refs := extractRefsFromText(seg.text, line)
 				result.Refs = append(result.Refs, refs...)
+
+				if len(datePatterns) > 0 {
+					mentions := extractDateMentionsFromLine(seg.text, line, datePatterns)
+					result.DateMentions = append(result.DateMentions, mentions...)
+				}
+				if issuePatterns != nil {
+					issues := extractIssueMentionsFromLine(seg.text, line, issuePatterns)
+					result.IssueMentions = append(result.IssueMentions, issues...)
+				}
 			}
 			result.Refs = append(result.Refs, extractMarkdownAssetRefs(processNode, content, lineStarts, startLine)...)
 
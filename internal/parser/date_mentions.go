@@ -0,0 +1,212 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aidanlsb/raven/internal/dates"
+	"github.com/aidanlsb/raven/internal/wikilink"
+)
+
+// DefaultDateMentionFormat is the ISO date format always recognized when
+// date mention linking is enabled.
+const DefaultDateMentionFormat = "YYYY-MM-DD"
+
+// dateMentionPattern is a compiled date mention format.
+type dateMentionPattern struct {
+	re                      *regexp.Regexp
+	yearIdx, monIdx, dayIdx int
+}
+
+var dateMentionPatternCache sync.Map // format -> *dateMentionPattern
+
+// ValidateDateMentionFormat reports whether format is a usable date mention
+// format. Formats are built from the tokens YYYY, MM, M, DD and D; every other
+// character matches literally (e.g. "MM/DD/YYYY", "D.M.YYYY").
+func ValidateDateMentionFormat(format string) error {
+	_, err := compileDateMentionFormat(format)
+	return err
+}
+
+func compileDateMentionFormat(format string) (*dateMentionPattern, error) {
+	if cached, ok := dateMentionPatternCache.Load(format); ok {
+		return cached.(*dateMentionPattern), nil
+	}
+
+	var b strings.Builder
+	var order []string
+	rest := format
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "YYYY"):
+			b.WriteString(`(\d{4})`)
+			order = append(order, "year")
+			rest = rest[4:]
+		case strings.HasPrefix(rest, "MM"):
+			b.WriteString(`(\d{2})`)
+			order = append(order, "month")
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "DD"):
+			b.WriteString(`(\d{2})`)
+			order = append(order, "day")
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "M"):
+			b.WriteString(`(\d{1,2})`)
+			order = append(order, "month")
+			rest = rest[1:]
+		case strings.HasPrefix(rest, "D"):
+			b.WriteString(`(\d{1,2})`)
+			order = append(order, "day")
+			rest = rest[1:]
+		default:
+			r, size := utf8.DecodeRuneInString(rest)
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			rest = rest[size:]
+		}
+	}
+
+	pattern := &dateMentionPattern{}
+	for i, part := range order {
+		group := i + 1
+		switch part {
+		case "year":
+			if pattern.yearIdx != 0 {
+				return nil, fmt.Errorf("date format %q repeats the year token", format)
+			}
+			pattern.yearIdx = group
+		case "month":
+			if pattern.monIdx != 0 {
+				return nil, fmt.Errorf("date format %q repeats the month token", format)
+			}
+			pattern.monIdx = group
+		case "day":
+			if pattern.dayIdx != 0 {
+				return nil, fmt.Errorf("date format %q repeats the day token", format)
+			}
+			pattern.dayIdx = group
+		}
+	}
+	if pattern.yearIdx == 0 || pattern.monIdx == 0 || pattern.dayIdx == 0 {
+		return nil, fmt.Errorf("date format %q must contain YYYY, MM (or M) and DD (or D)", format)
+	}
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid date format %q: %w", format, err)
+	}
+	pattern.re = re
+
+	dateMentionPatternCache.Store(format, pattern)
+	return pattern, nil
+}
+
+// compileDateMentionFormats compiles the configured formats, skipping invalid
+// ones. The ISO format is always included first.
+func compileDateMentionFormats(formats []string) []*dateMentionPattern {
+	seen := map[string]bool{}
+	all := append([]string{DefaultDateMentionFormat}, formats...)
+
+	var patterns []*dateMentionPattern
+	for _, format := range all {
+		format = strings.TrimSpace(format)
+		if format == "" || seen[format] {
+			continue
+		}
+		seen[format] = true
+		pattern, err := compileDateMentionFormat(format)
+		if err != nil {
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+// extractDateMentionsFromLine finds plain-text date mentions in a line.
+//
+// Dates inside wikilinks, inline code and trait values are skipped, since
+// those are already indexed as refs or trait dates. TargetRaw is the canonical
+// YYYY-MM-DD date and DisplayText is the text as written.
+func extractDateMentionsFromLine(line string, lineNum int, patterns []*dateMentionPattern) []Reference {
+	if len(patterns) == 0 || !strings.ContainsAny(line, "0123456789") {
+		return nil
+	}
+
+	var excluded []inlineCodeSpan
+	excluded = append(excluded, inlineCodeSpans(line)...)
+	for _, match := range wikilink.FindAllInLine(line, false) {
+		excluded = append(excluded, inlineCodeSpan{start: match.Start, end: match.End})
+	}
+	for _, loc := range TraitHighlightPattern.FindAllStringIndex(line, -1) {
+		excluded = append(excluded, inlineCodeSpan{start: loc[0], end: loc[1]})
+	}
+
+	var mentions []Reference
+	for _, pattern := range patterns {
+		for _, m := range pattern.re.FindAllStringSubmatchIndex(line, -1) {
+			start, end := m[0], m[1]
+			if !isDateMentionBoundary(line, start, end) || spanOverlaps(start, end, excluded) {
+				continue
+			}
+			date := line[m[2*pattern.yearIdx]:m[2*pattern.yearIdx+1]] + "-" +
+				padDatePart(line[m[2*pattern.monIdx]:m[2*pattern.monIdx+1]]) + "-" +
+				padDatePart(line[m[2*pattern.dayIdx]:m[2*pattern.dayIdx+1]])
+			if !dates.IsValidDate(date) {
+				continue
+			}
+			display := line[start:end]
+			mentions = append(mentions, Reference{
+				TargetRaw:   date,
+				DisplayText: &display,
+				Line:        lineNum,
+				Start:       start,
+				End:         end,
+			})
+			excluded = append(excluded, inlineCodeSpan{start: start, end: end})
+		}
+	}
+
+	sort.SliceStable(mentions, func(i, j int) bool {
+		return mentions[i].Start < mentions[j].Start
+	})
+	return mentions
+}
+
+// isDateMentionBoundary reports whether [start, end) is not embedded in a
+// longer word or number.
+func isDateMentionBoundary(line string, start, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRuneInString(line[:start])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	if end < len(line) {
+		r, _ := utf8.DecodeRuneInString(line[end:])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func padDatePart(part string) string {
+	if len(part) == 1 {
+		return "0" + part
+	}
+	return part
+}
+
+func spanOverlaps(start, end int, spans []inlineCodeSpan) bool {
+	for _, span := range spans {
+		if start < span.end && end > span.start {
+			return true
+		}
+	}
+	return false
+}
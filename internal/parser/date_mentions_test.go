@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDocumentDateMentions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		content  string
+		formats  []string
+		wantRaw  []string
+		wantText []string
+	}{
+		{
+			name:     "iso date in prose",
+			content:  "Met with the team on 2026-02-14 to plan.",
+			formats:  []string{},
+			wantRaw:  []string{"2026-02-14"},
+			wantText: []string{"2026-02-14"},
+		},
+		{
+			name:     "configured format is canonicalized",
+			content:  "Deadline moved to 2/9/2026, kickoff 2026-01-05.",
+			formats:  []string{"M/D/YYYY"},
+			wantRaw:  []string{"2026-02-09", "2026-01-05"},
+			wantText: []string{"2/9/2026", "2026-01-05"},
+		},
+		{
+			name:    "wikilinks, code and traits are skipped",
+			content: "See [[2026-02-14]] and `2026-02-15` @due(2026-02-16) done.",
+			formats: []string{},
+		},
+		{
+			name:    "invalid dates and embedded numbers are skipped",
+			content: "Order 12026-02-140 shipped; 2026-13-40 is not a date.",
+			formats: []string{},
+		},
+		{
+			name:    "fenced code is skipped",
+			content: "```\n2026-02-14\n```",
+			formats: []string{},
+		},
+		{
+			name:    "disabled without formats option",
+			content: "Met on 2026-02-14.",
+			formats: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			doc, err := ParseDocumentWithOptions(tt.content, "notes/meeting.md", "", &ParseOptions{DateMentionFormats: tt.formats})
+			if err != nil {
+				t.Fatalf("ParseDocumentWithOptions: %v", err)
+			}
+			var gotRaw, gotText []string
+			for _, mention := range doc.DateMentions {
+				gotRaw = append(gotRaw, mention.TargetRaw)
+				if mention.DisplayText != nil {
+					gotText = append(gotText, *mention.DisplayText)
+				}
+				if mention.SourceID != "notes/meeting" {
+					t.Errorf("mention source = %q, want notes/meeting", mention.SourceID)
+				}
+			}
+			if !reflect.DeepEqual(gotRaw, tt.wantRaw) {
+				t.Errorf("targets = %v, want %v", gotRaw, tt.wantRaw)
+			}
+			if !reflect.DeepEqual(gotText, tt.wantText) {
+				t.Errorf("display text = %v, want %v", gotText, tt.wantText)
+			}
+			for _, ref := range doc.Refs {
+				if ref.TargetRaw != "2026-02-14" {
+					continue
+				}
+				if ref.DisplayText != nil {
+					t.Errorf("explicit wikilink should not pick up mention display text: %#v", ref)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateDateMentionFormat(t *testing.T) {
+	t.Parallel()
+	for _, format := range []string{"YYYY-MM-DD", "MM/DD/YYYY", "D.M.YYYY"} {
+		if err := ValidateDateMentionFormat(format); err != nil {
+			t.Errorf("ValidateDateMentionFormat(%q) unexpected error: %v", format, err)
+		}
+	}
+	for _, format := range []string{"", "YYYY-MM", "DD/DD/YYYY"} {
+		if err := ValidateDateMentionFormat(format); err == nil {
+			t.Errorf("ValidateDateMentionFormat(%q) expected error", format)
+		}
+	}
+}
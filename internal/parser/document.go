@@ -19,6 +19,16 @@ type ParsedDocument struct {
 	Sections   []*ParsedSection
 	Traits     []*ParsedTrait // All traits in this document
 	Refs       []*ParsedRef   // All references in this document
+
+	// DateMentions are plain-text dates in the body (e.g. "2026-02-14"),
+	// recorded as refs to the matching daily note. Only populated when
+	// ParseOptions.DateMentionFormats is set. They are kept apart from Refs so
+	// validation and ref rewriting only see explicit [[wikilinks]].
+	DateMentions []*ParsedRef
+
+	// IssueRefs are Jira keys and GitHub issue URLs in the body. Only
+	// populated when ParseOptions.IssueRefs is set.
+	IssueRefs []*ParsedIssueRef
 }
 
 // ParsedObject represents a parsed file-backed object.
@@ -80,6 +90,18 @@ type ParseOptions struct {
 	// PagesRoot is the root directory for untyped pages (e.g., "pages/").
 	// If set, this prefix is stripped from file paths when computing object IDs.
 	PagesRoot string
+
+	// DateMentionFormats enables plain-text date mention detection when
+	// non-nil. ISO YYYY-MM-DD dates are always detected; additional formats
+	// use the tokens YYYY, MM, M, DD and D (e.g. "MM/DD/YYYY").
+	DateMentionFormats []string
+
+	// IssueRefs enables issue tracker reference detection when non-nil.
+	IssueRefs *IssueRefOptions
+
+	// HashtagTrait, when set, indexes #tags in the body as traits of this
+	// name, so #project/raven is read like @tag(project/raven).
+	HashtagTrait string
 }
 
 // ParseDocument parses a markdown document.
@@ -128,7 +150,17 @@ func ParseDocumentWithOptions(content string, filePath string, vaultPath string,
 
 	// Use goldmark AST to extract all content from the body.
 	// This automatically skips code blocks (fenced, indented, inline).
-	astContent, err := ExtractFromAST([]byte(bodyContent), contentStartLine)
+	var datePatterns []*dateMentionPattern
+	if opts != nil && opts.DateMentionFormats != nil {
+		datePatterns = compileDateMentionFormats(opts.DateMentionFormats)
+	}
+	var issuePatterns *issueRefPatterns
+	hashtagTrait := ""
+	if opts != nil {
+		issuePatterns = compileIssueRefOptions(opts.IssueRefs)
+		hashtagTrait = opts.HashtagTrait
+	}
+	astContent, err := extractFromAST([]byte(bodyContent), contentStartLine, datePatterns, issuePatterns, hashtagTrait)
 	if err != nil {
 		return nil, err
 	}
@@ -203,6 +235,29 @@ func ParseDocumentWithOptions(content string, filePath string, vaultPath string,
 		})
 	}
 
+	var dateMentions []*ParsedRef
+	for _, mention := range astContent.DateMentions {
+		dateMentions = append(dateMentions, &ParsedRef{
+			SourceID:    findScopeForLine(fileID, sections, mention.Line),
+			TargetRaw:   mention.TargetRaw,
+			DisplayText: mention.DisplayText,
+			Line:        mention.Line,
+			Start:       mention.Start,
+			End:         mention.End,
+		})
+	}
+
+	var issueRefs []*ParsedIssueRef
+	for _, mention := range astContent.IssueMentions {
+		issueRefs = append(issueRefs, &ParsedIssueRef{
+			SourceID: findScopeForLine(fileID, sections, mention.Line),
+			Provider: mention.Provider,
+			Key:      mention.Key,
+			URL:      mention.URL,
+			Line:     mention.Line,
+		})
+	}
+
 	computeSectionLineEnds(sections)
 
 	return &ParsedDocument{
@@ -213,6 +268,9 @@ func ParseDocumentWithOptions(content string, filePath string, vaultPath string,
 		Sections:   sections,
 		Traits:     traits,
 		Refs:       refs,
+
+		DateMentions: dateMentions,
+		IssueRefs:    issueRefs,
 	}, nil
 }
 
@@ -99,7 +99,13 @@ func ParseFrontmatter(content string) (*Frontmatter, error) {
 func validateYAMLFieldValue(value interface{}, path string) error {
 	switch v := value.(type) {
 	case map[string]interface{}:
-		return fmt.Errorf("unsupported nested YAML object for field %q", path)
+		for key, item := range v {
+			if err := validateYAMLFieldValue(item, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case map[interface{}]interface{}:
+		return fmt.Errorf("nested YAML object for field %q must have string keys", path)
 	case []interface{}:
 		for i, item := range v {
 			if err := validateYAMLFieldValue(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
@@ -142,11 +148,18 @@ func FieldValueFromYAML(value interface{}) schema.FieldValue {
 			items = append(items, FieldValueFromYAML(item))
 		}
 		return schema.Array(items)
+	case map[string]interface{}:
+		fields := make(map[string]schema.FieldValue, len(v))
+		for key, item := range v {
+			fields[key] = FieldValueFromYAML(item)
+		}
+		return schema.Object(fields)
 	case nil:
 		return schema.Null()
 	default:
-		// Unsupported YAML structures such as nested objects are expected to be
-		// rejected by higher-level validation before reaching this conversion path.
+		// Unsupported YAML structures such as objects with non-string keys are
+		// expected to be rejected by higher-level validation before reaching this
+		// conversion path.
 		return schema.Null()
 	}
 }
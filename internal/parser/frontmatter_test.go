@@ -140,7 +140,7 @@ name: Freya
 			wantNil: true,
 		},
 		{
-			name: "nested YAML object is rejected",
+			name: "nested YAML object",
 			content: `---
 type: person
 address:
@@ -148,16 +148,28 @@ address:
   country: Norway
 ---
 `,
-			wantErr: true,
+			wantType:    "person",
+			wantEndLine: 6,
 		},
 		{
-			name: "nested YAML object inside array is rejected",
+			name: "nested YAML object inside array",
 			content: `---
 type: person
 history:
   - year: 2025
     city: Oslo
 ---
+`,
+			wantType:    "person",
+			wantEndLine: 6,
+		},
+		{
+			name: "nested YAML object with non-string keys is rejected",
+			content: `---
+type: person
+history:
+  1: Oslo
+---
 `,
 			wantErr: true,
 		},
@@ -221,14 +233,35 @@ history:
 	}
 }
 
-func TestFieldValueFromYAML_UnsupportedMapReturnsNull(t *testing.T) {
+func TestFieldValueFromYAML_NestedMapReturnsObject(t *testing.T) {
 	t.Parallel()
 
 	got := FieldValueFromYAML(map[string]interface{}{
-		"city":    "Oslo",
-		"country": "Norway",
+		"city":  "Oslo",
+		"lead":  "[[people/freya]]",
+		"floor": 3,
 	})
 
+	obj, ok := got.AsObject()
+	if !ok {
+		t.Fatalf("expected object, got %v", got)
+	}
+	if city, _ := obj["city"].AsString(); city != "Oslo" {
+		t.Errorf("city = %q, want Oslo", city)
+	}
+	if ref, ok := obj["lead"].AsRef(); !ok || ref != "people/freya" {
+		t.Errorf("lead = %v, want ref people/freya", obj["lead"])
+	}
+	if floor, _ := obj["floor"].AsNumber(); floor != 3 {
+		t.Errorf("floor = %v, want 3", floor)
+	}
+}
+
+func TestFieldValueFromYAML_MapWithNonStringKeysReturnsNull(t *testing.T) {
+	t.Parallel()
+
+	got := FieldValueFromYAML(map[interface{}]interface{}{1: "Oslo"})
+
 	if !got.IsNull() {
 		t.Fatalf("expected null, got %v", got)
 	}
@@ -0,0 +1,59 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/wikilink"
+)
+
+// hashtagPattern matches Obsidian-style #tags. Tags may nest with "/" (as in
+// #project/raven) and must start after whitespace or the start of the line,
+// so anchors in URLs and words such as C# are not tags.
+var hashtagPattern = regexp.MustCompile(`(^|[\s(\[,;])#([\p{L}\p{N}_][\p{L}\p{N}_/-]*)`)
+
+// extractHashtagTraitsFromLine finds #tags in a line and returns each as a
+// trait annotation of traitName with the tag as its value.
+//
+// Tags inside wikilinks, inline code, and URLs are skipped, as are tags made
+// only of digits (#123 usually refers to an issue, not a tag).
+func extractHashtagTraitsFromLine(line string, lineNum int, traitName string) []TraitAnnotation {
+	if traitName == "" || !strings.Contains(line, "#") {
+		return nil
+	}
+
+	excluded := inlineCodeSpans(line)
+	for _, match := range wikilink.FindAllInLine(line, false) {
+		excluded = append(excluded, inlineCodeSpan{start: match.Start, end: match.End})
+	}
+	for _, loc := range bareURLPattern.FindAllStringIndex(line, -1) {
+		excluded = append(excluded, inlineCodeSpan{start: loc[0], end: loc[1]})
+	}
+
+	var traits []TraitAnnotation
+	content := ""
+	for _, m := range hashtagPattern.FindAllStringSubmatchIndex(line, -1) {
+		start, end := m[4]-1, m[5]
+		if spanOverlaps(start, end, excluded) {
+			continue
+		}
+		tag := strings.TrimRight(line[m[4]:m[5]], "/-")
+		if tag == "" || strings.Trim(tag, "0123456789") == "" {
+			continue
+		}
+		if content == "" {
+			content = StripTraitAnnotations(line)
+		}
+		value := schema.String(tag)
+		traits = append(traits, TraitAnnotation{
+			TraitName:   traitName,
+			Value:       &value,
+			Content:     content,
+			Line:        lineNum,
+			StartOffset: start,
+			EndOffset:   start + 1 + len(tag),
+		})
+	}
+	return traits
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDocumentWithOptions_HashtagTraits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		content string
+		trait   string
+		want    []string
+	}{
+		{
+			name:    "tags become traits",
+			content: "Kickoff notes #meeting and #project/raven.",
+			trait:   "tag",
+			want:    []string{"tag=meeting", "tag=project/raven"},
+		},
+		{
+			name:    "configured trait name",
+			content: "- [ ] call back #urgent",
+			trait:   "label",
+			want:    []string{"label=urgent"},
+		},
+		{
+			name:    "headings, anchors, and numbers are not tags",
+			content: "# Heading\n\nSee https://example.com/#intro, C#, [[notes/a#plan]], and #123.",
+			trait:   "tag",
+		},
+		{
+			name:    "inline code and fences are skipped",
+			content: "Run `grep #todo` now.\n\n```\n#not-a-tag\n```\n",
+			trait:   "tag",
+		},
+		{
+			name:    "disabled without a trait",
+			content: "Kickoff notes #meeting.",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			doc, err := ParseDocumentWithOptions(tt.content, "notes/standup.md", "", &ParseOptions{HashtagTrait: tt.trait})
+			if err != nil {
+				t.Fatalf("ParseDocumentWithOptions: %v", err)
+			}
+			var got []string
+			for _, trait := range doc.Traits {
+				got = append(got, trait.TraitType+"="+trait.ValueString())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("traits = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
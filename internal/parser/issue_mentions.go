@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aidanlsb/raven/internal/wikilink"
+)
+
+// Issue tracker providers recognized in content.
+const (
+	IssueProviderJira   = "jira"
+	IssueProviderGitHub = "github"
+)
+
+// IssueRefOptions enables detection of issue tracker references.
+type IssueRefOptions struct {
+	// JiraProjects lists project keys matched as bare mentions (PROJ-123).
+	// Bare keys are only detected for listed projects, since the KEY-123
+	// shape also matches ordinary text such as UTF-8.
+	JiraProjects []string
+
+	// JiraURL is the Jira site. Links to {JiraURL}/browse/KEY-123 are
+	// detected for any project, and bare keys get a browse URL.
+	JiraURL string
+}
+
+// ParsedIssueRef is a mention of an issue in an external tracker.
+type ParsedIssueRef struct {
+	SourceID string // Object or section containing the mention
+	Provider string // IssueProviderJira or IssueProviderGitHub
+	Key      string // PROJ-123 or owner/repo#45
+	URL      string // Canonical issue URL, empty when unknown
+	Line     int
+}
+
+// IssueMention is an issue reference found in one line of text.
+type IssueMention struct {
+	Provider string
+	Key      string
+	URL      string
+	Line     int
+	Start    int
+	End      int
+}
+
+var (
+	githubIssueURLPattern = regexp.MustCompile(`https?://github\.com/([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+)/issues/(\d+)`)
+	jiraKeyPattern        = regexp.MustCompile(`\b([A-Z][A-Z0-9_]+)-(\d+)\b`)
+	bareURLPattern        = regexp.MustCompile(`https?://\S+`)
+
+	jiraBrowsePatternCache sync.Map // Jira site URL -> *regexp.Regexp
+)
+
+// issueRefPatterns is IssueRefOptions prepared for matching.
+type issueRefPatterns struct {
+	jiraProjects map[string]bool
+	jiraURL      string
+	jiraBrowse   *regexp.Regexp
+}
+
+func compileIssueRefOptions(opts *IssueRefOptions) *issueRefPatterns {
+	if opts == nil {
+		return nil
+	}
+	patterns := &issueRefPatterns{jiraProjects: make(map[string]bool)}
+	for _, project := range opts.JiraProjects {
+		if project = strings.ToUpper(strings.TrimSpace(project)); project != "" {
+			patterns.jiraProjects[project] = true
+		}
+	}
+	if jiraURL := strings.TrimRight(strings.TrimSpace(opts.JiraURL), "/"); jiraURL != "" {
+		patterns.jiraURL = jiraURL
+		patterns.jiraBrowse = jiraBrowsePattern(jiraURL)
+	}
+	return patterns
+}
+
+func jiraBrowsePattern(jiraURL string) *regexp.Regexp {
+	if cached, ok := jiraBrowsePatternCache.Load(jiraURL); ok {
+		return cached.(*regexp.Regexp)
+	}
+	pattern := regexp.MustCompile(regexp.QuoteMeta(jiraURL) + `/browse/([A-Z][A-Z0-9_]+-\d+)\b`)
+	jiraBrowsePatternCache.Store(jiraURL, pattern)
+	return pattern
+}
+
+// extractIssueMentionsFromLine finds GitHub issue URLs and Jira keys in a line.
+//
+// Mentions inside wikilinks and inline code are skipped. A Jira key that is
+// part of a URL is only detected through the browse URL pattern, so keys in
+// unrelated links are not picked up.
+func extractIssueMentionsFromLine(line string, lineNum int, patterns *issueRefPatterns) []IssueMention {
+	if patterns == nil || !strings.ContainsAny(line, "0123456789") {
+		return nil
+	}
+
+	var excluded []inlineCodeSpan
+	excluded = append(excluded, inlineCodeSpans(line)...)
+	for _, match := range wikilink.FindAllInLine(line, false) {
+		excluded = append(excluded, inlineCodeSpan{start: match.Start, end: match.End})
+	}
+
+	var mentions []IssueMention
+	for _, m := range githubIssueURLPattern.FindAllStringSubmatchIndex(line, -1) {
+		if spanOverlaps(m[0], m[1], excluded) {
+			continue
+		}
+		owner, repo, number := line[m[2]:m[3]], line[m[4]:m[5]], line[m[6]:m[7]]
+		mentions = append(mentions, IssueMention{
+			Provider: IssueProviderGitHub,
+			Key:      owner + "/" + repo + "#" + number,
+			URL:      "https://github.com/" + owner + "/" + repo + "/issues/" + number,
+			Line:     lineNum,
+			Start:    m[0],
+			End:      m[1],
+		})
+	}
+	if patterns.jiraBrowse != nil {
+		for _, m := range patterns.jiraBrowse.FindAllStringSubmatchIndex(line, -1) {
+			if spanOverlaps(m[0], m[1], excluded) {
+				continue
+			}
+			key := line[m[2]:m[3]]
+			mentions = append(mentions, IssueMention{
+				Provider: IssueProviderJira,
+				Key:      key,
+				URL:      patterns.jiraURL + "/browse/" + key,
+				Line:     lineNum,
+				Start:    m[0],
+				End:      m[1],
+			})
+		}
+	}
+
+	if len(patterns.jiraProjects) > 0 {
+		for _, loc := range bareURLPattern.FindAllStringIndex(line, -1) {
+			excluded = append(excluded, inlineCodeSpan{start: loc[0], end: loc[1]})
+		}
+		for _, m := range jiraKeyPattern.FindAllStringSubmatchIndex(line, -1) {
+			if !patterns.jiraProjects[line[m[2]:m[3]]] || spanOverlaps(m[0], m[1], excluded) {
+				continue
+			}
+			key := line[m[0]:m[1]]
+			mention := IssueMention{Provider: IssueProviderJira, Key: key, Line: lineNum, Start: m[0], End: m[1]}
+			if patterns.jiraURL != "" {
+				mention.URL = patterns.jiraURL + "/browse/" + key
+			}
+			mentions = append(mentions, mention)
+		}
+	}
+
+	sort.SliceStable(mentions, func(i, j int) bool {
+		return mentions[i].Start < mentions[j].Start
+	})
+	return mentions
+}
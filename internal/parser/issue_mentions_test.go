@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDocumentWithOptions_IssueRefs(t *testing.T) {
+	t.Parallel()
+
+	opts := &IssueRefOptions{JiraProjects: []string{"proj"}, JiraURL: "https://acme.atlassian.net/"}
+
+	tests := []struct {
+		name    string
+		content string
+		opts    *IssueRefOptions
+		want    []string
+	}{
+		{
+			name:    "GitHub issue URL",
+			content: "Blocked on https://github.com/acme/widgets/issues/45 for now.",
+			opts:    opts,
+			want:    []string{"github acme/widgets#45 https://github.com/acme/widgets/issues/45"},
+		},
+		{
+			name:    "listed Jira project key gets a browse URL",
+			content: "Tracking this in PROJ-123.",
+			opts:    opts,
+			want:    []string{"jira PROJ-123 https://acme.atlassian.net/browse/PROJ-123"},
+		},
+		{
+			name:    "unlisted keys are ignored",
+			content: "Files are UTF-8 and OTHER-9 is someone else's.",
+			opts:    opts,
+		},
+		{
+			name:    "browse URL matches any project",
+			content: "See https://acme.atlassian.net/browse/OPS-7 today.",
+			opts:    opts,
+			want:    []string{"jira OPS-7 https://acme.atlassian.net/browse/OPS-7"},
+		},
+		{
+			name:    "key inside an unrelated URL is skipped",
+			content: "Logs at https://ci.example.com/PROJ-5/run and PROJ-6.",
+			opts:    opts,
+			want:    []string{"jira PROJ-6 https://acme.atlassian.net/browse/PROJ-6"},
+		},
+		{
+			name:    "inline code and wikilinks are skipped",
+			content: "Run `fix PROJ-1` after [[PROJ-2]] is done.",
+			opts:    opts,
+		},
+		{
+			name:    "disabled without options",
+			content: "Tracking this in PROJ-123.",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			doc, err := ParseDocumentWithOptions(tt.content, "notes/standup.md", "", &ParseOptions{IssueRefs: tt.opts})
+			if err != nil {
+				t.Fatalf("ParseDocumentWithOptions: %v", err)
+			}
+			var got []string
+			for _, ref := range doc.IssueRefs {
+				if ref.SourceID != "notes/standup" {
+					t.Errorf("ref source = %q, want notes/standup", ref.SourceID)
+				}
+				got = append(got, ref.Provider+" "+ref.Key+" "+ref.URL)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issue refs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
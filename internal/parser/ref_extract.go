@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/aidanlsb/raven/internal/schema"
@@ -38,6 +39,18 @@ func ExtractRefsFromFieldValue(fv schema.FieldValue, opts RefExtractOptions) []E
 		return refs
 	}
 
+	if obj, ok := fv.AsObject(); ok {
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			refs = append(refs, ExtractRefsFromFieldValue(obj[key], opts)...)
+		}
+		return refs
+	}
+
 	if s, ok := fv.AsString(); ok {
 		if opts.AllowWikilinksInString {
 			matches := wikilink.FindAllInLine(s, opts.AllowTripleBrackets)
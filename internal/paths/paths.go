@@ -328,8 +328,9 @@ var hardProtectedPrefixes = []string{
 }
 
 var hardProtectedFiles = map[string]struct{}{
-	"raven.yaml":  {},
-	"schema.yaml": {},
+	"raven.yaml":     {},
+	"schema.yaml":    {},
+	"redirects.yaml": {},
 }
 
 // IsProtectedRelPath returns true if relPath (vault-relative) is protected.
@@ -0,0 +1,541 @@
+package publishsvc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	gmparser "github.com/yuin/goldmark/parser"
+
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/readsvc"
+	"github.com/aidanlsb/raven/internal/resolver"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/slugs"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+	"github.com/aidanlsb/raven/internal/wikilink"
+)
+
+const indexURL = "index.html"
+
+// site holds the pages being published and resolves links between them.
+type site struct {
+	rt       *readsvc.Runtime
+	title    string
+	pages    []*sitePage
+	byID     map[string]*sitePage
+	resolver *resolver.Resolver
+	// assets collects vault-relative asset paths linked from published pages.
+	assets map[string]bool
+}
+
+type sitePage struct {
+	Page
+	fields map[string]interface{}
+}
+
+// TemplateData is passed to the page template. Index is set on the index
+// page, Page on every other page.
+type TemplateData struct {
+	Site    TemplateSite
+	Title   string
+	Root    string // Relative path from this page to the site root, e.g. "../"
+	Page    *TemplatePage
+	Index   []TemplateGroup
+	Content template.HTML
+}
+
+type TemplateSite struct {
+	Title string
+	Pages []Page
+}
+
+type TemplatePage struct {
+	Page
+	Fields    []TemplateField
+	Backlinks []TemplateLink
+}
+
+type TemplateField struct {
+	Name  string
+	Value template.HTML
+}
+
+type TemplateLink struct {
+	Title string
+	URL   string
+}
+
+// TemplateGroup lists the published pages of one type on the index page.
+type TemplateGroup struct {
+	Type  string
+	Pages []TemplateLink
+}
+
+func newSite(rt *readsvc.Runtime, objects []fileObject, title string) (*site, error) {
+	res, err := rt.DB.Resolver(index.ResolverOptions{
+		DailyDirectory: rt.VaultCfg.GetDailyDirectory(),
+		Schema:         rt.Schema,
+	})
+	if err != nil {
+		return nil, newError(CodeDatabaseError, "failed to build reference resolver", "Run 'rvn reindex' to rebuild the database", err)
+	}
+
+	s := &site{
+		rt:       rt,
+		title:    title,
+		byID:     make(map[string]*sitePage, len(objects)),
+		resolver: res,
+		assets:   make(map[string]bool),
+	}
+	for _, obj := range objects {
+		page := &sitePage{
+			Page: Page{
+				ID:    obj.ID,
+				Type:  obj.Type,
+				Title: pageTitle(obj, rt.Schema),
+				File:  obj.File,
+				URL:   obj.ID + ".html",
+			},
+			fields: obj.Fields,
+		}
+		s.pages = append(s.pages, page)
+		s.byID[obj.ID] = page
+	}
+	return s, nil
+}
+
+func pageTitle(obj fileObject, sch *schema.Schema) string {
+	if sch != nil {
+		if typeDef := sch.Types[obj.Type]; typeDef != nil && typeDef.NameField != "" {
+			if name, ok := obj.Fields[typeDef.NameField].(string); ok && strings.TrimSpace(name) != "" {
+				return name
+			}
+		}
+	}
+	return path.Base(obj.ID)
+}
+
+func (s *site) pageList() []Page {
+	pages := make([]Page, 0, len(s.pages))
+	for _, page := range s.pages {
+		pages = append(pages, page.Page)
+	}
+	return pages
+}
+
+// render returns the HTML for every page and the index, keyed by path
+// relative to the output directory.
+func (s *site) render(tmpl *template.Template) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(s.pages)+1)
+	sitePages := s.pageList()
+
+	for _, page := range s.pages {
+		raw, err := vaultcrypt.ReadFile(filepath.Join(s.rt.VaultPath, filepath.FromSlash(page.File)))
+		if err != nil {
+			return nil, newError(CodeFileReadError, fmt.Sprintf("failed to read %s", page.File), "", err)
+		}
+		body, _ := readsvc.RenderQueryBlocks(s.rt, stripFrontmatter(string(raw)))
+		content, err := renderMarkdown(s.linkify(body, page))
+		if err != nil {
+			return nil, newError(CodeFileWriteErr, fmt.Sprintf("failed to render %s", page.File), "", err)
+		}
+		backlinks, err := s.backlinks(page)
+		if err != nil {
+			return nil, newError(CodeDatabaseError, fmt.Sprintf("failed to read backlinks for %s", page.ID), "Run 'rvn reindex' to rebuild the database", err)
+		}
+
+		data := TemplateData{
+			Site:    TemplateSite{Title: s.title, Pages: sitePages},
+			Title:   page.Title,
+			Root:    rootPrefix(page.URL),
+			Page:    &TemplatePage{Page: page.Page, Fields: s.fieldRows(page), Backlinks: backlinks},
+			Content: content,
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, newError(CodeConfigInvalid, fmt.Sprintf("publish template failed on %s: %v", page.ID, err), "Fix the template set by publish.template or --template", err)
+		}
+		files[page.URL] = buf.Bytes()
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, TemplateData{
+		Site:  TemplateSite{Title: s.title, Pages: sitePages},
+		Title: s.title,
+		Index: s.indexGroups(),
+	}); err != nil {
+		return nil, newError(CodeConfigInvalid, fmt.Sprintf("publish template failed on the index page: %v", err), "Fix the template set by publish.template or --template", err)
+	}
+	files[indexURL] = buf.Bytes()
+	return files, nil
+}
+
+func (s *site) indexGroups() []TemplateGroup {
+	byType := make(map[string][]TemplateLink)
+	for _, page := range s.pages {
+		byType[page.Type] = append(byType[page.Type], TemplateLink{Title: page.Title, URL: escapePath(page.URL)})
+	}
+	groups := make([]TemplateGroup, 0, len(byType))
+	for _, typeName := range sortedKeys(byType) {
+		links := byType[typeName]
+		sort.SliceStable(links, func(i, j int) bool { return strings.ToLower(links[i].Title) < strings.ToLower(links[j].Title) })
+		groups = append(groups, TemplateGroup{Type: typeName, Pages: links})
+	}
+	return groups
+}
+
+// backlinks lists the published pages that reference page.
+func (s *site) backlinks(page *sitePage) ([]TemplateLink, error) {
+	refs, err := s.rt.DB.Backlinks(page.ID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var links []TemplateLink
+	for _, ref := range refs {
+		sourceID, _, _ := strings.Cut(ref.SourceID, "#")
+		source, ok := s.byID[sourceID]
+		if !ok || source == page || seen[sourceID] {
+			continue
+		}
+		seen[sourceID] = true
+		links = append(links, TemplateLink{Title: source.Title, URL: relativeURL(page.URL, source.URL)})
+	}
+	sort.SliceStable(links, func(i, j int) bool { return strings.ToLower(links[i].Title) < strings.ToLower(links[j].Title) })
+	return links, nil
+}
+
+// fieldRows renders frontmatter fields for the metadata table. Ref fields and
+// wikilinks in values link to their pages when those are published.
+func (s *site) fieldRows(page *sitePage) []TemplateField {
+	var refFields map[string]bool
+	if s.rt.Schema != nil {
+		if typeDef := s.rt.Schema.Types[page.Type]; typeDef != nil {
+			refFields = make(map[string]bool)
+			for name, field := range typeDef.Fields {
+				if field != nil && (field.Type == schema.FieldTypeRef || field.Type == schema.FieldTypeRefArray) {
+					refFields[name] = true
+				}
+			}
+		}
+	}
+
+	var rows []TemplateField
+	for _, name := range sortedKeys(page.fields) {
+		if name == PublishField {
+			continue
+		}
+		rows = append(rows, TemplateField{Name: name, Value: s.fieldValueHTML(page, page.fields[name], refFields[name])})
+	}
+	return rows
+}
+
+func (s *site) fieldValueHTML(page *sitePage, value interface{}, isRef bool) template.HTML {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, string(s.fieldValueHTML(page, item, isRef)))
+		}
+		return template.HTML(strings.Join(parts, ", "))
+	case map[string]interface{}:
+		encoded, _ := json.Marshal(v)
+		return template.HTML(template.HTMLEscapeString(string(encoded)))
+	case string:
+		if target, display, ok := wikilink.ParseExact(v); ok {
+			return s.linkHTML(page, target, display)
+		}
+		if isRef {
+			return s.linkHTML(page, v, nil)
+		}
+		return template.HTML(template.HTMLEscapeString(v))
+	case float64:
+		return template.HTML(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return template.HTML(template.HTMLEscapeString(fmt.Sprint(v)))
+	}
+}
+
+func (s *site) linkHTML(from *sitePage, target string, display *string) template.HTML {
+	label := target
+	if display != nil {
+		label = *display
+	}
+	href, _ := s.linkURL(from, target)
+	if href == "" {
+		return template.HTML(template.HTMLEscapeString(label))
+	}
+	return template.HTML(fmt.Sprintf(`<a href="%s">%s</a>`, template.HTMLEscapeString(href), template.HTMLEscapeString(label)))
+}
+
+// linkURL resolves a wikilink target to a URL relative to from, or "" when
+// the target is not published. Linked assets are recorded for copying.
+func (s *site) linkURL(from *sitePage, target string) (href string, asset bool) {
+	// Assets are not indexed objects, so they are matched by path.
+	if assetPath := path.Clean(strings.TrimPrefix(strings.TrimSpace(target), "/")); s.isAsset(assetPath) {
+		s.assets[assetPath] = true
+		return relativeURL(from.URL, assetPath), true
+	}
+	resolved := s.resolver.Resolve(target)
+	if resolved.TargetID == "" || resolved.Ambiguous {
+		return "", false
+	}
+	fileID, fragment, hasFragment := strings.Cut(resolved.TargetID, "#")
+	if page, ok := s.byID[fileID]; ok {
+		href = relativeURL(from.URL, page.URL)
+		if hasFragment {
+			href += "#" + url.PathEscape(fragment)
+		}
+		return href, false
+	}
+	return "", false
+}
+
+func (s *site) isAsset(id string) bool {
+	if strings.HasPrefix(id, "../") || id == ".." {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(id))
+	if ext == "" || ext == ".md" {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(s.rt.VaultPath, filepath.FromSlash(id)))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// linkify rewrites wikilinks in markdown outside code as markdown links.
+// Links to unpublished pages become plain text.
+func (s *site) linkify(body string, from *sitePage) string {
+	lines := strings.Split(body, "\n")
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		lines[i] = s.linkifyLine(line, from)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (s *site) linkifyLine(line string, from *sitePage) string {
+	// Odd segments between backticks are inline code and left alone.
+	segments := strings.Split(line, "`")
+	for i := 0; i < len(segments); i += 2 {
+		segments[i] = s.linkifyText(segments[i], from)
+	}
+	return strings.Join(segments, "`")
+}
+
+func (s *site) linkifyText(text string, from *sitePage) string {
+	matches := wikilink.FindAllInLine(text, false)
+	if len(matches) == 0 {
+		return text
+	}
+	var out strings.Builder
+	last := 0
+	for _, m := range matches {
+		start := m.Start
+		embed := start > 0 && text[start-1] == '!'
+		if embed {
+			start--
+		}
+		out.WriteString(text[last:start])
+
+		label := m.Target
+		if m.DisplayText != nil {
+			label = *m.DisplayText
+		}
+		label = escapeLinkLabel(label)
+		href, asset := s.linkURL(from, m.Target)
+		switch {
+		case href == "":
+			out.WriteString(label)
+		case embed && asset:
+			fmt.Fprintf(&out, "![%s](<%s>)", label, href)
+		default:
+			fmt.Fprintf(&out, "[%s](<%s>)", label, href)
+		}
+		last = m.End
+	}
+	out.WriteString(text[last:])
+	return out.String()
+}
+
+func escapeLinkLabel(label string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(label)
+}
+
+func stripFrontmatter(content string) string {
+	lines := strings.Split(content, "\n")
+	if _, end, ok := parser.FrontmatterBounds(lines); ok && end > 0 {
+		return strings.Join(lines[end+1:], "\n")
+	}
+	return content
+}
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM, extension.Footnote),
+	goldmark.WithParserOptions(gmparser.WithAutoHeadingID()),
+)
+
+// renderMarkdown converts markdown to HTML. Raw HTML in the source is
+// omitted, and heading IDs match Raven's section slugs so section links land
+// on the right heading.
+func renderMarkdown(source string) (template.HTML, error) {
+	var buf bytes.Buffer
+	ctx := gmparser.NewContext(gmparser.WithIDs(&headingIDs{used: make(map[string]int)}))
+	if err := markdown.Convert([]byte(source), &buf, gmparser.WithContext(ctx)); err != nil {
+		return "", err
+	}
+	return template.HTML(buf.String()), nil
+}
+
+// headingIDs generates heading anchors the way the parser names sections.
+type headingIDs struct {
+	used map[string]int
+}
+
+func (h *headingIDs) Generate(value []byte, _ ast.NodeKind) []byte {
+	base := slugs.HeadingSlug(string(value))
+	if base == "" {
+		base = "section"
+	}
+	next := h.used[base] + 1
+	for {
+		slug := base
+		if next > 1 {
+			slug = base + "-" + strconv.Itoa(next)
+		}
+		if _, exists := h.used[slug]; !exists {
+			h.used[base] = next
+			if slug != base {
+				h.used[slug] = 1
+			}
+			return []byte(slug)
+		}
+		next++
+	}
+}
+
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)]++
+}
+
+// rootPrefix is the relative path from a page URL back to the site root.
+func rootPrefix(pageURL string) string {
+	return strings.Repeat("../", strings.Count(pageURL, "/"))
+}
+
+func relativeURL(fromURL, toURL string) string {
+	return rootPrefix(fromURL) + escapePath(toURL)
+}
+
+func escapePath(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// loadTemplate parses the page template at templatePath (vault-relative), or
+// the built-in template when it is empty.
+func loadTemplate(vaultPath, templatePath string) (*template.Template, error) {
+	source := defaultTemplate
+	if templatePath != "" {
+		full := templatePath
+		if !filepath.IsAbs(full) {
+			full = filepath.Join(vaultPath, filepath.FromSlash(templatePath))
+		}
+		data, err := os.ReadFile(full)
+		if err != nil {
+			return nil, newError(CodeFileReadError, fmt.Sprintf("failed to read publish template %s", templatePath), "Check publish.template or --template", err)
+		}
+		source = string(data)
+	}
+	tmpl, err := template.New("page").Parse(source)
+	if err != nil {
+		return nil, newError(CodeConfigInvalid, fmt.Sprintf("invalid publish template: %v", err), "Fix the template's html/template syntax", err)
+	}
+	return tmpl, nil
+}
+
+const defaultTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}{{if .Page}} · {{.Site.Title}}{{end}}</title>
+<style>
+body { font: 16px/1.6 system-ui, sans-serif; max-width: 46rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+a { color: #0b5cad; }
+nav { font-size: .9rem; margin-bottom: 1.5rem; }
+table.fields { border-collapse: collapse; margin-bottom: 1.5rem; font-size: .9rem; }
+table.fields th, table.fields td { text-align: left; padding: .25rem .75rem .25rem 0; border-bottom: 1px solid #eee; vertical-align: top; }
+.type { color: #666; font-size: .85rem; text-transform: uppercase; letter-spacing: .05em; }
+pre { background: #f6f6f6; padding: .75rem; overflow-x: auto; }
+.backlinks { margin-top: 2.5rem; border-top: 1px solid #ddd; padding-top: 1rem; font-size: .95rem; }
+</style>
+</head>
+<body>
+<nav><a href="{{.Root}}index.html">{{.Site.Title}}</a></nav>
+{{- if .Page}}
+<article>
+<div class="type">{{.Page.Type}}</div>
+<h1>{{.Title}}</h1>
+{{- if .Page.Fields}}
+<table class="fields">
+{{- range .Page.Fields}}
+<tr><th>{{.Name}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{.Content}}
+</article>
+{{- if .Page.Backlinks}}
+<section class="backlinks">
+<h2>Linked from</h2>
+<ul>
+{{- range .Page.Backlinks}}
+<li><a href="{{.URL}}">{{.Title}}</a></li>
+{{- end}}
+</ul>
+</section>
+{{- end}}
+{{- else}}
+<h1>{{.Title}}</h1>
+{{- range .Index}}
+<h2>{{.Type}}</h2>
+<ul>
+{{- range .Pages}}
+<li><a href="{{.URL}}">{{.Title}}</a></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`
@@ -0,0 +1,382 @@
+// Package publishsvc renders vault pages to a static HTML site: wikilinks
+// become hyperlinks, frontmatter becomes a metadata table, and each page lists
+// the published pages that link to it.
+package publishsvc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/readsvc"
+)
+
+type Code = codes.ErrorCode
+
+const (
+	CodeInvalidInput  Code = codes.ErrInvalidInput
+	CodeConfigInvalid Code = codes.ErrConfigInvalid
+	CodeDatabaseError Code = codes.ErrDatabase
+	CodeQueryInvalid  Code = codes.ErrQueryInvalid
+	CodeFileReadError Code = codes.ErrFileRead
+	CodeFileWriteErr  Code = codes.ErrFileWrite
+)
+
+type Error struct {
+	Code       Code
+	Message    string
+	Suggestion string
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e == nil {
+		return ""
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return string(e.Code)
+}
+
+func (e *Error) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+func newError(code Code, message, suggestion string, err error) *Error {
+	return &Error{Code: code, Message: message, Suggestion: suggestion, Err: err}
+}
+
+func AsError(err error) (*Error, bool) {
+	var svcErr *Error
+	if errors.As(err, &svcErr) {
+		return svcErr, true
+	}
+	return nil, false
+}
+
+// Ways pages are selected for publishing.
+const (
+	SelectFrontmatter = "frontmatter" // Pages with publish: true
+	SelectQuery       = "query"
+	SelectAll         = "all"
+)
+
+// PublishField is the frontmatter flag that marks a page for publishing.
+const PublishField = "publish"
+
+// manifestName lists the files the last publish wrote, so the next run can
+// remove pages that are no longer published and refuse to write into a
+// directory it did not create.
+const manifestName = ".raven-publish.json"
+
+type PublishRequest struct {
+	VaultPath string
+	Output    string // Overrides publish.output; relative paths are vault-relative
+	Query     string // Overrides publish.query
+	All       bool   // Publish every page
+	Template  string // Overrides publish.template
+	DryRun    bool   // Select pages without writing anything
+}
+
+// Page is one published vault file.
+type Page struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	File  string `json:"file"`
+	URL   string `json:"url"` // Relative to the site root
+}
+
+type PublishResult struct {
+	Output    string
+	Selection string
+	Pages     []Page
+	Assets    []string // Vault-relative assets copied because published pages link to them
+	Removed   int      // Stale files from the previous publish that were deleted
+	DryRun    bool
+}
+
+type manifest struct {
+	Files []string `json:"files"`
+}
+
+// Publish selects pages and renders them, with an index page, to the output
+// directory.
+func Publish(req PublishRequest) (*PublishResult, error) {
+	rt, err := readsvc.NewRuntime(req.VaultPath, readsvc.RuntimeOptions{OpenDB: true})
+	if err != nil {
+		if errors.Is(err, index.ErrIndexLocked) {
+			return nil, newError(CodeDatabaseError, "index is locked", "Try again once the running reindex finishes", err)
+		}
+		return nil, newError(CodeDatabaseError, "failed to open the vault index", "Run 'rvn reindex' to rebuild the database", err)
+	}
+	defer rt.Close()
+	if _, err := readsvc.SmartReindex(rt); err != nil {
+		return nil, newError(CodeDatabaseError, "failed to refresh index", "Run 'rvn reindex' to rebuild the database", err)
+	}
+
+	cfg := rt.VaultCfg.GetPublishConfig()
+	output := firstNonEmpty(req.Output, cfg.Output)
+	if output == "" {
+		return nil, newError(CodeInvalidInput, "no output directory", "Pass --output or set publish.output in raven.yaml", nil)
+	}
+	if !filepath.IsAbs(output) {
+		output = filepath.Join(req.VaultPath, output)
+	}
+	output = filepath.Clean(output)
+	if vaultAbs, err := filepath.Abs(req.VaultPath); err == nil {
+		if outAbs, err := filepath.Abs(output); err == nil && (outAbs == vaultAbs || contains(outAbs, vaultAbs)) {
+			return nil, newError(CodeInvalidInput, "the output directory cannot be the vault or contain it", "Choose a subdirectory or a path outside the vault", nil)
+		}
+	}
+
+	objects, err := loadFileObjects(rt.DB)
+	if err != nil {
+		return nil, newError(CodeDatabaseError, "failed to read objects from index", "Run 'rvn reindex' to rebuild the database", err)
+	}
+
+	selection := SelectFrontmatter
+	queryString := firstNonEmpty(req.Query, cfg.Query)
+	switch {
+	case req.All:
+		selection = SelectAll
+	case queryString != "":
+		selection = SelectQuery
+	}
+	// Template files are indexed like pages but are never published.
+	templateDir := strings.Trim(rt.VaultCfg.GetTemplateDirectory(), "/") + "/"
+	objects = slices.DeleteFunc(objects, func(obj fileObject) bool {
+		return strings.HasPrefix(obj.File, templateDir)
+	})
+	selected, err := selectObjects(rt, objects, selection, queryString)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) == 0 {
+		return nil, newError(CodeInvalidInput, "no pages selected for publishing", "Add 'publish: true' to a page's frontmatter, or pass --query or --all", nil)
+	}
+
+	s, err := newSite(rt, selected, siteTitle(cfg.Title, req.VaultPath))
+	if err != nil {
+		return nil, err
+	}
+	result := &PublishResult{Output: output, Selection: selection, Pages: s.pageList(), Assets: []string{}, DryRun: req.DryRun}
+	if req.DryRun {
+		return result, nil
+	}
+
+	tmpl, err := loadTemplate(req.VaultPath, firstNonEmpty(req.Template, cfg.Template))
+	if err != nil {
+		return nil, err
+	}
+	previous, err := readManifest(output)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := s.render(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	written := make([]string, 0, len(files)+len(s.assets))
+	for _, rel := range sortedKeys(files) {
+		if err := writeOutputFile(output, rel, files[rel]); err != nil {
+			return nil, err
+		}
+		written = append(written, rel)
+	}
+	for _, asset := range sortedKeys(s.assets) {
+		content, err := os.ReadFile(filepath.Join(req.VaultPath, filepath.FromSlash(asset)))
+		if err != nil {
+			return nil, newError(CodeFileReadError, fmt.Sprintf("failed to read asset %s", asset), "", err)
+		}
+		if err := writeOutputFile(output, asset, content); err != nil {
+			return nil, err
+		}
+		written = append(written, asset)
+		result.Assets = append(result.Assets, asset)
+	}
+
+	result.Removed = removeStale(output, previous, written)
+	data, _ := json.MarshalIndent(manifest{Files: written}, "", "  ")
+	if err := writeOutputFile(output, manifestName, append(data, '\n')); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// fileObject is a file-level object from the index.
+type fileObject struct {
+	ID     string
+	Type   string
+	File   string
+	Fields map[string]interface{}
+}
+
+func loadFileObjects(db *index.Database) ([]fileObject, error) {
+	rows, err := db.DB().Query(`SELECT id, type, file_path, fields FROM objects WHERE id NOT LIKE '%#%' ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects []fileObject
+	for rows.Next() {
+		var obj fileObject
+		var fields string
+		if err := rows.Scan(&obj.ID, &obj.Type, &obj.File, &fields); err != nil {
+			return nil, err
+		}
+		_ = json.Unmarshal([]byte(fields), &obj.Fields)
+		objects = append(objects, obj)
+	}
+	return objects, rows.Err()
+}
+
+func selectObjects(rt *readsvc.Runtime, objects []fileObject, selection, queryString string) ([]fileObject, error) {
+	if selection == SelectAll {
+		return objects, nil
+	}
+
+	keep := make(map[string]bool)
+	if selection == SelectQuery {
+		result, err := readsvc.ExecuteQuery(rt, readsvc.ExecuteQueryRequest{QueryString: queryString})
+		if err != nil {
+			return nil, newError(CodeQueryInvalid, fmt.Sprintf("publish query failed: %v", err), "Check the query with 'rvn query'", err)
+		}
+		for _, obj := range result.Objects {
+			keep[obj.FilePath] = true
+		}
+		for _, trait := range result.Traits {
+			keep[trait.FilePath] = true
+		}
+		for _, section := range result.Sections {
+			keep[section.FilePath] = true
+		}
+	}
+
+	var selected []fileObject
+	for _, obj := range objects {
+		if selection == SelectQuery && keep[obj.File] {
+			selected = append(selected, obj)
+		}
+		if selection == SelectFrontmatter {
+			if flag, ok := obj.Fields[PublishField].(bool); ok && flag {
+				selected = append(selected, obj)
+			}
+		}
+	}
+	return selected, nil
+}
+
+func readManifest(output string) (*manifest, error) {
+	entries, err := os.ReadDir(output)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &manifest{}, nil
+		}
+		return nil, newError(CodeFileReadError, fmt.Sprintf("failed to read %s", output), "", err)
+	}
+	data, err := os.ReadFile(filepath.Join(output, manifestName))
+	if err != nil {
+		if os.IsNotExist(err) && len(entries) > 0 {
+			return nil, newError(CodeInvalidInput, fmt.Sprintf("%s is not empty and was not created by rvn publish", output), "Choose an empty or new output directory", nil)
+		}
+		if os.IsNotExist(err) {
+			return &manifest{}, nil
+		}
+		return nil, newError(CodeFileReadError, fmt.Sprintf("failed to read %s", manifestName), "", err)
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, newError(CodeFileReadError, fmt.Sprintf("%s in %s is corrupt", manifestName, output), "Delete the output directory and publish again", err)
+	}
+	return &m, nil
+}
+
+// removeStale deletes files the previous publish wrote that this one did not.
+func removeStale(output string, previous *manifest, written []string) int {
+	current := make(map[string]bool, len(written))
+	for _, rel := range written {
+		current[rel] = true
+	}
+	removed := 0
+	for _, rel := range previous.Files {
+		clean := path.Clean(rel)
+		if current[clean] || strings.HasPrefix(clean, "../") || path.IsAbs(clean) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(output, filepath.FromSlash(clean))); err != nil {
+			continue
+		}
+		removed++
+		// Drop directories the removal left empty.
+		for dir := path.Dir(clean); dir != "."; dir = path.Dir(dir) {
+			if os.Remove(filepath.Join(output, filepath.FromSlash(dir))) != nil {
+				break
+			}
+		}
+	}
+	return removed
+}
+
+func writeOutputFile(output, rel string, content []byte) error {
+	target := filepath.Join(output, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return newError(CodeFileWriteErr, fmt.Sprintf("failed to create %s", filepath.Dir(target)), "", err)
+	}
+	if err := atomicfile.WriteFile(target, content, 0o644); err != nil {
+		return newError(CodeFileWriteErr, fmt.Sprintf("failed to write %s", target), "", err)
+	}
+	return nil
+}
+
+func siteTitle(configured, vaultPath string) string {
+	if title := strings.TrimSpace(configured); title != "" {
+		return title
+	}
+	if abs, err := filepath.Abs(vaultPath); err == nil {
+		return filepath.Base(abs)
+	}
+	return "Raven"
+}
+
+// contains reports whether child is inside parent.
+func contains(parent, child string) bool {
+	rel, err := filepath.Rel(parent, child)
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
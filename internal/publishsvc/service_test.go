@@ -0,0 +1,150 @@
+package publishsvc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func buildPublishVault(t *testing.T) *testutil.TestVault {
+	t.Helper()
+	return testutil.NewTestVault(t).
+		WithSchema(testutil.PersonProjectSchema()).
+		WithRavenYAML("directories:\n  template: templates/\n").
+		WithFile("projects/raven.md", `---
+type: project
+title: Raven
+owner: people/freya
+publish: true
+---
+# Raven
+
+Led by [[people/freya]], see [[notes/private]].
+
+`+"`[[notes/private]]`"+`
+`).
+		WithFile("people/freya.md", `---
+type: person
+name: Freya
+publish: true
+---
+# Freya
+
+Works on [[projects/raven|the Raven project]].
+`).
+		WithFile("notes/private.md", "# Private\n\nLinks to [[projects/raven]].\n").
+		Build()
+}
+
+func readOutput(t *testing.T, output, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(output, filepath.FromSlash(rel)))
+	if err != nil {
+		t.Fatalf("read %s: %v", rel, err)
+	}
+	return string(data)
+}
+
+func TestPublishRendersFrontmatterSelectedPages(t *testing.T) {
+	v := buildPublishVault(t)
+	output := filepath.Join(t.TempDir(), "site")
+
+	result, err := Publish(PublishRequest{VaultPath: v.Path, Output: output})
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if result.Selection != SelectFrontmatter || len(result.Pages) != 2 {
+		t.Fatalf("selection = %s, pages = %+v; want 2 frontmatter pages", result.Selection, result.Pages)
+	}
+	if _, err := os.Stat(filepath.Join(output, "notes", "private.html")); !os.IsNotExist(err) {
+		t.Fatalf("unpublished page was written (err=%v)", err)
+	}
+
+	raven := readOutput(t, output, "projects/raven.html")
+	for _, want := range []string{
+		`<a href="../people/freya.html">people/freya</a>`,
+		"see notes/private.",
+		"<code>[[notes/private]]</code>",
+	} {
+		if !strings.Contains(raven, want) {
+			t.Errorf("projects/raven.html missing %q:\n%s", want, raven)
+		}
+	}
+	if strings.Contains(raven, "private.html") {
+		t.Errorf("projects/raven.html links to an unpublished page:\n%s", raven)
+	}
+	if strings.Contains(raven, "<th>publish</th>") {
+		t.Errorf("publish flag should not appear in the metadata table:\n%s", raven)
+	}
+
+	freya := readOutput(t, output, "people/freya.html")
+	if !strings.Contains(freya, `<a href="../projects/raven.html">the Raven project</a>`) {
+		t.Errorf("people/freya.html missing aliased link:\n%s", freya)
+	}
+	if !strings.Contains(freya, "Linked from") || strings.Contains(freya, "notes/private") {
+		t.Errorf("people/freya.html backlinks should list only published pages:\n%s", freya)
+	}
+	if index := readOutput(t, output, "index.html"); !strings.Contains(index, "projects/raven.html") {
+		t.Errorf("index.html missing page link:\n%s", index)
+	}
+}
+
+func TestPublishRemovesStalePagesOnRepublish(t *testing.T) {
+	v := buildPublishVault(t)
+	output := filepath.Join(t.TempDir(), "site")
+
+	if _, err := Publish(PublishRequest{VaultPath: v.Path, Output: output, All: true}); err != nil {
+		t.Fatalf("first Publish: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(output, "notes", "private.html")); err != nil {
+		t.Fatalf("expected notes/private.html after --all: %v", err)
+	}
+
+	result, err := Publish(PublishRequest{VaultPath: v.Path, Output: output, Query: "type:person"})
+	if err != nil {
+		t.Fatalf("second Publish: %v", err)
+	}
+	if result.Selection != SelectQuery || len(result.Pages) != 1 || result.Pages[0].ID != "people/freya" {
+		t.Fatalf("pages = %+v, want only people/freya", result.Pages)
+	}
+	if result.Removed != 2 {
+		t.Errorf("removed = %d, want 2", result.Removed)
+	}
+	for _, dir := range []string{"notes", "projects"} {
+		if _, err := os.Stat(filepath.Join(output, dir)); !os.IsNotExist(err) {
+			t.Errorf("expected empty directory %s to be removed (err=%v)", dir, err)
+		}
+	}
+	if manifest := readOutput(t, output, manifestName); !strings.Contains(manifest, "people/freya.html") || strings.Contains(manifest, "projects/raven.html") {
+		t.Errorf("manifest not updated:\n%s", manifest)
+	}
+}
+
+func TestPublishRefusesUnsafeOutput(t *testing.T) {
+	v := buildPublishVault(t)
+
+	foreign := t.TempDir()
+	if err := os.WriteFile(filepath.Join(foreign, "keep.txt"), []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Publish(PublishRequest{VaultPath: v.Path, Output: foreign})
+	if svcErr, ok := AsError(err); !ok || svcErr.Code != CodeInvalidInput {
+		t.Fatalf("expected INVALID_INPUT for a foreign directory, got %v", err)
+	}
+
+	_, err = Publish(PublishRequest{VaultPath: v.Path, Output: "."})
+	if svcErr, ok := AsError(err); !ok || svcErr.Code != CodeInvalidInput {
+		t.Fatalf("expected INVALID_INPUT for the vault itself, got %v", err)
+	}
+
+	result, err := Publish(PublishRequest{VaultPath: v.Path, Output: "site", DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(result.Pages) != 2 || v.FileExists("site") {
+		t.Fatalf("dry run should select pages without writing: pages=%d", len(result.Pages))
+	}
+}
@@ -1,6 +1,8 @@
 // Package query implements the Raven query language parser and executor.
 package query
 
+import "time"
+
 // QueryType represents the parsed query root.
 type QueryType int
 
@@ -11,13 +13,40 @@ const (
 	QueryTypeSection
 )
 
+// AnyType is the TypeName of a type:* query, which matches objects of every type.
+const AnyType = "*"
+
 // Query represents a parsed query.
 type Query struct {
 	Type      QueryType
-	TypeName  string    // Type name or trait name; empty for asset queries
+	TypeName  string    // Type name or trait name; "*" or "a|b" for cross-type object queries; empty for asset queries
+	TypeNames []string  // Member types of a type union like type:(project|task); nil otherwise
 	Predicate Predicate // Filter to apply (may be nil)
 }
 
+// IsCrossType reports whether an object query spans more than one type
+// (type:* or a type union).
+func (q *Query) IsCrossType() bool {
+	return q != nil && q.Type == QueryTypeObject && (q.TypeName == AnyType || len(q.TypeNames) > 0)
+}
+
+// MatchesTypeName reports whether an object query selects objects of typeName,
+// either directly or as a member of a type union. type:* is not counted.
+func (q *Query) MatchesTypeName(typeName string) bool {
+	if q == nil || q.Type != QueryTypeObject {
+		return false
+	}
+	if len(q.TypeNames) > 0 {
+		for _, name := range q.TypeNames {
+			if name == typeName {
+				return true
+			}
+		}
+		return false
+	}
+	return q.TypeName == typeName
+}
+
 // Predicate represents a filter condition in a query.
 type Predicate interface {
 	predicateNode()
@@ -104,21 +133,37 @@ func (ContainsPredicate) predicateNode() {}
 
 // RefsPredicate filters type-query results by what they reference.
 // Syntax: refs([[target]]), refs(target), refs(type:<name> ...)
+// Transitive form: refs*([[target]]), refs*(type:<name> ..., depth<=3)
 type RefsPredicate struct {
 	basePredicate
-	Target   string // Specific target like "projects/website" (mutually exclusive with SubQuery)
-	SubQuery *Query // Subquery to match targets (mutually exclusive with Target)
+	Target     string // Specific target like "projects/website" (mutually exclusive with SubQuery)
+	SubQuery   *Query // Subquery to match targets (mutually exclusive with Target)
+	Transitive bool   // refs*(): match through chains of references
+	MaxDepth   int    // Hop limit for Transitive; 0 means unlimited
 }
 
 func (RefsPredicate) predicateNode() {}
 
 // ContentPredicate filters type-query results by full-text search on their content.
-// Syntax: content("search terms"), content("exact phrase")
+// Syntax: content("search terms"), content("exact phrase"), content(heading:"retro"),
+// content(field:"distributed")
 type ContentPredicate struct {
 	basePredicate
-	SearchTerm string // The search term or phrase
+	SearchTerm string       // The search term or phrase
+	Scope      ContentScope // Region to search; empty means the body
 }
 
+// ContentScope narrows content() to one indexed region of a note.
+type ContentScope string
+
+const (
+	ContentScopeBody    ContentScope = ""
+	ContentScopeTitle   ContentScope = "title"
+	ContentScopeHeading ContentScope = "heading"
+	ContentScopeCode    ContentScope = "code"
+	ContentScopeField   ContentScope = "field"
+)
+
 func (ContentPredicate) predicateNode() {}
 
 // ValuePredicate filters traits by value.
@@ -267,10 +312,75 @@ func (AtPredicate) predicateNode() {}
 
 // RefdPredicate filters objects/traits by what references them (inverse of refs()).
 // Syntax: refd(type:type ...), refd(trait:name ...), refd([[target]]), refd(target)
+// Transitive form: refd*([[source]]), refd*(type:<name> ..., depth<=3)
 type RefdPredicate struct {
 	basePredicate
-	Target   string // Specific source ID
-	SubQuery *Query // Query matching the sources that reference this
+	Target     string // Specific source ID
+	SubQuery   *Query // Query matching the sources that reference this
+	Transitive bool   // refd*(): match through chains of references
+	MaxDepth   int    // Hop limit for Transitive; 0 means unlimited
 }
 
 func (RefdPredicate) predicateNode() {}
+
+// TimestampKind selects which file timestamp a TimestampPredicate filters on.
+type TimestampKind string
+
+const (
+	TimestampModified TimestampKind = "modified" // file modification time
+	TimestampCreated  TimestampKind = "created"  // first git commit, else first indexed mtime
+)
+
+// TimeBound is one bound of a TimestampPredicate.
+type TimeBound struct {
+	Op     string        // "within", "before", or "after"
+	Value  string        // Raw value as written (e.g. "7d", "2026-01-01", "today")
+	Window time.Duration // Parsed duration for "within"
+}
+
+// TimestampPredicate filters results by the indexed timestamps of their file.
+// Syntax: modified(within:7d), created(before:2026-01-01), modified(after:yesterday, before:today)
+type TimestampPredicate struct {
+	basePredicate
+	Kind   TimestampKind
+	Bounds []TimeBound // All bounds must hold
+}
+
+func (TimestampPredicate) predicateNode() {}
+
+// ExpiredPredicate matches objects whose type review_after policy has elapsed.
+// Syntax: expired()
+type ExpiredPredicate struct {
+	basePredicate
+}
+
+func (ExpiredPredicate) predicateNode() {}
+
+// AttachmentPredicate matches objects and sections that link to vault assets.
+// Syntax: has_attachment(), attachment(type:pdf), attachment(type:image, min_size:5MB)
+type AttachmentPredicate struct {
+	basePredicate
+	AssetType string // Extension (pdf) or media family (image, audio, video, text); empty matches any
+	MinSize   int64  // Minimum size in bytes; 0 means no minimum
+	MaxSize   int64  // Maximum size in bytes; 0 means no maximum
+}
+
+func (AttachmentPredicate) predicateNode() {}
+
+// FocusPredicate matches results in the focus working set (rvn focus).
+// Traits and sections match when their file's object is focused.
+// Syntax: @focus
+type FocusPredicate struct {
+	basePredicate
+}
+
+func (FocusPredicate) predicateNode() {}
+
+// LifecyclePredicate matches objects whose lifecycle state is one of States.
+// Syntax: lifecycle(active) or lifecycle(draft, active)
+type LifecyclePredicate struct {
+	basePredicate
+	States []string
+}
+
+func (LifecyclePredicate) predicateNode() {}
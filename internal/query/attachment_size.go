@@ -0,0 +1,44 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// attachmentMediaFamilies are attachment(type:...) values that match a MIME
+// type prefix rather than a file extension.
+var attachmentMediaFamilies = map[string]bool{
+	"image": true,
+	"audio": true,
+	"video": true,
+	"text":  true,
+}
+
+// parseAttachmentSize parses sizes like 500KB, 5MB, 1GB, or a plain byte
+// count. Units are binary (1KB = 1024 bytes).
+func parseAttachmentSize(value string) (int64, error) {
+	invalid := fmt.Errorf("invalid size %q (use a count with B, KB, MB, or GB, e.g. 5MB)", value)
+	upper := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		factor int64
+	}{
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper = strings.TrimSuffix(upper, unit.suffix)
+			multiplier = unit.factor
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(upper, 64)
+	if err != nil || n < 0 {
+		return 0, invalid
+	}
+	return int64(n * float64(multiplier)), nil
+}
@@ -0,0 +1,157 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/index"
+)
+
+// Capabilities returns the optional SQLite features available to the
+// executor, probing the database on first use.
+func (e *Executor) Capabilities() index.Capabilities {
+	c := e.cache
+	if c == nil {
+		return index.DetectCapabilities(e.db)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.caps == nil {
+		caps := index.DetectCapabilities(e.db)
+		c.caps = &caps
+	}
+	return *c.caps
+}
+
+// SetCapabilities overrides detected capabilities. Callers that already know
+// the database's capabilities (or tests exercising fallbacks) use it to skip
+// probing.
+func (e *Executor) SetCapabilities(caps index.Capabilities) {
+	if e.cache == nil {
+		e.cache = newExecutorCache()
+	}
+	e.cache.mu.Lock()
+	e.cache.caps = &caps
+	e.cache.mu.Unlock()
+}
+
+// DegradedFeatures lists the query features in q that run on a fallback
+// because caps lacks the SQLite capability they normally use: content()
+// without FTS5 and matches() without REGEXP.
+func DegradedFeatures(q *Query, caps index.Capabilities) []string {
+	var usesContent, usesMatches bool
+	walkQueryPredicates(q, func(p Predicate) {
+		switch pred := p.(type) {
+		case *ContentPredicate:
+			usesContent = true
+		case *StringFuncPredicate:
+			if pred.FuncType == StringFuncMatches {
+				usesMatches = true
+			}
+		}
+	})
+
+	var degraded []string
+	if usesContent && !caps.FTS5 {
+		degraded = append(degraded, "content")
+	}
+	if usesMatches && !caps.Regexp {
+		degraded = append(degraded, "matches")
+	}
+	return degraded
+}
+
+// walkQueryPredicates calls fn for every predicate in q, including predicates
+// nested in boolean groups and subqueries.
+func walkQueryPredicates(q *Query, fn func(Predicate)) {
+	if q == nil {
+		return
+	}
+	walkPredicate(q.Predicate, fn)
+}
+
+func walkPredicate(p Predicate, fn func(Predicate)) {
+	if p == nil {
+		return
+	}
+	fn(p)
+	switch pred := p.(type) {
+	case *OrPredicate:
+		for _, inner := range pred.Predicates {
+			walkPredicate(inner, fn)
+		}
+	case *GroupPredicate:
+		for _, inner := range pred.Predicates {
+			walkPredicate(inner, fn)
+		}
+	case *NotPredicate:
+		walkPredicate(pred.Inner, fn)
+	case *ArrayQuantifierPredicate:
+		walkPredicate(pred.ElementPred, fn)
+	case *HasPredicate:
+		walkQueryPredicates(pred.SubQuery, fn)
+	case *InPredicate:
+		walkQueryPredicates(pred.SubQuery, fn)
+	case *ContainsPredicate:
+		walkQueryPredicates(pred.SubQuery, fn)
+	case *RefsPredicate:
+		walkQueryPredicates(pred.SubQuery, fn)
+	case *WithinPredicate:
+		walkQueryPredicates(pred.SubQuery, fn)
+	case *AtPredicate:
+		walkQueryPredicates(pred.SubQuery, fn)
+	case *RefdPredicate:
+		walkQueryPredicates(pred.SubQuery, fn)
+	}
+}
+
+// stringFuncCondition builds SQL for a string function predicate, translating
+// matches() to LIKE when the database has no REGEXP function.
+func (e *Executor) stringFuncCondition(funcType StringFuncType, fieldExpr string, value string, caseSensitive bool) (string, []interface{}, error) {
+	if funcType == StringFuncMatches && !e.Capabilities().Regexp {
+		return buildMatchesLikeFallback(fieldExpr, value, caseSensitive)
+	}
+	return buildStringFuncCondition(funcType, fieldExpr, value, caseSensitive)
+}
+
+// buildMatchesLikeFallback translates a simple regular expression into LIKE.
+// Only literal text, escaped punctuation, `.*`, and the ^ and $ anchors are
+// supported; anything else is an error rather than a silently wrong match.
+func buildMatchesLikeFallback(fieldExpr string, pattern string, caseSensitive bool) (string, []interface{}, error) {
+	body := pattern
+	anchoredStart := strings.HasPrefix(body, "^")
+	body = strings.TrimPrefix(body, "^")
+	anchoredEnd := strings.HasSuffix(body, "$") && !strings.HasSuffix(body, `\$`)
+	if anchoredEnd {
+		body = strings.TrimSuffix(body, "$")
+	}
+
+	var like strings.Builder
+	if !anchoredStart {
+		like.WriteByte('%')
+	}
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\\' && i+1 < len(body) && isRegexpMeta(body[i+1]):
+			like.WriteString(escapeLikePattern(string(body[i+1])))
+			i++
+		case c == '.' && i+1 < len(body) && body[i+1] == '*':
+			like.WriteByte('%')
+			i++
+		case isRegexpMeta(c):
+			return "", nil, fmt.Errorf("matches(%q) needs regular expression support, which this SQLite build lacks; only literal text with ^, $, and .* is supported (or use includes(), startswith(), or endswith())", pattern)
+		default:
+			like.WriteString(escapeLikePattern(string(c)))
+		}
+	}
+	if !anchoredEnd {
+		like.WriteByte('%')
+	}
+
+	return likeCond(fieldExpr, !caseSensitive), []interface{}{like.String()}, nil
+}
+
+func isRegexpMeta(c byte) bool {
+	return strings.IndexByte(`\.+*?()|[]{}^$`, c) >= 0
+}
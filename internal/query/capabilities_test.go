@@ -0,0 +1,116 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/index"
+)
+
+func TestExecutorFallbacksWithoutCapabilities(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	executor := NewExecutor(db)
+	executor.SetCapabilities(index.Capabilities{})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: `type:project content("website")`, want: []string{"projects/website"}},
+		{query: `type:project content(code:"select")`, want: []string{"projects/website"}},
+		{query: `type:project !content(heading:"retro")`, want: []string{"projects/website"}},
+		{query: `type:project matches(.status, "^act")`, want: []string{"projects/website"}},
+		{query: `type:project matches(.status, "ive$")`, want: []string{"projects/website"}},
+		{query: `type:project matches(.status, "^a.*e$")`, want: []string{"projects/website"}},
+		{query: `type:project content(field:"paused")`, want: []string{"projects/mobile"}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		results, err := executor.ExecuteObjectQuery(q)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.query, err)
+		}
+		got := make([]string, 0, len(results))
+		for _, r := range results {
+			got = append(got, r.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	traitQuery, err := Parse(`trait:todo content("landing build")`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	traits, err := executor.ExecuteTraitQuery(traitQuery)
+	if err != nil {
+		t.Fatalf("trait content fallback: %v", err)
+	}
+	if len(traits) != 1 || traits[0].ID != "trait5" {
+		t.Errorf("trait content fallback = %v, want [trait5]", traits)
+	}
+
+	q, err := Parse(`type:project matches(.status, "act(ive|ual)")`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	_, err = executor.ExecuteObjectQuery(q)
+	if err == nil || !strings.Contains(err.Error(), "needs regular expression support") {
+		t.Fatalf("expected a clear error for unsupported pattern, got %v", err)
+	}
+}
+
+func TestBuildMatchesLikeFallback(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{pattern: "draft", want: "%draft%"},
+		{pattern: "^draft$", want: "draft"},
+		{pattern: `^v1\.2`, want: "v1.2%"},
+		{pattern: "100%", want: `%100\%%`},
+		{pattern: `cost\$`, want: "%cost$%"},
+	}
+	for _, tt := range tests {
+		_, args, err := buildMatchesLikeFallback("x", tt.pattern, true)
+		if err != nil {
+			t.Fatalf("buildMatchesLikeFallback(%q): %v", tt.pattern, err)
+		}
+		if args[0] != tt.want {
+			t.Errorf("buildMatchesLikeFallback(%q) pattern = %q, want %q", tt.pattern, args[0], tt.want)
+		}
+	}
+
+	for _, pattern := range []string{"a+", "[abc]", "a|b", `\d`} {
+		if _, _, err := buildMatchesLikeFallback("x", pattern, true); err == nil {
+			t.Errorf("buildMatchesLikeFallback(%q) succeeded, want error", pattern)
+		}
+	}
+}
+
+func TestDegradedFeatures(t *testing.T) {
+	t.Parallel()
+	q, err := Parse(`type:project has(trait:due matches(.value, "^2025")) | content("plan")`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if got := DegradedFeatures(q, index.FullCapabilities()); got != nil {
+		t.Errorf("DegradedFeatures(full) = %v, want none", got)
+	}
+	if got := DegradedFeatures(q, index.Capabilities{}); !reflect.DeepEqual(got, []string{"content", "matches"}) {
+		t.Errorf("DegradedFeatures(none) = %v, want [content matches]", got)
+	}
+	if got := DegradedFeatures(q, index.Capabilities{Regexp: true}); !reflect.DeepEqual(got, []string{"content"}) {
+		t.Errorf("DegradedFeatures(no fts5) = %v, want [content]", got)
+	}
+}
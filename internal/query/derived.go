@@ -0,0 +1,500 @@
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/dates"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// Aggregates available in a derived field expression.
+const (
+	DerivedCount = "count"
+	DerivedSum   = "sum"
+	DerivedMin   = "min"
+	DerivedMax   = "max"
+)
+
+// DerivedExpr is a parsed `derived` field expression: an aggregate over the
+// results of a query, such as count(trait:todo within(_) .value!=done).
+// A `_` directly inside a predicate's parentheses stands for the object the
+// value is computed for.
+type DerivedExpr struct {
+	Func  string
+	Query string
+	Field string // Field aggregated for object queries; trait queries use the value
+	Trait bool   // Query returns traits
+}
+
+// ParseDerivedExpr parses and checks a derived field expression.
+func ParseDerivedExpr(expr string) (*DerivedExpr, error) {
+	expr = strings.TrimSpace(expr)
+	open := strings.Index(expr, "(")
+	if open <= 0 || !strings.HasSuffix(expr, ")") {
+		return nil, fmt.Errorf("expected count(<query>), sum(<query>), min(<query>), or max(<query>)")
+	}
+	d := &DerivedExpr{Func: strings.ToLower(strings.TrimSpace(expr[:open]))}
+	switch d.Func {
+	case DerivedCount, DerivedSum, DerivedMin, DerivedMax:
+	default:
+		return nil, fmt.Errorf("unknown aggregate %s(); use count(), sum(), min(), or max()", d.Func)
+	}
+
+	inner := expr[open+1 : len(expr)-1]
+	d.Query = strings.TrimSpace(inner)
+	if comma, err := lastTopLevelComma(inner); err != nil {
+		return nil, err
+	} else if comma >= 0 {
+		field := strings.TrimSpace(inner[comma+1:])
+		if !strings.HasPrefix(field, ".") || len(field) == 1 || strings.ContainsAny(field, " ()") {
+			return nil, fmt.Errorf("expected a field like .points after the query in %s()", d.Func)
+		}
+		d.Query = strings.TrimSpace(inner[:comma])
+		d.Field = field[1:]
+	}
+	if d.Query == "" {
+		return nil, fmt.Errorf("%s() needs a query", d.Func)
+	}
+
+	q, err := Parse(d.QueryFor("_self"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid query in %s(): %w", d.Func, err)
+	}
+	switch q.Type {
+	case QueryTypeTrait:
+		d.Trait = true
+		if d.Field != "" {
+			return nil, fmt.Errorf("%s() over a trait query aggregates the trait values; drop .%s", d.Func, d.Field)
+		}
+	case QueryTypeObject:
+		if d.Func != DerivedCount && d.Field == "" {
+			return nil, fmt.Errorf("%s() over a type query needs a field, e.g. %s(%s, .points)", d.Func, d.Func, d.Query)
+		}
+	default:
+		return nil, fmt.Errorf("%s() needs a type or trait query", d.Func)
+	}
+	if d.Func == DerivedCount && d.Field != "" {
+		return nil, fmt.Errorf("count() takes only a query")
+	}
+	return d, nil
+}
+
+// QueryFor returns the query with each `_` argument replaced by a reference
+// to id.
+func (d *DerivedExpr) QueryFor(id string) string {
+	lexer := NewLexer(d.Query)
+	var b strings.Builder
+	last := 0
+	prev := TokenEOF
+	for {
+		tok := lexer.NextToken()
+		if tok.Type == TokenEOF || tok.Type == TokenError {
+			break
+		}
+		if tok.Type == TokenUnderscore && prev == TokenLParen {
+			b.WriteString(d.Query[last:tok.Pos])
+			b.WriteString("[[" + id + "]]")
+			last = tok.Pos + 1
+		}
+		prev = tok.Type
+	}
+	b.WriteString(d.Query[last:])
+	return b.String()
+}
+
+// lastTopLevelComma returns the offset of the last comma outside any
+// parentheses or brackets, or -1.
+func lastTopLevelComma(s string) (int, error) {
+	lexer := NewLexer(s)
+	depth, comma := 0, -1
+	for {
+		tok := lexer.NextToken()
+		switch tok.Type {
+		case TokenEOF:
+			return comma, nil
+		case TokenError:
+			return -1, fmt.Errorf("invalid query: %s", tok.Value)
+		case TokenLParen, TokenLBracket:
+			depth++
+		case TokenRParen, TokenRBracket:
+			depth--
+		case TokenComma:
+			if depth == 0 {
+				comma = tok.Pos
+			}
+		}
+	}
+}
+
+// ValidateDerivedFields returns an issue for each derived field whose
+// expression does not parse or whose type cannot hold its result.
+func ValidateDerivedFields(sch *schema.Schema) []string {
+	var issues []string
+	forEachDerivedField(sch, func(typeName, fieldName string, def *schema.FieldDefinition) {
+		expr, err := ParseDerivedExpr(def.Derived)
+		if err != nil {
+			issues = append(issues, fmt.Sprintf("Type '%s' field '%s' has an invalid derived expression: %v", typeName, fieldName, err))
+			return
+		}
+		if err := checkDerivedType(expr, def.Type); err != nil {
+			issues = append(issues, fmt.Sprintf("Type '%s' field '%s': %v", typeName, fieldName, err))
+		}
+	})
+	return issues
+}
+
+func checkDerivedType(expr *DerivedExpr, fieldType schema.FieldType) error {
+	switch {
+	case fieldType == schema.FieldTypeNumber:
+		return nil
+	case expr.Func == DerivedCount || expr.Func == DerivedSum:
+		return fmt.Errorf("%s() produces a number; set type: number", expr.Func)
+	case fieldType == schema.FieldTypeDate || fieldType == schema.FieldTypeDatetime:
+		return nil
+	}
+	return fmt.Errorf("derived fields must be number, date, or datetime, not %s", fieldType)
+}
+
+func forEachDerivedField(sch *schema.Schema, fn func(typeName, fieldName string, def *schema.FieldDefinition)) {
+	if sch == nil {
+		return
+	}
+	typeNames := make([]string, 0, len(sch.Types))
+	for name := range sch.Types {
+		typeNames = append(typeNames, name)
+	}
+	sort.Strings(typeNames)
+	for _, typeName := range typeNames {
+		typeDef := sch.Types[typeName]
+		if typeDef == nil {
+			continue
+		}
+		fieldNames := make([]string, 0, len(typeDef.Fields))
+		for name, def := range typeDef.Fields {
+			if def != nil && strings.TrimSpace(def.Derived) != "" {
+				fieldNames = append(fieldNames, name)
+			}
+		}
+		sort.Strings(fieldNames)
+		for _, fieldName := range fieldNames {
+			fn(typeName, fieldName, typeDef.Fields[fieldName])
+		}
+	}
+}
+
+type derivedUpdate struct {
+	id    string
+	path  string
+	value interface{} // nil leaves the field unset
+}
+
+// MaterializeDerivedFields computes every derived field in the schema for
+// each object of its type and stores the values in the objects' indexed
+// fields, so they query and sort like frontmatter fields. Each field is
+// computed with one query over all objects of its type, or one query per
+// object when its query uses a predicate that cannot be run that way. Stale
+// values are cleared first, so a derived query only sees frontmatter values.
+// Fields whose expression is invalid are skipped and reported in the error.
+func MaterializeDerivedFields(db *index.Database, sch *schema.Schema, dailyDir string) error {
+	type derivedField struct {
+		typeName string
+		name     string
+		def      *schema.FieldDefinition
+		expr     *DerivedExpr
+	}
+	var fields []derivedField
+	var problems []string
+	forEachDerivedField(sch, func(typeName, fieldName string, def *schema.FieldDefinition) {
+		expr, err := ParseDerivedExpr(def.Derived)
+		if err == nil {
+			err = checkDerivedType(expr, def.Type)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s.%s: %v", typeName, fieldName, err))
+			return
+		}
+		fields = append(fields, derivedField{typeName: typeName, name: fieldName, def: def, expr: expr})
+	})
+	if len(fields) == 0 && len(problems) == 0 {
+		return nil
+	}
+
+	tx, err := db.DB().Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, field := range fields {
+		if _, err := tx.Exec(`UPDATE objects SET fields = json_remove(fields, ?) WHERE type = ?`, derivedFieldPath(field.name), field.typeName); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	executor := NewExecutor(db.DB())
+	executor.SetSchema(sch)
+	executor.SetDailyDirectory(dailyDir)
+	var updates []derivedUpdate
+	for _, field := range fields {
+		ids, err := objectIDsOfType(db, field.typeName)
+		if err != nil {
+			return err
+		}
+		results, err := executor.derivedResults(field.expr, field.typeName, ids)
+		if errors.Is(err, errDerivedSelfUnsupported) {
+			results, err = executor.derivedResultsPerObject(field.expr, ids)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s.%s: %v", field.typeName, field.name, err))
+			continue
+		}
+		for _, id := range ids {
+			updates = append(updates, derivedUpdate{id: id, path: derivedFieldPath(field.name), value: field.expr.aggregate(results[id], field.def.Type)})
+		}
+	}
+
+	tx, err = db.DB().Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	for _, update := range updates {
+		if update.value == nil {
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE objects SET fields = json_set(fields, ?, ?) WHERE id = ?`, update.path, update.value, update.id); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// derivedResults runs expr once for all objects of typeName, with `_` bound
+// to each object in turn, and returns each object's matches ([]model.Object
+// or []model.Trait) keyed by ID. It returns errDerivedSelfUnsupported when
+// the query uses a predicate that cannot be bound this way.
+func (e *Executor) derivedResults(expr *DerivedExpr, typeName string, ids []string) (map[string]interface{}, error) {
+	q, err := Parse(expr.QueryFor(derivedSelfTarget))
+	if err != nil {
+		return nil, err
+	}
+	scoped := e.withExecutionNow()
+	scoped.derivedSelf = "derived_self.id"
+
+	var whereClause, columns, from string
+	var args []interface{}
+	if q.Type == QueryTypeTrait {
+		whereClause, args, err = scoped.buildTraitWhereClause(q)
+		columns = "t.id, t.trait_type, t.value, t.raw_value, t.content, t.file_path, t.line_number, t.parent_object_id"
+		from = "traits t"
+	} else {
+		whereClause, args, err = scoped.buildObjectWhereClause(q)
+		columns = "o.id, o.type, o.fields, o.file_path, o.line_start"
+		from = "objects o"
+	}
+	if err != nil {
+		return nil, err
+	}
+	sqlStr := fmt.Sprintf(`
+		SELECT derived_self.id, %s
+		FROM objects derived_self
+		JOIN %s ON %s
+		WHERE derived_self.type = ?
+	`, columns, from, whereClause)
+	rows, err := scoped.queryRows(sqlStr, append(args, typeName))
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w (SQL: %s)", err, sqlStr)
+	}
+	defer rows.Close()
+
+	objects := make(map[string][]model.Object, len(ids))
+	traits := make(map[string][]model.Trait, len(ids))
+	for rows.Next() {
+		var selfID string
+		if q.Type == QueryTypeTrait {
+			var t model.Trait
+			if err := rows.Scan(&selfID, &t.ID, &t.TraitType, &t.Value, &t.RawValue, &t.Content, &t.FilePath, &t.Line, &t.ParentObjectID); err != nil {
+				return nil, err
+			}
+			traits[selfID] = append(traits[selfID], t)
+			continue
+		}
+		var obj model.Object
+		var fieldsJSON string
+		if err := rows.Scan(&selfID, &obj.ID, &obj.Type, &fieldsJSON, &obj.FilePath, &obj.LineStart); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(fieldsJSON), &obj.Fields); err != nil {
+			obj.Fields = make(map[string]interface{})
+		}
+		objects[selfID] = append(objects[selfID], obj)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Objects without matches get an empty slice of the right type, so
+	// count() reports 0 for them.
+	results := make(map[string]interface{}, len(ids))
+	for _, id := range ids {
+		if q.Type == QueryTypeTrait {
+			results[id] = traits[id]
+		} else {
+			results[id] = objects[id]
+		}
+	}
+	return results, nil
+}
+
+// derivedResultsPerObject runs expr separately for each object, for queries
+// derivedResults cannot run in one pass.
+func (e *Executor) derivedResultsPerObject(expr *DerivedExpr, ids []string) (map[string]interface{}, error) {
+	results := make(map[string]interface{}, len(ids))
+	for _, id := range ids {
+		matches, err := e.Execute(expr.QueryFor(id))
+		if err != nil {
+			return nil, err
+		}
+		results[id] = matches
+	}
+	return results, nil
+}
+
+func derivedFieldPath(name string) string {
+	return `$."` + name + `"`
+}
+
+func objectIDsOfType(db *index.Database, typeName string) ([]string, error) {
+	rows, err := db.DB().Query(`SELECT id FROM objects WHERE type = ? ORDER BY id`, typeName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// aggregate reduces query results to the derived value. Values that do not
+// fit fieldType are ignored; min and max of no values is nil.
+func (d *DerivedExpr) aggregate(results interface{}, fieldType schema.FieldType) interface{} {
+	var values []interface{}
+	switch rows := results.(type) {
+	case []model.Trait:
+		if d.Func == DerivedCount {
+			return len(rows)
+		}
+		for _, trait := range rows {
+			if trait.Value != nil {
+				values = append(values, *trait.Value)
+			}
+		}
+	case []model.Object:
+		if d.Func == DerivedCount {
+			return len(rows)
+		}
+		for _, obj := range rows {
+			if value, ok := obj.Fields[d.Field]; ok && value != nil {
+				values = append(values, value)
+			}
+		}
+	default:
+		return nil
+	}
+
+	if fieldType == schema.FieldTypeNumber {
+		var numbers []float64
+		for _, value := range values {
+			if n, ok := derivedNumber(value); ok {
+				numbers = append(numbers, n)
+			}
+		}
+		if d.Func == DerivedSum {
+			total := 0.0
+			for _, n := range numbers {
+				total += n
+			}
+			return derivedNumberValue(total)
+		}
+		if len(numbers) == 0 {
+			return nil
+		}
+		best := numbers[0]
+		for _, n := range numbers[1:] {
+			if (d.Func == DerivedMin && n < best) || (d.Func == DerivedMax && n > best) {
+				best = n
+			}
+		}
+		return derivedNumberValue(best)
+	}
+
+	// Dates and datetimes in canonical form order as strings.
+	best := ""
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok || !validDerivedDate(s, fieldType) {
+			continue
+		}
+		if best == "" || (d.Func == DerivedMin && s < best) || (d.Func == DerivedMax && s > best) {
+			best = s
+		}
+	}
+	if best == "" {
+		return nil
+	}
+	return best
+}
+
+func derivedNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// derivedNumberValue stores whole numbers as integers, the way frontmatter
+// numbers are indexed.
+func derivedNumberValue(n float64) interface{} {
+	if n == math.Trunc(n) && math.Abs(n) < 1<<53 {
+		return int64(n)
+	}
+	return n
+}
+
+func validDerivedDate(s string, fieldType schema.FieldType) bool {
+	if fieldType == schema.FieldTypeDatetime {
+		return dates.IsValidDatetime(s)
+	}
+	return dates.IsValidDate(s)
+}
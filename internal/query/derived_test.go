@@ -0,0 +1,182 @@
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func TestParseDerivedExpr(t *testing.T) {
+	t.Parallel()
+
+	expr, err := ParseDerivedExpr("count(trait:todo within(_) .value!=done)")
+	if err != nil {
+		t.Fatalf("ParseDerivedExpr: %v", err)
+	}
+	if expr.Func != DerivedCount || !expr.Trait || expr.Field != "" {
+		t.Fatalf("expr = %+v", expr)
+	}
+	if got := expr.QueryFor("projects/alpha"); got != "trait:todo within([[projects/alpha]]) .value!=done" {
+		t.Fatalf("QueryFor = %q", got)
+	}
+
+	expr, err = ParseDerivedExpr("sum(type:task refs(_) any(.tags, _ == big), .estimate)")
+	if err != nil {
+		t.Fatalf("ParseDerivedExpr: %v", err)
+	}
+	if expr.Field != "estimate" || expr.Query != "type:task refs(_) any(.tags, _ == big)" {
+		t.Fatalf("expr = %+v", expr)
+	}
+	if got := expr.QueryFor("p"); got != "type:task refs([[p]]) any(.tags, _ == big)" {
+		t.Fatalf("QueryFor should leave quantifier _ alone, got %q", got)
+	}
+
+	invalid := map[string]string{
+		"type:task":                        "expected count",
+		"avg(type:task, .estimate)":        "unknown aggregate",
+		"sum(type:task)":                   "needs a field",
+		"max(trait:due, .value)":           "drop .value",
+		"count(type:task, .estimate)":      "only a query",
+		"count(type:task .estimate==)":     "invalid query",
+		"min(type:task refs(_), estimate)": "expected a field",
+	}
+	for input, want := range invalid {
+		if _, err := ParseDerivedExpr(input); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseDerivedExpr(%q) error = %v, want %q", input, err, want)
+		}
+	}
+}
+
+func newDerivedTestDB(t *testing.T) *index.Database {
+	t.Helper()
+
+	db, err := index.OpenInMemory()
+	if err != nil {
+		t.Fatalf("open in-memory db: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.DB().Exec(`
+		INSERT INTO objects (id, file_path, type, fields, line_start) VALUES
+			('projects/alpha', 'projects/alpha.md', 'project', '{"open":99}', 1),
+			('projects/beta', 'projects/beta.md', 'project', '{}', 1),
+			('tasks/one', 'tasks/one.md', 'task', '{"estimate":3}', 1),
+			('tasks/two', 'tasks/two.md', 'task', '{"estimate":1.5}', 1);
+		INSERT INTO traits (id, trait_type, value, content, file_path, line_number, parent_object_id) VALUES
+			('projects/alpha.md:trait:0', 'todo', 'todo', 'a', 'projects/alpha.md', 3, 'projects/alpha'),
+			('projects/alpha.md:trait:1', 'todo', 'done', 'b', 'projects/alpha.md', 4, 'projects/alpha'),
+			('projects/alpha.md:trait:2', 'due', '2026-11-02', 'a', 'projects/alpha.md', 3, 'projects/alpha'),
+			('projects/alpha.md:trait:3', 'due', '2026-10-20', 'b', 'projects/alpha.md', 4, 'projects/alpha');
+		INSERT INTO refs (source_id, target_id, target_raw, file_path, line_number) VALUES
+			('tasks/one', 'projects/alpha', 'projects/alpha', 'tasks/one.md', 5),
+			('tasks/two', 'projects/alpha', 'projects/alpha', 'tasks/two.md', 5);
+	`)
+	if err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	return db
+}
+
+func TestMaterializeDerivedFields(t *testing.T) {
+	t.Parallel()
+
+	db := newDerivedTestDB(t)
+	sch := &schema.Schema{
+		Types: map[string]*schema.TypeDefinition{
+			"project": {Fields: map[string]*schema.FieldDefinition{
+				"open":     {Type: schema.FieldTypeNumber, Derived: "count(trait:todo (in(_) | within(_)) .value!=done)"},
+				"next_due": {Type: schema.FieldTypeDate, Derived: "min(trait:due in(_))"},
+				"points":   {Type: schema.FieldTypeNumber, Derived: "sum(type:task refs(_), .estimate)"},
+			}},
+			"task": {Fields: map[string]*schema.FieldDefinition{
+				"estimate": {Type: schema.FieldTypeNumber},
+			}},
+		},
+		Traits: map[string]*schema.TraitDefinition{
+			"todo": {Type: schema.FieldTypeEnum, Values: []string{"todo", "done"}},
+			"due":  {Type: schema.FieldTypeDate},
+		},
+	}
+	if err := MaterializeDerivedFields(db, sch, ""); err != nil {
+		t.Fatalf("MaterializeDerivedFields: %v", err)
+	}
+
+	fields := func(id string) map[string]interface{} {
+		var raw string
+		if err := db.DB().QueryRow(`SELECT fields FROM objects WHERE id = ?`, id).Scan(&raw); err != nil {
+			t.Fatalf("read %s: %v", id, err)
+		}
+		var out map[string]interface{}
+		if err := json.Unmarshal([]byte(raw), &out); err != nil {
+			t.Fatalf("decode %s: %v", id, err)
+		}
+		return out
+	}
+	alpha := fields("projects/alpha")
+	if alpha["open"] != float64(1) || alpha["next_due"] != "2026-10-20" || alpha["points"] != 4.5 {
+		t.Fatalf("alpha fields = %v", alpha)
+	}
+	beta := fields("projects/beta")
+	if _, ok := beta["next_due"]; ok || beta["open"] != float64(0) || beta["points"] != float64(0) {
+		t.Fatalf("beta fields = %v, want zero counts and no next_due", beta)
+	}
+
+	e := NewExecutor(db.DB())
+	e.SetSchema(sch)
+	results, err := e.Execute("type:project .points>4")
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if objects := results.([]model.Object); len(objects) != 1 || objects[0].ID != "projects/alpha" {
+		t.Fatalf("derived field query = %+v", objects)
+	}
+}
+
+func TestDerivedResultsMatchPerObjectQueries(t *testing.T) {
+	t.Parallel()
+
+	db := newDerivedTestDB(t)
+	e := NewExecutor(db.DB())
+	ids := []string{"projects/alpha", "projects/beta"}
+	for _, exprText := range []string{
+		"count(trait:todo (in(_) | within(_)) .value!=done)",
+		"min(trait:due in(_))",
+		"sum(type:task refs(_), .estimate)",
+		"count(type:task refs*(_))",
+		"count(type:task !refs(_))",
+		"count(type:project refd(_))",
+	} {
+		expr, err := ParseDerivedExpr(exprText)
+		if err != nil {
+			t.Fatalf("parse %s: %v", exprText, err)
+		}
+		batched, err := e.derivedResults(expr, "project", ids)
+		if err != nil {
+			t.Fatalf("%s: derivedResults: %v", exprText, err)
+		}
+		perObject, err := e.derivedResultsPerObject(expr, ids)
+		if err != nil {
+			t.Fatalf("%s: derivedResultsPerObject: %v", exprText, err)
+		}
+		for _, id := range ids {
+			got := expr.aggregate(batched[id], schema.FieldTypeNumber)
+			want := expr.aggregate(perObject[id], schema.FieldTypeNumber)
+			if got != want {
+				t.Errorf("%s for %s: one query = %v, per object = %v", exprText, id, got, want)
+			}
+		}
+	}
+
+	expr, err := ParseDerivedExpr("count(type:task refd*(_))")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if _, err := e.derivedResults(expr, "project", ids); !errors.Is(err, errDerivedSelfUnsupported) {
+		t.Fatalf("refd*(_) err = %v, want errDerivedSelfUnsupported", err)
+	}
+}
@@ -0,0 +1,83 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// DisplayNameField is the object pseudo-field that resolves to an object's
+// display name: the value of its type's name_field, falling back to the last
+// segment of the object ID when the type has no name_field or the value is empty.
+// A real field named display_name on the type takes precedence.
+const DisplayNameField = "display_name"
+
+func isDisplayNameField(typeDef *schema.TypeDefinition, fieldName string) bool {
+	if fieldName != DisplayNameField {
+		return false
+	}
+	return typeDef == nil || typeDef.Fields[fieldName] == nil
+}
+
+func (e *Executor) typeDefinition(typeName string) *schema.TypeDefinition {
+	if e.schema == nil || typeName == "" {
+		return nil
+	}
+	return e.schema.Types[typeName]
+}
+
+// displayNameExpr returns a SQL expression for the display name of the object
+// row at alias. The name field path is inlined so the expression can be reused
+// by helpers that reference it more than once.
+func displayNameExpr(alias string, typeDef *schema.TypeDefinition) string {
+	// rtrim strips every non-slash character from the right, leaving the ID
+	// prefix up to the last '/', which replace() then removes.
+	baseName := fmt.Sprintf("replace(%[1]s.id, rtrim(%[1]s.id, replace(%[1]s.id, '/', '')), '')", alias)
+	if typeDef == nil || typeDef.NameField == "" {
+		return baseName
+	}
+	path := strings.ReplaceAll(jsonFieldPath(typeDef.NameField), "'", "''")
+	return fmt.Sprintf("COALESCE(NULLIF(CAST(json_extract(%s.fields, '%s') AS TEXT), ''), %s)", alias, path, baseName)
+}
+
+func (e *Executor) buildDisplayNameFieldPredicateSQL(p *FieldPredicate, alias string, typeDef *schema.TypeDefinition) (string, []interface{}, error) {
+	expr := displayNameExpr(alias, typeDef)
+
+	var cond string
+	var args []interface{}
+	switch {
+	case p.IsExists:
+		cond = expr + " IS NOT NULL"
+		if p.CompareOp == CompareNeq {
+			cond = expr + " IS NULL"
+		}
+	case p.IsRefValue:
+		return "", nil, fmt.Errorf(".%s does not support reference values; compare against the name text instead", DisplayNameField)
+	case p.CompareOp == CompareEq:
+		cond = fmt.Sprintf("LOWER(%s) = LOWER(?)", expr)
+		args = append(args, p.Value)
+	case p.CompareOp == CompareNeq:
+		cond = fmt.Sprintf("LOWER(%s) != LOWER(?)", expr)
+		args = append(args, p.Value)
+	default:
+		cond = fmt.Sprintf("%s %s ?", expr, compareOpToSQL(p.CompareOp))
+		args = append(args, p.Value)
+	}
+
+	if p.Negated() {
+		cond = "NOT (" + cond + ")"
+	}
+	return cond, args, nil
+}
+
+func (e *Executor) buildDisplayNameStringFuncPredicateSQL(p *StringFuncPredicate, alias string, typeDef *schema.TypeDefinition) (string, []interface{}, error) {
+	cond, args, err := e.stringFuncCondition(p.FuncType, displayNameExpr(alias, typeDef), p.Value, p.CaseSensitive)
+	if err != nil {
+		return "", nil, err
+	}
+	if p.Negated() {
+		cond = "NOT (" + cond + ")"
+	}
+	return cond, args, nil
+}
@@ -0,0 +1,77 @@
+package query
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func TestDisplayNamePseudoField(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO objects (id, file_path, type, fields, line_start) VALUES
+			('crew/freya', 'crew/freya.md', 'crewmate', '{"name": "Freya Ödegaard"}', 1),
+			('crew/thor', 'crew/thor.md', 'crewmate', '{"name": "Thor"}', 1),
+			('crew/loki', 'crew/loki.md', 'crewmate', '{}', 1),
+			('memo/inbox', 'memo/inbox.md', 'memo', '{}', 1),
+			('memo/daily/log', 'memo/daily/log.md', 'memo', '{}', 1);
+	`)
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	e := NewExecutor(db)
+	sch := &schema.Schema{
+		Types: map[string]*schema.TypeDefinition{
+			"crewmate": {
+				NameField: "name",
+				Fields: map[string]*schema.FieldDefinition{
+					"name": {Type: schema.FieldTypeString},
+				},
+			},
+			"memo": {},
+		},
+	}
+	e.SetSchema(sch)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: `type:crewmate .display_name=="thor"`, want: []string{"crew/thor"}},
+		{query: `type:crewmate .display_name=="loki"`, want: []string{"crew/loki"}},
+		{query: `type:crewmate .display_name!="thor"`, want: []string{"crew/freya", "crew/loki"}},
+		{query: `type:crewmate startswith(.display_name, "freya")`, want: []string{"crew/freya"}},
+		{query: `type:crewmate .display_name<"G"`, want: []string{"crew/freya"}},
+		{query: `type:memo .display_name=="log"`, want: []string{"memo/daily/log"}},
+		{query: `type:memo includes(.display_name, "box")`, want: []string{"memo/inbox"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if err := NewValidator(sch).Validate(q); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+			results, err := e.ExecuteObjectQuery(q)
+			if err != nil {
+				t.Fatalf("exec: %v", err)
+			}
+			ids := make([]string, 0, len(results))
+			for _, r := range results {
+				ids = append(ids, r.ID)
+			}
+			slices.Sort(ids)
+			if !slices.Equal(ids, tt.want) {
+				t.Fatalf("ids = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
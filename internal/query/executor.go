@@ -4,29 +4,32 @@ import (
 	"database/sql"
 	"time"
 
-	"github.com/aidanlsb/raven/internal/resolver"
 	"github.com/aidanlsb/raven/internal/schema"
 )
 
 // Executor executes queries against the database.
 type Executor struct {
 	db                         *sql.DB
-	resolver                   *resolver.Resolver // Cached resolver for target resolution
-	dailyDirectory             string             // Used for date shorthand refs (e.g. [[2026-01-01]])
+	cache                      *executorCache // Prepared statements and resolver, shared across executions
+	dailyDirectory             string         // Used for date shorthand refs (e.g. [[2026-01-01]])
 	schema                     *schema.Schema
 	now                        time.Time
 	nowFn                      func() time.Time
 	fieldRefAmbiguityCache     map[fieldRefAmbiguityKey]fieldRefAmbiguityResult
 	ambiguousFieldRefQueryHook func()
+	derivedSelf                string // SQL for the object `_` stands for in a derived field query
 }
 
 // NewExecutor creates a new query executor.
 func NewExecutor(db *sql.DB) *Executor {
-	return &Executor{db: db, dailyDirectory: "daily", nowFn: time.Now}
+	return &Executor{db: db, cache: newExecutorCache(), dailyDirectory: "daily", nowFn: time.Now}
 }
 
 // SetSchema injects a schema for type-aware query semantics.
 func (e *Executor) SetSchema(sch *schema.Schema) {
+	if sch != e.schema {
+		e.ResetCache()
+	}
 	e.schema = sch
 }
 
@@ -0,0 +1,85 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttachmentPredicates(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	executor := NewExecutor(db)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "type:project has_attachment()", want: []string{"projects/website"}},
+		{query: "type:project !has_attachment()", want: []string{"projects/mobile"}},
+		{query: "type:project attachment(type:pdf)", want: []string{"projects/website"}},
+		{query: "type:project attachment(type:image)", want: []string{"projects/website"}},
+		{query: "type:project attachment(type:mp4)", want: []string{}},
+		{query: "type:project attachment(min_size:10KB)", want: []string{"projects/website"}},
+		{query: "type:project attachment(type:png, min_size:10KB)", want: []string{}},
+		{query: "type:project attachment(max_size:1KB)", want: []string{}},
+		{query: "type:person has_attachment()", want: []string{}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		results, err := executor.ExecuteObjectQuery(q)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.query, err)
+		}
+		got := make([]string, 0, len(results))
+		for _, r := range results {
+			got = append(got, r.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	q, err := Parse("section attachment(type:image)")
+	if err != nil {
+		t.Fatalf("Parse section query: %v", err)
+	}
+	sections, err := executor.ExecuteSectionQuery(q)
+	if err != nil {
+		t.Fatalf("section attachment query: %v", err)
+	}
+	if len(sections) != 1 || sections[0].ID != "projects/website#tasks" {
+		t.Fatalf("section attachment(type:image) = %#v, want projects/website#tasks", sections)
+	}
+}
+
+func TestParseAttachmentPredicate(t *testing.T) {
+	t.Parallel()
+
+	q, err := Parse(`type:note attachment(type:PDF, min_size:"1.5MB", max_size:2048)`)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	pred, ok := q.Predicate.(*AttachmentPredicate)
+	if !ok {
+		t.Fatalf("predicate = %T, want *AttachmentPredicate", q.Predicate)
+	}
+	if pred.AssetType != "pdf" || pred.MinSize != 1572864 || pred.MaxSize != 2048 {
+		t.Fatalf("AttachmentPredicate = %+v", pred)
+	}
+
+	for _, input := range []string{
+		"type:note has_attachment(type:pdf)",
+		"type:note attachment()",
+		"type:note attachment(kind:pdf)",
+		"type:note attachment(min_size:lots)",
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", input)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func TestParseCrossTypeQueries(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input     string
+		wantName  string
+		wantNames []string
+	}{
+		{input: "type:*", wantName: AnyType},
+		{input: "type:* .status==active", wantName: AnyType},
+		{input: "type:(project|person)", wantName: "project|person", wantNames: []string{"project", "person"}},
+		{input: "type:(project OR person | project) has(trait:due)", wantName: "project|person", wantNames: []string{"project", "person"}},
+		{input: "type:(project)", wantName: "project"},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		if q.TypeName != tt.wantName || !reflect.DeepEqual(q.TypeNames, tt.wantNames) {
+			t.Errorf("Parse(%q) = %q %v, want %q %v", tt.input, q.TypeName, q.TypeNames, tt.wantName, tt.wantNames)
+		}
+	}
+
+	for _, input := range []string{"type:()", "type:(project|)", "type:(project person)", "trait:*", "type:(project"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestExecuteCrossTypeObjectQuery(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	executor := NewExecutor(db)
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "type:*", want: []string{"daily/2025-02-01", "people/freya", "people/loki", "projects/mobile", "projects/website"}},
+		{query: "type:(project|person)", want: []string{"people/freya", "people/loki", "projects/mobile", "projects/website"}},
+		{query: "type:(project|person) .status==active | .name==Loki", want: []string{"people/loki", "projects/website"}},
+		{query: "type:* refd(type:(project|date))", want: []string{"people/freya"}},
+		{query: "type:person refd(type:* .status==active)", want: []string{"people/freya"}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		results, err := executor.ExecuteObjectQuery(q)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.query, err)
+		}
+		got := make([]string, 0, len(results))
+		for _, r := range results {
+			got = append(got, r.ID)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+
+		count, err := executor.ExecuteObjectCountQuery(q)
+		if err != nil {
+			t.Fatalf("%s: count error: %v", tt.query, err)
+		}
+		if count != len(tt.want) {
+			t.Errorf("%s: count = %d, want %d", tt.query, count, len(tt.want))
+		}
+	}
+}
+
+func TestValidator_CrossTypeObjectQuery(t *testing.T) {
+	t.Parallel()
+	sch := &schema.Schema{
+		Types: map[string]*schema.TypeDefinition{
+			"project": {Fields: map[string]*schema.FieldDefinition{"status": {Type: schema.FieldTypeString}}},
+			"task":    {Fields: map[string]*schema.FieldDefinition{"owner": {Type: schema.FieldTypeString}}},
+		},
+		Traits: map[string]*schema.TraitDefinition{},
+	}
+	v := NewValidator(sch)
+
+	for _, input := range []string{
+		"type:*",
+		"type:* .status==active",
+		"type:(project|task) .owner==freya",
+		"type:(project|task) .status==active | .owner==freya",
+	} {
+		q, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		if err := v.Validate(q); err != nil {
+			t.Errorf("Validate(%q) = %v, want nil", input, err)
+		}
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "type:(project|nonexistent)", want: "unknown type 'nonexistent' in type union"},
+		{input: "type:(project|task) .missing==x", want: "no type in 'project|task' matches the predicate"},
+		{input: "type:* .missing==x", want: "no type in '*' matches the predicate"},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.input, err)
+		}
+		err = v.Validate(q)
+		var ve *ValidationError
+		if !errors.As(err, &ve) || !strings.Contains(ve.Message, tt.want) {
+			t.Errorf("Validate(%q) = %v, want message containing %q", tt.input, err, tt.want)
+		}
+	}
+}
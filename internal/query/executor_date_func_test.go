@@ -0,0 +1,138 @@
+package query
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDateFunctionValues(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	executor := NewExecutor(db)
+	executor.nowFn = func() time.Time { return time.Date(2025, time.February, 1, 9, 0, 0, 0, time.UTC) }
+
+	traitTests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "trait:due .value==within(3d)", want: []string{"trait2", "trait4"}},
+		{query: "trait:due .value!=within(3d)", want: []string{"trait1"}},
+		{query: "trait:due .value<date(+7d)", want: []string{"trait2", "trait4"}},
+		{query: "trait:due .value>date(+1d)", want: []string{"trait1", "trait2"}},
+		{query: "trait:due .value>endOfMonth()", want: []string{"trait1"}},
+		{query: "trait:due .value>=startOfMonth(+4m)", want: []string{"trait1"}},
+		{query: "trait:due .value<=endOfWeek()", want: []string{"trait4"}},
+	}
+	for _, tt := range traitTests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		results, err := executor.ExecuteTraitQuery(q)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.query, err)
+		}
+		got := make([]string, 0, len(results))
+		for _, r := range results {
+			got = append(got, r.ID)
+		}
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	q, err := Parse("type:date .date>=startOfYear() .date<=date(+1w)")
+	if err != nil {
+		t.Fatalf("Parse object query: %v", err)
+	}
+	objects, err := executor.ExecuteObjectQuery(q)
+	if err != nil {
+		t.Fatalf("object query: %v", err)
+	}
+	if len(objects) != 1 || objects[0].ID != "daily/2025-02-01" {
+		t.Fatalf("type:date with date functions = %#v, want daily/2025-02-01", objects)
+	}
+}
+
+func TestParseDateFunctionValues(t *testing.T) {
+	t.Parallel()
+
+	q, err := Parse("trait:due .value<Date(+7D)")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if fp, ok := q.Predicate.(*FieldPredicate); !ok || fp.Value != "date(+7d)" {
+		t.Fatalf("predicate = %#v, want canonical date(+7d) value", q.Predicate)
+	}
+
+	// With whitespace, a parenthesis after a value still opens a group.
+	if _, err := Parse("type:project .status==active (.priority==high | .priority==low)"); err != nil {
+		t.Fatalf("grouped predicate after value: %v", err)
+	}
+
+	for _, input := range []string{
+		"trait:due .value<date(+7x)",
+		"trait:due .value==within()",
+		"trait:due .value<nextTuesday()",
+		"trait:due .value<date(+7d",
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestRecurringTraitOccurrences(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO traits (id, file_path, parent_object_id, trait_type, value, content, line_number) VALUES
+			('rent', 'people/freya.md', 'people/freya', 'due', '2025-01-15, repeat:monthly', 'Pay rent', 20);
+		INSERT INTO trait_occurrences (trait_id, date, file_path) VALUES
+			('rent', '2025-01-15', 'people/freya.md'),
+			('rent', '2025-02-15', 'people/freya.md'),
+			('rent', '2025-03-15', 'people/freya.md');
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert recurring trait: %v", err)
+	}
+
+	executor := NewExecutor(db)
+	executor.nowFn = func() time.Time { return time.Date(2025, time.February, 1, 9, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "trait:due .value==2025-03-15", want: []string{"rent"}},
+		{query: "trait:due .value==within(3d)", want: []string{"trait2", "trait4"}},
+		{query: "trait:due .value!=2025-02-15", want: []string{"trait1", "trait2", "trait4"}},
+		{query: "trait:due .value<date(+20d)", want: []string{"rent", "trait2", "trait4"}},
+		{query: "trait:due .value>endOfMonth()", want: []string{"trait1"}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		results, err := executor.ExecuteTraitQuery(q)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.query, err)
+		}
+		got := make([]string, 0, len(results))
+		for _, r := range results {
+			got = append(got, r.ID)
+		}
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package query
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func TestObjectFieldPredicates_NestedPaths(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO objects (id, file_path, type, fields, line_start) VALUES
+			('members/freya', 'members/freya.md', 'member', '{"address":{"city":"Oslo","zip":150},"authors":[{"name":"Snorri"}]}', 1),
+			('members/thor', 'members/thor.md', 'member', '{"address":{"city":"Bergen","zip":5003},"authors":[{"name":"Anon"},{"name":"Snorri"}]}', 1),
+			('members/loki', 'members/loki.md', 'member', '{"name":"Loki"}', 1);
+	`)
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	sch := &schema.Schema{
+		Types: map[string]*schema.TypeDefinition{
+			"member": {Fields: map[string]*schema.FieldDefinition{
+				"name": {Type: schema.FieldTypeString},
+				"address": {Type: schema.FieldTypeObject, Fields: map[string]*schema.FieldDefinition{
+					"city": {Type: schema.FieldTypeString},
+					"zip":  {Type: schema.FieldTypeNumber},
+				}},
+				"authors": {Type: schema.FieldTypeObjectArray, Fields: map[string]*schema.FieldDefinition{
+					"name": {Type: schema.FieldTypeString},
+				}},
+			}},
+		},
+		Traits: map[string]*schema.TraitDefinition{},
+	}
+	e := NewExecutor(db)
+	e.SetSchema(sch)
+	v := NewValidator(sch)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "type:member .address.city==oslo", want: []string{"members/freya"}},
+		{query: "type:member .address.zip>1000", want: []string{"members/thor"}},
+		{query: "type:member .authors[0].name==Snorri", want: []string{"members/freya"}},
+		{query: "type:member .authors[1].name==Snorri", want: []string{"members/thor"}},
+		{query: `type:member startswith(.address.city, "Ber")`, want: []string{"members/thor"}},
+		{query: "type:member !exists(.address.city)", want: []string{"members/loki"}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		if err := v.Validate(q); err != nil {
+			t.Fatalf("Validate(%q): %v", tt.query, err)
+		}
+		results, err := e.ExecuteObjectQuery(q)
+		if err != nil {
+			t.Fatalf("Execute(%q): %v", tt.query, err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.ID)
+		}
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"type:member .address.country==x", "type:member .authors.name==x", "type:member .name[0]==x"} {
+		q, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		var ve *ValidationError
+		if err := v.Validate(q); !errors.As(err, &ve) {
+			t.Errorf("Validate(%q) = %v, want a validation error", input, err)
+		}
+	}
+}
+
+func TestParseFieldPaths(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]string{
+		"type:member .address.city==Oslo":          "address.city",
+		"type:book .authors[0].name==Snorri":       "authors[0].name",
+		"type:book .tags[12]==x":                   "tags[12]",
+		"type:member exists(.address.zip)":         "address.zip",
+		`type:member includes(.address.city, "O")`: "address.city",
+	} {
+		q, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		var field string
+		switch p := q.Predicate.(type) {
+		case *FieldPredicate:
+			field = p.Field
+		case *StringFuncPredicate:
+			field = p.Field
+		}
+		if field != want {
+			t.Errorf("Parse(%q) field = %q, want %q", input, field, want)
+		}
+	}
+
+	for _, input := range []string{"type:book .authors[x]==a", "type:book .authors[0==a", "type:book .authors[]==a"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", input)
+		}
+	}
+
+	// Whitespace separates predicates rather than continuing the path.
+	q, err := Parse("type:member .name==a .address.city==b")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	group, ok := q.Predicate.(*GroupPredicate)
+	if !ok || len(group.Predicates) != 2 {
+		t.Fatalf("expected two predicates, got %#v", q.Predicate)
+	}
+}
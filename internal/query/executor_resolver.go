@@ -1,6 +1,7 @@
 package query
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -16,13 +17,21 @@ func (e *Executor) SetDailyDirectory(dir string) {
 	if dir == "" {
 		dir = "daily"
 	}
+	if dir != e.dailyDirectory {
+		e.ResetCache()
+	}
 	e.dailyDirectory = dir
 }
 
 // getResolver returns a resolver for target resolution, creating it if needed.
+// The resolver is cached until ResetCache.
 func (e *Executor) getResolver() (*resolver.Resolver, error) {
-	if e.resolver != nil {
-		return e.resolver, nil
+	if e.cache != nil {
+		e.cache.mu.Lock()
+		defer e.cache.mu.Unlock()
+		if e.cache.resolver != nil {
+			return e.cache.resolver, nil
+		}
 	}
 
 	res, err := index.BuildResolver(e.db, index.ResolverOptions{
@@ -32,13 +41,27 @@ func (e *Executor) getResolver() (*resolver.Resolver, error) {
 	if err != nil {
 		return nil, fmt.Errorf("build resolver: %w", err)
 	}
-	e.resolver = res
-	return e.resolver, nil
+	if e.cache != nil {
+		e.cache.resolver = res
+	}
+	return res, nil
 }
 
+// derivedSelfTarget stands for `_` when a derived field is computed for every
+// object of its type in one query. Predicates that support it compare against
+// the outer object's id column (Executor.derivedSelf) instead of a resolved ID.
+const derivedSelfTarget = "__derived_self"
+
+// errDerivedSelfUnsupported reports a predicate that cannot compare against the
+// outer object, so the derived field is computed one object at a time instead.
+var errDerivedSelfUnsupported = errors.New("predicate does not support computing derived values in one query")
+
 // resolveTarget resolves a reference to an object ID.
 // Returns the resolved ID or an error if ambiguous.
 func (e *Executor) resolveTarget(target string) (string, error) {
+	if target == derivedSelfTarget {
+		return "", errDerivedSelfUnsupported
+	}
 	res, err := e.getResolver()
 	if err != nil {
 		return "", err
@@ -58,3 +81,29 @@ func (e *Executor) resolveTarget(target string) (string, error) {
 	}
 	return result.TargetID, nil
 }
+
+// targetIDExpr returns SQL for the object ID a target resolves to: a bound
+// parameter, or the outer object's id column for a derived field's `_`.
+func (e *Executor) targetIDExpr(target string) (string, []interface{}, error) {
+	if target == derivedSelfTarget && e.derivedSelf != "" {
+		return e.derivedSelf, nil, nil
+	}
+	resolved, err := e.resolveTarget(target)
+	if err != nil {
+		return "", nil, err
+	}
+	return "?", []interface{}{resolved}, nil
+}
+
+// refTargetCondition matches refs (aliased refAlias) pointing at target.
+func (e *Executor) refTargetCondition(refAlias, target string) (string, []interface{}, error) {
+	if target == derivedSelfTarget && e.derivedSelf != "" {
+		return fmt.Sprintf("(%[1]s.target_id = %[2]s OR %[1]s.target_raw = %[2]s)", refAlias, e.derivedSelf), nil, nil
+	}
+	resolved, err := e.resolveTarget(target)
+	if err != nil {
+		return "", nil, err
+	}
+	cond, args := buildRefTargetVariantsCondition(refAlias, resolved, target)
+	return cond, args, nil
+}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"database/sql"
+	"sort"
 	"strings"
 	"testing"
 
@@ -22,6 +23,8 @@ func setupTestDB(t *testing.T) *sql.DB {
 			type TEXT NOT NULL,
 			fields TEXT NOT NULL DEFAULT '{}',
 			line_start INTEGER NOT NULL,
+			file_mtime INTEGER,
+			file_created INTEGER,
 			created_at INTEGER,
 			updated_at INTEGER
 		);
@@ -32,6 +35,7 @@ func setupTestDB(t *testing.T) *sql.DB {
 			parent_object_id TEXT NOT NULL,
 			trait_type TEXT NOT NULL,
 			value TEXT,
+			raw_value TEXT,
 			content TEXT NOT NULL,
 			line_number INTEGER NOT NULL,
 			created_at INTEGER
@@ -94,13 +98,35 @@ func setupTestDB(t *testing.T) *sql.DB {
 			PRIMARY KEY (date, source_type, source_id, field_name)
 		);
 
+		CREATE TABLE trait_occurrences (
+			trait_id TEXT NOT NULL,
+			date TEXT NOT NULL,
+			file_path TEXT NOT NULL,
+			PRIMARY KEY (trait_id, date)
+		);
+
 		CREATE VIRTUAL TABLE fts_content USING fts5(
 			object_id,
 			title,
 			content,
+			headings,
+			code,
+			fields,
+			file_path UNINDEXED,
+			tokenize='porter unicode61'
+		);
+
+		CREATE VIRTUAL TABLE fts_traits USING fts5(
+			trait_id UNINDEXED,
+			content,
 			file_path UNINDEXED,
 			tokenize='porter unicode61'
 		);
+
+		CREATE TABLE focus (
+			object_id TEXT PRIMARY KEY,
+			added_at INTEGER NOT NULL
+		);
 	`)
 	if err != nil {
 		t.Fatalf("failed to create schema: %v", err)
@@ -157,14 +183,17 @@ func setupTestDB(t *testing.T) *sql.DB {
 			('assets/pdfs/paper.pdf', 'assets/pdfs/paper.pdf', 'application/pdf', 'pdf', 'paper.pdf', 12345, 100, 200),
 			('assets/raw/data.bin', 'assets/raw/data.bin', NULL, 'bin', 'data.bin', 99, 100, 200);
 
-		INSERT INTO fts_content (object_id, title, content, file_path) VALUES
-			('projects/website', 'Website Project', 'This is the website redesign project. Freya is a colleague working on this. Optional workflow input inputs.project is documented here.', 'projects/website.md'),
-			('projects/mobile', 'Mobile App', 'Mobile application for customers. Currently paused.', 'projects/mobile.md'),
-			('people/freya', 'Freya', 'Senior engineer and colleague. Works on platform team.', 'people/freya.md'),
-			('people/loki', 'Loki', 'Contractor helping with security review.', 'people/loki.md'),
-			('daily/2025-02-01', 'Daily Note', 'Morning standup and planning session.', 'daily/2025-02-01.md'),
-			('daily/2025-02-01#standup', 'Standup', 'Weekly standup meeting discussion.', 'daily/2025-02-01.md'),
-			('daily/2025-02-01#planning', 'Planning', 'Q2 planning session with the team.', 'daily/2025-02-01.md');
+		INSERT INTO fts_content (object_id, title, content, headings, code, fields, file_path) VALUES
+			('projects/website', 'Website Project', 'This is the website redesign project. Freya is a colleague working on this. Optional workflow input inputs.project is documented here.', 'Tasks', 'SELECT * FROM pages', 'high' || char(10) || 'active', 'projects/website.md'),
+			('projects/mobile', 'Mobile App', 'Mobile application for customers. Currently paused.', 'Tasks' || char(10) || 'Retro', '', 'medium' || char(10) || 'paused', 'projects/mobile.md'),
+			('people/freya', 'Freya', 'Senior engineer and colleague. Works on platform team.', '', '', 'freya@asgard.realm' || char(10) || 'Freya', 'people/freya.md'),
+			('people/loki', 'Loki', 'Contractor helping with security review.', '', '', 'Loki', 'people/loki.md'),
+			('daily/2025-02-01', 'Daily Note', 'Morning standup and planning session.', '', '', '', 'daily/2025-02-01.md'),
+			('daily/2025-02-01#standup', 'Standup', 'Weekly standup meeting discussion.', '', '', '', 'daily/2025-02-01.md'),
+			('daily/2025-02-01#planning', 'Planning', 'Q2 planning session with the team.', '', '', '', 'daily/2025-02-01.md');
+
+		INSERT INTO fts_traits (trait_id, content, file_path)
+			SELECT id, content, file_path FROM traits;
 	`)
 	if err != nil {
 		t.Fatalf("failed to insert test data: %v", err)
@@ -196,6 +225,7 @@ func setupRefRegressionDB(t *testing.T) *sql.DB {
 			parent_object_id TEXT NOT NULL,
 			trait_type TEXT NOT NULL,
 			value TEXT,
+			raw_value TEXT,
 			content TEXT NOT NULL,
 			line_number INTEGER NOT NULL,
 			created_at INTEGER
@@ -247,10 +277,19 @@ func setupRefRegressionDB(t *testing.T) *sql.DB {
 			PRIMARY KEY (date, source_type, source_id, field_name)
 		);
 
+		CREATE TABLE trait_occurrences (
+			trait_id TEXT NOT NULL,
+			date TEXT NOT NULL,
+			file_path TEXT NOT NULL,
+			PRIMARY KEY (trait_id, date)
+		);
+
 		CREATE VIRTUAL TABLE fts_content USING fts5(
 			object_id,
 			title,
 			content,
+			headings,
+			code,
 			file_path UNINDEXED,
 			tokenize='porter unicode61'
 		);
@@ -379,6 +418,41 @@ func TestExecuteObjectQuery(t *testing.T) {
 			query:     `type:project .status==active content("colleague")`,
 			wantCount: 1, // Website is active and mentions colleague
 		},
+		{
+			name:      "content title scope",
+			query:     `type:project content(title:"mobile")`,
+			wantCount: 1,
+		},
+		{
+			name:      "content title scope ignores body",
+			query:     `type:project content(title:"redesign")`,
+			wantCount: 0,
+		},
+		{
+			name:      "content heading scope",
+			query:     `type:project content(heading:"retro")`,
+			wantCount: 1, // Only mobile has a Retro heading
+		},
+		{
+			name:      "content code scope",
+			query:     `type:project content(code:"SELECT")`,
+			wantCount: 1, // Website has a SQL code block
+		},
+		{
+			name:      "content code scope negated",
+			query:     `type:project !content(code:"SELECT")`,
+			wantCount: 1,
+		},
+		{
+			name:      "content field scope",
+			query:     `type:project content(field:"paused")`,
+			wantCount: 1, // Only mobile has status paused
+		},
+		{
+			name:      "content field scope ignores body",
+			query:     `type:project content(field:"redesign")`,
+			wantCount: 0,
+		},
 		// Section containment predicate tests
 		{
 			name:      "has section",
@@ -814,6 +888,26 @@ func TestExecuteTraitQuery(t *testing.T) {
 			query:     `trait:highlight content("insight")`,
 			wantCount: 1, // trait3 has "Important insight"
 		},
+		{
+			name:      "content search stems terms",
+			query:     `trait:highlight content("insights")`,
+			wantCount: 1, // trait3 has "Important insight"
+		},
+		{
+			name:      "content search matches whole tokens",
+			query:     `trait:todo content("ild")`,
+			wantCount: 0, // substrings of "Build" are not tokens
+		},
+		{
+			name:      "content search phrase",
+			query:     `trait:todo content("\"landing page\"")`,
+			wantCount: 1, // trait5 has "Build landing page"
+		},
+		{
+			name:      "content search phrase out of order",
+			query:     `trait:todo content("\"page landing\"")`,
+			wantCount: 0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1127,6 +1221,21 @@ func TestBooleanEdgeCasesExecution(t *testing.T) {
 			query:     "type:project !oneof(.status, [active,paused])",
 			wantCount: 0, // all match
 		},
+		{
+			name:      "keyword AND NOT with OR group",
+			query:     "type:project (.status==active OR .status==paused) AND NOT refs([[people/freya]])",
+			wantCount: 1, // mobile; website refs freya
+		},
+		{
+			name:      "keyword OR across negated group",
+			query:     "type:project NOT (.status==active OR .status==paused) OR .priority==high",
+			wantCount: 1, // website
+		},
+		{
+			name:      "keyword operators inside subquery",
+			query:     "type:person refd(type:project (.status==active OR .status==paused) AND NOT .priority==medium)",
+			wantCount: 1, // freya, referenced by website
+		},
 	}
 
 	for _, tt := range tests {
@@ -1280,6 +1389,200 @@ func TestRefdPredicate(t *testing.T) {
 	}
 }
 
+func TestTransitiveRefPredicates(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	// notes/a -> notes/b, notes/b#intro -> notes/c, notes/c -> projects/website,
+	// notes/c -> notes/d -> notes/a (a cycle), and notes/e -> notes/b.
+	// topics/x_y has no refs; topics/xzy#s -> notes/e must not count as its.
+	_, err := db.Exec(`
+		INSERT INTO objects (id, file_path, type, fields, line_start) VALUES
+			('notes/a', 'notes/a.md', 'note', '{}', 1),
+			('notes/b', 'notes/b.md', 'note', '{}', 1),
+			('notes/c', 'notes/c.md', 'note', '{}', 1),
+			('notes/d', 'notes/d.md', 'note', '{}', 1),
+			('notes/e', 'notes/e.md', 'note', '{}', 1),
+			('topics/x_y', 'topics/x_y.md', 'topic', '{}', 1);
+
+		INSERT INTO refs (source_id, target_id, target_raw, file_path, line_number) VALUES
+			('notes/a', 'notes/b', 'notes/b', 'notes/a.md', 3),
+			('notes/b#intro', 'notes/c', 'notes/c', 'notes/b.md', 5),
+			('notes/c', 'projects/website', 'projects/website', 'notes/c.md', 3),
+			('notes/c', 'notes/d', 'notes/d', 'notes/c.md', 4),
+			('notes/d', 'notes/a', 'notes/a', 'notes/d.md', 3),
+			('notes/e', 'notes/b', 'notes/b', 'notes/e.md', 3),
+			('topics/xzy#s', 'notes/e', 'notes/e', 'topics/xzy.md', 3);
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert graph data: %v", err)
+	}
+
+	executor := NewExecutor(db)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantCount int
+	}{
+		{
+			name:      "refs* unbounded follows the cycle",
+			query:     "type:note refs*([[projects/website]])",
+			wantCount: 5,
+		},
+		{
+			name:      "refs* depth one matches direct refs",
+			query:     "type:note refs*([[projects/website]], depth<=1)",
+			wantCount: 1, // c
+		},
+		{
+			name:      "refs* through a section",
+			query:     "type:note refs*([[projects/website]], depth<=2)",
+			wantCount: 2, // c, b (via b#intro)
+		},
+		{
+			name:      "refs* strict depth bound",
+			query:     "type:note refs*([[projects/website]], depth<3)",
+			wantCount: 2,
+		},
+		{
+			name:      "refs* depth three",
+			query:     "type:note refs*([[projects/website]], depth<=3)",
+			wantCount: 4, // c, b, a, e
+		},
+		{
+			name:      "refs* with type subquery",
+			query:     "type:note refs*(type:project .status==active, depth<=2)",
+			wantCount: 2, // c, b reach the active website project
+		},
+		{
+			name:      "refs* matches unresolved refs",
+			query:     "type:project refs*([[projects/website]])",
+			wantCount: 1, // mobile refs website from its tasks section
+		},
+		{
+			name:      "negated refs*",
+			query:     "type:note !refs*([[projects/website]], depth<=1)",
+			wantCount: 4,
+		},
+		{
+			name:      "refd* unbounded",
+			query:     "type:note refd*([[notes/a]])",
+			wantCount: 4, // b, c, d, and a itself via the cycle
+		},
+		{
+			name:      "refd* depth two",
+			query:     "type:note refd*([[notes/a]], depth<=2)",
+			wantCount: 2, // b, c
+		},
+		{
+			name:      "refd* reaches other types",
+			query:     "type:person refd*([[notes/a]])",
+			wantCount: 1, // freya via c -> website
+		},
+		{
+			name:      "refd* respects depth across types",
+			query:     "type:project refd*([[notes/a]], depth<=2)",
+			wantCount: 0,
+		},
+		{
+			name:      "refd* with type subquery",
+			query:     "type:project refd*(type:note, depth<=1)",
+			wantCount: 1, // website from c
+		},
+		{
+			name:      "refd* treats LIKE wildcards in the source literally",
+			query:     "type:note refd*([[topics/x_y]])",
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+
+			results, err := executor.executeObjectQuery(q)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(results) != tt.wantCount {
+				t.Errorf("got %d results, want %d", len(results), tt.wantCount)
+				for _, r := range results {
+					t.Logf("  - %s (%s)", r.ID, r.Type)
+				}
+			}
+		})
+	}
+}
+
+func TestFocusPredicate(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO objects (id, file_path, type, fields, line_start) VALUES
+			('notes/a', 'notes/a.md', 'note', '{}', 1),
+			('notes/b', 'notes/b.md', 'note', '{}', 1);
+		INSERT INTO traits (id, trait_type, value, content, file_path, line_number, parent_object_id) VALUES
+			('notes/a.md:trait:0', 'due', '2026-01-01', 'ship it @due(2026-01-01)', 'notes/a.md', 3, 'notes/a'),
+			('notes/b.md:trait:0', 'due', '2026-01-02', 'later @due(2026-01-02)', 'notes/b.md', 3, 'notes/b');
+		INSERT INTO focus (object_id, added_at) VALUES ('notes/a', 1), ('projects/website', 2);
+	`)
+	if err != nil {
+		t.Fatalf("failed to insert focus data: %v", err)
+	}
+
+	executor := NewExecutor(db)
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "type:note @focus", want: []string{"notes/a"}},
+		{query: "type:* @focus", want: []string{"notes/a", "projects/website"}},
+		{query: "type:note !@focus", want: []string{"notes/b"}},
+		{query: "trait:due @focus", want: []string{"notes/a.md:trait:0", "trait1"}}, // trait1 is in projects/website.md
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			var got []string
+			if q.Type == QueryTypeTrait {
+				results, err := executor.ExecuteTraitQuery(q)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				for _, r := range results {
+					got = append(got, r.ID)
+				}
+			} else {
+				results, err := executor.executeObjectQuery(q)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				for _, r := range results {
+					got = append(got, r.ID)
+				}
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Parse("type:note @recent"); err == nil {
+		t.Fatal("expected error for unknown target set")
+	}
+}
+
 func TestComparisonOperators(t *testing.T) {
 	t.Parallel()
 	db := setupTestDB(t)
@@ -0,0 +1,247 @@
+package query
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func TestTimestampPredicates(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2026, 2, 14, 12, 0, 0, 0, time.Local)
+	day := func(y int, m time.Month, d int) int64 {
+		return time.Date(y, m, d, 9, 0, 0, 0, time.Local).Unix()
+	}
+	timestamps := []struct {
+		id      string
+		mtime   int64
+		created interface{}
+	}{
+		{"projects/website", day(2026, 2, 12), day(2025, 6, 1)},
+		{"projects/mobile", day(2026, 1, 10), day(2026, 1, 5)},
+		{"people/freya", day(2026, 2, 13), nil},
+		{"people/loki", day(2025, 12, 1), day(2025, 11, 1)},
+		{"daily/2025-02-01", day(2025, 2, 1), day(2025, 2, 1)},
+	}
+	for _, ts := range timestamps {
+		if _, err := db.Exec(`UPDATE objects SET file_mtime = ?, file_created = ? WHERE id = ?`, ts.mtime, ts.created, ts.id); err != nil {
+			t.Fatalf("failed to set timestamps: %v", err)
+		}
+	}
+
+	executor := NewExecutor(db)
+	executor.nowFn = func() time.Time { return now }
+
+	objectTests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "modified within days",
+			query: "type:project modified(within:7d)",
+			want:  []string{"projects/website"},
+		},
+		{
+			name:  "created before date",
+			query: "type:project created(before:2026-01-01)",
+			want:  []string{"projects/website"},
+		},
+		{
+			name:  "created falls back to mtime",
+			query: "type:person created(after:2026-01-31)",
+			want:  []string{"people/freya"},
+		},
+		{
+			name:  "combined bounds",
+			query: "type:project modified(after:2025-12-31, before:2026-02-01)",
+			want:  []string{"projects/mobile"},
+		},
+		{
+			name:  "relative date keyword",
+			query: "type:person modified(after:yesterday)",
+			want:  []string{},
+		},
+		{
+			name:  "negated",
+			query: "type:project !modified(within:2w)",
+			want:  []string{"projects/mobile"},
+		},
+	}
+
+	for _, tt := range objectTests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			results, err := executor.executeObjectQuery(q)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := make([]string, 0, len(results))
+			for _, r := range results {
+				got = append(got, r.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("trait uses file timestamps", func(t *testing.T) {
+		q, err := Parse("trait:due modified(within:7d)")
+		if err != nil {
+			t.Fatalf("parse error: %v", err)
+		}
+		results, err := executor.executeTraitQuery(q)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 2 {
+			t.Errorf("got %d traits, want 2 (website and freya)", len(results))
+		}
+	})
+}
+
+func TestParseTimestampPredicateErrors(t *testing.T) {
+	t.Parallel()
+	for _, input := range []string{
+		"type:project modified()",
+		"type:project modified(within:7x)",
+		"type:project created(before:next-week)",
+		"type:project created(since:2026-01-01)",
+		"type:project modified(within)",
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected error", input)
+		}
+	}
+}
+
+func TestExpiredPredicate(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Date(2026, 2, 14, 12, 0, 0, 0, time.Local)
+	day := func(y int, m time.Month, d int) int64 {
+		return time.Date(y, m, d, 9, 0, 0, 0, time.Local).Unix()
+	}
+	timestamps := []struct {
+		id      string
+		mtime   int64
+		created interface{}
+	}{
+		{"projects/website", day(2026, 2, 12), day(2025, 6, 1)},
+		{"projects/mobile", day(2026, 1, 10), day(2026, 1, 5)},
+		{"people/freya", day(2026, 2, 13), nil},
+		{"people/loki", day(2025, 1, 1), day(2024, 11, 1)},
+	}
+	for _, ts := range timestamps {
+		if _, err := db.Exec(`UPDATE objects SET file_mtime = ?, file_created = ? WHERE id = ?`, ts.mtime, ts.created, ts.id); err != nil {
+			t.Fatalf("failed to set timestamps: %v", err)
+		}
+	}
+
+	sch := &schema.Schema{Types: map[string]*schema.TypeDefinition{
+		"person":  {ReviewAfter: "1y"},
+		"project": {ReviewAfter: "26w", ReviewFrom: "created"},
+	}}
+
+	executor := NewExecutor(db)
+	executor.SetSchema(sch)
+	executor.nowFn = func() time.Time { return now }
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "type:person expired()", want: []string{"people/loki"}},
+		{query: "type:project expired()", want: []string{"projects/website"}},
+		{query: "type:project !expired()", want: []string{"projects/mobile"}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		results, err := executor.executeObjectQuery(q)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.query, err)
+		}
+		got := make([]string, 0, len(results))
+		for _, r := range results {
+			got = append(got, r.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if _, err := Parse("type:person expired(1y)"); err == nil {
+		t.Error("expected expired() with arguments to fail parsing")
+	}
+}
+
+func TestLifecyclePredicate(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	sch := &schema.Schema{Types: map[string]*schema.TypeDefinition{
+		"project": {
+			Fields:    map[string]*schema.FieldDefinition{"status": {Type: schema.FieldTypeString}},
+			Lifecycle: &schema.LifecycleDefinition{States: []string{"active", "paused", "archived"}},
+		},
+	}}
+
+	executor := NewExecutor(db)
+	executor.SetSchema(sch)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "type:project lifecycle(active)", want: []string{"projects/website"}},
+		{query: `type:project lifecycle(active, "paused")`, want: []string{"projects/mobile", "projects/website"}},
+		{query: "type:project !lifecycle(archived)", want: []string{"projects/mobile", "projects/website"}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.query, err)
+		}
+		results, err := executor.executeObjectQuery(q)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.query, err)
+		}
+		got := make([]string, 0, len(results))
+		for _, r := range results {
+			got = append(got, r.ID)
+		}
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"type:project lifecycle()", "type:project lifecycle(active,)"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) expected error", input)
+		}
+	}
+	q, err := Parse("type:project lifecycle(done)")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if err := NewValidator(sch).Validate(q); err == nil {
+		t.Error("expected an unknown lifecycle state to fail validation")
+	}
+}
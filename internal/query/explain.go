@@ -0,0 +1,305 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Match explains how one predicate of a query applies to one result row.
+type Match struct {
+	Predicate    string   `json:"predicate"`
+	Matched      bool     `json:"matched"`
+	Values       []string `json:"values,omitempty"`       // What matched, e.g. "status=active" or "[[people/freya]] on line 12"
+	Alternatives []Match  `json:"alternatives,omitempty"` // One entry per branch of an OR
+}
+
+// ExplainMatches reports, for each result ID, which of the query's top-level
+// predicates held and the values that made them hold. OR predicates list each
+// alternative, so rows that matched through an unexpected branch stand out.
+// text supplies predicate source text from ParseWithText.
+func (e *Executor) ExplainMatches(q *Query, text PredicateText, ids []string) (map[string][]Match, error) {
+	explanations := make(map[string][]Match, len(ids))
+	if q == nil || q.Predicate == nil || len(ids) == 0 {
+		return explanations, nil
+	}
+	scoped := e.withExecutionNow()
+
+	conjuncts := []Predicate{q.Predicate}
+	if group, ok := q.Predicate.(*GroupPredicate); ok && !group.Negated() {
+		conjuncts = group.Predicates
+	}
+
+	for _, pred := range conjuncts {
+		perID, err := scoped.explainPredicate(q, text, pred, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			explanations[id] = append(explanations[id], perID[id])
+		}
+	}
+	return explanations, nil
+}
+
+func (e *Executor) explainPredicate(q *Query, text PredicateText, pred Predicate, ids []string) (map[string]Match, error) {
+	matched, err := e.matchingIDs(q, pred)
+	if err != nil {
+		return nil, err
+	}
+
+	var alternatives map[string][]Match
+	if or, ok := pred.(*OrPredicate); ok && !or.Negated() {
+		alternatives = make(map[string][]Match, len(ids))
+		for _, alt := range or.Predicates {
+			perID, err := e.explainPredicate(q, text, alt, ids)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range ids {
+				alternatives[id] = append(alternatives[id], perID[id])
+			}
+		}
+	}
+
+	label := text[pred]
+	if label == "" {
+		label = fmt.Sprintf("%T", pred)
+		label = strings.TrimSuffix(strings.TrimPrefix(label, "*query."), "Predicate")
+	}
+
+	out := make(map[string]Match, len(ids))
+	for _, id := range ids {
+		match := Match{Predicate: label, Matched: matched[id], Alternatives: alternatives[id]}
+		if match.Matched && !pred.Negated() {
+			values, err := e.matchValues(q, pred, id)
+			if err != nil {
+				return nil, err
+			}
+			match.Values = values
+		}
+		out[id] = match
+	}
+	return out, nil
+}
+
+// matchingIDs runs the query root with pred as its only filter.
+func (e *Executor) matchingIDs(q *Query, pred Predicate) (map[string]bool, error) {
+	single := &Query{Type: q.Type, TypeName: q.TypeName, TypeNames: q.TypeNames, Predicate: pred}
+	var (
+		ids []string
+		err error
+	)
+	switch q.Type {
+	case QueryTypeObject:
+		ids, err = e.executeObjectIDQuery(single, 0, 0)
+	case QueryTypeTrait:
+		ids, err = e.executeTraitIDQuery(single, 0, 0)
+	case QueryTypeSection:
+		ids, err = e.executeSectionIDQuery(single, 0, 0)
+	case QueryTypeAsset:
+		ids, err = e.executeAssetIDQuery(single, 0, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set, nil
+}
+
+// matchValues returns the values behind a matched predicate. Only predicates
+// with a concrete value to show are covered; others explain by Matched alone.
+func (e *Executor) matchValues(q *Query, pred Predicate, id string) ([]string, error) {
+	switch p := pred.(type) {
+	case *FieldPredicate:
+		return e.fieldValues(q, p.Field, id)
+	case *StringFuncPredicate:
+		if p.IsElementRef {
+			return nil, nil
+		}
+		return e.fieldValues(q, p.Field, id)
+	case *ArrayQuantifierPredicate:
+		return e.fieldValues(q, p.Field, id)
+	case *RefsPredicate:
+		if q.Type != QueryTypeObject || p.Transitive {
+			return nil, nil
+		}
+		return e.refsValues(p, id)
+	case *RefdPredicate:
+		if q.Type != QueryTypeObject || p.Transitive {
+			return nil, nil
+		}
+		return e.refdValues(p, id)
+	case *HasPredicate:
+		if q.Type != QueryTypeObject || p.SubQuery == nil || p.SubQuery.Type != QueryTypeTrait {
+			return nil, nil
+		}
+		return e.hasTraitValues(p, id)
+	}
+	return nil, nil
+}
+
+func (e *Executor) fieldValues(q *Query, field, id string) ([]string, error) {
+	switch q.Type {
+	case QueryTypeObject:
+		var fieldsJSON string
+		if err := e.db.QueryRow(`SELECT fields FROM objects WHERE id = ?`, id).Scan(&fieldsJSON); err != nil {
+			return nil, err
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal([]byte(fieldsJSON), &fields); err != nil {
+			return nil, nil
+		}
+		value, ok := fields[field]
+		if !ok {
+			return nil, nil
+		}
+		return []string{field + "=" + formatMatchValue(value)}, nil
+	case QueryTypeTrait:
+		if field != "value" {
+			return nil, nil
+		}
+		var value *string
+		if err := e.db.QueryRow(`SELECT value FROM traits WHERE id = ?`, id).Scan(&value); err != nil {
+			return nil, err
+		}
+		if value == nil {
+			return nil, nil
+		}
+		return []string{"value=" + *value}, nil
+	}
+	return nil, nil
+}
+
+func formatMatchValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = formatMatchValue(item)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+func (e *Executor) refsValues(p *RefsPredicate, id string) ([]string, error) {
+	var (
+		sqlStr string
+		args   = []interface{}{id, escapeLikePattern(id) + "#%"}
+	)
+	switch {
+	case p.Target != "":
+		resolvedTarget, err := e.resolveTarget(p.Target)
+		if err != nil {
+			return nil, err
+		}
+		targetCond, targetArgs := buildRefTargetVariantsCondition("r", resolvedTarget, p.Target)
+		sqlStr = `SELECT r.target_raw, r.line_number FROM refs r
+			WHERE (r.source_id = ? OR r.source_id LIKE ? ESCAPE '\') AND ` + targetCond
+		args = append(args, targetArgs...)
+	case p.SubQuery != nil && p.SubQuery.Type == QueryTypeObject:
+		targetCond, targetArgs, err := e.buildObjectWhereForAlias(p.SubQuery, "target_obj")
+		if err != nil {
+			return nil, err
+		}
+		sqlStr = `SELECT r.target_raw, r.line_number FROM refs r
+			JOIN objects target_obj ON (r.target_id = target_obj.id OR (r.target_id IS NULL AND r.target_raw = target_obj.id))
+			WHERE (r.source_id = ? OR r.source_id LIKE ? ESCAPE '\') AND ` + targetCond
+		args = append(args, targetArgs...)
+	default:
+		return nil, nil
+	}
+	return e.refLineValues(sqlStr+" ORDER BY r.line_number", args, func(ref string) string { return "[[" + ref + "]]" })
+}
+
+func (e *Executor) refdValues(p *RefdPredicate, id string) ([]string, error) {
+	var (
+		sqlStr string
+		args   []interface{}
+	)
+	switch {
+	case p.Target != "" && !strings.HasPrefix(p.Target, "__trait_line:"):
+		sourceID, err := e.resolveTarget(p.Target)
+		if err != nil {
+			return nil, err
+		}
+		sqlStr = `SELECT r.source_id, r.line_number FROM refs r
+			WHERE r.source_id = ? AND (r.target_id = ? OR r.target_raw = ?)`
+		args = []interface{}{sourceID, id, id}
+	case p.SubQuery != nil && p.SubQuery.Type == QueryTypeObject:
+		sourceCond, sourceArgs, err := e.buildObjectWhereForAlias(p.SubQuery, "src")
+		if err != nil {
+			return nil, err
+		}
+		sqlStr = `SELECT r.source_id, r.line_number FROM refs r
+			JOIN objects src ON r.source_id = src.id
+			WHERE (r.target_id = ? OR r.target_raw = ?) AND ` + sourceCond
+		args = append([]interface{}{id, id}, sourceArgs...)
+	default:
+		return nil, nil
+	}
+	return e.refLineValues(sqlStr+" ORDER BY r.source_id, r.line_number", args, func(source string) string { return "from " + source })
+}
+
+func (e *Executor) refLineValues(sqlStr string, args []interface{}, label func(string) string) ([]string, error) {
+	rows, err := e.db.Query(sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var ref string
+		var line *int
+		if err := rows.Scan(&ref, &line); err != nil {
+			return nil, err
+		}
+		value := label(ref)
+		if line != nil {
+			value += fmt.Sprintf(" on line %d", *line)
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+func (e *Executor) hasTraitValues(p *HasPredicate, id string) ([]string, error) {
+	cond, condArgs, err := e.traitSubqueryCondition(p.SubQuery, "t")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := e.db.Query(`SELECT t.trait_type, t.value, t.line_number FROM traits t
+		WHERE t.parent_object_id = ? AND `+cond+` ORDER BY t.line_number`, append([]interface{}{id}, condArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var traitType string
+		var value *string
+		var line int
+		if err := rows.Scan(&traitType, &value, &line); err != nil {
+			return nil, err
+		}
+		entry := "@" + traitType
+		if value != nil && *value != "" {
+			entry += "=" + *value
+		}
+		values = append(values, fmt.Sprintf("%s on line %d", entry, line))
+	}
+	return values, rows.Err()
+}
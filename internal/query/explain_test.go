@@ -0,0 +1,93 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExplainMatches(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	executor := NewExecutor(db)
+
+	q, text, err := ParseWithText("type:project .status==active (refs([[people/freya]]) | has(trait:due))")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got, err := executor.ExplainMatches(q, text, []string{"projects/website", "projects/mobile"})
+	if err != nil {
+		t.Fatalf("ExplainMatches: %v", err)
+	}
+
+	website := got["projects/website"]
+	if len(website) != 2 {
+		t.Fatalf("expected 2 top-level predicates, got %+v", website)
+	}
+	want := Match{Predicate: ".status==active", Matched: true, Values: []string{"status=active"}}
+	if !reflect.DeepEqual(website[0], want) {
+		t.Fatalf("field match = %+v, want %+v", website[0], want)
+	}
+	or := website[1]
+	if or.Predicate != "(refs([[people/freya]]) | has(trait:due))" || !or.Matched || len(or.Alternatives) != 2 {
+		t.Fatalf("or match = %+v", or)
+	}
+	if got := or.Alternatives[0].Values; !reflect.DeepEqual(got, []string{"[[people/freya]] on line 5"}) {
+		t.Fatalf("refs values = %v", got)
+	}
+	if got := or.Alternatives[1].Values; !reflect.DeepEqual(got, []string{"@due=2025-06-30 on line 1"}) {
+		t.Fatalf("has values = %v", got)
+	}
+
+	mobile := got["projects/mobile"]
+	if mobile[0].Matched || mobile[0].Values != nil {
+		t.Fatalf("expected unmatched status predicate without values, got %+v", mobile[0])
+	}
+	if mobile[1].Matched || mobile[1].Alternatives[0].Matched || mobile[1].Alternatives[1].Matched {
+		t.Fatalf("expected unmatched OR for mobile, got %+v", mobile[1])
+	}
+}
+
+func TestExplainMatches_TraitValue(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	executor := NewExecutor(db)
+
+	q, text, err := ParseWithText("trait:due .value<2025-03-01 within(type:person)")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got, err := executor.ExplainMatches(q, text, []string{"trait4"})
+	if err != nil {
+		t.Fatalf("ExplainMatches: %v", err)
+	}
+	matches := got["trait4"]
+	if len(matches) != 2 || !matches[0].Matched || !reflect.DeepEqual(matches[0].Values, []string{"value=2025-02-01"}) {
+		t.Fatalf("trait matches = %+v", matches)
+	}
+}
+
+func TestExplainMatches_RefsSourceIsNotAPattern(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	if _, err := db.Exec(`
+		INSERT INTO objects (id, file_path, type, fields, line_start) VALUES
+			('projects/web_app', 'projects/web_app.md', 'project', '{}', 1);
+		INSERT INTO refs (source_id, target_id, target_raw, file_path, line_number) VALUES
+			('projects/webxapp#notes', 'people/freya', 'people/freya', 'projects/webxapp.md', 7);
+	`); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	executor := NewExecutor(db)
+
+	q, text, err := ParseWithText("type:project refs([[people/freya]])")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got, err := executor.ExplainMatches(q, text, []string{"projects/web_app"})
+	if err != nil {
+		t.Fatalf("ExplainMatches: %v", err)
+	}
+	if values := got["projects/web_app"][0].Values; len(values) != 0 {
+		t.Fatalf("refs values = %v, want none from projects/webxapp", values)
+	}
+}
@@ -215,6 +215,28 @@ func (l *Lexer) NextToken() Token {
 		}
 		// Otherwise it's an identifier
 		return l.scanIdent()
+	case '+':
+		// Signed offsets such as +7d in date(+7d) lex as identifiers.
+		if l.pos+1 < len(l.input) && l.input[l.pos+1] >= '0' && l.input[l.pos+1] <= '9' {
+			l.pos++
+			for l.pos < len(l.input) && isIdentChar(l.input[l.pos]) {
+				l.pos++
+			}
+			return Token{Type: TokenIdent, Value: l.input[l.start:l.pos], Pos: l.start}
+		}
+		l.pos++
+		return Token{Type: TokenError, Value: string(ch), Pos: l.start}
+	case '@':
+		// Named target sets such as @focus lex as identifiers.
+		if l.pos+1 < len(l.input) && isIdentStart(l.input[l.pos+1]) {
+			l.pos++
+			for l.pos < len(l.input) && isIdentChar(l.input[l.pos]) {
+				l.pos++
+			}
+			return Token{Type: TokenIdent, Value: l.input[l.start:l.pos], Pos: l.start}
+		}
+		l.pos++
+		return Token{Type: TokenError, Value: string(ch), Pos: l.start}
 	case '_':
 		// Check if it's a standalone _ or part of an identifier
 		// Standalone _ is followed by nothing, whitespace, '.', ':', or EOF
@@ -3,18 +3,57 @@ package query
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/dates"
 )
 
+// parseFieldPath reads the field name after '.', along with any nested keys
+// (.address.city) and array indexes (.authors[0].name) written directly after
+// it, and returns the path as written.
+func (p *Parser) parseFieldPath() (string, error) {
+	if p.curr.Type != TokenIdent {
+		return "", fmt.Errorf("expected field name after '.'")
+	}
+	path := p.curr.Value
+	end := p.curr.Pos + len(p.curr.Value)
+	p.advance()
+
+	for p.curr.Pos == end {
+		switch {
+		case p.curr.Type == TokenDot && p.peek.Type == TokenIdent && p.peek.Pos == end+1:
+			path += "." + p.peek.Value
+			end = p.peek.Pos + len(p.peek.Value)
+			p.advance()
+			p.advance()
+		case p.curr.Type == TokenLBracket:
+			p.advance()
+			if p.curr.Type != TokenIdent || strings.Trim(p.curr.Value, "0123456789") != "" {
+				return "", fmt.Errorf("expected array index after '[' in .%s at pos %d", path, p.curr.Pos)
+			}
+			index := p.curr.Value
+			p.advance()
+			if p.curr.Type != TokenRBracket {
+				return "", fmt.Errorf("expected ']' after array index in .%s at pos %d", path, p.curr.Pos)
+			}
+			path += "[" + index + "]"
+			end = p.curr.Pos + 1
+			p.advance()
+		default:
+			return path, nil
+		}
+	}
+	return path, nil
+}
+
 // parseFieldPredicate parses .field==value, .field!=value, .field>value, etc.
 // For string matching, use function-style predicates: includes(.field, "str"), startswith(...), etc.
 func (p *Parser) parseFieldPredicate(negated bool) (Predicate, error) {
-	if p.curr.Type != TokenIdent {
-		return nil, fmt.Errorf("expected field name after '.'")
+	field, err := p.parseFieldPath()
+	if err != nil {
+		return nil, err
 	}
 
-	field := p.curr.Value
-	p.advance()
-
 	// Determine the operator
 	var compareOp CompareOp
 
@@ -49,7 +88,17 @@ func (p *Parser) parseFieldPredicate(negated bool) (Predicate, error) {
 		return nil, fmt.Errorf(".field==* is no longer supported; use exists(.field) or !exists(.field) instead")
 	case TokenIdent:
 		value = p.curr.Value
+		end := p.curr.Pos + len(value)
 		p.advance()
+		// A '(' directly after the value makes it a date function call;
+		// with whitespace in between it opens a grouped predicate.
+		if p.curr.Type == TokenLParen && p.curr.Pos == end {
+			call, err := p.parseDateFunctionValue(value)
+			if err != nil {
+				return nil, err
+			}
+			value = call
+		}
 	case TokenRef:
 		value = p.curr.Value
 		isRefValue = true
@@ -71,6 +120,33 @@ func (p *Parser) parseFieldPredicate(negated bool) (Predicate, error) {
 	}, nil
 }
 
+// parseDateFunctionValue parses the argument list of a date function used as a
+// comparison value, e.g. date(+7d), startOfMonth(), or within(3d), and returns
+// its canonical spelling. The function is resolved against the execution time
+// when the query runs.
+func (p *Parser) parseDateFunctionValue(name string) (string, error) {
+	if !dates.IsDateFunction(name) {
+		return "", fmt.Errorf("unknown date function %s(); use date(), within(), startOfWeek(), endOfWeek(), startOfMonth(), endOfMonth(), startOfYear(), or endOfYear()", name)
+	}
+	if err := p.expect(TokenLParen); err != nil {
+		return "", err
+	}
+	arg := ""
+	if p.curr.Type == TokenIdent {
+		arg = p.curr.Value
+		p.advance()
+	}
+	if err := p.expect(TokenRParen); err != nil {
+		return "", fmt.Errorf("expected ')' after %s( argument: %w", name, err)
+	}
+
+	call := dates.FormatDateFunction(name, arg)
+	if _, _, err := dates.ResolveDateFunction(call, time.Now(), time.Monday); err != nil {
+		return "", err
+	}
+	return call, nil
+}
+
 type parsedValue struct {
 	Value string
 	IsRef bool
@@ -142,11 +218,10 @@ func (p *Parser) parseInPredicate(negated bool) (Predicate, error) {
 		return nil, fmt.Errorf("expected .field as first argument to oneof()")
 	}
 	p.advance()
-	if p.curr.Type != TokenIdent {
-		return nil, fmt.Errorf("expected field name after '.'")
+	field, err := p.parseFieldPath()
+	if err != nil {
+		return nil, err
 	}
-	field := p.curr.Value
-	p.advance()
 
 	if err := p.expect(TokenComma); err != nil {
 		return nil, err
@@ -212,11 +287,11 @@ func (p *Parser) parseStringFuncPredicate(negated bool, funcType StringFuncType)
 	// Parse first argument: .field or _
 	if p.curr.Type == TokenDot {
 		p.advance()
-		if p.curr.Type != TokenIdent {
-			return nil, fmt.Errorf("expected field name after '.'")
+		field, err := p.parseFieldPath()
+		if err != nil {
+			return nil, err
 		}
-		pred.Field = p.curr.Value
-		p.advance()
+		pred.Field = field
 	} else if p.curr.Type == TokenUnderscore {
 		pred.Field = "_"
 		pred.IsElementRef = true
@@ -288,11 +363,11 @@ func (p *Parser) parseArrayQuantifierPredicate(negated bool, quantifier ArrayQua
 		return nil, fmt.Errorf("expected .field as first argument to %s()", quantifier)
 	}
 	p.advance()
-	if p.curr.Type != TokenIdent {
-		return nil, fmt.Errorf("expected field name after '.'")
+	field, err := p.parseFieldPath()
+	if err != nil {
+		return nil, err
 	}
-	pred.Field = p.curr.Value
-	p.advance()
+	pred.Field = field
 
 	// Expect comma
 	if err := p.expect(TokenComma); err != nil {
@@ -323,23 +398,26 @@ func (p *Parser) parseElementOrPredicate() (Predicate, error) {
 		return nil, fmt.Errorf("expected element predicate")
 	}
 
-	if p.curr.Type != TokenPipe {
+	if !p.atOr() {
 		return first, nil
 	}
 
 	preds := []Predicate{first}
-	for p.curr.Type == TokenPipe {
-		pipePos := p.curr.Pos
+	for p.atOr() {
+		op := p.curr
 		p.advance()
-		if looksLikeShellPipeCommand(p.curr) {
-			return nil, shellPipeQueryError(pipePos)
+		if op.Type == TokenPipe && looksLikeShellPipeCommand(p.curr) {
+			return nil, shellPipeQueryError(op.Pos)
 		}
 		next, err := p.parseElementAndPredicate()
 		if err != nil {
 			return nil, err
 		}
 		if next == nil {
-			return nil, fmt.Errorf("expected element predicate after '|'")
+			if op.Type == TokenPipe {
+				return nil, fmt.Errorf("expected element predicate after '|'")
+			}
+			return nil, fmt.Errorf("expected element predicate after OR")
 		}
 		preds = append(preds, next)
 	}
@@ -352,9 +430,16 @@ func (p *Parser) parseElementAndPredicate() (Predicate, error) {
 	var preds []Predicate
 
 	for {
-		if p.curr.Type == TokenRParen || p.curr.Type == TokenPipe {
+		if p.curr.Type == TokenRParen || p.atOr() {
 			break
 		}
+		if p.atKeyword(keywordAnd) {
+			andPos := p.curr.Pos
+			p.advance()
+			if len(preds) == 0 || p.curr.Type == TokenRParen || p.atOr() {
+				return nil, fmt.Errorf("expected element predicates on both sides of AND at pos %d", andPos)
+			}
+		}
 		pred, err := p.parseElementUnaryPredicate()
 		if err != nil {
 			return nil, err
@@ -379,7 +464,7 @@ func (p *Parser) parseElementAndPredicate() (Predicate, error) {
 func (p *Parser) parseElementUnaryPredicate() (Predicate, error) {
 	// Check for negation
 	negated := false
-	if p.curr.Type == TokenBang {
+	if p.atNot() {
 		negated = true
 		p.advance()
 	}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -10,6 +11,7 @@ type Parser struct {
 	lexer *Lexer
 	curr  Token
 	peek  Token
+	spans PredicateText
 }
 
 var commonShellPipeCommands = map[string]struct{}{
@@ -48,31 +50,86 @@ func looksLikeShellPipeCommand(tok Token) bool {
 	return ok
 }
 
+// Boolean keywords are uppercase aliases for the symbolic operators:
+// OR for '|', AND for whitespace, NOT for '!'.
+const (
+	keywordOr  = "OR"
+	keywordAnd = "AND"
+	keywordNot = "NOT"
+)
+
+func (p *Parser) atKeyword(keyword string) bool {
+	return p.curr.Type == TokenIdent && p.curr.Value == keyword
+}
+
+// atOr reports whether the current token is an OR operator ('|' or OR).
+func (p *Parser) atOr() bool {
+	return p.curr.Type == TokenPipe || p.atKeyword(keywordOr)
+}
+
+// atNot reports whether the current token is a NOT operator ('!' or NOT).
+func (p *Parser) atNot() bool {
+	return p.curr.Type == TokenBang || p.atKeyword(keywordNot)
+}
+
+// atPredicateEnd reports whether the current token ends an AND sequence. A
+// comma ends a subquery followed by further function arguments.
+func (p *Parser) atPredicateEnd() bool {
+	return p.curr.Type == TokenEOF || p.curr.Type == TokenRParen || p.curr.Type == TokenComma || p.atOr()
+}
+
 // Parse parses a query string and returns a Query AST.
 func Parse(input string) (*Query, error) {
-	p := &Parser{lexer: NewLexer(input)}
+	q, _, err := ParseWithText(input)
+	return q, err
+}
+
+// PredicateText maps the top-level conjuncts and OR alternatives of a parsed
+// query to their text as written.
+type PredicateText map[Predicate]string
+
+// ParseWithText parses a query string like Parse and also returns the source
+// text of its predicates, so explanations can quote them as the user wrote them.
+func ParseWithText(input string) (*Query, PredicateText, error) {
+	p := &Parser{lexer: NewLexer(input), spans: make(PredicateText)}
 	p.advance()
 	p.advance()
 	if p.curr.Type == TokenError {
-		return nil, fmt.Errorf("%s at pos %d", p.curr.Value, p.curr.Pos)
+		return nil, nil, fmt.Errorf("%s at pos %d", p.curr.Value, p.curr.Pos)
 	}
 	q, err := p.parseQuery()
 	if err != nil {
 		if p.curr.Type == TokenError {
-			return nil, fmt.Errorf("%s at pos %d", p.curr.Value, p.curr.Pos)
+			return nil, nil, fmt.Errorf("%s at pos %d", p.curr.Value, p.curr.Pos)
 		}
-		return nil, err
+		return nil, nil, err
 	}
 	if p.curr.Type == TokenError {
-		return nil, fmt.Errorf("%s at pos %d", p.curr.Value, p.curr.Pos)
+		return nil, nil, fmt.Errorf("%s at pos %d", p.curr.Value, p.curr.Pos)
 	}
 	if p.curr.Type != TokenEOF {
 		if p.curr.Type == TokenPipe {
-			return nil, shellPipeQueryError(p.curr.Pos)
+			return nil, nil, shellPipeQueryError(p.curr.Pos)
 		}
-		return nil, fmt.Errorf("unexpected token %v at pos %d", p.curr.Type, p.curr.Pos)
+		return nil, nil, fmt.Errorf("unexpected token %v at pos %d", p.curr.Type, p.curr.Pos)
 	}
-	return q, nil
+	return q, p.spans, nil
+}
+
+// recordSpan remembers the source text of pred, from start up to the current
+// token, so explanations can quote predicates as the user wrote them.
+func (p *Parser) recordSpan(pred Predicate, start int) {
+	if p.spans == nil || pred == nil {
+		return
+	}
+	end := p.curr.Pos
+	if end > len(p.lexer.input) {
+		end = len(p.lexer.input)
+	}
+	if start < 0 || start > end {
+		return
+	}
+	p.spans[pred] = strings.TrimSpace(p.lexer.input[start:end])
 }
 
 func (p *Parser) advance() {
@@ -124,13 +181,6 @@ func (p *Parser) parseQuery() (*Query, error) {
 		return nil, err
 	}
 
-	if p.curr.Type != TokenIdent {
-		return nil, fmt.Errorf("expected type/trait name, got %v", p.curr.Value)
-	}
-
-	typeName := p.curr.Value
-	p.advance()
-
 	var query Query
 	switch queryKind {
 	case "type":
@@ -140,7 +190,18 @@ func (p *Parser) parseQuery() (*Query, error) {
 	default:
 		return nil, fmt.Errorf("invalid query type: %s (expected 'type', 'trait', 'section', or 'asset')", queryKind)
 	}
-	query.TypeName = typeName
+
+	if query.Type == QueryTypeObject && (p.curr.Type == TokenStar || p.curr.Type == TokenLParen) {
+		if err := p.parseTypeSelector(&query); err != nil {
+			return nil, err
+		}
+	} else {
+		if p.curr.Type != TokenIdent {
+			return nil, fmt.Errorf("expected type/trait name, got %v", p.curr.Value)
+		}
+		query.TypeName = p.curr.Value
+		p.advance()
+	}
 
 	// Parse predicates
 	pred, err := p.parsePredicate(query.Type)
@@ -157,6 +218,45 @@ func (p *Parser) parseQuery() (*Query, error) {
 	return &query, nil
 }
 
+// parseTypeSelector parses the cross-type selectors of an object query:
+// type:* for every type and type:(a|b) for a union of types.
+func (p *Parser) parseTypeSelector(query *Query) error {
+	if p.curr.Type == TokenStar {
+		p.advance()
+		query.TypeName = AnyType
+		return nil
+	}
+
+	p.advance() // consume (
+	var names []string
+	seen := make(map[string]struct{})
+	for {
+		if p.curr.Type != TokenIdent {
+			return fmt.Errorf("expected type name in type union, got %v", p.curr.Value)
+		}
+		if _, ok := seen[p.curr.Value]; !ok {
+			seen[p.curr.Value] = struct{}{}
+			names = append(names, p.curr.Value)
+		}
+		p.advance()
+		if !p.atOr() {
+			break
+		}
+		p.advance()
+	}
+	if err := p.expect(TokenRParen); err != nil {
+		return fmt.Errorf("unclosed type union: %w", err)
+	}
+
+	if len(names) == 1 {
+		query.TypeName = names[0]
+		return nil
+	}
+	query.TypeName = strings.Join(names, "|")
+	query.TypeNames = names
+	return nil
+}
+
 // parsePredicate parses a boolean expression of predicates.
 func (p *Parser) parsePredicate(qt QueryType) (Predicate, error) {
 	return p.parseOrPredicate(qt)
@@ -164,6 +264,7 @@ func (p *Parser) parsePredicate(qt QueryType) (Predicate, error) {
 
 // parseOrPredicate parses OR expressions (lowest precedence).
 func (p *Parser) parseOrPredicate(qt QueryType) (Predicate, error) {
+	start := p.curr.Pos
 	first, err := p.parseAndPredicate(qt)
 	if err != nil {
 		return nil, err
@@ -171,29 +272,37 @@ func (p *Parser) parseOrPredicate(qt QueryType) (Predicate, error) {
 	if first == nil {
 		return nil, nil
 	}
+	p.recordSpan(first, start)
 
-	if p.curr.Type != TokenPipe {
+	if !p.atOr() {
 		return first, nil
 	}
 
 	preds := []Predicate{first}
-	for p.curr.Type == TokenPipe {
-		pipePos := p.curr.Pos
+	for p.atOr() {
+		op := p.curr
 		p.advance()
-		if looksLikeShellPipeCommand(p.curr) {
-			return nil, shellPipeQueryError(pipePos)
+		if op.Type == TokenPipe && looksLikeShellPipeCommand(p.curr) {
+			return nil, shellPipeQueryError(op.Pos)
 		}
+		start := p.curr.Pos
 		next, err := p.parseAndPredicate(qt)
 		if err != nil {
 			return nil, err
 		}
 		if next == nil {
-			return nil, shellPipeQueryError(pipePos)
+			if op.Type == TokenPipe {
+				return nil, shellPipeQueryError(op.Pos)
+			}
+			return nil, fmt.Errorf("expected predicate after OR at pos %d", op.Pos)
 		}
+		p.recordSpan(next, start)
 		preds = append(preds, next)
 	}
 
-	return &OrPredicate{Predicates: preds}, nil
+	or := &OrPredicate{Predicates: preds}
+	p.recordSpan(or, start)
+	return or, nil
 }
 
 // parseAndPredicate parses implicit AND expressions (middle precedence).
@@ -202,20 +311,31 @@ func (p *Parser) parseAndPredicate(qt QueryType) (Predicate, error) {
 
 	for {
 		// Stop at EOF, closing parens, or OR operator
-		if p.curr.Type == TokenEOF || p.curr.Type == TokenRParen || p.curr.Type == TokenPipe {
+		if p.atPredicateEnd() {
 			break
 		}
 
+		// An explicit AND must sit between two predicates.
+		if p.atKeyword(keywordAnd) {
+			andPos := p.curr.Pos
+			p.advance()
+			if len(preds) == 0 || p.atPredicateEnd() || p.atKeyword(keywordAnd) {
+				return nil, fmt.Errorf("expected predicates on both sides of AND at pos %d", andPos)
+			}
+		}
+
+		start := p.curr.Pos
 		pred, err := p.parseUnaryPredicate(qt)
 		if err != nil {
 			return nil, err
 		}
 		if pred == nil {
-			if p.curr.Type == TokenEOF || p.curr.Type == TokenRParen || p.curr.Type == TokenPipe {
+			if p.atPredicateEnd() {
 				break
 			}
 			return nil, fmt.Errorf("unexpected token %v at pos %d", p.curr.Type, p.curr.Pos)
 		}
+		p.recordSpan(pred, start)
 		preds = append(preds, pred)
 	}
 
@@ -232,7 +352,7 @@ func (p *Parser) parseAndPredicate(qt QueryType) (Predicate, error) {
 func (p *Parser) parseUnaryPredicate(qt QueryType) (Predicate, error) {
 	// Check for negation
 	negated := false
-	if p.curr.Type == TokenBang {
+	if p.atNot() {
 		negated = true
 		p.advance()
 	}
@@ -256,7 +376,11 @@ func (p *Parser) parseUnaryPredicate(qt QueryType) (Predicate, error) {
 		return &NotPredicate{Inner: pred}, nil
 	}
 
-	return p.parseAtomicPredicate(qt, negated)
+	pred, err := p.parseAtomicPredicate(qt, negated)
+	if err == nil && pred == nil && negated {
+		return nil, fmt.Errorf("expected predicate after negation at pos %d", p.curr.Pos)
+	}
+	return pred, err
 }
 
 // parseAtomicPredicate parses a single predicate without boolean composition.
@@ -277,6 +401,22 @@ func (p *Parser) parseAtomicPredicate(qt QueryType, negated bool) (Predicate, er
 	if p.curr.Type == TokenIdent {
 		keyword := strings.ToLower(p.curr.Value)
 
+		// Transitive reference predicates: refs*(...), refd*(...)
+		if p.peek.Type == TokenStar && (keyword == "refs" || keyword == "refd") {
+			p.advance()
+			p.advance()
+			return p.parseTransitiveRefFuncPredicate(negated, keyword)
+		}
+
+		// Named target sets: @focus
+		if strings.HasPrefix(keyword, "@") {
+			if keyword != "@focus" {
+				return nil, fmt.Errorf("unknown target set %s; only @focus is supported", p.curr.Value)
+			}
+			p.advance()
+			return &FocusPredicate{basePredicate: basePredicate{negated: negated}}, nil
+		}
+
 		// Function-style predicates: func(...)
 		// v3: all structural predicates are functions (no keyword: forms).
 		if p.peek.Type == TokenLParen {
@@ -341,6 +481,40 @@ func (p *Parser) parseAtomicPredicate(qt QueryType, negated bool) (Predicate, er
 			case "at":
 				p.advance()
 				return p.parseAtFuncPredicate(negated)
+			// File timestamp windows
+			case "modified":
+				p.advance()
+				return p.parseTimestampFuncPredicate(negated, TimestampModified)
+			case "created":
+				p.advance()
+				return p.parseTimestampFuncPredicate(negated, TimestampCreated)
+			// Review policy expiry
+			case "expired":
+				p.advance()
+				if err := p.expect(TokenLParen); err != nil {
+					return nil, err
+				}
+				if err := p.expect(TokenRParen); err != nil {
+					return nil, fmt.Errorf("expired() takes no arguments; set review_after on the type in schema.yaml")
+				}
+				return &ExpiredPredicate{basePredicate: basePredicate{negated: negated}}, nil
+			// Type lifecycle state
+			case "lifecycle":
+				p.advance()
+				return p.parseLifecycleFuncPredicate(negated)
+			// Linked assets
+			case "has_attachment":
+				p.advance()
+				if err := p.expect(TokenLParen); err != nil {
+					return nil, err
+				}
+				if err := p.expect(TokenRParen); err != nil {
+					return nil, fmt.Errorf("has_attachment() takes no arguments; use attachment(type:pdf) to filter by asset type or size")
+				}
+				return &AttachmentPredicate{basePredicate: basePredicate{negated: negated}}, nil
+			case "attachment":
+				p.advance()
+				return p.parseAttachmentFuncPredicate(negated)
 			}
 		}
 
@@ -363,11 +537,10 @@ func (p *Parser) parseExistsPredicate(negated bool) (Predicate, error) {
 		return nil, fmt.Errorf("expected .field as argument to exists()")
 	}
 	p.advance()
-	if p.curr.Type != TokenIdent {
-		return nil, fmt.Errorf("expected field name after '.'")
+	field, err := p.parseFieldPath()
+	if err != nil {
+		return nil, err
 	}
-	field := p.curr.Value
-	p.advance()
 	if err := p.expect(TokenRParen); err != nil {
 		return nil, err
 	}
@@ -381,12 +554,23 @@ func (p *Parser) parseExistsPredicate(negated bool) (Predicate, error) {
 }
 
 func (p *Parser) parseContentFuncPredicate(negated bool) (Predicate, error) {
-	// content("search terms")
+	// content("search terms") or content(title:"search terms")
 	if err := p.expect(TokenLParen); err != nil {
 		return nil, err
 	}
+	scope := ContentScopeBody
+	if p.curr.Type == TokenIdent && p.peek.Type == TokenColon {
+		switch ContentScope(strings.ToLower(p.curr.Value)) {
+		case ContentScopeTitle, ContentScopeHeading, ContentScopeCode, ContentScopeField:
+			scope = ContentScope(strings.ToLower(p.curr.Value))
+		default:
+			return nil, fmt.Errorf("unknown content() scope '%s': use title:, heading:, code:, or field:", p.curr.Value)
+		}
+		p.advance()
+		p.advance()
+	}
 	if p.curr.Type != TokenString {
-		return nil, fmt.Errorf(`content() requires a quoted string, e.g. content("search term")`)
+		return nil, fmt.Errorf(`content() requires a quoted string, e.g. content("search term") or content(heading:"retro")`)
 	}
 	term := p.curr.Value
 	p.advance()
@@ -396,9 +580,132 @@ func (p *Parser) parseContentFuncPredicate(negated bool) (Predicate, error) {
 	return &ContentPredicate{
 		basePredicate: basePredicate{negated: negated},
 		SearchTerm:    term,
+		Scope:         scope,
 	}, nil
 }
 
+func (p *Parser) parseLifecycleFuncPredicate(negated bool) (Predicate, error) {
+	// lifecycle(active), lifecycle(draft, active)
+	if err := p.expect(TokenLParen); err != nil {
+		return nil, err
+	}
+	var states []string
+	for {
+		if p.curr.Type != TokenIdent && p.curr.Type != TokenString {
+			return nil, fmt.Errorf("lifecycle() expects one or more states, e.g. lifecycle(active)")
+		}
+		states = append(states, p.curr.Value)
+		p.advance()
+		if p.curr.Type == TokenComma {
+			p.advance()
+			continue
+		}
+		if err := p.expect(TokenRParen); err != nil {
+			return nil, err
+		}
+		break
+	}
+	return &LifecyclePredicate{basePredicate: basePredicate{negated: negated}, States: states}, nil
+}
+
+func (p *Parser) parseTimestampFuncPredicate(negated bool, kind TimestampKind) (Predicate, error) {
+	// modified(within:7d), created(before:2026-01-01, after:2025-06-30)
+	if err := p.expect(TokenLParen); err != nil {
+		return nil, err
+	}
+	usage := fmt.Sprintf("use %s(within:7d), %s(before:2026-01-01), or %s(after:yesterday)", kind, kind, kind)
+
+	var bounds []TimeBound
+	for {
+		if p.curr.Type != TokenIdent {
+			return nil, fmt.Errorf("%s() expects within:, before:, or after: bounds; %s", kind, usage)
+		}
+		op := strings.ToLower(p.curr.Value)
+		p.advance()
+		if err := p.expect(TokenColon); err != nil {
+			return nil, fmt.Errorf("%s() expects %s:<value>; %s", kind, op, usage)
+		}
+		if p.curr.Type != TokenIdent && p.curr.Type != TokenString {
+			return nil, fmt.Errorf("%s() expects a value after %s:; %s", kind, op, usage)
+		}
+		bound, err := parseTimeBound(op, p.curr.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s(): %w", kind, err)
+		}
+		p.advance()
+		bounds = append(bounds, bound)
+
+		if p.curr.Type == TokenComma {
+			p.advance()
+			continue
+		}
+		if err := p.expect(TokenRParen); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	return &TimestampPredicate{
+		basePredicate: basePredicate{negated: negated},
+		Kind:          kind,
+		Bounds:        bounds,
+	}, nil
+}
+
+func (p *Parser) parseAttachmentFuncPredicate(negated bool) (Predicate, error) {
+	// attachment(type:pdf), attachment(type:image, min_size:5MB, max_size:50MB)
+	if err := p.expect(TokenLParen); err != nil {
+		return nil, err
+	}
+	usage := "use attachment(type:pdf), attachment(type:image), or attachment(min_size:5MB); use has_attachment() to match any attachment"
+
+	pred := &AttachmentPredicate{basePredicate: basePredicate{negated: negated}}
+	for {
+		if p.curr.Type != TokenIdent {
+			return nil, fmt.Errorf("attachment() expects type:, min_size:, or max_size: filters; %s", usage)
+		}
+		op := strings.ToLower(p.curr.Value)
+		p.advance()
+		if err := p.expect(TokenColon); err != nil {
+			return nil, fmt.Errorf("attachment() expects %s:<value>; %s", op, usage)
+		}
+		if p.curr.Type != TokenIdent && p.curr.Type != TokenString {
+			return nil, fmt.Errorf("attachment() expects a value after %s:; %s", op, usage)
+		}
+		value := strings.TrimSpace(p.curr.Value)
+		switch op {
+		case "type":
+			pred.AssetType = strings.ToLower(strings.TrimPrefix(value, "."))
+			if pred.AssetType == "" {
+				return nil, fmt.Errorf("attachment() expects a value after type:; %s", usage)
+			}
+		case "min_size", "max_size":
+			size, err := parseAttachmentSize(value)
+			if err != nil {
+				return nil, fmt.Errorf("attachment(): %w", err)
+			}
+			if op == "min_size" {
+				pred.MinSize = size
+			} else {
+				pred.MaxSize = size
+			}
+		default:
+			return nil, fmt.Errorf("unknown attachment() filter %q; %s", op, usage)
+		}
+		p.advance()
+
+		if p.curr.Type == TokenComma {
+			p.advance()
+			continue
+		}
+		if err := p.expect(TokenRParen); err != nil {
+			return nil, err
+		}
+		break
+	}
+	return pred, nil
+}
+
 func (p *Parser) parseHasFuncPredicate(negated bool) (Predicate, error) {
 	// has(section ...) or has(trait:...)
 	subq, err := p.parseAnyQueryArg("section or trait")
@@ -594,6 +901,74 @@ func (p *Parser) parseRefdFuncPredicate(negated bool) (Predicate, error) {
 	return &RefdPredicate{basePredicate: basePredicate{negated: negated}, SubQuery: subq}, nil
 }
 
+// parseTransitiveRefFuncPredicate parses refs*(...) and refd*(...): a target
+// or type subquery, optionally followed by a hop limit (depth<=N or depth<N).
+func (p *Parser) parseTransitiveRefFuncPredicate(negated bool, kind string) (Predicate, error) {
+	if err := p.expect(TokenLParen); err != nil {
+		return nil, err
+	}
+	usage := fmt.Sprintf("use %s*([[target]]), %s*(type:<name> ...), or add a hop limit like %s*([[target]], depth<=3)", kind, kind, kind)
+
+	var target string
+	var subq *Query
+	switch {
+	case p.curr.Type == TokenRef:
+		target = p.curr.Value
+		p.advance()
+	case p.curr.Type == TokenUnderscore:
+		return nil, unsupportedSelfReferenceError()
+	case p.curr.Type == TokenIdent && (strings.ToLower(p.curr.Value) != "type" || p.peek.Type != TokenColon):
+		if strings.ToLower(p.curr.Value) == "trait" || strings.ToLower(p.curr.Value) == "section" {
+			return nil, fmt.Errorf("%s*() subquery must be a type query; %s", kind, usage)
+		}
+		target = p.curr.Value
+		p.advance()
+	case p.curr.Type == TokenIdent:
+		var err error
+		subq, err = p.parseQuery()
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("expected target or type subquery in %s*(); %s", kind, usage)
+	}
+
+	maxDepth := 0
+	if p.curr.Type == TokenComma {
+		p.advance()
+		if !p.atKeyword("depth") {
+			return nil, fmt.Errorf("%s*() only accepts a depth limit after the target; %s", kind, usage)
+		}
+		p.advance()
+		op := p.curr.Type
+		if op != TokenLte && op != TokenLt {
+			return nil, fmt.Errorf("%s*() depth limit must use <= or <; %s", kind, usage)
+		}
+		p.advance()
+		n, err := strconv.Atoi(p.curr.Value)
+		if p.curr.Type != TokenIdent || err != nil {
+			return nil, fmt.Errorf("%s*() depth must be a whole number; %s", kind, usage)
+		}
+		p.advance()
+		if op == TokenLt {
+			n--
+		}
+		if n < 1 {
+			return nil, fmt.Errorf("%s*() depth must allow at least one hop", kind)
+		}
+		maxDepth = n
+	}
+	if err := p.expect(TokenRParen); err != nil {
+		return nil, err
+	}
+
+	base := basePredicate{negated: negated}
+	if kind == "refd" {
+		return &RefdPredicate{basePredicate: base, Target: target, SubQuery: subq, Transitive: true, MaxDepth: maxDepth}, nil
+	}
+	return &RefsPredicate{basePredicate: base, Target: target, SubQuery: subq, Transitive: true, MaxDepth: maxDepth}, nil
+}
+
 func (p *Parser) parseAtFuncPredicate(negated bool) (Predicate, error) {
 	// at(trait:...)
 	subq, err := p.parseQueryArg(QueryTypeTrait, "trait")
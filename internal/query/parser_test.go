@@ -1,6 +1,7 @@
 package query
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -755,14 +756,79 @@ func TestParseRefsPredicate(t *testing.T) {
 	}
 }
 
+func TestParseTransitiveRefPredicates(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		input     string
+		wantRefd  bool
+		wantDepth int
+		wantSubQ  bool
+		wantErr   bool
+	}{
+		{name: "refs* target", input: "type:note refs*([[projects/raven]])"},
+		{name: "refs* depth", input: "type:note refs*([[projects/raven]], depth<=3)", wantDepth: 3},
+		{name: "refs* strict depth", input: "type:note refs*(raven, depth<3)", wantDepth: 2},
+		{name: "refs* subquery with depth", input: "type:note refs*(type:project .status==active, depth<=2)", wantDepth: 2, wantSubQ: true},
+		{name: "refd* target", input: "type:note refd*([[projects/raven]], depth<=1)", wantRefd: true, wantDepth: 1},
+		{name: "refd* subquery", input: "type:note !refd*(type:project)", wantRefd: true, wantSubQ: true},
+		{name: "zero depth", input: "type:note refs*([[projects/raven]], depth<1)", wantErr: true},
+		{name: "unsupported depth operator", input: "type:note refs*([[projects/raven]], depth>=2)", wantErr: true},
+		{name: "unknown argument", input: "type:note refs*([[projects/raven]], hops<=2)", wantErr: true},
+		{name: "trait subquery", input: "type:note refd*(trait:due)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var transitive, hasSubQ bool
+			var depth int
+			switch p := q.Predicate.(type) {
+			case *RefsPredicate:
+				if tt.wantRefd {
+					t.Fatalf("expected RefdPredicate, got %T", q.Predicate)
+				}
+				transitive, depth, hasSubQ = p.Transitive, p.MaxDepth, p.SubQuery != nil
+			case *RefdPredicate:
+				if !tt.wantRefd {
+					t.Fatalf("expected RefsPredicate, got %T", q.Predicate)
+				}
+				transitive, depth, hasSubQ = p.Transitive, p.MaxDepth, p.SubQuery != nil
+			default:
+				t.Fatalf("unexpected predicate %T", q.Predicate)
+			}
+			if !transitive {
+				t.Error("expected Transitive")
+			}
+			if depth != tt.wantDepth {
+				t.Errorf("MaxDepth = %d, want %d", depth, tt.wantDepth)
+			}
+			if hasSubQ != tt.wantSubQ {
+				t.Errorf("SubQuery present = %v, want %v", hasSubQ, tt.wantSubQ)
+			}
+		})
+	}
+}
+
 func TestParseContentPredicate(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
-		name     string
-		input    string
-		wantTerm string
-		wantNeg  bool
-		wantErr  bool
+		name      string
+		input     string
+		wantTerm  string
+		wantScope ContentScope
+		wantNeg   bool
+		wantErr   bool
 	}{
 		{
 			name:     "simple content search",
@@ -780,6 +846,30 @@ func TestParseContentPredicate(t *testing.T) {
 			wantTerm: "contractor",
 			wantNeg:  true,
 		},
+		{
+			name:      "title scope",
+			input:     `type:project content(title:"raven")`,
+			wantTerm:  "raven",
+			wantScope: ContentScopeTitle,
+		},
+		{
+			name:      "heading scope",
+			input:     `type:meeting content(heading:"retro")`,
+			wantTerm:  "retro",
+			wantScope: ContentScopeHeading,
+		},
+		{
+			name:      "code scope",
+			input:     `type:note !content(code:"SELECT")`,
+			wantTerm:  "SELECT",
+			wantScope: ContentScopeCode,
+			wantNeg:   true,
+		},
+		{
+			name:    "unknown scope",
+			input:   `type:note content(body:"x")`,
+			wantErr: true,
+		},
 		{
 			name:    "content without quotes",
 			input:   `type:person content(colleague)`,
@@ -809,6 +899,9 @@ func TestParseContentPredicate(t *testing.T) {
 			if cp.SearchTerm != tt.wantTerm {
 				t.Errorf("SearchTerm = %q, want %q", cp.SearchTerm, tt.wantTerm)
 			}
+			if cp.Scope != tt.wantScope {
+				t.Errorf("Scope = %q, want %q", cp.Scope, tt.wantScope)
+			}
 			if cp.Negated() != tt.wantNeg {
 				t.Errorf("Negated() = %v, want %v", cp.Negated(), tt.wantNeg)
 			}
@@ -1583,3 +1676,67 @@ func TestParseBooleanEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestParseBooleanKeywords(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		keywords string
+		symbolic string
+	}{
+		{
+			keywords: "type:project .status==active OR .status==paused",
+			symbolic: "type:project .status==active | .status==paused",
+		},
+		{
+			keywords: "type:project .status==active AND has(trait:due)",
+			symbolic: "type:project .status==active has(trait:due)",
+		},
+		{
+			keywords: "type:project NOT .archived==true",
+			symbolic: "type:project !.archived==true",
+		},
+		{
+			keywords: "type:project (.status==active OR .status==paused) AND NOT refs([[projects/raven]])",
+			symbolic: "type:project (.status==active | .status==paused) !refs([[projects/raven]])",
+		},
+		{
+			keywords: "type:project NOT (.status==active OR .priority==low) OR has(trait:due)",
+			symbolic: "type:project !(.status==active | .priority==low) | has(trait:due)",
+		},
+		{
+			keywords: "type:meeting refs(type:project (.status==active OR .status==paused) AND NOT .archived==true)",
+			symbolic: "type:meeting refs(type:project (.status==active | .status==paused) !.archived==true)",
+		},
+		{
+			keywords: "type:project any(.tags, _ == \"a\" OR NOT _ == \"b\")",
+			symbolic: "type:project any(.tags, _ == \"a\" | !_ == \"b\")",
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.keywords)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.keywords, err)
+		}
+		want, err := Parse(tt.symbolic)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.symbolic, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Parse(%q) = %#v, want %#v", tt.keywords, got.Predicate, want.Predicate)
+		}
+	}
+
+	for _, input := range []string{
+		"type:project AND .status==active",
+		"type:project .status==active AND",
+		"type:project .status==active AND AND .priority==high",
+		"type:project .status==active OR",
+		"type:project (.status==active AND) .priority==high",
+		"type:project NOT",
+	} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", input)
+		}
+	}
+}
@@ -21,26 +21,10 @@ func appendLimitOffset(sqlStr string, args []interface{}, limit, offset int) (st
 }
 
 func (e *Executor) buildObjectWhereClause(q *Query) (string, []interface{}, error) {
-	var conditions []string
-	var args []interface{}
-
-	conditions = append(conditions, "o.type = ?")
-	args = append(args, q.TypeName)
-
 	if err := e.prepareRefFieldAmbiguityChecks(q); err != nil {
 		return "", nil, err
 	}
-
-	if q.Predicate != nil {
-		cond, predArgs, err := e.buildObjectPredicateSQL(q.Predicate, "o", q.TypeName)
-		if err != nil {
-			return "", nil, err
-		}
-		conditions = append(conditions, cond)
-		args = append(args, predArgs...)
-	}
-
-	return strings.Join(conditions, " AND "), args, nil
+	return e.buildObjectWhereForAlias(q, "o")
 }
 
 func (e *Executor) buildTraitWhereClause(q *Query) (string, []interface{}, error) {
@@ -149,7 +133,7 @@ func (e *Executor) buildTraitPageSQL(q *Query, limit, offset int) (string, []int
 		return "", nil, err
 	}
 	sqlStr := fmt.Sprintf(`
-		SELECT t.id, t.trait_type, t.value, t.content, t.file_path, t.line_number, t.parent_object_id
+		SELECT t.id, t.trait_type, t.value, t.raw_value, t.content, t.file_path, t.line_number, t.parent_object_id
 		FROM traits t
 		WHERE %s
 		ORDER BY t.file_path, t.line_number
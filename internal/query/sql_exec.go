@@ -26,7 +26,7 @@ func scanObjectRows(rows *sql.Rows) ([]model.Object, error) {
 func scanTraitRows(rows *sql.Rows) ([]model.Trait, error) {
 	return sqlutil.ScanRows(rows, func(rows *sql.Rows) (model.Trait, error) {
 		var r model.Trait
-		if err := rows.Scan(&r.ID, &r.TraitType, &r.Value, &r.Content, &r.FilePath, &r.Line, &r.ParentObjectID); err != nil {
+		if err := rows.Scan(&r.ID, &r.TraitType, &r.Value, &r.RawValue, &r.Content, &r.FilePath, &r.Line, &r.ParentObjectID); err != nil {
 			return model.Trait{}, err
 		}
 		return r, nil
@@ -84,11 +84,19 @@ func scanIDRows(rows *sql.Rows) ([]string, error) {
 }
 
 func (e *Executor) executeCountQuery(sqlStr string, args []interface{}) (int, error) {
-	var count int
-	if err := e.db.QueryRow(sqlStr, args...).Scan(&count); err != nil {
+	rows, err := e.queryRows(sqlStr, args)
+	if err != nil {
 		return 0, err
 	}
-	return count, nil
+	defer rows.Close()
+
+	var count int
+	if rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			return 0, err
+		}
+	}
+	return count, rows.Err()
 }
 
 // executeObjectQuery executes a type query and returns matching objects.
@@ -107,7 +115,7 @@ func (e *Executor) executeObjectPageQuery(q *Query, limit, offset int) ([]model.
 		return nil, err
 	}
 
-	rows, err := e.db.Query(sqlStr, args...)
+	rows, err := e.queryRows(sqlStr, args)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w (SQL: %s)", err, sqlStr)
 	}
@@ -124,7 +132,7 @@ func (e *Executor) executeObjectIDQuery(q *Query, limit, offset int) ([]string,
 		return nil, err
 	}
 
-	rows, err := e.db.Query(sqlStr, args...)
+	rows, err := e.queryRows(sqlStr, args)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w (SQL: %s)", err, sqlStr)
 	}
@@ -164,7 +172,7 @@ func (e *Executor) executeTraitPageQuery(q *Query, limit, offset int) ([]model.T
 		return nil, err
 	}
 
-	rows, err := e.db.Query(sqlStr, args...)
+	rows, err := e.queryRows(sqlStr, args)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w (SQL: %s)", err, sqlStr)
 	}
@@ -182,7 +190,7 @@ func (e *Executor) executeTraitIDQuery(q *Query, limit, offset int) ([]string, e
 		return nil, err
 	}
 
-	rows, err := e.db.Query(sqlStr, args...)
+	rows, err := e.queryRows(sqlStr, args)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w (SQL: %s)", err, sqlStr)
 	}
@@ -223,7 +231,7 @@ func (e *Executor) executeAssetPageQuery(q *Query, limit, offset int) ([]model.A
 		return nil, err
 	}
 
-	rows, err := e.db.Query(sqlStr, args...)
+	rows, err := e.queryRows(sqlStr, args)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w (SQL: %s)", err, sqlStr)
 	}
@@ -240,7 +248,7 @@ func (e *Executor) executeAssetIDQuery(q *Query, limit, offset int) ([]string, e
 		return nil, err
 	}
 
-	rows, err := e.db.Query(sqlStr, args...)
+	rows, err := e.queryRows(sqlStr, args)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w (SQL: %s)", err, sqlStr)
 	}
@@ -278,7 +286,7 @@ func (e *Executor) executeSectionPageQuery(q *Query, limit, offset int) ([]model
 		return nil, err
 	}
 
-	rows, err := e.db.Query(sqlStr, args...)
+	rows, err := e.queryRows(sqlStr, args)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w (SQL: %s)", err, sqlStr)
 	}
@@ -295,7 +303,7 @@ func (e *Executor) executeSectionIDQuery(q *Query, limit, offset int) ([]string,
 		return nil, err
 	}
 
-	rows, err := e.db.Query(sqlStr, args...)
+	rows, err := e.queryRows(sqlStr, args)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w (SQL: %s)", err, sqlStr)
 	}
@@ -26,12 +26,18 @@ func (e *Executor) buildPredicateSQL(kind predicateKind, pred Predicate, alias,
 	case *GroupPredicate:
 		return e.buildGroupPredicateSQL(p, alias, recurse)
 	case *RefdPredicate:
+		if p.Transitive && (kind == predicateKindAsset || kind == predicateKindTrait) {
+			return "", nil, fmt.Errorf("refd*() predicate is only supported for type and section queries")
+		}
 		if kind == predicateKindAsset {
 			return e.buildAssetRefdPredicateSQL(p, alias)
 		}
 		if kind == predicateKindTrait {
 			return "", nil, fmt.Errorf("refd() predicate is only supported for type queries")
 		}
+		if p.Transitive {
+			return e.buildTransitiveRefdPredicateSQL(p, alias)
+		}
 		return e.buildRefdPredicateSQL(p, alias, false)
 	case *ContentPredicate:
 		if kind == predicateKindAsset {
@@ -49,8 +55,14 @@ func (e *Executor) buildPredicateSQL(kind predicateKind, pred Predicate, alias,
 			return "", nil, fmt.Errorf("refs() predicate is not valid for asset queries")
 		}
 		if kind == predicateKindTrait {
+			if p.Transitive {
+				return "", nil, fmt.Errorf("refs*() predicate is only supported for type and section queries")
+			}
 			return e.buildTraitRefsPredicateSQL(p, alias)
 		}
+		if p.Transitive {
+			return e.buildTransitiveRefsPredicateSQL(p, alias)
+		}
 		if kind == predicateKindSection {
 			return e.buildRefsPredicateSQL(p, alias)
 		}
@@ -65,8 +77,32 @@ func (e *Executor) buildPredicateSQL(kind predicateKind, pred Predicate, alias,
 		if kind == predicateKindSection {
 			return e.buildSectionStringFuncPredicateSQL(p, alias)
 		}
+		if typeDef := e.typeDefinition(typeName); isDisplayNameField(typeDef, p.Field) {
+			return e.buildDisplayNameStringFuncPredicateSQL(p, alias, typeDef)
+		}
 		return e.buildStringFuncPredicateSQL(p, alias)
 
+	case *TimestampPredicate:
+		return e.buildTimestampPredicateSQL(p, alias, kind)
+
+	case *ExpiredPredicate:
+		return e.buildExpiredPredicateSQL(p, alias, kind)
+
+	case *LifecyclePredicate:
+		return e.buildLifecyclePredicateSQL(p, alias, kind)
+
+	case *FocusPredicate:
+		return e.buildFocusPredicateSQL(p, alias, kind)
+
+	case *AttachmentPredicate:
+		if kind == predicateKindAsset {
+			return "", nil, fmt.Errorf("attachment predicates are not valid for asset queries")
+		}
+		if kind == predicateKindTrait {
+			return "", nil, fmt.Errorf("attachment predicates are only supported for type and section queries")
+		}
+		return e.buildAttachmentPredicateSQL(p, alias)
+
 	// Object-only predicate nodes (except .value is allowed for traits).
 	case *FieldPredicate:
 		if kind == predicateKindAsset {
@@ -89,7 +89,7 @@ func (e *Executor) buildAssetStringFuncPredicateSQL(p *StringFuncPredicate, alia
 		return "", nil, fmt.Errorf("string function predicates are not valid for asset field '.%s'", p.Field)
 	}
 
-	cond, args, err := buildStringFuncCondition(p.FuncType, column, p.Value, p.CaseSensitive)
+	cond, args, err := e.stringFuncCondition(p.FuncType, column, p.Value, p.CaseSensitive)
 	if err != nil {
 		return "", nil, err
 	}
@@ -141,19 +141,9 @@ func (e *Executor) buildAssetRefdPredicateSQL(p *RefdPredicate, alias string) (s
 }
 
 func (e *Executor) buildAssetRefdObjectSubquerySQL(p *RefdPredicate, alias string) (string, []interface{}, error) {
-	var sourceConditions []string
-	var args []interface{}
-
-	sourceConditions = append(sourceConditions, "src.type = ?")
-	args = append(args, p.SubQuery.TypeName)
-
-	if p.SubQuery.Predicate != nil {
-		cond, predArgs, err := e.buildObjectPredicateSQL(p.SubQuery.Predicate, "src", p.SubQuery.TypeName)
-		if err != nil {
-			return "", nil, err
-		}
-		sourceConditions = append(sourceConditions, cond)
-		args = append(args, predArgs...)
+	sourceCond, args, err := e.buildObjectWhereForAlias(p.SubQuery, "src")
+	if err != nil {
+		return "", nil, err
 	}
 
 	cond := fmt.Sprintf(`EXISTS (
@@ -161,7 +151,7 @@ func (e *Executor) buildAssetRefdObjectSubquerySQL(p *RefdPredicate, alias strin
 		JOIN objects src ON (r.source_id = src.id OR r.source_id LIKE src.id || '#%%')
 		WHERE (r.target_id = %[1]s.id OR r.target_raw = %[1]s.id)
 		  AND %[2]s
-	)`, alias, strings.Join(sourceConditions, " AND "))
+	)`, alias, sourceCond)
 
 	return cond, args, nil
 }
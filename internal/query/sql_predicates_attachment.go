@@ -0,0 +1,43 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// buildAttachmentPredicateSQL builds SQL for has_attachment() and
+// attachment(...): the object or section links to at least one indexed asset
+// matching the filters. Links from nested sections count toward their object.
+func (e *Executor) buildAttachmentPredicateSQL(p *AttachmentPredicate, alias string) (string, []interface{}, error) {
+	conds := []string{fmt.Sprintf("(r.source_id = %[1]s.id OR r.source_id LIKE %[1]s.id || '#%%')", alias)}
+	var args []interface{}
+
+	if p.AssetType != "" {
+		if attachmentMediaFamilies[p.AssetType] {
+			conds = append(conds, "LOWER(a.media_type) LIKE ?")
+			args = append(args, p.AssetType+"/%")
+		} else {
+			conds = append(conds, "LOWER(a.extension) = ?")
+			args = append(args, p.AssetType)
+		}
+	}
+	if p.MinSize > 0 {
+		conds = append(conds, "a.size_bytes >= ?")
+		args = append(args, p.MinSize)
+	}
+	if p.MaxSize > 0 {
+		conds = append(conds, "a.size_bytes <= ?")
+		args = append(args, p.MaxSize)
+	}
+
+	cond := fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM refs r
+		JOIN assets a ON (r.target_id = a.id OR r.target_raw = a.id)
+		WHERE %s
+	)`, strings.Join(conds, " AND "))
+
+	if p.Negated() {
+		cond = "NOT " + cond
+	}
+	return cond, args, nil
+}
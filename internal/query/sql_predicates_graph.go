@@ -0,0 +1,123 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// refFileObjectSQL maps a ref endpoint ID to its file-level object ID, or NULL
+// when the ID is already file-level. Section and embedded object IDs are
+// "<file object>#<slug>".
+func refFileObjectSQL(expr string) string {
+	return fmt.Sprintf("CASE WHEN instr(%s, '#') > 0 THEN substr(%s, 1, instr(%s, '#') - 1) END", expr, expr, expr)
+}
+
+// reachCTE builds a recursive CTE named reach holding the node IDs found by
+// seedSelect/seedFrom and then repeatedly by stepSelect/stepFrom, where stepFrom
+// joins the previous level as reach. A positive maxDepth bounds the number of
+// hops; without it, UNION dedupes nodes so cycles terminate.
+func reachCTE(seedSelect, seedFrom, stepSelect, stepFrom string, maxDepth int) string {
+	if maxDepth <= 0 {
+		return fmt.Sprintf(`WITH RECURSIVE reach(id) AS (
+			SELECT %s %s
+			UNION
+			SELECT %s %s
+		)`, seedSelect, seedFrom, stepSelect, stepFrom)
+	}
+	return fmt.Sprintf(`WITH RECURSIVE reach(id, depth) AS (
+			SELECT %s, 1 %s
+			UNION
+			SELECT %s, reach.depth + 1 %s
+			WHERE reach.depth < %d
+		)`, seedSelect, seedFrom, stepSelect, stepFrom, maxDepth)
+}
+
+// buildTransitiveRefsPredicateSQL builds SQL for refs*(...): results that reach
+// the target through a chain of references, at most MaxDepth hops long. As with
+// refs(), references from a note's sections and embedded objects count as
+// references from the note.
+func (e *Executor) buildTransitiveRefsPredicateSQL(p *RefsPredicate, alias string) (string, []interface{}, error) {
+	var seedFrom string
+	var args []interface{}
+	switch {
+	case p.Target != "":
+		targetCond, targetArgs, err := e.refTargetCondition("r", p.Target)
+		if err != nil {
+			return "", nil, err
+		}
+		seedFrom = "FROM refs r WHERE " + targetCond
+		args = targetArgs
+	case p.SubQuery != nil && p.SubQuery.Type == QueryTypeObject:
+		targetCond, subArgs, err := e.buildObjectWhereForAlias(p.SubQuery, "target_obj")
+		if err != nil {
+			return "", nil, err
+		}
+		seedFrom = fmt.Sprintf(`FROM refs r
+			JOIN objects target_obj ON (
+				r.target_id = target_obj.id OR
+				(r.target_id IS NULL AND r.target_raw = target_obj.id)
+			)
+			WHERE %s`, targetCond)
+		args = subArgs
+	default:
+		return "", nil, fmt.Errorf("refs*() needs a target or type subquery")
+	}
+
+	// A reference to a note reaches whatever the note's sections reach.
+	stepFrom := fmt.Sprintf(`FROM reach
+			JOIN refs r ON COALESCE(r.target_id, r.target_raw) IN (reach.id, %s)`, refFileObjectSQL("reach.id"))
+	cte := reachCTE("r.source_id", seedFrom, "r.source_id", stepFrom, p.MaxDepth)
+
+	cond := fmt.Sprintf(`%s.id IN (
+		%s
+		SELECT id FROM reach
+		UNION
+		SELECT %s FROM reach WHERE instr(id, '#') > 0
+	)`, alias, cte, refFileObjectSQL("id"))
+	if p.Negated() {
+		cond = "NOT " + cond
+	}
+	return cond, args, nil
+}
+
+// buildTransitiveRefdPredicateSQL builds SQL for refd*(...): results reachable
+// from the source through a chain of references, at most MaxDepth hops long.
+// References from a note's sections and embedded objects count as references
+// from the note.
+func (e *Executor) buildTransitiveRefdPredicateSQL(p *RefdPredicate, alias string) (string, []interface{}, error) {
+	var seedFrom string
+	var args []interface{}
+	switch {
+	case p.Target != "" && !strings.HasPrefix(p.Target, "__trait_line:"):
+		sourceID, err := e.resolveTarget(p.Target)
+		if err != nil {
+			return "", nil, err
+		}
+		seedFrom = `FROM refs r WHERE r.source_id = ? OR r.source_id LIKE ? ESCAPE '\'`
+		args = []interface{}{sourceID, escapeLikePattern(sourceID) + "#%"}
+	case p.SubQuery != nil && p.SubQuery.Type == QueryTypeObject:
+		sourceCond, subArgs, err := e.buildObjectWhereForAlias(p.SubQuery, "src")
+		if err != nil {
+			return "", nil, err
+		}
+		seedFrom = fmt.Sprintf(`FROM refs r
+			JOIN objects src ON (r.source_id = src.id OR r.source_id LIKE src.id || '#%%')
+			WHERE %s`, sourceCond)
+		args = subArgs
+	default:
+		return "", nil, fmt.Errorf("refd*() needs a source or type subquery")
+	}
+
+	stepFrom := `FROM reach
+			JOIN refs r ON (r.source_id = reach.id OR r.source_id LIKE reach.id || '#%')`
+	cte := reachCTE("COALESCE(r.target_id, r.target_raw)", seedFrom, "COALESCE(r.target_id, r.target_raw)", stepFrom, p.MaxDepth)
+
+	cond := fmt.Sprintf(`%s.id IN (
+		%s
+		SELECT id FROM reach
+	)`, alias, cte)
+	if p.Negated() {
+		cond = "NOT " + cond
+	}
+	return cond, args, nil
+}
@@ -19,6 +19,9 @@ func (e *Executor) buildFieldPredicateSQL(p *FieldPredicate, alias, typeName str
 	if isDateVirtualField(typeName, p.Field) {
 		return e.buildDateVirtualFieldPredicateSQL(p, alias)
 	}
+	if typeDef := e.typeDefinition(typeName); isDisplayNameField(typeDef, p.Field) {
+		return e.buildDisplayNameFieldPredicateSQL(p, alias, typeDef)
+	}
 
 	if p.IsExists {
 		cond, args := fieldExistsCond(alias, jsonPath, p.CompareOp == CompareNeq)
@@ -206,7 +209,7 @@ func (e *Executor) isRefField(typeName, fieldName string) bool {
 	if typeDef == nil {
 		return false
 	}
-	fieldDef := typeDef.Fields[fieldName]
+	fieldDef := typeDef.FieldAtPath(fieldName)
 	if fieldDef == nil {
 		return false
 	}
@@ -221,7 +224,7 @@ func (e *Executor) isRefArrayField(typeName, fieldName string) bool {
 	if typeDef == nil {
 		return false
 	}
-	fieldDef := typeDef.Fields[fieldName]
+	fieldDef := typeDef.FieldAtPath(fieldName)
 	if fieldDef == nil {
 		return false
 	}
@@ -239,7 +242,7 @@ func (e *Executor) normalizeBoolFieldComparisonValue(typeName, fieldName, value
 	if typeDef == nil {
 		return value
 	}
-	fieldDef := typeDef.Fields[fieldName]
+	fieldDef := typeDef.FieldAtPath(fieldName)
 	if fieldDef == nil {
 		return value
 	}
@@ -265,7 +268,7 @@ func (e *Executor) fieldEqualityMode(typeName, fieldName string) fieldEqualityMo
 	if typeDef == nil {
 		return fieldEqualityModeFallback
 	}
-	fieldDef := typeDef.Fields[fieldName]
+	fieldDef := typeDef.FieldAtPath(fieldName)
 	if fieldDef == nil {
 		return fieldEqualityModeFallback
 	}
@@ -319,6 +322,11 @@ func (e *Executor) collectRefFieldAmbiguityKeys(q *Query, keys map[fieldRefAmbig
 	if q == nil || q.Predicate == nil {
 		return nil
 	}
+	for _, name := range q.TypeNames {
+		if err := e.collectRefFieldAmbiguityPredicate(q.Type, name, q.Predicate, keys); err != nil {
+			return err
+		}
+	}
 	return e.collectRefFieldAmbiguityPredicate(q.Type, q.TypeName, q.Predicate, keys)
 }
 
@@ -654,7 +662,7 @@ func (e *Executor) buildStringFuncPredicateSQL(p *StringFuncPredicate, alias str
 	jsonPath := jsonFieldPath(p.Field)
 	fieldExpr := fmt.Sprintf("json_extract(%s.fields, ?)", alias)
 
-	cond, funcArgs, err := buildStringFuncCondition(p.FuncType, fieldExpr, p.Value, p.CaseSensitive)
+	cond, funcArgs, err := e.stringFuncCondition(p.FuncType, fieldExpr, p.Value, p.CaseSensitive)
 	if err != nil {
 		return "", nil, err
 	}
@@ -819,7 +827,7 @@ func (e *Executor) buildElementEqualitySQL(p *ElementEqualityPredicate) (string,
 
 // buildElementStringFuncSQL builds SQL for string functions on array elements.
 func (e *Executor) buildElementStringFuncSQL(p *StringFuncPredicate) (string, []interface{}, error) {
-	cond, args, err := buildStringFuncCondition(p.FuncType, "json_each.value", p.Value, p.CaseSensitive)
+	cond, args, err := e.stringFuncCondition(p.FuncType, "json_each.value", p.Value, p.CaseSensitive)
 	if err != nil {
 		return "", nil, err
 	}
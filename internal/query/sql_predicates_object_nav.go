@@ -153,11 +153,11 @@ func (e *Executor) buildScopeMatchPredicate(target string, subQuery *Query, cand
 
 func (e *Executor) scopeMatcherCondition(target string, subQuery *Query, alias string) (string, []interface{}, error) {
 	if target != "" {
-		resolvedTarget, err := e.resolveTarget(target)
+		idExpr, args, err := e.targetIDExpr(target)
 		if err != nil {
 			return "", nil, err
 		}
-		return fmt.Sprintf("%s.id = ?", alias), []interface{}{resolvedTarget}, nil
+		return fmt.Sprintf("%s.id = %s", alias, idExpr), args, nil
 	}
 	if subQuery == nil {
 		return "", nil, fmt.Errorf("scope predicate requires a target or subquery")
@@ -188,6 +188,28 @@ func (e *Executor) scopeMatcherCondition(target string, subQuery *Query, alias s
 }
 
 func (e *Executor) buildObjectWhereForAlias(q *Query, alias string) (string, []interface{}, error) {
+	if q.TypeName == AnyType {
+		if q.Predicate == nil {
+			return "1=1", nil, nil
+		}
+		return e.buildObjectPredicateSQL(q.Predicate, alias, AnyType)
+	}
+	if len(q.TypeNames) > 0 {
+		// Each member gets its own branch so type-specific field handling
+		// (ref fields, display names, booleans) follows that type's schema.
+		branches := make([]string, 0, len(q.TypeNames))
+		var args []interface{}
+		for _, name := range q.TypeNames {
+			cond, branchArgs, err := e.buildObjectWhereForAlias(&Query{Type: QueryTypeObject, TypeName: name, Predicate: q.Predicate}, alias)
+			if err != nil {
+				return "", nil, err
+			}
+			branches = append(branches, "("+cond+")")
+			args = append(args, branchArgs...)
+		}
+		return "(" + strings.Join(branches, " OR ") + ")", args, nil
+	}
+
 	conditions := []string{fmt.Sprintf("%s.type = ?", alias)}
 	args := []interface{}{q.TypeName}
 	if q.Predicate != nil {
@@ -262,11 +284,10 @@ func (e *Executor) buildRefsPredicateSQL(p *RefsPredicate, alias string) (string
 	if p.Target != "" {
 		// Direct reference to specific target
 		// Resolve the target to its canonical object ID (like backlinks does)
-		resolvedTarget, err := e.resolveTarget(p.Target)
+		targetCond, targetArgs, err := e.refTargetCondition("r", p.Target)
 		if err != nil {
 			return "", nil, err
 		}
-		targetCond, targetArgs := buildRefTargetVariantsCondition("r", resolvedTarget, p.Target)
 
 		cond = fmt.Sprintf(`EXISTS (
 			SELECT 1 FROM refs r
@@ -315,19 +336,64 @@ func (e *Executor) buildRefsPredicateSQL(p *RefsPredicate, alias string) (string
 }
 
 // buildContentPredicateSQL builds SQL for content("search terms") predicates.
-// Uses FTS5 full-text search to filter objects by their content.
+// Uses FTS5 full-text search to filter objects by their content, or by the
+// title, heading, code, or frontmatter fields column when the predicate is
+// scoped. Without FTS5 it falls back to LIKE matching on the same column.
 func (e *Executor) buildContentPredicateSQL(p *ContentPredicate, alias string) (string, []interface{}, error) {
-	// Use FTS5 to search content
-	// The fts_content table has: object_id, title, content, file_path
+	column := contentScopeColumn(p.Scope)
+
+	// The fts_content table has: object_id, title, content, headings, code, fields, file_path
+	match := "fts_content MATCH ?"
+	args := []interface{}{index.BuildFTSColumnQuery(column, p.SearchTerm)}
+	if !e.Capabilities().FTS5 {
+		match, args = index.BuildLikeSearchCondition([]string{"fts_content." + column}, p.SearchTerm)
+	}
+
 	cond := fmt.Sprintf(`EXISTS (
 		SELECT 1 FROM fts_content
 		WHERE fts_content.object_id = %s.id
-		  AND fts_content MATCH ?
-	)`, alias)
+		  AND %s
+	)`, alias, match)
 
 	if p.Negated() {
 		cond = "NOT " + cond
 	}
 
-	return cond, []interface{}{index.BuildFTSContentQuery(p.SearchTerm)}, nil
+	return cond, args, nil
+}
+
+// contentScopeColumn maps a content() scope to its fts_content column.
+func contentScopeColumn(scope ContentScope) string {
+	switch scope {
+	case ContentScopeTitle:
+		return index.FTSColumnTitle
+	case ContentScopeHeading:
+		return index.FTSColumnHeadings
+	case ContentScopeCode:
+		return index.FTSColumnCode
+	case ContentScopeField:
+		return index.FTSColumnFields
+	default:
+		return index.FTSColumnContent
+	}
+}
+
+// buildFocusPredicateSQL builds SQL for @focus: objects in the focus working
+// set, or traits and sections in files whose object is focused.
+func (e *Executor) buildFocusPredicateSQL(p *FocusPredicate, alias string, kind predicateKind) (string, []interface{}, error) {
+	var cond string
+	switch kind {
+	case predicateKindAsset:
+		return "", nil, fmt.Errorf("@focus is not valid for asset queries")
+	case predicateKindTrait, predicateKindSection:
+		cond = fmt.Sprintf(`%s.file_path IN (
+			SELECT fo.file_path FROM focus f JOIN objects fo ON fo.id = f.object_id
+		)`, alias)
+	default:
+		cond = fmt.Sprintf("%s.id IN (SELECT object_id FROM focus)", alias)
+	}
+	if p.Negated() {
+		cond = "NOT " + cond
+	}
+	return cond, nil, nil
 }
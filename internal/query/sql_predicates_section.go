@@ -59,7 +59,7 @@ func (e *Executor) buildSectionStringFuncPredicateSQL(p *StringFuncPredicate, al
 	if isNumericSectionField(p.Field) {
 		return "", nil, fmt.Errorf("section field '.%s' is numeric and does not support string functions", p.Field)
 	}
-	cond, args, err := buildStringFuncCondition(p.FuncType, column, p.Value, p.CaseSensitive)
+	cond, args, err := e.stringFuncCondition(p.FuncType, column, p.Value, p.CaseSensitive)
 	if err != nil {
 		return "", nil, err
 	}
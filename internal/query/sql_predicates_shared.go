@@ -134,19 +134,19 @@ func (e *Executor) buildRefdPredicateSQL(p *RefdPredicate, alias string, isTrait
 		}
 
 		// Referenced by a specific source
-		sourceID, err := e.resolveTarget(p.Target)
+		sourceExpr, sourceArgs, err := e.targetIDExpr(p.Target)
 		if err != nil {
 			return "", nil, err
 		}
 		cond := fmt.Sprintf(`EXISTS (
 			SELECT 1 FROM refs r
-			WHERE r.source_id = ?
+			WHERE r.source_id = %s
 			  AND (r.target_id = %s.id OR r.target_raw = %s.id)
-		)`, alias, alias)
+		)`, sourceExpr, alias, alias)
 		if p.Negated() {
 			cond = "NOT " + cond
 		}
-		return cond, []interface{}{sourceID}, nil
+		return cond, sourceArgs, nil
 	}
 
 	// Subquery - referenced by objects/traits matching the subquery
@@ -154,16 +154,9 @@ func (e *Executor) buildRefdPredicateSQL(p *RefdPredicate, alias string, isTrait
 	var args []interface{}
 
 	if p.SubQuery.Type == QueryTypeObject {
-		sourceConditions = append(sourceConditions, "src.type = ?")
-		args = append(args, p.SubQuery.TypeName)
-
-		if p.SubQuery.Predicate != nil {
-			cond, predArgs, err := e.buildObjectPredicateSQL(p.SubQuery.Predicate, "src", p.SubQuery.TypeName)
-			if err != nil {
-				return "", nil, err
-			}
-			sourceConditions = append(sourceConditions, cond)
-			args = append(args, predArgs...)
+		sourceCond, args, err := e.buildObjectWhereForAlias(p.SubQuery, "src")
+		if err != nil {
+			return "", nil, err
 		}
 
 		cond := fmt.Sprintf(`EXISTS (
@@ -171,7 +164,7 @@ func (e *Executor) buildRefdPredicateSQL(p *RefdPredicate, alias string, isTrait
 			JOIN objects src ON r.source_id = src.id
 			WHERE (r.target_id = %s.id OR r.target_raw = %s.id)
 			  AND %s
-		)`, alias, alias, strings.Join(sourceConditions, " AND "))
+		)`, alias, alias, sourceCond)
 
 		if p.Negated() {
 			cond = "NOT " + cond
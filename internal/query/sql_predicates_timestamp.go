@@ -0,0 +1,161 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// buildTimestampPredicateSQL builds SQL for modified(...) and created(...).
+//
+// Objects and assets filter their own timestamp columns. Traits and sections
+// use the timestamps of the file-backed object they live in.
+func (e *Executor) buildTimestampPredicateSQL(p *TimestampPredicate, alias string, kind predicateKind) (string, []interface{}, error) {
+	rowAlias := alias
+	if kind == predicateKindTrait || kind == predicateKindSection {
+		rowAlias = "tso"
+	}
+
+	column := rowAlias + ".file_mtime"
+	if p.Kind == TimestampCreated {
+		if kind == predicateKindAsset {
+			return "", nil, fmt.Errorf("created() predicate is not valid for asset queries")
+		}
+		column = fmt.Sprintf("COALESCE(%s.file_created, %s.file_mtime)", rowAlias, rowAlias)
+	}
+
+	now := e.queryNow()
+	conds := make([]string, 0, len(p.Bounds))
+	args := make([]interface{}, 0, len(p.Bounds))
+	for _, bound := range p.Bounds {
+		op, ts, err := resolveTimeBound(bound, now)
+		if err != nil {
+			return "", nil, err
+		}
+		conds = append(conds, fmt.Sprintf("%s %s ?", column, op))
+		args = append(args, ts)
+	}
+	cond := strings.Join(conds, " AND ")
+
+	if rowAlias != alias {
+		cond = fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM objects tso
+			WHERE tso.file_path = %s.file_path
+			  AND %s
+		)`, alias, cond)
+	} else {
+		cond = "(" + cond + ")"
+	}
+
+	if p.Negated() {
+		cond = "NOT " + cond
+	}
+	return cond, args, nil
+}
+
+// buildExpiredPredicateSQL builds SQL for expired(): objects whose type has a
+// review_after policy and whose review basis timestamp is older than the
+// window. Types without a policy never match.
+func (e *Executor) buildExpiredPredicateSQL(p *ExpiredPredicate, alias string, kind predicateKind) (string, []interface{}, error) {
+	if kind == predicateKindAsset {
+		return "", nil, fmt.Errorf("expired() predicate is not valid for asset queries")
+	}
+	rowAlias := alias
+	if kind == predicateKindTrait || kind == predicateKindSection {
+		rowAlias = "tso"
+	}
+
+	var typeNames []string
+	if e.schema != nil {
+		for name := range e.schema.Types {
+			typeNames = append(typeNames, name)
+		}
+	}
+	sort.Strings(typeNames)
+
+	now := e.queryNow()
+	var conds []string
+	var args []interface{}
+	for _, name := range typeNames {
+		window, from, ok := e.schema.Types[name].ReviewPolicy()
+		if !ok {
+			continue
+		}
+		column := rowAlias + ".file_mtime"
+		if from == schema.ReviewFromCreated {
+			column = fmt.Sprintf("COALESCE(%s.file_created, %s.file_mtime)", rowAlias, rowAlias)
+		}
+		conds = append(conds, fmt.Sprintf("(%s.type = ? AND %s < ?)", rowAlias, column))
+		args = append(args, name, now.Add(-window).Unix())
+	}
+
+	cond := "0"
+	if len(conds) > 0 {
+		cond = "(" + strings.Join(conds, " OR ") + ")"
+	}
+	if rowAlias != alias {
+		cond = fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM objects tso
+			WHERE tso.file_path = %s.file_path
+			  AND %s
+		)`, alias, cond)
+	}
+
+	if p.Negated() {
+		cond = "NOT " + cond
+	}
+	return cond, args, nil
+}
+
+// buildLifecyclePredicateSQL builds SQL for lifecycle(...): objects whose
+// type declares a lifecycle and whose state field holds one of the states.
+// Types without a lifecycle never match.
+func (e *Executor) buildLifecyclePredicateSQL(p *LifecyclePredicate, alias string, kind predicateKind) (string, []interface{}, error) {
+	if kind == predicateKindAsset {
+		return "", nil, fmt.Errorf("lifecycle() predicate is not valid for asset queries")
+	}
+	rowAlias := alias
+	if kind == predicateKindTrait || kind == predicateKindSection {
+		rowAlias = "tso"
+	}
+
+	var typeNames []string
+	if e.schema != nil {
+		for name, typeDef := range e.schema.Types {
+			if typeDef != nil && typeDef.Lifecycle != nil {
+				typeNames = append(typeNames, name)
+			}
+		}
+	}
+	sort.Strings(typeNames)
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(p.States)), ", ")
+	var conds []string
+	var args []interface{}
+	for _, name := range typeNames {
+		conds = append(conds, fmt.Sprintf("(%s.type = ? AND json_extract(%s.fields, ?) IN (%s))", rowAlias, rowAlias, placeholders))
+		args = append(args, name, jsonFieldPath(e.schema.Types[name].Lifecycle.StateField()))
+		for _, state := range p.States {
+			args = append(args, state)
+		}
+	}
+
+	cond := "0"
+	if len(conds) > 0 {
+		cond = "(" + strings.Join(conds, " OR ") + ")"
+	}
+	if rowAlias != alias {
+		cond = fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM objects tso
+			WHERE tso.file_path = %s.file_path
+			  AND %s
+		)`, alias, cond)
+	}
+
+	if p.Negated() {
+		cond = "NOT " + cond
+	}
+	return cond, args, nil
+}
@@ -6,25 +6,33 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aidanlsb/raven/internal/dates"
 	"github.com/aidanlsb/raven/internal/index"
 )
 
 // buildTraitContentPredicateSQL builds SQL for content("search terms") predicates on traits.
-// Uses LIKE matching on the trait's content column (the line text where the trait appears).
-// This is simpler than FTS5 since trait content is a single line.
+// Uses FTS5 full-text search over the trait's content (the line text where the
+// trait appears), so terms are tokenized and stemmed and quoted phrases match
+// as phrases. Without FTS5 it falls back to LIKE matching on traits.content.
 func (e *Executor) buildTraitContentPredicateSQL(p *ContentPredicate, alias string) (string, []interface{}, error) {
-	// Use case-insensitive LIKE to search the trait's line content
-	// The content column stores the full line where the trait annotation appears
-	cond := fmt.Sprintf("%s.content LIKE ? ESCAPE '\\'", alias)
-
-	// Escape special LIKE characters and wrap with wildcards for substring match
-	searchPattern := "%" + escapeLikePattern(p.SearchTerm) + "%"
+	var cond string
+	var args []interface{}
+	if e.Capabilities().FTS5 {
+		cond = fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM fts_traits
+		WHERE fts_traits.trait_id = %s.id
+		  AND fts_traits MATCH ?
+	)`, alias)
+		args = []interface{}{index.BuildFTSColumnQuery(index.FTSColumnContent, p.SearchTerm)}
+	} else {
+		cond, args = index.BuildLikeSearchCondition([]string{alias + ".content"}, p.SearchTerm)
+	}
 
 	if p.Negated() {
 		cond = "NOT " + cond
 	}
 
-	return cond, []interface{}{searchPattern}, nil
+	return cond, args, nil
 }
 
 // buildTraitRefsPredicateSQL builds SQL for refs([[target]]) or refs(type:...) predicates on traits.
@@ -40,11 +48,10 @@ func (e *Executor) buildTraitRefsPredicateSQL(p *RefsPredicate, alias string) (s
 	if p.Target != "" {
 		// Direct reference to specific target
 		// Resolve the target to its canonical object ID (like backlinks does)
-		resolvedTarget, err := e.resolveTarget(p.Target)
+		targetCond, targetArgs, err := e.refTargetCondition("r", p.Target)
 		if err != nil {
 			return "", nil, err
 		}
-		targetCond, targetArgs := buildRefTargetVariantsCondition("r", resolvedTarget, p.Target)
 
 		// Match refs on the same line as the trait
 		cond = fmt.Sprintf(`EXISTS (
@@ -110,7 +117,7 @@ func (e *Executor) buildTraitStringFuncPredicateSQL(p *StringFuncPredicate, alia
 
 	fieldExpr := fmt.Sprintf("%s.value", alias)
 
-	cond, args, err := buildStringFuncCondition(p.FuncType, fieldExpr, p.Value, p.CaseSensitive)
+	cond, args, err := e.stringFuncCondition(p.FuncType, fieldExpr, p.Value, p.CaseSensitive)
 	if err != nil {
 		return "", nil, err
 	}
@@ -164,7 +171,7 @@ func (e *Executor) buildTraitArrayQuantifierPredicateSQL(p *ArrayQuantifierPredi
 // buildValuePredicateSQL builds SQL for value==val predicates.
 // Comparisons are case-insensitive for equality, but case-sensitive for ordering comparisons.
 func (e *Executor) buildValuePredicateSQL(p *ValuePredicate, alias string) (string, []interface{}, error) {
-	cond, args := e.buildValueCondition(p, fmt.Sprintf("%s.value", alias))
+	cond, args := e.buildTraitValueCompareCondition(p.Value, p.CompareOp, p.Negated(), alias)
 	return cond, args, nil
 }
 
@@ -217,10 +224,47 @@ func (e *Executor) buildCompareCondition(value string, compareOp CompareOp, nega
 // buildTraitValueFieldPredicateSQL builds SQL for .value==val predicates on traits.
 // This is the newer syntax that replaces the bare value== syntax.
 func (e *Executor) buildTraitValueFieldPredicateSQL(p *FieldPredicate, alias string) (string, []interface{}, error) {
-	cond, args := e.buildCompareCondition(p.Value, p.CompareOp, p.Negated(), fmt.Sprintf("%s.value", alias))
+	cond, args := e.buildTraitValueCompareCondition(p.Value, p.CompareOp, p.Negated(), alias)
 	return cond, args, nil
 }
 
+// buildTraitValueCompareCondition compares a trait's value, expanding
+// recurring dates: == and != test the indexed occurrences, and ordering
+// comparisons use the next occurrence on or after today.
+func (e *Executor) buildTraitValueCompareCondition(value string, compareOp CompareOp, negated bool, alias string) (string, []interface{}) {
+	column := fmt.Sprintf("%s.value", alias)
+	value = strings.TrimSpace(value)
+	now := e.queryNow()
+	if _, _, ok := buildDateFilterConditionForCompare(value, compareOp, column, now); !ok {
+		return e.buildCompareCondition(value, compareOp, negated, column)
+	}
+
+	var cond string
+	var args []interface{}
+	switch compareOp {
+	case CompareEq, CompareNeq:
+		valueCond, valueArgs, _ := buildDateFilterConditionForCompare(value, CompareEq, column, now)
+		occurrenceCond, occurrenceArgs, _ := buildDateFilterConditionForCompare(value, CompareEq, "occ.date", now)
+		cond = fmt.Sprintf(
+			"(EXISTS (SELECT 1 FROM trait_occurrences occ WHERE occ.trait_id = %[1]s.id AND %[2]s) OR (NOT EXISTS (SELECT 1 FROM trait_occurrences occ WHERE occ.trait_id = %[1]s.id) AND %[3]s))",
+			alias, occurrenceCond, valueCond,
+		)
+		args = append(occurrenceArgs, valueArgs...)
+		if compareOp == CompareNeq {
+			cond = "NOT " + cond
+		}
+	default:
+		nextOccurrence := fmt.Sprintf("COALESCE((SELECT MIN(occ.date) FROM trait_occurrences occ WHERE occ.trait_id = %s.id AND occ.date >= ?), %s)", alias, column)
+		var valueArgs []interface{}
+		cond, valueArgs, _ = buildDateFilterConditionForCompare(value, compareOp, nextOccurrence, now)
+		args = append([]interface{}{now.Format(dates.DateLayout)}, valueArgs...)
+	}
+	if negated {
+		cond = "NOT (" + cond + ")"
+	}
+	return cond, args
+}
+
 func buildDateFilterConditionForCompare(value string, compareOp CompareOp, column string, now time.Time) (string, []interface{}, bool) {
 	if value == "" {
 		return "", nil, false
@@ -240,6 +284,9 @@ func buildDateFilterConditionForCompare(value string, compareOp CompareOp, colum
 // buildAtPredicateSQL builds SQL for at(trait:...) predicates.
 // Matches traits at the same file:line location as matching traits.
 func (e *Executor) buildAtPredicateSQL(p *AtPredicate, alias string) (string, []interface{}, error) {
+	if p.Target == derivedSelfTarget {
+		return "", nil, errDerivedSelfUnsupported
+	}
 	if p.Target != "" {
 		// Check for special self-reference marker from at:_ binding
 		if strings.HasPrefix(p.Target, "__selfref_trait:") {
@@ -0,0 +1,121 @@
+package query
+
+import (
+	"container/list"
+	"database/sql"
+	"sync"
+
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/resolver"
+)
+
+const stmtCacheMaxEntries = 128
+
+type stmtCacheEntry struct {
+	sqlStr string
+	stmt   *sql.Stmt
+}
+
+// executorCache holds state that survives across executions of one Executor:
+// prepared statements keyed by generated SQL, the target resolver, and the
+// database's detected capabilities.
+//
+// Generated SQL is the normalized form of a query AST: relative dates, resolved
+// targets, and search terms are bound as arguments, so repeated runs of the
+// same saved query produce identical SQL text and reuse one prepared
+// statement. The cache is shared by the scoped copies made for each execution.
+type executorCache struct {
+	mu       sync.Mutex
+	stmts    map[string]*list.Element
+	order    *list.List
+	resolver *resolver.Resolver
+	caps     *index.Capabilities
+	hits     int
+	misses   int
+}
+
+func newExecutorCache() *executorCache {
+	return &executorCache{
+		stmts: map[string]*list.Element{},
+		order: list.New(),
+	}
+}
+
+// CacheStats reports prepared statement cache usage for an Executor.
+type CacheStats struct {
+	Statements int
+	Hits       int
+	Misses     int
+}
+
+// CacheStats returns prepared statement cache counters.
+func (e *Executor) CacheStats() CacheStats {
+	c := e.cache
+	if c == nil {
+		return CacheStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Statements: len(c.stmts), Hits: c.hits, Misses: c.misses}
+}
+
+// ResetCache closes cached prepared statements and drops the cached resolver.
+// Call it after the index changes (for example after a reindex) so later
+// queries resolve targets against the new contents.
+func (e *Executor) ResetCache() {
+	c := e.cache
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		_ = elem.Value.(*stmtCacheEntry).stmt.Close()
+	}
+	c.stmts = map[string]*list.Element{}
+	c.order.Init()
+	c.resolver = nil
+	c.hits = 0
+	c.misses = 0
+}
+
+// prepared returns a cached prepared statement for sqlStr, preparing it on
+// first use and evicting the least recently used statement when full.
+func (e *Executor) prepared(sqlStr string) (*sql.Stmt, error) {
+	c := e.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem := c.stmts[sqlStr]; elem != nil {
+		c.order.MoveToFront(elem)
+		c.hits++
+		return elem.Value.(*stmtCacheEntry).stmt, nil
+	}
+
+	stmt, err := e.db.Prepare(sqlStr)
+	if err != nil {
+		return nil, err
+	}
+	c.misses++
+	c.stmts[sqlStr] = c.order.PushFront(&stmtCacheEntry{sqlStr: sqlStr, stmt: stmt})
+	if len(c.stmts) > stmtCacheMaxEntries {
+		if oldest := c.order.Back(); oldest != nil {
+			entry := oldest.Value.(*stmtCacheEntry)
+			c.order.Remove(oldest)
+			delete(c.stmts, entry.sqlStr)
+			_ = entry.stmt.Close()
+		}
+	}
+	return stmt, nil
+}
+
+func (e *Executor) queryRows(sqlStr string, args []interface{}) (*sql.Rows, error) {
+	if e.cache == nil {
+		return e.db.Query(sqlStr, args...)
+	}
+	stmt, err := e.prepared(sqlStr)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Query(args...)
+}
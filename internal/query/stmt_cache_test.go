@@ -0,0 +1,82 @@
+package query
+
+import "testing"
+
+func TestExecutorReusesPreparedStatements(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	exec := NewExecutor(db)
+	run := func(queryStr string) int {
+		t.Helper()
+		q, err := Parse(queryStr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", queryStr, err)
+		}
+		results, err := exec.ExecuteObjectQuery(q)
+		if err != nil {
+			t.Fatalf("ExecuteObjectQuery(%q): %v", queryStr, err)
+		}
+		return len(results)
+	}
+
+	first := run("type:project refs([[people/freya]])")
+	second := run("type:project   refs( [[people/freya]] )")
+	if first != 1 || second != 1 {
+		t.Fatalf("results = %d, %d; want 1 each", first, second)
+	}
+	stats := exec.CacheStats()
+	if stats.Statements != 1 || stats.Misses != 1 || stats.Hits != 1 {
+		t.Fatalf("CacheStats() = %+v, want one statement reused once", stats)
+	}
+
+	// Different bound arguments reuse the same statement.
+	if got := run("type:project refs([[people/loki]])"); got != 0 {
+		t.Fatalf("refs loki results = %d, want 0", got)
+	}
+	if stats := exec.CacheStats(); stats.Statements != 1 || stats.Hits != 2 {
+		t.Fatalf("CacheStats() = %+v, want argument changes to hit the cache", stats)
+	}
+
+	exec.ResetCache()
+	if stats := exec.CacheStats(); stats != (CacheStats{}) {
+		t.Fatalf("CacheStats() after ResetCache = %+v, want empty", stats)
+	}
+	if exec.cache.resolver != nil {
+		t.Fatal("ResetCache should drop the cached resolver")
+	}
+	if got := run("type:project refs([[people/freya]])"); got != 1 {
+		t.Fatalf("results after reset = %d, want 1", got)
+	}
+}
+
+func TestExecutorResolverSharedAcrossExecutions(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	exec := NewExecutor(db)
+	q, err := Parse("type:project refs([[people/freya]])")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, err := exec.ExecuteObjectQuery(q); err != nil {
+		t.Fatalf("ExecuteObjectQuery: %v", err)
+	}
+	res := exec.cache.resolver
+	if res == nil {
+		t.Fatal("expected resolver to be cached after execution")
+	}
+	if _, err := exec.ExecuteObjectCountQuery(q); err != nil {
+		t.Fatalf("ExecuteObjectCountQuery: %v", err)
+	}
+	if exec.cache.resolver != res {
+		t.Fatal("expected later executions to reuse the cached resolver")
+	}
+
+	exec.SetDailyDirectory("journal")
+	if exec.cache.resolver != nil {
+		t.Fatal("changing the daily directory should reset the cache")
+	}
+}
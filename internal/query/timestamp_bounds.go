@@ -0,0 +1,77 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/dates"
+)
+
+// parseTimeBound validates one modified()/created() bound at parse time.
+func parseTimeBound(op, value string) (TimeBound, error) {
+	value = strings.TrimSpace(value)
+	switch op {
+	case "within":
+		window, err := parseTimeWindow(value)
+		if err != nil {
+			return TimeBound{}, err
+		}
+		return TimeBound{Op: op, Value: value, Window: window}, nil
+	case "before", "after":
+		if !dates.IsRelativeDateKeyword(value) && !dates.IsValidDate(value) {
+			return TimeBound{}, fmt.Errorf("invalid date %q for %s: (use YYYY-MM-DD, today, yesterday, or tomorrow)", value, op)
+		}
+		return TimeBound{Op: op, Value: value}, nil
+	default:
+		return TimeBound{}, fmt.Errorf("unknown bound %q (use within:, before:, or after:)", op)
+	}
+}
+
+// parseTimeWindow parses durations like 12h, 7d, or 2w.
+func parseTimeWindow(value string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid window %q for within: (use a count with h, d, or w, e.g. 7d)", value)
+	if len(value) < 2 {
+		return 0, invalid
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n < 0 {
+		return 0, invalid
+	}
+	switch strings.ToLower(value[len(value)-1:]) {
+	case "h":
+		return time.Duration(n) * time.Hour, nil
+	case "d":
+		return time.Duration(n) * 24 * time.Hour, nil
+	case "w":
+		return time.Duration(n) * 7 * 24 * time.Hour, nil
+	default:
+		return 0, invalid
+	}
+}
+
+// resolveTimeBound returns the SQL operator and Unix timestamp for a bound.
+// before:D matches times before the start of D; after:D matches times from
+// the start of the following day.
+func resolveTimeBound(bound TimeBound, now time.Time) (string, int64, error) {
+	if bound.Op == "within" {
+		return ">=", now.Add(-bound.Window).Unix(), nil
+	}
+
+	var day time.Time
+	if resolved, ok := dates.ResolveRelativeDateKeyword(bound.Value, now, time.Monday); ok {
+		day = resolved.Date
+	} else {
+		parsed, err := dates.ParseDate(bound.Value)
+		if err != nil {
+			return "", 0, err
+		}
+		day = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, now.Location())
+	}
+
+	if bound.Op == "after" {
+		return ">=", day.AddDate(0, 0, 1).Unix(), nil
+	}
+	return "<", day.Unix(), nil
+}
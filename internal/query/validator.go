@@ -56,6 +56,10 @@ func (v *Validator) validateQuery(q *Query) error {
 }
 
 func (v *Validator) validateObjectQuery(q *Query) error {
+	if q.IsCrossType() {
+		return v.validateCrossTypeObjectQuery(q)
+	}
+
 	// Check that the type exists in schema
 	typeDef, exists := v.schema.Types[q.TypeName]
 	if !exists {
@@ -76,6 +80,67 @@ func (v *Validator) validateObjectQuery(q *Query) error {
 	return nil
 }
 
+// validateCrossTypeObjectQuery validates type:* and type unions. Every union
+// member must exist, and each predicate must be valid for at least one of the
+// types the query spans; objects of the other types simply do not match it.
+func (v *Validator) validateCrossTypeObjectQuery(q *Query) error {
+	typeNames := q.TypeNames
+	if q.TypeName == AnyType {
+		typeNames = v.availableTypes()
+	}
+	for _, name := range q.TypeNames {
+		if _, exists := v.schema.Types[name]; !exists {
+			return &ValidationError{
+				Message:    fmt.Sprintf("unknown type '%s' in type union", name),
+				Suggestion: fmt.Sprintf("Available types: %s", strings.Join(v.availableTypes(), ", ")),
+			}
+		}
+	}
+	if q.Predicate == nil {
+		return nil
+	}
+	return v.validateCrossTypeObjectPredicate(q.Predicate, q.TypeName, typeNames)
+}
+
+func (v *Validator) validateCrossTypeObjectPredicate(pred Predicate, label string, typeNames []string) error {
+	switch p := pred.(type) {
+	case *OrPredicate:
+		for _, sub := range p.Predicates {
+			if err := v.validateCrossTypeObjectPredicate(sub, label, typeNames); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *GroupPredicate:
+		for _, sub := range p.Predicates {
+			if err := v.validateCrossTypeObjectPredicate(sub, label, typeNames); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *NotPredicate:
+		return v.validateCrossTypeObjectPredicate(p.Inner, label, typeNames)
+	}
+
+	var firstErr error
+	for _, name := range typeNames {
+		err := v.validateObjectPredicate(pred, name, v.schema.Types[name])
+		if err == nil {
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if vErr, ok := firstErr.(*ValidationError); ok {
+		return &ValidationError{
+			Message:    fmt.Sprintf("no type in '%s' matches the predicate: %s", label, vErr.Message),
+			Suggestion: vErr.Suggestion,
+		}
+	}
+	return firstErr
+}
+
 func (v *Validator) validateTraitQuery(q *Query) error {
 	// Check that the trait exists in schema
 	if _, exists := v.schema.Traits[q.TypeName]; !exists {
@@ -142,6 +207,8 @@ func (v *Validator) validateObjectPredicate(pred Predicate, typeName string, typ
 		if p.SubQuery != nil {
 			return v.validateQuery(p.SubQuery)
 		}
+	case *LifecyclePredicate:
+		return v.validateLifecyclePredicate(p, typeName, typeDef)
 	case *ValuePredicate:
 		// ValuePredicate is deprecated; the parser now uses FieldPredicate with Field="value"
 		return &ValidationError{
@@ -182,6 +249,24 @@ func (v *Validator) validateObjectPredicate(pred Predicate, typeName string, typ
 	return nil
 }
 
+func (v *Validator) validateLifecyclePredicate(p *LifecyclePredicate, typeName string, typeDef *schema.TypeDefinition) error {
+	if typeDef == nil || typeDef.Lifecycle == nil {
+		return &ValidationError{
+			Message:    fmt.Sprintf("type '%s' has no lifecycle", typeName),
+			Suggestion: "Declare lifecycle states on the type in schema.yaml",
+		}
+	}
+	for _, state := range p.States {
+		if !typeDef.Lifecycle.HasState(state) {
+			return &ValidationError{
+				Message:    fmt.Sprintf("'%s' is not a lifecycle state of type '%s'", state, typeName),
+				Suggestion: fmt.Sprintf("Use one of: %s", strings.Join(typeDef.Lifecycle.States, ", ")),
+			}
+		}
+	}
+	return nil
+}
+
 func (v *Validator) validateTraitPredicate(pred Predicate, traitName string) error {
 	switch p := pred.(type) {
 	case *ValuePredicate:
@@ -211,6 +296,12 @@ func (v *Validator) validateTraitPredicate(pred Predicate, traitName string) err
 			return v.validateQuery(p.SubQuery)
 		}
 	case *RefsPredicate:
+		if p.Transitive {
+			return &ValidationError{
+				Message:    "refs*() predicate is only valid for type and section queries",
+				Suggestion: "Use refs(...) in trait queries, or move refs*(...) to a type query",
+			}
+		}
 		if p.SubQuery != nil {
 			return v.validateQuery(p.SubQuery)
 		}
@@ -222,6 +313,12 @@ func (v *Validator) validateTraitPredicate(pred Predicate, traitName string) err
 				Suggestion: `Provide a search term: content("search terms")`,
 			}
 		}
+		if p.Scope != ContentScopeBody {
+			return &ValidationError{
+				Message:    fmt.Sprintf("content(%s:...) is not valid for trait queries", p.Scope),
+				Suggestion: `Trait content is a single line; use content("search terms")`,
+			}
+		}
 	case *AtPredicate:
 		// at: is only valid for trait queries (which we're in)
 		if p.SubQuery != nil {
@@ -249,6 +346,11 @@ func (v *Validator) validateTraitPredicate(pred Predicate, traitName string) err
 		}
 	case *ArrayQuantifierPredicate:
 		return v.validateTraitArrayQuantifierPredicate(p, traitName)
+	case *AttachmentPredicate:
+		return &ValidationError{
+			Message:    "attachment predicates are only valid for type and section queries",
+			Suggestion: "Use has_attachment() or attachment(type:pdf) in type or section queries",
+		}
 	case *HasPredicate:
 		return &ValidationError{
 			Message:    "has() predicate is only valid for type and section queries",
@@ -308,6 +410,12 @@ func (v *Validator) validateAssetPredicate(pred Predicate) error {
 	case *StringFuncPredicate:
 		return v.validateAssetStringFuncPredicate(p)
 	case *RefdPredicate:
+		if p.Transitive {
+			return &ValidationError{
+				Message:    "refd*() predicate is not valid for asset queries",
+				Suggestion: "Use asset refd(...) to find assets referenced by objects or traits",
+			}
+		}
 		if p.SubQuery != nil {
 			return v.validateQuery(p.SubQuery)
 		}
@@ -346,6 +454,33 @@ func (v *Validator) validateAssetPredicate(pred Predicate) error {
 			Message:    "trait-location predicates are not valid for asset queries",
 			Suggestion: "Use asset refd(trait:...) to find assets referenced by matching trait lines",
 		}
+	case *AttachmentPredicate:
+		return &ValidationError{
+			Message:    "attachment predicates are not valid for asset queries",
+			Suggestion: "Filter assets by .extension or .size_bytes, or use asset refd(...) to find linked assets",
+		}
+	case *ExpiredPredicate:
+		return &ValidationError{
+			Message:    "expired() predicate is not valid for asset queries",
+			Suggestion: "Use expired() on type, trait, or section queries",
+		}
+	case *LifecyclePredicate:
+		return &ValidationError{
+			Message:    "lifecycle() predicate is not valid for asset queries",
+			Suggestion: "Use lifecycle() on type, trait, or section queries",
+		}
+	case *FocusPredicate:
+		return &ValidationError{
+			Message:    "@focus is not valid for asset queries",
+			Suggestion: "Use @focus on type, trait, or section queries",
+		}
+	case *TimestampPredicate:
+		if p.Kind == TimestampCreated {
+			return &ValidationError{
+				Message:    "created() predicate is not valid for asset queries",
+				Suggestion: "Assets only track modification time; use modified(...)",
+			}
+		}
 	case *ValuePredicate:
 		return &ValidationError{
 			Message:    "value predicates are not valid for asset queries",
@@ -463,7 +598,7 @@ func (v *Validator) validateSectionPredicate(pred Predicate) error {
 }
 
 func (v *Validator) validateFieldPredicate(p *FieldPredicate, typeName string, typeDef *schema.TypeDefinition) error {
-	if isDateVirtualField(typeName, p.Field) {
+	if isDateVirtualField(typeName, p.Field) || isDisplayNameField(typeDef, p.Field) {
 		return nil
 	}
 	_, err := v.fieldDefinitionForType(typeName, typeDef, p.Field)
@@ -511,6 +646,10 @@ func (v *Validator) validateObjectStringFuncPredicate(p *StringFuncPredicate, ty
 		}
 	}
 
+	if isDisplayNameField(typeDef, p.Field) {
+		return validateRegexPattern(p)
+	}
+
 	fieldDef, err := v.fieldDefinitionForType(typeName, typeDef, p.Field)
 	if err != nil {
 		return err
@@ -619,6 +758,14 @@ func validateRegexPattern(p *StringFuncPredicate) error {
 }
 
 func (v *Validator) fieldDefinitionForType(typeName string, typeDef *schema.TypeDefinition, fieldName string) (*schema.FieldDefinition, error) {
+	// Attribution fields are reserved and queryable on every type.
+	if schema.IsAttributionField(fieldName) {
+		if typeDef != nil && typeDef.Fields[fieldName] != nil {
+			return typeDef.Fields[fieldName], nil
+		}
+		return &schema.FieldDefinition{Type: schema.FieldTypeString}, nil
+	}
+
 	if typeDef == nil || typeDef.Fields == nil {
 		return nil, &ValidationError{
 			Message:    fmt.Sprintf("type '%s' has no defined fields", typeName),
@@ -626,6 +773,23 @@ func (v *Validator) fieldDefinitionForType(typeName string, typeDef *schema.Type
 		}
 	}
 
+	if schema.IsNestedFieldPath(fieldName) {
+		if _, err := schema.ParseFieldPath(fieldName); err != nil {
+			return nil, &ValidationError{
+				Message:    err.Error(),
+				Suggestion: "Write nested fields as .field.key and array elements as .field[0]",
+			}
+		}
+		fieldDef := typeDef.FieldAtPath(fieldName)
+		if fieldDef == nil {
+			return nil, &ValidationError{
+				Message:    fmt.Sprintf("type '%s' has no field '%s'", typeName, fieldName),
+				Suggestion: "Nested keys must be defined under an object field's fields, and [n] can only follow an array field",
+			}
+		}
+		return fieldDef, nil
+	}
+
 	fieldDef, exists := typeDef.Fields[fieldName]
 	if !exists {
 		available := v.availableFields(typeDef)
@@ -132,6 +132,30 @@ func TestValidator_UnknownField(t *testing.T) {
 	}
 }
 
+func TestValidator_AttributionFields(t *testing.T) {
+	t.Parallel()
+	sch := &schema.Schema{
+		Types: map[string]*schema.TypeDefinition{
+			"person": {},
+		},
+		Traits: map[string]*schema.TraitDefinition{},
+	}
+
+	v := NewValidator(sch)
+	for _, input := range []string{
+		`type:person .created_by=="Ada"`,
+		`type:person exists(.modified_by)`,
+	} {
+		q, err := Parse(input)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", input, err)
+		}
+		if err := v.Validate(q); err != nil {
+			t.Errorf("Validate(%q) error = %v, want nil", input, err)
+		}
+	}
+}
+
 func TestValidator_ValidQuery(t *testing.T) {
 	t.Parallel()
 	sch := &schema.Schema{
@@ -236,6 +260,11 @@ func TestValidator_AssetQueryRules(t *testing.T) {
 			query:   "asset has(trait:todo)",
 			wantMsg: "has() predicate is not valid for asset queries",
 		},
+		{
+			name:    "attachment rejected",
+			query:   "asset has_attachment()",
+			wantMsg: "attachment predicates are not valid for asset queries",
+		},
 	}
 
 	for _, tt := range tests {
@@ -282,6 +311,23 @@ func TestValidator_TraitRefdRejected(t *testing.T) {
 	}
 }
 
+func TestValidator_TraitAttachmentRejected(t *testing.T) {
+	t.Parallel()
+	v := NewValidator(&schema.Schema{
+		Types:  map[string]*schema.TypeDefinition{},
+		Traits: map[string]*schema.TraitDefinition{"due": {}},
+	})
+
+	q, err := Parse("trait:due attachment(type:pdf)")
+	if err != nil {
+		t.Fatalf("failed to parse query: %v", err)
+	}
+	err = v.Validate(q)
+	if err == nil || !strings.Contains(err.Error(), "only valid for type and section queries") {
+		t.Fatalf("expected attachment rejection for trait query, got %v", err)
+	}
+}
+
 func TestValidator_FieldNotTrait(t *testing.T) {
 	t.Parallel()
 	// Traits are NOT valid as field access - only actual fields are
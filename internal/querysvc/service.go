@@ -67,6 +67,7 @@ type SavedQueryInfo struct {
 	Args        []string
 	Description string
 	Options     *config.QueryOptions
+	Snapshot    *config.QuerySnapshot
 }
 
 type ListRequest struct {
@@ -93,6 +94,7 @@ type SetRequest struct {
 	Args        []string
 	Description string
 	Options     *config.QueryOptions
+	Snapshot    *config.QuerySnapshot
 }
 
 type SetStatus string
@@ -194,6 +196,12 @@ func Set(req SetRequest) (*SetResult, error) {
 	if err := ValidateInputDeclarations(name, queryStr, declaredArgs); err != nil {
 		return nil, err
 	}
+	snapshot := cloneQuerySnapshot(req.Snapshot)
+	if snapshot != nil {
+		if err := ValidateSnapshot(name, snapshot, declaredArgs); err != nil {
+			return nil, newError(CodeInvalidInput, err.Error(), "Use --snapshot-target <note> and an optional --snapshot-schedule like daily or 6h", err)
+		}
+	}
 
 	vaultCfg, err := config.LoadVaultConfig(req.VaultPath)
 	if err != nil {
@@ -208,6 +216,7 @@ func Set(req SetRequest) (*SetResult, error) {
 		Args:        declaredArgs,
 		Description: req.Description,
 		Options:     cloneQueryOptions(req.Options),
+		Snapshot:    snapshot,
 	}
 	if next.Options.IsEmpty() {
 		next.Options = nil
@@ -270,6 +279,7 @@ func savedQueryInfo(name string, q *config.SavedQuery) SavedQueryInfo {
 		Args:        append([]string(nil), q.Args...),
 		Description: q.Description,
 		Options:     cloneQueryOptions(q.Options),
+		Snapshot:    cloneQuerySnapshot(q.Snapshot),
 	}
 }
 
@@ -288,7 +298,7 @@ func savedQueriesEqual(a, b *config.SavedQuery) bool {
 			return false
 		}
 	}
-	return queryOptionsEqual(a.Options, b.Options)
+	return queryOptionsEqual(a.Options, b.Options) && querySnapshotsEqual(a.Snapshot, b.Snapshot)
 }
 
 func cloneQueryOptions(in *config.QueryOptions) *config.QueryOptions {
@@ -314,7 +324,8 @@ func queryOptionsEqual(a, b *config.QueryOptions) bool {
 		!boolPtrEqual(a.CountOnly, b.CountOnly) ||
 		!boolPtrEqual(a.Confirm, b.Confirm) ||
 		!boolPtrEqual(a.Pipe, b.Pipe) ||
-		!boolPtrEqual(a.Browse, b.Browse) {
+		!boolPtrEqual(a.Browse, b.Browse) ||
+		!boolPtrEqual(a.Full, b.Full) {
 		return false
 	}
 	if len(a.Apply) != len(b.Apply) {
@@ -0,0 +1,167 @@
+package querysvc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/config"
+)
+
+// snapshotUpdatedLayout is the timestamp format stored in snapshot markers.
+const snapshotUpdatedLayout = time.RFC3339
+
+// ParseSnapshotSchedule parses a snapshot schedule into its refresh interval.
+// Accepted values are hourly, daily, weekly, or a positive count with an
+// m, h, d or w suffix (e.g. "30m", "6h", "2d").
+func ParseSnapshotSchedule(schedule string) (time.Duration, error) {
+	s := strings.ToLower(strings.TrimSpace(schedule))
+	switch s {
+	case "":
+		return 0, fmt.Errorf("schedule is empty")
+	case "hourly":
+		return time.Hour, nil
+	case "daily":
+		return 24 * time.Hour, nil
+	case "weekly":
+		return 7 * 24 * time.Hour, nil
+	}
+
+	unit := s[len(s)-1]
+	count, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || count <= 0 {
+		return 0, fmt.Errorf("invalid schedule %q (use hourly, daily, weekly, or a count like 6h or 2d)", schedule)
+	}
+	switch unit {
+	case 'm':
+		return time.Duration(count) * time.Minute, nil
+	case 'h':
+		return time.Duration(count) * time.Hour, nil
+	case 'd':
+		return time.Duration(count) * 24 * time.Hour, nil
+	case 'w':
+		return time.Duration(count) * 7 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("invalid schedule %q (use hourly, daily, weekly, or a count like 6h or 2d)", schedule)
+	}
+}
+
+// ValidateSnapshot checks a saved query snapshot declaration. Snapshots run
+// unattended, so queries that require inputs cannot be snapshotted.
+func ValidateSnapshot(name string, snapshot *config.QuerySnapshot, declaredArgs []string) error {
+	if snapshot == nil {
+		return nil
+	}
+	if strings.TrimSpace(snapshot.Target) == "" {
+		return fmt.Errorf("snapshot for query '%s' requires a target note", name)
+	}
+	if strings.TrimSpace(snapshot.Schedule) != "" {
+		if _, err := ParseSnapshotSchedule(snapshot.Schedule); err != nil {
+			return fmt.Errorf("snapshot for query '%s': %w", name, err)
+		}
+	}
+	if len(declaredArgs) > 0 {
+		return fmt.Errorf("snapshot for query '%s' cannot be used with declared args", name)
+	}
+	return nil
+}
+
+// SnapshotLastUpdated returns the timestamp recorded in the named snapshot
+// block of content, if the block exists.
+func SnapshotLastUpdated(content, name string) (time.Time, bool) {
+	m := snapshotBeginPattern(name).FindStringSubmatch(content)
+	if m == nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(snapshotUpdatedLayout, m[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// SnapshotDue reports whether a scheduled snapshot should be refreshed at now.
+// Snapshots without a schedule are never due; scheduled snapshots that have
+// not been rendered yet always are.
+func SnapshotDue(snapshot *config.QuerySnapshot, content, name string, now time.Time) bool {
+	if snapshot == nil || strings.TrimSpace(snapshot.Schedule) == "" {
+		return false
+	}
+	interval, err := ParseSnapshotSchedule(snapshot.Schedule)
+	if err != nil {
+		return false
+	}
+	last, ok := SnapshotLastUpdated(content, name)
+	if !ok {
+		return true
+	}
+	return !now.Before(last.Add(interval))
+}
+
+// RenderSnapshotBlock renders the marked block holding a query snapshot.
+// Each item is rendered as one list entry.
+func RenderSnapshotBlock(name string, items []string, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!-- rvn:snapshot %s updated=%s -->\n", name, now.Format(snapshotUpdatedLayout))
+	noun := "results"
+	if len(items) == 1 {
+		noun = "result"
+	}
+	fmt.Fprintf(&b, "_Updated %s · %d %s_\n", now.Format("2006-01-02 15:04"), len(items), noun)
+	if len(items) > 0 {
+		b.WriteString("\n")
+		for _, item := range items {
+			b.WriteString("- ")
+			b.WriteString(item)
+			b.WriteString("\n")
+		}
+	}
+	fmt.Fprintf(&b, "<!-- /rvn:snapshot %s -->", name)
+	return b.String()
+}
+
+// ApplySnapshotBlock replaces the named snapshot block in content with block,
+// or appends block at the end of content when no block exists yet.
+func ApplySnapshotBlock(content, name, block string) string {
+	begin := snapshotBeginPattern(name).FindStringIndex(content)
+	if begin != nil {
+		endMarker := fmt.Sprintf("<!-- /rvn:snapshot %s -->", name)
+		if rel := strings.Index(content[begin[1]:], endMarker); rel >= 0 {
+			end := begin[1] + rel + len(endMarker)
+			return content[:begin[0]] + block + content[end:]
+		}
+	}
+
+	trimmed := strings.TrimRight(content, "\n")
+	if trimmed == "" {
+		return block + "\n"
+	}
+	return trimmed + "\n\n" + block + "\n"
+}
+
+func snapshotBeginPattern(name string) *regexp.Regexp {
+	return regexp.MustCompile(`<!-- rvn:snapshot ` + regexp.QuoteMeta(name) + ` updated=(\S+) -->`)
+}
+
+func cloneQuerySnapshot(in *config.QuerySnapshot) *config.QuerySnapshot {
+	if in == nil {
+		return nil
+	}
+	out := config.QuerySnapshot{
+		Target:   strings.TrimSpace(in.Target),
+		Schedule: strings.TrimSpace(in.Schedule),
+	}
+	if out.Target == "" && out.Schedule == "" {
+		return nil
+	}
+	return &out
+}
+
+func querySnapshotsEqual(a, b *config.QuerySnapshot) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,109 @@
+package querysvc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/config"
+)
+
+func TestParseSnapshotSchedule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		schedule string
+		want     time.Duration
+		wantErr  bool
+	}{
+		{schedule: "hourly", want: time.Hour},
+		{schedule: "Daily", want: 24 * time.Hour},
+		{schedule: "weekly", want: 7 * 24 * time.Hour},
+		{schedule: "30m", want: 30 * time.Minute},
+		{schedule: "6h", want: 6 * time.Hour},
+		{schedule: "2d", want: 48 * time.Hour},
+		{schedule: "1w", want: 7 * 24 * time.Hour},
+		{schedule: "", wantErr: true},
+		{schedule: "0h", wantErr: true},
+		{schedule: "3y", wantErr: true},
+		{schedule: "sometimes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSnapshotSchedule(tt.schedule)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseSnapshotSchedule(%q) = %v, want error", tt.schedule, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSnapshotSchedule(%q) = %v, %v; want %v", tt.schedule, got, err, tt.want)
+		}
+	}
+}
+
+func TestApplySnapshotBlock(t *testing.T) {
+	t.Parallel()
+
+	first := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	block := RenderSnapshotBlock("questions", []string{"[[note/a]]"}, first)
+
+	content := ApplySnapshotBlock("# Questions\n\nIntro.\n", "questions", block)
+	if !strings.HasPrefix(content, "# Questions\n\nIntro.\n\n<!-- rvn:snapshot questions updated=2026-03-01T09:00:00Z -->") {
+		t.Fatalf("expected block appended after content, got:\n%s", content)
+	}
+	if !strings.Contains(content, "_Updated 2026-03-01 09:00 · 1 result_\n\n- [[note/a]]\n<!-- /rvn:snapshot questions -->\n") {
+		t.Fatalf("unexpected block body:\n%s", content)
+	}
+
+	content += "\nOutro.\n"
+	second := first.Add(48 * time.Hour)
+	replaced := ApplySnapshotBlock(content, "questions", RenderSnapshotBlock("questions", nil, second))
+	if strings.Contains(replaced, "[[note/a]]") || strings.Count(replaced, "<!-- rvn:snapshot questions") != 1 {
+		t.Fatalf("expected block replaced in place, got:\n%s", replaced)
+	}
+	if !strings.Contains(replaced, "Intro.") || !strings.HasSuffix(replaced, "\nOutro.\n") {
+		t.Fatalf("expected surrounding content preserved, got:\n%s", replaced)
+	}
+
+	last, ok := SnapshotLastUpdated(replaced, "questions")
+	if !ok || !last.Equal(second) {
+		t.Fatalf("SnapshotLastUpdated() = %v, %v; want %v", last, ok, second)
+	}
+}
+
+func TestSnapshotDue(t *testing.T) {
+	t.Parallel()
+
+	rendered := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	content := RenderSnapshotBlock("q", nil, rendered)
+	daily := &config.QuerySnapshot{Target: "note/q", Schedule: "daily"}
+
+	if SnapshotDue(daily, content, "q", rendered.Add(23*time.Hour)) {
+		t.Fatal("expected daily snapshot not due after 23h")
+	}
+	if !SnapshotDue(daily, content, "q", rendered.Add(24*time.Hour)) {
+		t.Fatal("expected daily snapshot due after 24h")
+	}
+	if !SnapshotDue(daily, "no markers yet", "q", rendered) {
+		t.Fatal("expected never-rendered snapshot to be due")
+	}
+	if SnapshotDue(&config.QuerySnapshot{Target: "note/q"}, "no markers yet", "q", rendered) {
+		t.Fatal("expected unscheduled snapshot never due")
+	}
+}
+
+func TestValidateSnapshot(t *testing.T) {
+	t.Parallel()
+
+	if err := ValidateSnapshot("q", &config.QuerySnapshot{Schedule: "daily"}, nil); err == nil {
+		t.Fatal("expected missing target error")
+	}
+	if err := ValidateSnapshot("q", &config.QuerySnapshot{Target: "note/q"}, []string{"project"}); err == nil {
+		t.Fatal("expected declared args error")
+	}
+	if err := ValidateSnapshot("q", &config.QuerySnapshot{Target: "note/q", Schedule: "6h"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package readsvc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/query"
+)
+
+// DefaultInboxDir is the vault-relative directory listed by `rvn inbox` when
+// no directory is given.
+const DefaultInboxDir = "inbox/"
+
+// Inbox reasons explain why an object is listed in the inbox.
+const (
+	InboxReasonDirectory = "inbox_dir"
+	InboxReasonUntyped   = "untyped"
+)
+
+type ListInboxRequest struct {
+	// Dir is the vault-relative inbox directory. Empty uses DefaultInboxDir.
+	Dir string
+	// IncludeUntyped also lists untyped pages outside Dir.
+	IncludeUntyped bool
+	Limit          int
+	Offset         int
+}
+
+// InboxItem is an object waiting to be processed.
+type InboxItem struct {
+	Object model.Object
+	Reason string
+}
+
+type ListInboxResult struct {
+	Dir   string
+	Total int
+	Items []InboxItem
+}
+
+// ListInbox returns objects in the inbox directory, plus untyped pages
+// anywhere in the vault when IncludeUntyped is set, ordered by file path.
+// Daily notes are never inbox items.
+func ListInbox(rt *Runtime, req ListInboxRequest) (*ListInboxResult, error) {
+	if rt == nil || rt.DB == nil {
+		return nil, fmt.Errorf("runtime with database is required")
+	}
+	if req.Limit < 0 {
+		return nil, fmt.Errorf("limit must be >= 0")
+	}
+	if req.Offset < 0 {
+		return nil, fmt.Errorf("offset must be >= 0")
+	}
+
+	dir := paths.NormalizeDirRoot(strings.TrimSpace(req.Dir))
+	if dir == "" {
+		dir = DefaultInboxDir
+	}
+
+	result, err := ExecuteQuery(rt, ExecuteQueryRequest{QueryString: "type:" + query.AnyType})
+	if err != nil {
+		return nil, err
+	}
+
+	var items []InboxItem
+	for _, obj := range result.Objects {
+		switch {
+		case obj.Type == "date":
+			continue
+		case strings.HasPrefix(obj.FilePath, dir):
+			items = append(items, InboxItem{Object: obj, Reason: InboxReasonDirectory})
+		case req.IncludeUntyped && obj.Type == "page":
+			items = append(items, InboxItem{Object: obj, Reason: InboxReasonUntyped})
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Object.FilePath < items[j].Object.FilePath
+	})
+
+	total := len(items)
+	start := min(req.Offset, total)
+	end := total
+	if req.Limit > 0 {
+		end = min(start+req.Limit, total)
+	}
+
+	return &ListInboxResult{
+		Dir:   dir,
+		Total: total,
+		Items: items[start:end],
+	}, nil
+}
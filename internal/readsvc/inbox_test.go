@@ -0,0 +1,108 @@
+package readsvc
+
+import (
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/index"
+)
+
+func TestListInbox(t *testing.T) {
+	t.Parallel()
+
+	db, err := index.OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open in-memory db: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.DB().Exec(`
+		INSERT INTO objects (id, file_path, type, line_start, fields) VALUES
+			('inbox/idea', 'inbox/idea.md', 'page', 1, '{}'),
+			('inbox/call-notes', 'inbox/call-notes.md', 'meeting', 1, '{}'),
+			('capture/link', 'capture/link.md', 'page', 1, '{}'),
+			('notes/loose', 'notes/loose.md', 'page', 1, '{}'),
+			('projects/raven', 'projects/raven.md', 'project', 1, '{}'),
+			('daily/2025-02-01', 'daily/2025-02-01.md', 'date', 1, '{}')
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed objects: %v", err)
+	}
+	rt := &Runtime{VaultPath: t.TempDir(), DB: db}
+
+	tests := []struct {
+		name      string
+		req       ListInboxRequest
+		wantDir   string
+		wantTotal int
+		wantIDs   []string
+	}{
+		{
+			name:      "default inbox directory",
+			req:       ListInboxRequest{},
+			wantDir:   "inbox/",
+			wantTotal: 2,
+			wantIDs:   []string{"inbox/call-notes", "inbox/idea"},
+		},
+		{
+			name:      "custom directory without trailing slash",
+			req:       ListInboxRequest{Dir: "capture"},
+			wantDir:   "capture/",
+			wantTotal: 1,
+			wantIDs:   []string{"capture/link"},
+		},
+		{
+			name:      "untyped pages outside the inbox",
+			req:       ListInboxRequest{IncludeUntyped: true},
+			wantDir:   "inbox/",
+			wantTotal: 4,
+			wantIDs:   []string{"capture/link", "inbox/call-notes", "inbox/idea", "notes/loose"},
+		},
+		{
+			name:      "paginated",
+			req:       ListInboxRequest{IncludeUntyped: true, Limit: 2, Offset: 1},
+			wantDir:   "inbox/",
+			wantTotal: 4,
+			wantIDs:   []string{"inbox/call-notes", "inbox/idea"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ListInbox(rt, tc.req)
+			if err != nil {
+				t.Fatalf("ListInbox: %v", err)
+			}
+			if result.Dir != tc.wantDir || result.Total != tc.wantTotal {
+				t.Fatalf("dir/total = %q/%d, want %q/%d", result.Dir, result.Total, tc.wantDir, tc.wantTotal)
+			}
+			var ids []string
+			for _, item := range result.Items {
+				ids = append(ids, item.Object.ID)
+			}
+			if len(ids) != len(tc.wantIDs) {
+				t.Fatalf("ids = %v, want %v", ids, tc.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tc.wantIDs[i] {
+					t.Fatalf("ids = %v, want %v", ids, tc.wantIDs)
+				}
+			}
+		})
+	}
+
+	result, err := ListInbox(rt, ListInboxRequest{IncludeUntyped: true})
+	if err != nil {
+		t.Fatalf("ListInbox: %v", err)
+	}
+	reasons := map[string]string{}
+	for _, item := range result.Items {
+		reasons[item.Object.ID] = item.Reason
+	}
+	if reasons["inbox/idea"] != InboxReasonDirectory || reasons["notes/loose"] != InboxReasonUntyped {
+		t.Fatalf("unexpected reasons: %#v", reasons)
+	}
+
+	if _, err := ListInbox(rt, ListInboxRequest{Limit: -1}); err == nil {
+		t.Fatal("expected limit validation error")
+	}
+}
@@ -2,8 +2,12 @@ package readsvc
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 func Search(rt *Runtime, queryStr, objectType string, limit int) ([]model.SearchMatch, error) {
@@ -29,3 +33,103 @@ func Outlinks(rt *Runtime, source string) ([]model.Reference, error) {
 	}
 	return rt.DB.Outlinks(source)
 }
+
+// AddBacklinkContext fills in the line text, containing object, and containing
+// section for each backlink. Sources without an object type of their own (such
+// as section IDs) take the type of their containing object.
+func AddBacklinkContext(rt *Runtime, links []model.Reference) error {
+	if rt == nil || rt.DB == nil {
+		return fmt.Errorf("runtime with database is required")
+	}
+
+	fileLines := make(map[string][]string)
+	objectTypes := make(map[string]string)
+	for i := range links {
+		link := &links[i]
+		link.ObjectID = link.SourceID
+		if idx := strings.Index(link.ObjectID, "#"); idx > 0 {
+			link.ObjectID = link.ObjectID[:idx]
+		}
+		if link.SourceType == "" {
+			objectType, ok := objectTypes[link.ObjectID]
+			if !ok {
+				obj, err := rt.DB.GetObject(link.ObjectID)
+				if err != nil {
+					return err
+				}
+				if obj != nil {
+					objectType = obj.Type
+				}
+				objectTypes[link.ObjectID] = objectType
+			}
+			link.SourceType = objectType
+		}
+
+		if link.Line == nil || *link.Line <= 0 {
+			continue
+		}
+		lines, ok := fileLines[link.FilePath]
+		if !ok {
+			// A file that cannot be read just has no context; the index may be
+			// ahead of or behind the working tree.
+			if content, err := vaultcrypt.ReadFile(filepath.Join(rt.VaultPath, link.FilePath)); err == nil {
+				lines = strings.Split(string(content), "\n")
+			}
+			fileLines[link.FilePath] = lines
+		}
+		if *link.Line <= len(lines) {
+			link.Context = backlinkContextLine(lines, *link.Line, link.TargetRaw)
+		}
+
+		section, err := rt.DB.SectionAtLine(link.FilePath, *link.Line)
+		if err != nil {
+			return err
+		}
+		if section != nil {
+			link.SectionID = section.ID
+			link.SectionTitle = section.Title
+		}
+	}
+	return nil
+}
+
+// backlinkContextLine returns the trimmed text of line. Frontmatter refs are
+// indexed at the object's first line, so when that line does not mention the
+// target, the frontmatter line that does is used instead.
+func backlinkContextLine(lines []string, line int, targetRaw string) string {
+	text := strings.TrimSpace(lines[line-1])
+	if targetRaw == "" || strings.Contains(text, targetRaw) || text != "---" {
+		return text
+	}
+	for _, candidate := range lines[line:] {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "---" {
+			break
+		}
+		if strings.Contains(candidate, targetRaw) {
+			return candidate
+		}
+	}
+	return text
+}
+
+// GroupReferencesBySourceType groups references by source type, ordered by
+// type name. References without a source type are grouped under "".
+func GroupReferencesBySourceType(links []model.Reference) []model.ReferenceTypeGroup {
+	groups := make([]model.ReferenceTypeGroup, 0)
+	index := make(map[string]int)
+	for _, link := range links {
+		i, ok := index[link.SourceType]
+		if !ok {
+			i = len(groups)
+			index[link.SourceType] = i
+			groups = append(groups, model.ReferenceTypeGroup{SourceType: link.SourceType, Items: []model.Reference{}})
+		}
+		groups[i].Items = append(groups[i].Items, link)
+		groups[i].Count++
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].SourceType < groups[j].SourceType
+	})
+	return groups
+}
@@ -0,0 +1,68 @@
+package readsvc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBacklinksWithContext(t *testing.T) {
+	t.Parallel()
+
+	rt := seededSectionRuntime(t)
+	projectPath := filepath.Join(rt.VaultPath, "projects", "raven.md")
+	if err := os.MkdirAll(filepath.Dir(projectPath), 0o755); err != nil {
+		t.Fatalf("create project directory: %v", err)
+	}
+	if err := os.WriteFile(projectPath, []byte("---\ntype: project\nowner: people/freya\n---\nbody\n"), 0o644); err != nil {
+		t.Fatalf("write project: %v", err)
+	}
+	notePath := filepath.Join(rt.VaultPath, "note", "example.md")
+	if err := os.WriteFile(notePath, []byte("# Parent\nintro\n## Child\nask [[people/freya]]\n# Next\nnext\n"), 0o644); err != nil {
+		t.Fatalf("write note: %v", err)
+	}
+
+	_, err := rt.DB.DB().Exec(`
+		INSERT INTO objects (id, file_path, type, line_start, fields) VALUES
+			('projects/raven', 'projects/raven.md', 'project', 1, '{}');
+
+		INSERT INTO refs (source_id, target_id, target_raw, file_path, line_number) VALUES
+			('projects/raven', 'people/freya', 'people/freya', 'projects/raven.md', 1),
+			('note/example#child', 'people/freya', 'people/freya', 'note/example.md', 4);
+	`)
+	if err != nil {
+		t.Fatalf("seed refs: %v", err)
+	}
+
+	links, err := Backlinks(rt, "people/freya")
+	if err != nil {
+		t.Fatalf("Backlinks failed: %v", err)
+	}
+	if err := AddBacklinkContext(rt, links); err != nil {
+		t.Fatalf("AddBacklinkContext failed: %v", err)
+	}
+	if len(links) != 2 {
+		t.Fatalf("links = %#v, want 2", links)
+	}
+
+	for _, link := range links {
+		switch link.FilePath {
+		case "projects/raven.md":
+			if link.Context != "owner: people/freya" || link.ObjectID != "projects/raven" || link.SectionID != "" {
+				t.Fatalf("frontmatter backlink = %#v", link)
+			}
+		case "note/example.md":
+			if link.Context != "ask [[people/freya]]" || link.ObjectID != "note/example" || link.SourceType != "note" {
+				t.Fatalf("body backlink = %#v", link)
+			}
+			if link.SectionID != "note/example#child" || link.SectionTitle != "Child" {
+				t.Fatalf("section = %q %q, want innermost Child section", link.SectionID, link.SectionTitle)
+			}
+		}
+	}
+
+	groups := GroupReferencesBySourceType(links)
+	if len(groups) != 2 || groups[0].SourceType != "note" || groups[1].SourceType != "project" || groups[0].Count != 1 {
+		t.Fatalf("groups = %#v, want note then project", groups)
+	}
+}
@@ -0,0 +1,210 @@
+package readsvc
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/query"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+type ListObjectsRequest struct {
+	// TypeName limits the listing to one type. Empty lists objects of every
+	// indexed type.
+	TypeName string
+	// Sort is the field to order by (".name" or "name"). Empty orders by
+	// display name.
+	Sort       string
+	Descending bool
+	Limit      int
+	Offset     int
+}
+
+type ListObjectsResult struct {
+	TypeName string
+	Sort     string
+	Total    int
+	Objects  []model.Object
+}
+
+// ListSortError reports a --sort field that the listed type does not define.
+type ListSortError struct {
+	Field    string
+	TypeName string
+	Fields   []string
+}
+
+func (e *ListSortError) Error() string {
+	return fmt.Sprintf("type '%s' has no field '%s'", e.TypeName, e.Field)
+}
+
+// ListObjects returns indexed objects sorted by a field, then paginated.
+// Sorting happens before pagination, so Limit/Offset page through the sorted
+// order.
+func ListObjects(rt *Runtime, req ListObjectsRequest) (*ListObjectsResult, error) {
+	if rt == nil || rt.DB == nil {
+		return nil, fmt.Errorf("runtime with database is required")
+	}
+	if req.Limit < 0 {
+		return nil, fmt.Errorf("limit must be >= 0")
+	}
+	if req.Offset < 0 {
+		return nil, fmt.Errorf("offset must be >= 0")
+	}
+
+	typeName := strings.TrimSpace(req.TypeName)
+	sortField := strings.TrimPrefix(strings.TrimSpace(req.Sort), ".")
+	if sortField == "" {
+		sortField = query.DisplayNameField
+	}
+	if err := validateListSortField(rt.Schema, typeName, sortField); err != nil {
+		return nil, err
+	}
+
+	typeNames := []string{typeName}
+	if typeName == "" {
+		usage, err := rt.DB.TypeUsage()
+		if err != nil {
+			return nil, err
+		}
+		typeNames = typeNames[:0]
+		for name := range usage {
+			if name != "section" {
+				typeNames = append(typeNames, name)
+			}
+		}
+		sort.Strings(typeNames)
+	}
+
+	var objects []model.Object
+	for _, name := range typeNames {
+		result, err := ExecuteQuery(rt, ExecuteQueryRequest{QueryString: "type:" + name})
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, result.Objects...)
+	}
+
+	keys := make([]interface{}, len(objects))
+	for i, obj := range objects {
+		keys[i] = listSortKey(rt.Schema, obj, sortField)
+	}
+	order := make([]int, len(objects))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		cmp := compareListSortKeys(keys[a], keys[b], req.Descending)
+		if cmp != 0 {
+			return cmp < 0
+		}
+		return objects[a].ID < objects[b].ID
+	})
+
+	sorted := make([]model.Object, 0, len(objects))
+	for _, idx := range order {
+		sorted = append(sorted, objects[idx])
+	}
+
+	total := len(sorted)
+	start := min(req.Offset, total)
+	end := total
+	if req.Limit > 0 {
+		end = min(start+req.Limit, total)
+	}
+
+	return &ListObjectsResult{
+		TypeName: typeName,
+		Sort:     "." + sortField,
+		Total:    total,
+		Objects:  sorted[start:end],
+	}, nil
+}
+
+func validateListSortField(sch *schema.Schema, typeName, field string) error {
+	if field == "id" || field == query.DisplayNameField || sch == nil || typeName == "" {
+		return nil
+	}
+	typeDef := sch.Types[typeName]
+	if typeDef == nil || typeDef.FieldAtPath(field) != nil {
+		return nil
+	}
+	fields := make([]string, 0, len(typeDef.Fields))
+	for name := range typeDef.Fields {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return &ListSortError{Field: field, TypeName: typeName, Fields: fields}
+}
+
+// listSortKey returns the value an object sorts by. "id" and "display_name"
+// are pseudo-fields unless the type defines a real field with that name.
+func listSortKey(sch *schema.Schema, obj model.Object, field string) interface{} {
+	if value, ok := schema.ValueAtPath(obj.Fields, field); ok {
+		return value
+	}
+	switch field {
+	case "id":
+		return obj.ID
+	case query.DisplayNameField:
+		if sch != nil {
+			if typeDef := sch.Types[obj.Type]; typeDef != nil && typeDef.NameField != "" {
+				if name, ok := obj.Fields[typeDef.NameField].(string); ok && name != "" {
+					return name
+				}
+			}
+		}
+		return filepath.Base(obj.ID)
+	}
+	return nil
+}
+
+// compareListSortKeys orders numbers numerically and everything else as
+// case-insensitive text. Missing values always sort last.
+func compareListSortKeys(a, b interface{}, descending bool) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return 1
+		default:
+			return -1
+		}
+	}
+
+	cmp := 0
+	af, aNum := listSortNumber(a)
+	bf, bNum := listSortNumber(b)
+	if aNum && bNum {
+		switch {
+		case af < bf:
+			cmp = -1
+		case af > bf:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(strings.ToLower(fmt.Sprint(a)), strings.ToLower(fmt.Sprint(b)))
+	}
+	if descending {
+		return -cmp
+	}
+	return cmp
+}
+
+func listSortNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
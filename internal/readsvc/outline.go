@@ -0,0 +1,99 @@
+package readsvc
+
+import (
+	"github.com/aidanlsb/raven/internal/parser"
+)
+
+// ReadSection is one heading-derived section in a read --sections outline.
+// Sections are addressable objects (file-id#slug), so their IDs can be passed
+// straight to edit, set, or read.
+type ReadSection struct {
+	ID             string      `json:"id"`
+	Title          string      `json:"title"`
+	Level          int         `json:"level"`
+	Parent         string      `json:"parent,omitempty"`
+	LineStart      int         `json:"line_start"`
+	LineEnd        int         `json:"line_end"`
+	SubtreeLineEnd int         `json:"subtree_line_end"`
+	Traits         []ReadTrait `json:"traits"`
+}
+
+// ReadTrait is a trait annotation placed in an outline by line.
+type ReadTrait struct {
+	Trait   string `json:"trait"`
+	Value   string `json:"value,omitempty"`
+	Content string `json:"content,omitempty"`
+	Line    int    `json:"line"`
+}
+
+// ReadOutline is the structured outline returned by read --sections. Traits
+// holds traits that sit before the first heading; section traits are listed
+// on their section.
+type ReadOutline struct {
+	Type     string        `json:"type,omitempty"`
+	Traits   []ReadTrait   `json:"traits"`
+	Sections []ReadSection `json:"sections"`
+}
+
+// buildOutline parses content and returns its outline. When sectionID is set,
+// only that section and its subsections are included, and Traits is empty.
+func buildOutline(content, filePath, vaultPath, sectionID string, opts *parser.ParseOptions, lineCount int) (*ReadOutline, error) {
+	doc, err := parser.ParseDocumentWithOptions(content, filePath, vaultPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	outline := &ReadOutline{
+		Traits:   []ReadTrait{},
+		Sections: []ReadSection{},
+	}
+	if len(doc.Objects) > 0 {
+		outline.Type = doc.Objects[0].ObjectType
+	}
+
+	included := make(map[string]int, len(doc.Sections))
+	for _, section := range doc.Sections {
+		parent := ""
+		if section.ParentSectionID != nil {
+			parent = *section.ParentSectionID
+		}
+		if sectionID != "" && section.ID != sectionID {
+			if _, ok := included[parent]; parent == "" || !ok {
+				continue
+			}
+		}
+		entry := ReadSection{
+			ID:             section.ID,
+			Title:          section.Title,
+			Level:          section.Level,
+			Parent:         parent,
+			LineStart:      section.LineStart,
+			LineEnd:        lineCount,
+			SubtreeLineEnd: lineCount,
+			Traits:         []ReadTrait{},
+		}
+		if section.LineEnd != nil {
+			entry.LineEnd = *section.LineEnd
+		}
+		if section.SubtreeLineEnd != nil {
+			entry.SubtreeLineEnd = *section.SubtreeLineEnd
+		}
+		included[section.ID] = len(outline.Sections)
+		outline.Sections = append(outline.Sections, entry)
+	}
+
+	for _, trait := range doc.Traits {
+		entry := ReadTrait{
+			Trait:   trait.TraitType,
+			Value:   trait.ValueString(),
+			Content: trait.Content,
+			Line:    trait.Line,
+		}
+		if idx, ok := included[trait.ParentObjectID]; ok {
+			outline.Sections[idx].Traits = append(outline.Sections[idx].Traits, entry)
+		} else if sectionID == "" {
+			outline.Traits = append(outline.Traits, entry)
+		}
+	}
+	return outline, nil
+}
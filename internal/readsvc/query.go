@@ -13,6 +13,8 @@ type ExecuteQueryRequest struct {
 	Limit       int
 	Offset      int
 	CountOnly   bool
+	// ExplainMatches fills ExecuteQueryResult.Matches for the returned rows.
+	ExplainMatches bool
 }
 
 type ExecuteQueryResult struct {
@@ -27,6 +29,11 @@ type ExecuteQueryResult struct {
 	Traits    []model.Trait
 	Assets    []model.Asset
 	Sections  []model.Section
+	// Degraded lists query features that ran on a LIKE-based fallback because
+	// the SQLite build lacks FTS5 or REGEXP (see query.DegradedFeatures).
+	Degraded []string
+	// Matches explains, per returned row ID, which predicates matched.
+	Matches map[string][]query.Match
 }
 
 func ExecuteQuery(rt *Runtime, req ExecuteQueryRequest) (*ExecuteQueryResult, error) {
@@ -40,7 +47,7 @@ func ExecuteQuery(rt *Runtime, req ExecuteQueryRequest) (*ExecuteQueryResult, er
 		return nil, fmt.Errorf("offset must be >= 0")
 	}
 
-	q, err := query.Parse(req.QueryString)
+	q, text, err := query.ParseWithText(req.QueryString)
 	if err != nil {
 		return nil, err
 	}
@@ -52,10 +59,41 @@ func ExecuteQuery(rt *Runtime, req ExecuteQueryRequest) (*ExecuteQueryResult, er
 		}
 	}
 
-	executor := query.NewExecutor(rt.DB.DB())
-	executor.SetDailyDirectory(rt.VaultCfg.GetDailyDirectory())
-	executor.SetSchema(rt.Schema)
+	executor := rt.QueryExecutor()
+	result, err := executeParsedQuery(executor, q, req)
+	if err != nil || !req.ExplainMatches || req.CountOnly {
+		return result, err
+	}
+
+	result.Matches, err = executor.ExplainMatches(q, text, result.RowIDs())
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RowIDs returns the IDs of the returned rows, in order.
+func (r *ExecuteQueryResult) RowIDs() []string {
+	if r.IDs != nil {
+		return r.IDs
+	}
+	var ids []string
+	for _, obj := range r.Objects {
+		ids = append(ids, obj.ID)
+	}
+	for _, trait := range r.Traits {
+		ids = append(ids, trait.ID)
+	}
+	for _, asset := range r.Assets {
+		ids = append(ids, asset.ID)
+	}
+	for _, section := range r.Sections {
+		ids = append(ids, section.ID)
+	}
+	return ids
+}
 
+func executeParsedQuery(executor *query.Executor, q *query.Query, req ExecuteQueryRequest) (*ExecuteQueryResult, error) {
 	queryKind := "trait"
 	if q.Type == query.QueryTypeObject {
 		queryKind = "type"
@@ -69,6 +107,7 @@ func ExecuteQuery(rt *Runtime, req ExecuteQueryRequest) (*ExecuteQueryResult, er
 		TypeName:  q.TypeName,
 		Offset:    req.Offset,
 		Limit:     req.Limit,
+		Degraded:  query.DegradedFeatures(q, executor.Capabilities()),
 	}
 	paginated := req.Limit > 0 || req.Offset > 0
 
@@ -0,0 +1,299 @@
+package readsvc
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// queryBlockLanguage is the fence info string that marks an embedded query.
+const queryBlockLanguage = "raven-query"
+
+// queryBlockDefaultLimit caps the rows a query block renders when it sets no
+// limit of its own.
+const queryBlockDefaultLimit = 100
+
+// ReadQueryBlock reports one ```raven-query block rendered by read --render.
+type ReadQueryBlock struct {
+	Line  int    `json:"line"`
+	Query string `json:"query"`
+	Count int    `json:"count"`
+	Error string `json:"error,omitempty"`
+}
+
+var queryBlockOptionPattern = regexp.MustCompile(`^(format|fields|limit):\s+(.*)$`)
+
+// queryBlock is a parsed ```raven-query block. The body is the query string
+// or a saved query name, optionally preceded by format:, fields:, and limit:
+// option lines.
+type queryBlock struct {
+	query  string
+	format string
+	fields []string
+	limit  int
+}
+
+// RenderQueryBlocks replaces each ```raven-query fenced block in content with
+// the block's live results, as a markdown list or table. A block that fails
+// is replaced with a quoted error so the rest of the note still renders.
+func RenderQueryBlocks(rt *Runtime, content string) (string, []ReadQueryBlock) {
+	lines := strings.SplitAfter(content, "\n")
+	blocks := []ReadQueryBlock{}
+
+	var out strings.Builder
+	for i := 0; i < len(lines); i++ {
+		fence, info := fenceOpening(lines[i])
+		if fence == "" {
+			out.WriteString(lines[i])
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && !isFenceClose(lines[end], fence) {
+			end++
+		}
+		if end >= len(lines) {
+			// An unterminated fence runs to the end of the file.
+			out.WriteString(strings.Join(lines[i:], ""))
+			break
+		}
+		if info != queryBlockLanguage {
+			out.WriteString(strings.Join(lines[i:end+1], ""))
+			i = end
+			continue
+		}
+
+		block := parseQueryBlock(lines[i+1 : end])
+		report := ReadQueryBlock{Line: i + 1, Query: block.query}
+		rendered, count, err := renderQueryBlock(rt, block)
+		if err != nil {
+			report.Error = err.Error()
+			rendered = fmt.Sprintf("> raven-query error: %s\n", err)
+		}
+		report.Count = count
+		blocks = append(blocks, report)
+
+		if !strings.HasSuffix(lines[end], "\n") {
+			rendered = strings.TrimSuffix(rendered, "\n")
+		}
+		out.WriteString(rendered)
+		i = end
+	}
+	return out.String(), blocks
+}
+
+// fenceOpening returns the fence marker and the first word of the info
+// string when line opens a fenced code block.
+func fenceOpening(line string) (string, string) {
+	trimmed := strings.TrimRight(line, "\r\n")
+	indent := len(trimmed) - len(strings.TrimLeft(trimmed, " "))
+	if indent > 3 {
+		return "", ""
+	}
+	trimmed = trimmed[indent:]
+	for _, ch := range []byte{'`', '~'} {
+		n := 0
+		for n < len(trimmed) && trimmed[n] == ch {
+			n++
+		}
+		if n < 3 {
+			continue
+		}
+		info := strings.TrimSpace(trimmed[n:])
+		if ch == '`' && strings.Contains(info, "`") {
+			return "", ""
+		}
+		if fields := strings.Fields(info); len(fields) > 0 {
+			return trimmed[:n], fields[0]
+		}
+		return trimmed[:n], ""
+	}
+	return "", ""
+}
+
+func isFenceClose(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+func parseQueryBlock(body []string) queryBlock {
+	block := queryBlock{}
+	var queryLines []string
+	for _, line := range body {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if match := queryBlockOptionPattern.FindStringSubmatch(line); match != nil && len(queryLines) == 0 {
+			value := strings.TrimSpace(match[2])
+			switch match[1] {
+			case "format":
+				block.format = strings.ToLower(value)
+			case "fields":
+				for _, field := range strings.Split(value, ",") {
+					if field = strings.TrimPrefix(strings.TrimSpace(field), "."); field != "" {
+						block.fields = append(block.fields, field)
+					}
+				}
+			case "limit":
+				block.limit, _ = strconv.Atoi(value)
+				if block.limit <= 0 {
+					block.limit = -1
+				}
+			}
+			continue
+		}
+		queryLines = append(queryLines, line)
+	}
+	block.query = strings.Join(queryLines, " ")
+	return block
+}
+
+func renderQueryBlock(rt *Runtime, block queryBlock) (string, int, error) {
+	if block.query == "" {
+		return "", 0, fmt.Errorf("query block is empty")
+	}
+	switch block.format {
+	case "", "list", "table":
+	default:
+		return "", 0, fmt.Errorf("unknown format %q (use list or table)", block.format)
+	}
+	if block.limit < 0 {
+		return "", 0, fmt.Errorf("limit must be a positive number")
+	}
+
+	queryString := block.query
+	if saved, ok := rt.VaultCfg.Queries[queryString]; ok && saved != nil {
+		if len(saved.Args) > 0 {
+			return "", 0, fmt.Errorf("saved query %q takes args; use its query string instead", queryString)
+		}
+		queryString = saved.Query
+	}
+	limit := block.limit
+	if limit == 0 {
+		limit = queryBlockDefaultLimit
+	}
+	result, err := ExecuteQuery(rt, ExecuteQueryRequest{QueryString: queryString, Limit: limit})
+	if err != nil {
+		return "", 0, err
+	}
+
+	count := len(result.Objects) + len(result.Traits) + len(result.Sections) + len(result.Assets)
+	if count == 0 {
+		return "_No results_\n", 0, nil
+	}
+	format := block.format
+	if format == "" && len(block.fields) > 0 {
+		format = "table"
+	}
+	if format == "table" && (len(result.Objects) > 0 || len(result.Traits) > 0) {
+		return renderQueryTable(result, block.fields), count, nil
+	}
+
+	var b strings.Builder
+	for _, line := range QueryResultLines(result) {
+		b.WriteString("- ")
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String(), count, nil
+}
+
+// QueryResultLines renders query results as markdown list items: wikilinks
+// for objects, sections and assets, and trait content linked to its parent.
+func QueryResultLines(result *ExecuteQueryResult) []string {
+	var lines []string
+	for _, object := range result.Objects {
+		lines = append(lines, "[["+object.ID+"]]")
+	}
+	for _, section := range result.Sections {
+		lines = append(lines, "[["+section.ID+"]]")
+	}
+	for _, asset := range result.Assets {
+		lines = append(lines, "[["+asset.ID+"]]")
+	}
+	for _, trait := range result.Traits {
+		content := strings.TrimSpace(trait.Content)
+		if content == "" {
+			content = "@" + trait.TraitType
+		}
+		lines = append(lines, fmt.Sprintf("%s ([[%s]])", content, trait.ParentObjectID))
+	}
+	return lines
+}
+
+// renderQueryTable renders object or trait results as a markdown table.
+// Object tables show the given fields, or every field set on a result.
+func renderQueryTable(result *ExecuteQueryResult, fields []string) string {
+	var rows [][]string
+	var header []string
+	if len(result.Objects) > 0 {
+		if len(fields) == 0 {
+			seen := map[string]bool{}
+			for _, object := range result.Objects {
+				for name := range object.Fields {
+					if !seen[name] {
+						seen[name] = true
+						fields = append(fields, name)
+					}
+				}
+			}
+			sort.Strings(fields)
+		}
+		header = append([]string{"Object"}, fields...)
+		for _, object := range result.Objects {
+			row := []string{"[[" + object.ID + "]]"}
+			for _, field := range fields {
+				row = append(row, formatQueryCell(object.Fields[field]))
+			}
+			rows = append(rows, row)
+		}
+	} else {
+		header = []string{"Trait", "Value", "Content", "Object"}
+		for _, trait := range result.Traits {
+			value := ""
+			if trait.Value != nil {
+				value = *trait.Value
+			}
+			rows = append(rows, []string{"@" + trait.TraitType, value, strings.TrimSpace(trait.Content), "[[" + trait.ParentObjectID + "]]"})
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(cells []string) {
+		b.WriteString("|")
+		for _, cell := range cells {
+			b.WriteString(" ")
+			b.WriteString(strings.ReplaceAll(cell, "|", "\\|"))
+			b.WriteString(" |")
+		}
+		b.WriteString("\n")
+	}
+	writeRow(header)
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeRow(separator)
+	for _, row := range rows {
+		writeRow(row)
+	}
+	return b.String()
+}
+
+func formatQueryCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, formatQueryCell(item))
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return strings.ReplaceAll(fmt.Sprint(v), "\n", " ")
+	}
+}
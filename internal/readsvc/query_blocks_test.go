@@ -0,0 +1,78 @@
+package readsvc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func TestRenderQueryBlocks(t *testing.T) {
+	t.Parallel()
+
+	db, err := index.OpenInMemory()
+	if err != nil {
+		t.Fatalf("open in-memory index: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.DB().Exec(`
+		INSERT INTO objects (id, file_path, type, line_start, fields) VALUES
+			('projects/site', 'projects/site.md', 'project', 1, '{"status":"active","tags":["web","q1"]}'),
+			('projects/app', 'projects/app.md', 'project', 1, '{"status":"paused"}');
+	`)
+	if err != nil {
+		t.Fatalf("seed index: %v", err)
+	}
+	sch := schema.New()
+	sch.Types["project"] = &schema.TypeDefinition{
+		Fields: map[string]*schema.FieldDefinition{"status": {Type: schema.FieldTypeString}},
+	}
+	rt := &Runtime{
+		VaultPath: t.TempDir(),
+		VaultCfg: &config.VaultConfig{Queries: map[string]*config.SavedQuery{
+			"active": {Query: "type:project .status==active"},
+		}},
+		Schema: sch,
+		DB:     db,
+	}
+
+	content := strings.Join([]string{
+		"# Dashboard",
+		"```raven-query",
+		"active",
+		"```",
+		"```raven-query",
+		"fields: status, tags",
+		"type:project",
+		"```",
+		"```raven-query",
+		"type:missing",
+		"```",
+		"````markdown",
+		"```raven-query",
+		"type:project",
+		"```",
+		"````",
+		"",
+	}, "\n")
+
+	rendered, blocks := RenderQueryBlocks(rt, content)
+	for _, want := range []string{
+		"# Dashboard\n- [[projects/site]]\n| Object | status | tags |",
+		"| [[projects/site]] | active | web, q1 |",
+		"> raven-query error: ",
+		"````markdown\n```raven-query\ntype:project\n```\n````\n",
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("rendered content missing %q:\n%s", want, rendered)
+		}
+	}
+	if len(blocks) != 3 {
+		t.Fatalf("blocks = %#v, want 3", blocks)
+	}
+	if blocks[0].Line != 2 || blocks[0].Count != 1 || blocks[1].Count != 2 || blocks[2].Error == "" {
+		t.Fatalf("blocks = %#v, want saved query with 1 result, table with 2, and an error", blocks)
+	}
+}
@@ -185,6 +185,25 @@ func TestExecuteQuery_AmbiguousISODateRefReturnsError(t *testing.T) {
 	}
 }
 
+func TestExecuteQuery_ReusesRuntimeExecutor(t *testing.T) {
+	t.Parallel()
+	rt := seededRuntime(t)
+
+	for i := 0; i < 2; i++ {
+		if _, err := ExecuteQuery(rt, ExecuteQueryRequest{QueryString: "trait:todo .value==open"}); err != nil {
+			t.Fatalf("ExecuteQuery run %d: %v", i, err)
+		}
+	}
+	if stats := rt.QueryExecutor().CacheStats(); stats.Statements != 1 || stats.Hits != 1 {
+		t.Fatalf("CacheStats() = %+v, want the second run to reuse the statement", stats)
+	}
+
+	rt.ResetQueryCache()
+	if stats := rt.QueryExecutor().CacheStats(); stats.Statements != 0 {
+		t.Fatalf("CacheStats() after reset = %+v, want no statements", stats)
+	}
+}
+
 func seededRuntime(t *testing.T) *Runtime {
 	t.Helper()
 
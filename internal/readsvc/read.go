@@ -2,13 +2,13 @@ package readsvc
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/aidanlsb/raven/internal/index"
 	"github.com/aidanlsb/raven/internal/model"
 	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 	"github.com/aidanlsb/raven/internal/wikilink"
 )
 
@@ -18,6 +18,12 @@ type ReadRequest struct {
 	Lines     bool
 	StartLine int
 	EndLine   int
+
+	// Sections returns a structured outline instead of content.
+	Sections bool
+
+	// Render replaces ```raven-query blocks in the content with live results.
+	Render bool
 }
 
 type ReadLine struct {
@@ -48,6 +54,16 @@ type ReadResult struct {
 	References     []ReadReference
 	Backlinks      []ReadBacklinkGroup
 	BacklinksCount int
+
+	// Issues lists issue tracker references in the file when issue_refs is
+	// enabled in raven.yaml.
+	Issues []model.IssueRef
+
+	// Outline is set for read --sections.
+	Outline *ReadOutline
+
+	// QueryBlocks reports the query blocks rendered by read --render.
+	QueryBlocks []ReadQueryBlock
 }
 
 type InvalidLineRangeError struct {
@@ -93,7 +109,7 @@ func Read(rt *Runtime, req ReadRequest) (*ReadResult, error) {
 		return nil, err
 	}
 
-	contentBytes, err := os.ReadFile(resolved.FilePath)
+	contentBytes, err := vaultcrypt.ReadFile(resolved.FilePath)
 	if err != nil {
 		return nil, err
 	}
@@ -116,6 +132,20 @@ func Read(rt *Runtime, req ReadRequest) (*ReadResult, error) {
 		LineCount: lineCount,
 	}
 
+	if req.Sections {
+		sectionID := ""
+		if resolved.IsSection {
+			sectionID = resolved.ObjectID
+		}
+		outline, err := buildOutline(content, resolved.FilePath, rt.VaultPath, sectionID, buildParseOptions(rt.VaultCfg), lineCount)
+		if err != nil {
+			return nil, err
+		}
+		result.Content = ""
+		result.Outline = outline
+		return result, nil
+	}
+
 	explicitRange := req.StartLine > 0 || req.EndLine > 0
 	rangeStart := req.StartLine
 	rangeEnd := req.EndLine
@@ -163,9 +193,23 @@ func Read(rt *Runtime, req ReadRequest) (*ReadResult, error) {
 		return nil, err
 	}
 
+	if req.Render {
+		result.Content, result.QueryBlocks = RenderQueryBlocks(rt, result.Content)
+	}
+
 	result.References = refs
 	result.Backlinks = backlinkGroups
 	result.BacklinksCount = backlinksCount
+	if rt.VaultCfg.GetIssueRefs() != nil {
+		issues, err := rt.DB.IssueRefsForFile(filepath.ToSlash(relPath))
+		if err != nil {
+			return nil, err
+		}
+		if issues == nil {
+			issues = []model.IssueRef{}
+		}
+		result.Issues = issues
+	}
 	return result, nil
 }
 
@@ -324,7 +368,7 @@ func readBacklinksWithContext(rt *Runtime, targetObjectID string) ([]ReadBacklin
 		lines, ok := fileCache[filePath]
 		if !ok {
 			fullPath := filepath.Join(rt.VaultPath, filePath)
-			content, readErr := os.ReadFile(fullPath)
+			content, readErr := vaultcrypt.ReadFile(fullPath)
 			if readErr != nil {
 				out = append(out, ReadBacklinkGroup{
 					Source: filePath,
@@ -54,6 +54,46 @@ func TestReadSectionExplicitRangeWins(t *testing.T) {
 	}
 }
 
+func TestReadSectionsOutline(t *testing.T) {
+	t.Parallel()
+
+	rt := seededSectionRuntime(t)
+	notePath := filepath.Join(rt.VaultPath, "note", "example.md")
+	if err := os.WriteFile(notePath, []byte("# Parent\nintro\n## Child\n- @todo child\n# Next\nnext\n"), 0o644); err != nil {
+		t.Fatalf("write note: %v", err)
+	}
+
+	result, err := Read(rt, ReadRequest{Reference: "note/example", Sections: true})
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if result.Content != "" || result.Outline == nil {
+		t.Fatalf("result = %#v, want outline without content", result)
+	}
+	sections := result.Outline.Sections
+	if len(sections) != 3 {
+		t.Fatalf("sections = %#v, want 3", sections)
+	}
+	parent, child, next := sections[0], sections[1], sections[2]
+	if parent.ID != "note/example#parent" || parent.LineEnd != 2 || parent.SubtreeLineEnd != 4 {
+		t.Fatalf("parent = %#v, want lines 1-2 with subtree to 4", parent)
+	}
+	if child.Parent != parent.ID || len(child.Traits) != 1 || child.Traits[0].Trait != "todo" || child.Traits[0].Line != 4 {
+		t.Fatalf("child = %#v, want child of parent with @todo on line 4", child)
+	}
+	if next.LineEnd != 6 || next.SubtreeLineEnd != 6 {
+		t.Fatalf("next = %#v, want range ending at the last line", next)
+	}
+
+	result, err = Read(rt, ReadRequest{Reference: "note/example#parent", Sections: true})
+	if err != nil {
+		t.Fatalf("Read section failed: %v", err)
+	}
+	if got := len(result.Outline.Sections); got != 2 || result.Outline.Sections[1].ID != "note/example#child" {
+		t.Fatalf("section outline = %#v, want parent and child only", result.Outline.Sections)
+	}
+}
+
 func TestResolveOpenTargetIncludesSectionLine(t *testing.T) {
 	t.Parallel()
 
@@ -6,6 +6,7 @@ import (
 	"github.com/aidanlsb/raven/internal/config"
 	ravenignore "github.com/aidanlsb/raven/internal/ignore"
 	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/query"
 	"github.com/aidanlsb/raven/internal/schema"
 	"github.com/aidanlsb/raven/internal/vault"
 )
@@ -26,6 +27,50 @@ func CheckStaleness(rt *Runtime) (bool, []string, error) {
 	return len(staleFiles) > 0, staleFiles, nil
 }
 
+// Freshness is the result of CheckFreshness.
+type Freshness struct {
+	StaleFiles []string
+	Refreshed  int
+	// Unknown reports that staleness could not be checked for a plain read.
+	Unknown bool
+}
+
+// CheckFreshness compares the index with the vault before a read. With
+// refresh, changed files are always reindexed first. With requireFresh, they
+// are reindexed only when the index is stale. Files still stale afterwards
+// (for example because they fail to parse) are reported in StaleFiles.
+//
+// For plain reads (neither refresh nor requireFresh) a failed staleness check
+// is not an error: the read proceeds and the result is marked Unknown.
+func CheckFreshness(rt *Runtime, refresh, requireFresh bool) (*Freshness, error) {
+	result := &Freshness{}
+	if !refresh {
+		stale, staleFiles, err := CheckStaleness(rt)
+		if err != nil {
+			if !requireFresh {
+				result.Unknown = true
+				return result, nil
+			}
+			return nil, fmt.Errorf("failed to check index freshness: %w", err)
+		}
+		if !stale || !requireFresh {
+			result.StaleFiles = staleFiles
+			return result, nil
+		}
+	}
+
+	refreshed, err := SmartReindex(rt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh index: %w", err)
+	}
+	result.Refreshed = refreshed
+	_, result.StaleFiles, err = CheckStaleness(rt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check index freshness: %w", err)
+	}
+	return result, nil
+}
+
 func SmartReindex(rt *Runtime) (int, error) {
 	if rt == nil || rt.DB == nil {
 		return 0, fmt.Errorf("runtime with database is required")
@@ -51,7 +96,8 @@ func SmartReindex(rt *Runtime) (int, error) {
 		sch = loaded
 	}
 
-	if _, err := rt.DB.RemoveDeletedFiles(rt.VaultPath); err != nil {
+	removed, err := rt.DB.RemoveDeletedFiles(rt.VaultPath)
+	if err != nil {
 		return 0, err
 	}
 	matcher, err := excludeMatcher(rt)
@@ -84,6 +130,13 @@ func SmartReindex(rt *Runtime) (int, error) {
 	if err != nil {
 		return 0, err
 	}
+	if reindexed > 0 || len(removed) > 0 {
+		// Best effort: rvn reindex reports derived fields that fail to compute.
+		_ = query.MaterializeDerivedFields(rt.DB, sch, vaultCfg.GetDailyDirectory())
+	}
+	if reindexed > 0 {
+		rt.ResetQueryCache()
+	}
 
 	return reindexed, nil
 }
@@ -133,11 +186,23 @@ func filterIncluded(paths []string, matcher *ravenignore.Matcher) []string {
 }
 
 func buildParseOptions(vaultCfg *config.VaultConfig) *parser.ParseOptions {
-	if vaultCfg == nil || !vaultCfg.HasDirectoriesConfig() {
+	if vaultCfg == nil {
 		return nil
 	}
-	return &parser.ParseOptions{
-		ObjectsRoot: vaultCfg.GetObjectsRoot(),
-		PagesRoot:   vaultCfg.GetPagesRoot(),
+	dateFormats := vaultCfg.GetDateMentionFormats()
+	issueRefs := vaultCfg.GetIssueRefs()
+	hashtagTrait := vaultCfg.GetHashtagTrait()
+	if !vaultCfg.HasDirectoriesConfig() && dateFormats == nil && issueRefs == nil && hashtagTrait == "" {
+		return nil
+	}
+	opts := &parser.ParseOptions{
+		ObjectsRoot:        vaultCfg.GetObjectsRoot(),
+		PagesRoot:          vaultCfg.GetPagesRoot(),
+		DateMentionFormats: dateFormats,
+		HashtagTrait:       hashtagTrait,
+	}
+	if issueRefs != nil {
+		opts.IssueRefs = &parser.IssueRefOptions{JiraProjects: issueRefs.JiraProjects(), JiraURL: issueRefs.JiraURL()}
 	}
+	return opts
 }
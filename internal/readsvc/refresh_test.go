@@ -0,0 +1,38 @@
+package readsvc
+
+import (
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+)
+
+func TestCheckFreshnessReportsUnknownForPlainReads(t *testing.T) {
+	t.Parallel()
+
+	db, err := index.OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open in-memory db: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	// Break the staleness check without affecting object reads.
+	if _, err := db.DB().Exec("DROP TABLE assets"); err != nil {
+		t.Fatalf("drop assets: %v", err)
+	}
+	rt := &Runtime{VaultPath: t.TempDir(), VaultCfg: &config.VaultConfig{}, DB: db}
+
+	got, err := CheckFreshness(rt, false, false)
+	if err != nil {
+		t.Fatalf("CheckFreshness() plain read error = %v, want nil", err)
+	}
+	if !got.Unknown || len(got.StaleFiles) != 0 {
+		t.Fatalf("CheckFreshness() = %#v, want Unknown with no stale files", got)
+	}
+
+	if _, err := CheckFreshness(rt, false, true); err == nil {
+		t.Fatal("CheckFreshness() with requireFresh succeeded, want error")
+	}
+	if _, err := CheckFreshness(rt, true, false); err == nil {
+		t.Fatal("CheckFreshness() with refresh succeeded, want error")
+	}
+}
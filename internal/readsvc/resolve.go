@@ -11,6 +11,7 @@ import (
 	"github.com/aidanlsb/raven/internal/dates"
 	"github.com/aidanlsb/raven/internal/index"
 	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/redirects"
 	"github.com/aidanlsb/raven/internal/resolver"
 	"github.com/aidanlsb/raven/internal/vault"
 )
@@ -168,6 +169,9 @@ func (op *resolveOperation) resolveReference(reference string, allowMissing bool
 		if literalPathResult != nil {
 			return literalPathResult, nil
 		}
+		if redirected, err := op.resolveRedirect(ref, allowMissing); redirected != nil || err != nil {
+			return redirected, err
+		}
 		return nil, &RefNotFoundError{Reference: ref}
 	}
 
@@ -214,6 +218,33 @@ func (op *resolveOperation) resolveReference(reference string, allowMissing bool
 	return result, nil
 }
 
+// resolveRedirect resolves a reference to an ID that was moved or renamed by
+// following redirects.yaml. It returns nil when the ID has no redirect.
+func (op *resolveOperation) resolveRedirect(ref string, allowMissing bool) (*ResolveResult, error) {
+	baseRef, fragment, hasFragment := strings.Cut(ref, "#")
+	redirectMap, err := redirects.Load(op.rt.VaultPath)
+	if err != nil || len(redirectMap) == 0 {
+		return nil, nil
+	}
+	target, ok := redirects.Lookup(redirectMap, baseRef)
+	if !ok {
+		return nil, nil
+	}
+	if hasFragment {
+		target += "#" + fragment
+	}
+
+	result, err := op.resolveReference(target, allowMissing)
+	if err != nil {
+		if IsRefNotFound(err) {
+			return nil, &RefNotFoundError{Reference: ref, Detail: fmt.Sprintf("redirected to '%s', which was not found", target)}
+		}
+		return nil, err
+	}
+	result.MatchSource = "redirect"
+	return result, nil
+}
+
 func (op *resolveOperation) addSectionMetadata(result *ResolveResult) error {
 	if op == nil || result == nil || !result.IsSection {
 		return nil
@@ -5,6 +5,7 @@ import (
 
 	"github.com/aidanlsb/raven/internal/config"
 	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/query"
 	"github.com/aidanlsb/raven/internal/schema"
 )
 
@@ -17,6 +18,10 @@ type Runtime struct {
 	VaultCfg  *config.VaultConfig
 	Schema    *schema.Schema
 	DB        *index.Database
+
+	// executor is reused across queries on this runtime so repeated queries
+	// share prepared statements; SmartReindex resets its cache.
+	executor *query.Executor
 }
 
 func NewRuntime(vaultPath string, opts RuntimeOptions) (*Runtime, error) {
@@ -54,5 +59,26 @@ func (r *Runtime) Close() {
 	if r == nil || r.DB == nil {
 		return
 	}
+	r.ResetQueryCache()
 	_ = r.DB.Close()
 }
+
+// QueryExecutor returns the runtime's shared query executor, configured with
+// the current schema and daily directory.
+func (r *Runtime) QueryExecutor() *query.Executor {
+	if r.executor == nil {
+		r.executor = query.NewExecutor(r.DB.DB())
+	}
+	r.executor.SetDailyDirectory(r.VaultCfg.GetDailyDirectory())
+	r.executor.SetSchema(r.Schema)
+	return r.executor
+}
+
+// ResetQueryCache drops prepared statements and resolver state cached by the
+// runtime's query executor. Call it after the index changes.
+func (r *Runtime) ResetQueryCache() {
+	if r == nil || r.executor == nil {
+		return
+	}
+	r.executor.ResetCache()
+}
@@ -0,0 +1,353 @@
+package readsvc
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// DefaultTypeSuggestionLimit is the number of suggestions kept per page.
+const DefaultTypeSuggestionLimit = 3
+
+// Suggestion signals and their weights in the combined confidence.
+const (
+	SuggestSignalDirectory = "directory"
+	SuggestSignalFields    = "fields"
+	SuggestSignalTraits    = "traits"
+	SuggestSignalContent   = "content"
+)
+
+var suggestSignalWeights = map[string]float64{
+	SuggestSignalDirectory: 0.35,
+	SuggestSignalFields:    0.25,
+	SuggestSignalTraits:    0.15,
+	SuggestSignalContent:   0.25,
+}
+
+type SuggestTypesRequest struct {
+	// ObjectID limits suggestions to one untyped page. Empty means every
+	// untyped page in the vault.
+	ObjectID string
+	// Limit is the maximum number of suggestions per page. Zero uses
+	// DefaultTypeSuggestionLimit.
+	Limit int
+}
+
+// TypeSuggestion is one candidate type for an untyped page. Confidence is
+// the weighted sum of the per-signal scores, each in [0, 1].
+type TypeSuggestion struct {
+	Type       string
+	Confidence float64
+	Signals    map[string]float64
+	Reasons    []string
+}
+
+type PageTypeSuggestions struct {
+	ObjectID    string
+	FilePath    string
+	Suggestions []TypeSuggestion
+}
+
+type SuggestTypesResult struct {
+	Pages []PageTypeSuggestions
+}
+
+// NotUntypedError is returned when suggestions are requested for an object
+// that already has a type.
+type NotUntypedError struct {
+	ObjectID string
+	Type     string
+}
+
+func (e *NotUntypedError) Error() string {
+	return fmt.Sprintf("%s is already typed as '%s'", e.ObjectID, e.Type)
+}
+
+// typeProfile aggregates what typed objects of one type look like.
+type typeProfile struct {
+	name        string
+	defaultDir  string
+	fields      map[string]bool
+	traitCounts map[string]int
+	objectCount int
+	centroid    map[string]float64
+}
+
+// SuggestTypes proposes schema types for untyped pages from four signals:
+// the page's directory (a type's default_path, or the types of its typed
+// siblings), frontmatter keys matching a type's fields, traits commonly used
+// by a type, and content similarity to a type's existing objects.
+func SuggestTypes(rt *Runtime, req SuggestTypesRequest) (*SuggestTypesResult, error) {
+	if rt == nil || rt.DB == nil {
+		return nil, fmt.Errorf("runtime with database is required")
+	}
+	if req.Limit < 0 {
+		return nil, fmt.Errorf("limit must be >= 0")
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultTypeSuggestionLimit
+	}
+
+	objects, err := rt.DB.AllObjects()
+	if err != nil {
+		return nil, err
+	}
+	contents, err := rt.DB.ObjectContents()
+	if err != nil {
+		return nil, err
+	}
+	traitsByObject, err := rt.DB.TraitTypesByObject()
+	if err != nil {
+		return nil, err
+	}
+
+	var pages []model.Object
+	var typed []model.Object
+	for _, obj := range objects {
+		if strings.Contains(obj.ID, "#") {
+			continue
+		}
+		switch {
+		case req.ObjectID != "" && obj.ID == req.ObjectID:
+			if obj.Type != "page" {
+				return nil, &NotUntypedError{ObjectID: obj.ID, Type: obj.Type}
+			}
+			pages = append(pages, obj)
+		case req.ObjectID == "" && obj.Type == "page":
+			pages = append(pages, obj)
+		}
+		if !schema.IsBuiltinType(obj.Type) {
+			typed = append(typed, obj)
+		}
+	}
+	if req.ObjectID != "" && len(pages) == 0 {
+		return nil, &RefNotFoundError{Reference: req.ObjectID}
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].FilePath < pages[j].FilePath })
+
+	profiles := buildTypeProfiles(rt, typed, contents, traitsByObject)
+	siblingTypes := typesByDirectory(typed)
+
+	result := &SuggestTypesResult{Pages: make([]PageTypeSuggestions, 0, len(pages))}
+	for _, page := range pages {
+		suggestions := scorePage(rt, page, profiles, siblingTypes, termVector(contents[page.ID]), traitsByObject[page.ID])
+		if len(suggestions) > limit {
+			suggestions = suggestions[:limit]
+		}
+		result.Pages = append(result.Pages, PageTypeSuggestions{
+			ObjectID:    page.ID,
+			FilePath:    page.FilePath,
+			Suggestions: suggestions,
+		})
+	}
+	return result, nil
+}
+
+func buildTypeProfiles(rt *Runtime, typed []model.Object, contents map[string]string, traitsByObject map[string][]string) []*typeProfile {
+	if rt.Schema == nil {
+		return nil
+	}
+
+	byName := make(map[string]*typeProfile)
+	var profiles []*typeProfile
+	for name, def := range rt.Schema.Types {
+		if def == nil || schema.IsBuiltinType(name) {
+			continue
+		}
+		profile := &typeProfile{
+			name:        name,
+			defaultDir:  paths.NormalizeDirRoot(def.DefaultPath),
+			fields:      make(map[string]bool, len(def.Fields)),
+			traitCounts: make(map[string]int),
+			centroid:    make(map[string]float64),
+		}
+		for field := range def.Fields {
+			profile.fields[field] = true
+		}
+		byName[name] = profile
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].name < profiles[j].name })
+
+	for _, obj := range typed {
+		profile := byName[obj.Type]
+		if profile == nil {
+			continue
+		}
+		profile.objectCount++
+		for _, trait := range traitsByObject[obj.ID] {
+			profile.traitCounts[trait]++
+		}
+		for term, weight := range termVector(contents[obj.ID]) {
+			profile.centroid[term] += weight
+		}
+	}
+	return profiles
+}
+
+// typesByDirectory counts typed objects per type in each directory.
+func typesByDirectory(typed []model.Object) map[string]map[string]int {
+	dirs := make(map[string]map[string]int)
+	for _, obj := range typed {
+		dir := path.Dir(obj.FilePath)
+		if dirs[dir] == nil {
+			dirs[dir] = make(map[string]int)
+		}
+		dirs[dir][obj.Type]++
+	}
+	return dirs
+}
+
+func scorePage(rt *Runtime, page model.Object, profiles []*typeProfile, siblingTypes map[string]map[string]int, pageTerms map[string]float64, pageTraits []string) []TypeSuggestion {
+	relPath := page.FilePath
+	if rt.VaultCfg != nil {
+		if root := paths.NormalizeDirRoot(rt.VaultCfg.GetObjectsRoot()); root != "" {
+			relPath = strings.TrimPrefix(relPath, root)
+		}
+	}
+	dir := path.Dir(page.FilePath)
+	dirLabel := dir + "/"
+	if dir == "." {
+		dirLabel = "the vault root"
+	}
+	siblings := siblingTypes[dir]
+	siblingTotal := 0
+	for _, count := range siblings {
+		siblingTotal += count
+	}
+
+	var pageKeys []string
+	for key := range page.Fields {
+		if key != "type" && key != "alias" {
+			pageKeys = append(pageKeys, key)
+		}
+	}
+	sort.Strings(pageKeys)
+
+	var suggestions []TypeSuggestion
+	for _, profile := range profiles {
+		signals := make(map[string]float64)
+		var reasons []string
+
+		switch {
+		case profile.defaultDir != "" && strings.HasPrefix(relPath, profile.defaultDir):
+			signals[SuggestSignalDirectory] = 1
+			reasons = append(reasons, fmt.Sprintf("in %s, the type's default_path", profile.defaultDir))
+		case siblings[profile.name] > 0:
+			signals[SuggestSignalDirectory] = float64(siblings[profile.name]) / float64(siblingTotal)
+			reasons = append(reasons, fmt.Sprintf("%d of %d typed files in %s are %s", siblings[profile.name], siblingTotal, dirLabel, profile.name))
+		}
+
+		var matchedKeys []string
+		for _, key := range pageKeys {
+			if profile.fields[key] {
+				matchedKeys = append(matchedKeys, key)
+			}
+		}
+		if len(matchedKeys) > 0 {
+			signals[SuggestSignalFields] = float64(len(matchedKeys)) / float64(len(pageKeys))
+			reasons = append(reasons, "fields: "+strings.Join(matchedKeys, ", "))
+		}
+
+		if profile.objectCount > 0 && len(pageTraits) > 0 {
+			var score float64
+			var shared []string
+			for _, trait := range pageTraits {
+				if count := profile.traitCounts[trait]; count > 0 {
+					score += float64(count) / float64(profile.objectCount)
+					shared = append(shared, "@"+trait)
+				}
+			}
+			if len(shared) > 0 {
+				signals[SuggestSignalTraits] = math.Min(1, score/float64(len(pageTraits)))
+				reasons = append(reasons, "traits: "+strings.Join(shared, ", "))
+			}
+		}
+
+		if similarity := cosineSimilarity(pageTerms, profile.centroid); similarity >= minContentSimilarity {
+			signals[SuggestSignalContent] = similarity
+			reasons = append(reasons, fmt.Sprintf("content similar to %d %s object(s)", profile.objectCount, profile.name))
+		}
+
+		if len(signals) == 0 {
+			continue
+		}
+		var confidence float64
+		for signal, score := range signals {
+			confidence += suggestSignalWeights[signal] * score
+		}
+		suggestions = append(suggestions, TypeSuggestion{
+			Type:       profile.name,
+			Confidence: math.Round(confidence*100) / 100,
+			Signals:    signals,
+			Reasons:    reasons,
+		})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+		return suggestions[i].Type < suggestions[j].Type
+	})
+	return suggestions
+}
+
+// minContentSimilarity drops incidental word overlap from the content signal.
+const minContentSimilarity = 0.05
+
+// suggestStopwords are common words that carry no signal about a page's type.
+var suggestStopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true, "this": true,
+	"from": true, "are": true, "was": true, "were": true, "have": true, "has": true,
+	"not": true, "but": true, "you": true, "your": true, "our": true, "they": true,
+	"will": true, "can": true, "into": true, "about": true, "also": true, "there": true,
+}
+
+// termVector returns unit-length term frequencies for text.
+func termVector(text string) map[string]float64 {
+	terms := make(map[string]float64)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) < 3 || suggestStopwords[word] {
+			continue
+		}
+		terms[word]++
+	}
+	var norm float64
+	for _, count := range terms {
+		norm += count * count
+	}
+	norm = math.Sqrt(norm)
+	for term := range terms {
+		terms[term] /= norm
+	}
+	return terms
+}
+
+func cosineSimilarity(a, b map[string]float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for term, weight := range a {
+		normA += weight * weight
+		dot += weight * b[term]
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+	if dot == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
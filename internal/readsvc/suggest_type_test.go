@@ -0,0 +1,141 @@
+package readsvc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func suggestTypeRuntime(t *testing.T) *Runtime {
+	t.Helper()
+
+	db, err := index.OpenInMemory()
+	if err != nil {
+		t.Fatalf("failed to open in-memory db: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.DB().Exec(`
+		INSERT INTO objects (id, file_path, type, line_start, fields) VALUES
+			('projects/website', 'projects/website.md', 'project', 1, '{"status":"active"}'),
+			('projects/mobile', 'projects/mobile.md', 'project', 1, '{"status":"paused"}'),
+			('people/freya', 'people/freya.md', 'person', 1, '{"name":"Freya"}'),
+			('meetings/standup', 'notes/standup.md', 'meeting', 1, '{}'),
+			('projects/draft', 'projects/draft.md', 'page', 1, '{}'),
+			('inbox/loki', 'inbox/loki.md', 'page', 1, '{"email":"loki@asgard.example"}'),
+			('inbox/launch', 'inbox/launch.md', 'page', 1, '{}'),
+			('notes/retro', 'notes/retro.md', 'page', 1, '{}'),
+			('inbox/blank', 'inbox/blank.md', 'page', 1, '{}');
+		INSERT INTO fts_content (object_id, title, content, headings, code, file_path) VALUES
+			('projects/website', 'Website', 'Launch milestone roadmap for the website redesign', '', '', 'projects/website.md'),
+			('projects/mobile', 'Mobile', 'Roadmap and launch milestone for the mobile app', '', '', 'projects/mobile.md'),
+			('people/freya', 'Freya', 'Met Freya at the conference', '', '', 'people/freya.md'),
+			('inbox/launch', 'Launch', 'Launch milestone roadmap for the new app', '', '', 'inbox/launch.md');
+		INSERT INTO traits (id, file_path, parent_object_id, trait_type, value, content, line_number) VALUES
+			('notes/standup.md:trait:0', 'notes/standup.md', 'meetings/standup', 'attendee', NULL, 'x', 3),
+			('notes/retro.md:trait:0', 'notes/retro.md', 'notes/retro', 'attendee', NULL, 'y', 3)
+	`)
+	if err != nil {
+		t.Fatalf("failed to seed: %v", err)
+	}
+
+	sch := schema.New()
+	sch.Types["project"] = &schema.TypeDefinition{
+		DefaultPath: "projects/",
+		Fields:      map[string]*schema.FieldDefinition{"status": {Type: schema.FieldTypeString}},
+	}
+	sch.Types["person"] = &schema.TypeDefinition{
+		DefaultPath: "people/",
+		Fields: map[string]*schema.FieldDefinition{
+			"name":  {Type: schema.FieldTypeString},
+			"email": {Type: schema.FieldTypeString},
+		},
+	}
+	sch.Types["meeting"] = &schema.TypeDefinition{DefaultPath: "meetings/"}
+
+	return &Runtime{VaultPath: t.TempDir(), Schema: sch, DB: db}
+}
+
+func TestSuggestTypes(t *testing.T) {
+	t.Parallel()
+	rt := suggestTypeRuntime(t)
+
+	tests := []struct {
+		objectID   string
+		wantType   string
+		wantSignal string
+	}{
+		{objectID: "projects/draft", wantType: "project", wantSignal: SuggestSignalDirectory},
+		{objectID: "inbox/loki", wantType: "person", wantSignal: SuggestSignalFields},
+		{objectID: "inbox/launch", wantType: "project", wantSignal: SuggestSignalContent},
+		{objectID: "notes/retro", wantType: "meeting", wantSignal: SuggestSignalTraits},
+	}
+	for _, tc := range tests {
+		t.Run(tc.objectID, func(t *testing.T) {
+			result, err := SuggestTypes(rt, SuggestTypesRequest{ObjectID: tc.objectID})
+			if err != nil {
+				t.Fatalf("SuggestTypes: %v", err)
+			}
+			if len(result.Pages) != 1 || len(result.Pages[0].Suggestions) == 0 {
+				t.Fatalf("expected suggestions for %s, got %#v", tc.objectID, result.Pages)
+			}
+			top := result.Pages[0].Suggestions[0]
+			if top.Type != tc.wantType {
+				t.Fatalf("top suggestion = %s, want %s (all: %#v)", top.Type, tc.wantType, result.Pages[0].Suggestions)
+			}
+			if top.Signals[tc.wantSignal] <= 0 {
+				t.Fatalf("expected %s signal, got %#v", tc.wantSignal, top.Signals)
+			}
+			if top.Confidence <= 0 || top.Confidence > 1 || len(top.Reasons) == 0 {
+				t.Fatalf("unexpected suggestion: %#v", top)
+			}
+		})
+	}
+}
+
+func TestSuggestTypesAll(t *testing.T) {
+	t.Parallel()
+	rt := suggestTypeRuntime(t)
+
+	result, err := SuggestTypes(rt, SuggestTypesRequest{Limit: 1})
+	if err != nil {
+		t.Fatalf("SuggestTypes: %v", err)
+	}
+	var ids []string
+	for _, page := range result.Pages {
+		ids = append(ids, page.ObjectID)
+		if len(page.Suggestions) > 1 {
+			t.Fatalf("limit not applied for %s: %#v", page.ObjectID, page.Suggestions)
+		}
+		if page.ObjectID == "inbox/blank" && len(page.Suggestions) != 0 {
+			t.Fatalf("blank page should have no suggestions, got %#v", page.Suggestions)
+		}
+	}
+	want := []string{"inbox/blank", "inbox/launch", "inbox/loki", "notes/retro", "projects/draft"}
+	if len(ids) != len(want) {
+		t.Fatalf("pages = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("pages = %v, want %v", ids, want)
+		}
+	}
+}
+
+func TestSuggestTypesRejectsTypedObjects(t *testing.T) {
+	t.Parallel()
+	rt := suggestTypeRuntime(t)
+
+	_, err := SuggestTypes(rt, SuggestTypesRequest{ObjectID: "people/freya"})
+	var notUntyped *NotUntypedError
+	if !errors.As(err, &notUntyped) || notUntyped.Type != "person" {
+		t.Fatalf("expected NotUntypedError, got %v", err)
+	}
+
+	_, err = SuggestTypes(rt, SuggestTypesRequest{ObjectID: "inbox/missing"})
+	if !IsRefNotFound(err) {
+		t.Fatalf("expected RefNotFoundError, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package readsvc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/template"
+)
+
+// templateQueryLimit caps the results a single {{query}} call returns.
+const templateQueryLimit = 200
+
+// TemplateQuery returns a template.QueryFunc that runs queries against the
+// vault's index. A saved query name without args runs that saved query. The
+// index is opened on the first call and closed after each call.
+func TemplateQuery(vaultPath string) template.QueryFunc {
+	return func(queryString string) ([]template.QueryItem, error) {
+		rt, err := NewRuntime(vaultPath, RuntimeOptions{OpenDB: true})
+		if err != nil {
+			return nil, err
+		}
+		defer rt.Close()
+
+		queryString = strings.TrimSpace(queryString)
+		if saved, ok := rt.VaultCfg.Queries[queryString]; ok && saved != nil {
+			if len(saved.Args) > 0 {
+				return nil, fmt.Errorf("saved query %q takes args; use its query string instead", queryString)
+			}
+			queryString = saved.Query
+		}
+
+		result, err := ExecuteQuery(rt, ExecuteQueryRequest{QueryString: queryString, Limit: templateQueryLimit})
+		if err != nil {
+			return nil, fmt.Errorf("query %q: %w", queryString, err)
+		}
+
+		items := make([]template.QueryItem, 0, len(result.Objects)+len(result.Traits))
+		for _, obj := range result.Objects {
+			items = append(items, template.QueryItem{ID: obj.ID, Type: obj.Type, Fields: obj.Fields})
+		}
+		for _, trait := range result.Traits {
+			item := template.QueryItem{ID: trait.ID, Trait: trait.TraitType, Content: trait.Content, Line: trait.Line}
+			if trait.Value != nil {
+				item.Value = *trait.Value
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+}
@@ -0,0 +1,131 @@
+// Package redirects maintains redirects.yaml, which maps object IDs that were
+// moved or renamed to their current IDs. Reference resolution falls back to
+// it, so IDs held by external tools, exported sites, or printed links keep
+// resolving after a move.
+package redirects
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/history"
+)
+
+// FileName is the redirects file at the vault root.
+const FileName = "redirects.yaml"
+
+// maxHops bounds chain following in case the file was edited into a cycle.
+const maxHops = 32
+
+type file struct {
+	Redirects map[string]string `yaml:"redirects"`
+}
+
+// Path returns the redirects file path for a vault.
+func Path(vaultPath string) string {
+	return filepath.Join(vaultPath, FileName)
+}
+
+// Load reads the redirect map, old ID to new ID. A missing file is an empty
+// map.
+func Load(vaultPath string) (map[string]string, error) {
+	data, err := os.ReadFile(Path(vaultPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var parsed file
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName, err)
+	}
+	if parsed.Redirects == nil {
+		parsed.Redirects = map[string]string{}
+	}
+	return parsed.Redirects, nil
+}
+
+// Save writes the redirect map, removing the file when it is empty.
+func Save(vaultPath string, redirects map[string]string) error {
+	path := Path(vaultPath)
+	history.Capture(path)
+	if len(redirects) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	data, err := yaml.Marshal(file{Redirects: redirects})
+	if err != nil {
+		return err
+	}
+	header := "# Maintained by rvn move and rvn rename: old object ID -> current ID.\n"
+	return atomicfile.WriteFile(path, append([]byte(header), data...), 0o644)
+}
+
+// Record notes that oldID now lives at newID. Redirects that pointed at
+// oldID are repointed so chains stay one hop, and any redirect away from
+// newID is dropped because an object lives there again.
+func Record(vaultPath, oldID, newID string) error {
+	oldID, newID = normalizeID(oldID), normalizeID(newID)
+	if oldID == "" || newID == "" || oldID == newID {
+		return nil
+	}
+	redirects, err := Load(vaultPath)
+	if err != nil {
+		return err
+	}
+	for from, to := range redirects {
+		if to == oldID {
+			redirects[from] = newID
+		}
+	}
+	redirects[oldID] = newID
+	delete(redirects, newID)
+	for from, to := range redirects {
+		if from == to {
+			delete(redirects, from)
+		}
+	}
+	return Save(vaultPath, redirects)
+}
+
+// Lookup follows redirects from id and returns the final ID, or false when id
+// has no redirect or the chain does not end.
+func Lookup(redirects map[string]string, id string) (string, bool) {
+	current := normalizeID(id)
+	target, ok := redirects[current]
+	if !ok {
+		return "", false
+	}
+	for hops := 0; ok; hops++ {
+		if hops == maxHops {
+			return "", false
+		}
+		current = target
+		target, ok = redirects[current]
+	}
+	return current, true
+}
+
+// Sources returns the redirected IDs in sorted order.
+func Sources(redirects map[string]string) []string {
+	ids := make([]string, 0, len(redirects))
+	for id := range redirects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func normalizeID(id string) string {
+	return strings.TrimSuffix(strings.Trim(strings.TrimSpace(id), "/"), ".md")
+}
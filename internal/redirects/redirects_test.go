@@ -0,0 +1,66 @@
+package redirects
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestRecordKeepsChainsOneHop(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+
+	for _, move := range [][2]string{
+		{"people/freya", "people/freyja"},
+		{"people/freyja", "people/freyja-s"},
+		{"notes/a", "notes/b"},
+		{"notes/b", "notes/a"},
+	} {
+		if err := Record(vaultPath, move[0], move[1]); err != nil {
+			t.Fatalf("Record(%s, %s): %v", move[0], move[1], err)
+		}
+	}
+
+	got, err := Load(vaultPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := map[string]string{
+		"people/freya":  "people/freyja-s",
+		"people/freyja": "people/freyja-s",
+		"notes/b":       "notes/a",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("redirects = %#v, want %#v", got, want)
+	}
+}
+
+func TestLookupStopsOnCycles(t *testing.T) {
+	t.Parallel()
+
+	redirectMap := map[string]string{"a": "b", "b": "c", "x": "y", "y": "x"}
+	if got, ok := Lookup(redirectMap, "a.md"); !ok || got != "c" {
+		t.Fatalf("Lookup(a) = %q, %v, want c", got, ok)
+	}
+	if _, ok := Lookup(redirectMap, "x"); ok {
+		t.Fatal("Lookup(x) should fail on a cycle")
+	}
+	if _, ok := Lookup(redirectMap, "c"); ok {
+		t.Fatal("Lookup(c) should report no redirect")
+	}
+}
+
+func TestSaveRemovesEmptyFile(t *testing.T) {
+	t.Parallel()
+	vaultPath := t.TempDir()
+
+	if err := Record(vaultPath, "a", "b"); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if err := Save(vaultPath, map[string]string{}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := os.Stat(Path(vaultPath)); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed, stat err = %v", FileName, err)
+	}
+}
@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/aidanlsb/raven/internal/codes"
 	"github.com/aidanlsb/raven/internal/config"
 	ravenignore "github.com/aidanlsb/raven/internal/ignore"
 	"github.com/aidanlsb/raven/internal/index"
 	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/query"
 	"github.com/aidanlsb/raven/internal/schema"
 	"github.com/aidanlsb/raven/internal/vault"
 )
@@ -72,6 +75,10 @@ type RunRequest struct {
 	Full      bool
 	DryRun    bool
 	Context   context.Context
+	// Changed lists vault-relative files known to have changed. An
+	// incremental run reindexes them even when their modification time
+	// matches the index, which only has one-second resolution.
+	Changed []string
 }
 
 type RunResult struct {
@@ -94,8 +101,13 @@ type RunResult struct {
 
 	RefsResolved   int
 	RefsUnresolved int
+	RefsExternal   int
 	HasRefResult   bool
 
+	// SchemaDrift lists the types and traits changed since the index was last
+	// built; their files are reindexed even when unchanged on disk.
+	SchemaDrift *schema.Drift
+
 	WarningMessages []string
 }
 
@@ -119,9 +131,13 @@ func (r *RunResult) Data() map[string]interface{} {
 		data["deleted_files"] = r.DeletedFiles
 		data["excluded_files"] = r.ExcludedFiles
 	}
+	if r.SchemaDrift != nil {
+		data["schema_drift"] = r.SchemaDrift
+	}
 	if r.HasRefResult {
 		data["refs_resolved"] = r.RefsResolved
 		data["refs_unresolved"] = r.RefsUnresolved
+		data["refs_external"] = r.RefsExternal
 	}
 	return data
 }
@@ -196,6 +212,7 @@ func Run(req RunRequest) (*RunResult, error) {
 		HasRefResult:    false,
 		RefsResolved:    0,
 		RefsUnresolved:  0,
+		RefsExternal:    0,
 		FilesIndexed:    0,
 		FilesSkipped:    0,
 		FilesDeleted:    0,
@@ -254,6 +271,20 @@ func Run(req RunRequest) (*RunResult, error) {
 		}
 	}
 
+	fingerprint := sch.Fingerprint()
+	var drift schema.Drift
+	reindexAll := false
+	if incremental {
+		if stored, ok, fpErr := db.SchemaFingerprint(); fpErr != nil {
+			result.WarningMessages = append(result.WarningMessages, fmt.Sprintf("failed to read index schema stamp: %v", fpErr))
+		} else if ok && stored.Hash != fingerprint.Hash {
+			drift = fingerprint.DriftSince(stored)
+			result.SchemaDrift = &drift
+			// A format version change can affect every file.
+			reindexAll = stored.Version != fingerprint.Version
+		}
+	}
+
 	walkOpts := &vault.WalkOptions{ParseOptions: parseOpts, ExcludeMatcher: excludeMatcher}
 	walkErr := vault.WalkMarkdownFilesWithOptions(vaultPath, walkOpts, func(walkResult vault.WalkResult) error {
 		select {
@@ -268,8 +299,9 @@ func Run(req RunRequest) (*RunResult, error) {
 		}
 
 		if incremental {
+			mustReindex := reindexAll || documentAffectedByDrift(walkResult.Document, drift) || slices.Contains(req.Changed, walkResult.RelativePath)
 			indexedMtime, mtimeErr := db.GetFileMtime(walkResult.RelativePath)
-			if mtimeErr == nil && indexedMtime > 0 && walkResult.FileMtime <= indexedMtime {
+			if !mustReindex && mtimeErr == nil && indexedMtime > 0 && walkResult.FileMtime <= indexedMtime {
 				result.FilesSkipped++
 				return nil
 			}
@@ -366,14 +398,41 @@ func Run(req RunRequest) (*RunResult, error) {
 		} else if refResult != nil {
 			result.RefsResolved = refResult.Resolved
 			result.RefsUnresolved = refResult.Unresolved
+			result.RefsExternal = refResult.External
 			result.HasRefResult = true
 		}
 
+		if !incremental {
+			// Prefer git history over filesystem mtimes for creation times,
+			// since clones and checkouts reset mtimes.
+			if gitCreated := vault.GitFileCreatedTimes(vaultPath); len(gitCreated) > 0 {
+				if _, createdErr := db.ApplyFileCreatedTimes(gitCreated); createdErr != nil {
+					result.WarningMessages = append(result.WarningMessages, fmt.Sprintf("failed to apply git creation times: %v", createdErr))
+				}
+			}
+		}
+
 		if analyzeErr := db.Analyze(); analyzeErr != nil {
 			result.WarningMessages = append(result.WarningMessages, fmt.Sprintf("failed to analyze database: %v", analyzeErr))
 		}
 	}
 
+	// Derived fields aggregate over other files, so any change can move them.
+	if result.FilesIndexed > 0 || len(result.DeletedFiles) > 0 {
+		if err := query.MaterializeDerivedFields(db, sch, dailyDir); err != nil {
+			result.WarningMessages = append(result.WarningMessages, fmt.Sprintf("failed to compute derived fields: %v", err))
+		}
+	}
+
+	if err := db.SetSchemaFingerprint(fingerprint); err != nil {
+		result.WarningMessages = append(result.WarningMessages, fmt.Sprintf("failed to record index schema stamp: %v", err))
+	}
+	if stamp := vaultCfg.SchemaStamp; stamp == nil || stamp.Hash != fingerprint.Hash || stamp.Version != fingerprint.Version {
+		if err := config.StampSchema(vaultPath, config.SchemaStamp{Version: fingerprint.Version, Hash: fingerprint.Hash}); err != nil {
+			result.WarningMessages = append(result.WarningMessages, fmt.Sprintf("failed to stamp schema in raven.yaml: %v", err))
+		}
+	}
+
 	stats, err := db.Stats()
 	if err != nil {
 		return nil, newError(CodeDatabaseError, fmt.Sprintf("failed to get stats: %v", err), "", err)
@@ -383,9 +442,32 @@ func Run(req RunRequest) (*RunResult, error) {
 	result.References = stats.RefCount
 	result.Assets = stats.AssetCount
 
+	if _, err := db.RecordStatsSnapshot(time.Now()); err != nil {
+		result.WarningMessages = append(result.WarningMessages, fmt.Sprintf("failed to record stats snapshot: %v", err))
+	}
+
 	return result, nil
 }
 
+// documentAffectedByDrift reports whether doc declares an object of a drifted
+// type or uses a drifted trait, so its indexed rows may no longer be valid.
+func documentAffectedByDrift(doc *parser.ParsedDocument, drift schema.Drift) bool {
+	if doc == nil || drift.IsEmpty() {
+		return false
+	}
+	for _, obj := range doc.Objects {
+		if slices.Contains(drift.Types, obj.ObjectType) {
+			return true
+		}
+	}
+	for _, trait := range doc.Traits {
+		if slices.Contains(drift.Traits, trait.TraitType) {
+			return true
+		}
+	}
+	return false
+}
+
 func parsedDocumentStats(doc *parser.ParsedDocument) index.IndexStats {
 	if doc == nil {
 		return index.IndexStats{}
@@ -496,11 +578,23 @@ func fileIndexStats(db *index.Database, filePath string) (index.IndexStats, erro
 }
 
 func buildParseOptions(vaultCfg *config.VaultConfig) *parser.ParseOptions {
-	if vaultCfg == nil || !vaultCfg.HasDirectoriesConfig() {
+	if vaultCfg == nil {
 		return nil
 	}
-	return &parser.ParseOptions{
-		ObjectsRoot: vaultCfg.GetObjectsRoot(),
-		PagesRoot:   vaultCfg.GetPagesRoot(),
+	dateFormats := vaultCfg.GetDateMentionFormats()
+	issueRefs := vaultCfg.GetIssueRefs()
+	hashtagTrait := vaultCfg.GetHashtagTrait()
+	if !vaultCfg.HasDirectoriesConfig() && dateFormats == nil && issueRefs == nil && hashtagTrait == "" {
+		return nil
+	}
+	opts := &parser.ParseOptions{
+		ObjectsRoot:        vaultCfg.GetObjectsRoot(),
+		PagesRoot:          vaultCfg.GetPagesRoot(),
+		DateMentionFormats: dateFormats,
+		HashtagTrait:       hashtagTrait,
+	}
+	if issueRefs != nil {
+		opts.IssueRefs = &parser.IssueRefOptions{JiraProjects: issueRefs.JiraProjects(), JiraURL: issueRefs.JiraURL()}
 	}
+	return opts
 }
@@ -353,6 +353,58 @@ func TestRunIncrementalPurgesNewlyExcludedFiles(t *testing.T) {
 	}
 }
 
+func TestRunIncrementalReindexesFilesAffectedBySchemaChange(t *testing.T) {
+	t.Parallel()
+
+	vaultPath := t.TempDir()
+	writeTestFile(t, vaultPath, "raven.yaml", "auto_reindex: true\n")
+	writeTestFile(t, vaultPath, "schema.yaml", "version: 2\ntypes:\n  project:\n    fields:\n      status: {type: string}\n  person:\n    fields: {}\ntraits:\n  due: {type: date}\n")
+	writeTestFile(t, vaultPath, "project.md", "---\ntype: project\nstatus: open\n---\n")
+	writeTestFile(t, vaultPath, "person.md", "---\ntype: person\n---\n")
+	writeTestFile(t, vaultPath, "note.md", "- @due(2026-01-01) ship it\n")
+
+	if _, err := Run(RunRequest{VaultPath: vaultPath, Full: true}); err != nil {
+		t.Fatalf("initial Run returned error: %v", err)
+	}
+	cfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		t.Fatalf("LoadVaultConfig: %v", err)
+	}
+	if cfg.SchemaStamp == nil || cfg.SchemaStamp.Version != 2 || cfg.SchemaStamp.Hash == "" {
+		t.Fatalf("schema stamp = %#v, want version 2 with a hash", cfg.SchemaStamp)
+	}
+	firstHash := cfg.SchemaStamp.Hash
+
+	writeTestFile(t, vaultPath, "schema.yaml", "version: 2\ntypes:\n  project:\n    fields:\n      status: {type: enum, values: [open, done]}\n  person:\n    fields: {}\ntraits:\n  due: {type: date}\n")
+
+	result, err := Run(RunRequest{VaultPath: vaultPath})
+	if err != nil {
+		t.Fatalf("incremental Run returned error: %v", err)
+	}
+	if result.SchemaDrift == nil || len(result.SchemaDrift.Types) != 1 || result.SchemaDrift.Types[0] != "project" || len(result.SchemaDrift.Traits) != 0 {
+		t.Fatalf("schema drift = %#v, want type project", result.SchemaDrift)
+	}
+	if len(result.StaleFiles) != 1 || result.StaleFiles[0] != "project.md" {
+		t.Fatalf("stale files = %#v, want only project.md", result.StaleFiles)
+	}
+
+	cfg, err = config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		t.Fatalf("LoadVaultConfig: %v", err)
+	}
+	if cfg.SchemaStamp == nil || cfg.SchemaStamp.Hash == firstHash || !cfg.IsAutoReindexEnabled() {
+		t.Fatalf("raven.yaml after restamp = %#v", cfg)
+	}
+
+	result, err = Run(RunRequest{VaultPath: vaultPath})
+	if err != nil {
+		t.Fatalf("second incremental Run returned error: %v", err)
+	}
+	if result.SchemaDrift != nil || len(result.StaleFiles) != 0 {
+		t.Fatalf("expected no drift after restamp, got %#v %#v", result.SchemaDrift, result.StaleFiles)
+	}
+}
+
 func TestBuildParseOptions(t *testing.T) {
 	t.Parallel()
 	if got := buildParseOptions(nil); got != nil {
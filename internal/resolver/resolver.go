@@ -19,6 +19,7 @@ type Resolver struct {
 	aliasMap       map[string][]string // Map from alias to object IDs
 	nameFieldMap   map[string][]string // Map from name_field value (slugified) to object IDs
 	dailyDirectory string              // Directory for daily notes (e.g., "daily")
+	externalPaths  []string            // ID prefixes of sparse directories absent locally
 }
 
 // Options configures the resolver.
@@ -44,6 +45,11 @@ type Options struct {
 	// They participate in normal path/short-name resolution, plus extensionless
 	// short-name matching when unambiguous (e.g., "paper" -> "assets/paper.pdf").
 	AssetIDs []string
+
+	// ExternalPaths are ID prefixes (e.g., "archive/") of sparse directories
+	// that are not checked out locally. Unmatched references under these
+	// prefixes are reported as External rather than plain not-found.
+	ExternalPaths []string
 }
 
 // New creates a new Resolver with the given object IDs and options.
@@ -61,6 +67,11 @@ func New(objectIDs []string, opts Options) *Resolver {
 		suffixMap:      make(map[string][]string, len(allIDs)*2),
 		dailyDirectory: dailyDir,
 	}
+	for _, root := range opts.ExternalPaths {
+		if root = paths.NormalizeDirRoot(root); root != "" {
+			r.externalPaths = append(r.externalPaths, root)
+		}
+	}
 
 	for _, id := range allIDs {
 		r.objectIDs[id] = struct{}{}
@@ -187,6 +198,10 @@ type ResolveResult struct {
 	// MatchSources maps matched IDs to their match source.
 	MatchSources map[string]string
 
+	// External is true if nothing matched and the reference points into a
+	// sparse directory that is not checked out locally.
+	External bool
+
 	// Error message if resolution failed.
 	Error string
 }
@@ -253,7 +268,30 @@ func (r *Resolver) Resolve(ref string) ResolveResult {
 	}
 
 	matchSources := filterMatchSources(c.sources, matches)
-	return buildResolveResult(matches, matchSources)
+	result := buildResolveResult(matches, matchSources)
+	if len(matches) == 0 && r.isExternal(ref) {
+		result.External = true
+		result.Error = "reference points into a sparse directory that is not checked out"
+	}
+	return result
+}
+
+// isExternal reports whether ref's base ID lies under an external path.
+func (r *Resolver) isExternal(ref string) bool {
+	if len(r.externalPaths) == 0 {
+		return false
+	}
+	baseID, _, _ := paths.ParseSectionID(paths.NormalizeVaultRelPath(ref))
+	baseID = paths.TrimMDExtension(baseID)
+	if baseID == "" {
+		return false
+	}
+	for _, root := range r.externalPaths {
+		if strings.HasPrefix(baseID, root) {
+			return true
+		}
+	}
+	return false
 }
 
 type matchCollector struct {
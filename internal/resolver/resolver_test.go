@@ -106,6 +106,33 @@ func TestResolverAmbiguous(t *testing.T) {
 	}
 }
 
+func TestResolverExternalPaths(t *testing.T) {
+	t.Parallel()
+
+	r := New([]string{"people/freya", "archive/kept"}, Options{ExternalPaths: []string{"archive"}})
+
+	tests := []struct {
+		ref          string
+		wantTarget   string
+		wantExternal bool
+	}{
+		{ref: "archive/2019/retro", wantExternal: true},
+		{ref: "archive/2019/retro#notes", wantExternal: true},
+		{ref: "archive/2019/retro.md", wantExternal: true},
+		{ref: "archive/kept", wantTarget: "archive/kept"},
+		{ref: "projects/missing"},
+		{ref: "people/freya", wantTarget: "people/freya"},
+	}
+
+	for _, tt := range tests {
+		result := r.Resolve(tt.ref)
+		if result.TargetID != tt.wantTarget || result.External != tt.wantExternal {
+			t.Errorf("Resolve(%q) = target %q external %v, want target %q external %v",
+				tt.ref, result.TargetID, result.External, tt.wantTarget, tt.wantExternal)
+		}
+	}
+}
+
 func TestResolverAssetShortNames(t *testing.T) {
 	t.Parallel()
 
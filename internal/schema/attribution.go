@@ -0,0 +1,13 @@
+package schema
+
+// Attribution frontmatter fields stamped on mutations when vault attribution
+// is enabled. They are reserved: allowed on any type without a schema entry.
+const (
+	FieldCreatedBy  = "created_by"
+	FieldModifiedBy = "modified_by"
+)
+
+// IsAttributionField reports whether name is a reserved attribution field.
+func IsAttributionField(name string) bool {
+	return name == FieldCreatedBy || name == FieldModifiedBy
+}
@@ -0,0 +1,134 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FieldPathSegment is one step of a field path: a key, optionally followed by
+// an array index.
+type FieldPathSegment struct {
+	Key string
+	// Index is the array element selected by key[n], or -1 for none.
+	Index int
+}
+
+// ParseFieldPath splits a field path such as "address.city" or
+// "authors[0].name" into segments.
+func ParseFieldPath(path string) ([]FieldPathSegment, error) {
+	if path == "" {
+		return nil, fmt.Errorf("empty field path")
+	}
+	parts := strings.Split(path, ".")
+	segments := make([]FieldPathSegment, 0, len(parts))
+	for _, part := range parts {
+		segment := FieldPathSegment{Key: part, Index: -1}
+		if open := strings.IndexByte(part, '['); open >= 0 {
+			if !strings.HasSuffix(part, "]") {
+				return nil, fmt.Errorf("invalid field path %q: unclosed '['", path)
+			}
+			index, err := strconv.Atoi(part[open+1 : len(part)-1])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid field path %q: array index must be a non-negative integer", path)
+			}
+			segment.Key = part[:open]
+			segment.Index = index
+		}
+		if segment.Key == "" {
+			return nil, fmt.Errorf("invalid field path %q: empty field name", path)
+		}
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+// IsNestedFieldPath reports whether path reaches into an object or array
+// element rather than naming a top-level field.
+func IsNestedFieldPath(path string) bool {
+	return strings.ContainsAny(path, ".[")
+}
+
+// FieldAtPath returns the definition a field path refers to, descending into
+// object fields and array elements. A path naming a single element of an
+// array field returns a definition of the element type. It returns nil when
+// any part of the path is not defined.
+func (td *TypeDefinition) FieldAtPath(path string) *FieldDefinition {
+	if td == nil {
+		return nil
+	}
+	if !IsNestedFieldPath(path) {
+		return td.Fields[path]
+	}
+	segments, err := ParseFieldPath(path)
+	if err != nil {
+		return nil
+	}
+
+	fields := td.Fields
+	var def *FieldDefinition
+	for i, segment := range segments {
+		if i > 0 {
+			if def.Type != FieldTypeObject {
+				return nil
+			}
+			fields = def.Fields
+		}
+		def = fields[segment.Key]
+		if def == nil {
+			return nil
+		}
+		if segment.Index >= 0 {
+			def = def.elementDefinition()
+			if def == nil {
+				return nil
+			}
+		}
+	}
+	return def
+}
+
+// elementDefinition returns the definition of one element of an array field.
+func (fd *FieldDefinition) elementDefinition() *FieldDefinition {
+	elemType, ok := strings.CutSuffix(string(fd.Type), "[]")
+	if !ok {
+		return nil
+	}
+	elem := *fd
+	elem.Type = FieldType(elemType)
+	elem.Required = false
+	elem.Default = nil
+	return &elem
+}
+
+// ValueAtPath returns the value a field path refers to in decoded field
+// values (as returned by FieldValue.Raw or read back from the index).
+func ValueAtPath(fields map[string]interface{}, path string) (interface{}, bool) {
+	if !IsNestedFieldPath(path) {
+		value, ok := fields[path]
+		return value, ok
+	}
+	segments, err := ParseFieldPath(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var current interface{} = fields
+	for _, segment := range segments {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = obj[segment.Key]; !ok {
+			return nil, false
+		}
+		if segment.Index >= 0 {
+			items, ok := current.([]interface{})
+			if !ok || segment.Index >= len(items) {
+				return nil, false
+			}
+			current = items[segment.Index]
+		}
+	}
+	return current, true
+}
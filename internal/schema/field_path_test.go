@@ -0,0 +1,130 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldAtPath(t *testing.T) {
+	t.Parallel()
+
+	typeDef := &TypeDefinition{Fields: map[string]*FieldDefinition{
+		"tags": {Type: FieldTypeStringArray},
+		"address": {Type: FieldTypeObject, Fields: map[string]*FieldDefinition{
+			"city": {Type: FieldTypeString},
+		}},
+		"authors": {Type: FieldTypeObjectArray, Fields: map[string]*FieldDefinition{
+			"name": {Type: FieldTypeString, Required: true},
+		}},
+	}}
+
+	tests := []struct {
+		path string
+		want FieldType
+	}{
+		{path: "tags", want: FieldTypeStringArray},
+		{path: "tags[2]", want: FieldTypeString},
+		{path: "address.city", want: FieldTypeString},
+		{path: "authors[0]", want: FieldTypeObject},
+		{path: "authors[0].name", want: FieldTypeString},
+		{path: "address.country"},
+		{path: "authors.name"},
+		{path: "address[0]"},
+		{path: "tags[x]"},
+	}
+	for _, tt := range tests {
+		got := typeDef.FieldAtPath(tt.path)
+		if tt.want == "" {
+			if got != nil {
+				t.Errorf("FieldAtPath(%q) = %+v, want nil", tt.path, got)
+			}
+			continue
+		}
+		if got == nil || got.Type != tt.want {
+			t.Errorf("FieldAtPath(%q) = %+v, want type %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestValueAtPath(t *testing.T) {
+	t.Parallel()
+
+	fields := map[string]interface{}{
+		"address": map[string]interface{}{"city": "Oslo"},
+		"authors": []interface{}{
+			map[string]interface{}{"name": "Snorri"},
+		},
+	}
+
+	if got, ok := ValueAtPath(fields, "address.city"); !ok || got != "Oslo" {
+		t.Errorf("address.city = %v, %v", got, ok)
+	}
+	if got, ok := ValueAtPath(fields, "authors[0].name"); !ok || got != "Snorri" {
+		t.Errorf("authors[0].name = %v, %v", got, ok)
+	}
+	for _, path := range []string{"authors[1].name", "address.zip", "address.city.name"} {
+		if got, ok := ValueAtPath(fields, path); ok {
+			t.Errorf("ValueAtPath(%q) = %v, want missing", path, got)
+		}
+	}
+}
+
+func TestValidateFieldValueObject(t *testing.T) {
+	t.Parallel()
+
+	def := &FieldDefinition{Type: FieldTypeObjectArray, Fields: map[string]*FieldDefinition{
+		"name": {Type: FieldTypeString, Required: true},
+		"year": {Type: FieldTypeNumber},
+	}}
+
+	valid := Array([]FieldValue{
+		Object(map[string]FieldValue{"name": String("Snorri"), "year": Number(1220), "note": String("extra keys are allowed")}),
+	})
+	if err := validateFieldValue("authors", valid, def); err != nil {
+		t.Fatalf("expected valid, got %v", err)
+	}
+
+	invalid := Array([]FieldValue{
+		Object(map[string]FieldValue{"name": String("Snorri")}),
+		Object(map[string]FieldValue{"year": String("long ago")}),
+	})
+	err := validateFieldValue("authors", invalid, def)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{"item 1", "field 'name' is required", "field 'year': expected number"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	if err := validateFieldValue("authors", String("Snorri"), def); err == nil {
+		t.Fatal("expected a scalar to be rejected for object[]")
+	}
+}
+
+func TestValidateSchemaNestedFieldDefinitions(t *testing.T) {
+	t.Parallel()
+
+	sch := New()
+	sch.Types["book"] = &TypeDefinition{Fields: map[string]*FieldDefinition{
+		"authors": {Type: FieldTypeObjectArray, Fields: map[string]*FieldDefinition{
+			"lead": {Type: FieldTypeRef, Target: "missing"},
+		}},
+		"title": {Type: FieldTypeString, Fields: map[string]*FieldDefinition{
+			"x": {Type: FieldTypeString},
+		}},
+	}}
+	sch.Traits["meta"] = &TraitDefinition{Type: FieldTypeObject}
+
+	issues := strings.Join(ValidateSchema(sch), "\n")
+	for _, want := range []string{
+		"field 'authors.lead' references unknown type 'missing'",
+		"field 'title' of type 'string' cannot define nested fields",
+		"Trait 'meta' has unknown trait type 'object'",
+	} {
+		if !strings.Contains(issues, want) {
+			t.Errorf("issues do not mention %q:\n%s", want, issues)
+		}
+	}
+}
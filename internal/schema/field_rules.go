@@ -0,0 +1,227 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aidanlsb/raven/internal/dates"
+)
+
+// Validation rules beyond the field type, reported as ValidationError.Rule.
+const (
+	RulePattern = "pattern"
+	RuleMin     = "min"
+	RuleMax     = "max"
+	RuleUnique  = "unique"
+)
+
+// ruleError is a field value that has the right type but breaks a rule.
+type ruleError struct {
+	rule    string
+	message string
+}
+
+func (e *ruleError) Error() string { return e.message }
+
+func ruleOf(err error) string {
+	var re *ruleError
+	if errors.As(err, &re) {
+		return re.rule
+	}
+	return ""
+}
+
+// UnmarshalYAML reads min and max as either numbers or dates, so the same
+// keys bound number, date, and datetime fields.
+func (d *FieldDefinition) UnmarshalYAML(node *yaml.Node) error {
+	type plain FieldDefinition
+	if err := node.Decode((*plain)(d)); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if key != "min" && key != "max" {
+			continue
+		}
+		number, date, err := decodeFieldBound(value)
+		if err != nil {
+			return fmt.Errorf("line %d: %s %w", value.Line, key, err)
+		}
+		if key == "min" {
+			d.Min, d.MinDate = number, date
+		} else {
+			d.Max, d.MaxDate = number, date
+		}
+	}
+	return nil
+}
+
+func decodeFieldBound(node *yaml.Node) (*float64, string, error) {
+	if node.Kind == yaml.ScalarNode {
+		if node.Tag == "!!int" || node.Tag == "!!float" {
+			var n float64
+			if err := node.Decode(&n); err == nil {
+				return &n, "", nil
+			}
+		}
+		s := strings.TrimSpace(node.Value)
+		if dates.IsValidDate(s) || dates.IsValidDatetime(s) {
+			return nil, s, nil
+		}
+	}
+	return nil, "", fmt.Errorf("must be a number or a date (YYYY-MM-DD), got %q", node.Value)
+}
+
+type compiledPattern struct {
+	re  *regexp.Regexp
+	err error
+}
+
+var patternCache sync.Map // pattern -> compiledPattern
+
+// fieldPattern compiles a field's pattern, anchored so it must match the
+// whole value.
+func fieldPattern(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := patternCache.Load(pattern); ok {
+		c := cached.(compiledPattern)
+		return c.re, c.err
+	}
+	re, err := regexp.Compile(pattern)
+	if err == nil {
+		re, err = regexp.Compile(`^(?:` + pattern + `)$`)
+	}
+	patternCache.Store(pattern, compiledPattern{re: re, err: err})
+	return re, err
+}
+
+func checkPattern(s string, def *FieldDefinition) error {
+	if def.Pattern == "" {
+		return nil
+	}
+	re, err := fieldPattern(def.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern in schema: %v", err)
+	}
+	if !re.MatchString(s) {
+		return &ruleError{rule: RulePattern, message: fmt.Sprintf("value '%s' does not match pattern %s", s, def.Pattern)}
+	}
+	return nil
+}
+
+func checkNumberBounds(n float64, def *FieldDefinition) error {
+	if def.Min != nil && n < *def.Min {
+		return &ruleError{rule: RuleMin, message: fmt.Sprintf("value %v is below minimum %v", n, *def.Min)}
+	}
+	if def.Max != nil && n > *def.Max {
+		return &ruleError{rule: RuleMax, message: fmt.Sprintf("value %v is above maximum %v", n, *def.Max)}
+	}
+	return nil
+}
+
+func checkDateBounds(s string, def *FieldDefinition) error {
+	if def.MinDate != "" && compareToDateBound(s, def.MinDate) < 0 {
+		return &ruleError{rule: RuleMin, message: fmt.Sprintf("value %s is before minimum %s", s, def.MinDate)}
+	}
+	if def.MaxDate != "" && compareToDateBound(s, def.MaxDate) > 0 {
+		return &ruleError{rule: RuleMax, message: fmt.Sprintf("value %s is after maximum %s", s, def.MaxDate)}
+	}
+	return nil
+}
+
+// compareToDateBound compares a date or datetime to a bound. A date bound
+// covers its whole day, so 2026-12-31T18:00 is not after max 2026-12-31.
+func compareToDateBound(value, bound string) int {
+	if dates.IsValidDate(bound) && len(value) >= len(bound) {
+		return strings.Compare(value[:len(bound)], bound)
+	}
+	v, err := parseDateOrDatetime(value)
+	if err != nil {
+		return 0
+	}
+	b, err := parseDateOrDatetime(bound)
+	if err != nil {
+		return 0
+	}
+	return v.Compare(b)
+}
+
+func parseDateOrDatetime(s string) (time.Time, error) {
+	if dates.IsValidDate(s) {
+		return time.Parse(dates.DateLayout, s)
+	}
+	return dates.ParseDatetime(s)
+}
+
+// UniqueValueKey returns the form in which values of a unique field are
+// compared: strings as written (trimmed), numbers in shortest decimal form.
+func UniqueValueKey(value FieldValue) (string, bool) {
+	if n, ok := value.AsNumber(); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64), true
+	}
+	if s, ok := value.AsString(); ok && strings.TrimSpace(s) != "" {
+		return strings.TrimSpace(s), true
+	}
+	return "", false
+}
+
+func canBeUnique(fieldType FieldType) bool {
+	switch fieldType {
+	case FieldTypeString, FieldTypeNumber, FieldTypeURL, FieldTypeDate, FieldTypeDatetime:
+		return true
+	}
+	return false
+}
+
+// validateFieldRuleDefinitions reports pattern, min, max, and unique settings
+// that do not fit the field.
+func validateFieldRuleDefinitions(typeName, fieldName string, def *FieldDefinition) []string {
+	var issues []string
+	prefix := fmt.Sprintf("Type '%s' field '%s'", typeName, fieldName)
+	isDate := def.Type == FieldTypeDate || def.Type == FieldTypeDatetime
+
+	if def.Pattern != "" {
+		if def.Type != FieldTypeString && def.Type != FieldTypeStringArray {
+			issues = append(issues, fmt.Sprintf("%s of type '%s' cannot have a pattern; patterns apply to string and string[]", prefix, def.Type))
+		} else if _, err := fieldPattern(def.Pattern); err != nil {
+			issues = append(issues, fmt.Sprintf("%s has an invalid pattern: %v", prefix, err))
+		}
+	}
+
+	if def.Min != nil || def.Max != nil || def.MinDate != "" || def.MaxDate != "" {
+		switch {
+		case def.Type == FieldTypeNumber:
+			if def.MinDate != "" || def.MaxDate != "" {
+				issues = append(issues, fmt.Sprintf("%s is a number; min and max must be numbers", prefix))
+			} else if def.Min != nil && def.Max != nil && *def.Min > *def.Max {
+				issues = append(issues, fmt.Sprintf("%s has min %v greater than max %v", prefix, *def.Min, *def.Max))
+			}
+		case isDate:
+			if def.Min != nil || def.Max != nil {
+				issues = append(issues, fmt.Sprintf("%s is a %s; min and max must be dates (YYYY-MM-DD)", prefix, def.Type))
+			} else if def.MinDate != "" && def.MaxDate != "" && compareToDateBound(def.MinDate, def.MaxDate) > 0 {
+				issues = append(issues, fmt.Sprintf("%s has min %s after max %s", prefix, def.MinDate, def.MaxDate))
+			}
+		default:
+			issues = append(issues, fmt.Sprintf("%s of type '%s' cannot have min or max; they apply to number, date, and datetime", prefix, def.Type))
+		}
+	}
+
+	if def.Unique {
+		switch {
+		case strings.Contains(fieldName, "."):
+			issues = append(issues, fmt.Sprintf("%s is nested and cannot be unique", prefix))
+		case !canBeUnique(def.Type):
+			issues = append(issues, fmt.Sprintf("%s of type '%s' cannot be unique; unique applies to string, number, url, date, and datetime", prefix, def.Type))
+		case def.Default != nil:
+			issues = append(issues, fmt.Sprintf("%s is unique and cannot have a default", prefix))
+		}
+	}
+	return issues
+}
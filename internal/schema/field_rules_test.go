@@ -0,0 +1,113 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFieldDefinitionBoundsYAML(t *testing.T) {
+	t.Parallel()
+
+	var fields map[string]*FieldDefinition
+	err := yaml.Unmarshal([]byte(`
+rating: {type: number, min: 1, max: 5.5}
+published: {type: date, min: 1900-01-01, max: "2030-12-31"}
+isbn: {type: string, pattern: '97[89]\d{10}', unique: true}
+`), &fields)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if r := fields["rating"]; r.Min == nil || *r.Min != 1 || r.Max == nil || *r.Max != 5.5 || r.MinDate != "" {
+		t.Fatalf("rating = %+v", r)
+	}
+	if p := fields["published"]; p.Min != nil || p.MinDate != "1900-01-01" || p.MaxDate != "2030-12-31" {
+		t.Fatalf("published = %+v", p)
+	}
+	if i := fields["isbn"]; i.Pattern != `97[89]\d{10}` || !i.Unique {
+		t.Fatalf("isbn = %+v", i)
+	}
+
+	err = yaml.Unmarshal([]byte("rating: {type: number, min: low}\n"), &fields)
+	if err == nil || !strings.Contains(err.Error(), "min must be a number or a date") {
+		t.Fatalf("expected bad bound error, got %v", err)
+	}
+}
+
+func TestValidateFieldRules(t *testing.T) {
+	t.Parallel()
+
+	minRating, maxRating := 1.0, 5.0
+	defs := map[string]*FieldDefinition{
+		"isbn":      {Type: FieldTypeString, Pattern: `97[89]\d{10}`},
+		"tags":      {Type: FieldTypeStringArray, Pattern: `[a-z-]+`},
+		"rating":    {Type: FieldTypeNumber, Min: &minRating, Max: &maxRating},
+		"published": {Type: FieldTypeDate, MinDate: "1900-01-01", MaxDate: "2026-12-31"},
+		"met":       {Type: FieldTypeDatetime, MaxDate: "2026-12-31"},
+	}
+
+	tests := []struct {
+		name  string
+		field string
+		value FieldValue
+		rule  string
+	}{
+		{"pattern match", "isbn", String("9780441013593"), ""},
+		{"pattern is anchored", "isbn", String("x9780441013593"), RulePattern},
+		{"pattern on array item", "tags", Array([]FieldValue{String("ok"), String("Not OK")}), RulePattern},
+		{"number below min", "rating", Number(0), RuleMin},
+		{"number above max", "rating", Number(6), RuleMax},
+		{"number in range", "rating", Number(5), ""},
+		{"date before min", "published", Date("1899-12-31"), RuleMin},
+		{"date after max", "published", Date("2027-01-01"), RuleMax},
+		{"datetime within max day", "met", Datetime("2026-12-31T18:00"), ""},
+		{"datetime after max day", "met", Datetime("2027-01-01T00:00"), RuleMax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateFields(map[string]FieldValue{tt.field: tt.value}, map[string]*FieldDefinition{tt.field: defs[tt.field]}, nil)
+			if tt.rule == "" {
+				if len(errs) != 0 {
+					t.Fatalf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Rule != tt.rule {
+				t.Fatalf("expected one %s error, got %+v", tt.rule, errs)
+			}
+		})
+	}
+}
+
+func TestValidateSchemaFieldRules(t *testing.T) {
+	t.Parallel()
+
+	lo, hi := 5.0, 1.0
+	sch := &Schema{
+		Types: map[string]*TypeDefinition{
+			"book": {
+				Fields: map[string]*FieldDefinition{
+					"title":     {Type: FieldTypeString, Pattern: "(unclosed"},
+					"done":      {Type: FieldTypeBool, Pattern: "true", Unique: true},
+					"rating":    {Type: FieldTypeNumber, Min: &lo, Max: &hi},
+					"published": {Type: FieldTypeDate, Min: &lo},
+					"isbn":      {Type: FieldTypeString, Unique: true, Default: "none"},
+				},
+			},
+		},
+	}
+	issues := strings.Join(ValidateSchema(sch), "\n")
+	for _, want := range []string{
+		"field 'title' has an invalid pattern",
+		"field 'done' of type 'bool' cannot have a pattern",
+		"field 'done' of type 'bool' cannot be unique",
+		"field 'rating' has min 5 greater than max 1",
+		"field 'published' is a date; min and max must be dates",
+		"field 'isbn' is unique and cannot have a default",
+	} {
+		if !strings.Contains(issues, want) {
+			t.Errorf("missing issue %q in:\n%s", want, issues)
+		}
+	}
+}
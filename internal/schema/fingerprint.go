@@ -0,0 +1,111 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// Fingerprint identifies the parts of a schema that affect how files are
+// indexed and validated. Descriptions and template bindings are left out, so
+// editing them does not make indexed files look out of date.
+type Fingerprint struct {
+	// Version is the schema format version from schema.yaml.
+	Version int `json:"version"`
+	// Hash covers every type and trait definition below.
+	Hash string `json:"hash"`
+	// Types and Traits hold a hash per definition, keyed by name.
+	Types  map[string]string `json:"types"`
+	Traits map[string]string `json:"traits"`
+}
+
+// Drift lists the definitions that differ between two fingerprints.
+type Drift struct {
+	Types  []string `json:"types,omitempty"`
+	Traits []string `json:"traits,omitempty"`
+}
+
+// IsEmpty reports whether no definitions changed.
+func (d Drift) IsEmpty() bool {
+	return len(d.Types) == 0 && len(d.Traits) == 0
+}
+
+// Fingerprint computes the schema's fingerprint.
+func (s *Schema) Fingerprint() Fingerprint {
+	fp := Fingerprint{Types: map[string]string{}, Traits: map[string]string{}}
+	if s == nil {
+		fp.Hash = hashDefinition(fp)
+		return fp
+	}
+	fp.Version = s.Version
+	for name, def := range s.Types {
+		fp.Types[name] = hashDefinition(indexedTypeDefinition(def))
+	}
+	for name, def := range s.Traits {
+		fp.Traits[name] = hashDefinition(def)
+	}
+	fp.Hash = hashDefinition(struct {
+		Version int
+		Types   map[string]string
+		Traits  map[string]string
+	}{fp.Version, fp.Types, fp.Traits})
+	return fp
+}
+
+// DriftSince returns the types and traits that were added, removed, or changed
+// since prev was taken.
+func (f Fingerprint) DriftSince(prev Fingerprint) Drift {
+	return Drift{
+		Types:  changedKeys(prev.Types, f.Types),
+		Traits: changedKeys(prev.Traits, f.Traits),
+	}
+}
+
+// indexedTypeDefinition strips the settings of a type that do not change how
+// its objects are parsed, indexed, or validated.
+func indexedTypeDefinition(def *TypeDefinition) interface{} {
+	if def == nil {
+		return nil
+	}
+	fields := make(map[string]FieldDefinition, len(def.Fields))
+	for name, field := range def.Fields {
+		if field == nil {
+			continue
+		}
+		copied := *field
+		copied.Description = ""
+		fields[name] = copied
+	}
+	return struct {
+		Fields      map[string]FieldDefinition
+		DefaultPath string
+		NameField   string
+	}{fields, def.DefaultPath, def.NameField}
+}
+
+func hashDefinition(v interface{}) string {
+	// encoding/json sorts map keys, so equal definitions hash equally.
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+func changedKeys(prev, next map[string]string) []string {
+	var changed []string
+	for name, hash := range next {
+		if prev[name] != hash {
+			changed = append(changed, name)
+		}
+	}
+	for name := range prev {
+		if _, ok := next[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,95 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// extendsChain returns the ancestors of a type, nearest first, following
+// extends. It fails on unknown or built-in parents and on cycles.
+func extendsChain(types map[string]*TypeDefinition, typeName string) ([]string, error) {
+	var chain []string
+	seen := map[string]bool{typeName: true}
+	current := types[typeName]
+	for current != nil && current.Extends != "" {
+		parent := current.Extends
+		if IsBuiltinType(parent) {
+			return nil, fmt.Errorf("extends core type '%s'; only schema types can be extended", parent)
+		}
+		if seen[parent] {
+			return nil, fmt.Errorf("has a cycle in extends (%s -> %s)", strings.Join(append([]string{typeName}, chain...), " -> "), parent)
+		}
+		if types[parent] == nil {
+			return nil, fmt.Errorf("extends unknown type '%s'", parent)
+		}
+		seen[parent] = true
+		chain = append(chain, parent)
+		current = types[parent]
+	}
+	return chain, nil
+}
+
+// ExtendsError reports why a type's extends chain is invalid, or nil when it
+// resolves. Such types load unflattened so check can report the problem.
+func ExtendsError(types map[string]*TypeDefinition, typeName string) error {
+	_, err := extendsChain(types, typeName)
+	return err
+}
+
+// applyInheritance copies fields, required traits, and name_field from each
+// type's ancestors into the type. A type's own definitions win over inherited
+// ones; InheritedFields records which ancestor each copied field came from.
+// Types whose chain is invalid are left as declared.
+func applyInheritance(types map[string]*TypeDefinition) {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, typeName := range names {
+		typeDef := types[typeName]
+		if typeDef.Extends == "" {
+			continue
+		}
+		chain, err := extendsChain(types, typeName)
+		if err != nil {
+			continue
+		}
+		typeDef.InheritedFields = make(map[string]string)
+		traits := append([]string(nil), typeDef.RequiredTraits...)
+		for _, ancestor := range chain {
+			ancestorDef := types[ancestor]
+			for fieldName, fieldDef := range ancestorDef.Fields {
+				// Skip fields the ancestor itself inherited; the chain
+				// reaches the type that defines them.
+				if _, ok := typeDef.Fields[fieldName]; ok || ancestorDef.InheritedFields[fieldName] != "" {
+					continue
+				}
+				copied := *fieldDef
+				typeDef.Fields[fieldName] = &copied
+				typeDef.InheritedFields[fieldName] = ancestor
+			}
+			if typeDef.NameField == "" {
+				typeDef.NameField = ancestorDef.NameField
+			}
+			traits = append(traits, ancestorDef.RequiredTraits...)
+		}
+		typeDef.RequiredTraits = dedupeStrings(traits)
+	}
+}
+
+func dedupeStrings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadFlattensExtends(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	schemaContent := `
+version: 1
+types:
+  event:
+    name_field: title
+    required_traits: [reviewed]
+    fields:
+      title: {type: string, required: true}
+      where: {type: string}
+  meeting:
+    extends: event
+    required_traits: [status]
+    fields:
+      where: {type: ref, target: room}
+  standup:
+    extends: meeting
+    fields:
+      team: {type: string}
+  room: {}
+traits:
+  reviewed: {}
+  status: {type: enum, values: [todo, done]}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "schema.yaml"), []byte(schemaContent), 0644); err != nil {
+		t.Fatalf("write schema: %v", err)
+	}
+
+	sch, err := Load(tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	standup := sch.Types["standup"]
+	if standup.NameField != "title" || standup.Fields["title"] == nil || !standup.Fields["title"].Required {
+		t.Fatalf("standup should inherit title as name field, got %+v", standup)
+	}
+	if standup.Fields["where"].Type != FieldTypeRef {
+		t.Errorf("standup should inherit meeting's override of where, got %s", standup.Fields["where"].Type)
+	}
+	want := map[string]string{"title": "event", "where": "meeting"}
+	if !reflect.DeepEqual(standup.InheritedFields, want) {
+		t.Errorf("InheritedFields = %v, want %v", standup.InheritedFields, want)
+	}
+	if !reflect.DeepEqual(standup.RequiredTraits, []string{"status", "reviewed"}) {
+		t.Errorf("RequiredTraits = %v", standup.RequiredTraits)
+	}
+	if _, inherited := sch.Types["meeting"].InheritedFields["where"]; inherited {
+		t.Error("meeting declares where itself and should not mark it inherited")
+	}
+
+	standup.Fields["title"].Description = "changed"
+	if sch.Types["event"].Fields["title"].Description != "" {
+		t.Error("inherited fields should be copies, not shared with the parent")
+	}
+}
+
+func TestExtendsErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		types map[string]*TypeDefinition
+		want  string
+	}{
+		{
+			name:  "unknown parent",
+			types: map[string]*TypeDefinition{"meeting": {Extends: "event"}},
+			want:  "extends unknown type 'event'",
+		},
+		{
+			name:  "core parent",
+			types: map[string]*TypeDefinition{"note": {Extends: "page"}},
+			want:  "extends core type 'page'",
+		},
+		{
+			name: "cycle",
+			types: map[string]*TypeDefinition{
+				"a": {Extends: "b"},
+				"b": {Extends: "a"},
+			},
+			want: "has a cycle in extends (a -> b -> a)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := strings.Join(ValidateSchema(&Schema{Types: tt.types}), "\n")
+			if !strings.Contains(issues, tt.want) {
+				t.Errorf("ValidateSchema issues %q, want %q", issues, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadKeepsTypesWithBrokenExtends(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+	schemaContent := `
+version: 1
+types:
+  meeting:
+    extends: event
+    fields:
+      room: {type: string}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "schema.yaml"), []byte(schemaContent), 0644); err != nil {
+		t.Fatalf("write schema: %v", err)
+	}
+
+	sch, err := Load(tmpDir)
+	if err != nil {
+		t.Fatalf("unknown parent should not fail the load: %v", err)
+	}
+	meeting := sch.Types["meeting"]
+	if meeting.Fields["room"] == nil || len(meeting.InheritedFields) != 0 {
+		t.Fatalf("meeting should load as declared, got %+v", meeting)
+	}
+}
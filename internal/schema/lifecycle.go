@@ -0,0 +1,169 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultLifecycleField is the field that holds an object's lifecycle state
+// when the lifecycle does not name one.
+const DefaultLifecycleField = "status"
+
+// DefaultArchiveState is the state `rvn archive` moves objects to when the
+// lifecycle does not name one.
+const DefaultArchiveState = "archived"
+
+// LifecycleDefinition declares the states objects of a type move through.
+type LifecycleDefinition struct {
+	// Field holds the state (default: status). If the type does not define
+	// it, it is added as an enum field of the states.
+	Field string `yaml:"field,omitempty"`
+	// States lists every state, in order.
+	States []string `yaml:"states"`
+	// Transitions maps a state to the states it may move to. A state with no
+	// entry is final. When Transitions is empty, any transition is allowed.
+	Transitions map[string][]string `yaml:"transitions,omitempty"`
+	// ArchiveState is the state `rvn archive` sets (default: archived).
+	ArchiveState string `yaml:"archive_state,omitempty"`
+	// ArchiveDirectory is where `rvn archive` moves archived objects. Empty
+	// leaves them in place.
+	ArchiveDirectory string `yaml:"archive_directory,omitempty"`
+}
+
+// StateField returns the field that holds the lifecycle state.
+func (l *LifecycleDefinition) StateField() string {
+	if l == nil || strings.TrimSpace(l.Field) == "" {
+		return DefaultLifecycleField
+	}
+	return strings.TrimSpace(l.Field)
+}
+
+// HasState reports whether state is one of the lifecycle's states.
+func (l *LifecycleDefinition) HasState(state string) bool {
+	if l == nil {
+		return false
+	}
+	for _, s := range l.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedTransitions returns the states an object in from may move to. An
+// object with no state yet may take any state.
+func (l *LifecycleDefinition) AllowedTransitions(from string) []string {
+	if l == nil {
+		return nil
+	}
+	if from == "" || len(l.Transitions) == 0 {
+		return append([]string(nil), l.States...)
+	}
+	return append([]string(nil), l.Transitions[from]...)
+}
+
+// CheckTransition returns an error when moving from one state to another is
+// not allowed. Staying in the same state is always allowed, and a current
+// value outside the lifecycle can move to any state.
+func (l *LifecycleDefinition) CheckTransition(from, to string) error {
+	if l == nil || from == to {
+		return nil
+	}
+	if !l.HasState(to) {
+		return fmt.Errorf("'%s' is not a lifecycle state (use %s)", to, strings.Join(l.States, ", "))
+	}
+	if !l.HasState(from) {
+		return nil
+	}
+	allowed := l.AllowedTransitions(from)
+	for _, state := range allowed {
+		if state == to {
+			return nil
+		}
+	}
+	if len(allowed) == 0 {
+		return fmt.Errorf("cannot move from '%s' to '%s': '%s' is a final state", from, to, from)
+	}
+	return fmt.Errorf("cannot move from '%s' to '%s' (allowed: %s)", from, to, strings.Join(allowed, ", "))
+}
+
+// ArchiveTarget returns the state `rvn archive` sets.
+func (l *LifecycleDefinition) ArchiveTarget() string {
+	if l == nil || strings.TrimSpace(l.ArchiveState) == "" {
+		return DefaultArchiveState
+	}
+	return strings.TrimSpace(l.ArchiveState)
+}
+
+// applyLifecycleField adds the lifecycle field as an enum of the states when
+// the type does not define it.
+func applyLifecycleField(typeDef *TypeDefinition) {
+	if typeDef.Lifecycle == nil || len(typeDef.Lifecycle.States) == 0 {
+		return
+	}
+	field := typeDef.Lifecycle.StateField()
+	if _, ok := typeDef.Fields[field]; ok {
+		return
+	}
+	typeDef.Fields[field] = &FieldDefinition{
+		Type:   FieldTypeEnum,
+		Values: append([]string(nil), typeDef.Lifecycle.States...),
+	}
+}
+
+func validateLifecycle(typeDef *TypeDefinition) error {
+	l := typeDef.Lifecycle
+	if l == nil {
+		return nil
+	}
+	if len(l.States) == 0 {
+		return fmt.Errorf("lifecycle must list at least one state")
+	}
+	seen := make(map[string]bool, len(l.States))
+	for _, state := range l.States {
+		if strings.TrimSpace(state) == "" {
+			return fmt.Errorf("lifecycle states must not be empty")
+		}
+		if seen[state] {
+			return fmt.Errorf("lifecycle state '%s' is listed twice", state)
+		}
+		seen[state] = true
+	}
+
+	field := l.StateField()
+	fieldDef := typeDef.Fields[field]
+	if fieldDef == nil {
+		return fmt.Errorf("lifecycle field '%s' is not defined", field)
+	}
+	switch fieldDef.Type {
+	case FieldTypeString:
+	case FieldTypeEnum:
+		values := make(map[string]bool, len(fieldDef.Values))
+		for _, value := range fieldDef.Values {
+			values[value] = true
+		}
+		for _, state := range l.States {
+			if !values[state] {
+				return fmt.Errorf("lifecycle state '%s' is not a value of enum field '%s'", state, field)
+			}
+		}
+	default:
+		return fmt.Errorf("lifecycle field '%s' must be an enum or string field, got '%s'", field, fieldDef.Type)
+	}
+
+	for from, targets := range l.Transitions {
+		if !seen[from] {
+			return fmt.Errorf("lifecycle transition from unknown state '%s'", from)
+		}
+		for _, to := range targets {
+			if !seen[to] {
+				return fmt.Errorf("lifecycle transition from '%s' to unknown state '%s'", from, to)
+			}
+		}
+	}
+	if (strings.TrimSpace(l.ArchiveState) != "" || strings.TrimSpace(l.ArchiveDirectory) != "") && !seen[l.ArchiveTarget()] {
+		return fmt.Errorf("lifecycle archive state '%s' is not a lifecycle state", l.ArchiveTarget())
+	}
+	return nil
+}
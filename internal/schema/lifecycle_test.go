@@ -0,0 +1,76 @@
+package schema
+
+import "testing"
+
+func TestLifecycleCheckTransition(t *testing.T) {
+	t.Parallel()
+
+	l := &LifecycleDefinition{
+		States: []string{"draft", "active", "archived"},
+		Transitions: map[string][]string{
+			"draft":  {"active"},
+			"active": {"archived"},
+		},
+	}
+	tests := []struct {
+		from, to string
+		wantErr  bool
+	}{
+		{from: "draft", to: "active"},
+		{from: "active", to: "active"},
+		{from: "", to: "archived"},
+		{from: "legacy", to: "draft"},
+		{from: "draft", to: "archived", wantErr: true},
+		{from: "archived", to: "active", wantErr: true},
+		{from: "active", to: "done", wantErr: true},
+	}
+	for _, tt := range tests {
+		err := l.CheckTransition(tt.from, tt.to)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckTransition(%q, %q) error = %v, wantErr %v", tt.from, tt.to, err, tt.wantErr)
+		}
+	}
+
+	open := &LifecycleDefinition{States: []string{"draft", "archived"}}
+	if err := open.CheckTransition("archived", "draft"); err != nil {
+		t.Errorf("lifecycle without transitions rejected a move: %v", err)
+	}
+}
+
+func TestLifecycleAddsStateFieldAndValidates(t *testing.T) {
+	t.Parallel()
+
+	typeDef := &TypeDefinition{
+		Fields:    map[string]*FieldDefinition{},
+		Lifecycle: &LifecycleDefinition{States: []string{"draft", "archived"}},
+	}
+	applyLifecycleField(typeDef)
+	status := typeDef.Fields[DefaultLifecycleField]
+	if status == nil || status.Type != FieldTypeEnum || len(status.Values) != 2 {
+		t.Fatalf("status field = %#v, want enum of the states", status)
+	}
+	if err := validateLifecycle(typeDef); err != nil {
+		t.Fatalf("validateLifecycle() error = %v", err)
+	}
+
+	invalid := []*LifecycleDefinition{
+		{},
+		{States: []string{"draft", "draft"}},
+		{States: []string{"draft"}, Transitions: map[string][]string{"draft": {"done"}}},
+		{States: []string{"draft"}, ArchiveDirectory: "archive"},
+	}
+	for _, l := range invalid {
+		def := &TypeDefinition{Fields: map[string]*FieldDefinition{"status": {Type: FieldTypeString}}, Lifecycle: l}
+		if err := validateLifecycle(def); err == nil {
+			t.Errorf("validateLifecycle(%+v) succeeded, want error", l)
+		}
+	}
+
+	enum := &TypeDefinition{
+		Fields:    map[string]*FieldDefinition{"status": {Type: FieldTypeEnum, Values: []string{"draft"}}},
+		Lifecycle: &LifecycleDefinition{States: []string{"draft", "archived"}},
+	}
+	if err := validateLifecycle(enum); err == nil {
+		t.Error("validateLifecycle accepted a state missing from the enum field")
+	}
+}
@@ -176,6 +176,10 @@ func LoadWithWarnings(vaultPath string) (*LoadResult, error) {
 			fieldDef.Type = normalizeFieldType(fieldDef.Type)
 		}
 	}
+	applyInheritance(schema.Types)
+	for _, typeDef := range schema.Types {
+		applyLifecycleField(typeDef)
+	}
 	for traitName, traitDef := range schema.Traits {
 		if traitDef == nil {
 			return nil, fmt.Errorf("trait %q is null; expected an object definition", traitName)
@@ -0,0 +1,89 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Review bases for TypeDefinition.ReviewFrom.
+const (
+	ReviewFromModified = "modified"
+	ReviewFromCreated  = "created"
+)
+
+// ParseReviewWindow parses a review_after duration: a count followed by d, w,
+// or y (e.g. 90d, 6w, 1y). A year is 365 days.
+func ParseReviewWindow(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	invalid := fmt.Errorf("invalid review_after %q (use a count with d, w, or y, e.g. 90d or 1y)", value)
+	if len(value) < 2 {
+		return 0, invalid
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n <= 0 {
+		return 0, invalid
+	}
+	day := 24 * time.Hour
+	switch strings.ToLower(value[len(value)-1:]) {
+	case "d":
+		return time.Duration(n) * day, nil
+	case "w":
+		return time.Duration(n) * 7 * day, nil
+	case "y":
+		return time.Duration(n) * 365 * day, nil
+	default:
+		return 0, invalid
+	}
+}
+
+// ReviewPolicy returns the parsed review window and the timestamp it counts
+// from. ok is false when the type has no valid review_after policy.
+func (t *TypeDefinition) ReviewPolicy() (window time.Duration, from string, ok bool) {
+	if t == nil || strings.TrimSpace(t.ReviewAfter) == "" {
+		return 0, "", false
+	}
+	window, err := ParseReviewWindow(t.ReviewAfter)
+	if err != nil {
+		return 0, "", false
+	}
+	from = strings.TrimSpace(t.ReviewFrom)
+	if from == "" {
+		from = ReviewFromModified
+	}
+	return window, from, true
+}
+
+// ReviewExpiry returns when an object of this type falls due for review,
+// given its file modification and creation times. A zero created time falls
+// back to modified.
+func (t *TypeDefinition) ReviewExpiry(modified, created time.Time) (time.Time, bool) {
+	window, from, ok := t.ReviewPolicy()
+	if !ok {
+		return time.Time{}, false
+	}
+	base := modified
+	if from == ReviewFromCreated && !created.IsZero() {
+		base = created
+	}
+	return base.Add(window), true
+}
+
+func validateReviewPolicy(typeDef *TypeDefinition) error {
+	if strings.TrimSpace(typeDef.ReviewAfter) == "" {
+		if strings.TrimSpace(typeDef.ReviewFrom) != "" {
+			return fmt.Errorf("review_from requires review_after")
+		}
+		return nil
+	}
+	if _, err := ParseReviewWindow(typeDef.ReviewAfter); err != nil {
+		return err
+	}
+	switch strings.TrimSpace(typeDef.ReviewFrom) {
+	case "", ReviewFromModified, ReviewFromCreated:
+		return nil
+	default:
+		return fmt.Errorf("invalid review_from %q (use modified or created)", typeDef.ReviewFrom)
+	}
+}
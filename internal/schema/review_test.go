@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseReviewWindow(t *testing.T) {
+	t.Parallel()
+
+	day := 24 * time.Hour
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "90d", want: 90 * day},
+		{value: "6w", want: 42 * day},
+		{value: "1Y", want: 365 * day},
+		{value: "0d", wantErr: true},
+		{value: "12h", wantErr: true},
+		{value: "yearly", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseReviewWindow(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseReviewWindow(%q) = %v, want error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseReviewWindow(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestReviewExpiry(t *testing.T) {
+	t.Parallel()
+
+	modified := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	byModified := &TypeDefinition{ReviewAfter: "30d"}
+	if got, ok := byModified.ReviewExpiry(modified, created); !ok || !got.Equal(modified.AddDate(0, 0, 30)) {
+		t.Fatalf("modified basis expiry = %v, %v", got, ok)
+	}
+
+	byCreated := &TypeDefinition{ReviewAfter: "1y", ReviewFrom: ReviewFromCreated}
+	if got, ok := byCreated.ReviewExpiry(modified, created); !ok || !got.Equal(created.AddDate(1, 0, 0)) {
+		t.Fatalf("created basis expiry = %v, %v", got, ok)
+	}
+	if got, ok := byCreated.ReviewExpiry(modified, time.Time{}); !ok || !got.Equal(modified.AddDate(1, 0, 0)) {
+		t.Fatalf("created basis without created time = %v, %v; want modified fallback", got, ok)
+	}
+
+	if _, ok := (&TypeDefinition{}).ReviewExpiry(modified, created); ok {
+		t.Fatal("expected no expiry without review_after")
+	}
+}
+
+func TestValidateSchemaReviewPolicy(t *testing.T) {
+	t.Parallel()
+
+	sch := &Schema{Types: map[string]*TypeDefinition{
+		"person":  {ReviewAfter: "1y", ReviewFrom: "created"},
+		"project": {ReviewAfter: "soon"},
+		"note":    {ReviewFrom: "modified"},
+		"meeting": {ReviewAfter: "30d", ReviewFrom: "touched"},
+	}}
+	issues := ValidateSchema(sch)
+	if len(issues) != 3 {
+		t.Fatalf("ValidateSchema() issues = %#v, want 3 review policy issues", issues)
+	}
+}
@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/dates"
+)
+
+// NormalizeValue applies the trait's normalization rules to a scalar value.
+// Values are returned unchanged when the trait has no rules configured.
+func (td *TraitDefinition) NormalizeValue(value string) string {
+	if td == nil || td.Normalize == nil {
+		return value
+	}
+	rules := td.Normalize
+
+	if rules.Trim {
+		value = strings.TrimSpace(value)
+	}
+	if rules.Lowercase {
+		value = strings.ToLower(value)
+	}
+	if rules.Dates {
+		if canonical, ok := dates.CanonicalizeDate(value); ok {
+			value = canonical
+		}
+	}
+	if len(rules.Synonyms) > 0 {
+		key := strings.ToLower(strings.TrimSpace(value))
+		for synonym, target := range rules.Synonyms {
+			if strings.ToLower(strings.TrimSpace(synonym)) == key {
+				return target
+			}
+		}
+	}
+	return value
+}
+
+// NormalizeFieldValue applies NormalizeValue to string values, leaving numbers,
+// booleans, references, and arrays untouched. The second result reports
+// whether the value changed.
+func (td *TraitDefinition) NormalizeFieldValue(value FieldValue) (FieldValue, bool) {
+	if td == nil || td.Normalize == nil || value.IsRef() || value.IsDatetime() {
+		return value, false
+	}
+	s, ok := value.AsString()
+	if !ok {
+		return value, false
+	}
+	normalized := td.NormalizeValue(s)
+	if normalized == s {
+		return value, false
+	}
+	if dates.IsValidDate(normalized) {
+		return Date(normalized), true
+	}
+	return String(normalized), true
+}
@@ -0,0 +1,90 @@
+package schema
+
+import "testing"
+
+func TestTraitDefinitionNormalizeValue(t *testing.T) {
+	t.Parallel()
+	priority := &TraitDefinition{
+		Type:   FieldTypeEnum,
+		Values: []string{"low", "medium", "high"},
+		Normalize: &TraitNormalization{
+			Trim:      true,
+			Lowercase: true,
+			Synonyms:  map[string]string{"hi": "high", "Med": "medium"},
+		},
+	}
+	due := &TraitDefinition{
+		Type:      FieldTypeDate,
+		Normalize: &TraitNormalization{Dates: true},
+	}
+
+	tests := []struct {
+		name  string
+		def   *TraitDefinition
+		input string
+		want  string
+	}{
+		{"no rules", &TraitDefinition{Type: FieldTypeString}, " High ", " High "},
+		{"trim and lowercase", priority, "  HIGH ", "high"},
+		{"synonym", priority, "hi", "high"},
+		{"synonym key is case-insensitive", priority, "MED", "medium"},
+		{"unknown value passes through", priority, "Urgent", "urgent"},
+		{"loose date", due, "2025/2/1", "2025-02-01"},
+		{"named month date", due, "Feb 1, 2025", "2025-02-01"},
+		{"unparseable date passes through", due, "someday", "someday"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.def.NormalizeValue(tt.input); got != tt.want {
+				t.Errorf("NormalizeValue(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraitDefinitionNormalizeFieldValue(t *testing.T) {
+	t.Parallel()
+	def := &TraitDefinition{
+		Type:      FieldTypeDate,
+		Normalize: &TraitNormalization{Trim: true, Lowercase: true, Dates: true},
+	}
+
+	got, changed := def.NormalizeFieldValue(String("2025-2-1"))
+	if !changed || !got.IsDate() {
+		t.Fatalf("expected loose date to become a date value, got %#v changed=%v", got.Raw(), changed)
+	}
+
+	if _, changed := def.NormalizeFieldValue(Date("2025-02-01")); changed {
+		t.Error("canonical date should not be reported as changed")
+	}
+	if _, changed := def.NormalizeFieldValue(Datetime("2025-02-01T10:30")); changed {
+		t.Error("datetime values should be left untouched")
+	}
+	if _, changed := def.NormalizeFieldValue(Ref("People/Freya")); changed {
+		t.Error("reference values should be left untouched")
+	}
+}
+
+func TestValidateSchemaTraitSynonyms(t *testing.T) {
+	t.Parallel()
+	sch := &Schema{
+		Types: map[string]*TypeDefinition{},
+		Traits: map[string]*TraitDefinition{
+			"priority": {
+				Type:   FieldTypeEnum,
+				Values: []string{"low", "high"},
+				Normalize: &TraitNormalization{
+					Synonyms: map[string]string{"hi": "high", "urgent": "critical"},
+				},
+			},
+		},
+	}
+
+	issues := ValidateSchema(sch)
+	if len(issues) != 1 {
+		t.Fatalf("expected one issue for the undeclared synonym target, got %v", issues)
+	}
+}
@@ -51,8 +51,8 @@ func ValidateTraitValue(def *TraitDefinition, value FieldValue) error {
 		return nil
 	case FieldTypeDate:
 		s, ok := value.AsString()
-		if !ok || !dates.IsValidDate(s) {
-			return fmt.Errorf("invalid date format %q (expected YYYY-MM-DD)", traitValueDisplay(value))
+		if !ok || (!dates.IsValidDate(s) && !dates.IsRecurringDate(s)) {
+			return fmt.Errorf("invalid date format %q (expected YYYY-MM-DD or a recurrence like every:friday or 2026-03-01, repeat:monthly)", traitValueDisplay(value))
 		}
 		return nil
 	case FieldTypeDatetime:
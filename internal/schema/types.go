@@ -89,6 +89,12 @@ type CoreTypeDefinition struct {
 type TypeDefinition struct {
 	Fields      map[string]*FieldDefinition `yaml:"fields"`
 	DefaultPath string                      `yaml:"default_path,omitempty"`
+	// Extends names a type whose fields, required traits, and name_field this
+	// type inherits. The loader flattens inherited definitions into Fields.
+	Extends string `yaml:"extends,omitempty"`
+	// InheritedFields maps each field copied from an ancestor to the type
+	// that defines it. Set by the loader; empty for fields the type declares.
+	InheritedFields map[string]string `yaml:"-"`
 	// Description provides optional context for humans/agents about this type's purpose.
 	Description string `yaml:"description,omitempty"`
 	// NameField specifies which field serves as the display name for this type.
@@ -104,6 +110,18 @@ type TypeDefinition struct {
 	// DefaultTemplate selects the template ID from Templates that is applied by default.
 	// If empty, object creation proceeds without a template unless explicitly selected.
 	DefaultTemplate string `yaml:"default_template,omitempty"`
+	// ReviewAfter marks objects of this type as due for review once this long
+	// has passed since ReviewFrom (e.g. "90d", "1y"). Empty disables reviews.
+	ReviewAfter string `yaml:"review_after,omitempty"`
+	// ReviewFrom is the file timestamp ReviewAfter counts from: "modified"
+	// (default) or "created".
+	ReviewFrom string `yaml:"review_from,omitempty"`
+	// Lifecycle declares the states objects of this type move through and
+	// the transitions allowed between them.
+	Lifecycle *LifecycleDefinition `yaml:"lifecycle,omitempty"`
+	// RequiredTraits lists traits that must appear somewhere in every object
+	// of this type.
+	RequiredTraits []string `yaml:"required_traits,omitempty"`
 }
 
 // TemplateDefinition defines a schema-level template that can be bound to one or more types.
@@ -126,6 +144,23 @@ type TraitDefinition struct {
 
 	// Default is the default value if none provided.
 	Default interface{} `yaml:"default,omitempty"`
+
+	// Normalize configures how hand-typed values are cleaned up at index time.
+	Normalize *TraitNormalization `yaml:"normalize,omitempty"`
+}
+
+// TraitNormalization lists the rewrites applied to a trait value before it is
+// indexed. The original value is kept alongside the normalized one.
+type TraitNormalization struct {
+	// Trim removes surrounding whitespace.
+	Trim bool `yaml:"trim,omitempty"`
+	// Lowercase folds the value to lower case.
+	Lowercase bool `yaml:"lowercase,omitempty"`
+	// Dates rewrites loosely formatted dates (2025/2/1, Feb 1, 2025) as YYYY-MM-DD.
+	Dates bool `yaml:"dates,omitempty"`
+	// Synonyms maps alternate spellings to a canonical value (e.g., hi: high).
+	// Keys match case-insensitively, ignoring surrounding whitespace.
+	Synonyms map[string]string `yaml:"synonyms,omitempty"`
 }
 
 // IsBoolean returns true if this trait is a boolean/marker trait.
@@ -142,10 +177,16 @@ type FieldDefinition struct {
 	Target   string      `yaml:"target,omitempty"` // For ref types
 	// Description provides optional context for humans/agents about this field.
 	Description string   `yaml:"description,omitempty"`
-	Min         *float64 `yaml:"min,omitempty"`        // For number types
-	Max         *float64 `yaml:"max,omitempty"`        // For number types
+	Min         *float64 `yaml:"-"`                    // For number types; read from min
+	Max         *float64 `yaml:"-"`                    // For number types; read from max
+	MinDate     string   `yaml:"-"`                    // For date and datetime types; read from min
+	MaxDate     string   `yaml:"-"`                    // For date and datetime types; read from max
+	Pattern     string   `yaml:"pattern,omitempty"`    // For string types: regular expression values must match
+	Unique      bool     `yaml:"unique,omitempty"`     // No two objects of the type share a value
 	Derived     string   `yaml:"derived,omitempty"`    // How to compute value
 	Positional  bool     `yaml:"positional,omitempty"` // For traits: positional argument
+	// Fields defines the keys of object and object[] values.
+	Fields map[string]*FieldDefinition `yaml:"fields,omitempty"`
 }
 
 // FieldType represents the type of a field.
@@ -168,6 +209,8 @@ const (
 	FieldTypeBoolArray     FieldType = "bool[]"
 	FieldTypeRef           FieldType = "ref"
 	FieldTypeRefArray      FieldType = "ref[]"
+	FieldTypeObject        FieldType = "object"
+	FieldTypeObjectArray   FieldType = "object[]"
 )
 
 // FieldValue represents a parsed field value.
@@ -215,6 +258,11 @@ func Array(items []FieldValue) FieldValue {
 	return FieldValue{value: items}
 }
 
+// Object creates a nested object FieldValue.
+func Object(fields map[string]FieldValue) FieldValue {
+	return FieldValue{value: fields}
+}
+
 // Null creates a null FieldValue.
 func Null() FieldValue {
 	return FieldValue{value: nil}
@@ -269,6 +317,14 @@ func (fv FieldValue) AsArray() ([]FieldValue, bool) {
 	return nil, false
 }
 
+// AsObject returns the value as a nested object, if possible.
+func (fv FieldValue) AsObject() (map[string]FieldValue, bool) {
+	if obj, ok := fv.value.(map[string]FieldValue); ok {
+		return obj, true
+	}
+	return nil, false
+}
+
 // AsRef returns the value as a reference path, if possible.
 func (fv FieldValue) AsRef() (string, bool) {
 	if r, ok := fv.value.(refValue); ok {
@@ -310,6 +366,12 @@ func (fv FieldValue) Raw() interface{} {
 			result[i] = item.Raw()
 		}
 		return result
+	case map[string]FieldValue:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = item.Raw()
+		}
+		return result
 	default:
 		return v
 	}
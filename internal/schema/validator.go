@@ -2,6 +2,8 @@ package schema
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/aidanlsb/raven/internal/dates"
@@ -11,6 +13,7 @@ import (
 type ValidationError struct {
 	Field   string
 	Message string
+	Rule    string // Rule the value breaks (RulePattern, RuleMin, ...); empty for type errors
 }
 
 func (e ValidationError) Error() string {
@@ -32,7 +35,7 @@ func ValidateFields(fields map[string]FieldValue, fieldDefs map[string]*FieldDef
 			invalidDefs[name] = struct{}{}
 			continue
 		}
-		if def.Required {
+		if def.Required && def.Derived == "" {
 			val, exists := fields[name]
 			if !exists || val.IsNull() {
 				if def.Default == nil {
@@ -48,7 +51,7 @@ func ValidateFields(fields map[string]FieldValue, fieldDefs map[string]*FieldDef
 	// Validate each provided field
 	for name, value := range fields {
 		// Skip reserved fields
-		if name == "id" || name == "type" || name == "alias" {
+		if name == "id" || name == "type" || name == "alias" || IsAttributionField(name) {
 			continue
 		}
 
@@ -62,10 +65,18 @@ func ValidateFields(fields map[string]FieldValue, fieldDefs map[string]*FieldDef
 				}
 				continue
 			}
+			if def.Derived != "" {
+				errors = append(errors, ValidationError{
+					Field:   name,
+					Message: "Field is derived by the index; remove it from frontmatter",
+				})
+				continue
+			}
 			if err := validateFieldValue(name, value, def); err != nil {
 				errors = append(errors, ValidationError{
 					Field:   name,
 					Message: err.Error(),
+					Rule:    ruleOf(err),
 				})
 			}
 		}
@@ -82,9 +93,11 @@ func validateFieldValue(name string, value FieldValue, def *FieldDefinition) err
 
 	switch def.Type {
 	case FieldTypeString:
-		if _, ok := value.AsString(); !ok {
+		s, ok := value.AsString()
+		if !ok {
 			return fmt.Errorf("expected string")
 		}
+		return checkPattern(s, def)
 
 	case FieldTypeStringArray:
 		arr, ok := value.AsArray()
@@ -92,9 +105,13 @@ func validateFieldValue(name string, value FieldValue, def *FieldDefinition) err
 			return fmt.Errorf("expected array of strings")
 		}
 		for _, v := range arr {
-			if _, ok := v.AsString(); !ok {
+			s, ok := v.AsString()
+			if !ok {
 				return fmt.Errorf("expected array of strings")
 			}
+			if err := checkPattern(s, def); err != nil {
+				return err
+			}
 		}
 
 	case FieldTypeNumber:
@@ -102,12 +119,7 @@ func validateFieldValue(name string, value FieldValue, def *FieldDefinition) err
 		if !ok {
 			return fmt.Errorf("expected number")
 		}
-		if def.Min != nil && n < *def.Min {
-			return fmt.Errorf("value %v is below minimum %v", n, *def.Min)
-		}
-		if def.Max != nil && n > *def.Max {
-			return fmt.Errorf("value %v is above maximum %v", n, *def.Max)
-		}
+		return checkNumberBounds(n, def)
 
 	case FieldTypeNumberArray:
 		arr, ok := value.AsArray()
@@ -152,6 +164,7 @@ func validateFieldValue(name string, value FieldValue, def *FieldDefinition) err
 		if !dates.IsValidDate(s) {
 			return fmt.Errorf("invalid date format, expected YYYY-MM-DD")
 		}
+		return checkDateBounds(s, def)
 
 	case FieldTypeDateArray:
 		arr, ok := value.AsArray()
@@ -173,6 +186,7 @@ func validateFieldValue(name string, value FieldValue, def *FieldDefinition) err
 		if !dates.IsValidDatetime(s) {
 			return fmt.Errorf("invalid datetime format")
 		}
+		return checkDateBounds(s, def)
 
 	case FieldTypeDatetimeArray:
 		arr, ok := value.AsArray()
@@ -263,6 +277,28 @@ func validateFieldValue(name string, value FieldValue, def *FieldDefinition) err
 			}
 		}
 
+	case FieldTypeObject:
+		obj, ok := value.AsObject()
+		if !ok {
+			return fmt.Errorf("expected object")
+		}
+		return validateObjectValue(obj, def.Fields)
+
+	case FieldTypeObjectArray:
+		arr, ok := value.AsArray()
+		if !ok {
+			return fmt.Errorf("expected array of objects")
+		}
+		for i, v := range arr {
+			obj, ok := v.AsObject()
+			if !ok {
+				return fmt.Errorf("expected array of objects")
+			}
+			if err := validateObjectValue(obj, def.Fields); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+
 	default:
 		return fmt.Errorf("unsupported field type '%s'", def.Type)
 	}
@@ -270,6 +306,38 @@ func validateFieldValue(name string, value FieldValue, def *FieldDefinition) err
 	return nil
 }
 
+// validateObjectValue checks the keys of a nested object against its field
+// definitions. Keys without a definition are allowed, as at the top level.
+func validateObjectValue(obj map[string]FieldValue, defs map[string]*FieldDefinition) error {
+	keys := make([]string, 0, len(defs))
+	for key := range defs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	for _, key := range keys {
+		def := defs[key]
+		if def == nil {
+			continue
+		}
+		value, exists := obj[key]
+		if !exists || value.IsNull() {
+			if def.Required && def.Default == nil {
+				problems = append(problems, fmt.Sprintf("field '%s' is required", key))
+			}
+			continue
+		}
+		if err := validateFieldValue(key, value, def); err != nil {
+			problems = append(problems, fmt.Sprintf("field '%s': %s", key, err.Error()))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
 func refTargetFromFieldValue(value FieldValue) (string, bool) {
 	if r, ok := value.AsRef(); ok && r != "" {
 		return r, true
@@ -342,10 +410,30 @@ func ValidateSchema(sch *Schema) []string {
 		if err := ValidateNameField(typeDef); err != nil {
 			issues = append(issues, fmt.Sprintf("Type '%s': %s", typeName, err.Error()))
 		}
+		if err := validateReviewPolicy(typeDef); err != nil {
+			issues = append(issues, fmt.Sprintf("Type '%s': %s", typeName, err.Error()))
+		}
+		if err := validateLifecycle(typeDef); err != nil {
+			issues = append(issues, fmt.Sprintf("Type '%s': %s", typeName, err.Error()))
+		}
+		if typeDef.Extends != "" {
+			if _, err := extendsChain(sch.Types, typeName); err != nil {
+				issues = append(issues, fmt.Sprintf("Type '%s' %s", typeName, err.Error()))
+			}
+		}
+		for _, traitName := range typeDef.RequiredTraits {
+			if _, ok := sch.Traits[traitName]; !ok {
+				issues = append(issues, fmt.Sprintf("Type '%s' requires unknown trait '%s'", typeName, traitName))
+			}
+		}
 
 		// Validate ref field targets
 		if typeDef.Fields != nil {
 			for fieldName, fieldDef := range typeDef.Fields {
+				// Inherited fields are validated on the type that defines them.
+				if typeDef.InheritedFields[fieldName] != "" {
+					continue
+				}
 				issues = append(issues, validateSchemaFieldDefinition(typeName, fieldName, fieldDef, sch, validTypes)...)
 			}
 		}
@@ -423,6 +511,10 @@ func validateSchemaFieldDefinition(typeName, fieldName string, fieldDef *FieldDe
 	if !IsValidFieldType(fieldDef.Type) {
 		return append(issues, fmt.Sprintf("Type '%s' field '%s' has unknown field type '%s' (expected one of: %s)", typeName, fieldName, fieldDef.Type, validTypes))
 	}
+	issues = append(issues, validateFieldRuleDefinitions(typeName, fieldName, fieldDef)...)
+	if fieldDef.Derived != "" && (fieldDef.Required || fieldDef.Default != nil) {
+		issues = append(issues, fmt.Sprintf("Type '%s' field '%s' is derived and cannot be required or have a default", typeName, fieldName))
+	}
 	if (fieldDef.Type == FieldTypeEnum || fieldDef.Type == FieldTypeEnumArray) && len(fieldDef.Values) == 0 {
 		issues = append(issues, fmt.Sprintf("Type '%s' field '%s' of type '%s' must define at least one allowed value", typeName, fieldName, fieldDef.Type))
 	}
@@ -431,6 +523,18 @@ func validateSchemaFieldDefinition(typeName, fieldName string, fieldDef *FieldDe
 			issues = append(issues, fmt.Sprintf("Type '%s' field '%s' references unknown type '%s'", typeName, fieldName, fieldDef.Target))
 		}
 	}
+	if fieldDef.Type == FieldTypeObject || fieldDef.Type == FieldTypeObjectArray {
+		subNames := make([]string, 0, len(fieldDef.Fields))
+		for subName := range fieldDef.Fields {
+			subNames = append(subNames, subName)
+		}
+		sort.Strings(subNames)
+		for _, subName := range subNames {
+			issues = append(issues, validateSchemaFieldDefinition(typeName, fieldName+"."+subName, fieldDef.Fields[subName], sch, validTypes)...)
+		}
+	} else if len(fieldDef.Fields) > 0 {
+		issues = append(issues, fmt.Sprintf("Type '%s' field '%s' of type '%s' cannot define nested fields; use type 'object' or 'object[]'", typeName, fieldName, fieldDef.Type))
+	}
 	return issues
 }
 
@@ -447,6 +551,19 @@ func validateSchemaTraitDefinition(traitName string, traitDef *TraitDefinition)
 	if traitType == FieldTypeEnum && len(traitDef.Values) == 0 {
 		issues = append(issues, fmt.Sprintf("Trait '%s' of type '%s' must define at least one allowed value", traitName, traitType))
 	}
+	if traitDef.Normalize != nil && traitType == FieldTypeEnum && len(traitDef.Values) > 0 {
+		synonyms := make([]string, 0, len(traitDef.Normalize.Synonyms))
+		for synonym := range traitDef.Normalize.Synonyms {
+			synonyms = append(synonyms, synonym)
+		}
+		sort.Strings(synonyms)
+		for _, synonym := range synonyms {
+			target := traitDef.Normalize.Synonyms[synonym]
+			if !slices.Contains(traitDef.Values, target) {
+				issues = append(issues, fmt.Sprintf("Trait '%s' synonym '%s' maps to '%s', which is not one of its values %v", traitName, synonym, target, traitDef.Values))
+			}
+		}
+	}
 	return issues
 }
 
@@ -467,7 +584,9 @@ func IsValidFieldType(fieldType FieldType) bool {
 		FieldTypeBool,
 		FieldTypeBoolArray,
 		FieldTypeRef,
-		FieldTypeRefArray:
+		FieldTypeRefArray,
+		FieldTypeObject,
+		FieldTypeObjectArray:
 		return true
 	default:
 		return false
@@ -478,13 +597,18 @@ func IsValidTraitType(fieldType FieldType) bool {
 	if fieldType == "boolean" {
 		return true
 	}
+	if fieldType == FieldTypeObject || fieldType == FieldTypeObjectArray {
+		return false
+	}
 	return IsValidFieldType(fieldType)
 }
 
 func ValidFieldTypes() string {
-	return "string, string[], number, number[], url, url[], date, date[], datetime, datetime[], enum, enum[], bool, bool[], ref, ref[]"
+	return scalarFieldTypes + ", object, object[]"
 }
 
 func ValidTraitTypes() string {
-	return ValidFieldTypes() + ", boolean"
+	return scalarFieldTypes + ", boolean"
 }
+
+const scalarFieldTypes = "string, string[], number, number[], url, url[], date, date[], datetime, datetime[], enum, enum[], bool, bool[], ref, ref[]"
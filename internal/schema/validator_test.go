@@ -117,6 +117,20 @@ func TestValidateFields(t *testing.T) {
 		}
 	})
 
+	t.Run("derived field set in frontmatter", func(t *testing.T) {
+		fields := map[string]FieldValue{
+			"open_tasks": Number(3),
+		}
+		defs := map[string]*FieldDefinition{
+			"open_tasks": {Type: FieldTypeNumber, Derived: "count(trait:todo in(_))"},
+		}
+
+		errors := ValidateFields(fields, defs, nil)
+		if len(errors) != 1 || !strings.Contains(errors[0].Message, "derived by the index") {
+			t.Fatalf("expected derived field error, got %v", errors)
+		}
+	})
+
 	t.Run("unsupported field type reports validation error", func(t *testing.T) {
 		fields := map[string]FieldValue{
 			"broken": String("value"),
@@ -941,6 +955,35 @@ func TestValidateSchema(t *testing.T) {
 		}
 	})
 
+	t.Run("required trait not in schema", func(t *testing.T) {
+		sch := &Schema{
+			Types: map[string]*TypeDefinition{
+				"task": {RequiredTraits: []string{"status"}},
+			},
+			Traits: map[string]*TraitDefinition{},
+		}
+		issues := ValidateSchema(sch)
+		if len(issues) != 1 || !strings.Contains(issues[0], "requires unknown trait 'status'") {
+			t.Errorf("expected unknown required trait issue, got %v", issues)
+		}
+	})
+
+	t.Run("required derived field in schema", func(t *testing.T) {
+		sch := &Schema{
+			Types: map[string]*TypeDefinition{
+				"project": {
+					Fields: map[string]*FieldDefinition{
+						"open_tasks": {Type: FieldTypeNumber, Required: true, Derived: "count(trait:todo in(_))"},
+					},
+				},
+			},
+		}
+		issues := ValidateSchema(sch)
+		if !containsIssueSubstring(issues, "is derived and cannot be required or have a default") {
+			t.Fatalf("expected derived field issue, got %v", issues)
+		}
+	})
+
 	t.Run("invalid trait type in schema", func(t *testing.T) {
 		sch := &Schema{
 			Traits: map[string]*TraitDefinition{
@@ -0,0 +1,471 @@
+package schemasvc
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/config"
+	ravenignore "github.com/aidanlsb/raven/internal/ignore"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vault"
+)
+
+// driftLocationLimit caps the example locations kept per undeclared trait.
+const driftLocationLimit = 5
+
+// Cleanup priorities, most urgent first.
+const (
+	DriftPriorityHigh   = "high"
+	DriftPriorityMedium = "medium"
+	DriftPriorityLow    = "low"
+)
+
+// DriftReport measures how far schema.yaml and the vault's data have drifted
+// apart, with a cleanup plan ordered by priority.
+type DriftReport struct {
+	Summary             DriftSummary         `json:"summary"`
+	UnusedTypes         []DriftUnusedType    `json:"unused_types"`
+	UnusedFields        []DriftUnusedField   `json:"unused_fields"`
+	UnusedEnumValues    []DriftEnumValues    `json:"unused_enum_values"`
+	UndeclaredTraits    []DriftTrait         `json:"undeclared_traits"`
+	RefTargetMismatches []DriftRefMismatch   `json:"ref_target_mismatches"`
+	Plan                []DriftCleanupAction `json:"plan"`
+}
+
+// DriftSummary counts each kind of drift.
+type DriftSummary struct {
+	UnusedTypes         int `json:"unused_types"`
+	UnusedFields        int `json:"unused_fields"`
+	UnusedEnumValues    int `json:"unused_enum_values"`
+	UndeclaredTraits    int `json:"undeclared_traits"`
+	RefTargetMismatches int `json:"ref_target_mismatches"`
+}
+
+// Total returns the number of drift findings.
+func (s DriftSummary) Total() int {
+	return s.UnusedTypes + s.UnusedFields + s.UnusedEnumValues + s.UndeclaredTraits + s.RefTargetMismatches
+}
+
+// DriftUnusedType is a declared type with no objects. Its fields are not
+// reported separately.
+type DriftUnusedType struct {
+	Type string `json:"type"`
+}
+
+// DriftUnusedField is a declared field that no object of its type sets.
+type DriftUnusedField struct {
+	Type        string `json:"type"`
+	Field       string `json:"field"`
+	ObjectCount int    `json:"object_count"`
+}
+
+// DriftEnumValues lists the declared values of an enum field or trait that
+// never occur.
+type DriftEnumValues struct {
+	Kind   string   `json:"kind"` // "field" or "trait"
+	Type   string   `json:"type,omitempty"`
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+	Used   []string `json:"used"`
+}
+
+// DriftTrait is a trait used in files but not declared in schema.yaml.
+// Undeclared traits are not indexed, so they are found by scanning files.
+type DriftTrait struct {
+	Trait     string   `json:"trait"`
+	Count     int      `json:"count"`
+	FileCount int      `json:"file_count"`
+	HasValue  bool     `json:"has_value"`
+	Locations []string `json:"locations"`
+}
+
+// DriftRefMismatch counts ref field values that resolve to an object of a
+// type other than the field's declared target.
+type DriftRefMismatch struct {
+	Type           string `json:"type"`
+	Field          string `json:"field"`
+	DeclaredTarget string `json:"declared_target"`
+	ActualType     string `json:"actual_type"`
+	Count          int    `json:"count"`
+	Example        string `json:"example"`
+}
+
+// DriftCleanupAction is one step of the cleanup plan.
+type DriftCleanupAction struct {
+	Priority    string `json:"priority"`
+	Kind        string `json:"kind"`
+	Target      string `json:"target"`
+	Description string `json:"description"`
+	Command     string `json:"command,omitempty"`
+
+	weight int
+}
+
+// AnalyzeDrift compares schema.yaml with the indexed objects and traits and
+// the trait annotations in vault files.
+func AnalyzeDrift(vaultPath string, vaultCfg *config.VaultConfig) (*DriftReport, error) {
+	sch, err := loadSchema(vaultPath, "Run 'rvn init' first")
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return nil, newError(ErrorInternal, "failed to open index", "Run 'rvn reindex' to rebuild the index", nil, err)
+	}
+	defer db.Close()
+
+	report := &DriftReport{
+		UnusedTypes:         []DriftUnusedType{},
+		UnusedFields:        []DriftUnusedField{},
+		UnusedEnumValues:    []DriftEnumValues{},
+		UndeclaredTraits:    []DriftTrait{},
+		RefTargetMismatches: []DriftRefMismatch{},
+		Plan:                []DriftCleanupAction{},
+	}
+	if err := addFieldDrift(report, sch, db); err != nil {
+		return nil, newError(ErrorInternal, "failed to read objects from the index", "Run 'rvn reindex' to rebuild the index", nil, err)
+	}
+	if err := addTraitEnumDrift(report, sch, db); err != nil {
+		return nil, newError(ErrorInternal, "failed to read traits from the index", "Run 'rvn reindex' to rebuild the index", nil, err)
+	}
+	if err := addRefTargetDrift(report, sch, db); err != nil {
+		return nil, newError(ErrorInternal, "failed to read ref fields from the index", "Run 'rvn reindex' to rebuild the index", nil, err)
+	}
+	if err := addUndeclaredTraitDrift(report, sch, vaultPath, vaultCfg); err != nil {
+		return nil, newError(ErrorFileRead, "failed to scan vault files", "", nil, err)
+	}
+
+	report.Summary = DriftSummary{
+		UnusedTypes:         len(report.UnusedTypes),
+		UnusedFields:        len(report.UnusedFields),
+		UnusedEnumValues:    len(report.UnusedEnumValues),
+		UndeclaredTraits:    len(report.UndeclaredTraits),
+		RefTargetMismatches: len(report.RefTargetMismatches),
+	}
+	report.Plan = driftCleanupPlan(report)
+	return report, nil
+}
+
+// addFieldDrift finds unused types, unused fields, and unused enum field
+// values from the indexed objects.
+func addFieldDrift(report *DriftReport, sch *schema.Schema, db *index.Database) error {
+	objects, err := db.AllObjects()
+	if err != nil {
+		return err
+	}
+	objectCounts := make(map[string]int)
+	fieldCounts := make(map[string]map[string]int)
+	enumValues := make(map[string]map[string]map[string]bool)
+	for _, obj := range objects {
+		typeDef := sch.Types[obj.Type]
+		if typeDef == nil {
+			continue
+		}
+		objectCounts[obj.Type]++
+		if fieldCounts[obj.Type] == nil {
+			fieldCounts[obj.Type] = make(map[string]int)
+			enumValues[obj.Type] = make(map[string]map[string]bool)
+		}
+		for name, value := range obj.Fields {
+			fieldDef := typeDef.Fields[name]
+			if fieldDef == nil || value == nil {
+				continue
+			}
+			fieldCounts[obj.Type][name]++
+			if !isEnumFieldType(fieldDef.Type) {
+				continue
+			}
+			if enumValues[obj.Type][name] == nil {
+				enumValues[obj.Type][name] = make(map[string]bool)
+			}
+			for _, v := range driftScalarValues(value) {
+				enumValues[obj.Type][name][v] = true
+			}
+		}
+	}
+
+	for _, typeName := range sortedTypeNames(sch) {
+		typeDef := sch.Types[typeName]
+		if typeDef == nil || schema.IsBuiltinType(typeName) {
+			continue
+		}
+		if objectCounts[typeName] == 0 {
+			report.UnusedTypes = append(report.UnusedTypes, DriftUnusedType{Type: typeName})
+			continue
+		}
+		for _, fieldName := range sortedFieldNames(typeDef) {
+			fieldDef := typeDef.Fields[fieldName]
+			if fieldDef == nil || fieldDef.Derived != "" {
+				continue
+			}
+			if fieldCounts[typeName][fieldName] == 0 {
+				report.UnusedFields = append(report.UnusedFields, DriftUnusedField{
+					Type:        typeName,
+					Field:       fieldName,
+					ObjectCount: objectCounts[typeName],
+				})
+				continue
+			}
+			if isEnumFieldType(fieldDef.Type) {
+				if unused := unusedEnumValues(fieldDef.Values, enumValues[typeName][fieldName]); unused != nil {
+					unused.Kind = "field"
+					unused.Type = typeName
+					unused.Name = fieldName
+					report.UnusedEnumValues = append(report.UnusedEnumValues, *unused)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// addTraitEnumDrift finds declared enum trait values that no indexed trait
+// uses. Traits with no instances at all are left to their usage counts.
+func addTraitEnumDrift(report *DriftReport, sch *schema.Schema, db *index.Database) error {
+	names := make([]string, 0, len(sch.Traits))
+	for name := range sch.Traits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		traitDef := sch.Traits[name]
+		if traitDef == nil || !isEnumFieldType(traitDef.Type) {
+			continue
+		}
+		instances, err := db.QueryTraits(name, nil)
+		if err != nil {
+			return err
+		}
+		if len(instances) == 0 {
+			continue
+		}
+		used := make(map[string]bool)
+		for _, instance := range instances {
+			if instance.Value != nil {
+				used[strings.TrimSpace(*instance.Value)] = true
+			} else if def, ok := traitDef.Default.(string); ok {
+				used[def] = true
+			}
+		}
+		if unused := unusedEnumValues(traitDef.Values, used); unused != nil {
+			unused.Kind = "trait"
+			unused.Name = name
+			report.UnusedEnumValues = append(report.UnusedEnumValues, *unused)
+		}
+	}
+	return nil
+}
+
+// addRefTargetDrift finds ref field values pointing at objects of a type
+// other than the field's declared target.
+func addRefTargetDrift(report *DriftReport, sch *schema.Schema, db *index.Database) error {
+	targets, err := db.FieldRefTargets()
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		typeDef := sch.Types[target.SourceType]
+		if typeDef == nil {
+			continue
+		}
+		fieldDef := typeDef.Fields[target.FieldName]
+		if fieldDef == nil || fieldDef.Target == "" || fieldDef.Target == target.TargetType {
+			continue
+		}
+		report.RefTargetMismatches = append(report.RefTargetMismatches, DriftRefMismatch{
+			Type:           target.SourceType,
+			Field:          target.FieldName,
+			DeclaredTarget: fieldDef.Target,
+			ActualType:     target.TargetType,
+			Count:          target.Count,
+			Example:        target.Example,
+		})
+	}
+	return nil
+}
+
+// addUndeclaredTraitDrift scans vault files for trait annotations that
+// schema.yaml does not declare. Templates are skipped.
+func addUndeclaredTraitDrift(report *DriftReport, sch *schema.Schema, vaultPath string, vaultCfg *config.VaultConfig) error {
+	excludeMatcher, err := ravenignore.NewMatcher(vaultCfg.GetExcludePatterns())
+	if err != nil {
+		return err
+	}
+	templateDir := strings.TrimSuffix(vaultCfg.GetTemplateDirectory(), "/") + "/"
+
+	found := make(map[string]*DriftTrait)
+	files := make(map[string]map[string]bool)
+	walkOpts := &vault.WalkOptions{
+		ParseOptions: &parser.ParseOptions{
+			ObjectsRoot:  vaultCfg.GetObjectsRoot(),
+			PagesRoot:    vaultCfg.GetPagesRoot(),
+			HashtagTrait: vaultCfg.GetHashtagTrait(),
+		},
+		ExcludeMatcher: excludeMatcher,
+	}
+	err = vault.WalkMarkdownFilesWithOptions(vaultPath, walkOpts, func(result vault.WalkResult) error {
+		if result.Error != nil || result.Document == nil {
+			return nil
+		}
+		relPath := filepath.ToSlash(result.RelativePath)
+		if strings.HasPrefix(relPath, templateDir) {
+			return nil
+		}
+		for _, trait := range result.Document.Traits {
+			if _, ok := sch.Traits[trait.TraitType]; ok {
+				continue
+			}
+			entry := found[trait.TraitType]
+			if entry == nil {
+				entry = &DriftTrait{Trait: trait.TraitType, Locations: []string{}}
+				found[trait.TraitType] = entry
+				files[trait.TraitType] = make(map[string]bool)
+			}
+			entry.Count++
+			entry.HasValue = entry.HasValue || trait.HasValue()
+			files[trait.TraitType][relPath] = true
+			if len(entry.Locations) < driftLocationLimit {
+				entry.Locations = append(entry.Locations, fmt.Sprintf("%s:%d", relPath, trait.Line))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for name, entry := range found {
+		entry.FileCount = len(files[name])
+		report.UndeclaredTraits = append(report.UndeclaredTraits, *entry)
+	}
+	sort.Slice(report.UndeclaredTraits, func(i, j int) bool {
+		a, b := report.UndeclaredTraits[i], report.UndeclaredTraits[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Trait < b.Trait
+	})
+	return nil
+}
+
+// driftCleanupPlan orders fixes by how much they matter. Data the schema
+// cannot validate or query (undeclared traits, mistargeted refs) comes first,
+// then declarations nothing uses.
+func driftCleanupPlan(report *DriftReport) []DriftCleanupAction {
+	plan := []DriftCleanupAction{}
+	for _, trait := range report.UndeclaredTraits {
+		command := "rvn schema add trait " + trait.Trait
+		if !trait.HasValue {
+			command += " --type bool"
+		}
+		plan = append(plan, DriftCleanupAction{
+			Priority:    DriftPriorityHigh,
+			Kind:        "undeclared_trait",
+			Target:      "@" + trait.Trait,
+			Description: fmt.Sprintf("@%s is not declared, so it is not indexed or queryable (uses: %d, files: %d); declare it or remove the annotations", trait.Trait, trait.Count, trait.FileCount),
+			Command:     command,
+			weight:      trait.Count,
+		})
+	}
+	for _, mismatch := range report.RefTargetMismatches {
+		plan = append(plan, DriftCleanupAction{
+			Priority:    DriftPriorityHigh,
+			Kind:        "ref_target_mismatch",
+			Target:      fmt.Sprintf("%s.%s", mismatch.Type, mismatch.Field),
+			Description: fmt.Sprintf("values point at '%s' objects but the field targets '%s' (count: %d, e.g. %s); fix the references or change the target", mismatch.ActualType, mismatch.DeclaredTarget, mismatch.Count, mismatch.Example),
+			Command:     "rvn check --issues wrong_target_type",
+			weight:      mismatch.Count,
+		})
+	}
+	for _, field := range report.UnusedFields {
+		plan = append(plan, DriftCleanupAction{
+			Priority:    DriftPriorityMedium,
+			Kind:        "unused_field",
+			Target:      fmt.Sprintf("%s.%s", field.Type, field.Field),
+			Description: fmt.Sprintf("no '%s' object sets '%s' (objects: %d)", field.Type, field.Field, field.ObjectCount),
+			Command:     fmt.Sprintf("rvn schema remove field %s %s", field.Type, field.Field),
+			weight:      field.ObjectCount,
+		})
+	}
+	for _, typ := range report.UnusedTypes {
+		plan = append(plan, DriftCleanupAction{
+			Priority:    DriftPriorityLow,
+			Kind:        "unused_type",
+			Target:      typ.Type,
+			Description: fmt.Sprintf("type '%s' has no objects", typ.Type),
+			Command:     fmt.Sprintf("rvn schema remove type %s", typ.Type),
+		})
+	}
+	for _, enum := range report.UnusedEnumValues {
+		target, command := "@"+enum.Name, fmt.Sprintf("rvn schema update trait %s", enum.Name)
+		if enum.Kind == "field" {
+			target = fmt.Sprintf("%s.%s", enum.Type, enum.Name)
+			command = fmt.Sprintf("rvn schema update field %s %s", enum.Type, enum.Name)
+		}
+		if len(enum.Used) > 0 {
+			command += " --values " + strings.Join(enum.Used, ",")
+		} else {
+			command = ""
+		}
+		plan = append(plan, DriftCleanupAction{
+			Priority:    DriftPriorityLow,
+			Kind:        "unused_enum_values",
+			Target:      target,
+			Description: fmt.Sprintf("values never used: %s", strings.Join(enum.Values, ", ")),
+			Command:     command,
+			weight:      len(enum.Values),
+		})
+	}
+
+	rank := map[string]int{DriftPriorityHigh: 0, DriftPriorityMedium: 1, DriftPriorityLow: 2}
+	sort.SliceStable(plan, func(i, j int) bool {
+		if rank[plan[i].Priority] != rank[plan[j].Priority] {
+			return rank[plan[i].Priority] < rank[plan[j].Priority]
+		}
+		return plan[i].weight > plan[j].weight
+	})
+	return plan
+}
+
+// unusedEnumValues returns the declared values missing from used, keeping
+// the declared order, or nil when every value occurs.
+func unusedEnumValues(declared []string, used map[string]bool) *DriftEnumValues {
+	result := &DriftEnumValues{Values: []string{}, Used: []string{}}
+	for _, value := range declared {
+		if used[value] {
+			result.Used = append(result.Used, value)
+		} else {
+			result.Values = append(result.Values, value)
+		}
+	}
+	if len(result.Values) == 0 {
+		return nil
+	}
+	return result
+}
+
+func isEnumFieldType(fieldType schema.FieldType) bool {
+	return fieldType == schema.FieldTypeEnum || fieldType == schema.FieldTypeEnumArray
+}
+
+// driftScalarValues flattens an indexed field value to its string values.
+func driftScalarValues(value interface{}) []string {
+	switch v := value.(type) {
+	case []interface{}:
+		var out []string
+		for _, item := range v {
+			out = append(out, driftScalarValues(item)...)
+		}
+		return out
+	case string:
+		return []string{strings.TrimSpace(v)}
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
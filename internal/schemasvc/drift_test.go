@@ -0,0 +1,106 @@
+package schemasvc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/reindexsvc"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+const driftTestSchema = `version: 1
+types:
+  person:
+    fields:
+      name:
+        type: string
+  project:
+    fields:
+      title:
+        type: string
+        required: true
+      status:
+        type: enum
+        values: [active, paused, done]
+      owner:
+        type: ref
+        target: person
+  company:
+    fields:
+      name:
+        type: string
+traits:
+  priority:
+    type: enum
+    values: [low, medium, high]
+`
+
+func TestAnalyzeDrift(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).
+		WithSchema(driftTestSchema).
+		WithFile("templates/project.md", "- @draft placeholder\n").
+		WithFile("projects/alpha.md", "---\ntype: project\ntitle: Alpha\nstatus: active\nowner: \"[[projects/beta]]\"\n---\n- @priority(high) ship @waiting\n- @waiting review\n").
+		WithFile("projects/beta.md", "---\ntype: project\ntitle: Beta\nstatus: paused\nowner: \"[[people/freya]]\"\n---\n").
+		WithFile("people/freya.md", "---\ntype: person\nname: Freya\n---\n").
+		Build()
+	if _, err := reindexsvc.Run(reindexsvc.RunRequest{VaultPath: vault.Path, Full: true}); err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+
+	report, err := AnalyzeDrift(vault.Path, &config.VaultConfig{})
+	if err != nil {
+		t.Fatalf("AnalyzeDrift returned error: %v", err)
+	}
+
+	if want := []DriftUnusedType{{Type: "company"}}; !reflect.DeepEqual(report.UnusedTypes, want) {
+		t.Errorf("unused types = %+v, want %+v", report.UnusedTypes, want)
+	}
+	if len(report.UnusedFields) != 0 {
+		t.Errorf("unused fields = %+v, want none", report.UnusedFields)
+	}
+
+	enums := map[string][]string{}
+	for _, enum := range report.UnusedEnumValues {
+		enums[enum.Kind+":"+enum.Name] = enum.Values
+	}
+	wantEnums := map[string][]string{
+		"field:status":   {"done"},
+		"trait:priority": {"low", "medium"},
+	}
+	if !reflect.DeepEqual(enums, wantEnums) {
+		t.Errorf("unused enum values = %v, want %v", enums, wantEnums)
+	}
+
+	if len(report.UndeclaredTraits) != 1 {
+		t.Fatalf("undeclared traits = %+v, want only @waiting (templates skipped)", report.UndeclaredTraits)
+	}
+	if got := report.UndeclaredTraits[0]; got.Trait != "waiting" || got.Count != 2 || got.FileCount != 1 || got.HasValue {
+		t.Errorf("undeclared trait = %+v, want @waiting used twice in one file without a value", got)
+	}
+
+	wantMismatch := []DriftRefMismatch{{Type: "project", Field: "owner", DeclaredTarget: "person", ActualType: "project", Count: 1, Example: "projects/alpha"}}
+	if !reflect.DeepEqual(report.RefTargetMismatches, wantMismatch) {
+		t.Errorf("ref mismatches = %+v, want %+v", report.RefTargetMismatches, wantMismatch)
+	}
+
+	var kinds []string
+	for _, action := range report.Plan {
+		kinds = append(kinds, action.Priority+":"+action.Kind)
+	}
+	wantKinds := []string{
+		"high:undeclared_trait",
+		"high:ref_target_mismatch",
+		"low:unused_enum_values",
+		"low:unused_enum_values",
+		"low:unused_type",
+	}
+	if !reflect.DeepEqual(kinds, wantKinds) {
+		t.Errorf("plan = %v, want %v", kinds, wantKinds)
+	}
+	if report.Summary.Total() != 5 {
+		t.Errorf("summary total = %d, want 5", report.Summary.Total())
+	}
+}
@@ -0,0 +1,398 @@
+package schemasvc
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+// Impact kinds reported by the Analyze*Impact functions.
+const (
+	ImpactKindType       = "type"
+	ImpactKindTrait      = "trait"
+	ImpactKindField      = "field"
+	ImpactKindEnumValues = "enum_values"
+)
+
+// Impact describes what a destructive schema change would touch. It is built
+// from the index, schema.yaml, raven.yaml, and template files so callers can
+// review the blast radius before confirming.
+type Impact struct {
+	Kind             string            `json:"kind"`
+	Name             string            `json:"name"`
+	Type             string            `json:"type,omitempty"`
+	Field            string            `json:"field,omitempty"`
+	Values           []string          `json:"values,omitempty"`
+	Effect           string            `json:"effect"`
+	ObjectCount      int               `json:"object_count"`
+	TraitCount       int               `json:"trait_count,omitempty"`
+	Files            []string          `json:"files"`
+	Queries          []ImpactQuery     `json:"queries"`
+	Templates        []ImpactTemplate  `json:"templates"`
+	SchemaRefs       []ImpactSchemaRef `json:"schema_refs"`
+	IndexUnavailable bool              `json:"index_unavailable,omitempty"`
+}
+
+// ImpactQuery is a saved query in raven.yaml that mentions the removed item.
+type ImpactQuery struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// ImpactTemplate is a template that is bound to or mentions the removed item.
+type ImpactTemplate struct {
+	ID     string `json:"id,omitempty"`
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
+
+// ImpactSchemaRef is another schema definition that depends on the removed item.
+type ImpactSchemaRef struct {
+	Location    string `json:"location"`
+	Description string `json:"description"`
+}
+
+// HasUsage reports whether anything in the vault depends on the removed item.
+func (i *Impact) HasUsage() bool {
+	if i == nil {
+		return false
+	}
+	return i.ObjectCount > 0 || i.TraitCount > 0 || len(i.Queries) > 0 || len(i.Templates) > 0 || len(i.SchemaRefs) > 0
+}
+
+// AnalyzeTypeImpact reports what removing a type would affect.
+func AnalyzeTypeImpact(vaultPath, typeName string) (*Impact, error) {
+	typeName = strings.TrimSpace(typeName)
+	sch, err := loadSchema(vaultPath, "Run 'rvn init' first")
+	if err != nil {
+		return nil, err
+	}
+	typeDef, exists := sch.Types[typeName]
+	if !exists {
+		return nil, newError(ErrorTypeNotFound, fmt.Sprintf("type '%s' not found", typeName), "", nil, nil)
+	}
+
+	impact := newImpact(ImpactKindType, typeName)
+	impact.Effect = fmt.Sprintf("files of type '%s' will become 'page' type", typeName)
+
+	objects, ok := queryImpactObjects(vaultPath, typeName)
+	impact.IndexUnavailable = !ok
+	impact.ObjectCount = len(objects)
+	impact.Files = objectFiles(objects)
+
+	typePattern := regexp.MustCompile(`\btype:` + regexp.QuoteMeta(typeName) + `\b`)
+	impact.Queries = matchingSavedQueries(vaultPath, func(q string) bool {
+		return typePattern.MatchString(q)
+	})
+
+	impact.Templates = boundTemplates(vaultPath, sch, typeDef, func(string) (string, bool) {
+		return "bound to type", true
+	})
+
+	for _, ownerName := range sortedTypeNames(sch) {
+		ownerDef := sch.Types[ownerName]
+		if ownerDef == nil || ownerName == typeName {
+			continue
+		}
+		for _, fieldName := range sortedFieldNames(ownerDef) {
+			fieldDef := ownerDef.Fields[fieldName]
+			if fieldDef != nil && fieldDef.Target == typeName {
+				impact.SchemaRefs = append(impact.SchemaRefs, ImpactSchemaRef{
+					Location:    fmt.Sprintf("%s.%s", ownerName, fieldName),
+					Description: fmt.Sprintf("ref field targets type '%s'", typeName),
+				})
+			}
+		}
+	}
+	return impact, nil
+}
+
+// AnalyzeTraitImpact reports what removing a trait would affect.
+func AnalyzeTraitImpact(vaultPath, traitName string) (*Impact, error) {
+	traitName = strings.TrimSpace(traitName)
+	sch, err := loadSchema(vaultPath, "Run 'rvn init' first")
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := sch.Traits[traitName]; !exists {
+		return nil, newError(ErrorTraitNotFound, fmt.Sprintf("trait '%s' not found", traitName), "", nil, nil)
+	}
+
+	impact := newImpact(ImpactKindTrait, traitName)
+	impact.Effect = fmt.Sprintf("@%s instances will remain in files but no longer be indexed", traitName)
+
+	if db, err := index.Open(vaultPath); err == nil {
+		defer db.Close()
+		if instances, err := db.QueryTraits(traitName, nil); err == nil {
+			impact.TraitCount = len(instances)
+			impact.Files = traitFiles(instances)
+		} else {
+			impact.IndexUnavailable = true
+		}
+	} else {
+		impact.IndexUnavailable = true
+	}
+
+	traitPattern := regexp.MustCompile(`\btrait:` + regexp.QuoteMeta(traitName) + `\b`)
+	impact.Queries = matchingSavedQueries(vaultPath, func(q string) bool {
+		return traitPattern.MatchString(q)
+	})
+
+	token := regexp.MustCompile(`@` + regexp.QuoteMeta(traitName) + `\b`)
+	seen := make(map[string]struct{})
+	for _, typeName := range sortedTypeNames(sch) {
+		typeDef := sch.Types[typeName]
+		for _, tmpl := range boundTemplates(vaultPath, sch, typeDef, func(content string) (string, bool) {
+			return fmt.Sprintf("uses @%s", traitName), token.MatchString(content)
+		}) {
+			if _, dup := seen[tmpl.File]; dup {
+				continue
+			}
+			seen[tmpl.File] = struct{}{}
+			impact.Templates = append(impact.Templates, tmpl)
+		}
+	}
+
+	return impact, nil
+}
+
+// AnalyzeFieldImpact reports what removing a field from a type would affect.
+func AnalyzeFieldImpact(vaultPath, typeName, fieldName string) (*Impact, error) {
+	return analyzeFieldImpact(vaultPath, typeName, fieldName, nil)
+}
+
+// AnalyzeEnumValuesImpact reports what dropping values from an enum field
+// would affect. Only objects currently using one of the values are counted.
+func AnalyzeEnumValuesImpact(vaultPath, typeName, fieldName string, values []string) (*Impact, error) {
+	if len(values) == 0 {
+		return nil, newError(ErrorInvalidInput, "no enum values specified", "", nil, nil)
+	}
+	return analyzeFieldImpact(vaultPath, typeName, fieldName, values)
+}
+
+func analyzeFieldImpact(vaultPath, typeName, fieldName string, values []string) (*Impact, error) {
+	typeName = strings.TrimSpace(typeName)
+	fieldName = strings.TrimSpace(fieldName)
+	sch, err := loadSchema(vaultPath, "Run 'rvn init' first")
+	if err != nil {
+		return nil, err
+	}
+	typeDef, exists := sch.Types[typeName]
+	if !exists {
+		return nil, newError(ErrorTypeNotFound, fmt.Sprintf("type '%s' not found", typeName), "", nil, nil)
+	}
+	if typeDef == nil || typeDef.Fields == nil || typeDef.Fields[fieldName] == nil {
+		return nil, newError(ErrorFieldNotFound, fmt.Sprintf("field '%s' not found on type '%s'", fieldName, typeName), "", nil, nil)
+	}
+
+	impact := newImpact(ImpactKindField, fieldName)
+	impact.Type = typeName
+	impact.Field = fieldName
+	impact.Effect = fmt.Sprintf("'%s' values will remain in frontmatter but no longer be validated", fieldName)
+	removed := make(map[string]struct{}, len(values))
+	if values != nil {
+		impact.Kind = ImpactKindEnumValues
+		impact.Values = append([]string(nil), values...)
+		impact.Effect = fmt.Sprintf("objects using these values will fail validation for '%s.%s'", typeName, fieldName)
+		for _, value := range values {
+			removed[value] = struct{}{}
+		}
+	}
+
+	objects, ok := queryImpactObjects(vaultPath, typeName)
+	impact.IndexUnavailable = !ok
+	matching := make([]model.Object, 0, len(objects))
+	for _, obj := range objects {
+		raw, has := obj.Fields[fieldName]
+		if !has {
+			continue
+		}
+		if values != nil && !fieldUsesAnyValue(raw, removed) {
+			continue
+		}
+		matching = append(matching, obj)
+	}
+	impact.ObjectCount = len(matching)
+	impact.Files = objectFiles(matching)
+
+	typePattern := regexp.MustCompile(`\btype:` + regexp.QuoteMeta(typeName) + `\b`)
+	fieldPattern := regexp.MustCompile(`\.` + regexp.QuoteMeta(fieldName) + `\b`)
+	impact.Queries = matchingSavedQueries(vaultPath, func(q string) bool {
+		if !typePattern.MatchString(q) || !fieldPattern.MatchString(q) {
+			return false
+		}
+		if values == nil {
+			return true
+		}
+		for value := range removed {
+			if regexp.MustCompile(`\b` + regexp.QuoteMeta(value) + `\b`).MatchString(q) {
+				return true
+			}
+		}
+		return false
+	})
+
+	if values == nil {
+		token := "{{field." + fieldName + "}}"
+		impact.Templates = boundTemplates(vaultPath, sch, typeDef, func(content string) (string, bool) {
+			return "uses " + token, strings.Contains(content, token)
+		})
+		if typeDef.NameField == fieldName {
+			impact.SchemaRefs = append(impact.SchemaRefs, ImpactSchemaRef{
+				Location:    typeName,
+				Description: fmt.Sprintf("name_field is '%s'", fieldName),
+			})
+		}
+	}
+	return impact, nil
+}
+
+func newImpact(kind, name string) *Impact {
+	return &Impact{
+		Kind:       kind,
+		Name:       name,
+		Files:      []string{},
+		Queries:    []ImpactQuery{},
+		Templates:  []ImpactTemplate{},
+		SchemaRefs: []ImpactSchemaRef{},
+	}
+}
+
+func queryImpactObjects(vaultPath, typeName string) ([]model.Object, bool) {
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return nil, false
+	}
+	defer db.Close()
+	objects, err := db.QueryObjects(typeName)
+	if err != nil {
+		return nil, false
+	}
+	return objects, true
+}
+
+func objectFiles(objects []model.Object) []string {
+	seen := make(map[string]struct{}, len(objects))
+	files := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		if _, ok := seen[obj.FilePath]; ok || obj.FilePath == "" {
+			continue
+		}
+		seen[obj.FilePath] = struct{}{}
+		files = append(files, obj.FilePath)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func traitFiles(traits []model.Trait) []string {
+	seen := make(map[string]struct{}, len(traits))
+	files := make([]string, 0, len(traits))
+	for _, trait := range traits {
+		if _, ok := seen[trait.FilePath]; ok || trait.FilePath == "" {
+			continue
+		}
+		seen[trait.FilePath] = struct{}{}
+		files = append(files, trait.FilePath)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func fieldUsesAnyValue(raw interface{}, values map[string]struct{}) bool {
+	switch typed := raw.(type) {
+	case []interface{}:
+		for _, item := range typed {
+			if fieldUsesAnyValue(item, values) {
+				return true
+			}
+		}
+		return false
+	default:
+		_, ok := values[toStringSafe(typed)]
+		return ok
+	}
+}
+
+func matchingSavedQueries(vaultPath string, match func(string) bool) []ImpactQuery {
+	matches := []ImpactQuery{}
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil || vaultCfg == nil {
+		return matches
+	}
+	names := make([]string, 0, len(vaultCfg.Queries))
+	for name := range vaultCfg.Queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		q := vaultCfg.Queries[name]
+		if q == nil || q.Query == "" || !match(q.Query) {
+			continue
+		}
+		matches = append(matches, ImpactQuery{Name: name, Query: q.Query})
+	}
+	return matches
+}
+
+// boundTemplates returns the templates attached to a type (schema-level
+// template bindings plus the legacy per-type template path) for which match
+// reports true. match receives the template file contents.
+func boundTemplates(vaultPath string, sch *schema.Schema, typeDef *schema.TypeDefinition, match func(content string) (string, bool)) []ImpactTemplate {
+	templates := []ImpactTemplate{}
+	if typeDef == nil {
+		return templates
+	}
+
+	check := func(id, file string) {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			return
+		}
+		absPath := filepath.Join(vaultPath, filepath.FromSlash(file))
+		if err := paths.ValidateWithinVault(vaultPath, absPath); err != nil {
+			return
+		}
+		content, _ := vaultcrypt.ReadFile(absPath)
+		if reason, ok := match(string(content)); ok {
+			templates = append(templates, ImpactTemplate{ID: id, File: file, Reason: reason})
+		}
+	}
+
+	for _, id := range typeDef.Templates {
+		if def := sch.Templates[id]; def != nil {
+			check(id, def.File)
+		}
+	}
+	if looksLikeTemplatePath(typeDef.Template) {
+		check("", typeDef.Template)
+	}
+	return templates
+}
+
+func sortedTypeNames(sch *schema.Schema) []string {
+	names := make([]string, 0, len(sch.Types))
+	for name := range sch.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedFieldNames(typeDef *schema.TypeDefinition) []string {
+	names := make([]string, 0, len(typeDef.Fields))
+	for name := range typeDef.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,192 @@
+package schemasvc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/reindexsvc"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+const impactTestSchema = `version: 1
+types:
+  person:
+    name_field: name
+    fields:
+      name:
+        type: string
+        required: true
+  project:
+    name_field: title
+    templates: [project_default]
+    fields:
+      title:
+        type: string
+        required: true
+      status:
+        type: enum
+        values: [active, paused, done]
+      owner:
+        type: ref
+        target: person
+traits:
+  due:
+    type: date
+  priority:
+    type: enum
+    values: [low, medium, high]
+templates:
+  project_default:
+    file: templates/project.md
+`
+
+func buildImpactVault(t *testing.T) string {
+	t.Helper()
+
+	vault := testutil.NewTestVault(t).
+		WithSchema(impactTestSchema).
+		WithRavenYAML(`queries:
+  active-projects:
+    query: "type:project .status==active"
+  paused-projects:
+    query: "type:project .status==paused"
+  people:
+    query: "type:person"
+  due-soon:
+    query: "trait:due .value<today"
+`).
+		WithFile("templates/project.md", "# {{field.title}}\n\nOwner: {{field.owner}}\n\n- @due(today) kickoff\n").
+		WithFile("projects/alpha.md", "---\ntype: project\ntitle: Alpha\nstatus: active\n---\n- @due(2026-01-01) ship\n").
+		WithFile("projects/beta.md", "---\ntype: project\ntitle: Beta\nstatus: paused\n---\n").
+		WithFile("people/freya.md", "---\ntype: person\nname: Freya\n---\n").
+		Build()
+
+	if _, err := reindexsvc.Run(reindexsvc.RunRequest{VaultPath: vault.Path, Full: true}); err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+	return vault.Path
+}
+
+func impactQueryNames(impact *Impact) []string {
+	names := make([]string, 0, len(impact.Queries))
+	for _, q := range impact.Queries {
+		names = append(names, q.Name)
+	}
+	return names
+}
+
+func TestAnalyzeImpact(t *testing.T) {
+	t.Parallel()
+	vaultPath := buildImpactVault(t)
+
+	tests := []struct {
+		name        string
+		analyze     func() (*Impact, error)
+		wantKind    string
+		wantFiles   []string
+		wantQueries []string
+		wantTmpls   int
+		wantRefs    int
+	}{
+		{
+			name:        "type",
+			analyze:     func() (*Impact, error) { return AnalyzeTypeImpact(vaultPath, "person") },
+			wantKind:    ImpactKindType,
+			wantFiles:   []string{"people/freya.md"},
+			wantQueries: []string{"people"},
+			wantRefs:    1,
+		},
+		{
+			name:        "trait",
+			analyze:     func() (*Impact, error) { return AnalyzeTraitImpact(vaultPath, "due") },
+			wantKind:    ImpactKindTrait,
+			wantFiles:   []string{"projects/alpha.md", "templates/project.md"},
+			wantQueries: []string{"due-soon"},
+			wantTmpls:   1,
+		},
+		{
+			name:        "field",
+			analyze:     func() (*Impact, error) { return AnalyzeFieldImpact(vaultPath, "project", "status") },
+			wantKind:    ImpactKindField,
+			wantFiles:   []string{"projects/alpha.md", "projects/beta.md"},
+			wantQueries: []string{"active-projects", "paused-projects"},
+		},
+		{
+			name:        "bound template field",
+			analyze:     func() (*Impact, error) { return AnalyzeFieldImpact(vaultPath, "project", "owner") },
+			wantKind:    ImpactKindField,
+			wantFiles:   []string{},
+			wantQueries: []string{},
+			wantTmpls:   1,
+		},
+		{
+			name: "enum values",
+			analyze: func() (*Impact, error) {
+				return AnalyzeEnumValuesImpact(vaultPath, "project", "status", []string{"paused"})
+			},
+			wantKind:    ImpactKindEnumValues,
+			wantFiles:   []string{"projects/beta.md"},
+			wantQueries: []string{"paused-projects"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			impact, err := tt.analyze()
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			if impact.Kind != tt.wantKind {
+				t.Errorf("kind = %q, want %q", impact.Kind, tt.wantKind)
+			}
+			if !reflect.DeepEqual(impact.Files, tt.wantFiles) {
+				t.Errorf("files = %v, want %v", impact.Files, tt.wantFiles)
+			}
+			if got := impactQueryNames(impact); !reflect.DeepEqual(got, tt.wantQueries) {
+				t.Errorf("queries = %v, want %v", got, tt.wantQueries)
+			}
+			if len(impact.Templates) != tt.wantTmpls {
+				t.Errorf("templates = %#v, want %d", impact.Templates, tt.wantTmpls)
+			}
+			if len(impact.SchemaRefs) != tt.wantRefs {
+				t.Errorf("schema refs = %#v, want %d", impact.SchemaRefs, tt.wantRefs)
+			}
+		})
+	}
+}
+
+func TestUpdateFieldReportsDroppedEnumValues(t *testing.T) {
+	t.Parallel()
+	vaultPath := buildImpactVault(t)
+
+	result, err := UpdateField(UpdateFieldRequest{
+		VaultPath: vaultPath,
+		TypeName:  "project",
+		FieldName: "status",
+		Values:    "active,done",
+	})
+	if err != nil {
+		t.Fatalf("UpdateField: %v", err)
+	}
+	if result.Impact == nil {
+		t.Fatal("expected impact for dropped enum value")
+	}
+	if !reflect.DeepEqual(result.Impact.Values, []string{"paused"}) || result.Impact.ObjectCount != 1 {
+		t.Fatalf("impact = %#v, want one object using 'paused'", result.Impact)
+	}
+
+	result, err = UpdateField(UpdateFieldRequest{
+		VaultPath: vaultPath,
+		TypeName:  "project",
+		FieldName: "status",
+		Values:    "active,done,archived",
+	})
+	if err != nil {
+		t.Fatalf("UpdateField: %v", err)
+	}
+	if result.Impact != nil {
+		t.Fatalf("adding enum values should not report impact: %#v", result.Impact)
+	}
+}
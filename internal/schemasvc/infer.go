@@ -0,0 +1,682 @@
+package schemasvc
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/dates"
+	"github.com/aidanlsb/raven/internal/fieldmutation"
+	"github.com/aidanlsb/raven/internal/frontmatter"
+	"github.com/aidanlsb/raven/internal/history"
+	ravenignore "github.com/aidanlsb/raven/internal/ignore"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/slugs"
+	"github.com/aidanlsb/raven/internal/vault"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+const (
+	// InferSourceDirectory marks a type proposed for untyped files sharing a
+	// directory.
+	InferSourceDirectory = "directory"
+	// InferSourceFrontmatter marks a type that files already declare with
+	// `type:` but schema.yaml does not define.
+	InferSourceFrontmatter = "frontmatter"
+
+	// DefaultInferMinFiles is how many untyped files a directory needs before
+	// it is proposed as a type.
+	DefaultInferMinFiles = 2
+
+	// inferEnumMaxValues caps the distinct values of an enum candidate.
+	inferEnumMaxValues = 8
+	// inferExampleLimit caps the example values kept per field or trait.
+	inferExampleLimit = 3
+)
+
+type InferRequest struct {
+	VaultPath string
+	// MinFiles is the fewest untyped files a directory needs to become a
+	// type. Zero uses DefaultInferMinFiles.
+	MinFiles int
+	// Accept limits which proposals are written, by key (type:<name> or
+	// trait:<name>). Empty accepts every proposal.
+	Accept []string
+	// Confirm writes the accepted proposals; otherwise they are only
+	// returned.
+	Confirm bool
+}
+
+// InferredType is a proposed type definition.
+type InferredType struct {
+	Key         string          `json:"key"`
+	Name        string          `json:"name"`
+	Source      string          `json:"source"`
+	DefaultPath string          `json:"default_path,omitempty"`
+	Files       int             `json:"files"`
+	Fields      []InferredField `json:"fields"`
+	// UntypedFiles get `type: <name>` when the proposal is applied.
+	UntypedFiles []string `json:"untyped_files,omitempty"`
+}
+
+// InferredField is a proposed field, with the number of files that set it.
+type InferredField struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Values   []string `json:"values,omitempty"`
+	Target   string   `json:"target,omitempty"`
+	Count    int      `json:"count"`
+	Examples []string `json:"examples,omitempty"`
+}
+
+// InferredTrait is a proposed trait for annotations schema.yaml does not
+// declare.
+type InferredTrait struct {
+	Key      string   `json:"key"`
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Values   []string `json:"values,omitempty"`
+	Count    int      `json:"count"`
+	Examples []string `json:"examples,omitempty"`
+}
+
+type InferResult struct {
+	Preview bool
+	Types   []InferredType
+	Traits  []InferredTrait
+	// Applied lists the keys of the proposals written to schema.yaml.
+	Applied []string
+	// TypedFiles lists the files that were given a `type:`.
+	TypedFiles []string
+}
+
+// inferGroup collects the files behind one type proposal.
+type inferGroup struct {
+	name        string
+	source      string
+	dirs        map[string]int
+	files       []map[string]schema.FieldValue
+	untypedRels []string
+}
+
+// Infer scans frontmatter and trait annotations and proposes the types and
+// traits schema.yaml is missing: directories of untyped files, `type:` values
+// without a definition, and undeclared traits. Field and trait types are
+// inferred from the values in use; strings that repeat a few distinct values
+// become enums.
+func Infer(req InferRequest) (*InferResult, error) {
+	sch, err := loadSchema(req.VaultPath, "Run 'rvn init' first")
+	if err != nil {
+		return nil, err
+	}
+	vaultCfg, err := config.LoadVaultConfig(req.VaultPath)
+	if err != nil {
+		return nil, newError(ErrorConfigInvalid, err.Error(), "Fix raven.yaml and try again", nil, err)
+	}
+	minFiles := req.MinFiles
+	if minFiles <= 0 {
+		minFiles = DefaultInferMinFiles
+	}
+
+	groups, traitUses, idTypes, err := scanForInference(req.VaultPath, vaultCfg, sch)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InferResult{
+		Preview:    !req.Confirm,
+		Types:      []InferredType{},
+		Traits:     []InferredTrait{},
+		Applied:    []string{},
+		TypedFiles: []string{},
+	}
+
+	// Decide which groups become types before inferring fields, so ref
+	// targets can point at proposed types.
+	proposed := make(map[string]*inferGroup)
+	for name, group := range groups {
+		if group.source == InferSourceDirectory && len(group.files) < minFiles {
+			continue
+		}
+		proposed[name] = group
+	}
+	refTypes := make(map[string]string, len(idTypes))
+	shortTypes := make(map[string]string)
+	shortSeen := make(map[string]int)
+	for id, info := range idTypes {
+		typeName := info.declared
+		if typeName == "" || typeName == "page" {
+			if _, ok := proposed[info.group]; ok {
+				typeName = info.group
+			}
+		}
+		if typeName == "" || typeName == "page" {
+			continue
+		}
+		refTypes[id] = typeName
+		short := path.Base(id)
+		shortSeen[short]++
+		shortTypes[short] = typeName
+	}
+	for short, n := range shortSeen {
+		if n > 1 {
+			delete(shortTypes, short)
+		}
+	}
+	resolveRefType := func(target string) string {
+		target = strings.TrimSuffix(strings.SplitN(target, "#", 2)[0], ".md")
+		if typeName, ok := refTypes[target]; ok {
+			return typeName
+		}
+		return shortTypes[target]
+	}
+
+	names := make([]string, 0, len(proposed))
+	for name := range proposed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		group := proposed[name]
+		inferred := InferredType{
+			Key:          "type:" + name,
+			Name:         name,
+			Source:       group.source,
+			DefaultPath:  group.defaultPath(vaultCfg.GetObjectsRoot(), vaultCfg.GetPagesRoot()),
+			Files:        len(group.files),
+			Fields:       inferFields(group.files, resolveRefType),
+			UntypedFiles: group.untypedRels,
+		}
+		sort.Strings(inferred.UntypedFiles)
+		result.Types = append(result.Types, inferred)
+	}
+
+	traitNames := make([]string, 0, len(traitUses))
+	for name := range traitUses {
+		traitNames = append(traitNames, name)
+	}
+	sort.Strings(traitNames)
+	for _, name := range traitNames {
+		result.Traits = append(result.Traits, inferTrait(name, traitUses[name]))
+	}
+
+	if !req.Confirm {
+		return result, nil
+	}
+	if err := applyInference(req, vaultCfg, sch, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type inferIDInfo struct {
+	declared string
+	group    string
+}
+
+func scanForInference(vaultPath string, vaultCfg *config.VaultConfig, sch *schema.Schema) (map[string]*inferGroup, map[string][]*schema.FieldValue, map[string]inferIDInfo, error) {
+	excludeMatcher, err := ravenignore.NewMatcher(vaultCfg.GetExcludePatterns())
+	if err != nil {
+		return nil, nil, nil, newError(ErrorConfigInvalid, err.Error(), "", nil, err)
+	}
+	templateDir := strings.TrimSuffix(vaultCfg.GetTemplateDirectory(), "/") + "/"
+	dailyDir := strings.TrimSuffix(vaultCfg.GetDailyDirectory(), "/") + "/"
+
+	groups := make(map[string]*inferGroup)
+	traitUses := make(map[string][]*schema.FieldValue)
+	idTypes := make(map[string]inferIDInfo)
+	walkOpts := &vault.WalkOptions{
+		ParseOptions: &parser.ParseOptions{
+			ObjectsRoot:  vaultCfg.GetObjectsRoot(),
+			PagesRoot:    vaultCfg.GetPagesRoot(),
+			HashtagTrait: vaultCfg.GetHashtagTrait(),
+		},
+		ExcludeMatcher: excludeMatcher,
+	}
+	err = vault.WalkMarkdownFilesWithOptions(vaultPath, walkOpts, func(result vault.WalkResult) error {
+		if result.Error != nil || result.Document == nil || len(result.Document.Objects) == 0 {
+			return nil
+		}
+		relPath := filepath.ToSlash(result.RelativePath)
+		if strings.HasPrefix(relPath, templateDir) {
+			return nil
+		}
+		for _, trait := range result.Document.Traits {
+			if _, ok := sch.Traits[trait.TraitType]; !ok {
+				traitUses[trait.TraitType] = append(traitUses[trait.TraitType], trait.Value)
+			}
+		}
+		if strings.HasPrefix(relPath, dailyDir) {
+			return nil
+		}
+
+		obj := result.Document.Objects[0]
+		info := inferIDInfo{declared: obj.ObjectType}
+		dir := path.Dir(relPath)
+		switch {
+		case obj.ObjectType != "page":
+			if _, defined := sch.Types[obj.ObjectType]; defined || schema.IsBuiltinType(obj.ObjectType) {
+				break
+			}
+			group := ensureInferGroup(groups, obj.ObjectType, InferSourceFrontmatter)
+			group.source = InferSourceFrontmatter
+			group.dirs[dir]++
+			group.files = append(group.files, obj.Fields)
+		case dir != ".":
+			name := typeNameForDirectory(dir)
+			if name == "" || schema.IsBuiltinType(name) {
+				break
+			}
+			if _, defined := sch.Types[name]; defined {
+				break
+			}
+			group := ensureInferGroup(groups, name, InferSourceDirectory)
+			group.dirs[dir]++
+			group.files = append(group.files, obj.Fields)
+			group.untypedRels = append(group.untypedRels, relPath)
+			info.group = name
+		}
+		idTypes[obj.ID] = info
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, newError(ErrorFileRead, err.Error(), "", nil, err)
+	}
+	return groups, traitUses, idTypes, nil
+}
+
+func ensureInferGroup(groups map[string]*inferGroup, name, source string) *inferGroup {
+	group, ok := groups[name]
+	if !ok {
+		group = &inferGroup{name: name, source: source, dirs: make(map[string]int)}
+		groups[name] = group
+	}
+	return group
+}
+
+// defaultPath is the directory holding the group's files when they share
+// one, relative to the objects or pages root it sits under, since
+// default_path is resolved inside the objects root.
+func (g *inferGroup) defaultPath(roots ...string) string {
+	if len(g.dirs) != 1 {
+		return ""
+	}
+	for dir := range g.dirs {
+		dir += "/"
+		for _, root := range roots {
+			root = strings.TrimSuffix(root, "/") + "/"
+			if root != "/" && strings.HasPrefix(dir, root) {
+				dir = strings.TrimPrefix(dir, root)
+				break
+			}
+		}
+		if dir != "./" && dir != "" {
+			return dir
+		}
+	}
+	return ""
+}
+
+// typeNameForDirectory derives a type name from the last segment of dir,
+// singularizing common English plurals (projects -> project).
+func typeNameForDirectory(dir string) string {
+	name := slugs.ComponentSlug(path.Base(dir))
+	switch {
+	case name == "people":
+		return "person"
+	case strings.HasSuffix(name, "ies") && len(name) > 4:
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && !strings.HasSuffix(name, "us") && !strings.HasSuffix(name, "is") && len(name) > 3:
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
+
+// inferFields proposes a field for every frontmatter key the files set.
+func inferFields(files []map[string]schema.FieldValue, resolveRefType func(string) string) []InferredField {
+	values := make(map[string][]schema.FieldValue)
+	for _, fields := range files {
+		for name, value := range fields {
+			if name == "id" || name == "alias" || schema.IsAttributionField(name) || value.IsNull() {
+				continue
+			}
+			values[name] = append(values[name], value)
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := make([]InferredField, 0, len(names))
+	for _, name := range names {
+		field, ok := inferField(name, values[name], resolveRefType)
+		if ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func inferField(name string, values []schema.FieldValue, resolveRefType func(string) string) (InferredField, bool) {
+	isArray := false
+	var scalars []schema.FieldValue
+	for _, value := range values {
+		value = unwrapYAMLRef(value)
+		if items, ok := value.AsArray(); ok {
+			isArray = true
+			for _, item := range items {
+				scalars = append(scalars, unwrapYAMLRef(item))
+			}
+			continue
+		}
+		scalars = append(scalars, value)
+	}
+
+	field := InferredField{Name: name, Count: len(values), Examples: inferExamples(scalars)}
+	kind := inferValueKind(scalars)
+	switch kind {
+	case "":
+		return field, false
+	case "ref":
+		targets := make(map[string]struct{})
+		for _, value := range scalars {
+			ref, _ := value.AsRef()
+			if typeName := resolveRefType(ref); typeName != "" {
+				targets[typeName] = struct{}{}
+			}
+		}
+		if len(targets) == 1 {
+			for target := range targets {
+				field.Target = target
+			}
+		} else {
+			kind = "string"
+		}
+	case "string":
+		if enumValues := inferEnumValues(scalars); len(enumValues) > 0 {
+			kind = "enum"
+			field.Values = enumValues
+		}
+	}
+	field.Type = kind
+	if isArray {
+		field.Type += "[]"
+	}
+	return field, true
+}
+
+// inferTrait proposes a trait type from the values it is used with. Traits
+// never given a value are boolean.
+func inferTrait(name string, uses []*schema.FieldValue) InferredTrait {
+	var values []schema.FieldValue
+	for _, value := range uses {
+		if value != nil && !value.IsNull() {
+			values = append(values, *value)
+		}
+	}
+	trait := InferredTrait{Key: "trait:" + name, Name: name, Type: "boolean", Count: len(uses), Examples: inferExamples(values)}
+	if len(values) == 0 {
+		return trait
+	}
+	trait.Type = inferValueKind(values)
+	switch trait.Type {
+	case "", "ref":
+		trait.Type = "string"
+	}
+	if trait.Type == "string" && allNumericStrings(values) {
+		trait.Type = "number"
+	}
+	if trait.Type == "string" {
+		if enumValues := inferEnumValues(values); len(enumValues) > 0 {
+			trait.Type = "enum"
+			trait.Values = enumValues
+		}
+	}
+	return trait
+}
+
+// inferValueKind returns the one base type every value fits, falling back
+// to string when they disagree. Nested objects are not inferred.
+func inferValueKind(values []schema.FieldValue) string {
+	kinds := make(map[string]int)
+	for _, value := range values {
+		if value.IsNull() {
+			continue
+		}
+		if _, ok := value.AsObject(); ok {
+			return ""
+		}
+		kinds[scalarKind(value)]++
+	}
+	switch {
+	case len(kinds) == 0:
+		return "string"
+	case len(kinds) == 1:
+		for kind := range kinds {
+			return kind
+		}
+	case len(kinds) == 2 && kinds["date"] > 0 && kinds["datetime"] > 0:
+		return "datetime"
+	}
+	return "string"
+}
+
+func scalarKind(value schema.FieldValue) string {
+	if value.IsRef() {
+		return "ref"
+	}
+	if _, ok := value.AsBool(); ok {
+		return "bool"
+	}
+	if _, ok := value.AsNumber(); ok {
+		return "number"
+	}
+	s, ok := value.AsString()
+	if !ok {
+		return "string"
+	}
+	s = strings.TrimSpace(s)
+	switch {
+	case dates.IsValidDate(s):
+		return "date"
+	case dates.IsValidDatetime(s):
+		return "datetime"
+	case strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://"):
+		return "url"
+	}
+	return "string"
+}
+
+// allNumericStrings reports whether every value is a string that parses as
+// a number. Trait values are not parsed as numbers, so @rating(4) reads as "4".
+func allNumericStrings(values []schema.FieldValue) bool {
+	for _, value := range values {
+		s, ok := value.AsString()
+		if !ok || value.IsRef() {
+			return false
+		}
+		if _, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err != nil {
+			return false
+		}
+	}
+	return len(values) > 0
+}
+
+// inferEnumValues returns the distinct values, sorted, when they repeat
+// enough to look like a fixed set, and nil otherwise.
+func inferEnumValues(values []schema.FieldValue) []string {
+	counts := make(map[string]int)
+	for _, value := range values {
+		s, ok := value.AsString()
+		if !ok || strings.TrimSpace(s) == "" || strings.Contains(s, "\n") {
+			return nil
+		}
+		counts[s]++
+	}
+	if len(counts) == 0 || len(counts) > inferEnumMaxValues || len(values) < 3 || len(values) <= len(counts) {
+		return nil
+	}
+	distinct := make([]string, 0, len(counts))
+	for value := range counts {
+		distinct = append(distinct, value)
+	}
+	sort.Strings(distinct)
+	return distinct
+}
+
+func inferExamples(values []schema.FieldValue) []string {
+	seen := make(map[string]struct{})
+	examples := make([]string, 0, inferExampleLimit)
+	for _, value := range values {
+		literal := fieldmutation.SerializeFieldValueLiteral(value)
+		if _, ok := seen[literal]; ok {
+			continue
+		}
+		seen[literal] = struct{}{}
+		examples = append(examples, literal)
+		if len(examples) == inferExampleLimit {
+			break
+		}
+	}
+	sort.Strings(examples)
+	return examples
+}
+
+// applyInference writes the accepted proposals to schema.yaml and sets
+// `type:` on the untyped files behind accepted directory types.
+func applyInference(req InferRequest, vaultCfg *config.VaultConfig, sch *schema.Schema, result *InferResult) error {
+	accepted := make(map[string]bool, len(req.Accept))
+	for _, key := range req.Accept {
+		accepted[strings.TrimSpace(key)] = true
+	}
+	isAccepted := func(key string) bool {
+		return len(accepted) == 0 || accepted[key]
+	}
+
+	schemaDoc, err := readSchemaDoc(req.VaultPath)
+	if err != nil {
+		return err
+	}
+	typesNode := ensureMapNode(schemaDoc, "types")
+	addedTypes := make(map[string]bool)
+	for _, inferred := range result.Types {
+		if isAccepted(inferred.Key) {
+			addedTypes[inferred.Name] = true
+		}
+	}
+
+	var typed []inferTypedFile
+	for _, inferred := range result.Types {
+		if !addedTypes[inferred.Name] {
+			continue
+		}
+		typeNode := make(map[string]interface{})
+		if inferred.DefaultPath != "" {
+			typeNode["default_path"] = inferred.DefaultPath
+		}
+		if len(inferred.Fields) > 0 {
+			fields := make(map[string]interface{}, len(inferred.Fields))
+			for _, field := range inferred.Fields {
+				fieldNode := map[string]interface{}{"type": field.Type}
+				if len(field.Values) > 0 {
+					fieldNode["values"] = field.Values
+				}
+				if field.Target != "" {
+					if _, defined := sch.Types[field.Target]; defined || addedTypes[field.Target] {
+						fieldNode["target"] = field.Target
+					} else {
+						// The target type was not accepted; keep the links as text.
+						fieldNode["type"] = strings.Replace(field.Type, "ref", "string", 1)
+					}
+				}
+				fields[field.Name] = fieldNode
+			}
+			typeNode["fields"] = fields
+		}
+		typesNode[inferred.Name] = typeNode
+		result.Applied = append(result.Applied, inferred.Key)
+		for _, rel := range inferred.UntypedFiles {
+			typed = append(typed, inferTypedFile{rel: rel, typeName: inferred.Name})
+		}
+	}
+
+	traitsNode := ensureMapNode(schemaDoc, "traits")
+	for _, inferred := range result.Traits {
+		if !isAccepted(inferred.Key) {
+			continue
+		}
+		traitNode := map[string]interface{}{"type": inferred.Type}
+		if len(inferred.Values) > 0 {
+			traitNode["values"] = inferred.Values
+		}
+		traitsNode[inferred.Name] = traitNode
+		result.Applied = append(result.Applied, inferred.Key)
+	}
+	if len(traitsNode) == 0 {
+		delete(schemaDoc, "traits")
+	}
+
+	if len(result.Applied) == 0 {
+		return nil
+	}
+	if err := writeSchemaDoc(req.VaultPath, schemaDoc); err != nil {
+		return err
+	}
+	for _, file := range typed {
+		if vaultCfg.IsLockedPath(file.rel) {
+			continue
+		}
+		if err := setFileType(filepath.Join(req.VaultPath, filepath.FromSlash(file.rel)), file.typeName); err != nil {
+			return newError(ErrorFileWrite, fmt.Sprintf("%s: %v", file.rel, err), "", nil, err)
+		}
+		result.TypedFiles = append(result.TypedFiles, file.rel)
+	}
+	return nil
+}
+
+type inferTypedFile struct {
+	rel      string
+	typeName string
+}
+
+// setFileType adds `type:` as the first frontmatter line of the untyped file
+// at absPath, leaving the other lines as written, or adds frontmatter when
+// the file has none.
+func setFileType(absPath, typeName string) error {
+	content, err := vaultcrypt.ReadFile(absPath)
+	if err != nil {
+		return err
+	}
+	var updated string
+	lines := strings.Split(string(content), "\n")
+	if start, end, ok := parser.FrontmatterBounds(lines); ok {
+		if end == -1 {
+			return fmt.Errorf("unclosed frontmatter")
+		}
+		lines = slices.Insert(lines, start+1, "type: "+typeName)
+		updated = strings.Join(lines, "\n")
+	} else {
+		header, err := frontmatter.Render(typeName, nil, nil)
+		if err != nil {
+			return err
+		}
+		updated = header + string(content)
+	}
+	history.Capture(absPath)
+	sealed, err := vaultcrypt.Seal(absPath, []byte(updated))
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(absPath, sealed, 0o644)
+}
@@ -0,0 +1,143 @@
+package schemasvc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+const inferSchema = `version: 1
+types:
+  person:
+    default_path: people/
+    fields:
+      name:
+        type: string
+traits: {}
+`
+
+func newInferVault(t *testing.T) *testutil.TestVault {
+	t.Helper()
+	return testutil.NewTestVault(t).
+		WithSchema(inferSchema).
+		WithFile("people/freya.md", "---\ntype: person\nname: Freya\n---\n").
+		WithFile("books/dune.md", "---\nauthor: \"[[people/freya]]\"\nstatus: reading\nstarted: 2026-01-05\nfinished: false\n---\n@rating(4) great\n").
+		WithFile("books/emma.md", "---\nauthor: \"[[freya]]\"\nstatus: done\nstarted: 2026-02-01\nfinished: true\n---\n@rating(3)\n").
+		WithFile("books/ubik.md", "---\nstatus: reading\nstarted: 2026-03-09\n---\n@highlight\n").
+		WithFile("notes/solo.md", "---\nmood: fine\n---\n").
+		WithFile("inbox.md", "---\ntype: meeting\nwhen: 2026-01-01T10:00\n---\n").
+		Build()
+}
+
+func TestInfer_ProposesTypesAndTraits(t *testing.T) {
+	t.Parallel()
+
+	vault := newInferVault(t)
+	result, err := Infer(InferRequest{VaultPath: vault.Path})
+	if err != nil {
+		t.Fatalf("Infer returned error: %v", err)
+	}
+	if !result.Preview {
+		t.Fatal("expected a preview without confirm")
+	}
+
+	if len(result.Types) != 2 || result.Types[0].Name != "book" || result.Types[1].Name != "meeting" {
+		t.Fatalf("types = %+v, want book and meeting (notes/ has too few files)", result.Types)
+	}
+	book := result.Types[0]
+	if book.Source != InferSourceDirectory || book.DefaultPath != "books/" || book.Files != 3 {
+		t.Fatalf("book = %+v", book)
+	}
+	fields := make(map[string]InferredField)
+	for _, field := range book.Fields {
+		fields[field.Name] = field
+	}
+	if f := fields["author"]; f.Type != "ref" || f.Target != "person" || f.Count != 2 {
+		t.Fatalf("author = %+v, want ref to person", f)
+	}
+	if f := fields["status"]; f.Type != "enum" || !reflect.DeepEqual(f.Values, []string{"done", "reading"}) {
+		t.Fatalf("status = %+v, want enum [done reading]", f)
+	}
+	if f := fields["started"]; f.Type != "date" {
+		t.Fatalf("started = %+v, want date", f)
+	}
+	if f := fields["finished"]; f.Type != "bool" {
+		t.Fatalf("finished = %+v, want bool", f)
+	}
+
+	meeting := result.Types[1]
+	if meeting.Source != InferSourceFrontmatter || len(meeting.UntypedFiles) != 0 || meeting.Fields[0].Type != "datetime" {
+		t.Fatalf("meeting = %+v", meeting)
+	}
+
+	traits := make(map[string]InferredTrait)
+	for _, trait := range result.Traits {
+		traits[trait.Name] = trait
+	}
+	if traits["highlight"].Type != "boolean" || traits["rating"].Type != "number" {
+		t.Fatalf("traits = %+v, want highlight boolean and rating number", result.Traits)
+	}
+}
+
+func TestInfer_ConfirmWritesAcceptedProposals(t *testing.T) {
+	t.Parallel()
+
+	vault := newInferVault(t)
+	result, err := Infer(InferRequest{
+		VaultPath: vault.Path,
+		Accept:    []string{"type:book", "trait:rating"},
+		Confirm:   true,
+	})
+	if err != nil {
+		t.Fatalf("Infer returned error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Applied, []string{"type:book", "trait:rating"}) {
+		t.Fatalf("applied = %v", result.Applied)
+	}
+	if len(result.TypedFiles) != 3 {
+		t.Fatalf("typed files = %v, want the three books", result.TypedFiles)
+	}
+
+	loaded, err := schema.Load(vault.Path)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	book := loaded.Types["book"]
+	if book == nil || book.DefaultPath != "books/" || book.Fields["status"].Type != schema.FieldTypeEnum {
+		t.Fatalf("book type not written as expected: %+v", book)
+	}
+	if _, ok := loaded.Types["meeting"]; ok {
+		t.Fatal("meeting was not accepted and should not be written")
+	}
+	if loaded.Traits["rating"] == nil || loaded.Traits["highlight"] != nil {
+		t.Fatalf("traits = %+v, want only rating", loaded.Traits)
+	}
+
+	content := vault.ReadFile("books/dune.md")
+	if !strings.HasPrefix(content, "---\ntype: book\nauthor: \"[[people/freya]]\"\n") || !strings.Contains(content, "started: 2026-01-05\n") {
+		t.Fatalf("books/dune.md should gain type: and keep its other lines:\n%s", content)
+	}
+}
+
+func TestTypeNameForDirectory(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"projects":        "project",
+		"notes/People":    "person",
+		"stories":         "story",
+		"boxes":           "box",
+		"status":          "status",
+		"press":           "press",
+		"reading-lists":   "reading-list",
+		"journal/entries": "entry",
+	}
+	for dir, want := range tests {
+		if got := typeNameForDirectory(dir); got != want {
+			t.Errorf("typeNameForDirectory(%q) = %q, want %q", dir, got, want)
+		}
+	}
+}
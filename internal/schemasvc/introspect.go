@@ -21,12 +21,27 @@ type TypeSchema struct {
 	Name            string                 `json:"name"`
 	Builtin         bool                   `json:"builtin"`
 	DefaultPath     string                 `json:"default_path,omitempty"`
+	Extends         string                 `json:"extends,omitempty"`
 	Description     string                 `json:"description,omitempty"`
 	NameField       string                 `json:"name_field,omitempty"`
 	Template        string                 `json:"template,omitempty"`
 	Templates       []string               `json:"templates,omitempty"`
 	DefaultTemplate string                 `json:"default_template,omitempty"`
+	RequiredTraits  []string               `json:"required_traits,omitempty"`
 	Fields          map[string]FieldSchema `json:"fields,omitempty"`
+	Usage           *TypeUsage             `json:"usage,omitempty"`
+}
+
+// TypeUsage summarizes how many indexed objects use a type.
+type TypeUsage struct {
+	ObjectCount int    `json:"object_count"`
+	LastUsed    string `json:"last_used,omitempty"` // YYYY-MM-DD of the newest file using the type
+}
+
+// TraitUsage summarizes how many indexed trait instances use a trait.
+type TraitUsage struct {
+	InstanceCount int    `json:"instance_count"`
+	LastUsed      string `json:"last_used,omitempty"` // YYYY-MM-DD of the newest file using the trait
 }
 
 type CoreTypeSchema struct {
@@ -42,19 +57,25 @@ type TemplateSchema struct {
 }
 
 type FieldSchema struct {
-	Type        string   `json:"type"`
-	Required    bool     `json:"required"`
-	Default     string   `json:"default,omitempty"`
-	Values      []string `json:"values,omitempty"`
-	Target      string   `json:"target,omitempty"`
-	Description string   `json:"description,omitempty"`
+	Type          string   `json:"type"`
+	Required      bool     `json:"required"`
+	Default       string   `json:"default,omitempty"`
+	Values        []string `json:"values,omitempty"`
+	Target        string   `json:"target,omitempty"`
+	Description   string   `json:"description,omitempty"`
+	Pattern       string   `json:"pattern,omitempty"`
+	Min           string   `json:"min,omitempty"`
+	Max           string   `json:"max,omitempty"`
+	Unique        bool     `json:"unique,omitempty"`
+	InheritedFrom string   `json:"inherited_from,omitempty"`
 }
 
 type TraitSchema struct {
-	Name    string   `json:"name"`
-	Type    string   `json:"type"`
-	Values  []string `json:"values,omitempty"`
-	Default string   `json:"default,omitempty"`
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Values  []string    `json:"values,omitempty"`
+	Default string      `json:"default,omitempty"`
+	Usage   *TraitUsage `json:"usage,omitempty"`
 }
 
 type SavedQueryInfo struct {
@@ -72,12 +93,14 @@ type TypesHint struct {
 }
 
 type TypesResult struct {
-	Types map[string]TypeSchema `json:"types"`
-	Hint  *TypesHint            `json:"hint,omitempty"`
+	Types            map[string]TypeSchema `json:"types"`
+	Hint             *TypesHint            `json:"hint,omitempty"`
+	UsageUnavailable bool                  `json:"usage_unavailable,omitempty"`
 }
 
 type TraitsResult struct {
-	Traits map[string]TraitSchema `json:"traits"`
+	Traits           map[string]TraitSchema `json:"traits"`
+	UsageUnavailable bool                   `json:"usage_unavailable,omitempty"`
 }
 
 type CoreResult struct {
@@ -192,6 +215,7 @@ func Types(vaultPath string) (*TypesResult, error) {
 	types["date"] = TypeSchema{Name: "date", Builtin: true}
 
 	out := &TypesResult{Types: types}
+	out.UsageUnavailable = !attachTypeUsage(vaultPath, types)
 
 	var typesWithoutNameField []string
 	for name, typeDef := range sch.Types {
@@ -228,7 +252,9 @@ func Traits(vaultPath string) (*TraitsResult, error) {
 		traits[name] = buildTraitSchema(name, traitDef)
 	}
 
-	return &TraitsResult{Traits: traits}, nil
+	out := &TraitsResult{Traits: traits}
+	out.UsageUnavailable = !attachTraitUsage(vaultPath, traits)
+	return out, nil
 }
 
 func CoreList(vaultPath string) (*CoreResult, error) {
@@ -294,11 +320,13 @@ func buildTypeSchema(name string, typeDef *schema.TypeDefinition, builtin bool)
 	}
 
 	result.DefaultPath = typeDef.DefaultPath
+	result.Extends = typeDef.Extends
 	result.Description = typeDef.Description
 	result.NameField = typeDef.NameField
 	result.Template = typeDef.Template
 	result.Templates = append([]string(nil), typeDef.Templates...)
 	result.DefaultTemplate = typeDef.DefaultTemplate
+	result.RequiredTraits = append([]string(nil), typeDef.RequiredTraits...)
 
 	if len(typeDef.Fields) > 0 {
 		result.Fields = make(map[string]FieldSchema)
@@ -311,12 +339,17 @@ func buildTypeSchema(name string, typeDef *schema.TypeDefinition, builtin bool)
 				defaultStr = fmt.Sprintf("%v", fieldDef.Default)
 			}
 			result.Fields[fieldName] = FieldSchema{
-				Type:        string(fieldDef.Type),
-				Required:    fieldDef.Required,
-				Default:     defaultStr,
-				Values:      fieldDef.Values,
-				Target:      fieldDef.Target,
-				Description: fieldDef.Description,
+				Type:          string(fieldDef.Type),
+				Required:      fieldDef.Required,
+				Default:       defaultStr,
+				Values:        fieldDef.Values,
+				Target:        fieldDef.Target,
+				Description:   fieldDef.Description,
+				Pattern:       fieldDef.Pattern,
+				Min:           fieldBound(fieldDef.Min, fieldDef.MinDate),
+				Max:           fieldBound(fieldDef.Max, fieldDef.MaxDate),
+				Unique:        fieldDef.Unique,
+				InheritedFrom: typeDef.InheritedFields[fieldName],
 			}
 		}
 	}
@@ -324,6 +357,13 @@ func buildTypeSchema(name string, typeDef *schema.TypeDefinition, builtin bool)
 	return result
 }
 
+func fieldBound(number *float64, date string) string {
+	if number != nil {
+		return fmt.Sprintf("%v", *number)
+	}
+	return date
+}
+
 func buildCoreTypeSchema(name string, coreDef *schema.CoreTypeDefinition) CoreTypeSchema {
 	result := CoreTypeSchema{Name: name}
 	if coreDef == nil {
@@ -48,3 +48,39 @@ func TestBuildTypeSchemaIncludesTemplateBindings(t *testing.T) {
 		t.Fatalf("expected default template %q, got %q", "interview_technical", result.DefaultTemplate)
 	}
 }
+
+func TestTypesAndTraitsIncludeUsage(t *testing.T) {
+	t.Parallel()
+	vaultPath := buildImpactVault(t)
+
+	types, err := Types(vaultPath)
+	if err != nil {
+		t.Fatalf("Types: %v", err)
+	}
+	if types.UsageUnavailable {
+		t.Fatal("expected usage to be available")
+	}
+	project := types.Types["project"]
+	if project.Usage == nil || project.Usage.ObjectCount != 2 || project.Usage.LastUsed == "" {
+		t.Fatalf("project usage = %#v, want 2 objects with a last-used date", project.Usage)
+	}
+	person := types.Types["person"]
+	if person.Usage == nil || person.Usage.ObjectCount != 1 {
+		t.Fatalf("person usage = %#v, want 1 object", person.Usage)
+	}
+	if section := types.Types["section"]; section.Usage == nil || section.Usage.ObjectCount != 0 || section.Usage.LastUsed != "" {
+		t.Fatalf("section usage = %#v, want zero count and no date", section.Usage)
+	}
+
+	traits, err := Traits(vaultPath)
+	if err != nil {
+		t.Fatalf("Traits: %v", err)
+	}
+	due := traits.Traits["due"]
+	if due.Usage == nil || due.Usage.InstanceCount != 2 {
+		t.Fatalf("due usage = %#v, want 2 instances", due.Usage)
+	}
+	if priority := traits.Traits["priority"]; priority.Usage == nil || priority.Usage.InstanceCount != 0 {
+		t.Fatalf("priority usage = %#v, want 0 instances", priority.Usage)
+	}
+}
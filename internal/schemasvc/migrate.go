@@ -0,0 +1,277 @@
+package schemasvc
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/dates"
+	"github.com/aidanlsb/raven/internal/fieldmutation"
+	"github.com/aidanlsb/raven/internal/history"
+	ravenignore "github.com/aidanlsb/raven/internal/ignore"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vault"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+// FieldMigration describes how the existing values of a field convert when
+// its type changes. Only frontmatter values are migrated.
+type FieldMigration struct {
+	From        string                   `json:"from"`
+	To          string                   `json:"to"`
+	Conversions []FieldConversion        `json:"conversions"`
+	Failures    []FieldConversionFailure `json:"failures"`
+}
+
+// FieldConversion is one value rewritten to the new type.
+type FieldConversion struct {
+	ID       string `json:"id"`
+	FilePath string `json:"file_path"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+
+	path  string
+	value schema.FieldValue
+}
+
+// FieldConversionFailure is a value that cannot be converted to the new type.
+type FieldConversionFailure struct {
+	ID       string `json:"id"`
+	FilePath string `json:"file_path"`
+	Value    string `json:"value"`
+	Reason   string `json:"reason"`
+}
+
+// pending reports whether any existing value needs attention.
+func (m *FieldMigration) pending() bool {
+	return m != nil && (len(m.Conversions) > 0 || len(m.Failures) > 0)
+}
+
+// planFieldMigration scans the objects of typeName and converts each value of
+// fieldName to the type in def. Values that already fit are left out.
+func planFieldMigration(vaultPath, typeName, fieldName string, from schema.FieldType, def *schema.FieldDefinition) (*FieldMigration, error) {
+	vaultCfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		return nil, newError(ErrorConfigInvalid, err.Error(), "Fix raven.yaml and try again", nil, err)
+	}
+	excludeMatcher, err := ravenignore.NewMatcher(vaultCfg.GetExcludePatterns())
+	if err != nil {
+		return nil, newError(ErrorConfigInvalid, err.Error(), "", nil, err)
+	}
+	templateDir := strings.TrimSuffix(vaultCfg.GetTemplateDirectory(), "/") + "/"
+
+	migration := &FieldMigration{
+		From:        string(from),
+		To:          string(def.Type),
+		Conversions: []FieldConversion{},
+		Failures:    []FieldConversionFailure{},
+	}
+	walkOpts := &vault.WalkOptions{
+		ParseOptions: &parser.ParseOptions{
+			ObjectsRoot: vaultCfg.GetObjectsRoot(),
+			PagesRoot:   vaultCfg.GetPagesRoot(),
+		},
+		ExcludeMatcher: excludeMatcher,
+	}
+	err = vault.WalkMarkdownFilesWithOptions(vaultPath, walkOpts, func(result vault.WalkResult) error {
+		if result.Error != nil || result.Document == nil || len(result.Document.Objects) == 0 {
+			return nil
+		}
+		relPath := filepath.ToSlash(result.RelativePath)
+		if strings.HasPrefix(relPath, templateDir) {
+			return nil
+		}
+		obj := result.Document.Objects[0]
+		if obj.ObjectType != typeName {
+			return nil
+		}
+		value, ok := obj.Fields[fieldName]
+		if !ok || value.IsNull() {
+			return nil
+		}
+
+		literal := fieldmutation.SerializeFieldValueLiteral(value)
+		converted, err := convertFieldValue(value, def)
+		if err == nil && vaultCfg.IsLockedPath(relPath) && fieldmutation.SerializeFieldValueLiteral(converted) != literal {
+			err = fmt.Errorf("file is locked")
+		}
+		if err != nil {
+			migration.Failures = append(migration.Failures, FieldConversionFailure{
+				ID:       obj.ID,
+				FilePath: relPath,
+				Value:    literal,
+				Reason:   err.Error(),
+			})
+			return nil
+		}
+		if convertedLiteral := fieldmutation.SerializeFieldValueLiteral(converted); convertedLiteral != literal {
+			migration.Conversions = append(migration.Conversions, FieldConversion{
+				ID:       obj.ID,
+				FilePath: relPath,
+				From:     literal,
+				To:       convertedLiteral,
+				path:     result.Path,
+				value:    converted,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, newError(ErrorFileRead, err.Error(), "", nil, err)
+	}
+
+	sort.Slice(migration.Conversions, func(i, j int) bool {
+		return migration.Conversions[i].FilePath < migration.Conversions[j].FilePath
+	})
+	sort.Slice(migration.Failures, func(i, j int) bool {
+		return migration.Failures[i].FilePath < migration.Failures[j].FilePath
+	})
+	return migration, nil
+}
+
+// applyFieldMigration rewrites the frontmatter of every converted object.
+func applyFieldMigration(fieldName string, migration *FieldMigration) error {
+	for _, conversion := range migration.Conversions {
+		content, err := vaultcrypt.ReadFile(conversion.path)
+		if err != nil {
+			return newError(ErrorFileRead, err.Error(), "", nil, err)
+		}
+		updated, err := fieldmutation.UpdateFrontmatterFields(string(content), map[string]schema.FieldValue{fieldName: conversion.value})
+		if err != nil {
+			return newError(ErrorFileWrite, fmt.Sprintf("%s: %v", conversion.FilePath, err), "", nil, err)
+		}
+		history.Capture(conversion.path)
+		sealed, err := vaultcrypt.Seal(conversion.path, []byte(updated))
+		if err != nil {
+			return newError(ErrorFileWrite, err.Error(), "", nil, err)
+		}
+		if err := atomicfile.WriteFile(conversion.path, sealed, 0o644); err != nil {
+			return newError(ErrorFileWrite, err.Error(), "", nil, err)
+		}
+	}
+	return nil
+}
+
+// convertFieldValue converts value to the type of def: strings are parsed as
+// dates, numbers, or booleans, scalars are wrapped when the field becomes a
+// list, and single-item lists are unwrapped when it stops being one. The
+// result must pass schema validation for def.
+func convertFieldValue(value schema.FieldValue, def *schema.FieldDefinition) (schema.FieldValue, error) {
+	fieldType := string(def.Type)
+	baseType := strings.TrimSuffix(fieldType, "[]")
+	value = unwrapYAMLRef(value)
+
+	var converted schema.FieldValue
+	items, isList := value.AsArray()
+	switch {
+	case strings.HasSuffix(fieldType, "[]"):
+		if !isList {
+			items = []schema.FieldValue{value}
+		}
+		out := make([]schema.FieldValue, 0, len(items))
+		for _, item := range items {
+			out = append(out, convertScalarValue(unwrapYAMLRef(item), baseType))
+		}
+		converted = schema.Array(out)
+	case isList && baseType != "object":
+		if len(items) != 1 {
+			return value, fmt.Errorf("a list of %d values cannot become a single %s", len(items), baseType)
+		}
+		converted = convertScalarValue(unwrapYAMLRef(items[0]), baseType)
+	default:
+		converted = convertScalarValue(value, baseType)
+	}
+
+	errs := schema.ValidateFields(
+		map[string]schema.FieldValue{"value": converted},
+		map[string]*schema.FieldDefinition{"value": def},
+		nil,
+	)
+	if len(errs) > 0 {
+		return value, fmt.Errorf("%s", errs[0].Message)
+	}
+	return converted, nil
+}
+
+// convertScalarValue converts a single value to baseType where a conversion
+// exists, and returns it unchanged otherwise so validation can report it.
+func convertScalarValue(value schema.FieldValue, baseType string) schema.FieldValue {
+	if value.IsNull() {
+		return value
+	}
+	switch baseType {
+	case "string", "enum", "url":
+		if _, ok := value.AsString(); ok {
+			return value
+		}
+		if n, ok := value.AsNumber(); ok {
+			return schema.String(strconv.FormatFloat(n, 'f', -1, 64))
+		}
+		if b, ok := value.AsBool(); ok {
+			return schema.String(strconv.FormatBool(b))
+		}
+	case "number":
+		if s, ok := value.AsString(); ok && !value.IsRef() {
+			if n, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+				return schema.Number(n)
+			}
+		}
+	case "bool":
+		if s, ok := value.AsString(); ok && !value.IsRef() {
+			switch strings.ToLower(strings.TrimSpace(s)) {
+			case "true", "yes", "y", "on", "1":
+				return schema.Bool(true)
+			case "false", "no", "n", "off", "0":
+				return schema.Bool(false)
+			}
+		}
+		if n, ok := value.AsNumber(); ok && (n == 0 || n == 1) {
+			return schema.Bool(n == 1)
+		}
+	case "date":
+		if s, ok := value.AsString(); ok && !value.IsRef() {
+			if day, ok := dates.CanonicalizeDate(s); ok {
+				return schema.Date(day)
+			}
+			if t, err := dates.ParseDatetime(s); err == nil {
+				return schema.Date(t.Format(dates.DateLayout))
+			}
+		}
+	case "datetime":
+		if s, ok := value.AsString(); ok && !value.IsRef() {
+			if dates.IsValidDatetime(strings.TrimSpace(s)) {
+				return schema.Datetime(strings.TrimSpace(s))
+			}
+			if day, ok := dates.CanonicalizeDate(s); ok {
+				return schema.Datetime(day + "T00:00")
+			}
+		}
+	case "ref":
+		if s, ok := value.AsString(); ok && !value.IsRef() && strings.TrimSpace(s) != "" {
+			return schema.Ref(strings.TrimSpace(s))
+		}
+	}
+	return value
+}
+
+// unwrapYAMLRef turns an unquoted [[target]] in YAML, which decodes as a
+// nested list, back into a reference.
+func unwrapYAMLRef(value schema.FieldValue) schema.FieldValue {
+	outer, ok := value.AsArray()
+	if !ok || len(outer) != 1 {
+		return value
+	}
+	inner, ok := outer[0].AsArray()
+	if !ok || len(inner) != 1 {
+		return value
+	}
+	if s, ok := inner[0].AsString(); ok && s != "" {
+		return schema.Ref(s)
+	}
+	return value
+}
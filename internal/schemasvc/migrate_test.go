@@ -0,0 +1,168 @@
+package schemasvc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/fieldmutation"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+const migrateSchema = `version: 1
+types:
+  project:
+    default_path: projects/
+    fields:
+      due:
+        type: string
+      tags:
+        type: string
+`
+
+func TestUpdateField_TypeChangePreviewsConversions(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).
+		WithSchema(migrateSchema).
+		WithFile("projects/a.md", "---\ntype: project\ndue: Jan 5, 2026\n---\nBody\n").
+		WithFile("projects/b.md", "---\ntype: project\ndue: \"2026-02-01\"\n---\n").
+		WithFile("projects/c.md", "---\ntype: project\ndue: someday\n---\n").
+		Build()
+
+	result, err := UpdateField(UpdateFieldRequest{
+		VaultPath: vault.Path,
+		TypeName:  "project",
+		FieldName: "due",
+		FieldType: "date",
+	})
+	if err != nil {
+		t.Fatalf("UpdateField preview returned error: %v", err)
+	}
+	if !result.Preview || result.Migration == nil {
+		t.Fatalf("expected a migration preview, got %+v", result)
+	}
+	if len(result.Migration.Conversions) != 1 || result.Migration.Conversions[0].FilePath != "projects/a.md" || result.Migration.Conversions[0].To != "2026-01-05" {
+		t.Fatalf("conversions = %+v, want projects/a.md -> 2026-01-05", result.Migration.Conversions)
+	}
+	if len(result.Migration.Failures) != 1 || result.Migration.Failures[0].FilePath != "projects/c.md" {
+		t.Fatalf("failures = %+v, want projects/c.md", result.Migration.Failures)
+	}
+
+	loaded, err := schema.Load(vault.Path)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	if got := loaded.Types["project"].Fields["due"].Type; got != schema.FieldTypeString {
+		t.Fatalf("preview changed schema type to %q", got)
+	}
+
+	_, err = UpdateField(UpdateFieldRequest{
+		VaultPath: vault.Path,
+		TypeName:  "project",
+		FieldName: "due",
+		FieldType: "date",
+		Confirm:   true,
+	})
+	var svcErr *Error
+	if !errors.As(err, &svcErr) || svcErr.Code != ErrorDataIntegrity {
+		t.Fatalf("expected data integrity error for unconvertible values, got %v", err)
+	}
+
+	result, err = UpdateField(UpdateFieldRequest{
+		VaultPath: vault.Path,
+		TypeName:  "project",
+		FieldName: "due",
+		FieldType: "date",
+		Confirm:   true,
+		Force:     true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateField --force returned error: %v", err)
+	}
+	if result.Preview {
+		t.Fatal("confirmed update should not be a preview")
+	}
+	if content := vault.ReadFile("projects/a.md"); !strings.Contains(content, "2026-01-05") || !strings.Contains(content, "Body") {
+		t.Fatalf("projects/a.md was not converted:\n%s", content)
+	}
+	if content := vault.ReadFile("projects/c.md"); !strings.Contains(content, "due: someday") {
+		t.Fatalf("projects/c.md should be left unchanged:\n%s", content)
+	}
+	loaded, err = schema.Load(vault.Path)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	if got := loaded.Types["project"].Fields["due"].Type; got != schema.FieldTypeDate {
+		t.Fatalf("schema type = %q, want date", got)
+	}
+}
+
+func TestUpdateField_TypeChangeWithoutAffectedValuesApplies(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).
+		WithSchema(migrateSchema).
+		WithFile("projects/a.md", "---\ntype: project\ndue: \"2026-02-01\"\n---\n").
+		Build()
+
+	result, err := UpdateField(UpdateFieldRequest{
+		VaultPath: vault.Path,
+		TypeName:  "project",
+		FieldName: "due",
+		FieldType: "date",
+	})
+	if err != nil {
+		t.Fatalf("UpdateField returned error: %v", err)
+	}
+	if result.Preview || result.Migration != nil {
+		t.Fatalf("expected the change to apply without a migration, got %+v", result)
+	}
+}
+
+func TestConvertFieldValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   schema.FieldValue
+		def     *schema.FieldDefinition
+		want    string
+		wantErr bool
+	}{
+		{name: "loose date", value: schema.String("2026/3/4"), def: &schema.FieldDefinition{Type: schema.FieldTypeDate}, want: "2026-03-04"},
+		{name: "datetime to date", value: schema.String("2026-03-04T10:30"), def: &schema.FieldDefinition{Type: schema.FieldTypeDate}, want: "2026-03-04"},
+		{name: "date to datetime", value: schema.Date("2026-03-04"), def: &schema.FieldDefinition{Type: schema.FieldTypeDatetime}, want: "2026-03-04T00:00"},
+		{name: "unparseable date", value: schema.String("soon"), def: &schema.FieldDefinition{Type: schema.FieldTypeDate}, wantErr: true},
+		{name: "number", value: schema.String(" 42.5 "), def: &schema.FieldDefinition{Type: schema.FieldTypeNumber}, want: "42.5"},
+		{name: "bool word", value: schema.String("Yes"), def: &schema.FieldDefinition{Type: schema.FieldTypeBool}, want: "true"},
+		{name: "number to string", value: schema.Number(3), def: &schema.FieldDefinition{Type: schema.FieldTypeString}, want: `"3"`},
+		{name: "wrap scalar", value: schema.String("alpha"), def: &schema.FieldDefinition{Type: schema.FieldTypeStringArray}, want: "[alpha]"},
+		{name: "convert items", value: schema.Array([]schema.FieldValue{schema.String("1"), schema.String("2")}), def: &schema.FieldDefinition{Type: schema.FieldTypeNumberArray}, want: "[1, 2]"},
+		{name: "unwrap single item", value: schema.Array([]schema.FieldValue{schema.String("alpha")}), def: &schema.FieldDefinition{Type: schema.FieldTypeString}, want: "alpha"},
+		{name: "cannot unwrap list", value: schema.Array([]schema.FieldValue{schema.String("a"), schema.String("b")}), def: &schema.FieldDefinition{Type: schema.FieldTypeString}, wantErr: true},
+		{name: "enum value", value: schema.String("active"), def: &schema.FieldDefinition{Type: schema.FieldTypeEnum, Values: []string{"active", "done"}}, want: "active"},
+		{name: "enum outside values", value: schema.String("stalled"), def: &schema.FieldDefinition{Type: schema.FieldTypeEnum, Values: []string{"active", "done"}}, wantErr: true},
+		{name: "string to ref", value: schema.String("people/alice"), def: &schema.FieldDefinition{Type: schema.FieldTypeRef, Target: "person"}, want: "[[people/alice]]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := convertFieldValue(tt.value, tt.def)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", fieldmutation.SerializeFieldValueLiteral(got))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if literal := fieldmutation.SerializeFieldValueLiteral(got); literal != tt.want {
+				t.Fatalf("converted = %s, want %s", literal, tt.want)
+			}
+		})
+	}
+}
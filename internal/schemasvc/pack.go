@@ -0,0 +1,385 @@
+package schemasvc
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// Conflict handling for schema imports.
+const (
+	ImportConflictFail      = "fail"      // Refuse to import while any definition conflicts
+	ImportConflictSkip      = "skip"      // Keep the vault's definition
+	ImportConflictOverwrite = "overwrite" // Replace the vault's definition with the pack's
+)
+
+// Import entry statuses.
+const (
+	ImportStatusAdd       = "add"
+	ImportStatusUnchanged = "unchanged"
+	ImportStatusConflict  = "conflict"
+	ImportStatusSkip      = "skip"
+	ImportStatusOverwrite = "overwrite"
+)
+
+const packHeader = "# Raven schema pack. Add it to a vault with: rvn schema import <file>\n"
+
+// typeTemplateKeys are left out of exported types: they name templates that
+// live as files in the exporting vault.
+var typeTemplateKeys = []string{"template", "templates", "default_template"}
+
+type ExportRequest struct {
+	VaultPath string
+	Types     []string // Types to export; empty exports all unless Traits is set
+	Traits    []string // Traits to export; empty exports all unless Types is set
+}
+
+type ExportResult struct {
+	Types   []string `json:"types"`
+	Traits  []string `json:"traits"`
+	Notes   []string `json:"notes"`
+	Content string   `json:"content"`
+}
+
+// Export renders the selected type and trait definitions as a schema pack:
+// a schema.yaml with only version, types, and traits. Template bindings are
+// dropped, and refs to types outside the pack are noted.
+func Export(req ExportRequest) (*ExportResult, error) {
+	schemaDoc, err := readSchemaDoc(req.VaultPath)
+	if err != nil {
+		return nil, err
+	}
+	typesNode, _ := schemaDoc["types"].(map[string]interface{})
+	traitsNode, _ := schemaDoc["traits"].(map[string]interface{})
+
+	typeNames, traitNames := splitNameList(req.Types), splitNameList(req.Traits)
+	if len(typeNames) == 0 && len(traitNames) == 0 {
+		typeNames = sortedKeys(typesNode)
+		traitNames = sortedKeys(traitsNode)
+	}
+
+	result := &ExportResult{Types: []string{}, Traits: []string{}, Notes: []string{}}
+	packTypes := make(map[string]interface{})
+	for _, name := range typeNames {
+		def, ok := typesNode[name].(map[string]interface{})
+		if !ok {
+			return nil, newError(ErrorTypeNotFound, fmt.Sprintf("type '%s' not found", name), "Run 'rvn schema types' to see defined types", nil, nil)
+		}
+		def = cloneYAMLMap(def)
+		dropped := false
+		for _, key := range typeTemplateKeys {
+			if _, ok := def[key]; ok {
+				delete(def, key)
+				dropped = true
+			}
+		}
+		if dropped {
+			result.Notes = append(result.Notes, fmt.Sprintf("type %s: template bindings left out (templates are files in this vault)", name))
+		}
+		packTypes[name] = def
+		result.Types = append(result.Types, name)
+	}
+	packTraits := make(map[string]interface{})
+	for _, name := range traitNames {
+		def, ok := traitsNode[name]
+		if !ok {
+			return nil, newError(ErrorTraitNotFound, fmt.Sprintf("trait '%s' not found", name), "Run 'rvn schema traits' to see defined traits", nil, nil)
+		}
+		packTraits[name] = def
+		result.Traits = append(result.Traits, name)
+	}
+	sort.Strings(result.Types)
+	sort.Strings(result.Traits)
+
+	for _, name := range result.Types {
+		if parent, _ := packTypes[name].(map[string]interface{})["extends"].(string); parent != "" {
+			if _, ok := packTypes[parent]; !ok {
+				result.Notes = append(result.Notes, fmt.Sprintf("type %s extends type %s, which is not in the pack", name, parent))
+			}
+		}
+		fields, _ := packTypes[name].(map[string]interface{})["fields"].(map[string]interface{})
+		for _, fieldName := range sortedKeys(fields) {
+			fieldDef, _ := fields[fieldName].(map[string]interface{})
+			target, _ := fieldDef["target"].(string)
+			if target == "" || schema.IsBuiltinType(target) {
+				continue
+			}
+			if _, ok := packTypes[target]; !ok {
+				result.Notes = append(result.Notes, fmt.Sprintf("type %s: field %s refers to type %s, which is not in the pack", name, fieldName, target))
+			}
+		}
+	}
+
+	pack := map[string]interface{}{"version": schema.CurrentSchemaVersion}
+	if len(packTypes) > 0 {
+		pack["types"] = packTypes
+	}
+	if len(packTraits) > 0 {
+		pack["traits"] = packTraits
+	}
+	output, err := yaml.Marshal(pack)
+	if err != nil {
+		return nil, newError(ErrorInternal, err.Error(), "", nil, err)
+	}
+	result.Content = packHeader + string(output)
+	return result, nil
+}
+
+type ImportRequest struct {
+	VaultPath  string
+	File       string
+	OnConflict string
+	Confirm    bool
+}
+
+// ImportEntry is one type or trait in the pack and what importing does with
+// it. Differences lists the keys (fields.<name> for fields) that differ from
+// the vault's definition.
+type ImportEntry struct {
+	Key         string   `json:"key"`
+	Kind        string   `json:"kind"`
+	Name        string   `json:"name"`
+	Status      string   `json:"status"`
+	Differences []string `json:"differences,omitempty"`
+}
+
+type ImportResult struct {
+	Preview bool          `json:"preview"`
+	File    string        `json:"file"`
+	Entries []ImportEntry `json:"entries"`
+	Issues  []string      `json:"issues"`
+	Applied []string      `json:"applied"`
+}
+
+// Import merges the types and traits of a schema pack into schema.yaml.
+// Definitions identical to the vault's are left alone; differing ones are
+// conflicts, resolved by OnConflict. Without Confirm it only reports what
+// would happen. The merged schema must validate before it is written.
+func Import(req ImportRequest) (*ImportResult, error) {
+	onConflict := strings.TrimSpace(req.OnConflict)
+	if onConflict == "" {
+		onConflict = ImportConflictFail
+	}
+	switch onConflict {
+	case ImportConflictFail, ImportConflictSkip, ImportConflictOverwrite:
+	default:
+		return nil, newError(ErrorInvalidInput, fmt.Sprintf("unknown --on-conflict value %q", req.OnConflict), "Use one of: fail, skip, overwrite", nil, nil)
+	}
+
+	file := strings.TrimSpace(req.File)
+	if file == "" {
+		return nil, newError(ErrorInvalidInput, "pack file is required", "Usage: rvn schema import <file>", nil, nil)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, newError(ErrorFileRead, fmt.Sprintf("failed to read %s: %v", file, err), "", nil, err)
+	}
+	var pack map[string]interface{}
+	if err := yaml.Unmarshal(data, &pack); err != nil {
+		return nil, newError(ErrorSchemaInvalid, fmt.Sprintf("invalid pack %s: %v", file, err), "", nil, err)
+	}
+	if version, ok := pack["version"].(int); ok && version > schema.CurrentSchemaVersion {
+		return nil, newError(ErrorSchemaInvalid, fmt.Sprintf("pack is schema version %d, but this Raven build supports version %d", version, schema.CurrentSchemaVersion), "Upgrade rvn and try again", nil, nil)
+	}
+	packTypes, _ := pack["types"].(map[string]interface{})
+	packTraits, _ := pack["traits"].(map[string]interface{})
+	if len(packTypes) == 0 && len(packTraits) == 0 {
+		return nil, newError(ErrorInvalidInput, fmt.Sprintf("%s defines no types or traits", file), "Create a pack with 'rvn schema export'", nil, nil)
+	}
+	for name := range packTypes {
+		if schema.IsBuiltinType(name) {
+			return nil, newError(ErrorInvalidInput, fmt.Sprintf("pack defines built-in type '%s'", name), "Remove it from the pack", nil, nil)
+		}
+	}
+
+	schemaDoc, err := readSchemaDoc(req.VaultPath)
+	if err != nil {
+		return nil, err
+	}
+	current, err := schema.Load(req.VaultPath)
+	if err != nil {
+		return nil, newError(ErrorSchemaInvalid, err.Error(), "Fix schema.yaml and try again", nil, err)
+	}
+	typesNode := ensureMapNode(schemaDoc, "types")
+	traitsNode := ensureMapNode(schemaDoc, "traits")
+
+	result := &ImportResult{
+		Preview: !req.Confirm,
+		File:    file,
+		Entries: []ImportEntry{},
+		Issues:  []string{},
+		Applied: []string{},
+	}
+	merge := func(kind string, incoming, existing map[string]interface{}) {
+		for _, name := range sortedKeys(incoming) {
+			entry := ImportEntry{Key: kind + ":" + name, Kind: kind, Name: name, Status: ImportStatusAdd}
+			if have, ok := existing[name]; ok {
+				entry.Differences = definitionDifferences(have, incoming[name])
+				switch {
+				case len(entry.Differences) == 0:
+					entry.Status = ImportStatusUnchanged
+				case onConflict == ImportConflictSkip:
+					entry.Status = ImportStatusSkip
+				case onConflict == ImportConflictOverwrite:
+					entry.Status = ImportStatusOverwrite
+				default:
+					entry.Status = ImportStatusConflict
+				}
+			}
+			if entry.Status == ImportStatusAdd || entry.Status == ImportStatusOverwrite {
+				existing[name] = incoming[name]
+				result.Applied = append(result.Applied, entry.Key)
+			}
+			result.Entries = append(result.Entries, entry)
+		}
+	}
+	merge("type", packTypes, typesNode)
+	merge("trait", packTraits, traitsNode)
+	if len(traitsNode) == 0 {
+		delete(schemaDoc, "traits")
+	}
+
+	issues, err := newSchemaIssues(current, schemaDoc)
+	if err != nil {
+		return nil, err
+	}
+	result.Issues = issues
+	if !req.Confirm {
+		return result, nil
+	}
+
+	var conflicts []string
+	for _, entry := range result.Entries {
+		if entry.Status == ImportStatusConflict {
+			conflicts = append(conflicts, entry.Key)
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, newError(ErrorDataIntegrity,
+			fmt.Sprintf("%d definition(s) in the pack differ from this vault's: %s", len(conflicts), strings.Join(conflicts, ", ")),
+			"Pass --on-conflict skip to keep this vault's definitions or --on-conflict overwrite to take the pack's",
+			map[string]interface{}{"conflicts": conflicts}, nil)
+	}
+	if len(result.Issues) > 0 {
+		return nil, newError(ErrorSchemaInvalid,
+			fmt.Sprintf("importing the pack would make schema.yaml invalid: %s", result.Issues[0]),
+			"Fix the pack or add the missing definitions first",
+			map[string]interface{}{"issues": result.Issues}, nil)
+	}
+	if len(result.Applied) == 0 {
+		return result, nil
+	}
+	if err := writeSchemaDoc(req.VaultPath, schemaDoc); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// newSchemaIssues validates the merged schema document and returns only the
+// issues the current schema does not already have.
+func newSchemaIssues(current *schema.Schema, merged map[string]interface{}) ([]string, error) {
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, newError(ErrorInternal, err.Error(), "", nil, err)
+	}
+	var next schema.Schema
+	if err := yaml.Unmarshal(data, &next); err != nil {
+		return []string{err.Error()}, nil
+	}
+	if next.Types == nil {
+		next.Types = make(map[string]*schema.TypeDefinition)
+	}
+	for name, def := range current.Types {
+		if schema.IsBuiltinType(name) {
+			next.Types[name] = def
+		}
+	}
+
+	existing := make(map[string]bool)
+	for _, issue := range schema.ValidateSchema(current) {
+		existing[issue] = true
+	}
+	issues := []string{}
+	for _, issue := range schema.ValidateSchema(&next) {
+		if !existing[issue] {
+			issues = append(issues, issue)
+		}
+	}
+	sort.Strings(issues)
+	return issues, nil
+}
+
+// definitionDifferences lists the top-level keys that differ between two
+// definitions, naming individual fields as fields.<name>.
+func definitionDifferences(have, incoming interface{}) []string {
+	haveMap, ok1 := have.(map[string]interface{})
+	incomingMap, ok2 := incoming.(map[string]interface{})
+	if !ok1 || !ok2 {
+		if reflect.DeepEqual(have, incoming) {
+			return nil
+		}
+		return []string{"definition"}
+	}
+
+	var diffs []string
+	for _, key := range sortedKeys(mergeKeys(haveMap, incomingMap)) {
+		if reflect.DeepEqual(haveMap[key], incomingMap[key]) {
+			continue
+		}
+		haveFields, ok1 := haveMap[key].(map[string]interface{})
+		incomingFields, ok2 := incomingMap[key].(map[string]interface{})
+		if key != "fields" || !ok1 || !ok2 {
+			diffs = append(diffs, key)
+			continue
+		}
+		for _, field := range sortedKeys(mergeKeys(haveFields, incomingFields)) {
+			if !reflect.DeepEqual(haveFields[field], incomingFields[field]) {
+				diffs = append(diffs, "fields."+field)
+			}
+		}
+	}
+	return diffs
+}
+
+// splitNameList flattens repeated and comma-separated names.
+func splitNameList(values []string) []string {
+	var names []string
+	for _, value := range values {
+		names = append(names, splitCommaValues(value)...)
+	}
+	return names
+}
+
+func mergeKeys(a, b map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(a)+len(b))
+	for key := range a {
+		out[key] = nil
+	}
+	for key := range b {
+		out[key] = nil
+	}
+	return out
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func cloneYAMLMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		out[key] = value
+	}
+	return out
+}
@@ -0,0 +1,198 @@
+package schemasvc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+const packSourceSchema = `version: 1
+types:
+  person:
+    default_path: people/
+    name_field: name
+    fields:
+      name:
+        type: string
+        required: true
+      company:
+        type: string
+  meeting:
+    default_path: meetings/
+    templates: [standup]
+    default_template: standup
+    fields:
+      attendees:
+        type: ref[]
+        target: person
+traits:
+  priority:
+    type: enum
+    values: [low, high]
+  due:
+    type: date
+templates:
+  standup:
+    file: templates/standup.md
+`
+
+func writePack(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "pack.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write pack: %v", err)
+	}
+	return path
+}
+
+func TestExport_SelectedDefinitions(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).WithSchema(packSourceSchema).Build()
+	result, err := Export(ExportRequest{
+		VaultPath: vault.Path,
+		Types:     []string{"meeting"},
+		Traits:    []string{"priority,due"},
+	})
+	if err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Types, []string{"meeting"}) || !reflect.DeepEqual(result.Traits, []string{"due", "priority"}) {
+		t.Fatalf("exported types=%v traits=%v", result.Types, result.Traits)
+	}
+	if strings.Contains(result.Content, "standup") {
+		t.Fatalf("template bindings should be left out of the pack:\n%s", result.Content)
+	}
+	if len(result.Notes) != 2 || !strings.Contains(result.Notes[1], "refers to type person") {
+		t.Fatalf("notes = %v, want template and missing-target notes", result.Notes)
+	}
+
+	_, err = Export(ExportRequest{VaultPath: vault.Path, Types: []string{"nope"}})
+	var svcErr *Error
+	if !errors.As(err, &svcErr) || svcErr.Code != ErrorTypeNotFound {
+		t.Fatalf("expected type not found error, got %v", err)
+	}
+}
+
+func TestImport_ExportedPackRoundTrips(t *testing.T) {
+	t.Parallel()
+
+	source := testutil.NewTestVault(t).WithSchema(packSourceSchema).Build()
+	exported, err := Export(ExportRequest{VaultPath: source.Path, Types: []string{"person", "meeting"}})
+	if err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	packPath := writePack(t, exported.Content)
+
+	target := testutil.NewTestVault(t).WithSchema("version: 1\ntypes: {}\n").Build()
+	preview, err := Import(ImportRequest{VaultPath: target.Path, File: packPath})
+	if err != nil {
+		t.Fatalf("Import preview returned error: %v", err)
+	}
+	if !preview.Preview || len(preview.Applied) != 2 {
+		t.Fatalf("preview = %+v, want two additions", preview)
+	}
+	if loaded, _ := schema.Load(target.Path); loaded.Types["person"] != nil {
+		t.Fatal("preview should not write schema.yaml")
+	}
+
+	if _, err := Import(ImportRequest{VaultPath: target.Path, File: packPath, Confirm: true}); err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+	loaded, err := schema.Load(target.Path)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	if loaded.Types["meeting"] == nil || loaded.Types["meeting"].Fields["attendees"].Target != "person" {
+		t.Fatalf("meeting not imported: %+v", loaded.Types["meeting"])
+	}
+
+	again, err := Import(ImportRequest{VaultPath: target.Path, File: packPath, Confirm: true})
+	if err != nil {
+		t.Fatalf("re-import returned error: %v", err)
+	}
+	if len(again.Applied) != 0 || again.Entries[0].Status != ImportStatusUnchanged {
+		t.Fatalf("re-import = %+v, want everything unchanged", again)
+	}
+}
+
+func TestImport_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	pack := writePack(t, `version: 1
+types:
+  person:
+    default_path: contacts/
+    fields:
+      name:
+        type: string
+traits:
+  priority:
+    type: enum
+    values: [low, high]
+`)
+	newVault := func() *testutil.TestVault {
+		return testutil.NewTestVault(t).WithSchema(packSourceSchema).Build()
+	}
+
+	vault := newVault()
+	preview, err := Import(ImportRequest{VaultPath: vault.Path, File: pack})
+	if err != nil {
+		t.Fatalf("Import preview returned error: %v", err)
+	}
+	person := preview.Entries[0]
+	if person.Status != ImportStatusConflict || !reflect.DeepEqual(person.Differences, []string{"default_path", "fields.company", "fields.name", "name_field"}) {
+		t.Fatalf("person entry = %+v", person)
+	}
+	if preview.Entries[1].Status != ImportStatusUnchanged {
+		t.Fatalf("priority entry = %+v, want unchanged", preview.Entries[1])
+	}
+
+	_, err = Import(ImportRequest{VaultPath: vault.Path, File: pack, Confirm: true})
+	var svcErr *Error
+	if !errors.As(err, &svcErr) || svcErr.Code != ErrorDataIntegrity {
+		t.Fatalf("expected conflicts to block the import, got %v", err)
+	}
+
+	if _, err := Import(ImportRequest{VaultPath: vault.Path, File: pack, OnConflict: ImportConflictSkip, Confirm: true}); err != nil {
+		t.Fatalf("Import --on-conflict skip returned error: %v", err)
+	}
+	if loaded, _ := schema.Load(vault.Path); loaded.Types["person"].DefaultPath != "people/" {
+		t.Fatalf("skip should keep the vault's person, got default_path %q", loaded.Types["person"].DefaultPath)
+	}
+
+	vault = newVault()
+	if _, err := Import(ImportRequest{VaultPath: vault.Path, File: pack, OnConflict: ImportConflictOverwrite, Confirm: true}); err != nil {
+		t.Fatalf("Import --on-conflict overwrite returned error: %v", err)
+	}
+	if loaded, _ := schema.Load(vault.Path); loaded.Types["person"].DefaultPath != "contacts/" {
+		t.Fatalf("overwrite should take the pack's person, got default_path %q", loaded.Types["person"].DefaultPath)
+	}
+}
+
+func TestImport_RefusesInvalidMerge(t *testing.T) {
+	t.Parallel()
+
+	pack := writePack(t, "version: 1\ntypes:\n  meeting:\n    fields:\n      host:\n        type: ref\n        target: person\n")
+	vault := testutil.NewTestVault(t).WithSchema("version: 1\ntypes: {}\n").Build()
+
+	preview, err := Import(ImportRequest{VaultPath: vault.Path, File: pack})
+	if err != nil {
+		t.Fatalf("Import preview returned error: %v", err)
+	}
+	if len(preview.Issues) != 1 || !strings.Contains(preview.Issues[0], "unknown type 'person'") {
+		t.Fatalf("issues = %v", preview.Issues)
+	}
+
+	_, err = Import(ImportRequest{VaultPath: vault.Path, File: pack, Confirm: true})
+	var svcErr *Error
+	if !errors.As(err, &svcErr) || svcErr.Code != ErrorSchemaInvalid {
+		t.Fatalf("expected schema invalid error, got %v", err)
+	}
+}
@@ -13,12 +13,14 @@ import (
 
 	"github.com/aidanlsb/raven/internal/atomicfile"
 	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/history"
 	"github.com/aidanlsb/raven/internal/objectsvc"
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/paths"
 	"github.com/aidanlsb/raven/internal/query"
 	"github.com/aidanlsb/raven/internal/schema"
 	"github.com/aidanlsb/raven/internal/vault"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 type TypeRenameChange struct {
@@ -299,11 +301,19 @@ func RenameType(req RenameTypeRequest) (*RenameTypeResult, error) {
 	}
 
 	for typeName, typeDef := range sch.Types {
-		if typeDef == nil || typeDef.Fields == nil {
+		if typeDef == nil {
 			continue
 		}
+		if typeDef.Extends == oldName {
+			changes = append(changes, TypeRenameChange{
+				FilePath:    "schema.yaml",
+				ChangeType:  "schema_extends",
+				Description: fmt.Sprintf("update type '%s' extends from '%s' to '%s'", typeName, oldName, newName),
+			})
+		}
 		for fieldName, fieldDef := range typeDef.Fields {
-			if fieldDef == nil {
+			// Inherited fields are renamed where they are declared.
+			if fieldDef == nil || typeDef.InheritedFields[fieldName] != "" {
 				continue
 			}
 			if fieldDef.Target == oldName {
@@ -455,6 +465,10 @@ func RenameType(req RenameTypeRequest) (*RenameTypeResult, error) {
 		if !ok {
 			continue
 		}
+		if extends, ok := typeMap["extends"].(string); ok && extends == oldName {
+			typeMap["extends"] = newName
+			appliedChanges++
+		}
 		fields, ok := typeMap["fields"].(map[string]interface{})
 		if !ok {
 			continue
@@ -483,7 +497,7 @@ func RenameType(req RenameTypeRequest) (*RenameTypeResult, error) {
 		if result.Error != nil {
 			return result.Error
 		}
-		content, readErr := os.ReadFile(result.Path)
+		content, readErr := vaultcrypt.ReadFile(result.Path)
 		if readErr != nil {
 			return readErr
 		}
@@ -672,7 +686,7 @@ func buildFieldRenamePlan(vaultPath, typeName, oldField, newField string) (*fiel
 			if err != nil || parsed == nil {
 				continue
 			}
-			if parsed.Type != query.QueryTypeObject || parsed.TypeName != typeName {
+			if !parsed.MatchesTypeName(typeName) {
 				continue
 			}
 			newQuery := fieldRefPattern.ReplaceAllString(q.Query, "."+newField)
@@ -930,6 +944,7 @@ func applyTypeDirectoryRename(vaultPath string, vaultCfg *config.VaultConfig, mo
 		if err := os.MkdirAll(filepath.Dir(destAbs), 0o755); err != nil {
 			return 0, 0, err
 		}
+		history.Capture(sourceAbs)
 		if err := os.Rename(sourceAbs, destAbs); err != nil {
 			return 0, 0, err
 		}
@@ -969,7 +984,7 @@ func updateReferencesForTypeDirectoryMoves(vaultPath string, vaultCfg *config.Va
 			return nil
 		}
 
-		content, readErr := os.ReadFile(result.Path)
+		content, readErr := vaultcrypt.ReadFile(result.Path)
 		if readErr != nil {
 			return readErr
 		}
@@ -0,0 +1,49 @@
+package schemasvc
+
+import (
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+func TestRenameType_UpdatesExtends(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).WithSchema(`version: 1
+types:
+  event:
+    fields:
+      title: {type: string}
+      host: {type: ref, target: event}
+  meeting:
+    extends: event
+`).Build()
+
+	preview, err := RenameType(RenameTypeRequest{VaultPath: vault.Path, OldName: "event", NewName: "occasion"})
+	if err != nil {
+		t.Fatalf("preview rename: %v", err)
+	}
+	counts := map[string]int{}
+	for _, change := range preview.Changes {
+		counts[change.ChangeType]++
+	}
+	if counts["schema_extends"] != 1 || counts["schema_ref_target"] != 1 {
+		t.Fatalf("preview changes = %+v", preview.Changes)
+	}
+
+	if _, err := RenameType(RenameTypeRequest{VaultPath: vault.Path, OldName: "event", NewName: "occasion", Confirm: true}); err != nil {
+		t.Fatalf("apply rename: %v", err)
+	}
+	loaded, err := schema.Load(vault.Path)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	meeting := loaded.Types["meeting"]
+	if meeting.Extends != "occasion" || meeting.InheritedFields["title"] != "occasion" {
+		t.Fatalf("meeting = %+v", meeting)
+	}
+	if target := loaded.Types["occasion"].Fields["host"].Target; target != "occasion" {
+		t.Fatalf("host target = %q", target)
+	}
+}
@@ -0,0 +1,327 @@
+package schemasvc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+// Editor snippet formats supported by ExportSnippets.
+const (
+	SnippetFormatVSCode    = "vscode"
+	SnippetFormatObsidian  = "obsidian"
+	SnippetFormatYasnippet = "yasnippet"
+)
+
+// SnippetFormats lists the supported formats in display order.
+var SnippetFormats = []string{SnippetFormatVSCode, SnippetFormatObsidian, SnippetFormatYasnippet}
+
+type ExportSnippetsRequest struct {
+	VaultPath string
+	Format    string
+	Types     []string // Limit the export to these types; empty means all
+	OutputDir string   // Write files here (relative paths are vault-relative); empty returns content only
+}
+
+// SnippetFile is one generated snippet or template file. Path is relative to
+// the output directory.
+type SnippetFile struct {
+	Path    string   `json:"path"`
+	Types   []string `json:"types"`
+	Content string   `json:"content"`
+}
+
+type ExportSnippetsResult struct {
+	Format    string        `json:"format"`
+	Files     []SnippetFile `json:"files"`
+	OutputDir string        `json:"output_dir,omitempty"`
+}
+
+// ExportSnippets generates editor snippets or templates that scaffold the
+// frontmatter for each schema type, so files created outside rvn start with
+// the right type and fields.
+func ExportSnippets(req ExportSnippetsRequest) (*ExportSnippetsResult, error) {
+	format := strings.ToLower(strings.TrimSpace(req.Format))
+	if format == "" {
+		format = SnippetFormatVSCode
+	}
+	if !slices.Contains(SnippetFormats, format) {
+		return nil, newError(ErrorInvalidInput, fmt.Sprintf("unknown snippet format %q", req.Format), "Use one of: "+strings.Join(SnippetFormats, ", "), nil, nil)
+	}
+
+	sch, err := loadSchema(req.VaultPath, "Run 'rvn init' first")
+	if err != nil {
+		return nil, err
+	}
+	typeNames, err := snippetTypeNames(sch, req.Types)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []SnippetFile
+	switch format {
+	case SnippetFormatVSCode:
+		file, err := vscodeSnippetFile(sch, typeNames)
+		if err != nil {
+			return nil, err
+		}
+		files = []SnippetFile{file}
+	case SnippetFormatObsidian:
+		for _, typeName := range typeNames {
+			files = append(files, SnippetFile{
+				Path:    typeName + ".md",
+				Types:   []string{typeName},
+				Content: renderSnippetFrontmatter(typeName, sch.Types[typeName], obsidianPlaceholder),
+			})
+		}
+	case SnippetFormatYasnippet:
+		for _, typeName := range typeNames {
+			header := fmt.Sprintf("# -*- mode: snippet -*-\n# name: %s\n# key: rvn-%s\n# --\n", snippetDescription(typeName, sch.Types[typeName]), typeName)
+			files = append(files, SnippetFile{
+				Path:    filepath.ToSlash(filepath.Join("markdown-mode", "rvn-"+typeName)),
+				Types:   []string{typeName},
+				Content: header + renderSnippetFrontmatter(typeName, sch.Types[typeName], yasnippetPlaceholder) + "$0\n",
+			})
+		}
+	}
+
+	result := &ExportSnippetsResult{Format: format, Files: files}
+	if strings.TrimSpace(req.OutputDir) == "" {
+		return result, nil
+	}
+
+	outputDir := req.OutputDir
+	if !filepath.IsAbs(outputDir) {
+		outputDir = filepath.Join(req.VaultPath, outputDir)
+	}
+	for _, file := range files {
+		path := filepath.Join(outputDir, filepath.FromSlash(file.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, newError(ErrorFileWrite, fmt.Sprintf("failed to create %s", filepath.Dir(path)), "", nil, err)
+		}
+		if err := atomicfile.WriteFile(path, []byte(file.Content), 0o644); err != nil {
+			return nil, newError(ErrorFileWrite, fmt.Sprintf("failed to write %s", path), "", nil, err)
+		}
+	}
+	result.OutputDir = outputDir
+	return result, nil
+}
+
+// snippetTypeNames returns the requested types, or every user-defined type,
+// sorted by name.
+func snippetTypeNames(sch *schema.Schema, requested []string) ([]string, error) {
+	var names []string
+	if len(requested) == 0 {
+		for name, def := range sch.Types {
+			if def != nil && !schema.IsBuiltinType(name) {
+				names = append(names, name)
+			}
+		}
+	} else {
+		for _, name := range requested {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if def, ok := sch.Types[name]; !ok || def == nil || schema.IsBuiltinType(name) {
+				return nil, newError(ErrorTypeNotFound, fmt.Sprintf("type '%s' not found", name), "Run 'rvn schema types' to see available types", nil, nil)
+			}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	names = slices.Compact(names)
+	if len(names) == 0 {
+		return nil, newError(ErrorInvalidInput, "schema has no types to export", "Add one with 'rvn schema add type <name>'", nil, nil)
+	}
+	return names, nil
+}
+
+func vscodeSnippetFile(sch *schema.Schema, typeNames []string) (SnippetFile, error) {
+	type vscodeSnippet struct {
+		Prefix      string   `json:"prefix"`
+		Scope       string   `json:"scope"`
+		Description string   `json:"description"`
+		Body        []string `json:"body"`
+	}
+	snippets := make(map[string]vscodeSnippet, len(typeNames))
+	for _, typeName := range typeNames {
+		body := renderSnippetFrontmatter(typeName, sch.Types[typeName], vscodePlaceholder) + "$0"
+		snippets["Raven "+typeName] = vscodeSnippet{
+			Prefix:      "rvn-" + typeName,
+			Scope:       "markdown",
+			Description: snippetDescription(typeName, sch.Types[typeName]),
+			Body:        strings.Split(body, "\n"),
+		}
+	}
+	data, err := json.MarshalIndent(snippets, "", "  ")
+	if err != nil {
+		return SnippetFile{}, newError(ErrorInternal, "failed to encode VS Code snippets", "", nil, err)
+	}
+	return SnippetFile{Path: "raven.code-snippets", Types: typeNames, Content: string(data) + "\n"}, nil
+}
+
+func snippetDescription(typeName string, typeDef *schema.TypeDefinition) string {
+	if desc := strings.TrimSpace(typeDef.Description); desc != "" {
+		return fmt.Sprintf("Raven %s: %s", typeName, desc)
+	}
+	return "Raven " + typeName
+}
+
+// snippetPlaceholder renders the value slot for one field. tabstop numbers
+// the slot for editors with tab stops; isName marks the type's name_field.
+type snippetPlaceholder func(tabstop int, field *schema.FieldDefinition, isName bool) string
+
+// renderSnippetFrontmatter renders a frontmatter block for typeName with the
+// name field first, then required fields, then optional fields, each group
+// in name order. Derived fields are skipped since Raven computes them.
+func renderSnippetFrontmatter(typeName string, typeDef *schema.TypeDefinition, placeholder snippetPlaceholder) string {
+	var required, optional []string
+	for name, field := range typeDef.Fields {
+		if field == nil || field.Derived != "" || name == typeDef.NameField {
+			continue
+		}
+		if field.Required {
+			required = append(required, name)
+		} else {
+			optional = append(optional, name)
+		}
+	}
+	sort.Strings(required)
+	sort.Strings(optional)
+
+	var order []string
+	if field, ok := typeDef.Fields[typeDef.NameField]; ok && field != nil {
+		order = append(order, typeDef.NameField)
+	}
+	order = append(order, required...)
+	order = append(order, optional...)
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	b.WriteString("type: " + typeName + "\n")
+	for i, name := range order {
+		b.WriteString(name + ":")
+		if value := placeholder(i+1, typeDef.Fields[name], name == typeDef.NameField); value != "" {
+			b.WriteString(" " + value)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("---\n")
+	return b.String()
+}
+
+func vscodePlaceholder(tabstop int, field *schema.FieldDefinition, isName bool) string {
+	n := strconv.Itoa(tabstop)
+	switch {
+	case isName:
+		return "${" + n + ":$TM_FILENAME_BASE}"
+	case len(field.Values) > 0 && !isArrayField(field):
+		return "${" + n + "|" + strings.Join(orderedChoices(field), ",") + "|}"
+	case field.Type == schema.FieldTypeBool:
+		return "${" + n + "|" + strings.Join(boolChoices(field), ",") + "|}"
+	case field.Default != nil:
+		return "${" + n + ":" + snippetDefault(field) + "}"
+	case field.Type == schema.FieldTypeDate:
+		return "${" + n + ":$CURRENT_YEAR-$CURRENT_MONTH-$CURRENT_DATE}"
+	case isArrayField(field):
+		return "[${" + n + "}]"
+	default:
+		return "${" + n + "}"
+	}
+}
+
+func yasnippetPlaceholder(tabstop int, field *schema.FieldDefinition, isName bool) string {
+	n := strconv.Itoa(tabstop)
+	switch {
+	case isName:
+		return "${" + n + ":`(file-name-base (or (buffer-file-name) \"\"))`}"
+	case len(field.Values) > 0 && !isArrayField(field):
+		return "${" + n + ":$$(yas-choose-value '(" + quotedChoices(orderedChoices(field)) + "))}"
+	case field.Type == schema.FieldTypeBool:
+		return "${" + n + ":$$(yas-choose-value '(" + quotedChoices(boolChoices(field)) + "))}"
+	case field.Default != nil:
+		return "${" + n + ":" + snippetDefault(field) + "}"
+	case field.Type == schema.FieldTypeDate:
+		return "${" + n + ":`(format-time-string \"%Y-%m-%d\")`}"
+	case isArrayField(field):
+		return "[${" + n + "}]"
+	default:
+		return "${" + n + "}"
+	}
+}
+
+func obsidianPlaceholder(_ int, field *schema.FieldDefinition, isName bool) string {
+	switch {
+	case isName:
+		return "\"{{title}}\""
+	case field.Default != nil:
+		return snippetDefault(field)
+	case field.Type == schema.FieldTypeDate:
+		return "{{date:YYYY-MM-DD}}"
+	case field.Type == schema.FieldTypeDatetime:
+		return "{{date:YYYY-MM-DD}}T{{time:HH:mm}}"
+	case isArrayField(field):
+		return "[]"
+	default:
+		return ""
+	}
+}
+
+func isArrayField(field *schema.FieldDefinition) bool {
+	return strings.HasSuffix(string(field.Type), "[]")
+}
+
+// orderedChoices lists enum values with the default first.
+func orderedChoices(field *schema.FieldDefinition) []string {
+	def := snippetDefault(field)
+	choices := make([]string, 0, len(field.Values))
+	if slices.Contains(field.Values, def) {
+		choices = append(choices, def)
+	}
+	for _, value := range field.Values {
+		if value != def {
+			choices = append(choices, value)
+		}
+	}
+	return choices
+}
+
+func boolChoices(field *schema.FieldDefinition) []string {
+	if snippetDefault(field) == "true" {
+		return []string{"true", "false"}
+	}
+	return []string{"false", "true"}
+}
+
+func quotedChoices(choices []string) string {
+	quoted := make([]string, len(choices))
+	for i, choice := range choices {
+		quoted[i] = strconv.Quote(choice)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// snippetDefault formats a field's schema default as a YAML value.
+func snippetDefault(field *schema.FieldDefinition) string {
+	switch value := field.Default.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		items := make([]string, len(value))
+		for i, item := range value {
+			items[i] = fmt.Sprint(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,128 @@
+package schemasvc
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+const snippetsTestSchema = `version: 2
+types:
+  person:
+    name_field: name
+    fields:
+      name:
+        type: string
+        required: true
+  project:
+    name_field: title
+    description: A project
+    fields:
+      title:
+        type: string
+        required: true
+      status:
+        type: enum
+        values: [active, paused, done]
+        default: paused
+      owner:
+        type: ref
+        target: person
+        required: true
+      due:
+        type: date
+      tags:
+        type: string[]
+`
+
+func TestExportSnippets_VSCode(t *testing.T) {
+	t.Parallel()
+	vault := testutil.NewTestVault(t).WithSchema(snippetsTestSchema).Build()
+
+	result, err := ExportSnippets(ExportSnippetsRequest{VaultPath: vault.Path, Types: []string{"project"}})
+	if err != nil {
+		t.Fatalf("ExportSnippets: %v", err)
+	}
+	if result.Format != SnippetFormatVSCode || len(result.Files) != 1 || result.Files[0].Path != "raven.code-snippets" {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+
+	var snippets map[string]struct {
+		Prefix string   `json:"prefix"`
+		Body   []string `json:"body"`
+	}
+	if err := json.Unmarshal([]byte(result.Files[0].Content), &snippets); err != nil {
+		t.Fatalf("snippets are not valid JSON: %v", err)
+	}
+	project := snippets["Raven project"]
+	if project.Prefix != "rvn-project" {
+		t.Errorf("prefix = %q, want rvn-project", project.Prefix)
+	}
+	wantBody := []string{
+		"---",
+		"type: project",
+		"title: ${1:$TM_FILENAME_BASE}",
+		"owner: ${2}",
+		"due: ${3:$CURRENT_YEAR-$CURRENT_MONTH-$CURRENT_DATE}",
+		"status: ${4|paused,active,done|}",
+		"tags: [${5}]",
+		"---",
+		"$0",
+	}
+	if !reflect.DeepEqual(project.Body, wantBody) {
+		t.Errorf("body =\n%s\nwant\n%s", strings.Join(project.Body, "\n"), strings.Join(wantBody, "\n"))
+	}
+}
+
+func TestExportSnippets_WritesObsidianAndYasnippetFiles(t *testing.T) {
+	t.Parallel()
+	vault := testutil.NewTestVault(t).WithSchema(snippetsTestSchema).Build()
+
+	result, err := ExportSnippets(ExportSnippetsRequest{VaultPath: vault.Path, Format: "obsidian", OutputDir: "templates"})
+	if err != nil {
+		t.Fatalf("ExportSnippets obsidian: %v", err)
+	}
+	if result.OutputDir != filepath.Join(vault.Path, "templates") || len(result.Files) != 2 {
+		t.Fatalf("unexpected result: %#v", result)
+	}
+	vault.AssertFileContains("templates/person.md", "type: person\nname: \"{{title}}\"\n")
+	vault.AssertFileContains("templates/project.md", "owner:\ndue: {{date:YYYY-MM-DD}}\nstatus: paused\ntags: []\n")
+
+	outDir := t.TempDir()
+	if _, err := ExportSnippets(ExportSnippetsRequest{VaultPath: vault.Path, Format: "yasnippet", Types: []string{"person"}, OutputDir: outDir}); err != nil {
+		t.Fatalf("ExportSnippets yasnippet: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(outDir, "markdown-mode", "rvn-person"))
+	if err != nil {
+		t.Fatalf("read yasnippet: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "# -*- mode: snippet -*-\n# name: Raven person\n# key: rvn-person\n# --\n---\ntype: person\n") {
+		t.Errorf("unexpected yasnippet:\n%s", content)
+	}
+}
+
+func TestExportSnippets_RejectsUnknownFormatAndType(t *testing.T) {
+	t.Parallel()
+	vault := testutil.NewTestVault(t).WithSchema(snippetsTestSchema).Build()
+
+	for _, req := range []ExportSnippetsRequest{
+		{VaultPath: vault.Path, Format: "sublime"},
+		{VaultPath: vault.Path, Types: []string{"meeting"}},
+		{VaultPath: vault.Path, Types: []string{"page"}},
+	} {
+		_, err := ExportSnippets(req)
+		var svcErr *Error
+		if !errors.As(err, &svcErr) {
+			t.Fatalf("ExportSnippets(%+v) error = %v, want schemasvc error", req, err)
+		}
+		if svcErr.Code != ErrorInvalidInput && svcErr.Code != ErrorTypeNotFound {
+			t.Errorf("ExportSnippets(%+v) code = %s", req, svcErr.Code)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package schemasvc
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/aidanlsb/raven/internal/codes"
@@ -37,6 +38,12 @@ type UpdateFieldRequest struct {
 	Values      string
 	Target      string
 	Description string
+	// Confirm applies a type change that converts existing values; without
+	// it such a change is only previewed.
+	Confirm bool
+	// Force applies the conversions that succeed when some values cannot be
+	// converted, leaving those values as they are.
+	Force bool
 }
 
 type UpdateResult struct {
@@ -44,6 +51,13 @@ type UpdateResult struct {
 	Type    string
 	Field   string
 	Changes []string
+	// Impact is set when the update drops enum values that may still be in use.
+	Impact *Impact
+	// Migration is set when a type change affects existing values.
+	Migration *FieldMigration
+	// Preview is true when the change was not applied because existing
+	// values need converting and Confirm was not set.
+	Preview bool
 }
 
 type RemoveTypeRequest struct {
@@ -76,6 +90,7 @@ type RemoveResult struct {
 	Type     string
 	Field    string
 	Warnings []Warning
+	Impact   *Impact
 }
 
 func UpdateType(req UpdateTypeRequest) (*UpdateResult, error) {
@@ -512,17 +527,88 @@ func UpdateField(req UpdateFieldRequest) (*UpdateResult, error) {
 		)
 	}
 
+	var impact *Impact
+	if dropped := RemovedEnumValues(currentFieldDef, validation.BaseType, effectiveValues); len(dropped) > 0 {
+		impact, err = AnalyzeEnumValuesImpact(req.VaultPath, typeName, fieldName, dropped)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var migration *FieldMigration
+	if newType, _ := fieldNode["type"].(string); strings.TrimSpace(req.FieldType) != "" && newType != currentFieldType(currentFieldDef) {
+		migration, err = planFieldMigration(req.VaultPath, typeName, fieldName, currentFieldDef.Type, &schema.FieldDefinition{
+			Type:   schema.FieldType(newType),
+			Values: splitCommaValues(effectiveValues),
+			Target: effectiveTarget,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !migration.pending() {
+			migration = nil
+		}
+	}
+	if migration != nil && !req.Confirm {
+		return &UpdateResult{
+			Type:      typeName,
+			Field:     fieldName,
+			Changes:   changes,
+			Impact:    impact,
+			Migration: migration,
+			Preview:   true,
+		}, nil
+	}
+	if migration != nil && len(migration.Failures) > 0 && !req.Force {
+		return nil, newError(
+			ErrorDataIntegrity,
+			fmt.Sprintf("%d values of field '%s' cannot be converted to %s", len(migration.Failures), fieldName, migration.To),
+			"Fix the listed values, or rerun with --force to convert the rest and leave these unchanged",
+			map[string]interface{}{"failures": migration.Failures},
+			nil,
+		)
+	}
+
 	if err := writeSchemaDoc(req.VaultPath, schemaDoc); err != nil {
 		return nil, err
 	}
+	if migration != nil {
+		if err := applyFieldMigration(fieldName, migration); err != nil {
+			return nil, err
+		}
+	}
 
 	return &UpdateResult{
-		Type:    typeName,
-		Field:   fieldName,
-		Changes: changes,
+		Type:      typeName,
+		Field:     fieldName,
+		Changes:   changes,
+		Impact:    impact,
+		Migration: migration,
 	}, nil
 }
 
+// RemovedEnumValues returns the current enum values of a field that are not in
+// the comma-separated newValues. A field that stops being an enum drops all of
+// its values.
+func RemovedEnumValues(current *schema.FieldDefinition, newBaseType, newValues string) []string {
+	if current == nil || len(current.Values) == 0 {
+		return nil
+	}
+	keep := make(map[string]struct{})
+	if newBaseType == "" || newBaseType == "enum" {
+		for _, value := range splitCommaValues(newValues) {
+			keep[value] = struct{}{}
+		}
+	}
+	dropped := make([]string, 0)
+	for _, value := range current.Values {
+		if _, ok := keep[value]; !ok {
+			dropped = append(dropped, value)
+		}
+	}
+	return dropped
+}
+
 func currentTraitType(def *schema.TraitDefinition) string {
 	if def == nil {
 		return "string"
@@ -562,42 +648,44 @@ func RemoveType(req RemoveTypeRequest) (*RemoveResult, error) {
 	if _, exists := sch.Types[typeName]; !exists {
 		return nil, newError(ErrorTypeNotFound, fmt.Sprintf("type '%s' not found", typeName), "", nil, nil)
 	}
+	var children []string
+	for name, typeDef := range sch.Types {
+		if typeDef != nil && typeDef.Extends == typeName {
+			children = append(children, name)
+		}
+	}
+	if len(children) > 0 {
+		sort.Strings(children)
+		return nil, newError(
+			ErrorDataIntegrity,
+			fmt.Sprintf("type '%s' is extended by %s", typeName, strings.Join(children, ", ")),
+			"Remove or change extends on those types first",
+			map[string]interface{}{"extended_by": children},
+			nil,
+		)
+	}
 
+	impact, err := AnalyzeTypeImpact(req.VaultPath, typeName)
+	if err != nil {
+		return nil, err
+	}
 	warnings := make([]Warning, 0)
-	if db, err := index.Open(req.VaultPath); err == nil {
-		defer db.Close()
-		if objects, err := db.QueryObjects(typeName); err == nil && len(objects) > 0 {
-			warnings = append(warnings, Warning{
-				Code:    codes.WarnOrphanedFiles,
-				Message: fmt.Sprintf("%d files of type '%s' will become 'page' type", len(objects), typeName),
-			})
-			if req.Interactive && !req.Force {
-				details := map[string]interface{}{
-					"type":           typeName,
-					"affected_count": len(objects),
-				}
-				sample := make([]string, 0, 5)
-				for i, obj := range objects {
-					if i >= 5 {
-						break
-					}
-					sample = append(sample, obj.FilePath)
-				}
-				if len(sample) > 0 {
-					details["affected_files"] = sample
-				}
-				if len(objects) > len(sample) {
-					details["remaining_count"] = len(objects) - len(sample)
-				}
-				return nil, newError(
-					ErrorConfirmation,
-					fmt.Sprintf("%d files of type '%s' will become 'page' type", len(objects), typeName),
-					"Use --force to skip confirmation",
-					details,
-					nil,
-				)
-			}
-		}
+	if impact.ObjectCount > 0 {
+		message := fmt.Sprintf("%d files of type '%s' will become 'page' type", impact.ObjectCount, typeName)
+		warnings = append(warnings, Warning{Code: codes.WarnOrphanedFiles, Message: message})
+	}
+	if impact.HasUsage() && req.Interactive && !req.Force {
+		return nil, newError(
+			ErrorConfirmation,
+			fmt.Sprintf("removing type '%s' affects %d files, %d saved queries, and %d templates", typeName, impact.ObjectCount, len(impact.Queries), len(impact.Templates)),
+			"Use --force to skip confirmation",
+			map[string]interface{}{
+				"type":           typeName,
+				"affected_count": impact.ObjectCount,
+				"impact":         impact,
+			},
+			nil,
+		)
 	}
 
 	schemaDoc, typesNode, err := readSchemaDocWithTypes(req.VaultPath)
@@ -612,6 +700,7 @@ func RemoveType(req RemoveTypeRequest) (*RemoveResult, error) {
 	return &RemoveResult{
 		Name:     typeName,
 		Warnings: warnings,
+		Impact:   impact,
 	}, nil
 }
 
@@ -629,27 +718,27 @@ func RemoveTrait(req RemoveTraitRequest) (*RemoveResult, error) {
 		return nil, newError(ErrorTraitNotFound, fmt.Sprintf("trait '%s' not found", traitName), "", nil, nil)
 	}
 
+	impact, err := AnalyzeTraitImpact(req.VaultPath, traitName)
+	if err != nil {
+		return nil, err
+	}
 	warnings := make([]Warning, 0)
-	if db, err := index.Open(req.VaultPath); err == nil {
-		defer db.Close()
-		if instances, err := db.QueryTraits(traitName, nil); err == nil && len(instances) > 0 {
-			warnings = append(warnings, Warning{
-				Code:    codes.WarnOrphanedTraits,
-				Message: fmt.Sprintf("%d instances of @%s will remain in files (no longer indexed)", len(instances), traitName),
-			})
-			if req.Interactive && !req.Force {
-				return nil, newError(
-					ErrorConfirmation,
-					fmt.Sprintf("%d instances of @%s will remain in files (no longer indexed)", len(instances), traitName),
-					"Use --force to skip confirmation",
-					map[string]interface{}{
-						"trait":          traitName,
-						"affected_count": len(instances),
-					},
-					nil,
-				)
-			}
-		}
+	if impact.TraitCount > 0 {
+		message := fmt.Sprintf("%d instances of @%s will remain in files (no longer indexed)", impact.TraitCount, traitName)
+		warnings = append(warnings, Warning{Code: codes.WarnOrphanedTraits, Message: message})
+	}
+	if impact.HasUsage() && req.Interactive && !req.Force {
+		return nil, newError(
+			ErrorConfirmation,
+			fmt.Sprintf("removing trait '%s' affects %d instances, %d saved queries, and %d templates", traitName, impact.TraitCount, len(impact.Queries), len(impact.Templates)),
+			"Use --force to skip confirmation",
+			map[string]interface{}{
+				"trait":          traitName,
+				"affected_count": impact.TraitCount,
+				"impact":         impact,
+			},
+			nil,
+		)
 	}
 
 	schemaDoc, err := readSchemaDoc(req.VaultPath)
@@ -666,6 +755,7 @@ func RemoveTrait(req RemoveTraitRequest) (*RemoveResult, error) {
 	return &RemoveResult{
 		Name:     traitName,
 		Warnings: warnings,
+		Impact:   impact,
 	}, nil
 }
 
@@ -722,6 +812,11 @@ func RemoveField(req RemoveFieldRequest) (*RemoveResult, error) {
 		}
 	}
 
+	impact, err := AnalyzeFieldImpact(req.VaultPath, typeName, fieldName)
+	if err != nil {
+		return nil, err
+	}
+
 	schemaDoc, typesNode, err := readSchemaDocWithTypes(req.VaultPath)
 	if err != nil {
 		return nil, err
@@ -757,8 +852,9 @@ func RemoveField(req RemoveFieldRequest) (*RemoveResult, error) {
 	}
 
 	return &RemoveResult{
-		Type:  typeName,
-		Field: fieldName,
+		Type:   typeName,
+		Field:  fieldName,
+		Impact: impact,
 	}, nil
 }
 
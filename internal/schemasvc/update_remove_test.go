@@ -140,3 +140,33 @@ traits:
 		t.Fatalf("done default = %#v, want bool(true)", loaded.Traits["done"].Default)
 	}
 }
+
+func TestRemoveType_RefusesWhileExtended(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).WithSchema(`version: 1
+types:
+  event:
+    fields:
+      title: {type: string}
+  meeting:
+    extends: event
+`).Build()
+
+	_, err := RemoveType(RemoveTypeRequest{VaultPath: vault.Path, TypeName: "event", Force: true})
+	var svcErr *Error
+	if !errors.As(err, &svcErr) || svcErr.Code != ErrorDataIntegrity {
+		t.Fatalf("expected data integrity error, got %v", err)
+	}
+	if !strings.Contains(svcErr.Message, "extended by meeting") {
+		t.Fatalf("unexpected error message: %q", svcErr.Message)
+	}
+
+	loaded, err := schema.Load(vault.Path)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	if loaded.Types["event"] == nil {
+		t.Fatal("event should not have been removed")
+	}
+}
@@ -0,0 +1,56 @@
+package schemasvc
+
+import (
+	"time"
+
+	"github.com/aidanlsb/raven/internal/index"
+)
+
+// attachTypeUsage fills in Usage for every listed type from the index. It
+// returns false when the index can't be read, leaving Usage unset.
+func attachTypeUsage(vaultPath string, types map[string]TypeSchema) bool {
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return false
+	}
+	defer db.Close()
+
+	usage, err := db.TypeUsage()
+	if err != nil {
+		return false
+	}
+	for name, typeSchema := range types {
+		stats := usage[name]
+		typeSchema.Usage = &TypeUsage{ObjectCount: stats.Count, LastUsed: usageDate(stats.LastUsed)}
+		types[name] = typeSchema
+	}
+	return true
+}
+
+// attachTraitUsage fills in Usage for every listed trait from the index. It
+// returns false when the index can't be read, leaving Usage unset.
+func attachTraitUsage(vaultPath string, traits map[string]TraitSchema) bool {
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		return false
+	}
+	defer db.Close()
+
+	usage, err := db.TraitUsage()
+	if err != nil {
+		return false
+	}
+	for name, traitSchema := range traits {
+		stats := usage[name]
+		traitSchema.Usage = &TraitUsage{InstanceCount: stats.Count, LastUsed: usageDate(stats.LastUsed)}
+		traits[name] = traitSchema
+	}
+	return true
+}
+
+func usageDate(mtime int64) string {
+	if mtime <= 0 {
+		return ""
+	}
+	return time.Unix(mtime, 0).Format("2006-01-02")
+}
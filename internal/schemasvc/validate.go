@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 
+	"github.com/aidanlsb/raven/internal/query"
 	"github.com/aidanlsb/raven/internal/schema"
 )
 
@@ -25,6 +26,7 @@ func Validate(vaultPath string) (*ValidateResult, error) {
 	}
 
 	issues := schema.ValidateSchema(sch)
+	issues = append(issues, query.ValidateDerivedFields(sch)...)
 	return &ValidateResult{
 		Valid:  len(issues) == 0,
 		Issues: issues,
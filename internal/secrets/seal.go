@@ -0,0 +1,100 @@
+package secrets
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"sync"
+)
+
+// ErrInvalid indicates sealed data could not be opened: the passphrase is
+// wrong or the data is corrupted.
+var ErrInvalid = errors.New("data could not be decrypted (wrong key or corrupted data)")
+
+const (
+	// SaltSize is the length of key derivation salts.
+	SaltSize = 16
+	// pbkdf2Iterations trades a one-time cost per process (keys are cached)
+	// against offline guessing of weak passphrases.
+	pbkdf2Iterations = 200_000
+)
+
+// derivedKeys caches PBKDF2 output per passphrase and salt so repeated seals
+// and opens in one process derive the key once.
+var derivedKeys sync.Map
+
+// NewSalt returns a random key derivation salt.
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// Seal encrypts plaintext with AES-256-GCM under a key derived from
+// passphrase and salt with PBKDF2. The result is laid out as
+// magic | salt | nonce | ciphertext, and magic is authenticated with it.
+func Seal(magic, passphrase, salt, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(magic)+len(salt)+len(nonce)+len(plaintext)+aead.Overhead())
+	out = append(out, magic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, magic), nil
+}
+
+// Open decrypts data written by Seal with the same magic and returns the
+// plaintext and the salt it was sealed with.
+func Open(magic, passphrase, data []byte) ([]byte, []byte, error) {
+	header := len(magic) + SaltSize
+	if len(data) < header || !bytes.HasPrefix(data, magic) {
+		return nil, nil, ErrInvalid
+	}
+	salt := append([]byte(nil), data[len(magic):header]...)
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	rest := data[header:]
+	if len(rest) < aead.NonceSize() {
+		return nil, nil, ErrInvalid
+	}
+	plaintext, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], magic)
+	if err != nil {
+		return nil, nil, ErrInvalid
+	}
+	return plaintext, salt, nil
+}
+
+func newAEAD(passphrase, salt []byte) (cipher.AEAD, error) {
+	cacheKey := sha256.Sum256(append(append([]byte(nil), salt...), passphrase...))
+	var key []byte
+	if cached, ok := derivedKeys.Load(cacheKey); ok {
+		key = cached.([]byte)
+	} else {
+		derived, err := pbkdf2.Key(sha256.New, string(passphrase), salt, pbkdf2Iterations, 32)
+		if err != nil {
+			return nil, err
+		}
+		derivedKeys.Store(cacheKey, derived)
+		key = derived
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
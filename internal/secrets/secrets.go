@@ -0,0 +1,83 @@
+// Package secrets resolves passphrases and API tokens and seals data with
+// passphrase-derived keys.
+//
+// Secrets come only from sources the user controls: an environment variable,
+// or a command configured for that variable under [key_commands] in
+// config.toml. Vault files such as raven.yaml may name the variable to read
+// but never supply a command, so opening someone else's vault cannot run
+// their shell commands.
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// ErrNotSet indicates neither the environment variable nor a key command
+// provides the secret.
+var ErrNotSet = errors.New("secret is not set")
+
+var (
+	commandsMu sync.RWMutex
+	commands   map[string]string
+)
+
+// SetCommands installs the [key_commands] table from config.toml, which maps
+// environment variable names to commands that print their value.
+func SetCommands(cmds map[string]string) {
+	commandsMu.Lock()
+	defer commandsMu.Unlock()
+	commands = make(map[string]string, len(cmds))
+	for name, command := range cmds {
+		if command = strings.TrimSpace(command); command != "" {
+			commands[strings.TrimSpace(name)] = command
+		}
+	}
+}
+
+func commandFor(envName string) string {
+	commandsMu.RLock()
+	defer commandsMu.RUnlock()
+	return commands[envName]
+}
+
+// Lookup returns the value of the environment variable envName, falling back
+// to the output of the command config.toml configures for it (for example a
+// keychain lookup). It returns ErrNotSet when neither provides a value.
+func Lookup(envName string) ([]byte, error) {
+	if value := os.Getenv(envName); value != "" {
+		return []byte(value), nil
+	}
+	command := commandFor(envName)
+	if command == "" {
+		return nil, fmt.Errorf("%w: %s", ErrNotSet, Hint(envName))
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("key_commands.%s in config.toml failed: %w", envName, err)
+	}
+	value := bytes.TrimRight(out, "\r\n")
+	if len(value) == 0 {
+		return nil, fmt.Errorf("%w: key_commands.%s in config.toml printed nothing", ErrNotSet, envName)
+	}
+	return value, nil
+}
+
+// Hint tells the user where Lookup reads envName from.
+func Hint(envName string) string {
+	return fmt.Sprintf("set %s or key_commands.%s in config.toml", envName, envName)
+}
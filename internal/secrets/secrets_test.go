@@ -0,0 +1,52 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestLookupUsesEnvironmentThenKeyCommand(t *testing.T) {
+	t.Setenv("RAVEN_TEST_SECRET", "")
+	SetCommands(map[string]string{"RAVEN_TEST_SECRET": "echo from-keychain"})
+	t.Cleanup(func() { SetCommands(nil) })
+
+	value, err := Lookup("RAVEN_TEST_SECRET")
+	if err != nil || string(value) != "from-keychain" {
+		t.Fatalf("Lookup via key command = %q, %v", value, err)
+	}
+
+	t.Setenv("RAVEN_TEST_SECRET", "from-env")
+	if value, err := Lookup("RAVEN_TEST_SECRET"); err != nil || string(value) != "from-env" {
+		t.Fatalf("Lookup via environment = %q, %v", value, err)
+	}
+
+	if _, err := Lookup("RAVEN_TEST_SECRET_UNSET"); !errors.Is(err, ErrNotSet) {
+		t.Fatalf("expected ErrNotSet, got %v", err)
+	}
+}
+
+func TestSealAndOpen(t *testing.T) {
+	t.Parallel()
+	magic := []byte("RVNTEST1")
+	salt, err := NewSalt()
+	if err != nil {
+		t.Fatalf("NewSalt: %v", err)
+	}
+
+	sealed, err := Seal(magic, []byte("pass"), salt, []byte("hello"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	plaintext, gotSalt, err := Open(magic, []byte("pass"), sealed)
+	if err != nil || string(plaintext) != "hello" || !bytes.Equal(gotSalt, salt) {
+		t.Fatalf("Open = %q, %x, %v", plaintext, gotSalt, err)
+	}
+
+	if _, _, err := Open(magic, []byte("wrong"), sealed); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("wrong passphrase: expected ErrInvalid, got %v", err)
+	}
+	if _, _, err := Open([]byte("RVNOTHER"), []byte("pass"), sealed); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("wrong magic: expected ErrInvalid, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package syncsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/secrets"
+)
+
+// Record is one item in an external system, with adapter field values
+// rendered as strings.
+type Record struct {
+	ID     string
+	Fields map[string]string
+}
+
+// Adapter reads and writes records in an external system.
+type Adapter interface {
+	// List returns every record visible to the adapter.
+	List(ctx context.Context) ([]Record, error)
+
+	// Update writes the given adapter fields to an existing record.
+	Update(ctx context.Context, id string, fields map[string]string) error
+
+	// ReadOnly reports whether an adapter field cannot be written. Local
+	// changes to read-only fields are overwritten by the remote value.
+	ReadOnly(field string) bool
+}
+
+// NewAdapter builds the adapter configured for a sync entry.
+func NewAdapter(name string, cfg *config.SyncAdapterConfig, client *http.Client) (Adapter, error) {
+	switch kind := cfg.GetAdapter(name); kind {
+	case "github":
+		return newGitHubAdapter(cfg, client)
+	default:
+		return nil, newError(CodeConfigInvalid, fmt.Sprintf("unknown sync adapter %q", kind), "Set sync."+name+".adapter to one of: github", nil)
+	}
+}
+
+// resolveToken reads an API token from the environment variable envName, or
+// from the command config.toml configures for it under [key_commands]. A
+// missing token is not an error; reads of public data work without one.
+func resolveToken(envName string) (string, error) {
+	token, err := secrets.Lookup(envName)
+	if errors.Is(err, secrets.ErrNotSet) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(token)), nil
+}
@@ -0,0 +1,221 @@
+package syncsvc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/secrets"
+)
+
+const (
+	defaultGitHubURL      = "https://api.github.com"
+	defaultGitHubTokenEnv = "GITHUB_TOKEN"
+	githubPageSize        = 100
+	githubRequestTimeout  = 30 * time.Second
+)
+
+// githubFields lists the issue fields the GitHub adapter exposes. number and
+// url are read-only.
+var githubFields = map[string]bool{
+	"title":    true,
+	"state":    true,
+	"body":     true,
+	"assignee": true,
+	"labels":   true,
+	"number":   false,
+	"url":      false,
+}
+
+// githubAdapter syncs the issues of one repository. Pull requests, which the
+// issues API also returns, are skipped.
+type githubAdapter struct {
+	baseURL  string
+	repo     string
+	tokenEnv string
+	client   *http.Client
+}
+
+type githubIssue struct {
+	Number      int     `json:"number"`
+	Title       string  `json:"title"`
+	State       string  `json:"state"`
+	Body        *string `json:"body"`
+	HTMLURL     string  `json:"html_url"`
+	PullRequest *struct {
+		URL string `json:"url"`
+	} `json:"pull_request"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+func newGitHubAdapter(cfg *config.SyncAdapterConfig, client *http.Client) (*githubAdapter, error) {
+	repo := strings.Trim(strings.TrimSpace(cfg.Repo), "/")
+	if strings.Count(repo, "/") != 1 {
+		return nil, newError(CodeConfigInvalid, "github sync requires repo as owner/name", "Set repo: owner/name in the sync entry", nil)
+	}
+	for _, field := range cfg.Fields {
+		if _, ok := githubFields[field]; !ok {
+			return nil, newError(CodeConfigInvalid, fmt.Sprintf("unknown github field %q", field), "Map Raven fields to one of: "+strings.Join(sortedKeys(githubFields), ", "), nil)
+		}
+	}
+
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.URL), "/")
+	if baseURL == "" {
+		baseURL = defaultGitHubURL
+	}
+	tokenEnv := strings.TrimSpace(cfg.TokenEnv)
+	if tokenEnv == "" {
+		tokenEnv = defaultGitHubTokenEnv
+	}
+	if client == nil {
+		client = &http.Client{Timeout: githubRequestTimeout}
+	}
+	return &githubAdapter{
+		baseURL:  baseURL,
+		repo:     repo,
+		tokenEnv: tokenEnv,
+		client:   client,
+	}, nil
+}
+
+func (a *githubAdapter) ReadOnly(field string) bool {
+	return !githubFields[field]
+}
+
+func (a *githubAdapter) List(ctx context.Context) ([]Record, error) {
+	var records []Record
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/repos/%s/issues?state=all&per_page=%d&page=%d", a.baseURL, a.repo, githubPageSize, page)
+		var issues []githubIssue
+		if err := a.do(ctx, http.MethodGet, url, nil, &issues); err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if issue.PullRequest != nil {
+				continue
+			}
+			records = append(records, issue.record())
+		}
+		if len(issues) < githubPageSize {
+			return records, nil
+		}
+	}
+}
+
+func (a *githubAdapter) Update(ctx context.Context, id string, fields map[string]string) error {
+	payload := make(map[string]interface{}, len(fields))
+	for field, value := range fields {
+		switch field {
+		case "title", "state", "body":
+			payload[field] = value
+		case "assignee":
+			payload["assignees"] = splitList(value)
+		case "labels":
+			payload["labels"] = splitList(value)
+		default:
+			return fmt.Errorf("github field %q is read-only", field)
+		}
+	}
+	url := fmt.Sprintf("%s/repos/%s/issues/%s", a.baseURL, a.repo, id)
+	return a.do(ctx, http.MethodPatch, url, payload, nil)
+}
+
+func (a *githubAdapter) do(ctx context.Context, method, url string, payload interface{}, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	token, err := resolveToken(a.tokenEnv)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if method != http.MethodGet {
+		return fmt.Errorf("github updates require a token: %s", secrets.Hint(a.tokenEnv))
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("github %s %s: status %d", method, strings.TrimPrefix(url, a.baseURL), resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (issue githubIssue) record() Record {
+	fields := map[string]string{
+		"title":  issue.Title,
+		"state":  issue.State,
+		"number": strconv.Itoa(issue.Number),
+		"url":    issue.HTMLURL,
+	}
+	if issue.Body != nil {
+		fields["body"] = *issue.Body
+	} else {
+		fields["body"] = ""
+	}
+	assignees := make([]string, 0, len(issue.Assignees))
+	for _, assignee := range issue.Assignees {
+		assignees = append(assignees, assignee.Login)
+	}
+	fields["assignee"] = strings.Join(assignees, ", ")
+	labels := make([]string, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		labels = append(labels, label.Name)
+	}
+	sort.Strings(labels)
+	fields["labels"] = strings.Join(labels, ", ")
+	return Record{ID: strconv.Itoa(issue.Number), Fields: fields}
+}
+
+// splitList splits a comma-separated field value, dropping blanks.
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,142 @@
+package syncsvc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+)
+
+func newGitHubTestAdapter(t *testing.T, handler http.HandlerFunc) *githubAdapter {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	t.Setenv("RAVEN_SYNC_TEST_TOKEN", "secret")
+
+	adapter, err := newGitHubAdapter(&config.SyncAdapterConfig{
+		Repo:     "acme/widgets",
+		URL:      server.URL,
+		TokenEnv: "RAVEN_SYNC_TEST_TOKEN",
+	}, server.Client())
+	if err != nil {
+		t.Fatalf("newGitHubAdapter: %v", err)
+	}
+	return adapter
+}
+
+func TestGitHubAdapter_ListPagesAndSkipsPullRequests(t *testing.T) {
+	adapter := newGitHubTestAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("authorization = %q", got)
+		}
+		var issues []map[string]interface{}
+		if r.URL.Query().Get("page") == "1" {
+			for i := 1; i <= githubPageSize; i++ {
+				issue := map[string]interface{}{"number": i, "title": fmt.Sprintf("Issue %d", i), "state": "open"}
+				if i == 2 {
+					issue["pull_request"] = map[string]string{"url": "x"}
+				}
+				issues = append(issues, issue)
+			}
+		} else {
+			issues = append(issues, map[string]interface{}{
+				"number":    101,
+				"title":     "Last",
+				"state":     "closed",
+				"body":      "Details",
+				"html_url":  "https://github.com/acme/widgets/issues/101",
+				"assignees": []map[string]string{{"login": "ada"}},
+				"labels":    []map[string]string{{"name": "ui"}, {"name": "bug"}},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(issues)
+	})
+
+	records, err := adapter.List(context.Background())
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if got, want := len(records), githubPageSize; got != want {
+		t.Fatalf("records = %d, want %d", got, want)
+	}
+	last := records[len(records)-1]
+	want := map[string]string{
+		"title":    "Last",
+		"state":    "closed",
+		"body":     "Details",
+		"number":   "101",
+		"url":      "https://github.com/acme/widgets/issues/101",
+		"assignee": "ada",
+		"labels":   "bug, ui",
+	}
+	if last.ID != "101" || !reflect.DeepEqual(last.Fields, want) {
+		t.Fatalf("last record = %#v, want fields %#v", last, want)
+	}
+}
+
+func TestGitHubAdapter_UpdateSendsListFields(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]interface{}
+	adapter := newGitHubTestAdapter(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("method = %s, want PATCH", r.Method)
+		}
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		_, _ = w.Write([]byte("{}"))
+	})
+
+	err := adapter.Update(context.Background(), "7", map[string]string{
+		"state":    "closed",
+		"assignee": "ada, grace",
+		"labels":   "",
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if gotPath != "/repos/acme/widgets/issues/7" {
+		t.Fatalf("path = %q", gotPath)
+	}
+	want := map[string]interface{}{
+		"state":     "closed",
+		"assignees": []interface{}{"ada", "grace"},
+		"labels":    []interface{}{},
+	}
+	if !reflect.DeepEqual(gotBody, want) {
+		t.Fatalf("body = %#v, want %#v", gotBody, want)
+	}
+
+	if err := adapter.Update(context.Background(), "7", map[string]string{"number": "8"}); err == nil {
+		t.Fatal("expected error updating read-only field")
+	}
+	if !adapter.ReadOnly("url") || adapter.ReadOnly("title") {
+		t.Fatal("ReadOnly should report url as read-only and title as writable")
+	}
+}
+
+func TestNewGitHubAdapter_ValidatesConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  config.SyncAdapterConfig
+	}{
+		{name: "missing repo", cfg: config.SyncAdapterConfig{}},
+		{name: "unknown field", cfg: config.SyncAdapterConfig{Repo: "acme/widgets", Fields: map[string]string{"due": "milestone"}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := newGitHubAdapter(&tt.cfg, nil)
+			if svcErr, ok := AsError(err); !ok || svcErr.Code != CodeConfigInvalid {
+				t.Fatalf("err = %v, want %s", err, CodeConfigInvalid)
+			}
+		})
+	}
+}
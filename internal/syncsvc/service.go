@@ -0,0 +1,460 @@
+// Package syncsvc syncs Raven objects with records in external systems.
+//
+// A sync entry in raven.yaml maps one Raven type to an adapter (for example
+// GitHub issues) and maps Raven fields to adapter fields. Objects are linked
+// to records by an ID field. Each run compares local values, remote values,
+// and the values recorded at the previous sync: a side that changed since
+// then wins, and a field changed on both sides is a conflict that is left
+// alone unless a preferred side is given.
+package syncsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/objectsvc"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+type Code = codes.ErrorCode
+
+const (
+	CodeInvalidInput  Code = codes.ErrInvalidInput
+	CodeConfigInvalid Code = codes.ErrConfigInvalid
+	CodeFetchFailed   Code = codes.ErrFetchFailed
+	CodeDatabase      Code = codes.ErrDatabase
+	CodeFileWrite     Code = codes.ErrFileWrite
+)
+
+type Error struct {
+	Code       Code
+	Message    string
+	Suggestion string
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e == nil {
+		return ""
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return string(e.Code)
+}
+
+func (e *Error) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+func newError(code Code, message, suggestion string, err error) *Error {
+	return &Error{Code: code, Message: message, Suggestion: suggestion, Err: err}
+}
+
+func AsError(err error) (*Error, bool) {
+	var svcErr *Error
+	if errors.As(err, &svcErr) {
+		return svcErr, true
+	}
+	return nil, false
+}
+
+// Sync actions reported per record.
+const (
+	ActionCreated   = "created"
+	ActionPulled    = "pulled"
+	ActionPushed    = "pushed"
+	ActionUpdated   = "updated" // Pulled and pushed in the same run
+	ActionConflict  = "conflict"
+	ActionUnchanged = "unchanged"
+	ActionFailed    = "failed"
+)
+
+// Conflict sides accepted by RunRequest.Prefer.
+const (
+	PreferLocal  = "local"
+	PreferRemote = "remote"
+)
+
+type RunRequest struct {
+	VaultPath   string
+	VaultConfig *config.VaultConfig
+	Schema      *schema.Schema
+	DB          *index.Database
+	Name        string
+	DryRun      bool
+	Prefer      string  // "", "local", or "remote"
+	Adapter     Adapter // Overrides the configured adapter (tests)
+	Context     context.Context
+}
+
+// FieldChange is one field value that differs between Raven and the
+// external record.
+type FieldChange struct {
+	Field  string
+	Local  string
+	Remote string
+}
+
+// RecordResult describes what a sync run did for one external record.
+type RecordResult struct {
+	ExternalID string
+	ObjectID   string
+	Action     string
+	Pulled     []FieldChange
+	Pushed     []FieldChange
+	Conflicts  []FieldChange
+	Error      string
+
+	filePath string
+}
+
+type RunResult struct {
+	Name         string
+	Adapter      string
+	Type         string
+	DryRun       bool
+	Records      []RecordResult
+	ChangedFiles []string // Absolute paths of files written by the run
+}
+
+// Count returns the number of records with the given action.
+func (r *RunResult) Count(action string) int {
+	n := 0
+	for _, record := range r.Records {
+		if record.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Run syncs one configured sync entry.
+func Run(req RunRequest) (*RunResult, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, newError(CodeInvalidInput, "sync name is required", "Usage: rvn sync external <name>", nil)
+	}
+	cfg, ok := req.VaultConfig.GetSyncAdapter(name)
+	if !ok {
+		return nil, newError(CodeConfigInvalid, fmt.Sprintf("no sync entry named %q", name), "Add it under sync: in raven.yaml", nil)
+	}
+	if req.Prefer != "" && req.Prefer != PreferLocal && req.Prefer != PreferRemote {
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("invalid --prefer %q", req.Prefer), "Use --prefer local or --prefer remote", nil)
+	}
+	if req.Schema == nil || req.Schema.Types[cfg.Type] == nil {
+		return nil, newError(CodeConfigInvalid, fmt.Sprintf("sync.%s.type %q is not a schema type", name, cfg.Type), "Set type to a type defined in schema.yaml", nil)
+	}
+	if len(cfg.Fields) == 0 {
+		return nil, newError(CodeConfigInvalid, fmt.Sprintf("sync.%s.fields is empty", name), "Map at least one Raven field to an adapter field", nil)
+	}
+
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	adapter := req.Adapter
+	if adapter == nil {
+		var err error
+		adapter, err = NewAdapter(name, cfg, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	remote, err := adapter.List(ctx)
+	if err != nil {
+		return nil, newError(CodeFetchFailed, fmt.Sprintf("failed to list %s records", name), "Check the adapter settings and credentials", err)
+	}
+	locals, err := linkedObjects(req.DB, cfg.Type, cfg.GetIDField())
+	if err != nil {
+		return nil, newError(CodeDatabase, "failed to read local objects", "Run 'rvn reindex' to rebuild the database", err)
+	}
+	state, err := loadState(req.VaultPath, name)
+	if err != nil {
+		return nil, newError(CodeConfigInvalid, "failed to read sync state", "Delete "+statePath(req.VaultPath, name)+" to start over", err)
+	}
+
+	s := &syncer{req: req, cfg: cfg, adapter: adapter, ctx: ctx, state: state, fields: sortedFieldNames(cfg.Fields)}
+	result := &RunResult{Name: name, Adapter: cfg.GetAdapter(name), Type: cfg.Type, DryRun: req.DryRun}
+	for _, record := range remote {
+		var recordResult RecordResult
+		if local, ok := locals[record.ID]; ok {
+			recordResult = s.syncLinked(record, local)
+		} else {
+			recordResult = s.create(record)
+		}
+		if recordResult.filePath != "" {
+			result.ChangedFiles = append(result.ChangedFiles, recordResult.filePath)
+		}
+		result.Records = append(result.Records, recordResult)
+	}
+
+	if !req.DryRun {
+		if err := saveState(req.VaultPath, name, state); err != nil {
+			return result, newError(CodeFileWrite, "failed to write sync state", "", err)
+		}
+	}
+	return result, nil
+}
+
+type localObject struct {
+	ID       string
+	FilePath string
+	Fields   map[string]interface{}
+}
+
+type syncer struct {
+	req     RunRequest
+	cfg     *config.SyncAdapterConfig
+	adapter Adapter
+	ctx     context.Context
+	state   *syncState
+	fields  []string // Raven field names, sorted
+}
+
+// syncLinked reconciles a record with the object that links to it.
+func (s *syncer) syncLinked(record Record, local localObject) RecordResult {
+	result := RecordResult{ExternalID: record.ID, ObjectID: local.ID, Action: ActionUnchanged}
+	base := s.state.Records[record.ID]
+	newBase := make(map[string]string, len(s.fields))
+	pull := make(map[string]string)
+	push := make(map[string]string)
+
+	for _, field := range s.fields {
+		remoteField := s.cfg.Fields[field]
+		localValue := fieldString(local.Fields[field])
+		remoteValue := record.Fields[remoteField]
+		change := FieldChange{Field: field, Local: localValue, Remote: remoteValue}
+
+		baseValue, hasBase := base[field]
+		switch {
+		case localValue == remoteValue:
+			newBase[field] = remoteValue
+		case s.adapter.ReadOnly(remoteField):
+			pull[field] = remoteValue
+			result.Pulled = append(result.Pulled, change)
+		case hasBase && localValue == baseValue:
+			pull[field] = remoteValue
+			result.Pulled = append(result.Pulled, change)
+		case hasBase && remoteValue == baseValue:
+			push[remoteField] = localValue
+			result.Pushed = append(result.Pushed, change)
+		case s.req.Prefer == PreferRemote:
+			pull[field] = remoteValue
+			result.Pulled = append(result.Pulled, change)
+		case s.req.Prefer == PreferLocal:
+			push[remoteField] = localValue
+			result.Pushed = append(result.Pushed, change)
+		default:
+			result.Conflicts = append(result.Conflicts, change)
+			if hasBase {
+				newBase[field] = baseValue
+			}
+		}
+	}
+
+	if s.req.DryRun {
+		result.Action = summarizeAction(result)
+		return result
+	}
+
+	if len(push) > 0 {
+		if err := s.adapter.Update(s.ctx, record.ID, push); err != nil {
+			result.Action = ActionFailed
+			result.Error = err.Error()
+			return result
+		}
+		for _, change := range result.Pushed {
+			newBase[change.Field] = change.Local
+		}
+	}
+	if len(pull) > 0 {
+		filePath := filepath.Join(s.req.VaultPath, local.FilePath)
+		updates := make(map[string]schema.FieldValue, len(pull))
+		for field, value := range pull {
+			updates[field] = s.fieldValue(field, value)
+		}
+		_, err := objectsvc.SetObjectFile(objectsvc.SetObjectFileRequest{
+			VaultPath:    s.req.VaultPath,
+			VaultConfig:  s.req.VaultConfig,
+			FilePath:     filePath,
+			ObjectID:     local.ID,
+			TypedUpdates: updates,
+			Schema:       s.req.Schema,
+		})
+		if err != nil {
+			result.Action = ActionFailed
+			result.Error = err.Error()
+			return result
+		}
+		result.filePath = filePath
+		for _, change := range result.Pulled {
+			newBase[change.Field] = change.Remote
+		}
+	}
+
+	s.state.Records[record.ID] = newBase
+	result.Action = summarizeAction(result)
+	return result
+}
+
+// create adds a new object for a record no object links to yet.
+func (s *syncer) create(record Record) RecordResult {
+	result := RecordResult{ExternalID: record.ID, Action: ActionCreated}
+	values := make(map[string]schema.FieldValue, len(s.fields)+1)
+	newBase := make(map[string]string, len(s.fields))
+	for _, field := range s.fields {
+		value := record.Fields[s.cfg.Fields[field]]
+		result.Pulled = append(result.Pulled, FieldChange{Field: field, Remote: value})
+		newBase[field] = value
+		if value != "" {
+			values[field] = s.fieldValue(field, value)
+		}
+	}
+	values[s.cfg.GetIDField()] = schema.String(record.ID)
+
+	title := s.title(record)
+	if s.req.DryRun {
+		return result
+	}
+
+	created, err := objectsvc.Create(objectsvc.CreateRequest{
+		VaultPath:   s.req.VaultPath,
+		TypeName:    s.cfg.Type,
+		Title:       title,
+		FieldValues: values,
+		VaultConfig: s.req.VaultConfig,
+		Schema:      s.req.Schema,
+		ObjectsRoot: s.req.VaultConfig.GetObjectsRoot(),
+		PagesRoot:   s.req.VaultConfig.GetPagesRoot(),
+		TemplateDir: s.req.VaultConfig.GetTemplateDirectory(),
+	})
+	if err != nil {
+		result.Action = ActionFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.ObjectID = s.req.VaultConfig.FilePathToObjectID(created.RelativePath)
+	result.filePath = created.FilePath
+	s.state.Records[record.ID] = newBase
+	return result
+}
+
+// title picks a file title for a new object: the type's name_field value
+// when it is mapped, otherwise "<name> <id>".
+func (s *syncer) title(record Record) string {
+	title := ""
+	if typeDef := s.req.Schema.Types[s.cfg.Type]; typeDef != nil && typeDef.NameField != "" {
+		if remoteField, ok := s.cfg.Fields[typeDef.NameField]; ok {
+			title = strings.TrimSpace(record.Fields[remoteField])
+		}
+	}
+	if title == "" {
+		title = s.req.Name + " " + record.ID
+	}
+	return strings.NewReplacer("/", "-", `\`, "-").Replace(title)
+}
+
+// fieldValue converts a synced string into a typed value for the Raven
+// field, splitting comma-separated values for array fields.
+func (s *syncer) fieldValue(field, value string) schema.FieldValue {
+	if value == "" {
+		return schema.Null()
+	}
+	var fieldDef *schema.FieldDefinition
+	if typeDef := s.req.Schema.Types[s.cfg.Type]; typeDef != nil {
+		fieldDef = typeDef.Fields[field]
+	}
+	if fieldDef != nil && strings.HasSuffix(string(fieldDef.Type), "[]") {
+		items := splitList(value)
+		values := make([]schema.FieldValue, len(items))
+		for i, item := range items {
+			values[i] = parser.ParseFieldValue(item)
+		}
+		return schema.Array(values)
+	}
+	if fieldDef != nil && fieldDef.Type == schema.FieldTypeString {
+		return schema.String(value)
+	}
+	return parser.ParseFieldValue(value)
+}
+
+func summarizeAction(result RecordResult) string {
+	switch {
+	case len(result.Conflicts) > 0:
+		return ActionConflict
+	case len(result.Pulled) > 0 && len(result.Pushed) > 0:
+		return ActionUpdated
+	case len(result.Pulled) > 0:
+		return ActionPulled
+	case len(result.Pushed) > 0:
+		return ActionPushed
+	default:
+		return ActionUnchanged
+	}
+}
+
+// linkedObjects returns objects of typeName keyed by their idField value.
+func linkedObjects(db *index.Database, typeName, idField string) (map[string]localObject, error) {
+	objects, err := db.QueryObjects(typeName)
+	if err != nil {
+		return nil, err
+	}
+	linked := make(map[string]localObject, len(objects))
+	for _, obj := range objects {
+		id := fieldString(obj.Fields[idField])
+		if id == "" {
+			continue
+		}
+		linked[id] = localObject{ID: obj.ID, FilePath: obj.FilePath, Fields: obj.Fields}
+	}
+	return linked, nil
+}
+
+// fieldString renders an indexed field value the way adapters see it:
+// scalars as text and arrays as comma-separated items.
+func fieldString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fieldString(item))
+		}
+		return strings.Join(items, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func sortedFieldNames(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
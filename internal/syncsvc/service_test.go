@@ -0,0 +1,258 @@
+package syncsvc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/reindexsvc"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/testutil"
+)
+
+const syncTestSchema = `version: 1
+types:
+  issue:
+    default_path: issues/
+    name_field: title
+    fields:
+      title:
+        type: string
+        required: true
+      state:
+        type: string
+      labels:
+        type: string[]
+      external_id:
+        type: string
+traits: {}
+`
+
+const syncTestConfig = `sync:
+  tracker:
+    adapter: github
+    type: issue
+    repo: acme/widgets
+    fields:
+      title: title
+      state: state
+      labels: labels
+`
+
+type fakeAdapter struct {
+	records  []Record
+	readOnly map[string]bool
+	updates  map[string]map[string]string
+}
+
+func (a *fakeAdapter) List(context.Context) ([]Record, error) {
+	out := make([]Record, len(a.records))
+	for i, record := range a.records {
+		fields := make(map[string]string, len(record.Fields))
+		for k, v := range record.Fields {
+			fields[k] = v
+		}
+		out[i] = Record{ID: record.ID, Fields: fields}
+	}
+	return out, nil
+}
+
+func (a *fakeAdapter) Update(_ context.Context, id string, fields map[string]string) error {
+	if a.updates == nil {
+		a.updates = make(map[string]map[string]string)
+	}
+	a.updates[id] = fields
+	for i := range a.records {
+		if a.records[i].ID == id {
+			for k, v := range fields {
+				a.records[i].Fields[k] = v
+			}
+		}
+	}
+	return nil
+}
+
+func (a *fakeAdapter) ReadOnly(field string) bool {
+	return a.readOnly[field]
+}
+
+func runSyncForTest(t *testing.T, vaultPath string, adapter Adapter, dryRun bool, prefer string) *RunResult {
+	t.Helper()
+	if _, err := reindexsvc.Run(reindexsvc.RunRequest{VaultPath: vaultPath, Full: true}); err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+	cfg, err := config.LoadVaultConfig(vaultPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	sch, err := schema.Load(vaultPath)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		t.Fatalf("open index: %v", err)
+	}
+	defer db.Close()
+
+	result, err := Run(RunRequest{
+		VaultPath:   vaultPath,
+		VaultConfig: cfg,
+		Schema:      sch,
+		DB:          db,
+		Name:        "tracker",
+		DryRun:      dryRun,
+		Prefer:      prefer,
+		Adapter:     adapter,
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	return result
+}
+
+func TestRun_CreatesObjectsForNewRecords(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(syncTestSchema).
+		WithRavenYAML(syncTestConfig).
+		Build()
+	adapter := &fakeAdapter{records: []Record{
+		{ID: "7", Fields: map[string]string{"title": "Fix login", "state": "open", "labels": "bug, ui"}},
+	}}
+
+	result := runSyncForTest(t, v.Path, adapter, false, "")
+	if got := result.Count(ActionCreated); got != 1 {
+		t.Fatalf("created = %d, want 1: %#v", got, result.Records)
+	}
+	if got, want := result.Records[0].ObjectID, "issues/fix-login"; got != want {
+		t.Fatalf("object id = %q, want %q", got, want)
+	}
+	v.AssertFileContains("issues/fix-login.md", "external_id:")
+	v.AssertFileContains("issues/fix-login.md", "state: open")
+	v.AssertFileContains("issues/fix-login.md", "bug")
+
+	again := runSyncForTest(t, v.Path, adapter, false, "")
+	if got := again.Count(ActionUnchanged); got != 1 {
+		t.Fatalf("second run unchanged = %d, want 1: %#v", got, again.Records)
+	}
+}
+
+func TestRun_ReconcilesAgainstLastSyncedValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		local      string
+		remote     string
+		prefer     string
+		wantAction string
+		wantFile   string
+		wantRemote string
+	}{
+		{name: "remote change is pulled", local: "open", remote: "closed", wantAction: ActionPulled, wantFile: "state: closed", wantRemote: "closed"},
+		{name: "local change is pushed", local: "closed", remote: "open", wantAction: ActionPushed, wantFile: "state: closed", wantRemote: "closed"},
+		{name: "both changed is a conflict", local: "blocked", remote: "closed", wantAction: ActionConflict, wantFile: "state: blocked", wantRemote: "closed"},
+		{name: "prefer remote resolves conflict", local: "blocked", remote: "closed", prefer: PreferRemote, wantAction: ActionPulled, wantFile: "state: closed", wantRemote: "closed"},
+		{name: "prefer local resolves conflict", local: "blocked", remote: "closed", prefer: PreferLocal, wantAction: ActionPushed, wantFile: "state: blocked", wantRemote: "blocked"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := testutil.NewTestVault(t).
+				WithSchema(syncTestSchema).
+				WithRavenYAML(syncTestConfig).
+				Build()
+			adapter := &fakeAdapter{records: []Record{
+				{ID: "7", Fields: map[string]string{"title": "Fix login", "state": "open", "labels": ""}},
+			}}
+			runSyncForTest(t, v.Path, adapter, false, "")
+
+			// Simulate a local edit made since the last sync.
+			if tt.local != "open" {
+				content := v.ReadFile("issues/fix-login.md")
+				v.WriteFile("issues/fix-login.md", replaceOnce(t, content, "state: open", "state: "+tt.local))
+			}
+			adapter.records[0].Fields["state"] = tt.remote
+
+			result := runSyncForTest(t, v.Path, adapter, false, tt.prefer)
+			if got := result.Records[0].Action; got != tt.wantAction {
+				t.Fatalf("action = %q, want %q: %#v", got, tt.wantAction, result.Records[0])
+			}
+			v.AssertFileContains("issues/fix-login.md", tt.wantFile)
+			if got := adapter.records[0].Fields["state"]; got != tt.wantRemote {
+				t.Fatalf("remote state = %q, want %q", got, tt.wantRemote)
+			}
+		})
+	}
+}
+
+func TestRun_ReadOnlyRemoteFieldsAlwaysPull(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(syncTestSchema).
+		WithRavenYAML(syncTestConfig).
+		WithFile("issues/fix-login.md", "---\ntype: issue\ntitle: Fix login\nstate: blocked\nexternal_id: \"7\"\n---\n").
+		Build()
+	adapter := &fakeAdapter{
+		records:  []Record{{ID: "7", Fields: map[string]string{"title": "Fix login", "state": "closed", "labels": ""}}},
+		readOnly: map[string]bool{"state": true},
+	}
+
+	result := runSyncForTest(t, v.Path, adapter, false, "")
+	if got := result.Records[0].Action; got != ActionPulled {
+		t.Fatalf("action = %q, want pulled: %#v", got, result.Records[0])
+	}
+	if len(adapter.updates) != 0 {
+		t.Fatalf("updates = %#v, want none", adapter.updates)
+	}
+	v.AssertFileContains("issues/fix-login.md", "state: closed")
+}
+
+func TestRun_DryRunWritesNothing(t *testing.T) {
+	t.Parallel()
+
+	v := testutil.NewTestVault(t).
+		WithSchema(syncTestSchema).
+		WithRavenYAML(syncTestConfig).
+		Build()
+	adapter := &fakeAdapter{records: []Record{
+		{ID: "7", Fields: map[string]string{"title": "Fix login", "state": "open", "labels": ""}},
+	}}
+
+	result := runSyncForTest(t, v.Path, adapter, true, "")
+	if got := result.Count(ActionCreated); got != 1 {
+		t.Fatalf("created = %d, want 1", got)
+	}
+	v.AssertFileNotExists("issues/fix-login.md")
+	if _, err := os.Stat(filepath.Join(v.Path, ".raven", "sync", "tracker.json")); !os.IsNotExist(err) {
+		t.Fatalf("sync state should not be written on dry run, stat err = %v", err)
+	}
+}
+
+func TestRun_RejectsUnknownSyncName(t *testing.T) {
+	t.Parallel()
+
+	_, err := Run(RunRequest{VaultConfig: &config.VaultConfig{}, Name: "missing"})
+	svcErr, ok := AsError(err)
+	if !ok || svcErr.Code != CodeConfigInvalid {
+		t.Fatalf("err = %v, want %s", err, CodeConfigInvalid)
+	}
+}
+
+func replaceOnce(t *testing.T, content, old, replacement string) string {
+	t.Helper()
+	if !strings.Contains(content, old) {
+		t.Fatalf("%q not found in %q", old, content)
+	}
+	return strings.Replace(content, old, replacement, 1)
+}
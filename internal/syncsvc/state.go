@@ -0,0 +1,49 @@
+package syncsvc
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+)
+
+// syncState records the field values each record had after the last
+// successful sync. It is the common ancestor used to tell which side changed.
+type syncState struct {
+	Records map[string]map[string]string `json:"records"`
+}
+
+func statePath(vaultPath, name string) string {
+	return filepath.Join(vaultPath, ".raven", "sync", name+".json")
+}
+
+func loadState(vaultPath, name string) (*syncState, error) {
+	state := &syncState{Records: make(map[string]map[string]string)}
+	data, err := os.ReadFile(statePath(vaultPath, name))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Records == nil {
+		state.Records = make(map[string]map[string]string)
+	}
+	return state, nil
+}
+
+func saveState(vaultPath, name string, state *syncState) error {
+	path := statePath(vaultPath, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, append(data, '\n'), 0o644)
+}
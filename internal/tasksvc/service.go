@@ -0,0 +1,406 @@
+// Package tasksvc implements `rvn task`: an agenda of task traits and the
+// edits that complete, snooze, and schedule them.
+package tasksvc
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/codes"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/dates"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/model"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/traitsvc"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+type Code = codes.ErrorCode
+
+const (
+	CodeInvalidInput  Code = codes.ErrInvalidInput
+	CodeDatabaseError Code = codes.ErrDatabase
+	CodeFileReadError Code = codes.ErrFileRead
+)
+
+type Error struct {
+	Code       Code
+	Message    string
+	Suggestion string
+	Err        error
+}
+
+func (e *Error) Error() string {
+	if e == nil {
+		return ""
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return string(e.Code)
+}
+
+func (e *Error) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+func newError(code Code, message, suggestion string, err error) *Error {
+	return &Error{Code: code, Message: message, Suggestion: suggestion, Err: err}
+}
+
+func AsError(err error) (*Error, bool) {
+	var svcErr *Error
+	if errors.As(err, &svcErr) {
+		return svcErr, true
+	}
+	return nil, false
+}
+
+// Agenda group keys for tasks that are not grouped under their own date.
+const (
+	GroupOverdue = "overdue"
+	GroupNoDate  = "no_date"
+)
+
+// Task is one task trait with its due date.
+type Task struct {
+	ID         string `json:"id"`
+	FilePath   string `json:"file_path"`
+	Line       int    `json:"line"`
+	ObjectID   string `json:"object_id"`
+	Content    string `json:"content"`
+	Status     string `json:"status,omitempty"`
+	Due        string `json:"due,omitempty"`
+	Recurrence string `json:"recurrence,omitempty"`
+	Closed     bool   `json:"closed,omitempty"`
+}
+
+// Group is one agenda heading: overdue, a date, or no date.
+type Group struct {
+	Key   string `json:"key"`
+	Tasks []Task `json:"tasks"`
+}
+
+type ListRequest struct {
+	DB            *index.Database
+	Config        *config.TasksConfig
+	IncludeClosed bool
+	Today         time.Time
+}
+
+// List returns tasks grouped for an agenda: overdue open tasks first, then
+// one group per due date, then tasks without a due date.
+func List(req ListRequest) ([]Group, error) {
+	cfg := req.Config
+	tasks, err := req.DB.QueryTraits(cfg.Trait, nil)
+	if err != nil {
+		return nil, newError(CodeDatabaseError, "failed to query tasks", "Run 'rvn reindex' to rebuild the database", err)
+	}
+	dueTraits, err := req.DB.QueryTraits(cfg.DueTrait, nil)
+	if err != nil {
+		return nil, newError(CodeDatabaseError, "failed to query due dates", "Run 'rvn reindex' to rebuild the database", err)
+	}
+	dueByLine := make(map[string]model.Trait, len(dueTraits))
+	for _, due := range dueTraits {
+		key := lineKey(due.FilePath, due.Line)
+		if _, ok := dueByLine[key]; !ok {
+			dueByLine[key] = due
+		}
+	}
+
+	today := req.Today.Format(dates.DateLayout)
+	groups := map[string][]Task{}
+	for _, trait := range tasks {
+		task := Task{
+			ID:       trait.ID,
+			FilePath: trait.FilePath,
+			Line:     trait.Line,
+			ObjectID: trait.ParentObjectID,
+			Content:  trait.Content,
+		}
+		if trait.Value != nil {
+			task.Status = *trait.Value
+		}
+		task.Closed = task.Status == cfg.DoneValue || task.Status == cfg.CancelledValue
+		if task.Closed && !req.IncludeClosed {
+			continue
+		}
+		if due, ok := dueByLine[lineKey(trait.FilePath, trait.Line)]; ok && due.Value != nil {
+			task.Due, task.Recurrence = resolveDue(*due.Value, req.Today)
+		}
+
+		key := task.Due
+		switch {
+		case task.Due == "":
+			key = GroupNoDate
+		case task.Due < today && !task.Closed:
+			key = GroupOverdue
+		}
+		groups[key] = append(groups[key], task)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return groupRank(keys[i]) < groupRank(keys[j]) || (groupRank(keys[i]) == groupRank(keys[j]) && keys[i] < keys[j])
+	})
+
+	result := make([]Group, 0, len(keys))
+	for _, key := range keys {
+		items := groups[key]
+		sort.SliceStable(items, func(i, j int) bool {
+			if items[i].Due != items[j].Due {
+				return items[i].Due < items[j].Due
+			}
+			if items[i].FilePath != items[j].FilePath {
+				return items[i].FilePath < items[j].FilePath
+			}
+			return items[i].Line < items[j].Line
+		})
+		result = append(result, Group{Key: key, Tasks: items})
+	}
+	return result, nil
+}
+
+func groupRank(key string) int {
+	switch key {
+	case GroupOverdue:
+		return 0
+	case GroupNoDate:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func lineKey(filePath string, line int) string {
+	return fmt.Sprintf("%s:%d", filePath, line)
+}
+
+// resolveDue returns the due date a due trait value stands for and its
+// recurrence rule, if any. Unparseable values have no due date.
+func resolveDue(value string, today time.Time) (string, string) {
+	if dates.IsValidDate(value) {
+		return value, ""
+	}
+	if recurring, ok := dates.ParseRecurringDate(value); ok {
+		return recurring.Due(today).Format(dates.DateLayout), recurring.Rule
+	}
+	return "", ""
+}
+
+type EditRequest struct {
+	VaultPath    string
+	Schema       *schema.Schema
+	Config       *config.TasksConfig
+	Anchor       traitsvc.Anchor
+	ParseOptions *parser.ParseOptions
+	Preview      bool
+	Today        time.Time
+}
+
+// EditResult describes the trait a task edit rewrote.
+type EditResult struct {
+	Action string `json:"action"`
+	*traitsvc.SetResult
+}
+
+// Done marks a task done, or cancelled when cancel is set. A recurring task
+// that is completed stays open and moves to its next occurrence instead.
+func Done(req EditRequest, cancel bool) (*EditResult, error) {
+	line, err := loadTaskLine(req)
+	if err != nil {
+		return nil, err
+	}
+	cfg := req.Config
+
+	if !cancel && line.due != nil {
+		if recurring, ok := dates.ParseRecurringDate(line.due.ValueString()); ok {
+			next := recurring.WithDate(recurring.Next(recurring.Due(req.Today)))
+			result, err := setTrait(req, line.anchor, cfg.DueTrait, next.String())
+			if err != nil {
+				return nil, err
+			}
+			return &EditResult{Action: "rescheduled", SetResult: result}, nil
+		}
+	}
+
+	value, action := cfg.DoneValue, "done"
+	if cancel {
+		value, action = cfg.CancelledValue, "cancelled"
+	}
+	result, err := setTrait(req, line.anchor, cfg.Trait, value)
+	if err != nil {
+		return nil, err
+	}
+	return &EditResult{Action: action, SetResult: result}, nil
+}
+
+// Snooze pushes a task's due date back by offset (e.g. 1d, 2w), counting from
+// its due date or today, whichever is later. Recurring tasks keep their rule.
+func Snooze(req EditRequest, offset string) (*EditResult, error) {
+	line, err := loadTaskLine(req)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(offset, "+") && !strings.HasPrefix(offset, "-") {
+		offset = "+" + offset
+	}
+
+	base := req.Today
+	var recurring *dates.RecurringDate
+	if line.due != nil {
+		current := line.due.ValueString()
+		if r, ok := dates.ParseRecurringDate(current); ok {
+			recurring = &r
+			current = r.Due(req.Today).Format(dates.DateLayout)
+		}
+		if due, err := dates.ParseDate(current); err == nil && due.After(base) {
+			base = due
+		}
+	}
+	next, err := dates.ShiftDate(base, offset)
+	if err != nil {
+		return nil, newError(CodeInvalidInput, err.Error(), "Use a duration like 1d, 3d, or 1w", err)
+	}
+
+	value := next.Format(dates.DateLayout)
+	if recurring != nil {
+		value = recurring.WithDate(next).String()
+	}
+	result, err := setOrAddDue(req, line, value)
+	if err != nil {
+		return nil, err
+	}
+	return &EditResult{Action: "snoozed", SetResult: result}, nil
+}
+
+// Schedule sets a task's due date. when is a date (YYYY-MM-DD, today,
+// tomorrow, or a weekday name for its next occurrence) or a recurrence such
+// as every:friday.
+func Schedule(req EditRequest, when string) (*EditResult, error) {
+	value, err := ParseWhen(when, req.Today)
+	if err != nil {
+		return nil, err
+	}
+	line, err := loadTaskLine(req)
+	if err != nil {
+		return nil, err
+	}
+	result, err := setOrAddDue(req, line, value)
+	if err != nil {
+		return nil, err
+	}
+	return &EditResult{Action: "scheduled", SetResult: result}, nil
+}
+
+// ParseWhen resolves a schedule argument to the due trait value to write.
+// Weekday names mean the next such day after today; +N offsets count from
+// today.
+func ParseWhen(when string, today time.Time) (string, error) {
+	when = strings.ToLower(strings.TrimSpace(when))
+	if recurring, ok := dates.ParseRecurringDate(when); ok {
+		return recurring.String(), nil
+	}
+	if _, ok := dates.ParseWeekday(when); ok {
+		next := dates.RecurringDate{Rule: when}.Next(today)
+		return next.Format(dates.DateLayout), nil
+	}
+	if strings.HasPrefix(when, "+") {
+		shifted, err := dates.ShiftDate(today, when)
+		if err != nil {
+			return "", newError(CodeInvalidInput, err.Error(), "Use an offset like +1d, +2w, or +1m", err)
+		}
+		return shifted.Format(dates.DateLayout), nil
+	}
+	date, err := dates.ParseDateArg(when, today)
+	if err != nil {
+		return "", newError(CodeInvalidInput, fmt.Sprintf("invalid schedule %q", when), "Use YYYY-MM-DD, today, tomorrow, a weekday name, +Nd, or a recurrence like every:friday", err)
+	}
+	return date.Format(dates.DateLayout), nil
+}
+
+type taskLine struct {
+	anchor traitsvc.Anchor
+	due    *parser.TraitAnnotation
+}
+
+// loadTaskLine resolves the anchor to a line and checks that it holds a task.
+func loadTaskLine(req EditRequest) (*taskLine, error) {
+	anchor, err := traitsvc.ResolveLineAnchor(req.VaultPath, req.Schema, req.Anchor, req.ParseOptions)
+	if err != nil {
+		return nil, err
+	}
+	content, err := vaultcrypt.ReadFile(filepath.Join(req.VaultPath, anchor.FilePath))
+	if err != nil {
+		return nil, newError(CodeFileReadError, fmt.Sprintf("failed to read %s", anchor.FilePath), "Check the file path in the anchor", err)
+	}
+	lines := strings.Split(string(content), "\n")
+	if anchor.Line > len(lines) {
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("line %d is past the end of %s", anchor.Line, anchor.FilePath), "", nil)
+	}
+
+	line := &taskLine{anchor: anchor}
+	isTask := false
+	for _, annotation := range parser.ParseTraitAnnotations(lines[anchor.Line-1], anchor.Line) {
+		switch annotation.TraitName {
+		case req.Config.Trait:
+			isTask = true
+		case req.Config.DueTrait:
+			if line.due == nil {
+				annotation := annotation
+				line.due = &annotation
+			}
+		}
+	}
+	if !isTask {
+		return nil, newError(
+			CodeInvalidInput,
+			fmt.Sprintf("no @%s task on %s:%d", req.Config.Trait, anchor.FilePath, anchor.Line),
+			"Use the file:line or trait ID of a task from 'rvn task list'",
+			nil,
+		)
+	}
+	return line, nil
+}
+
+func setOrAddDue(req EditRequest, line *taskLine, value string) (*traitsvc.SetResult, error) {
+	if line.due != nil {
+		return setTrait(req, line.anchor, req.Config.DueTrait, value)
+	}
+	return traitsvc.AddAtAnchor(traitsvc.SetRequest{
+		VaultPath:    req.VaultPath,
+		Schema:       req.Schema,
+		Anchor:       line.anchor,
+		TraitType:    req.Config.DueTrait,
+		Value:        value,
+		ParseOptions: req.ParseOptions,
+		Preview:      req.Preview,
+	})
+}
+
+func setTrait(req EditRequest, anchor traitsvc.Anchor, traitType, value string) (*traitsvc.SetResult, error) {
+	return traitsvc.SetAtAnchor(traitsvc.SetRequest{
+		VaultPath:    req.VaultPath,
+		Schema:       req.Schema,
+		Anchor:       anchor,
+		TraitType:    traitType,
+		Value:        value,
+		ParseOptions: req.ParseOptions,
+		Preview:      req.Preview,
+	})
+}
@@ -0,0 +1,189 @@
+package tasksvc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/index"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/testutil"
+	"github.com/aidanlsb/raven/internal/traitsvc"
+)
+
+const taskSchema = `version: 2
+types: {}
+traits:
+  todo:
+    type: enum
+    values: [todo, done, cancelled]
+    default: todo
+  due:
+    type: date
+`
+
+const taskFile = `# Tasks
+
+- Write report @todo @due(2026-01-20)
+- Water plants @todo @due(every:friday)
+- Call home @todo
+- Ship release @todo(done) @due(2026-01-15)
+- Plan trip @todo @due(2026-02-02)
+`
+
+// today is Saturday 2026-01-31.
+var today = time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC)
+
+func TestListGroupsAgenda(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).WithSchema(taskSchema).WithFile("tasks.md", taskFile).Build()
+	vault.RunCLI("reindex").MustSucceed(t)
+
+	db, err := index.Open(vault.Path)
+	if err != nil {
+		t.Fatalf("open index: %v", err)
+	}
+	defer db.Close()
+
+	cfg := (&config.VaultConfig{}).GetTasksConfig()
+	groups, err := List(ListRequest{DB: db, Config: cfg, Today: today})
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+
+	var got []string
+	for _, group := range groups {
+		for _, task := range group.Tasks {
+			got = append(got, group.Key+"="+task.Content)
+		}
+	}
+	want := []string{
+		"overdue=Write report",
+		"2026-02-02=Plan trip",
+		"2026-02-06=Water plants",
+		"no_date=Call home",
+	}
+	if strings.Join(got, "; ") != strings.Join(want, "; ") {
+		t.Fatalf("agenda = %v, want %v", got, want)
+	}
+
+	groups, err = List(ListRequest{DB: db, Config: cfg, Today: today, IncludeClosed: true})
+	if err != nil {
+		t.Fatalf("List returned error: %v", err)
+	}
+	if groups[1].Key != "2026-01-15" || !groups[1].Tasks[0].Closed {
+		t.Fatalf("closed task group = %+v, want 2026-01-15 with the done task", groups[1])
+	}
+}
+
+func TestEditTasks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		line   int
+		apply  func(EditRequest) (*EditResult, error)
+		action string
+		want   string
+	}{
+		{
+			name:   "done",
+			line:   3,
+			apply:  func(req EditRequest) (*EditResult, error) { return Done(req, false) },
+			action: "done",
+			want:   "- Write report @todo(done) @due(2026-01-20)",
+		},
+		{
+			name:   "cancel",
+			line:   5,
+			apply:  func(req EditRequest) (*EditResult, error) { return Done(req, true) },
+			action: "cancelled",
+			want:   "- Call home @todo(cancelled)",
+		},
+		{
+			name:   "done recurring moves to next occurrence",
+			line:   4,
+			apply:  func(req EditRequest) (*EditResult, error) { return Done(req, false) },
+			action: "rescheduled",
+			want:   "- Water plants @todo @due(2026-02-13 every:friday)",
+		},
+		{
+			name:   "snooze overdue counts from today",
+			line:   3,
+			apply:  func(req EditRequest) (*EditResult, error) { return Snooze(req, "2d") },
+			action: "snoozed",
+			want:   "- Write report @todo @due(2026-02-02)",
+		},
+		{
+			name:   "snooze future counts from due date",
+			line:   7,
+			apply:  func(req EditRequest) (*EditResult, error) { return Snooze(req, "1w") },
+			action: "snoozed",
+			want:   "- Plan trip @todo @due(2026-02-09)",
+		},
+		{
+			name:   "schedule adds due date",
+			line:   5,
+			apply:  func(req EditRequest) (*EditResult, error) { return Schedule(req, "monday") },
+			action: "scheduled",
+			want:   "- Call home @todo @due(2026-02-02)",
+		},
+		{
+			name:   "schedule recurrence",
+			line:   3,
+			apply:  func(req EditRequest) (*EditResult, error) { return Schedule(req, "every:weekday") },
+			action: "scheduled",
+			want:   "- Write report @todo @due(every:weekday)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			vault := testutil.NewTestVault(t).WithSchema(taskSchema).WithFile("tasks.md", taskFile).Build()
+			sch, err := schema.Load(vault.Path)
+			if err != nil {
+				t.Fatalf("load schema: %v", err)
+			}
+			result, err := tt.apply(EditRequest{
+				VaultPath: vault.Path,
+				Schema:    sch,
+				Config:    (&config.VaultConfig{}).GetTasksConfig(),
+				Anchor:    traitsvc.Anchor{FilePath: "tasks.md", Line: tt.line},
+				Today:     today,
+			})
+			if err != nil {
+				t.Fatalf("edit returned error: %v", err)
+			}
+			if result.Action != tt.action {
+				t.Errorf("action = %q, want %q", result.Action, tt.action)
+			}
+			if got := strings.Split(vault.ReadFile("tasks.md"), "\n")[tt.line-1]; got != tt.want {
+				t.Errorf("line = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditRejectsNonTaskLine(t *testing.T) {
+	t.Parallel()
+
+	vault := testutil.NewTestVault(t).WithSchema(taskSchema).WithFile("tasks.md", taskFile).Build()
+	sch, err := schema.Load(vault.Path)
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	_, err = Done(EditRequest{
+		VaultPath: vault.Path,
+		Schema:    sch,
+		Config:    (&config.VaultConfig{}).GetTasksConfig(),
+		Anchor:    traitsvc.Anchor{FilePath: "tasks.md", Line: 1},
+		Today:     today,
+	}, false)
+	if svcErr, ok := AsError(err); !ok || svcErr.Code != CodeInvalidInput {
+		t.Fatalf("Done on a heading = %v, want invalid input error", err)
+	}
+}
@@ -0,0 +1,301 @@
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/dates"
+)
+
+// QueryItem is one result of a query run from a template. Object results set
+// ID, Type, and Fields; trait results set ID, Trait, Value, Content, and Line.
+type QueryItem struct {
+	ID      string
+	Type    string
+	Fields  map[string]interface{}
+	Trait   string
+	Value   string
+	Content string
+	Line    int
+}
+
+// QueryFunc runs a Raven query string or saved query name for {{query}}.
+type QueryFunc func(queryString string) ([]QueryItem, error)
+
+const (
+	escOpen  = "«RAVEN_ESC_OPEN»"
+	escClose = "«RAVEN_ESC_CLOSE»"
+)
+
+var (
+	fieldAccessPattern = regexp.MustCompile(`(^|[\s(|])field\.([A-Za-z_][A-Za-z0-9_-]*)`)
+	dateOffsetPattern  = regexp.MustCompile(`^(today|date)\s+([+-]?\d+[dwmy])$`)
+	bareFieldPattern   = regexp.MustCompile(`^field\.([A-Za-z_][A-Za-z0-9_-]*)$`)
+)
+
+// actionKeywords are text/template keywords that may start an action.
+var actionKeywords = map[string]bool{
+	"if": true, "else": true, "end": true, "range": true, "with": true,
+	"define": true, "template": true, "block": true, "break": true, "continue": true, "nil": true,
+}
+
+// builtinFuncs are text/template's predefined functions.
+var builtinFuncs = map[string]bool{
+	"and": true, "call": true, "html": true, "index": true, "slice": true, "js": true, "len": true,
+	"not": true, "or": true, "print": true, "printf": true, "println": true, "urlquery": true,
+	"eq": true, "ge": true, "gt": true, "le": true, "lt": true, "ne": true,
+}
+
+// Render executes a template body with Raven's template language, which is
+// text/template with these additions:
+//
+//   - {{title}}, {{date}}, {{today}}, {{field.name}}, and the other variables
+//     work as before; {{field.name}} is also usable in expressions, so
+//     {{if field.status}} and {{range field.attendees}} work
+//   - {{today +7d}} and {{date -1w}} shift a date by a d, w, m, or y offset;
+//     addDate, formatDate, link, join, and query are available as functions
+//
+// Actions that name nothing Raven knows, such as {{unknown}} or a missing
+// {{field.x}}, are left as written, and \{{ escapes a literal {{.
+func Render(content string, vars *Variables) (string, error) {
+	if content == "" || vars == nil {
+		return content, nil
+	}
+
+	funcs := vars.funcMap()
+	source := translateActions(content, funcs, vars)
+	tmpl, err := texttemplate.New("body").Funcs(funcs).Option("missingkey=zero").Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, nil); err != nil {
+		return "", fmt.Errorf("template failed: %w", err)
+	}
+
+	rendered := strings.ReplaceAll(out.String(), escOpen, "{{")
+	return strings.ReplaceAll(rendered, escClose, "}}"), nil
+}
+
+// checkSyntax parses content as a template without executing it.
+func checkSyntax(content string) error {
+	vars := &Variables{}
+	funcs := vars.funcMap()
+	if _, err := texttemplate.New("body").Funcs(funcs).Parse(translateActions(content, funcs, vars)); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	return nil
+}
+
+// translateActions rewrites Raven shorthand into text/template syntax and
+// escapes actions that should stay literal.
+func translateActions(content string, funcs texttemplate.FuncMap, vars *Variables) string {
+	content = strings.ReplaceAll(content, "\\{{", escOpen)
+	content = strings.ReplaceAll(content, "\\}}", escClose)
+
+	var out strings.Builder
+	for {
+		start := strings.Index(content, "{{")
+		if start < 0 {
+			out.WriteString(content)
+			break
+		}
+		end := strings.Index(content[start+2:], "}}")
+		if end < 0 {
+			out.WriteString(content)
+			break
+		}
+		end += start + 2
+		out.WriteString(content[:start])
+		out.WriteString(translateAction(content[start+2:end], funcs, vars))
+		content = content[end+2:]
+	}
+	return out.String()
+}
+
+func translateAction(body string, funcs texttemplate.FuncMap, vars *Variables) string {
+	inner := strings.TrimSpace(body)
+	leftTrim := strings.HasPrefix(inner, "- ")
+	rightTrim := strings.HasSuffix(inner, " -")
+	if leftTrim {
+		inner = strings.TrimSpace(inner[2:])
+	}
+	if rightTrim {
+		inner = strings.TrimSpace(inner[:len(inner)-2])
+	}
+
+	if match := bareFieldPattern.FindStringSubmatch(inner); match != nil && !vars.hasField(match[1]) {
+		return escOpen + body + escClose
+	}
+	if match := dateOffsetPattern.FindStringSubmatch(inner); match != nil {
+		inner = fmt.Sprintf("addDate %s %q", match[1], match[2])
+	}
+	inner = fieldAccessPattern.ReplaceAllString(inner, `$1(field "$2")`)
+	if !startsWithKnownName(inner, funcs) {
+		return escOpen + body + escClose
+	}
+
+	action := "{{"
+	if leftTrim {
+		action += "- "
+	}
+	action += inner
+	if rightTrim {
+		action += " -"
+	}
+	return action + "}}"
+}
+
+// startsWithKnownName reports whether an action begins with something
+// text/template can evaluate: a keyword, a known function, a variable, a
+// field, a literal, or a parenthesized pipeline.
+func startsWithKnownName(action string, funcs texttemplate.FuncMap) bool {
+	if action == "" {
+		return false
+	}
+	switch action[0] {
+	case '.', '$', '(', '"', '`', '\'', '/':
+		return true
+	}
+	if action[0] >= '0' && action[0] <= '9' || action[0] == '-' {
+		return true
+	}
+	name := action
+	if idx := strings.IndexAny(action, " \t\n|)"); idx >= 0 {
+		name = action[:idx]
+	}
+	if actionKeywords[name] || builtinFuncs[name] || name == "true" || name == "false" {
+		return true
+	}
+	_, ok := funcs[name]
+	return ok
+}
+
+func (v *Variables) hasField(name string) bool {
+	if _, ok := v.Values[name]; ok {
+		return true
+	}
+	_, ok := v.Fields[name]
+	return ok
+}
+
+func (v *Variables) funcMap() texttemplate.FuncMap {
+	today := v.Today
+	if today == "" {
+		today = time.Now().Format(dates.DateLayout)
+	}
+	constant := func(value string) func() string {
+		return func() string { return value }
+	}
+	return texttemplate.FuncMap{
+		"title":    constant(v.Title),
+		"slug":     constant(v.Slug),
+		"type":     constant(v.Type),
+		"date":     constant(v.Date),
+		"datetime": constant(v.Datetime),
+		"year":     constant(v.Year),
+		"month":    constant(v.Month),
+		"day":      constant(v.Day),
+		"weekday":  constant(v.Weekday),
+		"today":    constant(today),
+		"field": func(name string) interface{} {
+			if value, ok := v.Values[name]; ok {
+				return value
+			}
+			return v.Fields[name]
+		},
+		"addDate":    addDate,
+		"formatDate": formatDate,
+		"link":       link,
+		"join":       join,
+		"query": func(queryString string) ([]QueryItem, error) {
+			if v.Query == nil {
+				return nil, fmt.Errorf("queries are not available in this template")
+			}
+			return v.Query(queryString)
+		},
+	}
+}
+
+// addDate shifts a YYYY-MM-DD date by an offset such as +7d or -1m.
+func addDate(date, offset string) (string, error) {
+	t, err := dates.ParseDate(date)
+	if err != nil {
+		return "", err
+	}
+	shifted, err := dates.ShiftDate(t, offset)
+	if err != nil {
+		return "", err
+	}
+	return shifted.Format(dates.DateLayout), nil
+}
+
+// dateFormatTokens maps formatDate tokens to Go layout elements, longest
+// first so MMMM is replaced before MM.
+var dateFormatTokens = []struct{ token, layout string }{
+	{"YYYY", "2006"},
+	{"MMMM", "January"},
+	{"MMM", "Jan"},
+	{"MM", "01"},
+	{"M", "1"},
+	{"dddd", "Monday"},
+	{"ddd", "Mon"},
+	{"DD", "02"},
+	{"D", "2"},
+}
+
+// formatDate formats a YYYY-MM-DD date using the tokens YYYY, MMMM (January),
+// MMM (Jan), MM, M, dddd (Monday), ddd (Mon), DD, and D.
+func formatDate(date, format string) (string, error) {
+	t, err := dates.ParseDate(date)
+	if err != nil {
+		return "", err
+	}
+	// Each token is formatted on its own and everything else is copied
+	// as-is, so literal text such as "Q1" is never read as a Go layout.
+	var out strings.Builder
+	for i := 0; i < len(format); {
+		matched := false
+		for _, tok := range dateFormatTokens {
+			if strings.HasPrefix(format[i:], tok.token) {
+				out.WriteString(t.Format(tok.layout))
+				i += len(tok.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			out.WriteByte(format[i])
+			i++
+		}
+	}
+	return out.String(), nil
+}
+
+// link formats a ref value as a wikilink, accepting "id" or "[[id]]".
+func link(value interface{}) string {
+	id := strings.TrimSpace(fmt.Sprint(value))
+	id = strings.TrimSuffix(strings.TrimPrefix(id, "[["), "]]")
+	return "[[" + id + "]]"
+}
+
+// join joins a list field's values with sep.
+func join(values interface{}, sep string) string {
+	switch list := values.(type) {
+	case []string:
+		return strings.Join(list, sep)
+	case []interface{}:
+		parts := make([]string, 0, len(list))
+		for _, item := range list {
+			parts = append(parts, fmt.Sprint(item))
+		}
+		return strings.Join(parts, sep)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(list)
+	}
+}
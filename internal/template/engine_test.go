@@ -0,0 +1,127 @@
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender_EngineFeatures(t *testing.T) {
+	t.Parallel()
+	vars := &Variables{
+		Title:   "Kickoff",
+		Type:    "meeting",
+		Date:    "2026-01-31",
+		Today:   "2026-01-31",
+		Weekday: "Saturday",
+		Fields:  map[string]string{"status": "active"},
+		Values: map[string]interface{}{
+			"attendees": []interface{}{"[[people/freya]]", "people/thor"},
+			"tags":      []interface{}{"planning", "q1"},
+			"notes":     "",
+		},
+		Query: func(queryString string) ([]QueryItem, error) {
+			if queryString != "trait:todo" {
+				t.Fatalf("query = %q, want trait:todo", queryString)
+			}
+			return []QueryItem{{ID: "projects/site.md:trait:0", Trait: "todo", Content: "write copy"}}, nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "conditional on field",
+			template: "{{if eq field.status \"active\"}}Active{{else}}Idle{{end}}",
+			expected: "Active",
+		},
+		{
+			name:     "empty field is false",
+			template: "{{if field.notes}}notes{{else}}none{{end}}",
+			expected: "none",
+		},
+		{
+			name:     "loop over ref list",
+			template: "{{range field.attendees}}- {{link .}}\n{{end}}",
+			expected: "- [[people/freya]]\n- [[people/thor]]\n",
+		},
+		{
+			name:     "join list",
+			template: "Tags: {{join field.tags \", \"}}",
+			expected: "Tags: planning, q1",
+		},
+		{
+			name:     "date offsets",
+			template: "Due {{today +7d}}, last month {{date -1m}}, next month {{date 1m}}",
+			expected: "Due 2026-02-07, last month 2025-12-31, next month 2026-02-28",
+		},
+		{
+			name:     "formatted date",
+			template: "{{formatDate (addDate today \"+1d\") \"dddd, MMMM D YYYY\"}}",
+			expected: "Sunday, February 1 2026",
+		},
+		{
+			name:     "formatted date keeps literal text",
+			template: "{{formatDate today \"Q1 YYYY, week of MMM D (0405 PST)\"}}",
+			expected: "Q1 2026, week of Jan 31 (0405 PST)",
+		},
+		{
+			name:     "query results",
+			template: "{{range query \"trait:todo\"}}- [ ] {{.Content}}{{end}}",
+			expected: "- [ ] write copy",
+		},
+		{
+			name:     "trim markers",
+			template: "# {{title}}\n{{- if field.status}} ({{field.status}}){{end}}",
+			expected: "# Kickoff (active)",
+		},
+		{
+			name:     "unknown actions stay literal",
+			template: "{{unknown}} {{args.project}} {{time:HH:mm}} {{field.missing}}",
+			expected: "{{unknown}} {{args.project}} {{time:HH:mm}} {{field.missing}}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Render(tt.template, vars)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Render() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	t.Parallel()
+	vars := &Variables{Today: "2026-01-31"}
+
+	for _, tmpl := range []string{
+		"{{if title}}unterminated",
+		"{{today +7x}}",
+		"{{range query \"trait:todo\"}}{{end}}",
+	} {
+		if _, err := Render(tmpl, vars); err == nil {
+			t.Errorf("Render(%q) succeeded, want error", tmpl)
+		}
+	}
+
+	if got := Apply("{{if title}}unterminated", vars); !strings.Contains(got, "unterminated") {
+		t.Errorf("Apply() = %q, want content returned unchanged on error", got)
+	}
+}
+
+func TestValidateContent_RejectsTemplateSyntaxErrors(t *testing.T) {
+	t.Parallel()
+	if err := ValidateContent("{{range field.attendees}}- {{.}}"); err == nil {
+		t.Fatal("ValidateContent() succeeded for an unterminated range, want error")
+	}
+	if err := ValidateContent("# {{title}}\n{{if field.status}}{{field.status}}{{end}}\n{{unknown}}"); err != nil {
+		t.Fatalf("ValidateContent() error = %v, want valid template", err)
+	}
+}
@@ -34,6 +34,13 @@ type Variables struct {
 	Weekday string
 	// Fields are field values from --field flags
 	Fields map[string]string
+	// Values are typed field values, taking precedence over Fields: lists
+	// (such as ref[] fields) are []interface{} so templates can range over them
+	Values map[string]interface{}
+	// Today is the current date (YYYY-MM-DD); empty means the real date
+	Today string
+	// Query runs queries for {{query}}; nil disables it
+	Query QueryFunc
 }
 
 // NewVariables creates a Variables struct with the given title, type, and fields.
@@ -89,8 +96,16 @@ func Load(vaultPath, templateSpec, templateDir string) (string, error) {
 	return loadFromFile(vaultPath, fileRef)
 }
 
-// ValidateContent enforces that templates contain body content only.
+// ValidateContent enforces that templates contain body content only and
+// that their template actions parse.
 func ValidateContent(content string) error {
+	if err := validateNoFrontmatter(content); err != nil {
+		return err
+	}
+	return checkSyntax(content)
+}
+
+func validateNoFrontmatter(content string) error {
 	lines := strings.Split(content, "\n")
 	if len(lines) == 0 {
 		return nil
@@ -169,45 +184,12 @@ func loadFromFile(vaultPath, templatePath string) (string, error) {
 // Apply substitutes template variables in the content.
 // Variables use {{name}} syntax. Unknown variables are left as-is.
 // Escaped variables \{{name}} are converted to literal {{name}}.
+// Content that fails to render is returned unchanged; use Render to see
+// the error.
 func Apply(content string, vars *Variables) string {
-	if content == "" || vars == nil {
+	rendered, err := Render(content, vars)
+	if err != nil {
 		return content
 	}
-
-	// First, temporarily replace escaped sequences
-	// Use safe placeholder strings instead of null bytes to avoid editor issues
-	content = strings.ReplaceAll(content, "\\{{", "«RAVEN_ESC_OPEN»")
-	content = strings.ReplaceAll(content, "\\}}", "«RAVEN_ESC_CLOSE»")
-
-	// Build replacement map
-	replacements := map[string]string{
-		"{{title}}":    vars.Title,
-		"{{slug}}":     vars.Slug,
-		"{{type}}":     vars.Type,
-		"{{date}}":     vars.Date,
-		"{{datetime}}": vars.Datetime,
-		"{{year}}":     vars.Year,
-		"{{month}}":    vars.Month,
-		"{{day}}":      vars.Day,
-		"{{weekday}}":  vars.Weekday,
-	}
-
-	// Apply standard variable replacements
-	for placeholder, value := range replacements {
-		content = strings.ReplaceAll(content, placeholder, value)
-	}
-
-	// Apply field variables: {{field.X}}
-	if vars.Fields != nil {
-		for fieldName, fieldValue := range vars.Fields {
-			placeholder := "{{field." + fieldName + "}}"
-			content = strings.ReplaceAll(content, placeholder, fieldValue)
-		}
-	}
-
-	// Restore escaped sequences as literals
-	content = strings.ReplaceAll(content, "«RAVEN_ESC_OPEN»", "{{")
-	content = strings.ReplaceAll(content, "«RAVEN_ESC_CLOSE»", "}}")
-
-	return content
+	return rendered
 }
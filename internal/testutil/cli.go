@@ -46,8 +46,17 @@ type CLIWarning struct {
 
 // CLIMeta contains metadata from the response.
 type CLIMeta struct {
-	Count       int   `json:"count,omitempty"`
-	QueryTimeMs int64 `json:"query_time_ms,omitempty"`
+	Count       int           `json:"count,omitempty"`
+	QueryTimeMs int64         `json:"query_time_ms,omitempty"`
+	Freshness   *CLIFreshness `json:"freshness,omitempty"`
+}
+
+// CLIFreshness represents index freshness metadata from read commands.
+type CLIFreshness struct {
+	Stale      bool     `json:"stale"`
+	StaleCount int      `json:"stale_count,omitempty"`
+	StaleFiles []string `json:"stale_files,omitempty"`
+	Refreshed  int      `json:"refreshed,omitempty"`
 }
 
 // BuildCLI builds the rvn binary and returns its path.
@@ -0,0 +1,188 @@
+// Package textmerge performs line-based three-way merges.
+//
+// Each side is diffed against the common base. Changes to different lines
+// of the base are combined; changes to the same lines merge only when both
+// sides made the identical change, and otherwise conflict.
+package textmerge
+
+import "strings"
+
+// maxDiffCells bounds the LCS table for the differing middle of two
+// versions. Larger differences are reported as conflicts rather than merged.
+const maxDiffCells = 4_000_000
+
+// hunk replaces base[start:end] with lines.
+type hunk struct {
+	start, end int
+	lines      []string
+}
+
+// Merge combines the changes ours and theirs each made to base. It returns
+// the merged text and true, or "" and false when the changes conflict.
+func Merge(base, ours, theirs string) (string, bool) {
+	switch {
+	case ours == theirs || theirs == base:
+		return ours, true
+	case ours == base:
+		return theirs, true
+	}
+
+	baseLines := splitLines(base)
+	ourHunks, ok := diff(baseLines, splitLines(ours))
+	if !ok {
+		return "", false
+	}
+	theirHunks, ok := diff(baseLines, splitLines(theirs))
+	if !ok {
+		return "", false
+	}
+
+	var out strings.Builder
+	pos, i, j := 0, 0, 0
+	for i < len(ourHunks) || j < len(theirHunks) {
+		// Start a region at the earliest remaining hunk and grow it while
+		// hunks from either side touch it.
+		var lo, hi int
+		var ourRegion, theirRegion []hunk
+		if j >= len(theirHunks) || (i < len(ourHunks) && ourHunks[i].start <= theirHunks[j].start) {
+			lo, hi = ourHunks[i].start, ourHunks[i].end
+			ourRegion = append(ourRegion, ourHunks[i])
+			i++
+		} else {
+			lo, hi = theirHunks[j].start, theirHunks[j].end
+			theirRegion = append(theirRegion, theirHunks[j])
+			j++
+		}
+		for grown := true; grown; {
+			grown = false
+			if i < len(ourHunks) && touches(ourHunks[i], lo, hi) {
+				hi = max(hi, ourHunks[i].end)
+				ourRegion = append(ourRegion, ourHunks[i])
+				i++
+				grown = true
+			}
+			if j < len(theirHunks) && touches(theirHunks[j], lo, hi) {
+				hi = max(hi, theirHunks[j].end)
+				theirRegion = append(theirRegion, theirHunks[j])
+				j++
+				grown = true
+			}
+		}
+
+		out.WriteString(strings.Join(baseLines[pos:lo], ""))
+		switch {
+		case len(theirRegion) == 0:
+			out.WriteString(apply(baseLines, lo, hi, ourRegion))
+		case len(ourRegion) == 0:
+			out.WriteString(apply(baseLines, lo, hi, theirRegion))
+		default:
+			ourText, theirText := apply(baseLines, lo, hi, ourRegion), apply(baseLines, lo, hi, theirRegion)
+			if ourText != theirText {
+				return "", false
+			}
+			out.WriteString(ourText)
+		}
+		pos = hi
+	}
+	out.WriteString(strings.Join(baseLines[pos:], ""))
+	return out.String(), true
+}
+
+// touches reports whether h overlaps the region base[lo:hi]. Replacements
+// of neighbouring lines do not overlap, but an insertion at either edge of
+// the region does, since the order of the two changes would be ambiguous.
+func touches(h hunk, lo, hi int) bool {
+	return h.start < hi || (h.start == hi && (h.start == h.end || lo == hi))
+}
+
+// apply renders base[lo:hi] with hunks (all within the range) applied.
+func apply(base []string, lo, hi int, hunks []hunk) string {
+	var out strings.Builder
+	pos := lo
+	for _, h := range hunks {
+		out.WriteString(strings.Join(base[pos:h.start], ""))
+		out.WriteString(strings.Join(h.lines, ""))
+		pos = h.end
+	}
+	out.WriteString(strings.Join(base[pos:hi], ""))
+	return out.String()
+}
+
+// splitLines splits text into lines that keep their line endings, so
+// joining them restores the text exactly.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diff returns the hunks turning a into b, from a longest common
+// subsequence of lines. It reports false when the differing middle is too
+// large to diff.
+func diff(a, b []string) ([]hunk, bool) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	am, bm := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(am) == 0 && len(bm) == 0 {
+		return nil, true
+	}
+	if (len(am)+1)*(len(bm)+1) > maxDiffCells {
+		return nil, false
+	}
+
+	// lcs[x][y] is the LCS length of am[x:] and bm[y:].
+	width := len(bm) + 1
+	lcs := make([]int32, (len(am)+1)*width)
+	for x := len(am) - 1; x >= 0; x-- {
+		for y := len(bm) - 1; y >= 0; y-- {
+			if am[x] == bm[y] {
+				lcs[x*width+y] = lcs[(x+1)*width+y+1] + 1
+			} else {
+				lcs[x*width+y] = max(lcs[(x+1)*width+y], lcs[x*width+y+1])
+			}
+		}
+	}
+
+	var hunks []hunk
+	var current *hunk
+	flush := func() {
+		if current != nil {
+			hunks = append(hunks, *current)
+			current = nil
+		}
+	}
+	x, y := 0, 0
+	for x < len(am) || y < len(bm) {
+		switch {
+		case x < len(am) && y < len(bm) && am[x] == bm[y]:
+			flush()
+			x++
+			y++
+		case y < len(bm) && (x == len(am) || lcs[x*width+y+1] >= lcs[(x+1)*width+y]):
+			if current == nil {
+				current = &hunk{start: prefix + x, end: prefix + x}
+			}
+			current.lines = append(current.lines, bm[y])
+			y++
+		default:
+			if current == nil {
+				current = &hunk{start: prefix + x, end: prefix + x}
+			}
+			x++
+			current.end = prefix + x
+		}
+	}
+	flush()
+	return hunks, true
+}
@@ -0,0 +1,62 @@
+package textmerge
+
+import "testing"
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	base := "---\ntype: project\nstatus: active\nowner: freya\n---\n# Raven\n\nNotes.\n"
+	tests := []struct {
+		name   string
+		ours   string
+		theirs string
+		want   string
+		ok     bool
+	}{
+		{
+			name:   "changes to different lines combine",
+			ours:   "---\ntype: project\nstatus: done\nowner: freya\n---\n# Raven\n\nNotes.\n",
+			theirs: "---\ntype: project\nstatus: active\nowner: freya\n---\n# Raven\n\nNotes.\nMore notes.\n",
+			want:   "---\ntype: project\nstatus: done\nowner: freya\n---\n# Raven\n\nNotes.\nMore notes.\n",
+			ok:     true,
+		},
+		{
+			name:   "neighbouring line replacements combine",
+			ours:   "---\ntype: project\nstatus: done\nowner: freya\n---\n# Raven\n\nNotes.\n",
+			theirs: "---\ntype: project\nstatus: active\nowner: wren\n---\n# Raven\n\nNotes.\n",
+			want:   "---\ntype: project\nstatus: done\nowner: wren\n---\n# Raven\n\nNotes.\n",
+			ok:     true,
+		},
+		{
+			name:   "identical changes merge once",
+			ours:   "---\ntype: project\nstatus: done\nowner: freya\n---\n# Raven\n\nNotes.\n",
+			theirs: "---\ntype: project\nstatus: done\nowner: freya\n---\n# Raven\n\nNotes.\nMore.\n",
+			want:   "---\ntype: project\nstatus: done\nowner: freya\n---\n# Raven\n\nNotes.\nMore.\n",
+			ok:     true,
+		},
+		{
+			name:   "different changes to the same line conflict",
+			ours:   "---\ntype: project\nstatus: done\nowner: freya\n---\n# Raven\n\nNotes.\n",
+			theirs: "---\ntype: project\nstatus: paused\nowner: freya\n---\n# Raven\n\nNotes.\n",
+			ok:     false,
+		},
+		{
+			name:   "insertions at the same point conflict",
+			ours:   base + "- ours\n",
+			theirs: base + "- theirs\n",
+			ok:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := Merge(base, tt.ours, tt.theirs)
+			if ok != tt.ok {
+				t.Fatalf("Merge() ok = %v, want %v (merged %q)", ok, tt.ok, got)
+			}
+			if ok && got != tt.want {
+				t.Fatalf("Merge() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
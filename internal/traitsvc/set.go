@@ -0,0 +1,268 @@
+package traitsvc
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/parser"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+// Anchor addresses a single trait annotation, either by trait ID
+// (path/file.md:trait:N) or by file and line (path/file.md:12).
+type Anchor struct {
+	FilePath string
+	Line     int
+	TraitID  string
+	Ordinal  int
+}
+
+// ParseAnchor parses a trait ID or a file:line anchor. File paths are
+// vault-relative; the .md extension is optional.
+func ParseAnchor(raw string) (Anchor, error) {
+	raw = strings.TrimSpace(raw)
+	if filePath, ordinal, ok := strings.Cut(raw, ":trait:"); ok {
+		n, err := strconv.Atoi(ordinal)
+		if err != nil || n < 0 || strings.TrimSpace(filePath) == "" {
+			return Anchor{}, newError(CodeInvalidInput, fmt.Sprintf("invalid trait ID: %s", raw), "Trait IDs look like: path/file.md:trait:N", nil, nil)
+		}
+		return Anchor{FilePath: paths.NormalizeVaultRelPath(filePath), TraitID: raw, Ordinal: n}, nil
+	}
+
+	idx := strings.LastIndex(raw, ":")
+	if idx <= 0 {
+		return Anchor{}, newError(CodeInvalidInput, fmt.Sprintf("invalid trait anchor: %s", raw), "Use path/file.md:LINE or a trait ID (path/file.md:trait:N)", nil, nil)
+	}
+	line, err := strconv.Atoi(raw[idx+1:])
+	if err != nil || line < 1 {
+		return Anchor{}, newError(CodeInvalidInput, fmt.Sprintf("invalid line number in anchor: %s", raw), "Line numbers start at 1", nil, nil)
+	}
+	return Anchor{FilePath: paths.EnsureMDExtension(paths.NormalizeVaultRelPath(raw[:idx])), Line: line}, nil
+}
+
+type SetRequest struct {
+	VaultPath    string
+	Schema       *schema.Schema
+	Anchor       Anchor
+	TraitType    string // Required when a line holds more than one trait
+	Value        string
+	ParseOptions *parser.ParseOptions
+	Preview      bool
+}
+
+type SetResult struct {
+	FilePath  string `json:"file_path"`
+	Line      int    `json:"line"`
+	TraitType string `json:"trait_type"`
+	OldValue  string `json:"old_value"`
+	NewValue  string `json:"new_value"`
+	Before    string `json:"before"`
+	After     string `json:"after"`
+	Changed   bool   `json:"changed"`
+
+	ChangedFilePath string `json:"-"`
+}
+
+// SetAtAnchor rewrites the value of one trait annotation in place. Only the
+// annotation itself changes; the rest of the line is preserved.
+func SetAtAnchor(req SetRequest) (*SetResult, error) {
+	fullPath, content, err := readAnchorFile(req.VaultPath, req.Anchor)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(content, "\n")
+
+	lineNumber, traitType, nth := req.Anchor.Line, strings.TrimPrefix(strings.TrimSpace(req.TraitType), "@"), 0
+	if req.Anchor.TraitID != "" {
+		lineNumber, traitType, nth, err = locateTraitID(content, req.VaultPath, req.Schema, req.Anchor, req.ParseOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if lineNumber > len(lines) {
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("line %d is past the end of %s", lineNumber, req.Anchor.FilePath), "", nil, nil)
+	}
+
+	line := lines[lineNumber-1]
+	annotation, err := pickAnnotation(line, lineNumber, traitType, nth, req.Anchor.TraitID != "", req.Anchor.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	newValue, err := resolvedAndValidatedTraitValue(req.Value, annotation.TraitName, req.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	// StartOffset includes the delimiter before '@', which stays in place.
+	start := annotation.StartOffset + strings.IndexByte(line[annotation.StartOffset:annotation.EndOffset], '@')
+	newLine := line[:start] + fmt.Sprintf("@%s(%s)", annotation.TraitName, newValue) + line[annotation.EndOffset:]
+
+	result := &SetResult{
+		FilePath:  req.Anchor.FilePath,
+		Line:      lineNumber,
+		TraitType: annotation.TraitName,
+		OldValue:  annotation.ValueString(),
+		NewValue:  newValue,
+		Before:    line,
+		After:     newLine,
+		Changed:   newLine != line,
+	}
+	if !result.Changed || req.Preview {
+		return result, nil
+	}
+
+	lines[lineNumber-1] = newLine
+	if err := atomicfile.WriteFile(fullPath, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		return nil, newError(CodeFileWriteError, "failed to write file", "", nil, err)
+	}
+	result.ChangedFilePath = fullPath
+	return result, nil
+}
+
+// ResolveLineAnchor turns a trait ID anchor into a file:line anchor for the
+// line the trait is on. File:line anchors are returned unchanged.
+func ResolveLineAnchor(vaultPath string, sch *schema.Schema, anchor Anchor, opts *parser.ParseOptions) (Anchor, error) {
+	if anchor.TraitID == "" {
+		return anchor, nil
+	}
+	_, content, err := readAnchorFile(vaultPath, anchor)
+	if err != nil {
+		return Anchor{}, err
+	}
+	line, _, _, err := locateTraitID(content, vaultPath, sch, anchor, opts)
+	if err != nil {
+		return Anchor{}, err
+	}
+	return Anchor{FilePath: anchor.FilePath, Line: line}, nil
+}
+
+// AddAtAnchor appends a trait annotation to the end of a file:line anchor's
+// line.
+func AddAtAnchor(req SetRequest) (*SetResult, error) {
+	fullPath, content, err := readAnchorFile(req.VaultPath, req.Anchor)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(content, "\n")
+	if req.Anchor.Line < 1 || req.Anchor.Line > len(lines) {
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("line %d is past the end of %s", req.Anchor.Line, req.Anchor.FilePath), "", nil, nil)
+	}
+
+	traitType := strings.TrimPrefix(strings.TrimSpace(req.TraitType), "@")
+	newValue, err := resolvedAndValidatedTraitValue(req.Value, traitType, req.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	line := lines[req.Anchor.Line-1]
+	newLine := strings.TrimRight(line, " \t") + fmt.Sprintf(" @%s(%s)", traitType, newValue)
+	result := &SetResult{
+		FilePath:  req.Anchor.FilePath,
+		Line:      req.Anchor.Line,
+		TraitType: traitType,
+		NewValue:  newValue,
+		Before:    line,
+		After:     newLine,
+		Changed:   true,
+	}
+	if req.Preview {
+		return result, nil
+	}
+
+	lines[req.Anchor.Line-1] = newLine
+	if err := atomicfile.WriteFile(fullPath, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		return nil, newError(CodeFileWriteError, "failed to write file", "", nil, err)
+	}
+	result.ChangedFilePath = fullPath
+	return result, nil
+}
+
+func readAnchorFile(vaultPath string, anchor Anchor) (string, string, error) {
+	fullPath := filepath.Join(vaultPath, anchor.FilePath)
+	if err := paths.ValidateWithinVault(vaultPath, fullPath); err != nil {
+		return "", "", newError(CodeValidation, "trait file is outside the vault", "", nil, err)
+	}
+	content, err := vaultcrypt.ReadFile(fullPath)
+	if err != nil {
+		return "", "", newError(CodeFileReadError, fmt.Sprintf("failed to read %s", anchor.FilePath), "Check the file path in the anchor", nil, err)
+	}
+	return fullPath, string(content), nil
+}
+
+// locateTraitID finds the line, trait type, and position among same-type
+// traits on that line for a trait ID. IDs number the schema-defined traits
+// in document order, matching the index.
+func locateTraitID(content, vaultPath string, sch *schema.Schema, anchor Anchor, opts *parser.ParseOptions) (int, string, int, error) {
+	doc, err := parser.ParseDocumentWithOptions(content, anchor.FilePath, vaultPath, opts)
+	if err != nil {
+		return 0, "", 0, newError(CodeValidation, "failed to parse file", "", nil, err)
+	}
+
+	var defined []*parser.ParsedTrait
+	for _, trait := range doc.Traits {
+		if sch != nil {
+			if _, ok := sch.Traits[trait.TraitType]; !ok {
+				continue
+			}
+		}
+		defined = append(defined, trait)
+	}
+	if anchor.Ordinal >= len(defined) {
+		return 0, "", 0, newError(
+			CodeInvalidInput,
+			fmt.Sprintf("trait not found: %s", anchor.TraitID),
+			"Trait IDs shift when a file changes; re-run the query or use path/file.md:LINE",
+			nil,
+			nil,
+		)
+	}
+
+	target := defined[anchor.Ordinal]
+	nth := 0
+	for _, trait := range defined[:anchor.Ordinal] {
+		if trait.Line == target.Line && trait.TraitType == target.TraitType {
+			nth++
+		}
+	}
+	return target.Line, target.TraitType, nth, nil
+}
+
+// pickAnnotation returns the nth matching annotation on a line. A file:line
+// anchor must match exactly one annotation.
+func pickAnnotation(line string, lineNumber int, traitType string, nth int, byID bool, filePath string) (*parser.TraitAnnotation, error) {
+	var candidates []parser.TraitAnnotation
+	for _, annotation := range parser.ParseTraitAnnotations(line, lineNumber) {
+		if traitType == "" || annotation.TraitName == traitType {
+			candidates = append(candidates, annotation)
+		}
+	}
+
+	location := fmt.Sprintf("%s:%d", filePath, lineNumber)
+	switch {
+	case len(candidates) == 0 && traitType != "":
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("no @%s trait on %s", traitType, location), "", nil, nil)
+	case len(candidates) == 0:
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("no trait on %s", location), "", nil, nil)
+	case !byID && len(candidates) > 1:
+		names := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			names = append(names, "@"+candidate.TraitName)
+		}
+		return nil, newError(
+			CodeInvalidInput,
+			fmt.Sprintf("%s has %d traits: %s", location, len(candidates), strings.Join(names, ", ")),
+			"Pass --trait to choose one, or use the trait ID from query output",
+			map[string]interface{}{"traits": names},
+			nil,
+		)
+	case nth >= len(candidates):
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("trait not found on %s", location), "", nil, nil)
+	}
+	return &candidates[nth], nil
+}
@@ -0,0 +1,130 @@
+package traitsvc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/schema"
+)
+
+func TestParseAnchor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		raw     string
+		want    Anchor
+		wantErr bool
+	}{
+		{raw: "notes/tasks.md:7", want: Anchor{FilePath: "notes/tasks.md", Line: 7}},
+		{raw: "notes/tasks:7", want: Anchor{FilePath: "notes/tasks.md", Line: 7}},
+		{raw: "notes/tasks.md:trait:2", want: Anchor{FilePath: "notes/tasks.md", TraitID: "notes/tasks.md:trait:2", Ordinal: 2}},
+		{raw: "notes/tasks.md", wantErr: true},
+		{raw: "notes/tasks.md:0", wantErr: true},
+		{raw: "notes/tasks.md:trait:x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAnchor(tt.raw)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseAnchor(%q) = %+v, want error", tt.raw, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseAnchor(%q) = %+v, %v; want %+v", tt.raw, got, err, tt.want)
+		}
+	}
+}
+
+func TestSetAtAnchorRewritesOnlyTheAddressedAnnotation(t *testing.T) {
+	t.Parallel()
+
+	sch := &schema.Schema{Traits: map[string]*schema.TraitDefinition{
+		"due":      {Type: schema.FieldTypeDate},
+		"due_date": {Type: schema.FieldTypeString},
+		"priority": {Type: schema.FieldTypeString},
+	}}
+	const content = "# Tasks\n\n- @due(2026-01-01) a @due(2026-01-02) @due_date(x) `@due(y)`\n- @priority(low) b\n"
+
+	tests := []struct {
+		name      string
+		anchor    string
+		traitType string
+		value     string
+		wantLine  string
+		wantErr   bool
+	}{
+		{
+			name:     "file and line with a single trait",
+			anchor:   "notes/tasks.md:4",
+			value:    "high",
+			wantLine: "- @priority(high) b",
+		},
+		{
+			name:    "file and line with several traits needs --trait",
+			anchor:  "notes/tasks.md:3",
+			value:   "2026-03-01",
+			wantErr: true,
+		},
+		{
+			name:      "file and line with repeated trait stays ambiguous",
+			anchor:    "notes/tasks.md:3",
+			traitType: "due",
+			value:     "2026-03-01",
+			wantErr:   true,
+		},
+		{
+			name:      "file and line picks the named trait",
+			anchor:    "notes/tasks.md:3",
+			traitType: "due_date",
+			value:     "y",
+			wantLine:  "- @due(2026-01-01) a @due(2026-01-02) @due_date(y) `@due(y)`",
+		},
+		{
+			name:     "trait ID picks the second same-type annotation",
+			anchor:   "notes/tasks.md:trait:1",
+			value:    "2026-03-01",
+			wantLine: "- @due(2026-01-01) a @due(2026-03-01) @due_date(x) `@due(y)`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			vaultPath := t.TempDir()
+			filePath := filepath.Join(vaultPath, "notes", "tasks.md")
+			if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+				t.Fatalf("mkdir: %v", err)
+			}
+			if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			anchor, err := ParseAnchor(tt.anchor)
+			if err != nil {
+				t.Fatalf("ParseAnchor(%q): %v", tt.anchor, err)
+			}
+
+			result, err := SetAtAnchor(SetRequest{VaultPath: vaultPath, Schema: sch, Anchor: anchor, TraitType: tt.traitType, Value: tt.value})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("SetAtAnchor() = %+v, want error", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetAtAnchor() error = %v", err)
+			}
+			if result.After != tt.wantLine {
+				t.Errorf("After = %q, want %q", result.After, tt.wantLine)
+			}
+			written, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if got := strings.Split(string(written), "\n")[result.Line-1]; got != tt.wantLine {
+				t.Errorf("written line = %q, want %q", got, tt.wantLine)
+			}
+		})
+	}
+}
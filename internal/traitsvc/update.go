@@ -3,7 +3,6 @@ package traitsvc
 import (
 	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -16,6 +15,7 @@ import (
 	"github.com/aidanlsb/raven/internal/model"
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/schema"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 type Code = codes.ErrorCode
@@ -208,7 +208,7 @@ func ApplyUpdates(vaultPath string, traits []model.Trait, newValue string, sch *
 			fullPath = filepath.Join(vaultPath, filePath)
 		}
 
-		content, readErr := os.ReadFile(fullPath)
+		content, readErr := vaultcrypt.ReadFile(fullPath)
 		if readErr != nil {
 			for _, t := range fileTraits {
 				results = append(results, BulkResult{ID: t.ID, FilePath: t.FilePath, Line: t.Line, Status: "error", Reason: fmt.Sprintf("failed to read file: %v", readErr)})
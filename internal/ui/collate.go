@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+var (
+	collatorOnce sync.Once
+	collatorMu   sync.Mutex
+	collator     *collate.Collator
+)
+
+// CompareNames compares two display names using the collation rules of the
+// user's locale (LC_ALL, LC_COLLATE, then LANG), so accented and mixed-case
+// names sort the way a reader expects and embedded numbers sort numerically.
+func CompareNames(a, b string) int {
+	collatorOnce.Do(func() {
+		collator = collate.New(localeTag(), collate.IgnoreCase, collate.Numeric)
+	})
+	collatorMu.Lock()
+	defer collatorMu.Unlock()
+	return collator.CompareString(a, b)
+}
+
+// localeTag returns the language tag for the POSIX locale in the environment,
+// falling back to the root collation order.
+func localeTag() language.Tag {
+	for _, key := range []string{"LC_ALL", "LC_COLLATE", "LANG"} {
+		value := strings.TrimSpace(os.Getenv(key))
+		if value == "" {
+			continue
+		}
+		tag, ok := parsePOSIXLocale(value)
+		if !ok {
+			return language.Und
+		}
+		return tag
+	}
+	return language.Und
+}
+
+// parsePOSIXLocale parses values like "sv_SE.UTF-8" or "de_DE@euro".
+// "C" and "POSIX" map to the root collation.
+func parsePOSIXLocale(value string) (language.Tag, bool) {
+	if i := strings.IndexAny(value, ".@"); i >= 0 {
+		value = value[:i]
+	}
+	if value == "" || value == "C" || value == "POSIX" {
+		return language.Und, false
+	}
+	tag, err := language.Parse(strings.ReplaceAll(value, "_", "-"))
+	if err != nil {
+		return language.Und, false
+	}
+	return tag, true
+}
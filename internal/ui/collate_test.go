@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"sort"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestCompareNamesOrdersNaturally(t *testing.T) {
+	names := []string{"Zoë", "émile", "Item 10", "alice", "Item 2", "Bob"}
+	sort.SliceStable(names, func(i, j int) bool { return CompareNames(names[i], names[j]) < 0 })
+
+	want := []string{"alice", "Bob", "émile", "Item 2", "Item 10", "Zoë"}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("sorted = %v, want %v", names, want)
+		}
+	}
+}
+
+func TestParsePOSIXLocale(t *testing.T) {
+	tests := []struct {
+		input string
+		want  language.Tag
+		ok    bool
+	}{
+		{input: "sv_SE.UTF-8", want: language.MustParse("sv-SE"), ok: true},
+		{input: "de_DE@euro", want: language.MustParse("de-DE"), ok: true},
+		{input: "en", want: language.English, ok: true},
+		{input: "C", want: language.Und, ok: false},
+		{input: "POSIX", want: language.Und, ok: false},
+		{input: "C.UTF-8", want: language.Und, ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := parsePOSIXLocale(tt.input)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parsePOSIXLocale(%q) = %v, %v; want %v, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
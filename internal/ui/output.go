@@ -28,7 +28,7 @@ const (
 
 // Check returns a message with checkmark symbol (for explicit action success)
 func Check(msg string) string {
-	return fmt.Sprintf("%s %s", SymbolCheck, msg)
+	return fmt.Sprintf("%s %s", Success.Render(SymbolCheck), msg)
 }
 
 // Checkf returns a formatted message with checkmark symbol
@@ -48,7 +48,7 @@ func Starf(format string, args ...interface{}) string {
 
 // Error returns an error message with X symbol
 func Error(msg string) string {
-	return fmt.Sprintf("%s %s", SymbolError, msg)
+	return fmt.Sprintf("%s %s", Danger.Render(SymbolError), msg)
 }
 
 // Errorf returns a formatted error message with X symbol
@@ -58,7 +58,7 @@ func Errorf(format string, args ...interface{}) string {
 
 // Warning returns a warning message with warning symbol
 func Warning(msg string) string {
-	return fmt.Sprintf("%s %s", SymbolWarning, msg)
+	return fmt.Sprintf("%s %s", Warn.Render(SymbolWarning), msg)
 }
 
 // Warningf returns a formatted warning message with warning symbol
@@ -66,6 +66,15 @@ func Warningf(format string, args ...interface{}) string {
 	return Warning(fmt.Sprintf(format, args...))
 }
 
+// IssueSymbol returns the styled symbol for an issue level.
+// "warning" maps to the warning symbol; anything else is treated as an error.
+func IssueSymbol(level string) string {
+	if level == "warning" {
+		return Warn.Render(SymbolWarning)
+	}
+	return Danger.Render(SymbolError)
+}
+
 // Info returns an info message with info symbol
 func Info(msg string) string {
 	return fmt.Sprintf("%s %s", SymbolInfo, msg)
@@ -91,6 +100,17 @@ func FilePath(path string) string {
 	return Bold.Render(path)
 }
 
+// Hyperlink wraps text in an OSC 8 hyperlink to url.
+// Callers decide whether the terminal should receive links.
+func Hyperlink(url, text string) string {
+	return fmt.Sprintf("\x1b]8;;%s\x07%s\x1b]8;;\x07", url, text)
+}
+
+// Stat returns a "label: value" pair with a muted label and bold value.
+func Stat(label string, value interface{}) string {
+	return Muted.Render(label+": ") + Bold.Render(fmt.Sprint(value))
+}
+
 // Hint returns muted hint text
 func Hint(msg string) string {
 	return Muted.Render(msg)
@@ -43,6 +43,7 @@ type ResultsTable struct {
 	columns []ColumnDef
 	headers []string
 	rows    []ResultRow
+	wrap    bool
 }
 
 // Standard column definitions shared across retrieval types.
@@ -178,6 +179,17 @@ func ObjectLayout(fieldNames []string) []ColumnDef {
 	return columns
 }
 
+// SelectLayout returns the layout for projected query output:
+// [num, id, selected columns...].
+func SelectLayout(columnNames []string) []ColumnDef {
+	columns := make([]ColumnDef, 0, len(columnNames)+2)
+	columns = append(columns, colNum(), colObjectName())
+	for _, name := range columnNames {
+		columns = append(columns, colObjectField(name))
+	}
+	return columns
+}
+
 // NewResultsTable creates a new ResultsTable with the given display context and column layout.
 func NewResultsTable(display *DisplayContext, columns []ColumnDef) *ResultsTable {
 	return &ResultsTable{
@@ -197,6 +209,11 @@ func (t *ResultsTable) SetHeaders(headers []string) {
 	t.headers = headers
 }
 
+// SetWrap makes cells wrap within their column instead of being truncated.
+func (t *ResultsTable) SetWrap(wrap bool) {
+	t.wrap = wrap
+}
+
 // ContentWidth returns the calculated width for a specific column by name.
 // This allows callers to prepare content (e.g., snippet extraction) based on actual available width.
 func (t *ResultsTable) ContentWidth(columnName string) int {
@@ -431,6 +448,10 @@ func (t *ResultsTable) Render() string {
 	for _, row := range rowCells {
 		tableRow := make([]string, len(t.columns))
 		for j := range t.columns {
+			if t.wrap {
+				tableRow[j] = row[j]
+				continue
+			}
 			tableRow[j] = truncateCell(row[j], widths[j])
 		}
 		tableRows = append(tableRows, tableRow)
@@ -528,6 +549,17 @@ func TruncateWithEllipsis(s string, maxLen int) string {
 	return truncated + "..."
 }
 
+// TruncateField shortens a field value for single-line display: runs of
+// whitespace (including newlines) collapse to one space, and the result is
+// truncated with an ellipsis when maxLen > 0.
+func TruncateField(s string, maxLen int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if maxLen <= 0 {
+		return s
+	}
+	return TruncateWithEllipsis(s, maxLen)
+}
+
 // WrapTextTwoLines wraps text into at most two lines, with the second line truncated.
 func WrapTextTwoLines(text string, maxLen int) (line1, line2 string) {
 	runes := []rune(text)
@@ -18,6 +18,17 @@ func TestTruncateWithEllipsisPreservesUTF8(t *testing.T) {
 	}
 }
 
+func TestTruncateFieldCollapsesWhitespace(t *testing.T) {
+	t.Parallel()
+
+	if got := TruncateField("first line\n  second line", 0); got != "first line second line" {
+		t.Fatalf("expected whitespace collapsed without truncation, got %q", got)
+	}
+	if got := TruncateField("alpha beta gamma delta", 15); got != "alpha beta..." {
+		t.Fatalf("expected word-boundary truncation, got %q", got)
+	}
+}
+
 func TestWrapTextTwoLinesPreservesUTF8(t *testing.T) {
 	t.Parallel()
 
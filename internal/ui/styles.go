@@ -9,13 +9,13 @@ import (
 )
 
 // Minimal color palette with focused semantic accents.
-// Optional accent color can be configured via [ui].accent.
-// Uses ANSI colors for terminal theme compatibility.
+// Optional accent color can be configured via [ui].accent and status colors
+// via [ui.colors]. Uses ANSI colors for terminal theme compatibility.
 //
 // - Default: Primary text (terminal foreground)
 // - Muted (8 = Bright Black/Gray): Secondary info, hints, line numbers
 // - Bold: Emphasis, highlights
-// - No colored success/error/warning - use unicode symbols only
+// - Success/Warn/Danger (2/3/1): status symbols only; message text stays plain
 
 var (
 	// Muted style for secondary info, hints, line numbers
@@ -35,9 +35,32 @@ var (
 	// SyntaxSubtle style for supporting syntax values.
 	SyntaxSubtle = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
 
+	// Success style for success symbols.
+	Success = lipgloss.NewStyle().Foreground(lipgloss.Color(defaultSuccessColor))
+
+	// Warn style for warning symbols.
+	Warn = lipgloss.NewStyle().Foreground(lipgloss.Color(defaultWarningColor))
+
+	// Danger style for error symbols.
+	Danger = lipgloss.NewStyle().Foreground(lipgloss.Color(defaultErrorColor))
+
 	accentColor string
 )
 
+const (
+	defaultSuccessColor = "2"
+	defaultWarningColor = "3"
+	defaultErrorColor   = "1"
+)
+
+// Palette holds the configurable status colors. Empty values use the defaults;
+// "none", "off", and "default" render the status without color.
+type Palette struct {
+	Success string
+	Warning string
+	Error   string
+}
+
 // ConfigureTheme configures optional UI theme colors from config.
 // Supported accent values:
 //   - ANSI codes: "0" to "255"
@@ -73,6 +96,20 @@ func ConfigureTheme(accent string) {
 	SyntaxSubtle = lipgloss.NewStyle().Foreground(lipgloss.Color(normalized))
 }
 
+// ConfigurePalette configures the status colors from config.
+// Values use the same formats as ConfigureTheme's accent.
+func ConfigurePalette(p Palette) {
+	if NoColorEnabled() {
+		Success = lipgloss.NewStyle()
+		Warn = lipgloss.NewStyle()
+		Danger = lipgloss.NewStyle()
+		return
+	}
+	Success = statusStyle(p.Success, defaultSuccessColor)
+	Warn = statusStyle(p.Warning, defaultWarningColor)
+	Danger = statusStyle(p.Error, defaultErrorColor)
+}
+
 // AccentColor returns the currently configured accent color, if any.
 func AccentColor() (string, bool) {
 	if accentColor == "" {
@@ -147,3 +184,14 @@ func syntaxStyle() lipgloss.Style {
 func syntaxSubtleStyle() lipgloss.Style {
 	return lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
 }
+
+func statusStyle(raw, fallback string) lipgloss.Style {
+	if strings.TrimSpace(raw) == "" {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(fallback))
+	}
+	normalized, ok := normalizeAccentColor(raw)
+	if !ok {
+		return lipgloss.NewStyle()
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(normalized))
+}
@@ -1,6 +1,10 @@
 package ui
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 func TestNormalizeAccentColor(t *testing.T) {
 	tests := []struct {
@@ -106,3 +110,53 @@ func TestConfigureThemeHonorsNoColor(t *testing.T) {
 		t.Fatalf("expected configured accent color to be ignored when NO_COLOR is set")
 	}
 }
+
+func TestConfigurePalette(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	origSuccess, origWarn, origDanger := Success, Warn, Danger
+	t.Cleanup(func() {
+		Success, Warn, Danger = origSuccess, origWarn, origDanger
+	})
+
+	ConfigurePalette(Palette{Success: "#0f0", Warning: "", Error: "none"})
+
+	if got := Success.GetForeground(); got != lipgloss.Color("#00ff00") {
+		t.Fatalf("expected configured success color, got %v", got)
+	}
+	if got := Warn.GetForeground(); got != lipgloss.Color(defaultWarningColor) {
+		t.Fatalf("expected default warning color, got %v", got)
+	}
+	if _, ok := Danger.GetForeground().(lipgloss.NoColor); !ok {
+		t.Fatalf("expected error color to be disabled, got %v", Danger.GetForeground())
+	}
+}
+
+func TestConfigurePaletteHonorsNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	origSuccess, origWarn, origDanger := Success, Warn, Danger
+	t.Cleanup(func() {
+		Success, Warn, Danger = origSuccess, origWarn, origDanger
+	})
+
+	ConfigurePalette(Palette{Success: "2", Warning: "3", Error: "1"})
+
+	if got := Error("failed"); got != SymbolError+" failed" {
+		t.Fatalf("expected plain error output when NO_COLOR is set, got %q", got)
+	}
+	if got := IssueSymbol("warning"); got != SymbolWarning {
+		t.Fatalf("expected plain warning symbol when NO_COLOR is set, got %q", got)
+	}
+}
+
+func TestHyperlink(t *testing.T) {
+	got := Hyperlink("file:///vault/notes/a.md", "notes/a.md")
+	want := "\x1b]8;;file:///vault/notes/a.md\x07notes/a.md\x1b]8;;\x07"
+	if got != want {
+		t.Fatalf("Hyperlink() = %q, want %q", got, want)
+	}
+	if VisibleLen(got) != len("notes/a.md") {
+		t.Fatalf("expected hyperlink escapes to have zero visible width, got %d", VisibleLen(got))
+	}
+}
@@ -0,0 +1,47 @@
+package vault
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// GitFileCreatedTimes returns the Unix time of the commit that first added each
+// file under vaultPath, keyed by vault-relative slash path. It returns nil when
+// the vault is not inside a git work tree or git is unavailable.
+func GitFileCreatedTimes(vaultPath string) map[string]int64 {
+	cmd := exec.Command("git", "log", "--diff-filter=A", "--no-renames", "--relative", "--name-only", "-z", "--format=@%at", "--", ".")
+	cmd.Dir = vaultPath
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	return parseGitCreatedLog(string(out))
+}
+
+// parseGitCreatedLog parses `git log --name-only -z --format=@%at` output.
+// Commits are listed newest first, so later entries overwrite earlier ones and
+// each path ends up with its oldest add time.
+func parseGitCreatedLog(out string) map[string]int64 {
+	created := make(map[string]int64)
+	var commitTime int64
+	for _, token := range strings.Split(out, "\x00") {
+		token = strings.TrimPrefix(token, "\n")
+		if token == "" {
+			continue
+		}
+		if strings.HasPrefix(token, "@") {
+			ts, err := strconv.ParseInt(token[1:], 10, 64)
+			if err != nil {
+				commitTime = 0
+				continue
+			}
+			commitTime = ts
+			continue
+		}
+		if commitTime > 0 {
+			created[token] = commitTime
+		}
+	}
+	return created
+}
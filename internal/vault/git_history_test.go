@@ -0,0 +1,22 @@
+package vault
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGitCreatedLog(t *testing.T) {
+	t.Parallel()
+
+	// Newest commit first, as emitted by git log.
+	out := "@300\x00\nnotes/new.md\x00@200\x00\nnotes/readded.md\x00people/ü b.md\x00@100\x00\nnotes/readded.md\x00"
+	got := parseGitCreatedLog(out)
+	want := map[string]int64{
+		"notes/new.md":     300,
+		"people/ü b.md":    200,
+		"notes/readded.md": 100,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseGitCreatedLog() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,28 @@
+package vault
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/aidanlsb/raven/internal/config"
+)
+
+// ResolveIdentity returns the name used for attribution metadata.
+// It prefers identity.name from the global config and falls back to
+// `git config user.name` evaluated in the vault directory. Returns "" when
+// no identity is configured.
+func ResolveIdentity(cfg *config.Config, vaultPath string) string {
+	if cfg != nil {
+		if name := strings.TrimSpace(cfg.Identity.Name); name != "" {
+			return name
+		}
+	}
+
+	cmd := exec.Command("git", "config", "user.name")
+	cmd.Dir = vaultPath
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
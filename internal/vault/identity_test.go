@@ -0,0 +1,16 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+)
+
+func TestResolveIdentityPrefersConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Identity: config.IdentityConfig{Name: "  Ada Lovelace "}}
+	if got := ResolveIdentity(cfg, t.TempDir()); got != "Ada Lovelace" {
+		t.Fatalf("ResolveIdentity() = %q, want %q", got, "Ada Lovelace")
+	}
+}
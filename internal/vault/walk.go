@@ -13,6 +13,7 @@ import (
 	"github.com/aidanlsb/raven/internal/pages"
 	"github.com/aidanlsb/raven/internal/parser"
 	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
 )
 
 // WalkResult contains the result of processing a markdown file.
@@ -108,7 +109,7 @@ func WalkMarkdownFilesWithOptions(vaultPath string, opts *WalkOptions, handler f
 		fileMtime := info.ModTime().Unix()
 
 		// Read file
-		content, err := os.ReadFile(path)
+		content, err := vaultcrypt.ReadFile(path)
 		if err != nil {
 			return handler(WalkResult{
 				Path:         path,
@@ -0,0 +1,233 @@
+package vaultconfigsvc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/paths"
+	"github.com/aidanlsb/raven/internal/secrets"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+type EncryptDirectoryRequest struct {
+	VaultPath string
+	Directory string
+	Confirm   bool
+}
+
+type DecryptDirectoryRequest struct {
+	VaultPath string
+	Directory string
+	Confirm   bool
+}
+
+// EncryptionChangeResult reports the files an encrypt or decrypt rewrites.
+// Without Confirm nothing is written and Files lists what would change.
+type EncryptionChangeResult struct {
+	ConfigPath           string
+	Directory            string
+	Files                []string
+	Applied              bool
+	EncryptedDirectories []string
+	IndexEncrypted       bool
+}
+
+// EncryptDirectory adds a directory to encryption.directories and encrypts
+// the markdown files already in it. Running it again for a listed directory
+// encrypts files that were added there as plaintext outside Raven.
+func EncryptDirectory(req EncryptDirectoryRequest) (*EncryptionChangeResult, error) {
+	cfg, _, configPath, err := load(req.VaultPath)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := normalizeEncryptedDirectory(req.VaultPath, req.Directory)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEncryptionKey(cfg); err != nil {
+		return nil, err
+	}
+
+	files, err := encryptionCandidates(req.VaultPath, dir, false)
+	if err != nil {
+		return nil, err
+	}
+	dirs := normalizedEncryptedDirectories(cfg.GetEncryptedDirectories())
+	if !slices.Contains(dirs, dir) {
+		dirs = append(dirs, dir)
+		sort.Strings(dirs)
+	}
+	result := &EncryptionChangeResult{
+		ConfigPath:           configPath,
+		Directory:            dir,
+		Files:                files,
+		EncryptedDirectories: dirs,
+		IndexEncrypted:       cfg.IsIndexEncrypted(),
+	}
+	if !req.Confirm {
+		return result, nil
+	}
+
+	if cfg.Encryption == nil {
+		cfg.Encryption = &config.EncryptionConfig{}
+	}
+	cfg.Encryption.Directories = dirs
+	if cfg.Encryption.Salt == "" {
+		salt, err := secrets.NewSalt()
+		if err != nil {
+			return nil, newError(CodeFileWriteError, "failed to generate encryption salt", "", err)
+		}
+		cfg.Encryption.Salt = hex.EncodeToString(salt)
+	}
+	if err := config.SaveVaultConfig(req.VaultPath, cfg); err != nil {
+		return nil, newError(CodeFileWriteError, "failed to save vault config", "", err)
+	}
+
+	// atomicfile encrypts each rewrite now that the directory is listed.
+	for _, file := range files {
+		if err := rewriteVaultFile(req.VaultPath, file); err != nil {
+			return nil, err
+		}
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// DecryptDirectory removes a directory from encryption.directories and
+// rewrites its encrypted files as plaintext. The key settings and salt stay
+// in raven.yaml for the remaining encrypted directories.
+func DecryptDirectory(req DecryptDirectoryRequest) (*EncryptionChangeResult, error) {
+	cfg, _, configPath, err := load(req.VaultPath)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := normalizeEncryptedDirectory(req.VaultPath, req.Directory)
+	if err != nil {
+		return nil, err
+	}
+	dirs := normalizedEncryptedDirectories(cfg.GetEncryptedDirectories())
+	if !slices.Contains(dirs, dir) {
+		return nil, newError(CodeNotFound, fmt.Sprintf("directory '%s' is not encrypted", dir), "Run 'rvn vault config show' to see encrypted directories", nil)
+	}
+	if err := checkEncryptionKey(cfg); err != nil {
+		return nil, err
+	}
+
+	files, err := encryptionCandidates(req.VaultPath, dir, true)
+	if err != nil {
+		return nil, err
+	}
+	dirs = slices.DeleteFunc(dirs, func(existing string) bool { return existing == dir })
+	result := &EncryptionChangeResult{
+		ConfigPath:           configPath,
+		Directory:            dir,
+		Files:                files,
+		EncryptedDirectories: dirs,
+		IndexEncrypted:       cfg.IsIndexEncrypted(),
+	}
+	if !req.Confirm {
+		return result, nil
+	}
+
+	cfg.Encryption.Directories = dirs
+	if err := config.SaveVaultConfig(req.VaultPath, cfg); err != nil {
+		return nil, newError(CodeFileWriteError, "failed to save vault config", "", err)
+	}
+	for _, file := range files {
+		if err := rewriteVaultFile(req.VaultPath, file); err != nil {
+			return nil, err
+		}
+	}
+	result.Applied = true
+	return result, nil
+}
+
+func normalizeEncryptedDirectory(vaultPath, raw string) (string, error) {
+	dir := vaultcrypt.NormalizeDirectory(paths.NormalizeVaultRelPath(raw))
+	if dir == "" || !paths.IsValidVaultRelPath(dir) {
+		return "", newError(CodeInvalidInput, fmt.Sprintf("invalid directory: %q", raw), "Use a vault-relative directory such as 'people/private/'", nil)
+	}
+	if info, err := os.Stat(filepath.Join(vaultPath, filepath.FromSlash(dir))); err == nil && !info.IsDir() {
+		return "", newError(CodeInvalidInput, fmt.Sprintf("'%s' is not a directory", dir), "Encrypt a directory, not a single file", nil)
+	}
+	return dir, nil
+}
+
+func normalizedEncryptedDirectories(dirs []string) []string {
+	out := make([]string, 0, len(dirs))
+	for _, raw := range dirs {
+		if dir := vaultcrypt.NormalizeDirectory(raw); dir != "" && !slices.Contains(out, dir) {
+			out = append(out, dir)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// checkEncryptionKey fails early when no passphrase is available, before any
+// file is rewritten.
+func checkEncryptionKey(cfg *config.VaultConfig) error {
+	settings := vaultcrypt.Settings{}
+	if cfg.Encryption != nil {
+		settings.KeyEnv = cfg.Encryption.KeyEnv
+	}
+	if _, err := settings.Passphrase(); err != nil {
+		return newError(CodeConfigInvalid, err.Error(), "Export the passphrase in "+config.DefaultEncryptionKeyEnv+" (or encryption.key_env), or add a command for it under [key_commands] in config.toml", err)
+	}
+	return nil
+}
+
+// encryptionCandidates lists the markdown files under dir that are currently
+// encrypted (encrypted=true) or still plaintext.
+func encryptionCandidates(vaultPath, dir string, encrypted bool) ([]string, error) {
+	root := filepath.Join(vaultPath, filepath.FromSlash(dir))
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !paths.HasMDExtension(path) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if vaultcrypt.IsEncrypted(data) == encrypted {
+			rel, err := filepath.Rel(vaultPath, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, newError(CodeInvalidInput, fmt.Sprintf("failed to read %s", dir), "", err)
+	}
+	return files, nil
+}
+
+// rewriteVaultFile reads a file as plaintext and writes it back, letting
+// atomicfile encrypt it or not according to the current raven.yaml.
+func rewriteVaultFile(vaultPath, rel string) error {
+	path := filepath.Join(vaultPath, filepath.FromSlash(rel))
+	content, err := vaultcrypt.ReadFile(path)
+	if err != nil {
+		return newError(CodeFileWriteError, fmt.Sprintf("failed to read %s", rel), "", err)
+	}
+	if err := atomicfile.WriteFile(path, content, 0); err != nil {
+		return newError(CodeFileWriteError, fmt.Sprintf("failed to write %s", rel), "", err)
+	}
+	return nil
+}
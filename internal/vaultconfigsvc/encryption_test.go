@@ -0,0 +1,84 @@
+package vaultconfigsvc
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/vaultcrypt"
+)
+
+func TestEncryptAndDecryptDirectory(t *testing.T) {
+	t.Setenv(config.DefaultEncryptionKeyEnv, "hunter2")
+
+	tmp := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmp, "raven.yaml"), []byte("auto_reindex: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	note := filepath.Join(tmp, "people", "private", "freya.md")
+	if err := os.MkdirAll(filepath.Dir(note), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(note, []byte("# Freya\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	preview, err := EncryptDirectory(EncryptDirectoryRequest{VaultPath: tmp, Directory: "./people/private"})
+	if err != nil {
+		t.Fatalf("EncryptDirectory() preview error = %v", err)
+	}
+	if preview.Applied || preview.Directory != "people/private/" || !reflect.DeepEqual(preview.Files, []string{"people/private/freya.md"}) {
+		t.Fatalf("unexpected preview: %#v", preview)
+	}
+
+	if _, err := EncryptDirectory(EncryptDirectoryRequest{VaultPath: tmp, Directory: "people/private", Confirm: true}); err != nil {
+		t.Fatalf("EncryptDirectory() error = %v", err)
+	}
+	raw, err := os.ReadFile(note)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !vaultcrypt.IsEncrypted(raw) {
+		t.Fatalf("expected %s to be encrypted on disk", note)
+	}
+	cfg, err := config.LoadVaultConfig(tmp)
+	if err != nil {
+		t.Fatalf("LoadVaultConfig() error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg.GetEncryptedDirectories(), []string{"people/private/"}) || cfg.Encryption.Salt == "" {
+		t.Fatalf("unexpected encryption config: %#v", cfg.Encryption)
+	}
+
+	decrypted, err := DecryptDirectory(DecryptDirectoryRequest{VaultPath: tmp, Directory: "people/private/", Confirm: true})
+	if err != nil {
+		t.Fatalf("DecryptDirectory() error = %v", err)
+	}
+	if len(decrypted.EncryptedDirectories) != 0 {
+		t.Fatalf("expected no encrypted directories, got %#v", decrypted.EncryptedDirectories)
+	}
+	raw, err = os.ReadFile(note)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != "# Freya\n" {
+		t.Fatalf("expected plaintext after decrypt, got %q", raw)
+	}
+
+	_, err = DecryptDirectory(DecryptDirectoryRequest{VaultPath: tmp, Directory: "people/private/"})
+	svcErr, ok := AsError(err)
+	if !ok || svcErr.Code != CodeNotFound {
+		t.Fatalf("expected CodeNotFound for unencrypted directory, got %v", err)
+	}
+}
+
+func TestEncryptDirectoryRequiresKey(t *testing.T) {
+	t.Setenv(config.DefaultEncryptionKeyEnv, "")
+
+	_, err := EncryptDirectory(EncryptDirectoryRequest{VaultPath: t.TempDir(), Directory: "private/", Confirm: true})
+	svcErr, ok := AsError(err)
+	if !ok || svcErr.Code != CodeConfigInvalid {
+		t.Fatalf("expected CodeConfigInvalid without a key, got %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -21,6 +22,7 @@ const (
 	CodeConfigInvalid  Code = codes.ErrConfigInvalid
 	CodeFileWriteError Code = codes.ErrFileWrite
 	CodePrefixNotFound Code = codes.ErrPrefixNotFound
+	CodeNotFound       Code = codes.ErrNotFound
 )
 
 type Error struct {
@@ -80,6 +82,9 @@ type ShowResult struct {
 	ProtectedPrefixesUsed bool
 	Exclude               []string
 	ExcludeUsed           bool
+	SparsePaths           []string
+	AbsentSparsePaths     []string
+	LockedFiles           []string
 }
 
 type DirectoriesInfo struct {
@@ -277,6 +282,31 @@ type RemoveProtectedPrefixResult struct {
 	ProtectedPrefixes []string
 }
 
+type LockFileRequest struct {
+	VaultPath string
+	Path      string
+}
+
+type LockFileResult struct {
+	ConfigPath  string
+	Created     bool
+	Changed     bool
+	Path        string
+	LockedFiles []string
+}
+
+type UnlockFileRequest struct {
+	VaultPath string
+	Path      string
+}
+
+type UnlockFileResult struct {
+	ConfigPath  string
+	Changed     bool
+	Removed     string
+	LockedFiles []string
+}
+
 type ListExcludeRequest struct {
 	VaultPath string
 }
@@ -337,6 +367,9 @@ func Show(req ShowRequest) (*ShowResult, error) {
 		ProtectedPrefixesUsed: len(protected) > 0,
 		Exclude:               exclude,
 		ExcludeUsed:           len(exclude) > 0,
+		SparsePaths:           cfg.GetSparsePaths(),
+		AbsentSparsePaths:     cfg.AbsentSparsePaths(req.VaultPath),
+		LockedFiles:           normalizedLockedFiles(cfg.LockedFiles),
 	}, nil
 }
 
@@ -816,6 +849,72 @@ func RemoveProtectedPrefix(req RemoveProtectedPrefixRequest) (*RemoveProtectedPr
 	}, nil
 }
 
+func LockFile(req LockFileRequest) (*LockFileResult, error) {
+	cfg, exists, configPath, err := load(req.VaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := normalizeLockedFile(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := normalizedLockedFiles(cfg.LockedFiles)
+	changed := !slices.Contains(locked, path)
+	if changed {
+		locked = append(locked, path)
+		sort.Strings(locked)
+		cfg.LockedFiles = locked
+		if err := config.SaveVaultConfig(req.VaultPath, cfg); err != nil {
+			return nil, newError(CodeFileWriteError, "failed to save vault config", "", err)
+		}
+	}
+
+	return &LockFileResult{
+		ConfigPath:  configPath,
+		Created:     !exists && changed,
+		Changed:     changed,
+		Path:        path,
+		LockedFiles: locked,
+	}, nil
+}
+
+func UnlockFile(req UnlockFileRequest) (*UnlockFileResult, error) {
+	cfg, _, configPath, err := load(req.VaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := normalizeLockedFile(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := normalizedLockedFiles(cfg.LockedFiles)
+	next := make([]string, 0, len(locked))
+	for _, existing := range locked {
+		if existing != path {
+			next = append(next, existing)
+		}
+	}
+	if len(next) == len(locked) {
+		return nil, newError(CodeNotFound, fmt.Sprintf("file '%s' is not locked", path), "Run 'rvn vault config show' to see locked files", nil)
+	}
+
+	cfg.LockedFiles = next
+	if err := config.SaveVaultConfig(req.VaultPath, cfg); err != nil {
+		return nil, newError(CodeFileWriteError, "failed to save vault config", "", err)
+	}
+
+	return &UnlockFileResult{
+		ConfigPath:  configPath,
+		Changed:     true,
+		Removed:     path,
+		LockedFiles: next,
+	}, nil
+}
+
 func ListExclude(req ListExcludeRequest) (*ListExcludeResult, error) {
 	cfg, exists, configPath, err := load(req.VaultPath)
 	if err != nil {
@@ -952,6 +1051,36 @@ func normalizeProtectedPrefix(raw string) (string, error) {
 	return normalized, nil
 }
 
+func normalizedLockedFiles(files []string) []string {
+	if len(files) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]struct{}, len(files))
+	out := make([]string, 0, len(files))
+	for _, raw := range files {
+		path, err := normalizeLockedFile(raw)
+		if err != nil {
+			continue
+		}
+		if _, ok := seen[path]; ok {
+			continue
+		}
+		seen[path] = struct{}{}
+		out = append(out, path)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func normalizeLockedFile(raw string) (string, error) {
+	normalized := paths.NormalizeVaultRelPath(raw)
+	if normalized == "" || normalized == "." || strings.HasSuffix(strings.TrimSpace(raw), "/") || !paths.IsValidVaultRelPath(normalized) {
+		return "", newError(CodeInvalidInput, fmt.Sprintf("invalid locked file path: %q", raw), "Use a vault-relative file path such as 'reference/style-guide.md'", nil)
+	}
+	return normalized, nil
+}
+
 func normalizedExcludePatterns(patterns []string) []string {
 	return ravenignore.NormalizePatterns(patterns)
 }
@@ -168,6 +168,52 @@ func TestProtectedPrefixesRemoveRequiresExistingPrefix(t *testing.T) {
 	}
 }
 
+func TestLockAndUnlockFile(t *testing.T) {
+	tmp := t.TempDir()
+
+	result, err := LockFile(LockFileRequest{VaultPath: tmp, Path: "./reference/style.md"})
+	if err != nil {
+		t.Fatalf("LockFile() error = %v", err)
+	}
+	if !result.Changed || !result.Created || result.Path != "reference/style.md" {
+		t.Fatalf("unexpected lock result: %#v", result)
+	}
+
+	result, err = LockFile(LockFileRequest{VaultPath: tmp, Path: "reference/style.md"})
+	if err != nil {
+		t.Fatalf("LockFile() duplicate error = %v", err)
+	}
+	if result.Changed {
+		t.Fatalf("expected duplicate lock to be unchanged")
+	}
+
+	cfg, err := config.LoadVaultConfig(tmp)
+	if err != nil {
+		t.Fatalf("LoadVaultConfig() error = %v", err)
+	}
+	if !cfg.IsLockedPath("reference/style.md") {
+		t.Fatalf("expected reference/style.md to be locked, got %#v", cfg.LockedFiles)
+	}
+
+	unlockResult, err := UnlockFile(UnlockFileRequest{VaultPath: tmp, Path: "reference/style.md"})
+	if err != nil {
+		t.Fatalf("UnlockFile() error = %v", err)
+	}
+	if unlockResult.Removed != "reference/style.md" || len(unlockResult.LockedFiles) != 0 {
+		t.Fatalf("unexpected unlock result: %#v", unlockResult)
+	}
+
+	_, err = UnlockFile(UnlockFileRequest{VaultPath: tmp, Path: "reference/style.md"})
+	svcErr, ok := AsError(err)
+	if !ok || svcErr.Code != CodeNotFound {
+		t.Fatalf("expected CodeNotFound for unlocked file, got %v", err)
+	}
+
+	if _, err := LockFile(LockFileRequest{VaultPath: tmp, Path: "reference/"}); err == nil {
+		t.Fatalf("expected directory path to be rejected")
+	}
+}
+
 func TestProtectedPrefixesRejectInvalidPrefix(t *testing.T) {
 	tmp := t.TempDir()
 
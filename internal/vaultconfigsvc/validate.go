@@ -0,0 +1,286 @@
+package vaultconfigsvc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aidanlsb/raven/internal/atomicfile"
+	"github.com/aidanlsb/raven/internal/config"
+	"github.com/aidanlsb/raven/internal/query"
+	"github.com/aidanlsb/raven/internal/querysvc"
+)
+
+// Issue is a single problem found in raven.yaml content. Line and Column are
+// 1-based; zero means the position is unknown.
+type Issue struct {
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	location := ""
+	if i.Line > 0 {
+		location = fmt.Sprintf("line %d", i.Line)
+		if i.Column > 0 {
+			location += fmt.Sprintf(", column %d", i.Column)
+		}
+		location += ": "
+	}
+	if i.Path != "" {
+		return fmt.Sprintf("%s%s: %s", location, i.Path, i.Message)
+	}
+	return location + i.Message
+}
+
+type ReadRawRequest struct {
+	VaultPath string
+}
+
+type ReadRawResult struct {
+	ConfigPath string
+	Exists     bool
+	Content    string
+}
+
+type WriteRawRequest struct {
+	VaultPath string
+	Content   string
+}
+
+type WriteRawResult struct {
+	ConfigPath string
+	Valid      bool
+	Changed    bool
+	Issues     []Issue
+}
+
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// Validate checks raven.yaml content for YAML syntax errors, unknown keys,
+// values of the wrong type, and saved queries that do not parse.
+func Validate(content []byte) []Issue {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return []Issue{yamlErrorIssue(err)}
+	}
+	if len(root.Content) == 0 {
+		return nil
+	}
+
+	doc := root.Content[0]
+	issues := make([]Issue, 0)
+	checkNode(doc, reflect.TypeOf(config.VaultConfig{}), "", &issues)
+	checkSavedQueries(doc, &issues)
+
+	if len(issues) == 0 {
+		var cfg config.VaultConfig
+		if err := yaml.Unmarshal(content, &cfg); err != nil {
+			issues = append(issues, yamlErrorIssue(err))
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// ReadRaw returns the current raven.yaml content. Content is empty when the
+// file does not exist yet.
+func ReadRaw(req ReadRawRequest) (*ReadRawResult, error) {
+	if strings.TrimSpace(req.VaultPath) == "" {
+		return nil, newError(CodeInvalidInput, "vault path is required", "Resolve a vault before invoking the command", nil)
+	}
+	configPath := filepath.Join(req.VaultPath, "raven.yaml")
+	data, err := os.ReadFile(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return &ReadRawResult{ConfigPath: configPath}, nil
+	}
+	if err != nil {
+		return nil, newError(CodeConfigInvalid, "failed to read vault config", "Check raven.yaml permissions and try again", err)
+	}
+	return &ReadRawResult{ConfigPath: configPath, Exists: true, Content: string(data)}, nil
+}
+
+// WriteRaw validates content and replaces raven.yaml with it. Invalid content
+// is never written; the issues are returned instead.
+func WriteRaw(req WriteRawRequest) (*WriteRawResult, error) {
+	current, err := ReadRaw(ReadRawRequest{VaultPath: req.VaultPath})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WriteRawResult{ConfigPath: current.ConfigPath, Issues: Validate([]byte(req.Content))}
+	if len(result.Issues) > 0 {
+		return result, nil
+	}
+	result.Valid = true
+	if current.Exists && current.Content == req.Content {
+		return result, nil
+	}
+	if err := atomicfile.WriteFile(current.ConfigPath, []byte(req.Content), 0o644); err != nil {
+		return nil, newError(CodeFileWriteError, "failed to write vault config", "Check raven.yaml permissions and try again", err)
+	}
+	result.Changed = true
+	return result, nil
+}
+
+// checkNode walks a YAML node against the Go type it decodes into, reporting
+// unknown mapping keys and scalars that cannot be decoded.
+func checkNode(node *yaml.Node, typ reflect.Type, path string, issues *[]Issue) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			*issues = append(*issues, nodeIssue(node, path, "expected a mapping"))
+			return
+		}
+		fields := yamlFieldTypes(typ)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldType, ok := fields[key.Value]
+			if !ok {
+				*issues = append(*issues, nodeIssue(key, joinConfigPath(path, key.Value), "unknown key"))
+				continue
+			}
+			checkNode(value, fieldType, joinConfigPath(path, key.Value), issues)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			*issues = append(*issues, nodeIssue(node, path, "expected a mapping"))
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			checkNode(node.Content[i+1], typ.Elem(), joinConfigPath(path, node.Content[i].Value), issues)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			*issues = append(*issues, nodeIssue(node, path, "expected a list"))
+			return
+		}
+		for i, item := range node.Content {
+			checkNode(item, typ.Elem(), fmt.Sprintf("%s[%d]", path, i), issues)
+		}
+	default:
+		if node.Kind != yaml.ScalarNode {
+			*issues = append(*issues, nodeIssue(node, path, fmt.Sprintf("expected a %s value", typ.Kind())))
+			return
+		}
+		if err := node.Decode(reflect.New(typ).Interface()); err != nil {
+			*issues = append(*issues, nodeIssue(node, path, fmt.Sprintf("expected a %s value, got %q", typ.Kind(), node.Value)))
+		}
+	}
+}
+
+func checkSavedQueries(doc *yaml.Node, issues *[]Issue) {
+	queries := mappingValue(doc, "queries")
+	if queries == nil || queries.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(queries.Content); i += 2 {
+		name := queries.Content[i].Value
+		entry := queries.Content[i+1]
+		path := joinConfigPath("queries", name)
+		queryNode := mappingValue(entry, "query")
+		if queryNode == nil || strings.TrimSpace(queryNode.Value) == "" {
+			if entry.Kind == yaml.MappingNode {
+				*issues = append(*issues, nodeIssue(entry, path, "saved query has no query defined"))
+			}
+			continue
+		}
+
+		queryStr := queryNode.Value
+		if !strings.Contains(queryStr, "{{") {
+			if _, err := query.Parse(queryStr); err != nil {
+				*issues = append(*issues, nodeIssue(queryNode, path+".query", fmt.Sprintf("invalid query: %v", err)))
+				continue
+			}
+		}
+
+		var declared []string
+		if argsNode := mappingValue(entry, "args"); argsNode != nil {
+			_ = argsNode.Decode(&declared)
+		}
+		if err := querysvc.ValidateInputDeclarations(name, queryStr, declared); err != nil {
+			*issues = append(*issues, nodeIssue(queryNode, path+".query", err.Error()))
+		}
+
+		if snapshotNode := mappingValue(entry, "snapshot"); snapshotNode != nil && snapshotNode.Kind == yaml.MappingNode {
+			var snapshot config.QuerySnapshot
+			if err := snapshotNode.Decode(&snapshot); err == nil {
+				if err := querysvc.ValidateSnapshot(name, &snapshot, declared); err != nil {
+					*issues = append(*issues, nodeIssue(snapshotNode, path+".snapshot", err.Error()))
+				}
+			}
+		}
+	}
+}
+
+func yamlFieldTypes(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func nodeIssue(node *yaml.Node, path, message string) Issue {
+	return Issue{Line: node.Line, Column: node.Column, Path: path, Message: message}
+}
+
+func yamlErrorIssue(err error) Issue {
+	message := strings.TrimPrefix(err.Error(), "yaml: ")
+	issue := Issue{Message: message}
+	if match := yamlLinePattern.FindStringSubmatch(message); match != nil {
+		issue.Line, _ = strconv.Atoi(match[1])
+		issue.Message = strings.TrimSpace(strings.TrimPrefix(message, match[0]+":"))
+	}
+	return issue
+}
+
+func joinConfigPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
@@ -0,0 +1,134 @@
+package vaultconfigsvc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateReportsLineErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		content     string
+		wantLine    int
+		wantPath    string
+		wantMessage string
+	}{
+		{
+			name:        "valid config",
+			content:     "auto_reindex: true\nqueries:\n  active:\n    query: \"type:project .status==active\"\n",
+			wantMessage: "",
+		},
+		{
+			name:        "yaml syntax",
+			content:     "auto_reindex: true\nqueries:\n  active: [\n",
+			wantLine:    3,
+			wantMessage: "did not find expected node content",
+		},
+		{
+			name:        "unknown top-level key",
+			content:     "auto_reindex: true\nauto_reindx: false\n",
+			wantLine:    2,
+			wantPath:    "auto_reindx",
+			wantMessage: "unknown key",
+		},
+		{
+			name:        "unknown nested key",
+			content:     "directories:\n  daily: journal/\n  dailies: other/\n",
+			wantLine:    3,
+			wantPath:    "directories.dailies",
+			wantMessage: "unknown key",
+		},
+		{
+			name:        "wrong scalar type",
+			content:     "auto_reindex: sometimes\n",
+			wantLine:    1,
+			wantPath:    "auto_reindex",
+			wantMessage: "expected a bool value",
+		},
+		{
+			name:        "list expected",
+			content:     "exclude: drafts/\n",
+			wantLine:    1,
+			wantPath:    "exclude",
+			wantMessage: "expected a list",
+		},
+		{
+			name:        "invalid saved query",
+			content:     "queries:\n  broken:\n    query: \"type:project .status==\"\n",
+			wantLine:    3,
+			wantPath:    "queries.broken.query",
+			wantMessage: "invalid query",
+		},
+		{
+			name:        "undeclared saved query args",
+			content:     "queries:\n  by-owner:\n    query: \"type:project .owner=={{args.owner}}\"\n",
+			wantLine:    3,
+			wantPath:    "queries.by-owner.query",
+			wantMessage: "does not declare args",
+		},
+		{
+			name:        "invalid snapshot schedule",
+			content:     "queries:\n  questions:\n    query: \"trait:question\"\n    snapshot:\n      target: pages/open-questions\n      schedule: sometimes\n",
+			wantLine:    5,
+			wantPath:    "queries.questions.snapshot",
+			wantMessage: "invalid schedule",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			issues := Validate([]byte(tt.content))
+			if tt.wantMessage == "" {
+				if len(issues) != 0 {
+					t.Fatalf("expected no issues, got %#v", issues)
+				}
+				return
+			}
+			if len(issues) != 1 {
+				t.Fatalf("expected one issue, got %#v", issues)
+			}
+			issue := issues[0]
+			if issue.Line != tt.wantLine || issue.Path != tt.wantPath || !strings.Contains(issue.Message, tt.wantMessage) {
+				t.Fatalf("issue = %#v, want line %d path %q containing %q", issue, tt.wantLine, tt.wantPath, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestWriteRawRefusesInvalidContent(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	configPath := filepath.Join(tmp, "raven.yaml")
+	original := "auto_reindex: true\n"
+	if err := os.WriteFile(configPath, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := WriteRaw(WriteRawRequest{VaultPath: tmp, Content: "auto_reindex: nope\n"})
+	if err != nil {
+		t.Fatalf("WriteRaw() error = %v", err)
+	}
+	if result.Valid || result.Changed || len(result.Issues) == 0 {
+		t.Fatalf("expected invalid result, got %#v", result)
+	}
+	if data, _ := os.ReadFile(configPath); string(data) != original {
+		t.Fatalf("invalid content was persisted: %q", data)
+	}
+
+	result, err = WriteRaw(WriteRawRequest{VaultPath: tmp, Content: "auto_reindex: false\n"})
+	if err != nil {
+		t.Fatalf("WriteRaw() error = %v", err)
+	}
+	if !result.Valid || !result.Changed {
+		t.Fatalf("expected valid changed result, got %#v", result)
+	}
+	if data, _ := os.ReadFile(configPath); string(data) != "auto_reindex: false\n" {
+		t.Fatalf("raven.yaml = %q", data)
+	}
+}
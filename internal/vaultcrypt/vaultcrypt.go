@@ -0,0 +1,231 @@
+// Package vaultcrypt stores files in encrypted vault directories encrypted at
+// rest and decrypts them in memory when Raven reads them.
+//
+// Directories are listed under encryption.directories in raven.yaml. Writes
+// through atomicfile are sealed when the target lies in one of them, and
+// ReadFile decrypts any file carrying the encrypted header, so services read
+// and write plaintext while the files on disk stay encrypted.
+package vaultcrypt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/aidanlsb/raven/internal/secrets"
+)
+
+// DefaultKeyEnv matches config.DefaultEncryptionKeyEnv.
+const DefaultKeyEnv = "RAVEN_VAULT_KEY"
+
+var (
+	// ErrKeyMissing indicates a file is encrypted but no passphrase is available.
+	ErrKeyMissing = errors.New("vault encryption key is not available")
+	// ErrKeyInvalid indicates an encrypted file could not be decrypted.
+	ErrKeyInvalid = errors.New("file could not be decrypted (wrong key or corrupted file)")
+)
+
+// Encrypted file layout: magic | salt | nonce | AES-256-GCM ciphertext (see
+// secrets.Seal).
+var magic = []byte("RVNENC01")
+
+// Settings is the encryption configuration for a vault.
+type Settings struct {
+	Directories []string
+	KeyEnv      string
+	Salt        []byte
+}
+
+// vaultEncryptionConfig mirrors the encryption section of raven.yaml
+// (config.EncryptionConfig). vaultcrypt reads it directly because config
+// writes raven.yaml through atomicfile, which seals files through vaultcrypt.
+type vaultEncryptionConfig struct {
+	Encryption *struct {
+		Directories []string `yaml:"directories"`
+		KeyEnv      string   `yaml:"key_env"`
+		Salt        string   `yaml:"salt"`
+	} `yaml:"encryption"`
+}
+
+// LoadSettings reads the encryption settings from the vault's raven.yaml.
+// A vault without raven.yaml or without encrypted directories returns empty
+// settings.
+func LoadSettings(vaultPath string) (Settings, error) {
+	data, err := os.ReadFile(filepath.Join(vaultPath, "raven.yaml"))
+	if os.IsNotExist(err) {
+		return Settings{}, nil
+	}
+	if err != nil {
+		return Settings{}, fmt.Errorf("failed to read raven.yaml: %w", err)
+	}
+	var cfg vaultEncryptionConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Settings{}, fmt.Errorf("failed to parse raven.yaml: %w", err)
+	}
+	if cfg.Encryption == nil {
+		return Settings{}, nil
+	}
+
+	settings := Settings{KeyEnv: strings.TrimSpace(cfg.Encryption.KeyEnv)}
+	for _, dir := range cfg.Encryption.Directories {
+		if dir = NormalizeDirectory(dir); dir != "" {
+			settings.Directories = append(settings.Directories, dir)
+		}
+	}
+	if settings.KeyEnv == "" {
+		settings.KeyEnv = DefaultKeyEnv
+	}
+	if salt := strings.TrimSpace(cfg.Encryption.Salt); salt != "" {
+		settings.Salt, err = hex.DecodeString(salt)
+		if err != nil || len(settings.Salt) != secrets.SaltSize {
+			return Settings{}, fmt.Errorf("invalid encryption.salt in raven.yaml")
+		}
+	}
+	return settings, nil
+}
+
+// NormalizeDirectory returns dir as a vault-relative directory with a
+// trailing slash, or "" for the vault root or an invalid path.
+func NormalizeDirectory(dir string) string {
+	dir = filepath.ToSlash(strings.TrimSpace(dir))
+	dir = strings.Trim(filepath.ToSlash(filepath.Clean("/"+dir)), "/")
+	if dir == "" || dir == "." {
+		return ""
+	}
+	return dir + "/"
+}
+
+// Covers reports whether the vault-relative path lies in an encrypted directory.
+func (s Settings) Covers(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	for _, dir := range s.Directories {
+		if strings.HasPrefix(relPath, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// Passphrase reads the passphrase from the configured environment variable,
+// or from the command config.toml sets for it under [key_commands] (for
+// example a keychain lookup).
+func (s Settings) Passphrase() ([]byte, error) {
+	keyEnv := s.KeyEnv
+	if keyEnv == "" {
+		keyEnv = DefaultKeyEnv
+	}
+	value, err := secrets.Lookup(keyEnv)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyMissing, err)
+	}
+	return value, nil
+}
+
+// IsEncrypted reports whether data carries the encrypted file header.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// Encrypt seals plaintext with a key derived from passphrase and salt.
+func Encrypt(passphrase, salt, plaintext []byte) ([]byte, error) {
+	return secrets.Seal(magic, passphrase, salt, plaintext)
+}
+
+// Decrypt opens data written by Encrypt.
+func Decrypt(passphrase, data []byte) ([]byte, error) {
+	plaintext, _, err := secrets.Open(magic, passphrase, data)
+	if errors.Is(err, secrets.ErrInvalid) {
+		return nil, ErrKeyInvalid
+	}
+	return plaintext, err
+}
+
+// ReadFile reads path like os.ReadFile, decrypting it in memory when it is
+// an encrypted vault file.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Open(path, data)
+}
+
+// Open returns data unchanged unless it is an encrypted vault file, which it
+// decrypts with the passphrase of the vault containing path.
+func Open(path string, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	vaultPath := findVaultRoot(path)
+	if vaultPath == "" {
+		return nil, fmt.Errorf("%s: %w: no raven.yaml above the file", path, ErrKeyMissing)
+	}
+	settings, err := LoadSettings(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+	passphrase, err := settings.Passphrase()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := Decrypt(passphrase, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return plaintext, nil
+}
+
+// Seal returns the bytes to store at path: data encrypted when path lies in
+// an encrypted directory of its vault, and data unchanged otherwise.
+func Seal(path string, data []byte) ([]byte, error) {
+	if IsEncrypted(data) {
+		return data, nil
+	}
+	vaultPath := findVaultRoot(path)
+	if vaultPath == "" {
+		return data, nil
+	}
+	settings, err := LoadSettings(vaultPath)
+	if err != nil {
+		// Without readable settings there is no telling whether path must
+		// be encrypted, so refuse rather than risk writing plaintext.
+		return nil, err
+	}
+	if len(settings.Directories) == 0 {
+		return data, nil
+	}
+	rel, err := filepath.Rel(vaultPath, path)
+	if err != nil || !settings.Covers(rel) {
+		return data, nil
+	}
+	if len(settings.Salt) == 0 {
+		return nil, fmt.Errorf("encryption.salt is missing from raven.yaml; run 'rvn vault encrypt' again")
+	}
+	passphrase, err := settings.Passphrase()
+	if err != nil {
+		return nil, err
+	}
+	return Encrypt(passphrase, settings.Salt, data)
+}
+
+// findVaultRoot returns the nearest directory above path holding raven.yaml.
+func findVaultRoot(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	for dir := filepath.Dir(abs); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "raven.yaml")); err == nil {
+			return dir
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package vaultcrypt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	salt := []byte("0123456789abcdef")
+	sealed, err := Encrypt([]byte("hunter2"), salt, []byte("# Private\n"))
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !IsEncrypted(sealed) {
+		t.Fatalf("expected encrypted header, got %q", sealed[:8])
+	}
+
+	plaintext, err := Decrypt([]byte("hunter2"), sealed)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if string(plaintext) != "# Private\n" {
+		t.Fatalf("plaintext = %q", plaintext)
+	}
+	if _, err := Decrypt([]byte("wrong"), sealed); !errors.Is(err, ErrKeyInvalid) {
+		t.Fatalf("wrong key err = %v, want ErrKeyInvalid", err)
+	}
+}
+
+func TestSealEncryptsOnlyListedDirectories(t *testing.T) {
+	t.Setenv(DefaultKeyEnv, "hunter2")
+
+	vault := t.TempDir()
+	config := "encryption:\n  directories:\n    - people/private\n  salt: 000102030405060708090a0b0c0d0e0f\n"
+	if err := os.WriteFile(filepath.Join(vault, "raven.yaml"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	private := filepath.Join(vault, "people", "private", "freya.md")
+	sealed, err := Seal(private, []byte("secret"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !IsEncrypted(sealed) {
+		t.Fatalf("expected %s to be sealed", private)
+	}
+	if err := os.MkdirAll(filepath.Dir(private), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(private, sealed, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ReadFile(private)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(content) != "secret" {
+		t.Fatalf("ReadFile = %q, want plaintext", content)
+	}
+
+	public, err := Seal(filepath.Join(vault, "people", "freya.md"), []byte("hello"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if string(public) != "hello" {
+		t.Fatalf("expected file outside encrypted directories unchanged, got %q", public)
+	}
+}
+
+func TestSealFailsWhenSettingsAreUnreadable(t *testing.T) {
+	t.Parallel()
+
+	vault := t.TempDir()
+	if err := os.WriteFile(filepath.Join(vault, "raven.yaml"), []byte("encryption: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if sealed, err := Seal(filepath.Join(vault, "people", "private", "freya.md"), []byte("secret")); err == nil {
+		t.Fatalf("expected an error for unparseable raven.yaml, got %q", sealed)
+	}
+}
@@ -0,0 +1,90 @@
+package watchsvc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNotRunning is returned by ReadStatus when no watcher is serving the
+// vault.
+var ErrNotRunning = errors.New("no watcher is running for this vault")
+
+const statusTimeout = 500 * time.Millisecond
+
+// SocketPath is the unix socket a watcher serves its status on.
+func SocketPath(vaultPath string) string {
+	return filepath.Join(vaultPath, ".raven", "watch.sock")
+}
+
+// Serve answers GET /status on the vault's watch socket with the watcher's
+// Status as JSON. It fails if another watcher is already serving the vault.
+// The returned function stops the server and removes the socket.
+func (w *Watcher) Serve() (func(), error) {
+	socketPath := SocketPath(w.opts.VaultPath)
+	if _, err := ReadStatus(w.opts.VaultPath); err == nil {
+		return nil, fmt.Errorf("another watcher is already running for this vault (%s)", socketPath)
+	}
+	// Nothing answered, so any socket file left behind is stale.
+	_ = os.Remove(socketPath)
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0o755); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("watch socket %s: %w", socketPath, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(w.Status())
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	return func() {
+		_ = server.Close()
+		_ = os.Remove(socketPath)
+	}, nil
+}
+
+// ReadStatus asks the watcher serving vaultPath for its status. It returns
+// ErrNotRunning when no watcher answers.
+func ReadStatus(vaultPath string) (*Status, error) {
+	socketPath := SocketPath(vaultPath)
+	client := &http.Client{
+		Timeout: statusTimeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Get("http://raven-watch/status")
+	if err != nil {
+		return nil, ErrNotRunning
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("watcher status: %s", resp.Status)
+	}
+
+	var status Status
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("watcher status: %w", err)
+	}
+	return &status, nil
+}
@@ -0,0 +1,235 @@
+// Package watchsvc keeps a vault's index current while files change and
+// reports how fresh the index is to other processes.
+package watchsvc
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/config"
+	ravenignore "github.com/aidanlsb/raven/internal/ignore"
+	"github.com/aidanlsb/raven/internal/reindexsvc"
+)
+
+const (
+	// DefaultPollInterval is how often the vault is scanned for changes.
+	DefaultPollInterval = 100 * time.Millisecond
+	// DefaultDebounce is how long files must stay unchanged before a
+	// reindex runs, so a burst of editor saves reindexes once.
+	DefaultDebounce = 300 * time.Millisecond
+)
+
+// Options configures a Watcher.
+type Options struct {
+	VaultPath    string
+	PollInterval time.Duration
+	Debounce     time.Duration
+
+	// OnReindex is called after each reindex, from the watcher goroutine.
+	OnReindex func(Event)
+}
+
+// Event describes one reindex run by the watcher.
+type Event struct {
+	At      time.Time
+	Changed []string
+	Result  *reindexsvc.RunResult
+	Err     error
+}
+
+// Status is the watcher state served to other processes.
+type Status struct {
+	VaultPath     string     `json:"vault_path"`
+	PID           int        `json:"pid"`
+	StartedAt     time.Time  `json:"started_at"`
+	LastReindexAt *time.Time `json:"last_reindex_at,omitempty"`
+	LastChangeAt  *time.Time `json:"last_change_at,omitempty"`
+	// Pending is true while changed files are waiting for the debounce
+	// window to pass.
+	Pending bool `json:"pending"`
+	// Fresh is true when no changes are pending and the last reindex
+	// succeeded.
+	Fresh     bool   `json:"fresh"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Watcher reindexes a vault whenever its files change.
+type Watcher struct {
+	opts Options
+
+	mu     sync.Mutex
+	status Status
+}
+
+type fileStamp struct {
+	modTime int64
+	size    int64
+}
+
+// New returns a watcher for opts.VaultPath. Zero intervals use the defaults.
+func New(opts Options) *Watcher {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+	return &Watcher{
+		opts: opts,
+		status: Status{
+			VaultPath: opts.VaultPath,
+			PID:       os.Getpid(),
+			StartedAt: time.Now(),
+		},
+	}
+}
+
+// Status returns a snapshot of the watcher state.
+func (w *Watcher) Status() Status {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+// Run brings the index up to date, then reindexes changed files until ctx is
+// cancelled. It returns an error only when the first reindex fails.
+func (w *Watcher) Run(ctx context.Context) error {
+	if err := w.reindex(ctx, nil); err != nil {
+		return err
+	}
+	// Scan after reindexing: a reindex can restamp raven.yaml, which is not
+	// a change worth reindexing for.
+	snapshot := w.scan()
+
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+
+	var lastChange time.Time
+	var changed []string
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		next := w.scan()
+		if diff := diffSnapshots(snapshot, next); len(diff) > 0 {
+			snapshot = next
+			lastChange = time.Now()
+			changed = mergeChanged(changed, diff)
+			w.mu.Lock()
+			w.status.Pending = true
+			w.status.Fresh = false
+			w.status.LastChangeAt = &lastChange
+			w.mu.Unlock()
+			continue
+		}
+		if len(changed) == 0 || time.Since(lastChange) < w.opts.Debounce {
+			continue
+		}
+
+		// A failed reindex is reported through OnReindex and Status; the
+		// next change retries it.
+		_ = w.reindex(ctx, changed)
+		changed = nil
+		snapshot = w.scan()
+	}
+}
+
+func (w *Watcher) reindex(ctx context.Context, changed []string) error {
+	sort.Strings(changed)
+	result, err := reindexsvc.Run(reindexsvc.RunRequest{VaultPath: w.opts.VaultPath, Context: ctx, Changed: changed})
+	now := time.Now()
+
+	w.mu.Lock()
+	w.status.Pending = false
+	w.status.LastReindexAt = &now
+	w.status.Fresh = err == nil
+	w.status.LastError = ""
+	if err != nil {
+		w.status.LastError = err.Error()
+	}
+	w.mu.Unlock()
+
+	if w.opts.OnReindex != nil {
+		w.opts.OnReindex(Event{At: now, Changed: changed, Result: result, Err: err})
+	}
+	return err
+}
+
+// scan records the modification time and size of every file Raven manages.
+// raven.yaml and schema.yaml are included, so config and schema edits
+// trigger a reindex too.
+func (w *Watcher) scan() map[string]fileStamp {
+	var matcher *ravenignore.Matcher
+	if vaultCfg, err := config.LoadVaultConfig(w.opts.VaultPath); err == nil {
+		matcher, _ = ravenignore.NewMatcher(vaultCfg.GetExcludePatterns())
+	}
+
+	stamps := make(map[string]fileStamp)
+	_ = filepath.WalkDir(w.opts.VaultPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		relPath, _ := filepath.Rel(w.opts.VaultPath, path)
+		relPath = filepath.ToSlash(relPath)
+		if d.IsDir() {
+			name := d.Name()
+			if name == ".raven" || name == ".trash" || name == ".git" {
+				return filepath.SkipDir
+			}
+			if relPath != "." && matcher.Match(relPath, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if matcher.Match(relPath, false) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		stamps[relPath] = fileStamp{modTime: info.ModTime().UnixNano(), size: info.Size()}
+		return nil
+	})
+	return stamps
+}
+
+// diffSnapshots returns the paths added, removed, or modified between two
+// scans.
+func diffSnapshots(before, after map[string]fileStamp) []string {
+	var changed []string
+	for path, stamp := range after {
+		if prev, ok := before[path]; !ok || prev != stamp {
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+func mergeChanged(existing, added []string) []string {
+	seen := make(map[string]struct{}, len(existing))
+	for _, path := range existing {
+		seen[path] = struct{}{}
+	}
+	for _, path := range added {
+		if _, ok := seen[path]; ok {
+			continue
+		}
+		seen[path] = struct{}{}
+		existing = append(existing, path)
+	}
+	return existing
+}
@@ -0,0 +1,104 @@
+package watchsvc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aidanlsb/raven/internal/index"
+)
+
+func TestWatcherReindexesChangedFilesAndServesStatus(t *testing.T) {
+	t.Parallel()
+
+	vaultPath := t.TempDir()
+	writeFile(t, vaultPath, "schema.yaml", "version: 2\ntypes:\n  project:\n    fields:\n      status: {type: string}\n")
+	writeFile(t, vaultPath, "project.md", "---\ntype: project\nstatus: open\n---\n")
+
+	if _, err := ReadStatus(vaultPath); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("ReadStatus before serving = %v, want ErrNotRunning", err)
+	}
+
+	events := make(chan Event, 8)
+	w := New(Options{
+		VaultPath:    vaultPath,
+		PollInterval: 10 * time.Millisecond,
+		Debounce:     50 * time.Millisecond,
+		OnReindex:    func(e Event) { events <- e },
+	})
+	closeSocket, err := w.Serve()
+	if err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+	defer closeSocket()
+	if _, err := New(Options{VaultPath: vaultPath}).Serve(); err == nil {
+		t.Fatal("expected a second watcher for the vault to be refused")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if initial := waitForEvent(t, events); initial.Err != nil || initial.Changed != nil {
+		t.Fatalf("initial event = %+v", initial)
+	}
+
+	// Two saves in a row, within the same second, reindex once with the
+	// final content.
+	writeFile(t, vaultPath, "project.md", "---\ntype: project\nstatus: draft\n---\n")
+	writeFile(t, vaultPath, "project.md", "---\ntype: project\nstatus: done\n---\n")
+	event := waitForEvent(t, events)
+	if event.Err != nil || len(event.Changed) != 1 || event.Changed[0] != "project.md" {
+		t.Fatalf("event = %+v, want project.md reindexed", event)
+	}
+
+	db, err := index.Open(vaultPath)
+	if err != nil {
+		t.Fatalf("index.Open: %v", err)
+	}
+	defer db.Close()
+	var status string
+	if err := db.DB().QueryRow(`SELECT json_extract(fields, '$.status') FROM objects WHERE id = 'project'`).Scan(&status); err != nil {
+		t.Fatalf("query status: %v", err)
+	}
+	if status != "done" {
+		t.Fatalf("indexed status = %q, want done", status)
+	}
+
+	served, err := ReadStatus(vaultPath)
+	if err != nil {
+		t.Fatalf("ReadStatus: %v", err)
+	}
+	if !served.Fresh || served.Pending || served.LastReindexAt == nil {
+		t.Fatalf("served status = %+v, want fresh", served)
+	}
+}
+
+func waitForEvent(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a reindex")
+		return Event{}
+	}
+}
+
+func writeFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}